@@ -33,6 +33,7 @@ images=(
   openshift/origin-docker-builder
   openshift/origin-sti-builder
   openshift/origin-haproxy-router
+  openshift/origin-nginx-router
   openshift/hello-openshift
 )
 
@@ -0,0 +1,346 @@
+// Package compact provides a binary encoding of API objects that is cheaper to encode and decode than JSON.
+//
+// Objects are converted to an external version and written with encoding/gob, which matches fields by
+// name, so clients and servers of different releases can read each other's objects as they do with JSON.
+// Objects gob cannot encode, or would decode differently, are written as JSON.  Data without the compact marker is decoded as JSON, so
+// clients keep working against servers that only respond with JSON.  Watch events are framed as JSON, so the
+// codec of watches writes the compact encoding of their objects as JSON strings, which are decoded as well.
+package compact
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+
+	kmeta "github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/golang/glog"
+)
+
+// MediaType is the media type of objects in the compact encoding.
+const MediaType = "application/vnd.openshift.gob"
+
+// marker starts every object in the compact encoding.  JSON and YAML never start with a zero byte.
+const marker = "\x00gob1"
+
+// codec encodes objects in the compact encoding of version.
+type codec struct {
+	scheme  *runtime.Scheme
+	version string
+	// encodeJSON is true if objects are encoded as JSON and only decoded from the compact encoding
+	encodeJSON bool
+	// quoted is true if the compact encoding is written as a JSON string, which can be embedded in JSON
+	quoted bool
+}
+
+// NewCodec returns a codec that encodes objects in the compact encoding of version, and decodes objects in
+// the compact encoding or JSON.
+func NewCodec(scheme *runtime.Scheme, version string) runtime.Codec {
+	return &codec{scheme: scheme, version: version}
+}
+
+// NewWatchCodec returns a codec that encodes objects in the compact encoding of version written as JSON
+// strings, so they can be embedded in the JSON frames of watch events, and decodes the same objects as the
+// codec NewCodec returns.  The strings hold the compact encoding in base64.
+func NewWatchCodec(scheme *runtime.Scheme, version string) runtime.Codec {
+	return &codec{scheme: scheme, version: version, quoted: true}
+}
+
+// NewClientCodec returns a codec for clients that encodes objects as JSON, so servers that do not support
+// the compact encoding can read them, and decodes objects in the compact encoding or JSON.
+func NewClientCodec(scheme *runtime.Scheme, version string) runtime.Codec {
+	return &codec{scheme: scheme, version: version, encodeJSON: true}
+}
+
+// IsCompact returns true if c decodes the compact encoding.
+func IsCompact(c runtime.Codec) bool {
+	_, ok := c.(*codec)
+	return ok
+}
+
+// Encode implements runtime.Codec
+func (c *codec) Encode(obj runtime.Object) ([]byte, error) {
+	if c.encodeJSON {
+		return c.scheme.EncodeToVersion(obj, c.version)
+	}
+	versioned, err := c.scheme.ConvertToVersion(obj, c.version)
+	if err != nil {
+		return nil, err
+	}
+	version, kind, err := c.scheme.ObjectVersionAndKind(versioned)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBufferString(marker)
+	encoder := gob.NewEncoder(buf)
+	if err := encoder.Encode(version); err != nil {
+		return nil, err
+	}
+	if err := encoder.Encode(kind); err != nil {
+		return nil, err
+	}
+	if hasZeroPointer(reflect.ValueOf(versioned)) {
+		// gob sends the value a pointer points to and omits zero values, so such a pointer would be decoded as
+		// nil, which unlike zero usually means that a default applies
+		glog.V(4).Infof("Encoding %s as JSON, it has a pointer to a zero value", kind)
+		return c.scheme.EncodeToVersion(obj, c.version)
+	}
+	if err := encoder.Encode(versioned); err != nil {
+		// gob cannot encode some values JSON can, such as nil items of lists of pointers
+		glog.V(4).Infof("Encoding %s as JSON, it cannot be encoded compactly: %v", kind, err)
+		return c.scheme.EncodeToVersion(obj, c.version)
+	}
+	if c.quoted {
+		return json.Marshal(buf.Bytes())
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode implements runtime.Codec
+func (c *codec) Decode(data []byte) (runtime.Object, error) {
+	data, err := unquote(data)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte(marker)) {
+		return c.scheme.Decode(data)
+	}
+	external, kind, err := c.decodeExternal(data)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := c.scheme.New("", kind)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.scheme.Convert(external, obj); err != nil {
+		return nil, err
+	}
+	return obj, clearVersionAndKind(obj)
+}
+
+// DecodeInto implements runtime.Codec
+func (c *codec) DecodeInto(data []byte, obj runtime.Object) error {
+	data, err := unquote(data)
+	if err != nil {
+		return err
+	}
+	if !bytes.HasPrefix(data, []byte(marker)) {
+		return c.scheme.DecodeInto(data, obj)
+	}
+	external, kind, err := c.decodeExternal(data)
+	if err != nil {
+		return err
+	}
+	_, objKind, err := c.scheme.ObjectVersionAndKind(obj)
+	if err != nil {
+		return err
+	}
+	if kind != objKind {
+		return fmt.Errorf("data of kind %s cannot be decoded into %s", kind, objKind)
+	}
+	if err := c.scheme.Convert(external, obj); err != nil {
+		return err
+	}
+	return clearVersionAndKind(obj)
+}
+
+// unquote returns the compact encoding in data if it is written as a JSON string, and otherwise data.
+func unquote(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(`"`)) {
+		return data, nil
+	}
+	var compact []byte
+	if err := json.Unmarshal(data, &compact); err != nil {
+		return nil, err
+	}
+	return compact, nil
+}
+
+// decodeExternal returns the external object in data and its kind.
+func (c *codec) decodeExternal(data []byte) (runtime.Object, string, error) {
+	decoder := gob.NewDecoder(bytes.NewReader(data[len(marker):]))
+	version, kind := "", ""
+	if err := decoder.Decode(&version); err != nil {
+		return nil, "", err
+	}
+	if err := decoder.Decode(&kind); err != nil {
+		return nil, "", err
+	}
+	external, err := c.scheme.New(version, kind)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := decoder.Decode(external); err != nil {
+		return nil, "", fmt.Errorf("unable to decode %s: %v", kind, err)
+	}
+	return external, kind, nil
+}
+
+var gobEncoderType = reflect.TypeOf((*gob.GobEncoder)(nil)).Elem()
+
+// pointerTypes records for each type whether its values can hold pointers gob encodes, so values of types
+// that cannot are not searched for pointers to zero values.
+var pointerTypes = struct {
+	sync.RWMutex
+	holds map[reflect.Type]bool
+}{holds: map[reflect.Type]bool{}}
+
+// holdsPointers returns true if values of t can hold pointers in fields gob encodes.
+func holdsPointers(t reflect.Type) bool {
+	pointerTypes.RLock()
+	holds, ok := pointerTypes.holds[t]
+	pointerTypes.RUnlock()
+	if ok {
+		return holds
+	}
+	pointerTypes.Lock()
+	defer pointerTypes.Unlock()
+	holds = typeHoldsPointers(t, map[reflect.Type]bool{})
+	pointerTypes.holds[t] = holds
+	return holds
+}
+
+// typeHoldsPointers returns whether values of t can hold pointers gob encodes.  A type reached again while
+// it is visited is reached through a pointer, slice or map, which the outer visit accounts for.
+func typeHoldsPointers(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if holds, ok := pointerTypes.holds[t]; ok {
+		return holds
+	}
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+	holds := false
+	if !t.Implements(gobEncoderType) {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			holds = true
+		case reflect.Struct:
+			for i := 0; i < t.NumField(); i++ {
+				// gob ignores unexported fields
+				if field := t.Field(i); len(field.PkgPath) == 0 && typeHoldsPointers(field.Type, visiting) {
+					holds = true
+				}
+			}
+		case reflect.Slice, reflect.Array, reflect.Map:
+			holds = typeHoldsPointers(t.Elem(), visiting)
+		}
+	}
+	return holds
+}
+
+// hasZeroPointer returns true if v holds a pointer to a zero value in a field gob encodes.  Only the
+// values of types that can hold such pointers are searched.
+func hasZeroPointer(v reflect.Value) bool {
+	if !holdsPointers(v.Type()) {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return false
+		}
+		if isZero(v.Elem()) {
+			return true
+		}
+		return hasZeroPointer(v.Elem())
+	case reflect.Interface:
+		return !v.IsNil() && hasZeroPointer(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			// gob ignores unexported fields
+			if len(v.Type().Field(i).PkgPath) == 0 && hasZeroPointer(v.Field(i)) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if hasZeroPointer(v.Index(i)) {
+				return true
+			}
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			if hasZeroPointer(v.MapIndex(key)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isZero returns true if v is the zero value of its type.
+func isZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Complex64, reflect.Complex128:
+		return v.Complex() == 0
+	case reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func:
+		return v.IsNil()
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !isZero(v.Index(i)) {
+				return false
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !isZero(v.Field(i)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// clearVersionAndKind clears the version and kind of obj, which are only set in the wire format.
+func clearVersionAndKind(obj runtime.Object) error {
+	accessor, err := kmeta.TypeAccessor(obj)
+	if err != nil {
+		return err
+	}
+	accessor.SetAPIVersion("")
+	accessor.SetKind("")
+	return nil
+}
+
+// NewTransport returns a transport that asks servers to respond with objects in the compact encoding
+// through rt.
+func NewTransport(rt http.RoundTripper) http.RoundTripper {
+	return &transport{rt}
+}
+
+// transport sets the Accept header of requests.
+type transport struct {
+	http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(req.Header.Get("Accept")) == 0 {
+		// the request is shared with the caller, who must not see the header
+		copied := *req
+		copied.Header = make(http.Header)
+		for k, v := range req.Header {
+			copied.Header[k] = v
+		}
+		copied.Header.Set("Accept", MediaType+", application/json;q=0.9")
+		req = &copied
+	}
+	return t.RoundTripper.RoundTrip(req)
+}
@@ -0,0 +1,168 @@
+package compact
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	_ "github.com/GoogleCloudPlatform/kubernetes/pkg/api/v1beta1"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	watchjson "github.com/GoogleCloudPlatform/kubernetes/pkg/watch/json"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+	_ "github.com/openshift/origin/pkg/route/api/v1beta1"
+)
+
+func TestCodec(t *testing.T) {
+	pod := &kapi.Pod{
+		ObjectMeta: kapi.ObjectMeta{Name: "ruby", Namespace: "test", Labels: map[string]string{"app": "ruby"}},
+		Spec: kapi.PodSpec{
+			Containers: []kapi.Container{{Name: "ruby", Image: "openshift/ruby-20-centos7"}},
+		},
+	}
+	codec := NewCodec(kapi.Scheme, "v1beta1")
+	data, err := codec.Encode(pod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte(marker)) {
+		t.Fatalf("expected the compact encoding, got %q", data)
+	}
+	json, err := kapi.Scheme.EncodeToVersion(pod, "v1beta1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for name, data := range map[string][]byte{"compact": data, "json": json} {
+		obj, err := codec.Decode(data)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if !kapi.Semantic.DeepEqual(pod, obj) {
+			t.Errorf("%s: expected %#v, got %#v", name, pod, obj)
+		}
+		into := &kapi.Pod{}
+		if err := codec.DecodeInto(data, into); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if !kapi.Semantic.DeepEqual(pod, into) {
+			t.Errorf("%s: expected %#v, got %#v", name, pod, into)
+		}
+	}
+
+	if err := codec.DecodeInto(data, &kapi.Service{}); err == nil {
+		t.Errorf("expected an error decoding a pod into a service")
+	}
+}
+
+func TestCodecZeroPointers(t *testing.T) {
+	zero := 0
+	route := &routeapi.Route{
+		ObjectMeta:        kapi.ObjectMeta{Name: "canary", Namespace: "test"},
+		ServiceName:       "frontend",
+		ServiceWeight:     &zero,
+		AlternateBackends: []routeapi.RouteBackend{{ServiceName: "frontend-next", Weight: &zero}},
+	}
+	codec := NewCodec(kapi.Scheme, "v1beta1")
+	data, err := codec.Encode(route)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bytes.HasPrefix(data, []byte(marker)) {
+		t.Errorf("expected a weight of zero to be encoded as JSON")
+	}
+	obj, err := codec.Decode(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decoded := obj.(*routeapi.Route)
+	if decoded.ServiceWeight == nil || *decoded.ServiceWeight != 0 || decoded.AlternateBackends[0].Weight == nil || *decoded.AlternateBackends[0].Weight != 0 {
+		t.Errorf("expected the weights of zero to be kept, got %#v", decoded)
+	}
+
+	one := 1
+	route.ServiceWeight, route.AlternateBackends[0].Weight = &one, &one
+	if data, err = codec.Encode(route); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte(marker)) {
+		t.Errorf("expected other weights to be encoded compactly, got %q", data)
+	}
+}
+
+func TestHoldsPointers(t *testing.T) {
+	if !holdsPointers(reflect.TypeOf(routeapi.Route{})) {
+		t.Errorf("expected routes to hold pointers")
+	}
+	if holdsPointers(reflect.TypeOf(kapi.ObjectMeta{})) {
+		t.Errorf("expected object metadata not to hold pointers")
+	}
+}
+
+func TestWatchCodec(t *testing.T) {
+	route := &routeapi.Route{ObjectMeta: kapi.ObjectMeta{Name: "frontend", Namespace: "test"}, ServiceName: "frontend"}
+	buf := &bytes.Buffer{}
+	if err := watchjson.NewEncoder(buf, NewWatchCodec(kapi.Scheme, "v1beta1")).Encode(&watch.Event{Type: watch.Added, Object: route}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("frontend")) {
+		t.Errorf("expected the route to be framed in the compact encoding, got %q", buf.String())
+	}
+
+	decoder := watchjson.NewDecoder(ioutil.NopCloser(buf), NewClientCodec(kapi.Scheme, "v1beta1"))
+	eventType, obj, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if eventType != watch.Added || !kapi.Semantic.DeepEqual(route, obj) {
+		t.Errorf("expected %#v to be added, got %s %#v", route, eventType, obj)
+	}
+}
+
+func TestClientCodec(t *testing.T) {
+	codec := NewClientCodec(kapi.Scheme, "v1beta1")
+	if !IsCompact(codec) || IsCompact(kapi.Codec) {
+		t.Errorf("expected only the client codec to be compact")
+	}
+	data, err := codec.Encode(&kapi.Pod{ObjectMeta: kapi.ObjectMeta{Name: "ruby"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("{")) {
+		t.Errorf("expected clients to send JSON, got %q", data)
+	}
+}
+
+type recordingTransport struct {
+	accept string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.accept = req.Header.Get("Accept")
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func TestTransport(t *testing.T) {
+	rt := &recordingTransport{}
+	transport := NewTransport(rt)
+
+	req, _ := http.NewRequest("GET", "http://localhost/osapi/v1beta1/routes", nil)
+	transport.RoundTrip(req)
+	if rt.accept != "application/vnd.openshift.gob, application/json;q=0.9" {
+		t.Errorf("unexpected Accept header %q", rt.accept)
+	}
+	if len(req.Header.Get("Accept")) != 0 {
+		t.Errorf("expected the request of the caller to be unchanged")
+	}
+
+	req.Header.Set("Accept", "application/json")
+	transport.RoundTrip(req)
+	if rt.accept != "application/json" {
+		t.Errorf("expected the Accept header of the caller to be kept, got %q", rt.accept)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	_ "github.com/openshift/origin/pkg/api"
 	"github.com/openshift/origin/pkg/api/meta"
 	"github.com/openshift/origin/pkg/api/v1beta1"
+	"github.com/openshift/origin/pkg/api/v1beta2"
 )
 
 // Version is the string that represents the current external default version.
@@ -25,7 +26,7 @@ const OldestVersion = "v1beta1"
 // may be assumed to be least feature rich to most feature rich, and clients may
 // choose to prefer the latter items in the list over the former items when presented
 // with a set of versions to choose.
-var Versions = []string{"v1beta1"}
+var Versions = []string{"v1beta1", "v1beta2"}
 
 // Codec is the default codec for serializing output that should use
 // the latest supported version.  Use this Codec when writing to
@@ -51,6 +52,19 @@ var SelfLinker runtime.SelfLinker = accessor
 // Kubernetes versions.
 var RESTMapper kmeta.RESTMapper
 
+// CompactCodecFor returns the codec of the compact binary encoding of a version, or an error if the
+// version is not known.
+func CompactCodecFor(version string) (runtime.Codec, error) {
+	switch version {
+	case "v1beta1":
+		return v1beta1.CompactCodec, nil
+	case "v1beta2":
+		return v1beta2.CompactCodec, nil
+	default:
+		return nil, fmt.Errorf("unsupported version: %s (valid: %s)", version, strings.Join(Versions, ", "))
+	}
+}
+
 // InterfacesFor returns the default Codec and ResourceVersioner for a given version
 // string, or an error if the version is not known.
 func InterfacesFor(version string) (*kmeta.VersionInterfaces, error) {
@@ -61,6 +75,12 @@ func InterfacesFor(version string) (*kmeta.VersionInterfaces, error) {
 			ObjectConvertor:  api.Scheme,
 			MetadataAccessor: accessor,
 		}, nil
+	case "v1beta2":
+		return &kmeta.VersionInterfaces{
+			Codec:            v1beta2.Codec,
+			ObjectConvertor:  api.Scheme,
+			MetadataAccessor: accessor,
+		}, nil
 	default:
 		return nil, fmt.Errorf("unsupported storage version: %s (valid: %s)", version, strings.Join(Versions, ", "))
 	}
@@ -69,14 +89,14 @@ func InterfacesFor(version string) (*kmeta.VersionInterfaces, error) {
 // originTypes are the hardcoded types defined by the OpenShift API.
 var originTypes = []string{
 	"Build", "BuildConfig", "BuildLog",
-	"Deployment", "DeploymentConfig",
-	"Image", "ImageRepository", "ImageRepositoryMapping",
-	"Template", "TemplateConfig",
-	"Route",
-	"Project",
-	"User", "UserIdentityMapping",
-	"OAuthClient", "OAuthClientAuthorization", "OAuthAccessToken", "OAuthAuthorizeToken",
-	"Role", "RoleBinding", "Policy", "PolicyBinding",
+	"Deployment", "DeploymentConfig", "DeploymentLog",
+	"Image", "ImageRepository", "ImageRepositoryMapping", "ImageRepositoryRestore",
+	"Template", "TemplateConfig", "TemplateInstance",
+	"Route", "RouteStatusUpdate",
+	"Project", "ProjectRequest",
+	"User", "Identity", "UserIdentityMapping",
+	"OAuthClient", "OAuthClientAuthorization", "OAuthAccessToken", "OAuthAuthorizeToken", "BootstrapToken", "RouterToken",
+	"Role", "RoleBinding", "Policy", "PolicyBinding", "PolicyChangeReview",
 }
 
 // OriginKind returns true if OpenShift owns the kind described in a given apiVersion.
@@ -109,17 +129,20 @@ func init() {
 	// versions that used mixed case URL formats
 	versionMixedCase := map[string]bool{
 		"v1beta1": true,
+		"v1beta2": true,
 	}
 
-	// backwards compatibility, prior to v1beta2, we identified the namespace as a query parameter
+	// backwards compatibility, as with the Kubernetes v1beta1 and v1beta2 APIs, we identify the namespace as a query parameter
 	versionToNamespaceScope := map[string]kmeta.RESTScope{
 		"v1beta1": kmeta.RESTScopeNamespaceLegacy,
+		"v1beta2": kmeta.RESTScopeNamespaceLegacy,
 	}
 
 	// the list of kinds that are scoped at the root of the api hierarchy
 	// if a kind is not enumerated here, it is assumed to have a namespace scope
 	kindToRootScope := map[string]bool{
-		"Project": true,
+		"Project":        true,
+		"ProjectRequest": true,
 
 		"User":                true,
 		"Identity":            true,
@@ -129,6 +152,8 @@ func init() {
 		"OAuthAuthorizeToken":      true,
 		"OAuthClient":              true,
 		"OAuthClientAuthorization": true,
+		"BootstrapToken":           true,
+		"RouterToken":              true,
 	}
 
 	// enumerate all supported versions, get the kinds, and register with the mapper how to address our resources
@@ -0,0 +1,146 @@
+// Package selectable registers the fields of each OpenShift type that clients can select when they list or
+// watch objects of that type.
+package selectable
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	kmeta "github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// Func returns the selectable fields of an object.
+type Func func(obj runtime.Object) labels.Set
+
+var (
+	lock sync.RWMutex
+	// funcs are the registered field functions, by object type
+	funcs = make(map[reflect.Type]Func)
+	// names are the selectable field names, by object type
+	names = make(map[reflect.Type]util.StringSet)
+)
+
+// Register makes the fields returned by fn selectable on objects of the type of obj.  fn is called with obj
+// to learn the names of the fields, so it must accept an empty object.
+func Register(obj runtime.Object, fn Func) {
+	t := reflect.TypeOf(obj).Elem()
+	known := util.NewStringSet()
+	for name := range fn(obj) {
+		known.Insert(name)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	funcs[t] = fn
+	names[t] = known
+}
+
+// Fields returns the selectable fields of obj.  Only the name of an object of an unregistered type can be
+// selected.
+func Fields(obj runtime.Object) labels.Set {
+	lock.RLock()
+	fn, ok := funcs[reflect.TypeOf(obj).Elem()]
+	lock.RUnlock()
+	if ok {
+		return fn(obj)
+	}
+	meta, err := kmeta.Accessor(obj)
+	if err != nil {
+		return labels.Set{}
+	}
+	return labels.Set{"name": meta.Name()}
+}
+
+// FilterList removes the items of list that do not match label and field.  A bad request error is returned
+// if field selects a field that is not selectable on the items of list.
+func FilterList(list runtime.Object, label, field labels.Selector) error {
+	if label == nil {
+		label = labels.Everything()
+	}
+	if field == nil {
+		field = labels.Everything()
+	}
+	if label.Empty() && field.Empty() {
+		return nil
+	}
+	if v := reflect.ValueOf(list); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil
+	}
+	itemsPtr, err := runtime.GetItemsPtr(list)
+	if err != nil {
+		return err
+	}
+	if err := validate(reflect.TypeOf(itemsPtr).Elem().Elem(), field); err != nil {
+		return err
+	}
+
+	items, err := runtime.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	filtered := []runtime.Object{}
+	for _, item := range items {
+		meta, err := kmeta.Accessor(item)
+		if err != nil {
+			return err
+		}
+		if label.Matches(labels.Set(meta.Labels())) && field.Matches(Fields(item)) {
+			filtered = append(filtered, item)
+		}
+	}
+	return runtime.SetList(list, filtered)
+}
+
+// Matches returns true if the labels of obj match label and its selectable fields match field.
+func Matches(obj runtime.Object, label, field labels.Selector) bool {
+	meta, err := kmeta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	return (label == nil || label.Matches(labels.Set(meta.Labels()))) && (field == nil || field.Matches(Fields(obj)))
+}
+
+// ValidateFields returns a bad request error if field selects a field that is not selectable on objects of the
+// type of obj, such as a watch of those objects.
+func ValidateFields(obj runtime.Object, field labels.Selector) error {
+	if field == nil {
+		return nil
+	}
+	return validate(reflect.TypeOf(obj).Elem(), field)
+}
+
+// validate returns a bad request error if field selects a field that is not selectable on objects of type t.
+func validate(t reflect.Type, field labels.Selector) error {
+	if field.Empty() {
+		return nil
+	}
+	lock.RLock()
+	known, ok := names[t]
+	lock.RUnlock()
+	if !ok {
+		known = util.NewStringSet("name")
+	}
+	for _, name := range selectedNames(field) {
+		if !known.Has(name) {
+			return kerrors.NewBadRequest(fmt.Sprintf("field %q cannot be selected on %s, the supported fields are %s", name, t.Name(), strings.Join(known.List(), ", ")))
+		}
+	}
+	return nil
+}
+
+// selectedNames returns the names of the fields selector requires.
+func selectedNames(selector labels.Selector) []string {
+	result := []string{}
+	for _, term := range strings.Split(selector.String(), ",") {
+		if i := strings.IndexAny(term, "!="); i > 0 {
+			result = append(result, strings.TrimSpace(term[:i]))
+		}
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+package selectable
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+)
+
+func init() {
+	Register(&kapi.Pod{}, func(obj runtime.Object) labels.Set {
+		pod := obj.(*kapi.Pod)
+		return labels.Set{"name": pod.Name, "host": pod.Spec.Host}
+	})
+}
+
+func pods() *kapi.PodList {
+	return &kapi.PodList{Items: []kapi.Pod{
+		{ObjectMeta: kapi.ObjectMeta{Name: "a", Labels: map[string]string{"app": "web"}}, Spec: kapi.PodSpec{Host: "node1"}},
+		{ObjectMeta: kapi.ObjectMeta{Name: "b", Labels: map[string]string{"app": "web"}}, Spec: kapi.PodSpec{Host: "node2"}},
+		{ObjectMeta: kapi.ObjectMeta{Name: "c", Labels: map[string]string{"app": "db"}}, Spec: kapi.PodSpec{Host: "node1"}},
+	}}
+}
+
+func TestFilterList(t *testing.T) {
+	testCases := map[string]struct {
+		label, field string
+		expected     []string
+	}{
+		"everything":         {"", "", []string{"a", "b", "c"}},
+		"label":              {"app=web", "", []string{"a", "b"}},
+		"field":              {"", "host=node1", []string{"a", "c"}},
+		"label and field":    {"app=web", "host=node1", []string{"a"}},
+		"negated field":      {"", "host!=node1", []string{"b"}},
+		"several fields":     {"", "host=node1,name=c", []string{"c"}},
+		"nothing selected":   {"app=cache", "", []string{}},
+		"unregistered value": {"", "name=d", []string{}},
+	}
+
+	for name, tc := range testCases {
+		label, _ := labels.ParseSelector(tc.label)
+		field, _ := labels.ParseSelector(tc.field)
+		list := pods()
+		if err := FilterList(list, label, field); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		names := []string{}
+		for _, pod := range list.Items {
+			names = append(names, pod.Name)
+		}
+		if len(names) != len(tc.expected) {
+			t.Errorf("%s: expected %v, got %v", name, tc.expected, names)
+			continue
+		}
+		for i := range names {
+			if names[i] != tc.expected[i] {
+				t.Errorf("%s: expected %v, got %v", name, tc.expected, names)
+				break
+			}
+		}
+	}
+}
+
+func TestFilterListUnsupportedField(t *testing.T) {
+	field, _ := labels.ParseSelector("status=Running")
+	if err := FilterList(pods(), labels.Everything(), field); !kerrors.IsBadRequest(err) {
+		t.Errorf("expected a bad request for an unsupported field, got %v", err)
+	}
+
+	// only the name can be selected on types without registered fields
+	services := &kapi.ServiceList{Items: []kapi.Service{{ObjectMeta: kapi.ObjectMeta{Name: "a"}}, {ObjectMeta: kapi.ObjectMeta{Name: "b"}}}}
+	field, _ = labels.ParseSelector("name=b")
+	if err := FilterList(services, labels.Everything(), field); err != nil || len(services.Items) != 1 || services.Items[0].Name != "b" {
+		t.Errorf("expected services to be selected by name, got %v %v", services.Items, err)
+	}
+	field, _ = labels.ParseSelector("spec.portalIP=10.0.0.1")
+	if err := FilterList(services, labels.Everything(), field); !kerrors.IsBadRequest(err) {
+		t.Errorf("expected a bad request for an unregistered type, got %v", err)
+	}
+}
+
+func TestValidateFields(t *testing.T) {
+	field, _ := labels.ParseSelector("status=Running")
+	if err := ValidateFields(&kapi.Pod{}, field); !kerrors.IsBadRequest(err) {
+		t.Errorf("expected a bad request for an unsupported field, got %v", err)
+	}
+	field, _ = labels.ParseSelector("name=a")
+	if err := ValidateFields(&kapi.Pod{}, field); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateFields(&kapi.Pod{}, labels.Everything()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -18,9 +18,13 @@ import (
 	osapi "github.com/openshift/origin/pkg/api"
 	_ "github.com/openshift/origin/pkg/api/latest"
 	"github.com/openshift/origin/pkg/api/v1beta1"
+	"github.com/openshift/origin/pkg/api/v1beta2"
 	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
+	build "github.com/openshift/origin/pkg/build/api"
 	config "github.com/openshift/origin/pkg/config/api"
+	deploy "github.com/openshift/origin/pkg/deploy/api"
 	image "github.com/openshift/origin/pkg/image/api"
+	route "github.com/openshift/origin/pkg/route/api"
 	template "github.com/openshift/origin/pkg/template/api"
 )
 
@@ -98,6 +102,34 @@ var apiObjectFuzzer = fuzz.New().NilChance(.5).NumElements(1, 1).Funcs(
 		j.DockerImageMetadataVersion = []string{"pre012", "1.0"}[c.Rand.Intn(2)]
 		j.DockerImageReference = c.RandString()
 	},
+	// Objects must be fully defaulted to round trip
+	func(j *build.BuildStrategy, c fuzz.Continue) {
+		c.Fuzz(&j.Type)
+		c.Fuzz(&j.DockerStrategy)
+		c.Fuzz(&j.STIStrategy)
+		c.Fuzz(&j.CustomStrategy)
+		if j.Type == build.DockerBuildStrategyType && j.DockerStrategy == nil {
+			j.DockerStrategy = &build.DockerBuildStrategy{}
+		}
+	},
+	func(j *deploy.DeploymentStrategy, c fuzz.Continue) {
+		c.Fuzz(&j.Type)
+		c.Fuzz(&j.CustomParams)
+		if len(j.Type) == 0 {
+			j.Type = deploy.DeploymentStrategyTypeRecreate
+		}
+	},
+	func(j *route.TLSConfig, c fuzz.Continue) {
+		c.Fuzz(&j.Termination)
+		c.Fuzz(&j.Certificate)
+		c.Fuzz(&j.Key)
+		c.Fuzz(&j.CACertificate)
+		c.Fuzz(&j.DestinationCACertificate)
+		c.Fuzz(&j.InsecureEdgeTerminationPolicy)
+		if j.Termination == route.TLSTerminationEdge && len(j.InsecureEdgeTerminationPolicy) == 0 {
+			j.InsecureEdgeTerminationPolicy = route.InsecureEdgeTerminationPolicyAllow
+		}
+	},
 	func(j *config.Config, c fuzz.Continue) {
 		c.Fuzz(&j.ListMeta)
 		// TODO: replace with structured type definition
@@ -205,6 +237,9 @@ func TestTypes(t *testing.T) {
 				continue
 			}
 			runTest(t, v1beta1.Codec, item)
+			runTest(t, v1beta2.Codec, item)
+			runTest(t, v1beta1.CompactCodec, item)
+			runTest(t, v1beta2.CompactCodec, item)
 			runTest(t, osapi.Codec, item)
 		}
 	}
@@ -0,0 +1,155 @@
+package swagger
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/emicklei/go-restful/swagger"
+)
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	utilTimeType    = reflect.TypeOf(util.Time{})
+	intOrStringType = reflect.TypeOf(util.IntOrString{})
+	marshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// modelBuilder adds the models of API types to a Swagger declaration.
+type modelBuilder struct {
+	models map[string]swagger.Model
+	// types records the type each model was built from, so types of different packages with the same
+	// name get distinct models
+	types map[string]reflect.Type
+	// ids is the model id of each type a model was built from
+	ids map[reflect.Type]string
+}
+
+// newModelBuilder returns a builder that adds models to models.
+func newModelBuilder(models map[string]swagger.Model) *modelBuilder {
+	return &modelBuilder{models: models, types: map[string]reflect.Type{}, ids: map[reflect.Type]string{}}
+}
+
+// dataType returns the Swagger data type of values of t, adding a model for every struct it refers to.
+func (b *modelBuilder) dataType(t reflect.Type) swagger.DataTypeFields {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType || t == utilTimeType:
+		return primitive("string", "date-time")
+	case t == intOrStringType:
+		return primitive("string", "int-or-string")
+	case t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType):
+		// the fields of the type do not describe its encoding
+		return primitive("object", "")
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return primitive("boolean", "")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return primitive("integer", "int32")
+	case reflect.Int64, reflect.Uint64:
+		return primitive("integer", "int64")
+	case reflect.Float32:
+		return primitive("number", "float")
+	case reflect.Float64:
+		return primitive("number", "double")
+	case reflect.String:
+		return primitive("string", "")
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return primitive("string", "byte")
+		}
+		item := b.dataType(t.Elem())
+		fields := primitive("array", "")
+		fields.Items = []swagger.Item{{Type: item.Type, Ref: item.Ref, Format: item.Format}}
+		return fields
+	case reflect.Struct:
+		id := b.addModel(t)
+		return swagger.DataTypeFields{Ref: &id}
+	default:
+		// maps and interfaces hold arbitrary objects
+		return primitive("object", "")
+	}
+}
+
+// typeName returns the name of the data type described by fields, as used for the type of an operation.
+func (b *modelBuilder) typeName(fields swagger.DataTypeFields) string {
+	switch {
+	case fields.Ref != nil:
+		return *fields.Ref
+	case fields.Type != nil && *fields.Type == "array" && len(fields.Items) > 0:
+		item := fields.Items[0]
+		if item.Ref != nil {
+			return "array[" + *item.Ref + "]"
+		}
+		return "array[" + *item.Type + "]"
+	case fields.Type != nil:
+		return *fields.Type
+	}
+	return "void"
+}
+
+// addModel adds the model of the struct type t if it has not been added yet, and returns its id.
+func (b *modelBuilder) addModel(t reflect.Type) string {
+	if id, ok := b.ids[t]; ok {
+		return id
+	}
+	id := t.String()
+	if other, ok := b.types[id]; ok && other != t {
+		id = strings.Replace(t.PkgPath(), "/", ".", -1) + "." + t.Name()
+	}
+	b.ids[t], b.types[id] = id, t
+
+	// the model is referenced before its properties are built, for types that refer to themselves
+	model := swagger.Model{Id: id, Properties: map[string]swagger.ModelProperty{}}
+	b.models[id] = model
+	b.addProperties(&model, t)
+	b.models[id] = model
+	return id
+}
+
+// addProperties adds a property to model for every field of the struct type t in the JSON encoding.
+// Fields of inlined structs are properties of model.
+func (b *modelBuilder) addProperties(model *swagger.Model, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) != 0 {
+			// unexported
+			continue
+		}
+		parts := strings.Split(field.Tag.Get("json"), ",")
+		if parts[0] == "-" {
+			continue
+		}
+		if field.Anonymous && len(parts[0]) == 0 && field.Type.Kind() == reflect.Struct {
+			b.addProperties(model, field.Type)
+			continue
+		}
+		name, omitEmpty := parts[0], false
+		if len(name) == 0 {
+			name = field.Name
+		}
+		for _, option := range parts[1:] {
+			omitEmpty = omitEmpty || option == "omitempty"
+		}
+
+		property := swagger.ModelProperty{
+			DataTypeFields: b.dataType(field.Type),
+			Description:    field.Tag.Get("description"),
+		}
+		model.Properties[name] = property
+		if !omitEmpty && field.Type.Kind() != reflect.Ptr {
+			model.Required = append(model.Required, name)
+		}
+	}
+}
+
+// primitive returns the data type fields of a Swagger primitive type.
+func primitive(dataType, format string) swagger.DataTypeFields {
+	return swagger.DataTypeFields{Type: &dataType, Format: format}
+}
@@ -0,0 +1,205 @@
+// Package swagger describes the web services of the master in Swagger 1.2.  Unlike the description
+// go-restful generates, every object a service reads or writes is described by a full model built from its
+// API type: fields carry the description tag of the type, refer to the models of nested objects and are
+// marked required when the JSON encoding never omits them.
+package swagger
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	"github.com/emicklei/go-restful/swagger"
+)
+
+// swaggerVersion is the version of the Swagger specification the descriptions conform to.
+const swaggerVersion = "1.2"
+
+// Register serves a Swagger resource listing of services at apiPath on container, and the declaration of
+// each service at apiPath followed by the root path of the service.
+func Register(container *restful.Container, apiPath string, services []*restful.WebService) {
+	apiPath = strings.TrimSuffix(apiPath, "/")
+	declarations := Declarations(services)
+	container.Handle(apiPath+"/", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		resourcePath := strings.TrimPrefix(req.URL.Path, apiPath)
+		if resourcePath == "/" {
+			writeJSON(w, listing(declarations))
+			return
+		}
+		declaration, ok := declarations[resourcePath]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		scheme := "http"
+		if req.TLS != nil {
+			scheme = "https"
+		}
+		declaration.BasePath = scheme + "://" + req.Host
+		writeJSON(w, declaration)
+	}))
+}
+
+// Declarations returns the declaration of each of services by resource path.  Services registered at the
+// root path are declared per static path of their routes.
+func Declarations(services []*restful.WebService) map[string]swagger.ApiDeclaration {
+	declarations := map[string]swagger.ApiDeclaration{}
+	for _, service := range services {
+		rootPath := service.RootPath()
+		if rootPath != "" && rootPath != "/" {
+			declarations[rootPath] = declare(service, rootPath)
+			continue
+		}
+		for _, route := range service.Routes() {
+			path := staticPath(route.Path)
+			if _, ok := declarations[path]; !ok {
+				declarations[path] = declare(service, path)
+			}
+		}
+	}
+	return declarations
+}
+
+// declare returns the declaration of the routes of service below resourcePath.
+func declare(service *restful.WebService, resourcePath string) swagger.ApiDeclaration {
+	declaration := swagger.ApiDeclaration{
+		SwaggerVersion: swaggerVersion,
+		ApiVersion:     service.Version(),
+		ResourcePath:   resourcePath,
+		Models:         map[string]swagger.Model{},
+	}
+	models := newModelBuilder(declaration.Models)
+
+	rootParams := []swagger.Parameter{}
+	for _, param := range service.PathParameters() {
+		rootParams = append(rootParams, parameter(param.Data()))
+	}
+
+	paths := []string{}
+	routesByPath := map[string][]restful.Route{}
+	for _, route := range service.Routes() {
+		if !strings.HasPrefix(route.Path, resourcePath) {
+			continue
+		}
+		if _, ok := routesByPath[route.Path]; !ok {
+			paths = append(paths, route.Path)
+		}
+		routesByPath[route.Path] = append(routesByPath[route.Path], route)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		api := swagger.Api{Path: strings.TrimSuffix(path, "/"), Description: service.Documentation()}
+		for _, route := range routesByPath[path] {
+			operation := swagger.Operation{
+				Type:       "void",
+				Method:     route.Method,
+				Summary:    route.Doc,
+				Nickname:   route.Operation,
+				Parameters: append([]swagger.Parameter{}, rootParams...),
+				Produces:   route.Produces,
+				Consumes:   route.Consumes,
+			}
+			for _, param := range route.ParameterDocs {
+				data := param.Data()
+				p := parameter(data)
+				if data.Kind == restful.BodyParameterKind && route.ReadSample != nil {
+					p.DataTypeFields = models.dataType(reflect.TypeOf(route.ReadSample))
+				}
+				operation.Parameters = append(operation.Parameters, p)
+			}
+			if route.ReadSample != nil {
+				models.dataType(reflect.TypeOf(route.ReadSample))
+			}
+			if route.WriteSample != nil {
+				operation.Type = models.typeName(models.dataType(reflect.TypeOf(route.WriteSample)))
+			}
+			codes := []int{}
+			for code := range route.ResponseErrors {
+				codes = append(codes, code)
+			}
+			sort.Ints(codes)
+			for _, code := range codes {
+				responseError := route.ResponseErrors[code]
+				message := swagger.ResponseMessage{Code: code, Message: responseError.Message}
+				if responseError.Model != nil {
+					message.ResponseModel = models.typeName(models.dataType(reflect.TypeOf(responseError.Model)))
+				}
+				operation.ResponseMessages = append(operation.ResponseMessages, message)
+			}
+			api.Operations = append(api.Operations, operation)
+		}
+		declaration.Apis = append(declaration.Apis, api)
+	}
+	return declaration
+}
+
+// listing returns the resource listing of declarations, sorted by path.
+func listing(declarations map[string]swagger.ApiDeclaration) swagger.ResourceListing {
+	listing := swagger.ResourceListing{SwaggerVersion: swaggerVersion, Apis: []swagger.Resource{}}
+	for path, declaration := range declarations {
+		resource := swagger.Resource{Path: path}
+		if len(declaration.Apis) > 0 {
+			resource.Description = declaration.Apis[0].Description
+		}
+		listing.Apis = append(listing.Apis, resource)
+	}
+	sort.Sort(resourcesByPath(listing.Apis))
+	return listing
+}
+
+type resourcesByPath []swagger.Resource
+
+func (r resourcesByPath) Len() int           { return len(r) }
+func (r resourcesByPath) Less(i, j int) bool { return r[i].Path < r[j].Path }
+func (r resourcesByPath) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+
+// parameter returns the Swagger description of a route parameter.
+func parameter(data restful.ParameterData) swagger.Parameter {
+	dataType := data.DataType
+	param := swagger.Parameter{
+		DataTypeFields: swagger.DataTypeFields{Type: &dataType},
+		Name:           data.Name,
+		Description:    data.Description,
+		Required:       data.Required,
+		AllowMultiple:  data.AllowMultiple,
+	}
+	switch data.Kind {
+	case restful.PathParameterKind:
+		param.ParamType = "path"
+	case restful.QueryParameterKind:
+		param.ParamType = "query"
+	case restful.BodyParameterKind:
+		param.ParamType = "body"
+	case restful.HeaderParameterKind:
+		param.ParamType = "header"
+	case restful.FormParameterKind:
+		param.ParamType = "form"
+	}
+	return param
+}
+
+// staticPath returns the part of path before its first parameter, without a trailing slash.
+func staticPath(path string) string {
+	if i := strings.Index(path, "{"); i > 1 {
+		path = path[:i]
+	}
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+// writeJSON writes obj to w as JSON.
+func writeJSON(w http.ResponseWriter, obj interface{}) {
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", restful.MIME_JSON)
+	w.Write(data)
+}
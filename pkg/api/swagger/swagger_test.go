@@ -0,0 +1,196 @@
+package swagger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/emicklei/go-restful"
+	"github.com/emicklei/go-restful/swagger"
+)
+
+type testObject struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	Host     string            `json:"host" description:"the host the object is served under"`
+	Weight   *int              `json:"weight" description:"the share of traffic"`
+	Backends []testBackend     `json:"backends,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Data     []byte            `json:"data,omitempty"`
+	Started  util.Time         `json:"started,omitempty"`
+	Port     util.IntOrString  `json:"port,omitempty"`
+	Parent   *testObject       `json:"parent,omitempty"`
+	internal string
+}
+
+type testBackend struct {
+	Name string `json:"name"`
+}
+
+type testObjectList struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
+	Items         []testObject `json:"items"`
+}
+
+func noop(*restful.Request, *restful.Response) {}
+
+func testWebService() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Path("/osapi/v1beta1").ApiVersion("v1beta1").Doc("API at /osapi/v1beta1")
+	ws.Route(ws.GET("/objects").To(noop).Doc("list objects").Writes(&testObjectList{}))
+	ws.Route(ws.POST("/objects").To(noop).Doc("create an object").Reads(&testObject{}).Writes(&testObject{}))
+	ws.Route(ws.DELETE("/objects/{name}").To(noop).Doc("delete an object").Param(ws.PathParameter("name", "name of the object").DataType("string")))
+	return ws
+}
+
+func TestDeclarationModels(t *testing.T) {
+	declaration := Declarations([]*restful.WebService{testWebService()})["/osapi/v1beta1"]
+
+	model, ok := declaration.Models["swagger.testObject"]
+	if !ok {
+		t.Fatalf("Expected a model of the object, got %#v", declaration.Models)
+	}
+	if !reflect.DeepEqual(model.Required, []string{"host"}) {
+		t.Errorf("Expected only the host to be required, got %v", model.Required)
+	}
+	for name, expected := range map[string]struct {
+		dataType, ref, format, description string
+	}{
+		"kind":     {dataType: "string"},
+		"metadata": {ref: "api.ObjectMeta"},
+		"host":     {dataType: "string", description: "the host the object is served under"},
+		"weight":   {dataType: "integer", format: "int32", description: "the share of traffic"},
+		"backends": {dataType: "array"},
+		"labels":   {dataType: "object"},
+		"data":     {dataType: "string", format: "byte"},
+		"started":  {dataType: "string", format: "date-time"},
+		"port":     {dataType: "string", format: "int-or-string"},
+		"parent":   {ref: "swagger.testObject"},
+	} {
+		property, ok := model.Properties[name]
+		if !ok {
+			t.Errorf("Expected a property %s, got %#v", name, model.Properties)
+			continue
+		}
+		if dataType := stringValue(property.Type); dataType != expected.dataType {
+			t.Errorf("%s: expected type %q, got %q", name, expected.dataType, dataType)
+		}
+		if ref := stringValue(property.Ref); ref != expected.ref {
+			t.Errorf("%s: expected a reference to %q, got %q", name, expected.ref, ref)
+		}
+		if property.Format != expected.format {
+			t.Errorf("%s: expected format %q, got %q", name, expected.format, property.Format)
+		}
+		if property.Description != expected.description {
+			t.Errorf("%s: expected description %q, got %q", name, expected.description, property.Description)
+		}
+	}
+	if _, ok := model.Properties["internal"]; ok {
+		t.Errorf("Expected no property for an unexported field")
+	}
+	if items := model.Properties["backends"].Items; len(items) != 1 || stringValue(items[0].Ref) != "swagger.testBackend" {
+		t.Errorf("Expected backends to refer to the backend model, got %#v", items)
+	}
+
+	for _, id := range []string{"swagger.testObjectList", "swagger.testBackend", "api.ObjectMeta", "api.ListMeta"} {
+		if _, ok := declaration.Models[id]; !ok {
+			t.Errorf("Expected a model %s, got %#v", id, declaration.Models)
+		}
+	}
+	if required := declaration.Models["swagger.testBackend"].Required; !reflect.DeepEqual(required, []string{"name"}) {
+		t.Errorf("Expected the name of a backend to be required, got %v", required)
+	}
+}
+
+func TestDeclarationOperations(t *testing.T) {
+	declaration := Declarations([]*restful.WebService{testWebService()})["/osapi/v1beta1"]
+
+	operations := map[string]swagger.Operation{}
+	for _, api := range declaration.Apis {
+		for _, operation := range api.Operations {
+			operations[operation.Method+" "+api.Path] = operation
+		}
+	}
+	for key, expected := range map[string]string{
+		"GET /osapi/v1beta1/objects":           "swagger.testObjectList",
+		"POST /osapi/v1beta1/objects":          "swagger.testObject",
+		"DELETE /osapi/v1beta1/objects/{name}": "void",
+	} {
+		operation, ok := operations[key]
+		if !ok {
+			t.Errorf("Expected an operation %s, got %#v", key, operations)
+			continue
+		}
+		if operation.Type != expected {
+			t.Errorf("%s: expected type %q, got %q", key, expected, operation.Type)
+		}
+	}
+	if params := operations["DELETE /osapi/v1beta1/objects/{name}"].Parameters; len(params) != 1 || params[0].ParamType != "path" || params[0].Name != "name" {
+		t.Errorf("Expected the name path parameter, got %#v", params)
+	}
+}
+
+func TestModelIDsOfTypesWithTheSameName(t *testing.T) {
+	type testBackend struct {
+		Weight int `json:"weight"`
+	}
+	builder := newModelBuilder(map[string]swagger.Model{})
+	first := builder.addModel(reflect.TypeOf(testBackend{}))
+	second := builder.addModel(reflect.TypeOf(testBackend{}))
+	other := builder.addModel(reflect.TypeOf(testObject{}.Backends).Elem())
+	if first != second {
+		t.Errorf("Expected the same type to have one model, got %q and %q", first, second)
+	}
+	if first == other {
+		t.Errorf("Expected types with the same name to have different models, got %q", first)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	container := restful.NewContainer()
+	Register(container, "/swaggerapi/", []*restful.WebService{testWebService()})
+
+	w := httptest.NewRecorder()
+	container.ServeHTTP(w, newRequest("/swaggerapi/"))
+	listing := swagger.ResourceListing{}
+	if err := json.Unmarshal(w.Body.Bytes(), &listing); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(listing.Apis) != 1 || listing.Apis[0].Path != "/osapi/v1beta1" {
+		t.Errorf("Expected a listing of the service, got %#v", listing)
+	}
+
+	w = httptest.NewRecorder()
+	container.ServeHTTP(w, newRequest("/swaggerapi/osapi/v1beta1"))
+	declaration := swagger.ApiDeclaration{}
+	if err := json.Unmarshal(w.Body.Bytes(), &declaration); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if declaration.BasePath != "http://localhost:8443" || len(declaration.Models) == 0 {
+		t.Errorf("Expected a declaration with models, got %#v", declaration)
+	}
+
+	w = httptest.NewRecorder()
+	container.ServeHTTP(w, newRequest("/swaggerapi/osapi/v1beta3"))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected an unknown service to be not found, got %d", w.Code)
+	}
+}
+
+func newRequest(path string) *http.Request {
+	req, _ := http.NewRequest("GET", "http://localhost:8443"+path, nil)
+	return req
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
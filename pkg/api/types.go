@@ -1 +1,31 @@
 package api
+
+// APIGroup is the discovery document of the OpenShift API.  It is served at the root of the API and lists
+// the versions the server supports, as the Kubernetes API lists its versions.
+type APIGroup struct {
+	// Versions are the versions of the API the server supports
+	Versions []string `json:"versions" description:"versions of the API the server supports"`
+	// PreferredVersion is the version clients should use when they support several
+	PreferredVersion string `json:"preferredVersion" description:"version clients should use when they support several"`
+}
+
+// APIResourceList is the discovery document of a version of the OpenShift API, served at the root of the
+// version.  It lets generic clients find the resources of the version without a list of their own.
+type APIResourceList struct {
+	// Version is the version of the API the resources are served by
+	Version string `json:"version" description:"version of the API the resources are served by"`
+	// Resources are the resources of the version, sorted by name
+	Resources []APIResource `json:"resources" description:"resources of the version, sorted by name"`
+}
+
+// APIResource describes a resource of a version of the OpenShift API.
+type APIResource struct {
+	// Name is the name of the resource in its URLs
+	Name string `json:"name" description:"name of the resource in its URLs"`
+	// Kind is the kind of the objects of the resource
+	Kind string `json:"kind" description:"kind of the objects of the resource"`
+	// Namespaced is true if the objects of the resource belong to a namespace
+	Namespaced bool `json:"namespaced" description:"true if the objects of the resource belong to a namespace"`
+	// Verbs are the operations the resource supports: get, list, watch, create, update, patch and delete
+	Verbs []string `json:"verbs" description:"operations the resource supports: get, list, watch, create, update, patch and delete"`
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 
+	"github.com/openshift/origin/pkg/api/compact"
+	"github.com/openshift/origin/pkg/api/yaml"
 	_ "github.com/openshift/origin/pkg/authorization/api/v1beta1"
 	_ "github.com/openshift/origin/pkg/build/api/v1beta1"
 	_ "github.com/openshift/origin/pkg/config/api/v1beta1"
@@ -19,6 +21,16 @@ import (
 // Codec encodes internal objects to the v1beta1 scheme
 var Codec = runtime.CodecFor(api.Scheme, "v1beta1")
 
+// CompactCodec encodes internal objects to the compact binary encoding of the v1beta1 scheme
+var CompactCodec = compact.NewCodec(api.Scheme, "v1beta1")
+
+// CompactWatchCodec encodes internal objects to the compact binary encoding of the v1beta1 scheme written as
+// JSON strings, which are embedded in the JSON frames of watch events
+var CompactWatchCodec = compact.NewWatchCodec(api.Scheme, "v1beta1")
+
+// YAMLCodec encodes internal objects to the v1beta1 scheme as YAML
+var YAMLCodec = yaml.NewCodec(Codec)
+
 func init() {
 	api.Scheme.AddKnownTypes("v1beta1")
 }
@@ -0,0 +1,2 @@
+// Package v1beta2 is the v1beta2 version of the API.
+package v1beta2
@@ -0,0 +1,36 @@
+package v1beta2
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/api/compact"
+	"github.com/openshift/origin/pkg/api/yaml"
+	_ "github.com/openshift/origin/pkg/authorization/api/v1beta2"
+	_ "github.com/openshift/origin/pkg/build/api/v1beta2"
+	_ "github.com/openshift/origin/pkg/config/api/v1beta2"
+	_ "github.com/openshift/origin/pkg/deploy/api/v1beta2"
+	_ "github.com/openshift/origin/pkg/image/api/v1beta2"
+	_ "github.com/openshift/origin/pkg/oauth/api/v1beta2"
+	_ "github.com/openshift/origin/pkg/project/api/v1beta2"
+	_ "github.com/openshift/origin/pkg/route/api/v1beta2"
+	_ "github.com/openshift/origin/pkg/template/api/v1beta2"
+	_ "github.com/openshift/origin/pkg/user/api/v1beta2"
+)
+
+// Codec encodes internal objects to the v1beta2 scheme
+var Codec = runtime.CodecFor(api.Scheme, "v1beta2")
+
+// CompactCodec encodes internal objects to the compact binary encoding of the v1beta2 scheme
+var CompactCodec = compact.NewCodec(api.Scheme, "v1beta2")
+
+// CompactWatchCodec encodes internal objects to the compact binary encoding of the v1beta2 scheme written as
+// JSON strings, which are embedded in the JSON frames of watch events
+var CompactWatchCodec = compact.NewWatchCodec(api.Scheme, "v1beta2")
+
+// YAMLCodec encodes internal objects to the v1beta2 scheme as YAML
+var YAMLCodec = yaml.NewCodec(Codec)
+
+func init() {
+	api.Scheme.AddKnownTypes("v1beta2")
+}
@@ -0,0 +1 @@
+package v1beta2
@@ -0,0 +1,46 @@
+// Package yaml provides a codec that encodes API objects as YAML.
+//
+// Objects are decoded by the codec that is wrapped, which reads YAML as well as JSON since YAML is a superset
+// of JSON.
+package yaml
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/ghodss/yaml"
+)
+
+// MediaType is the media type of objects encoded as YAML.
+const MediaType = "application/yaml"
+
+// mediaTypes are the media types clients may send or accept YAML as.
+var mediaTypes = map[string]bool{
+	"application/yaml":   true,
+	"application/x-yaml": true,
+	"text/yaml":          true,
+	"text/x-yaml":        true,
+}
+
+// IsMediaType returns true if mediaType names YAML.
+func IsMediaType(mediaType string) bool {
+	return mediaTypes[mediaType]
+}
+
+// codec encodes objects with a JSON codec and converts them to YAML.
+type codec struct {
+	runtime.Codec
+}
+
+// NewCodec returns a codec that encodes objects as YAML in the form json encodes them, and decodes YAML or
+// JSON with json.
+func NewCodec(json runtime.Codec) runtime.Codec {
+	return codec{json}
+}
+
+// Encode implements runtime.Codec
+func (c codec) Encode(obj runtime.Object) ([]byte, error) {
+	data, err := c.Codec.Encode(obj)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.JSONToYAML(data)
+}
@@ -0,0 +1,34 @@
+package yaml
+
+import (
+	"strings"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/v1beta1"
+)
+
+func TestCodec(t *testing.T) {
+	pod := &kapi.Pod{
+		ObjectMeta: kapi.ObjectMeta{Name: "ruby", Namespace: "test"},
+	}
+	codec := NewCodec(v1beta1.Codec)
+	data, err := codec.Encode(pod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), "\nid: ruby\n") || strings.HasPrefix(string(data), "{") {
+		t.Fatalf("expected the pod as YAML, got %q", data)
+	}
+
+	for name, data := range map[string][]byte{"yaml": data, "json": []byte(`{"kind":"Pod","apiVersion":"v1beta1","id":"ruby","namespace":"test"}`)} {
+		obj, err := codec.Decode(data)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if decoded, ok := obj.(*kapi.Pod); !ok || decoded.Name != "ruby" || decoded.Namespace != "test" {
+			t.Errorf("%s: unexpected object: %#v", name, obj)
+		}
+	}
+}
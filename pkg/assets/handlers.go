@@ -1,14 +1,16 @@
 package assets
 
 import (
+	"bytes"
 	"compress/gzip"
 	"encoding/hex"
 	"fmt"
-	"html/template"
+	"html"
 	"io"
 	"net/http"
 	"regexp"
 	"strings"
+	"text/template"
 
 	"github.com/golang/glog"
 )
@@ -76,10 +78,11 @@ func CacheControlHandler(version string, h http.Handler) http.Handler {
 	})
 }
 
-func HTML5ModeHandler(h http.Handler) http.Handler {
+// HTML5ModeHandler serves index.html, read with asset, for every path that is not an asset.
+func HTML5ModeHandler(asset func(string) ([]byte, error), h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if _, err := Asset(strings.TrimPrefix(r.URL.Path, "/")); err != nil {
-			b, err := Asset("index.html")
+		if _, err := asset(strings.TrimPrefix(r.URL.Path, "/")); err != nil {
+			b, err := asset("index.html")
 			if err != nil {
 				http.Error(w, "Failed to read index.html", http.StatusInternalServerError)
 				return
@@ -92,6 +95,34 @@ func HTML5ModeHandler(h http.Handler) http.Handler {
 	})
 }
 
+// BaseAsset returns an asset function that reads assets with asset, and points the base element of index.html at
+// base so that the web console can be served under a path other than /.
+func BaseAsset(asset func(string) ([]byte, error), base string) func(string) ([]byte, error) {
+	return func(name string) ([]byte, error) {
+		data, err := asset(name)
+		if err != nil || name != "index.html" {
+			return data, err
+		}
+		return bytes.Replace(data, []byte(`<base href="/"`), []byte(`<base href="`+html.EscapeString(base)+`"`), 1), nil
+	}
+}
+
+// APIProxyHandler forwards requests that carry the bearer token of a user, in the Authorization header or in
+// the access_token parameter web sockets use, to proxy.  The asset server holds no credentials of its own, so
+// other requests are rejected rather than forwarded anonymously, and cookies are never forwarded.
+func APIProxyHandler(proxy http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := strings.TrimSpace(r.Header.Get("Authorization"))
+		if !strings.HasPrefix(strings.ToLower(authorization), "bearer ") && len(r.URL.Query().Get("access_token")) == 0 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="OpenShift"`)
+			http.Error(w, "A bearer token is required", http.StatusUnauthorized)
+			return
+		}
+		r.Header.Del("Cookie")
+		proxy.ServeHTTP(w, r)
+	})
+}
+
 var configTemplate = template.Must(template.New("webConsoleConfig").Parse(`
 window.OPENSHIFT_CONFIG = {
   api: {
@@ -107,9 +138,50 @@ window.OPENSHIFT_CONFIG = {
   auth: {
   	oauth_authorize_uri: "{{ .OAuthAuthorizeURL | js}}",
   	oauth_redirect_base: "{{ .OAuthRedirectBase | js}}",
-  	oauth_client_id: "{{ .OAuthClientID | js}}"
+  	oauth_client_id: "{{ .OAuthClientID | js}}",
+  	logout_uri: "{{ .LogoutURL | js}}",
+  	token_max_age_seconds: {{ .AccessTokenMaxAgeSeconds }},
+  	token_renewal_seconds: {{ .TokenRenewalSeconds }}
+  },
+  branding: {
+    productName: "{{ .Extensions.ProductName | js}}",
+    logoImageURL: "{{ .Extensions.LogoImageURL | js}}"
+  },
+  extensions: {
+    scripts: [{{ range $i, $url := .Extensions.Scripts }}{{ if $i }}, {{ end }}"{{ $url | js}}"{{ end }}],
+    stylesheets: [{{ range $i, $url := .Extensions.Stylesheets }}{{ if $i }}, {{ end }}"{{ $url | js}}"{{ end }}]
   }
 };
+
+(function(branding, extensions) {
+  var logo = document.querySelector(".navbar-brand img");
+  if (branding.productName) {
+    document.title = branding.productName;
+    if (logo) {
+      logo.alt = branding.productName;
+    }
+  }
+  if (branding.logoImageURL && logo) {
+    logo.src = branding.logoImageURL;
+  }
+
+  var head = document.getElementsByTagName("head")[0];
+  extensions.stylesheets.forEach(function(url) {
+    var link = document.createElement("link");
+    link.rel = "stylesheet";
+    link.href = url;
+    head.appendChild(link);
+  });
+  // scripts are added once the scripts of the console have run, in the order they are listed in
+  document.addEventListener("DOMContentLoaded", function() {
+    extensions.scripts.forEach(function(url) {
+      var script = document.createElement("script");
+      script.src = url;
+      script.async = false;
+      head.appendChild(script);
+    });
+  });
+})(window.OPENSHIFT_CONFIG.branding, window.OPENSHIFT_CONFIG.extensions);
 `))
 
 type WebConsoleConfig struct {
@@ -127,6 +199,28 @@ type WebConsoleConfig struct {
 	OAuthRedirectBase string
 	// OAuthClientID is the OAuth2 client_id to use to request an API token. It must be authorized to redirect to the web console URL.
 	OAuthClientID string
+	// Extensions brands the web console and adds scripts and stylesheets to it
+	Extensions WebConsoleExtensions
+
+	// LogoutURL is where the web console sends users after logging out. The console shows its own page if empty.
+	LogoutURL string
+	// AccessTokenMaxAgeSeconds is how long the tokens granted to the web console last, or zero if unknown
+	AccessTokenMaxAgeSeconds int
+	// TokenRenewalSeconds is how long before its token expires the web console should request a new one, rather
+	// than waiting for a request to fail with a 401.  Users are prompted to log in again if their session is gone.
+	TokenRenewalSeconds int
+}
+
+// WebConsoleExtensions brands and extends the web console without rebuilding its assets.
+type WebConsoleExtensions struct {
+	// ProductName replaces the product name shown by the web console, if set
+	ProductName string
+	// LogoImageURL replaces the logo shown by the web console, if set
+	LogoImageURL string
+	// Scripts are the URLs of scripts loaded after the web console's own, in order
+	Scripts []string
+	// Stylesheets are the URLs of stylesheets loaded after the web console's own, in order
+	Stylesheets []string
 }
 
 func GeneratedConfigHandler(config WebConsoleConfig, h http.Handler) http.Handler {
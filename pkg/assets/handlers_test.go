@@ -5,7 +5,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -220,3 +222,95 @@ func TestCacheWithValidEtag(t *testing.T) {
 		t.Fatalf("Expected status to be Not Modified (304), got %d.  Expected etag was %s, actual was %s", writer.Code, etag, writer.Header().Get("ETag"))
 	}
 }
+
+func TestGeneratedConfigExtensions(t *testing.T) {
+	config := WebConsoleConfig{
+		MasterAddr: "master.example.com:8443",
+		Extensions: WebConsoleExtensions{
+			ProductName:  `Example "Console"`,
+			LogoImageURL: "https://example.com/logo.png",
+			Scripts:      []string{"https://example.com/a.js", "https://example.com/b.js"},
+			Stylesheets:  []string{"https://example.com/a.css"},
+		},
+		LogoutURL:                "https://sso.example.com/logout",
+		AccessTokenMaxAgeSeconds: 3600,
+		TokenRenewalSeconds:      360,
+	}
+	handler := GeneratedConfigHandler(config, stubHandler("asset"))
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, &http.Request{Method: "GET", URL: &url.URL{Path: "/config.js"}})
+
+	body := writer.Body.String()
+	for _, expected := range []string{
+		`productName: "Example \"Console\""`,
+		`logoImageURL: "https://example.com/logo.png"`,
+		`scripts: ["https://example.com/a.js", "https://example.com/b.js"]`,
+		`stylesheets: ["https://example.com/a.css"]`,
+		`logout_uri: "https://sso.example.com/logout"`,
+		`token_max_age_seconds: 3600`,
+		`token_renewal_seconds: 360`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected config.js to contain %s, got:\n%s", expected, body)
+		}
+	}
+
+	writer = httptest.NewRecorder()
+	GeneratedConfigHandler(WebConsoleConfig{}, stubHandler("asset")).ServeHTTP(writer, &http.Request{Method: "GET", URL: &url.URL{Path: "/config.js"}})
+	if body := writer.Body.String(); !strings.Contains(body, "scripts: [],") || !strings.Contains(body, "stylesheets: []") {
+		t.Errorf("Expected empty extensions, got:\n%s", body)
+	}
+}
+
+func TestBaseAsset(t *testing.T) {
+	asset := func(name string) ([]byte, error) {
+		return []byte(`<html><head><base href="/" /></head></html>`), nil
+	}
+	if data, _ := BaseAsset(asset, "/console/")("index.html"); string(data) != `<html><head><base href="/console/" /></head></html>` {
+		t.Errorf("unexpected index.html: %s", data)
+	}
+	if data, _ := BaseAsset(asset, "/console/")("views/other.html"); string(data) != `<html><head><base href="/" /></head></html>` {
+		t.Errorf("expected other assets to be unchanged: %s", data)
+	}
+}
+
+func TestAPIProxyHandler(t *testing.T) {
+	var proxied *http.Request
+	handler := APIProxyHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r
+	}))
+
+	testCases := map[string]struct {
+		header  http.Header
+		query   string
+		proxied bool
+	}{
+		"bearer token":    {header: http.Header{"Authorization": {"Bearer token"}, "Cookie": {"ssn=secret"}}, proxied: true},
+		"parameter token": {query: "watch=true&access_token=token", proxied: true},
+		"basic auth":      {header: http.Header{"Authorization": {"Basic dXNlcjpwYXNz"}}},
+		"anonymous":       {header: http.Header{"Cookie": {"ssn=secret"}}},
+	}
+	for name, testCase := range testCases {
+		proxied = nil
+		header := testCase.header
+		if header == nil {
+			header = http.Header{}
+		}
+		writer := httptest.NewRecorder()
+		handler.ServeHTTP(writer, &http.Request{Method: "GET", URL: &url.URL{Path: "/osapi/v1beta1/projects", RawQuery: testCase.query}, Header: header})
+
+		if !testCase.proxied {
+			if proxied != nil || writer.Code != http.StatusUnauthorized {
+				t.Errorf("%s: expected the request to be rejected, got %d", name, writer.Code)
+			}
+			continue
+		}
+		if proxied == nil {
+			t.Errorf("%s: expected the request to be proxied, got %d", name, writer.Code)
+			continue
+		}
+		if len(proxied.Header.Get("Cookie")) != 0 {
+			t.Errorf("%s: expected cookies to be removed, got %v", name, proxied.Header)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package assets
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// Overlay serves the files under Dir in preference to the embedded assets, so that assets can be
+// replaced without rebuilding the binary.  The embedded assets are served alone if Dir is empty.
+type Overlay struct {
+	Dir string
+}
+
+// Asset returns the file name from Dir if it exists there, and the embedded asset otherwise.
+func (o Overlay) Asset(name string) ([]byte, error) {
+	if len(o.Dir) > 0 {
+		data, err := ioutil.ReadFile(o.path(name))
+		if err == nil {
+			return data, nil
+		}
+		if !os.IsNotExist(err) {
+			if info, statErr := os.Stat(o.path(name)); statErr != nil || !info.IsDir() {
+				return nil, err
+			}
+		}
+	}
+	return Asset(name)
+}
+
+// AssetDir returns the names of the files in directory name, in Dir and in the embedded assets.
+func (o Overlay) AssetDir(name string) ([]string, error) {
+	embedded, err := AssetDir(name)
+	if len(o.Dir) == 0 {
+		return embedded, err
+	}
+	infos, dirErr := ioutil.ReadDir(o.path(name))
+	if dirErr != nil {
+		return embedded, err
+	}
+
+	names := map[string]bool{}
+	for _, child := range embedded {
+		names[child] = true
+	}
+	for _, info := range infos {
+		names[info.Name()] = true
+	}
+	children := []string{}
+	for child := range names {
+		children = append(children, child)
+	}
+	sort.Strings(children)
+	return children, nil
+}
+
+// path returns the location of the asset name under Dir.  Names cannot refer to files outside of Dir.
+func (o Overlay) path(name string) string {
+	return filepath.Join(o.Dir, filepath.FromSlash(path.Clean("/"+name)))
+}
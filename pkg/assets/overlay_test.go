@@ -0,0 +1,63 @@
+package assets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "assets")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.MkdirAll(filepath.Join(dir, "scripts"), 0755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte("patched"), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "scripts", "extra.js"), []byte("extra"), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	overlay := Overlay{Dir: dir}
+
+	if data, err := overlay.Asset("index.html"); err != nil || string(data) != "patched" {
+		t.Errorf("expected the file on disk to win, got %q %v", data, err)
+	}
+	if data, err := overlay.Asset("scripts/extra.js"); err != nil || string(data) != "extra" {
+		t.Errorf("expected a file only on disk, got %q %v", data, err)
+	}
+	embedded, err := Asset("scripts/scripts.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, err := overlay.Asset("scripts/scripts.js"); err != nil || !reflect.DeepEqual(data, embedded) {
+		t.Errorf("expected the embedded asset, got %v", err)
+	}
+	if _, err := overlay.Asset("../" + filepath.Base(dir) + "/index.html"); err == nil {
+		t.Errorf("expected names outside of the directory to be served from the embedded assets only")
+	}
+	if _, err := overlay.Asset("missing.js"); err == nil {
+		t.Errorf("expected an error for a missing asset")
+	}
+
+	children, err := overlay.AssetDir("scripts")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found := map[string]bool{}
+	for _, child := range children {
+		found[child] = true
+	}
+	if !found["extra.js"] || !found["scripts.js"] {
+		t.Errorf("expected the files on disk and embedded, got %v", children)
+	}
+
+	if data, err := (Overlay{}).Asset("index.html"); err != nil || string(data) == "patched" {
+		t.Errorf("expected the embedded asset without a directory, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package assets
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// DefaultFrameOptions keeps the web console from being framed by other sites
+	DefaultFrameOptions = "DENY"
+	// DefaultStrictTransportSecurity has browsers use https for the asset server for a year
+	DefaultStrictTransportSecurity = "max-age=31536000"
+)
+
+// SecurityHeaders are the security related response headers of the asset server.  Empty headers are not sent.
+type SecurityHeaders struct {
+	// ContentSecurityPolicy restricts where the web console loads scripts, stylesheets and data from
+	ContentSecurityPolicy string
+	// FrameOptions is the X-Frame-Options header
+	FrameOptions string
+	// StrictTransportSecurity is the Strict-Transport-Security header, only sent on https requests
+	StrictTransportSecurity string
+}
+
+// SecurityHeadersHandler sets headers on every response of h.
+func SecurityHeadersHandler(headers SecurityHeaders, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(headers.ContentSecurityPolicy) > 0 {
+			w.Header().Set("Content-Security-Policy", headers.ContentSecurityPolicy)
+		}
+		if len(headers.FrameOptions) > 0 {
+			w.Header().Set("X-Frame-Options", headers.FrameOptions)
+		}
+		// browsers ignore the header on http, and sending it there would pin clients that never saw the certificate
+		if len(headers.StrictTransportSecurity) > 0 && r.TLS != nil {
+			w.Header().Set("Strict-Transport-Security", headers.StrictTransportSecurity)
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// ContentSecurityPolicy returns a policy that lets the web console load its own assets and those of extensions,
+// and call the APIs at apiURLs over http and web sockets.
+func ContentSecurityPolicy(apiURLs []string, extensions WebConsoleExtensions) string {
+	scripts := []string{"'self'"}
+	styles := []string{"'self'", "'unsafe-inline'"}
+	images := []string{"'self'", "data:"}
+	connect := []string{"'self'"}
+	for _, s := range extensions.Scripts {
+		scripts = appendOrigin(scripts, s)
+	}
+	for _, s := range extensions.Stylesheets {
+		styles = appendOrigin(styles, s)
+	}
+	images = appendOrigin(images, extensions.LogoImageURL)
+	for _, s := range apiURLs {
+		connect = appendOrigin(connect, s)
+		if u, err := url.Parse(s); err == nil {
+			// the console watches resources over web sockets on the same hosts
+			u.Scheme = strings.Replace(u.Scheme, "http", "ws", 1)
+			connect = appendOrigin(connect, u.String())
+		}
+	}
+	return strings.Join([]string{
+		"default-src 'self'",
+		"script-src " + strings.Join(scripts, " "),
+		"style-src " + strings.Join(styles, " "),
+		"img-src " + strings.Join(images, " "),
+		"font-src 'self' data:",
+		"connect-src " + strings.Join(connect, " "),
+	}, "; ")
+}
+
+// appendOrigin appends the scheme and host of an absolute URL to sources, relative URLs are served by the asset
+// server itself.
+func appendOrigin(sources []string, s string) []string {
+	u, err := url.Parse(s)
+	if err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+		return sources
+	}
+	origin := u.Scheme + "://" + u.Host
+	for _, existing := range sources {
+		if existing == origin {
+			return sources
+		}
+	}
+	return append(sources, origin)
+}
@@ -0,0 +1,58 @@
+package assets
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSecurityHeadersHandler(t *testing.T) {
+	headers := SecurityHeaders{
+		ContentSecurityPolicy:   "default-src 'self'",
+		FrameOptions:            DefaultFrameOptions,
+		StrictTransportSecurity: DefaultStrictTransportSecurity,
+	}
+	handler := SecurityHeadersHandler(headers, stubHandler("hello"))
+
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, &http.Request{Method: "GET"})
+	if writer.Header().Get("Content-Security-Policy") != "default-src 'self'" || writer.Header().Get("X-Frame-Options") != "DENY" {
+		t.Errorf("unexpected headers: %v", writer.Header())
+	}
+	if len(writer.Header().Get("Strict-Transport-Security")) != 0 {
+		t.Errorf("expected no Strict-Transport-Security header over http: %v", writer.Header())
+	}
+
+	writer = httptest.NewRecorder()
+	handler.ServeHTTP(writer, &http.Request{Method: "GET", TLS: &tls.ConnectionState{}})
+	if writer.Header().Get("Strict-Transport-Security") != DefaultStrictTransportSecurity {
+		t.Errorf("unexpected headers: %v", writer.Header())
+	}
+
+	writer = httptest.NewRecorder()
+	SecurityHeadersHandler(SecurityHeaders{}, stubHandler("hello")).ServeHTTP(writer, &http.Request{Method: "GET", TLS: &tls.ConnectionState{}})
+	for _, header := range []string{"Content-Security-Policy", "X-Frame-Options", "Strict-Transport-Security"} {
+		if _, ok := writer.Header()[header]; ok {
+			t.Errorf("expected no %s header: %v", header, writer.Header())
+		}
+	}
+}
+
+func TestContentSecurityPolicy(t *testing.T) {
+	extensions := WebConsoleExtensions{
+		LogoImageURL: "https://cdn.example.com/logo.png",
+		Scripts:      []string{"https://cdn.example.com/a.js", "https://cdn.example.com/b.js", "extensions/c.js"},
+		Stylesheets:  []string{"http://styles.example.com:8080/a.css"},
+	}
+	policy := ContentSecurityPolicy([]string{"https://master.example.com:8443", "https://master.example.com:8443"}, extensions)
+	expected := "default-src 'self'; " +
+		"script-src 'self' https://cdn.example.com; " +
+		"style-src 'self' 'unsafe-inline' http://styles.example.com:8080; " +
+		"img-src 'self' data: https://cdn.example.com; " +
+		"font-src 'self' data:; " +
+		"connect-src 'self' https://master.example.com:8443 wss://master.example.com:8443"
+	if policy != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, policy)
+	}
+}
@@ -1,5 +1,38 @@
 package api
 
+import "strings"
+
+// ClientNameExtraKey is the key of the extra information of a user authenticated with an OAuth token that holds
+// the name of the client the token was granted to
+const ClientNameExtraKey = "clientName"
+
+const (
+	// BootstrapTokenUsername is the user a bootstrap token authenticates as
+	BootstrapTokenUsername = "system:bootstrap"
+	// BootstrapTokenGroup is the group of the users bootstrap tokens authenticate as, which the bootstrap policy
+	// restricts to joining the cluster
+	BootstrapTokenGroup = "system:bootstrappers"
+)
+
+const (
+	// UnauthenticatedUsername is the user requests without credentials are served as
+	UnauthenticatedUsername = "system:anonymous"
+	// UnauthenticatedGroup is the group of UnauthenticatedUsername
+	UnauthenticatedGroup = "system:unauthenticated"
+)
+
+const (
+	// RouterTokenUsernamePrefix prefixes the name of the router a router token was created for to make the user
+	// the token authenticates as
+	RouterTokenUsernamePrefix = "system:router:"
+	// RouterTokenGroup is the group of the users router tokens authenticate as, which the bootstrap policy
+	// restricts to reading routes and endpoints
+	RouterTokenGroup = "system:routers"
+	// RouterNamespacesExtraKey is the key of the extra information of a user authenticated with a router token
+	// that holds the comma separated namespaces the token is limited to
+	RouterNamespacesExtraKey = "namespaces"
+)
+
 // TODO: Add display name to common meta?
 type UserInfo interface {
 	GetName() string
@@ -94,3 +127,46 @@ func (i *DefaultUserIdentityInfo) GetProviderName() string {
 func (i *DefaultUserIdentityInfo) GetExtra() map[string]string {
 	return i.Extra
 }
+
+// UserContextPath is where the master describes the user a request is made as.
+const UserContextPath = "/osapi/whoami"
+
+// UserContext describes the user a request is made as, and the roles it is bound to, for clients that log in.
+type UserContext struct {
+	Name   string   `json:"name"`
+	UID    string   `json:"uid,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+	// Scopes the user granted the OAuth client the request was made through.  No scopes mean the client acts
+	// with all the rights of the user.
+	Scopes []string `json:"scopes,omitempty"`
+	// Client is the OAuth client the request was made through, if any
+	Client string `json:"client,omitempty"`
+	// Impersonator is the authenticated user that made the request as this user, if any
+	Impersonator string `json:"impersonator,omitempty"`
+	// Roles maps the namespaces the user is bound to roles in to the names of those roles
+	Roles map[string][]string `json:"roles,omitempty"`
+	// Namespaces the credential of the request is limited to, if any
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// LimitedNamespaces returns the namespaces the credential user authenticated with is limited to, and false if
+// it is not limited.
+func LimitedNamespaces(user UserInfo) ([]string, bool) {
+	value, ok := user.GetExtra()[RouterNamespacesExtraKey]
+	if !ok {
+		return nil, false
+	}
+	namespaces := []string{}
+	for _, namespace := range strings.Split(value, ",") {
+		if len(namespace) > 0 {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces, true
+}
+
+// RouterName returns the name of the router the user reports the status of routes as: the router a router token
+// was created for, or the name of any other user.
+func RouterName(userName string) string {
+	return strings.TrimPrefix(userName, RouterTokenUsernamePrefix)
+}
@@ -1,36 +1,94 @@
 package context
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"net/http"
+	"strconv"
 	"sync"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 )
 
-type RequestContextMap struct {
-	objs map[*http.Request]interface{}
-	lock sync.Mutex
+// RequestContextMapper holds the context of every request in flight.  A context is created by
+// NewRequestContextFilter when a request enters the filter, and removed when the request has been served, so
+// handlers can attach data to a request without any of it outliving the request.
+type RequestContextMapper struct {
+	contexts map[*http.Request]kapi.Context
+	lock     sync.RWMutex
+}
+
+// NewRequestContextMapper returns a mapper with no requests in flight.
+func NewRequestContextMapper() *RequestContextMapper {
+	return &RequestContextMapper{
+		contexts: make(map[*http.Request]kapi.Context),
+	}
+}
+
+// Get returns the context of req, if it is in flight.
+func (m *RequestContextMapper) Get(req *http.Request) (kapi.Context, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	ctx, ok := m.contexts[req]
+	return ctx, ok
 }
 
-func NewRequestContextMap() *RequestContextMap {
-	return &RequestContextMap{
-		objs: make(map[*http.Request]interface{}),
+// Update replaces the context of req.  It fails if req did not pass through NewRequestContextFilter or has
+// already been served.
+func (m *RequestContextMapper) Update(req *http.Request, ctx kapi.Context) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.contexts[req]; !ok {
+		return errors.New("the request is not in flight")
 	}
+	m.contexts[req] = ctx
+	return nil
 }
 
-func (c *RequestContextMap) Set(req *http.Request, obj interface{}) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	c.objs[req] = obj
+// ServeWithContext serves req with handler while ctx is its context, for requests a handler makes on behalf of
+// the request it serves.  The context is removed once handler returns.
+func (m *RequestContextMapper) ServeWithContext(w http.ResponseWriter, req *http.Request, ctx kapi.Context, handler http.Handler) {
+	m.init(req, ctx)
+	defer m.remove(req)
+	handler.ServeHTTP(w, req)
 }
 
-func (c *RequestContextMap) Remove(req *http.Request) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	delete(c.objs, req)
+func (m *RequestContextMapper) init(req *http.Request, ctx kapi.Context) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.contexts[req] = ctx
 }
 
-func (c *RequestContextMap) Get(req *http.Request) (interface{}, bool) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	obj, ok := c.objs[req]
-	return obj, ok
+func (m *RequestContextMapper) remove(req *http.Request) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.contexts, req)
+}
+
+// NewRequestContextFilter creates a context for every request served by handler, with a new audit ID and a
+// deadline timeout from now, or sooner if the request asks for a shorter timeout.
+func NewRequestContextFilter(mapper *RequestContextMapper, timeout time.Duration, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		limit := timeout
+		if requested, err := time.ParseDuration(req.URL.Query().Get("timeout")); err == nil && requested > 0 && requested < limit {
+			limit = requested
+		}
+		ctx := WithAuditID(kapi.NewContext(), newAuditID())
+		ctx = WithDeadline(ctx, time.Now().Add(limit))
+
+		mapper.init(req, ctx)
+		defer mapper.remove(req)
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// newAuditID returns a random identifier for a request, falling back to the time if no randomness is available.
+func newAuditID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
 }
@@ -0,0 +1,57 @@
+package context
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	authapi "github.com/openshift/origin/pkg/auth/api"
+)
+
+func TestRequestContextFilter(t *testing.T) {
+	mapper := NewRequestContextMapper()
+	var auditID string
+	handler := NewRequestContextFilter(mapper, time.Minute, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, ok := mapper.Get(req)
+		if !ok {
+			t.Fatalf("expected a context for the request")
+		}
+		auditID = AuditIDFrom(ctx)
+		if len(auditID) == 0 {
+			t.Errorf("expected an audit ID")
+		}
+		deadline, ok := DeadlineFrom(ctx)
+		if !ok || deadline.After(time.Now().Add(10*time.Second)) {
+			t.Errorf("expected the deadline the request asked for, got %v", deadline)
+		}
+
+		user := &authapi.DefaultUserInfo{Name: "alice", Scope: "user:info"}
+		if err := mapper.Update(req, WithScopes(WithUser(ctx, user), []string{"user:info"})); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ctx, _ = mapper.Get(req)
+		if got, ok := UserFrom(ctx); !ok || got.GetName() != "alice" {
+			t.Errorf("expected the user, got %#v", got)
+		}
+		if scopes := ScopesFrom(ctx); len(scopes) != 1 || scopes[0] != "user:info" {
+			t.Errorf("expected the scopes, got %v", scopes)
+		}
+		if AuditIDFrom(ctx) != auditID {
+			t.Errorf("expected the audit ID to be kept")
+		}
+	}))
+
+	req, _ := http.NewRequest("GET", "/osapi/v1beta1/builds?timeout=10s", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if _, ok := mapper.Get(req); ok {
+		t.Errorf("expected the context to be removed once the request was served")
+	}
+	if err := mapper.Update(req, nil); err == nil {
+		t.Errorf("expected an error updating a request that is not in flight")
+	}
+	if len(mapper.contexts) != 0 {
+		t.Errorf("expected no contexts left, got %d", len(mapper.contexts))
+	}
+}
@@ -0,0 +1,89 @@
+package context
+
+import (
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	authapi "github.com/openshift/origin/pkg/auth/api"
+)
+
+// The key type is unexported to prevent collisions with the keys of other packages
+type key int
+
+const (
+	userKey key = iota
+	scopesKey
+	clientKey
+	impersonatorKey
+	auditIDKey
+	deadlineKey
+)
+
+// WithUser returns a copy of ctx in which the user the request is served as is set: the authenticated user, or
+// the user they impersonate.
+func WithUser(ctx kapi.Context, user authapi.UserInfo) kapi.Context {
+	return kapi.WithValue(ctx, userKey, user)
+}
+
+// UserFrom returns the user ctx is served as, if any.
+func UserFrom(ctx kapi.Context) (authapi.UserInfo, bool) {
+	user, ok := ctx.Value(userKey).(authapi.UserInfo)
+	return user, ok
+}
+
+// WithScopes returns a copy of ctx in which the scopes the user granted the client are set.
+func WithScopes(ctx kapi.Context, scopes []string) kapi.Context {
+	return kapi.WithValue(ctx, scopesKey, scopes)
+}
+
+// ScopesFrom returns the scopes of ctx.  No scopes means the client acts with all the rights of the user.
+func ScopesFrom(ctx kapi.Context) []string {
+	scopes, _ := ctx.Value(scopesKey).([]string)
+	return scopes
+}
+
+// WithClient returns a copy of ctx in which the OAuth client the user made the request through is set.
+func WithClient(ctx kapi.Context, client string) kapi.Context {
+	return kapi.WithValue(ctx, clientKey, client)
+}
+
+// ClientFrom returns the OAuth client of ctx, or "" if the request was not made with an OAuth token.
+func ClientFrom(ctx kapi.Context) string {
+	client, _ := ctx.Value(clientKey).(string)
+	return client
+}
+
+// WithImpersonator returns a copy of ctx in which the authenticated user that made the request as another user
+// is set.
+func WithImpersonator(ctx kapi.Context, user authapi.UserInfo) kapi.Context {
+	return kapi.WithValue(ctx, impersonatorKey, user)
+}
+
+// ImpersonatorFrom returns the authenticated user of ctx if the request is made as another user.
+func ImpersonatorFrom(ctx kapi.Context) (authapi.UserInfo, bool) {
+	user, ok := ctx.Value(impersonatorKey).(authapi.UserInfo)
+	return user, ok
+}
+
+// WithAuditID returns a copy of ctx in which the ID the request is audited with is set.
+func WithAuditID(ctx kapi.Context, id string) kapi.Context {
+	return kapi.WithValue(ctx, auditIDKey, id)
+}
+
+// AuditIDFrom returns the audit ID of ctx, or "" if it has none.
+func AuditIDFrom(ctx kapi.Context) string {
+	id, _ := ctx.Value(auditIDKey).(string)
+	return id
+}
+
+// WithDeadline returns a copy of ctx in which the time the request must be served by is set.
+func WithDeadline(ctx kapi.Context, deadline time.Time) kapi.Context {
+	return kapi.WithValue(ctx, deadlineKey, deadline)
+}
+
+// DeadlineFrom returns the deadline of ctx, if it has one.
+func DeadlineFrom(ctx kapi.Context) (time.Time, bool) {
+	deadline, ok := ctx.Value(deadlineKey).(time.Time)
+	return deadline, ok
+}
@@ -0,0 +1,37 @@
+package registry
+
+import (
+	"time"
+
+	"github.com/openshift/origin/pkg/auth/api"
+	oapi "github.com/openshift/origin/pkg/oauth/api"
+	"github.com/openshift/origin/pkg/oauth/registry/bootstraptoken"
+)
+
+// BootstrapTokenAuthenticator authenticates bootstrap tokens as the bootstrap user, a member of the bootstrap
+// group only.
+type BootstrapTokenAuthenticator struct {
+	registry bootstraptoken.Registry
+}
+
+func NewBootstrapTokenAuthenticator(registry bootstraptoken.Registry) *BootstrapTokenAuthenticator {
+	return &BootstrapTokenAuthenticator{
+		registry: registry,
+	}
+}
+
+func (a *BootstrapTokenAuthenticator) AuthenticateToken(value string) (api.UserInfo, bool, error) {
+	token, err := a.registry.GetBootstrapToken(oapi.TokenNameForSecret(value))
+	if err != nil {
+		return nil, false, err
+	}
+	// etcd removes expired tokens, but not before the TTL it checks on has passed
+	if token.CreationTimestamp.Time.Add(time.Duration(token.ExpiresIn) * time.Second).Before(time.Now()) {
+		return nil, false, ErrExpired
+	}
+	return &api.DefaultUserInfo{
+		Name:   api.BootstrapTokenUsername,
+		UID:    string(token.UID),
+		Groups: []string{api.BootstrapTokenGroup},
+	}, true, nil
+}
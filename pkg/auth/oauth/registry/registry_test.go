@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 
@@ -18,6 +19,8 @@ import (
 	"github.com/openshift/origin/pkg/oauth/registry/test"
 	"github.com/openshift/origin/pkg/oauth/server/osinserver"
 	"github.com/openshift/origin/pkg/oauth/server/osinserver/registrystorage"
+	userapi "github.com/openshift/origin/pkg/user/api"
+	usertest "github.com/openshift/origin/pkg/user/registry/test"
 )
 
 type testHandlers struct {
@@ -243,7 +246,7 @@ func TestRegistryAndServer(t *testing.T) {
 
 func TestAuthenticateTokenNotFound(t *testing.T) {
 	tokenRegistry := &test.AccessTokenRegistry{Err: apierrs.NewNotFound("AccessToken", "token")}
-	tokenAuthenticator := NewTokenAuthenticator(tokenRegistry)
+	tokenAuthenticator := NewTokenAuthenticator(tokenRegistry, &usertest.UserRegistry{User: &userapi.User{}})
 
 	userInfo, found, err := tokenAuthenticator.AuthenticateToken("token")
 	if found {
@@ -261,7 +264,7 @@ func TestAuthenticateTokenNotFound(t *testing.T) {
 }
 func TestAuthenticateTokenOtherGetError(t *testing.T) {
 	tokenRegistry := &test.AccessTokenRegistry{Err: errors.New("get error")}
-	tokenAuthenticator := NewTokenAuthenticator(tokenRegistry)
+	tokenAuthenticator := NewTokenAuthenticator(tokenRegistry, &usertest.UserRegistry{User: &userapi.User{}})
 
 	userInfo, found, err := tokenAuthenticator.AuthenticateToken("token")
 	if found {
@@ -285,7 +288,7 @@ func TestAuthenticateTokenExpired(t *testing.T) {
 			ExpiresIn:  600, // 10 minutes
 		},
 	}
-	tokenAuthenticator := NewTokenAuthenticator(tokenRegistry)
+	tokenAuthenticator := NewTokenAuthenticator(tokenRegistry, &usertest.UserRegistry{User: &userapi.User{}})
 
 	userInfo, found, err := tokenAuthenticator.AuthenticateToken("token")
 	if found {
@@ -304,9 +307,10 @@ func TestAuthenticateTokenValidated(t *testing.T) {
 		AccessToken: &oapi.OAuthAccessToken{
 			ObjectMeta: kapi.ObjectMeta{CreationTimestamp: util.Time{time.Now()}},
 			ExpiresIn:  600, // 10 minutes
+			ClientName: "openshift-web-console",
 		},
 	}
-	tokenAuthenticator := NewTokenAuthenticator(tokenRegistry)
+	tokenAuthenticator := NewTokenAuthenticator(tokenRegistry, &usertest.UserRegistry{User: &userapi.User{}})
 
 	userInfo, found, err := tokenAuthenticator.AuthenticateToken("token")
 	if !found {
@@ -316,6 +320,146 @@ func TestAuthenticateTokenValidated(t *testing.T) {
 		t.Error("Unexpected error: %v", err)
 	}
 	if userInfo == nil {
-		t.Error("Did not get a user!")
+		t.Fatal("Did not get a user!")
+	}
+	if client := userInfo.GetExtra()[api.ClientNameExtraKey]; client != "openshift-web-console" {
+		t.Errorf("Expected the client of the token, got %q", client)
+	}
+}
+func TestAuthenticateTokenDisabledUser(t *testing.T) {
+	tokenRegistry := &test.AccessTokenRegistry{
+		AccessToken: &oapi.OAuthAccessToken{
+			ObjectMeta: kapi.ObjectMeta{CreationTimestamp: util.Time{Time: time.Now()}},
+			ExpiresIn:  600,
+			UserName:   "alice",
+		},
+	}
+	userRegistry := &usertest.UserRegistry{User: &userapi.User{ObjectMeta: kapi.ObjectMeta{Name: "alice"}, Disabled: true}}
+	tokenAuthenticator := NewTokenAuthenticator(tokenRegistry, userRegistry)
+
+	userInfo, found, err := tokenAuthenticator.AuthenticateToken("token")
+	if found || err != ErrUserDisabled || userInfo != nil {
+		t.Errorf("Expected the token of a disabled user to be refused, got %v %t %v", userInfo, found, err)
+	}
+
+	userRegistry.User, userRegistry.Err = nil, apierrs.NewNotFound("User", "alice")
+	if _, found, err := tokenAuthenticator.AuthenticateToken("token"); !found || err != nil {
+		t.Errorf("Expected the token of a user that is not stored to be authenticated, got %t %v", found, err)
+	}
+}
+
+func TestAuthenticateTokenRecordsUse(t *testing.T) {
+	tokenRegistry := &test.AccessTokenRegistry{
+		AccessToken: &oapi.OAuthAccessToken{
+			ObjectMeta:        kapi.ObjectMeta{Name: "token", CreationTimestamp: util.Time{Time: time.Now()}},
+			ExpiresIn:         600,
+			UserName:          "alice",
+			LastUsedTimestamp: util.Time{Time: time.Now().Add(-time.Hour)},
+		},
+	}
+	tokenAuthenticator := NewTokenAuthenticator(tokenRegistry, &usertest.UserRegistry{User: &userapi.User{}})
+
+	if _, found, err := tokenAuthenticator.AuthenticateToken("token"); !found || err != nil {
+		t.Fatalf("Unexpected result: %t %v", found, err)
+	}
+	if tokenRegistry.UsedAccessTokenName != "token" || time.Since(tokenRegistry.UsedAccessTokenTime.Time) > time.Minute {
+		t.Errorf("Expected the use of the token to be recorded, got %q at %v", tokenRegistry.UsedAccessTokenName, tokenRegistry.UsedAccessTokenTime)
+	}
+
+	tokenRegistry.UsedAccessTokenName = ""
+	tokenRegistry.AccessToken.LastUsedTimestamp = util.Now()
+	if _, found, err := tokenAuthenticator.AuthenticateToken("token"); !found || err != nil {
+		t.Fatalf("Unexpected result: %t %v", found, err)
+	}
+	if len(tokenRegistry.UsedAccessTokenName) != 0 {
+		t.Errorf("Expected a recent use of the token not to be written again")
+	}
+}
+
+func TestAuthenticateBootstrapTokenExpired(t *testing.T) {
+	tokenRegistry := &test.BootstrapTokenRegistry{
+		BootstrapToken: &oapi.BootstrapToken{
+			ObjectMeta: kapi.ObjectMeta{CreationTimestamp: util.Time{Time: time.Now().Add(-1 * time.Hour)}},
+			ExpiresIn:  600,
+		},
+	}
+	tokenAuthenticator := NewBootstrapTokenAuthenticator(tokenRegistry)
+
+	userInfo, found, err := tokenAuthenticator.AuthenticateToken("token")
+	if found || userInfo != nil {
+		t.Errorf("Unexpected user: %v", userInfo)
+	}
+	if err != ErrExpired {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+func TestAuthenticateBootstrapTokenValidated(t *testing.T) {
+	tokenRegistry := &test.BootstrapTokenRegistry{
+		BootstrapToken: &oapi.BootstrapToken{
+			ObjectMeta: kapi.ObjectMeta{UID: "1234", CreationTimestamp: util.Time{Time: time.Now()}},
+			ExpiresIn:  600,
+		},
+	}
+	tokenAuthenticator := NewBootstrapTokenAuthenticator(tokenRegistry)
+
+	userInfo, found, err := tokenAuthenticator.AuthenticateToken("token")
+	if !found || err != nil {
+		t.Fatalf("Expected the token to be found, got %v", err)
+	}
+	if tokenRegistry.GetBootstrapTokenName != oapi.TokenNameForSecret("token") {
+		t.Errorf("Expected the token to be looked up by the hash of its secret, got %q", tokenRegistry.GetBootstrapTokenName)
+	}
+	if userInfo.GetName() != api.BootstrapTokenUsername || userInfo.GetUID() != "1234" {
+		t.Errorf("Unexpected user: %#v", userInfo)
+	}
+	if groups := userInfo.GetGroups(); len(groups) != 1 || groups[0] != api.BootstrapTokenGroup {
+		t.Errorf("Expected only the bootstrap group, got %v", groups)
+	}
+}
+
+func TestAuthenticateRouterTokenExpired(t *testing.T) {
+	tokenRegistry := &test.RouterTokenRegistry{
+		RouterToken: &oapi.RouterToken{
+			ObjectMeta: kapi.ObjectMeta{CreationTimestamp: util.Time{Time: time.Now().Add(-1 * time.Hour)}},
+			RouterName: "shard1",
+			Namespaces: []string{"a"},
+			ExpiresIn:  600,
+		},
+	}
+	tokenAuthenticator := NewRouterTokenAuthenticator(tokenRegistry)
+
+	userInfo, found, err := tokenAuthenticator.AuthenticateToken("token")
+	if found || userInfo != nil {
+		t.Errorf("Unexpected user: %v", userInfo)
+	}
+	if err != ErrExpired {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+func TestAuthenticateRouterTokenValidated(t *testing.T) {
+	tokenRegistry := &test.RouterTokenRegistry{
+		RouterToken: &oapi.RouterToken{
+			ObjectMeta: kapi.ObjectMeta{UID: "1234", CreationTimestamp: util.Time{Time: time.Now().Add(-24 * time.Hour)}},
+			RouterName: "shard1",
+			Namespaces: []string{"a", "b"},
+		},
+	}
+	tokenAuthenticator := NewRouterTokenAuthenticator(tokenRegistry)
+
+	userInfo, found, err := tokenAuthenticator.AuthenticateToken("token")
+	if !found || err != nil {
+		t.Fatalf("Expected the token to be found, got %v", err)
+	}
+	if tokenRegistry.GetRouterTokenName != oapi.TokenNameForSecret("token") {
+		t.Errorf("Expected the token to be looked up by the hash of its secret, got %q", tokenRegistry.GetRouterTokenName)
+	}
+	if userInfo.GetName() != "system:router:shard1" || userInfo.GetUID() != "1234" {
+		t.Errorf("Unexpected user: %#v", userInfo)
+	}
+	if groups := userInfo.GetGroups(); len(groups) != 1 || groups[0] != api.RouterTokenGroup {
+		t.Errorf("Expected only the router group, got %v", groups)
+	}
+	if namespaces, ok := api.LimitedNamespaces(userInfo); !ok || !reflect.DeepEqual(namespaces, []string{"a", "b"}) {
+		t.Errorf("Expected the user to be limited to the token namespaces, got %v %t", namespaces, ok)
 	}
 }
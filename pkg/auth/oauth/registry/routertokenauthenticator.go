@@ -0,0 +1,39 @@
+package registry
+
+import (
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/auth/api"
+	oapi "github.com/openshift/origin/pkg/oauth/api"
+	"github.com/openshift/origin/pkg/oauth/registry/routertoken"
+)
+
+// RouterTokenAuthenticator authenticates router tokens as the router they were created for, a member of the
+// router group only, limited to the namespaces of the token.
+type RouterTokenAuthenticator struct {
+	registry routertoken.Registry
+}
+
+func NewRouterTokenAuthenticator(registry routertoken.Registry) *RouterTokenAuthenticator {
+	return &RouterTokenAuthenticator{
+		registry: registry,
+	}
+}
+
+func (a *RouterTokenAuthenticator) AuthenticateToken(value string) (api.UserInfo, bool, error) {
+	token, err := a.registry.GetRouterToken(oapi.TokenNameForSecret(value))
+	if err != nil {
+		return nil, false, err
+	}
+	// router tokens without an expiry last until they are deleted
+	if token.ExpiresIn > 0 && token.CreationTimestamp.Time.Add(time.Duration(token.ExpiresIn)*time.Second).Before(time.Now()) {
+		return nil, false, ErrExpired
+	}
+	return &api.DefaultUserInfo{
+		Name:   api.RouterTokenUsernamePrefix + token.RouterName,
+		UID:    string(token.UID),
+		Groups: []string{api.RouterTokenGroup},
+		Extra:  map[string]string{api.RouterNamespacesExtraKey: strings.Join(token.Namespaces, ",")},
+	}, true, nil
+}
@@ -4,20 +4,33 @@ import (
 	"errors"
 	"time"
 
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+
 	"github.com/openshift/origin/pkg/auth/api"
 	"github.com/openshift/origin/pkg/oauth/registry/accesstoken"
 	"github.com/openshift/origin/pkg/oauth/scope"
+	userregistry "github.com/openshift/origin/pkg/user/registry/user"
 )
 
 type TokenAuthenticator struct {
 	registry accesstoken.Registry
+	users    userregistry.Registry
 }
 
 var ErrExpired = errors.New("Token is expired")
 
-func NewTokenAuthenticator(registry accesstoken.Registry) *TokenAuthenticator {
+// ErrUserDisabled is returned for the tokens of users that are disabled
+var ErrUserDisabled = errors.New("User is disabled")
+
+// tokenUseRecordInterval is how stale the recorded last use of a token may get before it is written again
+const tokenUseRecordInterval = time.Minute
+
+func NewTokenAuthenticator(registry accesstoken.Registry, users userregistry.Registry) *TokenAuthenticator {
 	return &TokenAuthenticator{
 		registry: registry,
+		users:    users,
 	}
 }
 
@@ -26,12 +39,26 @@ func (a *TokenAuthenticator) AuthenticateToken(value string) (api.UserInfo, bool
 	if err != nil {
 		return nil, false, err
 	}
-	if token.CreationTimestamp.Time.Add(time.Duration(token.ExpiresIn) * time.Second).Before(time.Now()) {
+	now := time.Now()
+	if token.CreationTimestamp.Time.Add(time.Duration(token.ExpiresIn) * time.Second).Before(now) {
 		return nil, false, ErrExpired
 	}
+	user, err := a.users.GetUser(token.UserName)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, false, err
+	}
+	if err == nil && user.Disabled {
+		return nil, false, ErrUserDisabled
+	}
+	if now.Sub(token.LastUsedTimestamp.Time) >= tokenUseRecordInterval {
+		if err := a.registry.RecordAccessTokenUse(token.Name, util.NewTime(now)); err != nil {
+			glog.Errorf("Unable to record the use of the token for %s: %v", token.UserName, err)
+		}
+	}
 	return &api.DefaultUserInfo{
 		Name:  token.UserName,
 		UID:   token.UserUID,
 		Scope: scope.Join(token.Scopes),
+		Extra: map[string]string{api.ClientNameExtraKey: token.ClientName},
 	}, true, nil
 }
@@ -1,19 +1,32 @@
 package identitymapper
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+
 	authapi "github.com/openshift/origin/pkg/auth/api"
 	userapi "github.com/openshift/origin/pkg/user/api"
+	userregistry "github.com/openshift/origin/pkg/user/registry/user"
 	"github.com/openshift/origin/pkg/user/registry/useridentitymapping"
 )
 
+// loginRecordInterval is the least time between the logins recorded for a user, so that frequent logins do not
+// each write the user.
+const loginRecordInterval = time.Minute
+
 type alwaysCreateUserIdentityToUserMapper struct {
 	providerID           string
 	userIdentityRegistry useridentitymapping.Registry
+	userRegistry         userregistry.Registry
 }
 
-// NewAlwaysCreateProvisioner always does a createOrUpdate for the passed identity while forcing the identity.Provider to the providerID supplied here
-func NewAlwaysCreateUserIdentityToUserMapper(providerID string, userIdentityRegistry useridentitymapping.Registry) authapi.UserIdentityMapper {
-	return &alwaysCreateUserIdentityToUserMapper{providerID, userIdentityRegistry}
+// NewAlwaysCreateProvisioner always does a createOrUpdate for the passed identity while forcing the identity.Provider to the providerID supplied here.
+// The time users log in is recorded in userRegistry.
+func NewAlwaysCreateUserIdentityToUserMapper(providerID string, userIdentityRegistry useridentitymapping.Registry, userRegistry userregistry.Registry) authapi.UserIdentityMapper {
+	return &alwaysCreateUserIdentityToUserMapper{providerID, userIdentityRegistry, userRegistry}
 }
 
 // ProvisionUser implements UserIdentityMapper.UserFor
@@ -29,6 +42,14 @@ func (p *alwaysCreateUserIdentityToUserMapper) UserFor(identityInfo authapi.User
 	if err != nil {
 		return nil, err
 	}
+	if authoritativeMapping.User.Disabled {
+		return nil, fmt.Errorf("the user %s is disabled", authoritativeMapping.User.Name)
+	}
+	if now := util.Now(); now.Sub(authoritativeMapping.User.LastLoginTimestamp.Time) >= loginRecordInterval {
+		if err := p.userRegistry.RecordLogin(authoritativeMapping.User.Name, now); err != nil {
+			glog.Errorf("Unable to record the login of %s: %v", authoritativeMapping.User.Name, err)
+		}
+	}
 
 	ret := &authapi.DefaultUserInfo{
 		Name:  authoritativeMapping.User.Name,
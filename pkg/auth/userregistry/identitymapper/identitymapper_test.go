@@ -3,14 +3,18 @@ package identitymapper
 import (
 	"testing"
 
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
 	authapi "github.com/openshift/origin/pkg/auth/api"
+	userapi "github.com/openshift/origin/pkg/user/api"
 	"github.com/openshift/origin/pkg/user/registry/test"
 )
 
 func TestProvisionUser(t *testing.T) {
 	userIdentityRegistry := &test.UserIdentityMappingRegistry{}
 	providerID := "papa"
-	identityMapper := NewAlwaysCreateUserIdentityToUserMapper(providerID, userIdentityRegistry)
+	identityMapper := NewAlwaysCreateUserIdentityToUserMapper(providerID, userIdentityRegistry, &test.UserRegistry{})
 	identity := &authapi.DefaultUserIdentityInfo{
 		UserName: "oscar",
 	}
@@ -24,3 +28,43 @@ func TestProvisionUser(t *testing.T) {
 	}
 
 }
+
+func TestProvisionDisabledUser(t *testing.T) {
+	userIdentityRegistry := &test.UserIdentityMappingRegistry{
+		UserIdentityMapping: &userapi.UserIdentityMapping{
+			User: userapi.User{ObjectMeta: kapi.ObjectMeta{Name: "oscar"}, Disabled: true},
+		},
+	}
+	identityMapper := NewAlwaysCreateUserIdentityToUserMapper("papa", userIdentityRegistry, &test.UserRegistry{})
+
+	if user, err := identityMapper.UserFor(&authapi.DefaultUserIdentityInfo{UserName: "oscar"}); err == nil {
+		t.Errorf("Expected disabled users to be refused, got %#v", user)
+	}
+}
+
+func TestProvisionRecordsLogin(t *testing.T) {
+	userIdentityRegistry := &test.UserIdentityMappingRegistry{
+		UserIdentityMapping: &userapi.UserIdentityMapping{
+			User: userapi.User{ObjectMeta: kapi.ObjectMeta{Name: "oscar"}},
+		},
+	}
+	userRegistry := &test.UserRegistry{}
+	identityMapper := NewAlwaysCreateUserIdentityToUserMapper("papa", userIdentityRegistry, userRegistry)
+	identity := &authapi.DefaultUserIdentityInfo{UserName: "oscar"}
+
+	if _, err := identityMapper.UserFor(identity); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if userRegistry.LoginUserID != "oscar" || userRegistry.LoginTime.IsZero() {
+		t.Errorf("Expected the login to be recorded, got %q at %v", userRegistry.LoginUserID, userRegistry.LoginTime)
+	}
+
+	userRegistry.LoginUserID = ""
+	userIdentityRegistry.UserIdentityMapping.User.LastLoginTimestamp = util.Now()
+	if _, err := identityMapper.UserFor(identity); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(userRegistry.LoginUserID) != 0 {
+		t.Errorf("Expected a login right after the last recorded one not to be recorded")
+	}
+}
@@ -18,6 +18,10 @@ const (
 	PolicyName  = "default"
 	ResourceAll = "*"
 	VerbAll     = "*"
+
+	// DryRunAnnotation, when set to "true" on a Role or RoleBinding that is created or updated, makes the
+	// server validate the change and return a PolicyChangeReview of it instead of making it
+	DryRunAnnotation = "openshift.io/dry-run"
 )
 
 // PolicyRule holds information that describes a policy rule, but does not contain information
@@ -101,3 +105,36 @@ type PolicyBindingList struct {
 	kapi.ListMeta `json:"metadata,omitempty"`
 	Items         []PolicyBinding `json:"items"`
 }
+
+// PolicyChangeReview evaluates a change to a role or role binding without making it.  Exactly one of Role
+// and RoleBinding is set, to the object to create or replace, or to delete if Delete is true.
+type PolicyChangeReview struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	// Role is the role to change
+	Role *Role `json:"role,omitempty"`
+	// RoleBinding is the role binding to change
+	RoleBinding *RoleBinding `json:"roleBinding,omitempty"`
+	// Delete is true if the role or role binding is deleted rather than created or replaced
+	Delete bool `json:"delete,omitempty"`
+
+	// Changes is set by the server to the access every user and group gains or loses through the change
+	Changes []AccessChange `json:"changes"`
+}
+
+// AccessChange is the access a user or group gains or loses in a namespace through a change to a role or
+// role binding.  Exactly one of UserName and GroupName is set.
+type AccessChange struct {
+	// UserName is the user whose access changes
+	UserName string `json:"userName,omitempty"`
+	// GroupName is the group whose access changes
+	GroupName string `json:"groupName,omitempty"`
+	// Namespace is the namespace of the role bindings that grant the access.  Access granted in the master
+	// namespace applies to every namespace
+	Namespace string `json:"namespace"`
+	// Added holds the rules gained
+	Added []PolicyRule `json:"added"`
+	// Removed holds the rules lost
+	Removed []PolicyRule `json:"removed"`
+}
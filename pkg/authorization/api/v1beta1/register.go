@@ -12,12 +12,14 @@ func init() {
 		&PolicyBinding{},
 		&PolicyList{},
 		&PolicyBindingList{},
+		&PolicyChangeReview{},
 	)
 }
 
-func (*Role) IsAnAPIObject()              {}
-func (*Policy) IsAnAPIObject()            {}
-func (*PolicyBinding) IsAnAPIObject()     {}
-func (*RoleBinding) IsAnAPIObject()       {}
-func (*PolicyList) IsAnAPIObject()        {}
-func (*PolicyBindingList) IsAnAPIObject() {}
+func (*Role) IsAnAPIObject()               {}
+func (*Policy) IsAnAPIObject()             {}
+func (*PolicyBinding) IsAnAPIObject()      {}
+func (*RoleBinding) IsAnAPIObject()        {}
+func (*PolicyList) IsAnAPIObject()         {}
+func (*PolicyBindingList) IsAnAPIObject()  {}
+func (*PolicyChangeReview) IsAnAPIObject() {}
@@ -17,12 +17,12 @@ import (
 // about who the rule applies to or which namespace the rule applies to.
 type PolicyRule struct {
 	// Deny is true if any request matching this rule should be denied.  If false, any request matching this rule is allowed.
-	Deny bool `json:"deny"`
+	Deny bool `json:"deny" description:"Deny is true if any request matching this rule should be denied.  If false, any request matching this rule is allowed."`
 	// Verbs is a list of Verbs that apply to ALL the ResourceKinds and AttributeRestrictions contained in this rule.  VerbAll represents all kinds.
-	Verbs []string `json:"verbs"`
+	Verbs []string `json:"verbs" description:"Verbs is a list of Verbs that apply to ALL the ResourceKinds and AttributeRestrictions contained in this rule.  VerbAll represents all kinds."`
 	// AttributeRestrictions will vary depending on what the Authorizer/AuthorizationAttributeBuilder pair supports.
 	// If the Authorizer does not recognize how to handle the AttributeRestrictions, the Authorizer should report an error.
-	AttributeRestrictions kruntime.RawExtension `json:"attributeRestrictions"`
+	AttributeRestrictions kruntime.RawExtension `json:"attributeRestrictions" description:"AttributeRestrictions will vary depending on what the Authorizer/AuthorizationAttributeBuilder pair supports. If the Authorizer does not recognize how to handle the AttributeRestrictions, the Authorizer should report an error."`
 	// ResourceKinds is a list of kinds this rule applies to.  ResourceAll represents all kinds.
 	ResourceKinds []string `json:"resourceKinds""`
 }
@@ -33,7 +33,7 @@ type Role struct {
 	kapi.ObjectMeta `json:"metadata,omitempty"`
 
 	// Rules holds all the PolicyRules for this Role
-	Rules []PolicyRule `json:"rules"`
+	Rules []PolicyRule `json:"rules" description:"Rules holds all the PolicyRules for this Role"`
 }
 
 // RoleBinding references a Role, but not contain it.  It adds who and namespace information.
@@ -43,14 +43,14 @@ type RoleBinding struct {
 	kapi.ObjectMeta `json:"metadata,omitempty"`
 
 	// UserNames holds all the usernames directly bound to the role
-	UserNames []string `json:"userNames"`
+	UserNames []string `json:"userNames" description:"UserNames holds all the usernames directly bound to the role"`
 	// GroupNames holds all the groups directly bound to the role
-	GroupNames []string `json:"groupNames"`
+	GroupNames []string `json:"groupNames" description:"GroupNames holds all the groups directly bound to the role"`
 
 	// Since Policy is a singleton, this is sufficient knowledge to locate a role
 	// RoleRefs can only reference the current namespace and the global namespace
 	// If the RoleRef cannot be resolved, the Authorizer must return an error.
-	RoleRef kapi.ObjectReference `json:"roleRef"`
+	RoleRef kapi.ObjectReference `json:"roleRef" description:"Since Policy is a singleton, this is sufficient knowledge to locate a role RoleRefs can only reference the current namespace and the global namespace If the RoleRef cannot be resolved, the Authorizer must return an error."`
 }
 
 // Policy is a object that holds all the Roles for a particular namespace.  There is at most
@@ -60,10 +60,10 @@ type Policy struct {
 	kapi.ObjectMeta `json:"metadata,omitempty"`
 
 	// LastModified is the last time that any part of the Policy was created, updated, or deleted
-	LastModified kutil.Time `json:"lastModified"`
+	LastModified kutil.Time `json:"lastModified" description:"LastModified is the last time that any part of the Policy was created, updated, or deleted"`
 
 	// Roles holds all the Roles held by this Policy, mapped by Role.Name
-	Roles []NamedRole `json:"roles"`
+	Roles []NamedRole `json:"roles" description:"Roles holds all the Roles held by this Policy, mapped by Role.Name"`
 }
 
 // PolicyBinding is a object that holds all the RoleBindings for a particular namespace.  There is
@@ -73,12 +73,12 @@ type PolicyBinding struct {
 	kapi.ObjectMeta `json:"metadata,omitempty"`
 
 	// LastModified is the last time that any part of the PolicyBinding was created, updated, or deleted
-	LastModified kutil.Time `json:"lastModified"`
+	LastModified kutil.Time `json:"lastModified" description:"LastModified is the last time that any part of the PolicyBinding was created, updated, or deleted"`
 
 	// PolicyRef is a reference to the Policy that contains all the Roles that this PolicyBinding's RoleBindings may reference
-	PolicyRef kapi.ObjectReference `json:"policyRef"`
+	PolicyRef kapi.ObjectReference `json:"policyRef" description:"PolicyRef is a reference to the Policy that contains all the Roles that this PolicyBinding's RoleBindings may reference"`
 	// RoleBindings holds all the RoleBindings held by this PolicyBinding, mapped by RoleBinding.Name
-	RoleBindings []NamedRoleBinding `json:"roleBindings"`
+	RoleBindings []NamedRoleBinding `json:"roleBindings" description:"RoleBindings holds all the RoleBindings held by this PolicyBinding, mapped by RoleBinding.Name"`
 }
 
 type NamedRole struct {
@@ -104,3 +104,36 @@ type PolicyBindingList struct {
 	kapi.ListMeta `json:"metadata,omitempty"`
 	Items         []PolicyBinding `json:"items"`
 }
+
+// PolicyChangeReview evaluates a change to a role or role binding without making it.  Exactly one of Role
+// and RoleBinding is set, to the object to create or replace, or to delete if Delete is true.
+type PolicyChangeReview struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	// Role is the role to change
+	Role *Role `json:"role,omitempty"`
+	// RoleBinding is the role binding to change
+	RoleBinding *RoleBinding `json:"roleBinding,omitempty"`
+	// Delete is true if the role or role binding is deleted rather than created or replaced
+	Delete bool `json:"delete,omitempty"`
+
+	// Changes is set by the server to the access every user and group gains or loses through the change
+	Changes []AccessChange `json:"changes"`
+}
+
+// AccessChange is the access a user or group gains or loses in a namespace through a change to a role or
+// role binding.  Exactly one of UserName and GroupName is set.
+type AccessChange struct {
+	// UserName is the user whose access changes
+	UserName string `json:"userName,omitempty"`
+	// GroupName is the group whose access changes
+	GroupName string `json:"groupName,omitempty"`
+	// Namespace is the namespace of the role bindings that grant the access.  Access granted in the master
+	// namespace applies to every namespace
+	Namespace string `json:"namespace"`
+	// Added holds the rules gained
+	Added []PolicyRule `json:"added"`
+	// Removed holds the rules lost
+	Removed []PolicyRule `json:"removed"`
+}
@@ -0,0 +1,25 @@
+package v1beta2
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+func init() {
+	api.Scheme.AddKnownTypes("v1beta2",
+		&Role{},
+		&RoleBinding{},
+		&Policy{},
+		&PolicyBinding{},
+		&PolicyList{},
+		&PolicyBindingList{},
+		&PolicyChangeReview{},
+	)
+}
+
+func (*Role) IsAnAPIObject()               {}
+func (*Policy) IsAnAPIObject()             {}
+func (*PolicyBinding) IsAnAPIObject()      {}
+func (*RoleBinding) IsAnAPIObject()        {}
+func (*PolicyList) IsAnAPIObject()         {}
+func (*PolicyBindingList) IsAnAPIObject()  {}
+func (*PolicyChangeReview) IsAnAPIObject() {}
@@ -53,3 +53,21 @@ func ValidateRoleBinding(roleBinding *authorizationapi.RoleBinding) errs.Validat
 	allErrs = append(allErrs, validation.ValidateLabels(roleBinding.Labels, "labels")...)
 	return allErrs
 }
+
+// ValidatePolicyChangeReview tests required fields for a PolicyChangeReview.
+func ValidatePolicyChangeReview(review *authorizationapi.PolicyChangeReview) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+
+	switch {
+	case review.Role != nil && review.RoleBinding != nil:
+		allErrs = append(allErrs, errs.NewFieldInvalid("roleBinding", review.RoleBinding.Name, "only one of role and roleBinding may be specified"))
+	case review.Role != nil:
+		allErrs = append(allErrs, ValidateRole(review.Role).Prefix("role")...)
+	case review.RoleBinding != nil:
+		allErrs = append(allErrs, ValidateRoleBinding(review.RoleBinding).Prefix("roleBinding")...)
+	default:
+		allErrs = append(allErrs, errs.NewFieldRequired("role", review.Role))
+	}
+
+	return allErrs
+}
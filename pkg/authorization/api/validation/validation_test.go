@@ -63,3 +63,28 @@ func TestPolicyBindingValidationFailure(t *testing.T) {
 		t.Errorf("Unexpected validation result: %v", result)
 	}
 }
+
+func TestPolicyChangeReviewValidation(t *testing.T) {
+	role := &authorizationapi.Role{ObjectMeta: kapi.ObjectMeta{Name: "my-name"}}
+	roleBinding := &authorizationapi.RoleBinding{
+		ObjectMeta: kapi.ObjectMeta{Name: "my-name"},
+		RoleRef:    kapi.ObjectReference{Namespace: "master"},
+	}
+
+	testCases := map[string]struct {
+		review authorizationapi.PolicyChangeReview
+		errs   int
+	}{
+		"role":        {authorizationapi.PolicyChangeReview{Role: role}, 0},
+		"roleBinding": {authorizationapi.PolicyChangeReview{RoleBinding: roleBinding, Delete: true}, 0},
+		"neither":     {authorizationapi.PolicyChangeReview{}, 1},
+		"both":        {authorizationapi.PolicyChangeReview{Role: role, RoleBinding: roleBinding}, 1},
+		"invalid":     {authorizationapi.PolicyChangeReview{Role: &authorizationapi.Role{}}, 1},
+	}
+
+	for name, testCase := range testCases {
+		if result := ValidatePolicyChangeReview(&testCase.review); len(result) != testCase.errs {
+			t.Errorf("%s: unexpected validation result: %v", name, result)
+		}
+	}
+}
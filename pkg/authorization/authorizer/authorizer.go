@@ -33,6 +33,11 @@ type AuthorizationAttributes interface {
 	GetRequestAttributes() interface{}
 }
 
+// RoleLister lists the roles a user is bound to in a namespace.
+type RoleLister interface {
+	GetRoleNames(namespace string, user authenticationapi.UserInfo) ([]string, error)
+}
+
 type openshiftAuthorizer struct {
 	masterAuthorizationNamespace string
 	policyRegistry               policyregistry.Registry
@@ -43,6 +48,11 @@ func NewAuthorizer(masterAuthorizationNamespace string, policyRuleBindingRegistr
 	return &openshiftAuthorizer{masterAuthorizationNamespace, policyRuleBindingRegistry, policyBindingRegistry}
 }
 
+// NewRoleLister returns a RoleLister that reads the role bindings the authorizer checks requests against.
+func NewRoleLister(masterAuthorizationNamespace string, policyRuleBindingRegistry policyregistry.Registry, policyBindingRegistry policybindingregistry.Registry) RoleLister {
+	return &openshiftAuthorizer{masterAuthorizationNamespace, policyRuleBindingRegistry, policyBindingRegistry}
+}
+
 type openshiftAuthorizationAttributes struct {
 	user              authenticationapi.UserInfo
 	verb              string
@@ -52,11 +62,12 @@ type openshiftAuthorizationAttributes struct {
 }
 
 type openshiftAuthorizationAttributeBuilder struct {
-	requestsToUsers *authcontext.RequestContextMap
+	contexts *authcontext.RequestContextMapper
 }
 
-func NewAuthorizationAttributeBuilder(requestsToUsers *authcontext.RequestContextMap) AuthorizationAttributeBuilder {
-	return &openshiftAuthorizationAttributeBuilder{requestsToUsers}
+// NewAuthorizationAttributeBuilder returns a builder that reads the user of a request from its context.
+func NewAuthorizationAttributeBuilder(contexts *authcontext.RequestContextMapper) AuthorizationAttributeBuilder {
+	return &openshiftAuthorizationAttributeBuilder{contexts}
 }
 
 func doesApplyToUser(ruleUsers, ruleGroups []string, user authenticationapi.UserInfo) bool {
@@ -161,6 +172,23 @@ func (a *openshiftAuthorizer) getEffectivePolicyRules(namespace string, user aut
 	return effectiveRules, nil
 }
 
+// GetRoleNames returns the sorted names of the roles bound to user in namespace, directly or through one of its
+// groups.
+func (a *openshiftAuthorizer) GetRoleNames(namespace string, user authenticationapi.UserInfo) ([]string, error) {
+	roleBindings, err := a.getRoleBindings(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	names := util.StringSet{}
+	for _, roleBinding := range roleBindings {
+		if doesApplyToUser(roleBinding.UserNames, roleBinding.GroupNames, user) {
+			names.Insert(roleBinding.RoleRef.Name)
+		}
+	}
+	return names.List(), nil
+}
+
 func (a *openshiftAuthorizer) Authorize(passedAttributes AuthorizationAttributes) (bool, string, error) {
 	// fmt.Printf("#### checking %#v\n", passedAttributes)
 
@@ -169,6 +197,14 @@ func (a *openshiftAuthorizer) Authorize(passedAttributes AuthorizationAttributes
 		return false, "", fmt.Errorf("attributes are not of expected type: %#v", attributes)
 	}
 
+	// credentials limited to some namespaces, like router tokens, cannot be used outside of them whatever roles
+	// their user is bound to, nor across all namespaces
+	if user := attributes.GetUserInfo(); user != nil {
+		if namespaces, limited := authenticationapi.LimitedNamespaces(user); limited && !util.NewStringSet(namespaces...).Has(attributes.GetNamespace()) {
+			return false, "denied outside the namespaces of the credential", nil
+		}
+	}
+
 	globalAuthorizationResult, globalReason, err := a.authorizeWithNamespaceRules(a.masterAuthorizationNamespace, attributes)
 	if err != nil {
 		return false, "", err
@@ -309,13 +345,13 @@ func (a *openshiftAuthorizationAttributeBuilder) GetAttributes(req *http.Request
 		return nil, err
 	}
 
-	userInterface, ok := a.requestsToUsers.Get(req)
+	ctx, ok := a.contexts.Get(req)
 	if !ok {
-		return nil, errors.New("could not get user")
+		return nil, errors.New("the request has no context")
 	}
-	userInfo, ok := userInterface.(authenticationapi.UserInfo)
+	userInfo, ok := authcontext.UserFrom(ctx)
 	if !ok {
-		return nil, errors.New("wrong type returned for user")
+		return nil, errors.New("could not get user")
 	}
 
 	return openshiftAuthorizationAttributes{
@@ -337,6 +373,25 @@ var specialVerbs = map[string]bool{
 
 var ErrNoStandardParts = errors.New("the provided URL does not match the standard API form")
 
+// DebugVerb is the verb of requests to the /debug endpoints of the master, which expose profiling and runtime
+// state.  It is only granted by rules that allow every verb or name it explicitly.
+const DebugVerb = "debug"
+
+// ImpersonateVerb is the verb a user must be granted on users in the master policy to make requests as another
+// user.  Like the debug verb, it is only granted by rules that allow every verb or name it explicitly.
+const ImpersonateVerb = "impersonate"
+
+// NewImpersonationAttributes returns the attributes that authorize user to make requests as another user.  They
+// are checked against the master policy only.
+func NewImpersonationAttributes(user authenticationapi.UserInfo) AuthorizationAttributes {
+	return openshiftAuthorizationAttributes{
+		user:         user,
+		verb:         ImpersonateVerb,
+		resourceKind: "users",
+		namespace:    kapi.NamespaceAll,
+	}
+}
+
 // VerbAndKindAndNamespace returns verb, kind, namespace, remaining parts, error
 func VerbAndKindAndNamespace(req *http.Request) (string, string, string, []string, error) {
 	parts := splitPath(req.URL.Path)
@@ -344,6 +399,14 @@ func VerbAndKindAndNamespace(req *http.Request) (string, string, string, []strin
 		return "", "", "", nil, ErrNoStandardParts
 	}
 
+	// URL forms: /debug/{kind}/*, authorized against the master policy only
+	if parts[0] == "debug" {
+		if len(parts) < 2 {
+			return "", "", "", parts, ErrNoStandardParts
+		}
+		return DebugVerb, parts[1], kapi.NamespaceAll, parts[1:], nil
+	}
+
 	verb := ""
 	switch req.Method {
 	case "POST":
@@ -389,7 +452,7 @@ func VerbAndKindAndNamespace(req *http.Request) (string, string, string, []strin
 		if len(parts) < 3 {
 			return "", "", "", parts, fmt.Errorf("ResourceTypeAndNamespace expects a path of form /ns/{namespace}/*")
 		}
-		return verb, parts[1], parts[2], parts[2:], ErrNoStandardParts
+		return verb, parts[2], parts[1], parts[2:], ErrNoStandardParts
 	}
 
 	// URL forms: /{kind}/*
@@ -446,7 +509,7 @@ func GetBootstrapPolicy(masterNamespace string) *authorizationapi.Policy {
 				},
 				Rules: []authorizationapi.PolicyRule{
 					{
-						Verbs:         []string{authorizationapi.VerbAll, "-create", "-update", "-delete"},
+						Verbs:         []string{authorizationapi.VerbAll, "-create", "-update", "-delete", "-" + DebugVerb, "-" + ImpersonateVerb},
 						ResourceKinds: []string{authorizationapi.ResourceAll},
 					},
 					{
@@ -462,7 +525,7 @@ func GetBootstrapPolicy(masterNamespace string) *authorizationapi.Policy {
 				},
 				Rules: []authorizationapi.PolicyRule{
 					{
-						Verbs:         []string{authorizationapi.VerbAll, "-create", "-update", "-delete"},
+						Verbs:         []string{authorizationapi.VerbAll, "-create", "-update", "-delete", "-" + DebugVerb, "-" + ImpersonateVerb},
 						ResourceKinds: []string{authorizationapi.ResourceAll, "-roles", "-roleBindings", "-policyBindings", "-policies"},
 					},
 					{
@@ -483,6 +546,78 @@ func GetBootstrapPolicy(masterNamespace string) *authorizationapi.Policy {
 					},
 				},
 			},
+			"self-provisioner": {
+				ObjectMeta: kapi.ObjectMeta{
+					Name:      "self-provisioner",
+					Namespace: masterNamespace,
+				},
+				Rules: []authorizationapi.PolicyRule{
+					{
+						Verbs:         []string{"create"},
+						ResourceKinds: []string{"projectRequests"},
+					},
+				},
+			},
+			"bootstrapper": {
+				ObjectMeta: kapi.ObjectMeta{
+					Name:      "bootstrapper",
+					Namespace: masterNamespace,
+				},
+				Rules: []authorizationapi.PolicyRule{
+					{
+						Verbs:         []string{"get", "create", "update"},
+						ResourceKinds: []string{"minions", "nodes"},
+					},
+					// the users of bootstrap tokens are authenticated users too, deny them what other roles allow
+					{
+						Deny:          true,
+						Verbs:         []string{authorizationapi.VerbAll, "-get", "-create", "-update"},
+						ResourceKinds: []string{authorizationapi.ResourceAll},
+					},
+					{
+						Deny:          true,
+						Verbs:         []string{"create", "update"},
+						ResourceKinds: []string{authorizationapi.ResourceAll, "-minions", "-nodes"},
+					},
+					{
+						Deny:          true,
+						Verbs:         []string{"get"},
+						ResourceKinds: []string{authorizationapi.ResourceAll, "-minions", "-nodes"},
+					},
+				},
+			},
+			"router": {
+				ObjectMeta: kapi.ObjectMeta{
+					Name:      "router",
+					Namespace: masterNamespace,
+				},
+				Rules: []authorizationapi.PolicyRule{
+					{
+						Verbs:         []string{"get", "list", "watch"},
+						ResourceKinds: []string{"routes", "endpoints"},
+					},
+					{
+						Verbs:         []string{"create"},
+						ResourceKinds: []string{"routeStatusUpdates"},
+					},
+					// the users of router tokens are authenticated users too, deny them what other roles allow
+					{
+						Deny:          true,
+						Verbs:         []string{authorizationapi.VerbAll, "-get", "-list", "-watch", "-create"},
+						ResourceKinds: []string{authorizationapi.ResourceAll},
+					},
+					{
+						Deny:          true,
+						Verbs:         []string{"create"},
+						ResourceKinds: []string{authorizationapi.ResourceAll, "-routeStatusUpdates"},
+					},
+					{
+						Deny:          true,
+						Verbs:         []string{"get", "list", "watch"},
+						ResourceKinds: []string{authorizationapi.ResourceAll, "-routes", "-endpoints"},
+					},
+				},
+			},
 			"ComponentRole": {
 				ObjectMeta: kapi.ObjectMeta{
 					Name:      "ComponentRole",
@@ -499,6 +634,10 @@ func GetBootstrapPolicy(masterNamespace string) *authorizationapi.Policy {
 	}
 }
 
+// BootstrapAdminUsername is the user the master issues a client certificate to on first start.  The bootstrap
+// policy binds it to the cluster-admin role.
+const BootstrapAdminUsername = "system:admin"
+
 func GetBootstrapPolicyBinding(masterNamespace string) *authorizationapi.PolicyBinding {
 	return &authorizationapi.PolicyBinding{
 		ObjectMeta: kapi.ObjectMeta{
@@ -521,6 +660,39 @@ func GetBootstrapPolicyBinding(masterNamespace string) *authorizationapi.PolicyB
 				// TODO until we get components added to their proper groups, enumerate them here
 				UserNames: []string{"openshift-client", "kube-client"},
 			},
+			"Self-Provisioners": {
+				ObjectMeta: kapi.ObjectMeta{
+					Name:      "Self-Provisioners",
+					Namespace: masterNamespace,
+				},
+				RoleRef: kapi.ObjectReference{
+					Name:      "self-provisioner",
+					Namespace: masterNamespace,
+				},
+				GroupNames: []string{"system:authenticated"},
+			},
+			"Bootstrappers": {
+				ObjectMeta: kapi.ObjectMeta{
+					Name:      "Bootstrappers",
+					Namespace: masterNamespace,
+				},
+				RoleRef: kapi.ObjectReference{
+					Name:      "bootstrapper",
+					Namespace: masterNamespace,
+				},
+				GroupNames: []string{authenticationapi.BootstrapTokenGroup},
+			},
+			"Routers": {
+				ObjectMeta: kapi.ObjectMeta{
+					Name:      "Routers",
+					Namespace: masterNamespace,
+				},
+				RoleRef: kapi.ObjectReference{
+					Name:      "router",
+					Namespace: masterNamespace,
+				},
+				GroupNames: []string{authenticationapi.RouterTokenGroup},
+			},
 			"Cluster-Admins": {
 				ObjectMeta: kapi.ObjectMeta{
 					Name:      "Cluster-Admins",
@@ -530,6 +702,7 @@ func GetBootstrapPolicyBinding(masterNamespace string) *authorizationapi.PolicyB
 					Name:      "cluster-admin",
 					Namespace: masterNamespace,
 				},
+				UserNames: []string{BootstrapAdminUsername},
 				// TODO until we decide to enforce policy, simply allow every one access
 				GroupNames: []string{"system:authenticated", "system:unauthenticated"},
 			},
@@ -1,6 +1,8 @@
 package authorizer
 
 import (
+	"net/http"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -288,6 +290,29 @@ func (test *authorizeTest) test(t *testing.T) {
 	}
 }
 
+func TestGetRoleNames(t *testing.T) {
+	_, globalBindings := newDefaultGlobalPolicy()
+	_, namespacedBindings := newAdzePolicy()
+	policyBindingRegistry := &testpolicyregistry.PolicyBindingRegistry{
+		MasterNamespace: testMasterNamespace,
+		PolicyBindings:  append(namespacedBindings, globalBindings...),
+	}
+	lister := NewRoleLister(testMasterNamespace, &testpolicyregistry.PolicyRegistry{MasterNamespace: testMasterNamespace}, policyBindingRegistry)
+
+	names, err := lister.GetRoleNames("adze", &authenticationapi.DefaultUserInfo{Name: "TeasedAdmin"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"admin", "anti-admin"}) {
+		t.Errorf("unexpected roles: %v", names)
+	}
+
+	names, err = lister.GetRoleNames("adze", &authenticationapi.DefaultUserInfo{Name: "Nobody"})
+	if err != nil || len(names) != 0 {
+		t.Errorf("expected no roles, got %v %v", names, err)
+	}
+}
+
 func matchString(expected, actual string, field string, t *testing.T) {
 	if expected != actual {
 		t.Errorf("%v: Expected %v, got %v", field, expected, actual)
@@ -458,3 +483,38 @@ func newAdzePolicy() ([]authorizationapi.Policy, []authorizationapi.PolicyBindin
 			},
 		)
 }
+
+func TestVerbAndKindAndNamespaceDebug(t *testing.T) {
+	testCases := map[string]struct {
+		verb      string
+		kind      string
+		expectErr bool
+	}{
+		"/debug/pprof/":          {verb: DebugVerb, kind: "pprof"},
+		"/debug/pprof/profile":   {verb: DebugVerb, kind: "pprof"},
+		"/debug/vars":            {verb: DebugVerb, kind: "vars"},
+		"/debug/goroutines":      {verb: DebugVerb, kind: "goroutines"},
+		"/debug":                 {expectErr: true},
+		"/osapi/v1beta1/debug/x": {verb: "get", kind: "debug"},
+	}
+	for path, tc := range testCases {
+		req, _ := http.NewRequest("GET", path, nil)
+		verb, kind, namespace, _, err := VerbAndKindAndNamespace(req)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", path)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", path, err)
+			continue
+		}
+		if verb != tc.verb || kind != tc.kind {
+			t.Errorf("%s: expected %s %s, got %s %s", path, tc.verb, tc.kind, verb, kind)
+		}
+		if tc.verb == DebugVerb && namespace != kapi.NamespaceAll {
+			t.Errorf("%s: expected debug requests to be authorized globally, got namespace %q", path, namespace)
+		}
+	}
+}
@@ -324,6 +324,216 @@ func TestAdminGetAllowedKindInAdze(t *testing.T) {
 	test.test(t)
 }
 
+func TestClusterAdminDebugAllowed(t *testing.T) {
+	test := &authorizeTest{
+		attributes: &openshiftAuthorizationAttributes{
+			user: &authenticationapi.DefaultUserInfo{
+				Name: "ClusterAdmin",
+			},
+			verb:         DebugVerb,
+			resourceKind: "pprof",
+		},
+		expectedAllowed: true,
+		expectedReason:  "allowed by rule in master",
+	}
+	test.globalPolicy, test.globalPolicyBinding = newDefaultGlobalPolicy()
+	test.namespacedPolicy, test.namespacedPolicyBinding = allNamespacedPolicies()
+	test.test(t)
+}
+func TestAdminDebugDenied(t *testing.T) {
+	test := &authorizeTest{
+		attributes: &openshiftAuthorizationAttributes{
+			user: &authenticationapi.DefaultUserInfo{
+				Name: "Matthew",
+			},
+			verb:         DebugVerb,
+			resourceKind: "pprof",
+		},
+		expectedAllowed: false,
+		expectedReason:  "denied by default",
+	}
+	test.globalPolicy, test.globalPolicyBinding = newDefaultGlobalPolicy()
+	test.namespacedPolicy, test.namespacedPolicyBinding = allNamespacedPolicies()
+	test.test(t)
+}
+
+func TestBootstrapRolesDenyDebug(t *testing.T) {
+	attributes := openshiftAuthorizationAttributes{verb: DebugVerb, resourceKind: "pprof"}
+	for name, role := range GetBootstrapPolicy(testMasterNamespace).Roles {
+		allowed := false
+		for _, rule := range role.Rules {
+			if matches, _ := attributes.ruleMatches(rule); matches && !rule.Deny {
+				allowed = true
+			}
+		}
+		expected := name == "cluster-admin" || name == "ComponentRole"
+		if allowed != expected {
+			t.Errorf("%s: expected the debug verb to be allowed=%v, got %v", name, expected, allowed)
+		}
+	}
+}
+
+func TestBootstrapAdminAllowed(t *testing.T) {
+	test := &authorizeTest{
+		attributes: &openshiftAuthorizationAttributes{
+			user: &authenticationapi.DefaultUserInfo{
+				Name: BootstrapAdminUsername,
+			},
+			verb:         "delete",
+			resourceKind: "policies",
+			namespace:    "mallet",
+		},
+		expectedAllowed: true,
+		expectedReason:  "allowed by rule in master",
+	}
+	test.globalPolicy = []authorizationapi.Policy{*GetBootstrapPolicy(testMasterNamespace)}
+	test.globalPolicyBinding = []authorizationapi.PolicyBinding{*GetBootstrapPolicyBinding(testMasterNamespace)}
+	test.namespacedPolicy, test.namespacedPolicyBinding = allNamespacedPolicies()
+	test.test(t)
+}
+
+func TestBootstrapTokenRestricted(t *testing.T) {
+	user := &authenticationapi.DefaultUserInfo{
+		Name:   authenticationapi.BootstrapTokenUsername,
+		Groups: []string{authenticationapi.BootstrapTokenGroup, "system:authenticated"},
+	}
+	testCases := []struct {
+		verb     string
+		kind     string
+		expected bool
+	}{
+		{"create", "minions", true},
+		{"update", "nodes", true},
+		{"get", "minions", true},
+		{"list", "minions", false},
+		{"watch", "routes", false},
+		{"list", "endpoints", false},
+		{"get", "services", false},
+		{"delete", "minions", false},
+		{"create", "pods", false},
+		{"get", "policies", false},
+		{"create", "projectRequests", false},
+		{DebugVerb, "pprof", false},
+		{ImpersonateVerb, "users", false},
+	}
+	for _, tc := range testCases {
+		test := &authorizeTest{
+			attributes: &openshiftAuthorizationAttributes{
+				user:         user,
+				verb:         tc.verb,
+				resourceKind: tc.kind,
+				namespace:    "mallet",
+			},
+			expectedAllowed: tc.expected,
+			expectedReason:  "denied by rule in master",
+		}
+		if tc.expected {
+			test.expectedReason = "allowed by rule in master"
+		}
+		test.globalPolicy = []authorizationapi.Policy{*GetBootstrapPolicy(testMasterNamespace)}
+		test.globalPolicyBinding = []authorizationapi.PolicyBinding{*GetBootstrapPolicyBinding(testMasterNamespace)}
+		test.namespacedPolicy, test.namespacedPolicyBinding = allNamespacedPolicies()
+		test.test(t)
+	}
+}
+
+func TestRouterTokenRestricted(t *testing.T) {
+	user := &authenticationapi.DefaultUserInfo{
+		Name:   authenticationapi.RouterTokenUsernamePrefix + "shard1",
+		Groups: []string{authenticationapi.RouterTokenGroup, "system:authenticated"},
+		Extra:  map[string]string{authenticationapi.RouterNamespacesExtraKey: "mallet,adze"},
+	}
+	testCases := []struct {
+		verb      string
+		kind      string
+		namespace string
+		expected  bool
+		reason    string
+	}{
+		{"watch", "routes", "mallet", true, "allowed by rule in master"},
+		{"list", "endpoints", "adze", true, "allowed by rule in master"},
+		{"create", "routeStatusUpdates", "mallet", true, "allowed by rule in master"},
+		{"update", "routes", "mallet", false, "denied by rule in master"},
+		{"create", "routes", "mallet", false, "denied by rule in master"},
+		{"list", "services", "mallet", false, "denied by rule in master"},
+		{"get", "policies", "mallet", false, "denied by rule in master"},
+		{"watch", "routes", "hammer", false, "denied outside the namespaces of the credential"},
+		{"list", "endpoints", "", false, "denied outside the namespaces of the credential"},
+	}
+	for _, tc := range testCases {
+		test := &authorizeTest{
+			attributes: &openshiftAuthorizationAttributes{
+				user:         user,
+				verb:         tc.verb,
+				resourceKind: tc.kind,
+				namespace:    tc.namespace,
+			},
+			expectedAllowed: tc.expected,
+			expectedReason:  tc.reason,
+		}
+		test.globalPolicy = []authorizationapi.Policy{*GetBootstrapPolicy(testMasterNamespace)}
+		test.globalPolicyBinding = []authorizationapi.PolicyBinding{*GetBootstrapPolicyBinding(testMasterNamespace)}
+		test.namespacedPolicy, test.namespacedPolicyBinding = allNamespacedPolicies()
+		test.test(t)
+	}
+}
+
+func TestClusterAdminImpersonateAllowed(t *testing.T) {
+	attributes := NewImpersonationAttributes(&authenticationapi.DefaultUserInfo{Name: "ClusterAdmin"}).(openshiftAuthorizationAttributes)
+	test := &authorizeTest{
+		attributes:      &attributes,
+		expectedAllowed: true,
+		expectedReason:  "allowed by rule in master",
+	}
+	test.globalPolicy, test.globalPolicyBinding = newDefaultGlobalPolicy()
+	test.namespacedPolicy, test.namespacedPolicyBinding = allNamespacedPolicies()
+	test.test(t)
+}
+func TestAdminImpersonateDenied(t *testing.T) {
+	attributes := NewImpersonationAttributes(&authenticationapi.DefaultUserInfo{Name: "Matthew"}).(openshiftAuthorizationAttributes)
+	test := &authorizeTest{
+		attributes:      &attributes,
+		expectedAllowed: false,
+		expectedReason:  "denied by default",
+	}
+	test.globalPolicy, test.globalPolicyBinding = newDefaultGlobalPolicy()
+	test.namespacedPolicy, test.namespacedPolicyBinding = allNamespacedPolicies()
+	test.test(t)
+}
+
+func TestAdminListIdentitiesDenied(t *testing.T) {
+	test := &authorizeTest{
+		attributes: &openshiftAuthorizationAttributes{
+			user: &authenticationapi.DefaultUserInfo{
+				Name: "Matthew",
+			},
+			verb:         "list",
+			resourceKind: "identities",
+		},
+		expectedAllowed: false,
+		expectedReason:  "denied by default",
+	}
+	test.globalPolicy, test.globalPolicyBinding = newDefaultGlobalPolicy()
+	test.namespacedPolicy, test.namespacedPolicyBinding = allNamespacedPolicies()
+	test.test(t)
+}
+
+func TestBootstrapRolesDenyImpersonate(t *testing.T) {
+	attributes := NewImpersonationAttributes(nil).(openshiftAuthorizationAttributes)
+	for name, role := range GetBootstrapPolicy(testMasterNamespace).Roles {
+		allowed := false
+		for _, rule := range role.Rules {
+			if matches, _ := attributes.ruleMatches(rule); matches && !rule.Deny {
+				allowed = true
+			}
+		}
+		expected := name == "cluster-admin" || name == "ComponentRole"
+		if allowed != expected {
+			t.Errorf("%s: expected the impersonate verb to be allowed=%v, got %v", name, expected, allowed)
+		}
+	}
+}
+
 func allNamespacedPolicies() ([]authorizationapi.Policy, []authorizationapi.PolicyBinding) {
 	adzePolicy, adzeBinding := newMalletPolicy()
 	malletPolicy, malletBinding := newMalletPolicy()
@@ -0,0 +1,100 @@
+package authorizer
+
+import (
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
+)
+
+// ReconcileProtectAnnotation is set to "true" on a role or role binding of the master policy that an
+// administrator changed on purpose, to keep ReconcilePolicy and ReconcilePolicyBinding from changing it.
+const ReconcileProtectAnnotation = "openshift.io/reconcile-protect"
+
+// ReconcileUnmodifiedAnnotation is set to "true" on a role binding of the master policy by an administrator
+// who has not changed its users and groups, to let ReconcilePolicyBinding add the users and groups the
+// bootstrap policy binds since.
+const ReconcileUnmodifiedAnnotation = "openshift.io/reconcile-unmodified"
+
+// ReconcilePolicy merges the roles of bootstrap into policy and returns true if policy changed.  Roles
+// missing from policy are added, and roles whose rules differ from bootstrap are reset unless they are
+// protected.  Roles that are not part of bootstrap are left alone.
+func ReconcilePolicy(policy, bootstrap *authorizationapi.Policy) bool {
+	if policy.Roles == nil {
+		policy.Roles = map[string]authorizationapi.Role{}
+	}
+	changed := false
+	for name, role := range bootstrap.Roles {
+		existing, ok := policy.Roles[name]
+		switch {
+		case !ok:
+		case isProtected(existing.Annotations), reflect.DeepEqual(existing.Rules, role.Rules):
+			continue
+		default:
+			existing.Rules = role.Rules
+			role = existing
+		}
+		policy.Roles[name] = role
+		changed = true
+	}
+	if changed {
+		policy.LastModified = util.Now()
+	}
+	return changed
+}
+
+// ReconcilePolicyBinding merges the role bindings of bootstrap into binding and returns true if binding
+// changed.  Role bindings missing from binding are added.  The role and subjects of role bindings that exist
+// are left as the administrator set them, since a user or group removed from a binding must not be bound
+// again, unless the binding is annotated as unmodified: those are given the role of bootstrap and the users
+// and groups of bootstrap they lack.  Role bindings that are not part of bootstrap are left alone.
+func ReconcilePolicyBinding(binding, bootstrap *authorizationapi.PolicyBinding) bool {
+	if binding.RoleBindings == nil {
+		binding.RoleBindings = map[string]authorizationapi.RoleBinding{}
+	}
+	changed := false
+	for name, roleBinding := range bootstrap.RoleBindings {
+		existing, ok := binding.RoleBindings[name]
+		if ok {
+			if isProtected(existing.Annotations) || !isUnmodified(existing.Annotations) {
+				continue
+			}
+			userNames, addedUsers := union(existing.UserNames, roleBinding.UserNames)
+			groupNames, addedGroups := union(existing.GroupNames, roleBinding.GroupNames)
+			if !addedUsers && !addedGroups && existing.RoleRef == roleBinding.RoleRef {
+				continue
+			}
+			existing.UserNames, existing.GroupNames, existing.RoleRef = userNames, groupNames, roleBinding.RoleRef
+			roleBinding = existing
+		}
+		binding.RoleBindings[name] = roleBinding
+		changed = true
+	}
+	if changed {
+		binding.LastModified = util.Now()
+	}
+	return changed
+}
+
+// isProtected returns true if annotations protect an object from reconciliation.
+func isProtected(annotations map[string]string) bool {
+	return annotations[ReconcileProtectAnnotation] == "true"
+}
+
+// isUnmodified returns true if annotations mark a role binding as left as it was bootstrapped.
+func isUnmodified(annotations map[string]string) bool {
+	return annotations[ReconcileUnmodifiedAnnotation] == "true"
+}
+
+// union returns the items of list followed by the items of add it lacks, and true if any were added.
+func union(list, add []string) ([]string, bool) {
+	added := false
+	for _, item := range add {
+		if !contains(list, item) {
+			list = append(list, item)
+			added = true
+		}
+	}
+	return list, added
+}
@@ -0,0 +1,83 @@
+package authorizer
+
+import (
+	"reflect"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
+)
+
+func TestReconcilePolicy(t *testing.T) {
+	bootstrap := GetBootstrapPolicy("master")
+	policy := GetBootstrapPolicy("master")
+	if ReconcilePolicy(policy, bootstrap) {
+		t.Errorf("expected the bootstrap policy to be current")
+	}
+
+	// a role added to the bootstrap policy, a role changed by an administrator and a protected role
+	delete(policy.Roles, "view")
+	edit := policy.Roles["edit"]
+	edit.Rules = nil
+	policy.Roles["edit"] = edit
+	admin := policy.Roles["admin"]
+	admin.Rules = nil
+	admin.Annotations = map[string]string{ReconcileProtectAnnotation: "true"}
+	policy.Roles["admin"] = admin
+	policy.Roles["custom"] = authorizationapi.Role{ObjectMeta: kapi.ObjectMeta{Name: "custom"}}
+
+	if !ReconcilePolicy(policy, bootstrap) {
+		t.Fatalf("expected the policy to change")
+	}
+	if !reflect.DeepEqual(policy.Roles["view"], bootstrap.Roles["view"]) {
+		t.Errorf("expected the missing role to be added, got %#v", policy.Roles["view"])
+	}
+	if !reflect.DeepEqual(policy.Roles["edit"].Rules, bootstrap.Roles["edit"].Rules) {
+		t.Errorf("expected the changed role to be reset, got %#v", policy.Roles["edit"])
+	}
+	if policy.Roles["admin"].Rules != nil {
+		t.Errorf("expected the protected role to be kept, got %#v", policy.Roles["admin"])
+	}
+	if _, ok := policy.Roles["custom"]; !ok {
+		t.Errorf("expected the role missing from the bootstrap policy to be kept")
+	}
+}
+
+func TestReconcilePolicyBinding(t *testing.T) {
+	bootstrap := GetBootstrapPolicyBinding("master")
+	binding := GetBootstrapPolicyBinding("master")
+	if ReconcilePolicyBinding(binding, bootstrap) {
+		t.Errorf("expected the bootstrap policy binding to be current")
+	}
+
+	delete(binding.RoleBindings, "Self-Provisioners")
+	components := binding.RoleBindings["Components"]
+	components.UserNames = []string{"admin"}
+	components.Annotations = map[string]string{ReconcileUnmodifiedAnnotation: "true"}
+	binding.RoleBindings["Components"] = components
+	routers := binding.RoleBindings["Routers"]
+	routers.GroupNames = []string{"routers"}
+	routers.Annotations = map[string]string{ReconcileProtectAnnotation: "true", ReconcileUnmodifiedAnnotation: "true"}
+	binding.RoleBindings["Routers"] = routers
+	// the subjects an administrator removed from a role binding are not bound again
+	admins := binding.RoleBindings["Cluster-Admins"]
+	admins.GroupNames = []string{}
+	binding.RoleBindings["Cluster-Admins"] = admins
+
+	if !ReconcilePolicyBinding(binding, bootstrap) {
+		t.Fatalf("expected the policy binding to change")
+	}
+	if !reflect.DeepEqual(binding.RoleBindings["Self-Provisioners"], bootstrap.RoleBindings["Self-Provisioners"]) {
+		t.Errorf("expected the missing role binding to be added, got %#v", binding.RoleBindings["Self-Provisioners"])
+	}
+	if e, a := []string{"admin", "openshift-client", "kube-client"}, binding.RoleBindings["Components"].UserNames; !reflect.DeepEqual(e, a) {
+		t.Errorf("expected users %v, got %v", e, a)
+	}
+	if e, a := []string{"routers"}, binding.RoleBindings["Routers"].GroupNames; !reflect.DeepEqual(e, a) {
+		t.Errorf("expected the protected role binding to be kept, got %v", a)
+	}
+	if a := binding.RoleBindings["Cluster-Admins"].GroupNames; len(a) != 0 {
+		t.Errorf("expected the modified role binding to be kept, got %v", a)
+	}
+}
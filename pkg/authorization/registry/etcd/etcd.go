@@ -11,6 +11,7 @@ import (
 	etcdgeneric "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic/etcd"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
 )
@@ -103,6 +104,11 @@ func (r *Etcd) DeletePolicy(ctx kapi.Context, name string) error {
 	return r.policyRegistry.Delete(ctx, name)
 }
 
+// WatchPolicies begins watching for new, changed, or deleted policies.
+func (r *Etcd) WatchPolicies(ctx kapi.Context, label, field klabels.Selector, resourceVersion string) (watch.Interface, error) {
+	return r.policyRegistry.Watch(ctx, &generic.SelectionPredicate{Label: label, Field: field, GetAttrs: getAttrs}, resourceVersion)
+}
+
 func makePolicyBindingListKey(ctx kapi.Context) string {
 	return kubeetcd.MakeEtcdListKey(ctx, PolicyBindingPath)
 }
@@ -148,3 +154,8 @@ func (r *Etcd) UpdatePolicyBinding(ctx kapi.Context, newPolicyBinding *authoriza
 func (r *Etcd) DeletePolicyBinding(ctx kapi.Context, name string) error {
 	return r.policyBindingRegistry.Delete(ctx, name)
 }
+
+// WatchPolicyBindings begins watching for new, changed, or deleted policy bindings.
+func (r *Etcd) WatchPolicyBindings(ctx kapi.Context, label, field klabels.Selector, resourceVersion string) (watch.Interface, error) {
+	return r.policyBindingRegistry.Watch(ctx, &generic.SelectionPredicate{Label: label, Field: field, GetAttrs: getAttrs}, resourceVersion)
+}
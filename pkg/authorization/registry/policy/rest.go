@@ -5,6 +5,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
 	klabels "github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
 )
@@ -46,6 +47,12 @@ func (r *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
 	return policy, err
 }
 
+// Watch returns Policy events via a watch.Interface.
+// It implements apiserver.ResourceWatcher.
+func (r *REST) Watch(ctx kapi.Context, label, field klabels.Selector, resourceVersion string) (watch.Interface, error) {
+	return r.registry.WatchPolicies(ctx, label, field, resourceVersion)
+}
+
 // Delete asynchronously deletes the Policy specified by its id.
 func (r *REST) Delete(ctx kapi.Context, id string) (<-chan apiserver.RESTResult, error) {
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
@@ -9,6 +9,7 @@ import (
 	klabels "github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
 	"github.com/openshift/origin/pkg/authorization/api/validation"
@@ -52,6 +53,12 @@ func (r *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
 	return policyBinding, err
 }
 
+// Watch returns PolicyBinding events via a watch.Interface.
+// It implements apiserver.ResourceWatcher.
+func (r *REST) Watch(ctx kapi.Context, label, field klabels.Selector, resourceVersion string) (watch.Interface, error) {
+	return r.registry.WatchPolicyBindings(ctx, label, field, resourceVersion)
+}
+
 // Delete asynchronously deletes the PolicyBinding specified by its id.
 func (r *REST) Delete(ctx kapi.Context, id string) (<-chan apiserver.RESTResult, error) {
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
@@ -0,0 +1,278 @@
+package policychangereview
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	klabels "github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
+	"github.com/openshift/origin/pkg/authorization/api/validation"
+	policyregistry "github.com/openshift/origin/pkg/authorization/registry/policy"
+	policybindingregistry "github.com/openshift/origin/pkg/authorization/registry/policybinding"
+)
+
+// Reviewer computes the access that users and groups gain or lose through a change to a role or role binding.
+type Reviewer interface {
+	// Review sets review.Changes for the role bindings in the namespace of ctx.
+	Review(ctx kapi.Context, review *authorizationapi.PolicyChangeReview) error
+}
+
+// REST implements the RESTStorage interface for PolicyChangeReviews.  Creating a PolicyChangeReview
+// evaluates the change it holds and returns it with Changes set; nothing is persisted.
+type REST struct {
+	reviewer Reviewer
+}
+
+// NewREST returns a new REST.
+func NewREST(reviewer Reviewer) apiserver.RESTStorage {
+	return &REST{reviewer}
+}
+
+// New returns a new PolicyChangeReview for use with Create.
+func (r *REST) New() runtime.Object {
+	return &authorizationapi.PolicyChangeReview{}
+}
+
+// Create evaluates the change in a PolicyChangeReview and returns the access it changes.
+func (r *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	review, ok := obj.(*authorizationapi.PolicyChangeReview)
+	if !ok {
+		return nil, fmt.Errorf("not a policyChangeReview: %#v", obj)
+	}
+	if errs := validation.ValidatePolicyChangeReview(review); len(errs) > 0 {
+		return nil, kerrors.NewInvalid("policyChangeReview", review.Name, errs)
+	}
+	if review.Role != nil && !kapi.ValidNamespace(ctx, &review.Role.ObjectMeta) {
+		return nil, kerrors.NewConflict("policyChangeReview", review.Role.Namespace, fmt.Errorf("Role.Namespace does not match the provided context"))
+	}
+	if review.RoleBinding != nil && !kapi.ValidNamespace(ctx, &review.RoleBinding.ObjectMeta) {
+		return nil, kerrors.NewConflict("policyChangeReview", review.RoleBinding.Namespace, fmt.Errorf("RoleBinding.Namespace does not match the provided context"))
+	}
+
+	return DryRun(ctx, r.reviewer, review)
+}
+
+// registryReviewer is a Reviewer that reads roles and role bindings from the policy registries.
+type registryReviewer struct {
+	policyRegistry  policyregistry.Registry
+	bindingRegistry policybindingregistry.Registry
+}
+
+// NewReviewer returns a Reviewer that reads the current roles and role bindings from the given registries.
+func NewReviewer(policyRegistry policyregistry.Registry, bindingRegistry policybindingregistry.Registry) Reviewer {
+	return &registryReviewer{policyRegistry, bindingRegistry}
+}
+
+// Review compares the rules every user and group is granted by the role bindings in the namespace of ctx
+// before and after the change.  A change to a role also compares the rules granted in every other namespace
+// with a role binding to the role, as role bindings may refer to the roles of the master namespace.
+func (r *registryReviewer) Review(ctx kapi.Context, review *authorizationapi.PolicyChangeReview) error {
+	namespace := kapi.Namespace(ctx)
+
+	listCtx := ctx
+	if review.Role != nil {
+		listCtx = kapi.WithNamespace(ctx, kapi.NamespaceAll)
+	}
+	policyBindings, err := r.bindingRegistry.ListPolicyBindings(listCtx, klabels.Everything(), klabels.Everything())
+	if err != nil {
+		return err
+	}
+	before := map[string][]authorizationapi.RoleBinding{}
+	namespaces := []string{namespace}
+	for _, policyBinding := range policyBindings.Items {
+		for _, roleBinding := range policyBinding.RoleBindings {
+			before[policyBinding.Namespace] = append(before[policyBinding.Namespace], roleBinding)
+			if review.Role != nil && policyBinding.Namespace != namespace && roleBinding.RoleRef.Namespace == namespace && roleBinding.RoleRef.Name == review.Role.Name {
+				namespaces = append(namespaces, policyBinding.Namespace)
+			}
+		}
+	}
+	namespaces = util.NewStringSet(namespaces...).List()
+
+	after := map[string][]authorizationapi.RoleBinding{}
+	for _, ns := range namespaces {
+		after[ns] = before[ns]
+	}
+	if review.RoleBinding != nil {
+		after[namespace] = []authorizationapi.RoleBinding{}
+		for _, roleBinding := range before[namespace] {
+			if roleBinding.Name != review.RoleBinding.Name {
+				after[namespace] = append(after[namespace], roleBinding)
+			}
+		}
+		if !review.Delete {
+			after[namespace] = append(after[namespace], *review.RoleBinding)
+		}
+	}
+
+	roles := &roleResolver{registry: r.policyRegistry, policies: map[string]*authorizationapi.Policy{}}
+	beforeRules := map[string]map[subject][]authorizationapi.PolicyRule{}
+	for _, ns := range namespaces {
+		if beforeRules[ns], err = roles.grantedRules(before[ns]); err != nil {
+			return err
+		}
+	}
+	if review.Role != nil {
+		policy, err := roles.policy(namespace)
+		if err != nil {
+			return err
+		}
+		changed := &authorizationapi.Policy{Roles: map[string]authorizationapi.Role{}}
+		for name, role := range policy.Roles {
+			changed.Roles[name] = role
+		}
+		if review.Delete {
+			delete(changed.Roles, review.Role.Name)
+		} else {
+			changed.Roles[review.Role.Name] = *review.Role
+		}
+		roles.policies[namespace] = changed
+	}
+
+	review.Changes = []authorizationapi.AccessChange{}
+	for _, ns := range namespaces {
+		afterRules, err := roles.grantedRules(after[ns])
+		if err != nil {
+			return err
+		}
+		review.Changes = append(review.Changes, diffRules(ns, beforeRules[ns], afterRules)...)
+	}
+	return nil
+}
+
+// subject identifies a user or group that role bindings grant rules to.
+type subject struct {
+	userName  string
+	groupName string
+}
+
+// roleResolver looks up the rules of the roles that role bindings refer to, reading each policy once.
+type roleResolver struct {
+	registry policyregistry.Registry
+	policies map[string]*authorizationapi.Policy
+}
+
+// policy returns the policy of namespace, or an empty policy if the namespace has none.
+func (r *roleResolver) policy(namespace string) (*authorizationapi.Policy, error) {
+	if policy, ok := r.policies[namespace]; ok {
+		return policy, nil
+	}
+	policy, err := r.registry.GetPolicy(kapi.WithNamespace(kapi.NewContext(), namespace), authorizationapi.PolicyName)
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			return nil, err
+		}
+		policy = &authorizationapi.Policy{}
+	}
+	r.policies[namespace] = policy
+	return policy, nil
+}
+
+// grantedRules returns the rules each subject of roleBindings is granted.  Role bindings to roles that
+// do not exist grant nothing.
+func (r *roleResolver) grantedRules(roleBindings []authorizationapi.RoleBinding) (map[subject][]authorizationapi.PolicyRule, error) {
+	rules := map[subject][]authorizationapi.PolicyRule{}
+	for _, roleBinding := range roleBindings {
+		policy, err := r.policy(roleBinding.RoleRef.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		role := policy.Roles[roleBinding.RoleRef.Name]
+		for _, userName := range roleBinding.UserNames {
+			key := subject{userName: userName}
+			rules[key] = append(rules[key], role.Rules...)
+		}
+		for _, groupName := range roleBinding.GroupNames {
+			key := subject{groupName: groupName}
+			rules[key] = append(rules[key], role.Rules...)
+		}
+	}
+	return rules, nil
+}
+
+// diffRules returns the rules each subject gains and loses between before and after, ordered by user
+// and then group name.  Subjects whose rules did not change are omitted.
+func diffRules(namespace string, before, after map[subject][]authorizationapi.PolicyRule) []authorizationapi.AccessChange {
+	subjects := []subject{}
+	for key := range before {
+		subjects = append(subjects, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			subjects = append(subjects, key)
+		}
+	}
+	sort.Sort(bySubject(subjects))
+
+	changes := []authorizationapi.AccessChange{}
+	for _, key := range subjects {
+		added := missingRules(after[key], before[key])
+		removed := missingRules(before[key], after[key])
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+		changes = append(changes, authorizationapi.AccessChange{
+			UserName:  key.userName,
+			GroupName: key.groupName,
+			Namespace: namespace,
+			Added:     added,
+			Removed:   removed,
+		})
+	}
+	return changes
+}
+
+// missingRules returns the rules in rules that are not in other.
+func missingRules(rules, other []authorizationapi.PolicyRule) []authorizationapi.PolicyRule {
+	missing := []authorizationapi.PolicyRule{}
+	for _, rule := range rules {
+		if !containsRule(other, rule) && !containsRule(missing, rule) {
+			missing = append(missing, rule)
+		}
+	}
+	return missing
+}
+
+func containsRule(rules []authorizationapi.PolicyRule, rule authorizationapi.PolicyRule) bool {
+	for _, curr := range rules {
+		if reflect.DeepEqual(curr, rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// bySubject sorts users before groups, and each by name.
+type bySubject []subject
+
+func (s bySubject) Len() int      { return len(s) }
+func (s bySubject) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s bySubject) Less(i, j int) bool {
+	if s[i].groupName != s[j].groupName {
+		return s[i].groupName < s[j].groupName
+	}
+	return s[i].userName < s[j].userName
+}
+
+// IsDryRun returns true if meta asks for a change to be reviewed rather than made.
+func IsDryRun(meta *kapi.ObjectMeta) bool {
+	return meta.Annotations[authorizationapi.DryRunAnnotation] == "true"
+}
+
+// DryRun asynchronously reviews a change with reviewer instead of making it.
+func DryRun(ctx kapi.Context, reviewer Reviewer, review *authorizationapi.PolicyChangeReview) (<-chan apiserver.RESTResult, error) {
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		if err := reviewer.Review(ctx, review); err != nil {
+			return nil, err
+		}
+		return review, nil
+	}), nil
+}
@@ -0,0 +1,183 @@
+package policychangereview
+
+import (
+	"reflect"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
+	"github.com/openshift/origin/pkg/authorization/registry/test"
+)
+
+var (
+	viewRule = authorizationapi.PolicyRule{Verbs: []string{"get"}, ResourceKinds: []string{"pods"}}
+	editRule = authorizationapi.PolicyRule{Verbs: []string{"update"}, ResourceKinds: []string{"pods"}}
+)
+
+func makeStorage() *REST {
+	policyRegistry := &test.PolicyRegistry{
+		Policies: []authorizationapi.Policy{
+			{
+				ObjectMeta: kapi.ObjectMeta{Name: authorizationapi.PolicyName, Namespace: "master"},
+				Roles: map[string]authorizationapi.Role{
+					"view": {ObjectMeta: kapi.ObjectMeta{Name: "view"}, Rules: []authorizationapi.PolicyRule{viewRule}},
+					"edit": {ObjectMeta: kapi.ObjectMeta{Name: "edit"}, Rules: []authorizationapi.PolicyRule{viewRule, editRule}},
+				},
+			},
+			{
+				ObjectMeta: kapi.ObjectMeta{Name: authorizationapi.PolicyName, Namespace: "unittest"},
+				Roles: map[string]authorizationapi.Role{
+					"local": {ObjectMeta: kapi.ObjectMeta{Name: "local"}, Rules: []authorizationapi.PolicyRule{viewRule}},
+				},
+			},
+		},
+	}
+	bindingRegistry := &test.PolicyBindingRegistry{
+		PolicyBindings: []authorizationapi.PolicyBinding{
+			{
+				ObjectMeta: kapi.ObjectMeta{Name: "master", Namespace: "unittest"},
+				RoleBindings: map[string]authorizationapi.RoleBinding{
+					"viewers": {
+						ObjectMeta: kapi.ObjectMeta{Name: "viewers", Namespace: "unittest"},
+						UserNames:  []string{"alice"},
+						RoleRef:    kapi.ObjectReference{Name: "view", Namespace: "master"},
+					},
+				},
+			},
+			{
+				ObjectMeta: kapi.ObjectMeta{Name: "unittest", Namespace: "unittest"},
+				RoleBindings: map[string]authorizationapi.RoleBinding{
+					"locals": {
+						ObjectMeta: kapi.ObjectMeta{Name: "locals", Namespace: "unittest"},
+						UserNames:  []string{"carol"},
+						RoleRef:    kapi.ObjectReference{Name: "local", Namespace: "unittest"},
+					},
+				},
+			},
+		},
+	}
+	return &REST{NewReviewer(policyRegistry, bindingRegistry)}
+}
+
+func review(t *testing.T, storage *REST, namespace string, review *authorizationapi.PolicyChangeReview) []authorizationapi.AccessChange {
+	ctx := kapi.WithNamespace(kapi.NewContext(), namespace)
+	channel, err := storage.Create(ctx, review)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := <-channel
+	obj, ok := result.Object.(*authorizationapi.PolicyChangeReview)
+	if !ok {
+		t.Fatalf("unexpected result: %#v", result.Object)
+	}
+	return obj.Changes
+}
+
+func TestReviewAddRoleBinding(t *testing.T) {
+	changes := review(t, makeStorage(), "unittest", &authorizationapi.PolicyChangeReview{
+		RoleBinding: &authorizationapi.RoleBinding{
+			ObjectMeta: kapi.ObjectMeta{Name: "editors"},
+			UserNames:  []string{"alice", "bob"},
+			GroupNames: []string{"devs"},
+			RoleRef:    kapi.ObjectReference{Name: "edit", Namespace: "master"},
+		},
+	})
+
+	expected := []authorizationapi.AccessChange{
+		{UserName: "alice", Namespace: "unittest", Added: []authorizationapi.PolicyRule{editRule}, Removed: []authorizationapi.PolicyRule{}},
+		{UserName: "bob", Namespace: "unittest", Added: []authorizationapi.PolicyRule{viewRule, editRule}, Removed: []authorizationapi.PolicyRule{}},
+		{GroupName: "devs", Namespace: "unittest", Added: []authorizationapi.PolicyRule{viewRule, editRule}, Removed: []authorizationapi.PolicyRule{}},
+	}
+	if !reflect.DeepEqual(expected, changes) {
+		t.Errorf("expected %#v, got %#v", expected, changes)
+	}
+}
+
+func TestReviewDeleteRoleBinding(t *testing.T) {
+	changes := review(t, makeStorage(), "unittest", &authorizationapi.PolicyChangeReview{
+		RoleBinding: &authorizationapi.RoleBinding{
+			ObjectMeta: kapi.ObjectMeta{Name: "viewers"},
+			RoleRef:    kapi.ObjectReference{Name: "view", Namespace: "master"},
+		},
+		Delete: true,
+	})
+
+	expected := []authorizationapi.AccessChange{
+		{UserName: "alice", Namespace: "unittest", Added: []authorizationapi.PolicyRule{}, Removed: []authorizationapi.PolicyRule{viewRule}},
+	}
+	if !reflect.DeepEqual(expected, changes) {
+		t.Errorf("expected %#v, got %#v", expected, changes)
+	}
+}
+
+func TestReviewUnchangedRoleBinding(t *testing.T) {
+	changes := review(t, makeStorage(), "unittest", &authorizationapi.PolicyChangeReview{
+		RoleBinding: &authorizationapi.RoleBinding{
+			ObjectMeta: kapi.ObjectMeta{Name: "viewers"},
+			UserNames:  []string{"alice"},
+			RoleRef:    kapi.ObjectReference{Name: "view", Namespace: "master"},
+		},
+	})
+	if len(changes) != 0 {
+		t.Errorf("unexpected changes: %#v", changes)
+	}
+}
+
+func TestReviewRole(t *testing.T) {
+	changes := review(t, makeStorage(), "master", &authorizationapi.PolicyChangeReview{
+		Role: &authorizationapi.Role{
+			ObjectMeta: kapi.ObjectMeta{Name: "view"},
+			Rules:      []authorizationapi.PolicyRule{editRule},
+		},
+	})
+	// role bindings in other namespaces refer to the roles of the master namespace
+	expected := []authorizationapi.AccessChange{
+		{UserName: "alice", Namespace: "unittest", Added: []authorizationapi.PolicyRule{editRule}, Removed: []authorizationapi.PolicyRule{viewRule}},
+	}
+	if !reflect.DeepEqual(expected, changes) {
+		t.Errorf("expected %#v, got %#v", expected, changes)
+	}
+
+	changes = review(t, makeStorage(), "master", &authorizationapi.PolicyChangeReview{
+		Role: &authorizationapi.Role{
+			ObjectMeta: kapi.ObjectMeta{Name: "edit"},
+			Rules:      []authorizationapi.PolicyRule{editRule},
+		},
+	})
+	// no role bindings refer to the role
+	if len(changes) != 0 {
+		t.Errorf("unexpected changes: %#v", changes)
+	}
+
+	changes = review(t, makeStorage(), "unittest", &authorizationapi.PolicyChangeReview{
+		Role: &authorizationapi.Role{
+			ObjectMeta: kapi.ObjectMeta{Name: "local"},
+			Rules:      []authorizationapi.PolicyRule{editRule},
+		},
+	})
+	expected = []authorizationapi.AccessChange{
+		{UserName: "carol", Namespace: "unittest", Added: []authorizationapi.PolicyRule{editRule}, Removed: []authorizationapi.PolicyRule{viewRule}},
+	}
+	if !reflect.DeepEqual(expected, changes) {
+		t.Errorf("expected %#v, got %#v", expected, changes)
+	}
+
+	changes = review(t, makeStorage(), "unittest", &authorizationapi.PolicyChangeReview{
+		Role:   &authorizationapi.Role{ObjectMeta: kapi.ObjectMeta{Name: "local"}},
+		Delete: true,
+	})
+	expected = []authorizationapi.AccessChange{
+		{UserName: "carol", Namespace: "unittest", Added: []authorizationapi.PolicyRule{}, Removed: []authorizationapi.PolicyRule{viewRule}},
+	}
+	if !reflect.DeepEqual(expected, changes) {
+		t.Errorf("expected %#v, got %#v", expected, changes)
+	}
+}
+
+func TestReviewInvalid(t *testing.T) {
+	ctx := kapi.WithNamespace(kapi.NewContext(), "unittest")
+	if _, err := makeStorage().Create(ctx, &authorizationapi.PolicyChangeReview{}); err == nil {
+		t.Errorf("expected a validation error")
+	}
+}
@@ -13,6 +13,7 @@ import (
 	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
 	"github.com/openshift/origin/pkg/authorization/api/validation"
 	policyregistry "github.com/openshift/origin/pkg/authorization/registry/policy"
+	"github.com/openshift/origin/pkg/authorization/registry/policychangereview"
 )
 
 // TODO add get and list
@@ -21,11 +22,12 @@ import (
 // REST implements the RESTStorage interface in terms of an Registry.
 type REST struct {
 	registry policyregistry.Registry
+	reviewer policychangereview.Reviewer
 }
 
-// NewREST creates a new REST for policies.
-func NewREST(registry policyregistry.Registry) apiserver.RESTStorage {
-	return &REST{registry}
+// NewREST creates a new REST for policies.  Roles annotated for a dry run are reviewed by reviewer.
+func NewREST(registry policyregistry.Registry, reviewer policychangereview.Reviewer) apiserver.RESTStorage {
+	return &REST{registry, reviewer}
 }
 
 // New creates a new Role object
@@ -74,6 +76,9 @@ func (r *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 	if doesRoleExist(role.Name, policy) {
 		return nil, fmt.Errorf("role %v already exists", role.Name)
 	}
+	if policychangereview.IsDryRun(&role.ObjectMeta) {
+		return policychangereview.DryRun(ctx, r.reviewer, &authorizationapi.PolicyChangeReview{Role: role})
+	}
 
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
 		policy.Roles[role.Name] = *role
@@ -107,6 +112,9 @@ func (r *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 	if !doesRoleExist(role.Name, policy) {
 		return nil, fmt.Errorf("role %v does not exist", role.Name)
 	}
+	if policychangereview.IsDryRun(&role.ObjectMeta) {
+		return policychangereview.DryRun(ctx, r.reviewer, &authorizationapi.PolicyChangeReview{Role: role})
+	}
 
 	// set defaults
 	role.CreationTimestamp = util.Now()
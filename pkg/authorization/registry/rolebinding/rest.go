@@ -15,6 +15,7 @@ import (
 	"github.com/openshift/origin/pkg/authorization/api/validation"
 	policyregistry "github.com/openshift/origin/pkg/authorization/registry/policy"
 	policybindingregistry "github.com/openshift/origin/pkg/authorization/registry/policybinding"
+	"github.com/openshift/origin/pkg/authorization/registry/policychangereview"
 	userregistry "github.com/openshift/origin/pkg/user/registry/user"
 )
 
@@ -27,11 +28,12 @@ type REST struct {
 	policyRegistry               policyregistry.Registry
 	userRegistry                 userregistry.Registry
 	masterAuthorizationNamespace string
+	reviewer                     policychangereview.Reviewer
 }
 
-// NewREST creates a new REST for policies.
-func NewREST(bindingRegistry policybindingregistry.Registry, policyRegistry policyregistry.Registry, userRegistry userregistry.Registry, masterAuthorizationNamespace string) apiserver.RESTStorage {
-	return &REST{bindingRegistry, policyRegistry, userRegistry, masterAuthorizationNamespace}
+// NewREST creates a new REST for policies.  Role bindings annotated for a dry run are reviewed by reviewer.
+func NewREST(bindingRegistry policybindingregistry.Registry, policyRegistry policyregistry.Registry, userRegistry userregistry.Registry, masterAuthorizationNamespace string, reviewer policychangereview.Reviewer) apiserver.RESTStorage {
+	return &REST{bindingRegistry, policyRegistry, userRegistry, masterAuthorizationNamespace, reviewer}
 }
 
 // New creates a new RoleBinding object
@@ -75,6 +77,9 @@ func (r *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 	if err := r.validateReferentialIntegrity(ctx, roleBinding); err != nil {
 		return nil, err
 	}
+	if policychangereview.IsDryRun(&roleBinding.ObjectMeta) {
+		return policychangereview.DryRun(ctx, r.reviewer, &authorizationapi.PolicyChangeReview{RoleBinding: roleBinding})
+	}
 
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
 		policyBinding, err := r.GetPolicyBinding(ctx, roleBinding.RoleRef.Namespace)
@@ -125,6 +130,9 @@ func (r *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 	if existingRoleBinding.RoleRef.Namespace != roleBinding.RoleRef.Namespace {
 		return nil, fmt.Errorf("cannot change roleBinding.RoleRef.Namespace from %v to %v", existingRoleBinding.RoleRef.Namespace, roleBinding.RoleRef.Namespace)
 	}
+	if policychangereview.IsDryRun(&roleBinding.ObjectMeta) {
+		return policychangereview.DryRun(ctx, r.reviewer, &authorizationapi.PolicyChangeReview{RoleBinding: roleBinding})
+	}
 
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
 		policyBinding, err := r.GetPolicyBinding(ctx, roleBinding.RoleRef.Namespace)
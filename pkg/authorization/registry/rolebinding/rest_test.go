@@ -8,6 +8,7 @@ import (
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
+	"github.com/openshift/origin/pkg/authorization/registry/policychangereview"
 	"github.com/openshift/origin/pkg/authorization/registry/test"
 	usertest "github.com/openshift/origin/pkg/user/registry/test"
 )
@@ -24,7 +25,7 @@ func makeSimpleStorage() (*REST, *test.PolicyBindingRegistry) {
 		}}
 	userRegistry := &usertest.UserRegistry{}
 
-	return &REST{bindingRegistry, policyRegistry, userRegistry, "master"}, bindingRegistry
+	return &REST{bindingRegistry, policyRegistry, userRegistry, "master", nil}, bindingRegistry
 }
 
 func TestCreateValidationError(t *testing.T) {
@@ -98,6 +99,45 @@ func TestCreateValidAutoCreateMasterPolicyBindings(t *testing.T) {
 	}
 }
 
+func TestCreateDryRun(t *testing.T) {
+	storage, registry := makeSimpleStorage()
+	storage.reviewer = policychangereview.NewReviewer(storage.policyRegistry, registry)
+
+	roleBinding := &authorizationapi.RoleBinding{
+		ObjectMeta: kapi.ObjectMeta{
+			Name:        "my-roleBinding",
+			Annotations: map[string]string{authorizationapi.DryRunAnnotation: "true"},
+		},
+		UserNames: []string{"bob"},
+		RoleRef:   kapi.ObjectReference{Name: "admin", Namespace: "master"},
+	}
+
+	ctx := kapi.WithNamespace(kapi.NewContext(), "unittest")
+	channel, err := storage.Create(ctx, roleBinding)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case r := <-channel:
+		review, ok := r.Object.(*authorizationapi.PolicyChangeReview)
+		if !ok {
+			t.Fatalf("Got unexpected type: %#v", r.Object)
+		}
+		if len(review.Changes) != 0 {
+			t.Errorf("Unexpected changes from a role without rules: %#v", review.Changes)
+		}
+		if review.RoleBinding != roleBinding {
+			t.Errorf("Expected the review of %#v, got %#v", roleBinding, review.RoleBinding)
+		}
+	case <-time.After(time.Millisecond * 100):
+		t.Error("Unexpected timeout from async channel")
+	}
+	if len(registry.PolicyBindings) != 0 {
+		t.Errorf("Unexpected policy bindings created by a dry run: %#v", registry.PolicyBindings)
+	}
+}
+
 func TestCreateValid(t *testing.T) {
 	storage, registry := makeSimpleStorage()
 	registry.PolicyBindings = append(make([]authorizationapi.PolicyBinding, 0),
@@ -204,7 +244,7 @@ func TestDeleteError(t *testing.T) {
 	registry.Err = errors.New("Sample Error")
 	policyRegistry := &test.PolicyRegistry{}
 	userRegistry := &usertest.UserRegistry{}
-	storage := &REST{registry, policyRegistry, userRegistry, "master"}
+	storage := &REST{registry, policyRegistry, userRegistry, "master", nil}
 
 	ctx := kapi.WithNamespace(kapi.NewContext(), "unittest")
 	channel, err := storage.Delete(ctx, "foo")
@@ -233,7 +273,7 @@ func TestDeleteValid(t *testing.T) {
 	registry := &test.PolicyBindingRegistry{}
 	policyRegistry := &test.PolicyRegistry{}
 	userRegistry := &usertest.UserRegistry{}
-	storage := &REST{registry, policyRegistry, userRegistry, "master"}
+	storage := &REST{registry, policyRegistry, userRegistry, "master", nil}
 	registry.PolicyBindings = append(make([]authorizationapi.PolicyBinding, 0),
 		authorizationapi.PolicyBinding{
 			ObjectMeta: kapi.ObjectMeta{Name: "master", Namespace: "unittest"},
@@ -6,6 +6,7 @@ import (
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	klabels "github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
 )
@@ -76,3 +77,8 @@ func (r *PolicyRegistry) DeletePolicy(ctx kapi.Context, id string) error {
 	r.DeletedPolicyName = id
 	return r.Err
 }
+
+// WatchPolicies watches for new/modified/deleted policies.
+func (r *PolicyRegistry) WatchPolicies(ctx kapi.Context, labels, fields klabels.Selector, resourceVersion string) (watch.Interface, error) {
+	return nil, r.Err
+}
@@ -6,6 +6,7 @@ import (
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	klabels "github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
 )
@@ -24,13 +25,10 @@ func (r *PolicyBindingRegistry) ListPolicyBindings(ctx kapi.Context, labels, fie
 	}
 
 	namespace := kapi.Namespace(ctx)
-	if len(namespace) == 0 {
-		return nil, errors.New("invalid request.  Namespace parameter required.")
-	}
 
 	list := make([]authorizationapi.PolicyBinding, 0)
 	for _, curr := range r.PolicyBindings {
-		if curr.Namespace == namespace {
+		if namespace == kapi.NamespaceAll || curr.Namespace == namespace {
 			list = append(list, curr)
 		}
 	}
@@ -79,3 +77,8 @@ func (r *PolicyBindingRegistry) DeletePolicyBinding(ctx kapi.Context, id string)
 	r.DeletedPolicyBindingName = id
 	return r.Err
 }
+
+// WatchPolicyBindings watches for new/modified/deleted policyBindings.
+func (r *PolicyBindingRegistry) WatchPolicyBindings(ctx kapi.Context, labels, fields klabels.Selector, resourceVersion string) (watch.Interface, error) {
+	return nil, r.Err
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/api/selectable"
+)
+
+func init() {
+	selectable.Register(&Build{}, func(obj runtime.Object) labels.Set { return BuildToSelectableFields(obj.(*Build)) })
+	selectable.Register(&BuildConfig{}, func(obj runtime.Object) labels.Set { return BuildConfigToSelectableFields(obj.(*BuildConfig)) })
+}
+
+// BuildToSelectableFields returns the fields of a build that clients can select on.
+func BuildToSelectableFields(build *Build) labels.Set {
+	return labels.Set{
+		"name":    build.Name,
+		"status":  string(build.Status),
+		"podName": build.PodName,
+	}
+}
+
+// BuildConfigToSelectableFields returns the fields of a build config that clients can select on.
+func BuildConfigToSelectableFields(config *BuildConfig) labels.Set {
+	return labels.Set{
+		"name": config.Name,
+	}
+}
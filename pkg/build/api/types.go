@@ -40,6 +40,10 @@ type BuildParameters struct {
 
 	// Output describes the Docker image the Strategy should produce.
 	Output BuildOutput `json:"output,omitempty"`
+
+	// CompletionDeadlineSeconds is the number of seconds the pod of a build may run before the
+	// build is failed and its pod killed. This is optional.
+	CompletionDeadlineSeconds *int64 `json:"completionDeadlineSeconds,omitempty"`
 }
 
 // BuildStatus represents the status of a build at a point in time.
@@ -83,8 +87,35 @@ const (
 type BuildSource struct {
 	Type BuildSourceType `json:"type,omitempty"`
 	Git  *GitBuildSource `json:"git,omitempty"`
+
+	// SourceSecret is the name of the credentials the source is cloned with, if it is private.
+	SourceSecret *SourceSecret `json:"sourceSecret,omitempty"`
+}
+
+// SourceSecret names the credentials a build clones a private source repository or pushes its output with: a
+// directory under the source secrets directory of the build nodes, named by the namespace of the build and
+// then the secret name.  A source secret holds either ssh-privatekey and known_hosts files or username and
+// password files, and a push secret holds a .dockercfg file.  The directory is mounted read only into the build pod, so the
+// credentials are never part of the built image.
+type SourceSecret struct {
+	// Name of the secret
+	Name string `json:"name"`
 }
 
+const (
+	// SourceSecretSSHKeyFile is the file of a source secret that holds a private SSH key
+	SourceSecretSSHKeyFile = "ssh-privatekey"
+	// SourceSecretKnownHostsFile is the file of a source secret that holds the SSH host keys the source
+	// repository may be cloned from
+	SourceSecretKnownHostsFile = "known_hosts"
+	// SourceSecretUsernameFile is the file of a source secret that holds the username of basic authentication
+	SourceSecretUsernameFile = "username"
+	// SourceSecretPasswordFile is the file of a source secret that holds the password of basic authentication
+	SourceSecretPasswordFile = "password"
+	// PushSecretDockercfgFile is the file of a push secret that holds the credentials of Docker registries
+	PushSecretDockercfgFile = ".dockercfg"
+)
+
 // SourceRevision is the revision or commit information from the source for the build
 type SourceRevision struct {
 	Type BuildSourceType    `json:"type,omitempty"`
@@ -192,6 +223,10 @@ type DockerBuildStrategy struct {
 	// build should "FROM".  If present, the build process will substitute this value
 	// into the FROM line of the dockerfile.
 	BaseImage string `json:"baseImage,omitempty"`
+
+	// BuilderPodImage is optional and is the image the build pod runs to perform the build
+	// instead of the Docker builder image of the cluster. The cluster must allow the image.
+	BuilderPodImage string `json:"builderPodImage,omitempty"`
 }
 
 // STIBuildStrategy defines input parameters specific to an STI build.
@@ -207,6 +242,10 @@ type STIBuildStrategy struct {
 
 	// Clean flag forces the STI build to not do incremental builds if true.
 	Clean bool `json:"clean,omitempty"`
+
+	// BuilderPodImage is optional and is the image the build pod runs to perform the build
+	// instead of the STI builder image of the cluster. The cluster must allow the image.
+	BuilderPodImage string `json:"builderPodImage,omitempty"`
 }
 
 // BuildOutput is input to a build strategy and describes the Docker image that the strategy
@@ -228,12 +267,20 @@ type BuildOutput struct {
 	// DockerImageReference is the full name of an image ([registry/]name[:tag]), and will be the
 	// value sent to Docker push at the end of a build if the To field is not defined.
 	DockerImageReference string `json:"dockerImageReference,omitempty"`
+
+	// PushSecret is the name of the credentials the output is pushed to its registry with, if the registry
+	// requires authentication.
+	PushSecret *SourceSecret `json:"pushSecret,omitempty"`
 }
 
 // BuildConfigLabel is the key of a Build label whose value is the ID of a BuildConfig
 // on which the Build is based.
 const BuildConfigLabel = "buildconfig"
 
+// BuildAnnotation is an annotation on a build Pod whose value is the name of the Build the Pod
+// executes.
+const BuildAnnotation = "build"
+
 // BuildConfig is a template which can be used to create new builds.
 type BuildConfig struct {
 	kapi.TypeMeta   `json:",inline"`
@@ -246,6 +293,31 @@ type BuildConfig struct {
 	// Parameters holds all the input necessary to produce a new build. A build config may only
 	// define either the Output.To or Output.DockerImageReference fields, but not both.
 	Parameters BuildParameters `json:"parameters,omitempty"`
+
+	// AllowedOverrides lists the overrides webhook and manual triggers may supply for a single
+	// build. If not set, triggers may not override the parameters of the build.
+	AllowedOverrides *BuildOverridePolicy `json:"allowedOverrides,omitempty"`
+}
+
+// BuildOverridePolicy lists the overrides triggers may supply for a single build of a BuildConfig.
+type BuildOverridePolicy struct {
+	// Env is the names of the environment variables of the build strategy triggers may set.
+	Env []string `json:"env,omitempty"`
+
+	// Ref allows triggers to build a branch, tag or commit other than the one of the BuildConfig.
+	Ref bool `json:"ref,omitempty"`
+}
+
+// BuildOverrides are supplied by a webhook or manual trigger to parameterize a single build.
+type BuildOverrides struct {
+	// Env sets environment variables of the build strategy.
+	Env []kapi.EnvVar `json:"env,omitempty"`
+
+	// Ref is the branch or tag to build instead of the ref of the BuildConfig.
+	Ref string `json:"ref,omitempty"`
+
+	// Commit is the commit to build.
+	Commit string `json:"commit,omitempty"`
 }
 
 // WebHookTrigger is a trigger that gets invoked using a webhook type of post
@@ -263,9 +335,11 @@ type ImageChangeTrigger struct {
 	// precedence over ImageRepositoryRef, which is deprecated and will be removed in v1beta2. The
 	// Kind may be left blank, in which case it defaults to "ImageRepository". The "Name" is
 	// the only required subfield - if Namespace is blank, the namespace of the current deployment
-	// trigger will be used.
+	// trigger will be used. If Kind is "BuildConfig", the image repository and tag the referenced
+	// BuildConfig outputs to are watched, chaining a build to the output of another.
 	From kapi.ObjectReference `json:"from"`
-	// Tag is the name of an image repository tag to watch for changes.
+	// Tag is the name of an image repository tag to watch for changes. For a BuildConfig, it
+	// defaults to the output tag of the BuildConfig.
 	Tag string `json:"tag,omitempty"`
 	// LastTriggeredImageID is used internally by the ImageChangeController to save last
 	// used image ID for build
@@ -308,7 +382,10 @@ const (
 type BuildList struct {
 	kapi.TypeMeta `json:",inline"`
 	kapi.ListMeta `json:"metadata,omitempty"`
-	Items         []Build `json:"items"`
+	// Continue is set when the list is a page of a larger list, and continues it from the next page when
+	// passed as the continue query parameter.
+	Continue string  `json:"continue,omitempty"`
+	Items    []Build `json:"items"`
 }
 
 // BuildConfigList is a collection of BuildConfigs.
@@ -325,6 +402,10 @@ type GenericWebHookEvent struct {
 
 	// Git is the git information if the Type is BuildSourceGit
 	Git *GitInfo `json:"git,omitempty"`
+
+	// Env sets environment variables of the build strategy. The BuildConfig must allow them to be
+	// overridden.
+	Env []kapi.EnvVar `json:"env,omitempty"`
 }
 
 // GitInfo is the aggregated git information for a generic webhook post
@@ -16,12 +16,14 @@ func init() {
 			out.Image = in.Image
 			out.Scripts = in.Scripts
 			out.Clean = in.Clean
+			out.BuilderPodImage = in.BuilderPodImage
 			s.Convert(&in.Env, &out.Env, 0)
 			return nil
 		},
 		func(in *STIBuildStrategy, out *newer.STIBuildStrategy, s conversion.Scope) error {
 			out.Scripts = in.Scripts
 			out.Clean = in.Clean
+			out.BuilderPodImage = in.BuilderPodImage
 			s.Convert(&in.Env, &out.Env, 0)
 			if len(in.Image) != 0 {
 				out.Image = in.Image
@@ -35,6 +37,9 @@ func init() {
 			if err := s.Convert(&in.To, &out.To, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.PushSecret, &out.PushSecret, 0); err != nil {
+				return err
+			}
 			out.Tag = in.Tag
 			if len(in.DockerImageReference) > 0 {
 				out.DockerImageReference = in.DockerImageReference
@@ -48,6 +53,9 @@ func init() {
 			if err := s.Convert(&in.To, &out.To, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.PushSecret, &out.PushSecret, 0); err != nil {
+				return err
+			}
 			out.Tag = in.Tag
 			if len(in.DockerImageReference) > 0 {
 				out.DockerImageReference = in.DockerImageReference
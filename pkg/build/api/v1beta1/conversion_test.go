@@ -70,3 +70,25 @@ func TestImageChangeTriggerFromRename(t *testing.T) {
 		t.Errorf("expected %#v, actual %#v", old, actual)
 	}
 }
+
+func TestBuildOutputPushSecretConversion(t *testing.T) {
+	old := current.BuildOutput{
+		ImageTag:   "foo/bar",
+		PushSecret: &current.SourceSecret{Name: "registry"},
+	}
+	actual := newer.BuildOutput{}
+	if err := Convert(&old, &actual); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual.PushSecret == nil || actual.PushSecret.Name != "registry" {
+		t.Errorf("expected the push secret to be converted, got %#v", actual)
+	}
+
+	back := current.BuildOutput{}
+	if err := Convert(&actual, &back); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if back.PushSecret == nil || back.PushSecret.Name != "registry" {
+		t.Errorf("expected the push secret to be converted back, got %#v", back)
+	}
+}
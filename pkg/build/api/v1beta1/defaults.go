@@ -0,0 +1,29 @@
+package v1beta1
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/conversion"
+
+	newer "github.com/openshift/origin/pkg/build/api"
+)
+
+// Defaults are set as objects are converted from v1beta1, so objects are fully specified when they are
+// created and when they are read back from storage.
+func init() {
+	err := api.Scheme.AddConversionFuncs(
+		// Every option of the Docker strategy is optional, so clients may omit the options entirely
+		func(in *BuildStrategy, out *newer.BuildStrategy, s conversion.Scope) error {
+			if err := s.DefaultConvert(in, out, conversion.IgnoreMissingFields); err != nil {
+				return err
+			}
+			if out.Type == newer.DockerBuildStrategyType && out.DockerStrategy == nil {
+				out.DockerStrategy = &newer.DockerBuildStrategy{}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		// If one of the conversion functions is malformed, detect it immediately.
+		panic(err)
+	}
+}
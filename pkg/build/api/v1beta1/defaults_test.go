@@ -0,0 +1,28 @@
+package v1beta1_test
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	newer "github.com/openshift/origin/pkg/build/api"
+	_ "github.com/openshift/origin/pkg/build/api/v1beta1"
+)
+
+func TestDefaultDockerStrategy(t *testing.T) {
+	obj, err := api.Scheme.Decode([]byte(`{"kind":"Build","apiVersion":"v1beta1","parameters":{"strategy":{"type":"Docker"}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if build := obj.(*newer.Build); build.Parameters.Strategy.DockerStrategy == nil {
+		t.Errorf("expected the Docker strategy options to be defaulted, got %#v", build.Parameters.Strategy)
+	}
+
+	obj, err = api.Scheme.Decode([]byte(`{"kind":"Build","apiVersion":"v1beta1","parameters":{"strategy":{"type":"STI","stiStrategy":{"image":"ruby"}}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if build := obj.(*newer.Build); build.Parameters.Strategy.DockerStrategy != nil || build.Parameters.Strategy.STIStrategy.Image != "ruby" {
+		t.Errorf("expected only the STI strategy options, got %#v", build.Parameters.Strategy)
+	}
+}
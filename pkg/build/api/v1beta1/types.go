@@ -11,35 +11,39 @@ type Build struct {
 	kapi.ObjectMeta `json:"metadata,omitempty"`
 
 	// Parameters are all the inputs used to create the build pod.
-	Parameters BuildParameters `json:"parameters,omitempty"`
+	Parameters BuildParameters `json:"parameters,omitempty" description:"Parameters are all the inputs used to create the build pod."`
 
 	// Status is the current status of the build.
-	Status BuildStatus `json:"status,omitempty"`
+	Status BuildStatus `json:"status,omitempty" description:"Status is the current status of the build."`
 
 	// A human readable message indicating details about why the build has this status
-	Message string `json:"message,omitempty"`
+	Message string `json:"message,omitempty" description:"A human readable message indicating details about why the build has this status"`
 
 	// PodName is the name of the pod that is used to execute the build
-	PodName string `json:"podName,omitempty"`
+	PodName string `json:"podName,omitempty" description:"PodName is the name of the pod that is used to execute the build"`
 
 	// Cancelled describes if a cancelling event was triggered for the build.
-	Cancelled bool `json:"cancelled,omitempty"`
+	Cancelled bool `json:"cancelled,omitempty" description:"Cancelled describes if a cancelling event was triggered for the build."`
 }
 
 // BuildParameters encapsulates all the inputs necessary to represent a build.
 type BuildParameters struct {
 	// Source describes the SCM in use.
-	Source BuildSource `json:"source,omitempty"`
+	Source BuildSource `json:"source,omitempty" description:"Source describes the SCM in use."`
 
 	// Revision is the information from the source for a specific repo snapshot.
 	// This is optional.
-	Revision *SourceRevision `json:"revision,omitempty"`
+	Revision *SourceRevision `json:"revision,omitempty" description:"Revision is the information from the source for a specific repo snapshot. This is optional."`
 
 	// Strategy defines how to perform a build.
-	Strategy BuildStrategy `json:"strategy,omitempty"`
+	Strategy BuildStrategy `json:"strategy,omitempty" description:"Strategy defines how to perform a build."`
 
 	// Output describes the Docker image the Strategy should produce.
-	Output BuildOutput `json:"output,omitempty"`
+	Output BuildOutput `json:"output,omitempty" description:"Output describes the Docker image the Strategy should produce."`
+
+	// CompletionDeadlineSeconds is the number of seconds the pod of a build may run before the
+	// build is failed and its pod killed. This is optional.
+	CompletionDeadlineSeconds *int64 `json:"completionDeadlineSeconds,omitempty" description:"CompletionDeadlineSeconds is the number of seconds the pod of a build may run before the build is failed and its pod killed. This is optional."`
 }
 
 // BuildStatus represents the status of a build at a point in time.
@@ -83,6 +87,15 @@ const (
 type BuildSource struct {
 	Type BuildSourceType `json:"type,omitempty"`
 	Git  *GitBuildSource `json:"git,omitempty"`
+
+	// SourceSecret is the name of the credentials the source is cloned with, if it is private.
+	SourceSecret *SourceSecret `json:"sourceSecret,omitempty" description:"SourceSecret is the name of the credentials the source is cloned with, if it is private"`
+}
+
+// SourceSecret names the credentials a build clones a private source repository or pushes its output with.
+type SourceSecret struct {
+	// Name of the secret
+	Name string `json:"name" description:"Name of the secret, a directory under the source secrets directory of the build nodes holding either ssh-privatekey and known_hosts files or username and password files"`
 }
 
 // SourceRevision is the revision or commit information from the source for the build
@@ -94,26 +107,26 @@ type SourceRevision struct {
 // GitSourceRevision is the commit information from a git source for a build
 type GitSourceRevision struct {
 	// Commit is the commit hash identifying a specific commit
-	Commit string `json:"commit,omitempty"`
+	Commit string `json:"commit,omitempty" description:"Commit is the commit hash identifying a specific commit"`
 
 	// Author is the author of a specific commit
-	Author SourceControlUser `json:"author,omitempty"`
+	Author SourceControlUser `json:"author,omitempty" description:"Author is the author of a specific commit"`
 
 	// Committer is the commiter of a specific commit
-	Committer SourceControlUser `json:"committer,omitempty"`
+	Committer SourceControlUser `json:"committer,omitempty" description:"Committer is the commiter of a specific commit"`
 
 	// Message is the description of a specific commit
-	Message string `json:"message,omitempty"`
+	Message string `json:"message,omitempty" description:"Message is the description of a specific commit"`
 }
 
 // GitBuildSource defines the parameters of a Git SCM
 type GitBuildSource struct {
 	// URI points to the source that will be built. The structure of the source
 	// will depend on the type of build to run
-	URI string `json:"uri,omitempty"`
+	URI string `json:"uri,omitempty" description:"URI points to the source that will be built. The structure of the source will depend on the type of build to run"`
 
 	// Ref is the branch/tag/ref to build.
-	Ref string `json:"ref,omitempty"`
+	Ref string `json:"ref,omitempty" description:"Ref is the branch/tag/ref to build."`
 }
 
 // SourceControlUser defines the identity of a user of source control
@@ -125,16 +138,16 @@ type SourceControlUser struct {
 // BuildStrategy contains the details of how to perform a build.
 type BuildStrategy struct {
 	// Type is the kind of build strategy.
-	Type BuildStrategyType `json:"type,omitempty"`
+	Type BuildStrategyType `json:"type,omitempty" description:"Type is the kind of build strategy."`
 
 	// DockerStrategy holds the parameters to the Docker build strategy.
-	DockerStrategy *DockerBuildStrategy `json:"dockerStrategy,omitempty"`
+	DockerStrategy *DockerBuildStrategy `json:"dockerStrategy,omitempty" description:"DockerStrategy holds the parameters to the Docker build strategy."`
 
 	// STIStrategy holds the parameters to the STI build strategy.
-	STIStrategy *STIBuildStrategy `json:"stiStrategy,omitempty"`
+	STIStrategy *STIBuildStrategy `json:"stiStrategy,omitempty" description:"STIStrategy holds the parameters to the STI build strategy."`
 
 	// CustomStrategy holds the parameters to the Custom build strategy
-	CustomStrategy *CustomBuildStrategy `json:"customStrategy,omitempty"`
+	CustomStrategy *CustomBuildStrategy `json:"customStrategy,omitempty" description:"CustomStrategy holds the parameters to the Custom build strategy"`
 }
 
 // BuildStrategyType describes a particular way of performing a build.
@@ -157,15 +170,15 @@ const (
 type CustomBuildStrategy struct {
 	// Image is the image required to execute the build. If not specified
 	// a validation error is returned.
-	Image string `json:"image"`
+	Image string `json:"image" description:"Image is the image required to execute the build. If not specified a validation error is returned."`
 
 	// Additional environment variables you want to pass into a builder container
-	Env []kapi.EnvVar `json:"env,omitempty"`
+	Env []kapi.EnvVar `json:"env,omitempty" description:"Additional environment variables you want to pass into a builder container"`
 
 	// ExposeDockerSocket will allow running Docker commands (and build Docker images) from
 	// inside the Docker container.
 	// TODO: Allow admins to enforce 'false' for this option
-	ExposeDockerSocket bool `json:"exposeDockerSocket,omitempty"`
+	ExposeDockerSocket bool `json:"exposeDockerSocket,omitempty" description:"ExposeDockerSocket will allow running Docker commands (and build Docker images) from inside the Docker container."`
 }
 
 // DockerBuildStrategy defines input parameters specific to Docker build.
@@ -173,35 +186,43 @@ type DockerBuildStrategy struct {
 	// ContextDir is used as the Docker build context. It is a path for a directory within the
 	// application source directory structure (as referenced in the BuildSource. See GitBuildSource
 	// for an example.)
-	ContextDir string `json:"contextDir,omitempty"`
+	ContextDir string `json:"contextDir,omitempty" description:"ContextDir is used as the Docker build context. It is a path for a directory within the application source directory structure (as referenced in the BuildSource. See GitBuildSource for an example.)"`
 
 	// NoCache if set to true indicates that the docker build must be executed with the
 	// --no-cache=true flag
-	NoCache bool `json:"noCache,omitempty"`
+	NoCache bool `json:"noCache,omitempty" description:"NoCache if set to true indicates that the docker build must be executed with the --no-cache=true flag"`
 
 	// BaseImage is optional and indicates the image that the dockerfile for this
 	// build should "FROM".  If present, the build process will substitute this value
 	// into the FROM line of the dockerfile.
-	BaseImage string `json:"baseImage,omitempty"`
+	BaseImage string `json:"baseImage,omitempty" description:"BaseImage is optional and indicates the image that the dockerfile for this build should 'FROM'.  If present, the build process will substitute this value into the FROM line of the dockerfile."`
+
+	// BuilderPodImage is optional and is the image the build pod runs to perform the build
+	// instead of the Docker builder image of the cluster. The cluster must allow the image.
+	BuilderPodImage string `json:"builderPodImage,omitempty" description:"BuilderPodImage is optional and is the image the build pod runs to perform the build instead of the Docker builder image of the cluster. The cluster must allow the image."`
 }
 
 // STIBuildStrategy defines input parameters specific to an STI build.
 type STIBuildStrategy struct {
 	// BuilderImage is the image used to execute the build.
 	// Deprecated: will be removed in v1beta2, use Image.
-	BuilderImage string `json:"builderImage,omitempty"`
+	BuilderImage string `json:"builderImage,omitempty" description:"BuilderImage is the image used to execute the build. Deprecated: will be removed in v1beta2, use Image."`
 
 	// Image is the image used to execute the build.
-	Image string `json:"image,omitempty"`
+	Image string `json:"image,omitempty" description:"Image is the image used to execute the build."`
 
 	// Additional environment variables you want to pass into a builder container
-	Env []kapi.EnvVar `json:"env,omitempty"`
+	Env []kapi.EnvVar `json:"env,omitempty" description:"Additional environment variables you want to pass into a builder container"`
 
 	// Scripts is the location of STI scripts
-	Scripts string `json:"scripts,omitempty"`
+	Scripts string `json:"scripts,omitempty" description:"Scripts is the location of STI scripts"`
 
 	// Clean flag forces the STI build to not do incremental builds if true.
-	Clean bool `json:"clean,omitempty"`
+	Clean bool `json:"clean,omitempty" description:"Clean flag forces the STI build to not do incremental builds if true."`
+
+	// BuilderPodImage is optional and is the image the build pod runs to perform the build
+	// instead of the STI builder image of the cluster. The cluster must allow the image.
+	BuilderPodImage string `json:"builderPodImage,omitempty" description:"BuilderPodImage is optional and is the image the build pod runs to perform the build instead of the STI builder image of the cluster. The cluster must allow the image."`
 }
 
 // BuildOutput is input to a build strategy and describes the Docker image that the strategy
@@ -212,26 +233,30 @@ type BuildOutput struct {
 	// the build. Kind must be set to 'ImageRepository' and is the only supported value. If set,
 	// this field takes priority over DockerImageReference. This value will be used to look up
 	// a Docker image repository to push to.
-	To *kapi.ObjectReference `json:"to,omitempty"`
+	To *kapi.ObjectReference `json:"to,omitempty" description:"To defines an optional ImageRepository to push the output of this build to. The namespace may be empty, in which case the ImageRepository will be looked for in the namespace of the build. Kind must be set to 'ImageRepository' and is the only supported value. If set, this field takes priority over DockerImageReference. This value will be used to look up a Docker image repository to push to."`
 
 	// Tag is the "version" that will be set on the remote server when the image is created. This
 	// field is only used if the To field is set, and is ignored when DockerImageReference is used.
 	// This value represents a consistent name for a set of related changes (v1, 5.x, 5.5, dev, stable)
 	// and defaults to the preferred label for "To" if not specified.
-	Tag string `json:"tag,omitempty"`
+	Tag string `json:"tag,omitempty" description:"Tag is the 'version' that will be set on the remote server when the image is created. This field is only used if the To field is set, and is ignored when DockerImageReference is used. This value represents a consistent name for a set of related changes (v1, 5.x, 5.5, dev, stable) and defaults to the preferred label for 'To' if not specified."`
 
 	// DockerImageReference is the full name of an image ([registry/]name[:tag]), and will be the
 	// value sent to Docker push at the end of a build.  If set, this field takes priority over
 	// ImageTag and Registry.
-	DockerImageReference string `json:"dockerImageReference,omitempty"`
+	DockerImageReference string `json:"dockerImageReference,omitempty" description:"DockerImageReference is the full name of an image ([registry/]name[:tag]), and will be the value sent to Docker push at the end of a build.  If set, this field takes priority over ImageTag and Registry."`
 
 	// ImageTag is the tag to give to the image resulting from the build.
 	// DEPRECATED: use DockerImageReference
-	ImageTag string `json:"imageTag,omitempty"`
+	ImageTag string `json:"imageTag,omitempty" description:"ImageTag is the tag to give to the image resulting from the build. DEPRECATED: use DockerImageReference"`
 
 	// Registry is the Docker registry which should receive the resulting built image via push.
 	// DEPRECATED: use DockerImageReference
-	Registry string `json:"registry,omitempty"`
+	Registry string `json:"registry,omitempty" description:"Registry is the Docker registry which should receive the resulting built image via push. DEPRECATED: use DockerImageReference"`
+
+	// PushSecret is the name of the credentials the output is pushed to its registry with, if the registry
+	// requires authentication.
+	PushSecret *SourceSecret `json:"pushSecret,omitempty" description:"PushSecret is the name of the credentials, a secret holding a .dockercfg file, the output is pushed to its registry with"`
 }
 
 // BuildConfigLabel is the key of a Build label whose value is the ID of a BuildConfig
@@ -245,54 +270,81 @@ type BuildConfig struct {
 
 	// Triggers determine how new Builds can be launched from a BuildConfig. If no triggers
 	// are defined, a new build can only occur as a result of an explicit client build creation.
-	Triggers []BuildTriggerPolicy `json:"triggers,omitempty"`
+	Triggers []BuildTriggerPolicy `json:"triggers,omitempty" description:"Triggers determine how new Builds can be launched from a BuildConfig. If no triggers are defined, a new build can only occur as a result of an explicit client build creation."`
 
 	// Parameters holds all the input necessary to produce a new build. A build config may only
 	// define either the Output.To or Output.DockerImageReference fields, but not both.
-	Parameters BuildParameters `json:"parameters,omitempty"`
+	Parameters BuildParameters `json:"parameters,omitempty" description:"Parameters holds all the input necessary to produce a new build. A build config may only define either the Output.To or Output.DockerImageReference fields, but not both."`
+
+	// AllowedOverrides lists the overrides webhook and manual triggers may supply for a single
+	// build. If not set, triggers may not override the parameters of the build.
+	AllowedOverrides *BuildOverridePolicy `json:"allowedOverrides,omitempty" description:"AllowedOverrides lists the overrides webhook and manual triggers may supply for a single build"`
+}
+
+// BuildOverridePolicy lists the overrides triggers may supply for a single build of a BuildConfig.
+type BuildOverridePolicy struct {
+	// Env is the names of the environment variables of the build strategy triggers may set.
+	Env []string `json:"env,omitempty" description:"Env is the names of the environment variables of the build strategy triggers may set"`
+
+	// Ref allows triggers to build a branch, tag or commit other than the one of the BuildConfig.
+	Ref bool `json:"ref,omitempty" description:"Ref allows triggers to build a branch, tag or commit other than the one of the BuildConfig"`
+}
+
+// BuildOverrides are supplied by a webhook or manual trigger to parameterize a single build.
+type BuildOverrides struct {
+	// Env sets environment variables of the build strategy.
+	Env []kapi.EnvVar `json:"env,omitempty" description:"Env sets environment variables of the build strategy"`
+
+	// Ref is the branch or tag to build instead of the ref of the BuildConfig.
+	Ref string `json:"ref,omitempty" description:"Ref is the branch or tag to build instead of the ref of the BuildConfig"`
+
+	// Commit is the commit to build.
+	Commit string `json:"commit,omitempty" description:"Commit is the commit to build"`
 }
 
 // WebHookTrigger is a trigger that gets invoked using a webhook type of post
 type WebHookTrigger struct {
 	// Secret used to validate requests.
-	Secret string `json:"secret,omitempty"`
+	Secret string `json:"secret,omitempty" description:"Secret used to validate requests."`
 }
 
 // ImageChangeTrigger allows builds to be triggered when an ImageRepository changes
 type ImageChangeTrigger struct {
 	// Image is used to specify the value in the BuildConfig to replace with the
 	// immutable image id supplied by the ImageRepository when this trigger fires.
-	Image          string `json:"image"`
+	Image          string `json:"image" description:"Image is used to specify the value in the BuildConfig to replace with the immutable image id supplied by the ImageRepository when this trigger fires."`
 	RepositoryName string `json:"repositoryName,omitempty"`
 	// From is a reference to a Docker image repository to watch for changes. This field takes
 	// precedence over ImageRepositoryRef, which is deprecated and will be removed in v1beta2. The
 	// Kind may be left blank, in which case it defaults to "ImageRepository". The "Name" is
 	// the only required subfield - if Namespace is blank, the namespace of the current deployment
-	// trigger will be used.
-	From kapi.ObjectReference `json:"from"`
+	// trigger will be used. If Kind is "BuildConfig", the image repository and tag the referenced
+	// BuildConfig outputs to are watched, chaining a build to the output of another.
+	From kapi.ObjectReference `json:"from" description:"From is a reference to a Docker image repository to watch for changes. This field takes precedence over ImageRepositoryRef, which is deprecated and will be removed in v1beta2. The Kind may be left blank, in which case it defaults to 'ImageRepository'. The 'Name' is the only required subfield - if Namespace is blank, the namespace of the current deployment trigger will be used. If Kind is 'BuildConfig', the image repository and tag the referenced BuildConfig outputs to are watched."`
 	// ImageRepositoryRef a reference to a Docker image repository to watch for changes.
 	// DEPRECATED: replaced by From
-	ImageRepositoryRef *kapi.ObjectReference `json:"imageRepositoryRef"`
-	// Tag is the name of an image repository tag to watch for changes.
-	Tag string `json:"tag,omitempty"`
+	ImageRepositoryRef *kapi.ObjectReference `json:"imageRepositoryRef" description:"ImageRepositoryRef a reference to a Docker image repository to watch for changes. DEPRECATED: replaced by From"`
+	// Tag is the name of an image repository tag to watch for changes. For a BuildConfig, it
+	// defaults to the output tag of the BuildConfig.
+	Tag string `json:"tag,omitempty" description:"Tag is the name of an image repository tag to watch for changes. For a BuildConfig, it defaults to the output tag of the BuildConfig."`
 	// LastTriggeredImageID is used internally by the ImageChangeController to save last
 	// used image ID for build
-	LastTriggeredImageID string `json:"lastTriggeredImageID,omitempty"`
+	LastTriggeredImageID string `json:"lastTriggeredImageID,omitempty" description:"LastTriggeredImageID is used internally by the ImageChangeController to save last used image ID for build"`
 }
 
 // BuildTriggerPolicy describes a policy for a single trigger that results in a new Build.
 type BuildTriggerPolicy struct {
 	// Type is the type of build trigger
-	Type BuildTriggerType `json:"type,omitempty"`
+	Type BuildTriggerType `json:"type,omitempty" description:"Type is the type of build trigger"`
 
 	// GithubWebHook contains the parameters for a Github webhook type of trigger
-	GithubWebHook *WebHookTrigger `json:"github,omitempty"`
+	GithubWebHook *WebHookTrigger `json:"github,omitempty" description:"GithubWebHook contains the parameters for a Github webhook type of trigger"`
 
 	// GenericWebHook contains the parameters for a Generic webhook type of trigger
-	GenericWebHook *WebHookTrigger `json:"generic,omitempty"`
+	GenericWebHook *WebHookTrigger `json:"generic,omitempty" description:"GenericWebHook contains the parameters for a Generic webhook type of trigger"`
 
 	// ImageChange contains parameters for an ImageChange type of trigger
-	ImageChange *ImageChangeTrigger `json:"imageChange,omitempty"`
+	ImageChange *ImageChangeTrigger `json:"imageChange,omitempty" description:"ImageChange contains parameters for an ImageChange type of trigger"`
 }
 
 // BuildTriggerType refers to a specific BuildTriggerPolicy implementation.
@@ -316,7 +368,10 @@ const (
 type BuildList struct {
 	kapi.TypeMeta `json:",inline"`
 	kapi.ListMeta `json:"metadata,omitempty"`
-	Items         []Build `json:"items"`
+	// Continue is set when the list is a page of a larger list, and continues it from the next page when
+	// passed as the continue query parameter.
+	Continue string  `json:"continue,omitempty"`
+	Items    []Build `json:"items"`
 }
 
 // BuildConfigList is a collection of BuildConfigs.
@@ -329,10 +384,14 @@ type BuildConfigList struct {
 // GenericWebHookEvent is the payload expected for a generic webhook post
 type GenericWebHookEvent struct {
 	// Type is the type of source repository
-	Type BuildSourceType `json:"type,omitempty"`
+	Type BuildSourceType `json:"type,omitempty" description:"Type is the type of source repository"`
 
 	// Git is the git information if the Type is BuildSourceGit
-	Git *GitInfo `json:"git,omitempty"`
+	Git *GitInfo `json:"git,omitempty" description:"Git is the git information if the Type is BuildSourceGit"`
+
+	// Env sets environment variables of the build strategy. The BuildConfig must allow them to be
+	// overridden.
+	Env []kapi.EnvVar `json:"env,omitempty" description:"Env sets environment variables of the build strategy, if the BuildConfig allows them to be overridden"`
 }
 
 // GitInfo is the aggregated git information for a generic webhook post
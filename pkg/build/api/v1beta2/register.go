@@ -0,0 +1,21 @@
+package v1beta2
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+func init() {
+	api.Scheme.AddKnownTypes("v1beta2",
+		&Build{},
+		&BuildList{},
+		&BuildConfig{},
+		&BuildConfigList{},
+		&BuildLog{},
+	)
+}
+
+func (*Build) IsAnAPIObject()           {}
+func (*BuildList) IsAnAPIObject()       {}
+func (*BuildConfig) IsAnAPIObject()     {}
+func (*BuildConfigList) IsAnAPIObject() {}
+func (*BuildLog) IsAnAPIObject()        {}
@@ -1,8 +1,10 @@
 package validation
 
 import (
+	"fmt"
 	"net/url"
 
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	errs "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/validation"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
@@ -29,6 +31,17 @@ func ValidateBuild(build *buildapi.Build) errs.ValidationErrorList {
 	return allErrs
 }
 
+// ValidateBuildUpdate tests that build is valid and does not change the immutable fields of oldBuild.  Only
+// the status of a build changes once it has been created.
+func ValidateBuildUpdate(build, oldBuild *buildapi.Build) errs.ValidationErrorList {
+	allErrs := ValidateBuild(build)
+	allErrs = append(allErrs, validation.ValidateObjectMetaUpdate(&oldBuild.ObjectMeta, &build.ObjectMeta)...)
+	if !kapi.Semantic.DeepEqual(build.Parameters, oldBuild.Parameters) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("parameters", "", "parameters cannot be changed once a build is created"))
+	}
+	return allErrs
+}
+
 // ValidateBuildConfig tests required fields for a Build.
 func ValidateBuildConfig(config *buildapi.BuildConfig) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
@@ -45,9 +58,35 @@ func ValidateBuildConfig(config *buildapi.BuildConfig) errs.ValidationErrorList
 	allErrs = append(allErrs, validation.ValidateLabels(config.Labels, "labels")...)
 	for i := range config.Triggers {
 		allErrs = append(allErrs, validateTrigger(&config.Triggers[i]).PrefixIndex(i).Prefix("triggers")...)
+		if triggersOnOwnOutput(config, &config.Triggers[i]) {
+			err := errs.NewFieldInvalid("imageChange.from.name", config.Name, "a BuildConfig may not be triggered by its own output")
+			allErrs = append(allErrs, errs.ValidationErrorList{err}.PrefixIndex(i).Prefix("triggers")...)
+		}
 	}
 	allErrs = append(allErrs, validateBuildParameters(&config.Parameters).Prefix("parameters")...)
 	allErrs = append(allErrs, validateBuildConfigOutput(&config.Parameters.Output).Prefix("parameters.output")...)
+	if config.AllowedOverrides != nil {
+		allErrs = append(allErrs, validateOverridePolicy(config.AllowedOverrides).Prefix("allowedOverrides")...)
+	}
+	return allErrs
+}
+
+func validateOverridePolicy(policy *buildapi.BuildOverridePolicy) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	for i, name := range policy.Env {
+		if len(name) == 0 {
+			allErrs = append(allErrs, errs.NewFieldRequired(fmt.Sprintf("env[%d]", i), name))
+		} else if !util.IsCIdentifier(name) {
+			allErrs = append(allErrs, errs.NewFieldInvalid(fmt.Sprintf("env[%d]", i), name, "must be a C identifier"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateBuildConfigUpdate tests that config is valid and does not change the immutable fields of oldConfig.
+func ValidateBuildConfigUpdate(config, oldConfig *buildapi.BuildConfig) errs.ValidationErrorList {
+	allErrs := ValidateBuildConfig(config)
+	allErrs = append(allErrs, validation.ValidateObjectMetaUpdate(&oldConfig.ObjectMeta, &config.ObjectMeta)...)
 	return allErrs
 }
 
@@ -66,6 +105,9 @@ func validateBuildParameters(params *buildapi.BuildParameters) errs.ValidationEr
 
 	allErrs = append(allErrs, validateOutput(&params.Output).Prefix("output")...)
 	allErrs = append(allErrs, validateStrategy(&params.Strategy).Prefix("strategy")...)
+	if params.CompletionDeadlineSeconds != nil && *params.CompletionDeadlineSeconds <= 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("completionDeadlineSeconds", *params.CompletionDeadlineSeconds, "must be greater than zero"))
+	}
 
 	return allErrs
 }
@@ -80,6 +122,19 @@ func validateSource(input *buildapi.BuildSource) errs.ValidationErrorList {
 	} else {
 		allErrs = append(allErrs, validateGitSource(input.Git).Prefix("git")...)
 	}
+	if input.SourceSecret != nil {
+		allErrs = append(allErrs, validateSourceSecret(input.SourceSecret).Prefix("sourceSecret")...)
+	}
+	return allErrs
+}
+
+func validateSourceSecret(secret *buildapi.SourceSecret) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if len(secret.Name) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("name", secret.Name))
+	} else if !util.IsDNSSubdomain(secret.Name) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("name", secret.Name, "name must be a valid subdomain"))
+	}
 	return allErrs
 }
 
@@ -130,6 +185,9 @@ func validateOutput(output *buildapi.BuildOutput) errs.ValidationErrorList {
 			allErrs = append(allErrs, errs.NewFieldInvalid("dockerImageReference", output.DockerImageReference, err.Error()))
 		}
 	}
+	if output.PushSecret != nil {
+		allErrs = append(allErrs, validateSourceSecret(output.PushSecret).Prefix("pushSecret")...)
+	}
 	return allErrs
 }
 
@@ -242,9 +300,24 @@ func validateImageChange(imageChange *buildapi.ImageChangeTrigger) errs.Validati
 	} else if len(imageChange.From.Name) == 0 {
 		allErrs = append(allErrs, errs.ValidationErrorList{errs.NewFieldRequired("name", "")}.Prefix("from")...)
 	}
+	switch kind := imageChange.From.Kind; kind {
+	case "", "ImageRepository", "BuildConfig":
+	default:
+		allErrs = append(allErrs, errs.NewFieldInvalid("from.kind", kind, "the source of an image change trigger must be 'ImageRepository' or 'BuildConfig'"))
+	}
 	return allErrs
 }
 
+// triggersOnOwnOutput returns true if trigger is an image change trigger on the output of config itself, which
+// would rebuild config after each of its builds.
+func triggersOnOwnOutput(config *buildapi.BuildConfig, trigger *buildapi.BuildTriggerPolicy) bool {
+	if trigger.Type != buildapi.ImageChangeBuildTriggerType || trigger.ImageChange == nil {
+		return false
+	}
+	from := trigger.ImageChange.From
+	return from.Kind == "BuildConfig" && from.Name == config.Name && (len(from.Namespace) == 0 || from.Namespace == config.Namespace)
+}
+
 func validateWebHook(webHook *buildapi.WebHookTrigger) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
 	if len(webHook.Secret) == 0 {
@@ -113,6 +113,75 @@ func TestBuildConfigValidationFailure(t *testing.T) {
 	}
 }
 
+func TestBuildConfigValidationOverridePolicyFailure(t *testing.T) {
+	buildConfig := &buildapi.BuildConfig{
+		ObjectMeta: kapi.ObjectMeta{Name: "config-id", Namespace: "namespace"},
+		Parameters: buildapi.BuildParameters{
+			Source: buildapi.BuildSource{
+				Type: buildapi.BuildSourceGit,
+				Git: &buildapi.GitBuildSource{
+					URI: "http://github.com/my/repository",
+				},
+			},
+			Strategy: buildapi.BuildStrategy{
+				Type:           buildapi.DockerBuildStrategyType,
+				DockerStrategy: &buildapi.DockerBuildStrategy{},
+			},
+			Output: buildapi.BuildOutput{
+				DockerImageReference: "repository/data",
+			},
+		},
+		AllowedOverrides: &buildapi.BuildOverridePolicy{Env: []string{"MODE", "", "NOT-AN-ID"}},
+	}
+	result := ValidateBuildConfig(buildConfig)
+	if len(result) != 2 {
+		t.Fatalf("Unexpected validation result %v", result)
+	}
+	if err := result[0].(*errs.ValidationError); err.Type != errs.ValidationErrorTypeRequired || err.Field != "allowedOverrides.env[1]" {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if err := result[1].(*errs.ValidationError); err.Type != errs.ValidationErrorTypeInvalid || err.Field != "allowedOverrides.env[2]" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestBuildConfigValidationOwnOutputTrigger(t *testing.T) {
+	buildConfig := &buildapi.BuildConfig{
+		ObjectMeta: kapi.ObjectMeta{Name: "config-id", Namespace: "namespace"},
+		Triggers: []buildapi.BuildTriggerPolicy{
+			{
+				Type: buildapi.ImageChangeBuildTriggerType,
+				ImageChange: &buildapi.ImageChangeTrigger{
+					Image: "base",
+					From:  kapi.ObjectReference{Kind: "BuildConfig", Name: "config-id"},
+				},
+			},
+		},
+		Parameters: buildapi.BuildParameters{
+			Source: buildapi.BuildSource{
+				Type: buildapi.BuildSourceGit,
+				Git: &buildapi.GitBuildSource{
+					URI: "http://github.com/my/repository",
+				},
+			},
+			Strategy: buildapi.BuildStrategy{
+				Type:           buildapi.DockerBuildStrategyType,
+				DockerStrategy: &buildapi.DockerBuildStrategy{},
+			},
+			Output: buildapi.BuildOutput{
+				DockerImageReference: "repository/data",
+			},
+		},
+	}
+	result := ValidateBuildConfig(buildConfig)
+	if len(result) != 1 {
+		t.Fatalf("Unexpected validation result %v", result)
+	}
+	if err := result[0].(*errs.ValidationError); err.Type != errs.ValidationErrorTypeInvalid || err.Field != "triggers[0].imageChange.from.name" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
 func TestBuildConfigValidationOutputFailure(t *testing.T) {
 	buildConfig := &buildapi.BuildConfig{
 		ObjectMeta: kapi.ObjectMeta{Name: ""},
@@ -140,6 +209,11 @@ func TestBuildConfigValidationOutputFailure(t *testing.T) {
 	if result := ValidateBuildConfig(buildConfig); len(result) != 3 {
 		t.Errorf("Unexpected validation result %v", result)
 	}
+
+	buildConfig.Parameters.Output.PushSecret = &buildapi.SourceSecret{Name: "../registry"}
+	if result := ValidateBuildConfig(buildConfig); len(result) != 4 {
+		t.Errorf("Expected the push secret to be validated, got %v", result)
+	}
 }
 
 func TestValidateSource(t *testing.T) {
@@ -156,6 +230,16 @@ func TestValidateSource(t *testing.T) {
 				URI: "::",
 			},
 		},
+		string(errs.ValidationErrorTypeRequired) + "sourceSecret.name": {
+			Type:         buildapi.BuildSourceGit,
+			Git:          &buildapi.GitBuildSource{URI: "ssh://git@github.com/user/private.git"},
+			SourceSecret: &buildapi.SourceSecret{},
+		},
+		string(errs.ValidationErrorTypeInvalid) + "sourceSecret.name": {
+			Type:         buildapi.BuildSourceGit,
+			Git:          &buildapi.GitBuildSource{URI: "ssh://git@github.com/user/private.git"},
+			SourceSecret: &buildapi.SourceSecret{Name: "../other"},
+		},
 	}
 	for desc, config := range errorCases {
 		errors := validateSource(config)
@@ -171,10 +255,30 @@ func TestValidateSource(t *testing.T) {
 }
 
 func TestValidateBuildParameters(t *testing.T) {
+	noDeadline := int64(0)
 	errorCases := []struct {
 		err string
 		*buildapi.BuildParameters
 	}{
+		{
+			string(errs.ValidationErrorTypeInvalid) + "completionDeadlineSeconds",
+			&buildapi.BuildParameters{
+				Source: buildapi.BuildSource{
+					Type: buildapi.BuildSourceGit,
+					Git: &buildapi.GitBuildSource{
+						URI: "http://github.com/my/repository",
+					},
+				},
+				Strategy: buildapi.BuildStrategy{
+					Type:           buildapi.DockerBuildStrategyType,
+					DockerStrategy: &buildapi.DockerBuildStrategy{},
+				},
+				Output: buildapi.BuildOutput{
+					DockerImageReference: "repository/data",
+				},
+				CompletionDeadlineSeconds: &noDeadline,
+			},
+		},
 		{
 			string(errs.ValidationErrorTypeInvalid) + "output.dockerImageReference",
 			&buildapi.BuildParameters{
@@ -384,6 +488,25 @@ func TestValidateTrigger(t *testing.T) {
 				},
 			},
 		},
+		"image change trigger from an invalid kind": {
+			trigger: buildapi.BuildTriggerPolicy{
+				Type: buildapi.ImageChangeBuildTriggerType,
+				ImageChange: &buildapi.ImageChangeTrigger{
+					Image: "base",
+					From:  kapi.ObjectReference{Kind: "Pod", Name: "base"},
+				},
+			},
+			expected: []*errs.ValidationError{errs.NewFieldInvalid("imageChange.from.kind", "", "")},
+		},
+		"valid image change trigger from a buildConfig": {
+			trigger: buildapi.BuildTriggerPolicy{
+				Type: buildapi.ImageChangeBuildTriggerType,
+				ImageChange: &buildapi.ImageChangeTrigger{
+					Image: "base",
+					From:  kapi.ObjectReference{Kind: "BuildConfig", Name: "base"},
+				},
+			},
+		},
 	}
 	for desc, test := range tests {
 		errors := validateTrigger(&test.trigger)
@@ -403,3 +526,94 @@ func TestValidateTrigger(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateBuildUpdate(t *testing.T) {
+	newBuild := func() *buildapi.Build {
+		return &buildapi.Build{
+			ObjectMeta: kapi.ObjectMeta{Name: "buildid", Namespace: "default", ResourceVersion: "1"},
+			Parameters: buildapi.BuildParameters{
+				Source: buildapi.BuildSource{
+					Type: buildapi.BuildSourceGit,
+					Git:  &buildapi.GitBuildSource{URI: "http://github.com/my/repository"},
+				},
+				Strategy: buildapi.BuildStrategy{
+					Type:           buildapi.DockerBuildStrategyType,
+					DockerStrategy: &buildapi.DockerBuildStrategy{},
+				},
+				Output: buildapi.BuildOutput{DockerImageReference: "repository/data"},
+			},
+			Status: buildapi.BuildStatusNew,
+		}
+	}
+
+	tests := map[string]struct {
+		update func(*buildapi.Build)
+		field  string
+	}{
+		"status changed": {
+			update: func(b *buildapi.Build) { b.Status = buildapi.BuildStatusRunning },
+		},
+		"name changed": {
+			update: func(b *buildapi.Build) { b.Name = "other" },
+			field:  "name",
+		},
+		"namespace changed": {
+			update: func(b *buildapi.Build) { b.Namespace = "other" },
+			field:  "namespace",
+		},
+		"parameters changed": {
+			update: func(b *buildapi.Build) { b.Parameters.Output.DockerImageReference = "repository/other" },
+			field:  "parameters",
+		},
+	}
+	for desc, test := range tests {
+		build := newBuild()
+		test.update(build)
+		errors := ValidateBuildUpdate(build, newBuild())
+		if len(test.field) == 0 {
+			if len(errors) != 0 {
+				t.Errorf("%s: Got unexpected validation errors: %#v", desc, errors)
+			}
+			continue
+		}
+		if len(errors) != 1 {
+			t.Errorf("%s: Expected one validation error, got %#v", desc, errors)
+			continue
+		}
+		if field := errors[0].(*errs.ValidationError).Field; field != test.field {
+			t.Errorf("%s: Unexpected error field: %s", desc, field)
+		}
+	}
+}
+
+func TestValidateBuildConfigUpdate(t *testing.T) {
+	newConfig := func() *buildapi.BuildConfig {
+		return &buildapi.BuildConfig{
+			ObjectMeta: kapi.ObjectMeta{Name: "config-id", Namespace: "namespace"},
+			Parameters: buildapi.BuildParameters{
+				Source: buildapi.BuildSource{
+					Type: buildapi.BuildSourceGit,
+					Git:  &buildapi.GitBuildSource{URI: "http://github.com/my/repository"},
+				},
+				Strategy: buildapi.BuildStrategy{
+					Type:           buildapi.DockerBuildStrategyType,
+					DockerStrategy: &buildapi.DockerBuildStrategy{},
+				},
+				Output: buildapi.BuildOutput{DockerImageReference: "repository/data"},
+			},
+		}
+	}
+
+	config := newConfig()
+	config.Parameters.Output.DockerImageReference = "repository/other"
+	if errors := ValidateBuildConfigUpdate(config, newConfig()); len(errors) != 0 {
+		t.Errorf("Unexpected validation errors: %#v", errors)
+	}
+
+	config = newConfig()
+	config.Namespace = "other"
+	errors := ValidateBuildConfigUpdate(config, newConfig())
+	if len(errors) != 1 || errors[0].(*errs.ValidationError).Field != "namespace" {
+		t.Errorf("Expected an error for the namespace, got %#v", errors)
+	}
+}
@@ -37,6 +37,12 @@ func run(builderFactory factoryFunc) {
 		log.Fatalf("Unable to parse build: %v", err)
 	}
 
+	if secret := os.Getenv("SOURCE_SECRET_PATH"); len(secret) > 0 {
+		if err := bld.SetupGitCredentials(secret); err != nil {
+			log.Fatalf("Unable to use the source secret: %v", err)
+		}
+	}
+
 	var (
 		authcfg     docker.AuthConfiguration
 		authPresent bool
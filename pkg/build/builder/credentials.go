@@ -0,0 +1,68 @@
+package builder
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/openshift/origin/pkg/build/api"
+)
+
+// SetupGitCredentials configures git, through the environment of the builder, to clone the source with the
+// source secret mounted at dir: a private SSH key and the host keys of the servers it may be used with, or a
+// username and password for basic authentication.  The git commands of the Docker and STI builders inherit
+// the environment.
+func SetupGitCredentials(dir string) error {
+	keyFile := filepath.Join(dir, api.SourceSecretSSHKeyFile)
+	if _, err := os.Stat(keyFile); err == nil {
+		knownHostsFile := filepath.Join(dir, api.SourceSecretKnownHostsFile)
+		if _, err := os.Stat(knownHostsFile); err != nil {
+			return fmt.Errorf("the source secret at %s holds an %s file but no %s file to verify the host keys of the source repository with", dir, api.SourceSecretSSHKeyFile, api.SourceSecretKnownHostsFile)
+		}
+		return setupSSHKey(keyFile, knownHostsFile)
+	}
+	usernameFile := filepath.Join(dir, api.SourceSecretUsernameFile)
+	if _, err := os.Stat(usernameFile); err == nil {
+		return setupBasicAuth(usernameFile, filepath.Join(dir, api.SourceSecretPasswordFile))
+	}
+	return fmt.Errorf("the source secret at %s holds neither an %s file nor a %s file", dir, api.SourceSecretSSHKeyFile, api.SourceSecretUsernameFile)
+}
+
+// setupSSHKey copies the key, since ssh refuses keys other users may read, and points GIT_SSH to a script that
+// runs ssh with it.  ssh refuses to connect to hosts whose keys are not in knownHostsFile.
+func setupSSHKey(keyFile, knownHostsFile string) error {
+	key, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return err
+	}
+	dir, err := ioutil.TempDir("", "source-secret")
+	if err != nil {
+		return err
+	}
+	privateKey := filepath.Join(dir, api.SourceSecretSSHKeyFile)
+	if err := ioutil.WriteFile(privateKey, key, 0600); err != nil {
+		return err
+	}
+	script := fmt.Sprintf("#!/bin/sh\nexec ssh -i '%s' -o StrictHostKeyChecking=yes -o UserKnownHostsFile='%s' \"$@\"\n", privateKey, knownHostsFile)
+	return writeGitScript(filepath.Join(dir, "ssh"), script, "GIT_SSH")
+}
+
+// setupBasicAuth points GIT_ASKPASS to a script that answers the username and password prompts of git from
+// the files of the secret.
+func setupBasicAuth(usernameFile, passwordFile string) error {
+	dir, err := ioutil.TempDir("", "source-secret")
+	if err != nil {
+		return err
+	}
+	script := fmt.Sprintf("#!/bin/sh\ncase \"$1\" in\nUsername*) cat '%s' ;;\n*) cat '%s' ;;\nesac\n", usernameFile, passwordFile)
+	return writeGitScript(filepath.Join(dir, "askpass"), script, "GIT_ASKPASS")
+}
+
+// writeGitScript writes an executable script and names it in the environment variable git reads it from.
+func writeGitScript(file, script, env string) error {
+	if err := ioutil.WriteFile(file, []byte(script), 0700); err != nil {
+		return err
+	}
+	return os.Setenv(env, file)
+}
@@ -0,0 +1,87 @@
+package builder
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift/origin/pkg/build/api"
+)
+
+func TestSetupGitCredentialsSSHKey(t *testing.T) {
+	defer os.Setenv("GIT_SSH", os.Getenv("GIT_SSH"))
+	dir, err := ioutil.TempDir("", "secret")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, api.SourceSecretSSHKeyFile), []byte("key"), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := SetupGitCredentials(dir); err == nil {
+		t.Fatalf("Expected an error for a key without known hosts")
+	}
+	knownHosts := filepath.Join(dir, api.SourceSecretKnownHostsFile)
+	if err := ioutil.WriteFile(knownHosts, []byte("github.com ssh-rsa AAAA"), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := SetupGitCredentials(dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	script, err := ioutil.ReadFile(os.Getenv("GIT_SSH"))
+	if err != nil {
+		t.Fatalf("Expected GIT_SSH to name a script: %v", err)
+	}
+	keyFile := filepath.Join(filepath.Dir(os.Getenv("GIT_SSH")), api.SourceSecretSSHKeyFile)
+	defer os.RemoveAll(filepath.Dir(keyFile))
+	if !strings.Contains(string(script), "ssh -i '"+keyFile+"'") {
+		t.Errorf("Expected the script to use the copied key, got %q", script)
+	}
+	if !strings.Contains(string(script), "StrictHostKeyChecking=yes -o UserKnownHostsFile='"+knownHosts+"'") {
+		t.Errorf("Expected the script to check host keys against the known hosts of the secret, got %q", script)
+	}
+	if info, err := os.Stat(keyFile); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the key to be copied with private permissions, got %v %v", info, err)
+	}
+}
+
+func TestSetupGitCredentialsBasicAuth(t *testing.T) {
+	defer os.Setenv("GIT_ASKPASS", os.Getenv("GIT_ASKPASS"))
+	dir, err := ioutil.TempDir("", "secret")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, api.SourceSecretUsernameFile), []byte("alice"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, api.SourceSecretPasswordFile), []byte("secret"), 0644)
+
+	if err := SetupGitCredentials(dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	askpass := os.Getenv("GIT_ASKPASS")
+	defer os.RemoveAll(filepath.Dir(askpass))
+	for prompt, expected := range map[string]string{
+		"Username for 'https://github.com': ":       "alice",
+		"Password for 'https://alice@github.com': ": "secret",
+	} {
+		out, err := exec.Command(askpass, prompt).Output()
+		if err != nil || string(out) != expected {
+			t.Errorf("Expected %q for %q, got %q %v", expected, prompt, out, err)
+		}
+	}
+}
+
+func TestSetupGitCredentialsEmpty(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secret")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := SetupGitCredentials(dir); err == nil {
+		t.Errorf("Expected an error for a secret without credentials")
+	}
+}
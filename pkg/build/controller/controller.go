@@ -2,27 +2,30 @@ package controller
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/golang/glog"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	errors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
 	buildapi "github.com/openshift/origin/pkg/build/api"
 	buildclient "github.com/openshift/origin/pkg/build/client"
+	"github.com/openshift/origin/pkg/client/record"
 	imageapi "github.com/openshift/origin/pkg/image/api"
 )
 
 // BuildController watches build resources and manages their state
 type BuildController struct {
 	BuildStore    cache.Store
-	NextBuild     func() *buildapi.Build
-	NextPod       func() *kapi.Pod
 	BuildUpdater  buildclient.BuildUpdater
 	PodManager    podManager
 	BuildStrategy BuildStrategy
+	// Recorder records the events of the lifecycle of builds.
+	Recorder record.Recorder
+	// Metrics may be set to record the durations of finished builds.
+	Metrics *BuildMetrics
 
 	ImageRepositoryClient imageRepositoryClient
 }
@@ -41,18 +44,13 @@ type imageRepositoryClient interface {
 	GetImageRepository(namespace, name string) (*imageapi.ImageRepository, error)
 }
 
-// Run begins watching and syncing build jobs onto the cluster.
-func (bc *BuildController) Run() {
-	go util.Forever(func() { bc.HandleBuild(bc.NextBuild()) }, 0)
-	go util.Forever(func() { bc.HandlePod(bc.NextPod()) }, 0)
-}
-
-func (bc *BuildController) HandleBuild(build *buildapi.Build) {
+// HandleBuild starts the pod of a new build.  An error is returned if the build should be retried.
+func (bc *BuildController) HandleBuild(build *buildapi.Build) error {
 	glog.V(4).Infof("Handling build %s", build.Name)
 
 	// We only deal with new builds here
 	if build.Status != buildapi.BuildStatusNew {
-		return
+		return nil
 	}
 
 	if err := bc.nextBuildStatus(build); err != nil {
@@ -62,11 +60,14 @@ func (bc *BuildController) HandleBuild(build *buildapi.Build) {
 		glog.V(4).Infof("Build failed with error %s/%s: %#v", build.Namespace, build.Name, err)
 		build.Status = buildapi.BuildStatusError
 		build.Message = err.Error()
+		bc.Recorder.Eventf(build, "failed", "Failed to start build: %v", err)
 	}
 
 	if err := bc.BuildUpdater.Update(build.Namespace, build); err != nil {
-		glog.V(2).Infof("Failed to record changes to build %s/%s: %#v", build.Namespace, build.Name, err)
+		// the pod of the build may have been created, which is detected when the build is retried
+		return fmt.Errorf("failed to record changes to build %s/%s: %v", build.Namespace, build.Name, err)
 	}
+	return nil
 }
 
 // nextBuildStatus updates build with any appropriate changes, or returns an error if
@@ -122,14 +123,17 @@ func (bc *BuildController) nextBuildStatus(build *buildapi.Build) error {
 			glog.V(4).Infof("Build pod already existed: %#v", podSpec)
 			return nil
 		}
-		return fmt.Errorf("failed to create pod for build %s/%s: s", build.Namespace, build.Name, err)
+		return fmt.Errorf("failed to create pod for build %s/%s: %v", build.Namespace, build.Name, err)
 	}
 
 	glog.V(4).Infof("Created pod for build: %#v", podSpec)
+	bc.Recorder.Eventf(build, "started", "Started build pod %s", podSpec.Name)
 	return nil
 }
 
-func (bc *BuildController) HandlePod(pod *kapi.Pod) {
+// HandlePod updates the status of a build from the phase of its pod.  An error is returned if the pod
+// should be retried.
+func (bc *BuildController) HandlePod(pod *kapi.Pod) error {
 	// Find the build for this pod
 	var build *buildapi.Build
 	for _, obj := range bc.BuildStore.List() {
@@ -141,17 +145,30 @@ func (bc *BuildController) HandlePod(pod *kapi.Pod) {
 	}
 
 	if build == nil {
-		return
+		return nil
 	}
+	// the build is shared with the store and must not be modified
+	copied, err := kapi.Scheme.Copy(build)
+	if err != nil {
+		return fmt.Errorf("unable to copy build %s: %v", build.Name, err)
+	}
+	build = copied.(*buildapi.Build)
 
 	// A cancelling event was triggered for the build, delete its pod and update build status.
 	if build.Cancelled {
 		glog.V(2).Infof("Cancelling build %s.", build.Name)
 
 		if err := bc.CancelBuild(build, pod); err != nil {
-			glog.Errorf("Failed to cancel build %s: %#v", build.Name, err)
+			return fmt.Errorf("failed to cancel build %s: %v", build.Name, err)
 		}
-		return
+		return nil
+	}
+
+	if deadlineExceeded(build, pod) {
+		if err := bc.failBuild(build, pod); err != nil {
+			return fmt.Errorf("failed to fail build %s after its deadline: %v", build.Name, err)
+		}
+		return nil
 	}
 
 	nextStatus := build.Status
@@ -175,9 +192,26 @@ func (bc *BuildController) HandlePod(pod *kapi.Pod) {
 		glog.V(4).Infof("Updating build %s status %s -> %s", build.Name, build.Status, nextStatus)
 		build.Status = nextStatus
 		if err := bc.BuildUpdater.Update(build.Namespace, build); err != nil {
-			glog.Errorf("Failed to update build %s: %#v", build.Name, err)
+			return fmt.Errorf("failed to update build %s: %v", build.Name, err)
+		}
+		switch nextStatus {
+		case buildapi.BuildStatusComplete:
+			bc.Recorder.Eventf(build, "completed", "Build pod %s completed", pod.Name)
+			bc.observeBuild(build, pod)
+		case buildapi.BuildStatusFailed:
+			bc.Recorder.Eventf(build, "failed", "Build pod %s failed", pod.Name)
+			bc.observeBuild(build, pod)
 		}
 	}
+	return nil
+}
+
+// observeBuild records the duration of a finished build from the creation of its pod, if metrics are recorded.
+func (bc *BuildController) observeBuild(build *buildapi.Build, pod *kapi.Pod) {
+	if bc.Metrics == nil || pod.CreationTimestamp.IsZero() {
+		return
+	}
+	bc.Metrics.ObserveBuild(build, time.Since(pod.CreationTimestamp.Time))
 }
 
 // CancelBuild updates a build status to Cancelled, after its associated pod is associated.
@@ -201,6 +235,40 @@ func (bc *BuildController) CancelBuild(build *buildapi.Build, pod *kapi.Pod) err
 	return nil
 }
 
+// failBuild deletes the pod of a build that exceeded its completion deadline and marks the build Failed.
+func (bc *BuildController) failBuild(build *buildapi.Build, pod *kapi.Pod) error {
+	err := bc.PodManager.DeletePod(build.Namespace, pod)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	build.Status = buildapi.BuildStatusFailed
+	build.Message = fmt.Sprintf("The build exceeded its completion deadline of %d seconds", *build.Parameters.CompletionDeadlineSeconds)
+	if err := bc.BuildUpdater.Update(build.Namespace, build); err != nil {
+		return err
+	}
+	bc.Recorder.Eventf(build, "failed", "Build pod %s was killed after the completion deadline of %d seconds", pod.Name, *build.Parameters.CompletionDeadlineSeconds)
+	bc.observeBuild(build, pod)
+	glog.V(2).Infof("Build %s exceeded its completion deadline and was failed.", build.Name)
+	return nil
+}
+
+// deadlineExceeded returns true if the pod of a pending or running build has not finished within the
+// completion deadline of the build, counted from the creation of the pod.
+func deadlineExceeded(build *buildapi.Build, pod *kapi.Pod) bool {
+	if build.Parameters.CompletionDeadlineSeconds == nil || pod.CreationTimestamp.IsZero() {
+		return false
+	}
+	if build.Status != buildapi.BuildStatusPending && build.Status != buildapi.BuildStatusRunning {
+		return false
+	}
+	if pod.Status.Phase == kapi.PodSucceeded || pod.Status.Phase == kapi.PodFailed {
+		return false
+	}
+	deadline := time.Duration(*build.Parameters.CompletionDeadlineSeconds) * time.Second
+	return time.Since(pod.CreationTimestamp.Time) > deadline
+}
+
 // isBuildCancellable checks for build status and returns true if the condition is checked.
 func isBuildCancellable(build *buildapi.Build) bool {
 	return build.Status == buildapi.BuildStatusNew || build.Status == buildapi.BuildStatusPending || build.Status == buildapi.BuildStatusRunning
@@ -2,20 +2,27 @@ package controller
 
 import (
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
 	buildapi "github.com/openshift/origin/pkg/build/api"
 	buildclient "github.com/openshift/origin/pkg/build/client"
 	buildtest "github.com/openshift/origin/pkg/build/controller/test"
+	"github.com/openshift/origin/pkg/client/record"
 	imageapi "github.com/openshift/origin/pkg/image/api"
 )
 
-type okBuildUpdater struct{}
+type okBuildUpdater struct {
+	build *buildapi.Build
+}
 
 func (okc *okBuildUpdater) Update(namespace string, build *buildapi.Build) error {
+	okc.build = build
 	return nil
 }
 
@@ -124,10 +131,9 @@ func mockBuildAndController(status buildapi.BuildStatus, output buildapi.BuildOu
 		BuildStore:            buildtest.NewFakeBuildStore(build),
 		BuildUpdater:          &okBuildUpdater{},
 		PodManager:            &okPodManager{},
-		NextBuild:             func() *buildapi.Build { return nil },
-		NextPod:               func() *kapi.Pod { return nil },
 		BuildStrategy:         &okStrategy{},
 		ImageRepositoryClient: &okImageRepositoryClient{},
+		Recorder:              &record.FakeRecorder{},
 	}
 	return
 }
@@ -158,6 +164,7 @@ func TestHandleBuild(t *testing.T) {
 		imageClient   imageRepositoryClient
 		podManager    podManager
 		outputSpec    string
+		expectErr     bool
 	}
 
 	tests := []handleBuildTest{
@@ -234,6 +241,7 @@ func TestHandleBuild(t *testing.T) {
 			buildOutput: buildapi.BuildOutput{
 				DockerImageReference: "repository/dataBuild",
 			},
+			expectErr: true,
 		},
 		{ // 10
 			inStatus:  buildapi.BuildStatusNew,
@@ -293,8 +301,10 @@ func TestHandleBuild(t *testing.T) {
 			ctrl.ImageRepositoryClient = tc.imageClient
 		}
 
-		ctrl.HandleBuild(build)
-
+		err := ctrl.HandleBuild(build)
+		if tc.expectErr != (err != nil) {
+			t.Errorf("(%d) Expected an error %t, got %v", i, tc.expectErr, err)
+		}
 		if build.Status != tc.outStatus {
 			t.Errorf("(%d) Expected %s, got %s!", i, tc.outStatus, build.Status)
 		}
@@ -310,6 +320,24 @@ func TestHandleBuild(t *testing.T) {
 	}
 }
 
+func TestHandleBuildRecordsEvents(t *testing.T) {
+	build, ctrl := mockBuildAndController(buildapi.BuildStatusNew, buildapi.BuildOutput{})
+	if err := ctrl.HandleBuild(build); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	build, failing := mockBuildAndController(buildapi.BuildStatusNew, buildapi.BuildOutput{})
+	failing.Recorder = ctrl.Recorder
+	failing.BuildStrategy = &errStrategy{}
+	if err := failing.HandleBuild(build); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := ctrl.Recorder.(*record.FakeRecorder).Events
+	if len(events) != 2 || !strings.HasPrefix(events[0], "started ") || !strings.HasPrefix(events[1], "failed ") {
+		t.Errorf("expected a started and a failed event, got %v", events)
+	}
+}
+
 func TestHandlePod(t *testing.T) {
 	type handlePodTest struct {
 		matchID      bool
@@ -318,6 +346,7 @@ func TestHandlePod(t *testing.T) {
 		podStatus    kapi.PodPhase
 		exitCode     int
 		buildUpdater buildclient.BuildUpdater
+		expectErr    bool
 	}
 
 	tests := []handlePodTest{
@@ -363,6 +392,7 @@ func TestHandlePod(t *testing.T) {
 			podStatus:    kapi.PodSucceeded,
 			exitCode:     0,
 			buildUpdater: &errBuildUpdater{},
+			expectErr:    true,
 		},
 	}
 
@@ -376,10 +406,101 @@ func TestHandlePod(t *testing.T) {
 			ctrl.BuildUpdater = tc.buildUpdater
 		}
 
-		ctrl.HandlePod(pod)
+		err := ctrl.HandlePod(pod)
+		if tc.expectErr != (err != nil) {
+			t.Errorf("(%d) Expected an error %t, got %v", i, tc.expectErr, err)
+		}
 
-		if build.Status != tc.outStatus {
-			t.Errorf("(%d) Expected %s, got %s!", i, tc.outStatus, build.Status)
+		if build.Status != tc.inStatus {
+			t.Errorf("(%d) Expected the build in the store to be unchanged, got %s", i, build.Status)
+		}
+		status := tc.inStatus
+		if updater, ok := ctrl.BuildUpdater.(*okBuildUpdater); ok && updater.build != nil {
+			status = updater.build.Status
+		}
+		if tc.expectErr {
+			continue
+		}
+		if status != tc.outStatus {
+			t.Errorf("(%d) Expected %s, got %s!", i, tc.outStatus, status)
+		}
+	}
+}
+
+func TestHandlePodDeadline(t *testing.T) {
+	deadline := int64(60)
+	tests := map[string]struct {
+		inStatus   buildapi.BuildStatus
+		podStatus  kapi.PodPhase
+		podAge     time.Duration
+		podManager podManager
+		outStatus  buildapi.BuildStatus
+		expectErr  bool
+	}{
+		"running within the deadline": {
+			inStatus:  buildapi.BuildStatusRunning,
+			podStatus: kapi.PodRunning,
+			podAge:    30 * time.Second,
+			outStatus: buildapi.BuildStatusRunning,
+		},
+		"running past the deadline": {
+			inStatus:  buildapi.BuildStatusRunning,
+			podStatus: kapi.PodRunning,
+			podAge:    2 * time.Minute,
+			outStatus: buildapi.BuildStatusFailed,
+		},
+		"pending past the deadline": {
+			inStatus:  buildapi.BuildStatusPending,
+			podStatus: kapi.PodPending,
+			podAge:    2 * time.Minute,
+			outStatus: buildapi.BuildStatusFailed,
+		},
+		"completed past the deadline": {
+			inStatus:  buildapi.BuildStatusRunning,
+			podStatus: kapi.PodSucceeded,
+			podAge:    2 * time.Minute,
+			outStatus: buildapi.BuildStatusComplete,
+		},
+		"pod already deleted": {
+			inStatus:   buildapi.BuildStatusRunning,
+			podStatus:  kapi.PodRunning,
+			podAge:     2 * time.Minute,
+			podManager: &errExistsPodManager{},
+			outStatus:  buildapi.BuildStatusFailed,
+		},
+		"pod deletion error": {
+			inStatus:   buildapi.BuildStatusRunning,
+			podStatus:  kapi.PodRunning,
+			podAge:     2 * time.Minute,
+			podManager: &errPodManager{},
+			outStatus:  buildapi.BuildStatusRunning,
+			expectErr:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		build, ctrl := mockBuildAndController(tc.inStatus, buildapi.BuildOutput{})
+		build.Parameters.CompletionDeadlineSeconds = &deadline
+		pod := mockPod(tc.podStatus, 0)
+		pod.CreationTimestamp = util.NewTime(time.Now().Add(-tc.podAge))
+		build.PodName = pod.Name
+		if tc.podManager != nil {
+			ctrl.PodManager = tc.podManager
+		}
+
+		err := ctrl.HandlePod(pod)
+		if tc.expectErr != (err != nil) {
+			t.Errorf("%s: expected an error %t, got %v", name, tc.expectErr, err)
+		}
+		status, message := tc.inStatus, ""
+		if updater := ctrl.BuildUpdater.(*okBuildUpdater); updater.build != nil {
+			status, message = updater.build.Status, updater.build.Message
+		}
+		if status != tc.outStatus {
+			t.Errorf("%s: expected %s, got %s", name, tc.outStatus, status)
+		}
+		if status == buildapi.BuildStatusFailed && !strings.Contains(message, "deadline") {
+			t.Errorf("%s: expected the message to name the deadline, got %q", name, message)
 		}
 	}
 }
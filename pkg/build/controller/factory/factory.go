@@ -2,6 +2,7 @@ package factory
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang/glog"
@@ -9,18 +10,28 @@ import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	buildapi "github.com/openshift/origin/pkg/build/api"
 	buildclient "github.com/openshift/origin/pkg/build/client"
 	controller "github.com/openshift/origin/pkg/build/controller"
 	strategy "github.com/openshift/origin/pkg/build/controller/strategy"
 	osclient "github.com/openshift/origin/pkg/client"
+	oscache "github.com/openshift/origin/pkg/client/cache"
+	"github.com/openshift/origin/pkg/client/record"
+	oscontroller "github.com/openshift/origin/pkg/controller"
 	imageapi "github.com/openshift/origin/pkg/image/api"
 )
 
+const (
+	// maxRetries is the number of times an object that failed to be handled is retried.
+	maxRetries = 5
+	// initialBackoff is the wait before the first retry of an object, which doubles with every retry up
+	// to maxBackoff.
+	initialBackoff = time.Second
+	maxBackoff     = time.Minute
+)
+
 type BuildControllerFactory struct {
 	OSClient            osclient.Interface
 	KubeClient          kclient.Interface
@@ -28,18 +39,36 @@ type BuildControllerFactory struct {
 	DockerBuildStrategy *strategy.DockerBuildStrategy
 	STIBuildStrategy    *strategy.STIBuildStrategy
 	CustomBuildStrategy *strategy.CustomBuildStrategy
+	// Informers may be set to share the watches and stores of objects with other controllers.
+	Informers *oscache.Informers
+	// Metrics may be set to record the durations of finished builds.
+	Metrics *controller.BuildMetrics
 	// Stop may be set to allow controllers created by this factory to be terminated.
 	Stop <-chan struct{}
 
 	buildStore cache.Store
 }
 
-func (factory *BuildControllerFactory) Create() *controller.BuildController {
-	factory.buildStore = cache.NewStore(cache.MetaNamespaceKeyFunc)
-	cache.NewReflector(&buildLW{client: factory.OSClient}, &buildapi.Build{}, factory.buildStore).RunUntil(factory.Stop)
+// Create creates a controller that starts the pods of new builds.
+func (factory *BuildControllerFactory) Create() *oscontroller.RetryController {
+	factory.Informers = informersOrNew(factory.Informers, factory.OSClient, factory.KubeClient)
+	factory.buildStore = factory.Informers.Builds().Store()
+	queue := factory.Informers.Builds().Queue(factory.Stop)
 
-	buildQueue := cache.NewFIFO(cache.MetaNamespaceKeyFunc)
-	cache.NewReflector(&buildLW{client: factory.OSClient}, &buildapi.Build{}, buildQueue).RunUntil(factory.Stop)
+	buildController := factory.buildController()
+	return newRetryController(queue, func(obj interface{}) error {
+		build, err := copyObject(obj)
+		if err != nil {
+			return err
+		}
+		return buildController.HandleBuild(build.(*buildapi.Build))
+	}, factory.Stop)
+}
+
+// CreatePodController creates a controller that updates the status of builds from their pods.
+func (factory *BuildControllerFactory) CreatePodController() *oscontroller.RetryController {
+	factory.Informers = informersOrNew(factory.Informers, factory.OSClient, factory.KubeClient)
+	factory.buildStore = factory.Informers.Builds().Store()
 
 	// Kubernetes does not currently synchronize Pod status in storage with a Pod's container
 	// states. Because of this, we can't receive events related to container (and thus Pod)
@@ -49,25 +78,25 @@ func (factory *BuildControllerFactory) Create() *controller.BuildController {
 	//
 	// TODO: Find a way to get watch events for Pod/container status updates. The polling
 	// strategy is horribly inefficient and should be addressed upstream somehow.
-	podQueue := cache.NewFIFO(cache.MetaNamespaceKeyFunc)
+	podQueue := oscache.NewWorkQueue(cache.MetaNamespaceKeyFunc)
 	cache.NewPoller(factory.pollPods, 10*time.Second, podQueue).RunUntil(factory.Stop)
 
+	buildController := factory.buildController()
+	return newRetryController(podQueue, func(obj interface{}) error {
+		return buildController.HandlePod(obj.(*kapi.Pod))
+	}, factory.Stop)
+}
+
+// buildController returns the controller that handles builds and their pods.
+func (factory *BuildControllerFactory) buildController() *controller.BuildController {
 	client := ControllerClient{factory.KubeClient, factory.OSClient}
 	return &controller.BuildController{
 		BuildStore:            factory.buildStore,
 		BuildUpdater:          factory.BuildUpdater,
 		ImageRepositoryClient: client,
 		PodManager:            client,
-		NextBuild: func() *buildapi.Build {
-			build := buildQueue.Pop().(*buildapi.Build)
-			panicIfStopped(factory.Stop, "build controller stopped")
-			return build
-		},
-		NextPod: func() *kapi.Pod {
-			pod := podQueue.Pop().(*kapi.Pod)
-			panicIfStopped(factory.Stop, "build controller stopped")
-			return pod
-		},
+		Recorder:              record.NewRecorder(),
+		Metrics:               factory.Metrics,
 		BuildStrategy: &typeBasedFactoryStrategy{
 			DockerBuildStrategy: factory.DockerBuildStrategy,
 			STIBuildStrategy:    factory.STIBuildStrategy,
@@ -82,30 +111,25 @@ type ImageChangeControllerFactory struct {
 	Client             osclient.Interface
 	BuildCreator       buildclient.BuildCreator
 	BuildConfigUpdater buildclient.BuildConfigUpdater
+	// Informers may be set to share the watches and stores of objects with other controllers.
+	Informers *oscache.Informers
 	// Stop may be set to allow controllers created by this factory to be terminated.
 	Stop <-chan struct{}
 }
 
-// Create creates a new ImageChangeController which is used to trigger builds when a new
-// image is available
-func (factory *ImageChangeControllerFactory) Create() *controller.ImageChangeController {
-	queue := cache.NewFIFO(cache.MetaNamespaceKeyFunc)
-	cache.NewReflector(&imageRepositoryLW{factory.Client}, &imageapi.ImageRepository{}, queue).RunUntil(factory.Stop)
+// Create creates a controller which is used to trigger builds when a new image is available
+func (factory *ImageChangeControllerFactory) Create() *oscontroller.RetryController {
+	informers := informersOrNew(factory.Informers, factory.Client, nil)
+	queue := informers.ImageRepositories().Queue(factory.Stop)
 
-	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
-	cache.NewReflector(&buildConfigLW{client: factory.Client}, &buildapi.BuildConfig{}, store).RunUntil(factory.Stop)
-
-	return &controller.ImageChangeController{
-		BuildConfigStore:   store,
+	imageChangeController := &controller.ImageChangeController{
+		BuildConfigStore:   informers.BuildConfigs().Store(),
 		BuildConfigUpdater: factory.BuildConfigUpdater,
 		BuildCreator:       factory.BuildCreator,
-		NextImageRepository: func() *imageapi.ImageRepository {
-			repo := queue.Pop().(*imageapi.ImageRepository)
-			panicIfStopped(factory.Stop, "build image change controller stopped")
-			return repo
-		},
-		Stop: factory.Stop,
 	}
+	return newRetryController(queue, func(obj interface{}) error {
+		return imageChangeController.HandleImageRepo(obj.(*imageapi.ImageRepository))
+	}, factory.Stop)
 }
 
 // pollPods lists pods for all builds in the buildStore which are pending or running and
@@ -167,58 +191,34 @@ func (f *typeBasedFactoryStrategy) CreateBuildPod(build *buildapi.Build) (*kapi.
 	}
 }
 
-// panicIfStopped panics with the provided object if the channel is closed
-func panicIfStopped(ch <-chan struct{}, message interface{}) {
-	select {
-	case <-ch:
-		panic(message)
-	default:
+// informersOrNew returns informers if they are set, or new informers of the objects listed and watched
+// with client and kubeClient.
+func informersOrNew(informers *oscache.Informers, client osclient.Interface, kubeClient kclient.Interface) *oscache.Informers {
+	if informers != nil {
+		return informers
 	}
+	return oscache.NewInformers(client, kubeClient)
 }
 
-// buildLW is a ListWatcher implementation for Builds.
-type buildLW struct {
-	client osclient.Interface
-}
-
-// List lists all Builds.
-func (lw *buildLW) List() (runtime.Object, error) {
-	return lw.client.Builds(kapi.NamespaceAll).List(labels.Everything(), labels.Everything())
-}
-
-// Watch watches all Builds.
-func (lw *buildLW) Watch(resourceVersion string) (watch.Interface, error) {
-	return lw.client.Builds(kapi.NamespaceAll).Watch(labels.Everything(), labels.Everything(), resourceVersion)
-}
-
-// buildConfigLW is a ListWatcher implementation for BuildConfigs.
-type buildConfigLW struct {
-	client osclient.Interface
-}
-
-// List lists all BuildConfigs.
-func (lw *buildConfigLW) List() (runtime.Object, error) {
-	return lw.client.BuildConfigs(kapi.NamespaceAll).List(labels.Everything(), labels.Everything())
-}
-
-// Watch watches all BuildConfigs.
-func (lw *buildConfigLW) Watch(resourceVersion string) (watch.Interface, error) {
-	return lw.client.BuildConfigs(kapi.NamespaceAll).Watch(labels.Everything(), labels.Everything(), resourceVersion)
-}
-
-// imageRepositoryLW is a ListWatcher for ImageRepositories.
-type imageRepositoryLW struct {
-	client osclient.Interface
-}
-
-// List lists all ImageRepositories.
-func (lw *imageRepositoryLW) List() (runtime.Object, error) {
-	return lw.client.ImageRepositories(kapi.NamespaceAll).List(labels.Everything(), labels.Everything())
+// newRetryController returns a controller that handles the objects of queue, retrying each object that
+// fails up to maxRetries times with an exponential backoff.
+func newRetryController(queue *oscache.WorkQueue, handle func(obj interface{}) error, stop <-chan struct{}) *oscontroller.RetryController {
+	return &oscontroller.RetryController{
+		Queue:        queue,
+		RetryManager: oscontroller.NewQueueRetryManager(queue, cache.MetaNamespaceKeyFunc, oscontroller.RetryLimit(maxRetries), initialBackoff, maxBackoff),
+		Handle:       handle,
+		Stop:         stop,
+	}
 }
 
-// Watch watches all ImageRepositories.
-func (lw *imageRepositoryLW) Watch(resourceVersion string) (watch.Interface, error) {
-	return lw.client.ImageRepositories(kapi.NamespaceAll).Watch(labels.Everything(), labels.Everything(), resourceVersion)
+// copyObject returns a copy of obj, since the objects of queues are shared with the stores of informers
+// and must not be modified.
+func copyObject(obj interface{}) (runtime.Object, error) {
+	copied, err := kapi.Scheme.Copy(obj.(runtime.Object))
+	if err != nil {
+		return nil, fmt.Errorf("unable to copy %#v: %v", obj, err)
+	}
+	return copied, nil
 }
 
 // ControllerClient implements the common interfaces needed for build controllers
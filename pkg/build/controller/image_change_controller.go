@@ -1,8 +1,11 @@
 package controller
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
@@ -14,31 +17,30 @@ import (
 
 // ImageChangeController watches for changes to ImageRepositories and triggers
 // builds when a new version of a tag referenced by a BuildConfig
-// is available.
+// is available.  A trigger may reference another BuildConfig instead, in which
+// case it watches the ImageRepository and tag that BuildConfig outputs to, so that
+// chains of builds (base image, runtime image, application) rebuild in turn.
 type ImageChangeController struct {
-	NextImageRepository func() *imageapi.ImageRepository
-	BuildConfigStore    cache.Store
-	BuildCreator        buildclient.BuildCreator
-	BuildConfigUpdater  buildclient.BuildConfigUpdater
-	// Stop is an optional channel that controls when the controller exits
-	Stop <-chan struct{}
-}
-
-// Run processes ImageRepository events one by one.
-func (c *ImageChangeController) Run() {
-	go util.Until(c.HandleImageRepo, 0, c.Stop)
+	BuildConfigStore   cache.Store
+	BuildCreator       buildclient.BuildCreator
+	BuildConfigUpdater buildclient.BuildConfigUpdater
 }
 
-// HandleImageRepo processes the next ImageRepository event.
-func (c *ImageChangeController) HandleImageRepo() {
-	glog.V(4).Infof("Waiting for imagerepo change")
-	imageRepo := c.NextImageRepository()
+// HandleImageRepo triggers the builds of the BuildConfigs with a trigger on a changed ImageRepository.  An
+// error is returned if the ImageRepository should be retried.
+func (c *ImageChangeController) HandleImageRepo(imageRepo *imageapi.ImageRepository) error {
 	glog.V(4).Infof("Build image change controller detected imagerepo change %s", imageRepo.DockerImageRepository)
 	imageSubstitutions := make(map[string]string)
 
 	// TODO: this is inefficient
+	var firstErr error
 	for _, bc := range c.BuildConfigStore.List() {
-		config := bc.(*buildapi.BuildConfig)
+		// the config is shared with the store and must not be modified
+		copied, err := kapi.Scheme.Copy(bc.(*buildapi.BuildConfig))
+		if err != nil {
+			return fmt.Errorf("unable to copy buildConfig: %v", err)
+		}
+		config := copied.(*buildapi.BuildConfig)
 		glog.V(4).Infof("Detecting changed images for buildConfig %s", config.Name)
 
 		// Extract relevant triggers for this imageRepo for this config
@@ -48,19 +50,19 @@ func (c *ImageChangeController) HandleImageRepo() {
 				continue
 			}
 			icTrigger := trigger.ImageChange
+			from, tag, ok := c.triggerSource(config, icTrigger)
+			if !ok {
+				continue
+			}
 			// only trigger a build if this image repo matches the name and namespace of the ref in the build trigger
 			// also do not trigger if the imagerepo does not have a valid DockerImageRepository value for us to pull
 			// the image from
-			if imageRepo.Status.DockerImageRepository == "" || icTrigger.From.Name != imageRepo.Name || (len(icTrigger.From.Namespace) != 0 && icTrigger.From.Namespace != imageRepo.Namespace) {
+			if imageRepo.Status.DockerImageRepository == "" || from.Name != imageRepo.Name || (len(from.Namespace) != 0 && from.Namespace != imageRepo.Namespace) {
 				continue
 			}
 			// for every ImageChange trigger, record the image it substitutes for and get the latest
 			// image id from the imagerepository.  We will substitute all images in the buildconfig
 			// with the latest values from the imagerepositories.
-			tag := icTrigger.Tag
-			if len(tag) == 0 {
-				tag = buildapi.DefaultImageTag
-			}
 			imageID, hasTag := imageRepo.Tags[tag]
 			if !hasTag {
 				continue
@@ -78,12 +80,86 @@ func (c *ImageChangeController) HandleImageRepo() {
 			glog.V(4).Infof("Running build for buildConfig %s in namespace %s", config.Name, config.Namespace)
 			b := buildutil.GenerateBuildFromConfig(config, nil, imageSubstitutions)
 			if err := c.BuildCreator.Create(config.Namespace, b); err != nil {
-				glog.V(2).Infof("Error starting build for buildConfig %v: %v", config.Name, err)
+				if firstErr == nil {
+					firstErr = fmt.Errorf("error starting build for buildConfig %v: %v", config.Name, err)
+				}
 			} else {
 				if err := c.BuildConfigUpdater.Update(config); err != nil {
+					// the build was started, retrying would start another one
 					glog.V(2).Infof("Error updating buildConfig %v: %v", config.Name, err)
 				}
 			}
 		}
 	}
+	return firstErr
+}
+
+// triggerSource returns the ImageRepository and tag an ImageChange trigger of config watches.  A trigger
+// from a BuildConfig watches the output of that BuildConfig, and false is returned if it does not exist or
+// does not output to an ImageRepository, or if config itself triggers that BuildConfig, directly or through
+// others, since the builds of the chain would then trigger each other forever.
+func (c *ImageChangeController) triggerSource(config *buildapi.BuildConfig, trigger *buildapi.ImageChangeTrigger) (kapi.ObjectReference, string, bool) {
+	from, tag := trigger.From, trigger.Tag
+	if from.Kind == "BuildConfig" {
+		namespace := from.Namespace
+		if len(namespace) == 0 {
+			namespace = config.Namespace
+		}
+		if c.triggersBuildConfig(config, namespace, from.Name, util.NewStringSet()) {
+			glog.Warningf("Ignoring the trigger of buildConfig %s/%s on buildConfig %s/%s, which is triggered by the output of %s itself", config.Namespace, config.Name, namespace, from.Name, config.Name)
+			return from, tag, false
+		}
+		obj, exists, err := c.BuildConfigStore.Get(&buildapi.BuildConfig{ObjectMeta: kapi.ObjectMeta{Namespace: namespace, Name: from.Name}})
+		if err != nil || !exists {
+			glog.V(4).Infof("BuildConfig %s/%s triggering buildConfig %s could not be found: %v", namespace, from.Name, config.Name, err)
+			return from, tag, false
+		}
+		output := obj.(*buildapi.BuildConfig).Parameters.Output
+		if output.To == nil {
+			glog.V(4).Infof("BuildConfig %s/%s triggering buildConfig %s does not output to an ImageRepository", namespace, from.Name, config.Name)
+			return from, tag, false
+		}
+		from = *output.To
+		if len(from.Namespace) == 0 {
+			from.Namespace = namespace
+		}
+		if len(tag) == 0 {
+			tag = output.Tag
+		}
+	}
+	if len(tag) == 0 {
+		tag = buildapi.DefaultImageTag
+	}
+	return from, tag, true
+}
+
+// triggersBuildConfig returns true if the BuildConfig namespace/name is config, or is triggered by the output of
+// config through a chain of BuildConfig image change triggers.  visited holds the BuildConfigs already followed.
+func (c *ImageChangeController) triggersBuildConfig(config *buildapi.BuildConfig, namespace, name string, visited util.StringSet) bool {
+	if namespace == config.Namespace && name == config.Name {
+		return true
+	}
+	key := namespace + "/" + name
+	if visited.Has(key) {
+		return false
+	}
+	visited.Insert(key)
+
+	obj, exists, err := c.BuildConfigStore.Get(&buildapi.BuildConfig{ObjectMeta: kapi.ObjectMeta{Namespace: namespace, Name: name}})
+	if err != nil || !exists {
+		return false
+	}
+	for _, trigger := range obj.(*buildapi.BuildConfig).Triggers {
+		if trigger.Type != buildapi.ImageChangeBuildTriggerType || trigger.ImageChange == nil || trigger.ImageChange.From.Kind != "BuildConfig" {
+			continue
+		}
+		upstream := trigger.ImageChange.From.Namespace
+		if len(upstream) == 0 {
+			upstream = namespace
+		}
+		if c.triggersBuildConfig(config, upstream, trigger.ImageChange.From.Name, visited) {
+			return true
+		}
+	}
+	return false
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 
 	buildapi "github.com/openshift/origin/pkg/build/api"
 	buildtest "github.com/openshift/origin/pkg/build/controller/test"
@@ -72,8 +73,8 @@ func appendTrigger(buildcfg *buildapi.BuildConfig, triggerImage, repoName, repoT
 	})
 }
 
-func mockImageChangeController(buildcfg *buildapi.BuildConfig, repoName, dockerImageRepo string, tags map[string]string) *ImageChangeController {
-	imageRepo := imageapi.ImageRepository{
+func mockImageChangeController(buildcfg *buildapi.BuildConfig, repoName, dockerImageRepo string, tags map[string]string) (*ImageChangeController, *imageapi.ImageRepository) {
+	imageRepo := &imageapi.ImageRepository{
 		ObjectMeta: kapi.ObjectMeta{
 			Name: repoName,
 		},
@@ -84,18 +85,17 @@ func mockImageChangeController(buildcfg *buildapi.BuildConfig, repoName, dockerI
 	}
 
 	return &ImageChangeController{
-		NextImageRepository: func() *imageapi.ImageRepository { return &imageRepo },
-		BuildConfigStore:    buildtest.NewFakeBuildConfigStore(buildcfg),
-		BuildCreator:        &mockBuildCreator{},
-		BuildConfigUpdater:  &mockBuildConfigUpdater{},
-	}
+		BuildConfigStore:   buildtest.NewFakeBuildConfigStore(buildcfg),
+		BuildCreator:       &mockBuildCreator{},
+		BuildConfigUpdater: &mockBuildConfigUpdater{},
+	}, imageRepo
 }
 
 func TestNewImageID(t *testing.T) {
 	// valid configuration, new build should be triggered.
 	buildcfg := mockBuildConfig("registry.com/namespace/imagename", "registry.com/namespace/imagename", "testImageRepo", "testTag")
-	controller := mockImageChangeController(buildcfg, "testImageRepo", "registry.com/namespace/imagename", map[string]string{"testTag": "newImageID123"})
-	controller.HandleImageRepo()
+	controller, imageRepo := mockImageChangeController(buildcfg, "testImageRepo", "registry.com/namespace/imagename", map[string]string{"testTag": "newImageID123"})
+	controller.HandleImageRepo(imageRepo)
 	buildCreator := controller.BuildCreator.(*mockBuildCreator)
 	buildConfigUpdater := controller.BuildConfigUpdater.(*mockBuildConfigUpdater)
 
@@ -116,8 +116,8 @@ func TestNewImageID(t *testing.T) {
 func TestNewImageIDDefaultTag(t *testing.T) {
 	// valid configuration using default tag, new build should be triggered.
 	buildcfg := mockBuildConfig("registry.com/namespace/imagename", "registry.com/namespace/imagename", "testImageRepo", "")
-	controller := mockImageChangeController(buildcfg, "testImageRepo", "registry.com/namespace/imagename", map[string]string{buildapi.DefaultImageTag: "newImageID123"})
-	controller.HandleImageRepo()
+	controller, imageRepo := mockImageChangeController(buildcfg, "testImageRepo", "registry.com/namespace/imagename", map[string]string{buildapi.DefaultImageTag: "newImageID123"})
+	controller.HandleImageRepo(imageRepo)
 	buildCreator := controller.BuildCreator.(*mockBuildCreator)
 	buildConfigUpdater := controller.BuildConfigUpdater.(*mockBuildConfigUpdater)
 
@@ -139,8 +139,8 @@ func TestNonExistentImageRepository(t *testing.T) {
 	// this buildconfig references a non-existent imagerepo, so an update to the real imagerepo should not
 	// trigger a build here.
 	buildcfg := mockBuildConfig("registry.com/namespace/imagename", "registry.com/namespace/imagename", "testImageRepo", "testTag")
-	controller := mockImageChangeController(buildcfg, "otherImageRepo", "registry.com/namespace/imagename", map[string]string{"testTag": "newImageID123"})
-	controller.HandleImageRepo()
+	controller, imageRepo := mockImageChangeController(buildcfg, "otherImageRepo", "registry.com/namespace/imagename", map[string]string{"testTag": "newImageID123"})
+	controller.HandleImageRepo(imageRepo)
 	buildCreator := controller.BuildCreator.(*mockBuildCreator)
 	buildConfigUpdater := controller.BuildConfigUpdater.(*mockBuildConfigUpdater)
 
@@ -155,8 +155,8 @@ func TestNonExistentImageRepository(t *testing.T) {
 func TestNewImageDifferentTagUpdate(t *testing.T) {
 	// this buildconfig references a different tag than the one that will be updated
 	buildcfg := mockBuildConfig("registry.com/namespace/imagename", "registry.com/namespace/imagename", "testImageRepo", "testTag")
-	controller := mockImageChangeController(buildcfg, "testImageRepo", "registry.com/namespace/imagename", map[string]string{"otherTag": "newImageID123"})
-	controller.HandleImageRepo()
+	controller, imageRepo := mockImageChangeController(buildcfg, "testImageRepo", "registry.com/namespace/imagename", map[string]string{"otherTag": "newImageID123"})
+	controller.HandleImageRepo(imageRepo)
 	buildCreator := controller.BuildCreator.(*mockBuildCreator)
 	buildConfigUpdater := controller.BuildConfigUpdater.(*mockBuildConfigUpdater)
 
@@ -173,9 +173,9 @@ func TestNewImageDifferentTagUpdate2(t *testing.T) {
 	// it has previously run a build for the testTagID123 tag.
 	buildcfg := mockBuildConfig("registry.com/namespace/imagename", "registry.com/namespace/imagename", "testImageRepo", "testTag")
 	buildcfg.Triggers[0].ImageChange.LastTriggeredImageID = "testTagID123"
-	controller := mockImageChangeController(buildcfg, "testImageRepo", "registry.com/namespace/imagename",
+	controller, imageRepo := mockImageChangeController(buildcfg, "testImageRepo", "registry.com/namespace/imagename",
 		map[string]string{"otherTag": "newImageID123", "testTag": "testTagID123"})
-	controller.HandleImageRepo()
+	controller.HandleImageRepo(imageRepo)
 	buildCreator := controller.BuildCreator.(*mockBuildCreator)
 	buildConfigUpdater := controller.BuildConfigUpdater.(*mockBuildConfigUpdater)
 
@@ -190,8 +190,8 @@ func TestNewImageDifferentTagUpdate2(t *testing.T) {
 func TestNewDifferentImageUpdate(t *testing.T) {
 	// this buildconfig references a different image than the one that will be updated
 	buildcfg := mockBuildConfig("registry.com/namespace/imagename1", "registry.com/namespace/imagename1", "testImageRepo1", "testTag1")
-	controller := mockImageChangeController(buildcfg, "testImageRepo2", "registry.com/namespace/imagename2", map[string]string{"testTag2": "newImageID123"})
-	controller.HandleImageRepo()
+	controller, imageRepo := mockImageChangeController(buildcfg, "testImageRepo2", "registry.com/namespace/imagename2", map[string]string{"testTag2": "newImageID123"})
+	controller.HandleImageRepo(imageRepo)
 	buildCreator := controller.BuildCreator.(*mockBuildCreator)
 	buildConfigUpdater := controller.BuildConfigUpdater.(*mockBuildConfigUpdater)
 
@@ -207,8 +207,8 @@ func TestMultipleTriggers(t *testing.T) {
 	// this buildconfig references multiple images
 	buildcfg := mockBuildConfig("registry.com/namespace/imagename1", "registry.com/namespace/imagename1", "testImageRepo1", "testTag1")
 	appendTrigger(buildcfg, "registry.com/namespace/imagename2", "testImageRepo2", "testTag2")
-	controller := mockImageChangeController(buildcfg, "testImageRepo2", "registry.com/namespace/imagename2", map[string]string{"testTag2": "newImageID123"})
-	controller.HandleImageRepo()
+	controller, imageRepo := mockImageChangeController(buildcfg, "testImageRepo2", "registry.com/namespace/imagename2", map[string]string{"testTag2": "newImageID123"})
+	controller.HandleImageRepo(imageRepo)
 	buildCreator := controller.BuildCreator.(*mockBuildCreator)
 	buildConfigUpdater := controller.BuildConfigUpdater.(*mockBuildConfigUpdater)
 
@@ -231,8 +231,8 @@ func TestBuildConfigWithDifferentTriggerType(t *testing.T) {
 	// this buildconfig has different (than ImageChangeTrigger) trigger defined
 	buildcfg := mockBuildConfig("registry.com/namespace/imagename1", "", "", "")
 	buildcfg.Triggers[0].Type = buildapi.GenericWebHookBuildTriggerType
-	controller := mockImageChangeController(buildcfg, "testImageRepo2", "registry.com/namespace/imagename2", map[string]string{"testTag2": "newImageID123"})
-	controller.HandleImageRepo()
+	controller, imageRepo := mockImageChangeController(buildcfg, "testImageRepo2", "registry.com/namespace/imagename2", map[string]string{"testTag2": "newImageID123"})
+	controller.HandleImageRepo(imageRepo)
 	buildCreator := controller.BuildCreator.(*mockBuildCreator)
 	buildConfigUpdater := controller.BuildConfigUpdater.(*mockBuildConfigUpdater)
 
@@ -249,8 +249,8 @@ func TestNoImageIDChange(t *testing.T) {
 	// startup when we're checking all the imageRepos
 	buildcfg := mockBuildConfig("registry.com/namespace/imagename", "registry.com/namespace/imagename", "testImageRepo", "testTag")
 	buildcfg.Triggers[0].ImageChange.LastTriggeredImageID = "imageID123"
-	controller := mockImageChangeController(buildcfg, "testImageRepo", "registry.com/namespace/imagename", map[string]string{"testTag": "imageID123"})
-	controller.HandleImageRepo()
+	controller, imageRepo := mockImageChangeController(buildcfg, "testImageRepo", "registry.com/namespace/imagename", map[string]string{"testTag": "imageID123"})
+	controller.HandleImageRepo(imageRepo)
 	buildCreator := controller.BuildCreator.(*mockBuildCreator)
 	buildConfigUpdater := controller.BuildConfigUpdater.(*mockBuildConfigUpdater)
 
@@ -265,10 +265,12 @@ func TestNoImageIDChange(t *testing.T) {
 func TestBuildCreateError(t *testing.T) {
 	// valid configuration, but build creation failes, in that situation the buildconfig should not be updated
 	buildcfg := mockBuildConfig("registry.com/namespace/imagename", "registry.com/namespace/imagename", "testImageRepo", "testTag")
-	controller := mockImageChangeController(buildcfg, "testImageRepo", "registry.com/namespace/imagename", map[string]string{"testTag": "newImageID123"})
+	controller, imageRepo := mockImageChangeController(buildcfg, "testImageRepo", "registry.com/namespace/imagename", map[string]string{"testTag": "newImageID123"})
 	buildCreator := controller.BuildCreator.(*mockBuildCreator)
 	buildCreator.err = fmt.Errorf("error")
-	controller.HandleImageRepo()
+	if err := controller.HandleImageRepo(imageRepo); err == nil {
+		t.Error("Expected an error so the build is retried")
+	}
 	buildConfigUpdater := controller.BuildConfigUpdater.(*mockBuildConfigUpdater)
 
 	if buildCreator.build == nil {
@@ -280,13 +282,16 @@ func TestBuildCreateError(t *testing.T) {
 	if buildConfigUpdater.buildcfg != nil {
 		t.Fatal("Expected no buildConfig update on BuildCreate error!")
 	}
+	if buildcfg.Triggers[0].ImageChange.LastTriggeredImageID != "" {
+		t.Errorf("Expected the buildConfig in the store to be unchanged, got %#v", buildcfg.Triggers[0].ImageChange)
+	}
 }
 
 func TestNewImageIDNoDockerRepo(t *testing.T) {
 	// No docker repository associated with the imagerepo, so no build can be created
 	buildcfg := mockBuildConfig("registry.com/namespace/imagename", "registry.com/namespace/imagename", "testImageRepo", "testTag")
-	controller := mockImageChangeController(buildcfg, "testImageRepo", "", map[string]string{"testTag": "newImageID123"})
-	controller.HandleImageRepo()
+	controller, imageRepo := mockImageChangeController(buildcfg, "testImageRepo", "", map[string]string{"testTag": "newImageID123"})
+	controller.HandleImageRepo(imageRepo)
 	buildCreator := controller.BuildCreator.(*mockBuildCreator)
 	buildConfigUpdater := controller.BuildConfigUpdater.(*mockBuildConfigUpdater)
 
@@ -297,3 +302,85 @@ func TestNewImageIDNoDockerRepo(t *testing.T) {
 		t.Error("BuildConfig was updated when no change happened!")
 	}
 }
+
+func TestBuildConfigOutputTrigger(t *testing.T) {
+	base := &buildapi.BuildConfig{
+		ObjectMeta: kapi.ObjectMeta{Name: "base", Namespace: "test"},
+		Parameters: buildapi.BuildParameters{
+			Output: buildapi.BuildOutput{
+				To:  &kapi.ObjectReference{Name: "baseImageRepo"},
+				Tag: "stable",
+			},
+		},
+	}
+	app := mockBuildConfig("registry.com/test/base", "registry.com/test/base", "base", "")
+	app.Namespace = "test"
+	app.Triggers[0].ImageChange.From.Kind = "BuildConfig"
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	store.Add(base)
+	store.Add(app)
+	controller := &ImageChangeController{
+		BuildConfigStore:   store,
+		BuildCreator:       &mockBuildCreator{},
+		BuildConfigUpdater: &mockBuildConfigUpdater{},
+	}
+	imageRepo := &imageapi.ImageRepository{
+		ObjectMeta: kapi.ObjectMeta{Name: "baseImageRepo", Namespace: "test"},
+		Status:     imageapi.ImageRepositoryStatus{DockerImageRepository: "registry.com/test/base"},
+		Tags:       map[string]string{buildapi.DefaultImageTag: "oldImageID", "stable": "newImageID123"},
+	}
+
+	if err := controller.HandleImageRepo(imageRepo); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	buildCreator := controller.BuildCreator.(*mockBuildCreator)
+	if buildCreator.build == nil {
+		t.Fatal("Expected a build when the output of the triggering buildConfig changed")
+	}
+	if buildCreator.build.Labels[buildapi.BuildConfigLabel] != app.Name {
+		t.Errorf("Expected a build of %s, got %#v", app.Name, buildCreator.build.Labels)
+	}
+	if image := buildCreator.build.Parameters.Strategy.DockerStrategy.BaseImage; image != "registry.com/test/base:newImageID123" {
+		t.Errorf("Expected the output tag of the triggering buildConfig to be substituted, got %s", image)
+	}
+
+	buildCreator.build = nil
+	base.Parameters.Output = buildapi.BuildOutput{DockerImageReference: "registry.com/test/base"}
+	imageRepo.Tags["stable"] = "newerImageID"
+	controller.HandleImageRepo(imageRepo)
+	if buildCreator.build != nil {
+		t.Error("Expected no build when the triggering buildConfig does not output to an ImageRepository")
+	}
+}
+
+func TestBuildConfigOutputTriggerCycle(t *testing.T) {
+	// app is triggered by the output of base, which is triggered by the output of app
+	base := mockBuildConfig("registry.com/test/app", "registry.com/test/app", "app", "")
+	base.Name, base.Namespace = "base", "test"
+	base.Triggers[0].ImageChange.From.Kind = "BuildConfig"
+	base.Parameters.Output = buildapi.BuildOutput{To: &kapi.ObjectReference{Name: "baseImageRepo"}}
+	app := mockBuildConfig("registry.com/test/base", "registry.com/test/base", "base", "")
+	app.Name, app.Namespace = "app", "test"
+	app.Triggers[0].ImageChange.From.Kind = "BuildConfig"
+	app.Parameters.Output = buildapi.BuildOutput{To: &kapi.ObjectReference{Name: "appImageRepo"}}
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	store.Add(base)
+	store.Add(app)
+	controller := &ImageChangeController{
+		BuildConfigStore:   store,
+		BuildCreator:       &mockBuildCreator{},
+		BuildConfigUpdater: &mockBuildConfigUpdater{},
+	}
+	imageRepo := &imageapi.ImageRepository{
+		ObjectMeta: kapi.ObjectMeta{Name: "baseImageRepo", Namespace: "test"},
+		Status:     imageapi.ImageRepositoryStatus{DockerImageRepository: "registry.com/test/base"},
+		Tags:       map[string]string{buildapi.DefaultImageTag: "newImageID123"},
+	}
+
+	if err := controller.HandleImageRepo(imageRepo); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if build := controller.BuildCreator.(*mockBuildCreator).build; build != nil {
+		t.Errorf("Expected no build for a cycle of buildConfig triggers, got %#v", build)
+	}
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+)
+
+// buildStatuses are the statuses builds are counted by, in the order they are reported.
+var buildStatuses = []buildapi.BuildStatus{
+	buildapi.BuildStatusNew,
+	buildapi.BuildStatusPending,
+	buildapi.BuildStatusRunning,
+	buildapi.BuildStatusComplete,
+	buildapi.BuildStatusFailed,
+	buildapi.BuildStatusError,
+	buildapi.BuildStatusCancelled,
+}
+
+// buildDuration accumulates the durations of the finished builds of a strategy.
+type buildDuration struct {
+	count int64
+	sum   time.Duration
+}
+
+// BuildMetrics reports the builds of a store by status, the builds waiting for a builder, and the durations of
+// the builds the controller saw finish by strategy, so builder nodes can be planned for without reading etcd.
+type BuildMetrics struct {
+	// Builds is the store of all builds
+	Builds cache.Store
+
+	lock      sync.Mutex
+	durations map[buildapi.BuildStrategyType]*buildDuration
+}
+
+// NewBuildMetrics returns a BuildMetrics that counts the builds of store.
+func NewBuildMetrics(store cache.Store) *BuildMetrics {
+	return &BuildMetrics{
+		Builds:    store,
+		durations: make(map[buildapi.BuildStrategyType]*buildDuration),
+	}
+}
+
+// ObserveBuild records the duration of a build that finished.
+func (m *BuildMetrics) ObserveBuild(build *buildapi.Build, elapsed time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	strategy := build.Parameters.Strategy.Type
+	if _, ok := m.durations[strategy]; !ok {
+		m.durations[strategy] = &buildDuration{}
+	}
+	m.durations[strategy].count++
+	m.durations[strategy].sum += elapsed
+}
+
+// Write writes the metrics in the Prometheus text format.
+func (m *BuildMetrics) Write(w io.Writer) {
+	counts := map[buildapi.BuildStatus]int64{}
+	for _, obj := range m.Builds.List() {
+		counts[obj.(*buildapi.Build).Status]++
+	}
+	fmt.Fprintf(w, "# HELP openshift_builds The number of builds by status.\n")
+	fmt.Fprintf(w, "# TYPE openshift_builds gauge\n")
+	for _, status := range buildStatuses {
+		fmt.Fprintf(w, "openshift_builds{status=%q} %d\n", status, counts[status])
+	}
+	fmt.Fprintf(w, "# HELP openshift_build_queue_depth The number of builds waiting for their pod to start.\n")
+	fmt.Fprintf(w, "# TYPE openshift_build_queue_depth gauge\n")
+	fmt.Fprintf(w, "openshift_build_queue_depth %d\n", counts[buildapi.BuildStatusNew]+counts[buildapi.BuildStatusPending])
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	strategies := []string{}
+	for strategy := range m.durations {
+		strategies = append(strategies, string(strategy))
+	}
+	sort.Strings(strategies)
+	fmt.Fprintf(w, "# HELP openshift_build_duration_seconds The duration of finished builds from the creation of their pod.\n")
+	fmt.Fprintf(w, "# TYPE openshift_build_duration_seconds summary\n")
+	for _, strategy := range strategies {
+		d := m.durations[buildapi.BuildStrategyType(strategy)]
+		fmt.Fprintf(w, "openshift_build_duration_seconds_sum{strategy=%q} %g\n", strategy, d.sum.Seconds())
+		fmt.Fprintf(w, "openshift_build_duration_seconds_count{strategy=%q} %d\n", strategy, d.count)
+	}
+}
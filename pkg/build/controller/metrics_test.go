@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+)
+
+func TestBuildMetrics(t *testing.T) {
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	for name, status := range map[string]buildapi.BuildStatus{
+		"a": buildapi.BuildStatusNew,
+		"b": buildapi.BuildStatusPending,
+		"c": buildapi.BuildStatusRunning,
+		"d": buildapi.BuildStatusComplete,
+		"e": buildapi.BuildStatusComplete,
+	} {
+		store.Add(&buildapi.Build{ObjectMeta: kapi.ObjectMeta{Name: name, Namespace: "test"}, Status: status})
+	}
+	metrics := NewBuildMetrics(store)
+	docker := &buildapi.Build{Parameters: buildapi.BuildParameters{Strategy: buildapi.BuildStrategy{Type: buildapi.DockerBuildStrategyType}}}
+	metrics.ObserveBuild(docker, 2*time.Minute)
+	metrics.ObserveBuild(docker, time.Minute)
+
+	out := &bytes.Buffer{}
+	metrics.Write(out)
+	for _, expected := range []string{
+		`openshift_builds{status="New"} 1`,
+		`openshift_builds{status="Complete"} 2`,
+		`openshift_builds{status="Failed"} 0`,
+		`openshift_build_queue_depth 2`,
+		`openshift_build_duration_seconds_sum{strategy="Docker"} 180`,
+		`openshift_build_duration_seconds_count{strategy="Docker"} 2`,
+	} {
+		if !strings.Contains(out.String(), expected+"\n") {
+			t.Errorf("Expected %q in metrics, got:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestHandlePodObservesFinishedBuilds(t *testing.T) {
+	build, ctrl := mockBuildAndController(buildapi.BuildStatusRunning, buildapi.BuildOutput{})
+	ctrl.Metrics = NewBuildMetrics(cache.NewStore(cache.MetaNamespaceKeyFunc))
+	pod := mockPod(kapi.PodSucceeded, 0)
+	pod.CreationTimestamp = util.NewTime(time.Now().Add(-time.Minute))
+	build.PodName = pod.Name
+
+	if err := ctrl.HandlePod(pod); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	d := ctrl.Metrics.durations[buildapi.DockerBuildStrategyType]
+	if d == nil || d.count != 1 || d.sum < time.Minute {
+		t.Errorf("Expected the duration of the build to be recorded, got %#v", d)
+	}
+}
@@ -13,6 +13,8 @@ import (
 // CustomBuildStrategy creates a build using a custom builder image.
 type CustomBuildStrategy struct {
 	UseLocalImages bool
+	// SourceSecretsDir is the directory of the nodes that holds the source and push secrets of builds by namespace
+	SourceSecretsDir string
 	// Codec is the codec to use for encoding the output pod.
 	// IMPORTANT: This may break backwards compatibility when
 	// it changes.
@@ -48,6 +50,9 @@ func (bs *CustomBuildStrategy) CreateBuildPod(build *buildapi.Build) (*kapi.Pod,
 	pod := &kapi.Pod{
 		ObjectMeta: kapi.ObjectMeta{
 			Name: build.PodName,
+			Annotations: map[string]string{
+				buildapi.BuildAnnotation: build.Name,
+			},
 		},
 		Spec: kapi.PodSpec{
 			Containers: []kapi.Container{
@@ -77,5 +82,8 @@ func (bs *CustomBuildStrategy) CreateBuildPod(build *buildapi.Build) (*kapi.Pod,
 		setupDockerSocket(pod)
 		setupDockerConfig(pod)
 	}
+	if err := setupSecrets(pod, build, bs.SourceSecretsDir); err != nil {
+		return nil, err
+	}
 	return pod, nil
 }
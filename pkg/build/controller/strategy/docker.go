@@ -11,6 +11,10 @@ import (
 type DockerBuildStrategy struct {
 	Image          string
 	UseLocalImages bool
+	// AllowedBuilderImages are the images builds may run instead of Image
+	AllowedBuilderImages []string
+	// SourceSecretsDir is the directory of the nodes that holds the source and push secrets of builds by namespace
+	SourceSecretsDir string
 	// Codec is the codec to use for encoding the output pod.
 	// IMPORTANT: This may break backwards compatibility when
 	// it changes.
@@ -25,15 +29,27 @@ func (bs *DockerBuildStrategy) CreateBuildPod(build *buildapi.Build) (*kapi.Pod,
 		return nil, err
 	}
 
+	override := ""
+	if strategy := build.Parameters.Strategy.DockerStrategy; strategy != nil {
+		override = strategy.BuilderPodImage
+	}
+	image, err := builderImage(bs.Image, override, bs.AllowedBuilderImages)
+	if err != nil {
+		return nil, err
+	}
+
 	pod := &kapi.Pod{
 		ObjectMeta: kapi.ObjectMeta{
 			Name: build.PodName,
+			Annotations: map[string]string{
+				buildapi.BuildAnnotation: build.Name,
+			},
 		},
 		Spec: kapi.PodSpec{
 			Containers: []kapi.Container{
 				{
 					Name:  "docker-build",
-					Image: bs.Image,
+					Image: image,
 					Env: []kapi.EnvVar{
 						{Name: "BUILD", Value: string(data)},
 					},
@@ -53,5 +69,8 @@ func (bs *DockerBuildStrategy) CreateBuildPod(build *buildapi.Build) (*kapi.Pod,
 
 	setupDockerSocket(pod)
 	setupDockerConfig(pod)
+	if err := setupSecrets(pod, build, bs.SourceSecretsDir); err != nil {
+		return nil, err
+	}
 	return pod, nil
 }
@@ -22,6 +22,9 @@ func TestDockerCreateBuildPod(t *testing.T) {
 	if actual.ObjectMeta.Name != expected.PodName {
 		t.Errorf("Expected %s, but got %s!", expected.PodName, actual.ObjectMeta.Name)
 	}
+	if actual.Annotations[buildapi.BuildAnnotation] != expected.Name {
+		t.Errorf("Expected the pod to be annotated with build %s, got %v", expected.Name, actual.Annotations)
+	}
 
 	container := actual.Spec.Containers[0]
 	if container.Name != "docker-build" {
@@ -50,6 +53,29 @@ func TestDockerCreateBuildPod(t *testing.T) {
 	}
 }
 
+func TestDockerCreateBuildPodBuilderImage(t *testing.T) {
+	strategy := DockerBuildStrategy{
+		Image:                "docker-test-image",
+		AllowedBuilderImages: []string{"example.com/docker-builder"},
+		Codec:                v1beta1.Codec,
+	}
+
+	build := mockDockerBuild()
+	build.Parameters.Strategy.DockerStrategy.BuilderPodImage = "example.com/docker-builder:v2"
+	pod, err := strategy.CreateBuildPod(build)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if image := pod.Spec.Containers[0].Image; image != "example.com/docker-builder:v2" {
+		t.Errorf("Expected the builder image of the build, got %s", image)
+	}
+
+	build.Parameters.Strategy.DockerStrategy.BuilderPodImage = "example.com/other-builder"
+	if _, err := strategy.CreateBuildPod(build); err == nil {
+		t.Errorf("Expected an error for a builder image that is not allowed")
+	}
+}
+
 func mockDockerBuild() *buildapi.Build {
 	return &buildapi.Build{
 		ObjectMeta: kapi.ObjectMeta{
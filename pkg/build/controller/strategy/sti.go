@@ -14,6 +14,10 @@ type STIBuildStrategy struct {
 	Image                string
 	TempDirectoryCreator TempDirectoryCreator
 	UseLocalImages       bool
+	// AllowedBuilderImages are the images builds may run instead of Image
+	AllowedBuilderImages []string
+	// SourceSecretsDir is the directory of the nodes that holds the source and push secrets of builds by namespace
+	SourceSecretsDir string
 	// Codec is the codec to use for encoding the output pod.
 	// IMPORTANT: This may break backwards compatibility when
 	// it changes.
@@ -39,6 +43,10 @@ func (bs *STIBuildStrategy) CreateBuildPod(build *buildapi.Build) (*kapi.Pod, er
 	if err != nil {
 		return nil, err
 	}
+	image, err := builderImage(bs.Image, build.Parameters.Strategy.STIStrategy.BuilderPodImage, bs.AllowedBuilderImages)
+	if err != nil {
+		return nil, err
+	}
 
 	containerEnv := []kapi.EnvVar{
 		{Name: "BUILD", Value: string(data)},
@@ -52,12 +60,15 @@ func (bs *STIBuildStrategy) CreateBuildPod(build *buildapi.Build) (*kapi.Pod, er
 	pod := &kapi.Pod{
 		ObjectMeta: kapi.ObjectMeta{
 			Name: build.PodName,
+			Annotations: map[string]string{
+				buildapi.BuildAnnotation: build.Name,
+			},
 		},
 		Spec: kapi.PodSpec{
 			Containers: []kapi.Container{
 				{
 					Name:  "sti-build",
-					Image: bs.Image,
+					Image: image,
 					Env:   containerEnv,
 					// TODO: run unprivileged https://github.com/openshift/origin/issues/662
 					Privileged: true,
@@ -75,5 +86,8 @@ func (bs *STIBuildStrategy) CreateBuildPod(build *buildapi.Build) (*kapi.Pod, er
 
 	setupDockerSocket(pod)
 	setupDockerConfig(pod)
+	if err := setupSecrets(pod, build, bs.SourceSecretsDir); err != nil {
+		return nil, err
+	}
 	return pod, nil
 }
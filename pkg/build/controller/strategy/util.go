@@ -1,14 +1,32 @@
 package strategy
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path"
+	"strings"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	buildapi "github.com/openshift/origin/pkg/build/api"
 	imageapi "github.com/openshift/origin/pkg/image/api"
 )
 
+// builderImage returns the image the build pod of a strategy runs: the override of the build if one of the
+// allowed images permits it, or the default image of the cluster otherwise.  An allowed image without a tag
+// or ID permits every tag and ID of its repository.
+func builderImage(defaultImage, override string, allowed []string) (string, error) {
+	if len(override) == 0 || override == defaultImage {
+		return defaultImage, nil
+	}
+	for _, image := range allowed {
+		if override == image || strings.HasPrefix(override, image+":") || strings.HasPrefix(override, image+"@") {
+			return override, nil
+		}
+	}
+	return "", fmt.Errorf("the builder image %s is not allowed by the cluster", override)
+}
+
 // dockerSocketPath is the default path for the Docker socket inside the builder
 // container
 const dockerSocketPath = "/var/run/docker.sock"
@@ -64,6 +82,55 @@ func setupDockerConfig(podSpec *kapi.Pod) {
 			dockerConfigVolumeMount)
 }
 
+const (
+	// sourceSecretMountPath is where the source secret of a build is mounted in the builder container
+	sourceSecretMountPath = "/var/run/secrets/openshift.io/source"
+	// pushSecretMountPath is where the push secret of a build is mounted in the builder container
+	pushSecretMountPath = "/var/run/secrets/openshift.io/push"
+)
+
+// setupSecrets mounts the source and push secrets of the build, if any, read only from the directories of the
+// secrets under secretsDir on the node.  The source secret mount is named in the SOURCE_SECRET_PATH environment
+// variable, and the .dockercfg file of the push secret in DOCKERCFG_PATH, which the builder reads registry
+// credentials from before the .dockercfg file of its home directory.
+func setupSecrets(pod *kapi.Pod, build *buildapi.Build, secretsDir string) error {
+	if secret := build.Parameters.Source.SourceSecret; secret != nil {
+		if err := setupSecret(pod, build.Namespace, secret, secretsDir, "source-secret", sourceSecretMountPath); err != nil {
+			return err
+		}
+		pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, kapi.EnvVar{Name: "SOURCE_SECRET_PATH", Value: sourceSecretMountPath})
+	}
+	if secret := build.Parameters.Output.PushSecret; secret != nil {
+		if err := setupSecret(pod, build.Namespace, secret, secretsDir, "push-secret", pushSecretMountPath); err != nil {
+			return err
+		}
+		dockercfg := path.Join(pushSecretMountPath, buildapi.PushSecretDockercfgFile)
+		pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, kapi.EnvVar{Name: "DOCKERCFG_PATH", Value: dockercfg})
+	}
+	return nil
+}
+
+// setupSecret mounts the directory of secret in namespace under secretsDir read only at mountPath.
+func setupSecret(pod *kapi.Pod, namespace string, secret *buildapi.SourceSecret, secretsDir, volumeName, mountPath string) error {
+	if len(secretsDir) == 0 {
+		return errors.New("the build has a secret, but no source secrets directory is configured")
+	}
+	pod.Spec.Volumes = append(pod.Spec.Volumes, kapi.Volume{
+		Name: volumeName,
+		Source: kapi.VolumeSource{
+			HostPath: &kapi.HostPath{
+				Path: path.Join(secretsDir, namespace, secret.Name),
+			},
+		},
+	})
+	pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, kapi.VolumeMount{
+		Name:      volumeName,
+		ReadOnly:  true,
+		MountPath: mountPath,
+	})
+	return nil
+}
+
 // setupBuildEnv injects human-friendly environment variables which provides
 // useful information about the current build.
 func setupBuildEnv(build *buildapi.Build, pod *kapi.Pod) error {
@@ -1,9 +1,12 @@
 package strategy
 
 import (
+	"reflect"
 	"testing"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
 )
 
 func TestSetupDockerSocketHostSocket(t *testing.T) {
@@ -101,3 +104,71 @@ func TestSetupBuildEnvFails(t *testing.T) {
 		t.Errorf("unexpected non-error: %v", err)
 	}
 }
+
+func TestSetupSecrets(t *testing.T) {
+	build := mockCustomBuild()
+	build.Namespace = "foo"
+	pod := &kapi.Pod{Spec: kapi.PodSpec{Containers: []kapi.Container{{Name: "custom-build"}}}}
+
+	if err := setupSecrets(pod, build, "/var/lib/secrets"); err != nil || len(pod.Spec.Volumes) != 0 {
+		t.Fatalf("Expected builds without secrets to be left alone, got %v %#v", err, pod.Spec.Volumes)
+	}
+
+	build.Parameters.Source.SourceSecret = &buildapi.SourceSecret{Name: "deploy-key"}
+	build.Parameters.Output.PushSecret = &buildapi.SourceSecret{Name: "registry"}
+	if err := setupSecrets(pod, build, ""); err == nil {
+		t.Errorf("Expected an error without a source secrets directory")
+	}
+	if err := setupSecrets(pod, build, "/var/lib/secrets"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	paths := []string{}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Source.HostPath != nil {
+			paths = append(paths, volume.Source.HostPath.Path)
+		}
+	}
+	if !reflect.DeepEqual(paths, []string{"/var/lib/secrets/foo/deploy-key", "/var/lib/secrets/foo/registry"}) {
+		t.Errorf("Expected the secrets of the build namespace to be mounted, got %#v", pod.Spec.Volumes)
+	}
+	container := pod.Spec.Containers[0]
+	for _, mount := range container.VolumeMounts {
+		if !mount.ReadOnly {
+			t.Errorf("Expected the secrets to be mounted read only, got %#v", mount)
+		}
+	}
+	expectedEnv := []kapi.EnvVar{
+		{Name: "SOURCE_SECRET_PATH", Value: sourceSecretMountPath},
+		{Name: "DOCKERCFG_PATH", Value: pushSecretMountPath + "/.dockercfg"},
+	}
+	if !reflect.DeepEqual(container.Env, expectedEnv) {
+		t.Errorf("Expected the mounts to be named in the environment, got %#v", container.Env)
+	}
+}
+
+func TestBuilderImage(t *testing.T) {
+	allowed := []string{"registry.example.com/builders/docker", "registry.example.com/builders/sti:1.0"}
+	tests := map[string]struct {
+		override string
+		expected string
+		err      bool
+	}{
+		"no override":         {expected: "openshift/origin-docker-builder"},
+		"default image":       {override: "openshift/origin-docker-builder", expected: "openshift/origin-docker-builder"},
+		"allowed repository":  {override: "registry.example.com/builders/docker:2.0", expected: "registry.example.com/builders/docker:2.0"},
+		"allowed image ID":    {override: "registry.example.com/builders/docker@sha256:abc", expected: "registry.example.com/builders/docker@sha256:abc"},
+		"allowed tag":         {override: "registry.example.com/builders/sti:1.0", expected: "registry.example.com/builders/sti:1.0"},
+		"other tag":           {override: "registry.example.com/builders/sti:2.0", err: true},
+		"repository prefix":   {override: "registry.example.com/builders/docker-evil", err: true},
+		"unlisted repository": {override: "example.com/builder", err: true},
+	}
+	for name, test := range tests {
+		image, err := builderImage("openshift/origin-docker-builder", test.override, allowed)
+		if test.err != (err != nil) {
+			t.Errorf("%s: expected an error %t, got %v", name, test.err, err)
+		}
+		if image != test.expected {
+			t.Errorf("%s: expected %q, got %q", name, test.expected, image)
+		}
+	}
+}
@@ -12,6 +12,9 @@ import (
 type Registry interface {
 	// ListBuilds obtains list of builds that match a selector.
 	ListBuilds(ctx kapi.Context, labels labels.Selector) (*api.BuildList, error)
+	// ListBuildsPage obtains a page of up to limit builds that match label and field, which continues the
+	// list continueToken was returned with.
+	ListBuildsPage(ctx kapi.Context, label, field labels.Selector, limit int, continueToken string) (*api.BuildList, error)
 	// GetBuild retrieves a specific build.
 	GetBuild(ctx kapi.Context, id string) (*api.Build, error)
 	// CreateBuild creates a new build.
@@ -11,18 +11,21 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
+	"github.com/openshift/origin/pkg/api/selectable"
 	"github.com/openshift/origin/pkg/build/api"
 	"github.com/openshift/origin/pkg/build/api/validation"
+	"github.com/openshift/origin/pkg/client/record"
 )
 
 // REST implements the RESTStorage interface in terms of an Registry.
 type REST struct {
 	registry Registry
+	recorder record.Recorder
 }
 
 // NewREST creates a new REST for builds.
 func NewREST(registry Registry) apiserver.RESTStorage {
-	return &REST{registry}
+	return &REST{registry, record.NewRecorder()}
 }
 
 // New creates a new Build object
@@ -40,8 +43,20 @@ func (r *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime
 	if err != nil {
 		return nil, err
 	}
-	return builds, err
+	if err := selectable.FilterList(builds, selector, fields); err != nil {
+		return nil, err
+	}
+	return builds, nil
+
+}
 
+// ListPage obtains a page of up to limit builds that match selector and fields, which continues the list
+// continueToken was returned with.
+func (r *REST) ListPage(ctx kapi.Context, selector, fields labels.Selector, limit int, continueToken string) (runtime.Object, error) {
+	if err := selectable.ValidateFields(&api.Build{}, fields); err != nil {
+		return nil, err
+	}
+	return r.registry.ListBuildsPage(ctx, selector, fields, limit, continueToken)
 }
 
 // Get obtains the build specified by its id.
@@ -85,6 +100,7 @@ func (r *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 		if err != nil {
 			return nil, err
 		}
+		r.recorder.Eventf(build, "created", "Created build %s", build.Name)
 		return build, nil
 	}), nil
 }
@@ -103,15 +119,24 @@ func (r *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 	}
 
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
-		err := r.registry.UpdateBuild(ctx, build)
+		existing, err := r.registry.GetBuild(ctx, build.Name)
 		if err != nil {
 			return nil, err
 		}
+		if errs := validation.ValidateBuildUpdate(build, existing); len(errs) > 0 {
+			return nil, errors.NewInvalid("build", build.Name, errs)
+		}
+		if err := r.registry.UpdateBuild(ctx, build); err != nil {
+			return nil, err
+		}
 		return build, nil
 	}), nil
 }
 
 // Watch begins watching for new, changed, or deleted Builds.
 func (r *REST) Watch(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	if err := selectable.ValidateFields(&api.Build{}, field); err != nil {
+		return nil, err
+	}
 	return r.registry.WatchBuilds(ctx, label, field, resourceVersion)
 }
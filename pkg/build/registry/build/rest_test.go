@@ -19,11 +19,12 @@ import (
 	"github.com/openshift/origin/pkg/api/latest"
 	"github.com/openshift/origin/pkg/build/api"
 	"github.com/openshift/origin/pkg/build/registry/test"
+	"github.com/openshift/origin/pkg/client/record"
 )
 
 func TestNewBuild(t *testing.T) {
 	mockRegistry := test.BuildRegistry{}
-	storage := REST{&mockRegistry}
+	storage := REST{&mockRegistry, &record.FakeRecorder{}}
 	obj := storage.New()
 	_, ok := obj.(*api.Build)
 	if !ok {
@@ -34,7 +35,7 @@ func TestNewBuild(t *testing.T) {
 func TestGetBuild(t *testing.T) {
 	expectedBuild := mockBuild()
 	mockRegistry := test.BuildRegistry{Build: expectedBuild}
-	storage := REST{&mockRegistry}
+	storage := REST{&mockRegistry, &record.FakeRecorder{}}
 	buildObj, err := storage.Get(kapi.NewDefaultContext(), "foo")
 	if err != nil {
 		t.Errorf("Unexpected error returned: %v", err)
@@ -50,7 +51,7 @@ func TestGetBuild(t *testing.T) {
 
 func TestGetBuildError(t *testing.T) {
 	mockRegistry := test.BuildRegistry{Err: fmt.Errorf("get error")}
-	storage := REST{&mockRegistry}
+	storage := REST{&mockRegistry, &record.FakeRecorder{}}
 	buildObj, err := storage.Get(kapi.NewDefaultContext(), "foo")
 	if err != mockRegistry.Err {
 		t.Errorf("Expected %#v, Got %#v", mockRegistry.Err, err)
@@ -63,7 +64,7 @@ func TestGetBuildError(t *testing.T) {
 func TestDeleteBuild(t *testing.T) {
 	mockRegistry := test.BuildRegistry{}
 	buildID := "test-build-id"
-	storage := REST{&mockRegistry}
+	storage := REST{&mockRegistry, &record.FakeRecorder{}}
 	channel, err := storage.Delete(kapi.NewDefaultContext(), buildID)
 	if err != nil {
 		t.Errorf("Unexpected error when deleting: %v", err)
@@ -89,7 +90,7 @@ func TestDeleteBuild(t *testing.T) {
 func TestDeleteBuildError(t *testing.T) {
 	mockRegistry := test.BuildRegistry{Err: fmt.Errorf("Delete error")}
 	buildID := "test-build-id"
-	storage := REST{&mockRegistry}
+	storage := REST{&mockRegistry, &record.FakeRecorder{}}
 	channel, _ := storage.Delete(kapi.NewDefaultContext(), buildID)
 	select {
 	case result := <-channel:
@@ -109,7 +110,7 @@ func TestListBuildsError(t *testing.T) {
 	mockRegistry := test.BuildRegistry{
 		Err: fmt.Errorf("test error"),
 	}
-	storage := REST{&mockRegistry}
+	storage := REST{&mockRegistry, &record.FakeRecorder{}}
 	builds, err := storage.List(kapi.NewDefaultContext(), nil, nil)
 	if err != mockRegistry.Err {
 		t.Errorf("Expected %#v, Got %#v", mockRegistry.Err, err)
@@ -119,9 +120,17 @@ func TestListBuildsError(t *testing.T) {
 	}
 }
 
+func TestWatchBuildsUnsupportedField(t *testing.T) {
+	storage := REST{&test.BuildRegistry{}, &record.FakeRecorder{}}
+	field := labels.SelectorFromSet(labels.Set{"spec.strategy": "Docker"})
+	if _, err := storage.Watch(kapi.NewDefaultContext(), labels.Everything(), field, "0"); !errors.IsBadRequest(err) {
+		t.Errorf("Expected a bad request for a field that cannot be selected, got %v", err)
+	}
+}
+
 func TestListEmptyBuildList(t *testing.T) {
 	mockRegistry := test.BuildRegistry{Builds: &api.BuildList{ListMeta: kapi.ListMeta{ResourceVersion: "1"}}}
-	storage := REST{&mockRegistry}
+	storage := REST{&mockRegistry, &record.FakeRecorder{}}
 	builds, err := storage.List(kapi.NewDefaultContext(), labels.Everything(), labels.Everything())
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
@@ -172,7 +181,7 @@ func TestListBuilds(t *testing.T) {
 
 func TestBuildDecode(t *testing.T) {
 	mockRegistry := test.BuildRegistry{}
-	storage := REST{&mockRegistry}
+	storage := REST{&mockRegistry, &record.FakeRecorder{}}
 	build := &api.Build{
 		ObjectMeta: kapi.ObjectMeta{
 			Name: "foo",
@@ -234,7 +243,7 @@ func TestBuildParsing(t *testing.T) {
 
 func TestCreateBuild(t *testing.T) {
 	mockRegistry := test.BuildRegistry{}
-	storage := REST{&mockRegistry}
+	storage := REST{&mockRegistry, &record.FakeRecorder{}}
 	build := mockBuild()
 	channel, err := storage.Create(kapi.NewDefaultContext(), build)
 	if err != nil {
@@ -257,11 +266,14 @@ func TestCreateBuild(t *testing.T) {
 	case <-time.After(time.Millisecond * 100):
 		t.Error("Unexpected timeout from async channel")
 	}
+	if e, a := []string{"created Created build " + build.Name}, storage.recorder.(*record.FakeRecorder).Events; !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected events %v, got %v", e, a)
+	}
 }
 
 func TestUpdateBuild(t *testing.T) {
-	mockRegistry := test.BuildRegistry{}
-	storage := REST{&mockRegistry}
+	mockRegistry := test.BuildRegistry{Build: mockBuild()}
+	storage := REST{&mockRegistry, &record.FakeRecorder{}}
 	build := mockBuild()
 	channel, err := storage.Update(kapi.NewDefaultContext(), build)
 	if err != nil {
@@ -288,7 +300,7 @@ func TestUpdateBuild(t *testing.T) {
 
 func TestUpdateBuildError(t *testing.T) {
 	mockRegistry := test.BuildRegistry{Err: fmt.Errorf("Update error")}
-	storage := REST{&mockRegistry}
+	storage := REST{&mockRegistry, &record.FakeRecorder{}}
 	build := mockBuild()
 	channel, err := storage.Update(kapi.NewDefaultContext(), build)
 	if err != nil {
@@ -311,7 +323,7 @@ func TestUpdateBuildError(t *testing.T) {
 
 func TestBuildRESTValidatesCreate(t *testing.T) {
 	mockRegistry := test.BuildRegistry{}
-	storage := REST{&mockRegistry}
+	storage := REST{&mockRegistry, &record.FakeRecorder{}}
 	failureCases := map[string]api.Build{
 		"empty input": {
 			ObjectMeta: kapi.ObjectMeta{Name: "abc"},
@@ -331,7 +343,7 @@ func TestBuildRESTValidatesCreate(t *testing.T) {
 
 func TestBuildRESTValidatesUpdate(t *testing.T) {
 	mockRegistry := test.BuildRegistry{}
-	storage := REST{&mockRegistry}
+	storage := REST{&mockRegistry, &record.FakeRecorder{}}
 	failureCases := map[string]api.Build{
 		"empty ID": {
 			ObjectMeta: kapi.ObjectMeta{Name: ""},
@@ -409,7 +421,7 @@ func TestCreateBuildConflictingNamespace(t *testing.T) {
 
 func TestUpdateBuildConflictingNamespace(t *testing.T) {
 	mockRegistry := test.BuildRegistry{}
-	storage := REST{&mockRegistry}
+	storage := REST{&mockRegistry, &record.FakeRecorder{}}
 
 	build := mockBuild()
 	channel, err := storage.Update(kapi.WithNamespace(kapi.NewContext(), "legal-name"), build)
@@ -11,6 +11,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
+	"github.com/openshift/origin/pkg/api/selectable"
 	"github.com/openshift/origin/pkg/build/api"
 	"github.com/openshift/origin/pkg/build/api/validation"
 )
@@ -40,7 +41,10 @@ func (r *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime
 	if err != nil {
 		return nil, err
 	}
-	return builds, err
+	if err := selectable.FilterList(builds, selector, fields); err != nil {
+		return nil, err
+	}
+	return builds, nil
 }
 
 // Get obtains the BuildConfig specified by its id.
@@ -99,15 +103,24 @@ func (r *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 	}
 
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
-		err := r.registry.UpdateBuildConfig(ctx, buildConfig)
+		existing, err := r.registry.GetBuildConfig(ctx, buildConfig.Name)
 		if err != nil {
 			return nil, err
 		}
+		if errs := validation.ValidateBuildConfigUpdate(buildConfig, existing); len(errs) > 0 {
+			return nil, errors.NewInvalid("buildConfig", buildConfig.Name, errs)
+		}
+		if err := r.registry.UpdateBuildConfig(ctx, buildConfig); err != nil {
+			return nil, err
+		}
 		return buildConfig, nil
 	}), nil
 }
 
 // Watch begins watching for new, changed, or deleted BuildConfigs.
 func (r *REST) Watch(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	if err := selectable.ValidateFields(&api.BuildConfig{}, field); err != nil {
+		return nil, err
+	}
 	return r.registry.WatchBuildConfigs(ctx, label, field, resourceVersion)
 }
@@ -281,7 +281,7 @@ func mockBuildConfig() *api.BuildConfig {
 }
 
 func TestUpdateBuildConfig(t *testing.T) {
-	mockRegistry := test.BuildConfigRegistry{}
+	mockRegistry := test.BuildConfigRegistry{BuildConfig: mockBuildConfig()}
 	storage := REST{&mockRegistry}
 	buildConfig := mockBuildConfig()
 	channel, err := storage.Update(kapi.NewDefaultContext(), buildConfig)
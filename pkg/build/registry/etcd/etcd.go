@@ -12,7 +12,9 @@ import (
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kubeetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/etcd"
+	"github.com/openshift/origin/pkg/api/selectable"
 	"github.com/openshift/origin/pkg/build/api"
+	etcdutil "github.com/openshift/origin/pkg/util/etcd"
 )
 
 const (
@@ -59,6 +61,21 @@ func (r *Etcd) ListBuilds(ctx kapi.Context, selector labels.Selector) (*api.Buil
 	return &allBuilds, nil
 }
 
+// ListBuildsPage retrieves a page of up to limit builds that match label and field, which continues the list
+// continueToken was returned with.
+func (r *Etcd) ListBuildsPage(ctx kapi.Context, label, field labels.Selector, limit int, continueToken string) (*api.BuildList, error) {
+	list := api.BuildList{}
+	matches := func(obj runtime.Object) bool {
+		return selectable.Matches(obj, label, field)
+	}
+	next, err := etcdutil.ExtractPageToList(r.EtcdHelper, makeBuildListKey(ctx), &list, matches, limit, continueToken)
+	if err != nil {
+		return nil, err
+	}
+	list.Continue = next
+	return &list, nil
+}
+
 // WatchBuilds begins watching for new, changed, or deleted Builds.
 func (r *Etcd) WatchBuilds(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
 	version, err := ktools.ParseWatchResourceVersion(resourceVersion, "build")
@@ -72,11 +89,7 @@ func (r *Etcd) WatchBuilds(ctx kapi.Context, label, field labels.Selector, resou
 			glog.Errorf("Unexpected object during build watch: %#v", obj)
 			return false
 		}
-		fields := labels.Set{
-			"name":    build.Name,
-			"status":  string(build.Status),
-			"podName": build.PodName,
-		}
+		fields := api.BuildToSelectableFields(build)
 		return label.Matches(labels.Set(build.Labels)) && field.Matches(fields)
 	})
 }
@@ -111,8 +124,7 @@ func (r *Etcd) UpdateBuild(ctx kapi.Context, build *api.Build) error {
 	if err != nil {
 		return err
 	}
-	err = r.SetObj(key, build)
-	return etcderr.InterpretUpdateError(err, "build", build.Name)
+	return etcdutil.UpdateObj(r.EtcdHelper, key, build, "build", build.Name)
 }
 
 // DeleteBuild deletes a Build specified by its ID.
@@ -180,8 +192,7 @@ func (r *Etcd) UpdateBuildConfig(ctx kapi.Context, config *api.BuildConfig) erro
 	if err != nil {
 		return err
 	}
-	err = r.SetObj(key, config)
-	return etcderr.InterpretUpdateError(err, "buildConfig", config.Name)
+	return etcdutil.UpdateObj(r.EtcdHelper, key, config, "buildConfig", config.Name)
 }
 
 // DeleteBuildConfig deletes a BuildConfig specified by its ID.
@@ -207,9 +218,7 @@ func (r *Etcd) WatchBuildConfigs(ctx kapi.Context, label, field labels.Selector,
 			glog.Errorf("Unexpected object during buildConfig watch: %#v", obj)
 			return false
 		}
-		fields := labels.Set{
-			"name": buildConfig.Name,
-		}
+		fields := api.BuildConfigToSelectableFields(buildConfig)
 		return label.Matches(labels.Set(buildConfig.Labels)) && field.Matches(fields)
 	})
 }
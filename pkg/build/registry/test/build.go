@@ -19,6 +19,10 @@ func (r *BuildRegistry) ListBuilds(ctx kapi.Context, labels labels.Selector) (*b
 	return r.Builds, r.Err
 }
 
+func (r *BuildRegistry) ListBuildsPage(ctx kapi.Context, label, field labels.Selector, limit int, continueToken string) (*buildapi.BuildList, error) {
+	return r.Builds, r.Err
+}
+
 func (r *BuildRegistry) GetBuild(ctx kapi.Context, id string) (*buildapi.Build, error) {
 	return r.Build, r.Err
 }
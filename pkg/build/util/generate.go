@@ -18,10 +18,11 @@ func GenerateBuildFromConfig(bc *buildapi.BuildConfig, r *buildapi.SourceRevisio
 
 	b := &buildapi.Build{
 		Parameters: buildapi.BuildParameters{
-			Source:   bcCopy.Parameters.Source,
-			Strategy: bcCopy.Parameters.Strategy,
-			Output:   bcCopy.Parameters.Output,
-			Revision: r,
+			Source:                    bcCopy.Parameters.Source,
+			Strategy:                  bcCopy.Parameters.Strategy,
+			Output:                    bcCopy.Parameters.Output,
+			Revision:                  r,
+			CompletionDeadlineSeconds: bcCopy.Parameters.CompletionDeadlineSeconds,
 		},
 		ObjectMeta: kapi.ObjectMeta{
 			Labels: map[string]string{buildapi.BuildConfigLabel: bcCopy.Name},
@@ -38,7 +38,12 @@ func TestGenerateBuildFromConfig(t *testing.T) {
 			Commit: "abcd",
 		},
 	}
+	deadline := int64(600)
+	bc.Parameters.CompletionDeadlineSeconds = &deadline
 	build := GenerateBuildFromConfig(bc, revision, nil)
+	if build.Parameters.CompletionDeadlineSeconds == nil || *build.Parameters.CompletionDeadlineSeconds != deadline {
+		t.Errorf("Build completion deadline does not match BuildConfig completion deadline")
+	}
 	if !reflect.DeepEqual(source, build.Parameters.Source) {
 		t.Errorf("Build source does not match BuildConfig source")
 	}
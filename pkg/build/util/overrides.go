@@ -0,0 +1,86 @@
+package util
+
+import (
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	buildapi "github.com/openshift/origin/pkg/build/api"
+)
+
+// ApplyBuildOverrides applies the overrides a trigger supplied to a build generated from config, after
+// checking that the AllowedOverrides of config permit each of them.  Environment variables replace the
+// variables of the same name of the build strategy or are appended to them; a ref replaces the ref of the git
+// source and a commit is recorded as the revision to build.
+func ApplyBuildOverrides(config *buildapi.BuildConfig, build *buildapi.Build, overrides *buildapi.BuildOverrides) error {
+	if overrides == nil {
+		return nil
+	}
+	policy := config.AllowedOverrides
+	if policy == nil {
+		policy = &buildapi.BuildOverridePolicy{}
+	}
+
+	if len(overrides.Env) > 0 {
+		allowed := map[string]bool{}
+		for _, name := range policy.Env {
+			allowed[name] = true
+		}
+		for _, env := range overrides.Env {
+			if !allowed[env.Name] {
+				return fmt.Errorf("BuildConfig %s does not allow the environment variable %q to be overridden", config.Name, env.Name)
+			}
+		}
+		env, err := strategyEnv(&build.Parameters.Strategy)
+		if err != nil {
+			return err
+		}
+		*env = mergeEnv(*env, overrides.Env)
+	}
+
+	if len(overrides.Ref) > 0 || len(overrides.Commit) > 0 {
+		if !policy.Ref {
+			return fmt.Errorf("BuildConfig %s does not allow the ref or commit to be overridden", config.Name)
+		}
+		if build.Parameters.Source.Git == nil {
+			return fmt.Errorf("BuildConfig %s does not build from a git source", config.Name)
+		}
+	}
+	if len(overrides.Ref) > 0 {
+		build.Parameters.Source.Git.Ref = overrides.Ref
+	}
+	if len(overrides.Commit) > 0 {
+		build.Parameters.Revision = &buildapi.SourceRevision{
+			Type: buildapi.BuildSourceGit,
+			Git:  &buildapi.GitSourceRevision{Commit: overrides.Commit},
+		}
+	}
+	return nil
+}
+
+// strategyEnv returns the environment variables of the strategy, which only the STI and Custom strategies take.
+func strategyEnv(strategy *buildapi.BuildStrategy) (*[]kapi.EnvVar, error) {
+	switch {
+	case strategy.Type == buildapi.STIBuildStrategyType && strategy.STIStrategy != nil:
+		return &strategy.STIStrategy.Env, nil
+	case strategy.Type == buildapi.CustomBuildStrategyType && strategy.CustomStrategy != nil:
+		return &strategy.CustomStrategy.Env, nil
+	}
+	return nil, fmt.Errorf("the %s build strategy does not take environment variables", strategy.Type)
+}
+
+// mergeEnv returns env with the values of overrides replacing the variables of the same name.
+func mergeEnv(env, overrides []kapi.EnvVar) []kapi.EnvVar {
+	for _, override := range overrides {
+		found := false
+		for i := range env {
+			if env[i].Name == override.Name {
+				env[i].Value = override.Value
+				found = true
+			}
+		}
+		if !found {
+			env = append(env, override)
+		}
+	}
+	return env
+}
@@ -0,0 +1,76 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/openshift/origin/pkg/build/api"
+)
+
+func TestApplyBuildOverrides(t *testing.T) {
+	strategy := mockSTIStrategy()
+	strategy.STIStrategy.Env = []kapi.EnvVar{{Name: "MODE", Value: "release"}}
+	bc := mockBuildConfig(mockSource(), strategy, mockOutput())
+	bc.AllowedOverrides = &api.BuildOverridePolicy{Env: []string{"MODE", "BUILD_ID"}, Ref: true}
+
+	build := GenerateBuildFromConfig(bc, nil, nil)
+	overrides := &api.BuildOverrides{
+		Env:    []kapi.EnvVar{{Name: "MODE", Value: "debug"}, {Name: "BUILD_ID", Value: "42"}},
+		Ref:    "feature",
+		Commit: "abcd",
+	}
+	if err := ApplyBuildOverrides(bc, build, overrides); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedEnv := []kapi.EnvVar{{Name: "MODE", Value: "debug"}, {Name: "BUILD_ID", Value: "42"}}
+	if !reflect.DeepEqual(build.Parameters.Strategy.STIStrategy.Env, expectedEnv) {
+		t.Errorf("Expected env %v, got %v", expectedEnv, build.Parameters.Strategy.STIStrategy.Env)
+	}
+	if build.Parameters.Source.Git.Ref != "feature" || build.Parameters.Revision.Git.Commit != "abcd" {
+		t.Errorf("Expected the ref and commit to be overridden, got %#v %#v", build.Parameters.Source.Git, build.Parameters.Revision.Git)
+	}
+	if bc.Parameters.Strategy.STIStrategy.Env[0].Value != "release" || bc.Parameters.Source.Git.Ref != "test-tag" {
+		t.Errorf("Expected the BuildConfig to be unchanged, got %#v", bc.Parameters)
+	}
+}
+
+func TestApplyBuildOverridesNotAllowed(t *testing.T) {
+	tests := map[string]struct {
+		policy    *api.BuildOverridePolicy
+		strategy  api.BuildStrategy
+		overrides *api.BuildOverrides
+	}{
+		"no policy": {
+			strategy:  mockSTIStrategy(),
+			overrides: &api.BuildOverrides{Env: []kapi.EnvVar{{Name: "MODE", Value: "debug"}}},
+		},
+		"env not listed": {
+			policy:    &api.BuildOverridePolicy{Env: []string{"BUILD_ID"}, Ref: true},
+			strategy:  mockSTIStrategy(),
+			overrides: &api.BuildOverrides{Env: []kapi.EnvVar{{Name: "MODE", Value: "debug"}}},
+		},
+		"ref not allowed": {
+			policy:    &api.BuildOverridePolicy{Env: []string{"MODE"}},
+			strategy:  mockSTIStrategy(),
+			overrides: &api.BuildOverrides{Ref: "feature"},
+		},
+		"commit not allowed": {
+			policy:    &api.BuildOverridePolicy{Env: []string{"MODE"}},
+			strategy:  mockCustomStrategy(),
+			overrides: &api.BuildOverrides{Commit: "abcd"},
+		},
+		"docker strategy env": {
+			policy:    &api.BuildOverridePolicy{Env: []string{"MODE"}},
+			strategy:  mockDockerStrategy(),
+			overrides: &api.BuildOverrides{Env: []kapi.EnvVar{{Name: "MODE", Value: "debug"}}},
+		},
+	}
+	for name, test := range tests {
+		bc := mockBuildConfig(mockSource(), test.strategy, mockOutput())
+		bc.AllowedOverrides = test.policy
+		if err := ApplyBuildOverrides(bc, GenerateBuildFromConfig(bc, nil, nil), test.overrides); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -17,9 +17,10 @@ import (
 type Plugin interface {
 	// Method extracts build information and returns:
 	// - newly created build object or nil if default is to be created
+	// - overrides of the build parameters or nil, which the BuildConfig must allow
 	// - information whether to trigger the build itself
 	// - eventual error.
-	Extract(buildCfg *api.BuildConfig, secret, path string, req *http.Request) (*api.SourceRevision, bool, error)
+	Extract(buildCfg *api.BuildConfig, secret, path string, req *http.Request) (*api.SourceRevision, *api.BuildOverrides, bool, error)
 }
 
 // controller used for processing webhook requests.
@@ -66,7 +67,7 @@ func (c *controller) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		notFound(w, "Plugin ", uv.plugin, " not found")
 		return
 	}
-	revision, proceed, err := plugin.Extract(buildCfg, uv.secret, uv.path, req)
+	revision, overrides, proceed, err := plugin.Extract(buildCfg, uv.secret, uv.path, req)
 	if err != nil {
 		badRequest(w, err.Error())
 		return
@@ -75,6 +76,10 @@ func (c *controller) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 	build := util.GenerateBuildFromConfig(buildCfg, revision, nil)
+	if err := util.ApplyBuildOverrides(buildCfg, build, overrides); err != nil {
+		badRequest(w, err.Error())
+		return
+	}
 
 	if err := c.buildCreator.Create(uv.namespace, build); err != nil {
 		badRequest(w, err.Error())
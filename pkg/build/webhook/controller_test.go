@@ -48,15 +48,21 @@ type pathPlugin struct {
 	Path string
 }
 
-func (p *pathPlugin) Extract(buildCfg *api.BuildConfig, secret, path string, req *http.Request) (*api.SourceRevision, bool, error) {
+func (p *pathPlugin) Extract(buildCfg *api.BuildConfig, secret, path string, req *http.Request) (*api.SourceRevision, *api.BuildOverrides, bool, error) {
 	p.Path = path
-	return nil, true, nil
+	return nil, nil, true, nil
+}
+
+type overridePlugin struct{}
+
+func (*overridePlugin) Extract(buildCfg *api.BuildConfig, secret, path string, req *http.Request) (*api.SourceRevision, *api.BuildOverrides, bool, error) {
+	return nil, &api.BuildOverrides{Ref: "feature"}, true, nil
 }
 
 type errPlugin struct{}
 
-func (*errPlugin) Extract(buildCfg *api.BuildConfig, secret, path string, req *http.Request) (*api.SourceRevision, bool, error) {
-	return nil, true, errors.New("Plugin error!")
+func (*errPlugin) Extract(buildCfg *api.BuildConfig, secret, path string, req *http.Request) (*api.SourceRevision, *api.BuildOverrides, bool, error) {
+	return nil, nil, true, errors.New("Plugin error!")
 }
 
 func TestParseUrlError(t *testing.T) {
@@ -166,6 +172,25 @@ func TestInvokeWebhookErrorBuildConfig(t *testing.T) {
 	}
 }
 
+func TestInvokeWebhookOverridesNotAllowed(t *testing.T) {
+	server := httptest.NewServer(NewController(&okBuildConfigGetter{}, &okBuildCreator{}, map[string]Plugin{
+		"overridePlugin": &overridePlugin{},
+	}))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/build100/secret101/overridePlugin",
+		"application/json", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusBadRequest ||
+		!strings.Contains(string(body), "does not allow") {
+		t.Errorf("Wrong response code, expecting 400, got %s: %s!", resp.Status,
+			string(body))
+	}
+}
+
 func TestInvokeWebhookErrorGetConfig(t *testing.T) {
 	server := httptest.NewServer(NewController(&errorBuildConfigGetter{}, &okBuildCreator{}, nil))
 	defer server.Close()
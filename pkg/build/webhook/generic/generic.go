@@ -20,8 +20,9 @@ func New() *WebHookPlugin {
 	return &WebHookPlugin{}
 }
 
-// Extract services generic webhooks.
-func (p *WebHookPlugin) Extract(buildCfg *api.BuildConfig, secret, path string, req *http.Request) (revision *api.SourceRevision, proceed bool, err error) {
+// Extract services generic webhooks.  The payload may set environment variables of the build, and a ref other
+// than the one of the BuildConfig is built rather than skipped if the BuildConfig allows the ref to be overridden.
+func (p *WebHookPlugin) Extract(buildCfg *api.BuildConfig, secret, path string, req *http.Request) (revision *api.SourceRevision, overrides *api.BuildOverrides, proceed bool, err error) {
 	trigger, ok := webhook.FindTriggerPolicy(api.GenericWebHookBuildTriggerType, buildCfg)
 	if !ok {
 		err = fmt.Errorf("BuildConfig %s does not support the Generic webhook trigger type", buildCfg.Name)
@@ -37,18 +38,30 @@ func (p *WebHookPlugin) Extract(buildCfg *api.BuildConfig, secret, path string,
 	if req.Body != nil {
 		body, err := ioutil.ReadAll(req.Body)
 		if err != nil {
-			return nil, false, err
+			return nil, nil, false, err
 		}
 		if len(body) == 0 {
-			return revision, true, nil
+			return revision, nil, true, nil
 		}
 		var data api.GenericWebHookEvent
 		if err = json.Unmarshal(body, &data); err != nil {
-			return nil, false, err
+			return nil, nil, false, err
+		}
+		if len(data.Env) > 0 {
+			overrides = &api.BuildOverrides{Env: data.Env}
+		}
+		if data.Git == nil {
+			return nil, overrides, true, nil
 		}
 		if !webhook.GitRefMatches(data.Git.Ref, buildCfg.Parameters.Source.Git.Ref) {
-			glog.V(2).Infof("Skipping build for '%s'.  Branch reference from '%s' does not match configuration", buildCfg, data)
-			return nil, false, nil
+			if buildCfg.AllowedOverrides == nil || !buildCfg.AllowedOverrides.Ref {
+				glog.V(2).Infof("Skipping build for '%s'.  Branch reference from '%s' does not match configuration", buildCfg, data)
+				return nil, nil, false, nil
+			}
+			if overrides == nil {
+				overrides = &api.BuildOverrides{}
+			}
+			overrides.Ref = strings.TrimPrefix(data.Git.Ref, "refs/heads/")
 		}
 		revision = &api.SourceRevision{
 			Type: api.BuildSourceGit,
@@ -60,7 +73,7 @@ func (p *WebHookPlugin) Extract(buildCfg *api.BuildConfig, secret, path string,
 			},
 		}
 	}
-	return revision, true, nil
+	return revision, overrides, true, nil
 }
 
 func verifyRequest(req *http.Request) error {
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"io/ioutil"
 	"net/http"
+	"reflect"
 	"strings"
 	"testing"
 
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/openshift/origin/pkg/build/api"
 )
 
@@ -16,7 +18,11 @@ func GivenRequest(method string) *http.Request {
 }
 
 func GivenRequestWithPayload(t *testing.T) *http.Request {
-	data, err := ioutil.ReadFile("fixtures/push-git.json")
+	return GivenRequestWithFixture(t, "push-git.json")
+}
+
+func GivenRequestWithFixture(t *testing.T, fixture string) *http.Request {
+	data, err := ioutil.ReadFile("fixtures/" + fixture)
 	if err != nil {
 		t.Errorf("Error reading setup data: %v", err)
 		return nil
@@ -109,7 +115,7 @@ func TestExtractWithEmptyPayload(t *testing.T) {
 		},
 	}
 	plugin := New()
-	revision, proceed, err := plugin.Extract(buildConfig, "secret100", "", req)
+	revision, _, proceed, err := plugin.Extract(buildConfig, "secret100", "", req)
 	if err != nil {
 		t.Errorf("Expected to be able to trigger a build without a payload error: %s", err)
 	}
@@ -143,7 +149,7 @@ func TestExtractWithUnmatchedRefGitPayload(t *testing.T) {
 		},
 	}
 	plugin := New()
-	build, proceed, err := plugin.Extract(buildConfig, "secret100", "", req)
+	build, _, proceed, err := plugin.Extract(buildConfig, "secret100", "", req)
 
 	if err != nil {
 		t.Errorf("Unexpected error when triggering build: %s", err)
@@ -179,7 +185,7 @@ func TestExtractWithGitPayload(t *testing.T) {
 	}
 	plugin := New()
 
-	revision, proceed, err := plugin.Extract(buildConfig, "secret100", "", req)
+	revision, _, proceed, err := plugin.Extract(buildConfig, "secret100", "", req)
 
 	if err != nil {
 		t.Errorf("Expected to be able to trigger a build without a payload error: %s", err)
@@ -191,3 +197,47 @@ func TestExtractWithGitPayload(t *testing.T) {
 		t.Error("Expected the 'revision' return value to not be nil")
 	}
 }
+
+func TestExtractWithOverrides(t *testing.T) {
+	buildConfig := &api.BuildConfig{
+		Triggers: []api.BuildTriggerPolicy{
+			{
+				Type: api.GenericWebHookBuildTriggerType,
+				GenericWebHook: &api.WebHookTrigger{
+					Secret: "secret100",
+				},
+			},
+		},
+		Parameters: api.BuildParameters{
+			Source: api.BuildSource{
+				Type: api.BuildSourceGit,
+				Git: &api.GitBuildSource{
+					Ref: "master",
+				},
+			},
+			Strategy: api.BuildStrategy{},
+		},
+	}
+	plugin := New()
+
+	_, _, proceed, err := plugin.Extract(buildConfig, "secret100", "", GivenRequestWithFixture(t, "push-git-env.json"))
+	if err != nil || proceed {
+		t.Errorf("Expected a build of another ref to be skipped unless allowed, got %v %v", proceed, err)
+	}
+
+	buildConfig.AllowedOverrides = &api.BuildOverridePolicy{Env: []string{"MODE"}, Ref: true}
+	revision, overrides, proceed, err := plugin.Extract(buildConfig, "secret100", "", GivenRequestWithFixture(t, "push-git-env.json"))
+	if err != nil || !proceed {
+		t.Fatalf("Expected the build to proceed, got %v %v", proceed, err)
+	}
+	if revision == nil || revision.Git.Commit != "9bdc3a26ff933b32f3e558636b58aea86a69f051" {
+		t.Errorf("Unexpected revision %#v", revision)
+	}
+	expected := &api.BuildOverrides{
+		Env: []kapi.EnvVar{{Name: "MODE", Value: "debug"}},
+		Ref: "feature",
+	}
+	if !reflect.DeepEqual(overrides, expected) {
+		t.Errorf("Expected overrides %#v, got %#v", expected, overrides)
+	}
+}
@@ -222,7 +222,7 @@ func TestExtractForAPingEvent(t *testing.T) {
 	context := setup(t, "pingevent.json", "ping")
 
 	//execute
-	_, proceed, err := context.plugin.Extract(context.buildCfg, "secret101", context.path, context.req)
+	_, _, proceed, err := context.plugin.Extract(context.buildCfg, "secret101", context.path, context.req)
 
 	//validation
 	if err != nil {
@@ -238,7 +238,7 @@ func TestExtractProvidesValidBuildForAPushEvent(t *testing.T) {
 	context := setup(t, "pushevent.json", "push")
 
 	//execute
-	revision, proceed, err := context.plugin.Extract(context.buildCfg, "secret101", context.path, context.req)
+	revision, _, proceed, err := context.plugin.Extract(context.buildCfg, "secret101", context.path, context.req)
 
 	//validation
 	if err != nil {
@@ -262,7 +262,7 @@ func TestExtractProvidesValidBuildForAPushEventOtherThanMaster(t *testing.T) {
 	context.buildCfg.Parameters.Source.Git.Ref = "my_other_branch"
 
 	//execute
-	revision, proceed, err := context.plugin.Extract(context.buildCfg, "secret101", context.path, context.req)
+	revision, _, proceed, err := context.plugin.Extract(context.buildCfg, "secret101", context.path, context.req)
 
 	//validation
 	if err != nil {
@@ -286,7 +286,7 @@ func TestExtractSkipsBuildForUnmatchedBranches(t *testing.T) {
 	context.buildCfg.Parameters.Source.Git.Ref = "adfj32qrafdavckeaewra"
 
 	//execute
-	_, proceed, _ := context.plugin.Extract(context.buildCfg, "secret101", context.path, context.req)
+	_, _, proceed, _ := context.plugin.Extract(context.buildCfg, "secret101", context.path, context.req)
 	if proceed {
 		t.Errorf("Expecting to not continue from this event because the branch is not for this buildConfig '%s'", context.buildCfg.Parameters.Source.Git.Ref)
 	}
@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"sync"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	osclient "github.com/openshift/origin/pkg/client"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+// Informers holds one SharedInformer of all the objects in every namespace for each kind the controllers
+// of the master watch, so the controllers share their watches and stores.
+type Informers struct {
+	Client     osclient.Interface
+	KubeClient kclient.Interface
+
+	lock      sync.Mutex
+	informers map[string]*SharedInformer
+}
+
+// NewInformers returns the informers of the objects listed and watched with client and kubeClient.
+func NewInformers(client osclient.Interface, kubeClient kclient.Interface) *Informers {
+	return &Informers{Client: client, KubeClient: kubeClient, informers: map[string]*SharedInformer{}}
+}
+
+// Builds returns the informer of Builds.
+func (i *Informers) Builds() *SharedInformer {
+	return i.informer("builds", &buildapi.Build{}, func() listWatch {
+		builds := i.Client.Builds(kapi.NamespaceAll)
+		return listWatch{
+			list: func() (runtime.Object, error) {
+				return builds.List(labels.Everything(), labels.Everything())
+			},
+			watch: func(resourceVersion string) (watch.Interface, error) {
+				return builds.Watch(labels.Everything(), labels.Everything(), resourceVersion)
+			},
+		}
+	})
+}
+
+// BuildConfigs returns the informer of BuildConfigs.
+func (i *Informers) BuildConfigs() *SharedInformer {
+	return i.informer("buildConfigs", &buildapi.BuildConfig{}, func() listWatch {
+		configs := i.Client.BuildConfigs(kapi.NamespaceAll)
+		return listWatch{
+			list: func() (runtime.Object, error) {
+				return configs.List(labels.Everything(), labels.Everything())
+			},
+			watch: func(resourceVersion string) (watch.Interface, error) {
+				return configs.Watch(labels.Everything(), labels.Everything(), resourceVersion)
+			},
+		}
+	})
+}
+
+// DeploymentConfigs returns the informer of DeploymentConfigs.
+func (i *Informers) DeploymentConfigs() *SharedInformer {
+	return i.informer("deploymentConfigs", &deployapi.DeploymentConfig{}, func() listWatch {
+		configs := i.Client.DeploymentConfigs(kapi.NamespaceAll)
+		return listWatch{
+			list: func() (runtime.Object, error) {
+				return configs.List(labels.Everything(), labels.Everything())
+			},
+			watch: func(resourceVersion string) (watch.Interface, error) {
+				return configs.Watch(labels.Everything(), labels.Everything(), resourceVersion)
+			},
+		}
+	})
+}
+
+// ImageRepositories returns the informer of ImageRepositories.
+func (i *Informers) ImageRepositories() *SharedInformer {
+	return i.informer("imageRepositories", &imageapi.ImageRepository{}, func() listWatch {
+		repos := i.Client.ImageRepositories(kapi.NamespaceAll)
+		return listWatch{
+			list: func() (runtime.Object, error) {
+				return repos.List(labels.Everything(), labels.Everything())
+			},
+			watch: func(resourceVersion string) (watch.Interface, error) {
+				return repos.Watch(labels.Everything(), labels.Everything(), resourceVersion)
+			},
+		}
+	})
+}
+
+// ReplicationControllers returns the informer of ReplicationControllers, which include deployments.
+func (i *Informers) ReplicationControllers() *SharedInformer {
+	return i.informer("replicationControllers", &kapi.ReplicationController{}, func() listWatch {
+		controllers := i.KubeClient.ReplicationControllers(kapi.NamespaceAll)
+		return listWatch{
+			list: func() (runtime.Object, error) {
+				return controllers.List(labels.Everything())
+			},
+			watch: func(resourceVersion string) (watch.Interface, error) {
+				return controllers.Watch(labels.Everything(), labels.Everything(), resourceVersion)
+			},
+		}
+	})
+}
+
+// informer returns the informer of resource, creating it with the lister and watcher returned by lw if
+// it does not exist yet.
+func (i *Informers) informer(resource string, expectedType interface{}, lw func() listWatch) *SharedInformer {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if i.informers == nil {
+		i.informers = map[string]*SharedInformer{}
+	}
+	informer, ok := i.informers[resource]
+	if !ok {
+		l := lw()
+		informer = NewSharedInformer(&l, expectedType)
+		i.informers[resource] = informer
+	}
+	return informer
+}
+
+// listWatch is a kcache.ListerWatcher implemented by functions.
+type listWatch struct {
+	list  func() (runtime.Object, error)
+	watch func(resourceVersion string) (watch.Interface, error)
+}
+
+// List implements kcache.ListerWatcher
+func (lw *listWatch) List() (runtime.Object, error) {
+	return lw.list()
+}
+
+// Watch implements kcache.ListerWatcher
+func (lw *listWatch) Watch(resourceVersion string) (watch.Interface, error) {
+	return lw.watch(resourceVersion)
+}
@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"sync"
+
+	kcache "github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// SharedInformer lists and watches one kind of object with a single reflector, keeps the objects in an
+// in-memory store and passes every change on to the queues of the controllers that share it.  Controllers
+// that watch the same kind through a SharedInformer cost the master one watch instead of one each.
+type SharedInformer struct {
+	lw           kcache.ListerWatcher
+	expectedType interface{}
+
+	// lock serializes the changes made by the reflector with adding and removing listeners, so a listener
+	// sees every change made after it was seeded with the contents of the store
+	lock      sync.Mutex
+	store     kcache.Store
+	listeners []kcache.Store
+	once      sync.Once
+}
+
+// NewSharedInformer returns an informer of the objects of expectedType listed and watched by lw.  The
+// informer starts once a store or queue is requested from it.
+func NewSharedInformer(lw kcache.ListerWatcher, expectedType interface{}) *SharedInformer {
+	return &SharedInformer{
+		lw:           lw,
+		expectedType: expectedType,
+		store:        kcache.NewStore(kcache.MetaNamespaceKeyFunc),
+	}
+}
+
+// Store returns the store of the informer.  The store is shared by every user of the informer and must
+// not be modified.
+func (i *SharedInformer) Store() kcache.Store {
+	i.run()
+	return i.store
+}
+
+// Queue returns a queue that receives the objects in the store of the informer and every change to them
+// until stop is closed.
+func (i *SharedInformer) Queue(stop <-chan struct{}) *WorkQueue {
+	queue := NewWorkQueue(kcache.MetaNamespaceKeyFunc)
+	i.addListener(queue, stop)
+	i.run()
+	return queue
+}
+
+// addListener passes every change to the informer on to listener until stop is closed.
+func (i *SharedInformer) addListener(listener kcache.Store, stop <-chan struct{}) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	listener.Replace(i.store.List())
+	i.listeners = append(i.listeners, listener)
+	if stop == nil {
+		return
+	}
+	go func() {
+		defer util.HandleCrash()
+		<-stop
+		i.removeListener(listener)
+	}()
+}
+
+// removeListener stops passing changes on to listener.
+func (i *SharedInformer) removeListener(listener kcache.Store) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	for j := range i.listeners {
+		if i.listeners[j] == listener {
+			i.listeners = append(i.listeners[:j], i.listeners[j+1:]...)
+			return
+		}
+	}
+}
+
+// run starts the reflector of the informer if it has not been started yet.  The informer runs for the
+// lifetime of the process, since its listeners come and go.
+func (i *SharedInformer) run() {
+	i.once.Do(func() {
+		kcache.NewReflector(i.lw, i.expectedType, &informerStore{i}).Run()
+	})
+}
+
+// informerStore applies the changes of a reflector to the store and listeners of an informer.
+type informerStore struct {
+	informer *SharedInformer
+}
+
+// Add implements kcache.Store
+func (s *informerStore) Add(obj interface{}) error {
+	return s.apply(func(store kcache.Store) error { return store.Add(obj) })
+}
+
+// Update implements kcache.Store
+func (s *informerStore) Update(obj interface{}) error {
+	return s.apply(func(store kcache.Store) error { return store.Update(obj) })
+}
+
+// Delete implements kcache.Store
+func (s *informerStore) Delete(obj interface{}) error {
+	return s.apply(func(store kcache.Store) error { return store.Delete(obj) })
+}
+
+// Replace implements kcache.Store
+func (s *informerStore) Replace(list []interface{}) error {
+	// every store takes ownership of the list it is given
+	return s.apply(func(store kcache.Store) error { return store.Replace(append([]interface{}{}, list...)) })
+}
+
+// List implements kcache.Store
+func (s *informerStore) List() []interface{} {
+	return s.informer.store.List()
+}
+
+// Get implements kcache.Store
+func (s *informerStore) Get(obj interface{}) (interface{}, bool, error) {
+	return s.informer.store.Get(obj)
+}
+
+// GetByKey implements kcache.Store
+func (s *informerStore) GetByKey(key string) (interface{}, bool, error) {
+	return s.informer.store.GetByKey(key)
+}
+
+// apply makes a change to the store of the informer and then to each of its listeners.  The first error
+// is returned.
+func (s *informerStore) apply(change func(kcache.Store) error) error {
+	i := s.informer
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	err := change(i.store)
+	for _, listener := range i.listeners {
+		if listenerErr := change(listener); listenerErr != nil && err == nil {
+			err = listenerErr
+		}
+	}
+	return err
+}
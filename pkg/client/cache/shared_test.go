@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	osclient "github.com/openshift/origin/pkg/client"
+)
+
+// testLW lists pods once and then serves a single watch.
+type testLW struct {
+	lock    sync.Mutex
+	lists   int
+	watches int
+	list    *kapi.PodList
+	watcher *watch.FakeWatcher
+}
+
+func (lw *testLW) List() (runtime.Object, error) {
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+	lw.lists++
+	return lw.list, nil
+}
+
+func (lw *testLW) Watch(resourceVersion string) (watch.Interface, error) {
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+	lw.watches++
+	return lw.watcher, nil
+}
+
+func testPod(name string) *kapi.Pod {
+	return &kapi.Pod{ObjectMeta: kapi.ObjectMeta{Namespace: "default", Name: name, ResourceVersion: "2"}}
+}
+
+func waitFor(t *testing.T, what string, condition func() bool) {
+	for i := 0; i < 200; i++ {
+		if condition() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %s", what)
+}
+
+func TestSharedInformerSharesOneWatch(t *testing.T) {
+	lw := &testLW{
+		list:    &kapi.PodList{ListMeta: kapi.ListMeta{ResourceVersion: "1"}, Items: []kapi.Pod{*testPod("first")}},
+		watcher: watch.NewFake(),
+	}
+	informer := NewSharedInformer(lw, &kapi.Pod{})
+
+	store := informer.Store()
+	waitFor(t, "the store to be listed", func() bool { return len(store.List()) == 1 })
+	stop := make(chan struct{})
+	first, second := informer.Queue(stop), informer.Queue(nil)
+	for _, queue := range []interface {
+		Pop() interface{}
+	}{first, second} {
+		if pod := queue.Pop().(*kapi.Pod); pod.Name != "first" {
+			t.Errorf("Expected a queue to receive the listed pod, got %#v", pod)
+		}
+	}
+
+	lw.watcher.Add(testPod("second"))
+	if pod := first.Pop().(*kapi.Pod); pod.Name != "second" {
+		t.Errorf("Expected the first queue to receive the added pod, got %#v", pod)
+	}
+	if pod := second.Pop().(*kapi.Pod); pod.Name != "second" {
+		t.Errorf("Expected the second queue to receive the added pod, got %#v", pod)
+	}
+	if _, exists, _ := store.GetByKey("default/second"); !exists {
+		t.Errorf("Expected the store to contain the added pod")
+	}
+
+	close(stop)
+	waitFor(t, "the stopped queue to be removed", func() bool {
+		informer.lock.Lock()
+		defer informer.lock.Unlock()
+		return len(informer.listeners) == 1
+	})
+	lw.watcher.Add(testPod("third"))
+	if pod := second.Pop().(*kapi.Pod); pod.Name != "third" {
+		t.Errorf("Expected the running queue to receive the added pod, got %#v", pod)
+	}
+	if items := first.List(); len(items) != 0 {
+		t.Errorf("Expected the stopped queue to receive no more pods, got %#v", items)
+	}
+
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+	if lw.lists != 1 || lw.watches != 1 {
+		t.Errorf("Expected one list and one watch, got %d lists and %d watches", lw.lists, lw.watches)
+	}
+}
+
+func TestInformersAreSharedByKind(t *testing.T) {
+	informers := NewInformers(&osclient.Fake{}, nil)
+	if informers.Builds() != informers.Builds() {
+		t.Errorf("Expected the informer of a kind to be shared")
+	}
+	if informers.Builds() == informers.BuildConfigs() {
+		t.Errorf("Expected each kind to have its own informer")
+	}
+}
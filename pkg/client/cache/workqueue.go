@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+
+	kcache "github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+)
+
+// WorkQueue is a Store that queues objects to be processed by key.  Like kcache.FIFO, an object changed
+// several times before it is processed is processed once, in its latest state.  Objects that failed to be
+// processed can be added back with AddIfNotPresent without replacing a newer state queued meanwhile.
+type WorkQueue struct {
+	lock sync.RWMutex
+	cond sync.Cond
+	// items holds the latest state of every queued key, and queue the keys in the order they were added
+	items   map[string]interface{}
+	queue   []string
+	keyFunc kcache.KeyFunc
+}
+
+// NewWorkQueue returns an empty queue of objects keyed by keyFunc.
+func NewWorkQueue(keyFunc kcache.KeyFunc) *WorkQueue {
+	q := &WorkQueue{
+		items:   map[string]interface{}{},
+		queue:   []string{},
+		keyFunc: keyFunc,
+	}
+	q.cond.L = &q.lock
+	return q
+}
+
+// Add queues obj, replacing the state of obj queued before.
+func (q *WorkQueue) Add(obj interface{}) error {
+	return q.add(obj, true)
+}
+
+// AddIfNotPresent queues obj unless a state of obj is queued already.
+func (q *WorkQueue) AddIfNotPresent(obj interface{}) error {
+	return q.add(obj, false)
+}
+
+// add queues obj.  A queued state of obj is replaced only if replace is true.
+func (q *WorkQueue) add(obj interface{}, replace bool) error {
+	key, err := q.keyFunc(obj)
+	if err != nil {
+		return fmt.Errorf("couldn't create key for object: %v", err)
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if _, exists := q.items[key]; exists {
+		if replace {
+			q.items[key] = obj
+		}
+		return nil
+	}
+	q.queue = append(q.queue, key)
+	q.items[key] = obj
+	q.cond.Broadcast()
+	return nil
+}
+
+// Update is the same as Add.
+func (q *WorkQueue) Update(obj interface{}) error {
+	return q.Add(obj)
+}
+
+// Delete removes obj from the queue.
+func (q *WorkQueue) Delete(obj interface{}) error {
+	key, err := q.keyFunc(obj)
+	if err != nil {
+		return fmt.Errorf("couldn't create key for object: %v", err)
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	delete(q.items, key)
+	return nil
+}
+
+// List returns the queued objects.
+func (q *WorkQueue) List() []interface{} {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+	list := make([]interface{}, 0, len(q.items))
+	for _, item := range q.items {
+		list = append(list, item)
+	}
+	return list
+}
+
+// Get returns the queued state of obj.
+func (q *WorkQueue) Get(obj interface{}) (interface{}, bool, error) {
+	key, err := q.keyFunc(obj)
+	if err != nil {
+		return nil, false, fmt.Errorf("couldn't create key for object: %v", err)
+	}
+	return q.GetByKey(key)
+}
+
+// GetByKey returns the queued object with key.
+func (q *WorkQueue) GetByKey(key string) (interface{}, bool, error) {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+	item, exists := q.items[key]
+	return item, exists, nil
+}
+
+// Replace replaces the contents of the queue with list.
+func (q *WorkQueue) Replace(list []interface{}) error {
+	items := map[string]interface{}{}
+	for _, item := range list {
+		key, err := q.keyFunc(item)
+		if err != nil {
+			return fmt.Errorf("couldn't create key for object: %v", err)
+		}
+		items[key] = item
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.items = items
+	q.queue = q.queue[:0]
+	for key := range items {
+		q.queue = append(q.queue, key)
+	}
+	if len(q.queue) > 0 {
+		q.cond.Broadcast()
+	}
+	return nil
+}
+
+// Pop waits until an object is queued and removes it from the queue.  Objects are returned in the order
+// they were first queued.
+func (q *WorkQueue) Pop() interface{} {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for {
+		for len(q.queue) == 0 {
+			q.cond.Wait()
+		}
+		key := q.queue[0]
+		q.queue = q.queue[1:]
+		item, ok := q.items[key]
+		if !ok {
+			// deleted after it was queued
+			continue
+		}
+		delete(q.items, key)
+		return item
+	}
+}
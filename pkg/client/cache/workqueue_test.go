@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"testing"
+)
+
+func TestWorkQueueAddReplacesQueuedState(t *testing.T) {
+	q := NewWorkQueue(keyFunc)
+	q.Add(cacheable{"a", 1})
+	q.Add(cacheable{"b", 1})
+	q.Add(cacheable{"a", 2})
+
+	if e, a := (cacheable{"a", 2}), q.Pop().(cacheable); e != a {
+		t.Errorf("expected %#v, got %#v", e, a)
+	}
+	if e, a := (cacheable{"b", 1}), q.Pop().(cacheable); e != a {
+		t.Errorf("expected %#v, got %#v", e, a)
+	}
+	if len(q.List()) != 0 {
+		t.Errorf("expected an empty queue, got %#v", q.List())
+	}
+}
+
+func TestWorkQueueAddIfNotPresentKeepsQueuedState(t *testing.T) {
+	q := NewWorkQueue(keyFunc)
+	q.Add(cacheable{"a", 2})
+	q.AddIfNotPresent(cacheable{"a", 1})
+	q.AddIfNotPresent(cacheable{"b", 1})
+
+	if e, a := (cacheable{"a", 2}), q.Pop().(cacheable); e != a {
+		t.Errorf("expected %#v, got %#v", e, a)
+	}
+	if e, a := (cacheable{"b", 1}), q.Pop().(cacheable); e != a {
+		t.Errorf("expected %#v, got %#v", e, a)
+	}
+}
+
+func TestWorkQueueDeleteSkipsObject(t *testing.T) {
+	q := NewWorkQueue(keyFunc)
+	q.Add(cacheable{"a", 1})
+	q.Add(cacheable{"b", 1})
+	q.Delete(cacheable{"a", 1})
+
+	if e, a := (cacheable{"b", 1}), q.Pop().(cacheable); e != a {
+		t.Errorf("expected %#v, got %#v", e, a)
+	}
+}
+
+func TestWorkQueueReplace(t *testing.T) {
+	q := NewWorkQueue(keyFunc)
+	q.Add(cacheable{"a", 1})
+	q.Replace([]interface{}{cacheable{"b", 1}})
+
+	if _, exists, _ := q.GetByKey("a"); exists {
+		t.Errorf("expected a to be removed")
+	}
+	if e, a := (cacheable{"b", 1}), q.Pop().(cacheable); e != a {
+		t.Errorf("expected %#v, got %#v", e, a)
+	}
+}
@@ -2,13 +2,16 @@ package client
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"path"
 	"runtime"
 	"strings"
 
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 
+	"github.com/openshift/origin/pkg/api/compact"
 	"github.com/openshift/origin/pkg/api/latest"
 	"github.com/openshift/origin/pkg/version"
 )
@@ -24,13 +27,19 @@ type Interface interface {
 	DeploymentsNamespacer
 	DeploymentConfigsNamespacer
 	RoutesNamespacer
+	RouteStatusUpdatesNamespacer
+	TemplatesNamespacer
+	TemplateInstancesNamespacer
 	UsersInterface
 	UserIdentityMappingsInterface
+	IdentitiesInterface
 	ProjectsInterface
+	ProjectRequestsInterface
 	PoliciesNamespacer
 	RolesNamespacer
 	RoleBindingsNamespacer
 	PolicyBindingsNamespacer
+	PolicyChangeReviewsNamespacer
 }
 
 func (c *Client) Builds(namespace string) BuildInterface {
@@ -75,6 +84,11 @@ func (c *Client) Routes(namespace string) RouteInterface {
 	return newRoutes(c, namespace)
 }
 
+// RouteStatusUpdates provides a REST client for RouteStatusUpdates
+func (c *Client) RouteStatusUpdates(namespace string) RouteStatusUpdateInterface {
+	return newRouteStatusUpdates(c, namespace)
+}
+
 // Users provides a REST client for User
 func (c *Client) Users() UserInterface {
 	return newUsers(c)
@@ -85,11 +99,31 @@ func (c *Client) UserIdentityMappings() UserIdentityMappingInterface {
 	return newUserIdentityMappings(c)
 }
 
+// Identities provides a REST client for Identity
+func (c *Client) Identities() IdentityInterface {
+	return newIdentities(c)
+}
+
 // Projects provides a REST client for Projects
 func (c *Client) Projects() ProjectInterface {
 	return newProjects(c)
 }
 
+// ProjectRequests provides a REST client for ProjectRequests
+func (c *Client) ProjectRequests() ProjectRequestInterface {
+	return newProjectRequests(c)
+}
+
+// Templates provides a REST client for Templates
+func (c *Client) Templates(namespace string) TemplateInterface {
+	return newTemplates(c, namespace)
+}
+
+// TemplateInstances provides a REST client for TemplateInstances
+func (c *Client) TemplateInstances(namespace string) TemplateInstanceInterface {
+	return newTemplateInstances(c, namespace)
+}
+
 // TemplateConfigs provides a REST client for TemplateConfig
 func (c *Client) TemplateConfigs(namespace string) TemplateConfigInterface {
 	return newTemplateConfigs(c, namespace)
@@ -111,6 +145,10 @@ func (c *Client) RoleBindings(namespace string) RoleBindingInterface {
 	return newRoleBindings(c, namespace)
 }
 
+func (c *Client) PolicyChangeReviews(namespace string) PolicyChangeReviewInterface {
+	return newPolicyChangeReviews(c, namespace)
+}
+
 // Client is an OpenShift client object
 type Client struct {
 	*kclient.RESTClient
@@ -128,9 +166,28 @@ func New(c *kclient.Config) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	if compact.IsCompact(config.Codec) {
+		transport, err := kclient.TransportFor(&config)
+		if err != nil {
+			return nil, err
+		}
+		client.Client = &http.Client{Transport: compact.NewTransport(transport)}
+	}
 	return &Client{client}, nil
 }
 
+// UseCompactEncoding sets config to ask the server for objects in the compact binary encoding, which is
+// cheaper than JSON for the server and the client to encode and decode, including the objects of watch
+// events.  Objects sent to the server are still JSON, and servers that do not support the compact encoding
+// respond with JSON, which the client still reads.
+func UseCompactEncoding(config *kclient.Config) error {
+	if err := SetOpenShiftDefaults(config); err != nil {
+		return err
+	}
+	config.Codec = compact.NewClientCodec(kapi.Scheme, config.Version)
+	return nil
+}
+
 func SetOpenShiftDefaults(config *kclient.Config) error {
 	if config.Prefix == "" {
 		config.Prefix = "/osapi"
@@ -151,7 +208,7 @@ func SetOpenShiftDefaults(config *kclient.Config) error {
 	if config.Codec == nil {
 		config.Codec = versionInterfaces.Codec
 	}
-	config.LegacyBehavior = (config.Version == "v1beta1")
+	config.LegacyBehavior = (config.Version == "v1beta1" || config.Version == "v1beta2")
 	return nil
 }
 
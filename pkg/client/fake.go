@@ -48,6 +48,18 @@ func (c *Fake) Routes(namespace string) RouteInterface {
 	return &FakeRoutes{Fake: c, Namespace: namespace}
 }
 
+func (c *Fake) RouteStatusUpdates(namespace string) RouteStatusUpdateInterface {
+	return &FakeRouteStatusUpdates{Fake: c, Namespace: namespace}
+}
+
+func (c *Fake) Templates(namespace string) TemplateInterface {
+	return &FakeTemplates{Fake: c, Namespace: namespace}
+}
+
+func (c *Fake) TemplateInstances(namespace string) TemplateInstanceInterface {
+	return &FakeTemplateInstances{Fake: c, Namespace: namespace}
+}
+
 func (c *Fake) Users() UserInterface {
 	return &FakeUsers{Fake: c}
 }
@@ -56,10 +68,18 @@ func (c *Fake) UserIdentityMappings() UserIdentityMappingInterface {
 	return &FakeUserIdentityMappings{Fake: c}
 }
 
+func (c *Fake) Identities() IdentityInterface {
+	return &FakeIdentities{Fake: c}
+}
+
 func (c *Fake) Projects() ProjectInterface {
 	return &FakeProjects{Fake: c}
 }
 
+func (c *Fake) ProjectRequests() ProjectRequestInterface {
+	return &FakeProjectRequests{Fake: c}
+}
+
 func (c *Fake) Policies(namespace string) PolicyInterface {
 	return &FakePolicies{Fake: c}
 }
@@ -75,3 +95,7 @@ func (c *Fake) RoleBindings(namespace string) RoleBindingInterface {
 func (c *Fake) PolicyBindings(namespace string) PolicyBindingInterface {
 	return &FakePolicyBindings{Fake: c}
 }
+
+func (c *Fake) PolicyChangeReviews(namespace string) PolicyChangeReviewInterface {
+	return &FakePolicyChangeReviews{Fake: c, Namespace: namespace}
+}
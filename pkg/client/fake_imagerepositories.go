@@ -39,6 +39,21 @@ func (c *FakeImageRepositories) Delete(name string) error {
 	return nil
 }
 
+func (c *FakeImageRepositories) Restore(name string) (*imageapi.ImageRepository, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "restore-imagerepository", Value: name})
+	return &imageapi.ImageRepository{}, nil
+}
+
+func (c *FakeImageRepositories) ListRestorable(label, field labels.Selector) (*imageapi.ImageRepositoryList, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "list-restorable-imagerepositories"})
+	return &imageapi.ImageRepositoryList{}, nil
+}
+
+func (c *FakeImageRepositories) Purge(name string) error {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "purge-imagerepository", Value: name})
+	return nil
+}
+
 func (c *FakeImageRepositories) Watch(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
 	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "watch-imagerepositories"})
 	return nil, nil
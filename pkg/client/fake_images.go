@@ -32,3 +32,8 @@ func (c *FakeImages) Delete(name string) error {
 	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "delete-image", Value: name})
 	return nil
 }
+
+func (c *FakeImages) Search(label labels.Selector) (*imageapi.ImageList, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "search-images"})
+	return &imageapi.ImageList{}, nil
+}
@@ -0,0 +1,17 @@
+package client
+
+import (
+	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
+)
+
+// FakePolicyChangeReviews implements PolicyChangeReviewInterface. Meant to be embedded into a struct to get a default
+// implementation. This makes faking out just the methods you want to test easier.
+type FakePolicyChangeReviews struct {
+	Fake      *Fake
+	Namespace string
+}
+
+func (c *FakePolicyChangeReviews) Create(review *authorizationapi.PolicyChangeReview) (*authorizationapi.PolicyChangeReview, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "create-policyChangeReview", Value: review})
+	return &authorizationapi.PolicyChangeReview{}, nil
+}
@@ -0,0 +1,12 @@
+package client
+
+import projectapi "github.com/openshift/origin/pkg/project/api"
+
+type FakeProjectRequests struct {
+	Fake *Fake
+}
+
+func (c *FakeProjectRequests) Create(request *projectapi.ProjectRequest) (*projectapi.Project, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "create-projectRequest", Value: request})
+	return &projectapi.Project{}, nil
+}
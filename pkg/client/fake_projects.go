@@ -1,7 +1,11 @@
 package client
 
-import "github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
-import projectapi "github.com/openshift/origin/pkg/project/api"
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	projectapi "github.com/openshift/origin/pkg/project/api"
+)
 
 type FakeProjects struct {
 	Fake *Fake
@@ -31,3 +35,8 @@ func (c *FakeProjects) Delete(name string) error {
 	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "delete-project", Value: name})
 	return nil
 }
+
+func (c *FakeProjects) Watch(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "watch-projects"})
+	return nil, nil
+}
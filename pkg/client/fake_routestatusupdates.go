@@ -0,0 +1,17 @@
+package client
+
+import (
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+// FakeRouteStatusUpdates implements RouteStatusUpdateInterface. Meant to be embedded into a struct to get a default
+// implementation. This makes faking out just the methods you want to test easier.
+type FakeRouteStatusUpdates struct {
+	Fake      *Fake
+	Namespace string
+}
+
+func (c *FakeRouteStatusUpdates) Create(update *routeapi.RouteStatusUpdate) (*routeapi.Route, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "create-routeStatusUpdate", Value: update})
+	return &routeapi.Route{}, nil
+}
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	templateapi "github.com/openshift/origin/pkg/template/api"
+)
+
+// FakeTemplateInstances implements TemplateInstanceInterface. Meant to be embedded into a struct to get a default
+// implementation. This makes faking out just the methods you want to test easier.
+type FakeTemplateInstances struct {
+	Fake      *Fake
+	Namespace string
+}
+
+func (c *FakeTemplateInstances) List(label, field labels.Selector) (*templateapi.TemplateInstanceList, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "list-templateInstances"})
+	return &templateapi.TemplateInstanceList{}, nil
+}
+
+func (c *FakeTemplateInstances) Get(name string) (*templateapi.TemplateInstance, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "get-templateInstance"})
+	return &templateapi.TemplateInstance{}, nil
+}
+
+func (c *FakeTemplateInstances) Create(instance *templateapi.TemplateInstance) (*templateapi.TemplateInstance, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "create-templateInstance", Value: instance})
+	return &templateapi.TemplateInstance{}, nil
+}
+
+func (c *FakeTemplateInstances) Update(instance *templateapi.TemplateInstance) (*templateapi.TemplateInstance, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "update-templateInstance"})
+	return &templateapi.TemplateInstance{}, nil
+}
+
+func (c *FakeTemplateInstances) Delete(name string) error {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "delete-templateInstance"})
+	return nil
+}
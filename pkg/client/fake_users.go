@@ -1,6 +1,7 @@
 package client
 
 import (
+	authapi "github.com/openshift/origin/pkg/auth/api"
 	userapi "github.com/openshift/origin/pkg/user/api"
 )
 
@@ -14,3 +15,8 @@ func (c *FakeUsers) Get(name string) (*userapi.User, error) {
 	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "get-user", Value: name})
 	return &userapi.User{}, nil
 }
+
+func (c *FakeUsers) Context(namespace string) (*authapi.UserContext, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "get-usercontext", Value: namespace})
+	return &authapi.UserContext{}, nil
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	userapi "github.com/openshift/origin/pkg/user/api"
+	_ "github.com/openshift/origin/pkg/user/api/v1beta1"
+)
+
+// IdentitiesInterface has methods to work with Identity resources
+type IdentitiesInterface interface {
+	Identities() IdentityInterface
+}
+
+// IdentityInterface exposes methods on identity resources.
+type IdentityInterface interface {
+	List(label, field labels.Selector) (*userapi.IdentityList, error)
+	Get(name string) (*userapi.Identity, error)
+	Delete(name string) error
+}
+
+// identities implements IdentitiesInterface
+type identities struct {
+	r *Client
+}
+
+// newIdentities returns an identities
+func newIdentities(c *Client) *identities {
+	return &identities{
+		r: c,
+	}
+}
+
+// List returns the identities that match the label selector
+func (c *identities) List(label, field labels.Selector) (result *userapi.IdentityList, err error) {
+	result = &userapi.IdentityList{}
+	err = c.r.Get().
+		Resource("identities").
+		SelectorParam("labels", label).
+		SelectorParam("fields", field).
+		Do().
+		Into(result)
+	return
+}
+
+// Get returns information about a particular identity or an error
+func (c *identities) Get(name string) (result *userapi.Identity, err error) {
+	result = &userapi.Identity{}
+	err = c.r.Get().Resource("identities").Name(name).Do().Into(result)
+	return
+}
+
+// Delete removes the mapping of the identity to its user
+func (c *identities) Delete(name string) error {
+	return c.r.Delete().Resource("identities").Name(name).Do().Error()
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
@@ -19,6 +20,9 @@ type ImageRepositoryInterface interface {
 	Create(repo *imageapi.ImageRepository) (*imageapi.ImageRepository, error)
 	Update(repo *imageapi.ImageRepository) (*imageapi.ImageRepository, error)
 	Delete(name string) error
+	Restore(name string) (*imageapi.ImageRepository, error)
+	ListRestorable(label, field labels.Selector) (*imageapi.ImageRepositoryList, error)
+	Purge(name string) error
 	Watch(label, field labels.Selector, resourceVersion string) (watch.Interface, error)
 }
 
@@ -76,6 +80,36 @@ func (c *imageRepositories) Delete(name string) (err error) {
 	return
 }
 
+// Restore restores an image repository deleted within the deletion grace period of the server, returns
+// the restored repository and error if one occurs.
+func (c *imageRepositories) Restore(name string) (result *imageapi.ImageRepository, err error) {
+	restore := &imageapi.ImageRepositoryRestore{ObjectMeta: kapi.ObjectMeta{Name: name}}
+	result = &imageapi.ImageRepository{}
+	err = c.r.Post().Namespace(c.ns).Resource("imageRepositoryRestores").Body(restore).Do().Into(result)
+	return
+}
+
+// ListRestorable returns the image repositories deleted within the deletion grace period of the server,
+// which can still be restored, and error if one occurs.
+func (c *imageRepositories) ListRestorable(label, field labels.Selector) (result *imageapi.ImageRepositoryList, err error) {
+	result = &imageapi.ImageRepositoryList{}
+	err = c.r.Get().
+		Namespace(c.ns).
+		Resource("imageRepositoryRestores").
+		SelectorParam("labels", label).
+		SelectorParam("fields", field).
+		Do().
+		Into(result)
+	return
+}
+
+// Purge removes a deleted image repository, which can then no longer be restored, and returns error if
+// one occurs.
+func (c *imageRepositories) Purge(name string) (err error) {
+	err = c.r.Delete().Namespace(c.ns).Resource("imageRepositoryRestores").Name(name).Do().Error()
+	return
+}
+
 // Watch returns a watch.Interface that watches the requested imagerepositories.
 func (c *imageRepositories) Watch(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
 	return c.r.Get().
@@ -17,6 +17,7 @@ type ImageInterface interface {
 	Get(name string) (*imageapi.Image, error)
 	Create(image *imageapi.Image) (*imageapi.Image, error)
 	Delete(name string) error
+	Search(label labels.Selector) (*imageapi.ImageList, error)
 }
 
 // images implements ImagesNamespacer interface
@@ -46,6 +47,18 @@ func (c *images) List(label, field labels.Selector) (result *imageapi.ImageList,
 	return
 }
 
+// Search returns a list of images whose Docker labels match the label selector.
+func (c *images) Search(label labels.Selector) (result *imageapi.ImageList, err error) {
+	result = &imageapi.ImageList{}
+	err = c.r.Get().
+		Namespace(c.ns).
+		Resource("imageSearches").
+		SelectorParam("labels", label).
+		Do().
+		Into(result)
+	return
+}
+
 // Get returns information about a particular image and error if one occurs.
 func (c *images) Get(name string) (result *imageapi.Image, err error) {
 	result = &imageapi.Image{}
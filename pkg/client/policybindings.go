@@ -2,6 +2,7 @@ package client
 
 import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
 )
@@ -17,6 +18,7 @@ type PolicyBindingInterface interface {
 	Get(name string) (*authorizationapi.PolicyBinding, error)
 	Create(policyBinding *authorizationapi.PolicyBinding) (*authorizationapi.PolicyBinding, error)
 	Delete(name string) error
+	Watch(label, field labels.Selector, resourceVersion string) (watch.Interface, error)
 }
 
 // policyBindings implements PolicyBindingsNamespacer interface
@@ -59,3 +61,8 @@ func (c *policyBindings) Delete(name string) (err error) {
 	err = c.r.Delete().Namespace(c.ns).Resource("policyBindings").Name(name).Do().Error()
 	return
 }
+
+// Watch returns a watch.Interface that watches the requested policyBindings.
+func (c *policyBindings) Watch(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return c.r.Get().Prefix("watch").Namespace(c.ns).Resource("policyBindings").Param("resourceVersion", resourceVersion).SelectorParam("labels", label).SelectorParam("fields", field).Watch()
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
+)
+
+// PolicyChangeReviewsNamespacer has methods to work with PolicyChangeReview resources in a namespace
+type PolicyChangeReviewsNamespacer interface {
+	PolicyChangeReviews(namespace string) PolicyChangeReviewInterface
+}
+
+// PolicyChangeReviewInterface exposes methods on PolicyChangeReview resources.
+type PolicyChangeReviewInterface interface {
+	Create(review *authorizationapi.PolicyChangeReview) (*authorizationapi.PolicyChangeReview, error)
+}
+
+// policyChangeReviews implements PolicyChangeReviewInterface interface
+type policyChangeReviews struct {
+	r  *Client
+	ns string
+}
+
+// newPolicyChangeReviews returns a policyChangeReviews
+func newPolicyChangeReviews(c *Client, namespace string) *policyChangeReviews {
+	return &policyChangeReviews{
+		r:  c,
+		ns: namespace,
+	}
+}
+
+// Create evaluates a change to a role or role binding without making it and returns the access it changes
+func (c *policyChangeReviews) Create(review *authorizationapi.PolicyChangeReview) (result *authorizationapi.PolicyChangeReview, err error) {
+	result = &authorizationapi.PolicyChangeReview{}
+	err = c.r.Post().Namespace(c.ns).Resource("policyChangeReviews").Body(review).Do().Into(result)
+	return
+}
@@ -0,0 +1,33 @@
+package client
+
+import (
+	projectapi "github.com/openshift/origin/pkg/project/api"
+)
+
+// ProjectRequestsInterface has methods to work with ProjectRequest resources
+type ProjectRequestsInterface interface {
+	ProjectRequests() ProjectRequestInterface
+}
+
+// ProjectRequestInterface exposes methods on projectRequest resources.
+type ProjectRequestInterface interface {
+	Create(request *projectapi.ProjectRequest) (*projectapi.Project, error)
+}
+
+type projectRequests struct {
+	r *Client
+}
+
+// newProjectRequests returns a projectRequests
+func newProjectRequests(c *Client) *projectRequests {
+	return &projectRequests{
+		r: c,
+	}
+}
+
+// Create requests a new Project and returns the Project that was created
+func (c *projectRequests) Create(request *projectapi.ProjectRequest) (result *projectapi.Project, err error) {
+	result = &projectapi.Project{}
+	err = c.r.Post().Resource("projectRequests").Body(request).Do().Into(result)
+	return
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 	projectapi "github.com/openshift/origin/pkg/project/api"
 	_ "github.com/openshift/origin/pkg/user/api/v1beta1"
 )
@@ -15,6 +16,7 @@ type ProjectsInterface interface {
 type ProjectInterface interface {
 	Get(name string) (*projectapi.Project, error)
 	List(label, field labels.Selector) (*projectapi.ProjectList, error)
+	Watch(label, field labels.Selector, resourceVersion string) (watch.Interface, error)
 }
 
 type projects struct {
@@ -66,3 +68,14 @@ func (c *projects) Delete(name string) (err error) {
 	err = c.r.Delete().Resource("projects").Name(name).Do().Error()
 	return
 }
+
+// Watch returns a watch.Interface that watches the requested projects
+func (c *projects) Watch(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return c.r.Get().
+		Prefix("watch").
+		Resource("projects").
+		Param("resourceVersion", resourceVersion).
+		SelectorParam("labels", label).
+		SelectorParam("fields", field).
+		Watch()
+}
@@ -0,0 +1,3 @@
+// Package record records Kubernetes Events about OpenShift resources, so the lifecycle of builds,
+// deployments and routes shows up next to the events of pods.
+package record
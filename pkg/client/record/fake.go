@@ -0,0 +1,22 @@
+package record
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+)
+
+// FakeRecorder is a Recorder that keeps the events it is given, for tests.  Each event is kept as the
+// reason and message separated by a space.
+type FakeRecorder struct {
+	lock   sync.Mutex
+	Events []string
+}
+
+// Eventf implements Recorder
+func (r *FakeRecorder) Eventf(obj runtime.Object, reason, messageFmt string, args ...interface{}) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.Events = append(r.Events, reason+" "+fmt.Sprintf(messageFmt, args...))
+}
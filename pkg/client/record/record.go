@@ -0,0 +1,108 @@
+package record
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	klatest "github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
+	kmeta "github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	krecord "github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	"github.com/openshift/origin/pkg/api/latest"
+)
+
+// Recorder records events about objects.
+type Recorder interface {
+	// Eventf records an event about obj.  reason is short and machine readable, like "created" or
+	// "failed", and the message is formatted from messageFmt and args.
+	Eventf(obj runtime.Object, reason, messageFmt string, args ...interface{})
+}
+
+// NewRecorder returns a Recorder that passes events on to the recording started with
+// krecord.StartRecording, which sends them to the master in the background.
+func NewRecorder() Recorder {
+	return recorder{}
+}
+
+type recorder struct{}
+
+// Eventf implements Recorder
+func (recorder) Eventf(obj runtime.Object, reason, messageFmt string, args ...interface{}) {
+	ref, err := GetReference(obj)
+	if err != nil {
+		glog.Errorf("Could not construct reference to %#v due to: %v.  Will not report event: %s %s", obj, err, reason, fmt.Sprintf(messageFmt, args...))
+		return
+	}
+	krecord.Eventf(ref, reason, messageFmt, args...)
+}
+
+// NewClientRecorder returns a Recorder that creates every event with client as soon as it is recorded.  It
+// suits short-lived processes, which may exit before the events recorded in the background are sent.
+func NewClientRecorder(client kclient.EventNamespacer, source kapi.EventSource) Recorder {
+	return &clientRecorder{client, source}
+}
+
+type clientRecorder struct {
+	client kclient.EventNamespacer
+	source kapi.EventSource
+}
+
+// Eventf implements Recorder
+func (r *clientRecorder) Eventf(obj runtime.Object, reason, messageFmt string, args ...interface{}) {
+	message := fmt.Sprintf(messageFmt, args...)
+	ref, err := GetReference(obj)
+	if err != nil {
+		glog.Errorf("Could not construct reference to %#v due to: %v.  Will not report event: %s %s", obj, err, reason, message)
+		return
+	}
+
+	t := util.Now()
+	event := &kapi.Event{
+		ObjectMeta: kapi.ObjectMeta{
+			Name:      fmt.Sprintf("%v.%x", ref.Name, t.UnixNano()),
+			Namespace: ref.Namespace,
+		},
+		InvolvedObject: *ref,
+		Reason:         reason,
+		Message:        message,
+		Source:         r.source,
+		Timestamp:      t,
+	}
+	if _, err := r.client.Events(ref.Namespace).Create(event); err != nil {
+		glog.Errorf("Unable to write event %#v: %v", event, err)
+	}
+}
+
+// GetReference returns a reference to obj.  Unlike kapi.GetReference, which takes the version from the self
+// link of obj, the reference is to the current version of the API that serves the kind of obj, since the
+// objects handled by controllers and registries often have no self link.
+func GetReference(obj runtime.Object) (*kapi.ObjectReference, error) {
+	if obj == nil {
+		return nil, kapi.ErrNilObject
+	}
+	meta, err := kmeta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	_, kind, err := kapi.Scheme.ObjectVersionAndKind(obj)
+	if err != nil {
+		return nil, err
+	}
+	version := klatest.Version
+	if latest.OriginKind(kind, latest.Version) {
+		version = latest.Version
+	}
+	return &kapi.ObjectReference{
+		Kind:            kind,
+		APIVersion:      version,
+		Name:            meta.Name(),
+		Namespace:       meta.Namespace(),
+		UID:             meta.UID(),
+		ResourceVersion: meta.ResourceVersion(),
+	}, nil
+}
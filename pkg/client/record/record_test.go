@@ -0,0 +1,65 @@
+package record
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	klatest "github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/api/latest"
+	buildapi "github.com/openshift/origin/pkg/build/api"
+)
+
+func TestGetReference(t *testing.T) {
+	meta := kapi.ObjectMeta{Name: "foo", Namespace: "bar", UID: "1", ResourceVersion: "2"}
+	tests := []struct {
+		obj     runtime.Object
+		kind    string
+		version string
+	}{
+		{&buildapi.Build{ObjectMeta: meta}, "Build", latest.Version},
+		{&kapi.ReplicationController{ObjectMeta: meta}, "ReplicationController", klatest.Version},
+	}
+
+	for _, test := range tests {
+		ref, err := GetReference(test.obj)
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", test.kind, err)
+			continue
+		}
+		expected := kapi.ObjectReference{Kind: test.kind, APIVersion: test.version, Name: "foo", Namespace: "bar", UID: "1", ResourceVersion: "2"}
+		if *ref != expected {
+			t.Errorf("expected %#v, got %#v", expected, *ref)
+		}
+	}
+}
+
+type fakeEvents struct {
+	kclient.EventInterface
+	created []*kapi.Event
+}
+
+func (f *fakeEvents) Events(namespace string) kclient.EventInterface {
+	return f
+}
+
+func (f *fakeEvents) Create(event *kapi.Event) (*kapi.Event, error) {
+	f.created = append(f.created, event)
+	return event, nil
+}
+
+func TestClientRecorder(t *testing.T) {
+	client := &fakeEvents{}
+	recorder := NewClientRecorder(client, kapi.EventSource{Component: "test"})
+	recorder.Eventf(&buildapi.Build{ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: "bar"}}, "created", "Created %s", "foo")
+
+	if len(client.created) != 1 {
+		t.Fatalf("expected an event to be created, got %#v", client.created)
+	}
+	event := client.created[0]
+	if event.Reason != "created" || event.Message != "Created foo" || event.Namespace != "bar" || event.Source.Component != "test" {
+		t.Errorf("unexpected event %#v", event)
+	}
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+// RouteStatusUpdatesNamespacer has methods to work with RouteStatusUpdate resources in a namespace
+type RouteStatusUpdatesNamespacer interface {
+	RouteStatusUpdates(namespace string) RouteStatusUpdateInterface
+}
+
+// RouteStatusUpdateInterface exposes methods on RouteStatusUpdate resources.
+type RouteStatusUpdateInterface interface {
+	Create(update *routeapi.RouteStatusUpdate) (*routeapi.Route, error)
+}
+
+// routeStatusUpdates implements RouteStatusUpdateInterface interface
+type routeStatusUpdates struct {
+	r  *Client
+	ns string
+}
+
+// newRouteStatusUpdates returns a routeStatusUpdates
+func newRouteStatusUpdates(c *Client, namespace string) *routeStatusUpdates {
+	return &routeStatusUpdates{
+		r:  c,
+		ns: namespace,
+	}
+}
+
+// Create records a router's view of a route and returns the updated Route
+func (c *routeStatusUpdates) Create(update *routeapi.RouteStatusUpdate) (result *routeapi.Route, err error) {
+	result = &routeapi.Route{}
+	err = c.r.Post().Namespace(c.ns).Resource("routeStatusUpdates").Body(update).Do().Into(result)
+	return
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	templateapi "github.com/openshift/origin/pkg/template/api"
+)
+
+// TemplateInstancesNamespacer has methods to work with TemplateInstance resources in a namespace
+type TemplateInstancesNamespacer interface {
+	TemplateInstances(namespace string) TemplateInstanceInterface
+}
+
+// TemplateInstanceInterface exposes methods on TemplateInstance resources
+type TemplateInstanceInterface interface {
+	List(label, field labels.Selector) (*templateapi.TemplateInstanceList, error)
+	Get(name string) (*templateapi.TemplateInstance, error)
+	Create(instance *templateapi.TemplateInstance) (*templateapi.TemplateInstance, error)
+	Update(instance *templateapi.TemplateInstance) (*templateapi.TemplateInstance, error)
+	Delete(name string) error
+}
+
+// templateInstances implements TemplateInstanceInterface interface
+type templateInstances struct {
+	r  *Client
+	ns string
+}
+
+// newTemplateInstances returns a templateInstances
+func newTemplateInstances(c *Client, namespace string) *templateInstances {
+	return &templateInstances{
+		r:  c,
+		ns: namespace,
+	}
+}
+
+// List takes a label and field selector, and returns the list of template instances that match that selectors
+func (c *templateInstances) List(label, field labels.Selector) (result *templateapi.TemplateInstanceList, err error) {
+	result = &templateapi.TemplateInstanceList{}
+	err = c.r.Get().
+		Namespace(c.ns).
+		Resource("templateInstances").
+		SelectorParam("labels", label).
+		SelectorParam("fields", field).
+		Do().
+		Into(result)
+	return
+}
+
+// Get takes the name of the template instance, and returns the corresponding TemplateInstance object, and an error if it occurs
+func (c *templateInstances) Get(name string) (result *templateapi.TemplateInstance, err error) {
+	result = &templateapi.TemplateInstance{}
+	err = c.r.Get().Namespace(c.ns).Resource("templateInstances").Name(name).Do().Into(result)
+	return
+}
+
+// Delete takes the name of the template instance, and returns an error if one occurs
+func (c *templateInstances) Delete(name string) error {
+	return c.r.Delete().Namespace(c.ns).Resource("templateInstances").Name(name).Do().Error()
+}
+
+// Create takes the representation of a template instance.  Returns the server's representation of the template instance, and an error, if it occurs
+func (c *templateInstances) Create(instance *templateapi.TemplateInstance) (result *templateapi.TemplateInstance, err error) {
+	result = &templateapi.TemplateInstance{}
+	err = c.r.Post().Namespace(c.ns).Resource("templateInstances").Body(instance).Do().Into(result)
+	return
+}
+
+// Update takes the representation of a template instance to update.  Returns the server's representation of the template instance, and an error, if it occurs
+func (c *templateInstances) Update(instance *templateapi.TemplateInstance) (result *templateapi.TemplateInstance, err error) {
+	result = &templateapi.TemplateInstance{}
+	err = c.r.Put().Namespace(c.ns).Resource("templateInstances").Name(instance.Name).Body(instance).Do().Into(result)
+	return
+}
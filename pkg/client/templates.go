@@ -0,0 +1,88 @@
+package client
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	templateapi "github.com/openshift/origin/pkg/template/api"
+)
+
+// TemplatesNamespacer has methods to work with Template resources in a namespace
+type TemplatesNamespacer interface {
+	Templates(namespace string) TemplateInterface
+}
+
+// TemplateInterface exposes methods on Template resources
+type TemplateInterface interface {
+	List(label, field labels.Selector) (*templateapi.TemplateList, error)
+	Get(name string) (*templateapi.Template, error)
+	Create(template *templateapi.Template) (*templateapi.Template, error)
+	Update(template *templateapi.Template) (*templateapi.Template, error)
+	Delete(name string) error
+	Watch(label, field labels.Selector, resourceVersion string) (watch.Interface, error)
+}
+
+// templates implements TemplateInterface interface
+type templates struct {
+	r  *Client
+	ns string
+}
+
+// newTemplates returns a templates
+func newTemplates(c *Client, namespace string) *templates {
+	return &templates{
+		r:  c,
+		ns: namespace,
+	}
+}
+
+// List takes a label and field selector, and returns the list of templates that match that selectors
+func (c *templates) List(label, field labels.Selector) (result *templateapi.TemplateList, err error) {
+	result = &templateapi.TemplateList{}
+	err = c.r.Get().
+		Namespace(c.ns).
+		Resource("templates").
+		SelectorParam("labels", label).
+		SelectorParam("fields", field).
+		Do().
+		Into(result)
+	return
+}
+
+// Get takes the name of the template, and returns the corresponding Template object, and an error if it occurs
+func (c *templates) Get(name string) (result *templateapi.Template, err error) {
+	result = &templateapi.Template{}
+	err = c.r.Get().Namespace(c.ns).Resource("templates").Name(name).Do().Into(result)
+	return
+}
+
+// Delete takes the name of the template, and returns an error if one occurs
+func (c *templates) Delete(name string) error {
+	return c.r.Delete().Namespace(c.ns).Resource("templates").Name(name).Do().Error()
+}
+
+// Create takes the representation of a template.  Returns the server's representation of the template, and an error, if it occurs
+func (c *templates) Create(template *templateapi.Template) (result *templateapi.Template, err error) {
+	result = &templateapi.Template{}
+	err = c.r.Post().Namespace(c.ns).Resource("templates").Body(template).Do().Into(result)
+	return
+}
+
+// Update takes the representation of a template to update.  Returns the server's representation of the template, and an error, if it occurs
+func (c *templates) Update(template *templateapi.Template) (result *templateapi.Template, err error) {
+	result = &templateapi.Template{}
+	err = c.r.Put().Namespace(c.ns).Resource("templates").Name(template.Name).Body(template).Do().Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested templates.
+func (c *templates) Watch(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return c.r.Get().
+		Prefix("watch").
+		Namespace(c.ns).
+		Resource("templates").
+		Param("resourceVersion", resourceVersion).
+		SelectorParam("labels", label).
+		SelectorParam("fields", field).
+		Watch()
+}
@@ -1,6 +1,9 @@
 package client
 
 import (
+	"encoding/json"
+
+	authapi "github.com/openshift/origin/pkg/auth/api"
 	userapi "github.com/openshift/origin/pkg/user/api"
 	_ "github.com/openshift/origin/pkg/user/api/v1beta1"
 )
@@ -13,6 +16,7 @@ type UsersInterface interface {
 // UserInterface exposes methods on user resources.
 type UserInterface interface {
 	Get(name string) (*userapi.User, error)
+	Context(namespace string) (*authapi.UserContext, error)
 }
 
 // users implements UserIdentityMappingsNamespacer interface
@@ -33,3 +37,21 @@ func (c *users) Get(name string) (result *userapi.User, err error) {
 	err = c.r.Get().Resource("users").Name(name).Do().Into(result)
 	return
 }
+
+// Context returns the name, groups and token scopes of the current user, and its roles in the master
+// namespace and in namespace, if not empty
+func (c *users) Context(namespace string) (*authapi.UserContext, error) {
+	req := c.r.Get().AbsPath(authapi.UserContextPath)
+	if len(namespace) > 0 {
+		req = req.Param("namespace", namespace)
+	}
+	data, err := req.Do().Raw()
+	if err != nil {
+		return nil, err
+	}
+	result := &authapi.UserContext{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -78,6 +78,7 @@ func NewCommandCLI(name, fullName string) *cobra.Command {
 	cmds.AddCommand(cmd.NewCmdCancelBuild(f, out))
 
 	cmds.AddCommand(cmd.NewCmdRollback(name, "rollback", f, out))
+	cmds.AddCommand(cmd.NewCmdDeployLogs(f, out))
 
 	cmds.AddCommand(cmd.NewCmdOptions(f, out))
 
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	kubecmd "github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/cmd"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/wait"
+	"github.com/spf13/cobra"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
+)
+
+func NewCmdDeployLogs(f *Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy-logs <deploymentConfig>",
+		Short: "Show container logs from the deployer pod of a deployment",
+		Long: `Retrieve logs from the deployer pod of the latest deployment of a deployment config
+
+The logs of a running deployment are streamed until the deployment finishes. The deployer
+pods of complete deployments are removed, so only the logs of running and failed deployments
+can be shown.
+
+NOTE: This command may be moved in the future.
+
+Examples:
+$ osc deploy-logs frontend
+<stream logs from the latest deployment of frontend to stdout>
+
+$ osc deploy-logs frontend --follow
+<wait for the latest deployment of frontend to start, then stream its logs to stdout>
+
+$ osc deploy-logs frontend --previous
+<show logs from the last failed deployment of frontend before the latest one>`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				usageError(cmd, "<deploymentConfig> is a required argument")
+			}
+
+			namespace, err := f.DefaultNamespace(cmd)
+			checkErr(err)
+
+			mapper, _ := f.Object(cmd)
+			mapping, err := mapper.RESTMapping("DeploymentLog", kubecmd.GetFlagString(cmd, "api-version"))
+			checkErr(err)
+			c, kc, err := f.Clients(cmd)
+			checkErr(err)
+
+			config, err := c.DeploymentConfigs(namespace).Get(args[0])
+			checkErr(err)
+			name := deployutil.LatestDeploymentNameForConfig(config)
+			if kubecmd.GetFlagBool(cmd, "previous") {
+				name, err = previousFailedDeployment(kc, config)
+				checkErr(err)
+			} else if kubecmd.GetFlagBool(cmd, "follow") {
+				checkErr(waitForDeployment(kc, namespace, name))
+			}
+
+			// TODO: This should be a method on the origin Client - DeploymentLogs(namespace).Redirect(name)
+			request := c.Get().Namespace(namespace).Prefix("redirect").Resource(mapping.Resource).Name(name)
+
+			readCloser, err := request.Stream()
+			checkErr(err)
+			defer readCloser.Close()
+
+			_, err = io.Copy(out, readCloser)
+			checkErr(err)
+		},
+	}
+	cmd.Flags().BoolP("follow", "f", false, "Wait for the deployment to start if it has not yet, then stream its logs")
+	cmd.Flags().Bool("previous", false, "Show the logs of the last failed deployment before the latest one")
+	return cmd
+}
+
+// previousFailedDeployment returns the name of the newest failed deployment of config older than its
+// latest one.  Deployments that were removed are skipped.
+func previousFailedDeployment(kc kclient.Interface, config *deployapi.DeploymentConfig) (string, error) {
+	for version := config.LatestVersion - 1; version > 0; version-- {
+		name := deployutil.DeploymentNameForConfigVersion(config.Name, version)
+		deployment, err := kc.ReplicationControllers(config.Namespace).Get(name)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		if deployapi.DeploymentStatus(deployment.Annotations[deployapi.DeploymentStatusAnnotation]) == deployapi.DeploymentStatusFailed {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("deployment config %s has no failed deployment before its latest one", config.Name)
+}
+
+// waitForDeployment waits until the deployer pod of the deployment name has started.
+func waitForDeployment(kc kclient.Interface, namespace, name string) error {
+	return wait.Poll(time.Second, 0, func() (bool, error) {
+		deployment, err := kc.ReplicationControllers(namespace).Get(name)
+		if err != nil {
+			return false, err
+		}
+		switch deployapi.DeploymentStatus(deployment.Annotations[deployapi.DeploymentStatusAnnotation]) {
+		case deployapi.DeploymentStatusNew, deployapi.DeploymentStatusPending:
+			return false, nil
+		}
+		return true, nil
+	})
+}
@@ -28,6 +28,9 @@ a human-readable representation of the updated deployment configuration instead
 executing the rollback. This is useful if you're not quite sure what the outcome
 will be.
 
+To roll back to a deployment by its version, pass the name of the deployment
+configuration and '--to-revision'.
+
 Examples:
   Perform a rollback:
 
@@ -37,6 +40,10 @@ Examples:
 
   $ %[1]s %[2]s deployment-1 --dry-run
 
+  Roll back to the second deployment of the "deployment" configuration, including its scaling settings:
+
+  $ %[1]s %[2]s deployment --to-revision=2 --change-scaling-settings
+
   Perform the rollback manually by piping the JSON of the new config back to %[1]s:
 
   $ %[1]s %[2]s deployment-1 --output=json | %[1]s update deploymentConfigs deployment -f -
@@ -50,7 +57,7 @@ func NewCmdRollback(parentName string, name string, f *Factory, out io.Writer) *
 	}
 
 	cmd := &cobra.Command{
-		Use:   fmt.Sprintf("%s <from-deployment>", name),
+		Use:   fmt.Sprintf("%s <from-deployment>|<deploymentConfig> --to-revision=<version>", name),
 		Short: "Revert part of an application back to a previous deployment.",
 		Long:  fmt.Sprintf(rollbackLongDesc, parentName, name),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -101,6 +108,7 @@ func NewCmdRollback(parentName string, name string, f *Factory, out io.Writer) *
 	cmd.Flags().BoolVar(&rollback.Spec.IncludeTriggers, "change-triggers", false, "Include the previous deployment's triggers in the rollback")
 	cmd.Flags().BoolVar(&rollback.Spec.IncludeStrategy, "change-strategy", false, "Include the previous deployment's strategy in the rollback")
 	cmd.Flags().BoolVar(&rollback.Spec.IncludeReplicationMeta, "change-scaling-settings", false, "Include the previous deployment's replicationController replica count and selector in the rollback")
+	cmd.Flags().IntVar(&rollback.Spec.Revision, "to-revision", 0, "Roll back the deployment config named by the argument to the deployment of this version")
 	cmd.Flags().BoolP("dry-run", "d", false, "Instead of performing the rollback, describe what the rollback will look like in human-readable form")
 	cmd.Flags().StringP("output", "o", "", "Instead of performing the rollback, print the updated deployment configuration in the specified format (json|yaml|template|templatefile)")
 	cmd.Flags().StringP("template", "t", "", "Template string or path to template file to use when -o=template or -o=templatefile.")
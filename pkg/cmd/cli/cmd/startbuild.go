@@ -3,16 +3,22 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/spf13/cobra"
 
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kubecmd "github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/cmd"
+	kutil "github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/errors"
 
 	build "github.com/openshift/origin/pkg/build/api"
 	"github.com/openshift/origin/pkg/build/util"
+	cmdutil "github.com/openshift/origin/pkg/cmd/util"
 )
 
 func NewCmdStartBuild(f *Factory, out io.Writer) *cobra.Command {
+	env := kutil.StringList{}
 	cmd := &cobra.Command{
 		Use:   "start-build (<buildConfig>|--from-build=<build>)",
 		Short: "Starts a new build from existing build or buildConfig",
@@ -25,6 +31,10 @@ Examples:
   $ osc start-build 3bd2ug53b
   <Starts build from buildConfig matching the name "3bd2ug53b">
 
+  $ osc start-build 3bd2ug53b --env=MODE=debug --commit=9bdc3a2
+  <Starts build of commit "9bdc3a2" from buildConfig matching the name "3bd2ug53b" with the
+  environment variable MODE set, if the buildConfig allows them to be overridden>
+
   $ osc start-build --from-build=3bd2ug53b
   <Starts build from build matching the name "3bd2ug53b">`,
 		Run: func(cmd *cobra.Command, args []string) {
@@ -32,6 +42,11 @@ Examples:
 			if len(args) != 1 && len(buildName) == 0 {
 				usageError(cmd, "Must pass a name of buildConfig or specify build name with '--from-build' flag")
 			}
+			overrides, err := buildOverrides(env, kubecmd.GetFlagString(cmd, "ref"), kubecmd.GetFlagString(cmd, "commit"))
+			checkErr(err)
+			if overrides != nil && len(buildName) != 0 {
+				usageError(cmd, "The '--env', '--ref' and '--commit' flags may only be used to start a build from a buildConfig")
+			}
 
 			client, _, err := f.Clients(cmd)
 			checkErr(err)
@@ -46,6 +61,7 @@ Examples:
 				checkErr(err)
 
 				newBuild = util.GenerateBuildFromConfig(config, nil, nil)
+				checkErr(util.ApplyBuildOverrides(config, newBuild, overrides))
 			} else {
 				build, err := client.Builds(namespace).Get(buildName)
 				checkErr(err)
@@ -59,5 +75,31 @@ Examples:
 		},
 	}
 	cmd.Flags().StringP("from-build", "", "", "Specify the name of a build which should be re-run")
+	cmd.Flags().VarP(&env, "env", "e", "Specify key value pairs of environment variables to set for the build, if the buildConfig allows them to be overridden.")
+	cmd.Flags().String("ref", "", "Specify the branch or tag to build, if the buildConfig allows the ref to be overridden.")
+	cmd.Flags().String("commit", "", "Specify the commit to build, if the buildConfig allows the ref to be overridden.")
 	return cmd
 }
+
+// buildOverrides returns the overrides of the build parameters set by the flags, or nil if none is set.
+func buildOverrides(env kutil.StringList, ref, commit string) (*build.BuildOverrides, error) {
+	if len(env) == 0 && len(ref) == 0 && len(commit) == 0 {
+		return nil, nil
+	}
+	vars, _, errs := cmdutil.ParseEnvironmentArguments(env)
+	if len(errs) > 0 {
+		return nil, errors.NewAggregate(errs)
+	}
+	overrides := &build.BuildOverrides{Ref: ref, Commit: commit}
+	for name, value := range vars {
+		overrides.Env = append(overrides.Env, kapi.EnvVar{Name: name, Value: value})
+	}
+	sort.Sort(envByName(overrides.Env))
+	return overrides, nil
+}
+
+type envByName []kapi.EnvVar
+
+func (e envByName) Len() int           { return len(e) }
+func (e envByName) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e envByName) Less(i, j int) bool { return e[i].Name < e[j].Name }
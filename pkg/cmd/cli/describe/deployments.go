@@ -92,6 +92,9 @@ func (d *DeploymentConfigDescriber) Describe(namespace, name string) (string, er
 		} else {
 			formatString(out, "Latest Version", strconv.Itoa(deploymentConfig.LatestVersion))
 		}
+		if deploymentConfig.Test {
+			formatString(out, "Test", "Deployments are scaled back to zero once validated")
+		}
 
 		printStrategy(deploymentConfig.Template.Strategy, out)
 		printTriggers(deploymentConfig.Triggers, out)
@@ -192,6 +195,9 @@ func printDeploymentRc(deployment *kapi.ReplicationController, client deployment
 	fmt.Fprintf(w, "\tLabels:\t%s\n", formatLabels(deployment.Labels))
 	fmt.Fprintf(w, "\tReplicas:\t%d current / %d desired\n", deployment.Status.Replicas, deployment.Spec.Replicas)
 	fmt.Fprintf(w, "\tPods Status:\t%d Running / %d Waiting / %d Succeeded / %d Failed\n", running, waiting, succeeded, failed)
+	if drifted, ok := deployment.Annotations[deployapi.DeploymentDriftAnnotation]; ok {
+		fmt.Fprintf(w, "\tDrift:\tpod template changed outside of the deployment config, detected %s\n", drifted)
+	}
 
 	return nil
 }
@@ -12,6 +12,8 @@ import (
 
 	buildapi "github.com/openshift/origin/pkg/build/api"
 	"github.com/openshift/origin/pkg/client"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+	routeapi "github.com/openshift/origin/pkg/route/api"
 )
 
 func DescriberFor(kind string, c *client.Client, kclient kclient.Interface, host string) (kctl.Describer, bool) {
@@ -190,6 +192,16 @@ func (d *ImageDescriber) Describe(namespace, name string) (string, error) {
 	return tabbedString(func(out *tabwriter.Writer) error {
 		formatMeta(out, image.ObjectMeta)
 		formatString(out, "Docker Image", image.DockerImageReference)
+		config := image.DockerImageMetadata.Config
+		formatString(out, "Docker Labels", formatLabels(config.Labels))
+		ports := []string{}
+		for port := range config.ExposedPorts {
+			ports = append(ports, port)
+		}
+		sort.Strings(ports)
+		formatString(out, "Exposed Ports", strings.Join(ports, ", "))
+		formatString(out, "Entrypoint", strings.Join(config.Entrypoint, " "))
+		formatString(out, "Environment", strings.Join(config.Env, " "))
 		return nil
 	})
 }
@@ -210,6 +222,22 @@ func (d *ImageRepositoryDescriber) Describe(namespace, name string) (string, err
 		formatMeta(out, imageRepository.ObjectMeta)
 		formatString(out, "Tags", formatLabels(imageRepository.Tags))
 		formatString(out, "Registry", imageRepository.Status.DockerImageRepository)
+		tags := []string{}
+		for tag := range imageRepository.Status.Tags {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		for _, tag := range tags {
+			latest := imageapi.LatestTaggedImage(imageRepository, tag)
+			if latest == nil {
+				continue
+			}
+			image := latest.DockerImageReference
+			if len(image) == 0 {
+				image = latest.Image
+			}
+			formatString(out, fmt.Sprintf("Tag %s", tag), fmt.Sprintf("%s (set %s)", image, latest.Created))
+		}
 		return nil
 	})
 }
@@ -231,6 +259,23 @@ func (d *RouteDescriber) Describe(namespace, name string) (string, error) {
 		formatString(out, "Host", route.Host)
 		formatString(out, "Path", route.Path)
 		formatString(out, "Service", route.ServiceName)
+		status := route.Status
+		if status == nil {
+			status = &routeapi.RouteStatus{}
+		}
+		if status.CertificateExpires != nil {
+			formatString(out, "Certificate Expires", status.CertificateExpires.Time)
+		}
+		if len(status.Ingress) == 0 {
+			formatString(out, "Routers", "<none>")
+		}
+		for _, ingress := range status.Ingress {
+			status := string(ingress.Phase)
+			if len(ingress.Message) > 0 {
+				status = fmt.Sprintf("%s (%s: %s)", status, ingress.Reason, ingress.Message)
+			}
+			formatString(out, fmt.Sprintf("Router %s", ingress.RouterName), status)
+		}
 		return nil
 	})
 }
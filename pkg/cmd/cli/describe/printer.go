@@ -29,16 +29,21 @@ var (
 	deploymentColumns       = []string{"NAME", "STATUS", "CAUSE"}
 	deploymentConfigColumns = []string{"NAME", "TRIGGERS", "LATEST VERSION"}
 	parameterColumns        = []string{"NAME", "DESCRIPTION", "GENERATOR", "VALUE"}
+	templateColumns         = []string{"NAME", "DESCRIPTION", "PARAMETERS", "OBJECTS"}
+	templateInstanceColumns = []string{"NAME", "TEMPLATE", "OBJECTS"}
 	policyColumns           = []string{"NAME", "ROLES", "LAST MODIFIED"}
 	policyBindingColumns    = []string{"NAME", "ROLE BINDINGS", "LAST MODIFIED"}
 
 	oauthClientColumns              = []string{"NAME", "SECRET", "WWW-CHALLENGE", "REDIRECT URIS"}
 	oauthClientAuthorizationColumns = []string{"NAME", "USER NAME", "CLIENT NAME", "SCOPES"}
-	oauthAccessTokenColumns         = []string{"NAME", "USER NAME", "CLIENT NAME", "CREATED", "EXPIRES", "REDIRECT URI", "SCOPES"}
+	oauthAccessTokenColumns         = []string{"NAME", "USER NAME", "CLIENT NAME", "CREATED", "EXPIRES", "REDIRECT URI", "SCOPES", "LAST USED"}
 	oauthAuthorizeTokenColumns      = []string{"NAME", "USER NAME", "CLIENT NAME", "CREATED", "EXPIRES", "REDIRECT URI", "SCOPES"}
+	bootstrapTokenColumns           = []string{"NAME", "CREATED", "EXPIRES", "DESCRIPTION"}
+	routerTokenColumns              = []string{"NAME", "ROUTER", "NAMESPACES", "CREATED", "EXPIRES"}
 
-	userColumns                = []string{"NAME", "UID", "FULL NAME"}
+	userColumns                = []string{"NAME", "UID", "FULL NAME", "LAST LOGIN"}
 	userIdentityMappingColumns = []string{"NAME", "IDENTITY PROVIDER", "IDENTITY USERNAME", "USER NAME"}
+	identityColumns            = []string{"NAME", "IDENTITY PROVIDER", "IDENTITY USERNAME", "USER NAME", "USER UID"}
 )
 
 func NewHumanReadablePrinter(noHeaders bool) *kctl.HumanReadablePrinter {
@@ -60,6 +65,10 @@ func NewHumanReadablePrinter(noHeaders bool) *kctl.HumanReadablePrinter {
 	p.Handler(deploymentConfigColumns, printDeploymentConfig)
 	p.Handler(deploymentConfigColumns, printDeploymentConfigList)
 	p.Handler(parameterColumns, printParameters)
+	p.Handler(templateColumns, printTemplate)
+	p.Handler(templateColumns, printTemplateList)
+	p.Handler(templateInstanceColumns, printTemplateInstance)
+	p.Handler(templateInstanceColumns, printTemplateInstanceList)
 	p.Handler(policyColumns, printPolicy)
 	p.Handler(policyColumns, printPolicyList)
 	p.Handler(policyBindingColumns, printPolicyBinding)
@@ -73,9 +82,15 @@ func NewHumanReadablePrinter(noHeaders bool) *kctl.HumanReadablePrinter {
 	p.Handler(oauthAccessTokenColumns, printOAuthAccessTokenList)
 	p.Handler(oauthAuthorizeTokenColumns, printOAuthAuthorizeToken)
 	p.Handler(oauthAuthorizeTokenColumns, printOAuthAuthorizeTokenList)
+	p.Handler(bootstrapTokenColumns, printBootstrapToken)
+	p.Handler(bootstrapTokenColumns, printBootstrapTokenList)
+	p.Handler(routerTokenColumns, printRouterToken)
+	p.Handler(routerTokenColumns, printRouterTokenList)
 
 	p.Handler(userColumns, printUser)
 	p.Handler(userIdentityMappingColumns, printUserIdentityMapping)
+	p.Handler(identityColumns, printIdentity)
+	p.Handler(identityColumns, printIdentityList)
 	return p
 }
 
@@ -175,6 +190,38 @@ func printProjectList(projects *projectapi.ProjectList, w io.Writer) error {
 	return nil
 }
 
+func printTemplate(t *templateapi.Template, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", t.Name, t.Annotations["description"], len(t.Parameters), len(t.Items))
+	return err
+}
+
+func printTemplateList(list *templateapi.TemplateList, w io.Writer) error {
+	for _, t := range list.Items {
+		if err := printTemplate(&t, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printTemplateInstance(instance *templateapi.TemplateInstance, w io.Writer) error {
+	template := instance.Template.Name
+	if len(instance.Template.Namespace) > 0 {
+		template = instance.Template.Namespace + "/" + template
+	}
+	_, err := fmt.Fprintf(w, "%s\t%s\t%d\n", instance.Name, template, len(instance.Objects))
+	return err
+}
+
+func printTemplateInstanceList(list *templateapi.TemplateInstanceList, w io.Writer) error {
+	for _, instance := range list.Items {
+		if err := printTemplateInstance(&instance, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func printRoute(route *routeapi.Route, w io.Writer) error {
 	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", route.Name, route.Host, route.Path, route.ServiceName, labels.Set(route.Labels))
 	return err
@@ -305,7 +352,7 @@ func printOAuthClientAuthorizationList(list *oauthapi.OAuthClientAuthorizationLi
 }
 
 func printOAuthAccessToken(token *oauthapi.OAuthAccessToken, w io.Writer) error {
-	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", token.Name, token.UserName, token.ClientName, token.CreationTimestamp, token.ExpiresIn, token.RedirectURI, strings.Join(token.Scopes, ","))
+	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n", token.Name, token.UserName, token.ClientName, token.CreationTimestamp, token.ExpiresIn, token.RedirectURI, strings.Join(token.Scopes, ","), token.LastUsedTimestamp)
 	return err
 }
 func printOAuthAccessTokenList(list *oauthapi.OAuthAccessTokenList, w io.Writer) error {
@@ -330,8 +377,34 @@ func printOAuthAuthorizeTokenList(list *oauthapi.OAuthAuthorizeTokenList, w io.W
 	return nil
 }
 
+func printBootstrapToken(token *oauthapi.BootstrapToken, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", token.Name, token.CreationTimestamp, token.ExpiresIn, token.Description)
+	return err
+}
+func printBootstrapTokenList(list *oauthapi.BootstrapTokenList, w io.Writer) error {
+	for _, item := range list.Items {
+		if err := printBootstrapToken(&item, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printRouterToken(token *oauthapi.RouterToken, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", token.Name, token.RouterName, strings.Join(token.Namespaces, ","), token.CreationTimestamp, token.ExpiresIn)
+	return err
+}
+func printRouterTokenList(list *oauthapi.RouterTokenList, w io.Writer) error {
+	for _, item := range list.Items {
+		if err := printRouterToken(&item, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func printUser(user *userapi.User, w io.Writer) error {
-	_, err := fmt.Fprintf(w, "%s\t%s\t%s\n", user.Name, user.UID, user.FullName)
+	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", user.Name, user.UID, user.FullName, user.LastLoginTimestamp)
 	return err
 }
 
@@ -339,3 +412,16 @@ func printUserIdentityMapping(mapping *userapi.UserIdentityMapping, w io.Writer)
 	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", mapping.Name, mapping.Identity.Provider, mapping.Identity.UserName, mapping.User.Name)
 	return err
 }
+
+func printIdentity(identity *userapi.Identity, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", identity.Name, identity.Provider, identity.UserName, identity.User.Name, identity.User.UID)
+	return err
+}
+func printIdentityList(list *userapi.IdentityList, w io.Writer) error {
+	for _, item := range list.Items {
+		if err := printIdentity(&item, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
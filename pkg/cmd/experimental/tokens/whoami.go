@@ -2,12 +2,17 @@ package tokens
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
 
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	authapi "github.com/openshift/origin/pkg/auth/api"
 	osclient "github.com/openshift/origin/pkg/client"
 	"github.com/openshift/origin/pkg/cmd/cli/cmd"
 	"github.com/openshift/origin/pkg/cmd/util/tokencmd"
@@ -29,7 +34,10 @@ func NewCmdWhoAmI(f *cmd.Factory) *cobra.Command {
 				fmt.Errorf("%v\n", err)
 			}
 
-			whoami(token, clientCfg, cmd)
+			// the current namespace is only used to report the roles of the user in it
+			namespace, _ := f.OpenShiftClientConfig.Namespace()
+
+			whoami(token, namespace, clientCfg, cmd)
 
 		},
 	}
@@ -37,7 +45,7 @@ func NewCmdWhoAmI(f *cmd.Factory) *cobra.Command {
 	return cmd
 }
 
-func whoami(token string, clientCfg *kclient.Config, cmd *cobra.Command) {
+func whoami(token, namespace string, clientCfg *kclient.Config, cmd *cobra.Command) {
 	// TODO this is now pulled out of the auth config file (https://github.com/GoogleCloudPlatform/kubernetes/pull/2437)
 	if len(token) > 0 {
 		clientCfg.BearerToken = token
@@ -49,7 +57,7 @@ func whoami(token string, clientCfg *kclient.Config, cmd *cobra.Command) {
 		return
 	}
 
-	me, err := osClient.Users().Get("~")
+	me, err := osClient.Users().Context(namespace)
 	if err != nil {
 		glog.Errorf("Error fetching user: %v\n", err)
 
@@ -63,12 +71,39 @@ func whoami(token string, clientCfg *kclient.Config, cmd *cobra.Command) {
 		clientCfg.BearerToken = accessToken
 		osClient, _ = osclient.New(clientCfg)
 
-		me, err = osClient.Users().Get("~")
+		me, err = osClient.Users().Context(namespace)
 		if err != nil {
 			fmt.Printf("Error making request: %v\n", err)
 			return
 		}
 	}
 
-	fmt.Printf("%v\n", me)
+	printUserContext(os.Stdout, me)
+}
+
+// printUserContext writes the user, its groups and scopes, and the roles it is bound to in each namespace.
+func printUserContext(w io.Writer, me *authapi.UserContext) {
+	out := tabwriter.NewWriter(w, 0, 8, 1, ' ', 0)
+	defer out.Flush()
+
+	fmt.Fprintf(out, "User:\t%s\n", me.Name)
+	if len(me.Impersonator) > 0 {
+		fmt.Fprintf(out, "Impersonated by:\t%s\n", me.Impersonator)
+	}
+	fmt.Fprintf(out, "Groups:\t%s\n", strings.Join(me.Groups, ", "))
+	if len(me.Client) > 0 {
+		fmt.Fprintf(out, "Client:\t%s\n", me.Client)
+	}
+	if len(me.Scopes) > 0 {
+		fmt.Fprintf(out, "Scopes:\t%s\n", strings.Join(me.Scopes, ", "))
+	}
+
+	namespaces := make([]string, 0, len(me.Roles))
+	for namespace := range me.Roles {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	for _, namespace := range namespaces {
+		fmt.Fprintf(out, "Roles in %s:\t%s\n", namespace, strings.Join(me.Roles[namespace], ", "))
+	}
 }
@@ -3,14 +3,21 @@ package router
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
 
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
 
+	authapi "github.com/openshift/origin/pkg/auth/api"
+	osclient "github.com/openshift/origin/pkg/client"
 	"github.com/openshift/origin/pkg/cmd/templates"
 	"github.com/openshift/origin/pkg/cmd/util"
 	"github.com/openshift/origin/pkg/cmd/util/clientcmd"
 	"github.com/openshift/origin/pkg/router"
+	"github.com/openshift/origin/pkg/router/controller"
 	controllerfactory "github.com/openshift/origin/pkg/router/controller/factory"
 	templateplugin "github.com/openshift/origin/plugins/router/template"
 )
@@ -23,9 +30,22 @@ created by users and keeps a local router configuration up to date with those ch
 `
 
 type templateRouterConfig struct {
-	Config       *clientcmd.Config
-	TemplateFile string
-	ReloadScript string
+	Config           *clientcmd.Config
+	TemplateFile     string
+	TemplateDir      string
+	TemplateWatch    string
+	ReloadScript     string
+	AllowHostSharing bool
+	HealthAddr       string
+	ReloadInterval   string
+	StatsSocket      string
+	DynamicEndpoints bool
+	DefaultCert      string
+	Backend          string
+	Namespace        string
+	TokenNamespaces  bool
+	Labels           string
+	TCPPorts         string
 }
 
 // NewCommndTemplateRouter provides CLI handler for the template router backend
@@ -44,7 +64,19 @@ func NewCommandTemplateRouter(name string) *cobra.Command {
 				glog.Fatal(err)
 			}
 
-			if err = start(cfg.Config, plugin); err != nil {
+			if len(cfg.HealthAddr) > 0 {
+				mux := http.NewServeMux()
+				health := plugin.HealthHandler()
+				mux.Handle("/healthz", health)
+				mux.Handle("/healthz/", health)
+				mux.Handle("/metrics", plugin.MetricsHandler(cfg.StatsSocket))
+				go func() {
+					glog.Fatal(http.ListenAndServe(cfg.HealthAddr, mux))
+				}()
+				glog.Infof("Router health checks and metrics available at http://%s", cfg.HealthAddr)
+			}
+
+			if err = start(cfg, plugin); err != nil {
 				glog.Fatal(err)
 			}
 		},
@@ -56,31 +88,124 @@ func NewCommandTemplateRouter(name string) *cobra.Command {
 	flag := cmd.Flags()
 	cfg.Config.Bind(flag)
 	flag.StringVar(&cfg.TemplateFile, "template", util.Env("TEMPLATE_FILE", ""), "The path to the template file to use")
+	flag.StringVar(&cfg.TemplateDir, "template-dir", util.Env("TEMPLATE_DIR", ""), "The path to a directory of template files parsed after the template file, templates defined in it replace the templates of the same name")
+	flag.StringVar(&cfg.TemplateWatch, "template-watch-interval", util.Env("TEMPLATE_WATCH_INTERVAL", "5s"), "How often the template files are checked for changes, the router is reloaded with the new templates when they change. Disabled if 0")
 	flag.StringVar(&cfg.ReloadScript, "reload", util.Env("RELOAD_SCRIPT", ""), "The path to the reload script to use")
+	flag.BoolVar(&cfg.AllowHostSharing, "allow-host-sharing", false, "If true, routes in different namespaces that claim the same host and path are all exposed. By default only the namespace of the oldest route is.")
+	flag.StringVar(&cfg.ReloadInterval, "interval", util.Env("RELOAD_INTERVAL", "5s"), "The minimum time between router reloads, changes made in between are applied together")
+	flag.StringVar(&cfg.Backend, "backend", util.Env("ROUTER_BACKEND", templateplugin.BackendHAProxy), "The router backend configured by the template, haproxy or nginx. Per route metrics require haproxy")
+	flag.StringVar(&cfg.DefaultCert, "default-cert", util.Env("DEFAULT_CERTIFICATE", ""), "The path to a PEM file with the certificate and key served for hosts without a certificate of their own, the image default is used if empty")
+	flag.StringVar(&cfg.Namespace, "namespace", util.Env("ROUTER_NAMESPACE", ""), "Only handle the routes in this namespace, all namespaces are handled if empty")
+	flag.BoolVar(&cfg.TokenNamespaces, "namespaces-from-token", false, "If true, only handle the routes in the namespaces the router token of the client credentials is limited to, which the master reports")
+	flag.StringVar(&cfg.Labels, "labels", util.Env("ROUTE_LABELS", ""), "Only handle the routes matching this label selector, all routes are handled if empty")
+	flag.StringVar(&cfg.TCPPorts, "tcp-ports", util.Env("ROUTER_TCP_PORTS", ""), "The range of ports, such as 10000-10999, routes may claim with the router.openshift.io/tcp-port annotation to be exposed as raw TCP services, disabled if empty")
+	flag.StringVar(&cfg.HealthAddr, "health-addr", util.Env("ROUTER_HEALTH_ADDR", "0.0.0.0:1936"), "The address to serve router health checks and metrics on, disabled if empty")
+	flag.StringVar(&cfg.StatsSocket, "stats-socket", util.Env("STATS_SOCKET", "/var/lib/haproxy/run/haproxy.sock"), "The path to the haproxy admin socket to read per route metrics from, per route metrics are disabled if empty")
+	flag.BoolVar(&cfg.DynamicEndpoints, "dynamic-endpoints", false, "If true, endpoint changes are applied through the haproxy admin socket of --stats-socket without reloading the router. New endpoints are set in the disabled server slots the template reserves for each service, the router is reloaded once a service runs out of slots. Other changes still reload the router")
 
 	return cmd
 }
 
 func makeTemplatePlugin(cfg *templateRouterConfig) (*templateplugin.TemplatePlugin, error) {
-	if cfg.TemplateFile == "" {
-		return nil, errors.New("Template file must be specified")
+	if cfg.TemplateFile == "" && cfg.TemplateDir == "" {
+		return nil, errors.New("Template file or directory must be specified")
 	}
 
 	if cfg.ReloadScript == "" {
 		return nil, errors.New("Reload script must be specified")
 	}
 
-	return templateplugin.NewTemplatePlugin(cfg.TemplateFile, cfg.ReloadScript)
+	reloadInterval, err := time.ParseDuration(cfg.ReloadInterval)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid reload interval %q: %v", cfg.ReloadInterval, err)
+	}
+	templateWatch, err := time.ParseDuration(cfg.TemplateWatch)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid template watch interval %q: %v", cfg.TemplateWatch, err)
+	}
+
+	defaultCert := ""
+	if len(cfg.DefaultCert) > 0 {
+		data, err := ioutil.ReadFile(cfg.DefaultCert)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read the default certificate: %v", err)
+		}
+		defaultCert = string(data)
+	}
+
+	runtimeSocket := ""
+	if cfg.DynamicEndpoints {
+		if len(cfg.StatsSocket) == 0 {
+			return nil, errors.New("Dynamic endpoints require the stats socket to be specified")
+		}
+		runtimeSocket = cfg.StatsSocket
+	}
+
+	plugin, err := templateplugin.NewTemplatePlugin(cfg.TemplateFile, cfg.TemplateDir, cfg.ReloadScript, reloadInterval, defaultCert, cfg.Backend, runtimeSocket)
+	if err != nil {
+		return nil, err
+	}
+	if templateWatch > 0 {
+		plugin.WatchTemplates(templateWatch, nil)
+	}
+
+	if len(cfg.TCPPorts) > 0 {
+		if err := plugin.ExposeTCPPorts(cfg.TCPPorts); err != nil {
+			return nil, err
+		}
+	}
+	return plugin, nil
 }
 
 // start launches the load balancer.
-func start(cfg *clientcmd.Config, plugin router.Plugin) error {
-	kubeClient, osClient, err := cfg.Clients()
+func start(cfg *templateRouterConfig, plugin router.Plugin) error {
+	kubeClient, _, err := cfg.Config.Clients()
 	if err != nil {
 		return err
 	}
+	// the router lists every route and endpoint, read them in the compact encoding
+	osConfig := cfg.Config.OpenShiftConfig()
+	if err := osclient.UseCompactEncoding(osConfig); err != nil {
+		return err
+	}
+	osClient, err := osclient.New(osConfig)
+	if err != nil {
+		return fmt.Errorf("Unable to configure OpenShift client: %v", err)
+	}
+
+	selector, err := labels.ParseSelector(cfg.Labels)
+	if err != nil {
+		return fmt.Errorf("Invalid route label selector %q: %v", cfg.Labels, err)
+	}
 
-	factory := controllerfactory.RouterControllerFactory{kubeClient, osClient}
+	// the master records the status of routes under the router the client credentials authenticate as
+	user, err := osClient.Users().Context("")
+	if err != nil {
+		return fmt.Errorf("Unable to read the user of the client credentials: %v", err)
+	}
+	recorder := controller.NewStatusRecorder(osClient, authapi.RouterName(user.Name))
+	if templatePlugin, ok := plugin.(*templateplugin.TemplatePlugin); ok {
+		templatePlugin.Recorder = recorder
+	}
+	if !cfg.AllowHostSharing {
+		plugin = controller.NewUniqueHost(plugin, recorder)
+	}
+	plugin = controller.NewStatusAdmitter(plugin, recorder)
+
+	factory := controllerfactory.RouterControllerFactory{
+		KClient:   kubeClient,
+		OSClient:  osClient,
+		Namespace: cfg.Namespace,
+		Labels:    selector,
+	}
+	if cfg.TokenNamespaces {
+		// a router token may only read the routes and endpoints of its namespaces, never all namespaces
+		if len(user.Namespaces) == 0 {
+			return fmt.Errorf("The credentials of %s are not limited to any namespace, use a router token", user.Name)
+		}
+		glog.Infof("Handling the routes in namespaces %v", user.Namespaces)
+		factory.Namespaces = user.Namespaces
+	}
 	controller := factory.Create(plugin)
 	controller.Run()
 
@@ -114,6 +114,7 @@ func newExperimentalCommand(parentName, name string) *cobra.Command {
 	experimental.AddCommand(policy.NewCommandPolicy("policy"))
 	experimental.AddCommand(generate.NewCmdGenerate("generate"))
 	experimental.AddCommand(login.NewCmdLogin("login", experimental))
+	experimental.AddCommand(server.NewCommandMigrateStorage("migrate-storage"))
 	return experimental
 }
 
@@ -0,0 +1,2 @@
+// Package api contains the configuration file format of the OpenShift master.
+package api
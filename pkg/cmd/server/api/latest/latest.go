@@ -0,0 +1,43 @@
+package latest
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/openshift/origin/pkg/cmd/server/api"
+	"github.com/openshift/origin/pkg/cmd/server/api/v1"
+)
+
+// Version is the current version of the configuration file format.
+const Version = "v1"
+
+// Codec encodes configuration files in the current version and decodes every known version.
+var Codec = v1.Codec
+
+// ReadMasterConfig reads the master configuration file at path, which may be YAML or JSON.
+func ReadMasterConfig(path string) (*api.MasterConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := Codec.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %v", path, err)
+	}
+	config, ok := obj.(*api.MasterConfig)
+	if !ok {
+		return nil, fmt.Errorf("%s must contain a MasterConfig, got %T", path, obj)
+	}
+	return config, nil
+}
+
+// WriteYAML encodes config in the current version as YAML.
+func WriteYAML(config *api.MasterConfig) ([]byte, error) {
+	json, err := Codec.Encode(config)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.JSONToYAML(json)
+}
@@ -0,0 +1,96 @@
+package latest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openshift/origin/pkg/cmd/server/api"
+)
+
+func TestReadMasterConfigYAML(t *testing.T) {
+	dir, err := ioutil.TempDir("", "master-config")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "master.yaml")
+	data := `kind: MasterConfig
+apiVersion: v1
+bindAddress: 0.0.0.0:8443
+nodeList:
+- node1.example.com
+etcd:
+  address: etcd.example.com:4001
+oauth:
+  grantHandler: prompt
+  sessionMaxAgeSeconds: 600
+images:
+  format: example/origin-${component}:${version}
+disabledControllers:
+- quotaUsage
+namedCertificates:
+- names:
+  - master.example.com
+  certFile: master.crt
+  keyFile: master.key
+`
+	if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	config, err := ReadMasterConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := &api.MasterConfig{
+		BindAddress: "0.0.0.0:8443",
+		NodeList:    []string{"node1.example.com"},
+		Etcd:        api.EtcdConfig{Address: "etcd.example.com:4001"},
+		OAuth: api.OAuthConfig{
+			GrantHandler:         "prompt",
+			SessionMaxAgeSeconds: 600,
+		},
+		Images:              api.ImageConfig{Format: "example/origin-${component}:${version}"},
+		DisabledControllers: []string{api.QuotaUsageController},
+		NamedCertificates: []api.NamedCertificate{
+			{Names: []string{"master.example.com"}, CertFile: "master.crt", KeyFile: "master.key"},
+		},
+	}
+	if !reflect.DeepEqual(expected, config) {
+		t.Errorf("Expected %#v, got %#v", expected, config)
+	}
+}
+
+func TestWriteYAMLRoundTrip(t *testing.T) {
+	config := &api.MasterConfig{
+		MasterAddress:            "https://master.example.com:8443",
+		CORSAllowedOrigins:       []string{"example.com"},
+		OAuth:                    api.OAuthConfig{SessionSecrets: []string{"secret"}},
+		AllowRouteHostSharing:    true,
+		BuildSourceSecretsDir:    "/var/lib/openshift/build-secrets",
+		AllowedBuilderImages:     []string{"registry.example.com/builders/docker"},
+		ReconcileBootstrapPolicy: true,
+		Assets:                   api.AssetConfig{ProductName: "Example", ExtensionStylesheets: []string{"https://example.com/a.css"}, ContentSecurityPolicy: "default-src 'self'"},
+	}
+
+	data, err := WriteYAML(config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), "apiVersion: v1") || !strings.Contains(string(data), "kind: MasterConfig") {
+		t.Errorf("Expected the version and kind to be written:\n%s", data)
+	}
+
+	obj, err := Codec.Decode(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(config, obj) {
+		t.Errorf("Expected %#v, got %#v", config, obj)
+	}
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+)
+
+// Scheme is the scheme of the configuration files.  It is separate from the API scheme so configuration types
+// are never served by the master.
+var Scheme = runtime.NewScheme()
+
+func init() {
+	Scheme.AddKnownTypes("",
+		&MasterConfig{},
+	)
+}
+
+func (*MasterConfig) IsAnAPIObject() {}
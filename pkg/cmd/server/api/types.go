@@ -0,0 +1,260 @@
+package api
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+// MasterConfig holds the configuration of an OpenShift master.
+type MasterConfig struct {
+	kapi.TypeMeta
+
+	// BindAddress is the address the master API listens on (host, host:port, or URL)
+	BindAddress string
+	// InsecureBindAddress is a loopback host:port the master API is also served on without TLS, disabled if empty
+	InsecureBindAddress string
+	// MasterAddress is the address OpenShift components reach the master on (host, host:port, or URL)
+	MasterAddress string
+	// MasterPublicAddress is the address public clients reach the master on, if different
+	MasterPublicAddress string
+	// KubernetesAddress is the address of an external Kubernetes server.  The Kubernetes components are
+	// started in the master if empty
+	KubernetesAddress string
+	// KubernetesPublicAddress is the address public clients reach Kubernetes on, if different
+	KubernetesPublicAddress string
+	// PortalNet is the CIDR range portal IPs are assigned from
+	PortalNet string
+	// NodeList holds the hostnames of the nodes
+	NodeList []string
+	// CORSAllowedOrigins are additional origins allowed to make cross origin requests
+	CORSAllowedOrigins []string
+	// CORSAllowedMethods are the methods cross origin requests may use, instead of the defaults
+	CORSAllowedMethods []string
+	// CORSAllowedHeaders are the headers cross origin requests may send, instead of the defaults
+	CORSAllowedHeaders []string
+	// CORSExposedHeaders are the response headers cross origin clients may read, instead of the defaults
+	CORSExposedHeaders []string
+	// CORSMaxAgeSeconds is how long browsers may cache the answer to a preflight request
+	CORSMaxAgeSeconds int
+	// CertDir is the directory certificates are generated in
+	CertDir string
+	// AdminKubeConfig is where the client config file of the cluster administrator is written on first start
+	AdminKubeConfig string
+	// ShutdownGracePeriodSeconds is how long the master waits for requests in flight when it shuts down
+	ShutdownGracePeriodSeconds int
+	// MaxRequestsInFlight is the number of API requests served at once, unlimited if zero
+	MaxRequestsInFlight int
+	// MaxRequestsInFlightPerUser is the number of API requests served at once for a single user, unlimited if zero
+	MaxRequestsInFlightPerUser int
+	// MaxRequestsPerSecondPerUser is the rate of API requests a single user may make, unlimited if zero
+	MaxRequestsPerSecondPerUser float64
+	// RequestBurstPerUser is the number of API requests a single user may make at once above their rate
+	RequestBurstPerUser int
+	// MaxRequestsPerSecondPerSystemUser is the rate of API requests a single system user may make, unlimited if
+	// zero
+	MaxRequestsPerSecondPerSystemUser float64
+	// SlowRequestThresholdMilliseconds is the latency past which API requests are logged as slow
+	SlowRequestThresholdMilliseconds int
+	// ControllerLeaseTTLSeconds is the TTL of the lease masters sharing an etcd cluster compete for to run the controllers
+	ControllerLeaseTTLSeconds int
+
+	// Etcd configures the storage of the master
+	Etcd EtcdConfig
+	// OAuth configures how users authenticate
+	OAuth OAuthConfig
+	// Images configures the images used for cluster components
+	Images ImageConfig
+	// DisabledControllers lists the controllers the master does not run
+	DisabledControllers []string
+	// NamedCertificates are served on the master listener to clients that request one of their names through SNI
+	NamedCertificates []NamedCertificate
+	// Audit configures the log of mutating API requests
+	Audit AuditConfig
+	// Assets configures the branding and extensions of the web console
+	Assets AssetConfig
+
+	// ProjectRequestTemplate is the path to the template instantiated in every requested project
+	ProjectRequestTemplate string
+	// AllowRouteHostSharing allows routes in different namespaces to claim the same host and path
+	AllowRouteHostSharing bool
+	// ImageRepositoryDeletionGracePeriodSeconds is how long deleted image repositories can be restored, never if zero
+	ImageRepositoryDeletionGracePeriodSeconds int
+	// RouteCertificateExpiryWindowSeconds is how long before their certificate expires routes are reported
+	RouteCertificateExpiryWindowSeconds int
+	// BuildSourceSecretsDir is the directory of the nodes that holds the source and push secrets of builds by namespace
+	BuildSourceSecretsDir string
+	// AllowedBuilderImages are the images Docker and STI builds may run instead of the builder images of the cluster
+	AllowedBuilderImages []string
+	// ReconcileBootstrapPolicy adds the roles and role bindings missing from the master policy when the
+	// master starts, and resets the ones that differ from the bootstrap policy unless they are protected
+	ReconcileBootstrapPolicy bool
+}
+
+// EtcdConfig configures the storage of the master.
+type EtcdConfig struct {
+	// Address is the address of an external etcd server.  An etcd server is started in the master if empty
+	Address string
+	// DataDir is the data directory of the etcd server started in the master
+	DataDir string
+	// StorageVersion is the API version objects are stored in
+	StorageVersion string
+	// Prefix is the key OpenShift objects are stored under, so several masters can share an etcd cluster
+	Prefix string
+
+	// CA is the certificate bundle used to verify the etcd server, which is not verified if empty
+	CA string
+	// CertFile and KeyFile are the client certificate presented to the etcd server
+	CertFile string
+	KeyFile  string
+	// Username and Password authenticate the master to the etcd server with basic auth
+	Username string
+	Password string
+
+	// EncryptionKeys encrypt OAuth tokens, build webhook secrets, and route certificates stored in etcd.  New
+	// values are encrypted with the first key, and values encrypted with any key can be read
+	EncryptionKeys []EncryptionKey
+}
+
+// EncryptionKey is a named key that encrypts sensitive objects stored in etcd.
+type EncryptionKey struct {
+	// Name identifies the key in the values it encrypts, and must not change
+	Name string
+	// Secret is the base64 encoded 32 byte key
+	Secret string
+}
+
+// OAuthConfig configures how users authenticate with the master.
+type OAuthConfig struct {
+	// RequestHandlers are the ways requests are authenticated
+	RequestHandlers []string
+	// Handler handles unauthenticated requests to the authorize endpoint
+	Handler string
+	// GrantHandler decides whether to grant tokens to clients
+	GrantHandler string
+
+	// SessionSecrets sign and encrypt sessions, the first secret is used for new sessions
+	SessionSecrets []string
+	// SessionMaxAgeSeconds is how long a session lasts
+	SessionMaxAgeSeconds int
+	// SessionName is the name of the session cookie
+	SessionName string
+
+	// PasswordAuth is how passwords are checked
+	PasswordAuth string
+	// BasicAuthURL is the remote URL passwords are checked against when PasswordAuth is basicauthurl
+	BasicAuthURL string
+	// IdentityMappingMethod is how identities that have not logged in before are mapped to users: claim,
+	// lookup, add, or generate
+	IdentityMappingMethod string
+
+	// TokenStore is where tokens are stored
+	TokenStore string
+	// AccessTokenMaxAgeSeconds is how long access tokens granted by the master last
+	AccessTokenMaxAgeSeconds int
+	// TokenFilePath is the file tokens are read from when TokenStore is file
+	TokenFilePath string
+
+	// GoogleClientID and GoogleClientSecret identify the master to Google
+	GoogleClientID     string
+	GoogleClientSecret string
+	// GithubClientID and GithubClientSecret identify the master to GitHub
+	GithubClientID     string
+	GithubClientSecret string
+}
+
+// ImageConfig configures the images used for cluster components.
+type ImageConfig struct {
+	// Format is the image name format, where ${component} and ${version} are replaced
+	Format string
+	// Latest uses the latest images rather than those of the current release
+	Latest bool
+	// UseLocal uses images present on the nodes without pulling them
+	UseLocal bool
+}
+
+// NamedCertificate is a certificate served to TLS clients that request one of its names.
+type NamedCertificate struct {
+	// Names are the server names the certificate is served for, which may be wildcards like *.example.com
+	Names []string
+	// CertFile is the PEM encoded certificate, followed by any intermediate certificates
+	CertFile string
+	// KeyFile is the PEM encoded private key of the certificate
+	KeyFile string
+}
+
+// AssetConfig configures the branding and extensions of the web console served by the asset server.
+type AssetConfig struct {
+	// Dir holds files served in place of the embedded web console assets, if set
+	Dir string
+	// PathPrefix serves the web console under this path on the master listener instead of on its own port, if set
+	PathPrefix string
+	// ProxyAPI serves the APIs to the web console through the asset server, avoiding cross origin requests
+	ProxyAPI bool
+	// ProductName replaces the product name shown by the web console, if set
+	ProductName string
+	// LogoImageURL replaces the logo shown by the web console, if set
+	LogoImageURL string
+	// ExtensionScripts are the URLs of scripts the web console loads after its own
+	ExtensionScripts []string
+	// ExtensionStylesheets are the URLs of stylesheets the web console loads after its own
+	ExtensionStylesheets []string
+	// LogoutURL is where the web console sends users after logging out, if set
+	LogoutURL string
+	// ContentSecurityPolicy replaces the policy derived from the API addresses and extensions, if set
+	ContentSecurityPolicy string
+	// FrameOptions is the X-Frame-Options header of the asset server, DENY if empty
+	FrameOptions string
+	// StrictTransportSecurity is the Strict-Transport-Security header of the asset server on https, one year if empty
+	StrictTransportSecurity string
+}
+
+// AuditConfig configures the log of mutating API requests.
+type AuditConfig struct {
+	// Path is the file audit records are written to, or "-" for stdout.  Auditing is disabled if empty
+	Path string
+	// MaxSizeMegabytes is the size the audit log is rotated at, it is never rotated if zero
+	MaxSizeMegabytes int
+	// MaxBackups is the number of rotated audit logs kept
+	MaxBackups int
+}
+
+// The controllers run by the master, which may be listed in DisabledControllers.
+const (
+	BuildController                        = "build"
+	BuildImageChangeTriggerController      = "buildImageChangeTrigger"
+	DeploymentController                   = "deployment"
+	DeploymentConfigController             = "deploymentConfig"
+	DeploymentConfigChangeController       = "deploymentConfigChange"
+	DeploymentImageChangeTriggerController = "deploymentImageChangeTrigger"
+	DeploymentAutoscaleController          = "deploymentAutoscale"
+	DeploymentDriftController              = "deploymentDrift"
+	RouteCertificateExpiryController       = "routeCertificateExpiry"
+	QuotaUsageController                   = "quotaUsage"
+	ProjectFinalizerController             = "projectFinalizer"
+	GarbageCollectorController             = "garbageCollector"
+)
+
+// KnownControllers lists every controller run by the master.
+var KnownControllers = []string{
+	BuildController,
+	BuildImageChangeTriggerController,
+	DeploymentController,
+	DeploymentConfigController,
+	DeploymentConfigChangeController,
+	DeploymentImageChangeTriggerController,
+	DeploymentAutoscaleController,
+	DeploymentDriftController,
+	RouteCertificateExpiryController,
+	QuotaUsageController,
+	ProjectFinalizerController,
+	GarbageCollectorController,
+}
+
+// ControllerEnabled returns true unless the named controller is listed in DisabledControllers.
+func (c *MasterConfig) ControllerEnabled(name string) bool {
+	for _, disabled := range c.DisabledControllers {
+		if disabled == name {
+			return false
+		}
+	}
+	return true
+}
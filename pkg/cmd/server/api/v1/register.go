@@ -0,0 +1,18 @@
+package v1
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/cmd/server/api"
+)
+
+// Codec encodes internal configuration objects to the v1 format.
+var Codec = runtime.CodecFor(api.Scheme, "v1")
+
+func init() {
+	api.Scheme.AddKnownTypes("v1",
+		&MasterConfig{},
+	)
+}
+
+func (*MasterConfig) IsAnAPIObject() {}
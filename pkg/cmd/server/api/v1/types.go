@@ -0,0 +1,218 @@
+package v1
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+// MasterConfig holds the configuration of an OpenShift master.
+type MasterConfig struct {
+	kapi.TypeMeta `json:",inline"`
+
+	// BindAddress is the address the master API listens on (host, host:port, or URL)
+	BindAddress string `json:"bindAddress,omitempty"`
+	// InsecureBindAddress is a loopback host:port the master API is also served on without TLS, disabled if empty
+	InsecureBindAddress string `json:"insecureBindAddress,omitempty"`
+	// MasterAddress is the address OpenShift components reach the master on (host, host:port, or URL)
+	MasterAddress string `json:"masterAddress,omitempty"`
+	// MasterPublicAddress is the address public clients reach the master on, if different
+	MasterPublicAddress string `json:"masterPublicAddress,omitempty"`
+	// KubernetesAddress is the address of an external Kubernetes server.  The Kubernetes components are
+	// started in the master if empty
+	KubernetesAddress string `json:"kubernetesAddress,omitempty"`
+	// KubernetesPublicAddress is the address public clients reach Kubernetes on, if different
+	KubernetesPublicAddress string `json:"kubernetesPublicAddress,omitempty"`
+	// PortalNet is the CIDR range portal IPs are assigned from
+	PortalNet string `json:"portalNet,omitempty"`
+	// NodeList holds the hostnames of the nodes
+	NodeList []string `json:"nodeList,omitempty"`
+	// CORSAllowedOrigins are additional origins allowed to make cross origin requests
+	CORSAllowedOrigins []string `json:"corsAllowedOrigins,omitempty"`
+	// CORSAllowedMethods are the methods cross origin requests may use, instead of the defaults
+	CORSAllowedMethods []string `json:"corsAllowedMethods,omitempty"`
+	// CORSAllowedHeaders are the headers cross origin requests may send, instead of the defaults
+	CORSAllowedHeaders []string `json:"corsAllowedHeaders,omitempty"`
+	// CORSExposedHeaders are the response headers cross origin clients may read, instead of the defaults
+	CORSExposedHeaders []string `json:"corsExposedHeaders,omitempty"`
+	// CORSMaxAgeSeconds is how long browsers may cache the answer to a preflight request
+	CORSMaxAgeSeconds int `json:"corsMaxAgeSeconds,omitempty"`
+	// CertDir is the directory certificates are generated in
+	CertDir string `json:"certDir,omitempty"`
+	// AdminKubeConfig is where the client config file of the cluster administrator is written on first start
+	AdminKubeConfig string `json:"adminKubeConfig,omitempty"`
+	// ShutdownGracePeriodSeconds is how long the master waits for requests in flight when it shuts down
+	ShutdownGracePeriodSeconds int `json:"shutdownGracePeriodSeconds,omitempty"`
+	// MaxRequestsInFlight is the number of API requests served at once, unlimited if zero
+	MaxRequestsInFlight int `json:"maxRequestsInFlight,omitempty"`
+	// MaxRequestsInFlightPerUser is the number of API requests served at once for a single user, unlimited if zero
+	MaxRequestsInFlightPerUser int `json:"maxRequestsInFlightPerUser,omitempty"`
+	// MaxRequestsPerSecondPerUser is the rate of API requests a single user may make, unlimited if zero
+	MaxRequestsPerSecondPerUser float64 `json:"maxRequestsPerSecondPerUser,omitempty"`
+	// RequestBurstPerUser is the number of API requests a single user may make at once above their rate
+	RequestBurstPerUser int `json:"requestBurstPerUser,omitempty"`
+	// MaxRequestsPerSecondPerSystemUser is the rate of API requests a single system user may make, unlimited if
+	// zero
+	MaxRequestsPerSecondPerSystemUser float64 `json:"maxRequestsPerSecondPerSystemUser,omitempty"`
+	// SlowRequestThresholdMilliseconds is the latency past which API requests are logged as slow
+	SlowRequestThresholdMilliseconds int `json:"slowRequestThresholdMilliseconds,omitempty"`
+	// ControllerLeaseTTLSeconds is the TTL of the lease masters sharing an etcd cluster compete for to run the controllers
+	ControllerLeaseTTLSeconds int `json:"controllerLeaseTTLSeconds,omitempty"`
+
+	// Etcd configures the storage of the master
+	Etcd EtcdConfig `json:"etcd,omitempty"`
+	// OAuth configures how users authenticate
+	OAuth OAuthConfig `json:"oauth,omitempty"`
+	// Images configures the images used for cluster components
+	Images ImageConfig `json:"images,omitempty"`
+	// DisabledControllers lists the controllers the master does not run
+	DisabledControllers []string `json:"disabledControllers,omitempty"`
+	// NamedCertificates are served on the master listener to clients that request one of their names through SNI
+	NamedCertificates []NamedCertificate `json:"namedCertificates,omitempty"`
+	// Audit configures the log of mutating API requests
+	Audit AuditConfig `json:"audit,omitempty"`
+	// Assets configures the branding and extensions of the web console
+	Assets AssetConfig `json:"assets,omitempty"`
+
+	// ProjectRequestTemplate is the path to the template instantiated in every requested project
+	ProjectRequestTemplate string `json:"projectRequestTemplate,omitempty"`
+	// AllowRouteHostSharing allows routes in different namespaces to claim the same host and path
+	AllowRouteHostSharing bool `json:"allowRouteHostSharing,omitempty"`
+	// ImageRepositoryDeletionGracePeriodSeconds is how long deleted image repositories can be restored, never if zero
+	ImageRepositoryDeletionGracePeriodSeconds int `json:"imageRepositoryDeletionGracePeriodSeconds,omitempty"`
+	// RouteCertificateExpiryWindowSeconds is how long before their certificate expires routes are reported
+	RouteCertificateExpiryWindowSeconds int `json:"routeCertificateExpiryWindowSeconds,omitempty"`
+	// BuildSourceSecretsDir is the directory of the nodes that holds the source and push secrets of builds by namespace
+	BuildSourceSecretsDir string `json:"buildSourceSecretsDir,omitempty"`
+	// AllowedBuilderImages are the images Docker and STI builds may run instead of the builder images of the cluster
+	AllowedBuilderImages []string `json:"allowedBuilderImages,omitempty"`
+	// ReconcileBootstrapPolicy adds the roles and role bindings missing from the master policy when the
+	// master starts, and resets the ones that differ from the bootstrap policy unless they are protected
+	ReconcileBootstrapPolicy bool `json:"reconcileBootstrapPolicy,omitempty"`
+}
+
+// EtcdConfig configures the storage of the master.
+type EtcdConfig struct {
+	// Address is the address of an external etcd server.  An etcd server is started in the master if empty
+	Address string `json:"address,omitempty"`
+	// DataDir is the data directory of the etcd server started in the master
+	DataDir string `json:"dataDir,omitempty"`
+	// StorageVersion is the API version objects are stored in
+	StorageVersion string `json:"storageVersion,omitempty"`
+	// Prefix is the key OpenShift objects are stored under, so several masters can share an etcd cluster
+	Prefix string `json:"prefix,omitempty"`
+
+	// CA is the certificate bundle used to verify the etcd server, which is not verified if empty
+	CA string `json:"ca,omitempty"`
+	// CertFile and KeyFile are the client certificate presented to the etcd server
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
+	// Username and Password authenticate the master to the etcd server with basic auth
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// EncryptionKeys encrypt OAuth tokens, build webhook secrets, and route certificates stored in etcd.  New
+	// values are encrypted with the first key, and values encrypted with any key can be read
+	EncryptionKeys []EncryptionKey `json:"encryptionKeys,omitempty"`
+}
+
+// EncryptionKey is a named key that encrypts sensitive objects stored in etcd.
+type EncryptionKey struct {
+	// Name identifies the key in the values it encrypts, and must not change
+	Name string `json:"name"`
+	// Secret is the base64 encoded 32 byte key
+	Secret string `json:"secret"`
+}
+
+// OAuthConfig configures how users authenticate with the master.
+type OAuthConfig struct {
+	// RequestHandlers are the ways requests are authenticated
+	RequestHandlers []string `json:"requestHandlers,omitempty"`
+	// Handler handles unauthenticated requests to the authorize endpoint
+	Handler string `json:"handler,omitempty"`
+	// GrantHandler decides whether to grant tokens to clients
+	GrantHandler string `json:"grantHandler,omitempty"`
+
+	// SessionSecrets sign and encrypt sessions, the first secret is used for new sessions
+	SessionSecrets []string `json:"sessionSecrets,omitempty"`
+	// SessionMaxAgeSeconds is how long a session lasts
+	SessionMaxAgeSeconds int `json:"sessionMaxAgeSeconds,omitempty"`
+	// SessionName is the name of the session cookie
+	SessionName string `json:"sessionName,omitempty"`
+
+	// PasswordAuth is how passwords are checked
+	PasswordAuth string `json:"passwordAuth,omitempty"`
+	// BasicAuthURL is the remote URL passwords are checked against when PasswordAuth is basicauthurl
+	BasicAuthURL string `json:"basicAuthURL,omitempty"`
+	// IdentityMappingMethod is how identities that have not logged in before are mapped to users: claim,
+	// lookup, add, or generate
+	IdentityMappingMethod string `json:"identityMappingMethod,omitempty"`
+
+	// TokenStore is where tokens are stored
+	TokenStore string `json:"tokenStore,omitempty"`
+	// AccessTokenMaxAgeSeconds is how long access tokens granted by the master last
+	AccessTokenMaxAgeSeconds int `json:"accessTokenMaxAgeSeconds,omitempty"`
+	// TokenFilePath is the file tokens are read from when TokenStore is file
+	TokenFilePath string `json:"tokenFilePath,omitempty"`
+
+	// GoogleClientID and GoogleClientSecret identify the master to Google
+	GoogleClientID     string `json:"googleClientID,omitempty"`
+	GoogleClientSecret string `json:"googleClientSecret,omitempty"`
+	// GithubClientID and GithubClientSecret identify the master to GitHub
+	GithubClientID     string `json:"githubClientID,omitempty"`
+	GithubClientSecret string `json:"githubClientSecret,omitempty"`
+}
+
+// NamedCertificate is a certificate served to TLS clients that request one of its names.
+type NamedCertificate struct {
+	// Names are the server names the certificate is served for, which may be wildcards like *.example.com
+	Names []string `json:"names"`
+	// CertFile is the PEM encoded certificate, followed by any intermediate certificates
+	CertFile string `json:"certFile"`
+	// KeyFile is the PEM encoded private key of the certificate
+	KeyFile string `json:"keyFile"`
+}
+
+// AssetConfig configures the branding and extensions of the web console served by the asset server.
+type AssetConfig struct {
+	// Dir holds files served in place of the embedded web console assets, if set
+	Dir string `json:"dir,omitempty"`
+	// PathPrefix serves the web console under this path on the master listener instead of on its own port, if set
+	PathPrefix string `json:"pathPrefix,omitempty"`
+	// ProxyAPI serves the APIs to the web console through the asset server, avoiding cross origin requests
+	ProxyAPI bool `json:"proxyAPI,omitempty"`
+	// ProductName replaces the product name shown by the web console, if set
+	ProductName string `json:"productName,omitempty"`
+	// LogoImageURL replaces the logo shown by the web console, if set
+	LogoImageURL string `json:"logoImageURL,omitempty"`
+	// ExtensionScripts are the URLs of scripts the web console loads after its own
+	ExtensionScripts []string `json:"extensionScripts,omitempty"`
+	// ExtensionStylesheets are the URLs of stylesheets the web console loads after its own
+	ExtensionStylesheets []string `json:"extensionStylesheets,omitempty"`
+	// LogoutURL is where the web console sends users after logging out, if set
+	LogoutURL string `json:"logoutURL,omitempty"`
+	// ContentSecurityPolicy replaces the policy derived from the API addresses and extensions, if set
+	ContentSecurityPolicy string `json:"contentSecurityPolicy,omitempty"`
+	// FrameOptions is the X-Frame-Options header of the asset server, DENY if empty
+	FrameOptions string `json:"frameOptions,omitempty"`
+	// StrictTransportSecurity is the Strict-Transport-Security header of the asset server on https, one year if empty
+	StrictTransportSecurity string `json:"strictTransportSecurity,omitempty"`
+}
+
+// AuditConfig configures the log of mutating API requests.
+type AuditConfig struct {
+	// Path is the file audit records are written to, or "-" for stdout.  Auditing is disabled if empty
+	Path string `json:"path,omitempty"`
+	// MaxSizeMegabytes is the size the audit log is rotated at, it is never rotated if zero
+	MaxSizeMegabytes int `json:"maxSizeMegabytes,omitempty"`
+	// MaxBackups is the number of rotated audit logs kept
+	MaxBackups int `json:"maxBackups,omitempty"`
+}
+
+// ImageConfig configures the images used for cluster components.
+type ImageConfig struct {
+	// Format is the image name format, where ${component} and ${version} are replaced
+	Format string `json:"format,omitempty"`
+	// Latest uses the latest images rather than those of the current release
+	Latest bool `json:"latest,omitempty"`
+	// UseLocal uses images present on the nodes without pulling them
+	UseLocal bool `json:"useLocal,omitempty"`
+}
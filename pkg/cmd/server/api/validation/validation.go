@@ -0,0 +1,195 @@
+package validation
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strings"
+
+	errs "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	"github.com/openshift/origin/pkg/api/latest"
+	"github.com/openshift/origin/pkg/cmd/server/api"
+	cmdutil "github.com/openshift/origin/pkg/cmd/util"
+	"github.com/openshift/origin/pkg/user"
+)
+
+// ValidateMasterConfig tests that the values in a master configuration file are usable.
+func ValidateMasterConfig(config *api.MasterConfig) errs.ValidationErrorList {
+	result := errs.ValidationErrorList{}
+
+	if len(config.PortalNet) > 0 {
+		if _, _, err := net.ParseCIDR(config.PortalNet); err != nil {
+			result = append(result, errs.NewFieldInvalid("portalNet", config.PortalNet, err.Error()))
+		}
+	}
+	if len(config.InsecureBindAddress) > 0 && !cmdutil.IsLoopbackHostPort(config.InsecureBindAddress) {
+		result = append(result, errs.NewFieldInvalid("insecureBindAddress", config.InsecureBindAddress, "must be a loopback host:port"))
+	}
+	if config.ShutdownGracePeriodSeconds < 0 {
+		result = append(result, errs.NewFieldInvalid("shutdownGracePeriodSeconds", config.ShutdownGracePeriodSeconds, "must not be negative"))
+	}
+	if config.MaxRequestsInFlight < 0 {
+		result = append(result, errs.NewFieldInvalid("maxRequestsInFlight", config.MaxRequestsInFlight, "must not be negative"))
+	}
+	if config.MaxRequestsInFlightPerUser < 0 {
+		result = append(result, errs.NewFieldInvalid("maxRequestsInFlightPerUser", config.MaxRequestsInFlightPerUser, "must not be negative"))
+	}
+	if config.MaxRequestsPerSecondPerUser < 0 {
+		result = append(result, errs.NewFieldInvalid("maxRequestsPerSecondPerUser", config.MaxRequestsPerSecondPerUser, "must not be negative"))
+	}
+	if config.RequestBurstPerUser < 0 {
+		result = append(result, errs.NewFieldInvalid("requestBurstPerUser", config.RequestBurstPerUser, "must not be negative"))
+	}
+	if config.MaxRequestsPerSecondPerSystemUser < 0 {
+		result = append(result, errs.NewFieldInvalid("maxRequestsPerSecondPerSystemUser", config.MaxRequestsPerSecondPerSystemUser, "must not be negative"))
+	}
+	if config.SlowRequestThresholdMilliseconds < 0 {
+		result = append(result, errs.NewFieldInvalid("slowRequestThresholdMilliseconds", config.SlowRequestThresholdMilliseconds, "must not be negative"))
+	}
+	if config.CORSMaxAgeSeconds < 0 {
+		result = append(result, errs.NewFieldInvalid("corsMaxAgeSeconds", config.CORSMaxAgeSeconds, "must not be negative"))
+	}
+	if config.ControllerLeaseTTLSeconds < 0 {
+		result = append(result, errs.NewFieldInvalid("controllerLeaseTTLSeconds", config.ControllerLeaseTTLSeconds, "must not be negative"))
+	}
+	if config.ImageRepositoryDeletionGracePeriodSeconds < 0 {
+		result = append(result, errs.NewFieldInvalid("imageRepositoryDeletionGracePeriodSeconds", config.ImageRepositoryDeletionGracePeriodSeconds, "must not be negative"))
+	}
+	if config.RouteCertificateExpiryWindowSeconds < 0 {
+		result = append(result, errs.NewFieldInvalid("routeCertificateExpiryWindowSeconds", config.RouteCertificateExpiryWindowSeconds, "must not be negative"))
+	}
+
+	known := util.NewStringSet(api.KnownControllers...)
+	seen := util.NewStringSet()
+	for i, name := range config.DisabledControllers {
+		field := fmt.Sprintf("disabledControllers[%d]", i)
+		switch {
+		case !known.Has(name):
+			result = append(result, errs.NewFieldNotSupported(field, name))
+		case seen.Has(name):
+			result = append(result, errs.NewFieldDuplicate(field, name))
+		}
+		seen.Insert(name)
+	}
+
+	result = append(result, validateEtcdConfig(&config.Etcd).Prefix("etcd")...)
+	result = append(result, validateOAuthConfig(&config.OAuth).Prefix("oauth")...)
+	for i := range config.NamedCertificates {
+		result = append(result, validateNamedCertificate(&config.NamedCertificates[i]).Prefix(fmt.Sprintf("namedCertificates[%d]", i))...)
+	}
+	result = append(result, validateAuditConfig(&config.Audit).Prefix("audit")...)
+	result = append(result, validateAssetConfig(&config.Assets).Prefix("assets")...)
+	return result
+}
+
+// validateAssetConfig tests that the web console can be served as configured.
+func validateAssetConfig(config *api.AssetConfig) errs.ValidationErrorList {
+	result := errs.ValidationErrorList{}
+
+	if len(config.PathPrefix) > 0 && !IsAssetPathPrefix(config.PathPrefix) {
+		result = append(result, errs.NewFieldInvalid("pathPrefix", config.PathPrefix, "must begin and must not end with a /"))
+	}
+	return result
+}
+
+// IsAssetPathPrefix returns true if the web console can be served under prefix on the master listener.
+func IsAssetPathPrefix(prefix string) bool {
+	return len(prefix) > 1 && strings.HasPrefix(prefix, "/") && !strings.HasSuffix(prefix, "/")
+}
+
+// validateEtcdConfig tests that the etcd client credentials are complete.
+func validateEtcdConfig(config *api.EtcdConfig) errs.ValidationErrorList {
+	result := errs.ValidationErrorList{}
+
+	if len(config.CertFile) > 0 && len(config.KeyFile) == 0 {
+		result = append(result, errs.NewFieldRequired("keyFile", config.KeyFile))
+	}
+	if len(config.KeyFile) > 0 && len(config.CertFile) == 0 {
+		result = append(result, errs.NewFieldRequired("certFile", config.CertFile))
+	}
+	if len(config.Prefix) > 0 && !strings.HasPrefix(config.Prefix, "/") {
+		result = append(result, errs.NewFieldInvalid("prefix", config.Prefix, "must start with /"))
+	}
+	if len(config.Password) > 0 && len(config.Username) == 0 {
+		result = append(result, errs.NewFieldRequired("username", config.Username))
+	}
+	if len(config.StorageVersion) > 0 && !util.NewStringSet(latest.Versions...).Has(config.StorageVersion) {
+		result = append(result, errs.NewFieldNotSupported("storageVersion", config.StorageVersion))
+	}
+	names := util.NewStringSet()
+	for i, key := range config.EncryptionKeys {
+		field := fmt.Sprintf("encryptionKeys[%d]", i)
+		switch {
+		case len(key.Name) == 0:
+			result = append(result, errs.NewFieldRequired(field+".name", key.Name))
+		case strings.Contains(key.Name, ":"):
+			result = append(result, errs.NewFieldInvalid(field+".name", key.Name, "must not contain ':'"))
+		case names.Has(key.Name):
+			result = append(result, errs.NewFieldDuplicate(field+".name", key.Name))
+		}
+		names.Insert(key.Name)
+		if secret, err := base64.StdEncoding.DecodeString(key.Secret); err != nil || len(secret) != 32 {
+			result = append(result, errs.NewFieldInvalid(field+".secret", "", "must be 32 bytes, base64 encoded"))
+		}
+	}
+	return result
+}
+
+// validateNamedCertificate tests that a named certificate has files and at least one name.
+func validateNamedCertificate(config *api.NamedCertificate) errs.ValidationErrorList {
+	result := errs.ValidationErrorList{}
+
+	if len(config.CertFile) == 0 {
+		result = append(result, errs.NewFieldRequired("certFile", config.CertFile))
+	}
+	if len(config.KeyFile) == 0 {
+		result = append(result, errs.NewFieldRequired("keyFile", config.KeyFile))
+	}
+	if len(config.Names) == 0 {
+		result = append(result, errs.NewFieldRequired("names", config.Names))
+	}
+	for i, name := range config.Names {
+		if len(name) == 0 {
+			result = append(result, errs.NewFieldRequired(fmt.Sprintf("names[%d]", i), name))
+		}
+	}
+	return result
+}
+
+// validateAuditConfig tests that the audit log rotation settings are usable.
+func validateAuditConfig(config *api.AuditConfig) errs.ValidationErrorList {
+	result := errs.ValidationErrorList{}
+
+	if config.MaxSizeMegabytes < 0 {
+		result = append(result, errs.NewFieldInvalid("maxSizeMegabytes", config.MaxSizeMegabytes, "must not be negative"))
+	}
+	if config.MaxBackups < 0 {
+		result = append(result, errs.NewFieldInvalid("maxBackups", config.MaxBackups, "must not be negative"))
+	}
+	return result
+}
+
+// validateOAuthConfig tests that the OAuth session settings are usable.
+func validateOAuthConfig(config *api.OAuthConfig) errs.ValidationErrorList {
+	result := errs.ValidationErrorList{}
+
+	if config.SessionMaxAgeSeconds < 0 {
+		result = append(result, errs.NewFieldInvalid("sessionMaxAgeSeconds", config.SessionMaxAgeSeconds, "must not be negative"))
+	}
+	if config.AccessTokenMaxAgeSeconds < 0 {
+		result = append(result, errs.NewFieldInvalid("accessTokenMaxAgeSeconds", config.AccessTokenMaxAgeSeconds, "must not be negative"))
+	}
+	switch user.MappingMethod(config.IdentityMappingMethod) {
+	case "", user.MappingMethodClaim, user.MappingMethodLookup, user.MappingMethodAdd, user.MappingMethodGenerate:
+	default:
+		result = append(result, errs.NewFieldNotSupported("identityMappingMethod", config.IdentityMappingMethod))
+	}
+	for i, secret := range config.SessionSecrets {
+		if len(secret) == 0 {
+			result = append(result, errs.NewFieldRequired(fmt.Sprintf("sessionSecrets[%d]", i), secret))
+		}
+	}
+	return result
+}
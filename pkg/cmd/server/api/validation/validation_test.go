@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/openshift/origin/pkg/cmd/server/api"
+)
+
+// testEncryptionSecret is a base64 encoded 32 byte key
+const testEncryptionSecret = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
+func TestValidateMasterConfig(t *testing.T) {
+	testCases := map[string]struct {
+		config         api.MasterConfig
+		expectedErrors int
+	}{
+		"empty": {api.MasterConfig{}, 0},
+		"valid": {api.MasterConfig{
+			PortalNet:           "172.30.17.0/24",
+			DisabledControllers: []string{api.BuildController, api.QuotaUsageController},
+			OAuth:               api.OAuthConfig{SessionSecrets: []string{"secret"}, SessionMaxAgeSeconds: 300},
+		}, 0},
+		"invalid portal net":         {api.MasterConfig{PortalNet: "172.30.17.0"}, 1},
+		"loopback insecure address":  {api.MasterConfig{InsecureBindAddress: "127.0.0.1:8080"}, 0},
+		"public insecure address":    {api.MasterConfig{InsecureBindAddress: "0.0.0.0:8080"}, 1},
+		"insecure address no port":   {api.MasterConfig{InsecureBindAddress: "localhost"}, 1},
+		"named certificate":          {api.MasterConfig{NamedCertificates: []api.NamedCertificate{{Names: []string{"*.example.com"}, CertFile: "a.crt", KeyFile: "a.key"}}}, 0},
+		"named certificate no files": {api.MasterConfig{NamedCertificates: []api.NamedCertificate{{Names: []string{"example.com"}}}}, 2},
+		"named certificate no names": {api.MasterConfig{NamedCertificates: []api.NamedCertificate{{CertFile: "a.crt", KeyFile: "a.key"}}}, 1},
+		"etcd client certificate":    {api.MasterConfig{Etcd: api.EtcdConfig{CA: "ca.crt", CertFile: "etcd.crt", KeyFile: "etcd.key", Username: "master", Password: "secret"}}, 0},
+		"etcd certificate no key":    {api.MasterConfig{Etcd: api.EtcdConfig{CertFile: "etcd.crt"}}, 1},
+		"etcd key no certificate":    {api.MasterConfig{Etcd: api.EtcdConfig{KeyFile: "etcd.key"}}, 1},
+		"etcd prefix":                {api.MasterConfig{Etcd: api.EtcdConfig{Prefix: "/openshift.io"}}, 0},
+		"relative etcd prefix":       {api.MasterConfig{Etcd: api.EtcdConfig{Prefix: "openshift.io"}}, 1},
+		"etcd password no username":  {api.MasterConfig{Etcd: api.EtcdConfig{Password: "secret"}}, 1},
+		"etcd storage version":       {api.MasterConfig{Etcd: api.EtcdConfig{StorageVersion: "v1beta2"}}, 0},
+		"unknown storage version":    {api.MasterConfig{Etcd: api.EtcdConfig{StorageVersion: "v2"}}, 1},
+		"etcd encryption keys":       {api.MasterConfig{Etcd: api.EtcdConfig{EncryptionKeys: []api.EncryptionKey{{Name: "b", Secret: testEncryptionSecret}, {Name: "a", Secret: testEncryptionSecret}}}}, 0},
+		"etcd encryption key name":   {api.MasterConfig{Etcd: api.EtcdConfig{EncryptionKeys: []api.EncryptionKey{{Secret: testEncryptionSecret}, {Name: "a:b", Secret: testEncryptionSecret}}}}, 2},
+		"duplicate encryption key":   {api.MasterConfig{Etcd: api.EtcdConfig{EncryptionKeys: []api.EncryptionKey{{Name: "a", Secret: testEncryptionSecret}, {Name: "a", Secret: testEncryptionSecret}}}}, 1},
+		"short encryption key":       {api.MasterConfig{Etcd: api.EtcdConfig{EncryptionKeys: []api.EncryptionKey{{Name: "a", Secret: "c2VjcmV0"}}}}, 1},
+		"negative grace period":      {api.MasterConfig{ShutdownGracePeriodSeconds: -1}, 1},
+		"negative max in flight":     {api.MasterConfig{MaxRequestsInFlight: -1, MaxRequestsInFlightPerUser: -1}, 2},
+		"negative request rate":      {api.MasterConfig{MaxRequestsPerSecondPerUser: -1, RequestBurstPerUser: -1}, 2},
+		"negative slow threshold":    {api.MasterConfig{SlowRequestThresholdMilliseconds: -1}, 1},
+		"negative controller lease":  {api.MasterConfig{ControllerLeaseTTLSeconds: -1}, 1},
+		"negative CORS max age":      {api.MasterConfig{CORSMaxAgeSeconds: -1}, 1},
+		"unknown controller":         {api.MasterConfig{DisabledControllers: []string{"scheduler"}}, 1},
+		"duplicate controller":       {api.MasterConfig{DisabledControllers: []string{api.BuildController, api.BuildController}}, 1},
+		"empty session secret":       {api.MasterConfig{OAuth: api.OAuthConfig{SessionSecrets: []string{""}}}, 1},
+		"negative session max age":   {api.MasterConfig{OAuth: api.OAuthConfig{SessionMaxAgeSeconds: -1}}, 1},
+		"negative token max age":     {api.MasterConfig{OAuth: api.OAuthConfig{AccessTokenMaxAgeSeconds: -1}}, 1},
+		"unknown mapping method":     {api.MasterConfig{OAuth: api.OAuthConfig{IdentityMappingMethod: "merge"}}, 1},
+		"negative audit log size":    {api.MasterConfig{Audit: api.AuditConfig{Path: "audit.log", MaxSizeMegabytes: -1}}, 1},
+		"negative audit backups":     {api.MasterConfig{Audit: api.AuditConfig{Path: "audit.log", MaxBackups: -1}}, 1},
+		"relative asset path prefix": {api.MasterConfig{Assets: api.AssetConfig{PathPrefix: "console"}}, 1},
+		"root asset path prefix":     {api.MasterConfig{Assets: api.AssetConfig{PathPrefix: "/"}}, 1},
+	}
+
+	for name, tc := range testCases {
+		if errs := ValidateMasterConfig(&tc.config); len(errs) != tc.expectedErrors {
+			t.Errorf("%s: expected %d errors, got %v", name, tc.expectedErrors, errs)
+		}
+	}
+}
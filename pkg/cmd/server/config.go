@@ -0,0 +1,405 @@
+package server
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/origin/pkg/cmd/flagtypes"
+	configapi "github.com/openshift/origin/pkg/cmd/server/api"
+	configapilatest "github.com/openshift/origin/pkg/cmd/server/api/latest"
+	configvalidation "github.com/openshift/origin/pkg/cmd/server/api/validation"
+	"github.com/openshift/origin/pkg/cmd/server/origin"
+	"github.com/openshift/origin/pkg/user"
+)
+
+// loadMasterConfig sets the values of cfg that are not flags from the environment, then applies the master
+// configuration file named by cfg.ConfigFile, if any.
+func loadMasterConfig(cfg *config, flags *pflag.FlagSet) error {
+	cfg.OAuth = defaultOAuthConfig()
+	cfg.UseLocalImages = env("USE_LOCAL_IMAGES", "false") == "true"
+	cfg.EtcdPassword = env("ETCD_PASSWORD", "")
+
+	if len(cfg.ConfigFile) == 0 {
+		return nil
+	}
+	masterConfig, err := configapilatest.ReadMasterConfig(cfg.ConfigFile)
+	if err != nil {
+		return err
+	}
+	if errs := configvalidation.ValidateMasterConfig(masterConfig); len(errs) > 0 {
+		return fmt.Errorf("invalid master configuration %s: %v", cfg.ConfigFile, errs)
+	}
+	return applyMasterConfig(cfg, masterConfig, flags)
+}
+
+// writeMasterConfig writes the master configuration file equivalent to cfg to cfg.WriteConfigFile, or to
+// stdout if it is "-".
+func writeMasterConfig(cfg *config) error {
+	data, err := configapilatest.WriteYAML(masterConfigFromFlags(cfg))
+	if err != nil {
+		return err
+	}
+	if cfg.WriteConfigFile == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return ioutil.WriteFile(cfg.WriteConfigFile, data, 0600)
+}
+
+// defaultOAuthConfig returns the OAuth configuration set by the ORIGIN_OAUTH_* environment variables.
+func defaultOAuthConfig() configapi.OAuthConfig {
+	// Default to a session authenticator (for browsers), and a basicauth authenticator (for clients responding to WWW-Authenticate challenges)
+	defaultAuthRequestHandlers := strings.Join([]string{
+		string(origin.AuthRequestHandlerSession),
+		string(origin.AuthRequestHandlerBasicAuth),
+	}, ",")
+
+	// Sessions need to last as long as we expect the grant flow to take
+	// Session auth is invalidated at the end of an authorize flow, so it can only be used once
+	sessionMaxAgeSeconds, err := strconv.ParseInt(env("ORIGIN_OAUTH_SESSION_MAX_AGE_SECONDS", "300"), 10, 0)
+	if err != nil || sessionMaxAgeSeconds <= 0 {
+		sessionMaxAgeSeconds = 300
+	}
+	accessTokenMaxAgeSeconds, err := strconv.ParseInt(env("ORIGIN_OAUTH_ACCESS_TOKEN_MAX_AGE_SECONDS", "3600"), 10, 0)
+	if err != nil || accessTokenMaxAgeSeconds <= 0 {
+		accessTokenMaxAgeSeconds = 3600
+	}
+
+	return configapi.OAuthConfig{
+		RequestHandlers: strings.Split(env("ORIGIN_OAUTH_REQUEST_HANDLERS", defaultAuthRequestHandlers), ","),
+		Handler:         env("ORIGIN_OAUTH_HANDLER", string(origin.AuthHandlerLogin)),
+		GrantHandler:    env("ORIGIN_OAUTH_GRANT_HANDLER", string(origin.GrantHandlerAuto)),
+		// Session config
+		SessionSecrets:       []string{env("ORIGIN_OAUTH_SESSION_SECRET", "secret12345")},
+		SessionMaxAgeSeconds: int(sessionMaxAgeSeconds),
+		SessionName:          env("ORIGIN_OAUTH_SESSION_NAME", "ssn"),
+		// Password config
+		PasswordAuth: env("ORIGIN_OAUTH_PASSWORD_AUTH", string(origin.PasswordAuthAnyPassword)),
+		BasicAuthURL: env("ORIGIN_OAUTH_BASIC_AUTH_URL", ""),
+		// Identity config
+		IdentityMappingMethod: env("ORIGIN_OAUTH_IDENTITY_MAPPING_METHOD", string(user.MappingMethodClaim)),
+		// Token config
+		TokenStore:               env("ORIGIN_OAUTH_TOKEN_STORE", string(origin.TokenStoreEtcd)),
+		TokenFilePath:            env("ORIGIN_OAUTH_TOKEN_FILE_PATH", ""),
+		AccessTokenMaxAgeSeconds: int(accessTokenMaxAgeSeconds),
+		// Google config
+		GoogleClientID:     env("ORIGIN_OAUTH_GOOGLE_CLIENT_ID", ""),
+		GoogleClientSecret: env("ORIGIN_OAUTH_GOOGLE_CLIENT_SECRET", ""),
+		// Github config
+		GithubClientID:     env("ORIGIN_OAUTH_GITHUB_CLIENT_ID", ""),
+		GithubClientSecret: env("ORIGIN_OAUTH_GITHUB_CLIENT_SECRET", ""),
+	}
+}
+
+// masterConfigFromFlags returns the master configuration file equivalent to cfg.  Addresses are only included
+// if they were provided, so that the master derives the same defaults when it starts from the file.
+func masterConfigFromFlags(cfg *config) *configapi.MasterConfig {
+	masterConfig := &configapi.MasterConfig{
+		PortalNet:                         cfg.PortalNet.String(),
+		NodeList:                          cfg.NodeList,
+		CORSAllowedOrigins:                cfg.CORSAllowedOrigins,
+		CORSAllowedMethods:                cfg.CORSAllowedMethods,
+		CORSAllowedHeaders:                cfg.CORSAllowedHeaders,
+		CORSExposedHeaders:                cfg.CORSExposedHeaders,
+		CORSMaxAgeSeconds:                 int(cfg.CORSMaxAge / time.Second),
+		CertDir:                           cfg.CertDir,
+		AdminKubeConfig:                   cfg.AdminKubeConfig,
+		ShutdownGracePeriodSeconds:        int(cfg.ShutdownGracePeriod / time.Second),
+		MaxRequestsInFlight:               cfg.MaxRequestsInFlight,
+		MaxRequestsInFlightPerUser:        cfg.MaxRequestsInFlightPerUser,
+		MaxRequestsPerSecondPerUser:       cfg.MaxRequestsPerSecondPerUser,
+		RequestBurstPerUser:               cfg.RequestBurstPerUser,
+		MaxRequestsPerSecondPerSystemUser: cfg.MaxRequestsPerSecondPerSystemUser,
+		SlowRequestThresholdMilliseconds:  int(cfg.SlowRequestThreshold / time.Millisecond),
+		ControllerLeaseTTLSeconds:         int(cfg.ControllerLeaseTTL / time.Second),
+		Etcd: configapi.EtcdConfig{
+			DataDir:        cfg.EtcdDir,
+			StorageVersion: cfg.StorageVersion,
+			Prefix:         cfg.EtcdPrefix,
+			CA:             cfg.EtcdCA,
+			CertFile:       cfg.EtcdCertFile,
+			KeyFile:        cfg.EtcdKeyFile,
+			Username:       cfg.EtcdUsername,
+			Password:       cfg.EtcdPassword,
+			EncryptionKeys: cfg.EtcdEncryptionKeys,
+		},
+		OAuth: cfg.OAuth,
+		Images: configapi.ImageConfig{
+			Format:   cfg.ImageFormat,
+			Latest:   cfg.LatestReleaseImages,
+			UseLocal: cfg.UseLocalImages,
+		},
+		DisabledControllers: cfg.DisabledControllers,
+		NamedCertificates:   cfg.NamedCertificates,
+		Audit:               cfg.Audit,
+		Assets: configapi.AssetConfig{
+			Dir:                     cfg.AssetDir,
+			PathPrefix:              cfg.AssetPathPrefix,
+			ProxyAPI:                cfg.AssetProxyAPI,
+			ProductName:             cfg.AssetProductName,
+			LogoImageURL:            cfg.AssetLogoImageURL,
+			ExtensionScripts:        cfg.AssetExtensionScripts,
+			ExtensionStylesheets:    cfg.AssetExtensionStylesheets,
+			LogoutURL:               cfg.AssetLogoutURL,
+			ContentSecurityPolicy:   cfg.AssetContentSecurityPolicy,
+			FrameOptions:            cfg.AssetFrameOptions,
+			StrictTransportSecurity: cfg.AssetStrictTransportSecurity,
+		},
+		ProjectRequestTemplate:                    cfg.ProjectRequestTemplate,
+		AllowRouteHostSharing:                     cfg.AllowRouteHostSharing,
+		ImageRepositoryDeletionGracePeriodSeconds: int(cfg.ImageRepositoryDeletionGracePeriod / time.Second),
+		RouteCertificateExpiryWindowSeconds:       int(cfg.RouteCertificateExpiryWindow / time.Second),
+		BuildSourceSecretsDir:                     cfg.BuildSourceSecretsDir,
+		AllowedBuilderImages:                      cfg.AllowedBuilderImages,
+		ReconcileBootstrapPolicy:                  cfg.ReconcileBootstrapPolicy,
+	}
+
+	providedAddr := func(addr *flagtypes.Addr) string {
+		if addr.Provided {
+			return addr.String()
+		}
+		return ""
+	}
+	masterConfig.BindAddress = providedAddr(&cfg.BindAddr)
+	masterConfig.InsecureBindAddress = cfg.InsecureBindAddr
+	masterConfig.MasterAddress = providedAddr(&cfg.MasterAddr)
+	masterConfig.MasterPublicAddress = providedAddr(&cfg.MasterPublicAddr)
+	masterConfig.KubernetesAddress = providedAddr(&cfg.KubernetesAddr)
+	masterConfig.KubernetesPublicAddress = providedAddr(&cfg.KubernetesPublicAddr)
+	masterConfig.Etcd.Address = providedAddr(&cfg.EtcdAddr)
+
+	return masterConfig
+}
+
+// applyMasterConfig sets the values of the master configuration file on cfg.  Values set with flags on the
+// command line take precedence over the file, and empty values in the file leave the defaults in place.
+func applyMasterConfig(cfg *config, masterConfig *configapi.MasterConfig, flags *pflag.FlagSet) error {
+	unset := func(name string) bool {
+		flag := flags.Lookup(name)
+		return flag == nil || !flag.Changed
+	}
+	setValue := func(name string, value pflag.Value, s string) error {
+		if len(s) == 0 || !unset(name) {
+			return nil
+		}
+		if err := value.Set(s); err != nil {
+			return fmt.Errorf("invalid value %q for %s: %v", s, name, err)
+		}
+		return nil
+	}
+
+	for _, v := range []struct {
+		name  string
+		value pflag.Value
+		s     string
+	}{
+		{"listen", &cfg.BindAddr, masterConfig.BindAddress},
+		{"master", &cfg.MasterAddr, masterConfig.MasterAddress},
+		{"public-master", &cfg.MasterPublicAddr, masterConfig.MasterPublicAddress},
+		{"kubernetes", &cfg.KubernetesAddr, masterConfig.KubernetesAddress},
+		{"public-kubernetes", &cfg.KubernetesPublicAddr, masterConfig.KubernetesPublicAddress},
+		{"etcd", &cfg.EtcdAddr, masterConfig.Etcd.Address},
+		{"portal-net", &cfg.PortalNet, masterConfig.PortalNet},
+	} {
+		if err := setValue(v.name, v.value, v.s); err != nil {
+			return err
+		}
+	}
+
+	if len(masterConfig.InsecureBindAddress) > 0 && unset("insecure-listen") {
+		cfg.InsecureBindAddr = masterConfig.InsecureBindAddress
+	}
+	if len(masterConfig.NodeList) > 0 && unset("nodes") {
+		cfg.NodeList = masterConfig.NodeList
+	}
+	if len(masterConfig.CORSAllowedOrigins) > 0 && unset("cors-allowed-origins") {
+		cfg.CORSAllowedOrigins = masterConfig.CORSAllowedOrigins
+	}
+	if len(masterConfig.CORSAllowedMethods) > 0 && unset("cors-allowed-methods") {
+		cfg.CORSAllowedMethods = masterConfig.CORSAllowedMethods
+	}
+	if len(masterConfig.CORSAllowedHeaders) > 0 && unset("cors-allowed-headers") {
+		cfg.CORSAllowedHeaders = masterConfig.CORSAllowedHeaders
+	}
+	if len(masterConfig.CORSExposedHeaders) > 0 && unset("cors-exposed-headers") {
+		cfg.CORSExposedHeaders = masterConfig.CORSExposedHeaders
+	}
+	if masterConfig.CORSMaxAgeSeconds > 0 && unset("cors-max-age") {
+		cfg.CORSMaxAge = time.Duration(masterConfig.CORSMaxAgeSeconds) * time.Second
+	}
+	if len(masterConfig.CertDir) > 0 && unset("cert-dir") {
+		cfg.CertDir = masterConfig.CertDir
+	}
+	if len(masterConfig.AdminKubeConfig) > 0 && unset("admin-kubeconfig") {
+		cfg.AdminKubeConfig = masterConfig.AdminKubeConfig
+	}
+	if masterConfig.ShutdownGracePeriodSeconds > 0 && unset("shutdown-grace-period") {
+		cfg.ShutdownGracePeriod = time.Duration(masterConfig.ShutdownGracePeriodSeconds) * time.Second
+	}
+	if masterConfig.ControllerLeaseTTLSeconds > 0 && unset("controller-lease-ttl") {
+		cfg.ControllerLeaseTTL = time.Duration(masterConfig.ControllerLeaseTTLSeconds) * time.Second
+	}
+	if masterConfig.MaxRequestsInFlight > 0 && unset("max-requests-inflight") {
+		cfg.MaxRequestsInFlight = masterConfig.MaxRequestsInFlight
+	}
+	if masterConfig.MaxRequestsInFlightPerUser > 0 && unset("max-requests-inflight-per-user") {
+		cfg.MaxRequestsInFlightPerUser = masterConfig.MaxRequestsInFlightPerUser
+	}
+	if masterConfig.MaxRequestsPerSecondPerUser > 0 && unset("max-requests-per-second-per-user") {
+		cfg.MaxRequestsPerSecondPerUser = masterConfig.MaxRequestsPerSecondPerUser
+	}
+	if masterConfig.RequestBurstPerUser > 0 && unset("request-burst-per-user") {
+		cfg.RequestBurstPerUser = masterConfig.RequestBurstPerUser
+	}
+	if masterConfig.MaxRequestsPerSecondPerSystemUser > 0 && unset("max-requests-per-second-per-system-user") {
+		cfg.MaxRequestsPerSecondPerSystemUser = masterConfig.MaxRequestsPerSecondPerSystemUser
+	}
+	if masterConfig.SlowRequestThresholdMilliseconds > 0 && unset("slow-request-threshold") {
+		cfg.SlowRequestThreshold = time.Duration(masterConfig.SlowRequestThresholdMilliseconds) * time.Millisecond
+	}
+	if len(masterConfig.Etcd.DataDir) > 0 && unset("etcd-dir") {
+		cfg.EtcdDir = masterConfig.Etcd.DataDir
+	}
+	if len(masterConfig.Etcd.Prefix) > 0 && unset("etcd-prefix") {
+		cfg.EtcdPrefix = masterConfig.Etcd.Prefix
+	}
+	if len(masterConfig.Etcd.CA) > 0 && unset("etcd-ca") {
+		cfg.EtcdCA = masterConfig.Etcd.CA
+	}
+	if len(masterConfig.Etcd.CertFile) > 0 && unset("etcd-cert") {
+		cfg.EtcdCertFile = masterConfig.Etcd.CertFile
+	}
+	if len(masterConfig.Etcd.KeyFile) > 0 && unset("etcd-key") {
+		cfg.EtcdKeyFile = masterConfig.Etcd.KeyFile
+	}
+	if len(masterConfig.Etcd.Username) > 0 && unset("etcd-username") {
+		cfg.EtcdUsername = masterConfig.Etcd.Username
+	}
+	if len(masterConfig.Etcd.Password) > 0 {
+		cfg.EtcdPassword = masterConfig.Etcd.Password
+	}
+	cfg.EtcdEncryptionKeys = append(cfg.EtcdEncryptionKeys, masterConfig.Etcd.EncryptionKeys...)
+	if len(masterConfig.Etcd.StorageVersion) > 0 && unset("storage-version") {
+		cfg.StorageVersion = masterConfig.Etcd.StorageVersion
+	}
+	if len(masterConfig.Images.Format) > 0 && unset("images") {
+		cfg.ImageFormat = masterConfig.Images.Format
+	}
+	if masterConfig.Images.Latest && unset("latest-images") {
+		cfg.LatestReleaseImages = true
+	}
+	if masterConfig.Images.UseLocal {
+		cfg.UseLocalImages = true
+	}
+	if len(masterConfig.ProjectRequestTemplate) > 0 && unset("project-request-template") {
+		cfg.ProjectRequestTemplate = masterConfig.ProjectRequestTemplate
+	}
+	if masterConfig.AllowRouteHostSharing && unset("allow-route-host-sharing") {
+		cfg.AllowRouteHostSharing = true
+	}
+	if masterConfig.ImageRepositoryDeletionGracePeriodSeconds > 0 && unset("image-repository-deletion-grace-period") {
+		cfg.ImageRepositoryDeletionGracePeriod = time.Duration(masterConfig.ImageRepositoryDeletionGracePeriodSeconds) * time.Second
+	}
+	if masterConfig.RouteCertificateExpiryWindowSeconds > 0 && unset("route-certificate-expiry-window") {
+		cfg.RouteCertificateExpiryWindow = time.Duration(masterConfig.RouteCertificateExpiryWindowSeconds) * time.Second
+	}
+	if len(masterConfig.BuildSourceSecretsDir) > 0 && unset("build-source-secrets-dir") {
+		cfg.BuildSourceSecretsDir = masterConfig.BuildSourceSecretsDir
+	}
+	if len(masterConfig.AllowedBuilderImages) > 0 && unset("allowed-builder-images") {
+		cfg.AllowedBuilderImages = masterConfig.AllowedBuilderImages
+	}
+	if masterConfig.ReconcileBootstrapPolicy && unset("reconcile-bootstrap-policy") {
+		cfg.ReconcileBootstrapPolicy = true
+	}
+	if len(masterConfig.Assets.Dir) > 0 && unset("asset-dir") {
+		cfg.AssetDir = masterConfig.Assets.Dir
+	}
+	if len(masterConfig.Assets.PathPrefix) > 0 && unset("asset-path-prefix") {
+		cfg.AssetPathPrefix = masterConfig.Assets.PathPrefix
+	}
+	if masterConfig.Assets.ProxyAPI && unset("asset-proxy-api") {
+		cfg.AssetProxyAPI = true
+	}
+	if len(masterConfig.Assets.ProductName) > 0 && unset("asset-product-name") {
+		cfg.AssetProductName = masterConfig.Assets.ProductName
+	}
+	if len(masterConfig.Assets.LogoImageURL) > 0 && unset("asset-logo-url") {
+		cfg.AssetLogoImageURL = masterConfig.Assets.LogoImageURL
+	}
+	if len(masterConfig.Assets.ExtensionScripts) > 0 && unset("asset-extension-scripts") {
+		cfg.AssetExtensionScripts = masterConfig.Assets.ExtensionScripts
+	}
+	if len(masterConfig.Assets.ExtensionStylesheets) > 0 && unset("asset-extension-stylesheets") {
+		cfg.AssetExtensionStylesheets = masterConfig.Assets.ExtensionStylesheets
+	}
+	if len(masterConfig.Assets.LogoutURL) > 0 && unset("asset-logout-url") {
+		cfg.AssetLogoutURL = masterConfig.Assets.LogoutURL
+	}
+	if len(masterConfig.Assets.ContentSecurityPolicy) > 0 && unset("asset-content-security-policy") {
+		cfg.AssetContentSecurityPolicy = masterConfig.Assets.ContentSecurityPolicy
+	}
+	if len(masterConfig.Assets.FrameOptions) > 0 && unset("asset-frame-options") {
+		cfg.AssetFrameOptions = masterConfig.Assets.FrameOptions
+	}
+	if len(masterConfig.Assets.StrictTransportSecurity) > 0 && unset("asset-strict-transport-security") {
+		cfg.AssetStrictTransportSecurity = masterConfig.Assets.StrictTransportSecurity
+	}
+	cfg.DisabledControllers = append(cfg.DisabledControllers, masterConfig.DisabledControllers...)
+	cfg.NamedCertificates = append(cfg.NamedCertificates, masterConfig.NamedCertificates...)
+	if len(masterConfig.Audit.Path) > 0 && unset("audit-log") {
+		cfg.Audit.Path = masterConfig.Audit.Path
+	}
+	if masterConfig.Audit.MaxSizeMegabytes > 0 && unset("audit-log-max-size") {
+		cfg.Audit.MaxSizeMegabytes = masterConfig.Audit.MaxSizeMegabytes
+	}
+	if masterConfig.Audit.MaxBackups > 0 && unset("audit-log-max-backups") {
+		cfg.Audit.MaxBackups = masterConfig.Audit.MaxBackups
+	}
+
+	applyOAuthConfig(&cfg.OAuth, &masterConfig.OAuth)
+	return nil
+}
+
+// applyOAuthConfig sets the non-empty values of from on to.
+func applyOAuthConfig(to, from *configapi.OAuthConfig) {
+	if len(from.RequestHandlers) > 0 {
+		to.RequestHandlers = from.RequestHandlers
+	}
+	if len(from.SessionSecrets) > 0 {
+		to.SessionSecrets = from.SessionSecrets
+	}
+	if from.SessionMaxAgeSeconds > 0 {
+		to.SessionMaxAgeSeconds = from.SessionMaxAgeSeconds
+	}
+	if from.AccessTokenMaxAgeSeconds > 0 {
+		to.AccessTokenMaxAgeSeconds = from.AccessTokenMaxAgeSeconds
+	}
+	for _, v := range []struct {
+		to   *string
+		from string
+	}{
+		{&to.Handler, from.Handler},
+		{&to.GrantHandler, from.GrantHandler},
+		{&to.SessionName, from.SessionName},
+		{&to.PasswordAuth, from.PasswordAuth},
+		{&to.BasicAuthURL, from.BasicAuthURL},
+		{&to.IdentityMappingMethod, from.IdentityMappingMethod},
+		{&to.TokenStore, from.TokenStore},
+		{&to.TokenFilePath, from.TokenFilePath},
+		{&to.GoogleClientID, from.GoogleClientID},
+		{&to.GoogleClientSecret, from.GoogleClientSecret},
+		{&to.GithubClientID, from.GithubClientID},
+		{&to.GithubClientSecret, from.GithubClientSecret},
+	} {
+		if len(v.from) > 0 {
+			*v.to = v.from
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/origin/pkg/cmd/flagtypes"
+	configapi "github.com/openshift/origin/pkg/cmd/server/api"
+)
+
+func TestApplyMasterConfigFlagsTakePrecedence(t *testing.T) {
+	cfg := &config{
+		EtcdAddr:   flagtypes.Addr{Value: "0.0.0.0:4001", DefaultScheme: "http", DefaultPort: 4001}.Default(),
+		MasterAddr: flagtypes.Addr{Value: "localhost:8443", DefaultScheme: "https", DefaultPort: 8443, AllowPrefix: true}.Default(),
+		PortalNet:  flagtypes.DefaultIPNet("172.30.17.0/24"),
+	}
+	flags := pflag.NewFlagSet("start", pflag.ContinueOnError)
+	flags.Var(&cfg.EtcdAddr, "etcd", "")
+	flags.Var(&cfg.MasterAddr, "master", "")
+	flags.Var(&cfg.PortalNet, "portal-net", "")
+	if err := flags.Parse([]string{"--etcd=etcd.example.com:4001"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cfg.OAuth = configapi.OAuthConfig{GrantHandler: "auto", SessionName: "ssn"}
+
+	masterConfig := &configapi.MasterConfig{
+		MasterAddress:              "master.example.com",
+		PortalNet:                  "10.0.0.0/16",
+		ShutdownGracePeriodSeconds: 30,
+		CORSMaxAgeSeconds:          60,
+		AdminKubeConfig:            "/etc/openshift/admin.kubeconfig",
+		Etcd:                       configapi.EtcdConfig{Address: "other.example.com:4001"},
+		OAuth:                      configapi.OAuthConfig{GrantHandler: "prompt", AccessTokenMaxAgeSeconds: 600, IdentityMappingMethod: "generate"},
+		DisabledControllers:        []string{configapi.QuotaUsageController},
+		Assets:                     configapi.AssetConfig{ProductName: "Example", ExtensionScripts: []string{"https://example.com/a.js"}, FrameOptions: "SAMEORIGIN"},
+	}
+	if err := applyMasterConfig(cfg, masterConfig, flags); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.EtcdAddr.URL.Host != "etcd.example.com:4001" {
+		t.Errorf("Expected the etcd flag to take precedence, got %s", cfg.EtcdAddr.String())
+	}
+	if !cfg.MasterAddr.Provided || cfg.MasterAddr.URL.Host != "master.example.com:8443" {
+		t.Errorf("Expected the master address from the file, got %s", cfg.MasterAddr.String())
+	}
+	if cfg.PortalNet.String() != "10.0.0.0/16" {
+		t.Errorf("Expected the portal net from the file, got %s", cfg.PortalNet.String())
+	}
+	if cfg.CORSMaxAge != time.Minute {
+		t.Errorf("Expected the CORS max age from the file, got %v", cfg.CORSMaxAge)
+	}
+	if cfg.ShutdownGracePeriod != 30*time.Second {
+		t.Errorf("Expected the grace period from the file, got %v", cfg.ShutdownGracePeriod)
+	}
+	if cfg.OAuth.GrantHandler != "prompt" || cfg.OAuth.SessionName != "ssn" || cfg.OAuth.AccessTokenMaxAgeSeconds != 600 || cfg.OAuth.IdentityMappingMethod != "generate" {
+		t.Errorf("Expected the file to override only the OAuth values it sets, got %#v", cfg.OAuth)
+	}
+	if len(cfg.DisabledControllers) != 1 || cfg.DisabledControllers[0] != configapi.QuotaUsageController {
+		t.Errorf("Expected the disabled controllers from the file, got %v", cfg.DisabledControllers)
+	}
+	if cfg.AssetProductName != "Example" || len(cfg.AssetExtensionScripts) != 1 || len(cfg.AssetExtensionStylesheets) != 0 {
+		t.Errorf("Expected the asset config from the file, got %q %v %v", cfg.AssetProductName, cfg.AssetExtensionScripts, cfg.AssetExtensionStylesheets)
+	}
+	if cfg.AdminKubeConfig != "/etc/openshift/admin.kubeconfig" {
+		t.Errorf("Expected the admin client config path from the file, got %q", cfg.AdminKubeConfig)
+	}
+	if cfg.AssetFrameOptions != "SAMEORIGIN" {
+		t.Errorf("Expected the frame options from the file, got %q", cfg.AssetFrameOptions)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
@@ -148,20 +149,27 @@ func writeClientCertsToDir(client *kclient.Config, dir string) error {
 }
 
 func writeKubeConfigToDir(client *kclient.Config, username string, dir string) error {
+	return WriteKubeConfig(client, username, filepath.Join(dir, ".kubeconfig"))
+}
+
+// WriteKubeConfig writes a client config file to path that connects to client.Host as username, with the
+// credentials of client.  The certificate and key files of client are referenced relative to the directory of path.
+func WriteKubeConfig(client *kclient.Config, username string, path string) error {
+	dir := filepath.Dir(path)
 	// mkdir
 	if err := os.MkdirAll(dir, os.FileMode(0755)); err != nil {
 		return err
 	}
 
-	caFile, err := filepath.Rel(dir, client.CAFile)
+	caFile, err := relativePath(dir, client.CAFile)
 	if err != nil {
 		return err
 	}
-	certFile, err := filepath.Rel(dir, client.CertFile)
+	certFile, err := relativePath(dir, client.CertFile)
 	if err != nil {
 		return err
 	}
-	keyFile, err := filepath.Rel(dir, client.KeyFile)
+	keyFile, err := relativePath(dir, client.KeyFile)
 	if err != nil {
 		return err
 	}
@@ -192,13 +200,28 @@ func writeKubeConfigToDir(client *kclient.Config, username string, dir string) e
 		CurrentContext: contextName,
 	}
 
-	if err := clientcmd.WriteToFile(*config, filepath.Join(dir, ".kubeconfig")); err != nil {
+	if err := clientcmd.WriteToFile(*config, path); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// relativePath returns path relative to dir, or "" if path is empty.
+func relativePath(dir, path string) (string, error) {
+	if len(path) == 0 {
+		return "", nil
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return "", err
+	}
+	return filepath.Rel(dir, path)
+}
+
 func certsFromPEM(pemCerts []byte) ([]*x509.Certificate, error) {
 	ok := false
 	certs := []*x509.Certificate{}
@@ -231,6 +254,9 @@ var (
 	// Default templates to last for a year
 	lifetime = time.Hour * 24 * 365
 
+	// Server certificates are regenerated once they expire within a month
+	renewBefore = time.Hour * 24 * 30
+
 	// Default keys are 2048 bits
 	keyBits = 2048
 )
@@ -240,6 +266,9 @@ type CA struct {
 	SerialFile string
 	Serial     int64
 	Config     *TLSCertificateConfig
+
+	// serialLock guards Serial and SerialFile while certificates are signed
+	serialLock sync.Mutex
 }
 
 // InitCA ensures a certificate authority structure exists in the given directory, creating it if necessary:
@@ -309,6 +338,7 @@ func InitCA(dir string, name string) (*CA, error) {
 //	DNSNames subjectAltNames containing all specified hostnames
 //	IPAddresses subjectAltNames containing all specified hostnames which are IP addresses
 //	ExtKeyUsage: ExtKeyUsageServerAuth
+// An existing certificate is regenerated if it is missing any of the hostnames or expires within a month.
 func (ca *CA) MakeServerCert(name string, hostnames []string) (*TLSCertificateConfig, error) {
 	serverDir := filepath.Join(ca.Dir, name)
 
@@ -318,12 +348,15 @@ func (ca *CA) MakeServerCert(name string, hostnames []string) (*TLSCertificateCo
 		ips, dns := IPAddressesDNSNames(hostnames)
 		missingIps := ipsNotInSlice(ips, cert.IPAddresses)
 		missingDns := stringsNotInSlice(dns, cert.DNSNames)
-		if len(missingIps) == 0 && len(missingDns) == 0 {
+		switch {
+		case len(missingIps) != 0 || len(missingDns) != 0:
+			glog.Infof("Existing server certificate in %s was missing some hostnames (%v) or IP addresses (%v)", serverDir, missingDns, missingIps)
+		case certificateExpiring(cert):
+			glog.Infof("Existing server certificate in %s expires at %v", serverDir, cert.NotAfter)
+		default:
 			glog.Infof("Using existing server certificate in %s", serverDir)
 			return server, nil
 		}
-
-		glog.Infof("Existing server certificate in %s was missing some hostnames (%v) or IP addresses (%v)", serverDir, missingDns, missingIps)
 	}
 
 	glog.Infof("Generating server certificate in %s", serverDir)
@@ -351,10 +384,20 @@ func (ca *CA) MakeServerCert(name string, hostnames []string) (*TLSCertificateCo
 //	CommonName: username
 //	ExtKeyUsage: ExtKeyUsageClientAuth
 func (ca *CA) MakeClientConfig(username string, defaults kclient.Config) (kclient.Config, error) {
-	clientDir := filepath.Join(ca.Dir, username)
+	return ca.MakeClientConfigInDir(username, username, defaults)
+}
+
+// MakeClientConfigInDir creates the certificates for the given client in <CA.dir>/<name>, like MakeClientConfig.
+// Existing certificates issued to another user are replaced.
+func (ca *CA) MakeClientConfigInDir(name, username string, defaults kclient.Config) (kclient.Config, error) {
+	clientDir := filepath.Join(ca.Dir, name)
 	kubeConfig := filepath.Join(clientDir, ".kubeconfig")
 
 	client, err := readClientConfigFromDir(clientDir, defaults)
+	if err == nil && !issuedTo(client.CertData, username) {
+		glog.Infof("Replacing the client config in %s, which is not issued to %s", kubeConfig, username)
+		err = fmt.Errorf("the client certificate is not issued to %s", username)
+	}
 	if err == nil {
 		// Always write .kubeconfig to pick up hostname changes
 		if err := writeKubeConfigToDir(&client, username, clientDir); err != nil {
@@ -401,7 +444,16 @@ func (ca *CA) MakeClientConfig(username string, defaults kclient.Config) (kclien
 	return client, nil
 }
 
+// issuedTo returns true if the first certificate of certData is issued to username.
+func issuedTo(certData []byte, username string) bool {
+	certs, err := certsFromPEM(certData)
+	return err == nil && len(certs) > 0 && certs[0].Subject.CommonName == username
+}
+
 func (ca *CA) signCertificate(template *x509.Certificate, requestKey crypto.PublicKey) (*x509.Certificate, error) {
+	ca.serialLock.Lock()
+	defer ca.serialLock.Unlock()
+
 	// Increment and persist serial
 	ca.Serial = ca.Serial + 1
 	if err := ioutil.WriteFile(ca.SerialFile, []byte(fmt.Sprintf("%d", ca.Serial)), os.FileMode(0640)); err != nil {
@@ -411,6 +463,11 @@ func (ca *CA) signCertificate(template *x509.Certificate, requestKey crypto.Publ
 	return signCertificate(template, requestKey, ca.Config.Certs[0], ca.Config.Key)
 }
 
+// certificateExpiring returns true if cert has expired or will within renewBefore.
+func certificateExpiring(cert *x509.Certificate) bool {
+	return time.Now().Add(renewBefore).After(cert.NotAfter)
+}
+
 func NewKeyPair() (crypto.PublicKey, crypto.PrivateKey, error) {
 	privateKey, err := rsa.GenerateKey(rand.Reader, keyBits)
 	if err != nil {
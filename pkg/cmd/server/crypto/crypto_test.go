@@ -5,7 +5,11 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"testing"
+
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 )
 
 func TestCrypto(t *testing.T) {
@@ -73,6 +77,43 @@ func TestCrypto(t *testing.T) {
 	}, true, 4)
 }
 
+func TestMakeClientConfigInDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crypto")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	ca, err := InitCA(dir, "test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	defaults := kclient.Config{Host: "https://localhost:8443"}
+	first, err := ca.MakeClientConfig("admin", defaults)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !issuedTo(first.CertData, "admin") {
+		t.Errorf("Expected a certificate issued to admin")
+	}
+
+	second, err := ca.MakeClientConfigInDir("admin", "system:admin", defaults)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !issuedTo(second.CertData, "system:admin") {
+		t.Errorf("Expected the certificate issued to another user to be replaced")
+	}
+
+	third, err := ca.MakeClientConfigInDir("admin", "system:admin", defaults)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(third.CertData) != string(second.CertData) {
+		t.Errorf("Expected the existing certificate to be reused")
+	}
+}
+
 func buildCA(t *testing.T) (crypto.PrivateKey, *x509.Certificate) {
 	caPublicKey, caPrivateKey, err := NewKeyPair()
 	if err != nil {
@@ -0,0 +1,122 @@
+package crypto
+
+import (
+	"crypto/tls"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+)
+
+// ServingCertificate holds the certificate a server presents to TLS clients, loaded from a certificate and
+// key file.  The files are reloaded when they change, so a rotated certificate is served to new connections
+// without restarting the server.
+type ServingCertificate struct {
+	certFile string
+	keyFile  string
+
+	lock sync.RWMutex
+	cert *tls.Certificate
+	// modTime is the latest modification time of the files the current certificate was loaded from
+	modTime time.Time
+}
+
+// NewServingCertificate loads the certificate in certFile and keyFile.
+func NewServingCertificate(certFile, keyFile string) (*ServingCertificate, error) {
+	c := &ServingCertificate{certFile: certFile, keyFile: keyFile}
+	if _, err := c.Reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// current returns the current certificate.
+func (c *ServingCertificate) current() (*tls.Certificate, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.cert == nil {
+		return nil, errors.New("no serving certificate is loaded")
+	}
+	return c.cert, nil
+}
+
+// Reload loads the certificate again if its files were modified since it was last loaded, and returns true if
+// it was.  The current certificate is kept if the files cannot be loaded, for example while they are being
+// rewritten.
+func (c *ServingCertificate) Reload() (bool, error) {
+	modTime, err := latestModTime(c.certFile, c.keyFile)
+	if err != nil {
+		return false, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.cert != nil && !modTime.After(c.modTime) {
+		return false, nil
+	}
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return false, err
+	}
+	c.cert, c.modTime = &cert, modTime
+	return true, nil
+}
+
+// Run reloads the certificate every period, forever.
+func (c *ServingCertificate) Run(period time.Duration) {
+	go util.Forever(func() {
+		reloaded, err := c.Reload()
+		switch {
+		case err != nil:
+			glog.Errorf("Unable to reload the serving certificate %s: %v", c.certFile, err)
+		case reloaded:
+			glog.Infof("Reloaded the serving certificate %s", c.certFile)
+		}
+	}, period)
+}
+
+// latestModTime returns the latest modification time of files.
+func latestModTime(files ...string) (time.Time, error) {
+	latest := time.Time{}
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// ServingCertController keeps the server certificate named Name, signed by CA, valid for Hostnames.  The
+// certificate is regenerated in place once it nears expiry, and servers pick it up through a
+// ServingCertificate loaded from its files.
+type ServingCertController struct {
+	CA        *CA
+	Name      string
+	Hostnames []string
+}
+
+// Run checks the certificate every period, forever.
+func (c *ServingCertController) Run(period time.Duration) {
+	go util.Forever(func() {
+		if err := c.sync(); err != nil {
+			glog.Errorf("Unable to renew the %s server certificate: %v", c.Name, err)
+		}
+	}, period)
+}
+
+// sync regenerates the certificate if it is missing, unreadable, or expiring.
+func (c *ServingCertController) sync() error {
+	if server, err := newTLSCertificateConfig(filepath.Join(c.CA.Dir, c.Name)); err == nil && !certificateExpiring(server.Certs[0]) {
+		return nil
+	}
+	_, err := c.CA.MakeServerCert(c.Name, c.Hostnames)
+	return err
+}
@@ -0,0 +1,133 @@
+package crypto
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestServingCertificateReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "servingcert")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca, err := InitCA(dir, "test-ca")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server, err := ca.MakeServerCert("server", []string{"localhost"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cert, err := NewServingCertificate(server.CertFile, server.KeyFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	original, _ := cert.current()
+
+	if reloaded, err := cert.Reload(); err != nil || reloaded {
+		t.Errorf("expected no reload of unchanged files, got %v %v", reloaded, err)
+	}
+
+	// a new hostname forces the certificate to be regenerated
+	server, err = ca.MakeServerCert("server", []string{"localhost", "example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	later := time.Now().Add(time.Minute)
+	for _, file := range []string{server.CertFile, server.KeyFile} {
+		if err := os.Chtimes(file, later, later); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if reloaded, err := cert.Reload(); err != nil || !reloaded {
+		t.Fatalf("expected the rotated certificate to be reloaded, got %v %v", reloaded, err)
+	}
+	rotated, _ := cert.current()
+	if bytes.Equal(original.Certificate[0], rotated.Certificate[0]) {
+		t.Errorf("expected the rotated certificate to be served")
+	}
+}
+
+func TestServingCertificateKeepsCertificateOnError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "servingcert")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca, err := InitCA(dir, "test-ca")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server, err := ca.MakeServerCert("server", []string{"localhost"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cert, err := NewServingCertificate(server.CertFile, server.KeyFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// a partially written certificate is not served
+	if err := ioutil.WriteFile(server.CertFile, []byte("partial"), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(server.CertFile, later, later)
+	if _, err := cert.Reload(); err == nil {
+		t.Errorf("expected an error reloading an invalid certificate")
+	}
+	if current, err := cert.current(); err != nil || current == nil {
+		t.Errorf("expected the previous certificate to be kept, got %v %v", current, err)
+	}
+}
+
+func TestServingCertControllerRenewsExpiring(t *testing.T) {
+	dir, err := ioutil.TempDir("", "servingcert")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca, err := InitCA(dir, "test-ca")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	controller := &ServingCertController{CA: ca, Name: "server", Hostnames: []string{"localhost"}}
+
+	// a missing certificate is generated
+	if err := controller.sync(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server, err := newTLSCertificateConfig(filepath.Join(dir, "server"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	serial := server.Certs[0].SerialNumber
+
+	// a valid certificate is kept
+	if err := controller.sync(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server, _ = newTLSCertificateConfig(filepath.Join(dir, "server"))
+	if server.Certs[0].SerialNumber.Cmp(serial) != 0 {
+		t.Errorf("expected the valid certificate to be kept")
+	}
+
+	// an expiring certificate is regenerated
+	defer func(old time.Duration) { renewBefore = old }(renewBefore)
+	renewBefore = lifetime + time.Hour
+	if err := controller.sync(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server, _ = newTLSCertificateConfig(filepath.Join(dir, "server"))
+	if server.Certs[0].SerialNumber.Cmp(serial) == 0 {
+		t.Errorf("expected the expiring certificate to be regenerated")
+	}
+}
@@ -0,0 +1,27 @@
+//go:build go1.4
+// +build go1.4
+
+package crypto
+
+import (
+	"crypto/tls"
+)
+
+// GetCertificate returns the current certificate.  It can be used as the GetCertificate function of a
+// tls.Config.
+func (c *ServingCertificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return c.current()
+}
+
+// GetCertificate returns the certificate for the server name requested in hello.  It can be used as the
+// GetCertificate function of a tls.Config.
+func (n *NamedCertificates) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return n.certificateFor(hello.ServerName)
+}
+
+// SetServingCertificates makes config serve the certificates of certs.  The certificate is picked as each
+// connection is made, so rotated certificates are served without restarting.
+func SetServingCertificates(config *tls.Config, certs *NamedCertificates) error {
+	config.GetCertificate = certs.GetCertificate
+	return nil
+}
@@ -0,0 +1,24 @@
+//go:build !go1.4
+// +build !go1.4
+
+package crypto
+
+import (
+	"crypto/tls"
+
+	"github.com/golang/glog"
+)
+
+// SetServingCertificates makes config serve the certificates of certs.  Before Go 1.4 a tls.Config cannot
+// pick the certificate as each connection is made, so the current certificates are served, and rotated
+// certificates are only served after a restart.
+func SetServingCertificates(config *tls.Config, certs *NamedCertificates) error {
+	defaultCert, byName, err := certs.currentByName()
+	if err != nil {
+		return err
+	}
+	config.Certificates = []tls.Certificate{*defaultCert}
+	config.NameToCertificate = byName
+	glog.Warningf("Rotated serving certificates are only served after a restart when built with Go older than 1.4")
+	return nil
+}
@@ -0,0 +1,82 @@
+package crypto
+
+import (
+	"crypto/tls"
+	"strings"
+	"sync"
+)
+
+// NamedCertificates selects the certificate served to a TLS client by the server name the client requests
+// through SNI.  Names may be wildcards of the form *.example.com, which match a single leftmost label.
+// Clients that request no name or an unknown name are served the default certificate.
+type NamedCertificates struct {
+	defaultCert *ServingCertificate
+
+	lock  sync.RWMutex
+	names map[string]*ServingCertificate
+}
+
+// NewNamedCertificates returns a NamedCertificates serving defaultCert to clients requesting no known name.
+func NewNamedCertificates(defaultCert *ServingCertificate) *NamedCertificates {
+	return &NamedCertificates{
+		defaultCert: defaultCert,
+		names:       make(map[string]*ServingCertificate),
+	}
+}
+
+// Add serves cert to clients requesting any of names.  Names added later take precedence.
+func (n *NamedCertificates) Add(names []string, cert *ServingCertificate) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	for _, name := range names {
+		n.names[strings.ToLower(name)] = cert
+	}
+}
+
+// certificateFor returns the current certificate for serverName.
+func (n *NamedCertificates) certificateFor(serverName string) (*tls.Certificate, error) {
+	if cert := n.lookup(serverName); cert != nil {
+		return cert.current()
+	}
+	return n.defaultCert.current()
+}
+
+// currentByName returns the current default certificate and the current certificates by the names they
+// were added for.
+func (n *NamedCertificates) currentByName() (*tls.Certificate, map[string]*tls.Certificate, error) {
+	defaultCert, err := n.defaultCert.current()
+	if err != nil {
+		return nil, nil, err
+	}
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	byName := map[string]*tls.Certificate{}
+	for name, cert := range n.names {
+		current, err := cert.current()
+		if err != nil {
+			return nil, nil, err
+		}
+		byName[name] = current
+	}
+	return defaultCert, byName, nil
+}
+
+// lookup returns the certificate added for name, or for a wildcard matching it, or nil if there is none.
+func (n *NamedCertificates) lookup(name string) *ServingCertificate {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	if len(name) == 0 {
+		return nil
+	}
+
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	if cert, ok := n.names[name]; ok {
+		return cert
+	}
+	if i := strings.Index(name, "."); i > 0 {
+		if cert, ok := n.names["*"+name[i:]]; ok {
+			return cert
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package crypto
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestNamedCertificates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "namedcerts")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca, err := InitCA(dir, "test-ca")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	certs := map[string]*ServingCertificate{}
+	for _, name := range []string{"default", "exact", "wildcard"} {
+		server, err := ca.MakeServerCert(name, []string{name + ".example.com"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if certs[name], err = NewServingCertificate(server.CertFile, server.KeyFile); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	named := NewNamedCertificates(certs["default"])
+	named.Add([]string{"master.example.com"}, certs["exact"])
+	named.Add([]string{"*.apps.example.com"}, certs["wildcard"])
+
+	testCases := map[string]string{
+		"":                       "default",
+		"localhost":              "default",
+		"master.example.com":     "exact",
+		"MASTER.example.com.":    "exact",
+		"web.apps.example.com":   "wildcard",
+		"apps.example.com":       "default",
+		"a.web.apps.example.com": "default",
+	}
+	for serverName, expected := range testCases {
+		actual, err := named.certificateFor(serverName)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", serverName, err)
+			continue
+		}
+		if want, _ := certs[expected].current(); actual != want {
+			t.Errorf("%q: expected the %s certificate", serverName, expected)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/coreos/go-etcd/etcd"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+
+	authorizationetcd "github.com/openshift/origin/pkg/authorization/registry/etcd"
+	buildetcd "github.com/openshift/origin/pkg/build/registry/etcd"
+	"github.com/openshift/origin/pkg/cmd/flagtypes"
+	"github.com/openshift/origin/pkg/cmd/server/origin"
+	deployetcd "github.com/openshift/origin/pkg/deploy/registry/etcd"
+	imageetcd "github.com/openshift/origin/pkg/image/registry/etcd"
+	oauthetcd "github.com/openshift/origin/pkg/oauth/registry/etcd"
+	projectetcd "github.com/openshift/origin/pkg/project/registry/etcd"
+	routeetcd "github.com/openshift/origin/pkg/route/registry/etcd"
+	templateetcd "github.com/openshift/origin/pkg/template/registry/etcd"
+	useretcd "github.com/openshift/origin/pkg/user/registry/etcd"
+)
+
+const migrateStorageLongDesc = `
+Migrate the OpenShift objects stored in etcd to the storage version
+
+Every OpenShift object in etcd is read, and rewritten in the storage version of the master
+configuration if it is stored in another API version. Objects holding credentials are also
+rewritten if they are not encrypted with the current etcd encryption key, so that old keys can be
+removed from the configuration once storage is migrated. Objects modified while they are migrated
+are read again. Run this before upgrading masters to a release that drops an API version.
+
+Kubernetes objects are not migrated.
+`
+
+// migrateStorageMaxAttempts is the number of times an object is rewritten when it is modified concurrently
+const migrateStorageMaxAttempts = 3
+
+// storageRoots are the etcd keys OpenShift objects are stored under
+var storageRoots = []string{
+	authorizationetcd.PolicyPath,
+	authorizationetcd.PolicyBindingPath,
+	buildetcd.BuildPath,
+	buildetcd.BuildConfigPath,
+	deployetcd.DeploymentPath,
+	deployetcd.DeploymentConfigPath,
+	imageetcd.ImagePath,
+	imageetcd.ImageRepositoriesPath,
+	imageetcd.DeletedImageRepositoriesPath,
+	oauthetcd.OAuthAccessTokenPath,
+	oauthetcd.OAuthAuthorizeTokenPath,
+	oauthetcd.OAuthClientPath,
+	oauthetcd.OAuthClientAuthorizationPath,
+	oauthetcd.BootstrapTokenPath,
+	oauthetcd.RouterTokenPath,
+	projectetcd.ProjectPath,
+	routeetcd.RoutePath,
+	templateetcd.TemplatePath,
+	templateetcd.TemplateInstancePath,
+	useretcd.UserPath,
+	useretcd.UserIdentityMappingPath,
+}
+
+// NewCommandMigrateStorage provides a CLI handler that migrates the OpenShift objects in etcd to the storage
+// version.
+func NewCommandMigrateStorage(name string) *cobra.Command {
+	cfg := &config{
+		EtcdAddr: flagtypes.Addr{Value: "0.0.0.0:4001", DefaultScheme: "http", DefaultPort: 4001}.Default(),
+	}
+	dryRun := false
+
+	cmd := &cobra.Command{
+		Use:   name,
+		Short: "Migrate the OpenShift objects in etcd to the storage version",
+		Long:  migrateStorageLongDesc,
+		Run: func(c *cobra.Command, args []string) {
+			if err := loadMasterConfig(cfg, c.Flags()); err != nil {
+				glog.Fatal(err)
+			}
+			etcdClient, err := getEtcdClient(cfg)
+			if err != nil {
+				glog.Fatal(err)
+			}
+			helper, err := origin.NewEtcdHelper(cfg.StorageVersion, etcdClient)
+			if err != nil {
+				glog.Fatalf("Error setting up server storage: %v", err)
+			}
+			raw := origin.NewPrefixedEtcdClient(helper.Client, cfg.EtcdPrefix)
+			if helper.Client, err = newEncryptingEtcdClient(raw, cfg); err != nil {
+				glog.Fatalf("Error setting up server storage: %v", err)
+			}
+
+			result, err := migrateStorage(helper, raw, storageRoots, dryRun)
+			result.print(os.Stdout, dryRun)
+			if err != nil {
+				glog.Fatal(err)
+			}
+		},
+	}
+
+	flag := cmd.Flags()
+	flag.StringVar(&cfg.ConfigFile, "config", "", "The master configuration file to read the etcd connection and storage version from. Flags set on the command line take precedence over the file.")
+	flag.Var(&cfg.EtcdAddr, "etcd", "The address of the etcd server (host, host:port, or URL).")
+	installEtcdClientFlags(flag, cfg)
+	flag.StringVar(&cfg.StorageVersion, "storage-version", "", "The API version objects are rewritten in. Defaults to the storage version of the master configuration, or the latest version.")
+	flag.BoolVar(&dryRun, "dry-run", false, "If true, report the objects that would be migrated without rewriting them.")
+
+	return cmd
+}
+
+// migrationResult counts the objects seen by a storage migration.
+type migrationResult struct {
+	// unchanged objects are already stored in the storage version
+	unchanged int
+	// migrated objects were rewritten in the storage version, or would be in a dry run
+	migrated int
+	// failed objects could not be decoded or rewritten
+	failed int
+}
+
+func (r migrationResult) print(out io.Writer, dryRun bool) {
+	verb := "migrated"
+	if dryRun {
+		verb = "to migrate"
+	}
+	fmt.Fprintf(out, "%d objects %s, %d unchanged, %d failed\n", r.migrated, verb, r.unchanged, r.failed)
+}
+
+// migrateStorage rewrites every object under roots in the storage version of helper, and encrypted as helper
+// encrypts it.  raw is the etcd client beneath the encryption of helper, which the stored values are compared
+// through.  Objects that fail to migrate are logged and counted, and an error is returned once every object has
+// been seen.
+func migrateStorage(helper tools.EtcdHelper, raw tools.EtcdGetSet, roots []string, dryRun bool) (migrationResult, error) {
+	result := migrationResult{}
+	for _, root := range roots {
+		resp, err := raw.Get(root, false, true)
+		if err != nil {
+			if tools.IsEtcdNotFound(err) {
+				continue
+			}
+			return result, fmt.Errorf("unable to list %s: %v", root, err)
+		}
+		for _, node := range leafNodes(resp.Node) {
+			migrated, err := migrateNode(helper, raw, node, dryRun)
+			switch {
+			case err != nil:
+				glog.Errorf("Unable to migrate %s: %v", node.Key, err)
+				result.failed++
+			case migrated:
+				glog.V(2).Infof("Migrated %s", node.Key)
+				result.migrated++
+			default:
+				result.unchanged++
+			}
+		}
+	}
+	if result.failed > 0 {
+		return result, fmt.Errorf("%d objects could not be migrated", result.failed)
+	}
+	return result, nil
+}
+
+// migrateNode rewrites the object in node, as stored in etcd, in the storage version of helper and returns true
+// if it was not already stored in that version or not encrypted as helper encrypts it.  The object is read
+// again if it is modified concurrently.
+func migrateNode(helper tools.EtcdHelper, raw tools.EtcdGetSet, node *etcd.Node, dryRun bool) (bool, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := helper.Client.Get(node.Key, false, false)
+		if err != nil {
+			if tools.IsEtcdNotFound(err) {
+				// deleted while it was migrated
+				return false, nil
+			}
+			return false, err
+		}
+		if resp.Node.ModifiedIndex == node.ModifiedIndex {
+			migrated, err := rewriteNode(helper, node, resp.Node.Value, dryRun)
+			if err == nil || !tools.IsEtcdTestFailed(err) || attempt >= migrateStorageMaxAttempts {
+				return migrated, err
+			}
+		} else if attempt >= migrateStorageMaxAttempts {
+			return false, fmt.Errorf("%s was modified while it was migrated", node.Key)
+		}
+
+		if resp, err = raw.Get(node.Key, false, false); err != nil {
+			if tools.IsEtcdNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		node = resp.Node
+	}
+}
+
+// rewriteNode writes the object in plaintext, the decrypted value of node, through helper unless node already
+// stores it as helper would.
+func rewriteNode(helper tools.EtcdHelper, node *etcd.Node, plaintext string, dryRun bool) (bool, error) {
+	obj, err := helper.Codec.Decode([]byte(plaintext))
+	if err != nil {
+		return false, err
+	}
+	data, err := helper.Codec.Encode(obj)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(data, []byte(plaintext)) && origin.EncryptedAsConfigured(helper.Client, node.Key, node.Value) {
+		return false, nil
+	}
+	if dryRun {
+		return true, nil
+	}
+
+	ttl := uint64(0)
+	if node.TTL > 0 {
+		ttl = uint64(node.TTL)
+	}
+	if _, err := helper.Client.CompareAndSwap(node.Key, string(data), ttl, "", node.ModifiedIndex); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// leafNodes returns the nodes under node that hold values.
+func leafNodes(node *etcd.Node) []*etcd.Node {
+	if node == nil {
+		return nil
+	}
+	if !node.Dir {
+		return []*etcd.Node{node}
+	}
+	nodes := []*etcd.Node{}
+	for _, child := range node.Nodes {
+		nodes = append(nodes, leafNodes(child)...)
+	}
+	return nodes
+}
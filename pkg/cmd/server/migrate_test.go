@@ -0,0 +1,147 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/coreos/go-etcd/etcd"
+
+	"github.com/openshift/origin/pkg/api/latest"
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	"github.com/openshift/origin/pkg/cmd/server/origin"
+	oauthapi "github.com/openshift/origin/pkg/oauth/api"
+)
+
+func newMigrateTestHelper(t *testing.T) (*tools.FakeEtcdClient, tools.EtcdHelper) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	return fakeClient, tools.EtcdHelper{Client: fakeClient, Codec: latest.Codec, ResourceVersioner: tools.RuntimeVersionAdapter{Versioner: latest.ResourceVersioner}}
+}
+
+func TestMigrateStorage(t *testing.T) {
+	fakeClient, helper := newMigrateTestHelper(t)
+
+	current := runtime.EncodeOrDie(latest.Codec, &buildapi.Build{ObjectMeta: kapi.ObjectMeta{Name: "current", Namespace: "test"}})
+	// an object written by an older encoder, which decodes to the same build but is not stored as the current
+	// encoder would store it
+	outdated := `{"kind": "Build", "apiVersion": "v1beta1", "metadata": {"name": "outdated", "namespace": "test"}}`
+	nodes := []*etcd.Node{
+		{Key: "/builds/test/current", Value: current, ModifiedIndex: 1},
+		{Key: "/builds/test/outdated", Value: outdated, ModifiedIndex: 2, TTL: 60},
+	}
+	for _, node := range nodes {
+		fakeClient.Data[node.Key] = tools.EtcdResponseWithError{R: &etcd.Response{Node: node}}
+	}
+	fakeClient.Data["/builds"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{Node: &etcd.Node{Key: "/builds", Dir: true, Nodes: etcd.Nodes{
+			{Key: "/builds/test", Dir: true, Nodes: nodes},
+		}}},
+	}
+	fakeClient.ExpectNotFoundGet("/images")
+
+	result, err := migrateStorage(helper, fakeClient, []string{"/builds", "/images"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != (migrationResult{unchanged: 1, migrated: 1}) {
+		t.Errorf("unexpected result: %#v", result)
+	}
+	if value := fakeClient.Data["/builds/test/current"].R.Node.Value; value != current {
+		t.Errorf("expected the current object to be left alone, got %s", value)
+	}
+	expected := runtime.EncodeOrDie(latest.Codec, &buildapi.Build{ObjectMeta: kapi.ObjectMeta{Name: "outdated", Namespace: "test"}})
+	if value := fakeClient.Data["/builds/test/outdated"].R.Node.Value; value != expected {
+		t.Errorf("expected the outdated object to be rewritten, got %s", value)
+	}
+	if fakeClient.LastSetTTL != 60 {
+		t.Errorf("expected the TTL to be preserved, got %d", fakeClient.LastSetTTL)
+	}
+}
+
+func TestMigrateStorageDryRun(t *testing.T) {
+	fakeClient, helper := newMigrateTestHelper(t)
+
+	outdated := `{"kind": "Build", "apiVersion": "v1beta1", "metadata": {"name": "outdated", "namespace": "test"}}`
+	node := &etcd.Node{Key: "/builds/outdated", Value: outdated, ModifiedIndex: 1}
+	fakeClient.Data[node.Key] = tools.EtcdResponseWithError{R: &etcd.Response{Node: node}}
+	fakeClient.Data["/builds"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{Node: &etcd.Node{Key: "/builds", Dir: true, Nodes: etcd.Nodes{node}}},
+	}
+
+	result, err := migrateStorage(helper, fakeClient, []string{"/builds"}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != (migrationResult{migrated: 1}) {
+		t.Errorf("unexpected result: %#v", result)
+	}
+	if value := fakeClient.Data["/builds/outdated"].R.Node.Value; value != outdated {
+		t.Errorf("expected no object to be written in a dry run, got %s", value)
+	}
+}
+
+func TestMigrateStorageInvalidObject(t *testing.T) {
+	fakeClient, helper := newMigrateTestHelper(t)
+
+	node := &etcd.Node{Key: "/builds/invalid", Value: "not json", ModifiedIndex: 1}
+	fakeClient.Data[node.Key] = tools.EtcdResponseWithError{R: &etcd.Response{Node: node}}
+	fakeClient.Data["/builds"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{Node: &etcd.Node{Key: "/builds", Dir: true, Nodes: etcd.Nodes{node}}},
+	}
+
+	result, err := migrateStorage(helper, fakeClient, []string{"/builds"}, false)
+	if err == nil {
+		t.Errorf("expected an error for an object that cannot be decoded")
+	}
+	if result != (migrationResult{failed: 1}) {
+		t.Errorf("unexpected result: %#v", result)
+	}
+}
+
+func TestMigrateStorageRotatesEncryptionKeys(t *testing.T) {
+	fakeClient, helper := newMigrateTestHelper(t)
+	oldKey := origin.EncryptionKey{Name: "old", Secret: make([]byte, origin.EncryptionKeySize)}
+	newKey := origin.EncryptionKey{Name: "new", Secret: []byte(strings.Repeat("n", origin.EncryptionKeySize))}
+	paths := []string{"/oauth/clients"}
+
+	old, err := origin.NewEncryptingEtcdClient(fakeClient, []origin.EncryptionKey{oldKey}, paths)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client := runtime.EncodeOrDie(latest.Codec, &oauthapi.OAuthClient{ObjectMeta: kapi.ObjectMeta{Name: "client"}, Secret: "secret"})
+	if _, err := old.Set("/oauth/clients/client", client, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	node := fakeClient.Data["/oauth/clients/client"].R.Node
+	node.Key = "/oauth/clients/client"
+	fakeClient.Data["/oauth/clients"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{Node: &etcd.Node{Key: "/oauth/clients", Dir: true, Nodes: etcd.Nodes{node}}},
+	}
+
+	if helper.Client, err = origin.NewEncryptingEtcdClient(fakeClient, []origin.EncryptionKey{newKey, oldKey}, paths); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := migrateStorage(helper, fakeClient, []string{"/oauth/clients"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != (migrationResult{migrated: 1}) {
+		t.Errorf("expected the object encrypted with the old key to be migrated, got %#v", result)
+	}
+	if value := fakeClient.Data["/oauth/clients/client"].R.Node.Value; !strings.HasPrefix(value, "openshift:enc:aesgcm:v1:new:") {
+		t.Errorf("expected the object to be encrypted with the new key, got %s", value)
+	}
+
+	// the rewritten object is current
+	node = fakeClient.Data["/oauth/clients/client"].R.Node
+	node.Key = "/oauth/clients/client"
+	fakeClient.Data["/oauth/clients"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{Node: &etcd.Node{Key: "/oauth/clients", Dir: true, Nodes: etcd.Nodes{node}}},
+	}
+	if result, err := migrateStorage(helper, fakeClient, []string{"/oauth/clients"}, false); err != nil || result != (migrationResult{unchanged: 1}) {
+		t.Errorf("expected the object to be unchanged, got %#v %v", result, err)
+	}
+}
@@ -0,0 +1,186 @@
+package origin
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+	"github.com/openshift/origin/pkg/authorization/authorizer"
+)
+
+// mutatingMethods are the HTTP methods of the requests that are audited
+var mutatingMethods = map[string]bool{
+	"POST":   true,
+	"PUT":    true,
+	"PATCH":  true,
+	"DELETE": true,
+}
+
+// auditFilter writes a line to out for every mutating request served by handler, recording the user, verb,
+// resource, namespace, object name, source IP, response code, audit ID, and the user that impersonated the user
+// if any.  The users and audit ID are read from the request context once handler has served the request, so the
+// filter must run after the request is authenticated and records the user an inner filter impersonates.
+func auditFilter(handler http.Handler, out io.Writer, contexts *authcontext.RequestContextMapper) http.Handler {
+	lock := &sync.Mutex{}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !mutatingMethods[req.Method] {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		delegate := &responseWriterDelegator{ResponseWriter: w}
+		handler.ServeHTTP(delegate, req)
+
+		user, id, impersonator := requestUserName(req, contexts), "", ""
+		if ctx, ok := contexts.Get(req); ok {
+			id = authcontext.AuditIDFrom(ctx)
+			if info, ok := authcontext.ImpersonatorFrom(ctx); ok {
+				impersonator = info.GetName()
+			}
+		}
+		verb, resource, namespace, name := requestAttributes(req)
+		sourceIP, _, err := net.SplitHostPort(req.RemoteAddr)
+		if err != nil {
+			sourceIP = req.RemoteAddr
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		if _, err := fmt.Fprintf(out, "%s AUDIT user=%q verb=%q resource=%q namespace=%q name=%q ip=%q code=%d uri=%q id=%q impersonator=%q\n",
+			time.Now().UTC().Format(time.RFC3339), user, verb, resource, namespace, name, sourceIP, delegate.statusCode(), req.RequestURI, id, impersonator,
+		); err != nil {
+			glog.Errorf("Unable to write audit log: %v", err)
+		}
+	})
+}
+
+// requestUserName returns the name of the user that made req, or "" if the request is not authenticated.
+func requestUserName(req *http.Request, contexts *authcontext.RequestContextMapper) string {
+	if ctx, ok := contexts.Get(req); ok {
+		if user, ok := authcontext.UserFrom(ctx); ok {
+			return user.GetName()
+		}
+	}
+	return ""
+}
+
+// requestAttributes returns the verb, resource, namespace, and object name of a request.  Only requests to the
+// Kubernetes and OpenShift APIs have a resource, namespace, and name, and the verb of any request that cannot be
+// resolved is its lowercase method.
+func requestAttributes(req *http.Request) (verb, resource, namespace, name string) {
+	var parts []string
+	if strings.HasPrefix(req.URL.Path, "/api/") || strings.HasPrefix(req.URL.Path, OpenShiftAPIPrefix+"/") {
+		var err error
+		verb, resource, namespace, parts, err = authorizer.VerbAndKindAndNamespace(req)
+		switch {
+		case err == nil:
+		case err == authorizer.ErrNoStandardParts && len(resource) > 0:
+			// requests of the form /ns/{namespace}/{kind}/{name} are resolved but reported as non standard
+		default:
+			verb, resource, namespace, parts = "", "", "", nil
+		}
+	}
+	if len(parts) > 1 {
+		name = parts[1]
+	}
+	if len(verb) == 0 {
+		verb = strings.ToLower(req.Method)
+	}
+	return verb, resource, namespace, name
+}
+
+// NewAuditLog returns the writer audit records are written to: stdout if path is "-", otherwise the file at
+// path, which is rotated once it grows past maxSizeMegabytes keeping maxBackups rotated files.
+func NewAuditLog(path string, maxSizeMegabytes, maxBackups int) (io.Writer, error) {
+	if path == "-" {
+		return os.Stdout, nil
+	}
+	return newRotatingFile(path, int64(maxSizeMegabytes)*1024*1024, maxBackups)
+}
+
+// rotatingFile is a file that is rotated once it grows past maxSize bytes, keeping up to maxBackups rotated
+// files named path.1 (the newest) through path.N.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	lock sync.Mutex
+	file *os.File
+	size int64
+}
+
+// newRotatingFile opens or creates the file at path for appending.  If maxSize is not positive the file
+// is never rotated.
+func newRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	f := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open opens the file at f.path for appending.
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// Write appends data to the file, rotating it first if data would grow it past maxSize.
+func (f *rotatingFile) Write(data []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(data)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(data)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate moves the current file to path.1, shifting older backups and removing those past maxBackups, and
+// opens a new file at path.
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	if f.maxBackups > 0 {
+		os.Remove(fmt.Sprintf("%s.%d", f.path, f.maxBackups))
+		for i := f.maxBackups - 1; i > 0; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", f.path, i), fmt.Sprintf("%s.%d", f.path, i+1))
+		}
+		if err := os.Rename(f.path, f.path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(f.path); err != nil {
+		return err
+	}
+	return f.open()
+}
+
+// Close closes the file.
+func (f *rotatingFile) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.file.Close()
+}
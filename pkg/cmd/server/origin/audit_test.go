@@ -0,0 +1,122 @@
+package origin
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	authenticationapi "github.com/openshift/origin/pkg/auth/api"
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+)
+
+func TestAuditFilter(t *testing.T) {
+	out := &bytes.Buffer{}
+	contexts := authcontext.NewRequestContextMapper()
+	handler := auditFilter(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == "DELETE" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}), out, contexts)
+
+	testCases := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{"GET", "/osapi/v1beta1/builds?namespace=test", ""},
+		{"POST", "/osapi/v1beta1/builds?namespace=test",
+			`AUDIT user="alice" verb="create" resource="builds" namespace="test" name="" ip="10.0.0.1" code=201 uri="/osapi/v1beta1/builds?namespace=test"`},
+		{"PUT", "/api/v1beta1/pods/frontend?namespace=test",
+			`AUDIT user="alice" verb="update" resource="pods" namespace="test" name="frontend" ip="10.0.0.1" code=201`},
+		{"DELETE", "/osapi/v1beta1/ns/test/routes/web",
+			`AUDIT user="alice" verb="delete" resource="routes" namespace="test" name="web" ip="10.0.0.1" code=403`},
+		{"POST", "/login",
+			`AUDIT user="alice" verb="post" resource="" namespace="" name="" ip="10.0.0.1" code=201`},
+	}
+	for _, tc := range testCases {
+		out.Reset()
+		req, _ := http.NewRequest(tc.method, tc.path, nil)
+		req.RequestURI = tc.path
+		req.RemoteAddr = "10.0.0.1:34567"
+		withUser(contexts, "alice", handler).ServeHTTP(httptest.NewRecorder(), req)
+
+		if len(tc.expected) == 0 {
+			if out.Len() != 0 {
+				t.Errorf("%s %s: expected no audit record, got %q", tc.method, tc.path, out.String())
+			}
+			continue
+		}
+		if !strings.Contains(out.String(), tc.expected) {
+			t.Errorf("%s %s: expected audit record containing %q, got %q", tc.method, tc.path, tc.expected, out.String())
+		}
+		if strings.Contains(out.String(), `id=""`) || !strings.Contains(out.String(), ` id="`) {
+			t.Errorf("%s %s: expected audit record with the audit ID of the request, got %q", tc.method, tc.path, out.String())
+		}
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+
+	f, err := newRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	f.Close()
+
+	for name, expected := range map[string]string{
+		"audit.log":   "fourth\n",
+		"audit.log.1": "third\n",
+		"audit.log.2": "second\n",
+	} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("Unexpected error reading %s: %v", name, err)
+			continue
+		}
+		if string(data) != expected {
+			t.Errorf("Expected %s to contain %q, got %q", name, expected, string(data))
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "audit.log.3")); !os.IsNotExist(err) {
+		t.Errorf("Expected only 2 rotated files to be kept, got %v", err)
+	}
+
+	// reopening appends to the existing file
+	f, err = newRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	f.Write([]byte("x\n"))
+	f.Close()
+	if data, _ := ioutil.ReadFile(path); string(data) != "fourth\nx\n" {
+		t.Errorf("Expected the log to be appended to, got %q", string(data))
+	}
+}
+
+// withUser serves handler with a request context in which user is authenticated.
+func withUser(contexts *authcontext.RequestContextMapper, user string, handler http.Handler) http.Handler {
+	return authcontext.NewRequestContextFilter(contexts, time.Minute, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, _ := contexts.Get(req)
+		contexts.Update(req, authcontext.WithUser(ctx, &authenticationapi.DefaultUserInfo{Name: user}))
+		handler.ServeHTTP(w, req)
+	}))
+}
@@ -1,7 +1,10 @@
 package origin
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"path"
@@ -12,12 +15,14 @@ import (
 
 	"code.google.com/p/go-uuid/uuid"
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
 	"github.com/RangelReale/osin"
 	"github.com/RangelReale/osincli"
 	"github.com/emicklei/go-restful"
 	"github.com/golang/glog"
 
+	"github.com/openshift/origin/pkg/api/latest"
 	"github.com/openshift/origin/pkg/auth/api"
 	"github.com/openshift/origin/pkg/auth/authenticator"
 	"github.com/openshift/origin/pkg/auth/authenticator/challenger/passwordchallenger"
@@ -40,7 +45,6 @@ import (
 	"github.com/openshift/origin/pkg/auth/server/login"
 	"github.com/openshift/origin/pkg/auth/server/session"
 	"github.com/openshift/origin/pkg/auth/server/tokenrequest"
-	userregistry "github.com/openshift/origin/pkg/user/registry/user"
 
 	"github.com/openshift/origin/pkg/auth/userregistry/identitymapper"
 	cmdutil "github.com/openshift/origin/pkg/cmd/util"
@@ -49,8 +53,11 @@ import (
 	oauthclient "github.com/openshift/origin/pkg/oauth/registry/client"
 	"github.com/openshift/origin/pkg/oauth/registry/clientauthorization"
 	oauthetcd "github.com/openshift/origin/pkg/oauth/registry/etcd"
+	"github.com/openshift/origin/pkg/oauth/scope"
 	"github.com/openshift/origin/pkg/oauth/server/osinserver"
 	"github.com/openshift/origin/pkg/oauth/server/osinserver/registrystorage"
+	projectapi "github.com/openshift/origin/pkg/project/api"
+	routeapi "github.com/openshift/origin/pkg/route/api"
 	"github.com/openshift/origin/pkg/user"
 	useretcd "github.com/openshift/origin/pkg/user/registry/etcd"
 )
@@ -180,10 +187,15 @@ type AuthConfig struct {
 	// BasicAuthURL specifies the remote URL to validate username/passwords against using basic auth. Used by PasswordAuthBasicAuthURL.
 	BasicAuthURL string
 
+	// IdentityMappingMethod specifies how identities that have not logged in before are mapped to users
+	IdentityMappingMethod user.MappingMethod
+
 	// TokenStore specifies how to validate bearer tokens. Used by AuthRequestHandlerBearer.
 	TokenStore TokenStoreType
 	// TokenFilePath is a path to a CSV file to load valid tokens from. Used by TokenStoreFile.
 	TokenFilePath string
+	// AccessTokenMaxAgeSeconds is how long granted access tokens last. The osin default is used if zero.
+	AccessTokenMaxAgeSeconds int
 
 	// SessionSecrets list the secret(s) to use to encrypt created sessions. Used by AuthRequestHandlerSession
 	SessionSecrets []string
@@ -222,6 +234,9 @@ func (c *AuthConfig) InstallAPI(container *restful.Container) []string {
 
 	storage := registrystorage.New(oauthEtcd, oauthEtcd, oauthEtcd, registry.NewUserConversion())
 	config := osinserver.NewDefaultServerConfig()
+	if c.AccessTokenMaxAgeSeconds > 0 {
+		config.AccessExpiration = int32(c.AccessTokenMaxAgeSeconds)
+	}
 
 	grantChecker := registry.NewClientAuthorizationGrantChecker(oauthEtcd)
 	grantHandler := c.getGrantHandler(mux, authRequestHandler, oauthEtcd, oauthEtcd)
@@ -410,8 +425,7 @@ func (c *AuthConfig) getAuthenticationHandler(mux cmdutil.Mux, errorHandler hand
 	switch authHandlerType {
 	case AuthHandlerGithub, AuthHandlerGoogle:
 		callbackPath := path.Join(OpenShiftOAuthCallbackPrefix, string(authHandlerType))
-		userRegistry := useretcd.New(c.EtcdHelper, user.NewDefaultUserInitStrategy())
-		identityMapper := identitymapper.NewAlwaysCreateUserIdentityToUserMapper(string(authHandlerType) /*for now*/, userRegistry)
+		identityMapper := c.getIdentityMapper(string(authHandlerType) /*for now*/)
 
 		var oauthProvider external.Provider
 		if authHandlerType == AuthHandlerGoogle {
@@ -450,8 +464,7 @@ func (c *AuthConfig) getPasswordAuthenticator() authenticator.Password {
 	// TODO presumeably we'll want either a list of what we've got or a way to describe a registry of these
 	// hard-coded strings as a stand-in until it gets sorted out
 	passwordAuthType := c.PasswordAuth
-	userRegistry := useretcd.New(c.EtcdHelper, user.NewDefaultUserInitStrategy())
-	identityMapper := identitymapper.NewAlwaysCreateUserIdentityToUserMapper(string(passwordAuthType) /*for now*/, userRegistry)
+	identityMapper := c.getIdentityMapper(string(passwordAuthType) /*for now*/)
 
 	var passwordAuth authenticator.Password
 	switch passwordAuthType {
@@ -471,6 +484,13 @@ func (c *AuthConfig) getPasswordAuthenticator() authenticator.Password {
 	return passwordAuth
 }
 
+// getIdentityMapper returns a mapper from identities of providerID to users that maps new identities with the
+// configured IdentityMappingMethod.
+func (c *AuthConfig) getIdentityMapper(providerID string) api.UserIdentityMapper {
+	userRegistry := useretcd.New(c.EtcdHelper, user.NewDefaultUserInitStrategy(), c.IdentityMappingMethod)
+	return identitymapper.NewAlwaysCreateUserIdentityToUserMapper(providerID, userRegistry, userRegistry)
+}
+
 func (c *AuthConfig) getAuthenticationSuccessHandler() handlers.AuthenticationSuccessHandler {
 	successHandlers := handlers.AuthenticationSuccessHandlers{}
 
@@ -515,8 +535,7 @@ func (c *AuthConfig) getAuthenticationRequestHandlerFromType(authRequestHandlerT
 			glog.Fatalf("Unknown TokenStore %s. Must be etcd or file.  The oauth server cannot start!", c.TokenStore)
 		}
 	case AuthRequestHandlerRequestHeader:
-		userRegistry := useretcd.New(c.EtcdHelper, user.NewDefaultUserInitStrategy())
-		identityMapper := identitymapper.NewAlwaysCreateUserIdentityToUserMapper(string(authRequestHandlerType) /*for now*/, userRegistry)
+		identityMapper := c.getIdentityMapper(string(authRequestHandlerType) /*for now*/)
 		authRequestHandler = headerrequest.NewAuthenticator(headerrequest.NewDefaultConfig(), identityMapper)
 	case AuthRequestHandlerBasicAuth:
 		passwordAuthenticator := c.getPasswordAuthenticator()
@@ -544,7 +563,18 @@ func (c *AuthConfig) getAuthenticationRequestHandler() authenticator.Request {
 
 func GetEtcdTokenAuthenticator(etcdHelper tools.EtcdHelper) (authenticator.Token, error) {
 	oauthRegistry := oauthetcd.New(etcdHelper)
-	return authnregistry.NewTokenAuthenticator(oauthRegistry), nil
+	userRegistry := useretcd.New(etcdHelper, user.NewDefaultUserInitStrategy(), user.MappingMethodClaim)
+	return authnregistry.NewTokenAuthenticator(oauthRegistry, userRegistry), nil
+}
+
+// GetEtcdBootstrapTokenAuthenticator returns an authenticator of the bootstrap tokens stored in etcd.
+func GetEtcdBootstrapTokenAuthenticator(etcdHelper tools.EtcdHelper) authenticator.Token {
+	return authnregistry.NewBootstrapTokenAuthenticator(oauthetcd.New(etcdHelper))
+}
+
+// GetEtcdRouterTokenAuthenticator returns an authenticator of the router tokens stored in etcd.
+func GetEtcdRouterTokenAuthenticator(etcdHelper tools.EtcdHelper) authenticator.Token {
+	return authnregistry.NewRouterTokenAuthenticator(oauthetcd.New(etcdHelper))
 }
 
 func GetCSVTokenAuthenticator(path string) (authenticator.Token, error) {
@@ -594,7 +624,7 @@ func (redirectSuccessHandler) AuthenticationSucceeded(user api.UserInfo, then st
 }
 
 // currentUserContextFilter replaces the the last segment of the provided URL with the current user's name
-func currentUserContextFilter(requestsToUsers *authcontext.RequestContextMap) restful.FilterFunction {
+func currentUserContextFilter(contexts *authcontext.RequestContextMapper) restful.FilterFunction {
 	return func(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
 		name := path.Base(req.Request.URL.Path)
 		if name != "~" {
@@ -602,26 +632,101 @@ func currentUserContextFilter(requestsToUsers *authcontext.RequestContextMap) re
 			return
 		}
 
-		val, found := requestsToUsers.Get(req.Request)
+		user, found := requestUser(req.Request, contexts)
 		if !found {
 			http.Error(res.ResponseWriter, "Need to be authenticated to access this method", http.StatusUnauthorized)
 			return
 		}
-		user, ok := val.(userregistry.Info)
+
+		base := path.Dir(req.Request.URL.Path)
+		req.Request.URL.Path = path.Join(base, user.GetName())
+
+		chain.ProcessFilter(req, res)
+	}
+}
+
+// projectRequesterFilter records the current user as the requester of a ProjectRequest, replacing any
+// requester provided by the client
+func projectRequesterFilter(contexts *authcontext.RequestContextMapper) restful.FilterFunction {
+	return rewriteRequestFilter(contexts, func(obj runtime.Object, user api.UserInfo) error {
+		request, ok := obj.(*projectapi.ProjectRequest)
 		if !ok {
-			http.Error(res.ResponseWriter, "Unable to convert internal object", http.StatusInternalServerError)
+			return errors.New("The request body must be a ProjectRequest")
+		}
+		if request.Annotations == nil {
+			request.Annotations = map[string]string{}
+		}
+		request.Annotations[projectapi.ProjectRequesterAnnotation] = user.GetName()
+		return nil
+	})
+}
+
+// routeStatusReporterFilter records the router the current user authenticates as in a RouteStatusUpdate,
+// replacing any router name provided by the client, so that routers only report for themselves
+func routeStatusReporterFilter(contexts *authcontext.RequestContextMapper) restful.FilterFunction {
+	return rewriteRequestFilter(contexts, func(obj runtime.Object, user api.UserInfo) error {
+		update, ok := obj.(*routeapi.RouteStatusUpdate)
+		if !ok {
+			return errors.New("The request body must be a RouteStatusUpdate")
+		}
+		update.Ingress.RouterName = api.RouterName(user.GetName())
+		return nil
+	})
+}
+
+// maxRewrittenBodyBytes bounds the size of the request bodies filters decode.
+const maxRewrittenBodyBytes = 3 * 1024 * 1024
+
+// rewriteRequestFilter returns a filter that decodes the object in the body of authenticated requests, lets rewrite
+// change it for the current user, and passes the request on with the encoded result as its body.
+func rewriteRequestFilter(contexts *authcontext.RequestContextMapper, rewrite func(runtime.Object, api.UserInfo) error) restful.FilterFunction {
+	return func(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
+		user, found := requestUser(req.Request, contexts)
+		if !found {
+			http.Error(res.ResponseWriter, "Need to be authenticated to access this method", http.StatusUnauthorized)
 			return
 		}
 
-		base := path.Dir(req.Request.URL.Path)
-		req.Request.URL.Path = path.Join(base, user.GetName())
+		body, err := ioutil.ReadAll(http.MaxBytesReader(res.ResponseWriter, req.Request.Body, maxRewrittenBodyBytes))
+		if err != nil {
+			http.Error(res.ResponseWriter, err.Error(), http.StatusBadRequest)
+			return
+		}
+		obj, err := latest.Codec.Decode(body)
+		if err != nil {
+			http.Error(res.ResponseWriter, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := rewrite(obj, user); err != nil {
+			http.Error(res.ResponseWriter, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		body, err = latest.Codec.Encode(obj)
+		if err != nil {
+			http.Error(res.ResponseWriter, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		req.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req.Request.ContentLength = int64(len(body))
 
 		chain.ProcessFilter(req, res)
 	}
 }
 
-// authenticationHandlerFilter creates a filter object that will enforce authentication directly
-func authenticationHandlerFilter(handler http.Handler, authenticator authenticator.Request, requestsToUsers *authcontext.RequestContextMap) http.Handler {
+// requestUser returns the authenticated user of req.
+func requestUser(req *http.Request, contexts *authcontext.RequestContextMapper) (api.UserInfo, bool) {
+	ctx, ok := contexts.Get(req)
+	if !ok {
+		return nil, false
+	}
+	return authcontext.UserFrom(ctx)
+}
+
+// authenticationHandlerFilter creates a filter object that will enforce authentication directly.  The user and
+// the scopes granted to the client are added to the request context, which must have been created by an outer
+// authcontext.NewRequestContextFilter.
+func authenticationHandlerFilter(handler http.Handler, authenticator authenticator.Request, contexts *authcontext.RequestContextMapper) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		user, ok, err := authenticator.AuthenticateRequest(req)
 		if err != nil || !ok {
@@ -631,8 +736,19 @@ func authenticationHandlerFilter(handler http.Handler, authenticator authenticat
 		}
 		glog.V(4).Infof("user %v -> %v", user, req.URL)
 
-		requestsToUsers.Set(req, user)
-		defer requestsToUsers.Remove(req)
+		ctx, ok := contexts.Get(req)
+		if !ok {
+			http.Error(w, "The request has no context", http.StatusInternalServerError)
+			return
+		}
+		ctx = authcontext.WithScopes(authcontext.WithUser(ctx, user), scope.Split(user.GetScope()))
+		if client := user.GetExtra()[api.ClientNameExtraKey]; len(client) > 0 {
+			ctx = authcontext.WithClient(ctx, client)
+		}
+		if err := contexts.Update(req, ctx); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		handler.ServeHTTP(w, req)
 	})
@@ -0,0 +1,53 @@
+package origin
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/emicklei/go-restful"
+
+	"github.com/openshift/origin/pkg/api/latest"
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+func TestRouteStatusReporterFilter(t *testing.T) {
+	contexts := authcontext.NewRequestContextMapper()
+	reported := ""
+	ws := new(restful.WebService)
+	ws.Route(ws.POST("/routeStatusUpdates").Consumes(restful.MIME_JSON).Filter(routeStatusReporterFilter(contexts)).To(func(req *restful.Request, res *restful.Response) {
+		body, _ := ioutil.ReadAll(req.Request.Body)
+		obj, err := latest.Codec.Decode(body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		reported = obj.(*routeapi.RouteStatusUpdate).Ingress.RouterName
+	}))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	testCases := map[string]struct {
+		user     string
+		reported string
+	}{
+		"router token": {"system:router:shard-a", "shard-a"},
+		"other user":   {"openshift-client", "openshift-client"},
+	}
+	for name, tc := range testCases {
+		reported = ""
+		body := runtime.EncodeOrDie(latest.Codec, &routeapi.RouteStatusUpdate{
+			Ingress: routeapi.RouteIngress{RouterName: "shard-b", Phase: routeapi.RouteAdmitted},
+		})
+		req, _ := http.NewRequest("POST", "/routeStatusUpdates", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		withUser(contexts, tc.user, container).ServeHTTP(w, req)
+		if reported != tc.reported {
+			t.Errorf("%s: expected the status to be reported by %q, got %q (%d)", name, tc.reported, reported, w.Code)
+		}
+	}
+}
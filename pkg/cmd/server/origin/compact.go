@@ -0,0 +1,101 @@
+package origin
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	restful "github.com/emicklei/go-restful"
+
+	"github.com/openshift/origin/pkg/api/compact"
+)
+
+// compactAPIPrefix is where the OpenShift API is installed with the compact codecs.  Clients never use it
+// directly, compactRoutingFilter sends requests there.
+const compactAPIPrefix = "/osapi-compact"
+
+// compactWatchAPIPrefix is where the OpenShift API is installed with the codecs that embed the compact
+// encoding in the JSON frames of watch events.  Clients never use it directly, compactRoutingFilter sends
+// watches there.
+const compactWatchAPIPrefix = "/osapi-compact-watch"
+
+// compactRoutingFilter serves OpenShift API requests in the compact encoding to clients that prefer
+// compact.MediaType in the Accept header or send it as the Content-Type.  Watches are served by the API
+// installed at compactWatchAPIPrefix, whose events remain JSON frames holding objects in the compact
+// encoding.  Other requests are served by the API installed at compactAPIPrefix, whose responses are labeled
+// with compact.MediaType.  Other long running requests, such as proxied ones, are served unchanged.
+func compactRoutingFilter(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if isUnder(req.URL.Path, compactAPIPrefix) || isUnder(req.URL.Path, compactWatchAPIPrefix) {
+			http.NotFound(w, req)
+			return
+		}
+		if !strings.HasPrefix(req.URL.Path, OpenShiftAPIPrefix+"/") {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if contentType != compact.MediaType && !acceptsCompact(req.Header.Get("Accept")) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		if verb, _, _, _ := requestAttributes(req); verb == "watch" {
+			req.URL.Path = compactWatchAPIPrefix + strings.TrimPrefix(req.URL.Path, OpenShiftAPIPrefix)
+			handler.ServeHTTP(w, req)
+			return
+		}
+		if longRunningRequest(req) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		req.URL.Path = compactAPIPrefix + strings.TrimPrefix(req.URL.Path, OpenShiftAPIPrefix)
+		handler.ServeHTTP(&mediaTypeResponseWriter{ResponseWriter: w, mediaType: compact.MediaType}, req)
+	})
+}
+
+// isUnder returns true if path is prefix or below it.
+func isUnder(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// acceptsCompact returns true if the media types in accept prefer the compact encoding.
+func acceptsCompact(accept string) bool {
+	return preferredMediaType(accept) == compact.MediaType
+}
+
+// mediaTypeResponseWriter labels the responses of an API installed with other codecs, which the API server
+// labels as JSON, with mediaType.
+type mediaTypeResponseWriter struct {
+	http.ResponseWriter
+	mediaType   string
+	wroteHeader bool
+}
+
+func (w *mediaTypeResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if contentType, _, err := mime.ParseMediaType(w.Header().Get("Content-Type")); err == nil && contentType == "application/json" {
+			w.Header().Set("Content-Type", w.mediaType)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *mediaTypeResponseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// publicWebServices returns the web services of container that clients may call directly, leaving out the
+// APIs installed at compactAPIPrefix, compactWatchAPIPrefix and yamlAPIPrefix.
+func publicWebServices(container *restful.Container) []*restful.WebService {
+	services := []*restful.WebService{}
+	for _, svc := range container.RegisteredWebServices() {
+		if !isUnder(svc.RootPath(), compactAPIPrefix) && !isUnder(svc.RootPath(), compactWatchAPIPrefix) && !isUnder(svc.RootPath(), yamlAPIPrefix) {
+			services = append(services, svc)
+		}
+	}
+	return services
+}
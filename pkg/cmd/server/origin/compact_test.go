@@ -0,0 +1,107 @@
+package origin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openshift/origin/pkg/api/compact"
+)
+
+func TestCompactRoutingFilter(t *testing.T) {
+	served := ""
+	handler := compactRoutingFilter(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		served = req.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+
+	testCases := map[string]struct {
+		path        string
+		contentType string
+		accept      string
+		served      string
+		response    string
+		code        int
+	}{
+		"json": {
+			path:     "/osapi/v1beta1/routes",
+			accept:   "application/json",
+			served:   "/osapi/v1beta1/routes",
+			response: "application/json",
+		},
+		"compact": {
+			path:     "/osapi/v1beta2/routes",
+			accept:   "application/vnd.openshift.gob, application/json;q=0.9",
+			served:   "/osapi-compact/v1beta2/routes",
+			response: compact.MediaType,
+		},
+		"compact request": {
+			path:        "/osapi/v1beta1/routes",
+			contentType: compact.MediaType,
+			served:      "/osapi-compact/v1beta1/routes",
+			response:    compact.MediaType,
+		},
+		"json preferred": {
+			path:     "/osapi/v1beta1/routes",
+			accept:   "application/json, application/vnd.openshift.gob",
+			served:   "/osapi/v1beta1/routes",
+			response: "application/json",
+		},
+		"watch": {
+			path:     "/osapi/v1beta1/watch/routes",
+			accept:   compact.MediaType,
+			served:   "/osapi-compact-watch/v1beta1/watch/routes",
+			response: "application/json",
+		},
+		"json watch": {
+			path:     "/osapi/v1beta1/watch/routes",
+			accept:   "application/json",
+			served:   "/osapi/v1beta1/watch/routes",
+			response: "application/json",
+		},
+		"limited list": {
+			path:     "/osapi/v1beta1/routes?limit=10",
+			accept:   compact.MediaType,
+			served:   "/osapi-compact/v1beta1/routes",
+			response: compact.MediaType,
+		},
+		"outside the API": {
+			path:     "/api/v1beta1/pods",
+			accept:   compact.MediaType,
+			served:   "/api/v1beta1/pods",
+			response: "application/json",
+		},
+		"compact API": {
+			path: "/osapi-compact/v1beta1/routes",
+			code: http.StatusNotFound,
+		},
+		"compact watch API": {
+			path: "/osapi-compact-watch/v1beta1/watch/routes",
+			code: http.StatusNotFound,
+		},
+	}
+	for name, tc := range testCases {
+		served = ""
+		req, _ := http.NewRequest("GET", tc.path, nil)
+		req.Header.Set("Content-Type", tc.contentType)
+		req.Header.Set("Accept", tc.accept)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		code := tc.code
+		if code == 0 {
+			code = http.StatusOK
+		}
+		if w.Code != code {
+			t.Errorf("%s: expected %d, got %d", name, code, w.Code)
+			continue
+		}
+		if served != tc.served {
+			t.Errorf("%s: expected %q to be served, got %q", name, tc.served, served)
+		}
+		if code == http.StatusOK && w.HeaderMap.Get("Content-Type") != tc.response {
+			t.Errorf("%s: expected Content-Type %q, got %q", name, tc.response, w.HeaderMap.Get("Content-Type"))
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package origin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+)
+
+// controllersPath reports the status of the controllers run by the master.  It is installed with the
+// debug endpoints, behind authorization.
+const controllersPath = "/debug/controllers"
+
+// ControllerFunc starts a controller that runs until stop is closed.  The controller calls synced each
+// time it completes a unit of work, such as handling an object from its queue or a pass over every object,
+// and crashed with the value of any panic in the goroutines it runs.
+type ControllerFunc func(stop <-chan struct{}, synced func(), crashed func(interface{}))
+
+// ControllerStatus reports the state of a controller run by the ControllerManager.
+type ControllerStatus struct {
+	// Name identifies the controller
+	Name string `json:"name"`
+	// Enabled is false if the controller is disabled in the configuration of the master
+	Enabled bool `json:"enabled"`
+	// Running is true once the controller has started, until it fails
+	Running bool `json:"running"`
+	// Restarts is the number of times the controller was restarted after failing
+	Restarts int `json:"restarts"`
+	// LastSyncTime is when the controller last completed a unit of work
+	LastSyncTime util.Time `json:"lastSyncTime,omitempty"`
+	// LastError describes the last failure of the controller
+	LastError string `json:"lastError,omitempty"`
+}
+
+// ControllerManager starts the controllers of the master, stops the controllers that panic while starting
+// or running and restarts them after a backoff, and reports the status of each controller.
+type ControllerManager struct {
+	lock        sync.Mutex
+	controllers []*managedController
+
+	// initialBackoff is how long a failed controller waits to be restarted the first time, doubling up to
+	// maxBackoff on further failures until the controller syncs
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	now            func() time.Time
+}
+
+// managedController is a controller and its status.
+type managedController struct {
+	status  ControllerStatus
+	run     ControllerFunc
+	stop    chan struct{}
+	backoff time.Duration
+}
+
+// NewControllerManager returns a manager without controllers.
+func NewControllerManager() *ControllerManager {
+	return &ControllerManager{initialBackoff: time.Second, maxBackoff: time.Minute, now: time.Now}
+}
+
+// Add registers the controller name, which is started by Start if enabled.
+func (m *ControllerManager) Add(name string, enabled bool, run ControllerFunc) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.controllers = append(m.controllers, &managedController{
+		status:  ControllerStatus{Name: name, Enabled: enabled},
+		run:     run,
+		backoff: m.initialBackoff,
+	})
+}
+
+// Start starts every enabled controller in the order they were added.
+func (m *ControllerManager) Start() {
+	m.lock.Lock()
+	controllers := append([]*managedController{}, m.controllers...)
+	m.lock.Unlock()
+
+	for _, c := range controllers {
+		if !c.status.Enabled {
+			glog.Infof("Controller %s is disabled", c.status.Name)
+			continue
+		}
+		m.start(c)
+	}
+}
+
+// Stop stops every running controller.
+func (m *ControllerManager) Stop() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, c := range m.controllers {
+		if c.stop != nil {
+			close(c.stop)
+			c.stop = nil
+		}
+		c.status.Running = false
+	}
+}
+
+// start runs c, and schedules a restart after its backoff if it panics.
+func (m *ControllerManager) start(c *managedController) {
+	stop := make(chan struct{})
+	m.lock.Lock()
+	c.stop = stop
+	m.lock.Unlock()
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		buf := make([]byte, 4096)
+		buf = buf[:runtime.Stack(buf, false)]
+		glog.Errorf("Controller %s panicked while starting: %v\n%s", c.status.Name, r, buf)
+		m.crashed(c, stop, r)
+	}()
+
+	c.run(stop, func() { m.synced(c) }, func(r interface{}) {
+		glog.Errorf("Controller %s panicked: %v", c.status.Name, r)
+		m.crashed(c, stop, r)
+	})
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if c.stop == stop {
+		c.status.Running = true
+	}
+}
+
+// crashed stops c, which panicked with r while running until stop is closed, and schedules a restart after
+// its backoff.  Only the first panic of a run restarts the controller.
+func (m *ControllerManager) crashed(c *managedController, stop chan struct{}, r interface{}) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if c.stop != stop {
+		// the manager was stopped, or the controller has already been stopped for an earlier panic
+		return
+	}
+	close(stop)
+	c.stop = nil
+	c.status.Running = false
+	c.status.Restarts++
+	c.status.LastError = fmt.Sprintf("%v", r)
+	backoff := c.backoff
+	if c.backoff *= 2; c.backoff > m.maxBackoff {
+		c.backoff = m.maxBackoff
+	}
+	glog.Infof("Restarting controller %s in %v", c.status.Name, backoff)
+	time.AfterFunc(backoff, func() { m.start(c) })
+}
+
+// synced records that c completed a unit of work.
+func (m *ControllerManager) synced(c *managedController) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	c.status.LastSyncTime = util.NewTime(m.now())
+	c.backoff = m.initialBackoff
+}
+
+// Status returns the status of every controller in the order they were added.
+func (m *ControllerManager) Status() []ControllerStatus {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	statuses := []ControllerStatus{}
+	for _, c := range m.controllers {
+		statuses = append(statuses, c.status)
+	}
+	return statuses
+}
+
+// ServeHTTP writes the status of every controller as JSON.
+func (m *ControllerManager) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	data, err := json.MarshalIndent(m.Status(), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -0,0 +1,141 @@
+package origin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestControllerManagerStartsEnabledControllers(t *testing.T) {
+	now := time.Date(2015, 3, 1, 10, 0, 0, 0, time.UTC)
+	manager := NewControllerManager()
+	manager.now = func() time.Time { return now }
+
+	started := map[string]bool{}
+	manager.Add("build", true, func(stop <-chan struct{}, synced func(), crashed func(interface{})) {
+		started["build"] = true
+		synced()
+	})
+	manager.Add("deployment", false, func(stop <-chan struct{}, synced func(), crashed func(interface{})) {
+		started["deployment"] = true
+	})
+	manager.Start()
+	defer manager.Stop()
+
+	if !started["build"] || started["deployment"] {
+		t.Errorf("Expected only the enabled controller to start, got %v", started)
+	}
+	statuses := manager.Status()
+	if len(statuses) != 2 {
+		t.Fatalf("Expected the status of both controllers, got %#v", statuses)
+	}
+	if s := statuses[0]; s.Name != "build" || !s.Enabled || !s.Running || !s.LastSyncTime.Time.Equal(now) {
+		t.Errorf("Unexpected status of the enabled controller: %#v", s)
+	}
+	if s := statuses[1]; s.Name != "deployment" || s.Enabled || s.Running || !s.LastSyncTime.IsZero() {
+		t.Errorf("Unexpected status of the disabled controller: %#v", s)
+	}
+}
+
+func TestControllerManagerRestartsPanickingControllers(t *testing.T) {
+	manager := NewControllerManager()
+	manager.initialBackoff = time.Millisecond
+
+	attempts := make(chan (<-chan struct{}), 2)
+	count := 0
+	manager.Add("build", true, func(stop <-chan struct{}, synced func(), crashed func(interface{})) {
+		count++
+		attempts <- stop
+		if count == 1 {
+			panic("unable to list builds")
+		}
+	})
+	manager.Start()
+	defer manager.Stop()
+
+	first := <-attempts
+	select {
+	case <-first:
+	default:
+		t.Errorf("Expected the failed controller to be stopped")
+	}
+	select {
+	case <-attempts:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the controller to be restarted")
+	}
+
+	// the restarted controller is running once its start returns
+	var status ControllerStatus
+	for i := 0; i < 100; i++ {
+		if status = manager.Status()[0]; status.Running {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !status.Running || status.Restarts != 1 || status.LastError != "unable to list builds" {
+		t.Errorf("Unexpected status of the restarted controller: %#v", status)
+	}
+}
+
+func TestControllerManagerRestartsCrashedControllers(t *testing.T) {
+	manager := NewControllerManager()
+	manager.initialBackoff = time.Millisecond
+
+	attempts := make(chan (<-chan struct{}), 2)
+	count := 0
+	manager.Add("build", true, func(stop <-chan struct{}, synced func(), crashed func(interface{})) {
+		count++
+		attempts <- stop
+		if count == 1 {
+			// a panic in a goroutine of the controller is reported after the controller has started
+			go func() {
+				crashed("unable to handle build")
+				crashed("unable to handle another build")
+			}()
+		}
+	})
+	manager.Start()
+	defer manager.Stop()
+
+	first := <-attempts
+	select {
+	case <-first:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the crashed controller to be stopped")
+	}
+	select {
+	case <-attempts:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the controller to be restarted")
+	}
+
+	var status ControllerStatus
+	for i := 0; i < 100; i++ {
+		if status = manager.Status()[0]; status.Running {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !status.Running || status.Restarts != 1 || status.LastError != "unable to handle build" {
+		t.Errorf("Unexpected status of the restarted controller: %#v", status)
+	}
+}
+
+func TestControllerManagerServesStatus(t *testing.T) {
+	manager := NewControllerManager()
+	manager.Add("build", false, func(<-chan struct{}, func(), func(interface{})) {})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", controllersPath, nil)
+	manager.ServeHTTP(w, req)
+	statuses := []ControllerStatus{}
+	if err := json.Unmarshal(w.Body.Bytes(), &statuses); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Name != "build" || statuses[0].Enabled {
+		t.Errorf("Unexpected status: %#v", statuses)
+	}
+}
@@ -0,0 +1,114 @@
+package origin
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// defaultCORSAllowedMethods are the methods cross origin requests may use if none are configured
+	defaultCORSAllowedMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	// defaultCORSAllowedHeaders are the headers cross origin requests may send if none are configured
+	defaultCORSAllowedHeaders = []string{"Authorization", "Accept", "Accept-Encoding", "Content-Type", "Content-Length", "If-Modified-Since", "If-None-Match", "X-CSRF-Token", "X-Requested-With", ImpersonateUserHeader}
+	// defaultCORSExposedHeaders are the response headers cross origin clients may read if none are configured
+	defaultCORSExposedHeaders = []string{"Retry-After", "Warning", "WWW-Authenticate"}
+)
+
+// corsConfig is how the master answers cross origin requests.
+type corsConfig struct {
+	// AllowedOrigins match the origins allowed to make cross origin requests
+	AllowedOrigins []*regexp.Regexp
+	// AllowedMethods, AllowedHeaders and ExposedHeaders use the defaults if empty
+	AllowedMethods []string
+	AllowedHeaders []string
+	ExposedHeaders []string
+	// MaxAgeSeconds is how long browsers may cache the answer to a preflight request, they choose if zero
+	MaxAgeSeconds int
+}
+
+// corsFilter answers preflight requests itself, without authenticating them, and sets the CORS headers on the
+// responses to allowed origins.  The headers are set again when the response is written, so the CORS headers of
+// a proxied or streamed response never conflict with those of the master.
+func corsFilter(handler http.Handler, config corsConfig) http.Handler {
+	methods := strings.Join(stringsOrDefault(config.AllowedMethods, defaultCORSAllowedMethods), ", ")
+	headers := strings.Join(stringsOrDefault(config.AllowedHeaders, defaultCORSAllowedHeaders), ", ")
+	exposed := strings.Join(stringsOrDefault(config.ExposedHeaders, defaultCORSExposedHeaders), ", ")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		origin := req.Header.Get("Origin")
+		if len(origin) == 0 {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		// responses differ by origin, caches must not serve one origin the response to another
+		w.Header().Add("Vary", "Origin")
+		allowed := false
+		for _, pattern := range config.AllowedOrigins {
+			if allowed = pattern.MatchString(origin); allowed {
+				break
+			}
+		}
+
+		if req.Method == "OPTIONS" && len(req.Header.Get("Access-Control-Request-Method")) > 0 {
+			if !allowed {
+				http.Error(w, "Cross origin requests are not allowed from "+origin, http.StatusForbidden)
+				return
+			}
+			setCORSHeaders(w.Header(), origin)
+			w.Header().Set("Access-Control-Allow-Methods", methods)
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+			if config.MaxAgeSeconds > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(config.MaxAgeSeconds))
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if !allowed {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		// set before the response is written too, in case the handler writes nothing
+		setCORSHeaders(w.Header(), origin)
+		w.Header().Set("Access-Control-Expose-Headers", exposed)
+		handler.ServeHTTP(&corsResponseWriter{&responseWriterDelegator{ResponseWriter: w}, origin, exposed}, req)
+	})
+}
+
+// setCORSHeaders allows origin to read a response made with credentials.
+func setCORSHeaders(header http.Header, origin string) {
+	header.Set("Access-Control-Allow-Origin", origin)
+	header.Set("Access-Control-Allow-Credentials", "true")
+}
+
+func stringsOrDefault(values, defaults []string) []string {
+	if len(values) == 0 {
+		return defaults
+	}
+	return values
+}
+
+// corsResponseWriter sets the CORS headers of an allowed origin again when the response is written, replacing
+// any a proxied backend copied in.
+type corsResponseWriter struct {
+	*responseWriterDelegator
+	origin  string
+	exposed string
+}
+
+func (w *corsResponseWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		setCORSHeaders(w.Header(), w.origin)
+		w.Header().Set("Access-Control-Expose-Headers", w.exposed)
+	}
+	w.responseWriterDelegator.WriteHeader(code)
+}
+
+func (w *corsResponseWriter) Write(data []byte) (int, error) {
+	if w.code == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.responseWriterDelegator.Write(data)
+}
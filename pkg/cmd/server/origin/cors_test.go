@@ -0,0 +1,117 @@
+package origin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestCORSFilter(t *testing.T) {
+	config := corsConfig{
+		AllowedOrigins: []*regexp.Regexp{regexp.MustCompile(`^https://console\.example\.com$`)},
+		AllowedHeaders: []string{"Authorization", "X-Custom"},
+		MaxAgeSeconds:  600,
+	}
+	testCases := map[string]struct {
+		method    string
+		origin    string
+		preflight bool
+		upstream  string
+
+		code       int
+		dispatched bool
+		headers    map[string]string
+	}{
+		"same origin": {
+			method: "GET", code: http.StatusOK, dispatched: true,
+			headers: map[string]string{"Access-Control-Allow-Origin": "", "Vary": ""},
+		},
+		"allowed origin": {
+			method: "GET", origin: "https://console.example.com", code: http.StatusOK, dispatched: true,
+			headers: map[string]string{
+				"Access-Control-Allow-Origin":      "https://console.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "Retry-After, Warning, WWW-Authenticate",
+				"Vary":                             "Origin",
+			},
+		},
+		"proxied CORS headers are replaced": {
+			method: "GET", origin: "https://console.example.com", upstream: "*", code: http.StatusOK, dispatched: true,
+			headers: map[string]string{"Access-Control-Allow-Origin": "https://console.example.com"},
+		},
+		"other origin": {
+			method: "GET", origin: "https://evil.example.com", code: http.StatusOK, dispatched: true,
+			headers: map[string]string{"Access-Control-Allow-Origin": "", "Vary": "Origin"},
+		},
+		"preflight": {
+			method: "OPTIONS", origin: "https://console.example.com", preflight: true, code: http.StatusNoContent,
+			headers: map[string]string{
+				"Access-Control-Allow-Origin":  "https://console.example.com",
+				"Access-Control-Allow-Methods": "GET, HEAD, POST, PUT, PATCH, DELETE, OPTIONS",
+				"Access-Control-Allow-Headers": "Authorization, X-Custom",
+				"Access-Control-Max-Age":       "600",
+			},
+		},
+		"preflight from other origin": {
+			method: "OPTIONS", origin: "https://evil.example.com", preflight: true, code: http.StatusForbidden,
+			headers: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		"options without preflight": {
+			method: "OPTIONS", origin: "https://console.example.com", code: http.StatusOK, dispatched: true,
+			headers: map[string]string{"Access-Control-Allow-Methods": ""},
+		},
+	}
+
+	for name, tc := range testCases {
+		dispatched := false
+		handler := corsFilter(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			dispatched = true
+			if len(tc.upstream) > 0 {
+				w.Header().Set("Access-Control-Allow-Origin", tc.upstream)
+			}
+			w.Write([]byte("{}"))
+		}), config)
+
+		req, _ := http.NewRequest(tc.method, "/osapi/v1beta1/projects", nil)
+		if len(tc.origin) > 0 {
+			req.Header.Set("Origin", tc.origin)
+		}
+		if tc.preflight {
+			req.Header.Set("Access-Control-Request-Method", "DELETE")
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != tc.code {
+			t.Errorf("%s: expected %d, got %d", name, tc.code, w.Code)
+		}
+		if dispatched != tc.dispatched {
+			t.Errorf("%s: expected dispatched %t, got %t", name, tc.dispatched, dispatched)
+		}
+		for header, expected := range tc.headers {
+			if got := w.Header().Get(header); got != expected {
+				t.Errorf("%s: expected %s %q, got %q", name, header, expected, got)
+			}
+		}
+	}
+}
+
+func TestCORSFilterStreaming(t *testing.T) {
+	config := corsConfig{AllowedOrigins: []*regexp.Regexp{regexp.MustCompile(`.*`)}}
+	handler := corsFilter(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, ok := w.(http.Flusher); !ok {
+			t.Errorf("expected the response of a watch to be flushable")
+		}
+		if _, ok := w.(http.Hijacker); !ok {
+			t.Errorf("expected the response of an upgrade to be hijackable")
+		}
+		if _, ok := w.(http.CloseNotifier); !ok {
+			t.Errorf("expected the response of a watch to notify of closed connections")
+		}
+	}), config)
+
+	req, _ := http.NewRequest("GET", "/api/v1beta1/watch/pods", nil)
+	req.Header.Set("Origin", "https://console.example.com")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
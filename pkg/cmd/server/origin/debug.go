@@ -0,0 +1,39 @@
+package origin
+
+import (
+	_ "expvar"
+	"net/http"
+	"net/http/pprof"
+	runtimepprof "runtime/pprof"
+
+	restful "github.com/emicklei/go-restful"
+)
+
+// installDebug registers the Go profiler at /debug/pprof/, the exported runtime variables at /debug/vars, a
+// dump of every goroutine stack at /debug/goroutines, the status of the controllers at /debug/controllers, and
+// the requests made by each user and OAuth client at /debug/requestusage.  They must be installed behind
+// authorization, which requires the debug verb for them.
+func (c *MasterConfig) installDebug(container *restful.Container) []string {
+	container.Handle("/debug/pprof/", http.HandlerFunc(pprof.Index))
+	container.Handle("/debug/pprof/cmdline", http.HandlerFunc(pprof.Cmdline))
+	container.Handle("/debug/pprof/profile", http.HandlerFunc(pprof.Profile))
+	container.Handle("/debug/pprof/symbol", http.HandlerFunc(pprof.Symbol))
+	// expvar registers its handler on the default mux
+	container.Handle("/debug/vars", http.DefaultServeMux)
+	container.Handle("/debug/goroutines", http.HandlerFunc(goroutineDump))
+	if c.Controllers != nil {
+		container.Handle(controllersPath, c.Controllers)
+	}
+	if c.requestUsage != nil {
+		container.Handle(requestUsagePath, c.requestUsage)
+	}
+	return []string{
+		"Started debug endpoints at %s/debug",
+	}
+}
+
+// goroutineDump writes the stack of every goroutine.
+func goroutineDump(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	runtimepprof.Lookup("goroutine").WriteTo(w, 2)
+}
@@ -0,0 +1,136 @@
+package origin
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/golang/glog"
+)
+
+// deprecatedAPIVersions maps the OpenShift API versions that will be removed to the version clients should
+// use instead.
+var deprecatedAPIVersions = map[string]string{
+	"v1beta1": "v1beta2",
+}
+
+// deprecatedField is a field of an API object that clients should stop setting.
+type deprecatedField struct {
+	// resource is the resource the object is sent to
+	resource string
+	// path is the JSON path of the field, dot separated.  Lists along the path are searched item by item.
+	path string
+	// replacement is the path of the field clients should set instead
+	replacement string
+}
+
+// deprecatedFields are the fields that are still accepted but will be removed.
+var deprecatedFields = []deprecatedField{
+	{"builds", "parameters.strategy.stiStrategy.builderImage", "parameters.strategy.stiStrategy.image"},
+	{"builds", "parameters.output.imageTag", "parameters.output.dockerImageReference"},
+	{"builds", "parameters.output.registry", "parameters.output.dockerImageReference"},
+	{"buildConfigs", "parameters.strategy.stiStrategy.builderImage", "parameters.strategy.stiStrategy.image"},
+	{"buildConfigs", "parameters.output.imageTag", "parameters.output.dockerImageReference"},
+	{"buildConfigs", "parameters.output.registry", "parameters.output.dockerImageReference"},
+	{"buildConfigs", "triggers.imageChange.imageRepositoryRef", "triggers.imageChange.from"},
+	{"deploymentConfigs", "triggers.imageChangeParams.repositoryName", "triggers.imageChangeParams.from"},
+}
+
+// deprecationKey identifies the requests a deprecation count is recorded for.
+type deprecationKey struct {
+	version  string
+	resource string
+	// field is the deprecated field set by the request, or empty if the request used a deprecated version
+	field string
+}
+
+// deprecationFilter warns clients that use a deprecated OpenShift API version or set a deprecated field
+// with a Warning response header, and counts the request in metrics so operators can find the clients to
+// migrate before the version or field is removed.  The filter reads request bodies, so it must run after
+// the request is authenticated.
+func deprecationFilter(handler http.Handler, metrics *Metrics) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !strings.HasPrefix(req.URL.Path, OpenShiftAPIPrefix+"/") {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		version := strings.SplitN(strings.TrimPrefix(req.URL.Path, OpenShiftAPIPrefix+"/"), "/", 2)[0]
+		// only resources the API serves are recorded, see requestKeyFor
+		resource := requestKeyFor(req).resource
+
+		deprecations := []deprecationKey{}
+		if replacement, ok := deprecatedAPIVersions[version]; ok {
+			addWarning(w, fmt.Sprintf("OpenShift API version %s is deprecated, use %s", version, replacement))
+			deprecations = append(deprecations, deprecationKey{version, resource, ""})
+		}
+		if (req.Method == "POST" || req.Method == "PUT") && req.Body != nil {
+			body, ok := readRequestBody(w, req)
+			if !ok {
+				return
+			}
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			for _, field := range setDeprecatedFields(resource, body) {
+				addWarning(w, fmt.Sprintf("%s is deprecated, use %s", field.path, field.replacement))
+				deprecations = append(deprecations, deprecationKey{version, resource, field.path})
+			}
+		}
+
+		if len(deprecations) > 0 {
+			metrics.lock.Lock()
+			for _, key := range deprecations {
+				metrics.deprecatedRequests[key]++
+			}
+			metrics.lock.Unlock()
+			glog.V(2).Infof("Deprecated API use by %q from %s: %s %s", req.UserAgent(), req.RemoteAddr, req.Method, req.RequestURI)
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// addWarning adds a persistent warning (code 299) with text to the response.
+func addWarning(w http.ResponseWriter, text string) {
+	w.Header().Add("Warning", fmt.Sprintf("299 - %q", text))
+}
+
+// setDeprecatedFields returns the deprecated fields of resource set in the JSON or YAML object in body.
+// Bodies that are not objects have no deprecated fields.
+func setDeprecatedFields(resource string, body []byte) []deprecatedField {
+	var obj interface{}
+	if err := yaml.Unmarshal(body, &obj); err != nil {
+		return nil
+	}
+	set := []deprecatedField{}
+	for _, field := range deprecatedFields {
+		if field.resource == resource && hasField(obj, strings.Split(field.path, ".")) {
+			set = append(set, field)
+		}
+	}
+	return set
+}
+
+// hasField returns true if the field at path in obj is set.  Every item of a list along path is searched.
+func hasField(obj interface{}, path []string) bool {
+	switch t := obj.(type) {
+	case []interface{}:
+		for _, item := range t {
+			if hasField(item, path) {
+				return true
+			}
+		}
+		return false
+	case map[string]interface{}:
+		value, ok := t[path[0]]
+		if !ok || value == nil {
+			return false
+		}
+		if len(path) == 1 {
+			return value != ""
+		}
+		return hasField(value, path[1:])
+	default:
+		return false
+	}
+}
@@ -0,0 +1,107 @@
+package origin
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDeprecationFilter(t *testing.T) {
+	metrics := NewMetrics()
+	served := ""
+	handler := deprecationFilter(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Body != nil {
+			body, _ := ioutil.ReadAll(req.Body)
+			served = string(body)
+		}
+	}), metrics)
+
+	testCases := map[string]struct {
+		method   string
+		path     string
+		body     string
+		warnings []string
+	}{
+		"deprecated version": {
+			method:   "GET",
+			path:     "/osapi/v1beta1/builds",
+			warnings: []string{`299 - "OpenShift API version v1beta1 is deprecated, use v1beta2"`},
+		},
+		"current version": {
+			method: "GET",
+			path:   "/osapi/v1beta2/builds",
+		},
+		"deprecated field": {
+			method:   "POST",
+			path:     "/osapi/v1beta2/buildConfigs",
+			body:     `{"triggers":[{"type":"generic"},{"type":"imageChange","imageChange":{"imageRepositoryRef":{"name":"ruby"}}}]}`,
+			warnings: []string{`299 - "triggers.imageChange.imageRepositoryRef is deprecated, use triggers.imageChange.from"`},
+		},
+		"deprecated field as YAML": {
+			method:   "POST",
+			path:     "/osapi/v1beta2/deploymentConfigs",
+			body:     "triggers:\n- type: ImageChange\n  imageChangeParams:\n    repositoryName: registry:5000/test/ruby\n",
+			warnings: []string{`299 - "triggers.imageChangeParams.repositoryName is deprecated, use triggers.imageChangeParams.from"`},
+		},
+		"deprecated field and version": {
+			method: "PUT",
+			path:   "/osapi/v1beta1/builds/ruby",
+			body:   `{"parameters":{"output":{"imageTag":"ruby","registry":"registry:5000"}}}`,
+			warnings: []string{
+				`299 - "OpenShift API version v1beta1 is deprecated, use v1beta2"`,
+				`299 - "parameters.output.imageTag is deprecated, use parameters.output.dockerImageReference"`,
+				`299 - "parameters.output.registry is deprecated, use parameters.output.dockerImageReference"`,
+			},
+		},
+		"empty deprecated field": {
+			method: "POST",
+			path:   "/osapi/v1beta2/builds",
+			body:   `{"parameters":{"output":{"imageTag":""}}}`,
+		},
+		"field of another resource": {
+			method: "POST",
+			path:   "/osapi/v1beta2/deploymentConfigs",
+			body:   `{"parameters":{"output":{"imageTag":"ruby"}}}`,
+		},
+		"not an object": {
+			method: "POST",
+			path:   "/osapi/v1beta2/builds",
+			body:   `parameters`,
+		},
+		"outside the API": {
+			method: "GET",
+			path:   "/api/v1beta1/pods",
+		},
+	}
+	for name, tc := range testCases {
+		served = ""
+		req, _ := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if warnings := w.HeaderMap["Warning"]; !reflect.DeepEqual(warnings, tc.warnings) {
+			t.Errorf("%s: expected warnings %v, got %v", name, tc.warnings, warnings)
+		}
+		if served != tc.body {
+			t.Errorf("%s: expected the body to be passed on, got %q", name, served)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, &http.Request{})
+	out := w.Body.String()
+	for _, expected := range []string{
+		`master_http_deprecated_requests_total{version="v1beta1",resource="builds",field=""} 2`,
+		`master_http_deprecated_requests_total{version="v1beta1",resource="builds",field="parameters.output.imageTag"} 1`,
+		`master_http_deprecated_requests_total{version="v1beta2",resource="buildConfigs",field="triggers.imageChange.imageRepositoryRef"} 1`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in metrics, got:\n%s", expected, out)
+		}
+	}
+	if strings.Contains(out, `version="v1beta2",resource="builds"`) {
+		t.Errorf("Expected no deprecations for the current version, got:\n%s", out)
+	}
+}
@@ -0,0 +1,87 @@
+package origin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kmeta "github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/emicklei/go-restful"
+
+	osapi "github.com/openshift/origin/pkg/api"
+)
+
+// apiResources returns the discovery document of version, describing every resource in storage.  The kind
+// and scope of a resource come from mapper, its verbs from the interfaces its storage implements.
+func apiResources(version string, storage map[string]apiserver.RESTStorage, mapper kmeta.RESTMapper) (*osapi.APIResourceList, error) {
+	list := &osapi.APIResourceList{Version: version, Resources: []osapi.APIResource{}}
+	for name, s := range storage {
+		_, kind, err := kapi.Scheme.ObjectVersionAndKind(s.New())
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine the kind of %s: %v", name, err)
+		}
+		mapping, err := mapper.RESTMapping(kind, version)
+		if err != nil {
+			return nil, fmt.Errorf("unable to map %s of kind %s: %v", name, kind, err)
+		}
+		list.Resources = append(list.Resources, osapi.APIResource{
+			Name:       name,
+			Kind:       kind,
+			Namespaced: mapping.Scope.Name() == kmeta.RESTScopeNameNamespace,
+			Verbs:      storageVerbs(s),
+		})
+	}
+	sort.Sort(resourcesByName(list.Resources))
+	return list, nil
+}
+
+// storageVerbs returns the verbs storage supports.  Objects that can be read and updated can be patched.
+func storageVerbs(storage apiserver.RESTStorage) []string {
+	verbs := []string{}
+	_, getter := storage.(apiserver.RESTGetter)
+	_, updater := storage.(apiserver.RESTUpdater)
+	if getter {
+		verbs = append(verbs, "get")
+	}
+	if _, ok := storage.(apiserver.RESTLister); ok {
+		verbs = append(verbs, "list")
+	}
+	if _, ok := storage.(apiserver.ResourceWatcher); ok {
+		verbs = append(verbs, "watch")
+	}
+	if _, ok := storage.(apiserver.RESTCreater); ok {
+		verbs = append(verbs, "create")
+	}
+	if updater {
+		verbs = append(verbs, "update")
+	}
+	if getter && updater {
+		verbs = append(verbs, "patch")
+	}
+	if _, ok := storage.(apiserver.RESTDeleter); ok {
+		verbs = append(verbs, "delete")
+	}
+	return verbs
+}
+
+type resourcesByName []osapi.APIResource
+
+func (r resourcesByName) Len() int           { return len(r) }
+func (r resourcesByName) Less(i, j int) bool { return r[i].Name < r[j].Name }
+func (r resourcesByName) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+
+// discoveryHandler returns a handler that writes obj as JSON.
+func discoveryHandler(obj interface{}) restful.RouteFunction {
+	return func(req *restful.Request, resp *restful.Response) {
+		data, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			http.Error(resp.ResponseWriter, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resp.ResponseWriter.Header().Set("Content-Type", restful.MIME_JSON)
+		resp.ResponseWriter.Write(data)
+	}
+}
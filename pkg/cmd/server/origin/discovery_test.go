@@ -0,0 +1,76 @@
+package origin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/emicklei/go-restful"
+
+	osapi "github.com/openshift/origin/pkg/api"
+	"github.com/openshift/origin/pkg/api/latest"
+	routeapi "github.com/openshift/origin/pkg/route/api"
+	userapi "github.com/openshift/origin/pkg/user/api"
+)
+
+type fakeRouteStorage struct{}
+
+func (fakeRouteStorage) New() runtime.Object { return &routeapi.Route{} }
+func (fakeRouteStorage) Get(ctx kapi.Context, id string) (runtime.Object, error) {
+	return &routeapi.Route{}, nil
+}
+func (fakeRouteStorage) Update(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	return nil, nil
+}
+
+type fakeUserStorage struct{}
+
+func (fakeUserStorage) New() runtime.Object { return &userapi.User{} }
+func (fakeUserStorage) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	return nil, nil
+}
+
+func TestAPIResources(t *testing.T) {
+	storage := map[string]apiserver.RESTStorage{
+		"users":  fakeUserStorage{},
+		"routes": fakeRouteStorage{},
+	}
+	list, err := apiResources("v1beta1", storage, latest.RESTMapper)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := &osapi.APIResourceList{
+		Version: "v1beta1",
+		Resources: []osapi.APIResource{
+			{Name: "routes", Kind: "Route", Namespaced: true, Verbs: []string{"get", "update", "patch"}},
+			{Name: "users", Kind: "User", Namespaced: false, Verbs: []string{"create"}},
+		},
+	}
+	if !reflect.DeepEqual(expected, list) {
+		t.Errorf("Expected %#v, got %#v", expected, list)
+	}
+}
+
+func TestAPIVersionRouteNamesThePreferredVersion(t *testing.T) {
+	container := restful.NewContainer()
+	service := new(restful.WebService)
+	initAPIVersionRoute(service, "v1beta1", "v1beta1", "v1beta2")
+	container.Add(service)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "http://localhost:8443"+OpenShiftAPIPrefix, nil)
+	container.ServeHTTP(w, req)
+	group := osapi.APIGroup{}
+	if err := json.Unmarshal(w.Body.Bytes(), &group); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := osapi.APIGroup{Versions: []string{"v1beta1", "v1beta2"}, PreferredVersion: "v1beta1"}
+	if !reflect.DeepEqual(expected, group) {
+		t.Errorf("Expected %#v, got %#v", expected, group)
+	}
+}
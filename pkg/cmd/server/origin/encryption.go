@@ -0,0 +1,342 @@
+package origin
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/coreos/go-etcd/etcd"
+	"github.com/golang/glog"
+
+	buildetcd "github.com/openshift/origin/pkg/build/registry/etcd"
+	oauthetcd "github.com/openshift/origin/pkg/oauth/registry/etcd"
+	routeetcd "github.com/openshift/origin/pkg/route/registry/etcd"
+)
+
+// EncryptionKeySize is the size in bytes of the keys that encrypt objects stored in etcd.
+const EncryptionKeySize = 32
+
+// encryptedValuePrefix marks a value stored in etcd as encrypted.  It is followed by the name of the key the
+// value was encrypted with and a colon.
+const encryptedValuePrefix = "openshift:enc:aesgcm:v1:"
+
+// SensitiveEtcdPaths are the etcd keys of objects holding credentials: OAuth, bootstrap and router tokens,
+// client secrets, build configs with webhook secrets, and routes with TLS keys.
+var SensitiveEtcdPaths = []string{
+	oauthetcd.OAuthAccessTokenPath,
+	oauthetcd.OAuthAuthorizeTokenPath,
+	oauthetcd.OAuthClientPath,
+	oauthetcd.BootstrapTokenPath,
+	oauthetcd.RouterTokenPath,
+	buildetcd.BuildConfigPath,
+	routeetcd.RoutePath,
+}
+
+// EncryptionKey is a named key that encrypts objects stored in etcd.
+type EncryptionKey struct {
+	Name   string
+	Secret []byte
+}
+
+// NewEncryptingEtcdClient returns an etcd client that encrypts the values stored under paths through client.
+// Each value is encrypted with a data key of its own, which is stored with the value encrypted by the first
+// of keys.  Values encrypted by any of keys can be read, so keys are rotated by adding a new first key; the
+// old key must be kept until every object encrypted with it has been written again, for example by
+// migrating storage.  Values stored before encryption was configured are read unchanged.  client is returned
+// if keys is empty.
+func NewEncryptingEtcdClient(client tools.EtcdGetSet, keys []EncryptionKey, paths []string) (tools.EtcdGetSet, error) {
+	if len(keys) == 0 {
+		return client, nil
+	}
+	c := &encryptingEtcdClient{
+		EtcdGetSet: client,
+		keys:       make(map[string]cipher.AEAD),
+		paths:      paths,
+	}
+	for i, key := range keys {
+		if len(key.Name) == 0 || strings.Contains(key.Name, ":") {
+			return nil, fmt.Errorf("invalid encryption key name %q", key.Name)
+		}
+		if len(key.Secret) != EncryptionKeySize {
+			return nil, fmt.Errorf("encryption key %q must be %d bytes", key.Name, EncryptionKeySize)
+		}
+		if _, exists := c.keys[key.Name]; exists {
+			return nil, fmt.Errorf("duplicate encryption key %q", key.Name)
+		}
+		aead, err := newAEAD(key.Secret)
+		if err != nil {
+			return nil, err
+		}
+		c.keys[key.Name] = aead
+		if i == 0 {
+			c.current = key.Name
+		}
+	}
+	return c, nil
+}
+
+// EncryptedAsConfigured returns true if stored, the value of key as read from etcd without decrypting it, is
+// encrypted the way client writes key: with the current key if key is sensitive, and not at all otherwise.
+// Clients that do not encrypt write every value unencrypted.
+func EncryptedAsConfigured(client tools.EtcdGetSet, key, stored string) bool {
+	c, ok := client.(*encryptingEtcdClient)
+	if !ok || !c.sensitive(key) {
+		return !strings.HasPrefix(stored, encryptedValuePrefix)
+	}
+	return strings.HasPrefix(stored, encryptedValuePrefix+c.current+":")
+}
+
+// encryptingEtcdClient encrypts the values of the keys under paths.
+type encryptingEtcdClient struct {
+	tools.EtcdGetSet
+	// keys are the key encryption keys values can be read with, by name
+	keys map[string]cipher.AEAD
+	// current is the name of the key new values are encrypted with
+	current string
+	paths   []string
+}
+
+// sensitive returns true if the value of key is encrypted.
+func (c *encryptingEtcdClient) sensitive(key string) bool {
+	for _, p := range c.paths {
+		if key == p || strings.HasPrefix(key, strings.TrimSuffix(p, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// encrypt seals value with a new data key, and the data key with the current key.  The result is
+// base64 encoded so it survives etcd's JSON encoding.
+func (c *encryptingEtcdClient) encrypt(value string) (string, error) {
+	dataKey := make([]byte, EncryptionKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	envelope := seal(c.keys[c.current], nil, dataKey)
+	envelope = seal(data, envelope, []byte(value))
+	return encryptedValuePrefix + c.current + ":" + base64.StdEncoding.EncodeToString(envelope), nil
+}
+
+// decrypt returns the plaintext of a value written by encrypt.  Values without the encrypted prefix are
+// returned unchanged.
+func (c *encryptingEtcdClient) decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedValuePrefix) {
+		return value, nil
+	}
+	parts := strings.SplitN(strings.TrimPrefix(value, encryptedValuePrefix), ":", 2)
+	if len(parts) != 2 {
+		return "", errors.New("malformed encrypted value")
+	}
+	key, ok := c.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("value is encrypted with unknown key %q", parts[0])
+	}
+	envelope, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %v", err)
+	}
+	dataKey, envelope, err := open(key, envelope, EncryptionKeySize)
+	if err != nil {
+		return "", err
+	}
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, _, err := open(data, envelope, len(envelope)-data.NonceSize()-data.Overhead())
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// response returns a copy of resp with the encrypted values decrypted.
+func (c *encryptingEtcdClient) response(resp *etcd.Response) (*etcd.Response, error) {
+	if resp == nil {
+		return nil, nil
+	}
+	decrypted := *resp
+	var err error
+	if decrypted.Node, err = c.node(resp.Node); err != nil {
+		return nil, err
+	}
+	if decrypted.PrevNode, err = c.node(resp.PrevNode); err != nil {
+		return nil, err
+	}
+	return &decrypted, nil
+}
+
+// node returns a copy of node and its children with the encrypted values decrypted.  Any encrypted value is
+// decrypted, whatever its key, so values stay readable if the sensitive paths change.
+func (c *encryptingEtcdClient) node(node *etcd.Node) (*etcd.Node, error) {
+	if node == nil {
+		return nil, nil
+	}
+	decrypted := *node
+	if !node.Dir {
+		value, err := c.decrypt(node.Value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt %s: %v", node.Key, err)
+		}
+		decrypted.Value = value
+	}
+	if node.Nodes != nil {
+		decrypted.Nodes = make(etcd.Nodes, 0, len(node.Nodes))
+		for _, child := range node.Nodes {
+			child, err := c.node(child)
+			if err != nil {
+				return nil, err
+			}
+			decrypted.Nodes = append(decrypted.Nodes, child)
+		}
+	}
+	return &decrypted, nil
+}
+
+// value returns the value to store for key.
+func (c *encryptingEtcdClient) value(key, value string) (string, error) {
+	if !c.sensitive(key) {
+		return value, nil
+	}
+	return c.encrypt(value)
+}
+
+func (c *encryptingEtcdClient) Get(key string, sort, recursive bool) (*etcd.Response, error) {
+	resp, err := c.EtcdGetSet.Get(key, sort, recursive)
+	if err != nil {
+		return resp, err
+	}
+	return c.response(resp)
+}
+
+func (c *encryptingEtcdClient) Set(key, value string, ttl uint64) (*etcd.Response, error) {
+	value, err := c.value(key, value)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.EtcdGetSet.Set(key, value, ttl)
+	if err != nil {
+		return resp, err
+	}
+	return c.response(resp)
+}
+
+func (c *encryptingEtcdClient) Create(key, value string, ttl uint64) (*etcd.Response, error) {
+	value, err := c.value(key, value)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.EtcdGetSet.Create(key, value, ttl)
+	if err != nil {
+		return resp, err
+	}
+	return c.response(resp)
+}
+
+// CompareAndSwap encrypts value.  Callers compare against the plaintext they read, which never matches the
+// stored value of a sensitive key, so prevValue is only compared when no prevIndex is given, and then against
+// the decrypted stored value.
+func (c *encryptingEtcdClient) CompareAndSwap(key, value string, ttl uint64, prevValue string, prevIndex uint64) (*etcd.Response, error) {
+	if !c.sensitive(key) {
+		return c.EtcdGetSet.CompareAndSwap(key, value, ttl, prevValue, prevIndex)
+	}
+	if len(prevValue) > 0 {
+		if prevIndex == 0 {
+			current, err := c.Get(key, false, false)
+			if err != nil {
+				return nil, err
+			}
+			if current.Node.Value != prevValue {
+				return nil, &etcd.EtcdError{ErrorCode: tools.EtcdErrorCodeTestFailed, Message: "Compare failed", Cause: fmt.Sprintf("[%s != %s]", prevValue, current.Node.Value), Index: current.EtcdIndex}
+			}
+			prevIndex = current.Node.ModifiedIndex
+		}
+		prevValue = ""
+	}
+
+	value, err := c.encrypt(value)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.EtcdGetSet.CompareAndSwap(key, value, ttl, prevValue, prevIndex)
+	if err != nil {
+		return resp, err
+	}
+	return c.response(resp)
+}
+
+func (c *encryptingEtcdClient) Delete(key string, recursive bool) (*etcd.Response, error) {
+	resp, err := c.EtcdGetSet.Delete(key, recursive)
+	if err != nil {
+		return resp, err
+	}
+	return c.response(resp)
+}
+
+// Watch decrypts every response sent to receiver.  Values that cannot be decrypted are logged and sent
+// without their value, so a watcher fails to decode them rather than seeing ciphertext.
+func (c *encryptingEtcdClient) Watch(prefix string, waitIndex uint64, recursive bool, receiver chan *etcd.Response, stop chan bool) (*etcd.Response, error) {
+	resp, err := translateWatch(receiver, c.watchResponse, func(receiver chan *etcd.Response) (*etcd.Response, error) {
+		return c.EtcdGetSet.Watch(prefix, waitIndex, recursive, receiver, stop)
+	})
+	if err != nil {
+		return resp, err
+	}
+	return c.response(resp)
+}
+
+func (c *encryptingEtcdClient) watchResponse(resp *etcd.Response) *etcd.Response {
+	decrypted, err := c.response(resp)
+	if err != nil {
+		glog.Errorf("Unable to decrypt watched value: %v", err)
+		decrypted = &etcd.Response{Action: resp.Action, EtcdIndex: resp.EtcdIndex, RaftIndex: resp.RaftIndex, RaftTerm: resp.RaftTerm}
+		if resp.Node != nil {
+			decrypted.Node = &etcd.Node{Key: resp.Node.Key, ModifiedIndex: resp.Node.ModifiedIndex, CreatedIndex: resp.Node.CreatedIndex}
+		}
+	}
+	return decrypted
+}
+
+// newAEAD returns AES-GCM with key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal appends a random nonce and plaintext sealed with aead to dst.
+func seal(aead cipher.AEAD, dst, plaintext []byte) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		panic(err)
+	}
+	dst = append(dst, nonce...)
+	return aead.Seal(dst, nonce, plaintext, nil)
+}
+
+// open opens the nonce and sealed plaintext of size bytes at the start of data, and returns the plaintext and
+// the rest of data.
+func open(aead cipher.AEAD, data []byte, size int) ([]byte, []byte, error) {
+	n := aead.NonceSize() + size + aead.Overhead()
+	if size < 0 || len(data) < n {
+		return nil, nil, errors.New("malformed encrypted value")
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():n], nil)
+	if err != nil {
+		return nil, nil, errors.New("unable to decrypt value, it may have been modified")
+	}
+	return plaintext, data[n:], nil
+}
@@ -0,0 +1,153 @@
+package origin
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/coreos/go-etcd/etcd"
+)
+
+func testEncryptionKey(name string, b byte) EncryptionKey {
+	return EncryptionKey{Name: name, Secret: bytes.Repeat([]byte{b}, EncryptionKeySize)}
+}
+
+func newTestEncryptingClient(t *testing.T, client tools.EtcdGetSet, keys ...EncryptionKey) tools.EtcdGetSet {
+	encrypting, err := NewEncryptingEtcdClient(client, keys, []string{"/secrets"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return encrypting
+}
+
+func TestEncryptingEtcdClient(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	client := newTestEncryptingClient(t, fakeClient, testEncryptionKey("first", 1))
+
+	if _, err := client.Set("/secrets/foo", "token", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored := fakeClient.Data["/secrets/foo"].R.Node.Value
+	if !strings.HasPrefix(stored, encryptedValuePrefix+"first:") || strings.Contains(stored, "token") {
+		t.Errorf("expected the value to be encrypted with the first key, got %q", stored)
+	}
+
+	resp, err := client.Get("/secrets/foo", false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Node.Value != "token" {
+		t.Errorf("expected the value to be decrypted, got %q", resp.Node.Value)
+	}
+
+	if _, err := client.Set("/builds/foo", "build", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored := fakeClient.Data["/builds/foo"].R.Node.Value; stored != "build" {
+		t.Errorf("expected values outside the sensitive paths to be stored unchanged, got %q", stored)
+	}
+}
+
+func TestEncryptingEtcdClientReadsPlaintext(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.Data["/secrets/foo"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{Node: &etcd.Node{Key: "/secrets/foo", Value: "token"}},
+	}
+	client := newTestEncryptingClient(t, fakeClient, testEncryptionKey("first", 1))
+
+	resp, err := client.Get("/secrets/foo", false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Node.Value != "token" {
+		t.Errorf("expected values stored before encryption to be read unchanged, got %q", resp.Node.Value)
+	}
+}
+
+func TestEncryptingEtcdClientKeyRotation(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	old := newTestEncryptingClient(t, fakeClient, testEncryptionKey("old", 1))
+	if _, err := old.Set("/secrets/foo", "token", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rotated := newTestEncryptingClient(t, fakeClient, testEncryptionKey("new", 2), testEncryptionKey("old", 1))
+	resp, err := rotated.Get("/secrets/foo", false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Node.Value != "token" {
+		t.Errorf("expected a value encrypted with an old key to be read, got %q", resp.Node.Value)
+	}
+	if _, err := rotated.Set("/secrets/foo", "token", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored := fakeClient.Data["/secrets/foo"].R.Node.Value; !strings.HasPrefix(stored, encryptedValuePrefix+"new:") {
+		t.Errorf("expected the value to be encrypted with the new key, got %q", stored)
+	}
+
+	removed := newTestEncryptingClient(t, fakeClient, testEncryptionKey("old", 1))
+	if _, err := removed.Get("/secrets/foo", false, false); err == nil {
+		t.Errorf("expected an error reading a value encrypted with an unknown key")
+	}
+	wrong := newTestEncryptingClient(t, fakeClient, testEncryptionKey("new", 3))
+	if _, err := wrong.Get("/secrets/foo", false, false); err == nil {
+		t.Errorf("expected an error reading a value with the wrong key")
+	}
+}
+
+func TestEncryptingEtcdClientCompareAndSwap(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	client := newTestEncryptingClient(t, fakeClient, testEncryptionKey("first", 1))
+
+	if _, err := client.Create("/secrets/foo", "token", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := client.Get("/secrets/foo", false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the etcd helper compares against the plaintext it read
+	if _, err := client.CompareAndSwap("/secrets/foo", "updated", 0, "token", resp.Node.ModifiedIndex); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.CompareAndSwap("/secrets/foo", "stale", 0, "token", resp.Node.ModifiedIndex); !tools.IsEtcdTestFailed(err) {
+		t.Errorf("expected a stale index to fail, got %v", err)
+	}
+	if _, err := client.CompareAndSwap("/secrets/foo", "stale", 0, "token", 0); !tools.IsEtcdTestFailed(err) {
+		t.Errorf("expected a stale value to fail, got %v", err)
+	}
+	if _, err := client.CompareAndSwap("/secrets/foo", "final", 0, "updated", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err = client.Get("/secrets/foo", false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Node.Value != "final" {
+		t.Errorf("expected the swapped value, got %q", resp.Node.Value)
+	}
+}
+
+func TestNewEncryptingEtcdClient(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	if client, err := NewEncryptingEtcdClient(fakeClient, nil, SensitiveEtcdPaths); err != nil || client != fakeClient {
+		t.Errorf("expected the client to be returned unchanged without keys, got %v", err)
+	}
+
+	invalid := map[string][]EncryptionKey{
+		"short key":     {{Name: "a", Secret: []byte("secret")}},
+		"no name":       {testEncryptionKey("", 1)},
+		"name with ':'": {testEncryptionKey("a:b", 1)},
+		"duplicate":     {testEncryptionKey("a", 1), testEncryptionKey("a", 2)},
+	}
+	for name, keys := range invalid {
+		if _, err := NewEncryptingEtcdClient(fakeClient, keys, SensitiveEtcdPaths); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
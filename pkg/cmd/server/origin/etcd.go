@@ -0,0 +1,112 @@
+package origin
+
+import (
+	"path"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/coreos/go-etcd/etcd"
+)
+
+// NewPrefixedEtcdClient returns an etcd client that stores every key made through client under prefix, so
+// several masters, or OpenShift and Kubernetes, can share an etcd cluster.  Keys in responses have prefix
+// removed, so callers see the same keys they would without a prefix.  client is returned if prefix is empty.
+func NewPrefixedEtcdClient(client tools.EtcdGetSet, prefix string) tools.EtcdGetSet {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if len(prefix) == 0 {
+		return client
+	}
+	return &prefixedEtcdClient{client, prefix}
+}
+
+// prefixedEtcdClient adds prefix to the keys of the operations made through an etcd client.
+type prefixedEtcdClient struct {
+	tools.EtcdGetSet
+	prefix string
+}
+
+// key returns the etcd key for a key without the prefix.
+func (c *prefixedEtcdClient) key(key string) string {
+	return path.Join(c.prefix, key)
+}
+
+// response removes the prefix from the keys in resp.
+func (c *prefixedEtcdClient) response(resp *etcd.Response) *etcd.Response {
+	if resp != nil {
+		c.node(resp.Node)
+		c.node(resp.PrevNode)
+	}
+	return resp
+}
+
+// node removes the prefix from the keys of node and its children.
+func (c *prefixedEtcdClient) node(node *etcd.Node) {
+	if node == nil {
+		return
+	}
+	if strings.HasPrefix(node.Key, c.prefix) {
+		node.Key = strings.TrimPrefix(node.Key, c.prefix)
+		if len(node.Key) == 0 {
+			node.Key = "/"
+		}
+	}
+	for _, child := range node.Nodes {
+		c.node(child)
+	}
+}
+
+func (c *prefixedEtcdClient) Get(key string, sort, recursive bool) (*etcd.Response, error) {
+	resp, err := c.EtcdGetSet.Get(c.key(key), sort, recursive)
+	return c.response(resp), err
+}
+
+func (c *prefixedEtcdClient) Set(key, value string, ttl uint64) (*etcd.Response, error) {
+	resp, err := c.EtcdGetSet.Set(c.key(key), value, ttl)
+	return c.response(resp), err
+}
+
+func (c *prefixedEtcdClient) Create(key, value string, ttl uint64) (*etcd.Response, error) {
+	resp, err := c.EtcdGetSet.Create(c.key(key), value, ttl)
+	return c.response(resp), err
+}
+
+func (c *prefixedEtcdClient) CompareAndSwap(key, value string, ttl uint64, prevValue string, prevIndex uint64) (*etcd.Response, error) {
+	resp, err := c.EtcdGetSet.CompareAndSwap(c.key(key), value, ttl, prevValue, prevIndex)
+	return c.response(resp), err
+}
+
+func (c *prefixedEtcdClient) Delete(key string, recursive bool) (*etcd.Response, error) {
+	resp, err := c.EtcdGetSet.Delete(c.key(key), recursive)
+	return c.response(resp), err
+}
+
+// Watch removes the prefix from every response sent to receiver.  As with the etcd client, receiver is
+// closed before Watch returns.
+func (c *prefixedEtcdClient) Watch(prefix string, waitIndex uint64, recursive bool, receiver chan *etcd.Response, stop chan bool) (*etcd.Response, error) {
+	resp, err := translateWatch(receiver, c.response, func(receiver chan *etcd.Response) (*etcd.Response, error) {
+		return c.EtcdGetSet.Watch(c.key(prefix), waitIndex, recursive, receiver, stop)
+	})
+	return c.response(resp), err
+}
+
+// translateWatch calls watch, passing every response it sends through translate before sending it on to
+// receiver.  receiver is closed before translateWatch returns, as the etcd client does.  If receiver is nil
+// watch returns a single response, which the caller translates.
+func translateWatch(receiver chan *etcd.Response, translate func(*etcd.Response) *etcd.Response, watch func(chan *etcd.Response) (*etcd.Response, error)) (*etcd.Response, error) {
+	if receiver == nil {
+		return watch(nil)
+	}
+
+	incoming := make(chan *etcd.Response)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer close(receiver)
+		for resp := range incoming {
+			receiver <- translate(resp)
+		}
+	}()
+	resp, err := watch(incoming)
+	<-done
+	return resp, err
+}
@@ -0,0 +1,77 @@
+package origin
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/coreos/go-etcd/etcd"
+)
+
+func TestPrefixedEtcdClient(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	client := NewPrefixedEtcdClient(fakeClient, "/openshift.io/")
+
+	if _, err := client.Set("/builds/foo", "value", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := fakeClient.Data["/openshift.io/builds/foo"]; !ok {
+		t.Errorf("expected the key to be stored under the prefix, got %#v", fakeClient.Data)
+	}
+
+	fakeClient.Data["/openshift.io/builds"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Key: "/openshift.io/builds",
+				Dir: true,
+				Nodes: etcd.Nodes{
+					{Key: "/openshift.io/builds/foo", Value: "value"},
+				},
+			},
+		},
+	}
+	resp, err := client.Get("/builds", false, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Node.Key != "/builds" || resp.Node.Nodes[0].Key != "/builds/foo" {
+		t.Errorf("expected the prefix to be removed from the response, got %#v", resp.Node)
+	}
+}
+
+func TestPrefixedEtcdClientEmptyPrefix(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	if client := NewPrefixedEtcdClient(fakeClient, ""); client != fakeClient {
+		t.Errorf("expected the client to be returned unchanged")
+	}
+}
+
+func TestPrefixedEtcdClientWatch(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	client := NewPrefixedEtcdClient(fakeClient, "/openshift.io")
+
+	receiver := make(chan *etcd.Response)
+	stop := make(chan bool)
+	returned := make(chan struct{})
+	go func() {
+		defer close(returned)
+		client.Watch("/builds", 1, true, receiver, stop)
+	}()
+
+	fakeClient.WaitForWatchCompletion()
+	go func() {
+		fakeClient.WatchResponse <- &etcd.Response{
+			Action: "set",
+			Node:   &etcd.Node{Key: "/openshift.io/builds/foo", Value: "value"},
+		}
+	}()
+	resp := <-receiver
+	if resp.Node.Key != "/builds/foo" {
+		t.Errorf("expected the prefix to be removed from the watch response, got %s", resp.Node.Key)
+	}
+
+	close(stop)
+	<-returned
+	if _, open := <-receiver; open {
+		t.Errorf("expected the receiver to be closed when the watch returns")
+	}
+}
@@ -0,0 +1,103 @@
+package origin
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/emicklei/go-restful"
+)
+
+const (
+	// healthzPath reports whether the master API server is serving
+	healthzPath = "/healthz"
+	// readyzPath reports whether the master is ready to handle requests
+	readyzPath = "/healthz/ready"
+)
+
+// healthCheck is a named condition the master must satisfy to be ready.
+type healthCheck struct {
+	name  string
+	check func() error
+}
+
+// bootstrapStatus records whether the master finished creating the policy and OAuth resources it
+// depends on.
+type bootstrapStatus struct {
+	lock      sync.Mutex
+	completed bool
+}
+
+// complete marks the bootstrap as completed.
+func (s *bootstrapStatus) complete() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.completed = true
+}
+
+// check returns an error until the bootstrap has completed.
+func (s *bootstrapStatus) check() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.completed {
+		return fmt.Errorf("the policy and OAuth bootstrap has not completed")
+	}
+	return nil
+}
+
+// installHealthz registers the liveness check at /healthz and the readiness check at /healthz/ready.
+// Both are served without authentication so load balancers and process supervisors can use them.
+func (c *MasterConfig) installHealthz(container *restful.Container) []string {
+	serving := healthCheck{"server", c.checkServing}
+	container.Handle(healthzPath, healthzHandler(serving))
+	container.Handle(readyzPath, healthzHandler(
+		serving,
+		healthCheck{"etcd", c.checkEtcd},
+		healthCheck{"bootstrap", c.bootstrap.check},
+	))
+	return []string{
+		fmt.Sprintf("Started health checks at %%s%s", healthzPath),
+	}
+}
+
+// checkServing returns an error unless the master API server is accepting connections.
+func (c *MasterConfig) checkServing() error {
+	if c.server == nil || !c.server.Serving() {
+		return fmt.Errorf("the master API server is not serving")
+	}
+	return nil
+}
+
+// checkEtcd returns an error if etcd cannot be reached.
+func (c *MasterConfig) checkEtcd() error {
+	if c.EtcdHelper.Client == nil {
+		return fmt.Errorf("no etcd client is configured")
+	}
+	if _, err := c.EtcdHelper.Client.Get("/", false, false); err != nil && !tools.IsEtcdNotFound(err) {
+		return fmt.Errorf("unable to reach etcd: %v", err)
+	}
+	return nil
+}
+
+// healthzHandler responds with 200 and "ok" when every check passes, and with 503 and the failed checks
+// otherwise.
+func healthzHandler(checks ...healthCheck) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		failures := []string{}
+		for _, check := range checks {
+			if err := check.check(); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", check.name, err))
+			}
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if len(failures) > 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, strings.Join(failures, "\n"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+}
@@ -0,0 +1,71 @@
+package origin
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/coreos/go-etcd/etcd"
+	"github.com/emicklei/go-restful"
+)
+
+func TestHealthz(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.ExpectNotFoundGet("/")
+	config := &MasterConfig{EtcdHelper: tools.EtcdHelper{Client: fakeClient}}
+	container := restful.NewContainer()
+	config.installHealthz(container)
+
+	get := func(path string) (int, string) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		container.ServeHTTP(w, req)
+		return w.Code, w.Body.String()
+	}
+
+	// the server has not started
+	if code, body := get(healthzPath); code != http.StatusServiceUnavailable || !strings.Contains(body, "server:") {
+		t.Errorf("Expected the liveness check to fail before the server starts, got %d %q", code, body)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	config.server = newGracefulServer(&http.Server{Handler: container})
+	go config.server.Serve(listener)
+	defer config.server.Stop(0)
+	for !config.server.Serving() {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if code, body := get(healthzPath); code != http.StatusOK || body != "ok" {
+		t.Errorf("Expected the liveness check to pass, got %d %q", code, body)
+	}
+	if code, body := get(readyzPath); code != http.StatusServiceUnavailable || !strings.Contains(body, "bootstrap:") || strings.Contains(body, "etcd:") {
+		t.Errorf("Expected the readiness check to fail until the bootstrap completes, got %d %q", code, body)
+	}
+
+	config.bootstrap.complete()
+	if code, body := get(readyzPath); code != http.StatusOK || body != "ok" {
+		t.Errorf("Expected the readiness check to pass, got %d %q", code, body)
+	}
+
+	fakeClient.Data["/"] = tools.EtcdResponseWithError{R: &etcd.Response{}, E: errors.New("connection refused")}
+	if code, body := get(readyzPath); code != http.StatusServiceUnavailable || !strings.Contains(body, "etcd: unable to reach etcd: connection refused") {
+		t.Errorf("Expected the readiness check to fail when etcd is unreachable, got %d %q", code, body)
+	}
+	if code, _ := get(healthzPath); code != http.StatusOK {
+		t.Errorf("Expected the liveness check to ignore etcd, got %d", code)
+	}
+
+	config.server.Stop(0)
+	if code, _ := get(healthzPath); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected the liveness check to fail once the server stops, got %d", code)
+	}
+}
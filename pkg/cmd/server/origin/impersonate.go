@@ -0,0 +1,100 @@
+package origin
+
+import (
+	"net/http"
+
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/golang/glog"
+
+	authenticationapi "github.com/openshift/origin/pkg/auth/api"
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+	"github.com/openshift/origin/pkg/authorization/authorizer"
+	userapi "github.com/openshift/origin/pkg/user/api"
+)
+
+// ImpersonateUserHeader names the user a request is made as.  The authenticated user must be granted the
+// impersonate verb on users in the master policy.
+const ImpersonateUserHeader = "Impersonate-User"
+
+// userGetter looks up the users that are impersonated.
+type userGetter interface {
+	GetUser(name string) (*userapi.User, error)
+}
+
+// impersonationFilter serves requests that name a user in the Impersonate-User header as that user, if the
+// authenticated user may impersonate others.  The authenticated user is kept in the request context as the
+// impersonator, and the scopes and OAuth client of their token still apply.  The impersonated user must exist
+// and not be disabled, and is given its UID from the user registry and the groups of an authenticated user.
+// The filter must run after the request is authenticated and before it is authorized.
+func impersonationFilter(handler http.Handler, authz authorizer.Authorizer, users userGetter, contexts *authcontext.RequestContextMapper) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		name := req.Header.Get(ImpersonateUserHeader)
+		if len(name) == 0 {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		ctx, ok := contexts.Get(req)
+		if !ok {
+			http.Error(w, "The request has no context", http.StatusInternalServerError)
+			return
+		}
+		user, ok := authcontext.UserFrom(ctx)
+		if !ok {
+			forbidden("Only authenticated users may impersonate others", w, req)
+			return
+		}
+		allowed, reason, err := authz.Authorize(authorizer.NewImpersonationAttributes(user))
+		if err != nil {
+			forbidden(err.Error(), w, req)
+			return
+		}
+		if !allowed {
+			forbidden(reason, w, req)
+			return
+		}
+
+		impersonated, err := users.GetUser(name)
+		if kerrors.IsNotFound(err) {
+			forbidden("The impersonated user does not exist", w, req)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if impersonated.Disabled {
+			forbidden("The impersonated user is disabled", w, req)
+			return
+		}
+
+		glog.V(2).Infof("%s is impersonating %s for %s %s", user.GetName(), name, req.Method, req.RequestURI)
+		info := &authenticationapi.DefaultUserInfo{
+			Name:   impersonated.Name,
+			UID:    string(impersonated.UID),
+			Groups: []string{"system:authenticated"},
+		}
+		ctx = authcontext.WithImpersonator(authcontext.WithUser(ctx, info), user)
+		if err := contexts.Update(req, ctx); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// impersonatingRESTClient returns a copy of client that makes its requests as user, in the Impersonate-User
+// header.  The user client authenticates as must be allowed to impersonate others.
+func impersonatingRESTClient(client *kclient.RESTClient, user string) *kclient.RESTClient {
+	impersonating := *client
+	httpClient := client.Client
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	impersonating.Client = kclient.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+		req.Header.Set(ImpersonateUserHeader, user)
+		return httpClient.Do(req)
+	})
+	return &impersonating
+}
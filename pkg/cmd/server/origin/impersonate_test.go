@@ -0,0 +1,101 @@
+package origin
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+	"github.com/openshift/origin/pkg/authorization/authorizer"
+	userapi "github.com/openshift/origin/pkg/user/api"
+)
+
+// impersonatedUsers returns the users it holds by name.
+type impersonatedUsers map[string]*userapi.User
+
+func (u impersonatedUsers) GetUser(name string) (*userapi.User, error) {
+	if user, ok := u[name]; ok {
+		return user, nil
+	}
+	return nil, kerrors.NewNotFound("user", name)
+}
+
+// impersonateAuthorizer allows only the users it lists to impersonate others.
+type impersonateAuthorizer map[string]bool
+
+func (a impersonateAuthorizer) Authorize(attributes authorizer.AuthorizationAttributes) (bool, string, error) {
+	if attributes.GetVerb() != authorizer.ImpersonateVerb || attributes.GetNamespace() != "" {
+		return false, "unexpected attributes", nil
+	}
+	if a[attributes.GetUserInfo().GetName()] {
+		return true, "allowed", nil
+	}
+	return false, "denied", nil
+}
+
+func TestImpersonationFilter(t *testing.T) {
+	out := &bytes.Buffer{}
+	contexts := authcontext.NewRequestContextMapper()
+	served, uid, groups := "", "", []string{}
+	users := impersonatedUsers{
+		"alice": {ObjectMeta: kapi.ObjectMeta{Name: "alice", UID: "alice-uid"}},
+		"bob":   {ObjectMeta: kapi.ObjectMeta{Name: "bob", UID: "bob-uid"}},
+		"carol": {ObjectMeta: kapi.ObjectMeta{Name: "carol", UID: "carol-uid"}, Disabled: true},
+	}
+	handler := impersonationFilter(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		served = requestUserName(req, contexts)
+		ctx, _ := contexts.Get(req)
+		user, _ := authcontext.UserFrom(ctx)
+		uid, groups = user.GetUID(), user.GetGroups()
+		w.WriteHeader(http.StatusCreated)
+	}), impersonateAuthorizer{"alice": true}, users, contexts)
+	handler = auditFilter(handler, out, contexts)
+
+	serve := func(user, impersonate string) *httptest.ResponseRecorder {
+		served = ""
+		out.Reset()
+		req, _ := http.NewRequest("POST", "/osapi/v1beta1/builds?namespace=test", nil)
+		if len(impersonate) > 0 {
+			req.Header.Set(ImpersonateUserHeader, impersonate)
+		}
+		w := httptest.NewRecorder()
+		withUser(contexts, user, handler).ServeHTTP(w, req)
+		return w
+	}
+
+	if w := serve("alice", ""); w.Code != http.StatusCreated || served != "alice" {
+		t.Errorf("Expected requests without the header to be served as the user, got %d as %q", w.Code, served)
+	}
+	if !strings.Contains(out.String(), `user="alice"`) || !strings.Contains(out.String(), `impersonator=""`) {
+		t.Errorf("Unexpected audit record: %q", out.String())
+	}
+
+	if w := serve("alice", "bob"); w.Code != http.StatusCreated || served != "bob" {
+		t.Errorf("Expected the request to be served as the impersonated user, got %d as %q", w.Code, served)
+	}
+	if !strings.Contains(out.String(), `user="bob"`) || !strings.Contains(out.String(), `impersonator="alice"`) {
+		t.Errorf("Expected the request to be audited as the impersonated user, got %q", out.String())
+	}
+	if uid != "bob-uid" || len(groups) != 1 || groups[0] != "system:authenticated" {
+		t.Errorf("Expected the impersonated user to have its UID and the authenticated group, got %q %v", uid, groups)
+	}
+
+	if w := serve("alice", "dave"); w.Code != http.StatusForbidden || len(served) != 0 {
+		t.Errorf("Expected impersonating an unknown user to be forbidden, got %d as %q", w.Code, served)
+	}
+	if w := serve("alice", "carol"); w.Code != http.StatusForbidden || len(served) != 0 {
+		t.Errorf("Expected impersonating a disabled user to be forbidden, got %d as %q", w.Code, served)
+	}
+
+	if w := serve("bob", "alice"); w.Code != http.StatusForbidden || len(served) != 0 {
+		t.Errorf("Expected users that may not impersonate to be forbidden, got %d as %q", w.Code, served)
+	}
+	if !strings.Contains(out.String(), `user="bob"`) || !strings.Contains(out.String(), `code=403`) {
+		t.Errorf("Expected the forbidden impersonation to be audited as the authenticated user, got %q", out.String())
+	}
+}
@@ -0,0 +1,111 @@
+package origin
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+)
+
+// inFlightRetryAfterSeconds is how long clients are asked to wait when a request is rejected
+const inFlightRetryAfterSeconds = "1"
+
+// inFlightLimiter counts the requests being served, in total and by user.
+type inFlightLimiter struct {
+	// max is the number of requests served at once, unlimited if zero
+	max int
+	// maxPerUser is the number of requests served at once for a single user, unlimited if zero
+	maxPerUser int
+
+	lock    sync.Mutex
+	total   int
+	perUser map[string]int
+}
+
+// acquire records a request from user and returns true, or returns false if serving it would exceed a limit.
+func (l *inFlightLimiter) acquire(user string) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.max > 0 && l.total >= l.max {
+		return false
+	}
+	if l.maxPerUser > 0 && l.perUser[user] >= l.maxPerUser {
+		return false
+	}
+	l.total++
+	l.perUser[user]++
+	return true
+}
+
+// release records that a request from user completed.
+func (l *inFlightLimiter) release(user string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.total--
+	if l.perUser[user]--; l.perUser[user] <= 0 {
+		delete(l.perUser, user)
+	}
+}
+
+// inFlightLimitFilter rejects requests with 429 once max requests, or maxPerUser requests for the requesting
+// user, are being served by handler.  Watches and proxied requests are long running and are not limited.  The
+// user is read from the request context, so the filter must run after the request is authenticated.
+func inFlightLimitFilter(handler http.Handler, max, maxPerUser int, contexts *authcontext.RequestContextMapper) http.Handler {
+	if max <= 0 && maxPerUser <= 0 {
+		return handler
+	}
+	limiter := &inFlightLimiter{max: max, maxPerUser: maxPerUser, perUser: make(map[string]int)}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if longRunningRequest(req) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		user := requestUserName(req, contexts)
+		if !limiter.acquire(user) {
+			glog.V(4).Infof("Too many requests in flight, rejecting %s %s from %q", req.Method, req.RequestURI, user)
+			w.Header().Set("Retry-After", inFlightRetryAfterSeconds)
+			http.Error(w, "Too many requests, please try again later.", 429)
+			return
+		}
+		defer limiter.release(user)
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// deadlineFilter responds with 503 to requests that handler has not served by the deadline of their context.
+// Long running requests are held open past any deadline and are not limited.
+func deadlineFilter(handler http.Handler, contexts *authcontext.RequestContextMapper) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, ok := contexts.Get(req)
+		if !ok || longRunningRequest(req) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		deadline, ok := authcontext.DeadlineFrom(ctx)
+		if !ok {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		http.TimeoutHandler(handler, deadline.Sub(time.Now()), "The request was not served before its deadline.").ServeHTTP(w, req)
+	})
+}
+
+// longRunningRequest returns true for requests that are held open, such as watches and proxied connections.
+func longRunningRequest(req *http.Request) bool {
+	if req.URL.Path == watchMuxPath {
+		return true
+	}
+	if req.URL.Query().Get("watch") == "true" {
+		return true
+	}
+	verb, _, _, _ := requestAttributes(req)
+	switch verb {
+	case "watch", "proxy", "redirect":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package origin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+)
+
+func TestDeadlineFilter(t *testing.T) {
+	contexts := authcontext.NewRequestContextMapper()
+	release := make(chan struct{})
+	handler := authcontext.NewRequestContextFilter(contexts, time.Minute, deadlineFilter(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("slow") == "true" {
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	}), contexts))
+	serve := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := serve("/osapi/v1beta1/builds?timeout=10ms"); w.Code != http.StatusOK {
+		t.Errorf("Expected requests served before the deadline to succeed, got %d", w.Code)
+	}
+	if w := serve("/osapi/v1beta1/builds?timeout=10ms&slow=true"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected requests not served by the deadline to be rejected, got %d", w.Code)
+	}
+
+	// watches are not limited
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- serve("/osapi/v1beta1/watch/builds?timeout=10ms&slow=true") }()
+	select {
+	case w := <-done:
+		t.Errorf("Expected the watch to be held open past its deadline, got %d", w.Code)
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	if w := <-done; w.Code != http.StatusOK {
+		t.Errorf("Expected the watch to be served, got %d", w.Code)
+	}
+}
+
+func TestInFlightLimitFilter(t *testing.T) {
+	contexts := authcontext.NewRequestContextMapper()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := inFlightLimitFilter(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		started <- struct{}{}
+		<-release
+	}), 3, 2, contexts)
+
+	serve := func(user, path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		withUser(contexts, user, handler).ServeHTTP(w, req)
+		return w
+	}
+	// hold blocks a request from user in the handler until release is closed
+	wg := sync.WaitGroup{}
+	hold := func(user, path string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if w := serve(user, path); w.Code != http.StatusOK {
+				t.Errorf("Expected a held request from %s to succeed, got %d", user, w.Code)
+			}
+		}()
+		<-started
+	}
+
+	hold("alice", "/osapi/v1beta1/builds")
+	hold("alice", "/osapi/v1beta1/builds")
+	if w := serve("alice", "/osapi/v1beta1/builds"); w.Code != 429 || w.Header().Get("Retry-After") != "1" {
+		t.Errorf("Expected the per user limit to reject the request with a Retry-After, got %d %v", w.Code, w.Header())
+	}
+
+	hold("bob", "/osapi/v1beta1/builds")
+	if w := serve("carol", "/osapi/v1beta1/builds"); w.Code != 429 {
+		t.Errorf("Expected the global limit to reject the request, got %d", w.Code)
+	}
+
+	// watches are not limited
+	hold("alice", "/osapi/v1beta1/watch/builds")
+	hold("alice", "/osapi/v1beta1/builds?watch=true")
+
+	close(release)
+	wg.Wait()
+
+	started = make(chan struct{}, 1)
+	if w := serve("alice", "/osapi/v1beta1/builds"); w.Code != http.StatusOK {
+		t.Errorf("Expected requests to be served once others complete, got %d", w.Code)
+	}
+}
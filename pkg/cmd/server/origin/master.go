@@ -3,9 +3,12 @@ package origin
 import (
 	"crypto/tls"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"regexp"
 	"strings"
 	"time"
@@ -13,26 +16,34 @@ import (
 	etcdclient "github.com/coreos/go-etcd/etcd"
 	"github.com/elazarl/go-bindata-assetfs"
 	restful "github.com/emicklei/go-restful"
-	"github.com/emicklei/go-restful/swagger"
 	"github.com/golang/glog"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/resource"
+	klabels "github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	kmaster "github.com/GoogleCloudPlatform/kubernetes/pkg/master"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
-	"github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/admission/admit"
 
+	osapi "github.com/openshift/origin/pkg/api"
 	"github.com/openshift/origin/pkg/api/latest"
+	"github.com/openshift/origin/pkg/api/swagger"
 	"github.com/openshift/origin/pkg/api/v1beta1"
+	"github.com/openshift/origin/pkg/api/v1beta2"
 	"github.com/openshift/origin/pkg/assets"
+	authenticationapi "github.com/openshift/origin/pkg/auth/api"
 	"github.com/openshift/origin/pkg/auth/authenticator"
 	authcontext "github.com/openshift/origin/pkg/auth/context"
 	"github.com/openshift/origin/pkg/authorization/authorizer"
 	buildclient "github.com/openshift/origin/pkg/build/client"
+	buildcontroller "github.com/openshift/origin/pkg/build/controller"
 	buildcontrollerfactory "github.com/openshift/origin/pkg/build/controller/factory"
 	buildstrategy "github.com/openshift/origin/pkg/build/controller/strategy"
 	buildregistry "github.com/openshift/origin/pkg/build/registry/build"
@@ -43,40 +54,68 @@ import (
 	"github.com/openshift/origin/pkg/build/webhook/generic"
 	"github.com/openshift/origin/pkg/build/webhook/github"
 	osclient "github.com/openshift/origin/pkg/client"
+	oscache "github.com/openshift/origin/pkg/client/cache"
+	eventrecord "github.com/openshift/origin/pkg/client/record"
+	"github.com/openshift/origin/pkg/cmd/server/crypto"
+	"github.com/openshift/origin/pkg/cmd/server/kubernetes"
 	cmdutil "github.com/openshift/origin/pkg/cmd/util"
 	"github.com/openshift/origin/pkg/cmd/util/clientcmd"
+	osconfig "github.com/openshift/origin/pkg/config"
+	deploycontroller "github.com/openshift/origin/pkg/deploy/controller"
 	deploycontrollerfactory "github.com/openshift/origin/pkg/deploy/controller/factory"
 	deployconfiggenerator "github.com/openshift/origin/pkg/deploy/generator"
 	deployregistry "github.com/openshift/origin/pkg/deploy/registry/deploy"
 	deployconfigregistry "github.com/openshift/origin/pkg/deploy/registry/deployconfig"
+	deploylogregistry "github.com/openshift/origin/pkg/deploy/registry/deploylog"
 	deployetcd "github.com/openshift/origin/pkg/deploy/registry/etcd"
 	deployrollback "github.com/openshift/origin/pkg/deploy/rollback"
+	"github.com/openshift/origin/pkg/gc"
 	imageetcd "github.com/openshift/origin/pkg/image/registry/etcd"
 	"github.com/openshift/origin/pkg/image/registry/image"
 	"github.com/openshift/origin/pkg/image/registry/imagerepository"
 	"github.com/openshift/origin/pkg/image/registry/imagerepositorymapping"
+	"github.com/openshift/origin/pkg/image/registry/imagerepositoryrestore"
 	"github.com/openshift/origin/pkg/image/registry/imagerepositorytag"
+	"github.com/openshift/origin/pkg/image/registry/imagesearch"
+	limitrangeadmission "github.com/openshift/origin/pkg/limitrange/admission"
 	accesstokenregistry "github.com/openshift/origin/pkg/oauth/registry/accesstoken"
 	authorizetokenregistry "github.com/openshift/origin/pkg/oauth/registry/authorizetoken"
+	bootstraptokenregistry "github.com/openshift/origin/pkg/oauth/registry/bootstraptoken"
 	clientregistry "github.com/openshift/origin/pkg/oauth/registry/client"
 	clientauthorizationregistry "github.com/openshift/origin/pkg/oauth/registry/clientauthorization"
 	oauthetcd "github.com/openshift/origin/pkg/oauth/registry/etcd"
+	routertokenregistry "github.com/openshift/origin/pkg/oauth/registry/routertoken"
+	projectadmission "github.com/openshift/origin/pkg/project/admission"
+	projectcontroller "github.com/openshift/origin/pkg/project/controller"
 	projectetcd "github.com/openshift/origin/pkg/project/registry/etcd"
 	projectregistry "github.com/openshift/origin/pkg/project/registry/project"
+	projectrequestregistry "github.com/openshift/origin/pkg/project/registry/projectrequest"
+	"github.com/openshift/origin/pkg/quota"
+	quotaadmission "github.com/openshift/origin/pkg/quota/admission"
+	quotacontroller "github.com/openshift/origin/pkg/quota/controller"
+	routecontroller "github.com/openshift/origin/pkg/route/controller"
 	routeetcd "github.com/openshift/origin/pkg/route/registry/etcd"
 	routeregistry "github.com/openshift/origin/pkg/route/registry/route"
+	routestatusregistry "github.com/openshift/origin/pkg/route/registry/routestatus"
 	"github.com/openshift/origin/pkg/service"
+	templateapi "github.com/openshift/origin/pkg/template/api"
 	templateregistry "github.com/openshift/origin/pkg/template/registry"
+	templateetcd "github.com/openshift/origin/pkg/template/registry/etcd"
+	templatestorage "github.com/openshift/origin/pkg/template/registry/template"
+	templateinstanceregistry "github.com/openshift/origin/pkg/template/registry/templateinstance"
 	"github.com/openshift/origin/pkg/user"
 	useretcd "github.com/openshift/origin/pkg/user/registry/etcd"
+	identityregistry "github.com/openshift/origin/pkg/user/registry/identity"
 	userregistry "github.com/openshift/origin/pkg/user/registry/user"
 	"github.com/openshift/origin/pkg/user/registry/useridentitymapping"
+	"github.com/openshift/origin/pkg/util/httpproxy"
 	"github.com/openshift/origin/pkg/version"
 
 	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
 	authorizationetcd "github.com/openshift/origin/pkg/authorization/registry/etcd"
 	policyregistry "github.com/openshift/origin/pkg/authorization/registry/policy"
 	policybindingregistry "github.com/openshift/origin/pkg/authorization/registry/policybinding"
+	policychangereviewregistry "github.com/openshift/origin/pkg/authorization/registry/policychangereview"
 	roleregistry "github.com/openshift/origin/pkg/authorization/registry/role"
 	rolebindingregistry "github.com/openshift/origin/pkg/authorization/registry/rolebinding"
 )
@@ -84,13 +123,22 @@ import (
 const (
 	OpenShiftAPIPrefix        = "/osapi"
 	OpenShiftAPIPrefixV1Beta1 = OpenShiftAPIPrefix + "/v1beta1"
+	OpenShiftAPIPrefixV1Beta2 = OpenShiftAPIPrefix + "/v1beta2"
 	swaggerAPIPrefix          = "/swaggerapi/"
+
+	// servingCertReloadPeriod is how often the master and asset servers check their certificate files for changes
+	servingCertReloadPeriod = time.Minute
+	// requestTimeout is how long the master reads and writes a request for, and the deadline of its context
+	requestTimeout = 5 * time.Minute
 )
 
 // MasterConfig defines the required parameters for starting the OpenShift master
 type MasterConfig struct {
-	// host:port to bind master to
+	// host:port to bind master to, served with TLS if TLS is true
 	MasterBindAddr string
+	// host:port to serve the master API on without TLS for local tooling, in addition to MasterBindAddr.  It
+	// must be a loopback address, and no insecure listener is started if empty
+	InsecureBindAddr string
 	// host:port to bind asset server to
 	AssetBindAddr string
 	// url to access the master API on within the cluster
@@ -102,8 +150,30 @@ type MasterConfig struct {
 	KubernetesPublicAddr string
 	AssetPublicAddr      string
 
+	// AssetExtensions brands and extends the web console served by the asset server
+	AssetExtensions assets.WebConsoleExtensions
+	// AssetDir holds files the asset server serves in place of the embedded assets, if set
+	AssetDir string
+	// AssetProxyAPI forwards the API requests of the console to the masters through the asset server
+	AssetProxyAPI bool
+	// AssetPathPrefix serves the OpenShift UI under this path on the master listener instead of AssetBindAddr, if set
+	AssetPathPrefix string
+	// AssetLogoutURL is where the web console sends users after logging out, if set
+	AssetLogoutURL string
+	// AccessTokenMaxAgeSeconds is how long the tokens the web console is granted last
+	AccessTokenMaxAgeSeconds int
+	// AssetSecurityHeaders are sent with the web console, a Content-Security-Policy is derived if it is empty
+	AssetSecurityHeaders assets.SecurityHeaders
+
 	CORSAllowedOrigins []string
-	Authenticator      authenticator.Request
+	// CORSAllowedMethods, CORSAllowedHeaders and CORSExposedHeaders replace the defaults of cross origin requests, if set
+	CORSAllowedMethods []string
+	CORSAllowedHeaders []string
+	CORSExposedHeaders []string
+	// CORSMaxAge is how long browsers may cache the answer to a preflight request
+	CORSMaxAge time.Duration
+
+	Authenticator authenticator.Request
 	// TODO Have MasterConfig take a fully formed Authorizer
 	MasterAuthorizationNamespace string
 
@@ -111,6 +181,55 @@ type MasterConfig struct {
 
 	AdmissionControl admission.Interface
 
+	// ProjectRequestTemplate is instantiated in every project created through a project request
+	ProjectRequestTemplate *templateapi.Template
+
+	// AllowRouteHostSharing allows routes in different namespaces to claim the same host and path
+	AllowRouteHostSharing bool
+	// ImageRepositoryDeletionGracePeriod is how long deleted image repositories can be restored, never if zero
+	ImageRepositoryDeletionGracePeriod time.Duration
+	// RouteCertificateExpiryWindow is how long before their certificate expires routes are reported
+	RouteCertificateExpiryWindow time.Duration
+	// ReconcileBootstrapPolicy merges the roles and role bindings of the bootstrap policy into the existing
+	// master policy instead of leaving it as it is
+	ReconcileBootstrapPolicy bool
+
+	// ShutdownGracePeriod is how long Stop waits for requests in flight to complete
+	ShutdownGracePeriod time.Duration
+
+	// Metrics records the requests served by the master API, a new Metrics is created by Run if nil
+	Metrics *Metrics
+	// Controllers runs the controllers of the master and reports their status, a new ControllerManager is
+	// created by Run if nil
+	Controllers *ControllerManager
+	// Informers holds the watches and stores of objects shared by the controllers of the master, new
+	// Informers are created by Run if nil
+	Informers *oscache.Informers
+	// SlowRequestThreshold is the latency past which requests are logged with the etcd operations made while
+	// they were served, slow requests are not logged if zero
+	SlowRequestThreshold time.Duration
+
+	// AuditLog receives a record of every mutating API request, auditing is disabled if nil
+	AuditLog io.Writer
+
+	// MaxRequestsInFlight is the number of protected requests served at once, unlimited if zero
+	MaxRequestsInFlight int
+	// MaxRequestsInFlightPerUser is the number of protected requests served at once for a single user, unlimited
+	// if zero
+	MaxRequestsInFlightPerUser int
+	// MaxRequestsPerSecondPerUser is the rate of protected requests a single user may make, unlimited if zero
+	MaxRequestsPerSecondPerUser float64
+	// RequestBurstPerUser is the number of protected requests a single user may make at once above their rate
+	RequestBurstPerUser int
+	// MaxRequestsPerSecondPerSystemUser is the rate of protected requests a single system user, such as a node
+	// or an infrastructure component, may make, unlimited if zero
+	MaxRequestsPerSecondPerSystemUser float64
+
+	// BuildSourceSecretsDir is the directory of the nodes that holds the source and push secrets of builds by namespace
+	BuildSourceSecretsDir string
+	// AllowedBuilderImages are the images Docker and STI builds may run instead of the builder images of the cluster
+	AllowedBuilderImages []string
+
 	// true if the system should use pullIfNotPresent for images (which means updates will not be fetched aggressively)
 	UseLocalImages bool
 
@@ -124,6 +243,10 @@ type MasterConfig struct {
 	AssetCertFile  string
 	AssetKeyFile   string
 
+	// NamedCertificates are served on the master listener instead of MasterCertFile to clients that request
+	// one of their names through SNI
+	NamedCertificates []NamedCertificate
+
 	// kubeClient is the client used to call Kubernetes APIs from system components, built from KubeClientConfig.
 	// It should only be accessed via the *Client() helper methods.
 	// To apply different access control to a system component, create a separate client/config specifically for that component.
@@ -143,8 +266,32 @@ type MasterConfig struct {
 	// DeployerOSClientConfig is the client configuration used to call OpenShift APIs from launched deployer pods
 	DeployerOSClientConfig kclient.Config
 
-	// requestsToUsers is a shared auth context map
-	requestsToUsers *authcontext.RequestContextMap
+	// requestUsage counts the protected requests of each user and OAuth client
+	requestUsage *requestUsage
+	// buildMetrics records the builds of the build controller, it outlives restarts of the controller so
+	// that it is added to Metrics once
+	buildMetrics *buildcontroller.BuildMetrics
+	// routeCertificateMetrics reports the routes the route certificate expiry controller finds, it outlives
+	// restarts of the controller so that it is added to Metrics once
+	routeCertificateMetrics *routecontroller.CertificateExpiryMetrics
+	// policyAuthorizer authorizes protected requests against the master and project policies
+	policyAuthorizer authorizer.Authorizer
+	// requestContextMapper holds the context of every authenticated request in flight
+	requestContextMapper *authcontext.RequestContextMapper
+	// server is the master API server started by Run
+	server *gracefulServer
+	// insecureServer is the master API server started by Run on InsecureBindAddr, if any
+	insecureServer *gracefulServer
+	// bootstrap is completed once Run has created the policy and OAuth resources the master depends on
+	bootstrap bootstrapStatus
+}
+
+// NamedCertificate is a certificate served to TLS clients that request one of its names.
+type NamedCertificate struct {
+	// Names are the server names the certificate is served for, which may be wildcards like *.example.com
+	Names    []string
+	CertFile string
+	KeyFile  string
 }
 
 // APIInstaller installs additional API components into this server
@@ -168,7 +315,12 @@ func (c *MasterConfig) BuildClients() {
 	}
 	c.kubeClient = kubeClient
 
-	osclient, err := osclient.New(&c.OSClientConfig)
+	// system components read objects in the compact encoding
+	osClientConfig := c.OSClientConfig
+	if err := osclient.UseCompactEncoding(&osClientConfig); err != nil {
+		glog.Fatalf("Unable to configure client: %v", err)
+	}
+	osclient, err := osclient.New(&osClientConfig)
 	if err != nil {
 		glog.Fatalf("Unable to configure client: %v", err)
 	}
@@ -222,9 +374,48 @@ func (c *MasterConfig) DeploymentConfigControllerClients() (*osclient.Client, *k
 func (c *MasterConfig) DeploymentConfigChangeControllerClients() (*osclient.Client, *kclient.Client) {
 	return c.osClient, c.kubeClient
 }
+func (c *MasterConfig) DeploymentAutoscaleControllerClients() (*osclient.Client, *kclient.Client) {
+	return c.osClient, c.kubeClient
+}
+func (c *MasterConfig) DeploymentDriftControllerClients() (*osclient.Client, *kclient.Client) {
+	return c.osClient, c.kubeClient
+}
 func (c *MasterConfig) DeploymentImageChangeControllerClient() *osclient.Client {
 	return c.osClient
 }
+func (c *MasterConfig) RouteCertificateExpiryControllerClient() *osclient.Client {
+	return c.osClient
+}
+
+// ProjectRequestClients returns the clients used to create the contents of newly requested projects
+func (c *MasterConfig) ProjectRequestClients() (*osclient.Client, *kclient.Client) {
+	return c.osClient, c.kubeClient
+}
+
+// ProjectFinalizerClient returns the client used to delete the contents of terminating projects
+func (c *MasterConfig) ProjectFinalizerClient() *osclient.Client {
+	return c.osClient
+}
+
+// GarbageCollectorClients returns the clients used to delete the objects whose owners have been deleted
+func (c *MasterConfig) GarbageCollectorClients() (*osclient.Client, *kclient.Client) {
+	return c.osClient, c.kubeClient
+}
+
+// TemplateInstanceClients returns the clients used to find the objects created from templates when template instances are created
+func (c *MasterConfig) TemplateInstanceClients() (*osclient.Client, *kclient.Client) {
+	return c.osClient, c.kubeClient
+}
+
+// PodNodeEnvironmentClient returns the client used to read the environment and phase of projects when objects are created
+func (c *MasterConfig) PodNodeEnvironmentClient() *osclient.Client {
+	return c.osClient
+}
+
+// ResourceQuotaClients returns the clients used to measure and record the usage of project quotas
+func (c *MasterConfig) ResourceQuotaClients() (*osclient.Client, *kclient.Client) {
+	return c.osClient, c.kubeClient
+}
 
 func (c *MasterConfig) InstallProtectedAPI(container *restful.Container) []string {
 	defaultRegistry := env("OPENSHIFT_DEFAULT_REGISTRY", "${DOCKER_REGISTRY_SERVICE_HOST}:${DOCKER_REGISTRY_SERVICE_PORT}")
@@ -235,13 +426,15 @@ func (c *MasterConfig) InstallProtectedAPI(container *restful.Container) []strin
 	}
 
 	buildEtcd := buildetcd.New(c.EtcdHelper)
-	imageEtcd := imageetcd.New(c.EtcdHelper, imageetcd.DefaultRegistryFunc(defaultRegistryFunc))
+	imageEtcd := imageetcd.New(c.EtcdHelper, imageetcd.DefaultRegistryFunc(defaultRegistryFunc), c.ImageRepositoryDeletionGracePeriod)
 	deployEtcd := deployetcd.New(c.EtcdHelper)
 	routeEtcd := routeetcd.New(c.EtcdHelper)
 	projectEtcd := projectetcd.New(c.EtcdHelper)
-	userEtcd := useretcd.New(c.EtcdHelper, user.NewDefaultUserInitStrategy())
+	userEtcd := useretcd.New(c.EtcdHelper, user.NewDefaultUserInitStrategy(), user.MappingMethodClaim)
 	oauthEtcd := oauthetcd.New(c.EtcdHelper)
 	authorizationEtcd := authorizationetcd.New(c.EtcdHelper)
+	policyChangeReviewer := policychangereviewregistry.NewReviewer(authorizationEtcd, authorizationEtcd)
+	templateEtcd := templateetcd.New(c.EtcdHelper)
 
 	// TODO: with sharding, this needs to be changed
 	deployConfigGenerator := &deployconfiggenerator.DeploymentConfigGenerator{
@@ -270,72 +463,165 @@ func (c *MasterConfig) InstallProtectedAPI(container *restful.Container) []strin
 		"imageRepositories":       imagerepository.NewREST(imageEtcd),
 		"imageRepositoryMappings": imagerepositorymapping.NewREST(imageEtcd, imageEtcd),
 		"imageRepositoryTags":     imagerepositorytag.NewREST(imageEtcd, imageEtcd),
+		"imageSearches":           imagesearch.NewREST(imageEtcd),
+		"imageRepositoryRestores": imagerepositoryrestore.NewREST(imageEtcd),
 
 		"deployments":               deployregistry.NewREST(deployEtcd),
 		"deploymentConfigs":         deployconfigregistry.NewREST(deployEtcd),
 		"generateDeploymentConfigs": deployconfiggenerator.NewREST(deployConfigGenerator, v1beta1.Codec),
 		"deploymentConfigRollbacks": deployrollback.NewREST(deployRollbackClient, latest.Codec),
+		"deploymentLogs":            deploylogregistry.NewREST(c.DeploymentClient()),
 
-		"templateConfigs": templateregistry.NewREST(),
+		"templateConfigs":   templateregistry.NewREST(),
+		"templates":         templatestorage.NewREST(templateEtcd),
+		"templateInstances": templateinstanceregistry.NewREST(templateEtcd, c.templateInstanceObjectLister()),
 
-		"routes": routeregistry.NewREST(routeEtcd),
+		"routes":             routeregistry.NewREST(routeEtcd, c.AllowRouteHostSharing),
+		"routeStatusUpdates": routestatusregistry.NewREST(routeEtcd),
 
-		"projects": projectregistry.NewREST(projectEtcd),
+		"projects":        projectregistry.NewREST(projectEtcd),
+		"projectRequests": projectrequestregistry.NewREST(projectEtcd, c.ProjectRequestTemplate, c.projectRequestCreator(), c.MasterAuthorizationNamespace),
 
 		"userIdentityMappings": useridentitymapping.NewREST(userEtcd),
-		"users":                userregistry.NewREST(userEtcd),
+		"identities":           identityregistry.NewREST(userEtcd),
+		"users": userregistry.NewREST(userEtcd, &userregistry.Cleanup{
+			AccessTokens:         oauthEtcd,
+			AuthorizeTokens:      oauthEtcd,
+			ClientAuthorizations: oauthEtcd,
+			PolicyBindings:       authorizationEtcd,
+			Namespaces:           c.policyNamespaces(projectEtcd),
+		}),
 
 		"oAuthAuthorizeTokens":      authorizetokenregistry.NewREST(oauthEtcd),
 		"oAuthAccessTokens":         accesstokenregistry.NewREST(oauthEtcd),
 		"oAuthClients":              clientregistry.NewREST(oauthEtcd),
 		"oAuthClientAuthorizations": clientauthorizationregistry.NewREST(oauthEtcd),
-
-		"policies":       policyregistry.NewREST(authorizationEtcd),
-		"policyBindings": policybindingregistry.NewREST(authorizationEtcd),
-		"roles":          roleregistry.NewREST(authorizationEtcd),
-		"roleBindings":   rolebindingregistry.NewREST(authorizationEtcd, authorizationEtcd, userEtcd, c.MasterAuthorizationNamespace),
+		"bootstrapTokens":           bootstraptokenregistry.NewREST(oauthEtcd),
+		"routerTokens":              routertokenregistry.NewREST(oauthEtcd),
+
+		"policies":            policyregistry.NewREST(authorizationEtcd),
+		"policyBindings":      policybindingregistry.NewREST(authorizationEtcd),
+		"roles":               roleregistry.NewREST(authorizationEtcd, policyChangeReviewer),
+		"roleBindings":        rolebindingregistry.NewREST(authorizationEtcd, authorizationEtcd, userEtcd, c.MasterAuthorizationNamespace, policyChangeReviewer),
+		"policyChangeReviews": policychangereviewregistry.NewREST(policyChangeReviewer),
 	}
 
-	admissionControl := admit.NewAlwaysAdmit()
-
-	if err := apiserver.NewAPIGroupVersion(storage, v1beta1.Codec, OpenShiftAPIPrefixV1Beta1, latest.SelfLinker, admissionControl, latest.RESTMapper).InstallREST(container, OpenShiftAPIPrefix, "v1beta1"); err != nil {
-		glog.Fatalf("Unable to initialize API: %v", err)
+	// every version is served from the same storage, objects are converted to and from the version of the request.
+	// Each version is also served in the compact encoding under compactAPIPrefix and compactWatchAPIPrefix, and as
+	// YAML under yamlAPIPrefix, where compactRoutingFilter and yamlRoutingFilter send the requests of clients that
+	// ask for them.
+	versions := []struct {
+		version string
+		prefix  string
+		codec   runtime.Codec
+	}{
+		{"v1beta1", OpenShiftAPIPrefixV1Beta1, v1beta1.Codec},
+		{"v1beta2", OpenShiftAPIPrefixV1Beta2, v1beta2.Codec},
+		{"v1beta1", compactAPIPrefix + "/v1beta1", v1beta1.CompactCodec},
+		{"v1beta2", compactAPIPrefix + "/v1beta2", v1beta2.CompactCodec},
+		{"v1beta1", compactWatchAPIPrefix + "/v1beta1", v1beta1.CompactWatchCodec},
+		{"v1beta2", compactWatchAPIPrefix + "/v1beta2", v1beta2.CompactWatchCodec},
+		{"v1beta1", yamlAPIPrefix + "/v1beta1", v1beta1.YAMLCodec},
+		{"v1beta2", yamlAPIPrefix + "/v1beta2", v1beta2.YAMLCodec},
 	}
+	admissionControl := c.originAdmissionControl()
+	for _, v := range versions {
+		root := path.Dir(v.prefix)
+		// self links name the public API
+		selfLinkPrefix := path.Join(OpenShiftAPIPrefix, v.version)
+		if err := apiserver.NewAPIGroupVersion(storage, v.codec, selfLinkPrefix, latest.SelfLinker, admissionControl, latest.RESTMapper).InstallREST(container, root, v.version); err != nil {
+			glog.Fatalf("Unable to initialize API %s at %s: %v", v.version, root, err)
+		}
+	}
+
+	// images and builds are numerous enough that clients list them a page at a time
+	pagedResources := []string{"images", "builds"}
 
 	var root *restful.WebService
 	userRoutesChanged := 0
+	projectRequestRoutesChanged := 0
+	routeStatusRoutesChanged := 0
+	pagedRoutesChanged := 0
 	for _, svc := range container.RegisteredWebServices() {
-		switch svc.RootPath() {
-		case "/":
+		if svc.RootPath() == "/" {
 			root = svc
-		case OpenShiftAPIPrefixV1Beta1:
-			svc.Doc("OpenShift REST API, version v1beta1").ApiVersion("v1beta1")
+		}
+		for _, v := range versions {
+			if svc.RootPath() != v.prefix {
+				continue
+			}
+			svc.Doc("OpenShift REST API, version " + v.version).ApiVersion(v.version)
+
+			resources, err := apiResources(v.version, storage, latest.RESTMapper)
+			if err != nil {
+				glog.Fatalf("Unable to describe the resources of API %s: %v", v.version, err)
+			}
+			svc.Route(svc.GET("").To(discoveryHandler(resources)).
+				Doc("list the resources of API " + v.version).
+				Operation("getAPIResources").
+				Produces(restful.MIME_JSON))
 
 			// add the current user filter
 			// TODO: factor this better
-			filter := currentUserContextFilter(c.getRequestsToUsers())
+			filter := currentUserContextFilter(c.getRequestContextMapper())
+			requesterFilter := projectRequesterFilter(c.getRequestContextMapper())
+			reporterFilter := routeStatusReporterFilter(c.getRequestContextMapper())
 			routes := svc.Routes()
 			for i := range routes {
 				route := &routes[i]
-				if route.Method == "GET" && (route.Path == OpenShiftAPIPrefixV1Beta1+"/users/{name}") {
+				if route.Method == "GET" && (route.Path == v.prefix+"/users/{name}") {
 					route.Filters = append(route.Filters, filter)
 					userRoutesChanged++
 				}
+				if route.Method == "POST" && (route.Path == v.prefix+"/projectRequests") {
+					route.Filters = append(route.Filters, requesterFilter)
+					projectRequestRoutesChanged++
+				}
+				if route.Method == "POST" && (route.Path == v.prefix+"/routeStatusUpdates") {
+					route.Filters = append(route.Filters, reporterFilter)
+					routeStatusRoutesChanged++
+				}
+				for _, resource := range pagedResources {
+					if route.Method == "GET" && route.Path == v.prefix+"/"+resource {
+						route.Filters = append(route.Filters, listPageFilter(storage[resource].(pager), resource, v.codec, latest.SelfLinker, path.Join(OpenShiftAPIPrefix, v.version)))
+						pagedRoutesChanged++
+					}
+				}
 			}
 		}
 	}
-	if userRoutesChanged != 1 {
+	if userRoutesChanged != len(versions) {
 		glog.Fatalf("Could not find user route to install the current user filter.")
 	}
+	if projectRequestRoutesChanged != len(versions) {
+		glog.Fatalf("Could not find project request route to install the project requester filter.")
+	}
+	if routeStatusRoutesChanged != len(versions) {
+		glog.Fatalf("Could not find route status route to install the route status reporter filter.")
+	}
+	if pagedRoutesChanged != len(versions)*len(pagedResources) {
+		glog.Fatalf("Could not find the list routes of %v to install the list page filter.", pagedResources)
+	}
 	if root == nil {
 		root = new(restful.WebService)
 		container.Add(root)
 	}
-	initAPIVersionRoute(root, "v1beta1")
+	initAPIVersionRoute(root, latest.Version, latest.Versions...)
 
-	return []string{
-		fmt.Sprintf("Started OpenShift API at %%s%s", OpenShiftAPIPrefixV1Beta1),
+	messages := []string{}
+	for _, v := range versions {
+		if strings.HasPrefix(v.prefix, OpenShiftAPIPrefix+"/") {
+			messages = append(messages, fmt.Sprintf("Started OpenShift API %s at %%s%s", v.version, v.prefix))
+		}
 	}
+	roleLister := authorizer.NewRoleLister(c.MasterAuthorizationNamespace, authorizationEtcd, authorizationEtcd)
+	container.Handle(authenticationapi.UserContextPath, userContextHandler(roleLister, c.MasterAuthorizationNamespace, c.getRequestContextMapper()))
+	messages = append(messages, fmt.Sprintf("Started the user context endpoint at %%s%s", authenticationapi.UserContextPath))
+	// every watch is authorized by the API it is made against
+	container.Handle(watchMuxPath, newWatchMux(c.watchAsUser, c.getRequestContextMapper()))
+	messages = append(messages, fmt.Sprintf("Started the web socket watch multiplexer at %%s%s", watchMuxPath))
+
+	return append(messages, c.installDebug(container)...)
 }
 
 func (c *MasterConfig) InstallUnprotectedAPI(container *restful.Container) []string {
@@ -352,12 +638,110 @@ func (c *MasterConfig) InstallUnprotectedAPI(container *restful.Container) []str
 	prefix := OpenShiftAPIPrefixV1Beta1 + "/buildConfigHooks/"
 	handler = http.StripPrefix(prefix, handler)
 	container.Handle(prefix, handler)
-	return []string{}
+
+	extra := c.installHealthz(container)
+	if len(c.AssetPathPrefix) > 0 {
+		// the console authenticates its API requests itself, and its assets are public
+		mux := http.NewServeMux()
+		c.installAssets(mux)
+		container.Handle(c.AssetPathPrefix+"/", http.StripPrefix(c.AssetPathPrefix, mux))
+		extra = append(extra, fmt.Sprintf("Started the OpenShift UI at %%s%s", c.AssetPathPrefix))
+	}
+	if c.Metrics != nil {
+		container.Handle(metricsPath, c.Metrics.Handler())
+		extra = append(extra, fmt.Sprintf("Started metrics at %%s%s", metricsPath))
+	}
+	return extra
+}
+
+// policyNamespaces returns a function that lists the master authorization namespace and the namespaces of all
+// projects, which are the namespaces that hold role bindings.
+func (c *MasterConfig) policyNamespaces(projects projectregistry.Registry) func() ([]string, error) {
+	return func() ([]string, error) {
+		list, err := projects.ListProjects(kapi.NewContext(), klabels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		namespaces := []string{c.MasterAuthorizationNamespace}
+		for _, project := range list.Items {
+			namespaces = append(namespaces, project.Name)
+		}
+		return namespaces, nil
+	}
+}
+
+// projectRequestCreator returns the creator used to instantiate the project template in requested projects
+func (c *MasterConfig) projectRequestCreator() *osconfig.Bulk {
+	osClient, kubeClient := c.ProjectRequestClients()
+	return &osconfig.Bulk{
+		Mapper:            latest.RESTMapper,
+		Typer:             kapi.Scheme,
+		RESTClientFactory: clientForMapping(osClient, kubeClient),
+	}
+}
+
+// templateInstanceObjectLister returns the lister that finds the objects created from templates
+func (c *MasterConfig) templateInstanceObjectLister() *templateinstanceregistry.ClientObjectLister {
+	osClient, kubeClient := c.TemplateInstanceClients()
+	return &templateinstanceregistry.ClientObjectLister{
+		Mapper:  latest.RESTMapper,
+		Clients: clientForMapping(osClient, kubeClient),
+	}
+}
+
+// clientForMapping returns a function that picks osClient for the mappings of OpenShift kinds and
+// kubeClient for the others.
+func clientForMapping(osClient *osclient.Client, kubeClient *kclient.Client) resource.ClientMapperFunc {
+	return func(mapping *meta.RESTMapping) (resource.RESTClient, error) {
+		if latest.OriginKind(mapping.Kind, mapping.APIVersion) {
+			return osClient, nil
+		}
+		return kubeClient, nil
+	}
+}
+
+// KubeAdmissionControl returns the admission control applied to Kubernetes API objects by the
+// Kubernetes master started with OpenShift: nothing is created in terminating projects, and the
+// node selector and pod labels of a project are applied to the pods created in it.
+func (c *MasterConfig) KubeAdmissionControl() admission.Interface {
+	return admissionChain{
+		projectadmission.NewProjectLifecycle(c.PodNodeEnvironmentClient()),
+		projectadmission.NewPodNodeEnvironment(c.PodNodeEnvironmentClient()),
+	}
+}
+
+// originAdmissionControl returns the admission control applied to OpenShift API objects: rejection of
+// creates in terminating projects, the configured AdmissionControl, and enforcement of project limit
+// ranges and resource quotas.
+func (c *MasterConfig) originAdmissionControl() admission.Interface {
+	osclient, kclient := c.ResourceQuotaClients()
+	measures := quota.NewMeasuresFunc(osclient, time.Now)
+	chain := admissionChain{projectadmission.NewProjectLifecycle(c.PodNodeEnvironmentClient())}
+	if c.AdmissionControl != nil {
+		chain = append(chain, c.AdmissionControl)
+	}
+	chain = append(chain, limitrangeadmission.NewLimitRanger(kclient, measures))
+	chain = append(chain, quotaadmission.NewResourceQuota(kclient, measures))
+	return chain
 }
 
-//initAPIVersionRoute initializes the osapi endpoint to behave similiar to the upstream api endpoint
-func initAPIVersionRoute(root *restful.WebService, version string) {
-	versionHandler := apiserver.APIVersionHandler(version)
+// admissionChain performs admission control with each handler in order and returns the first error
+type admissionChain []admission.Interface
+
+// Admit implements admission.Interface
+func (chain admissionChain) Admit(a admission.Attributes) error {
+	for _, handler := range chain {
+		if err := handler.Admit(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initAPIVersionRoute initializes the osapi endpoint to behave similiar to the upstream api endpoint.  Besides
+// the versions the upstream endpoint lists, it names the version clients should prefer.
+func initAPIVersionRoute(root *restful.WebService, preferred string, versions ...string) {
+	versionHandler := discoveryHandler(osapi.APIGroup{Versions: versions, PreferredVersion: preferred})
 	root.Route(root.GET(OpenShiftAPIPrefix).To(versionHandler).
 		Doc("list supported server API versions").
 		Produces(restful.MIME_JSON).
@@ -371,7 +755,24 @@ func initAPIVersionRoute(root *restful.WebService, version string) {
 func (c *MasterConfig) Run(protected []APIInstaller, unprotected []APIInstaller) {
 	var extra []string
 
-	c.ensureComponentAuthorizationRules()
+	if c.Metrics == nil {
+		c.Metrics = NewMetrics()
+	}
+	if c.Controllers == nil {
+		c.Controllers = NewControllerManager()
+	}
+	if c.requestUsage == nil {
+		c.requestUsage = newRequestUsage(c.MaxRequestsPerSecondPerUser, c.RequestBurstPerUser, c.MaxRequestsPerSecondPerSystemUser)
+	}
+	if c.Informers == nil {
+		c.Informers = oscache.NewInformers(c.osClient, c.kubeClient)
+	}
+	// the build metrics are served by the master running the build controller
+	c.buildMetrics = buildcontroller.NewBuildMetrics(c.Informers.Builds().Store())
+	c.Metrics.AddCollector(c.buildMetrics.Write)
+	c.routeCertificateMetrics = &routecontroller.CertificateExpiryMetrics{}
+	c.Metrics.AddCollector(c.routeCertificateMetrics.Write)
+	policyBootstrapped := c.ensureComponentAuthorizationRules()
 
 	safe := kmaster.NewHandlerContainer(http.NewServeMux())
 	open := kmaster.NewHandlerContainer(http.NewServeMux())
@@ -381,8 +782,25 @@ func (c *MasterConfig) Run(protected []APIInstaller, unprotected []APIInstaller)
 	for _, i := range protected {
 		extra = append(extra, i.InstallAPI(safe)...)
 	}
-	handler := c.authorizationFilter(safe)
-	handler = authenticationHandlerFilter(handler, c.Authenticator, c.getRequestsToUsers())
+	handler := c.authorizationFilter(yamlRoutingFilter(compactRoutingFilter(safe)))
+	// authorize requests as the user they impersonate, once the authenticated user is limited
+	handler = impersonationFilter(handler, c.getAuthorizer(), useretcd.New(c.EtcdHelper, user.NewDefaultUserInitStrategy(), user.MappingMethodClaim), c.getRequestContextMapper())
+	if c.AuditLog != nil {
+		handler = auditFilter(handler, c.AuditLog, c.getRequestContextMapper())
+	}
+	// serve PATCH as a read and an update of the object, each audited and authorized on its own
+	handler = patchFilter(handler, c.getRequestContextMapper())
+	// warn clients that use deprecated API versions and fields, once they are authenticated and limited
+	handler = deprecationFilter(handler, c.Metrics)
+	handler = inFlightLimitFilter(handler, c.MaxRequestsInFlight, c.MaxRequestsInFlightPerUser, c.getRequestContextMapper())
+	handler = requestUsageFilter(handler, c.requestUsage, c.getRequestContextMapper())
+	handler = authenticationHandlerFilter(handler, c.Authenticator, c.getRequestContextMapper())
+	// stop waiting on requests that have not been served by the deadline of their context
+	handler = deadlineFilter(handler, c.getRequestContextMapper())
+	// browsers send the bearer token of a web socket as a subprotocol
+	handler = bearerProtocolFilter(handler)
+	// every request gets a context of its own here, which the reads and updates a PATCH is served as share
+	handler = authcontext.NewRequestContextFilter(c.getRequestContextMapper(), requestTimeout, handler)
 
 	// unprotected resources
 	unprotected = append(unprotected, APIInstallFunc(c.InstallUnprotectedAPI))
@@ -391,98 +809,240 @@ func (c *MasterConfig) Run(protected []APIInstaller, unprotected []APIInstaller)
 	}
 	open.Handle("/", handler)
 
-	// install swagger
-	swaggerConfig := swagger.Config{
-		WebServices: append(safe.RegisteredWebServices(), open.RegisteredWebServices()...),
-		ApiPath:     swaggerAPIPrefix,
+	// the unprotected installers create the default OAuth clients
+	if policyBootstrapped {
+		c.bootstrap.complete()
 	}
-	swagger.RegisterSwaggerService(swaggerConfig, open)
+
+	// install swagger
+	swagger.Register(open, swaggerAPIPrefix, append(publicWebServices(safe), open.RegisteredWebServices()...))
 	extra = append(extra, fmt.Sprintf("Started Swagger Schema API at %%s%s", swaggerAPIPrefix))
 
-	handler = open
+	// record every request, whether it is served by a protected or an unprotected endpoint
+	handler = c.Metrics.InstrumentHandler(open)
+	if c.SlowRequestThreshold > 0 {
+		handler = slowRequestFilter(handler, c.SlowRequestThreshold, c.Metrics)
+	}
+	// accept the labelSelector and fieldSelector query parameters
+	handler = selectorParamsFilter(handler)
 
 	// add CORS support
 	if origins := c.ensureCORSAllowedOrigins(); len(origins) != 0 {
-		handler = apiserver.CORS(handler, origins, nil, nil, "true")
+		handler = corsFilter(handler, corsConfig{
+			AllowedOrigins: origins,
+			AllowedMethods: c.CORSAllowedMethods,
+			AllowedHeaders: c.CORSAllowedHeaders,
+			ExposedHeaders: c.CORSExposedHeaders,
+			MaxAgeSeconds:  int(c.CORSMaxAge / time.Second),
+		})
 	}
 
 	server := &http.Server{
 		Addr:           c.MasterBindAddr,
 		Handler:        handler,
-		ReadTimeout:    5 * time.Minute,
-		WriteTimeout:   5 * time.Minute,
+		ReadTimeout:    requestTimeout,
+		WriteTimeout:   requestTimeout,
 		MaxHeaderBytes: 1 << 20,
 	}
 
-	go util.Forever(func() {
+	listener, err := net.Listen("tcp", c.MasterBindAddr)
+	if err != nil {
+		glog.Fatalf("Unable to listen on %s: %v", c.MasterBindAddr, err)
+	}
+	if c.TLS {
+		cert, err := crypto.NewServingCertificate(c.MasterCertFile, c.MasterKeyFile)
+		if err != nil {
+			glog.Fatalf("Unable to load the master certificate: %v", err)
+		}
+		// serve rotated certificates to new connections without restarting
+		cert.Run(servingCertReloadPeriod)
+		certs := crypto.NewNamedCertificates(cert)
+		for _, named := range c.NamedCertificates {
+			namedCert, err := crypto.NewServingCertificate(named.CertFile, named.KeyFile)
+			if err != nil {
+				glog.Fatalf("Unable to load the certificate for %v: %v", named.Names, err)
+			}
+			namedCert.Run(servingCertReloadPeriod)
+			certs.Add(named.Names, namedCert)
+		}
+		server.TLSConfig = &tls.Config{
+			// Change default from SSLv3 to TLSv1.0 (because of POODLE vulnerability)
+			MinVersion: tls.VersionTLS10,
+			// Populate PeerCertificates in requests, but don't reject connections without certificates
+			// This allows certificates to be validated by authenticators, while still allowing other auth types
+			ClientAuth: tls.RequestClientCert,
+			// exec, attach and port-forward take over the connection, which HTTP/2 cannot do, so only
+			// HTTP/1.1 is offered
+			NextProtos: []string{"http/1.1"},
+		}
+		if err := crypto.SetServingCertificates(server.TLSConfig, certs); err != nil {
+			glog.Fatalf("Unable to serve the master certificates: %v", err)
+		}
+		listener = tls.NewListener(listener, server.TLSConfig)
+	}
+
+	c.server = newGracefulServer(server)
+	go func() {
 		for _, s := range extra {
 			glog.Infof(s, c.MasterAddr)
 		}
-		if c.TLS {
-			server.TLSConfig = &tls.Config{
-				// Change default from SSLv3 to TLSv1.0 (because of POODLE vulnerability)
-				MinVersion: tls.VersionTLS10,
-				// Populate PeerCertificates in requests, but don't reject connections without certificates
-				// This allows certificates to be validated by authenticators, while still allowing other auth types
-				ClientAuth: tls.RequestClientCert,
-			}
-			glog.Fatal(server.ListenAndServeTLS(c.MasterCertFile, c.MasterKeyFile))
-		} else {
-			glog.Fatal(server.ListenAndServe())
+		if err := c.server.Serve(listener); err != nil {
+			glog.Fatal(err)
 		}
-	}, 0)
+		glog.Infof("Stopped serving the master API on %s", c.MasterBindAddr)
+	}()
 
 	// Attempt to verify the server came up for 20 seconds (100 tries * 100ms, 100ms timeout per try)
 	cmdutil.WaitForSuccessfulDial("tcp", c.MasterBindAddr, 100*time.Millisecond, 100*time.Millisecond, 100)
+
+	if len(c.InsecureBindAddr) > 0 {
+		c.runInsecure(handler)
+	}
+}
+
+// runInsecure serves handler without TLS on InsecureBindAddr.  Requests are authenticated and authorized as
+// they are on the secure listener, the listener only lets local tooling such as health checks skip TLS.
+func (c *MasterConfig) runInsecure(handler http.Handler) {
+	if !cmdutil.IsLoopbackHostPort(c.InsecureBindAddr) {
+		glog.Fatalf("The insecure master API address %s must be a loopback address", c.InsecureBindAddr)
+	}
+	listener, err := net.Listen("tcp", c.InsecureBindAddr)
+	if err != nil {
+		glog.Fatalf("Unable to listen on %s: %v", c.InsecureBindAddr, err)
+	}
+
+	c.insecureServer = newGracefulServer(&http.Server{
+		Addr:           c.InsecureBindAddr,
+		Handler:        handler,
+		ReadTimeout:    5 * time.Minute,
+		WriteTimeout:   5 * time.Minute,
+		MaxHeaderBytes: 1 << 20,
+	})
+	go func() {
+		glog.Infof("Serving the master API without TLS at http://%s", c.InsecureBindAddr)
+		if err := c.insecureServer.Serve(listener); err != nil {
+			glog.Fatal(err)
+		}
+		glog.Infof("Stopped serving the master API on %s", c.InsecureBindAddr)
+	}()
+
+	cmdutil.WaitForSuccessfulDial("tcp", c.InsecureBindAddr, 100*time.Millisecond, 100*time.Millisecond, 100)
 }
 
-// getRequestsToUsers returns the shared user context
-func (c *MasterConfig) getRequestsToUsers() *authcontext.RequestContextMap {
-	if c.requestsToUsers == nil {
-		c.requestsToUsers = authcontext.NewRequestContextMap()
+// Stop stops the master API server started by Run.  New connections are refused immediately, and requests
+// in flight are given ShutdownGracePeriod to complete before their connections are closed.
+func (c *MasterConfig) Stop() {
+	if c.server == nil {
+		return
+	}
+	glog.Infof("Draining master API connections for up to %v", c.ShutdownGracePeriod)
+	if c.insecureServer != nil {
+		// drain both listeners at once so neither waits on the other's grace period
+		go c.insecureServer.Stop(c.ShutdownGracePeriod)
 	}
-	return c.requestsToUsers
+	if err := c.server.Stop(c.ShutdownGracePeriod); err != nil {
+		glog.Warningf("Master API did not drain cleanly: %v", err)
+	}
+}
+
+// getRequestContextMapper returns the shared request context mapper
+func (c *MasterConfig) getRequestContextMapper() *authcontext.RequestContextMapper {
+	if c.requestContextMapper == nil {
+		c.requestContextMapper = authcontext.NewRequestContextMapper()
+	}
+	return c.requestContextMapper
 }
 
-// ensureComponentAuthorizationRules initializes the global policies
-func (c *MasterConfig) ensureComponentAuthorizationRules() {
+// getAuthorizer returns the authorizer of protected requests, creating it on first use.
+func (c *MasterConfig) getAuthorizer() authorizer.Authorizer {
+	if c.policyAuthorizer == nil {
+		authorizationEtcd := authorizationetcd.New(c.EtcdHelper)
+		c.policyAuthorizer = authorizer.NewAuthorizer(c.MasterAuthorizationNamespace, authorizationEtcd, authorizationEtcd)
+	}
+	return c.policyAuthorizer
+}
+
+// ensureComponentAuthorizationRules initializes the global policies and returns true if they exist.  Existing
+// policies are reconciled with the bootstrap policy if ReconcileBootstrapPolicy is set.
+func (c *MasterConfig) ensureComponentAuthorizationRules() bool {
 	registry := authorizationetcd.New(c.EtcdHelper)
 	ctx := kapi.WithNamespace(kapi.NewContext(), c.MasterAuthorizationNamespace)
+	ok := true
 
 	if existing, err := registry.GetPolicy(ctx, authorizationapi.PolicyName); err == nil || strings.Contains(err.Error(), " not found") {
+		bootstrapGlobalPolicy := authorizer.GetBootstrapPolicy(c.MasterAuthorizationNamespace)
 		if existing != nil && existing.Name == authorizationapi.PolicyName {
-			return
-		}
+			merge := func(obj runtime.Object) bool {
+				return authorizer.ReconcilePolicy(obj.(*authorizationapi.Policy), bootstrapGlobalPolicy)
+			}
+			if c.reconcile(existing, merge) {
+				if err := registry.UpdatePolicy(ctx, existing); err != nil {
+					glog.Errorf("Error reconciling policy: %v due to %v\n", existing.Name, err)
+					ok = false
+				} else {
+					glog.Infof("Reconciled policy %s with the bootstrap policy", existing.Name)
+				}
+			}
 
-		bootstrapGlobalPolicy := authorizer.GetBootstrapPolicy(c.MasterAuthorizationNamespace)
-		if err = registry.CreatePolicy(ctx, bootstrapGlobalPolicy); err != nil {
+		} else if err = registry.CreatePolicy(ctx, bootstrapGlobalPolicy); err != nil {
 			glog.Errorf("Error creating policy: %v due to %v\n", bootstrapGlobalPolicy, err)
+			ok = false
 		}
 
 	} else {
 		glog.Errorf("Error getting policy: %v due to %v\n", authorizationapi.PolicyName, err)
+		ok = false
 	}
 
 	if existing, err := registry.GetPolicyBinding(ctx, c.MasterAuthorizationNamespace); err == nil || strings.Contains(err.Error(), " not found") {
+		bootstrapGlobalPolicyBinding := authorizer.GetBootstrapPolicyBinding(c.MasterAuthorizationNamespace)
 		if existing != nil && existing.Name == c.MasterAuthorizationNamespace {
-			return
-		}
+			merge := func(obj runtime.Object) bool {
+				return authorizer.ReconcilePolicyBinding(obj.(*authorizationapi.PolicyBinding), bootstrapGlobalPolicyBinding)
+			}
+			if c.reconcile(existing, merge) {
+				if err := registry.UpdatePolicyBinding(ctx, existing); err != nil {
+					glog.Errorf("Error reconciling policy binding: %v due to %v\n", existing.Name, err)
+					ok = false
+				} else {
+					glog.Infof("Reconciled policy binding %s with the bootstrap policy", existing.Name)
+				}
+			}
 
-		bootstrapGlobalPolicyBinding := authorizer.GetBootstrapPolicyBinding(c.MasterAuthorizationNamespace)
-		if err = registry.CreatePolicyBinding(ctx, bootstrapGlobalPolicyBinding); err != nil {
+		} else if err = registry.CreatePolicyBinding(ctx, bootstrapGlobalPolicyBinding); err != nil {
 			glog.Errorf("Error creating policy: %v due to %v\n", bootstrapGlobalPolicyBinding, err)
+			ok = false
 		}
 
 	} else {
 		glog.Errorf("Error getting policy: %v due to %v\n", c.MasterAuthorizationNamespace, err)
+		ok = false
+	}
+
+	return ok
+}
+
+// reconcile merges the bootstrap policy into obj with merge and returns true if obj changed and should be
+// saved.  Unless ReconcileBootstrapPolicy is set, obj is left alone and a warning is logged if it is out of date.
+func (c *MasterConfig) reconcile(obj runtime.Object, merge func(obj runtime.Object) bool) bool {
+	if c.ReconcileBootstrapPolicy {
+		return merge(obj)
 	}
+	copied, err := kapi.Scheme.Copy(obj)
+	if err != nil {
+		glog.Errorf("Unable to copy %#v: %v", obj, err)
+		return false
+	}
+	if merge(copied) {
+		glog.Warningf("The master policy lacks roles or role bindings of the bootstrap policy; start the master with --reconcile-bootstrap-policy to add them")
+	}
+	return false
 }
 
 // TODO Have MasterConfig take a fully formed Authorizer
 func (c *MasterConfig) authorizationFilter(handler http.Handler) http.Handler {
-	authorizationEtcd := authorizationetcd.New(c.EtcdHelper)
-	authorizationAttributeBuilder := authorizer.NewAuthorizationAttributeBuilder(c.getRequestsToUsers())
-	authz := authorizer.NewAuthorizer(c.MasterAuthorizationNamespace, authorizationEtcd, authorizationEtcd)
+	authorizationAttributeBuilder := authorizer.NewAuthorizationAttributeBuilder(c.getRequestContextMapper())
+	authz := c.getAuthorizer()
 
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		attributes, err := authorizationAttributeBuilder.GetAttributes(req)
@@ -526,54 +1086,16 @@ func forbidden(reason string, w http.ResponseWriter, req *http.Request) {
 	fmt.Fprintf(w, "Forbidden: %q %s", req.RequestURI, reason)
 }
 
-// RunAssetServer starts the asset server for the OpenShift UI.
+// RunAssetServer starts the asset server for the OpenShift UI, unless the master serves it under AssetPathPrefix.
 func (c *MasterConfig) RunAssetServer() {
-	// TODO use	version.Get().GitCommit as an etag cache header
-	mux := http.NewServeMux()
-
-	masterURL, err := url.Parse(c.MasterPublicAddr)
-	if err != nil {
-		glog.Fatalf("Error parsing master url: %v", err)
-	}
-
-	k8sURL, err := url.Parse(c.KubernetesPublicAddr)
-	if err != nil {
-		glog.Fatalf("Error parsing kubernetes url: %v", err)
-	}
-
-	config := assets.WebConsoleConfig{
-		MasterAddr:        masterURL.Host,
-		MasterPrefix:      OpenShiftAPIPrefix,
-		KubernetesAddr:    k8sURL.Host,
-		KubernetesPrefix:  "/api",
-		OAuthAuthorizeURL: OpenShiftOAuthAuthorizeURL(masterURL.String()),
-		OAuthRedirectBase: c.AssetPublicAddr,
-		OAuthClientID:     OpenShiftWebConsoleClientID,
+	if len(c.AssetPathPrefix) > 0 {
+		glog.Infof("OpenShift UI available at %s", c.AssetPublicAddr)
+		return
 	}
 
-	mux.Handle("/",
-		// Gzip first so that inner handlers can react to the addition of the Vary header
-		assets.GzipHandler(
-			// Generated config.js can not be cached since it changes depending on startup options
-			assets.GeneratedConfigHandler(
-				config,
-				// Cache control should happen after all Vary headers are added, but before
-				// any asset related routing (HTML5ModeHandler and FileServer)
-				assets.CacheControlHandler(
-					version.Get().GitCommit,
-					assets.HTML5ModeHandler(
-						http.FileServer(
-							&assetfs.AssetFS{
-								assets.Asset,
-								assets.AssetDir,
-								"",
-							},
-						),
-					),
-				),
-			),
-		),
-	)
+	// TODO use	version.Get().GitCommit as an etag cache header
+	mux := http.NewServeMux()
+	c.installAssets(mux)
 
 	server := &http.Server{
 		Addr:           c.AssetBindAddr,
@@ -585,6 +1107,11 @@ func (c *MasterConfig) RunAssetServer() {
 
 	go util.Forever(func() {
 		if c.TLS {
+			cert, err := crypto.NewServingCertificate(c.AssetCertFile, c.AssetKeyFile)
+			if err != nil {
+				glog.Fatalf("Unable to load the asset server certificate: %v", err)
+			}
+			cert.Run(servingCertReloadPeriod)
 			server.TLSConfig = &tls.Config{
 				// Change default from SSLv3 to TLSv1.0 (because of POODLE vulnerability)
 				MinVersion: tls.VersionTLS10,
@@ -592,8 +1119,15 @@ func (c *MasterConfig) RunAssetServer() {
 				// This allows certificates to be validated by authenticators, while still allowing other auth types
 				ClientAuth: tls.RequestClientCert,
 			}
+			if err := crypto.SetServingCertificates(server.TLSConfig, crypto.NewNamedCertificates(cert)); err != nil {
+				glog.Fatalf("Unable to serve the asset server certificate: %v", err)
+			}
+			listener, err := net.Listen("tcp", c.AssetBindAddr)
+			if err != nil {
+				glog.Fatalf("Unable to listen on %s: %v", c.AssetBindAddr, err)
+			}
 			glog.Infof("OpenShift UI listening at https://%s", c.AssetBindAddr)
-			glog.Fatal(server.ListenAndServeTLS(c.AssetCertFile, c.AssetKeyFile))
+			glog.Fatal(server.Serve(tls.NewListener(listener, server.TLSConfig)))
 		} else {
 			glog.Infof("OpenShift UI listening at https://%s", c.AssetBindAddr)
 			glog.Fatal(server.ListenAndServe())
@@ -606,8 +1140,118 @@ func (c *MasterConfig) RunAssetServer() {
 	glog.Infof("OpenShift UI available at %s", c.AssetPublicAddr)
 }
 
+// installAssets serves the OpenShift UI, its generated configuration and the API proxies of the asset server on mux.
+func (c *MasterConfig) installAssets(mux *http.ServeMux) {
+	masterURL, err := url.Parse(c.MasterPublicAddr)
+	if err != nil {
+		glog.Fatalf("Error parsing master url: %v", err)
+	}
+
+	k8sURL, err := url.Parse(c.KubernetesPublicAddr)
+	if err != nil {
+		glog.Fatalf("Error parsing kubernetes url: %v", err)
+	}
+
+	config := assets.WebConsoleConfig{
+		MasterAddr:        masterURL.Host,
+		MasterPrefix:      OpenShiftAPIPrefix,
+		KubernetesAddr:    k8sURL.Host,
+		KubernetesPrefix:  "/api",
+		OAuthAuthorizeURL: OpenShiftOAuthAuthorizeURL(masterURL.String()),
+		OAuthRedirectBase: c.AssetPublicAddr,
+		OAuthClientID:     OpenShiftWebConsoleClientID,
+		Extensions:        c.AssetExtensions,
+
+		LogoutURL:                c.AssetLogoutURL,
+		AccessTokenMaxAgeSeconds: c.AccessTokenMaxAgeSeconds,
+		// renew ahead of expiry, so requests in flight are not sent with a token about to expire
+		TokenRenewalSeconds: c.AccessTokenMaxAgeSeconds / 10,
+	}
+
+	// a console served by the master calls the APIs on the same host already
+	proxyAPI := c.AssetProxyAPI && len(c.AssetPathPrefix) == 0
+	if proxyAPI {
+		if err := c.installAPIProxies(mux); err != nil {
+			glog.Fatalf("Unable to proxy the APIs from the asset server: %v", err)
+		}
+		assetURL, err := url.Parse(c.AssetPublicAddr)
+		if err != nil {
+			glog.Fatalf("Error parsing asset server url: %v", err)
+		}
+		// the console calls the APIs through the asset server it is served from
+		config.MasterAddr = assetURL.Host
+		config.KubernetesAddr = assetURL.Host
+	}
+
+	securityHeaders := c.AssetSecurityHeaders
+	if len(securityHeaders.ContentSecurityPolicy) == 0 {
+		apiURLs := []string{masterURL.String(), k8sURL.String()}
+		if proxyAPI {
+			apiURLs = []string{c.AssetPublicAddr}
+		}
+		securityHeaders.ContentSecurityPolicy = assets.ContentSecurityPolicy(apiURLs, c.AssetExtensions)
+	}
+
+	overlay := assets.Overlay{Dir: c.AssetDir}
+	asset := overlay.Asset
+	if len(c.AssetPathPrefix) > 0 {
+		asset = assets.BaseAsset(asset, c.AssetPathPrefix+"/")
+	}
+	assetHandler := assets.HTML5ModeHandler(
+		asset,
+		http.FileServer(
+			&assetfs.AssetFS{
+				asset,
+				overlay.AssetDir,
+				"",
+			},
+		),
+	)
+	if len(c.AssetDir) == 0 {
+		// Cache control should happen after all Vary headers are added, but before
+		// any asset related routing (HTML5ModeHandler and FileServer).  Assets served
+		// from disk may change at any time and are not cached.
+		assetHandler = assets.CacheControlHandler(version.Get().GitCommit, assetHandler)
+	} else {
+		glog.Infof("Serving the files in %s in place of the embedded OpenShift UI assets", c.AssetDir)
+	}
+
+	mux.Handle("/",
+		assets.SecurityHeadersHandler(securityHeaders,
+			// Gzip first so that inner handlers can react to the addition of the Vary header
+			assets.GzipHandler(
+				// Generated config.js can not be cached since it changes depending on startup options
+				assets.GeneratedConfigHandler(config, assetHandler),
+			),
+		),
+	)
+}
+
+// installAPIProxies forwards the Kubernetes and OpenShift API requests of users made to the asset server to
+// the masters, with only the credentials of the user making the request.
+func (c *MasterConfig) installAPIProxies(mux *http.ServeMux) error {
+	clientConfig := c.userClientConfig("", "")
+	backends := map[string]string{
+		"/api/":                  c.KubernetesAddr,
+		OpenShiftAPIPrefix + "/": c.MasterAddr,
+	}
+	for prefix, addr := range backends {
+		backendURL, err := url.Parse(addr)
+		if err != nil {
+			return err
+		}
+		proxy, err := httpproxy.NewUpgradeAwareSingleHostReverseProxy(clientConfig, backendURL)
+		if err != nil {
+			return err
+		}
+		mux.Handle(prefix, assets.APIProxyHandler(proxy))
+		glog.Infof("Proxying %s on the asset server to %s", prefix, backendURL)
+	}
+	return nil
+}
+
 // RunBuildController starts the build sync loop for builds and buildConfig processing.
-func (c *MasterConfig) RunBuildController() {
+func (c *MasterConfig) RunBuildController(stop <-chan struct{}, synced func(), crashed func(interface{})) {
 	// initialize build controller
 	dockerImage := c.ImageFor("docker-builder")
 	stiImage := c.ImageFor("sti-builder")
@@ -619,8 +1263,10 @@ func (c *MasterConfig) RunBuildController() {
 		KubeClient:   kclient,
 		BuildUpdater: buildclient.NewOSClientBuildClient(osclient),
 		DockerBuildStrategy: &buildstrategy.DockerBuildStrategy{
-			Image:          dockerImage,
-			UseLocalImages: useLocalImages,
+			Image:                dockerImage,
+			UseLocalImages:       useLocalImages,
+			AllowedBuilderImages: c.AllowedBuilderImages,
+			SourceSecretsDir:     c.BuildSourceSecretsDir,
 			// TODO: this will be set to --storage-version (the internal schema we use)
 			Codec: v1beta1.Codec,
 		},
@@ -628,31 +1274,49 @@ func (c *MasterConfig) RunBuildController() {
 			Image:                stiImage,
 			TempDirectoryCreator: buildstrategy.STITempDirectoryCreator,
 			UseLocalImages:       useLocalImages,
+			AllowedBuilderImages: c.AllowedBuilderImages,
+			SourceSecretsDir:     c.BuildSourceSecretsDir,
 			// TODO: this will be set to --storage-version (the internal schema we use)
 			Codec: v1beta1.Codec,
 		},
 		CustomBuildStrategy: &buildstrategy.CustomBuildStrategy{
-			UseLocalImages: useLocalImages,
+			UseLocalImages:   useLocalImages,
+			SourceSecretsDir: c.BuildSourceSecretsDir,
 			// TODO: this will be set to --storage-version (the internal schema we use)
 			Codec: v1beta1.Codec,
 		},
+		Informers: c.Informers,
+		Metrics:   c.buildMetrics,
+		Stop:      stop,
 	}
 
-	controller := factory.Create()
+	controller, podController := factory.Create(), factory.CreatePodController()
+	controller.Synced, podController.Synced = synced, synced
+	controller.Crashed, podController.Crashed = crashed, crashed
 	controller.Run()
+	podController.Run()
 }
 
 // RunDeploymentController starts the build image change trigger controller process.
-func (c *MasterConfig) RunBuildImageChangeTriggerController() {
+func (c *MasterConfig) RunBuildImageChangeTriggerController(stop <-chan struct{}, synced func(), crashed func(interface{})) {
 	bcClient, _ := c.BuildControllerClients()
 	bcUpdater := buildclient.NewOSClientBuildConfigClient(bcClient)
 	bCreator := buildclient.NewOSClientBuildClient(bcClient)
-	factory := buildcontrollerfactory.ImageChangeControllerFactory{Client: bcClient, BuildCreator: bCreator, BuildConfigUpdater: bcUpdater}
-	factory.Create().Run()
+	factory := buildcontrollerfactory.ImageChangeControllerFactory{
+		Client:             bcClient,
+		BuildCreator:       bCreator,
+		BuildConfigUpdater: bcUpdater,
+		Informers:          c.Informers,
+		Stop:               stop,
+	}
+	controller := factory.Create()
+	controller.Synced = synced
+	controller.Crashed = crashed
+	controller.Run()
 }
 
 // RunDeploymentController starts the deployment controller process.
-func (c *MasterConfig) RunDeploymentController() {
+func (c *MasterConfig) RunDeploymentController(stop <-chan struct{}, synced func(), crashed func(interface{})) {
 	osclient, kclient := c.DeploymentControllerClients()
 	factory := deploycontrollerfactory.DeploymentControllerFactory{
 		Client:     osclient,
@@ -664,44 +1328,177 @@ func (c *MasterConfig) RunDeploymentController() {
 		},
 		UseLocalImages:        c.UseLocalImages,
 		RecreateStrategyImage: c.ImageFor("deployer"),
+		Informers:             c.Informers,
+		Stop:                  stop,
 	}
 
 	envvars := clientcmd.EnvVarsFromConfig(c.DeployerClientConfig())
 	factory.Environment = append(factory.Environment, envvars...)
 
-	controller := factory.Create()
+	controller, podController := factory.Create(), factory.CreatePodController()
+	controller.Synced, podController.Synced = synced, synced
+	controller.Crashed, podController.Crashed = crashed, crashed
 	controller.Run()
+	podController.Run()
 }
 
-func (c *MasterConfig) RunDeploymentConfigController() {
+func (c *MasterConfig) RunDeploymentConfigController(stop <-chan struct{}, synced func(), crashed func(interface{})) {
 	osclient, kclient := c.DeploymentConfigControllerClients()
 	factory := deploycontrollerfactory.DeploymentConfigControllerFactory{
 		Client:     osclient,
 		KubeClient: kclient,
 		Codec:      latest.Codec,
+		Informers:  c.Informers,
+		Stop:       stop,
 	}
 	controller := factory.Create()
+	controller.Synced = synced
+	controller.Crashed = crashed
 	controller.Run()
 }
 
-func (c *MasterConfig) RunDeploymentConfigChangeController() {
+func (c *MasterConfig) RunDeploymentConfigChangeController(stop <-chan struct{}, synced func(), crashed func(interface{})) {
 	osclient, kclient := c.DeploymentConfigChangeControllerClients()
 	factory := deploycontrollerfactory.DeploymentConfigChangeControllerFactory{
 		Client:     osclient,
 		KubeClient: kclient,
 		Codec:      latest.Codec,
+		Informers:  c.Informers,
+		Stop:       stop,
 	}
 	controller := factory.Create()
+	controller.Synced = synced
+	controller.Crashed = crashed
 	controller.Run()
 }
 
-func (c *MasterConfig) RunDeploymentImageChangeTriggerController() {
+func (c *MasterConfig) RunDeploymentImageChangeTriggerController(stop <-chan struct{}, synced func(), crashed func(interface{})) {
 	osclient := c.DeploymentImageChangeControllerClient()
-	factory := deploycontrollerfactory.ImageChangeControllerFactory{Client: osclient}
+	factory := deploycontrollerfactory.ImageChangeControllerFactory{Client: osclient, Informers: c.Informers, Stop: stop}
 	controller := factory.Create()
+	controller.Synced = synced
+	controller.Crashed = crashed
+	controller.Run()
+}
+
+// RunDeploymentAutoscaleController starts the controller that scales the latest deployments of
+// DeploymentConfigs with autoscaling parameters according to the CPU usage cAdvisor reports for their pods.
+func (c *MasterConfig) RunDeploymentAutoscaleController(stop <-chan struct{}, synced func(), crashed func(interface{})) {
+	osClient, kubeClient := c.DeploymentAutoscaleControllerClients()
+	controller := &deploycontroller.AutoscaleController{
+		Client:   deploycontroller.NewAutoscaleClient(osClient, kubeClient),
+		CPUUsage: deploycontroller.NewCadvisorCPUUsage(&kclient.HTTPContainerInfoGetter{Client: http.DefaultClient, Port: kubernetes.NodePort}),
+		Period:   30 * time.Second,
+		Synced:   synced,
+		Crashed:  crashed,
+		Stop:     stop,
+	}
+	controller.Run()
+}
+
+// RunDeploymentDriftController starts the controller that reports, or reverts, changes made to the pod
+// templates of deployments outside of their DeploymentConfigs.
+func (c *MasterConfig) RunDeploymentDriftController(stop <-chan struct{}, synced func(), crashed func(interface{})) {
+	osClient, kubeClient := c.DeploymentDriftControllerClients()
+	controller := &deploycontroller.DriftController{
+		Client:   deploycontroller.NewDriftClient(osClient, kubeClient),
+		Codec:    latest.Codec,
+		Recorder: eventrecord.NewRecorder(),
+		Period:   time.Minute,
+		Synced:   synced,
+		Crashed:  crashed,
+		Stop:     stop,
+	}
+	controller.Run()
+}
+
+// RunRouteCertificateExpiryController starts the controller that reports the routes whose certificates
+// expire within RouteCertificateExpiryWindow.
+func (c *MasterConfig) RunRouteCertificateExpiryController(stop <-chan struct{}, synced func(), crashed func(interface{})) {
+	controller := &routecontroller.CertificateExpiryController{
+		Client:   c.RouteCertificateExpiryControllerClient(),
+		Recorder: eventrecord.NewRecorder(),
+		Window:   c.RouteCertificateExpiryWindow,
+		Period:   time.Hour,
+		Synced:   synced,
+		Crashed:  crashed,
+		Stop:     stop,
+		Metrics:  c.routeCertificateMetrics,
+	}
+	controller.Run()
+}
+
+// RunQuotaUsageController starts the controller that recalculates the usage of OpenShift resources in project quotas.
+func (c *MasterConfig) RunQuotaUsageController(stop <-chan struct{}, synced func(), crashed func(interface{})) {
+	osclient, kclient := c.ResourceQuotaClients()
+	controller := &quotacontroller.UsageController{
+		Client:  kclient,
+		Usage:   quota.NewUsageFuncs(osclient, time.Now),
+		Period:  30 * time.Second,
+		Synced:  synced,
+		Crashed: crashed,
+		Stop:    stop,
+	}
 	controller.Run()
 }
 
+// RunProjectFinalizerController starts the controller that deletes the contents of terminating projects
+// and then removes the projects.
+func (c *MasterConfig) RunProjectFinalizerController(stop <-chan struct{}, synced func(), crashed func(interface{})) {
+	projectEtcd := projectetcd.New(c.EtcdHelper)
+	finalizer := &projectcontroller.ProjectFinalizer{
+		Client: c.ProjectFinalizerClient(),
+		Remove: func(name string) error {
+			return projectEtcd.DeleteProject(kapi.NewContext(), name)
+		},
+		Period:  10 * time.Second,
+		Synced:  synced,
+		Crashed: crashed,
+		Stop:    stop,
+	}
+	finalizer.Run()
+}
+
+// RunGarbageCollectorController starts the controller that deletes the objects whose owners have been
+// deleted, in the OpenShift and Kubernetes APIs.
+func (c *MasterConfig) RunGarbageCollectorController(stop <-chan struct{}, synced func(), crashed func(interface{})) {
+	osClient, kubeClient := c.GarbageCollectorClients()
+	rcReaper, err := kubectl.ReaperFor("ReplicationController", kubeClient)
+	if err != nil {
+		glog.Fatalf("Unable to stop replication controllers: %v", err)
+	}
+	collector := &gc.Collector{
+		Mapper:      latest.RESTMapper,
+		Clients:     clientForMapping(osClient, kubeClient),
+		Relations:   gc.DefaultRelations(),
+		Inventories: gc.DefaultInventories(),
+		Reapers:     map[string]kubectl.Reaper{"ReplicationController": rcReaper},
+		Period:      time.Minute,
+		Synced:      synced,
+		Crashed:     crashed,
+		Stop:        stop,
+	}
+	collector.Impersonate = func(user string) (*gc.Collector, error) {
+		// system users are not in the user registry and cannot be impersonated, the objects the master
+		// components create are deleted with the permissions of the collector
+		if strings.HasPrefix(user, "system:") {
+			return collector, nil
+		}
+		userOSClient := &osclient.Client{RESTClient: impersonatingRESTClient(osClient.RESTClient, user)}
+		userKubeClient := &kclient.Client{RESTClient: impersonatingRESTClient(kubeClient.RESTClient, user)}
+		userRCReaper, err := kubectl.ReaperFor("ReplicationController", userKubeClient)
+		if err != nil {
+			return nil, err
+		}
+		return &gc.Collector{
+			Mapper:  collector.Mapper,
+			Clients: clientForMapping(userOSClient, userKubeClient),
+			Reapers: map[string]kubectl.Reaper{"ReplicationController": userRCReaper},
+		}, nil
+	}
+	collector.Run()
+}
+
 // ensureCORSAllowedOrigins takes a string list of origins and attempts to covert them to CORS origin
 // regexes, or exits if it cannot.
 func (c *MasterConfig) ensureCORSAllowedOrigins() []*regexp.Regexp {
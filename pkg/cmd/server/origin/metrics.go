@@ -0,0 +1,389 @@
+package origin
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/coreos/go-etcd/etcd"
+
+	"github.com/openshift/origin/pkg/api/latest"
+)
+
+// metricsPath is where the master serves its metrics
+const metricsPath = "/metrics"
+
+// requestKey identifies the requests a metric is recorded for.
+type requestKey struct {
+	verb     string
+	resource string
+}
+
+// labels returns the metric labels identifying k.
+func (k requestKey) labels() string {
+	return fmt.Sprintf("verb=%q,resource=%q", k.verb, k.resource)
+}
+
+// responseKey identifies the responses a request count is recorded for.
+type responseKey struct {
+	requestKey
+	code int
+}
+
+// duration accumulates the latency of a set of operations.
+type duration struct {
+	count int64
+	sum   time.Duration
+}
+
+// add records an operation that took elapsed.
+func (d *duration) add(elapsed time.Duration) {
+	d.count++
+	d.sum += elapsed
+}
+
+// Metrics records the requests served by the master API and the operations it performs against etcd, and
+// serves them in the Prometheus text format.
+type Metrics struct {
+	lock sync.Mutex
+	// requests is the number of completed requests by verb, resource, and response code
+	requests map[responseKey]int64
+	// requestDurations is the latency of completed requests by verb and resource
+	requestDurations map[requestKey]*duration
+	// inFlight is the number of requests being served by verb and resource
+	inFlight map[requestKey]int64
+	// etcdDurations is the latency of etcd operations by operation
+	etcdDurations map[string]*duration
+	// etcdErrors is the number of etcd operations that failed by operation, not counting missing keys and
+	// conflicts
+	etcdErrors map[string]int64
+	// etcdCalls holds the most recent etcd operations so they can be reported with slow requests
+	etcdCalls *etcdJournal
+	// slowRequests is the number of requests that exceeded the slow request threshold by verb and resource
+	slowRequests map[requestKey]int64
+	// deprecatedRequests is the number of requests that used a deprecated API version or field
+	deprecatedRequests map[deprecationKey]int64
+	// collectors write the metrics of other components of the master, such as its controllers
+	collectors []func(io.Writer)
+}
+
+// NewMetrics returns an empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests:         make(map[responseKey]int64),
+		requestDurations: make(map[requestKey]*duration),
+		inFlight:         make(map[requestKey]int64),
+		etcdDurations:    make(map[string]*duration),
+		etcdErrors:       make(map[string]int64),
+		etcdCalls:        newEtcdJournal(maxEtcdJournalCalls),
+		slowRequests:     make(map[requestKey]int64),
+
+		deprecatedRequests: make(map[deprecationKey]int64),
+	}
+}
+
+// InstrumentHandler returns a handler that records the count, latency, and concurrency of the requests
+// served by handler.
+func (m *Metrics) InstrumentHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		key := requestKeyFor(req)
+		start := time.Now()
+		m.lock.Lock()
+		m.inFlight[key]++
+		m.lock.Unlock()
+
+		delegate := &responseWriterDelegator{ResponseWriter: w}
+		defer func() {
+			elapsed := time.Since(start)
+			m.lock.Lock()
+			defer m.lock.Unlock()
+			m.inFlight[key]--
+			m.requests[responseKey{key, delegate.statusCode()}]++
+			if _, ok := m.requestDurations[key]; !ok {
+				m.requestDurations[key] = &duration{}
+			}
+			m.requestDurations[key].add(elapsed)
+		}()
+		handler.ServeHTTP(delegate, req)
+	})
+}
+
+// InstrumentEtcd returns an etcd client that records the latency and failures of the operations made
+// through client.
+func (m *Metrics) InstrumentEtcd(client tools.EtcdGetSet) tools.EtcdGetSet {
+	return &instrumentedEtcdClient{client, m}
+}
+
+// observeEtcd records an etcd operation on key that started at start and returned err.
+func (m *Metrics) observeEtcd(operation, key string, start time.Time, err error) {
+	elapsed := time.Since(start)
+	m.etcdCalls.record(etcdCall{operation: operation, key: key, start: start, duration: elapsed, err: err})
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.etcdDurations[operation]; !ok {
+		m.etcdDurations[operation] = &duration{}
+	}
+	m.etcdDurations[operation].add(elapsed)
+	if err != nil && !tools.IsEtcdNotFound(err) && !tools.IsEtcdNodeExist(err) && !tools.IsEtcdTestFailed(err) {
+		m.etcdErrors[operation]++
+	}
+}
+
+// AddCollector adds a function that writes metrics in the Prometheus text format to the metrics served.
+func (m *Metrics) AddCollector(collector func(io.Writer)) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.collectors = append(m.collectors, collector)
+}
+
+// Handler returns a handler serving the recorded metrics.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+		m.lock.Lock()
+		defer m.lock.Unlock()
+
+		responses := []responseKey{}
+		for key := range m.requests {
+			responses = append(responses, key)
+		}
+		sort.Sort(byResponse(responses))
+		fmt.Fprintf(w, "# HELP master_http_requests_total The number of requests served by the master API.\n")
+		fmt.Fprintf(w, "# TYPE master_http_requests_total counter\n")
+		for _, key := range responses {
+			fmt.Fprintf(w, "master_http_requests_total{%s,code=\"%d\"} %d\n", key.labels(), key.code, m.requests[key])
+		}
+
+		fmt.Fprintf(w, "# HELP master_http_request_duration_seconds The latency of requests served by the master API.\n")
+		fmt.Fprintf(w, "# TYPE master_http_request_duration_seconds summary\n")
+		requests := []requestKey{}
+		for key := range m.requestDurations {
+			requests = append(requests, key)
+		}
+		sort.Sort(byRequest(requests))
+		for _, key := range requests {
+			d := m.requestDurations[key]
+			fmt.Fprintf(w, "master_http_request_duration_seconds_sum{%s} %g\n", key.labels(), d.sum.Seconds())
+			fmt.Fprintf(w, "master_http_request_duration_seconds_count{%s} %d\n", key.labels(), d.count)
+		}
+
+		fmt.Fprintf(w, "# HELP master_http_slow_requests_total The number of requests served by the master API that exceeded the slow request threshold.\n")
+		fmt.Fprintf(w, "# TYPE master_http_slow_requests_total counter\n")
+		slow := []requestKey{}
+		for key := range m.slowRequests {
+			slow = append(slow, key)
+		}
+		sort.Sort(byRequest(slow))
+		for _, key := range slow {
+			fmt.Fprintf(w, "master_http_slow_requests_total{%s} %d\n", key.labels(), m.slowRequests[key])
+		}
+
+		fmt.Fprintf(w, "# HELP master_http_deprecated_requests_total The number of requests served by the master API that used a deprecated API version or field.\n")
+		fmt.Fprintf(w, "# TYPE master_http_deprecated_requests_total counter\n")
+		deprecated := []deprecationKey{}
+		for key := range m.deprecatedRequests {
+			deprecated = append(deprecated, key)
+		}
+		sort.Sort(byDeprecation(deprecated))
+		for _, key := range deprecated {
+			fmt.Fprintf(w, "master_http_deprecated_requests_total{version=%q,resource=%q,field=%q} %d\n", key.version, key.resource, key.field, m.deprecatedRequests[key])
+		}
+
+		fmt.Fprintf(w, "# HELP master_http_requests_in_flight The number of requests being served by the master API.\n")
+		fmt.Fprintf(w, "# TYPE master_http_requests_in_flight gauge\n")
+		inFlight := []requestKey{}
+		for key := range m.inFlight {
+			inFlight = append(inFlight, key)
+		}
+		sort.Sort(byRequest(inFlight))
+		for _, key := range inFlight {
+			fmt.Fprintf(w, "master_http_requests_in_flight{%s} %d\n", key.labels(), m.inFlight[key])
+		}
+
+		operations := []string{}
+		for operation := range m.etcdDurations {
+			operations = append(operations, operation)
+		}
+		sort.Strings(operations)
+		fmt.Fprintf(w, "# HELP master_etcd_request_duration_seconds The latency of etcd operations made by the master.\n")
+		fmt.Fprintf(w, "# TYPE master_etcd_request_duration_seconds summary\n")
+		for _, operation := range operations {
+			d := m.etcdDurations[operation]
+			fmt.Fprintf(w, "master_etcd_request_duration_seconds_sum{operation=%q} %g\n", operation, d.sum.Seconds())
+			fmt.Fprintf(w, "master_etcd_request_duration_seconds_count{operation=%q} %d\n", operation, d.count)
+		}
+		fmt.Fprintf(w, "# HELP master_etcd_request_errors_total The number of etcd operations made by the master that failed.\n")
+		fmt.Fprintf(w, "# TYPE master_etcd_request_errors_total counter\n")
+		for _, operation := range operations {
+			fmt.Fprintf(w, "master_etcd_request_errors_total{operation=%q} %d\n", operation, m.etcdErrors[operation])
+		}
+
+		for _, collector := range m.collectors {
+			collector(w)
+		}
+	})
+}
+
+// metricVerbs are the verbs requests are recorded by.  Requests with any other method are recorded as "other".
+var metricVerbs = map[string]bool{
+	"get": true, "list": true, "watch": true, "create": true, "update": true, "delete": true,
+	"proxy": true, "redirect": true, "post": true, "put": true, "patch": true, "head": true, "options": true,
+}
+
+// requestKeyFor returns the verb and resource of req.  Requests outside of the API are recorded by method
+// with an empty resource.  Only the verbs in metricVerbs and the resources the API serves are recorded, so
+// clients cannot add metrics by requesting arbitrary paths or methods.
+func requestKeyFor(req *http.Request) requestKey {
+	verb, resource, _, _ := requestAttributes(req)
+	if !metricVerbs[verb] {
+		verb = "other"
+	}
+	if len(resource) > 0 {
+		if _, _, err := latest.RESTMapper.VersionAndKindForResource(resource); err != nil {
+			resource = ""
+		}
+	}
+	return requestKey{verb, resource}
+}
+
+// byRequest sorts request keys by verb and resource so metrics are written in a stable order.
+type byRequest []requestKey
+
+func (k byRequest) Len() int      { return len(k) }
+func (k byRequest) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+func (k byRequest) Less(i, j int) bool {
+	if k[i].verb != k[j].verb {
+		return k[i].verb < k[j].verb
+	}
+	return k[i].resource < k[j].resource
+}
+
+// byResponse sorts response keys by verb, resource, and code so metrics are written in a stable order.
+type byResponse []responseKey
+
+func (k byResponse) Len() int      { return len(k) }
+func (k byResponse) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+func (k byResponse) Less(i, j int) bool {
+	if k[i].requestKey != k[j].requestKey {
+		return byRequest{k[i].requestKey, k[j].requestKey}.Less(0, 1)
+	}
+	return k[i].code < k[j].code
+}
+
+// byDeprecation sorts deprecation keys by version, resource, and field so metrics are written in a stable order.
+type byDeprecation []deprecationKey
+
+func (k byDeprecation) Len() int      { return len(k) }
+func (k byDeprecation) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+func (k byDeprecation) Less(i, j int) bool {
+	if k[i].version != k[j].version {
+		return k[i].version < k[j].version
+	}
+	if k[i].resource != k[j].resource {
+		return k[i].resource < k[j].resource
+	}
+	return k[i].field < k[j].field
+}
+
+// responseWriterDelegator records the status code written to a ResponseWriter.  Flushing, hijacking, and
+// close notification are passed through so watches and proxied connections keep working.
+type responseWriterDelegator struct {
+	http.ResponseWriter
+	code int
+}
+
+func (w *responseWriterDelegator) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriterDelegator) Write(data []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseWriterDelegator) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *responseWriterDelegator) CloseNotify() <-chan bool {
+	if notifier, ok := w.ResponseWriter.(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+	return make(chan bool)
+}
+
+func (w *responseWriterDelegator) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("the response does not support hijacking")
+	}
+	if w.code == 0 {
+		w.code = http.StatusSwitchingProtocols
+	}
+	return hijacker.Hijack()
+}
+
+// statusCode returns the status code written, which is 200 if the handler wrote nothing.
+func (w *responseWriterDelegator) statusCode() int {
+	if w.code == 0 {
+		return http.StatusOK
+	}
+	return w.code
+}
+
+// instrumentedEtcdClient records the latency of the operations made through an etcd client.  Watches are
+// long running and are not recorded.
+type instrumentedEtcdClient struct {
+	tools.EtcdGetSet
+	metrics *Metrics
+}
+
+func (c *instrumentedEtcdClient) Get(key string, sort, recursive bool) (*etcd.Response, error) {
+	start := time.Now()
+	resp, err := c.EtcdGetSet.Get(key, sort, recursive)
+	c.metrics.observeEtcd("get", key, start, err)
+	return resp, err
+}
+
+func (c *instrumentedEtcdClient) Set(key, value string, ttl uint64) (*etcd.Response, error) {
+	start := time.Now()
+	resp, err := c.EtcdGetSet.Set(key, value, ttl)
+	c.metrics.observeEtcd("set", key, start, err)
+	return resp, err
+}
+
+func (c *instrumentedEtcdClient) Create(key, value string, ttl uint64) (*etcd.Response, error) {
+	start := time.Now()
+	resp, err := c.EtcdGetSet.Create(key, value, ttl)
+	c.metrics.observeEtcd("create", key, start, err)
+	return resp, err
+}
+
+func (c *instrumentedEtcdClient) CompareAndSwap(key, value string, ttl uint64, prevValue string, prevIndex uint64) (*etcd.Response, error) {
+	start := time.Now()
+	resp, err := c.EtcdGetSet.CompareAndSwap(key, value, ttl, prevValue, prevIndex)
+	c.metrics.observeEtcd("compare_and_swap", key, start, err)
+	return resp, err
+}
+
+func (c *instrumentedEtcdClient) Delete(key string, recursive bool) (*etcd.Response, error) {
+	start := time.Now()
+	resp, err := c.EtcdGetSet.Delete(key, recursive)
+	c.metrics.observeEtcd("delete", key, start, err)
+	return resp, err
+}
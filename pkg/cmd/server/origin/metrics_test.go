@@ -0,0 +1,117 @@
+package origin
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+)
+
+func TestMetricsInstrumentHandler(t *testing.T) {
+	metrics := NewMetrics()
+	inFlight := ""
+	handler := metrics.InstrumentHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/osapi/v1beta1/routes/missing" {
+			http.NotFound(w, req)
+			return
+		}
+		if req.URL.Path == "/osapi/v1beta1/builds" {
+			body := httptest.NewRecorder()
+			metrics.Handler().ServeHTTP(body, req)
+			inFlight = body.Body.String()
+		}
+		w.Write([]byte("ok"))
+	}))
+
+	for _, r := range []struct{ method, path string }{
+		{"GET", "/osapi/v1beta1/routes/missing"},
+		{"GET", "/osapi/v1beta1/routes"},
+		{"GET", "/osapi/v1beta1/routes"},
+		{"POST", "/osapi/v1beta1/builds"},
+		{"GET", "/healthz"},
+		{"GET", "/osapi/v1beta1/randomResource1"},
+		{"GET", "/osapi/v1beta1/randomResource2"},
+		{"BREW", "/healthz"},
+		{"GET", "/api/v1beta1/pods"},
+		{"GET", "/osapi/v1beta1/buildConfigs"},
+	} {
+		req, _ := http.NewRequest(r.method, r.path, nil)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if !strings.Contains(inFlight, `master_http_requests_in_flight{verb="create",resource="builds"} 1`) {
+		t.Errorf("Expected the request being served to be in flight, got:\n%s", inFlight)
+	}
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, &http.Request{})
+	out := w.Body.String()
+	for _, expected := range []string{
+		`master_http_requests_total{verb="get",resource="routes",code="200"} 2`,
+		`master_http_requests_total{verb="get",resource="routes",code="404"} 1`,
+		`master_http_requests_total{verb="create",resource="builds",code="200"} 1`,
+		`master_http_requests_total{verb="get",resource="",code="200"} 3`,
+		`master_http_requests_total{verb="other",resource="",code="200"} 1`,
+		`master_http_requests_total{verb="get",resource="pods",code="200"} 1`,
+		`master_http_requests_total{verb="get",resource="buildConfigs",code="200"} 1`,
+		`master_http_request_duration_seconds_count{verb="get",resource="routes"} 3`,
+		`master_http_requests_in_flight{verb="create",resource="builds"} 0`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in metrics, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestMetricsInstrumentEtcd(t *testing.T) {
+	metrics := NewMetrics()
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.ExpectNotFoundGet("/missing")
+	client := metrics.InstrumentEtcd(fakeClient)
+
+	if _, err := client.Set("/key", "value", 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.Get("/key", false, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.Get("/missing", false, false); !tools.IsEtcdNotFound(err) {
+		t.Fatalf("Expected a not found error, got %v", err)
+	}
+	fakeClient.Err = errors.New("connection refused")
+	if _, err := client.Set("/key", "value", 0); err == nil {
+		t.Fatalf("Expected an error")
+	}
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, &http.Request{})
+	out := w.Body.String()
+	for _, expected := range []string{
+		`master_etcd_request_duration_seconds_count{operation="get"} 2`,
+		`master_etcd_request_duration_seconds_count{operation="set"} 2`,
+		`master_etcd_request_errors_total{operation="get"} 0`,
+		`master_etcd_request_errors_total{operation="set"} 1`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in metrics, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestMetricsAddCollector(t *testing.T) {
+	metrics := NewMetrics()
+	metrics.AddCollector(func(w io.Writer) {
+		fmt.Fprintf(w, "openshift_builds{status=\"New\"} 3\n")
+	})
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, &http.Request{})
+	if out := w.Body.String(); !strings.Contains(out, "openshift_builds{status=\"New\"} 3\n") {
+		t.Errorf("Expected the metrics of the collector to be served, got:\n%s", out)
+	}
+}
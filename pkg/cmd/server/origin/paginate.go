@@ -0,0 +1,131 @@
+package origin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	restful "github.com/emicklei/go-restful"
+)
+
+// pager is implemented by the storage of resources that can be listed a page at a time.
+type pager interface {
+	// ListPage returns a page of up to limit objects that match label and field, which continues the list
+	// continueToken was returned with.
+	ListPage(ctx kapi.Context, label, field labels.Selector, limit int, continueToken string) (runtime.Object, error)
+}
+
+// listPageFilter serves the lists of resource a page at a time from storage to clients that send the limit
+// query parameter.  The registry lists each page in key order, and when more objects remain the list holds a
+// continue token, which the client sends as the continue query parameter to get the next page.  Lists
+// without a limit are read whole by the API server.  Pages bound the size of the response, while the
+// registry still reads the whole collection from etcd for each page.  Pages are encoded with codec, and the self links of
+// their objects name the API at selfLinkPrefix.
+func listPageFilter(storage pager, resource string, codec runtime.Codec, selfLinker runtime.SelfLinker, selfLinkPrefix string) restful.FilterFunction {
+	return func(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
+		query := req.Request.URL.Query()
+		if len(query.Get("limit")) == 0 {
+			chain.ProcessFilter(req, res)
+			return
+		}
+		w := res.ResponseWriter
+
+		limit, err := strconv.Atoi(query.Get("limit"))
+		if err != nil || limit <= 0 {
+			writeAPIError(kerrors.NewBadRequest(fmt.Sprintf("limit must be a positive integer, got %q", query.Get("limit"))), codec, w)
+			return
+		}
+		label, err := labels.ParseSelector(query.Get("labels"))
+		if err != nil {
+			writeAPIError(kerrors.NewBadRequest(err.Error()), codec, w)
+			return
+		}
+		field, err := labels.ParseSelector(query.Get("fields"))
+		if err != nil {
+			writeAPIError(kerrors.NewBadRequest(err.Error()), codec, w)
+			return
+		}
+		namespace := query.Get("namespace")
+
+		list, err := storage.ListPage(kapi.WithNamespace(kapi.NewContext(), namespace), label, field, limit, query.Get("continue"))
+		if err != nil {
+			writeAPIError(err, codec, w)
+			return
+		}
+		if err := setPageSelfLinks(list, selfLinker, path.Join(selfLinkPrefix, resource), namespace); err != nil {
+			writeAPIError(err, codec, w)
+			return
+		}
+		writeAPIObject(http.StatusOK, codec, list, w)
+	}
+}
+
+// setPageSelfLinks sets the self links of list and its objects, which are listed at collection.
+func setPageSelfLinks(list runtime.Object, selfLinker runtime.SelfLinker, collection, namespace string) error {
+	if err := selfLinker.SetSelfLink(list, selfLink(collection, namespace)); err != nil {
+		return err
+	}
+	items, err := runtime.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		name, err := selfLinker.Name(item)
+		if err != nil {
+			return err
+		}
+		itemNamespace, err := selfLinker.Namespace(item)
+		if err != nil {
+			return err
+		}
+		if err := selfLinker.SetSelfLink(item, selfLink(path.Join(collection, name), itemNamespace)); err != nil {
+			return err
+		}
+	}
+	return runtime.SetList(list, items)
+}
+
+// selfLink returns the self link of the object at path in namespace, which the API names in the namespace
+// query parameter.
+func selfLink(path, namespace string) string {
+	link := url.URL{Path: path}
+	if len(namespace) > 0 {
+		link.RawQuery = url.Values{"namespace": []string{namespace}}.Encode()
+	}
+	return link.String()
+}
+
+// writeAPIObject writes obj encoded with codec as the response, labeled as JSON as the API server labels its
+// responses.  JSON is indented as the API server indents it.
+func writeAPIObject(code int, codec runtime.Codec, obj runtime.Object, w http.ResponseWriter) {
+	data, err := codec.Encode(obj)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to encode the response: %v", err), http.StatusInternalServerError)
+		return
+	}
+	indented := &bytes.Buffer{}
+	if err := json.Indent(indented, data, "", "  "); err == nil {
+		data = indented.Bytes()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+// writeAPIError writes err as the status the API server responds with.
+func writeAPIError(err error, codec runtime.Codec, w http.ResponseWriter) {
+	status := kapi.Status{Status: kapi.StatusFailure, Code: http.StatusInternalServerError, Reason: kapi.StatusReasonUnknown, Message: err.Error()}
+	if statusErr, ok := err.(*kerrors.StatusError); ok {
+		status = statusErr.Status()
+		status.Status = kapi.StatusFailure
+	}
+	writeAPIObject(status.Code, codec, &status, w)
+}
@@ -0,0 +1,96 @@
+package origin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/emicklei/go-restful"
+
+	"github.com/openshift/origin/pkg/api/latest"
+	"github.com/openshift/origin/pkg/api/v1beta1"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+// fakePager returns a page of two images named after the namespace, label, field and token it is asked for.
+type fakePager struct {
+	limit int
+}
+
+func (p *fakePager) ListPage(ctx kapi.Context, label, field labels.Selector, limit int, continueToken string) (runtime.Object, error) {
+	if continueToken == "bad" {
+		return nil, kerrors.NewBadRequest("the continue token is invalid, list again without it")
+	}
+	p.limit = limit
+	namespace := kapi.Namespace(ctx)
+	return &imageapi.ImageList{
+		Continue: "next",
+		Items: []imageapi.Image{
+			{ObjectMeta: kapi.ObjectMeta{Name: label.String(), Namespace: namespace}},
+			{ObjectMeta: kapi.ObjectMeta{Name: field.String() + continueToken, Namespace: namespace}},
+		},
+	}, nil
+}
+
+func TestListPageFilter(t *testing.T) {
+	storage := &fakePager{}
+	listed := false
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/images").Filter(listPageFilter(storage, "images", v1beta1.Codec, latest.SelfLinker, "/osapi/v1beta1")).To(func(req *restful.Request, res *restful.Response) {
+		listed = true
+	}))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	// lists without a limit are read whole
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/images?namespace=ns", nil)
+	container.ServeHTTP(w, req)
+	if !listed {
+		t.Errorf("expected a list without a limit to be served by the API server")
+	}
+
+	listed = false
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/images?namespace=ns&limit=2&labels=a%3Db&fields=c%3Dd&continue=1", nil)
+	container.ServeHTTP(w, req)
+	if listed || w.Code != http.StatusOK || storage.limit != 2 {
+		t.Fatalf("expected the page to be served by the filter, got %d %s", w.Code, w.Body.String())
+	}
+	obj, err := v1beta1.Codec.Decode(w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	list := obj.(*imageapi.ImageList)
+	if list.Continue != "next" || list.SelfLink != "/osapi/v1beta1/images?namespace=ns" {
+		t.Errorf("unexpected list: %#v", list)
+	}
+	if len(list.Items) != 2 || list.Items[0].Name != "a=b" || list.Items[1].Name != "c=d1" || list.Items[1].SelfLink != "/osapi/v1beta1/images/c=d1?namespace=ns" {
+		t.Errorf("unexpected items: %#v", list.Items)
+	}
+
+	testCases := map[string]string{
+		"negative limit": "/images?limit=-1",
+		"bad limit":      "/images?limit=x",
+		"bad labels":     "/images?limit=2&labels=a%3Db%3Dc",
+		"bad token":      "/images?limit=2&continue=bad",
+	}
+	for name, path := range testCases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		container.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected a bad request, got %d %s", name, w.Code, w.Body.String())
+			continue
+		}
+		if obj, err := v1beta1.Codec.Decode(w.Body.Bytes()); err != nil {
+			t.Errorf("%s: expected a status, got %v", name, err)
+		} else if status, ok := obj.(*kapi.Status); !ok || status.Status != kapi.StatusFailure {
+			t.Errorf("%s: expected a failure status, got %#v", name, obj)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package origin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+	"github.com/openshift/origin/pkg/util/patch"
+)
+
+// maxPatchAttempts is how many times a patch is applied to a newer version of the object when the object
+// changes between reading and updating it.
+const maxPatchAttempts = 5
+
+// patchers apply the patch types clients may send, by Content-Type.
+var patchers = map[string]func(original, patch []byte) ([]byte, error){
+	"application/merge-patch+json":           patch.MergePatch,
+	"application/strategic-merge-patch+json": patch.StrategicMergePatch,
+	"application/json-patch+json":            patch.JSONPatch,
+}
+
+// patchFilter serves PATCH requests for OpenShift API objects, so clients can change a field without
+// reading and updating the whole object themselves.  The object is read and updated through handler with
+// the context of the request, so the client must be allowed to get and update it.  If the object changes
+// between the two the patch is applied again to the new version, unless the patch sets the resourceVersion
+// itself.  The filter must run after the request is authenticated.
+func patchFilter(handler http.Handler, contexts *authcontext.RequestContextMapper) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "PATCH" || !strings.HasPrefix(req.URL.Path, OpenShiftAPIPrefix+"/") {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		if _, _, _, name := requestAttributes(req); len(name) == 0 {
+			http.Error(w, "only named objects can be patched", http.StatusMethodNotAllowed)
+			return
+		}
+		contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		apply, ok := patchers[contentType]
+		if !ok {
+			http.Error(w, fmt.Sprintf("the patch Content-Type %q is not supported, use application/merge-patch+json, application/strategic-merge-patch+json or application/json-patch+json", contentType), http.StatusUnsupportedMediaType)
+			return
+		}
+		if req.Body == nil {
+			http.Error(w, "the patch is empty", http.StatusBadRequest)
+			return
+		}
+		ctx, ok := contexts.Get(req)
+		if !ok {
+			http.Error(w, "The request has no context", http.StatusInternalServerError)
+			return
+		}
+		body, ok := readRequestBody(w, req)
+		if !ok {
+			return
+		}
+
+		for attempt := 1; ; attempt++ {
+			current := httptest.NewRecorder()
+			contexts.ServeWithContext(current, patchSubrequest(req, "GET", nil), ctx, handler)
+			if current.Code != http.StatusOK {
+				writeRecorded(w, current, current.Body.Bytes())
+				return
+			}
+			original := current.Body.Bytes()
+			patched, err := apply(original, body)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("unable to apply the patch: %v", err), http.StatusUnprocessableEntity)
+				return
+			}
+
+			updated := httptest.NewRecorder()
+			contexts.ServeWithContext(updated, patchSubrequest(req, "PUT", patched), ctx, handler)
+			if updated.Code == http.StatusConflict && attempt < maxPatchAttempts && reflect.DeepEqual(resourceVersion(original), resourceVersion(patched)) {
+				continue
+			}
+			writeRecorded(w, updated, updated.Body.Bytes())
+			return
+		}
+	})
+}
+
+// maxRequestBodyBytes is the largest request body the filters that read it before the API server accept.
+const maxRequestBodyBytes = 3 * 1024 * 1024
+
+// readRequestBody reads and closes the body of req and returns true.  If the body cannot be read or is larger
+// than maxRequestBodyBytes the request is rejected and false is returned.
+func readRequestBody(w http.ResponseWriter, req *http.Request) ([]byte, bool) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxRequestBodyBytes))
+	req.Body.Close()
+	if err != nil && len(body) >= maxRequestBodyBytes {
+		http.Error(w, fmt.Sprintf("the request body is larger than %d bytes", maxRequestBodyBytes), http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to read the request body: %v", err), http.StatusBadRequest)
+		return nil, false
+	}
+	return body, true
+}
+
+// writeRecorded writes the status and headers of recorder to w with body in place of the recorded body.
+func writeRecorded(w http.ResponseWriter, recorder *httptest.ResponseRecorder, body []byte) {
+	for key, values := range recorder.HeaderMap {
+		w.Header()[key] = values
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(recorder.Code)
+	w.Write(body)
+}
+
+// patchSubrequest returns a JSON request with method and body for the object req patches.
+func patchSubrequest(req *http.Request, method string, body []byte) *http.Request {
+	sub := *req
+	sub.Method = method
+	url := *req.URL
+	sub.URL = &url
+	sub.Header = http.Header{}
+	for k, v := range req.Header {
+		sub.Header[k] = v
+	}
+	sub.Header.Set("Accept", "application/json")
+	sub.Header.Del("Content-Type")
+	sub.Body, sub.ContentLength = nil, 0
+	if body != nil {
+		sub.Header.Set("Content-Type", "application/json")
+		sub.Body = ioutil.NopCloser(bytes.NewReader(body))
+		sub.ContentLength = int64(len(body))
+	}
+	return &sub
+}
+
+// resourceVersion returns the resourceVersion of the JSON object in data, which is a field of the object
+// or of its metadata depending on the API version.
+func resourceVersion(data []byte) interface{} {
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil
+	}
+	if meta, ok := obj["metadata"].(map[string]interface{}); ok {
+		if version, ok := meta["resourceVersion"]; ok {
+			return version
+		}
+	}
+	return obj["resourceVersion"]
+}
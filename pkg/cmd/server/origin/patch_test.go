@@ -0,0 +1,154 @@
+package origin
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+)
+
+// routeStorage serves a route over GET and PUT, failing the first conflicts updates.
+type routeStorage struct {
+	route     string
+	conflicts int
+	puts      int
+}
+
+func (s *routeStorage) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case "GET":
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(s.route))
+	case "PUT":
+		s.puts++
+		if s.conflicts > 0 {
+			s.conflicts--
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		s.route = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestPatchFilter(t *testing.T) {
+	testCases := map[string]struct {
+		path        string
+		contentType string
+		body        string
+		conflicts   int
+		code        int
+		route       string
+		puts        int
+	}{
+		"merge patch": {
+			path:        "/osapi/v1beta1/routes/ruby?namespace=test",
+			contentType: "application/merge-patch+json",
+			body:        `{"host":"ruby.example.com"}`,
+			code:        http.StatusOK,
+			route:       `{"host":"ruby.example.com","resourceVersion":"1"}`,
+			puts:        1,
+		},
+		"json patch": {
+			path:        "/osapi/v1beta1/routes/ruby?namespace=test",
+			contentType: "application/json-patch+json",
+			body:        `[{"op":"replace","path":"/host","value":"ruby.example.com"}]`,
+			code:        http.StatusOK,
+			route:       `{"host":"ruby.example.com","resourceVersion":"1"}`,
+			puts:        1,
+		},
+		"retried conflict": {
+			path:        "/osapi/v1beta1/routes/ruby?namespace=test",
+			contentType: "application/strategic-merge-patch+json",
+			body:        `{"host":"ruby.example.com"}`,
+			conflicts:   2,
+			code:        http.StatusOK,
+			route:       `{"host":"ruby.example.com","resourceVersion":"1"}`,
+			puts:        3,
+		},
+		"conflict with the patch resourceVersion": {
+			path:        "/osapi/v1beta1/routes/ruby?namespace=test",
+			contentType: "application/merge-patch+json",
+			body:        `{"host":"ruby.example.com","resourceVersion":"0"}`,
+			conflicts:   1,
+			code:        http.StatusConflict,
+			route:       `{"host":"www.example.com","resourceVersion":"1"}`,
+			puts:        1,
+		},
+		"persistent conflict": {
+			path:        "/osapi/v1beta1/routes/ruby?namespace=test",
+			contentType: "application/merge-patch+json",
+			body:        `{"host":"ruby.example.com"}`,
+			conflicts:   maxPatchAttempts,
+			code:        http.StatusConflict,
+			route:       `{"host":"www.example.com","resourceVersion":"1"}`,
+			puts:        maxPatchAttempts,
+		},
+		"failed test": {
+			path:        "/osapi/v1beta1/routes/ruby?namespace=test",
+			contentType: "application/json-patch+json",
+			body:        `[{"op":"test","path":"/host","value":"ruby.example.com"}]`,
+			code:        http.StatusUnprocessableEntity,
+			route:       `{"host":"www.example.com","resourceVersion":"1"}`,
+		},
+		"unsupported patch": {
+			path:        "/osapi/v1beta1/routes/ruby?namespace=test",
+			contentType: "application/json",
+			body:        `{"host":"ruby.example.com"}`,
+			code:        http.StatusUnsupportedMediaType,
+			route:       `{"host":"www.example.com","resourceVersion":"1"}`,
+		},
+		"collection": {
+			path:        "/osapi/v1beta1/routes?namespace=test",
+			contentType: "application/merge-patch+json",
+			body:        `{"host":"ruby.example.com"}`,
+			code:        http.StatusMethodNotAllowed,
+			route:       `{"host":"www.example.com","resourceVersion":"1"}`,
+		},
+		"outside the API": {
+			path:        "/api/v1beta1/pods/ruby?namespace=test",
+			contentType: "application/merge-patch+json",
+			body:        `{"host":"ruby.example.com"}`,
+			code:        http.StatusMethodNotAllowed,
+			route:       `{"host":"www.example.com","resourceVersion":"1"}`,
+		},
+		"too large": {
+			path:        "/osapi/v1beta1/routes/ruby?namespace=test",
+			contentType: "application/merge-patch+json",
+			body:        `{"host":"` + strings.Repeat("a", maxRequestBodyBytes) + `"}`,
+			code:        http.StatusRequestEntityTooLarge,
+			route:       `{"host":"www.example.com","resourceVersion":"1"}`,
+		},
+	}
+	for name, tc := range testCases {
+		storage := &routeStorage{route: `{"host":"www.example.com","resourceVersion":"1"}`, conflicts: tc.conflicts}
+		contexts := authcontext.NewRequestContextMapper()
+		handler := patchFilter(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if user := requestUserName(req, contexts); user != "alice" {
+				t.Errorf("%s: expected the object to be read and updated as the user, got %q", name, user)
+			}
+			storage.ServeHTTP(w, req)
+		}), contexts)
+		req, _ := http.NewRequest("PATCH", tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", tc.contentType)
+		w := httptest.NewRecorder()
+		withUser(contexts, "alice", handler).ServeHTTP(w, req)
+
+		if w.Code != tc.code {
+			t.Errorf("%s: expected %d, got %d: %s", name, tc.code, w.Code, w.Body.String())
+		}
+		if storage.route != tc.route {
+			t.Errorf("%s: expected route %s, got %s", name, tc.route, storage.route)
+		}
+		if storage.puts != tc.puts {
+			t.Errorf("%s: expected %d updates, got %d", name, tc.puts, storage.puts)
+		}
+	}
+}
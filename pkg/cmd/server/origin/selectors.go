@@ -0,0 +1,35 @@
+package origin
+
+import (
+	"net/http"
+)
+
+// selectorParams maps the selector query parameters clients may send to the parameters the API server reads.
+var selectorParams = map[string]string{
+	"labelSelector": "labels",
+	"fieldSelector": "fields",
+}
+
+// selectorParamsFilter lets clients list and watch with the labelSelector and fieldSelector query parameters,
+// in addition to labels and fields.  A parameter already set under the name the API server reads wins.
+func selectorParamsFilter(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		changed := false
+		for from, to := range selectorParams {
+			value, ok := query[from]
+			if !ok {
+				continue
+			}
+			if _, exists := query[to]; !exists {
+				query[to] = value
+			}
+			query.Del(from)
+			changed = true
+		}
+		if changed {
+			req.URL.RawQuery = query.Encode()
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,47 @@
+package origin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestSelectorParamsFilter(t *testing.T) {
+	testCases := map[string]struct {
+		query    string
+		expected url.Values
+	}{
+		"no selectors": {"", url.Values{}},
+		"aliases": {
+			"labelSelector=app%3Dfrontend&fieldSelector=status%3DRunning",
+			url.Values{"labels": {"app=frontend"}, "fields": {"status=Running"}},
+		},
+		"existing parameter wins": {
+			"labels=app%3Dbackend&labelSelector=app%3Dfrontend",
+			url.Values{"labels": {"app=backend"}},
+		},
+		"other parameters kept": {
+			"resourceVersion=5&fieldSelector=name%3Dfoo",
+			url.Values{"resourceVersion": {"5"}, "fields": {"name=foo"}},
+		},
+	}
+
+	for name, tc := range testCases {
+		var got url.Values
+		handler := selectorParamsFilter(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			got = req.URL.Query()
+		}))
+		req, _ := http.NewRequest("GET", "/osapi/v1beta1/builds?"+tc.query, nil)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if len(got) != len(tc.expected) {
+			t.Errorf("%s: expected %v, got %v", name, tc.expected, got)
+			continue
+		}
+		for key, values := range tc.expected {
+			if got.Get(key) != values[0] {
+				t.Errorf("%s: expected %s=%s, got %v", name, key, values[0], got)
+			}
+		}
+	}
+}
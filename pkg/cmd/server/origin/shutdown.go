@@ -0,0 +1,120 @@
+package origin
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// gracefulServer serves HTTP on a listener and drains the connections it accepted when stopped.
+type gracefulServer struct {
+	server *http.Server
+
+	lock sync.Mutex
+	// listener is the listener being served, nil until Serve is called
+	listener net.Listener
+	// conns holds the state of every open connection
+	conns map[net.Conn]http.ConnState
+	// stopping is true once Stop has been called
+	stopping bool
+}
+
+// newGracefulServer returns a gracefulServer for server.  The ConnState hook of server is replaced.
+func newGracefulServer(server *http.Server) *gracefulServer {
+	s := &gracefulServer{
+		server: server,
+		conns:  make(map[net.Conn]http.ConnState),
+	}
+	server.ConnState = s.trackConn
+	return s
+}
+
+// Serve accepts connections on listener until Stop is called, in which case nil is returned.
+func (s *gracefulServer) Serve(listener net.Listener) error {
+	s.lock.Lock()
+	if s.stopping {
+		s.lock.Unlock()
+		listener.Close()
+		return nil
+	}
+	s.listener = listener
+	s.lock.Unlock()
+
+	err := s.server.Serve(listener)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.stopping {
+		return nil
+	}
+	return err
+}
+
+// Serving returns true while the server is accepting connections.
+func (s *gracefulServer) Serving() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.listener != nil && !s.stopping
+}
+
+// Stop stops accepting connections, closes idle connections, and waits up to gracePeriod for in flight
+// requests to complete.  Connections still open after gracePeriod are closed and an error is returned.
+func (s *gracefulServer) Stop(gracePeriod time.Duration) error {
+	s.lock.Lock()
+	if s.stopping {
+		s.lock.Unlock()
+		return nil
+	}
+	s.stopping = true
+	s.server.SetKeepAlivesEnabled(false)
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	for conn, state := range s.conns {
+		if state != http.StateActive {
+			conn.Close()
+		}
+	}
+	s.lock.Unlock()
+
+	deadline := time.Now().Add(gracePeriod)
+	for {
+		s.lock.Lock()
+		open := len(s.conns)
+		if open == 0 {
+			s.lock.Unlock()
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			for conn := range s.conns {
+				conn.Close()
+			}
+			s.lock.Unlock()
+			return fmt.Errorf("closed %d connections that were still active after %v", open, gracePeriod)
+		}
+		s.lock.Unlock()
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// trackConn records the state of conn, closing it once it is idle if the server is stopping.
+func (s *gracefulServer) trackConn(conn net.Conn, state http.ConnState) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch state {
+	case http.StateHijacked, http.StateClosed:
+		delete(s.conns, conn)
+	case http.StateIdle:
+		if s.stopping {
+			conn.Close()
+			delete(s.conns, conn)
+			return
+		}
+		s.conns[conn] = state
+	default:
+		s.conns[conn] = state
+	}
+}
@@ -0,0 +1,90 @@
+package origin
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// startGracefulServer serves handler on a local port and returns the server, its address, and a channel
+// that receives the result of Serve.
+func startGracefulServer(t *testing.T, handler http.Handler) (*gracefulServer, string, chan error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	server := newGracefulServer(&http.Server{Handler: handler})
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(listener)
+	}()
+	return server, listener.Addr().String(), served
+}
+
+func TestGracefulServerDrainsRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server, addr, served := startGracefulServer(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("done"))
+	}))
+
+	responses := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/")
+		if err != nil {
+			responses <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		responses <- string(body)
+	}()
+	<-started
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- server.Stop(5 * time.Second)
+	}()
+
+	// the listener is closed while the request is in flight
+	time.Sleep(100 * time.Millisecond)
+	if conn, err := net.DialTimeout("tcp", addr, time.Second); err == nil {
+		conn.Close()
+		t.Errorf("Expected new connections to be refused while draining")
+	}
+
+	close(release)
+	if body := <-responses; body != "done" {
+		t.Errorf("Expected the request in flight to complete, got %q", body)
+	}
+	if err := <-stopped; err != nil {
+		t.Errorf("Unexpected error stopping: %v", err)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("Expected Serve to return nil after Stop, got %v", err)
+	}
+}
+
+func TestGracefulServerGracePeriod(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	server, addr, served := startGracefulServer(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		close(started)
+		<-release
+	}))
+
+	go http.Get("http://" + addr + "/")
+	<-started
+
+	if err := server.Stop(100 * time.Millisecond); err == nil {
+		t.Errorf("Expected an error when requests are still active after the grace period")
+	}
+	if err := <-served; err != nil {
+		t.Errorf("Expected Serve to return nil after Stop, got %v", err)
+	}
+}
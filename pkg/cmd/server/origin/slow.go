@@ -0,0 +1,125 @@
+package origin
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const (
+	// maxEtcdJournalCalls is the number of recent etcd operations kept to report with slow requests
+	maxEtcdJournalCalls = 1000
+	// maxSlowRequestEtcdCalls is the number of etcd operations logged for a single slow request, slowest first
+	maxSlowRequestEtcdCalls = 10
+)
+
+// etcdCall is a completed etcd operation.
+type etcdCall struct {
+	operation string
+	key       string
+	start     time.Time
+	duration  time.Duration
+	err       error
+}
+
+// etcdJournal holds the most recent etcd operations in a fixed size ring.
+type etcdJournal struct {
+	lock  sync.Mutex
+	calls []etcdCall
+	// next is the index the next call is recorded at
+	next int
+}
+
+// newEtcdJournal returns a journal holding up to size calls.
+func newEtcdJournal(size int) *etcdJournal {
+	return &etcdJournal{calls: make([]etcdCall, 0, size)}
+}
+
+// record adds call to the journal, replacing the oldest call if it is full.
+func (j *etcdJournal) record(call etcdCall) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if len(j.calls) < cap(j.calls) {
+		j.calls = append(j.calls, call)
+		return
+	}
+	j.calls[j.next] = call
+	j.next = (j.next + 1) % len(j.calls)
+}
+
+// during returns the recorded calls that started and completed between start and end, slowest first.  Calls
+// made by concurrent requests in the same interval are included, since calls are not tied to a request.
+func (j *etcdJournal) during(start, end time.Time) []etcdCall {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	calls := []etcdCall{}
+	for _, call := range j.calls {
+		if !call.start.Before(start) && !call.start.Add(call.duration).After(end) {
+			calls = append(calls, call)
+		}
+	}
+	sort.Sort(bySlowest(calls))
+	return calls
+}
+
+// bySlowest sorts etcd calls by decreasing duration.
+type bySlowest []etcdCall
+
+func (c bySlowest) Len() int           { return len(c) }
+func (c bySlowest) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c bySlowest) Less(i, j int) bool { return c[i].duration > c[j].duration }
+
+// slowRequestFilter logs every request served by handler that takes longer than threshold, along with the
+// slowest etcd operations made while it was served, and counts it in metrics.  Watches and proxied requests
+// are long running and are not logged.
+func slowRequestFilter(handler http.Handler, threshold time.Duration, metrics *Metrics) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if longRunningRequest(req) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		start := time.Now()
+		delegate := &responseWriterDelegator{ResponseWriter: w}
+		handler.ServeHTTP(delegate, req)
+		end := time.Now()
+		elapsed := end.Sub(start)
+		if elapsed <= threshold {
+			return
+		}
+
+		key := requestKeyFor(req)
+		metrics.lock.Lock()
+		metrics.slowRequests[key]++
+		metrics.lock.Unlock()
+
+		calls := metrics.etcdCalls.during(start, end)
+		glog.Warningf("Slow request: %s %s took %v (code %d, threshold %v)%s", req.Method, req.RequestURI, elapsed, delegate.statusCode(), threshold, formatEtcdCalls(calls))
+	})
+}
+
+// formatEtcdCalls describes up to maxSlowRequestEtcdCalls of calls for a slow request log message.
+func formatEtcdCalls(calls []etcdCall) string {
+	if len(calls) == 0 {
+		return ", no etcd operations"
+	}
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, ", %d etcd operations during the request", len(calls))
+	if len(calls) > maxSlowRequestEtcdCalls {
+		fmt.Fprintf(buf, ", the %d slowest", maxSlowRequestEtcdCalls)
+		calls = calls[:maxSlowRequestEtcdCalls]
+	}
+	buf.WriteString(":")
+	for _, call := range calls {
+		fmt.Fprintf(buf, "\n  %s %s took %v", call.operation, call.key, call.duration)
+		if call.err != nil {
+			fmt.Fprintf(buf, ": %v", call.err)
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,75 @@
+package origin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+)
+
+func TestEtcdJournal(t *testing.T) {
+	journal := newEtcdJournal(3)
+	base := time.Now()
+	for i, key := range []string{"/a", "/b", "/c", "/d"} {
+		journal.record(etcdCall{operation: "get", key: key, start: base.Add(time.Duration(i) * time.Second), duration: time.Duration(i+1) * time.Millisecond})
+	}
+
+	calls := journal.during(base, base.Add(10*time.Second))
+	keys := []string{}
+	for _, call := range calls {
+		keys = append(keys, call.key)
+	}
+	if strings.Join(keys, ",") != "/d,/c,/b" {
+		t.Errorf("Expected the 3 most recent calls slowest first, got %v", keys)
+	}
+
+	calls = journal.during(base.Add(1500*time.Millisecond), base.Add(10*time.Second))
+	if len(calls) != 2 {
+		t.Errorf("Expected only the calls made in the interval, got %#v", calls)
+	}
+}
+
+func TestSlowRequestFilter(t *testing.T) {
+	metrics := NewMetrics()
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.ExpectNotFoundGet("/slow")
+	client := metrics.InstrumentEtcd(fakeClient)
+
+	handler := slowRequestFilter(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/osapi/v1beta1/builds" {
+			client.Get("/slow", false, false)
+			time.Sleep(20 * time.Millisecond)
+		}
+	}), 10*time.Millisecond, metrics)
+
+	for _, path := range []string{"/osapi/v1beta1/builds", "/osapi/v1beta1/routes"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, &http.Request{})
+	if !strings.Contains(w.Body.String(), `master_http_slow_requests_total{verb="get",resource="builds"} 1`) {
+		t.Errorf("Expected the slow request to be counted, got:\n%s", w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), `master_http_slow_requests_total{verb="get",resource="routes"}`) {
+		t.Errorf("Expected the fast request not to be counted, got:\n%s", w.Body.String())
+	}
+}
+
+func TestFormatEtcdCalls(t *testing.T) {
+	if s := formatEtcdCalls(nil); s != ", no etcd operations" {
+		t.Errorf("Unexpected description %q", s)
+	}
+	calls := []etcdCall{}
+	for i := 0; i < maxSlowRequestEtcdCalls+2; i++ {
+		calls = append(calls, etcdCall{operation: "get", key: "/key", duration: time.Millisecond})
+	}
+	s := formatEtcdCalls(calls)
+	if !strings.Contains(s, "12 etcd operations during the request, the 10 slowest:") || strings.Count(s, "\n") != maxSlowRequestEtcdCalls {
+		t.Errorf("Expected the description to be limited to the slowest operations, got %q", s)
+	}
+}
@@ -0,0 +1,237 @@
+package origin
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+
+	authapi "github.com/openshift/origin/pkg/auth/api"
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+	"github.com/openshift/origin/pkg/user"
+)
+
+// requestUsagePath reports the API requests made by each user and OAuth client.  It is installed with the
+// debug endpoints, so reading it requires the debug verb.
+const requestUsagePath = "/debug/requestusage"
+
+const (
+	// requestUsageIdleTimeout is how long the usage of a user or OAuth client is kept after its last request
+	requestUsageIdleTimeout = time.Hour
+	// requestUsagePruneInterval is how often the usage of idle users and OAuth clients is dropped
+	requestUsagePruneInterval = time.Minute
+)
+
+// usageRecord counts the requests of a user or OAuth client.
+type usageRecord struct {
+	Requests    int64     `json:"requests"`
+	Throttled   int64     `json:"throttled"`
+	LastRequest time.Time `json:"lastRequest"`
+}
+
+// tokenBucket allows burst requests at once, refilled at qps.  Tokens are added when the bucket is used, so idle
+// buckets cost nothing.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// full returns true if the bucket has refilled since it was last used, so that it can be replaced by a new
+// bucket.
+func (b *tokenBucket) full(now time.Time, qps float64, burst int) bool {
+	return b.tokens+now.Sub(b.last).Seconds()*qps >= float64(burst)
+}
+
+// take refills the bucket for the time since it was last used, and takes a token if there is one.
+func (b *tokenBucket) take(now time.Time, qps float64, burst int) bool {
+	b.tokens += now.Sub(b.last).Seconds() * qps
+	if b.tokens > float64(burst) {
+		b.tokens = float64(burst)
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// requestUsage counts the API requests of each user and OAuth client, and limits the rate each user may make
+// requests at.  The usage of users and clients that have been idle for requestUsageIdleTimeout is dropped.
+type requestUsage struct {
+	// qps is the rate a user may make requests at, unlimited if zero
+	qps float64
+	// burst is the number of requests a user may make at once above qps
+	burst int
+	// systemQPS is the rate a system user may make requests at instead of qps, unlimited if zero
+	systemQPS float64
+	now       func() time.Time
+
+	lock       sync.Mutex
+	users      map[string]*usageRecord
+	clients    map[string]*usageRecord
+	buckets    map[string]*tokenBucket
+	lastPruned time.Time
+}
+
+// newRequestUsage returns a requestUsage limiting users to qps requests a second with bursts of burst, and
+// system users to systemQPS requests a second.
+func newRequestUsage(qps float64, burst int, systemQPS float64) *requestUsage {
+	if burst < 1 {
+		burst = 1
+	}
+	return &requestUsage{
+		qps:       qps,
+		burst:     burst,
+		systemQPS: systemQPS,
+		now:       time.Now,
+		users:     make(map[string]*usageRecord),
+		clients:   make(map[string]*usageRecord),
+		buckets:   make(map[string]*tokenBucket),
+	}
+}
+
+// admit records a request by user through client, and returns false if user is over its rate.  System users
+// are limited to the rate of system users.
+func (u *requestUsage) admit(user, client string, system bool) bool {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	now := u.now()
+	if now.Sub(u.lastPruned) >= requestUsagePruneInterval {
+		u.prune(now)
+	}
+
+	admitted := true
+	if qps := u.rate(system); qps > 0 {
+		bucket, ok := u.buckets[user]
+		if !ok {
+			bucket = &tokenBucket{tokens: float64(u.burst), last: now}
+			u.buckets[user] = bucket
+		}
+		admitted = bucket.take(now, qps, u.burst)
+	}
+
+	records := []*usageRecord{record(u.users, user)}
+	if len(client) > 0 {
+		records = append(records, record(u.clients, client))
+	}
+	for _, r := range records {
+		r.Requests++
+		r.LastRequest = now
+		if !admitted {
+			r.Throttled++
+		}
+	}
+	return admitted
+}
+
+// rate returns the rate system users, or other users, may make requests at.
+func (u *requestUsage) rate(system bool) float64 {
+	if system {
+		return u.systemQPS
+	}
+	return u.qps
+}
+
+// prune drops the usage of users and clients idle for requestUsageIdleTimeout, and the buckets that have
+// refilled since they were last used, which a new bucket would replace.  Must be called with u.lock held.
+func (u *requestUsage) prune(now time.Time) {
+	u.lastPruned = now
+	for _, records := range []map[string]*usageRecord{u.users, u.clients} {
+		for name, r := range records {
+			if now.Sub(r.LastRequest) >= requestUsageIdleTimeout {
+				delete(records, name)
+			}
+		}
+	}
+	// a bucket is full once it has refilled at the lower of the rates buckets are kept for
+	qps := u.qps
+	if qps == 0 || (u.systemQPS > 0 && u.systemQPS < qps) {
+		qps = u.systemQPS
+	}
+	for name, bucket := range u.buckets {
+		if qps > 0 && bucket.full(now, qps, u.burst) {
+			delete(u.buckets, name)
+		}
+	}
+}
+
+// record returns the record of name in records, adding it if it is missing.
+func record(records map[string]*usageRecord, name string) *usageRecord {
+	r, ok := records[name]
+	if !ok {
+		r = &usageRecord{}
+		records[name] = r
+	}
+	return r
+}
+
+// ServeHTTP writes the usage of every user and OAuth client, and the rate users are limited to, as JSON.
+func (u *requestUsage) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	u.lock.Lock()
+	data, err := json.MarshalIndent(struct {
+		RequestsPerSecond           float64                 `json:"requestsPerSecond"`
+		Burst                       int                     `json:"burst"`
+		SystemUserRequestsPerSecond float64                 `json:"systemUserRequestsPerSecond"`
+		Users                       map[string]*usageRecord `json:"users"`
+		Clients                     map[string]*usageRecord `json:"clients"`
+	}{u.qps, u.burst, u.systemQPS, u.users, u.clients}, "", "  ")
+	u.lock.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// requestUsageFilter records every request served by handler against the user and OAuth client that made it,
+// and rejects the requests of users over their rate with 429.  The user and client are read from the request
+// context, so the filter must run after the request is authenticated.  Unauthenticated requests are recorded
+// and limited by the address of the client, and the users reserved for the system and infrastructure
+// components are limited to the rate of system users.
+func requestUsageFilter(handler http.Handler, usage *requestUsage, contexts *authcontext.RequestContextMapper) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		name, client := "", ""
+		if ctx, ok := contexts.Get(req); ok {
+			if info, ok := authcontext.UserFrom(ctx); ok {
+				name = info.GetName()
+			}
+			client = authcontext.ClientFrom(ctx)
+		}
+		system := false
+		if len(name) == 0 || name == authapi.UnauthenticatedUsername {
+			name = authapi.UnauthenticatedUsername + "@" + remoteHost(req)
+		} else {
+			system = user.IsReservedUserName(name)
+		}
+		if !usage.admit(name, client, system) {
+			glog.V(4).Infof("Rate of requests exceeded, rejecting %s %s from %q", req.Method, req.RequestURI, name)
+			w.Header().Set("Retry-After", retryAfter(usage.rate(system)))
+			http.Error(w, "Too many requests, please try again later.", 429)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// retryAfter returns the seconds a client over qps should wait before it makes another request.
+func retryAfter(qps float64) string {
+	if qps > 0 && qps < 1 {
+		return strconv.Itoa(int(1/qps + 0.5))
+	}
+	return "1"
+}
+
+// remoteHost returns the address of the client that sent req, without its port.
+func remoteHost(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
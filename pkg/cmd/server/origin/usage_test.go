@@ -0,0 +1,176 @@
+package origin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	authapi "github.com/openshift/origin/pkg/auth/api"
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+)
+
+func TestRequestUsageRate(t *testing.T) {
+	now := time.Unix(0, 0)
+	usage := newRequestUsage(2, 3, 0)
+	usage.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if !usage.admit("alice", "", false) {
+			t.Fatalf("Expected request %d of the burst to be admitted", i)
+		}
+	}
+	if usage.admit("alice", "", false) {
+		t.Errorf("Expected a request over the burst to be rejected")
+	}
+	if !usage.admit("bob", "", false) {
+		t.Errorf("Expected other users to have their own rate")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if !usage.admit("alice", "", false) {
+		t.Errorf("Expected a request to be admitted once the bucket refills")
+	}
+	if usage.admit("alice", "", false) {
+		t.Errorf("Expected a single token to be refilled in half a second")
+	}
+
+	now = now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		if !usage.admit("alice", "", false) {
+			t.Fatalf("Expected request %d of the refilled burst to be admitted", i)
+		}
+	}
+	if usage.admit("alice", "", false) {
+		t.Errorf("Expected the bucket to refill no further than the burst")
+	}
+
+	if r := usage.users["alice"]; r.Requests != 10 || r.Throttled != 3 || !r.LastRequest.Equal(now) {
+		t.Errorf("Unexpected usage of alice: %#v", r)
+	}
+}
+
+func TestRequestUsageUnlimited(t *testing.T) {
+	usage := newRequestUsage(0, 0, 0)
+	for i := 0; i < 100; i++ {
+		if !usage.admit("alice", "openshift-web-console", false) {
+			t.Fatalf("Expected request %d to be admitted without a rate", i)
+		}
+	}
+	usage.admit("bob", "openshift-web-console", false)
+	usage.admit("bob", "", false)
+
+	if r := usage.users["alice"]; r.Requests != 100 || r.Throttled != 0 {
+		t.Errorf("Unexpected usage of alice: %#v", r)
+	}
+	if r := usage.clients["openshift-web-console"]; r.Requests != 101 {
+		t.Errorf("Unexpected usage of the console: %#v", r)
+	}
+	if len(usage.clients) != 1 {
+		t.Errorf("Expected requests without a client to be counted only for the user: %#v", usage.clients)
+	}
+}
+
+func TestRequestUsageFilter(t *testing.T) {
+	contexts := authcontext.NewRequestContextMapper()
+	usage := newRequestUsage(0.1, 1, 0)
+	handler := withUser(contexts, "alice", requestUsageFilter(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}), usage, contexts))
+
+	serve := func() *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/osapi/v1beta1/builds", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	if w := serve(); w.Code != http.StatusOK {
+		t.Errorf("Expected the first request to be served, got %d", w.Code)
+	}
+	if w := serve(); w.Code != 429 || w.Header().Get("Retry-After") != "10" {
+		t.Errorf("Expected the second request to be rejected with a Retry-After, got %d %v", w.Code, w.Header())
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", requestUsagePath, nil)
+	usage.ServeHTTP(w, req)
+	if w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected content type: %v", w.Header())
+	}
+	out := struct {
+		RequestsPerSecond float64
+		Burst             int
+		Users             map[string]usageRecord
+		Clients           map[string]usageRecord
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out.RequestsPerSecond != 0.1 || out.Burst != 1 {
+		t.Errorf("Unexpected rate: %#v", out)
+	}
+	if r := out.Users["alice"]; r.Requests != 2 || r.Throttled != 1 {
+		t.Errorf("Unexpected usage of alice: %#v", out.Users)
+	}
+}
+
+func TestRequestUsagePrune(t *testing.T) {
+	now := time.Unix(0, 0)
+	usage := newRequestUsage(1, 2, 0)
+	usage.now = func() time.Time { return now }
+
+	usage.admit("alice", "openshift-web-console", false)
+	usage.admit("alice", "openshift-web-console", false)
+	now = now.Add(30 * time.Minute)
+	usage.admit("bob", "", false)
+	if len(usage.buckets) != 1 {
+		t.Errorf("Expected the refilled bucket of alice to be dropped: %#v", usage.buckets)
+	}
+	if len(usage.users) != 2 || len(usage.clients) != 1 {
+		t.Errorf("Expected recent usage to be kept: %#v %#v", usage.users, usage.clients)
+	}
+
+	now = now.Add(time.Hour)
+	usage.admit("carol", "", false)
+	if _, ok := usage.users["carol"]; !ok || len(usage.users) != 1 || len(usage.clients) != 0 {
+		t.Errorf("Expected the usage of idle users and clients to be dropped: %#v %#v", usage.users, usage.clients)
+	}
+}
+
+func TestRequestUsageFilterUsers(t *testing.T) {
+	contexts := authcontext.NewRequestContextMapper()
+	usage := newRequestUsage(0.1, 1, 0)
+	serve := func(user, remoteAddr string) int {
+		handler := withUser(contexts, user, requestUsageFilter(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}), usage, contexts))
+		req, _ := http.NewRequest("GET", "/osapi/v1beta1/builds", nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// unauthenticated clients are limited by their address
+	if code := serve(authapi.UnauthenticatedUsername, "10.0.0.1:1234"); code != http.StatusOK {
+		t.Errorf("Expected the first anonymous request to be served, got %d", code)
+	}
+	if code := serve(authapi.UnauthenticatedUsername, "10.0.0.2:1234"); code != http.StatusOK {
+		t.Errorf("Expected an anonymous request from another client to be served, got %d", code)
+	}
+	if code := serve(authapi.UnauthenticatedUsername, "10.0.0.1:5678"); code != 429 {
+		t.Errorf("Expected the second anonymous request of a client to be rejected, got %d", code)
+	}
+	if _, ok := usage.users["system:anonymous@10.0.0.2"]; !ok {
+		t.Errorf("Expected anonymous usage to be recorded by address: %#v", usage.users)
+	}
+
+	// system users are not limited without a rate of their own
+	for i := 0; i < 3; i++ {
+		if code := serve("system:node:node1", "10.0.0.3:1234"); code != http.StatusOK {
+			t.Errorf("Expected request %d of a system user to be served, got %d", i, code)
+		}
+	}
+	usage.systemQPS = 0.1
+	serve("system:node:node2", "10.0.0.3:1234")
+	if code := serve("system:node:node2", "10.0.0.3:1234"); code != 429 {
+		t.Errorf("Expected a system user over the system user rate to be rejected, got %d", code)
+	}
+}
@@ -0,0 +1,335 @@
+package origin
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/golang/glog"
+	"golang.org/x/net/websocket"
+
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+	osclient "github.com/openshift/origin/pkg/client"
+)
+
+// watchMuxPath is where web socket clients watch any number of resources over a single connection
+const watchMuxPath = "/ws/watch"
+
+const (
+	// watchMuxProtocol is the subprotocol the server accepts when a client offers it.  Browsers that offer a
+	// bearer token as a subprotocol must offer it too, since they expect one of their subprotocols back.
+	watchMuxProtocol = "watch.openshift.io"
+	// bearerProtocolPrefix prefixes the base64url encoded bearer token a browser offers as a subprotocol, since
+	// browsers cannot set the headers of a web socket and tokens in the URL end up in access logs
+	bearerProtocolPrefix = "base64url.bearer.authorization.openshift.io."
+)
+
+const (
+	// watchMuxMaxConnections is the number of web sockets served at once
+	watchMuxMaxConnections = 1000
+	// watchMuxMaxConnectionsPerUser is the number of web sockets served at once for a single user
+	watchMuxMaxConnectionsPerUser = 10
+)
+
+// watchClosed is the type of the event sent when a watch ends, after which the client may start it again
+const watchClosed watch.EventType = "CLOSED"
+
+// watchMuxRequest is sent by the client to start or stop a watch.
+type watchMuxRequest struct {
+	// ID identifies the watch in the events sent for it, and in the request to stop it
+	ID string `json:"id"`
+	// Stop ends the watch with ID instead of starting one
+	Stop bool `json:"stop,omitempty"`
+
+	// API is "openshift" to watch an OpenShift resource, or "kubernetes" to watch a Kubernetes resource
+	API             string `json:"api,omitempty"`
+	Resource        string `json:"resource,omitempty"`
+	Namespace       string `json:"namespace,omitempty"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+	Labels          string `json:"labels,omitempty"`
+	Fields          string `json:"fields,omitempty"`
+}
+
+// watchMuxEvent is sent to the client for every event of a watch.  An event of type ERROR without an object
+// reports a watch that could not be started or stopped.
+type watchMuxEvent struct {
+	ID     string          `json:"id"`
+	Type   watch.EventType `json:"type"`
+	Object json.RawMessage `json:"object,omitempty"`
+	Error  string          `json:"error,omitempty"`
+
+	// source is the watch the event was received from, if any
+	source watch.Interface
+}
+
+// watchFunc starts the watch described by req with the credentials of the user holding token, and returns
+// the codec to encode its objects with.
+type watchFunc func(token string, req *watchMuxRequest) (watch.Interface, runtime.Codec, error)
+
+// watchMux multiplexes watches over a web socket, for clients like the web console that cannot rely on long
+// running HTTP requests through proxies.  It is served behind the same filters as the API, so the web socket
+// is authenticated and rate limited like any other request.  Each watch is made against the API with the
+// bearer token the web socket was opened with, so it is authorized as if the client made it directly.
+type watchMux struct {
+	watch    watchFunc
+	limiter  *inFlightLimiter
+	contexts *authcontext.RequestContextMapper
+}
+
+// newWatchMux returns a watchMux that serves at most watchMuxMaxConnections web sockets at once, and at most
+// watchMuxMaxConnectionsPerUser for the user in the request context of each.
+func newWatchMux(watch watchFunc, contexts *authcontext.RequestContextMapper) *watchMux {
+	return &watchMux{
+		watch:    watch,
+		limiter:  &inFlightLimiter{max: watchMuxMaxConnections, maxPerUser: watchMuxMaxConnectionsPerUser, perUser: make(map[string]int)},
+		contexts: contexts,
+	}
+}
+
+// ServeHTTP upgrades requests that carry a bearer token to a web socket and serves watches over it.
+func (m *watchMux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	token := bearerToken(req)
+	if len(token) == 0 {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="OpenShift"`)
+		http.Error(w, "A bearer token is required", http.StatusUnauthorized)
+		return
+	}
+	user := requestUserName(req, m.contexts)
+	if !m.limiter.acquire(user) {
+		glog.V(4).Infof("Too many web sockets open, rejecting %s from %q", req.RequestURI, user)
+		w.Header().Set("Retry-After", inFlightRetryAfterSeconds)
+		http.Error(w, "Too many connections, please try again later.", 429)
+		return
+	}
+	defer m.limiter.release(user)
+
+	websocket.Server{
+		Handler: func(ws *websocket.Conn) {
+			m.serve(ws, token)
+		},
+		Handshake: watchMuxHandshake,
+	}.ServeHTTP(w, req)
+}
+
+// watchMuxHandshake requires a valid origin, like the handshake of websocket.Handler, and accepts
+// watchMuxProtocol if the client offered it.  The bearer token a client offers is never echoed back.
+func watchMuxHandshake(config *websocket.Config, req *http.Request) error {
+	origin, err := websocket.Origin(config, req)
+	if err != nil {
+		return err
+	}
+	if origin == nil {
+		return fmt.Errorf("null origin")
+	}
+	config.Origin = origin
+
+	offered := config.Protocol
+	config.Protocol = nil
+	for _, protocol := range offered {
+		if protocol == watchMuxProtocol {
+			config.Protocol = []string{watchMuxProtocol}
+		}
+	}
+	return nil
+}
+
+// bearerToken returns the token in the Authorization header of req.
+func bearerToken(req *http.Request) string {
+	authorization := strings.TrimSpace(req.Header.Get("Authorization"))
+	if len(authorization) > 7 && strings.ToLower(authorization[:7]) == "bearer " {
+		return strings.TrimSpace(authorization[7:])
+	}
+	return ""
+}
+
+// bearerProtocolFilter moves the bearer token a web socket client offers as a subprotocol prefixed with
+// bearerProtocolPrefix into the Authorization header, so the request is authenticated like any other.  Requests
+// that already carry an Authorization header are left alone.  The filter must run before authentication.
+func bearerProtocolFilter(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != watchMuxPath || len(req.Header.Get("Authorization")) > 0 {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		protocols := []string{}
+		token := ""
+		for _, header := range req.Header[http.CanonicalHeaderKey("Sec-WebSocket-Protocol")] {
+			for _, protocol := range strings.Split(header, ",") {
+				protocol = strings.TrimSpace(protocol)
+				if !strings.HasPrefix(protocol, bearerProtocolPrefix) {
+					protocols = append(protocols, protocol)
+					continue
+				}
+				encoded := strings.TrimPrefix(protocol, bearerProtocolPrefix)
+				if n := len(encoded) % 4; n != 0 {
+					encoded += strings.Repeat("=", 4-n)
+				}
+				decoded, err := base64.URLEncoding.DecodeString(encoded)
+				if err != nil {
+					http.Error(w, "The bearer token subprotocol is not base64url encoded", http.StatusBadRequest)
+					return
+				}
+				token = string(decoded)
+			}
+		}
+		if len(token) > 0 {
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Del("Sec-WebSocket-Protocol")
+			if len(protocols) > 0 {
+				req.Header.Set("Sec-WebSocket-Protocol", strings.Join(protocols, ", "))
+			}
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// serve starts and stops the watches the client asks for and forwards their events until the client goes
+// away.  Every watch is stopped when serve returns.
+func (m *watchMux) serve(ws *websocket.Conn, token string) {
+	defer ws.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	events := make(chan watchMuxEvent)
+	requests := make(chan watchMuxRequest)
+	go func() {
+		defer close(requests)
+		for {
+			req := watchMuxRequest{}
+			if err := websocket.JSON.Receive(ws, &req); err != nil {
+				return
+			}
+			select {
+			case requests <- req:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	watches := map[string]watch.Interface{}
+	defer func() {
+		for _, w := range watches {
+			w.Stop()
+		}
+	}()
+
+	for {
+		var event watchMuxEvent
+		select {
+		case req, ok := <-requests:
+			if !ok {
+				return
+			}
+			existing, exists := watches[req.ID]
+			switch {
+			case req.Stop && !exists:
+				event = watchMuxEvent{ID: req.ID, Type: watch.Error, Error: fmt.Sprintf("watch %q does not exist", req.ID)}
+			case req.Stop:
+				existing.Stop()
+				delete(watches, req.ID)
+				continue
+			case exists:
+				event = watchMuxEvent{ID: req.ID, Type: watch.Error, Error: fmt.Sprintf("watch %q already exists", req.ID)}
+			default:
+				w, codec, err := m.watch(token, &req)
+				if err != nil {
+					event = watchMuxEvent{ID: req.ID, Type: watch.Error, Error: err.Error()}
+					break
+				}
+				watches[req.ID] = w
+				go forwardWatch(req.ID, w, codec, events, done)
+				continue
+			}
+		case event = <-events:
+			if watches[event.ID] != event.source {
+				// the watch was stopped by the client, which expects no more events from it
+				continue
+			}
+			if event.Type == watchClosed {
+				delete(watches, event.ID)
+			}
+		}
+
+		if err := websocket.JSON.Send(ws, event); err != nil {
+			glog.V(4).Infof("Unable to send a watch event to %s: %v", ws.Request().RemoteAddr, err)
+			return
+		}
+	}
+}
+
+// forwardWatch sends the events of w to events, followed by a closed event once w ends.
+func forwardWatch(id string, w watch.Interface, codec runtime.Codec, events chan<- watchMuxEvent, done <-chan struct{}) {
+	for e := range w.ResultChan() {
+		event := watchMuxEvent{ID: id, Type: e.Type, source: w}
+		data, err := codec.Encode(e.Object)
+		if err != nil {
+			event.Type = watch.Error
+			event.Error = fmt.Sprintf("unable to encode the object: %v", err)
+		} else {
+			event.Object = data
+		}
+		select {
+		case events <- event:
+		case <-done:
+			return
+		}
+	}
+	select {
+	case events <- watchMuxEvent{ID: id, Type: watchClosed, source: w}:
+	case <-done:
+	}
+}
+
+// userClientConfig returns the configuration of a client that calls host with token.  The server is verified
+// with the CA the system clients trust, but none of their credentials are used.
+func (c *MasterConfig) userClientConfig(host, token string) *kclient.Config {
+	return &kclient.Config{
+		Host:        host,
+		BearerToken: token,
+		TLSClientConfig: kclient.TLSClientConfig{
+			CAFile: c.OSClientConfig.CAFile,
+			CAData: c.OSClientConfig.CAData,
+		},
+		Insecure: c.OSClientConfig.Insecure,
+	}
+}
+
+// watchAsUser starts a watch against the OpenShift or Kubernetes API with the credentials of a user.
+func (c *MasterConfig) watchAsUser(token string, req *watchMuxRequest) (watch.Interface, runtime.Codec, error) {
+	var client *kclient.RESTClient
+	switch req.API {
+	case "openshift":
+		osClient, err := osclient.New(c.userClientConfig(c.MasterAddr, token))
+		if err != nil {
+			return nil, nil, err
+		}
+		client = osClient.RESTClient
+	case "kubernetes":
+		kubeClient, err := kclient.New(c.userClientConfig(c.KubernetesAddr, token))
+		if err != nil {
+			return nil, nil, err
+		}
+		client = kubeClient.RESTClient
+	default:
+		return nil, nil, fmt.Errorf("unknown API %q, must be openshift or kubernetes", req.API)
+	}
+
+	w, err := client.Get().
+		Prefix("watch").
+		Namespace(req.Namespace).
+		Resource(req.Resource).
+		Param("resourceVersion", req.ResourceVersion).
+		ParseSelectorParam("labels", req.Labels).
+		ParseSelectorParam("fields", req.Fields).
+		Watch()
+	if err != nil {
+		return nil, nil, err
+	}
+	return w, client.Codec, nil
+}
@@ -0,0 +1,138 @@
+package origin
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	klatest "github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"golang.org/x/net/websocket"
+
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+)
+
+func TestWatchMuxRequiresToken(t *testing.T) {
+	server := httptest.NewServer(bearerProtocolFilter(newWatchMux(nil, authcontext.NewRequestContextMapper())))
+	defer server.Close()
+
+	for _, path := range []string{watchMuxPath, watchMuxPath + "?access_token=secret"} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s: expected %d, got %d", path, http.StatusUnauthorized, resp.StatusCode)
+		}
+	}
+}
+
+func TestWatchMuxLimitsConnections(t *testing.T) {
+	mux := newWatchMux(func(token string, req *watchMuxRequest) (watch.Interface, runtime.Codec, error) {
+		return watch.NewFake(), klatest.Codec, nil
+	}, authcontext.NewRequestContextMapper())
+	mux.limiter.maxPerUser = 1
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	config, _ := websocket.NewConfig(strings.Replace(server.URL, "http", "ws", 1)+watchMuxPath, "http://localhost/")
+	config.Header = http.Header{"Authorization": {"Bearer secret"}}
+	ws, err := websocket.DialConfig(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer ws.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+watchMuxPath, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 429 {
+		t.Errorf("expected the second web socket of the user to be rejected, got %d", resp.StatusCode)
+	}
+}
+
+func TestWatchMux(t *testing.T) {
+	type started struct {
+		token   string
+		req     watchMuxRequest
+		watcher *watch.FakeWatcher
+	}
+	watches := make(chan started, 10)
+	mux := newWatchMux(func(token string, req *watchMuxRequest) (watch.Interface, runtime.Codec, error) {
+		if req.Resource == "unknown" {
+			return nil, nil, errors.New("unknown resource")
+		}
+		w := watch.NewFake()
+		watches <- started{token, *req, w}
+		return w, klatest.Codec, nil
+	}, authcontext.NewRequestContextMapper())
+	server := httptest.NewServer(bearerProtocolFilter(mux))
+	defer server.Close()
+
+	// browsers offer the token as a subprotocol, which is never accepted
+	config, _ := websocket.NewConfig(strings.Replace(server.URL, "http", "ws", 1)+watchMuxPath, "http://localhost/")
+	config.Protocol = []string{watchMuxProtocol, bearerProtocolPrefix + strings.TrimRight(base64.URLEncoding.EncodeToString([]byte("secret")), "=")}
+	ws, err := websocket.DialConfig(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer ws.Close()
+
+	send := func(req watchMuxRequest) {
+		if err := websocket.JSON.Send(ws, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	receive := func() watchMuxEvent {
+		event := watchMuxEvent{}
+		if err := websocket.JSON.Receive(ws, &event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return event
+	}
+
+	send(watchMuxRequest{ID: "pods", API: "kubernetes", Resource: "pods", Namespace: "test"})
+	pods := <-watches
+	if pods.token != "secret" || pods.req.Resource != "pods" || pods.req.Namespace != "test" {
+		t.Errorf("unexpected watch: %#v", pods)
+	}
+	go pods.watcher.Add(&kapi.Pod{ObjectMeta: kapi.ObjectMeta{Name: "pod-1", Namespace: "test"}})
+	if event := receive(); event.ID != "pods" || event.Type != watch.Added || !strings.Contains(string(event.Object), "pod-1") {
+		t.Errorf("unexpected event: %#v", event)
+	}
+
+	send(watchMuxRequest{ID: "pods", API: "kubernetes", Resource: "pods"})
+	if event := receive(); event.ID != "pods" || event.Type != watch.Error || !strings.Contains(event.Error, "already exists") {
+		t.Errorf("unexpected event: %#v", event)
+	}
+	send(watchMuxRequest{ID: "other", API: "kubernetes", Resource: "unknown"})
+	if event := receive(); event.ID != "other" || event.Type != watch.Error || event.Error != "unknown resource" {
+		t.Errorf("unexpected event: %#v", event)
+	}
+
+	// a watch stopped by the client ends without an event, one that ends on its own is reported closed
+	send(watchMuxRequest{ID: "pods", Stop: true})
+	send(watchMuxRequest{ID: "builds", API: "openshift", Resource: "builds"})
+	builds := <-watches
+	if !pods.watcher.Stopped {
+		t.Errorf("expected the stopped watch to be stopped")
+	}
+	builds.watcher.Stop()
+	if event := receive(); event.ID != "builds" || event.Type != watchClosed {
+		t.Errorf("unexpected event: %#v", event)
+	}
+	send(watchMuxRequest{ID: "builds", Stop: true})
+	if event := receive(); event.ID != "builds" || event.Type != watch.Error || !strings.Contains(event.Error, "does not exist") {
+		t.Errorf("unexpected event: %#v", event)
+	}
+}
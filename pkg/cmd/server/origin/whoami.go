@@ -0,0 +1,72 @@
+package origin
+
+import (
+	"encoding/json"
+	"net/http"
+
+	authenticationapi "github.com/openshift/origin/pkg/auth/api"
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+	"github.com/openshift/origin/pkg/authorization/authorizer"
+)
+
+// userContextHandler describes the user a request is made as in a single call for the CLI and console login
+// flows: its name, UID and groups, the scopes and client of its token, the namespaces its credential is limited
+// to, and the roles it is bound to in masterNamespace and in the namespace named by the namespace query
+// parameter, if any.  Roles are only read for those namespaces so that a call costs the same however many
+// projects exist.  The path is not a standard API path, so any authenticated user may read it.
+func userContextHandler(roles authorizer.RoleLister, masterNamespace string, contexts *authcontext.RequestContextMapper) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "GET" {
+			http.Error(w, "Only GET is allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		ctx, ok := contexts.Get(req)
+		if !ok {
+			http.Error(w, "The request has no context", http.StatusInternalServerError)
+			return
+		}
+		user, ok := authcontext.UserFrom(ctx)
+		if !ok {
+			http.Error(w, "Need to be authenticated to access this method", http.StatusUnauthorized)
+			return
+		}
+
+		out := authenticationapi.UserContext{
+			Name:   user.GetName(),
+			UID:    user.GetUID(),
+			Groups: user.GetGroups(),
+			Scopes: authcontext.ScopesFrom(ctx),
+			Client: authcontext.ClientFrom(ctx),
+			Roles:  map[string][]string{},
+		}
+		if impersonator, ok := authcontext.ImpersonatorFrom(ctx); ok {
+			out.Impersonator = impersonator.GetName()
+		}
+		if namespaces, ok := authenticationapi.LimitedNamespaces(user); ok {
+			out.Namespaces = namespaces
+		}
+
+		list := []string{masterNamespace}
+		if namespace := req.URL.Query().Get("namespace"); len(namespace) > 0 && namespace != masterNamespace {
+			list = append(list, namespace)
+		}
+		for _, namespace := range list {
+			names, err := roles.GetRoleNames(namespace, user)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if len(names) > 0 {
+				out.Roles[namespace] = names
+			}
+		}
+
+		data, err := json.Marshal(out)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
@@ -0,0 +1,99 @@
+package origin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	authenticationapi "github.com/openshift/origin/pkg/auth/api"
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+)
+
+// namespaceRoles lists the roles of every user by namespace.
+type namespaceRoles map[string][]string
+
+func (r namespaceRoles) GetRoleNames(namespace string, user authenticationapi.UserInfo) ([]string, error) {
+	return r[namespace], nil
+}
+
+func TestUserContextHandler(t *testing.T) {
+	contexts := authcontext.NewRequestContextMapper()
+	roles := namespaceRoles{"master": {"self-provisioner"}, "foo": {"admin"}, "bar": {"view"}}
+	handler := withUser(contexts, "alice", userContextHandler(roles, "master", contexts))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", authenticationapi.UserContextPath+"?namespace=foo", nil)
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("Unexpected response: %d %v", w.Code, w.Header())
+	}
+	out := authenticationapi.UserContext{}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := authenticationapi.UserContext{
+		Name:  "alice",
+		Roles: map[string][]string{"master": {"self-provisioner"}, "foo": {"admin"}},
+	}
+	if !reflect.DeepEqual(out, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, out)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", authenticationapi.UserContextPath, nil)
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected only GET to be allowed, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", authenticationapi.UserContextPath, nil)
+	handler.ServeHTTP(w, req)
+	out = authenticationapi.UserContext{}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := map[string][]string{"master": {"self-provisioner"}}; !reflect.DeepEqual(out.Roles, expected) {
+		t.Errorf("Expected only the roles of the master namespace without a namespace, got %v", out.Roles)
+	}
+}
+
+func TestUserContextHandlerUnauthenticated(t *testing.T) {
+	contexts := authcontext.NewRequestContextMapper()
+	handler := authcontext.NewRequestContextFilter(contexts, time.Minute, userContextHandler(namespaceRoles{}, "master", contexts))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", authenticationapi.UserContextPath, nil)
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected requests without a user to be unauthorized, got %d", w.Code)
+	}
+}
+
+func TestUserContextHandlerLimitedNamespaces(t *testing.T) {
+	contexts := authcontext.NewRequestContextMapper()
+	router := &authenticationapi.DefaultUserInfo{
+		Name:   "system:router:shard1",
+		Groups: []string{authenticationapi.RouterTokenGroup},
+		Extra:  map[string]string{authenticationapi.RouterNamespacesExtraKey: "foo,bar"},
+	}
+	handler := authcontext.NewRequestContextFilter(contexts, time.Minute, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, _ := contexts.Get(req)
+		contexts.Update(req, authcontext.WithUser(ctx, router))
+		userContextHandler(namespaceRoles{}, "master", contexts).ServeHTTP(w, req)
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", authenticationapi.UserContextPath, nil)
+	handler.ServeHTTP(w, req)
+	out := authenticationapi.UserContext{}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(out.Namespaces, []string{"foo", "bar"}) {
+		t.Errorf("Expected the namespaces of the router token, got %v", out.Namespaces)
+	}
+}
@@ -0,0 +1,65 @@
+package origin
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/origin/pkg/api/yaml"
+)
+
+// yamlAPIPrefix is where the OpenShift API is installed with the YAML codecs.  Clients never use it directly,
+// yamlRoutingFilter sends requests there.
+const yamlAPIPrefix = "/osapi-yaml"
+
+// yamlRoutingFilter serves OpenShift API requests as YAML to clients that prefer a YAML media type in the
+// Accept header.  Those requests are served by the API installed at yamlAPIPrefix, whose responses are
+// labeled with yaml.MediaType.  Objects sent as YAML are decoded by every version of the API, so only the
+// response decides where a request is served.  Watches are always served as JSON, since their events are
+// framed as JSON.
+func yamlRoutingFilter(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == yamlAPIPrefix || strings.HasPrefix(req.URL.Path, yamlAPIPrefix+"/") {
+			http.NotFound(w, req)
+			return
+		}
+		if !strings.HasPrefix(req.URL.Path, OpenShiftAPIPrefix+"/") || longRunningRequest(req) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		if !acceptsYAML(req.Header.Get("Accept")) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		req.URL.Path = yamlAPIPrefix + strings.TrimPrefix(req.URL.Path, OpenShiftAPIPrefix)
+		handler.ServeHTTP(&mediaTypeResponseWriter{ResponseWriter: w, mediaType: yaml.MediaType}, req)
+	})
+}
+
+// acceptsYAML returns true if the media types in accept prefer YAML to JSON.
+func acceptsYAML(accept string) bool {
+	return yaml.IsMediaType(preferredMediaType(accept))
+}
+
+// preferredMediaType returns the media type in accept the client prefers.  The media type with the highest
+// quality wins, and the first listed of equal quality.
+func preferredMediaType(accept string) string {
+	best, bestQuality := "", 0.0
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(mediaRange)
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality > bestQuality {
+			best, bestQuality = mediaType, quality
+		}
+	}
+	return best
+}
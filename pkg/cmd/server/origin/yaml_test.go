@@ -0,0 +1,109 @@
+package origin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openshift/origin/pkg/api/yaml"
+)
+
+func TestYAMLRoutingFilter(t *testing.T) {
+	served := ""
+	handler := yamlRoutingFilter(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		served = req.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("kind: Build\n"))
+	}))
+
+	testCases := map[string]struct {
+		path        string
+		contentType string
+		accept      string
+		served      string
+		response    string
+		code        int
+	}{
+		"json": {
+			path:     "/osapi/v1beta1/builds",
+			accept:   "application/json",
+			served:   "/osapi/v1beta1/builds",
+			response: "application/json",
+		},
+		"yaml": {
+			path:     "/osapi/v1beta2/builds/ruby",
+			accept:   "application/yaml",
+			served:   "/osapi-yaml/v1beta2/builds/ruby",
+			response: yaml.MediaType,
+		},
+		"json preferred": {
+			path:     "/osapi/v1beta1/builds/ruby",
+			accept:   "application/yaml;q=0.5, application/json",
+			served:   "/osapi/v1beta1/builds/ruby",
+			response: "application/json",
+		},
+		"yaml request": {
+			path:        "/osapi/v1beta1/builds",
+			contentType: "application/x-yaml; charset=utf-8",
+			served:      "/osapi/v1beta1/builds",
+			response:    "application/json",
+		},
+		"watch": {
+			path:     "/osapi/v1beta1/watch/builds",
+			accept:   "application/yaml",
+			served:   "/osapi/v1beta1/watch/builds",
+			response: "application/json",
+		},
+		"outside the API": {
+			path:     "/healthz",
+			accept:   "application/yaml",
+			served:   "/healthz",
+			response: "application/json",
+		},
+		"yaml API": {
+			path: "/osapi-yaml/v1beta1/builds",
+			code: http.StatusNotFound,
+		},
+	}
+	for name, tc := range testCases {
+		served = ""
+		req, _ := http.NewRequest("GET", tc.path, nil)
+		req.Header.Set("Content-Type", tc.contentType)
+		req.Header.Set("Accept", tc.accept)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		code := tc.code
+		if code == 0 {
+			code = http.StatusOK
+		}
+		if w.Code != code {
+			t.Errorf("%s: expected %d, got %d", name, code, w.Code)
+			continue
+		}
+		if served != tc.served {
+			t.Errorf("%s: expected %q to be served, got %q", name, tc.served, served)
+		}
+		if code == http.StatusOK && w.HeaderMap.Get("Content-Type") != tc.response {
+			t.Errorf("%s: expected Content-Type %q, got %q", name, tc.response, w.HeaderMap.Get("Content-Type"))
+		}
+	}
+}
+
+func TestAcceptsYAML(t *testing.T) {
+	testCases := map[string]bool{
+		"":                                       false,
+		"*/*":                                    false,
+		"application/json":                       false,
+		"application/yaml":                       true,
+		"text/yaml, application/json":            true,
+		"application/json, application/yaml":     false,
+		"application/json;q=0.9, text/x-yaml":    true,
+		"application/yaml;q=x, application/json": false,
+	}
+	for accept, expected := range testCases {
+		if actual := acceptsYAML(accept); actual != expected {
+			t.Errorf("%q: expected %t, got %t", accept, expected, actual)
+		}
+	}
+}
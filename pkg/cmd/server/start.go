@@ -2,15 +2,21 @@ package server
 
 import (
 	"crypto/x509"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	_ "net/http/pprof"
+	"net/url"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -22,6 +28,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
 	kmaster "github.com/GoogleCloudPlatform/kubernetes/pkg/master"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	kutil "github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/admission/admit"
 	etcdclient "github.com/coreos/go-etcd/etcd"
 	"github.com/golang/glog"
@@ -29,6 +36,7 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/openshift/origin/pkg/api/latest"
+	"github.com/openshift/origin/pkg/assets"
 	"github.com/openshift/origin/pkg/auth/api"
 	"github.com/openshift/origin/pkg/auth/authenticator"
 	"github.com/openshift/origin/pkg/auth/authenticator/request/bearertoken"
@@ -36,7 +44,10 @@ import (
 	"github.com/openshift/origin/pkg/auth/authenticator/request/unionrequest"
 	"github.com/openshift/origin/pkg/auth/authenticator/request/x509request"
 	"github.com/openshift/origin/pkg/auth/group"
+	"github.com/openshift/origin/pkg/authorization/authorizer"
 	"github.com/openshift/origin/pkg/cmd/flagtypes"
+	configapi "github.com/openshift/origin/pkg/cmd/server/api"
+	configvalidation "github.com/openshift/origin/pkg/cmd/server/api/validation"
 	"github.com/openshift/origin/pkg/cmd/server/crypto"
 	"github.com/openshift/origin/pkg/cmd/server/etcd"
 	"github.com/openshift/origin/pkg/cmd/server/kubernetes"
@@ -44,6 +55,10 @@ import (
 	"github.com/openshift/origin/pkg/cmd/util"
 	"github.com/openshift/origin/pkg/cmd/util/docker"
 	"github.com/openshift/origin/pkg/cmd/util/variable"
+	"github.com/openshift/origin/pkg/election"
+	"github.com/openshift/origin/pkg/project/registry/projectrequest"
+	templateapi "github.com/openshift/origin/pkg/template/api"
+	"github.com/openshift/origin/pkg/user"
 	pkgutil "github.com/openshift/origin/pkg/util"
 )
 
@@ -80,21 +95,26 @@ instance.
 `
 
 const (
-	unauthenticatedUsername = "system:anonymous"
+	unauthenticatedUsername = api.UnauthenticatedUsername
 
 	authenticatedGroup   = "system:authenticated"
-	unauthenticatedGroup = "system:unauthenticated"
+	unauthenticatedGroup = api.UnauthenticatedGroup
+
+	// servingCertCheckPeriod is how often the master checks whether its server certificate needs to be renewed
+	servingCertCheckPeriod = time.Hour
 )
 
 // config is a struct that the command stores flag values into.
 type config struct {
 	Docker *docker.Helper
 
-	MasterAddr     flagtypes.Addr
-	BindAddr       flagtypes.Addr
-	EtcdAddr       flagtypes.Addr
-	KubernetesAddr flagtypes.Addr
-	PortalNet      flagtypes.IPNet
+	MasterAddr flagtypes.Addr
+	BindAddr   flagtypes.Addr
+	// InsecureBindAddr is the loopback host:port the master API is also served on without TLS, disabled if empty.
+	InsecureBindAddr string
+	EtcdAddr         flagtypes.Addr
+	KubernetesAddr   flagtypes.Addr
+	PortalNet        flagtypes.IPNet
 	// addresses for external clients
 	MasterPublicAddr     flagtypes.Addr
 	KubernetesPublicAddr flagtypes.Addr
@@ -106,8 +126,21 @@ type config struct {
 	VolumeDir string
 
 	EtcdDir string
+	// EtcdPrefix is the key OpenShift objects are stored under in etcd.
+	EtcdPrefix string
+	// EtcdCA, EtcdCertFile, and EtcdKeyFile secure the connection to an external etcd server.
+	EtcdCA       string
+	EtcdCertFile string
+	EtcdKeyFile  string
+	// EtcdUsername and EtcdPassword authenticate the master to an external etcd server.
+	EtcdUsername string
+	EtcdPassword string
+	// EtcdEncryptionKeys encrypt sensitive objects stored in etcd.
+	EtcdEncryptionKeys []configapi.EncryptionKey
 
 	CertDir string
+	// AdminKubeConfig is where the client config file of the cluster administrator is written if it is missing.
+	AdminKubeConfig string
 
 	StorageVersion string
 
@@ -119,6 +152,92 @@ type config struct {
 	ClientConfig clientcmd.ClientConfig
 
 	CORSAllowedOrigins flagtypes.StringList
+	// CORSAllowedMethods, CORSAllowedHeaders and CORSExposedHeaders replace the defaults of cross origin requests.
+	CORSAllowedMethods flagtypes.StringList
+	CORSAllowedHeaders flagtypes.StringList
+	CORSExposedHeaders flagtypes.StringList
+	// CORSMaxAge is how long browsers may cache the answer to a preflight request.
+	CORSMaxAge time.Duration
+
+	// ProjectRequestTemplate is the path to the template instantiated in every requested project.
+	ProjectRequestTemplate string
+
+	// AllowRouteHostSharing allows routes in different namespaces to claim the same host and path.
+	AllowRouteHostSharing bool
+
+	// ImageRepositoryDeletionGracePeriod is how long deleted image repositories can be restored, never if zero.
+	ImageRepositoryDeletionGracePeriod time.Duration
+
+	// RouteCertificateExpiryWindow is how long before their certificate expires routes are reported.
+	RouteCertificateExpiryWindow time.Duration
+
+	// BuildSourceSecretsDir is the directory of the nodes that holds the source and push secrets of builds by namespace.
+	BuildSourceSecretsDir string
+
+	// AllowedBuilderImages are the images Docker and STI builds may run instead of the builder images of the cluster.
+	AllowedBuilderImages flagtypes.StringList
+
+	// ReconcileBootstrapPolicy merges the roles and role bindings of the bootstrap policy into the existing
+	// master policy on start.
+	ReconcileBootstrapPolicy bool
+
+	// ShutdownGracePeriod is how long the master waits for requests in flight to complete when shutting down.
+	ShutdownGracePeriod time.Duration
+
+	// MaxRequestsInFlight is the number of API requests served at once, unlimited if zero.
+	MaxRequestsInFlight int
+	// MaxRequestsInFlightPerUser is the number of API requests served at once for a single user, unlimited if zero.
+	MaxRequestsInFlightPerUser int
+	// MaxRequestsPerSecondPerUser is the rate of API requests a single user may make, unlimited if zero.
+	MaxRequestsPerSecondPerUser float64
+	// RequestBurstPerUser is the number of API requests a single user may make at once above their rate.
+	RequestBurstPerUser int
+	// MaxRequestsPerSecondPerSystemUser is the rate of API requests a single system user may make, unlimited if zero.
+	MaxRequestsPerSecondPerSystemUser float64
+	// SlowRequestThreshold is the latency past which API requests are logged as slow, disabled if zero.
+	SlowRequestThreshold time.Duration
+	// ControllerLeaseTTL is the TTL of the lease masters compete for to run the controllers, every master
+	// runs them if zero.
+	ControllerLeaseTTL time.Duration
+
+	// ConfigFile is the master configuration file to read, if any.
+	ConfigFile string
+	// WriteConfigFile is where the master configuration built from the flags is written instead of starting.
+	WriteConfigFile string
+
+	// OAuth configures how users authenticate with the master.
+	OAuth configapi.OAuthConfig
+	// UseLocalImages uses images present on the nodes without pulling them.
+	UseLocalImages bool
+	// DisabledControllers lists the controllers the master does not run.
+	DisabledControllers flagtypes.StringList
+
+	// Audit configures the log of mutating API requests.
+	Audit configapi.AuditConfig
+	// AssetDir holds files served in place of the embedded web console assets.
+	AssetDir string
+	// AssetPathPrefix serves the web console under this path on the master listener instead of its own port.
+	AssetPathPrefix string
+	// AssetProxyAPI serves the APIs to the web console through the asset server.
+	AssetProxyAPI bool
+	// AssetProductName replaces the product name shown by the web console.
+	AssetProductName string
+	// AssetLogoImageURL replaces the logo shown by the web console.
+	AssetLogoImageURL string
+	// AssetExtensionScripts are the URLs of scripts the web console loads after its own.
+	AssetExtensionScripts flagtypes.StringList
+	// AssetExtensionStylesheets are the URLs of stylesheets the web console loads after its own.
+	AssetExtensionStylesheets flagtypes.StringList
+	// AssetLogoutURL is where the web console sends users after logging out.
+	AssetLogoutURL string
+	// AssetContentSecurityPolicy replaces the Content-Security-Policy header derived for the asset server.
+	AssetContentSecurityPolicy string
+	// AssetFrameOptions is the X-Frame-Options header of the asset server.
+	AssetFrameOptions string
+	// AssetStrictTransportSecurity is the Strict-Transport-Security header of the asset server.
+	AssetStrictTransportSecurity string
+	// NamedCertificates are served on the master listener to clients that request one of their names through SNI.
+	NamedCertificates []configapi.NamedCertificate
 }
 
 // NewCommandStartServer provides a CLI handler for 'start' command
@@ -149,6 +268,15 @@ func NewCommandStartServer(name string) *cobra.Command {
 		Short: "Launch OpenShift",
 		Long:  longCommandDesc,
 		Run: func(c *cobra.Command, args []string) {
+			if err := loadMasterConfig(cfg, c.Flags()); err != nil {
+				glog.Fatal(err)
+			}
+			if len(cfg.WriteConfigFile) > 0 {
+				if err := writeMasterConfig(cfg); err != nil {
+					glog.Fatal(err)
+				}
+				return
+			}
 			if err := start(cfg, args); err != nil {
 				glog.Fatal(err)
 			}
@@ -157,7 +285,10 @@ func NewCommandStartServer(name string) *cobra.Command {
 
 	flag := cmd.Flags()
 
+	flag.StringVar(&cfg.ConfigFile, "config", "", "The master configuration file to read. Flags set on the command line take precedence over the file.")
+	flag.StringVar(&cfg.WriteConfigFile, "write-config", "", "Write the master configuration built from the other flags to this file, or to stdout if '-', and exit.")
 	flag.Var(&cfg.BindAddr, "listen", "The address to listen for connections on (host, host:port, or URL).")
+	flag.StringVar(&cfg.InsecureBindAddr, "insecure-listen", "", "A loopback host:port to also serve the master API on without TLS, for local tooling such as health checks. Disabled if empty.")
 	flag.Var(&cfg.MasterAddr, "master", "The master address for use by OpenShift components (host, host:port, or URL). Scheme and port default to the --listen scheme and port.")
 	flag.Var(&cfg.MasterPublicAddr, "public-master", "The master address for use by public clients, if different (host, host:port, or URL). Defaults to same as --master.")
 	flag.Var(&cfg.EtcdAddr, "etcd", "The address of the etcd server (host, host:port, or URL). If specified, no built-in etcd will be started.")
@@ -170,11 +301,48 @@ func NewCommandStartServer(name string) *cobra.Command {
 
 	flag.StringVar(&cfg.VolumeDir, "volume-dir", "openshift.local.volumes", "The volume storage directory.")
 	flag.StringVar(&cfg.EtcdDir, "etcd-dir", "openshift.local.etcd", "The etcd data directory.")
+	installEtcdClientFlags(flag, cfg)
+	flag.StringVar(&cfg.StorageVersion, "storage-version", "", fmt.Sprintf("The API version OpenShift objects are stored in etcd in (%s). Defaults to %s. Objects stored in other versions are still read, run \"openshift ex migrate-storage\" to rewrite them.", strings.Join(latest.Versions, ", "), latest.Version))
 	flag.StringVar(&cfg.CertDir, "cert-dir", "openshift.local.certificates", "The certificate data directory.")
+	flag.StringVar(&cfg.AdminKubeConfig, "admin-kubeconfig", "", "If set, the master writes a client config file for the cluster administrator to this path on start unless it exists, authenticating with the certificate of the admin directory of --cert-dir.")
 
 	flag.StringVar(&cfg.Hostname, "hostname", cfg.Hostname, "The hostname to identify this node with the master.")
 	flag.Var(&cfg.NodeList, "nodes", "The hostnames of each node. This currently must be specified up front. Comma delimited list")
+	flag.StringVar(&cfg.ProjectRequestTemplate, "project-request-template", "", "The path to a template file that is instantiated in every project created by a project request. Defaults to a template that creates no additional objects.")
+	flag.BoolVar(&cfg.AllowRouteHostSharing, "allow-route-host-sharing", false, "If true, routes in different namespaces may claim the same host and path. By default the oldest route owns a host.")
+	flag.DurationVar(&cfg.ImageRepositoryDeletionGracePeriod, "image-repository-deletion-grace-period", 0, "How long a deleted image repository can be restored with its tags. Deleted image repositories are removed immediately if 0.")
+	flag.DurationVar(&cfg.RouteCertificateExpiryWindow, "route-certificate-expiry-window", 30*24*time.Hour, "How long before its certificate expires an event is recorded on a route. Expired certificates are always reported.")
+	flag.StringVar(&cfg.BuildSourceSecretsDir, "build-source-secrets-dir", "", "The directory of the nodes that holds the credentials builds clone private source repositories and push to registries with, in a directory per namespace and then secret name. Builds with a source or push secret fail if unset.")
+	flag.Var(&cfg.AllowedBuilderImages, "allowed-builder-images", "List of images Docker and STI builds may run instead of the builder images of the cluster, comma separated.  An image without a tag allows every tag of its repository.")
+	flag.BoolVar(&cfg.ReconcileBootstrapPolicy, "reconcile-bootstrap-policy", false, "If true, the roles and role bindings added to the bootstrap policy since the master policy was created are added to it on start. Roles that differ are reset unless annotated with openshift.io/reconcile-protect=true; the users and groups of existing role bindings are kept unless annotated with openshift.io/reconcile-unmodified=true.")
+	flag.DurationVar(&cfg.ShutdownGracePeriod, "shutdown-grace-period", 10*time.Second, "How long the master waits for requests in flight to complete when it receives SIGINT or SIGTERM.")
+	flag.IntVar(&cfg.MaxRequestsInFlight, "max-requests-inflight", 400, "The number of API requests served at once, not counting watches. Further requests are rejected with 429 Too Many Requests. Unlimited if 0.")
+	flag.Float64Var(&cfg.MaxRequestsPerSecondPerUser, "max-requests-per-second-per-user", 0, "The rate of API requests a single user may make, averaged over --request-burst-per-user requests. Further requests are rejected with 429 Too Many Requests. Unauthenticated requests are limited per client address. Unlimited if 0.")
+	flag.IntVar(&cfg.RequestBurstPerUser, "request-burst-per-user", 50, "The number of API requests a single user may make at once above --max-requests-per-second-per-user.")
+	flag.Float64Var(&cfg.MaxRequestsPerSecondPerSystemUser, "max-requests-per-second-per-system-user", 0, "The rate of API requests a single system user, such as a node or an infrastructure component, may make instead of --max-requests-per-second-per-user. Unlimited if 0.")
+	flag.IntVar(&cfg.MaxRequestsInFlightPerUser, "max-requests-inflight-per-user", 100, "The number of API requests served at once for a single user, not counting watches. Unlimited if 0.")
+	flag.DurationVar(&cfg.SlowRequestThreshold, "slow-request-threshold", 5*time.Second, "API requests that take longer than this are logged with the etcd operations made while they were served. Disabled if 0.")
+	flag.Var(&cfg.DisabledControllers, "disable-controllers", fmt.Sprintf("The controllers the master does not run, comma separated: %s. The status of the controllers is reported at /debug/controllers.", strings.Join(configapi.KnownControllers, ", ")))
+	flag.DurationVar(&cfg.ControllerLeaseTTL, "controller-lease-ttl", 30*time.Second, "The TTL of the etcd lease masters sharing an etcd cluster compete for, only the master holding it runs the controllers. A master that loses the lease exits. Every master runs the controllers if 0.")
+	flag.StringVar(&cfg.Audit.Path, "audit-log", "", "The file to log every mutating API request to, or '-' for stdout. Auditing is disabled if empty.")
+	flag.IntVar(&cfg.Audit.MaxSizeMegabytes, "audit-log-max-size", 100, "The size in megabytes the audit log is rotated at. The audit log is never rotated if 0.")
+	flag.IntVar(&cfg.Audit.MaxBackups, "audit-log-max-backups", 5, "The number of rotated audit logs to keep.")
+	flag.StringVar(&cfg.AssetDir, "asset-dir", "", "A directory of web console assets that are served in place of the embedded ones with the same path, for developing or patching the console without rebuilding. Assets served from it are not cached.")
+	flag.StringVar(&cfg.AssetPathPrefix, "asset-path-prefix", "", "If set, the web console is served under this path, for example /console, by the master with its certificate instead of on a port of its own.")
+	flag.BoolVar(&cfg.AssetProxyAPI, "asset-proxy-api", false, "If true, the asset server forwards /api and /osapi requests that carry a bearer token to the masters, and the web console calls the APIs through it to avoid cross origin and mixed content requests.")
+	flag.StringVar(&cfg.AssetProductName, "asset-product-name", "", "The product name shown by the web console instead of OpenShift.")
+	flag.StringVar(&cfg.AssetLogoImageURL, "asset-logo-url", "", "The URL of the logo shown by the web console instead of the OpenShift logo.")
+	flag.Var(&cfg.AssetExtensionScripts, "asset-extension-scripts", "URLs of scripts the web console loads after its own, comma separated.")
+	flag.Var(&cfg.AssetExtensionStylesheets, "asset-extension-stylesheets", "URLs of stylesheets the web console loads after its own, comma separated.")
+	flag.StringVar(&cfg.AssetLogoutURL, "asset-logout-url", "", "The URL the web console sends users to after logging out, for example to end the session of an external identity provider.")
+	flag.StringVar(&cfg.AssetContentSecurityPolicy, "asset-content-security-policy", "", "The Content-Security-Policy header of the asset server. If empty, a policy allowing the assets, extensions and APIs of the web console is used.")
+	flag.StringVar(&cfg.AssetFrameOptions, "asset-frame-options", assets.DefaultFrameOptions, "The X-Frame-Options header of the asset server, or empty to allow framing the web console anywhere.")
+	flag.StringVar(&cfg.AssetStrictTransportSecurity, "asset-strict-transport-security", assets.DefaultStrictTransportSecurity, "The Strict-Transport-Security header of the asset server when serving https, or empty to not send it.")
 	flag.Var(&cfg.CORSAllowedOrigins, "cors-allowed-origins", "List of allowed origins for CORS, comma separated.  An allowed origin can be a regular expression to support subdomain matching.  CORS is enabled for localhost, 127.0.0.1, and the asset server by default.")
+	flag.Var(&cfg.CORSAllowedMethods, "cors-allowed-methods", "List of the methods cross origin requests may use, comma separated.  Defaults to the methods of the API.")
+	flag.Var(&cfg.CORSAllowedHeaders, "cors-allowed-headers", "List of the headers cross origin requests may send, comma separated.  Defaults to the headers API clients send.")
+	flag.Var(&cfg.CORSExposedHeaders, "cors-exposed-headers", "List of the response headers cross origin clients may read, comma separated.  Defaults to Retry-After, Warning and WWW-Authenticate.")
+	flag.DurationVar(&cfg.CORSMaxAge, "cors-max-age", 10*time.Minute, "How long browsers may cache the answer to a CORS preflight request.")
 
 	cfg.ClientConfig = defaultClientConfig(flag)
 
@@ -183,6 +351,15 @@ func NewCommandStartServer(name string) *cobra.Command {
 	return cmd
 }
 
+// installEtcdClientFlags binds the flags that configure how the master connects to etcd to cfg.
+func installEtcdClientFlags(flag *pflag.FlagSet, cfg *config) {
+	flag.StringVar(&cfg.EtcdPrefix, "etcd-prefix", "", "The etcd key OpenShift objects are stored under, for example /openshift.io, so several masters can share an etcd cluster. Objects are stored at the root if empty.")
+	flag.StringVar(&cfg.EtcdCA, "etcd-ca", "", "The certificate bundle used to verify the etcd server. The server certificate is not verified if empty.")
+	flag.StringVar(&cfg.EtcdCertFile, "etcd-cert", "", "The client certificate presented to the etcd server.")
+	flag.StringVar(&cfg.EtcdKeyFile, "etcd-key", "", "The private key of the client certificate presented to the etcd server.")
+	flag.StringVar(&cfg.EtcdUsername, "etcd-username", "", "The username the master authenticates to the etcd server with. The password is read from ETCD_PASSWORD or the config file.")
+}
+
 // Copy of kubectl/cmd/DefaultClientConfig, using NewNonInteractiveDeferredLoadingClientConfig
 // TODO: there should be two client configs, one for OpenShift, and one for Kubernetes
 func defaultClientConfig(flags *pflag.FlagSet) clientcmd.ClientConfig {
@@ -262,6 +439,13 @@ func start(cfg *config, args []string) error {
 		cfg.KubernetesPublicAddr = cfg.MasterPublicAddr
 	}
 
+	if len(cfg.InsecureBindAddr) > 0 && !util.IsLoopbackHostPort(cfg.InsecureBindAddr) {
+		return fmt.Errorf("--insecure-listen must be a loopback host:port, got %q", cfg.InsecureBindAddr)
+	}
+	if len(cfg.AssetPathPrefix) > 0 && !configvalidation.IsAssetPathPrefix(cfg.AssetPathPrefix) {
+		return fmt.Errorf("--asset-path-prefix must begin and must not end with a /, got %q", cfg.AssetPathPrefix)
+	}
+
 	if env("OPENSHIFT_PROFILE", "") == "web" {
 		go func() {
 			glog.Infof("Starting profiling endpoint at http://127.0.0.1:6060/debug/pprof/")
@@ -273,10 +457,12 @@ func start(cfg *config, args []string) error {
 	imageResolverFn := func(component string) string {
 		return expandImage(component, cfg.ImageFormat, cfg.LatestReleaseImages)
 	}
-	useLocalImages := env("USE_LOCAL_IMAGES", "false") == "true"
+	useLocalImages := cfg.UseLocalImages
 
 	// the node can reuse an existing client
 	var existingKubeClient *kclient.Client
+	// the master is stopped before exiting
+	var osmaster *origin.MasterConfig
 
 	if startMaster {
 		if len(cfg.NodeList) == 1 && cfg.NodeList[0] == "127.0.0.1" {
@@ -314,6 +500,17 @@ func start(cfg *config, args []string) error {
 			return fmt.Errorf("Error setting up Kubernetes server storage: %v", err)
 		}
 
+		// record the latency of every etcd operation made by the master
+		metrics := origin.NewMetrics()
+		etcdHelper.Client = metrics.InstrumentEtcd(etcdHelper.Client)
+		// store OpenShift objects under the configured prefix, the Kubernetes objects are already under /registry
+		etcdHelper.Client = origin.NewPrefixedEtcdClient(etcdHelper.Client, cfg.EtcdPrefix)
+		// encrypt credentials stored in etcd, beneath the prefix so the sensitive keys are recognized
+		if etcdHelper.Client, err = newEncryptingEtcdClient(etcdHelper.Client, cfg); err != nil {
+			return fmt.Errorf("Error setting up server storage: %v", err)
+		}
+		ketcdHelper.Client = metrics.InstrumentEtcd(ketcdHelper.Client)
+
 		// determine whether public API addresses were specified
 		masterPublicAddr := cfg.MasterAddr
 		if cfg.MasterPublicAddr.Provided {
@@ -331,6 +528,11 @@ func start(cfg *config, args []string) error {
 		// Derive the asset public address by incrementing the master public address port by 1
 		assetPublicAddr := *masterPublicAddr.URL
 		assetPublicAddr.Host = net.JoinHostPort(masterPublicAddr.Host, strconv.Itoa(masterPublicAddr.Port+1))
+		if len(cfg.AssetPathPrefix) > 0 {
+			// the master serves the console itself
+			assetPublicAddr = *masterPublicAddr.URL
+			assetPublicAddr.Path = cfg.AssetPathPrefix
+		}
 
 		// Build the list of valid redirect_uri prefixes for a login using the openshift-web-console client to redirect to
 		// TODO: allow configuring this
@@ -345,9 +547,36 @@ func start(cfg *config, args []string) error {
 			cfg.CORSAllowedOrigins = append(cfg.CORSAllowedOrigins, origin)
 		}
 
-		osmaster := &origin.MasterConfig{
+		projectRequestTemplate := projectrequest.DefaultTemplate()
+		if len(cfg.ProjectRequestTemplate) > 0 {
+			data, err := ioutil.ReadFile(cfg.ProjectRequestTemplate)
+			if err != nil {
+				glog.Fatalf("Unable to read the project request template: %v", err)
+			}
+			obj, err := latest.Codec.Decode(data)
+			if err != nil {
+				glog.Fatalf("Unable to decode the project request template: %v", err)
+			}
+			template, ok := obj.(*templateapi.Template)
+			if !ok {
+				glog.Fatalf("The project request template %s must contain a Template", cfg.ProjectRequestTemplate)
+			}
+			projectRequestTemplate = template
+		}
+
+		var auditLog io.Writer
+		if len(cfg.Audit.Path) > 0 {
+			auditLog, err = origin.NewAuditLog(cfg.Audit.Path, cfg.Audit.MaxSizeMegabytes, cfg.Audit.MaxBackups)
+			if err != nil {
+				return fmt.Errorf("Unable to open the audit log: %v", err)
+			}
+			glog.Infof("Logging mutating API requests to %s", cfg.Audit.Path)
+		}
+
+		osmaster = &origin.MasterConfig{
 			TLS:                  cfg.BindAddr.URL.Scheme == "https",
 			MasterBindAddr:       cfg.BindAddr.URL.Host,
+			InsecureBindAddr:     cfg.InsecureBindAddr,
 			MasterAddr:           cfg.MasterAddr.URL.String(),
 			MasterPublicAddr:     masterPublicAddr.URL.String(),
 			AssetBindAddr:        assetBindAddr,
@@ -356,11 +585,48 @@ func start(cfg *config, args []string) error {
 			KubernetesPublicAddr: k8sPublicAddr.URL.String(),
 
 			CORSAllowedOrigins: cfg.CORSAllowedOrigins,
+			CORSAllowedMethods: cfg.CORSAllowedMethods,
+			CORSAllowedHeaders: cfg.CORSAllowedHeaders,
+			CORSExposedHeaders: cfg.CORSExposedHeaders,
+			CORSMaxAge:         cfg.CORSMaxAge,
+
+			AssetDir:        cfg.AssetDir,
+			AssetProxyAPI:   cfg.AssetProxyAPI,
+			AssetPathPrefix: cfg.AssetPathPrefix,
+			AssetExtensions: assets.WebConsoleExtensions{
+				ProductName:  cfg.AssetProductName,
+				LogoImageURL: cfg.AssetLogoImageURL,
+				Scripts:      cfg.AssetExtensionScripts,
+				Stylesheets:  cfg.AssetExtensionStylesheets,
+			},
+			AssetLogoutURL:           cfg.AssetLogoutURL,
+			AccessTokenMaxAgeSeconds: cfg.OAuth.AccessTokenMaxAgeSeconds,
+			AssetSecurityHeaders: assets.SecurityHeaders{
+				ContentSecurityPolicy:   cfg.AssetContentSecurityPolicy,
+				FrameOptions:            cfg.AssetFrameOptions,
+				StrictTransportSecurity: cfg.AssetStrictTransportSecurity,
+			},
 
 			EtcdHelper: etcdHelper,
 
-			AdmissionControl:             admit.NewAlwaysAdmit(),
-			MasterAuthorizationNamespace: "master",
+			AdmissionControl:                   admit.NewAlwaysAdmit(),
+			MasterAuthorizationNamespace:       "master",
+			ProjectRequestTemplate:             projectRequestTemplate,
+			AllowRouteHostSharing:              cfg.AllowRouteHostSharing,
+			ImageRepositoryDeletionGracePeriod: cfg.ImageRepositoryDeletionGracePeriod,
+			RouteCertificateExpiryWindow:       cfg.RouteCertificateExpiryWindow,
+			BuildSourceSecretsDir:              cfg.BuildSourceSecretsDir,
+			AllowedBuilderImages:               cfg.AllowedBuilderImages,
+			ReconcileBootstrapPolicy:           cfg.ReconcileBootstrapPolicy,
+			ShutdownGracePeriod:                cfg.ShutdownGracePeriod,
+			Metrics:                            metrics,
+			AuditLog:                           auditLog,
+			MaxRequestsInFlight:                cfg.MaxRequestsInFlight,
+			MaxRequestsInFlightPerUser:         cfg.MaxRequestsInFlightPerUser,
+			MaxRequestsPerSecondPerUser:        cfg.MaxRequestsPerSecondPerUser,
+			RequestBurstPerUser:                cfg.RequestBurstPerUser,
+			MaxRequestsPerSecondPerSystemUser:  cfg.MaxRequestsPerSecondPerSystemUser,
+			SlowRequestThreshold:               cfg.SlowRequestThreshold,
 
 			UseLocalImages: useLocalImages,
 			ImageFor:       imageResolverFn,
@@ -389,6 +655,10 @@ func start(cfg *config, args []string) error {
 		// TODO: limit this authenticator to watch methods, if possible
 		// TODO: prevent access_token param from getting logged, if possible
 		authenticators = append(authenticators, paramtoken.New("access_token", tokenAuthenticator))
+		// Bootstrap tokens let new nodes and components join the cluster, the bootstrap policy restricts them to that
+		authenticators = append(authenticators, bearertoken.New(origin.GetEtcdBootstrapTokenAuthenticator(etcdHelper)))
+		// Router tokens let routers read the routes and endpoints of the namespaces of their shard only
+		authenticators = append(authenticators, bearertoken.New(origin.GetEtcdRouterTokenAuthenticator(etcdHelper)))
 
 		var roots *x509.CertPool
 		if osmaster.TLS {
@@ -418,8 +688,14 @@ func start(cfg *config, args []string) error {
 			if err != nil {
 				return err
 			}
+			// Regenerate the server certificate before it expires, the master and asset servers reload it
+			certController := &crypto.ServingCertController{CA: ca, Name: "master", Hostnames: pkgutil.UniqueStrings(certHostnames)}
+			certController.Run(servingCertCheckPeriod)
 			osmaster.MasterCertFile = serverCert.CertFile
 			osmaster.MasterKeyFile = serverCert.KeyFile
+			for _, named := range cfg.NamedCertificates {
+				osmaster.NamedCertificates = append(osmaster.NamedCertificates, origin.NamedCertificate{Names: named.Names, CertFile: named.CertFile, KeyFile: named.KeyFile})
+			}
 			osmaster.AssetCertFile = serverCert.CertFile
 			osmaster.AssetKeyFile = serverCert.KeyFile
 
@@ -434,8 +710,12 @@ func start(cfg *config, args []string) error {
 			if osmaster.DeployerOSClientConfig, err = ca.MakeClientConfig("openshift-deployer", osClientConfigTemplate); err != nil {
 				return err
 			}
-			// Admin config (creates files on disk for osc)
-			if _, err = ca.MakeClientConfig("admin", osClientConfigTemplate); err != nil {
+			// Admin config (creates files on disk for osc), issued to the user the bootstrap policy makes a cluster admin
+			adminConfig, err := ca.MakeClientConfigInDir("admin", authorizer.BootstrapAdminUsername, osClientConfigTemplate)
+			if err != nil {
+				return err
+			}
+			if err := writeAdminKubeConfig(cfg.AdminKubeConfig, adminConfig, masterPublicAddr.URL.String()); err != nil {
 				return err
 			}
 
@@ -464,6 +744,9 @@ func start(cfg *config, args []string) error {
 			osClientConfig := kclient.Config{Host: cfg.MasterAddr.URL.String(), Version: latest.Version}
 			osmaster.OSClientConfig = osClientConfig
 			osmaster.DeployerOSClientConfig = osClientConfig
+			if err := writeAdminKubeConfig(cfg.AdminKubeConfig, osClientConfig, masterPublicAddr.URL.String()); err != nil {
+				return err
+			}
 		}
 
 		// TODO: make anonymous auth optional?
@@ -479,19 +762,6 @@ func start(cfg *config, args []string) error {
 
 		osmaster.BuildClients()
 
-		// Sessions need to last as long as we expect the grant flow to take
-		// Session auth is invalidated at the end of an authorize flow, so it can only be used once
-		sessionMaxAgeSeconds, err := strconv.ParseInt(env("ORIGIN_OAUTH_SESSION_MAX_AGE_SECONDS", "300"), 10, 0)
-		if err != nil || sessionMaxAgeSeconds <= 0 {
-			glog.Warningf("Invalid ORIGIN_OAUTH_SESSION_MAX_AGE_SECONDS. Defaulting to 5 minutes.")
-			sessionMaxAgeSeconds = 300
-		}
-
-		// Default to a session authenticator (for browsers), and a basicauth authenticator (for clients responding to WWW-Authenticate challenges)
-		defaultAuthRequestHandlers := strings.Join([]string{
-			string(origin.AuthRequestHandlerSession),
-			string(origin.AuthRequestHandlerBasicAuth),
-		}, ",")
 		auth := &origin.AuthConfig{
 			MasterAddr:           cfg.MasterAddr.URL.String(),
 			MasterPublicAddr:     masterPublicAddr.URL.String(),
@@ -499,25 +769,28 @@ func start(cfg *config, args []string) error {
 			MasterRoots:          roots,
 			EtcdHelper:           etcdHelper,
 
-			AuthRequestHandlers: origin.ParseAuthRequestHandlerTypes(env("ORIGIN_OAUTH_REQUEST_HANDLERS", defaultAuthRequestHandlers)),
-			AuthHandler:         origin.AuthHandlerType(env("ORIGIN_OAUTH_HANDLER", string(origin.AuthHandlerLogin))),
-			GrantHandler:        origin.GrantHandlerType(env("ORIGIN_OAUTH_GRANT_HANDLER", string(origin.GrantHandlerAuto))),
+			AuthRequestHandlers: origin.ParseAuthRequestHandlerTypes(strings.Join(cfg.OAuth.RequestHandlers, ",")),
+			AuthHandler:         origin.AuthHandlerType(cfg.OAuth.Handler),
+			GrantHandler:        origin.GrantHandlerType(cfg.OAuth.GrantHandler),
 			// Session config
-			SessionSecrets:       []string{env("ORIGIN_OAUTH_SESSION_SECRET", "secret12345")},
-			SessionMaxAgeSeconds: int(sessionMaxAgeSeconds),
-			SessionName:          env("ORIGIN_OAUTH_SESSION_NAME", "ssn"),
+			SessionSecrets:       cfg.OAuth.SessionSecrets,
+			SessionMaxAgeSeconds: cfg.OAuth.SessionMaxAgeSeconds,
+			SessionName:          cfg.OAuth.SessionName,
 			// Password config
-			PasswordAuth: origin.PasswordAuthType(env("ORIGIN_OAUTH_PASSWORD_AUTH", string(origin.PasswordAuthAnyPassword))),
-			BasicAuthURL: env("ORIGIN_OAUTH_BASIC_AUTH_URL", ""),
+			PasswordAuth: origin.PasswordAuthType(cfg.OAuth.PasswordAuth),
+			BasicAuthURL: cfg.OAuth.BasicAuthURL,
+
+			IdentityMappingMethod: user.MappingMethod(cfg.OAuth.IdentityMappingMethod),
 			// Token config
-			TokenStore:    origin.TokenStoreType(env("ORIGIN_OAUTH_TOKEN_STORE", string(origin.TokenStoreEtcd))),
-			TokenFilePath: env("ORIGIN_OAUTH_TOKEN_FILE_PATH", ""),
+			TokenStore:               origin.TokenStoreType(cfg.OAuth.TokenStore),
+			TokenFilePath:            cfg.OAuth.TokenFilePath,
+			AccessTokenMaxAgeSeconds: cfg.OAuth.AccessTokenMaxAgeSeconds,
 			// Google config
-			GoogleClientID:     env("ORIGIN_OAUTH_GOOGLE_CLIENT_ID", ""),
-			GoogleClientSecret: env("ORIGIN_OAUTH_GOOGLE_CLIENT_SECRET", ""),
+			GoogleClientID:     cfg.OAuth.GoogleClientID,
+			GoogleClientSecret: cfg.OAuth.GoogleClientSecret,
 			// Github config
-			GithubClientID:     env("ORIGIN_OAUTH_GITHUB_CLIENT_ID", ""),
-			GithubClientSecret: env("ORIGIN_OAUTH_GITHUB_CLIENT_SECRET", ""),
+			GithubClientID:     cfg.OAuth.GithubClientID,
+			GithubClientSecret: cfg.OAuth.GithubClientSecret,
 		}
 
 		// Allow privileged containers
@@ -526,6 +799,13 @@ func start(cfg *config, args []string) error {
 			AllowPrivileged: true,
 		})
 
+		// the controllers of the Kubernetes master started with OpenShift, if any, which are run with the
+		// OpenShift controllers and cannot be disabled
+		type kubeController struct {
+			name string
+			run  func()
+		}
+		kubeControllers := []kubeController{}
 		if startKube {
 			portalNet := net.IPNet(cfg.PortalNet)
 			masterIP := net.ParseIP(cfg.MasterAddr.Host)
@@ -548,16 +828,18 @@ func start(cfg *config, args []string) error {
 				EtcdHelper:       ketcdHelper,
 				KubeClient:       osmaster.KubeClient(),
 				Authorizer:       apiserver.NewAlwaysAllowAuthorizer(),
-				AdmissionControl: admit.NewAlwaysAdmit(),
+				AdmissionControl: osmaster.KubeAdmissionControl(),
 			}
 			kmaster.EnsurePortalFlags()
 
 			osmaster.Run([]origin.APIInstaller{kmaster}, []origin.APIInstaller{auth})
 
-			kmaster.RunScheduler()
-			kmaster.RunReplicationController()
-			kmaster.RunEndpointController()
-			kmaster.RunMinionController()
+			kubeControllers = append(kubeControllers,
+				kubeController{"kubernetesScheduler", kmaster.RunScheduler},
+				kubeController{"kubernetesReplication", kmaster.RunReplicationController},
+				kubeController{"kubernetesEndpoints", kmaster.RunEndpointController},
+				kubeController{"kubernetesMinions", kmaster.RunMinionController},
+			)
 
 		} else {
 			proxy := &kubernetes.ProxyConfig{
@@ -571,12 +853,35 @@ func start(cfg *config, args []string) error {
 		record.StartRecording(osmaster.KubeClient().Events(""), kapi.EventSource{Component: "master"})
 
 		osmaster.RunAssetServer()
-		osmaster.RunBuildController()
-		osmaster.RunBuildImageChangeTriggerController()
-		osmaster.RunDeploymentController()
-		osmaster.RunDeploymentConfigController()
-		osmaster.RunDeploymentConfigChangeController()
-		osmaster.RunDeploymentImageChangeTriggerController()
+
+		controllers := map[string]origin.ControllerFunc{
+			configapi.BuildController:                        osmaster.RunBuildController,
+			configapi.BuildImageChangeTriggerController:      osmaster.RunBuildImageChangeTriggerController,
+			configapi.DeploymentController:                   osmaster.RunDeploymentController,
+			configapi.DeploymentConfigController:             osmaster.RunDeploymentConfigController,
+			configapi.DeploymentConfigChangeController:       osmaster.RunDeploymentConfigChangeController,
+			configapi.DeploymentImageChangeTriggerController: osmaster.RunDeploymentImageChangeTriggerController,
+			configapi.DeploymentAutoscaleController:          osmaster.RunDeploymentAutoscaleController,
+			configapi.DeploymentDriftController:              osmaster.RunDeploymentDriftController,
+			configapi.RouteCertificateExpiryController:       osmaster.RunRouteCertificateExpiryController,
+			configapi.QuotaUsageController:                   osmaster.RunQuotaUsageController,
+			configapi.ProjectFinalizerController:             osmaster.RunProjectFinalizerController,
+			configapi.GarbageCollectorController:             osmaster.RunGarbageCollectorController,
+		}
+		manager := osmaster.Controllers
+		for _, c := range kubeControllers {
+			run := c.run
+			manager.Add(c.name, true, func(<-chan struct{}, func(), func(interface{})) { run() })
+		}
+		disabled := kutil.NewStringSet(cfg.DisabledControllers...)
+		for _, name := range configapi.KnownControllers {
+			manager.Add(name, !disabled.Has(name), controllers[name])
+		}
+		if cfg.ControllerLeaseTTL > 0 {
+			go runWithControllerLease(etcdHelper.Client, cfg, manager.Start)
+		} else {
+			manager.Start()
+		}
 
 		existingKubeClient = osmaster.KubeClient()
 	}
@@ -615,8 +920,13 @@ func start(cfg *config, args []string) error {
 		nodeConfig.RunKubelet()
 	}
 
-	select {}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	glog.Infof("Received %v, shutting down", <-signals)
 
+	if osmaster != nil {
+		osmaster.Stop()
+	}
 	return nil
 }
 
@@ -624,8 +934,10 @@ func start(cfg *config, args []string) error {
 // until etcd server is reachable. It errors out and exits if the server cannot
 // be reached for a certain amount of time.
 func getEtcdClient(cfg *config) (*etcdclient.Client, error) {
-	etcdServers := []string{cfg.EtcdAddr.URL.String()}
-	etcdClient := etcdclient.NewClient(etcdServers)
+	etcdClient, err := newEtcdClient(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	for i := 0; ; i++ {
 		_, err := etcdClient.Get("/", false, false)
@@ -641,6 +953,77 @@ func getEtcdClient(cfg *config) (*etcdclient.Client, error) {
 	return etcdClient, nil
 }
 
+// newEtcdClient creates an etcd client for EtcdAddr, presenting the client certificate and basic auth
+// credentials in cfg and verifying the server against EtcdCA, if they are set.
+func newEtcdClient(cfg *config) (*etcdclient.Client, error) {
+	etcdURL := *cfg.EtcdAddr.URL
+	if len(cfg.EtcdUsername) > 0 {
+		// the etcd client sends the credentials in the server URL with every request
+		etcdURL.User = url.UserPassword(cfg.EtcdUsername, cfg.EtcdPassword)
+	}
+	etcdServers := []string{etcdURL.String()}
+
+	var etcdClient *etcdclient.Client
+	if len(cfg.EtcdCertFile) > 0 {
+		// the CA is added below, NewTLSClient ignores errors loading it
+		client, err := etcdclient.NewTLSClient(etcdServers, cfg.EtcdCertFile, cfg.EtcdKeyFile, "")
+		if err != nil {
+			return nil, fmt.Errorf("Unable to load the etcd client certificate: %v", err)
+		}
+		etcdClient = client
+	} else {
+		etcdClient = etcdclient.NewClient(etcdServers)
+	}
+
+	if len(cfg.EtcdCA) > 0 {
+		if err := etcdClient.AddRootCA(cfg.EtcdCA); err != nil {
+			return nil, fmt.Errorf("Unable to load the etcd CA: %v", err)
+		}
+	} else if etcdURL.Scheme == "https" {
+		glog.Warningf("No etcd CA was provided, the certificate of the etcd server at %s will not be verified", cfg.EtcdAddr.URL.Host)
+	}
+	return etcdClient, nil
+}
+
+// newEncryptingEtcdClient wraps client to encrypt sensitive objects with the EtcdEncryptionKeys in cfg.
+func newEncryptingEtcdClient(client tools.EtcdGetSet, cfg *config) (tools.EtcdGetSet, error) {
+	keys := []origin.EncryptionKey{}
+	for _, key := range cfg.EtcdEncryptionKeys {
+		secret, err := base64.StdEncoding.DecodeString(key.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to decode the etcd encryption key %q: %v", key.Name, err)
+		}
+		keys = append(keys, origin.EncryptionKey{Name: key.Name, Secret: secret})
+	}
+	return origin.NewEncryptingEtcdClient(client, keys, origin.SensitiveEtcdPaths)
+}
+
+// controllerLeaseKey is the etcd key of the lease masters compete for to run the controllers, beneath the
+// etcd prefix of the master.
+const controllerLeaseKey = "/leases/controllers"
+
+// runWithControllerLease waits until this master holds the controller lease, then calls run and renews
+// the lease.  The controllers cannot be stopped, so the master exits when it loses the lease to let another
+// master run them.  Masters often share the public master URL, so the lease is held under the hostname and a
+// random id unique to the process.
+func runWithControllerLease(client tools.EtcdGetSet, cfg *config, run func()) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		glog.Fatalf("Unable to get the hostname to hold the controller lease under: %v", err)
+	}
+	id := fmt.Sprintf("%s-%s", hostname, kutil.NewUUID())
+	lease := election.NewEtcdLease(client, controllerLeaseKey, id, cfg.ControllerLeaseTTL)
+	interval := cfg.ControllerLeaseTTL / 3
+
+	glog.Infof("Waiting to acquire the controller lease as %s", id)
+	lease.Acquire(interval, nil)
+	glog.Infof("Acquired the controller lease as %s, starting controllers", id)
+	run()
+	lease.Hold(interval, func(err error) {
+		glog.Fatalf("Lost the controller lease, exiting so another master can run the controllers: %v", err)
+	}, nil)
+}
+
 // defaultHostname returns the default hostname for this system.
 func defaultHostname() (string, error) {
 	// Note: We use exec here instead of os.Hostname() because we
@@ -698,6 +1081,24 @@ func defaultMasterAddress(cfg *config) error {
 	return nil
 }
 
+// writeAdminKubeConfig writes a client config file for the cluster administrator to path that connects to the
+// public master address with the credentials of client, unless path is empty or the file exists.
+func writeAdminKubeConfig(path string, client kclient.Config, masterPublicAddr string) error {
+	if len(path) == 0 {
+		return nil
+	}
+	if _, err := os.Stat(path); err == nil {
+		glog.Infof("Using existing cluster administrator client config in %s", path)
+		return nil
+	}
+	client.Host = masterPublicAddr
+	if err := crypto.WriteKubeConfig(&client, authorizer.BootstrapAdminUsername, path); err != nil {
+		return fmt.Errorf("unable to write the cluster administrator client config to %s: %v", path, err)
+	}
+	glog.Infof("Wrote the cluster administrator client config to %s", path)
+	return nil
+}
+
 // clientConfigFromKubeConfig reads the client configuration settings for connecting to
 // a Kubernetes master.
 func clientConfigFromKubeConfig(cfg *config) *kclient.Config {
@@ -1,9 +1,18 @@
 package server
 
 import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/clientcmd"
+
+	"github.com/openshift/origin/pkg/authorization/authorizer"
+	"github.com/openshift/origin/pkg/cmd/flagtypes"
 	"github.com/openshift/origin/pkg/cmd/util/variable"
 	"github.com/openshift/origin/pkg/version"
 )
@@ -32,3 +41,89 @@ func TestExpandDefaultImage(t *testing.T) {
 		}
 	}
 }
+
+func TestNewEtcdClientBasicAuth(t *testing.T) {
+	var username, password string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		username, password, _ = req.BasicAuth()
+		w.Header().Set("X-Etcd-Index", "1")
+		w.Write([]byte(`{"action":"get","node":{"key":"/","dir":true}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config{
+		EtcdAddr:     flagtypes.Addr{DefaultScheme: "http", DefaultPort: 4001}.Default(),
+		EtcdUsername: "master",
+		EtcdPassword: "secret",
+	}
+	if err := cfg.EtcdAddr.Set(server.URL); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client, err := newEtcdClient(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.Get("/", false, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if username != "master" || password != "secret" {
+		t.Errorf("Expected the etcd credentials to be sent, got %q %q", username, password)
+	}
+}
+
+func TestNewEtcdClientMissingCA(t *testing.T) {
+	cfg := &config{
+		EtcdAddr: flagtypes.Addr{Value: "https://etcd.example.com:4001", DefaultScheme: "http", DefaultPort: 4001}.Default(),
+		EtcdCA:   "/nonexistent/ca.crt",
+	}
+	if _, err := newEtcdClient(cfg); err == nil {
+		t.Errorf("Expected an error loading a missing CA")
+	}
+}
+
+func TestWriteAdminKubeConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	certDir := filepath.Join(dir, "certificates", "admin")
+	client := kclient.Config{
+		Host: "https://0.0.0.0:8443",
+		TLSClientConfig: kclient.TLSClientConfig{
+			CAFile:   filepath.Join(certDir, "root.crt"),
+			CertFile: filepath.Join(certDir, "cert.crt"),
+			KeyFile:  filepath.Join(certDir, "key.key"),
+		},
+	}
+	if err := writeAdminKubeConfig("", client, "https://master.example.com:8443"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	path := filepath.Join(dir, "config", "admin.kubeconfig")
+	if err := writeAdminKubeConfig(path, client, "https://master.example.com:8443"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	config, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if server := config.Clusters["master"].Server; server != "https://master.example.com:8443" {
+		t.Errorf("Expected the public master address, got %q", server)
+	}
+	authInfo, ok := config.AuthInfos[authorizer.BootstrapAdminUsername]
+	if !ok || authInfo.ClientCertificate != "../certificates/admin/cert.crt" || authInfo.ClientKey != "../certificates/admin/key.key" {
+		t.Errorf("Expected the admin certificate relative to the config file, got %#v", config.AuthInfos)
+	}
+
+	if err := writeAdminKubeConfig(path, client, "https://other.example.com:8443"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config, err = clientcmd.LoadFromFile(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if server := config.Clusters["master"].Server; server != "https://master.example.com:8443" {
+		t.Errorf("Expected the existing config to be kept, got %q", server)
+	}
+}
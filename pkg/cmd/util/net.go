@@ -7,6 +7,19 @@ import (
 	"github.com/golang/glog"
 )
 
+// IsLoopbackHostPort returns true if hostport is a host:port whose host is localhost or a loopback IP.
+func IsLoopbackHostPort(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 // WaitForDial attempts to connect to the given address, closing and returning nil on the first successful connection.
 func WaitForSuccessfulDial(network, address string, timeout, interval time.Duration, retries int) error {
 	var (
@@ -0,0 +1,22 @@
+package util
+
+import "testing"
+
+func TestIsLoopbackHostPort(t *testing.T) {
+	testCases := map[string]bool{
+		"localhost:8080":   true,
+		"127.0.0.1:8080":   true,
+		"127.0.1.1:8080":   true,
+		"[::1]:8080":       true,
+		"0.0.0.0:8080":     false,
+		"10.0.0.1:8080":    false,
+		"example.com:8080": false,
+		"127.0.0.1":        false,
+		"":                 false,
+	}
+	for hostport, expected := range testCases {
+		if actual := IsLoopbackHostPort(hostport); actual != expected {
+			t.Errorf("%q: expected %v, got %v", hostport, expected, actual)
+		}
+	}
+}
@@ -13,5 +13,5 @@ type Config struct {
 
 	// Required: Items is an array of Kubernetes resources of Service,
 	// Pod and/or ReplicationController kind.
-	Items []runtime.RawExtension `json:"items"`
+	Items []runtime.RawExtension `json:"items" description:"Items is an array of Kubernetes resources of Service, Pod and/or ReplicationController kind."`
 }
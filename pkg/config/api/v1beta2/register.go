@@ -0,0 +1,13 @@
+package v1beta2
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+func init() {
+	api.Scheme.AddKnownTypes("v1beta2",
+		&Config{},
+	)
+}
+
+func (*Config) IsAnAPIObject() {}
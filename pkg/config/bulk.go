@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+)
+
+// Bulk creates the items of a list using the REST client that serves each item's kind.
+type Bulk struct {
+	Mapper meta.RESTMapper
+	Typer  runtime.ObjectTyper
+	// RESTClientFactory returns the client to use for the provided mapping.
+	RESTClientFactory func(mapping *meta.RESTMapping) (resource.RESTClient, error)
+}
+
+// Create attempts to create each item of list in namespace and returns the errors encountered.
+// A failure to create one item does not prevent the remaining items from being created.
+func (b *Bulk) Create(list *kapi.List, namespace string) []error {
+	resourceMapper := &resource.Mapper{
+		ObjectTyper:  b.Typer,
+		RESTMapper:   b.Mapper,
+		ClientMapper: resource.ClientMapperFunc(b.RESTClientFactory),
+	}
+
+	errs := []error{}
+	for i, item := range list.Items {
+		info, err := resourceMapper.InfoForObject(item)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("item %d: %v", i, err))
+			continue
+		}
+		data, err := info.Mapping.Codec.Encode(item)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("item %d: %v", i, err))
+			continue
+		}
+		if err := resource.NewHelper(info.Client, info.Mapping).Create(namespace, false, data); err != nil {
+			errs = append(errs, fmt.Errorf("%s %q: %v", info.Mapping.Kind, info.Name, err))
+			continue
+		}
+	}
+	return errs
+}
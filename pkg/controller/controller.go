@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	kcache "github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+)
+
+// Queue is a queue of objects to process.
+type Queue interface {
+	// Pop waits until an object is queued and removes it from the queue.
+	Pop() interface{}
+}
+
+// ReQueue is a Queue objects can be added back to.
+type ReQueue interface {
+	Queue
+	// AddIfNotPresent queues obj unless a newer state of obj is queued already.
+	AddIfNotPresent(obj interface{}) error
+}
+
+// RetryManager decides what to do with objects after they are processed.
+type RetryManager interface {
+	// Retry is called with an object that failed to be processed with err.
+	Retry(obj interface{}, err error)
+	// Forget is called with an object that was processed successfully.
+	Forget(obj interface{})
+}
+
+// RetryController processes the objects of Queue one at a time with Handle, and passes the objects Handle
+// fails on to RetryManager.
+type RetryController struct {
+	// Queue holds the objects to process.
+	Queue Queue
+	// RetryManager is told about the outcome of processing every object.
+	RetryManager RetryManager
+	// Handle processes an object.  Objects are retried when Handle returns an error.
+	Handle func(obj interface{}) error
+	// Synced may be set to be called after every object is processed.
+	Synced func()
+	// Crashed may be set to be called with the value of a panic while processing an object.
+	Crashed func(interface{})
+	// Stop is an optional channel that controls when the controller exits.
+	Stop <-chan struct{}
+}
+
+// Run processes the objects of the queue until the controller is stopped.
+func (c *RetryController) Run() {
+	go Until(c.handleOne, 0, c.Stop, c.Crashed)
+}
+
+// Until runs f every period until stop is closed, like util.Until.  A panic in f is logged and, if crashed is
+// set, passed to crashed, so that whatever runs the controller can restart it.
+func Until(f func(), period time.Duration, stop <-chan struct{}, crashed func(interface{})) {
+	util.Until(func() {
+		defer handleCrash(crashed)
+		f()
+	}, period, stop)
+}
+
+// handleCrash recovers a panic, logs it and passes it to crashed if set.  Meant to be called via defer.
+func handleCrash(crashed func(interface{})) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	for _, fn := range util.PanicHandlers {
+		fn(r)
+	}
+	if crashed != nil {
+		crashed(r)
+	}
+}
+
+// handleOne processes the next object of the queue.
+func (c *RetryController) handleOne() {
+	obj := c.Queue.Pop()
+	select {
+	case <-c.Stop:
+		// the object is left to the controller that replaces this one
+		return
+	default:
+	}
+	if err := c.Handle(obj); err != nil {
+		c.RetryManager.Retry(obj, err)
+	} else {
+		c.RetryManager.Forget(obj)
+	}
+	if c.Synced != nil {
+		c.Synced()
+	}
+}
+
+// RetryFunc returns true if obj should be retried after it failed to be processed with err retries times
+// before.
+type RetryFunc func(obj interface{}, err error, retries int) bool
+
+// RetryNever is a RetryFunc that never retries.
+func RetryNever(obj interface{}, err error, retries int) bool {
+	return false
+}
+
+// RetryLimit returns a RetryFunc that retries every object up to limit times.
+func RetryLimit(limit int) RetryFunc {
+	return func(obj interface{}, err error, retries int) bool {
+		return retries < limit
+	}
+}
+
+// QueueRetryManager adds the objects that failed back to a queue, waiting twice as long before every retry
+// of the same object.  An object is not added back if a newer state of it was queued meanwhile.
+type QueueRetryManager struct {
+	queue   ReQueue
+	keyFunc kcache.KeyFunc
+	retry   RetryFunc
+
+	// initialBackoff is the wait before the first retry of an object, maxBackoff the longest wait
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	lock sync.Mutex
+	// retries is the number of times each object has been retried since it was last processed successfully
+	retries map[string]int
+	// after schedules f to be called after d, it is replaced in tests
+	after func(d time.Duration, f func())
+}
+
+// NewQueueRetryManager returns a RetryManager that adds the objects retry accepts back to queue by key,
+// first after initialBackoff and then after twice as long as the previous time, up to maxBackoff.
+func NewQueueRetryManager(queue ReQueue, keyFunc kcache.KeyFunc, retry RetryFunc, initialBackoff, maxBackoff time.Duration) *QueueRetryManager {
+	return &QueueRetryManager{
+		queue:          queue,
+		keyFunc:        keyFunc,
+		retry:          retry,
+		initialBackoff: initialBackoff,
+		maxBackoff:     maxBackoff,
+		retries:        map[string]int{},
+		after:          func(d time.Duration, f func()) { time.AfterFunc(d, f) },
+	}
+}
+
+// Retry implements RetryManager
+func (m *QueueRetryManager) Retry(obj interface{}, err error) {
+	key, keyErr := m.keyFunc(obj)
+	if keyErr != nil {
+		util.HandleError(keyErr)
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	retries := m.retries[key]
+	if !m.retry(obj, err, retries) {
+		util.HandleError(err)
+		delete(m.retries, key)
+		return
+	}
+	m.retries[key] = retries + 1
+
+	backoff := m.initialBackoff
+	for i := 0; i < retries && backoff < m.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > m.maxBackoff {
+		backoff = m.maxBackoff
+	}
+	glog.V(4).Infof("Retrying %s in %v after error: %v", key, backoff, err)
+	m.after(backoff, func() {
+		if err := m.queue.AddIfNotPresent(obj); err != nil {
+			util.HandleError(err)
+		}
+	})
+}
+
+// Forget implements RetryManager
+func (m *QueueRetryManager) Forget(obj interface{}) {
+	key, err := m.keyFunc(obj)
+	if err != nil {
+		util.HandleError(err)
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.retries, key)
+}
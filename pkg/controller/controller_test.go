@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type fakeQueue struct {
+	items []interface{}
+}
+
+func (q *fakeQueue) Pop() interface{} {
+	obj := q.items[0]
+	q.items = q.items[1:]
+	return obj
+}
+
+func (q *fakeQueue) AddIfNotPresent(obj interface{}) error {
+	q.items = append(q.items, obj)
+	return nil
+}
+
+func keyFunc(obj interface{}) (string, error) {
+	return fmt.Sprintf("%v", obj), nil
+}
+
+// newTestRetryManager returns a QueueRetryManager that records the backoffs it waits and adds objects back
+// immediately.
+func newTestRetryManager(queue ReQueue, retry RetryFunc) (*QueueRetryManager, *[]time.Duration) {
+	backoffs := []time.Duration{}
+	m := NewQueueRetryManager(queue, keyFunc, retry, time.Second, 5*time.Second)
+	m.after = func(d time.Duration, f func()) {
+		backoffs = append(backoffs, d)
+		f()
+	}
+	return m, &backoffs
+}
+
+func TestQueueRetryManagerBackoff(t *testing.T) {
+	queue := &fakeQueue{}
+	m, backoffs := newTestRetryManager(queue, RetryLimit(10))
+
+	for i := 0; i < 5; i++ {
+		m.Retry("a", errors.New("failed"))
+	}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	if !reflect.DeepEqual(expected, *backoffs) {
+		t.Errorf("expected backoffs %v, got %v", expected, *backoffs)
+	}
+	if len(queue.items) != 5 {
+		t.Errorf("expected the object to be queued 5 times, got %d", len(queue.items))
+	}
+
+	m.Forget("a")
+	m.Retry("a", errors.New("failed"))
+	if e, a := time.Second, (*backoffs)[len(*backoffs)-1]; e != a {
+		t.Errorf("expected the backoff to be reset to %v, got %v", e, a)
+	}
+}
+
+func TestQueueRetryManagerLimit(t *testing.T) {
+	queue := &fakeQueue{}
+	m, _ := newTestRetryManager(queue, RetryLimit(2))
+
+	for i := 0; i < 3; i++ {
+		m.Retry("a", errors.New("failed"))
+	}
+	if len(queue.items) != 2 {
+		t.Errorf("expected the object to be retried twice, got %d", len(queue.items))
+	}
+
+	// the retries of an object that was given up on start over
+	m.Retry("a", errors.New("failed"))
+	if len(queue.items) != 3 {
+		t.Errorf("expected the object to be retried again, got %d", len(queue.items))
+	}
+
+	queue = &fakeQueue{}
+	m, _ = newTestRetryManager(queue, RetryNever)
+	m.Retry("a", errors.New("failed"))
+	if len(queue.items) != 0 {
+		t.Errorf("expected the object not to be retried, got %d", len(queue.items))
+	}
+}
+
+type fakeRetryManager struct {
+	retried   []interface{}
+	forgotten []interface{}
+}
+
+func (m *fakeRetryManager) Retry(obj interface{}, err error) {
+	m.retried = append(m.retried, obj)
+}
+
+func (m *fakeRetryManager) Forget(obj interface{}) {
+	m.forgotten = append(m.forgotten, obj)
+}
+
+func TestRetryControllerHandleOne(t *testing.T) {
+	retryManager := &fakeRetryManager{}
+	synced := 0
+	c := &RetryController{
+		Queue:        &fakeQueue{items: []interface{}{"ok", "fail"}},
+		RetryManager: retryManager,
+		Handle: func(obj interface{}) error {
+			if obj == "fail" {
+				return errors.New("failed")
+			}
+			return nil
+		},
+		Synced: func() { synced++ },
+	}
+
+	c.handleOne()
+	c.handleOne()
+
+	if e, a := []interface{}{"ok"}, retryManager.forgotten; !reflect.DeepEqual(e, a) {
+		t.Errorf("expected forgotten %v, got %v", e, a)
+	}
+	if e, a := []interface{}{"fail"}, retryManager.retried; !reflect.DeepEqual(e, a) {
+		t.Errorf("expected retried %v, got %v", e, a)
+	}
+	if synced != 2 {
+		t.Errorf("expected Synced to be called twice, got %d", synced)
+	}
+}
+
+func TestRetryControllerStopped(t *testing.T) {
+	stop := make(chan struct{})
+	close(stop)
+	c := &RetryController{
+		Queue:        &fakeQueue{items: []interface{}{"a"}},
+		RetryManager: &fakeRetryManager{},
+		Handle: func(obj interface{}) error {
+			t.Errorf("unexpected call to Handle with %v", obj)
+			return nil
+		},
+		Stop: stop,
+	}
+	c.handleOne()
+}
+
+func TestUntilReportsPanics(t *testing.T) {
+	stop := make(chan struct{})
+	crashed := make(chan interface{})
+	runs := 0
+	go Until(func() {
+		runs++
+		if runs == 1 {
+			panic("unable to handle")
+		}
+		close(stop)
+	}, 0, stop, func(r interface{}) { crashed <- r })
+
+	select {
+	case r := <-crashed:
+		if r != "unable to handle" {
+			t.Errorf("Unexpected panic reported: %v", r)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the panic to be reported")
+	}
+	select {
+	case <-stop:
+	case <-time.After(5 * time.Second):
+		t.Errorf("Expected f to be run again after the panic")
+	}
+}
@@ -0,0 +1,3 @@
+// Package controller provides the loop that the controllers of the master use to process the objects of
+// a queue, retrying the objects that fail with an exponential backoff.
+package controller
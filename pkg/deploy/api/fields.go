@@ -0,0 +1,30 @@
+package api
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/api/selectable"
+)
+
+func init() {
+	selectable.Register(&Deployment{}, func(obj runtime.Object) labels.Set { return DeploymentToSelectableFields(obj.(*Deployment)) })
+	selectable.Register(&DeploymentConfig{}, func(obj runtime.Object) labels.Set {
+		return DeploymentConfigToSelectableFields(obj.(*DeploymentConfig))
+	})
+}
+
+// DeploymentToSelectableFields returns the fields of a deployment that clients can select on.
+func DeploymentToSelectableFields(deployment *Deployment) labels.Set {
+	return labels.Set{
+		"name":   deployment.Name,
+		"status": string(deployment.Status),
+	}
+}
+
+// DeploymentConfigToSelectableFields returns the fields of a deployment config that clients can select on.
+func DeploymentConfigToSelectableFields(config *DeploymentConfig) labels.Set {
+	return labels.Set{
+		"name": config.Name,
+	}
+}
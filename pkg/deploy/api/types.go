@@ -104,6 +104,10 @@ const (
 	// annotation value is the LatestVersion value of the DeploymentConfig which was the basis for
 	// the deployment.
 	DeploymentVersionAnnotation = "deploymentVersion"
+	// DeploymentDriftAnnotation is an annotation on a deployment (a ReplicationController) whose pod
+	// template was changed outside of the DeploymentConfig it was made from. The annotation value is
+	// the time the drift was detected.
+	DeploymentDriftAnnotation = "deploymentDrift"
 	// DeploymentLabel is the name of a label used to correlate a deployment with the Pod created
 	// to execute the deployment logic.
 	// TODO: This is a workaround for upstream's lack of annotation support on PodTemplate. Once
@@ -130,11 +134,32 @@ type DeploymentConfig struct {
 	// LatestVersion is used to determine whether the current deployment associated with a DeploymentConfig
 	// is out of sync.
 	LatestVersion int `json:"latestVersion,omitempty"`
+	// Test means that deployments of this config only validate the new pods: the new deployment is
+	// scaled up until its pods run, then back to zero, and prior deployments are left untouched.
+	Test bool `json:"test,omitempty"`
+	// Autoscale, if set, scales the latest deployment between a minimum and maximum replica count
+	// according to the CPU usage of its pods.
+	Autoscale *DeploymentAutoscaleParams `json:"autoscale,omitempty"`
+	// Reconcile means that changes made to the pod template of the latest deployment outside of the
+	// DeploymentConfig are reverted once detected, rather than only reported.  Pods already running
+	// from the changed template are left alone.
+	Reconcile bool `json:"reconcile,omitempty"`
 	// The reasons for the update to this deployment config.
 	// This could be based on a change made by the user or caused by an automatic trigger
 	Details *DeploymentDetails `json:"details,omitempty"`
 }
 
+// DeploymentAutoscaleParams are the bounds and the target the latest deployment of a DeploymentConfig
+// is autoscaled with.
+type DeploymentAutoscaleParams struct {
+	// MinReplicas is the lowest replica count the deployment is scaled to.
+	MinReplicas int `json:"minReplicas"`
+	// MaxReplicas is the highest replica count the deployment is scaled to.
+	MaxReplicas int `json:"maxReplicas"`
+	// TargetCPUMillicores is the average CPU usage per pod, in millicores, the replica count is chosen to reach.
+	TargetCPUMillicores int64 `json:"targetCPUMillicores"`
+}
+
 // DeploymentTemplate contains all the necessary information to create a deployment from a
 // DeploymentStrategy.
 type DeploymentTemplate struct {
@@ -149,6 +174,8 @@ type DeploymentTriggerPolicy struct {
 	Type DeploymentTriggerType `json:"type,omitempty"`
 	// ImageChangeParams represents the parameters for the ImageChange trigger.
 	ImageChangeParams *DeploymentTriggerImageChangeParams `json:"imageChangeParams,omitempty"`
+	// ConfigChangeParams represents the parameters for the ConfigChange trigger.
+	ConfigChangeParams *DeploymentTriggerConfigChangeParams `json:"configChangeParams,omitempty"`
 }
 
 // DeploymentTriggerType refers to a specific DeploymentTriggerPolicy implementation.
@@ -184,6 +211,14 @@ type DeploymentTriggerImageChangeParams struct {
 	Tag string `json:"tag,omitempty"`
 }
 
+// DeploymentTriggerConfigChangeParams represents the parameters to the ConfigChange trigger.
+type DeploymentTriggerConfigChangeParams struct {
+	// IgnoredFields names fields of the pod template spec whose changes do not result in a new deployment,
+	// by their JSON path within the spec (e.g. "nodeSelector" or "containers.env"). Changes to the replica
+	// count or to the labels and annotations of the template never result in a new deployment.
+	IgnoredFields []string `json:"ignoredFields,omitempty"`
+}
+
 // DeploymentDetails captures information about the causes of a deployment.
 type DeploymentDetails struct {
 	// The user specified change message, if this deployment was triggered manually by the user
@@ -223,14 +258,23 @@ type DeploymentConfigRollback struct {
 
 // DeploymentConfigRollbackSpec represents the options for rollback generation.
 type DeploymentConfigRollbackSpec struct {
-	// From points to a ReplicationController which is a deployment.
+	// From points to a ReplicationController which is a deployment, or to a DeploymentConfig whose
+	// deployment of the given Revision is rolled back to.
 	From kapi.ObjectReference `json:"from"`
+	// Revision is the LatestVersion of the deployment to roll back to when From points to a DeploymentConfig.
+	Revision int `json:"revision,omitempty"`
 	// IncludeTriggers specifies whether to include config Triggers.
-	IncludeTriggers bool `json:"includeTriggers`
+	IncludeTriggers bool `json:"includeTriggers"`
 	// IncludeTemplate specifies whether to include the PodTemplateSpec.
-	IncludeTemplate bool `json:"includeTemplate`
+	IncludeTemplate bool `json:"includeTemplate"`
 	// IncludeReplicationMeta specifies whether to include the replica count and selector.
-	IncludeReplicationMeta bool `json:"includeReplicationMeta`
+	IncludeReplicationMeta bool `json:"includeReplicationMeta"`
 	// IncludeStrategy specifies whether to include the deployment Strategy.
-	IncludeStrategy bool `json:"includeStrategy`
+	IncludeStrategy bool `json:"includeStrategy"`
+}
+
+// DeploymentLog is the (unused) resource associated with the deployment log redirector
+type DeploymentLog struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
 }
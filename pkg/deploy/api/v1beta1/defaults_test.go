@@ -0,0 +1,28 @@
+package v1beta1_test
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	newer "github.com/openshift/origin/pkg/deploy/api"
+	_ "github.com/openshift/origin/pkg/deploy/api/v1beta1"
+)
+
+func TestDefaultDeploymentStrategy(t *testing.T) {
+	obj, err := api.Scheme.Decode([]byte(`{"kind":"DeploymentConfig","apiVersion":"v1beta1","template":{"strategy":{}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config := obj.(*newer.DeploymentConfig); config.Template.Strategy.Type != newer.DeploymentStrategyTypeRecreate {
+		t.Errorf("expected the Recreate strategy, got %#v", config.Template.Strategy)
+	}
+
+	obj, err = api.Scheme.Decode([]byte(`{"kind":"Deployment","apiVersion":"v1beta1","strategy":{"type":"Custom","customParams":{"image":"deployer"}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deployment := obj.(*newer.Deployment); deployment.Strategy.Type != newer.DeploymentStrategyTypeCustom || deployment.Strategy.CustomParams.Image != "deployer" {
+		t.Errorf("expected the Custom strategy, got %#v", deployment.Strategy)
+	}
+}
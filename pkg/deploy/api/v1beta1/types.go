@@ -15,18 +15,18 @@ type Deployment struct {
 	kapi.ObjectMeta `json:"metadata,omitempty"`
 
 	// Strategy describes how a deployment is executed.
-	Strategy DeploymentStrategy `json:"strategy,omitempty"`
+	Strategy DeploymentStrategy `json:"strategy,omitempty" description:"Strategy describes how a deployment is executed."`
 	// ControllerTemplate is the desired replication state the deployment works to materialize.
-	ControllerTemplate v1beta1.ReplicationControllerState `json:"controllerTemplate,omitempty"`
+	ControllerTemplate v1beta1.ReplicationControllerState `json:"controllerTemplate,omitempty" description:"ControllerTemplate is the desired replication state the deployment works to materialize."`
 	// Status is the execution status of the deployment.
-	Status DeploymentStatus `json:"status,omitempty"`
+	Status DeploymentStatus `json:"status,omitempty" description:"Status is the execution status of the deployment."`
 	// Details captures the causes for the creation of this deployment resource.
 	// This could be based on a change made by the user to the deployment config
 	// or caused by an automatic trigger that was specified in the deployment config.
 	// Multiple triggers could have caused this deployment.
 	// If no trigger is specified here, then the deployment was likely created as a result of an
 	// explicit client request to create a new deployment resource.
-	Details *DeploymentDetails `json:"details,omitempty"`
+	Details *DeploymentDetails `json:"details,omitempty" description:"Details captures the causes for the creation of this deployment resource. This could be based on a change made by the user to the deployment config or caused by an automatic trigger that was specified in the deployment config. Multiple triggers could have caused this deployment. If no trigger is specified here, then the deployment was likely created as a result of an explicit client request to create a new deployment resource."`
 }
 
 // DeploymentStatus decribes the possible states a deployment can be in.
@@ -50,9 +50,9 @@ const (
 // DeploymentStrategy describes how to perform a deployment.
 type DeploymentStrategy struct {
 	// Type is the name of a deployment strategy.
-	Type DeploymentStrategyType `json:"type,omitempty"`
+	Type DeploymentStrategyType `json:"type,omitempty" description:"Type is the name of a deployment strategy."`
 	// CustomParams are the input to the Custom deployment strategy.
-	CustomParams *CustomDeploymentStrategyParams `json:"customParams,omitempty"`
+	CustomParams *CustomDeploymentStrategyParams `json:"customParams,omitempty" description:"CustomParams are the input to the Custom deployment strategy."`
 }
 
 // DeploymentStrategyType refers to a specific DeploymentStrategy implementation.
@@ -68,11 +68,11 @@ const (
 // CustomParams are the input to the Custom deployment strategy.
 type CustomDeploymentStrategyParams struct {
 	// Image specifies a Docker image which can carry out a deployment.
-	Image string `json:"image,omitempty"`
+	Image string `json:"image,omitempty" description:"Image specifies a Docker image which can carry out a deployment."`
 	// Environment holds the environment which will be given to the container for Image.
-	Environment []kapi.EnvVar `json:"environment,omitempty"`
+	Environment []kapi.EnvVar `json:"environment,omitempty" description:"Environment holds the environment which will be given to the container for Image."`
 	// Command is optional and overrides CMD in the container Image.
-	Command []string `json:"command,omitempty"`
+	Command []string `json:"command,omitempty" description:"Command is optional and overrides CMD in the container Image."`
 }
 
 // A DeploymentList is a collection of deployments.
@@ -125,31 +125,54 @@ type DeploymentConfig struct {
 	// Triggers determine how updates to a DeploymentConfig result in new deployments. If no triggers
 	// are defined, a new deployment can only occur as a result of an explicit client update to the
 	// DeploymentConfig with a new LatestVersion.
-	Triggers []DeploymentTriggerPolicy `json:"triggers,omitempty"`
+	Triggers []DeploymentTriggerPolicy `json:"triggers,omitempty" description:"Triggers determine how updates to a DeploymentConfig result in new deployments. If no triggers are defined, a new deployment can only occur as a result of an explicit client update to the DeploymentConfig with a new LatestVersion."`
 	// Template represents a desired deployment state and how to deploy it.
-	Template DeploymentTemplate `json:"template,omitempty"`
+	Template DeploymentTemplate `json:"template,omitempty" description:"Template represents a desired deployment state and how to deploy it."`
 	// LatestVersion is used to determine whether the current deployment associated with a DeploymentConfig
 	// is out of sync.
-	LatestVersion int `json:"latestVersion,omitempty"`
+	LatestVersion int `json:"latestVersion,omitempty" description:"LatestVersion is used to determine whether the current deployment associated with a DeploymentConfig is out of sync."`
+	// Test means that deployments of this config only validate the new pods: the new deployment is
+	// scaled up until its pods run, then back to zero, and prior deployments are left untouched.
+	Test bool `json:"test,omitempty" description:"Test means that deployments are scaled back to zero once their pods have been validated."`
+	// Autoscale, if set, scales the latest deployment between a minimum and maximum replica count
+	// according to the CPU usage of its pods.
+	Autoscale *DeploymentAutoscaleParams `json:"autoscale,omitempty" description:"Autoscale, if set, scales the latest deployment between a minimum and maximum replica count according to the CPU usage of its pods."`
+	// Reconcile means that changes made to the pod template of the latest deployment outside of the
+	// DeploymentConfig are reverted once detected, rather than only reported.  Pods already running
+	// from the changed template are left alone.
+	Reconcile bool `json:"reconcile,omitempty" description:"Reconcile means that changes made to the pod template of the latest deployment outside of the deployment config are reverted; running pods are left alone."`
 	// The reasons for the update to this deployment config.
 	// This could be based on a change made by the user or caused by an automatic trigger
-	Details *DeploymentDetails `json:"details,omitempty"`
+	Details *DeploymentDetails `json:"details,omitempty" description:"The reasons for the update to this deployment config. This could be based on a change made by the user or caused by an automatic trigger"`
+}
+
+// DeploymentAutoscaleParams are the bounds and the target the latest deployment of a DeploymentConfig
+// is autoscaled with.
+type DeploymentAutoscaleParams struct {
+	// MinReplicas is the lowest replica count the deployment is scaled to.
+	MinReplicas int `json:"minReplicas" description:"MinReplicas is the lowest replica count the deployment is scaled to."`
+	// MaxReplicas is the highest replica count the deployment is scaled to.
+	MaxReplicas int `json:"maxReplicas" description:"MaxReplicas is the highest replica count the deployment is scaled to."`
+	// TargetCPUMillicores is the average CPU usage per pod, in millicores, the replica count is chosen to reach.
+	TargetCPUMillicores int64 `json:"targetCPUMillicores" description:"TargetCPUMillicores is the average CPU usage per pod, in millicores, the replica count is chosen to reach."`
 }
 
 // DeploymentTemplate contains all the necessary information to create a deployment from a
 // DeploymentStrategy.
 type DeploymentTemplate struct {
 	// Strategy describes how a deployment is executed.
-	Strategy DeploymentStrategy `json:"strategy,omitempty"`
+	Strategy DeploymentStrategy `json:"strategy,omitempty" description:"Strategy describes how a deployment is executed."`
 	// ControllerTemplate is the desired replication state the deployment works to materialize.
-	ControllerTemplate v1beta1.ReplicationControllerState `json:"controllerTemplate,omitempty"`
+	ControllerTemplate v1beta1.ReplicationControllerState `json:"controllerTemplate,omitempty" description:"ControllerTemplate is the desired replication state the deployment works to materialize."`
 }
 
 // DeploymentTriggerPolicy describes a policy for a single trigger that results in a new deployment.
 type DeploymentTriggerPolicy struct {
 	Type DeploymentTriggerType `json:"type,omitempty"`
 	// ImageChangeParams represents the parameters for the ImageChange trigger.
-	ImageChangeParams *DeploymentTriggerImageChangeParams `json:"imageChangeParams,omitempty"`
+	ImageChangeParams *DeploymentTriggerImageChangeParams `json:"imageChangeParams,omitempty" description:"ImageChangeParams represents the parameters for the ImageChange trigger."`
+	// ConfigChangeParams represents the parameters for the ConfigChange trigger.
+	ConfigChangeParams *DeploymentTriggerConfigChangeParams `json:"configChangeParams,omitempty" description:"ConfigChangeParams represents the parameters for the ConfigChange trigger."`
 }
 
 // DeploymentTriggerType refers to a specific DeploymentTriggerPolicy implementation.
@@ -169,43 +192,51 @@ const (
 // DeploymentTriggerImageChangeParams represents the parameters to the ImageChange trigger.
 type DeploymentTriggerImageChangeParams struct {
 	// Automatic means that the detection of a new tag value should result in a new deployment.
-	Automatic bool `json:"automatic,omitempty"`
+	Automatic bool `json:"automatic,omitempty" description:"Automatic means that the detection of a new tag value should result in a new deployment."`
 	// ContainerNames is used to restrict tag updates to the specified set of container names in a pod.
-	ContainerNames []string `json:"containerNames,omitempty"`
+	ContainerNames []string `json:"containerNames,omitempty" description:"ContainerNames is used to restrict tag updates to the specified set of container names in a pod."`
 	// RepositoryName is the identifier for a Docker image repository to watch for changes.
 	// DEPRECATED: will be removed in v1beta2.
-	RepositoryName string `json:"repositoryName,omitempty"`
+	RepositoryName string `json:"repositoryName,omitempty" description:"RepositoryName is the identifier for a Docker image repository to watch for changes. DEPRECATED: will be removed in v1beta2."`
 	// From is a reference to a Docker image repository to watch for changes. This field takes
 	// precedence over RepositoryName, which is deprecated and will be removed in v1beta2. The
 	// Kind may be left blank, in which case it defaults to "ImageRepository". The "Name" is
 	// the only required subfield - if Namespace is blank, the namespace of the current deployment
 	// trigger will be used.
-	From kapi.ObjectReference `json:"from"`
+	From kapi.ObjectReference `json:"from" description:"From is a reference to a Docker image repository to watch for changes. This field takes precedence over RepositoryName, which is deprecated and will be removed in v1beta2. The Kind may be left blank, in which case it defaults to 'ImageRepository'. The 'Name' is the only required subfield - if Namespace is blank, the namespace of the current deployment trigger will be used."`
 	// Tag is the name of an image repository tag to watch for changes.
-	Tag string `json:"tag,omitempty"`
+	Tag string `json:"tag,omitempty" description:"Tag is the name of an image repository tag to watch for changes."`
+}
+
+// DeploymentTriggerConfigChangeParams represents the parameters to the ConfigChange trigger.
+type DeploymentTriggerConfigChangeParams struct {
+	// IgnoredFields names fields of the pod template spec whose changes do not result in a new deployment,
+	// by their JSON path within the spec (e.g. "nodeSelector" or "containers.env"). Changes to the replica
+	// count or to the labels and annotations of the template never result in a new deployment.
+	IgnoredFields []string `json:"ignoredFields,omitempty" description:"IgnoredFields names fields of the pod template spec whose changes do not result in a new deployment."`
 }
 
 // DeploymentDetails captures information about the causes of a deployment.
 type DeploymentDetails struct {
 	// The user specified change message, if this deployment was triggered manually by the user
-	Message string `json:"message,omitempty"`
+	Message string `json:"message,omitempty" description:"The user specified change message, if this deployment was triggered manually by the user"`
 	// Extended data associated with all the causes for creating a new deployment
-	Causes []*DeploymentCause `json:"causes,omitempty"`
+	Causes []*DeploymentCause `json:"causes,omitempty" description:"Extended data associated with all the causes for creating a new deployment"`
 }
 
 // DeploymentCause captures information about a particular cause of a deployment.
 type DeploymentCause struct {
 	// The type of the trigger that resulted in the creation of a new deployment
-	Type DeploymentTriggerType `json:"type"`
+	Type DeploymentTriggerType `json:"type" description:"The type of the trigger that resulted in the creation of a new deployment"`
 	// The image trigger details, if this trigger was fired based on an image change
-	ImageTrigger *DeploymentCauseImageTrigger `json:"imageTrigger,omitempty"`
+	ImageTrigger *DeploymentCauseImageTrigger `json:"imageTrigger,omitempty" description:"The image trigger details, if this trigger was fired based on an image change"`
 }
 
 type DeploymentCauseImageTrigger struct {
 	// RepositoryName is the identifier for a Docker image repository that was updated.
-	RepositoryName string `json:"repositoryName,omitempty"`
+	RepositoryName string `json:"repositoryName,omitempty" description:"RepositoryName is the identifier for a Docker image repository that was updated."`
 	// Tag is the name of an image repository tag that is now pointing to a new image.
-	Tag string `json:"tag,omitempty"`
+	Tag string `json:"tag,omitempty" description:"Tag is the name of an image repository tag that is now pointing to a new image."`
 }
 
 // A DeploymentConfigList is a collection of deployment configs.
@@ -219,19 +250,28 @@ type DeploymentConfigList struct {
 type DeploymentConfigRollback struct {
 	kapi.TypeMeta `json:",inline"`
 	// Spec defines the options to rollback generation.
-	Spec DeploymentConfigRollbackSpec `json:"spec"`
+	Spec DeploymentConfigRollbackSpec `json:"spec" description:"Spec defines the options to rollback generation."`
 }
 
 // DeploymentConfigRollbackSpec represents the options for rollback generation.
 type DeploymentConfigRollbackSpec struct {
-	// From points to a ReplicationController which is a deployment.
-	From kapi.ObjectReference `json:"from"`
+	// From points to a ReplicationController which is a deployment, or to a DeploymentConfig whose
+	// deployment of the given Revision is rolled back to.
+	From kapi.ObjectReference `json:"from" description:"From points to a ReplicationController which is a deployment, or to a DeploymentConfig whose deployment of the given Revision is rolled back to."`
+	// Revision is the LatestVersion of the deployment to roll back to when From points to a DeploymentConfig.
+	Revision int `json:"revision,omitempty" description:"Revision is the LatestVersion of the deployment to roll back to when From points to a DeploymentConfig."`
 	// IncludeTriggers specifies whether to include config Triggers.
-	IncludeTriggers bool `json:"includeTriggers`
+	IncludeTriggers bool `json:"includeTriggers"`
 	// IncludeTemplate specifies whether to include the PodTemplateSpec.
-	IncludeTemplate bool `json:"includeTemplate`
+	IncludeTemplate bool `json:"includeTemplate"`
 	// IncludeReplicationMeta specifies whether to include the replica count and selector.
-	IncludeReplicationMeta bool `json:"includeReplicationMeta`
+	IncludeReplicationMeta bool `json:"includeReplicationMeta"`
 	// IncludeStrategy specifies whether to include the deployment Strategy.
-	IncludeStrategy bool `json:"includeStrategy`
+	IncludeStrategy bool `json:"includeStrategy"`
+}
+
+// DeploymentLog is the (unused) resource associated with the deployment log redirector
+type DeploymentLog struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
 }
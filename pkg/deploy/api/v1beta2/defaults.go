@@ -0,0 +1,29 @@
+package v1beta2
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/conversion"
+
+	newer "github.com/openshift/origin/pkg/deploy/api"
+)
+
+// Defaults are set as objects are converted from v1beta2, so objects are fully specified when they are
+// created and when they are read back from storage.
+func init() {
+	err := api.Scheme.AddConversionFuncs(
+		// Deployments recreate their pods unless another strategy is chosen
+		func(in *DeploymentStrategy, out *newer.DeploymentStrategy, s conversion.Scope) error {
+			if err := s.DefaultConvert(in, out, conversion.IgnoreMissingFields); err != nil {
+				return err
+			}
+			if len(out.Type) == 0 {
+				out.Type = newer.DeploymentStrategyTypeRecreate
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		// If one of the conversion functions is malformed, detect it immediately.
+		panic(err)
+	}
+}
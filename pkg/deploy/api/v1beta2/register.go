@@ -0,0 +1,23 @@
+package v1beta2
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+func init() {
+	api.Scheme.AddKnownTypes("v1beta2",
+		&Deployment{},
+		&DeploymentList{},
+		&DeploymentConfig{},
+		&DeploymentConfigList{},
+		&DeploymentConfigRollback{},
+		&DeploymentLog{},
+	)
+}
+
+func (*Deployment) IsAnAPIObject()               {}
+func (*DeploymentList) IsAnAPIObject()           {}
+func (*DeploymentConfig) IsAnAPIObject()         {}
+func (*DeploymentConfigList) IsAnAPIObject()     {}
+func (*DeploymentConfigRollback) IsAnAPIObject() {}
+func (*DeploymentLog) IsAnAPIObject()            {}
@@ -0,0 +1,277 @@
+package v1beta2
+
+import (
+	v1beta2 "github.com/GoogleCloudPlatform/kubernetes/pkg/api/v1beta2"
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api/v1beta3"
+)
+
+// A deployment represents a single configuration of a pod deployed into the cluster, and may
+// represent both a current deployment or a historical deployment.
+//
+// DEPRECATED: This type longer drives any system behavior. Deployments are now represented directly
+// by ReplicationControllers. Use DeploymentConfig to drive deployments.
+type Deployment struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	// Strategy describes how a deployment is executed.
+	Strategy DeploymentStrategy `json:"strategy,omitempty" description:"Strategy describes how a deployment is executed."`
+	// ControllerTemplate is the desired replication state the deployment works to materialize.
+	ControllerTemplate v1beta2.ReplicationControllerState `json:"controllerTemplate,omitempty" description:"ControllerTemplate is the desired replication state the deployment works to materialize."`
+	// Status is the execution status of the deployment.
+	Status DeploymentStatus `json:"status,omitempty" description:"Status is the execution status of the deployment."`
+	// Details captures the causes for the creation of this deployment resource.
+	// This could be based on a change made by the user to the deployment config
+	// or caused by an automatic trigger that was specified in the deployment config.
+	// Multiple triggers could have caused this deployment.
+	// If no trigger is specified here, then the deployment was likely created as a result of an
+	// explicit client request to create a new deployment resource.
+	Details *DeploymentDetails `json:"details,omitempty" description:"Details captures the causes for the creation of this deployment resource. This could be based on a change made by the user to the deployment config or caused by an automatic trigger that was specified in the deployment config. Multiple triggers could have caused this deployment. If no trigger is specified here, then the deployment was likely created as a result of an explicit client request to create a new deployment resource."`
+}
+
+// DeploymentStatus decribes the possible states a deployment can be in.
+type DeploymentStatus string
+
+const (
+	// DeploymentStatusNew means the deployment has been accepted but not yet acted upon.
+	DeploymentStatusNew DeploymentStatus = "New"
+	// DeploymentStatusPending means the deployment been handed over to a deployment strategy,
+	// but the strategy has not yet declared the deployment to be running.
+	DeploymentStatusPending DeploymentStatus = "Pending"
+	// DeploymentStatusRunning means the deployment strategy has reported the deployment as
+	// being in-progress.
+	DeploymentStatusRunning DeploymentStatus = "Running"
+	// DeploymentStatusComplete means the deployment finished without an error.
+	DeploymentStatusComplete DeploymentStatus = "Complete"
+	// DeploymentStatusFailed means the deployment finished with an error.
+	DeploymentStatusFailed DeploymentStatus = "Failed"
+)
+
+// DeploymentStrategy describes how to perform a deployment.
+type DeploymentStrategy struct {
+	// Type is the name of a deployment strategy.
+	Type DeploymentStrategyType `json:"type,omitempty" description:"Type is the name of a deployment strategy."`
+	// CustomParams are the input to the Custom deployment strategy.
+	CustomParams *CustomDeploymentStrategyParams `json:"customParams,omitempty" description:"CustomParams are the input to the Custom deployment strategy."`
+}
+
+// DeploymentStrategyType refers to a specific DeploymentStrategy implementation.
+type DeploymentStrategyType string
+
+const (
+	// DeploymentStrategyTypeRecreate is a simple strategy suitable as a default.
+	DeploymentStrategyTypeRecreate DeploymentStrategyType = "Recreate"
+	// DeploymentStrategyTypeCustom is a user defined strategy.
+	DeploymentStrategyTypeCustom DeploymentStrategyType = "Custom"
+)
+
+// CustomParams are the input to the Custom deployment strategy.
+type CustomDeploymentStrategyParams struct {
+	// Image specifies a Docker image which can carry out a deployment.
+	Image string `json:"image,omitempty" description:"Image specifies a Docker image which can carry out a deployment."`
+	// Environment holds the environment which will be given to the container for Image.
+	Environment []kapi.EnvVar `json:"environment,omitempty" description:"Environment holds the environment which will be given to the container for Image."`
+	// Command is optional and overrides CMD in the container Image.
+	Command []string `json:"command,omitempty" description:"Command is optional and overrides CMD in the container Image."`
+}
+
+// A DeploymentList is a collection of deployments.
+// DEPRECATED: Like Deployment, this is no longer used.
+type DeploymentList struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
+	Items         []Deployment `json:"items"`
+}
+
+// These constants represent keys used for correlating objects related to deployments.
+const (
+	// DeploymentConfigAnnotation is an annotation name used to correlate a deployment with the
+	// DeploymentConfig on which the deployment is based.
+	DeploymentConfigAnnotation = "deploymentConfig"
+	// DeploymentAnnotation is an annotation on a deployer Pod. The annotation value is the name
+	// of the deployment (a ReplicationController) on which the deployer Pod acts.
+	DeploymentAnnotation = "deployment"
+	// DeploymentPodAnnotation is an annotation on a deployment (a ReplicationController). The
+	// annotation value is the name of the deployer Pod which will act upon the ReplicationController
+	// to implement the deployment behavior.
+	DeploymentPodAnnotation = "pod"
+	// DeploymentStatusAnnotation is an annotation name used to retrieve the DeploymentStatus of
+	// a deployment.
+	DeploymentStatusAnnotation = "deploymentStatus"
+	// DeploymentEncodedConfigAnnotation is an annotation name used to retrieve specific encoded
+	// DeploymentConfig on which a given deployment is based.
+	DeploymentEncodedConfigAnnotation = "encodedDeploymentConfig"
+	// DeploymentVersionAnnotation is an annotation on a deployment (a ReplicationController). The
+	// annotation value is the LatestVersion value of the DeploymentConfig which was the basis for
+	// the deployment.
+	DeploymentVersionAnnotation = "deploymentVersion"
+	// DeploymentLabel is the name of a label used to correlate a deployment with the Pod created
+	// to execute the deployment logic.
+	// TODO: This is a workaround for upstream's lack of annotation support on PodTemplate. Once
+	// annotations are available on PodTemplate, audit this constant with the goal of removing it.
+	DeploymentLabel = "deployment"
+	// DeploymentConfigLabel is the name of a label used to correlate a deployment with the
+	// DeploymentConfigs on which the deployment is based.
+	DeploymentConfigLabel = "deploymentconfig"
+)
+
+// DeploymentConfig represents a configuration for a single deployment (represented as a
+// ReplicationController). It also contains details about changes which resulted in the current
+// state of the DeploymentConfig. Each change to the DeploymentConfig which should result in
+// a new deployment results in an increment of LatestVersion.
+type DeploymentConfig struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+	// Triggers determine how updates to a DeploymentConfig result in new deployments. If no triggers
+	// are defined, a new deployment can only occur as a result of an explicit client update to the
+	// DeploymentConfig with a new LatestVersion.
+	Triggers []DeploymentTriggerPolicy `json:"triggers,omitempty" description:"Triggers determine how updates to a DeploymentConfig result in new deployments. If no triggers are defined, a new deployment can only occur as a result of an explicit client update to the DeploymentConfig with a new LatestVersion."`
+	// Template represents a desired deployment state and how to deploy it.
+	Template DeploymentTemplate `json:"template,omitempty" description:"Template represents a desired deployment state and how to deploy it."`
+	// LatestVersion is used to determine whether the current deployment associated with a DeploymentConfig
+	// is out of sync.
+	LatestVersion int `json:"latestVersion,omitempty" description:"LatestVersion is used to determine whether the current deployment associated with a DeploymentConfig is out of sync."`
+	// Test means that deployments of this config only validate the new pods: the new deployment is
+	// scaled up until its pods run, then back to zero, and prior deployments are left untouched.
+	Test bool `json:"test,omitempty" description:"Test means that deployments are scaled back to zero once their pods have been validated."`
+	// Autoscale, if set, scales the latest deployment between a minimum and maximum replica count
+	// according to the CPU usage of its pods.
+	Autoscale *DeploymentAutoscaleParams `json:"autoscale,omitempty" description:"Autoscale, if set, scales the latest deployment between a minimum and maximum replica count according to the CPU usage of its pods."`
+	// Reconcile means that changes made to the pod template of the latest deployment outside of the
+	// DeploymentConfig are reverted once detected, rather than only reported.  Pods already running
+	// from the changed template are left alone.
+	Reconcile bool `json:"reconcile,omitempty" description:"Reconcile means that changes made to the pod template of the latest deployment outside of the deployment config are reverted; running pods are left alone."`
+	// The reasons for the update to this deployment config.
+	// This could be based on a change made by the user or caused by an automatic trigger
+	Details *DeploymentDetails `json:"details,omitempty" description:"The reasons for the update to this deployment config. This could be based on a change made by the user or caused by an automatic trigger"`
+}
+
+// DeploymentAutoscaleParams are the bounds and the target the latest deployment of a DeploymentConfig
+// is autoscaled with.
+type DeploymentAutoscaleParams struct {
+	// MinReplicas is the lowest replica count the deployment is scaled to.
+	MinReplicas int `json:"minReplicas" description:"MinReplicas is the lowest replica count the deployment is scaled to."`
+	// MaxReplicas is the highest replica count the deployment is scaled to.
+	MaxReplicas int `json:"maxReplicas" description:"MaxReplicas is the highest replica count the deployment is scaled to."`
+	// TargetCPUMillicores is the average CPU usage per pod, in millicores, the replica count is chosen to reach.
+	TargetCPUMillicores int64 `json:"targetCPUMillicores" description:"TargetCPUMillicores is the average CPU usage per pod, in millicores, the replica count is chosen to reach."`
+}
+
+// DeploymentTemplate contains all the necessary information to create a deployment from a
+// DeploymentStrategy.
+type DeploymentTemplate struct {
+	// Strategy describes how a deployment is executed.
+	Strategy DeploymentStrategy `json:"strategy,omitempty" description:"Strategy describes how a deployment is executed."`
+	// ControllerTemplate is the desired replication state the deployment works to materialize.
+	ControllerTemplate v1beta2.ReplicationControllerState `json:"controllerTemplate,omitempty" description:"ControllerTemplate is the desired replication state the deployment works to materialize."`
+}
+
+// DeploymentTriggerPolicy describes a policy for a single trigger that results in a new deployment.
+type DeploymentTriggerPolicy struct {
+	Type DeploymentTriggerType `json:"type,omitempty"`
+	// ImageChangeParams represents the parameters for the ImageChange trigger.
+	ImageChangeParams *DeploymentTriggerImageChangeParams `json:"imageChangeParams,omitempty" description:"ImageChangeParams represents the parameters for the ImageChange trigger."`
+	// ConfigChangeParams represents the parameters for the ConfigChange trigger.
+	ConfigChangeParams *DeploymentTriggerConfigChangeParams `json:"configChangeParams,omitempty" description:"ConfigChangeParams represents the parameters for the ConfigChange trigger."`
+}
+
+// DeploymentTriggerType refers to a specific DeploymentTriggerPolicy implementation.
+type DeploymentTriggerType string
+
+const (
+	// DeploymentTriggerManual is a placeholder implementation which does nothing.
+	DeploymentTriggerManual DeploymentTriggerType = "Manual"
+	// DeploymentTriggerOnImageChange will create new deployments in response to updated tags from
+	// a Docker image repository.
+	DeploymentTriggerOnImageChange DeploymentTriggerType = "ImageChange"
+	// DeploymentTriggerOnConfigChange will create new deployments in response to changes to
+	// the ControllerTemplate of a DeploymentConfig.
+	DeploymentTriggerOnConfigChange DeploymentTriggerType = "ConfigChange"
+)
+
+// DeploymentTriggerImageChangeParams represents the parameters to the ImageChange trigger.
+type DeploymentTriggerImageChangeParams struct {
+	// Automatic means that the detection of a new tag value should result in a new deployment.
+	Automatic bool `json:"automatic,omitempty" description:"Automatic means that the detection of a new tag value should result in a new deployment."`
+	// ContainerNames is used to restrict tag updates to the specified set of container names in a pod.
+	ContainerNames []string `json:"containerNames,omitempty" description:"ContainerNames is used to restrict tag updates to the specified set of container names in a pod."`
+	// RepositoryName is the identifier for a Docker image repository to watch for changes.
+	// DEPRECATED: use From.
+	RepositoryName string `json:"repositoryName,omitempty" description:"RepositoryName is the identifier for a Docker image repository to watch for changes. DEPRECATED: use From."`
+	// From is a reference to a Docker image repository to watch for changes. This field takes
+	// precedence over RepositoryName, which is deprecated. The
+	// Kind may be left blank, in which case it defaults to "ImageRepository". The "Name" is
+	// the only required subfield - if Namespace is blank, the namespace of the current deployment
+	// trigger will be used.
+	From kapi.ObjectReference `json:"from" description:"From is a reference to a Docker image repository to watch for changes. This field takes precedence over RepositoryName, which is deprecated. The Kind may be left blank, in which case it defaults to 'ImageRepository'. The 'Name' is the only required subfield - if Namespace is blank, the namespace of the current deployment trigger will be used."`
+	// Tag is the name of an image repository tag to watch for changes.
+	Tag string `json:"tag,omitempty" description:"Tag is the name of an image repository tag to watch for changes."`
+}
+
+// DeploymentTriggerConfigChangeParams represents the parameters to the ConfigChange trigger.
+type DeploymentTriggerConfigChangeParams struct {
+	// IgnoredFields names fields of the pod template spec whose changes do not result in a new deployment,
+	// by their JSON path within the spec (e.g. "nodeSelector" or "containers.env"). Changes to the replica
+	// count or to the labels and annotations of the template never result in a new deployment.
+	IgnoredFields []string `json:"ignoredFields,omitempty" description:"IgnoredFields names fields of the pod template spec whose changes do not result in a new deployment."`
+}
+
+// DeploymentDetails captures information about the causes of a deployment.
+type DeploymentDetails struct {
+	// The user specified change message, if this deployment was triggered manually by the user
+	Message string `json:"message,omitempty" description:"The user specified change message, if this deployment was triggered manually by the user"`
+	// Extended data associated with all the causes for creating a new deployment
+	Causes []*DeploymentCause `json:"causes,omitempty" description:"Extended data associated with all the causes for creating a new deployment"`
+}
+
+// DeploymentCause captures information about a particular cause of a deployment.
+type DeploymentCause struct {
+	// The type of the trigger that resulted in the creation of a new deployment
+	Type DeploymentTriggerType `json:"type" description:"The type of the trigger that resulted in the creation of a new deployment"`
+	// The image trigger details, if this trigger was fired based on an image change
+	ImageTrigger *DeploymentCauseImageTrigger `json:"imageTrigger,omitempty" description:"The image trigger details, if this trigger was fired based on an image change"`
+}
+
+type DeploymentCauseImageTrigger struct {
+	// RepositoryName is the identifier for a Docker image repository that was updated.
+	RepositoryName string `json:"repositoryName,omitempty" description:"RepositoryName is the identifier for a Docker image repository that was updated."`
+	// Tag is the name of an image repository tag that is now pointing to a new image.
+	Tag string `json:"tag,omitempty" description:"Tag is the name of an image repository tag that is now pointing to a new image."`
+}
+
+// A DeploymentConfigList is a collection of deployment configs.
+type DeploymentConfigList struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
+	Items         []DeploymentConfig `json:"items"`
+}
+
+// DeploymentConfigRollback provides the input to rollback generation.
+type DeploymentConfigRollback struct {
+	kapi.TypeMeta `json:",inline"`
+	// Spec defines the options to rollback generation.
+	Spec DeploymentConfigRollbackSpec `json:"spec" description:"Spec defines the options to rollback generation."`
+}
+
+// DeploymentConfigRollbackSpec represents the options for rollback generation.
+type DeploymentConfigRollbackSpec struct {
+	// From points to a ReplicationController which is a deployment, or to a DeploymentConfig whose
+	// deployment of the given Revision is rolled back to.
+	From kapi.ObjectReference `json:"from" description:"From points to a ReplicationController which is a deployment, or to a DeploymentConfig whose deployment of the given Revision is rolled back to."`
+	// Revision is the LatestVersion of the deployment to roll back to when From points to a DeploymentConfig.
+	Revision int `json:"revision,omitempty" description:"Revision is the LatestVersion of the deployment to roll back to when From points to a DeploymentConfig."`
+	// IncludeTriggers specifies whether to include config Triggers.
+	IncludeTriggers bool `json:"includeTriggers" description:"IncludeTriggers specifies whether to include config Triggers."`
+	// IncludeTemplate specifies whether to include the PodTemplateSpec.
+	IncludeTemplate bool `json:"includeTemplate" description:"IncludeTemplate specifies whether to include the PodTemplateSpec."`
+	// IncludeReplicationMeta specifies whether to include the replica count and selector.
+	IncludeReplicationMeta bool `json:"includeReplicationMeta" description:"IncludeReplicationMeta specifies whether to include the replica count and selector."`
+	// IncludeStrategy specifies whether to include the deployment Strategy.
+	IncludeStrategy bool `json:"includeStrategy" description:"IncludeStrategy specifies whether to include the deployment Strategy."`
+}
+
+// DeploymentLog is the (unused) resource associated with the deployment log redirector
+type DeploymentLog struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
+}
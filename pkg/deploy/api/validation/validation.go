@@ -1,11 +1,14 @@
 package validation
 
 import (
+	"fmt"
+
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/validation"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
 )
 
 // TODO: These tests validate the ReplicationControllerState in a Deployment or DeploymentConfig.
@@ -29,6 +32,14 @@ func ValidateDeployment(deployment *deployapi.Deployment) errors.ValidationError
 	return errs
 }
 
+// ValidateDeploymentUpdate tests that deployment is valid and does not change the immutable fields of
+// oldDeployment.
+func ValidateDeploymentUpdate(deployment, oldDeployment *deployapi.Deployment) errors.ValidationErrorList {
+	errs := ValidateDeployment(deployment)
+	errs = append(errs, validation.ValidateObjectMetaUpdate(&oldDeployment.ObjectMeta, &deployment.ObjectMeta)...)
+	return errs
+}
+
 func ValidateDeploymentConfig(config *deployapi.DeploymentConfig) errors.ValidationErrorList {
 	errs := errors.ValidationErrorList{}
 	if len(config.Name) == 0 {
@@ -48,6 +59,44 @@ func ValidateDeploymentConfig(config *deployapi.DeploymentConfig) errors.Validat
 	}
 	errs = append(errs, validateDeploymentStrategy(&config.Template.Strategy).Prefix("template.strategy")...)
 	errs = append(errs, validation.ValidateReplicationControllerSpec(&config.Template.ControllerTemplate).Prefix("template.controllerTemplate")...)
+	if config.Test && config.Template.Strategy.Type == deployapi.DeploymentStrategyTypeCustom {
+		errs = append(errs, errors.NewFieldInvalid("test", config.Test, "test deployments are not supported by the Custom strategy"))
+	}
+	if config.Test && config.Autoscale != nil {
+		errs = append(errs, errors.NewFieldInvalid("test", config.Test, "test deployments cannot be autoscaled"))
+	}
+	if config.Autoscale != nil {
+		errs = append(errs, validateAutoscaleParams(config.Autoscale).Prefix("autoscale")...)
+	}
+	return errs
+}
+
+func validateAutoscaleParams(params *deployapi.DeploymentAutoscaleParams) errors.ValidationErrorList {
+	errs := errors.ValidationErrorList{}
+
+	if params.MinReplicas < 0 {
+		errs = append(errs, errors.NewFieldInvalid("minReplicas", params.MinReplicas, "must not be negative"))
+	}
+	if params.MaxReplicas < 1 {
+		errs = append(errs, errors.NewFieldInvalid("maxReplicas", params.MaxReplicas, "must be greater than zero"))
+	} else if params.MaxReplicas < params.MinReplicas {
+		errs = append(errs, errors.NewFieldInvalid("maxReplicas", params.MaxReplicas, "must not be less than minReplicas"))
+	}
+	if params.TargetCPUMillicores <= 0 {
+		errs = append(errs, errors.NewFieldInvalid("targetCPUMillicores", params.TargetCPUMillicores, "must be greater than zero"))
+	}
+
+	return errs
+}
+
+// ValidateDeploymentConfigUpdate tests that config is valid and does not change the immutable fields of
+// oldConfig.  The latest version of a config only increases, since deployments are named after it.
+func ValidateDeploymentConfigUpdate(config, oldConfig *deployapi.DeploymentConfig) errors.ValidationErrorList {
+	errs := ValidateDeploymentConfig(config)
+	errs = append(errs, validation.ValidateObjectMetaUpdate(&oldConfig.ObjectMeta, &config.ObjectMeta)...)
+	if config.LatestVersion < oldConfig.LatestVersion {
+		errs = append(errs, errors.NewFieldInvalid("latestVersion", config.LatestVersion, "latestVersion cannot be decreased"))
+	}
 	return errs
 }
 
@@ -59,11 +108,24 @@ func ValidateDeploymentConfigRollback(rollback *deployapi.DeploymentConfigRollba
 	}
 
 	if len(rollback.Spec.From.Kind) == 0 {
-		rollback.Spec.From.Kind = "ReplicationController"
+		if rollback.Spec.Revision != 0 {
+			rollback.Spec.From.Kind = "DeploymentConfig"
+		} else {
+			rollback.Spec.From.Kind = "ReplicationController"
+		}
 	}
 
-	if rollback.Spec.From.Kind != "ReplicationController" {
-		result = append(result, errors.NewFieldInvalid("spec.from.kind", rollback.Spec.From.Kind, "the kind of the rollback target must be 'ReplicationController'"))
+	switch rollback.Spec.From.Kind {
+	case "ReplicationController":
+		if rollback.Spec.Revision != 0 {
+			result = append(result, errors.NewFieldInvalid("spec.revision", rollback.Spec.Revision, "a revision may only be given when rolling back a 'DeploymentConfig'"))
+		}
+	case "DeploymentConfig":
+		if rollback.Spec.Revision <= 0 {
+			result = append(result, errors.NewFieldInvalid("spec.revision", rollback.Spec.Revision, "the revision to roll back to must be greater than zero"))
+		}
+	default:
+		result = append(result, errors.NewFieldInvalid("spec.from.kind", rollback.Spec.From.Kind, "the kind of the rollback target must be 'ReplicationController' or 'DeploymentConfig'"))
 	}
 
 	return result
@@ -113,6 +175,22 @@ func validateTrigger(trigger *deployapi.DeploymentTriggerPolicy) errors.Validati
 		}
 	}
 
+	if trigger.ConfigChangeParams != nil {
+		errs = append(errs, validateConfigChangeParams(trigger.ConfigChangeParams).Prefix("configChangeParams")...)
+	}
+
+	return errs
+}
+
+func validateConfigChangeParams(params *deployapi.DeploymentTriggerConfigChangeParams) errors.ValidationErrorList {
+	errs := errors.ValidationErrorList{}
+
+	for i, field := range params.IgnoredFields {
+		if !deployutil.IsPodSpecFieldPath(field) {
+			errs = append(errs, errors.NewFieldInvalid(fmt.Sprintf("ignoredFields[%d]", i), field, "must name a field of the pod template spec"))
+		}
+	}
+
 	return errs
 }
 
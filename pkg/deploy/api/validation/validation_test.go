@@ -132,6 +132,66 @@ func TestValidateDeploymentConfigMissingFields(t *testing.T) {
 			errors.ValidationErrorTypeRequired,
 			"triggers[0].type",
 		},
+		"test with a custom strategy": {
+			api.DeploymentConfig{
+				ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: "bar"},
+				Triggers:   manualTrigger(),
+				Template: api.DeploymentTemplate{
+					Strategy:           test.OkCustomStrategy(),
+					ControllerTemplate: test.OkControllerTemplate(),
+				},
+				Test: true,
+			},
+			errors.ValidationErrorTypeInvalid,
+			"test",
+		},
+		"test with autoscale": {
+			api.DeploymentConfig{
+				ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: "bar"},
+				Triggers:   manualTrigger(),
+				Template:   test.OkDeploymentTemplate(),
+				Test:       true,
+				Autoscale:  &api.DeploymentAutoscaleParams{MinReplicas: 1, MaxReplicas: 2, TargetCPUMillicores: 100},
+			},
+			errors.ValidationErrorTypeInvalid,
+			"test",
+		},
+		"autoscale.maxReplicas less than minReplicas": {
+			api.DeploymentConfig{
+				ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: "bar"},
+				Triggers:   manualTrigger(),
+				Template:   test.OkDeploymentTemplate(),
+				Autoscale:  &api.DeploymentAutoscaleParams{MinReplicas: 3, MaxReplicas: 2, TargetCPUMillicores: 500},
+			},
+			errors.ValidationErrorTypeInvalid,
+			"autoscale.maxReplicas",
+		},
+		"missing autoscale.targetCPUMillicores": {
+			api.DeploymentConfig{
+				ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: "bar"},
+				Triggers:   manualTrigger(),
+				Template:   test.OkDeploymentTemplate(),
+				Autoscale:  &api.DeploymentAutoscaleParams{MinReplicas: 1, MaxReplicas: 2},
+			},
+			errors.ValidationErrorTypeInvalid,
+			"autoscale.targetCPUMillicores",
+		},
+		"invalid Trigger configChangeParams.ignoredFields": {
+			api.DeploymentConfig{
+				ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: "bar"},
+				Triggers: []api.DeploymentTriggerPolicy{
+					{
+						Type: api.DeploymentTriggerOnConfigChange,
+						ConfigChangeParams: &api.DeploymentTriggerConfigChangeParams{
+							IgnoredFields: []string{"containers.env", "replicas"},
+						},
+					},
+				},
+				Template: test.OkDeploymentTemplate(),
+			},
+			errors.ValidationErrorTypeInvalid,
+			"triggers[0].configChangeParams.ignoredFields[1]",
+		},
 		"missing Trigger imageChangeParams.from": {
 			api.DeploymentConfig{
 				ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: "bar"},
@@ -264,6 +324,26 @@ func TestValidateDeploymentConfigRollbackOK(t *testing.T) {
 	}
 }
 
+func TestValidateDeploymentConfigRollbackRevisionOK(t *testing.T) {
+	rollback := &api.DeploymentConfigRollback{
+		Spec: api.DeploymentConfigRollbackSpec{
+			From: kapi.ObjectReference{
+				Name: "config",
+			},
+			Revision: 2,
+		},
+	}
+
+	errs := ValidateDeploymentConfigRollback(rollback)
+	if len(errs) > 0 {
+		t.Errorf("Unxpected non-empty error list: %v", errs)
+	}
+
+	if e, a := "DeploymentConfig", rollback.Spec.From.Kind; e != a {
+		t.Errorf("expected kind %s, got %s", e, a)
+	}
+}
+
 func TestValidateDeploymentConfigRollbackInvalidFields(t *testing.T) {
 	errorCases := map[string]struct {
 		D api.DeploymentConfigRollback
@@ -291,6 +371,31 @@ func TestValidateDeploymentConfigRollbackInvalidFields(t *testing.T) {
 			errors.ValidationErrorTypeInvalid,
 			"spec.from.kind",
 		},
+		"spec.revision for a deployment": {
+			api.DeploymentConfigRollback{
+				Spec: api.DeploymentConfigRollbackSpec{
+					From: kapi.ObjectReference{
+						Kind: "ReplicationController",
+						Name: "deployment",
+					},
+					Revision: 1,
+				},
+			},
+			errors.ValidationErrorTypeInvalid,
+			"spec.revision",
+		},
+		"missing spec.revision for a config": {
+			api.DeploymentConfigRollback{
+				Spec: api.DeploymentConfigRollbackSpec{
+					From: kapi.ObjectReference{
+						Kind: "DeploymentConfig",
+						Name: "config",
+					},
+				},
+			},
+			errors.ValidationErrorTypeInvalid,
+			"spec.revision",
+		},
 	}
 
 	for k, v := range errorCases {
@@ -308,3 +413,46 @@ func TestValidateDeploymentConfigRollbackInvalidFields(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateDeploymentConfigUpdate(t *testing.T) {
+	oldConfig := test.OkDeploymentConfig(2)
+
+	if errs := ValidateDeploymentConfigUpdate(test.OkDeploymentConfig(3), oldConfig); len(errs) > 0 {
+		t.Errorf("Unxpected non-empty error list: %#v", errs)
+	}
+
+	errorCases := map[string]struct {
+		D *api.DeploymentConfig
+		T errors.ValidationErrorType
+		F string
+	}{
+		"latestVersion decreased": {
+			test.OkDeploymentConfig(1),
+			errors.ValidationErrorTypeInvalid,
+			"latestVersion",
+		},
+		"name changed": {
+			func() *api.DeploymentConfig {
+				config := test.OkDeploymentConfig(2)
+				config.Name = "other"
+				return config
+			}(),
+			errors.ValidationErrorTypeInvalid,
+			"name",
+		},
+	}
+
+	for k, v := range errorCases {
+		errs := ValidateDeploymentConfigUpdate(v.D, oldConfig)
+		if len(errs) != 1 {
+			t.Errorf("%s: expected one error, got %v", k, errs)
+			continue
+		}
+		if errs[0].(*errors.ValidationError).Type != v.T {
+			t.Errorf("%s: expected errors to have type %s: %v", k, v.T, errs[0])
+		}
+		if errs[0].(*errors.ValidationError).Field != v.F {
+			t.Errorf("%s: expected errors to have field %s: %v", k, v.F, errs[0])
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+	"github.com/google/cadvisor/info"
+
+	osclient "github.com/openshift/origin/pkg/client"
+	oscontroller "github.com/openshift/origin/pkg/controller"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
+)
+
+// AutoscaleController periodically scales the latest deployment of every DeploymentConfig with
+// autoscaling parameters to the replica count that brings the average CPU usage of its running pods
+// closest to the target, within the configured bounds.  Deployments still in progress are left alone.
+type AutoscaleController struct {
+	// Client provides access to DeploymentConfigs, deployments and their pods.
+	Client AutoscaleClient
+	// CPUUsage returns the current CPU usage of a pod in millicores.
+	CPUUsage CPUUsageFunc
+	// Period is the interval between scaling decisions.
+	Period time.Duration
+	// Synced is an optional function called after each pass.
+	Synced func()
+	// Crashed is an optional function called with the value of a panic in the controller.
+	Crashed func(interface{})
+	// Stop is an optional channel that controls when the controller exits.
+	Stop <-chan struct{}
+}
+
+// AutoscaleClient is the access to DeploymentConfigs, deployments and pods the AutoscaleController needs.
+type AutoscaleClient interface {
+	ListDeploymentConfigs() (*deployapi.DeploymentConfigList, error)
+	GetDeployment(namespace, name string) (*kapi.ReplicationController, error)
+	UpdateDeployment(namespace string, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error)
+	ListPods(namespace string, selector labels.Selector) (*kapi.PodList, error)
+}
+
+// CPUUsageFunc returns the current CPU usage of pod in millicores.
+type CPUUsageFunc func(pod *kapi.Pod) (int64, error)
+
+// ErrNotEnoughSamples is returned by a CPUUsageFunc for a pod that has not been running long enough to
+// measure its usage.  Such pods are left out of the scaling decision.
+var ErrNotEnoughSamples = errors.New("not enough samples")
+
+// Run begins scaling deployments every Period.
+func (c *AutoscaleController) Run() {
+	go oscontroller.Until(func() {
+		c.HandleDeploymentConfigs()
+		if c.Synced != nil {
+			c.Synced()
+		}
+	}, c.Period, c.Stop, c.Crashed)
+}
+
+// HandleDeploymentConfigs scales the latest deployment of every DeploymentConfig with autoscaling
+// parameters.
+func (c *AutoscaleController) HandleDeploymentConfigs() {
+	configs, err := c.Client.ListDeploymentConfigs()
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to list deployment configs: %v", err))
+		return
+	}
+	for i := range configs.Items {
+		config := &configs.Items[i]
+		if config.Autoscale == nil || config.LatestVersion == 0 {
+			continue
+		}
+		if err := c.scale(config); err != nil {
+			util.HandleError(fmt.Errorf("unable to autoscale deployment config %s/%s: %v", config.Namespace, config.Name, err))
+		}
+	}
+}
+
+// scale updates the replica count of the latest deployment of config if it differs from the desired one.
+func (c *AutoscaleController) scale(config *deployapi.DeploymentConfig) error {
+	deployment, err := c.Client.GetDeployment(config.Namespace, deployutil.LatestDeploymentNameForConfig(config))
+	if err != nil {
+		return err
+	}
+	if status := deployapi.DeploymentStatus(deployment.Annotations[deployapi.DeploymentStatusAnnotation]); status != deployapi.DeploymentStatusComplete {
+		glog.V(4).Infof("Not autoscaling deployment %s/%s with status %s", deployment.Namespace, deployment.Name, status)
+		return nil
+	}
+
+	pods, err := c.Client.ListPods(deployment.Namespace, labels.SelectorFromSet(deployment.Spec.Selector))
+	if err != nil {
+		return err
+	}
+	running, usage := 0, int64(0)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != kapi.PodRunning {
+			continue
+		}
+		millicores, err := c.CPUUsage(pod)
+		if err == ErrNotEnoughSamples {
+			glog.V(4).Infof("Not counting pod %s/%s in the CPU usage of deployment %s yet: %v", pod.Namespace, pod.Name, deployment.Name, err)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("unable to measure the CPU usage of pod %s: %v", pod.Name, err)
+		}
+		running++
+		usage += millicores
+	}
+
+	desired := deployment.Spec.Replicas
+	if running > 0 {
+		// The smallest replica count that keeps the average usage at or below the target.
+		desired = int((usage + config.Autoscale.TargetCPUMillicores - 1) / config.Autoscale.TargetCPUMillicores)
+	}
+	if desired < config.Autoscale.MinReplicas {
+		desired = config.Autoscale.MinReplicas
+	}
+	if desired > config.Autoscale.MaxReplicas {
+		desired = config.Autoscale.MaxReplicas
+	}
+	if desired == deployment.Spec.Replicas {
+		return nil
+	}
+
+	glog.V(2).Infof("Autoscaling deployment %s/%s from %d to %d replicas for %dm of CPU used by %d pods", deployment.Namespace, deployment.Name, deployment.Spec.Replicas, desired, usage, running)
+	deployment.Spec.Replicas = desired
+	_, err = c.Client.UpdateDeployment(deployment.Namespace, deployment)
+	return err
+}
+
+// NewAutoscaleClient returns an AutoscaleClient backed by the OpenShift and Kubernetes clients.
+func NewAutoscaleClient(osClient osclient.Interface, kubeClient kclient.Interface) AutoscaleClient {
+	return &realDeploymentClient{osClient, kubeClient}
+}
+
+// realDeploymentClient provides access to DeploymentConfigs, deployments and pods for the periodic
+// deployment controllers.
+type realDeploymentClient struct {
+	osClient   osclient.Interface
+	kubeClient kclient.Interface
+}
+
+func (c *realDeploymentClient) ListDeploymentConfigs() (*deployapi.DeploymentConfigList, error) {
+	return c.osClient.DeploymentConfigs(kapi.NamespaceAll).List(labels.Everything(), labels.Everything())
+}
+
+func (c *realDeploymentClient) GetDeployment(namespace, name string) (*kapi.ReplicationController, error) {
+	return c.kubeClient.ReplicationControllers(namespace).Get(name)
+}
+
+func (c *realDeploymentClient) UpdateDeployment(namespace string, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+	return c.kubeClient.ReplicationControllers(namespace).Update(deployment)
+}
+
+func (c *realDeploymentClient) ListPods(namespace string, selector labels.Selector) (*kapi.PodList, error) {
+	return c.kubeClient.Pods(namespace).List(selector)
+}
+
+// NewCadvisorCPUUsage returns a CPUUsageFunc that measures the CPU usage of the containers of a pod over
+// the last two samples cAdvisor reports through the kubelet of its host.
+func NewCadvisorCPUUsage(getter kclient.ContainerInfoGetter) CPUUsageFunc {
+	return func(pod *kapi.Pod) (int64, error) {
+		podID := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, pod.UID)
+		total := int64(0)
+		for _, container := range pod.Spec.Containers {
+			containerInfo, err := getter.GetContainerInfo(pod.Status.Host, podID, container.Name, &info.ContainerInfoRequest{NumStats: 2})
+			if err != nil {
+				return 0, err
+			}
+			stats := containerInfo.Stats
+			if len(stats) < 2 {
+				return 0, ErrNotEnoughSamples
+			}
+			previous, latest := stats[len(stats)-2], stats[len(stats)-1]
+			elapsed := latest.Timestamp.Sub(previous.Timestamp)
+			if elapsed <= 0 || latest.Cpu.Usage.Total < previous.Cpu.Usage.Total {
+				return 0, fmt.Errorf("inconsistent samples of container %s", container.Name)
+			}
+			// CPU time is reported in nanoseconds, so the rate is in cores.
+			total += int64(latest.Cpu.Usage.Total-previous.Cpu.Usage.Total) * 1000 / int64(elapsed)
+		}
+		return total, nil
+	}
+}
@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/google/cadvisor/info"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployapitest "github.com/openshift/origin/pkg/deploy/api/test"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
+)
+
+type testAutoscaleClient struct {
+	configs    []deployapi.DeploymentConfig
+	deployment *kapi.ReplicationController
+	pods       []kapi.Pod
+	updated    *kapi.ReplicationController
+}
+
+func (c *testAutoscaleClient) ListDeploymentConfigs() (*deployapi.DeploymentConfigList, error) {
+	return &deployapi.DeploymentConfigList{Items: c.configs}, nil
+}
+
+func (c *testAutoscaleClient) GetDeployment(namespace, name string) (*kapi.ReplicationController, error) {
+	if name != c.deployment.Name {
+		return nil, errors.New("not found")
+	}
+	return c.deployment, nil
+}
+
+func (c *testAutoscaleClient) UpdateDeployment(namespace string, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+	c.updated = deployment
+	return deployment, nil
+}
+
+func (c *testAutoscaleClient) ListPods(namespace string, selector labels.Selector) (*kapi.PodList, error) {
+	return &kapi.PodList{Items: c.pods}, nil
+}
+
+func TestAutoscaleController(t *testing.T) {
+	testCases := map[string]struct {
+		status   deployapi.DeploymentStatus
+		replicas int
+		usage    []int64
+		expected int
+	}{
+		"scale up":              {deployapi.DeploymentStatusComplete, 2, []int64{900, 800}, 4},
+		"scale down":            {deployapi.DeploymentStatusComplete, 3, []int64{100, 100, 50}, 1},
+		"scale up to the max":   {deployapi.DeploymentStatusComplete, 2, []int64{2000, 2000}, 5},
+		"scale down to the min": {deployapi.DeploymentStatusComplete, 3, []int64{0, 0, 0}, 1},
+		"no running pods":       {deployapi.DeploymentStatusComplete, 7, []int64{}, 5},
+		"unchanged":             {deployapi.DeploymentStatusComplete, 2, []int64{400, 500}, 0},
+		"deployment running":    {deployapi.DeploymentStatusRunning, 2, []int64{2000, 2000}, 0},
+		// -1 stands for a pod without enough samples yet
+		"pod without samples": {deployapi.DeploymentStatusComplete, 3, []int64{900, -1, -1}, 2},
+	}
+
+	for name, test := range testCases {
+		config := deployapitest.OkDeploymentConfig(1)
+		config.Autoscale = &deployapi.DeploymentAutoscaleParams{MinReplicas: 1, MaxReplicas: 5, TargetCPUMillicores: 500}
+		deployment, _ := deployutil.MakeDeployment(config, kapi.Codec)
+		deployment.Annotations[deployapi.DeploymentStatusAnnotation] = string(test.status)
+		deployment.Spec.Replicas = test.replicas
+
+		usage := map[string]int64{}
+		pods := []kapi.Pod{{ObjectMeta: kapi.ObjectMeta{Name: "pending"}, Status: kapi.PodStatus{Phase: kapi.PodPending}}}
+		for i, millicores := range test.usage {
+			name := string(rune('a' + i))
+			usage[name] = millicores
+			pods = append(pods, kapi.Pod{ObjectMeta: kapi.ObjectMeta{Name: name}, Status: kapi.PodStatus{Phase: kapi.PodRunning}})
+		}
+
+		unscaled := deployapitest.OkDeploymentConfig(1)
+		client := &testAutoscaleClient{configs: []deployapi.DeploymentConfig{*unscaled, *config}, deployment: deployment, pods: pods}
+		controller := &AutoscaleController{
+			Client: client,
+			CPUUsage: func(pod *kapi.Pod) (int64, error) {
+				millicores, ok := usage[pod.Name]
+				if !ok {
+					t.Errorf("%s: unexpected measure of pod %s", name, pod.Name)
+				}
+				if millicores < 0 {
+					return 0, ErrNotEnoughSamples
+				}
+				return millicores, nil
+			},
+		}
+		controller.HandleDeploymentConfigs()
+
+		if test.expected == 0 {
+			if client.updated != nil {
+				t.Errorf("%s: unexpected update to %d replicas", name, client.updated.Spec.Replicas)
+			}
+			continue
+		}
+		if client.updated == nil {
+			t.Errorf("%s: expected the deployment to be scaled to %d replicas", name, test.expected)
+			continue
+		}
+		if client.updated.Spec.Replicas != test.expected {
+			t.Errorf("%s: expected %d replicas, got %d", name, test.expected, client.updated.Spec.Replicas)
+		}
+	}
+}
+
+type testContainerInfoGetter map[string][]*info.ContainerStats
+
+func (g testContainerInfoGetter) GetContainerInfo(host, podID, containerID string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error) {
+	if host != "node" || podID != "default/pod/uid" {
+		return nil, errors.New("unexpected pod")
+	}
+	return &info.ContainerInfo{Stats: g[containerID]}, nil
+}
+
+func (g testContainerInfoGetter) GetRootInfo(host string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error) {
+	return nil, errors.New("unexpected call")
+}
+
+func (g testContainerInfoGetter) GetMachineInfo(host string) (*info.MachineInfo, error) {
+	return nil, errors.New("unexpected call")
+}
+
+func cpuSample(at time.Time, total uint64) *info.ContainerStats {
+	stats := &info.ContainerStats{Timestamp: at}
+	stats.Cpu.Usage.Total = total
+	return stats
+}
+
+func TestCadvisorCPUUsage(t *testing.T) {
+	now := time.Now()
+	getter := testContainerInfoGetter{
+		// half a core over ten seconds
+		"web": {cpuSample(now.Add(-10*time.Second), 1e9), cpuSample(now, 6e9)},
+		// a quarter of a core over two seconds
+		"sidecar": {cpuSample(now.Add(-4*time.Second), 0), cpuSample(now.Add(-2*time.Second), 5e8), cpuSample(now, 1e9)},
+	}
+	pod := &kapi.Pod{
+		ObjectMeta: kapi.ObjectMeta{Name: "pod", Namespace: kapi.NamespaceDefault, UID: "uid"},
+		Spec:       kapi.PodSpec{Containers: []kapi.Container{{Name: "web"}, {Name: "sidecar"}}},
+		Status:     kapi.PodStatus{Host: "node"},
+	}
+
+	usage, err := NewCadvisorCPUUsage(getter)(pod)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if usage != 750 {
+		t.Errorf("Expected 750 millicores, got %d", usage)
+	}
+
+	getter["web"] = getter["web"][1:]
+	if _, err := NewCadvisorCPUUsage(getter)(pod); err != ErrNotEnoughSamples {
+		t.Errorf("Expected ErrNotEnoughSamples for a container with a single sample, got %v", err)
+	}
+}
@@ -1,7 +1,10 @@
 package controller
 
 import (
+	"fmt"
+
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	cache "github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	runtime "github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	util "github.com/GoogleCloudPlatform/kubernetes/pkg/util"
@@ -14,13 +17,11 @@ import (
 
 // DeploymentConfigChangeController watches for changes to DeploymentConfigs and regenerates them only
 // when detecting a change to the PodTemplate of a DeploymentConfig containing a ConfigChange trigger.
+// Changes to the fields the trigger ignores do not regenerate the DeploymentConfig.
 type DeploymentConfigChangeController struct {
-	ChangeStrategy       ChangeStrategy
-	NextDeploymentConfig func() *deployapi.DeploymentConfig
-	DeploymentStore      cache.Store
-	Codec                runtime.Codec
-	// Stop is an optional channel that controls when the controller exits
-	Stop <-chan struct{}
+	ChangeStrategy  ChangeStrategy
+	DeploymentStore cache.Store
+	Codec           runtime.Codec
 }
 
 // ChangeStrategy knows how to generate and update DeploymentConfigs.
@@ -29,43 +30,40 @@ type ChangeStrategy interface {
 	UpdateDeploymentConfig(namespace string, config *deployapi.DeploymentConfig) (*deployapi.DeploymentConfig, error)
 }
 
-// Run watches for config change events.
-func (dc *DeploymentConfigChangeController) Run() {
-	go util.Until(func() { dc.HandleDeploymentConfig() }, 0, dc.Stop)
-}
-
-// HandleDeploymentConfig handles the next DeploymentConfig change that happens.
-func (dc *DeploymentConfigChangeController) HandleDeploymentConfig() {
-	config := dc.NextDeploymentConfig()
-
+// HandleDeploymentConfig handles a change to a DeploymentConfig.  An error is returned if the
+// DeploymentConfig should be retried.
+func (dc *DeploymentConfigChangeController) HandleDeploymentConfig(config *deployapi.DeploymentConfig) error {
 	hasChangeTrigger := false
+	ignoredFields := []string{}
 	for _, trigger := range config.Triggers {
 		if trigger.Type == deployapi.DeploymentTriggerOnConfigChange {
 			hasChangeTrigger = true
+			if trigger.ConfigChangeParams != nil {
+				ignoredFields = trigger.ConfigChangeParams.IgnoredFields
+			}
 			break
 		}
 	}
 
 	if !hasChangeTrigger {
 		glog.V(4).Infof("Config has no change trigger; skipping")
-		return
+		return nil
 	}
 
 	if config.LatestVersion == 0 {
 		glog.V(4).Infof("Creating new deployment for config %v", config.Name)
-		dc.generateDeployment(config, nil)
-		return
+		return dc.generateDeployment(config, nil)
 	}
 
 	latestDeploymentID := deployutil.LatestDeploymentNameForConfig(config)
 	obj, exists, err := dc.DeploymentStore.Get(&kapi.ReplicationController{ObjectMeta: kapi.ObjectMeta{Name: latestDeploymentID, Namespace: config.Namespace}})
 	if err != nil {
-		glog.Errorf("Unable to retrieve deployment from store: %v", err)
+		return fmt.Errorf("unable to retrieve deployment from store: %v", err)
 	}
 
 	if !exists {
 		glog.V(4).Info("Ignoring config change due to lack of existing deployment")
-		return
+		return nil
 	}
 
 	deployment := obj.(*kapi.ReplicationController)
@@ -73,23 +71,22 @@ func (dc *DeploymentConfigChangeController) HandleDeploymentConfig() {
 	deployedConfig, err := deployutil.DecodeDeploymentConfig(deployment, dc.Codec)
 	if err != nil {
 		glog.V(0).Infof("Error decoding deploymentConfig from deployment %s: %v", deployment.Name, err)
-		return
+		return nil
 	}
 
-	if deployutil.PodSpecsEqual(config.Template.ControllerTemplate.Template.Spec, deployedConfig.Template.ControllerTemplate.Template.Spec) {
+	if deployutil.PodSpecsEqualIgnoring(config.Template.ControllerTemplate.Template.Spec, deployedConfig.Template.ControllerTemplate.Template.Spec, ignoredFields) {
 		glog.V(4).Infof("Ignoring updated config %s with LatestVersion=%d because it matches deployed config %s", config.Name, config.LatestVersion, deployment.Name)
-		return
+		return nil
 	}
 	glog.V(4).Infof("Diff:\n%s", util.ObjectDiff(config.Template.ControllerTemplate.Template.Spec, deployedConfig.Template.ControllerTemplate.Template.Spec))
 
-	dc.generateDeployment(config, deployment)
+	return dc.generateDeployment(config, deployment)
 }
 
-func (dc *DeploymentConfigChangeController) generateDeployment(config *deployapi.DeploymentConfig, deployment *kapi.ReplicationController) {
+func (dc *DeploymentConfigChangeController) generateDeployment(config *deployapi.DeploymentConfig, deployment *kapi.ReplicationController) error {
 	newConfig, err := dc.ChangeStrategy.GenerateDeploymentConfig(config.Namespace, config.Name)
 	if err != nil {
-		glog.V(2).Infof("Error generating new version of deploymentConfig %v: %#v", config.Name, err)
-		return
+		return fmt.Errorf("error generating new version of deploymentConfig %v: %v", config.Name, err)
 	}
 
 	if newConfig.LatestVersion == config.LatestVersion {
@@ -114,6 +111,11 @@ func (dc *DeploymentConfigChangeController) generateDeployment(config *deployapi
 	// okay - we can just ignore the update for the old resource and any changes to the more
 	// current config will be captured in future events.
 	if _, err = dc.ChangeStrategy.UpdateDeploymentConfig(config.Namespace, newConfig); err != nil {
-		glog.V(2).Infof("Error updating deploymentConfig %v: %#v", config.Name, err)
+		if kerrors.IsConflict(err) {
+			glog.V(2).Infof("Ignoring update of outdated deploymentConfig %v: %v", config.Name, err)
+			return nil
+		}
+		return fmt.Errorf("error updating deploymentConfig %v: %v", config.Name, err)
 	}
+	return nil
 }
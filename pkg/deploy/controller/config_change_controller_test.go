@@ -29,15 +29,12 @@ func TestNewConfigWithoutTrigger(t *testing.T) {
 				return config, nil
 			},
 		},
-		NextDeploymentConfig: func() *deployapi.DeploymentConfig {
-			config := deployapitest.OkDeploymentConfig(1)
-			config.Triggers = []deployapi.DeploymentTriggerPolicy{}
-			return config
-		},
 		DeploymentStore: deploytest.NewFakeDeploymentStore(nil),
 	}
 
-	controller.HandleDeploymentConfig()
+	config := deployapitest.OkDeploymentConfig(1)
+	config.Triggers = []deployapi.DeploymentTriggerPolicy{}
+	controller.HandleDeploymentConfig(config)
 
 	if generated {
 		t.Error("Unexpected generation of deploymentConfig")
@@ -62,15 +59,12 @@ func TestNewConfigWithTrigger(t *testing.T) {
 				return config, nil
 			},
 		},
-		NextDeploymentConfig: func() *deployapi.DeploymentConfig {
-			config := deployapitest.OkDeploymentConfig(0)
-			config.Triggers = []deployapi.DeploymentTriggerPolicy{deployapitest.OkConfigChangeTrigger()}
-			return config
-		},
 		DeploymentStore: deploytest.NewFakeDeploymentStore(nil),
 	}
 
-	controller.HandleDeploymentConfig()
+	config := deployapitest.OkDeploymentConfig(0)
+	config.Triggers = []deployapi.DeploymentTriggerPolicy{deployapitest.OkConfigChangeTrigger()}
+	controller.HandleDeploymentConfig(config)
 
 	if updated == nil {
 		t.Fatalf("expected config to be updated")
@@ -105,16 +99,13 @@ func TestChangeWithTemplateDiff(t *testing.T) {
 				return config, nil
 			},
 		},
-		NextDeploymentConfig: func() *deployapi.DeploymentConfig {
-			config := deployapitest.OkDeploymentConfig(1)
-			config.Triggers = []deployapi.DeploymentTriggerPolicy{deployapitest.OkConfigChangeTrigger()}
-			config.Template.ControllerTemplate.Template.Spec.Containers[1].Name = "modified"
-			return config
-		},
 		DeploymentStore: deploytest.NewFakeDeploymentStore(deployment),
 	}
 
-	controller.HandleDeploymentConfig()
+	config := deployapitest.OkDeploymentConfig(1)
+	config.Triggers = []deployapi.DeploymentTriggerPolicy{deployapitest.OkConfigChangeTrigger()}
+	config.Template.ControllerTemplate.Template.Spec.Containers[1].Name = "modified"
+	controller.HandleDeploymentConfig(config)
 
 	if updated == nil {
 		t.Fatalf("expected config to be updated")
@@ -154,13 +145,10 @@ func TestChangeWithoutTemplateDiff(t *testing.T) {
 				return config, nil
 			},
 		},
-		NextDeploymentConfig: func() *deployapi.DeploymentConfig {
-			return config
-		},
 		DeploymentStore: deploytest.NewFakeDeploymentStore(deployment),
 	}
 
-	controller.HandleDeploymentConfig()
+	controller.HandleDeploymentConfig(config)
 
 	if generated {
 		t.Error("Unexpected generation of deploymentConfig")
@@ -171,6 +159,47 @@ func TestChangeWithoutTemplateDiff(t *testing.T) {
 	}
 }
 
+// Test the controller's response when only fields ignored by the trigger are changed
+func TestChangeWithIgnoredTemplateDiff(t *testing.T) {
+	config := deployapitest.OkDeploymentConfig(1)
+	trigger := deployapitest.OkConfigChangeTrigger()
+	trigger.ConfigChangeParams = &deployapi.DeploymentTriggerConfigChangeParams{
+		IgnoredFields: []string{"nodeSelector", "containers.env"},
+	}
+	config.Triggers = []deployapi.DeploymentTriggerPolicy{trigger}
+	config.Template.ControllerTemplate.Replicas = 5
+	config.Template.ControllerTemplate.Template.Spec.NodeSelector = map[string]string{"region": "east"}
+	config.Template.ControllerTemplate.Template.Spec.Containers[0].Env = []kapi.EnvVar{{Name: "FOO", Value: "bar"}}
+
+	deployment, _ := deployutil.MakeDeployment(deployapitest.OkDeploymentConfig(1), kapi.Codec)
+
+	updated := false
+	controller := &DeploymentConfigChangeController{
+		Codec: api.Codec,
+		ChangeStrategy: &testChangeStrategy{
+			GenerateDeploymentConfigFunc: func(namespace, name string) (*deployapi.DeploymentConfig, error) {
+				return deployapitest.OkDeploymentConfig(2), nil
+			},
+			UpdateDeploymentConfigFunc: func(namespace string, config *deployapi.DeploymentConfig) (*deployapi.DeploymentConfig, error) {
+				updated = true
+				return config, nil
+			},
+		},
+		DeploymentStore: deploytest.NewFakeDeploymentStore(deployment),
+	}
+
+	controller.HandleDeploymentConfig(config)
+	if updated {
+		t.Error("Unexpected update of deploymentConfig for ignored fields")
+	}
+
+	config.Template.ControllerTemplate.Template.Spec.Containers[0].Image = "registry:8080/repo1:changed"
+	controller.HandleDeploymentConfig(config)
+	if !updated {
+		t.Error("Expected the deploymentConfig to be updated for a field that is not ignored")
+	}
+}
+
 type testChangeStrategy struct {
 	GenerateDeploymentConfigFunc func(namespace, name string) (*deployapi.DeploymentConfig, error)
 	UpdateDeploymentConfigFunc   func(namespace string, config *deployapi.DeploymentConfig) (*deployapi.DeploymentConfig, error)
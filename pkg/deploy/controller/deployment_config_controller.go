@@ -9,6 +9,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/golang/glog"
 
+	"github.com/openshift/origin/pkg/client/record"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	deployutil "github.com/openshift/origin/pkg/deploy/util"
 )
@@ -22,12 +23,10 @@ import (
 type DeploymentConfigController struct {
 	// DeploymentInterface provides access to Deployments.
 	DeploymentInterface dccDeploymentInterface
-	// NextDeploymentConfig blocks until the next DeploymentConfig is available.
-	NextDeploymentConfig func() *deployapi.DeploymentConfig
 	// Codec is used to encode DeploymentConfigs which are stored on deployments.
 	Codec runtime.Codec
-	// Stop is an optional channel that controls when the controller exits.
-	Stop <-chan struct{}
+	// Recorder records the events of the lifecycle of DeploymentConfigs.
+	Recorder record.Recorder
 }
 
 // dccDeploymentInterface is a small private interface for dealing with Deployments.
@@ -36,34 +35,33 @@ type dccDeploymentInterface interface {
 	CreateDeployment(namespace string, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error)
 }
 
-// Process DeploymentConfig events one at a time.
-func (c *DeploymentConfigController) Run() {
-	go util.Until(c.HandleDeploymentConfig, 0, c.Stop)
-}
-
-// Process a single DeploymentConfig event.
-func (c *DeploymentConfigController) HandleDeploymentConfig() {
-	config := c.NextDeploymentConfig()
+// HandleDeploymentConfig creates the deployment of the latest version of a DeploymentConfig if it does not
+// exist.  An error is returned if the DeploymentConfig should be retried.
+func (c *DeploymentConfigController) HandleDeploymentConfig(config *deployapi.DeploymentConfig) error {
 	deploy, err := c.shouldDeploy(config)
 	if err != nil {
-		util.HandleError(fmt.Errorf("unable to decide whether to redeploy %s: %v", labelFor(config), err))
-		return
+		return fmt.Errorf("unable to decide whether to redeploy %s: %v", labelFor(config), err)
 	}
 	if !deploy {
-		return
+		return nil
 	}
 
 	deployment, err := deployutil.MakeDeployment(config, c.Codec)
 	if err != nil {
+		// the config would fail the same way when retried
 		util.HandleError(fmt.Errorf("unable to create deployment for %s: %v", labelFor(config), err))
-		return
+		return nil
 	}
 
 	glog.V(4).Infof("Deploying %s", labelFor(config))
-	if _, deployErr := c.DeploymentInterface.CreateDeployment(config.Namespace, deployment); deployErr != nil {
-		util.HandleError(fmt.Errorf("unable to create deployment %s: %v", labelFor(config), err))
-		return
+	if _, err := c.DeploymentInterface.CreateDeployment(config.Namespace, deployment); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return nil
+		}
+		return fmt.Errorf("unable to create deployment %s: %v", labelFor(config), err)
 	}
+	c.Recorder.Eventf(config, "created", "Created deployment %s", deployment.Name)
+	return nil
 }
 
 // shouldDeploy returns true if the DeploymentConfig should have a new Deployment created.
@@ -1,20 +1,22 @@
 package controller
 
 import (
+	"reflect"
 	"testing"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 
 	api "github.com/openshift/origin/pkg/api/latest"
-	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	"github.com/openshift/origin/pkg/client/record"
 	deploytest "github.com/openshift/origin/pkg/deploy/api/test"
 	deployutil "github.com/openshift/origin/pkg/deploy/util"
 )
 
 func TestHandleNewDeploymentConfig(t *testing.T) {
 	controller := &DeploymentConfigController{
-		Codec: api.Codec,
+		Recorder: &record.FakeRecorder{},
+		Codec:    api.Codec,
 		DeploymentInterface: &testDeploymentInterface{
 			GetDeploymentFunc: func(namespace, name string) (*kapi.ReplicationController, error) {
 				t.Fatalf("unexpected call with name %s", name)
@@ -25,12 +27,9 @@ func TestHandleNewDeploymentConfig(t *testing.T) {
 				return nil, nil
 			},
 		},
-		NextDeploymentConfig: func() *deployapi.DeploymentConfig {
-			return deploytest.OkDeploymentConfig(0)
-		},
 	}
 
-	controller.HandleDeploymentConfig()
+	controller.HandleDeploymentConfig(deploytest.OkDeploymentConfig(0))
 }
 
 func TestHandleInitialDeployment(t *testing.T) {
@@ -38,7 +37,8 @@ func TestHandleInitialDeployment(t *testing.T) {
 	var deployed *kapi.ReplicationController
 
 	controller := &DeploymentConfigController{
-		Codec: api.Codec,
+		Recorder: &record.FakeRecorder{},
+		Codec:    api.Codec,
 		DeploymentInterface: &testDeploymentInterface{
 			GetDeploymentFunc: func(namespace, name string) (*kapi.ReplicationController, error) {
 				return nil, kerrors.NewNotFound("replicationController", name)
@@ -48,23 +48,24 @@ func TestHandleInitialDeployment(t *testing.T) {
 				return deployment, nil
 			},
 		},
-		NextDeploymentConfig: func() *deployapi.DeploymentConfig {
-			return deploymentConfig
-		},
 	}
 
-	controller.HandleDeploymentConfig()
+	controller.HandleDeploymentConfig(deploymentConfig)
 
 	if deployed == nil {
 		t.Fatalf("expected a deployment")
 	}
+	if e, a := []string{"created Created deployment " + deployed.Name}, controller.Recorder.(*record.FakeRecorder).Events; !reflect.DeepEqual(e, a) {
+		t.Errorf("expected events %v, got %v", e, a)
+	}
 }
 
 func TestHandleConfigChangeLatestAlreadyDeployed(t *testing.T) {
 	deploymentConfig := deploytest.OkDeploymentConfig(0)
 
 	controller := &DeploymentConfigController{
-		Codec: api.Codec,
+		Recorder: &record.FakeRecorder{},
+		Codec:    api.Codec,
 		DeploymentInterface: &testDeploymentInterface{
 			GetDeploymentFunc: func(namespace, name string) (*kapi.ReplicationController, error) {
 				deployment, _ := deployutil.MakeDeployment(deploymentConfig, kapi.Codec)
@@ -75,12 +76,9 @@ func TestHandleConfigChangeLatestAlreadyDeployed(t *testing.T) {
 				return nil, nil
 			},
 		},
-		NextDeploymentConfig: func() *deployapi.DeploymentConfig {
-			return deploymentConfig
-		},
 	}
 
-	controller.HandleDeploymentConfig()
+	controller.HandleDeploymentConfig(deploymentConfig)
 }
 
 type testDeploymentInterface struct {
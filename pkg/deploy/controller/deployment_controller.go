@@ -9,8 +9,8 @@ import (
 	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
+	"github.com/openshift/origin/pkg/client/record"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	deployutil "github.com/openshift/origin/pkg/deploy/util"
 )
@@ -26,10 +26,6 @@ type DeploymentController struct {
 	DeploymentInterface dcDeploymentInterface
 	// PodInterface provides access to pods.
 	PodInterface dcPodInterface
-	// NextDeployment blocks until the next deployment is available.
-	NextDeployment func() *kapi.ReplicationController
-	// NextPod blocks until the next pod is available.
-	NextPod func() *kapi.Pod
 	// DeploymentStore is a cache of deployments.
 	DeploymentStore cache.Store
 	// Environment is a set of environment which should be injected into all deployment pod
@@ -39,8 +35,8 @@ type DeploymentController struct {
 	UseLocalImages bool
 	// Codec is used to decode DeploymentConfigs.
 	Codec runtime.Codec
-	// Stop is an optional channel that controls when the controller exits.
-	Stop <-chan struct{}
+	// Recorder records the events of the lifecycle of deployments.
+	Recorder record.Recorder
 }
 
 // DeploymentContainerCreator knows how to create a deployment pod's container based on
@@ -58,22 +54,14 @@ type dcPodInterface interface {
 	DeletePod(namespace, id string) error
 }
 
-// Run begins watching and synchronizing deployment states.
-func (dc *DeploymentController) Run() {
-	go util.Until(func() { dc.HandleDeployment() }, 0, dc.Stop)
-	go util.Until(func() { dc.HandlePod() }, 0, dc.Stop)
-}
-
 // HandleDeployment processes a new deployment and creates a new Pod which implements the specific
 // deployment behavior. The deployment and pod are correlated with annotations. If the pod was
 // successfully created, the deployment's status is transitioned to pending; otherwise, the status
-// is transitioned to failed.
-func (dc *DeploymentController) HandleDeployment() {
-	deployment := dc.NextDeployment()
-
+// is transitioned to failed.  An error is returned if the deployment should be retried.
+func (dc *DeploymentController) HandleDeployment(deployment *kapi.ReplicationController) error {
 	if deployment.Annotations[deployapi.DeploymentStatusAnnotation] != string(deployapi.DeploymentStatusNew) {
 		glog.V(4).Infof("Ignoring deployment %s with non-New status", deployment.Name)
-		return
+		return nil
 	}
 
 	// TODO: transition to a failed state? seems like yes since this is probably not recoverable
@@ -81,7 +69,7 @@ func (dc *DeploymentController) HandleDeployment() {
 	var deploymentPodError error
 	if deploymentPod, deploymentPodError = dc.makeDeploymentPod(deployment); deploymentPodError != nil {
 		glog.V(0).Infof("Failed to make deployment pod for %s: %v", deployment.Name, deploymentPodError)
-		return
+		return nil
 	}
 
 	nextStatus := deployment.Annotations[deployapi.DeploymentStatusAnnotation]
@@ -93,9 +81,11 @@ func (dc *DeploymentController) HandleDeployment() {
 		} else {
 			glog.Infof("Error creating pod for deployment %s: %v", deployment.Name, err)
 			nextStatus = string(deployapi.DeploymentStatusFailed)
+			dc.Recorder.Eventf(deployment, "failed", "Failed to create deployer pod: %v", err)
 		}
 	} else {
 		glog.V(2).Infof("Created pod %s for deployment %s", pod.Name, deployment.Name)
+		dc.Recorder.Eventf(deployment, "started", "Started deployer pod %s", pod.Name)
 		deployment.Annotations[deployapi.DeploymentPodAnnotation] = pod.Name
 		nextStatus = string(deployapi.DeploymentStatusPending)
 	}
@@ -104,33 +94,37 @@ func (dc *DeploymentController) HandleDeployment() {
 
 	glog.V(2).Infof("Updating deployment %s status %s -> %s", deployment.Name, deployment.Status, nextStatus)
 	if _, err := dc.DeploymentInterface.UpdateDeployment(deployment.Namespace, deployment); err != nil {
-		glog.V(2).Infof("Failed to update deployment %s: %v", deployment.Name, err)
+		// the pod may have been created, which is detected when the deployment is retried
+		return fmt.Errorf("failed to update deployment %s: %v", deployment.Name, err)
 	}
+	return nil
 }
 
 // HandlePod reconciles a pod's current state with its associated deployment and updates the
-// deployment appropriately.
-func (dc *DeploymentController) HandlePod() {
-	pod := dc.NextPod()
-
+// deployment appropriately.  An error is returned if the pod should be retried.
+func (dc *DeploymentController) HandlePod(pod *kapi.Pod) error {
 	// Verify the assumption that we'll be given only pods correlated to a deployment
 	deploymentID, hasDeploymentID := pod.Annotations[deployapi.DeploymentAnnotation]
 	if !hasDeploymentID {
 		glog.V(2).Infof("Unexpected state: Pod %s has no deployment annotation; skipping", pod.Name)
-		return
+		return nil
 	}
 
 	deploymentObj, deploymentExists, err := dc.DeploymentStore.Get(&kapi.ReplicationController{ObjectMeta: kapi.ObjectMeta{Name: deploymentID, Namespace: pod.Namespace}})
 	if err != nil {
-		glog.Errorf("Unable to retrieve deployment from store: %v", err)
-		return
+		return fmt.Errorf("unable to retrieve deployment from store: %v", err)
 	}
 	if !deploymentExists {
 		glog.V(2).Infof("Couldn't find deployment %s associated with pod %s", deploymentID, pod.Name)
-		return
+		return nil
 	}
 
-	deployment := deploymentObj.(*kapi.ReplicationController)
+	// the deployment is shared with the store and must not be modified
+	copied, err := kapi.Scheme.Copy(deploymentObj.(*kapi.ReplicationController))
+	if err != nil {
+		return fmt.Errorf("unable to copy deployment %s: %v", deploymentID, err)
+	}
+	deployment := copied.(*kapi.ReplicationController)
 	nextDeploymentStatus := deployment.Annotations[deployapi.DeploymentStatusAnnotation]
 
 	switch pod.Status.Phase {
@@ -159,9 +153,16 @@ func (dc *DeploymentController) HandlePod() {
 		glog.V(2).Infof("Updating deployment %s status %s -> %s", deployment.Name, deployment.Annotations[deployapi.DeploymentStatusAnnotation], nextDeploymentStatus)
 		deployment.Annotations[deployapi.DeploymentStatusAnnotation] = nextDeploymentStatus
 		if _, err := dc.DeploymentInterface.UpdateDeployment(pod.Namespace, deployment); err != nil {
-			glog.V(2).Infof("Failed to update deployment %v: %v", deployment.Name, err)
+			return fmt.Errorf("failed to update deployment %v: %v", deployment.Name, err)
+		}
+		switch deployapi.DeploymentStatus(nextDeploymentStatus) {
+		case deployapi.DeploymentStatusComplete:
+			dc.Recorder.Eventf(deployment, "completed", "Deployer pod %s completed", pod.Name)
+		case deployapi.DeploymentStatusFailed:
+			dc.Recorder.Eventf(deployment, "failed", "Deployer pod %s failed", pod.Name)
 		}
 	}
+	return nil
 }
 
 // makeDeploymentPod creates a pod which implements deployment behavior. The pod is correlated to
@@ -8,6 +8,7 @@ import (
 	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 
 	api "github.com/openshift/origin/pkg/api/latest"
+	"github.com/openshift/origin/pkg/client/record"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	deploytest "github.com/openshift/origin/pkg/deploy/controller/test"
 	deployutil "github.com/openshift/origin/pkg/deploy/util"
@@ -21,7 +22,8 @@ func TestHandleNewDeploymentCreatePodOk(t *testing.T) {
 	)
 
 	controller := &DeploymentController{
-		Codec: api.Codec,
+		Recorder: &record.FakeRecorder{},
+		Codec:    api.Codec,
 		DeploymentInterface: &testDcDeploymentInterface{
 			UpdateDeploymentFunc: func(namespace string, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error) {
 				updatedDeployment = deployment
@@ -34,11 +36,6 @@ func TestHandleNewDeploymentCreatePodOk(t *testing.T) {
 				return pod, nil
 			},
 		},
-		NextDeployment: func() *kapi.ReplicationController {
-			deployment := basicDeployment()
-			deployment.Annotations[deployapi.DeploymentStatusAnnotation] = string(deployapi.DeploymentStatusNew)
-			return deployment
-		},
 		ContainerCreator: &testContainerCreator{
 			CreateContainerFunc: func(strategy *deployapi.DeploymentStrategy) *kapi.Container {
 				return expectedContainer
@@ -47,7 +44,11 @@ func TestHandleNewDeploymentCreatePodOk(t *testing.T) {
 	}
 
 	// Verify new -> pending
-	controller.HandleDeployment()
+	deployment := basicDeployment()
+	deployment.Annotations[deployapi.DeploymentStatusAnnotation] = string(deployapi.DeploymentStatusNew)
+	if err := controller.HandleDeployment(deployment); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	if updatedDeployment == nil {
 		t.Fatalf("expected an updated deployment")
@@ -92,7 +93,8 @@ func TestHandleNewDeploymentCreatePodFail(t *testing.T) {
 	var updatedDeployment *kapi.ReplicationController
 
 	controller := &DeploymentController{
-		Codec: api.Codec,
+		Recorder: &record.FakeRecorder{},
+		Codec:    api.Codec,
 		DeploymentInterface: &testDcDeploymentInterface{
 			UpdateDeploymentFunc: func(namspace string, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error) {
 				updatedDeployment = deployment
@@ -104,11 +106,6 @@ func TestHandleNewDeploymentCreatePodFail(t *testing.T) {
 				return nil, fmt.Errorf("Failed to create pod %s", pod.Name)
 			},
 		},
-		NextDeployment: func() *kapi.ReplicationController {
-			deployment := basicDeployment()
-			deployment.Annotations[deployapi.DeploymentStatusAnnotation] = string(deployapi.DeploymentStatusNew)
-			return deployment
-		},
 		ContainerCreator: &testContainerCreator{
 			CreateContainerFunc: func(strategy *deployapi.DeploymentStrategy) *kapi.Container {
 				return basicContainer()
@@ -117,7 +114,11 @@ func TestHandleNewDeploymentCreatePodFail(t *testing.T) {
 	}
 
 	// Verify new -> failed
-	controller.HandleDeployment()
+	deployment := basicDeployment()
+	deployment.Annotations[deployapi.DeploymentStatusAnnotation] = string(deployapi.DeploymentStatusNew)
+	if err := controller.HandleDeployment(deployment); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	if updatedDeployment == nil {
 		t.Fatalf("expected an updated deployment")
@@ -132,7 +133,8 @@ func TestHandleNewDeploymentCreatePodAlreadyExists(t *testing.T) {
 	var updatedDeployment *kapi.ReplicationController
 
 	controller := &DeploymentController{
-		Codec: api.Codec,
+		Recorder: &record.FakeRecorder{},
+		Codec:    api.Codec,
 		DeploymentInterface: &testDcDeploymentInterface{
 			UpdateDeploymentFunc: func(namespace string, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error) {
 				updatedDeployment = deployment
@@ -144,11 +146,6 @@ func TestHandleNewDeploymentCreatePodAlreadyExists(t *testing.T) {
 				return nil, kerrors.NewAlreadyExists("pod", pod.Name)
 			},
 		},
-		NextDeployment: func() *kapi.ReplicationController {
-			deployment := basicDeployment()
-			deployment.Annotations[deployapi.DeploymentStatusAnnotation] = string(deployapi.DeploymentStatusNew)
-			return deployment
-		},
 		ContainerCreator: &testContainerCreator{
 			CreateContainerFunc: func(strategy *deployapi.DeploymentStrategy) *kapi.Container {
 				return basicContainer()
@@ -157,7 +154,11 @@ func TestHandleNewDeploymentCreatePodAlreadyExists(t *testing.T) {
 	}
 
 	// Verify new -> pending
-	controller.HandleDeployment()
+	deployment := basicDeployment()
+	deployment.Annotations[deployapi.DeploymentStatusAnnotation] = string(deployapi.DeploymentStatusNew)
+	if err := controller.HandleDeployment(deployment); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	if updatedDeployment == nil {
 		t.Fatalf("expected an updated deployment")
@@ -170,30 +171,28 @@ func TestHandleNewDeploymentCreatePodAlreadyExists(t *testing.T) {
 
 func TestHandleUncorrelatedPod(t *testing.T) {
 	controller := &DeploymentController{
-		Codec: api.Codec,
+		Recorder: &record.FakeRecorder{},
+		Codec:    api.Codec,
 		DeploymentInterface: &testDcDeploymentInterface{
 			UpdateDeploymentFunc: func(namespace string, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error) {
 				t.Fatalf("Unexpected deployment update")
 				return nil, nil
 			},
 		},
-		PodInterface:   &testDcPodInterface{},
-		NextDeployment: func() *kapi.ReplicationController { return nil },
-		NextPod: func() *kapi.Pod {
-			pod := runningPod()
-			pod.Annotations = make(map[string]string)
-			return pod
-		},
+		PodInterface:    &testDcPodInterface{},
 		DeploymentStore: deploytest.NewFakeDeploymentStore(pendingDeployment()),
 	}
 
 	// Verify no-op
-	controller.HandlePod()
+	pod := runningPod()
+	pod.Annotations = make(map[string]string)
+	controller.HandlePod(pod)
 }
 
 func TestHandleOrphanedPod(t *testing.T) {
 	controller := &DeploymentController{
-		Codec: api.Codec,
+		Recorder: &record.FakeRecorder{},
+		Codec:    api.Codec,
 		DeploymentInterface: &testDcDeploymentInterface{
 			UpdateDeploymentFunc: func(namespace string, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error) {
 				t.Fatalf("Unexpected deployment update")
@@ -201,35 +200,30 @@ func TestHandleOrphanedPod(t *testing.T) {
 			},
 		},
 		PodInterface:    &testDcPodInterface{},
-		NextDeployment:  func() *kapi.ReplicationController { return nil },
-		NextPod:         func() *kapi.Pod { return runningPod() },
 		DeploymentStore: deploytest.NewFakeDeploymentStore(nil),
 	}
 
 	// Verify no-op
-	controller.HandlePod()
+	controller.HandlePod(runningPod())
 }
 
 func TestHandlePodRunning(t *testing.T) {
 	var updatedDeployment *kapi.ReplicationController
 
 	controller := &DeploymentController{
-		Codec: api.Codec,
+		Recorder: &record.FakeRecorder{},
+		Codec:    api.Codec,
 		DeploymentInterface: &testDcDeploymentInterface{
 			UpdateDeploymentFunc: func(namespace string, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error) {
 				updatedDeployment = deployment
 				return deployment, nil
 			},
 		},
-		PodInterface: &testDcPodInterface{},
-		NextDeployment: func() *kapi.ReplicationController {
-			return nil
-		},
-		NextPod:         func() *kapi.Pod { return runningPod() },
+		PodInterface:    &testDcPodInterface{},
 		DeploymentStore: deploytest.NewFakeDeploymentStore(pendingDeployment()),
 	}
 
-	controller.HandlePod()
+	controller.HandlePod(runningPod())
 
 	if updatedDeployment == nil {
 		t.Fatalf("Expected a deployment to be updated")
@@ -245,7 +239,8 @@ func TestHandlePodTerminatedOk(t *testing.T) {
 	var deletedPodID string
 
 	controller := &DeploymentController{
-		Codec: api.Codec,
+		Recorder: &record.FakeRecorder{},
+		Codec:    api.Codec,
 		DeploymentInterface: &testDcDeploymentInterface{
 			UpdateDeploymentFunc: func(namespace string, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error) {
 				updatedDeployment = deployment
@@ -258,12 +253,10 @@ func TestHandlePodTerminatedOk(t *testing.T) {
 				return nil
 			},
 		},
-		NextDeployment:  func() *kapi.ReplicationController { return nil },
-		NextPod:         func() *kapi.Pod { return succeededPod() },
 		DeploymentStore: deploytest.NewFakeDeploymentStore(runningDeployment()),
 	}
 
-	controller.HandlePod()
+	controller.HandlePod(succeededPod())
 
 	if updatedDeployment == nil {
 		t.Fatalf("Expected a deployment to be updated")
@@ -282,7 +275,8 @@ func TestHandlePodTerminatedNotOk(t *testing.T) {
 	var updatedDeployment *kapi.ReplicationController
 
 	controller := &DeploymentController{
-		Codec: api.Codec,
+		Recorder: &record.FakeRecorder{},
+		Codec:    api.Codec,
 		DeploymentInterface: &testDcDeploymentInterface{
 			UpdateDeploymentFunc: func(namespace string, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error) {
 				updatedDeployment = deployment
@@ -300,12 +294,10 @@ func TestHandlePodTerminatedNotOk(t *testing.T) {
 				return basicContainer()
 			},
 		},
-		NextDeployment:  func() *kapi.ReplicationController { return nil },
-		NextPod:         func() *kapi.Pod { return failedPod() },
 		DeploymentStore: deploytest.NewFakeDeploymentStore(runningDeployment()),
 	}
 
-	controller.HandlePod()
+	controller.HandlePod(failedPod())
 
 	if updatedDeployment == nil {
 		t.Fatalf("Expected a deployment to be updated")
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+
+	osclient "github.com/openshift/origin/pkg/client"
+	"github.com/openshift/origin/pkg/client/record"
+	oscontroller "github.com/openshift/origin/pkg/controller"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
+)
+
+// DriftController periodically compares the pod template of the latest deployment of every
+// DeploymentConfig with the template of the DeploymentConfig the deployment was made from, to detect
+// changes made to the ReplicationController directly.  Drifted deployments are annotated with
+// DeploymentDriftAnnotation and an event is recorded; if the DeploymentConfig sets Reconcile, the pod
+// template of the deployment is restored instead.  Changes to the DeploymentConfig that have not been
+// deployed yet are not drift.
+//
+// The whole pod template is compared, including its labels and container resources.  Restoring the pod
+// template does not touch the pods already running from the changed template; only pods the
+// ReplicationController creates afterwards use the restored one.
+type DriftController struct {
+	// Client provides access to DeploymentConfigs and deployments.
+	Client DriftClient
+	// Codec is used to decode the DeploymentConfigs deployments were made from.
+	Codec runtime.Codec
+	// Recorder records drift and its reconciliation as events of the deployment.
+	Recorder record.Recorder
+	// Period is the interval between comparisons.
+	Period time.Duration
+	// Synced is an optional function called after each pass.
+	Synced func()
+	// Crashed is an optional function called with the value of a panic in the controller.
+	Crashed func(interface{})
+	// Stop is an optional channel that controls when the controller exits.
+	Stop <-chan struct{}
+}
+
+// DriftClient is the access to DeploymentConfigs and deployments the DriftController needs.
+type DriftClient interface {
+	ListDeploymentConfigs() (*deployapi.DeploymentConfigList, error)
+	GetDeployment(namespace, name string) (*kapi.ReplicationController, error)
+	UpdateDeployment(namespace string, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error)
+}
+
+// NewDriftClient returns a DriftClient backed by the OpenShift and Kubernetes clients.
+func NewDriftClient(osClient osclient.Interface, kubeClient kclient.Interface) DriftClient {
+	return &realDeploymentClient{osClient, kubeClient}
+}
+
+// Run begins comparing deployments every Period.
+func (c *DriftController) Run() {
+	go oscontroller.Until(func() {
+		c.HandleDeploymentConfigs()
+		if c.Synced != nil {
+			c.Synced()
+		}
+	}, c.Period, c.Stop, c.Crashed)
+}
+
+// HandleDeploymentConfigs checks the latest deployment of every DeploymentConfig for drift.
+func (c *DriftController) HandleDeploymentConfigs() {
+	configs, err := c.Client.ListDeploymentConfigs()
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to list deployment configs: %v", err))
+		return
+	}
+	for i := range configs.Items {
+		config := &configs.Items[i]
+		if config.LatestVersion == 0 {
+			continue
+		}
+		if err := c.handleDeployment(config); err != nil {
+			util.HandleError(fmt.Errorf("unable to check deployment config %s/%s for drift: %v", config.Namespace, config.Name, err))
+		}
+	}
+}
+
+// handleDeployment reports, reverts or clears the drift of the latest deployment of config.
+func (c *DriftController) handleDeployment(config *deployapi.DeploymentConfig) error {
+	deployment, err := c.Client.GetDeployment(config.Namespace, deployutil.LatestDeploymentNameForConfig(config))
+	if err != nil {
+		return err
+	}
+	deployedConfig, err := deployutil.DecodeDeploymentConfig(deployment, c.Codec)
+	if err != nil {
+		return err
+	}
+	desired, err := deployutil.MakeDeployment(deployedConfig, c.Codec)
+	if err != nil {
+		return err
+	}
+
+	_, drifted := deployment.Annotations[deployapi.DeploymentDriftAnnotation]
+	if deployutil.PodTemplatesEqual(desired.Spec.Template, deployment.Spec.Template) {
+		if !drifted {
+			return nil
+		}
+		// The deployment was changed back outside of the controller.
+		delete(deployment.Annotations, deployapi.DeploymentDriftAnnotation)
+		_, err := c.Client.UpdateDeployment(deployment.Namespace, deployment)
+		return err
+	}
+
+	glog.V(4).Infof("Deployment %s/%s drifted from its config:\n%s", deployment.Namespace, deployment.Name, util.ObjectDiff(desired.Spec.Template, deployment.Spec.Template))
+	if config.Reconcile {
+		deployment.Spec.Template = desired.Spec.Template
+		delete(deployment.Annotations, deployapi.DeploymentDriftAnnotation)
+		if _, err := c.Client.UpdateDeployment(deployment.Namespace, deployment); err != nil {
+			return err
+		}
+		c.Recorder.Eventf(deployment, "reconciled", "Reverted changes to the pod template of deployment %s made outside of deployment config %s", deployment.Name, config.Name)
+		return nil
+	}
+	if drifted {
+		return nil
+	}
+
+	deployment.Annotations[deployapi.DeploymentDriftAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	if _, err := c.Client.UpdateDeployment(deployment.Namespace, deployment); err != nil {
+		return err
+	}
+	c.Recorder.Eventf(deployment, "drifted", "The pod template of deployment %s was changed outside of deployment config %s", deployment.Name, config.Name)
+	return nil
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+
+	api "github.com/openshift/origin/pkg/api/latest"
+	"github.com/openshift/origin/pkg/client/record"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployapitest "github.com/openshift/origin/pkg/deploy/api/test"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
+)
+
+func TestDriftController(t *testing.T) {
+	testCases := map[string]struct {
+		edit      bool
+		drifted   bool
+		reconcile bool
+
+		expectUpdate  bool
+		expectDrifted bool
+		expectImage   string
+		expectEvents  int
+	}{
+		"no drift":            {},
+		"new drift":           {edit: true, expectUpdate: true, expectDrifted: true, expectImage: "edited", expectEvents: 1},
+		"reported drift":      {edit: true, drifted: true},
+		"drift reverted":      {drifted: true, expectUpdate: true, expectImage: "registry:8080/repo1:ref1"},
+		"drift reconciled":    {edit: true, reconcile: true, expectUpdate: true, expectImage: "registry:8080/repo1:ref1", expectEvents: 1},
+		"reported reconciled": {edit: true, drifted: true, reconcile: true, expectUpdate: true, expectImage: "registry:8080/repo1:ref1", expectEvents: 1},
+	}
+
+	for name, test := range testCases {
+		config := deployapitest.OkDeploymentConfig(1)
+		config.Reconcile = test.reconcile
+		deployment, _ := deployutil.MakeDeployment(deployapitest.OkDeploymentConfig(1), api.Codec)
+		if test.edit {
+			deployment.Spec.Template.Spec.Containers[0].Image = "edited"
+		}
+		if test.drifted {
+			deployment.Annotations[deployapi.DeploymentDriftAnnotation] = "2015-01-01T00:00:00Z"
+		}
+
+		client := &testAutoscaleClient{configs: []deployapi.DeploymentConfig{*deployapitest.OkDeploymentConfig(0), *config}, deployment: deployment}
+		recorder := &record.FakeRecorder{}
+		controller := &DriftController{Client: client, Codec: api.Codec, Recorder: recorder}
+		controller.HandleDeploymentConfigs()
+
+		if !test.expectUpdate {
+			if client.updated != nil {
+				t.Errorf("%s: unexpected update of the deployment", name)
+			}
+			continue
+		}
+		if client.updated == nil {
+			t.Errorf("%s: expected the deployment to be updated", name)
+			continue
+		}
+		if _, drifted := client.updated.Annotations[deployapi.DeploymentDriftAnnotation]; drifted != test.expectDrifted {
+			t.Errorf("%s: expected the drift annotation to be %t, got %t", name, test.expectDrifted, drifted)
+		}
+		if image := client.updated.Spec.Template.Spec.Containers[0].Image; image != test.expectImage {
+			t.Errorf("%s: expected image %s, got %s", name, test.expectImage, image)
+		}
+		if len(recorder.Events) != test.expectEvents {
+			t.Errorf("%s: expected %d events, got %v", name, test.expectEvents, recorder.Events)
+		}
+	}
+}
+
+func TestDriftControllerKeepsResources(t *testing.T) {
+	config := deployapitest.OkDeploymentConfig(1)
+	deployment, _ := deployutil.MakeDeployment(config, api.Codec)
+	deployment.Spec.Template.Spec.Containers[0].Image = "edited"
+	deployment.Spec.Template.Spec.Containers[0].Resources = kapi.ResourceRequirementSpec{Limits: kapi.ResourceList{kapi.ResourceCPU: *resource.NewQuantity(1, resource.DecimalSI)}}
+
+	client := &testAutoscaleClient{configs: []deployapi.DeploymentConfig{*config}, deployment: deployment}
+	controller := &DriftController{Client: client, Codec: api.Codec, Recorder: &record.FakeRecorder{}}
+	controller.HandleDeploymentConfigs()
+
+	if client.updated == nil {
+		t.Fatalf("expected the drift of the deployment to be reported")
+	}
+	if len(client.updated.Spec.Template.Spec.Containers[0].Resources.Limits) != 1 {
+		t.Errorf("expected the resources of the deployment to be left unchanged, got %#v", client.updated.Spec.Template.Spec.Containers[0])
+	}
+}
+
+func TestDriftControllerRevertsResources(t *testing.T) {
+	config := deployapitest.OkDeploymentConfig(1)
+	deployment, _ := deployutil.MakeDeployment(config, api.Codec)
+	deployment.Spec.Template.Spec.Containers[0].Resources = kapi.ResourceRequirementSpec{Limits: kapi.ResourceList{kapi.ResourceCPU: *resource.NewQuantity(1, resource.DecimalSI)}}
+
+	client := &testAutoscaleClient{configs: []deployapi.DeploymentConfig{*config}, deployment: deployment}
+	controller := &DriftController{Client: client, Codec: api.Codec, Recorder: &record.FakeRecorder{}}
+	controller.HandleDeploymentConfigs()
+	if client.updated == nil {
+		t.Fatalf("expected the changed resources to be reported as drift")
+	}
+	if _, drifted := client.updated.Annotations[deployapi.DeploymentDriftAnnotation]; !drifted {
+		t.Errorf("expected the deployment to be annotated as drifted")
+	}
+
+	config.Reconcile = true
+	delete(deployment.Annotations, deployapi.DeploymentDriftAnnotation)
+	client = &testAutoscaleClient{configs: []deployapi.DeploymentConfig{*config}, deployment: deployment}
+	controller = &DriftController{Client: client, Codec: api.Codec, Recorder: &record.FakeRecorder{}}
+	controller.HandleDeploymentConfigs()
+	if client.updated == nil {
+		t.Fatalf("expected the changed resources to be reverted")
+	}
+	if limits := client.updated.Spec.Template.Spec.Containers[0].Resources.Limits; len(limits) != 0 {
+		t.Errorf("expected the resources of the config to be restored, got %#v", limits)
+	}
+}
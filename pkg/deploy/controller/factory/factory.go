@@ -1,6 +1,7 @@
 package factory
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/golang/glog"
@@ -8,39 +9,53 @@ import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	osclient "github.com/openshift/origin/pkg/client"
+	oscache "github.com/openshift/origin/pkg/client/cache"
+	"github.com/openshift/origin/pkg/client/record"
+	oscontroller "github.com/openshift/origin/pkg/controller"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	controller "github.com/openshift/origin/pkg/deploy/controller"
 	imageapi "github.com/openshift/origin/pkg/image/api"
 )
 
+const (
+	// maxRetries is the number of times an object that failed to be handled is retried.
+	maxRetries = 5
+	// initialBackoff is the wait before the first retry of an object, which doubles with every retry up
+	// to maxBackoff.
+	initialBackoff = time.Second
+	maxBackoff     = time.Minute
+)
+
 // DeploymentConfigControllerFactory can create a DeploymentConfigController which obtains
 // DeploymentConfigs from a queue populated from a watch of all DeploymentConfigs.
 type DeploymentConfigControllerFactory struct {
 	Client     *osclient.Client
 	KubeClient kclient.Interface
 	Codec      runtime.Codec
-	Stop       <-chan struct{}
+	// Informers may be set to share the watches and stores of objects with other controllers.
+	Informers *oscache.Informers
+	Stop      <-chan struct{}
 }
 
-func (factory *DeploymentConfigControllerFactory) Create() *controller.DeploymentConfigController {
-	queue := cache.NewFIFO(cache.MetaNamespaceKeyFunc)
-	cache.NewReflector(&deploymentConfigLW{factory.Client}, &deployapi.DeploymentConfig{}, queue).RunUntil(factory.Stop)
+// Create creates a controller that creates the deployments of DeploymentConfigs.
+func (factory *DeploymentConfigControllerFactory) Create() *oscontroller.RetryController {
+	queue := informersOrNew(factory.Informers, factory.Client, factory.KubeClient).DeploymentConfigs().Queue(factory.Stop)
 
-	return &controller.DeploymentConfigController{
+	configController := &controller.DeploymentConfigController{
 		DeploymentInterface: &ClientDeploymentInterface{factory.KubeClient},
-		NextDeploymentConfig: func() *deployapi.DeploymentConfig {
-			config := queue.Pop().(*deployapi.DeploymentConfig)
-			panicIfStopped(factory.Stop, "deployment config controller stopped")
-			return config
-		},
-		Codec: factory.Codec,
-		Stop:  factory.Stop,
+		Codec:               factory.Codec,
+		Recorder:            record.NewRecorder(),
 	}
+	return newRetryController(queue, func(obj interface{}) error {
+		config, err := copyObject(obj)
+		if err != nil {
+			return err
+		}
+		return configController.HandleDeploymentConfig(config.(*deployapi.DeploymentConfig))
+	}, factory.Stop)
 }
 
 // DeploymentControllerFactory can create a DeploymentController which obtains Deployments
@@ -59,6 +74,8 @@ type DeploymentControllerFactory struct {
 	RecreateStrategyImage string
 	// Codec is used to decode DeploymentConfigs.
 	Codec runtime.Codec
+	// Informers may be set to share the watches and stores of objects with other controllers.
+	Informers *oscache.Informers
 	// Stop may be set to allow controllers created by this factory to be terminated.
 	Stop <-chan struct{}
 
@@ -66,12 +83,26 @@ type DeploymentControllerFactory struct {
 	deploymentStore cache.Store
 }
 
-func (factory *DeploymentControllerFactory) Create() *controller.DeploymentController {
-	deploymentQueue := cache.NewFIFO(cache.MetaNamespaceKeyFunc)
-	cache.NewReflector(&deploymentLW{client: factory.KubeClient, field: labels.Everything()}, &kapi.ReplicationController{}, deploymentQueue).RunUntil(factory.Stop)
+// Create creates a controller that starts the deployer pods of new deployments.
+func (factory *DeploymentControllerFactory) Create() *oscontroller.RetryController {
+	factory.Informers = informersOrNew(factory.Informers, factory.Client, factory.KubeClient)
+	factory.deploymentStore = factory.Informers.ReplicationControllers().Store()
+	queue := factory.Informers.ReplicationControllers().Queue(factory.Stop)
+
+	deploymentController := factory.deploymentController()
+	return newRetryController(queue, func(obj interface{}) error {
+		deployment, err := copyObject(obj)
+		if err != nil {
+			return err
+		}
+		return deploymentController.HandleDeployment(deployment.(*kapi.ReplicationController))
+	}, factory.Stop)
+}
 
-	factory.deploymentStore = cache.NewStore(cache.MetaNamespaceKeyFunc)
-	cache.NewReflector(&deploymentLW{client: factory.KubeClient, field: labels.Everything()}, &kapi.ReplicationController{}, factory.deploymentStore).RunUntil(factory.Stop)
+// CreatePodController creates a controller that updates the status of deployments from their deployer pods.
+func (factory *DeploymentControllerFactory) CreatePodController() *oscontroller.RetryController {
+	factory.Informers = informersOrNew(factory.Informers, factory.Client, factory.KubeClient)
+	factory.deploymentStore = factory.Informers.ReplicationControllers().Store()
 
 	// Kubernetes does not currently synchronize Pod status in storage with a Pod's container
 	// states. Because of this, we can't receive events related to container (and thus Pod)
@@ -81,28 +112,26 @@ func (factory *DeploymentControllerFactory) Create() *controller.DeploymentContr
 	//
 	// TODO: Find a way to get watch events for Pod/container status updates. The polling
 	// strategy is horribly inefficient and should be addressed upstream somehow.
-	podQueue := cache.NewFIFO(cache.MetaNamespaceKeyFunc)
+	podQueue := oscache.NewWorkQueue(cache.MetaNamespaceKeyFunc)
 	cache.NewPoller(factory.pollPods, 10*time.Second, podQueue).RunUntil(factory.Stop)
 
+	deploymentController := factory.deploymentController()
+	return newRetryController(podQueue, func(obj interface{}) error {
+		return deploymentController.HandlePod(obj.(*kapi.Pod))
+	}, factory.Stop)
+}
+
+// deploymentController returns the controller that handles deployments and their deployer pods.
+func (factory *DeploymentControllerFactory) deploymentController() *controller.DeploymentController {
 	return &controller.DeploymentController{
 		ContainerCreator:    factory,
 		DeploymentInterface: &ClientDeploymentInterface{factory.KubeClient},
 		PodInterface:        &DeploymentControllerPodInterface{factory.KubeClient},
 		Environment:         factory.Environment,
-		NextDeployment: func() *kapi.ReplicationController {
-			deployment := deploymentQueue.Pop().(*kapi.ReplicationController)
-			panicIfStopped(factory.Stop, "deployment controller stopped")
-			return deployment
-		},
-		NextPod: func() *kapi.Pod {
-			pod := podQueue.Pop().(*kapi.Pod)
-			panicIfStopped(factory.Stop, "deployment controller stopped")
-			return pod
-		},
-		DeploymentStore: factory.deploymentStore,
-		UseLocalImages:  factory.UseLocalImages,
-		Codec:           factory.Codec,
-		Stop:            factory.Stop,
+		DeploymentStore:     factory.deploymentStore,
+		UseLocalImages:      factory.UseLocalImages,
+		Codec:               factory.Codec,
+		Recorder:            record.NewRecorder(),
 	}
 }
 
@@ -195,110 +224,85 @@ type DeploymentConfigChangeControllerFactory struct {
 	Client     osclient.Interface
 	KubeClient kclient.Interface
 	Codec      runtime.Codec
+	// Informers may be set to share the watches and stores of objects with other controllers.
+	Informers *oscache.Informers
 	// Stop may be set to allow controllers created by this factory to be terminated.
 	Stop <-chan struct{}
 }
 
-func (factory *DeploymentConfigChangeControllerFactory) Create() *controller.DeploymentConfigChangeController {
-	queue := cache.NewFIFO(cache.MetaNamespaceKeyFunc)
-	cache.NewReflector(&deploymentConfigLW{factory.Client}, &deployapi.DeploymentConfig{}, queue).RunUntil(factory.Stop)
-
-	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
-	cache.NewReflector(&deploymentLW{client: factory.KubeClient, field: labels.Everything()}, &kapi.ReplicationController{}, store).RunUntil(factory.Stop)
-
-	return &controller.DeploymentConfigChangeController{
-		ChangeStrategy: &ClientDeploymentConfigInterface{factory.Client},
-		NextDeploymentConfig: func() *deployapi.DeploymentConfig {
-			config := queue.Pop().(*deployapi.DeploymentConfig)
-			panicIfStopped(factory.Stop, "deployment config change controller stopped")
-			return config
-		},
-		DeploymentStore: store,
+// Create creates a controller that regenerates DeploymentConfigs with a config change trigger when
+// their template changes.
+func (factory *DeploymentConfigChangeControllerFactory) Create() *oscontroller.RetryController {
+	informers := informersOrNew(factory.Informers, factory.Client, factory.KubeClient)
+	queue := informers.DeploymentConfigs().Queue(factory.Stop)
+
+	changeController := &controller.DeploymentConfigChangeController{
+		ChangeStrategy:  &ClientDeploymentConfigInterface{factory.Client},
+		DeploymentStore: informers.ReplicationControllers().Store(),
 		Codec:           factory.Codec,
-		Stop:            factory.Stop,
 	}
+	return newRetryController(queue, func(obj interface{}) error {
+		config, err := copyObject(obj)
+		if err != nil {
+			return err
+		}
+		return changeController.HandleDeploymentConfig(config.(*deployapi.DeploymentConfig))
+	}, factory.Stop)
 }
 
 // ImageChangeControllerFactory can create an ImageChangeController which obtains ImageRepositories
 // from a queue populated from a watch of all ImageRepositories.
 type ImageChangeControllerFactory struct {
 	Client *osclient.Client
+	// Informers may be set to share the watches and stores of objects with other controllers.
+	Informers *oscache.Informers
 	// Stop may be set to allow controllers created by this factory to be terminated.
 	Stop <-chan struct{}
 }
 
-func (factory *ImageChangeControllerFactory) Create() *controller.ImageChangeController {
-	queue := cache.NewFIFO(cache.MetaNamespaceKeyFunc)
-	cache.NewReflector(&imageRepositoryLW{factory.Client}, &imageapi.ImageRepository{}, queue).RunUntil(factory.Stop)
-
-	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
-	cache.NewReflector(&deploymentConfigLW{factory.Client}, &deployapi.DeploymentConfig{}, store).RunUntil(factory.Stop)
+// Create creates a controller that regenerates DeploymentConfigs with an image change trigger when a
+// new image is available.
+func (factory *ImageChangeControllerFactory) Create() *oscontroller.RetryController {
+	informers := informersOrNew(factory.Informers, factory.Client, nil)
+	queue := informers.ImageRepositories().Queue(factory.Stop)
 
-	return &controller.ImageChangeController{
+	imageChangeController := &controller.ImageChangeController{
 		DeploymentConfigInterface: &ClientDeploymentConfigInterface{factory.Client},
-		DeploymentConfigStore:     store,
-		NextImageRepository: func() *imageapi.ImageRepository {
-			repo := queue.Pop().(*imageapi.ImageRepository)
-			panicIfStopped(factory.Stop, "deployment config change controller stopped")
-			return repo
-		},
-		Stop: factory.Stop,
+		DeploymentConfigStore:     informers.DeploymentConfigs().Store(),
 	}
+	return newRetryController(queue, func(obj interface{}) error {
+		return imageChangeController.HandleImageRepo(obj.(*imageapi.ImageRepository))
+	}, factory.Stop)
 }
 
-// panicIfStopped panics with the provided object if the channel is closed
-func panicIfStopped(ch <-chan struct{}, message interface{}) {
-	select {
-	case <-ch:
-		panic(message)
-	default:
+// informersOrNew returns informers if they are set, or new informers of the objects listed and watched
+// with client and kubeClient.
+func informersOrNew(informers *oscache.Informers, client osclient.Interface, kubeClient kclient.Interface) *oscache.Informers {
+	if informers != nil {
+		return informers
 	}
+	return oscache.NewInformers(client, kubeClient)
 }
 
-// deploymentLW is a ListWatcher implementation for Deployments.
-type deploymentLW struct {
-	client kclient.Interface
-	field  labels.Selector
-}
-
-// List lists all Deployments which match the given field selector.
-func (lw *deploymentLW) List() (runtime.Object, error) {
-	return lw.client.ReplicationControllers(kapi.NamespaceAll).List(labels.Everything())
-}
-
-// Watch watches all Deployments matching the given field selector.
-func (lw *deploymentLW) Watch(resourceVersion string) (watch.Interface, error) {
-	return lw.client.ReplicationControllers(kapi.NamespaceAll).Watch(labels.Everything(), lw.field, resourceVersion)
-}
-
-// deploymentConfigLW is a ListWatcher implementation for DeploymentConfigs.
-type deploymentConfigLW struct {
-	client osclient.Interface
-}
-
-// List lists all DeploymentConfigs.
-func (lw *deploymentConfigLW) List() (runtime.Object, error) {
-	return lw.client.DeploymentConfigs(kapi.NamespaceAll).List(labels.Everything(), labels.Everything())
-}
-
-// Watch watches all DeploymentConfigs.
-func (lw *deploymentConfigLW) Watch(resourceVersion string) (watch.Interface, error) {
-	return lw.client.DeploymentConfigs(kapi.NamespaceAll).Watch(labels.Everything(), labels.Everything(), resourceVersion)
-}
-
-// imageRepositoryLW is a ListWatcher for ImageRepositories.
-type imageRepositoryLW struct {
-	client osclient.Interface
-}
-
-// List lists all ImageRepositories.
-func (lw *imageRepositoryLW) List() (runtime.Object, error) {
-	return lw.client.ImageRepositories(kapi.NamespaceAll).List(labels.Everything(), labels.Everything())
+// newRetryController returns a controller that handles the objects of queue, retrying each object that
+// fails up to maxRetries times with an exponential backoff.
+func newRetryController(queue *oscache.WorkQueue, handle func(obj interface{}) error, stop <-chan struct{}) *oscontroller.RetryController {
+	return &oscontroller.RetryController{
+		Queue:        queue,
+		RetryManager: oscontroller.NewQueueRetryManager(queue, cache.MetaNamespaceKeyFunc, oscontroller.RetryLimit(maxRetries), initialBackoff, maxBackoff),
+		Handle:       handle,
+		Stop:         stop,
+	}
 }
 
-// Watch watches all ImageRepositories.
-func (lw *imageRepositoryLW) Watch(resourceVersion string) (watch.Interface, error) {
-	return lw.client.ImageRepositories(kapi.NamespaceAll).Watch(labels.Everything(), labels.Everything(), resourceVersion)
+// copyObject returns a copy of obj, since the objects of queues are shared with the stores of informers
+// and must not be modified.
+func copyObject(obj interface{}) (runtime.Object, error) {
+	copied, err := kapi.Scheme.Copy(obj.(runtime.Object))
+	if err != nil {
+		return nil, fmt.Errorf("unable to copy %#v: %v", obj, err)
+	}
+	return copied, nil
 }
 
 // ClientDeploymentInterface is a dccDeploymentInterface and dcDeploymentInterface which delegates to the OpenShift client interfaces
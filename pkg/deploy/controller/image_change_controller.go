@@ -17,10 +17,7 @@ import (
 // is available.
 type ImageChangeController struct {
 	DeploymentConfigInterface icDeploymentConfigInterface
-	NextImageRepository       func() *imageapi.ImageRepository
 	DeploymentConfigStore     cache.Store
-	// Stop is an optional channel that controls when the controller exits
-	Stop <-chan struct{}
 }
 
 type icDeploymentConfigInterface interface {
@@ -28,14 +25,9 @@ type icDeploymentConfigInterface interface {
 	GenerateDeploymentConfig(namespace, name string) (*deployapi.DeploymentConfig, error)
 }
 
-// Run processes ImageRepository events one by one.
-func (c *ImageChangeController) Run() {
-	go util.Until(c.HandleImageRepo, 0, c.Stop)
-}
-
-// HandleImageRepo processes the next ImageRepository event.
-func (c *ImageChangeController) HandleImageRepo() {
-	imageRepo := c.NextImageRepository()
+// HandleImageRepo regenerates the DeploymentConfigs with a trigger on a changed ImageRepository.  An error
+// is returned if the ImageRepository should be retried.
+func (c *ImageChangeController) HandleImageRepo(imageRepo *imageapi.ImageRepository) error {
 	configsToGenerate := []*deployapi.DeploymentConfig{}
 	firedTriggersForConfig := make(map[string][]deployapi.DeploymentTriggerImageChangeParams)
 
@@ -79,13 +71,15 @@ func (c *ImageChangeController) HandleImageRepo() {
 		}
 	}
 
+	var firstErr error
 	for _, config := range configsToGenerate {
 		glog.V(4).Infof("Regenerating deploymentConfig %s/%s", config.Namespace, config.Name)
 		err := c.regenerate(imageRepo, config, firedTriggersForConfig[config.Name])
-		if err != nil {
-			glog.V(2).Infof("Error regenerating deploymentConfig %s/%s: %v", config.Namespace, config.Name, err)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("error regenerating deploymentConfig %s/%s: %v", config.Namespace, config.Name, err)
 		}
 	}
+	return firstErr
 }
 
 // triggerMatchesImages decides whether a given trigger for config matches the provided image repo.
@@ -42,14 +42,11 @@ func TestUnregisteredContainer(t *testing.T) {
 				return nil, nil
 			},
 		},
-		NextImageRepository: func() *imageapi.ImageRepository {
-			return tagUpdate()
-		},
 		DeploymentConfigStore: deploytest.NewFakeDeploymentConfigStore(config),
 	}
 
 	// verify no-op
-	controller.HandleImageRepo()
+	controller.HandleImageRepo(tagUpdate())
 }
 
 func TestImageChangeForNonAutomaticTag(t *testing.T) {
@@ -67,14 +64,11 @@ func TestImageChangeForNonAutomaticTag(t *testing.T) {
 				return nil, nil
 			},
 		},
-		NextImageRepository: func() *imageapi.ImageRepository {
-			return tagUpdate()
-		},
 		DeploymentConfigStore: deploytest.NewFakeDeploymentConfigStore(config),
 	}
 
 	// verify no-op
-	controller.HandleImageRepo()
+	controller.HandleImageRepo(tagUpdate())
 }
 
 func TestImageChangeForUnregisteredTag(t *testing.T) {
@@ -91,18 +85,15 @@ func TestImageChangeForUnregisteredTag(t *testing.T) {
 				return nil, nil
 			},
 		},
-		NextImageRepository: func() *imageapi.ImageRepository {
-			imageRepo := tagUpdate()
-			imageRepo.Tags = map[string]string{
-				"unknown-tag": "ref-1",
-			}
-			return imageRepo
-		},
 		DeploymentConfigStore: deploytest.NewFakeDeploymentConfigStore(config),
 	}
 
+	imageRepo := tagUpdate()
+	imageRepo.Tags = map[string]string{
+		"unknown-tag": "ref-1",
+	}
 	// verify no-op
-	controller.HandleImageRepo()
+	controller.HandleImageRepo(imageRepo)
 }
 
 func TestImageChangeMatchScenarios(t *testing.T) {
@@ -130,17 +121,17 @@ func TestImageChangeMatchScenarios(t *testing.T) {
 	updates := map[string]*imageapi.ImageRepository{
 		"repo.1": {
 			ObjectMeta: kapi.ObjectMeta{Name: "repoA", Namespace: kapi.NamespaceDefault},
-			Status:     imageapi.ImageRepositoryStatus{"registry:8080/openshift/test-image"},
+			Status:     imageapi.ImageRepositoryStatus{DockerImageRepository: "registry:8080/openshift/test-image"},
 			Tags:       map[string]string{"test-tag": "ref-2"},
 		},
 		"repo.2": {
 			ObjectMeta: kapi.ObjectMeta{Name: "repoB", Namespace: kapi.NamespaceDefault},
-			Status:     imageapi.ImageRepositoryStatus{"registry:8080/openshift/test-image"},
+			Status:     imageapi.ImageRepositoryStatus{DockerImageRepository: "registry:8080/openshift/test-image"},
 			Tags:       map[string]string{"test-tag": "ref-3"},
 		},
 		"repo.3": {
 			ObjectMeta: kapi.ObjectMeta{Name: "repoC", Namespace: kapi.NamespaceDefault},
-			Status:     imageapi.ImageRepositoryStatus{"registry:8080/openshift/test-image-B"},
+			Status:     imageapi.ImageRepositoryStatus{DockerImageRepository: "registry:8080/openshift/test-image-B"},
 			Tags:       map[string]string{"test-tag": "ref-2"},
 		},
 		"repo.4": {
@@ -203,14 +194,11 @@ func TestImageChangeMatchScenarios(t *testing.T) {
 					return config, nil
 				},
 			},
-			NextImageRepository: func() *imageapi.ImageRepository {
-				return updates[s.repo]
-			},
 			DeploymentConfigStore: deploytest.NewFakeDeploymentConfigStore(config),
 		}
 
 		t.Logf("running scenario: %v", s)
-		controller.HandleImageRepo()
+		controller.HandleImageRepo(updates[s.repo])
 
 		// assert updates/generations occured
 		if s.matches && !updated {
@@ -12,6 +12,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 	"github.com/golang/glog"
 
+	"github.com/openshift/origin/pkg/api/selectable"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	"github.com/openshift/origin/pkg/deploy/api/validation"
 )
@@ -44,6 +45,9 @@ func (s *REST) List(ctx kapi.Context, label, field labels.Selector) (runtime.Obj
 		return nil, err
 	}
 
+	if err := selectable.FilterList(deployments, label, field); err != nil {
+		return nil, err
+	}
 	return deployments, nil
 }
 
@@ -108,15 +112,28 @@ func (s *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 		return nil, kerrors.NewConflict("deployment", deployment.Namespace, fmt.Errorf("Deployment.Namespace does not match the provided context"))
 	}
 
+	if errs := validation.ValidateDeployment(deployment); len(errs) > 0 {
+		return nil, kerrors.NewInvalid("deployment", deployment.Name, errs)
+	}
+
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
-		err := s.registry.UpdateDeployment(ctx, deployment)
+		existing, err := s.registry.GetDeployment(ctx, deployment.Name)
 		if err != nil {
 			return nil, err
 		}
+		if errs := validation.ValidateDeploymentUpdate(deployment, existing); len(errs) > 0 {
+			return nil, kerrors.NewInvalid("deployment", deployment.Name, errs)
+		}
+		if err := s.registry.UpdateDeployment(ctx, deployment); err != nil {
+			return nil, err
+		}
 		return deployment, nil
 	}), nil
 }
 
 func (s *REST) Watch(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	if err := selectable.ValidateFields(&deployapi.Deployment{}, field); err != nil {
+		return nil, err
+	}
 	return s.registry.WatchDeployments(ctx, label, field, resourceVersion)
 }
@@ -222,7 +222,9 @@ func TestUpdateRegistryErrorSaving(t *testing.T) {
 	storage := REST{registry: mockRepositoryRegistry}
 
 	channel, err := storage.Update(kapi.NewDefaultContext(), &api.Deployment{
-		ObjectMeta: kapi.ObjectMeta{Name: "bar"},
+		ObjectMeta:         kapi.ObjectMeta{Name: "bar"},
+		Strategy:           deploytest.OkStrategy(),
+		ControllerTemplate: deploytest.OkControllerTemplate(),
 	})
 	if err != nil {
 		t.Errorf("Unexpected non-nil error: %#v", err)
@@ -239,10 +241,17 @@ func TestUpdateRegistryErrorSaving(t *testing.T) {
 
 func TestUpdateDeploymentOk(t *testing.T) {
 	mockRepositoryRegistry := test.NewDeploymentRegistry()
+	mockRepositoryRegistry.Deployment = &api.Deployment{
+		ObjectMeta:         kapi.ObjectMeta{Name: "bar", Namespace: kapi.NamespaceDefault},
+		Strategy:           deploytest.OkStrategy(),
+		ControllerTemplate: deploytest.OkControllerTemplate(),
+	}
 	storage := REST{registry: mockRepositoryRegistry}
 
 	channel, err := storage.Update(kapi.NewDefaultContext(), &api.Deployment{
-		ObjectMeta: kapi.ObjectMeta{Name: "bar"},
+		ObjectMeta:         kapi.ObjectMeta{Name: "bar"},
+		Strategy:           deploytest.OkStrategy(),
+		ControllerTemplate: deploytest.OkControllerTemplate(),
 	})
 	if err != nil {
 		t.Errorf("Unexpected non-nil error: %#v", err)
@@ -13,6 +13,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 	"github.com/golang/glog"
 
+	"github.com/openshift/origin/pkg/api/selectable"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	validation "github.com/openshift/origin/pkg/deploy/api/validation"
 )
@@ -45,11 +46,17 @@ func (s *REST) List(ctx kapi.Context, label, field labels.Selector) (runtime.Obj
 		return nil, err
 	}
 
+	if err := selectable.FilterList(deploymentConfigs, label, field); err != nil {
+		return nil, err
+	}
 	return deploymentConfigs, nil
 }
 
 // Watch begins watching for new, changed, or deleted ImageRepositories.
 func (s *REST) Watch(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	if err := selectable.ValidateFields(&deployapi.DeploymentConfig{}, field); err != nil {
+		return nil, err
+	}
 	return s.registry.WatchDeploymentConfigs(ctx, label, field, resourceVersion)
 }
 
@@ -113,11 +120,21 @@ func (s *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 		return nil, kerrors.NewConflict("deploymentConfig", deploymentConfig.Namespace, fmt.Errorf("DeploymentConfig.Namespace does not match the provided context"))
 	}
 
+	if errs := validation.ValidateDeploymentConfig(deploymentConfig); len(errs) > 0 {
+		return nil, kerrors.NewInvalid("deploymentConfig", deploymentConfig.Name, errs)
+	}
+
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
-		err := s.registry.UpdateDeploymentConfig(ctx, deploymentConfig)
+		existing, err := s.registry.GetDeploymentConfig(ctx, deploymentConfig.Name)
 		if err != nil {
 			return nil, err
 		}
+		if errs := validation.ValidateDeploymentConfigUpdate(deploymentConfig, existing); len(errs) > 0 {
+			return nil, kerrors.NewInvalid("deploymentConfig", deploymentConfig.Name, errs)
+		}
+		if err := s.registry.UpdateDeploymentConfig(ctx, deploymentConfig); err != nil {
+			return nil, err
+		}
 		return s.Get(ctx, deploymentConfig.Name)
 	}), nil
 }
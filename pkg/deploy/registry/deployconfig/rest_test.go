@@ -225,9 +225,7 @@ func TestUpdateRegistryErrorSaving(t *testing.T) {
 	mockRepositoryRegistry.Err = fmt.Errorf("foo")
 	storage := REST{registry: mockRepositoryRegistry}
 
-	channel, err := storage.Update(kapi.NewDefaultContext(), &api.DeploymentConfig{
-		ObjectMeta: kapi.ObjectMeta{Name: "bar"},
-	})
+	channel, err := storage.Update(kapi.NewDefaultContext(), deploytest.OkDeploymentConfig(1))
 	if err != nil {
 		t.Errorf("Unexpected non-nil error: %#v", err)
 	}
@@ -243,11 +241,10 @@ func TestUpdateRegistryErrorSaving(t *testing.T) {
 
 func TestUpdateDeploymentConfigOK(t *testing.T) {
 	mockRepositoryRegistry := test.NewDeploymentConfigRegistry()
+	mockRepositoryRegistry.DeploymentConfig = deploytest.OkDeploymentConfig(1)
 	storage := REST{registry: mockRepositoryRegistry}
 
-	channel, err := storage.Update(kapi.NewDefaultContext(), &api.DeploymentConfig{
-		ObjectMeta: kapi.ObjectMeta{Name: "bar"},
-	})
+	channel, err := storage.Update(kapi.NewDefaultContext(), deploytest.OkDeploymentConfig(2))
 	if err != nil {
 		t.Errorf("Unexpected non-nil error: %#v", err)
 	}
@@ -256,7 +253,7 @@ func TestUpdateDeploymentConfigOK(t *testing.T) {
 	if !ok {
 		t.Errorf("Expected DeploymentConfig, got %#v", result)
 	}
-	if repo.Name != "bar" {
+	if repo.Name != "config" || repo.LatestVersion != 2 {
 		t.Errorf("Unexpected repo returned: %#v", repo)
 	}
 }
@@ -0,0 +1,85 @@
+// Package deploylog provides the RESTStorage implementation redirecting to the logs of the deployer pod
+// of a deployment.
+package deploylog
+
+import (
+	"fmt"
+	"net/url"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/cmd/server/kubernetes"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+)
+
+// REST is an implementation of RESTStorage for the api server.
+type REST struct {
+	DeploymentFn func(ctx kapi.Context, name string) (*kapi.ReplicationController, error)
+	PodFn        func(namespace, name string) (*kapi.Pod, error)
+}
+
+// NewREST creates a new REST for DeploymentLog.  Deployments and their deployer pods are read with the
+// given client to assemble the URL the request is redirected to in order to get the deployment logs.
+func NewREST(c kclient.Interface) apiserver.RESTStorage {
+	return &REST{
+		DeploymentFn: func(ctx kapi.Context, name string) (*kapi.ReplicationController, error) {
+			return c.ReplicationControllers(kapi.Namespace(ctx)).Get(name)
+		},
+		PodFn: func(namespace, name string) (*kapi.Pod, error) {
+			return c.Pods(namespace).Get(name)
+		},
+	}
+}
+
+// ResourceLocation returns the location of the logs of the deployer pod of the deployment id.  The logs of
+// running deployments are followed until the deployer pod exits.  The deployer pods of complete
+// deployments are removed, so only the logs of running and failed deployments can be retrieved.
+func (r *REST) ResourceLocation(ctx kapi.Context, id string) (string, error) {
+	deployment, err := r.DeploymentFn(ctx, id)
+	if err != nil {
+		return "", errors.NewNotFound("deployment", id)
+	}
+
+	podName := deployment.Annotations[deployapi.DeploymentPodAnnotation]
+	if len(podName) == 0 {
+		return "", errors.NewBadRequest(fmt.Sprintf("deployment %s has no deployer pod yet", id))
+	}
+
+	status := deployapi.DeploymentStatus(deployment.Annotations[deployapi.DeploymentStatusAnnotation])
+	follow := false
+	switch status {
+	case deployapi.DeploymentStatusRunning:
+		follow = true
+	case deployapi.DeploymentStatusFailed:
+		// The deployer pod exited, so the logs are complete.
+	default:
+		return "", errors.NewBadRequest(fmt.Sprintf("deployment %s is %s; only the logs of Running or Failed deployments are available", id, status))
+	}
+
+	pod, err := r.PodFn(deployment.Namespace, podName)
+	if err != nil {
+		return "", errors.NewNotFound("pod", podName)
+	}
+	// The deployer pod has no containers to read logs from before it runs.
+	if pod.Status.Phase == kapi.PodPending || pod.Status.Phase == kapi.PodUnknown {
+		return "", errors.NewBadRequest(fmt.Sprintf("deployer pod %s is %s; logs are available once it runs", podName, pod.Status.Phase))
+	}
+
+	location := &url.URL{
+		Scheme: kubernetes.NodeScheme,
+		Host:   fmt.Sprintf("%s:%d", pod.Status.Host, kubernetes.NodePort),
+		Path:   fmt.Sprintf("/containerLogs/%s/%s/%s", pod.Namespace, pod.Name, pod.Spec.Containers[0].Name),
+	}
+	if follow {
+		location.RawQuery = url.Values{"follow": []string{"1"}}.Encode()
+	}
+	return location.String(), nil
+}
+
+func (r *REST) New() runtime.Object {
+	return &deployapi.DeploymentLog{}
+}
@@ -0,0 +1,79 @@
+package deploylog
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	"github.com/openshift/origin/pkg/cmd/server/kubernetes"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+)
+
+func fakeREST(status deployapi.DeploymentStatus, podName string, phase kapi.PodPhase) *REST {
+	return &REST{
+		DeploymentFn: func(ctx kapi.Context, name string) (*kapi.ReplicationController, error) {
+			if name != "config-1" {
+				return nil, errors.New("not found")
+			}
+			return &kapi.ReplicationController{
+				ObjectMeta: kapi.ObjectMeta{
+					Name:      name,
+					Namespace: kapi.Namespace(ctx),
+					Annotations: map[string]string{
+						deployapi.DeploymentStatusAnnotation: string(status),
+						deployapi.DeploymentPodAnnotation:    podName,
+					},
+				},
+			}, nil
+		},
+		PodFn: func(namespace, name string) (*kapi.Pod, error) {
+			if name != "deploy-config-1" {
+				return nil, errors.New("not found")
+			}
+			return &kapi.Pod{
+				ObjectMeta: kapi.ObjectMeta{Name: name, Namespace: namespace},
+				Spec:       kapi.PodSpec{Containers: []kapi.Container{{Name: "deployment"}}},
+				Status:     kapi.PodStatus{Phase: phase, Host: "foo-host"},
+			}, nil
+		},
+	}
+}
+
+func TestResourceLocation(t *testing.T) {
+	location := fmt.Sprintf("%s://foo-host:%d/containerLogs/%s/deploy-config-1/deployment", kubernetes.NodeScheme, kubernetes.NodePort, kapi.NamespaceDefault)
+	testCases := map[string]struct {
+		status   deployapi.DeploymentStatus
+		podName  string
+		phase    kapi.PodPhase
+		name     string
+		expected string
+	}{
+		"running":       {deployapi.DeploymentStatusRunning, "deploy-config-1", kapi.PodRunning, "config-1", location + "?follow=1"},
+		"failed":        {deployapi.DeploymentStatusFailed, "deploy-config-1", kapi.PodFailed, "config-1", location},
+		"new":           {deployapi.DeploymentStatusNew, "", kapi.PodPending, "config-1", ""},
+		"pending":       {deployapi.DeploymentStatusPending, "deploy-config-1", kapi.PodPending, "config-1", ""},
+		"complete":      {deployapi.DeploymentStatusComplete, "deploy-config-1", kapi.PodSucceeded, "config-1", ""},
+		"pending pod":   {deployapi.DeploymentStatusRunning, "deploy-config-1", kapi.PodPending, "config-1", ""},
+		"missing pod":   {deployapi.DeploymentStatusFailed, "deploy-config-0", kapi.PodFailed, "config-1", ""},
+		"no deployment": {deployapi.DeploymentStatusRunning, "deploy-config-1", kapi.PodRunning, "config-2", ""},
+	}
+
+	for name, test := range testCases {
+		actual, err := fakeREST(test.status, test.podName, test.phase).ResourceLocation(kapi.NewDefaultContext(), test.name)
+		if len(test.expected) == 0 {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %s", name, actual)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if actual != test.expected {
+			t.Errorf("%s: expected %s, got %s", name, test.expected, actual)
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	"github.com/openshift/origin/pkg/deploy/api"
+	etcdutil "github.com/openshift/origin/pkg/util/etcd"
 )
 
 const (
@@ -45,10 +46,7 @@ func (r *Etcd) ListDeployments(ctx kapi.Context, label, field labels.Selector) (
 
 	filtered := []api.Deployment{}
 	for _, item := range deployments.Items {
-		fields := labels.Set{
-			"name":   item.Name,
-			"status": string(item.Status),
-		}
+		fields := api.DeploymentToSelectableFields(&item)
 		if label.Matches(labels.Set(item.Labels)) && field.Matches(fields) {
 			filtered = append(filtered, item)
 		}
@@ -96,8 +94,7 @@ func (r *Etcd) UpdateDeployment(ctx kapi.Context, deployment *api.Deployment) er
 	if err != nil {
 		return err
 	}
-	err = r.SetObj(key, deployment)
-	return etcderr.InterpretUpdateError(err, "deployment", deployment.Name)
+	return etcdutil.UpdateObj(r.EtcdHelper, key, deployment, "deployment", deployment.Name)
 }
 
 // DeleteDeployment deletes a Deployment specified by its ID.
@@ -123,10 +120,7 @@ func (r *Etcd) WatchDeployments(ctx kapi.Context, label, field labels.Selector,
 			glog.Errorf("Unexpected object during deployment watch: %#v", obj)
 			return false
 		}
-		fields := labels.Set{
-			"name":   deployment.Name,
-			"status": string(deployment.Status),
-		}
+		fields := api.DeploymentToSelectableFields(deployment)
 		return label.Matches(labels.Set(deployment.Labels)) && field.Matches(fields)
 	})
 }
@@ -140,9 +134,7 @@ func (r *Etcd) ListDeploymentConfigs(ctx kapi.Context, label, field labels.Selec
 	}
 	filtered := []api.DeploymentConfig{}
 	for _, item := range deploymentConfigs.Items {
-		fields := labels.Set{
-			"name": item.Name,
-		}
+		fields := api.DeploymentConfigToSelectableFields(&item)
 		if label.Matches(labels.Set(item.Labels)) && field.Matches(fields) {
 			filtered = append(filtered, item)
 		}
@@ -165,9 +157,7 @@ func (r *Etcd) WatchDeploymentConfigs(ctx kapi.Context, label, field labels.Sele
 			glog.Errorf("Unexpected object during deploymentConfig watch: %#v", obj)
 			return false
 		}
-		fields := labels.Set{
-			"name": config.Name,
-		}
+		fields := api.DeploymentConfigToSelectableFields(config)
 		return label.Matches(labels.Set(config.Labels)) && field.Matches(fields)
 	})
 }
@@ -213,8 +203,7 @@ func (r *Etcd) UpdateDeploymentConfig(ctx kapi.Context, deploymentConfig *api.De
 		return err
 	}
 
-	err = r.SetObj(key, deploymentConfig)
-	return etcderr.InterpretUpdateError(err, "deploymentConfig", deploymentConfig.Name)
+	return etcdutil.UpdateObj(r.EtcdHelper, key, deploymentConfig, "deploymentConfig", deploymentConfig.Name)
 }
 
 // DeleteDeploymentConfig deletes a DeploymentConfig specified by its ID.
@@ -2,6 +2,7 @@ package etcd
 
 import (
 	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/coreos/go-etcd/etcd"
@@ -272,10 +273,21 @@ func TestEtcdCreateAlreadyExistsDeployments(t *testing.T) {
 
 func TestEtcdUpdateOkDeployments(t *testing.T) {
 	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	resp, _ := fakeClient.Set(makeTestDefaultDeploymentKey("foo"), runtime.EncodeOrDie(latest.Codec, &api.Deployment{ObjectMeta: kapi.ObjectMeta{Name: "foo"}}), 0)
 	registry := NewTestEtcd(fakeClient)
-	err := registry.UpdateDeployment(kapi.NewDefaultContext(), &api.Deployment{ObjectMeta: kapi.ObjectMeta{Name: "foo"}})
+	err := registry.UpdateDeployment(kapi.NewDefaultContext(), &api.Deployment{ObjectMeta: kapi.ObjectMeta{Name: "foo", ResourceVersion: strconv.FormatUint(resp.Node.ModifiedIndex, 10)}})
 	if err != nil {
-		t.Error("Unexpected error: %#v", err)
+		t.Errorf("Unexpected error: %#v", err)
+	}
+}
+
+func TestEtcdUpdateDeploymentsWithoutResourceVersion(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	registry := NewTestEtcd(fakeClient)
+	err := registry.UpdateDeployment(kapi.NewDefaultContext(), &api.Deployment{ObjectMeta: kapi.ObjectMeta{Name: "foo"}})
+	if !errors.IsConflict(err) {
+		t.Errorf("Expected a conflict, got %#v", err)
 	}
 }
 
@@ -496,10 +508,23 @@ func TestEtcdCreateAlreadyExistsDeploymentConfig(t *testing.T) {
 
 func TestEtcdUpdateOkDeploymentConfig(t *testing.T) {
 	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	resp, _ := fakeClient.Set(makeTestDefaultDeploymentConfigKey("foo"), runtime.EncodeOrDie(latest.Codec, &api.DeploymentConfig{ObjectMeta: kapi.ObjectMeta{Name: "foo"}}), 0)
 	registry := NewTestEtcd(fakeClient)
-	err := registry.UpdateDeploymentConfig(kapi.NewDefaultContext(), &api.DeploymentConfig{ObjectMeta: kapi.ObjectMeta{Name: "foo"}})
+	err := registry.UpdateDeploymentConfig(kapi.NewDefaultContext(), &api.DeploymentConfig{ObjectMeta: kapi.ObjectMeta{Name: "foo", ResourceVersion: strconv.FormatUint(resp.Node.ModifiedIndex, 10)}})
 	if err != nil {
-		t.Error("Unexpected error %#v", err)
+		t.Errorf("Unexpected error %#v", err)
+	}
+}
+
+func TestEtcdUpdateStaleDeploymentConfig(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	resp, _ := fakeClient.Set(makeTestDefaultDeploymentConfigKey("foo"), runtime.EncodeOrDie(latest.Codec, &api.DeploymentConfig{ObjectMeta: kapi.ObjectMeta{Name: "foo"}}), 0)
+	registry := NewTestEtcd(fakeClient)
+	err := registry.UpdateDeploymentConfig(kapi.NewDefaultContext(), &api.DeploymentConfig{ObjectMeta: kapi.ObjectMeta{Name: "foo", ResourceVersion: strconv.FormatUint(resp.Node.ModifiedIndex-1, 10)}})
+	if !errors.IsConflict(err) {
+		t.Errorf("Expected a conflict, got %#v", err)
 	}
 }
 
@@ -69,7 +69,11 @@ func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 	// Roll back "from" the current deployment "to" a target deployment
 
 	// Find the target ("to") deployment and decode the DeploymentConfig
-	targetDeployment, err := s.generator.GetDeployment(ctx, rollback.Spec.From.Name)
+	targetName := rollback.Spec.From.Name
+	if rollback.Spec.From.Kind == "DeploymentConfig" {
+		targetName = deployutil.DeploymentNameForConfigVersion(rollback.Spec.From.Name, rollback.Spec.Revision)
+	}
+	targetDeployment, err := s.generator.GetDeployment(ctx, targetName)
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			return nil, newInvalidDeploymentError(rollback, "Deployment not found")
@@ -101,5 +105,8 @@ func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 
 func newInvalidDeploymentError(rollback *deployapi.DeploymentConfigRollback, reason string) error {
 	err := kerrors.NewFieldInvalid("spec.from.name", rollback.Spec.From.Name, reason)
+	if rollback.Spec.From.Kind == "DeploymentConfig" {
+		err = kerrors.NewFieldInvalid("spec.revision", rollback.Spec.Revision, reason)
+	}
 	return kerrors.NewInvalid("DeploymentConfigRollback", "", kerrors.ValidationErrorList{err})
 }
@@ -85,6 +85,63 @@ func TestCreateOk(t *testing.T) {
 	}
 }
 
+func TestCreateRevision(t *testing.T) {
+	requested := ""
+	rest := REST{
+		generator: Client{
+			GRFn: func(from, to *deployapi.DeploymentConfig, spec *deployapi.DeploymentConfigRollbackSpec) (*deployapi.DeploymentConfig, error) {
+				return to, nil
+			},
+			RCFn: func(ctx kapi.Context, name string) (*kapi.ReplicationController, error) {
+				requested = name
+				if name != "config-2" {
+					return nil, kerrors.NewNotFound("replicationController", name)
+				}
+				deployment, _ := deployutil.MakeDeployment(deploytest.OkDeploymentConfig(2), kapi.Codec)
+				return deployment, nil
+			},
+			DCFn: func(ctx kapi.Context, name string) (*deployapi.DeploymentConfig, error) {
+				return deploytest.OkDeploymentConfig(3), nil
+			},
+		},
+		codec: api.Codec,
+	}
+
+	channel, err := rest.Create(kapi.NewDefaultContext(), &deployapi.DeploymentConfigRollback{
+		Spec: deployapi.DeploymentConfigRollbackSpec{
+			From:     kapi.ObjectReference{Kind: "DeploymentConfig", Name: "config"},
+			Revision: 2,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requested != "config-2" {
+		t.Errorf("Expected the deployment of revision 2 to be rolled back to, got %s", requested)
+	}
+	select {
+	case result := <-channel:
+		if config, ok := result.Object.(*deployapi.DeploymentConfig); !ok || config.LatestVersion != 2 {
+			t.Errorf("expected the DeploymentConfig of revision 2, got a %#v", result.Object)
+		}
+	case <-time.After(50 * time.Millisecond):
+		t.Errorf("Timed out waiting for result")
+	}
+
+	_, err = rest.Create(kapi.NewDefaultContext(), &deployapi.DeploymentConfigRollback{
+		Spec: deployapi.DeploymentConfigRollbackSpec{
+			From:     kapi.ObjectReference{Kind: "DeploymentConfig", Name: "config"},
+			Revision: 5,
+		},
+	})
+	if err == nil || !kerrors.IsInvalid(err) {
+		t.Fatalf("Expected an invalid error for a missing revision, got %v", err)
+	}
+	if details := err.(*kerrors.StatusError).ErrStatus.Details; len(details.Causes) != 1 || details.Causes[0].Field != "spec.revision" {
+		t.Errorf("Expected the revision to be invalid, got %#v", details)
+	}
+}
+
 func TestCreateGeneratorError(t *testing.T) {
 	rest := REST{
 		generator: Client{
@@ -9,8 +9,10 @@ import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 
+	"github.com/openshift/origin/pkg/client/record"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	deployutil "github.com/openshift/origin/pkg/deploy/util"
 )
@@ -20,22 +22,34 @@ import (
 // to zero.
 //
 // A failure to disable any existing deployments will be considered a deployment failure.
+//
+// Deployments of a DeploymentConfig marked as a test are validated instead: the new deployment is scaled
+// up until all of its pods run and then back to zero, and previous deployments are left as they are.
 type RecreateDeploymentStrategy struct {
 	// client is used to interact with ReplicatonControllers.
 	client replicationControllerClient
+	// pods is used to list the pods of test deployments.
+	pods podClient
 	// codec is used to decode DeploymentConfigs contained in deployments.
 	codec runtime.Codec
+	// recorder records an event for every deployment that is scaled.
+	recorder record.Recorder
 
 	retryTimeout time.Duration
 	retryPeriod  time.Duration
+	// validationTimeout is how long the pods of a test deployment may take to run.
+	validationTimeout time.Duration
 }
 
 func NewRecreateDeploymentStrategy(client kclient.Interface, codec runtime.Codec) *RecreateDeploymentStrategy {
 	return &RecreateDeploymentStrategy{
-		client:       &realReplicationController{client},
-		codec:        codec,
-		retryTimeout: 10 * time.Second,
-		retryPeriod:  1 * time.Second,
+		client:            &realReplicationController{client},
+		pods:              &realPodClient{client},
+		codec:             codec,
+		recorder:          record.NewClientRecorder(client, kapi.EventSource{Component: "deployer"}),
+		retryTimeout:      10 * time.Second,
+		retryPeriod:       1 * time.Second,
+		validationTimeout: 5 * time.Minute,
 	}
 }
 
@@ -48,6 +62,10 @@ func (s *RecreateDeploymentStrategy) Deploy(deployment *kapi.ReplicationControll
 		return fmt.Errorf("Couldn't decode DeploymentConfig from deployment %s: %v", deployment.Name, err)
 	}
 
+	if deploymentConfig.Test {
+		return s.test(deployment, deploymentConfig.Template.ControllerTemplate.Replicas)
+	}
+
 	if err = s.updateReplicas(deployment.Namespace, deployment.Name, deploymentConfig.Template.ControllerTemplate.Replicas); err != nil {
 		return err
 	}
@@ -70,6 +88,67 @@ func (s *RecreateDeploymentStrategy) Deploy(deployment *kapi.ReplicationControll
 	return nil
 }
 
+// test scales deployment up, waits for its pods to run and scales it back to zero.  An error is returned if
+// the pods failed or did not all run before the validation timeout.
+func (s *RecreateDeploymentStrategy) test(deployment *kapi.ReplicationController, replicas int) error {
+	// A test deployment validates at least one pod.
+	if replicas == 0 {
+		replicas = 1
+	}
+	if err := s.updateReplicas(deployment.Namespace, deployment.Name, replicas); err != nil {
+		return err
+	}
+
+	validationErr := s.waitForPods(deployment, replicas)
+	if validationErr != nil {
+		s.recorder.Eventf(deployment, "failedValidation", "Test deployment %s failed validation: %v", deployment.Name, validationErr)
+	} else {
+		s.recorder.Eventf(deployment, "validated", "Validated %d pods of test deployment %s", replicas, deployment.Name)
+	}
+
+	if err := s.updateReplicas(deployment.Namespace, deployment.Name, 0); err != nil {
+		return err
+	}
+	if validationErr != nil {
+		return fmt.Errorf("Test deployment %s failed validation: %v", deployment.Name, validationErr)
+	}
+	glog.Infof("Test deployment %s validated", deployment.Name)
+	return nil
+}
+
+// waitForPods waits until replicas pods selected by deployment are running.  It fails as soon as one of the
+// pods fails.
+func (s *RecreateDeploymentStrategy) waitForPods(deployment *kapi.ReplicationController, replicas int) error {
+	selector := labels.SelectorFromSet(deployment.Spec.Selector)
+	timeout := time.After(s.validationTimeout)
+	for {
+		select {
+		case <-timeout:
+			return fmt.Errorf("%d pods were not running after %v", replicas, s.validationTimeout)
+		default:
+			pods, err := s.pods.listPods(deployment.Namespace, selector)
+			if err != nil {
+				glog.Errorf("Couldn't list pods of deployment %s/%s: %v", deployment.Namespace, deployment.Name, err)
+			} else {
+				running := 0
+				for _, pod := range pods.Items {
+					switch pod.Status.Phase {
+					case kapi.PodRunning:
+						running++
+					case kapi.PodFailed:
+						return fmt.Errorf("pod %s failed", pod.Name)
+					}
+				}
+				if running >= replicas {
+					return nil
+				}
+			}
+
+			time.Sleep(s.retryPeriod)
+		}
+	}
+}
+
 // updateReplicas attempts to set the given deployment's replicaCount using retry logic.
 func (s *RecreateDeploymentStrategy) updateReplicas(namespace, name string, replicaCount int) error {
 	var err error
@@ -87,6 +166,7 @@ func (s *RecreateDeploymentStrategy) updateReplicas(namespace, name string, repl
 				deployment.Spec.Replicas = replicaCount
 				glog.Infof("Updating deployment %s/%s replica count to %d", namespace, name, replicaCount)
 				if _, err = s.client.updateReplicationController(namespace, deployment); err == nil {
+					s.recorder.Eventf(deployment, "scaled", "Scaled deployment %s to %d replicas", name, replicaCount)
 					return nil
 				}
 				// For conflict errors, retry immediately
@@ -117,3 +197,15 @@ func (r realReplicationController) getReplicationController(namespace string, na
 func (r realReplicationController) updateReplicationController(namespace string, ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
 	return r.client.ReplicationControllers(namespace).Update(ctrl)
 }
+
+type podClient interface {
+	listPods(namespace string, selector labels.Selector) (*kapi.PodList, error)
+}
+
+type realPodClient struct {
+	client kclient.Interface
+}
+
+func (r realPodClient) listPods(namespace string, selector labels.Selector) (*kapi.PodList, error) {
+	return r.client.Pods(namespace).List(selector)
+}
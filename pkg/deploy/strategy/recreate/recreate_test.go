@@ -2,12 +2,15 @@ package recreate
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 
 	api "github.com/openshift/origin/pkg/api/latest"
+	"github.com/openshift/origin/pkg/client/record"
 	deploytest "github.com/openshift/origin/pkg/deploy/api/test"
 	deployutil "github.com/openshift/origin/pkg/deploy/util"
 )
@@ -18,6 +21,7 @@ func TestFirstDeployment(t *testing.T) {
 
 	strategy := &RecreateDeploymentStrategy{
 		codec:        api.Codec,
+		recorder:     &record.FakeRecorder{},
 		retryTimeout: 1 * time.Second,
 		retryPeriod:  1 * time.Millisecond,
 		client: &testControllerClient{
@@ -44,6 +48,10 @@ func TestFirstDeployment(t *testing.T) {
 	if e, a := 1, updatedController.Spec.Replicas; e != a {
 		t.Fatalf("expected controller replicas to be %d, got %d", e, a)
 	}
+
+	if e, a := 1, len(strategy.recorder.(*record.FakeRecorder).Events); e != a {
+		t.Fatalf("expected %d scaled event, got %d", e, a)
+	}
 }
 
 func TestSecondDeploymentSuccessfulRetries(t *testing.T) {
@@ -56,6 +64,7 @@ func TestSecondDeploymentSuccessfulRetries(t *testing.T) {
 
 	strategy := &RecreateDeploymentStrategy{
 		codec:        api.Codec,
+		recorder:     &record.FakeRecorder{},
 		retryTimeout: 1 * time.Second,
 		retryPeriod:  1 * time.Millisecond,
 		client: &testControllerClient{
@@ -109,6 +118,7 @@ func TestSecondDeploymentFailedInitialRetries(t *testing.T) {
 
 	strategy := &RecreateDeploymentStrategy{
 		codec:        api.Codec,
+		recorder:     &record.FakeRecorder{},
 		retryTimeout: 1 * time.Millisecond,
 		retryPeriod:  1 * time.Millisecond,
 		client: &testControllerClient{
@@ -153,6 +163,7 @@ func TestSecondDeploymentFailedDisableRetries(t *testing.T) {
 
 	strategy := &RecreateDeploymentStrategy{
 		codec:        api.Codec,
+		recorder:     &record.FakeRecorder{},
 		retryTimeout: 1 * time.Millisecond,
 		retryPeriod:  1 * time.Millisecond,
 		client: &testControllerClient{
@@ -197,6 +208,58 @@ func TestSecondDeploymentFailedDisableRetries(t *testing.T) {
 	}
 }
 
+func TestTestDeployment(t *testing.T) {
+	for phase, expectErr := range map[kapi.PodPhase]bool{kapi.PodRunning: false, kapi.PodFailed: true, kapi.PodPending: true} {
+		replicas := []int{}
+		config := deploytest.OkDeploymentConfig(2)
+		config.Test = true
+		config.Template.ControllerTemplate.Replicas = 2
+		deployment, _ := deployutil.MakeDeployment(config, kapi.Codec)
+
+		strategy := &RecreateDeploymentStrategy{
+			codec:             api.Codec,
+			recorder:          &record.FakeRecorder{},
+			retryTimeout:      1 * time.Second,
+			retryPeriod:       1 * time.Millisecond,
+			validationTimeout: 10 * time.Millisecond,
+			client: &testControllerClient{
+				getReplicationControllerFunc: func(namespace, name string) (*kapi.ReplicationController, error) {
+					if name != deployment.Name {
+						t.Fatalf("unexpected call to getReplicationController: %s/%s", namespace, name)
+					}
+					return deployment, nil
+				},
+				updateReplicationControllerFunc: func(namespace string, ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+					replicas = append(replicas, ctrl.Spec.Replicas)
+					return ctrl, nil
+				},
+			},
+			pods: &testPodClient{
+				listPodsFunc: func(namespace string, selector labels.Selector) (*kapi.PodList, error) {
+					if !selector.Matches(labels.Set(deployment.Spec.Template.Labels)) {
+						t.Errorf("expected the pods of the deployment to be selected, got %s", selector)
+					}
+					return &kapi.PodList{Items: []kapi.Pod{
+						{ObjectMeta: kapi.ObjectMeta{Name: "pod-1"}, Status: kapi.PodStatus{Phase: kapi.PodRunning}},
+						{ObjectMeta: kapi.ObjectMeta{Name: "pod-2"}, Status: kapi.PodStatus{Phase: phase}},
+					}}, nil
+				},
+			},
+		}
+
+		err := strategy.Deploy(deployment, []kapi.ObjectReference{{Namespace: kapi.NamespaceDefault, Name: "config-1"}})
+		if expectErr && err == nil {
+			t.Errorf("%s: expected a validation error", phase)
+		}
+		if !expectErr && err != nil {
+			t.Errorf("%s: unexpected deploy error: %v", phase, err)
+		}
+		if e, a := []int{2, 0}, replicas; !reflect.DeepEqual(e, a) {
+			t.Errorf("%s: expected the test deployment to be scaled to %v and prior deployments to be left alone, got %v", phase, e, a)
+		}
+	}
+}
+
 type testControllerClient struct {
 	getReplicationControllerFunc    func(namespace, name string) (*kapi.ReplicationController, error)
 	updateReplicationControllerFunc func(namespace string, ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error)
@@ -209,3 +272,11 @@ func (t *testControllerClient) getReplicationController(namespace, name string)
 func (t *testControllerClient) updateReplicationController(namespace string, ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
 	return t.updateReplicationControllerFunc(namespace, ctrl)
 }
+
+type testPodClient struct {
+	listPodsFunc func(namespace string, selector labels.Selector) (*kapi.PodList, error)
+}
+
+func (t *testPodClient) listPods(namespace string, selector labels.Selector) (*kapi.PodList, error) {
+	return t.listPodsFunc(namespace, selector)
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/adler32"
+	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/golang/glog"
 
@@ -16,17 +18,25 @@ import (
 
 // LatestDeploymentNameForConfig returns a stable identifier for config based on its version.
 func LatestDeploymentNameForConfig(config *deployapi.DeploymentConfig) string {
-	return config.Name + "-" + strconv.Itoa(config.LatestVersion)
+	return DeploymentNameForConfigVersion(config.Name, config.LatestVersion)
+}
+
+// DeploymentNameForConfigVersion returns the name of the deployment of the given version of the config name.
+func DeploymentNameForConfigVersion(name string, version int) string {
+	return name + "-" + strconv.Itoa(version)
 }
 
 // HashPodSpecs hashes a PodSpec into a uint64.
 // TODO: Resources are currently ignored due to the formats not surviving encoding/decoding
 // in a consistent manner (e.g. 0 is represented sometimes as 0.000)
 func HashPodSpec(t api.PodSpec) uint64 {
-	// Ignore resources by making them uniformly empty
-	for i := range t.Containers {
-		t.Containers[i].Resources = api.ResourceRequirementSpec{}
+	// Ignore resources by making them uniformly empty, in a copy of the containers shared with the caller
+	containers := make([]api.Container, len(t.Containers))
+	copy(containers, t.Containers)
+	for i := range containers {
+		containers[i].Resources = api.ResourceRequirementSpec{}
 	}
+	t.Containers = containers
 
 	jsonString, err := json.Marshal(t)
 	if err != nil {
@@ -43,6 +53,105 @@ func PodSpecsEqual(a, b api.PodSpec) bool {
 	return HashPodSpec(a) == HashPodSpec(b)
 }
 
+// PodTemplatesEqual returns true if the given pod templates have the same labels, annotations and
+// spec.  Unlike PodSpecsEqual, container resources are compared, by their value rather than how it is
+// formatted.
+func PodTemplatesEqual(a, b *api.PodTemplateSpec) bool {
+	return api.Semantic.DeepEqual(a, b)
+}
+
+// PodSpecsEqualIgnoring returns true if the given PodSpecs are the same once the fields named in ignored
+// are cleared from both.  Fields are named by their JSON path, like "nodeSelector" or "containers.env"; a
+// path through a list names the field in every item of the list.
+func PodSpecsEqualIgnoring(a, b api.PodSpec, ignored []string) bool {
+	if len(ignored) == 0 {
+		return PodSpecsEqual(a, b)
+	}
+	fieldsA, err := podSpecFields(a, ignored)
+	if err != nil {
+		glog.Errorf("An error occurred marshalling pod state: %v", err)
+		return false
+	}
+	fieldsB, err := podSpecFields(b, ignored)
+	if err != nil {
+		glog.Errorf("An error occurred marshalling pod state: %v", err)
+		return false
+	}
+	return reflect.DeepEqual(fieldsA, fieldsB)
+}
+
+// IsPodSpecFieldPath returns true if path names a field of a PodSpec in the form PodSpecsEqualIgnoring
+// accepts.
+func IsPodSpecFieldPath(path string) bool {
+	t := reflect.TypeOf(api.PodSpec{})
+	for _, name := range strings.Split(path, ".") {
+		for t.Kind() == reflect.Slice || t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || len(name) == 0 {
+			return false
+		}
+		field, ok := fieldByJSONName(t, name)
+		if !ok {
+			return false
+		}
+		t = field.Type
+	}
+	return true
+}
+
+// fieldByJSONName returns the field of the struct type t that is serialized as name.
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if strings.Split(field.Tag.Get("json"), ",")[0] == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// podSpecFields returns the serialized fields of spec without the ignored ones.  Resources are left out as
+// they are by HashPodSpec.
+func podSpecFields(spec api.PodSpec, ignored []string) (map[string]interface{}, error) {
+	containers := make([]api.Container, len(spec.Containers))
+	copy(containers, spec.Containers)
+	for i := range containers {
+		containers[i].Resources = api.ResourceRequirementSpec{}
+	}
+	spec.Containers = containers
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for _, path := range ignored {
+		removeField(fields, strings.Split(path, "."))
+	}
+	return fields, nil
+}
+
+// removeField deletes the field at path from the serialized object obj, descending into every item of the
+// lists along the path.
+func removeField(obj interface{}, path []string) {
+	switch t := obj.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			delete(t, path[0])
+			return
+		}
+		removeField(t[path[0]], path[1:])
+	case []interface{}:
+		for _, item := range t {
+			removeField(item, path)
+		}
+	}
+}
+
 // DecodeDeploymentConfig decodes a DeploymentConfig from controller using codec. An error is returned
 // if the controller doesn't contain an encoded config.
 func DecodeDeploymentConfig(controller *api.ReplicationController, codec runtime.Codec) (*deployapi.DeploymentConfig, error) {
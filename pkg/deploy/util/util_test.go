@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	deploytest "github.com/openshift/origin/pkg/deploy/api/test"
 )
@@ -76,6 +77,61 @@ func TestPodSpecsEqualAdditionalContainerInManifest(t *testing.T) {
 	}
 }
 
+func TestPodTemplatesEqual(t *testing.T) {
+	a := podTemplateA()
+	b := podTemplateA()
+	a.Spec.Containers[0].Resources = kapi.ResourceRequirementSpec{Limits: kapi.ResourceList{kapi.ResourceCPU: resource.MustParse("1")}}
+	b.Spec.Containers[0].Resources = kapi.ResourceRequirementSpec{Limits: kapi.ResourceList{kapi.ResourceCPU: resource.MustParse("1000m")}}
+	if !PodTemplatesEqual(a, b) {
+		t.Errorf("Unexpected false result for equal resources formatted differently")
+	}
+
+	b.Spec.Containers[0].Resources.Limits[kapi.ResourceCPU] = resource.MustParse("2")
+	if PodTemplatesEqual(a, b) {
+		t.Errorf("Unexpected true result for changed resources")
+	}
+	if PodTemplatesEqual(podTemplateA(), podTemplateB()) {
+		t.Errorf("Unexpected true result for changed labels")
+	}
+}
+
+func TestPodSpecsEqualIgnoring(t *testing.T) {
+	a := podTemplateA().Spec
+	b := podTemplateA().Spec
+	b.NodeSelector = map[string]string{"region": "east"}
+	b.Containers[1].Env = []kapi.EnvVar{{Name: "FOO", Value: "bar"}}
+
+	if PodSpecsEqualIgnoring(a, b, nil) {
+		t.Errorf("Unexpected true result without ignored fields")
+	}
+	if PodSpecsEqualIgnoring(a, b, []string{"nodeSelector"}) {
+		t.Errorf("Unexpected true result with a changed field that is not ignored")
+	}
+	if !PodSpecsEqualIgnoring(a, b, []string{"nodeSelector", "containers.env"}) {
+		t.Errorf("Unexpected false result with every changed field ignored")
+	}
+	if len(b.Containers[1].Env) != 1 {
+		t.Errorf("Expected the compared specs to be left unchanged")
+	}
+}
+
+func TestIsPodSpecFieldPath(t *testing.T) {
+	for path, expected := range map[string]bool{
+		"nodeSelector":              true,
+		"containers.env":            true,
+		"containers.ports.hostPort": true,
+		"volumes.source":            true,
+		"containers.unknown":        false,
+		"nodeSelector.region":       false,
+		"replicas":                  false,
+		"":                          false,
+	} {
+		if actual := IsPodSpecFieldPath(path); actual != expected {
+			t.Errorf("Expected %t for %q, got %t", expected, path, actual)
+		}
+	}
+}
+
 func TestMakeDeploymentOk(t *testing.T) {
 	config := deploytest.OkDeploymentConfig(1)
 	deployment, err := MakeDeployment(config, kapi.Codec)
@@ -0,0 +1,3 @@
+// Package election elects one of several processes sharing an etcd cluster to perform a task, by having
+// the processes compete for a lease stored in etcd.
+package election
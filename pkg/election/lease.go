@@ -0,0 +1,132 @@
+package election
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+)
+
+// Lease is held by at most one process at a time: the value of its etcd key identifies the holder, and
+// the key expires unless the holder renews it within the TTL of the lease.
+type Lease struct {
+	client tools.EtcdGetSet
+	key    string
+	id     string
+	ttl    uint64
+
+	// renewed is when the last successful acquisition or renewal was requested, which the key
+	// expires a TTL after at the latest.
+	renewed time.Time
+}
+
+// NewEtcdLease returns the lease stored at key in client, competed for by the process identified by id.
+// The TTL is rounded up to whole seconds.
+func NewEtcdLease(client tools.EtcdGetSet, key, id string, ttl time.Duration) *Lease {
+	seconds := uint64((ttl + time.Second - 1) / time.Second)
+	return &Lease{client: client, key: key, id: id, ttl: seconds}
+}
+
+// TryAcquire attempts to acquire the lease once, and returns true if it is held.  A lease already held
+// under the id of the process is renewed.
+func (l *Lease) TryAcquire() (bool, error) {
+	requested := time.Now()
+	_, err := l.client.Create(l.key, l.id, l.ttl)
+	if err == nil {
+		l.renewed = requested
+		return true, nil
+	}
+	if !tools.IsEtcdNodeExist(err) {
+		return false, err
+	}
+	resp, err := l.client.Get(l.key, false, false)
+	if err != nil {
+		if tools.IsEtcdNotFound(err) {
+			// the lease expired since it was created, the next attempt may acquire it
+			return false, nil
+		}
+		return false, err
+	}
+	if resp.Node == nil || resp.Node.Value != l.id {
+		return false, nil
+	}
+	if err := l.Renew(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Acquire attempts to acquire the lease every interval until it is held, and returns true, or until stop
+// is closed, and returns false.
+func (l *Lease) Acquire(interval time.Duration, stop <-chan struct{}) bool {
+	for {
+		held, err := l.TryAcquire()
+		if err != nil {
+			util.HandleError(fmt.Errorf("unable to acquire the lease %s: %v", l.key, err))
+		}
+		if held {
+			glog.V(2).Infof("Acquired the lease %s as %s", l.key, l.id)
+			return true
+		}
+		select {
+		case <-stop:
+			return false
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Renew extends the lease by its TTL.  It returns an error if the lease is not held by the process.
+func (l *Lease) Renew() error {
+	requested := time.Now()
+	if _, err := l.client.CompareAndSwap(l.key, l.id, l.ttl, l.id, 0); err != nil {
+		if tools.IsEtcdNotFound(err) || tools.IsEtcdTestFailed(err) {
+			return lostError{l}
+		}
+		return err
+	}
+	l.renewed = requested
+	return nil
+}
+
+// Hold renews the held lease every interval until a renewal fails, when lost is called with the
+// failure, or until stop is closed.  Intervals well below the TTL leave room for failed renewals to be
+// retried, but renewals are abandoned once the next attempt would come after the lease may have
+// expired, so the holder stops before another process can acquire the lease.
+func (l *Lease) Hold(interval time.Duration, lost func(error), stop <-chan struct{}) {
+	ttl := time.Duration(l.ttl) * time.Second
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(interval):
+		}
+		err := l.Renew()
+		if err == nil {
+			continue
+		}
+		if time.Since(l.renewed)+interval < ttl && !isLost(err) {
+			util.HandleError(fmt.Errorf("unable to renew the lease %s: %v", l.key, err))
+			continue
+		}
+		lost(err)
+		return
+	}
+}
+
+// lostError reports that a lease expired or is held by another process.
+type lostError struct {
+	lease *Lease
+}
+
+func (e lostError) Error() string {
+	return fmt.Sprintf("the lease %s is no longer held by %s", e.lease.key, e.lease.id)
+}
+
+// isLost returns true if err reports the lease expired or is held by another process.
+func isLost(err error) bool {
+	_, ok := err.(lostError)
+	return ok
+}
@@ -0,0 +1,130 @@
+package election
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/coreos/go-etcd/etcd"
+)
+
+func newFakeClient(t *testing.T) *tools.FakeEtcdClient {
+	client := tools.NewFakeEtcdClient(t)
+	client.TestIndex = true
+	return client
+}
+
+func TestLeaseIsHeldByOneProcess(t *testing.T) {
+	client := newFakeClient(t)
+	client.ExpectNotFoundGet("/leases/test")
+	first := NewEtcdLease(client, "/leases/test", "first", 1500*time.Millisecond)
+	second := NewEtcdLease(client, "/leases/test", "second", 1500*time.Millisecond)
+
+	if held, err := first.TryAcquire(); !held || err != nil {
+		t.Fatalf("Expected the first process to acquire the lease, got %t %v", held, err)
+	}
+	if client.LastSetTTL != 2 {
+		t.Errorf("Expected the TTL to be rounded up to 2 seconds, got %d", client.LastSetTTL)
+	}
+	if held, err := second.TryAcquire(); held || err != nil {
+		t.Errorf("Expected the second process not to acquire the lease, got %t %v", held, err)
+	}
+	if err := second.Renew(); !isLost(err) {
+		t.Errorf("Expected the second process not to renew the lease, got %v", err)
+	}
+	if err := first.Renew(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestLeaseIsReacquiredByItsHolder(t *testing.T) {
+	client := newFakeClient(t)
+	client.Set("/leases/test", "first", 10)
+	lease := NewEtcdLease(client, "/leases/test", "first", 30*time.Second)
+	if held, err := lease.TryAcquire(); !held || err != nil {
+		t.Errorf("Expected the lease to be reacquired, got %t %v", held, err)
+	}
+}
+
+func TestAcquireStops(t *testing.T) {
+	client := newFakeClient(t)
+	client.Set("/leases/test", "other", 10)
+	stop := make(chan struct{})
+	close(stop)
+	if NewEtcdLease(client, "/leases/test", "first", time.Second).Acquire(time.Millisecond, stop) {
+		t.Errorf("Expected the lease not to be acquired")
+	}
+}
+
+func TestHoldReportsLostLease(t *testing.T) {
+	client := newFakeClient(t)
+	client.ExpectNotFoundGet("/leases/test")
+	lease := NewEtcdLease(client, "/leases/test", "first", 30*time.Second)
+	if held, err := lease.TryAcquire(); !held || err != nil {
+		t.Fatalf("Expected the lease to be acquired, got %t %v", held, err)
+	}
+	// another process acquires the lease after it expired
+	client.Set("/leases/test", "other", 10)
+
+	lost := make(chan error, 1)
+	go lease.Hold(time.Millisecond, func(err error) { lost <- err }, nil)
+	select {
+	case err := <-lost:
+		if !isLost(err) {
+			t.Errorf("Expected the lease to be reported lost, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the lease to be reported lost")
+	}
+}
+
+func TestHoldRetriesFailedRenewals(t *testing.T) {
+	client := newFakeClient(t)
+	client.Set("/leases/test", "first", 10)
+	lease := NewEtcdLease(client, "/leases/test", "first", 30*time.Second)
+	if held, err := lease.TryAcquire(); !held || err != nil {
+		t.Fatalf("Expected the lease to be reacquired, got %t %v", held, err)
+	}
+	client.Err = &etcd.EtcdError{ErrorCode: 300}
+
+	lost := make(chan error, 1)
+	stop, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		lease.Hold(time.Millisecond, func(err error) { lost <- err }, stop)
+		close(done)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(stop)
+	<-done
+	select {
+	case err := <-lost:
+		t.Errorf("Expected failed renewals to be retried within the TTL, got %v", err)
+	default:
+	}
+	if isLost(errors.New("unavailable")) {
+		t.Errorf("Expected other errors not to report the lease lost")
+	}
+}
+
+func TestHoldStopsBeforeTheLeaseExpires(t *testing.T) {
+	client := newFakeClient(t)
+	client.ExpectNotFoundGet("/leases/test")
+	lease := NewEtcdLease(client, "/leases/test", "first", time.Second)
+	acquired := time.Now()
+	if held, err := lease.TryAcquire(); !held || err != nil {
+		t.Fatalf("Expected the lease to be acquired, got %t %v", held, err)
+	}
+	client.Err = &etcd.EtcdError{ErrorCode: 300}
+
+	lost := make(chan error, 1)
+	go lease.Hold(300*time.Millisecond, func(err error) { lost <- err }, nil)
+	select {
+	case <-lost:
+		if elapsed := time.Since(acquired); elapsed >= time.Second {
+			t.Errorf("Expected the lease to be given up before its TTL ran out, gave up after %v", elapsed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the lease to be given up")
+	}
+}
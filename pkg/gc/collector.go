@@ -0,0 +1,395 @@
+package gc
+
+import (
+	"fmt"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/golang/glog"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	"github.com/openshift/origin/pkg/controller"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	templateapi "github.com/openshift/origin/pkg/template/api"
+)
+
+// Relation describes objects of a kind that depend on an owner of another kind in their namespace, and
+// name that owner.
+type Relation struct {
+	// Kind is the kind of the dependents
+	Kind string
+	// OwnerKind is the kind of the owners
+	OwnerKind string
+	// Owner returns the name of the owner of dependent, or false if dependent has no owner
+	Owner func(dependent runtime.Object) (string, bool)
+}
+
+// AnnotationRelation returns the relation of objects of kind that name their owner of ownerKind in
+// annotation.
+func AnnotationRelation(kind, ownerKind, annotation string) Relation {
+	return Relation{
+		Kind:      kind,
+		OwnerKind: ownerKind,
+		Owner: func(dependent runtime.Object) (string, bool) {
+			accessor, err := meta.Accessor(dependent)
+			if err != nil {
+				return "", false
+			}
+			name, ok := accessor.Annotations()[annotation]
+			return name, ok && len(name) > 0
+		},
+	}
+}
+
+// Inventory describes owners of a kind that list the objects they own, which are deleted with the owner.
+// Only the objects of Kinds in the namespace of the owner are deleted, and only if they have the UID the
+// owner recorded for them.
+type Inventory struct {
+	// Kind is the kind of the owners
+	Kind string
+	// Kinds are the kinds of the objects owners may own
+	Kinds util.StringSet
+	// Dependents returns the references of owner to the objects it owns
+	Dependents func(owner runtime.Object) []kapi.ObjectReference
+	// User is an optional function that returns the user who created owner, as whom its objects are
+	// deleted, or "" if the objects are deleted by the collector
+	User func(owner runtime.Object) string
+}
+
+// DefaultRelations returns the relations of the objects OpenShift creates on behalf of others: the pods
+// of builds, the deployments of deployment configs and the deployer pods of deployments.
+func DefaultRelations() []Relation {
+	return []Relation{
+		AnnotationRelation("Pod", "Build", buildapi.BuildAnnotation),
+		AnnotationRelation("ReplicationController", "DeploymentConfig", deployapi.DeploymentConfigAnnotation),
+		AnnotationRelation("Pod", "ReplicationController", deployapi.DeploymentAnnotation),
+	}
+}
+
+// DefaultInventories returns the inventories of OpenShift objects: template instances own the objects
+// created from their template, which are deleted as the user who created the instance.
+func DefaultInventories() []Inventory {
+	return []Inventory{
+		{
+			Kind:  "TemplateInstance",
+			Kinds: util.NewStringSet(templateapi.TemplateInstanceKinds...),
+			Dependents: func(owner runtime.Object) []kapi.ObjectReference {
+				instance, ok := owner.(*templateapi.TemplateInstance)
+				if !ok {
+					return nil
+				}
+				return instance.Objects
+			},
+			User: func(owner runtime.Object) string {
+				accessor, err := meta.Accessor(owner)
+				if err != nil {
+					return ""
+				}
+				return accessor.Annotations()[templateapi.TemplateInstanceRequesterAnnotation]
+			},
+		},
+	}
+}
+
+// Collector deletes the dependents of owners that no longer exist.  The dependents of each relation are
+// checked every Period, the objects listed by the owners of an inventory as a watch reports the owners
+// deleted, and as the owners are listed again every Period.  Kinds are mapped to resources with Mapper, which
+// may map the kinds of several APIs, and each resource is reached through the client Clients returns for it.
+// The objects of owners that name the user who created them are deleted through the collector Impersonate
+// returns for that user, with the permissions of the user rather than those of the collector.
+type Collector struct {
+	// Mapper maps kinds to the resources that serve them
+	Mapper meta.RESTMapper
+	// Clients returns the client of the API serving a resource
+	Clients resource.ClientMapper
+	// Relations are the dependents checked every Period
+	Relations []Relation
+	// Inventories are the owners whose objects are deleted with them
+	Inventories []Inventory
+	// Reapers stop the objects of a kind instead of deleting them, such as replication controllers, which are
+	// scaled down until their pods are gone before they are deleted
+	Reapers map[string]kubectl.Reaper
+	// Impersonate is an optional function that returns a collector that makes its requests as user.  If it is
+	// nil, the objects of every owner are deleted by this collector.
+	Impersonate func(user string) (*Collector, error)
+	// Period is the interval between checks of the dependents of Relations
+	Period time.Duration
+	// Synced is an optional function called after each check of the dependents of Relations
+	Synced func()
+	// Crashed is an optional function called with the value of a panic in the controller.
+	Crashed func(interface{})
+	// Stop is an optional channel that controls when the collector exits.
+	Stop <-chan struct{}
+}
+
+// Run begins deleting the dependents of deleted owners.
+func (c *Collector) Run() {
+	go controller.Until(func() {
+		c.Collect()
+		if c.Synced != nil {
+			c.Synced()
+		}
+	}, c.Period, c.Stop, c.Crashed)
+	for i := range c.Inventories {
+		inventory := c.Inventories[i]
+		known := map[string]runtime.Object{}
+		go controller.Until(func() { c.WatchInventory(inventory, known) }, time.Second, c.Stop, c.Crashed)
+	}
+}
+
+// Collect deletes the dependents of every relation whose owner does not exist.
+func (c *Collector) Collect() {
+	for _, relation := range c.Relations {
+		if err := c.collect(relation); err != nil {
+			util.HandleError(fmt.Errorf("unable to collect %s owned by %s: %v", relation.Kind, relation.OwnerKind, err))
+		}
+	}
+}
+
+// collect deletes the dependents of relation whose owner does not exist.  The failure to check or delete
+// one dependent does not prevent the others from being collected.
+func (c *Collector) collect(relation Relation) error {
+	dependents, err := c.helper(relation.Kind)
+	if err != nil {
+		return err
+	}
+	owners, err := c.helper(relation.OwnerKind)
+	if err != nil {
+		return err
+	}
+	list, err := dependents.List(kapi.NamespaceAll, labels.Everything())
+	if err != nil {
+		return err
+	}
+	items, err := runtime.ExtractList(list)
+	if err != nil {
+		return err
+	}
+
+	// owners are looked up once per namespace and name
+	exists := map[string]bool{}
+	for _, item := range items {
+		owner, ok := relation.Owner(item)
+		if !ok {
+			continue
+		}
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			util.HandleError(err)
+			continue
+		}
+		namespace, name := accessor.Namespace(), accessor.Name()
+		key := namespace + "/" + owner
+		found, checked := exists[key]
+		if !checked {
+			_, err := owners.Get(namespace, owner)
+			switch {
+			case err == nil:
+				found = true
+			case kerrors.IsNotFound(err):
+				found = false
+			default:
+				util.HandleError(fmt.Errorf("unable to get %s %s/%s: %v", relation.OwnerKind, namespace, owner, err))
+				continue
+			}
+			exists[key] = found
+		}
+		if found {
+			continue
+		}
+		glog.V(2).Infof("Deleting %s %s/%s, its %s %s no longer exists", relation.Kind, namespace, name, relation.OwnerKind, owner)
+		if err := c.delete(relation.Kind, dependents, namespace, name); err != nil && !kerrors.IsNotFound(err) {
+			util.HandleError(fmt.Errorf("unable to delete %s %s/%s: %v", relation.Kind, namespace, name, err))
+		}
+	}
+	return nil
+}
+
+// WatchInventory lists the owners of inventory and deletes the objects of the owners in known that are no
+// longer listed, then deletes the objects of the owners a watch reports deleted, until the watch ends, Period
+// passes or Stop is closed.  known holds the owners seen by namespace and name, so that the next call finds
+// the owners deleted while no watch was open.  Owners whose objects could not all be deleted stay in known
+// and are tried again by the next call.
+func (c *Collector) WatchInventory(inventory Inventory, known map[string]runtime.Object) {
+	owners, err := c.helper(inventory.Kind)
+	if err != nil {
+		util.HandleError(err)
+		return
+	}
+	list, err := owners.List(kapi.NamespaceAll, labels.Everything())
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to list %s: %v", inventory.Kind, err))
+		return
+	}
+	items, err := runtime.ExtractList(list)
+	if err != nil {
+		util.HandleError(err)
+		return
+	}
+	listed := map[string]runtime.Object{}
+	for _, item := range items {
+		key, err := ownerKey(item)
+		if err != nil {
+			util.HandleError(err)
+			continue
+		}
+		listed[key] = item
+	}
+	for key, owner := range known {
+		if _, ok := listed[key]; !ok && c.deleteInventory(inventory, owner) {
+			delete(known, key)
+		}
+	}
+	for key, owner := range listed {
+		known[key] = owner
+	}
+
+	accessor, err := meta.Accessor(list)
+	if err != nil {
+		util.HandleError(err)
+		return
+	}
+	w, err := owners.Watch(kapi.NamespaceAll, accessor.ResourceVersion(), labels.Everything(), labels.Everything())
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to watch %s: %v", inventory.Kind, err))
+		return
+	}
+	defer w.Stop()
+	resync := time.After(c.Period)
+	for {
+		select {
+		case <-c.Stop:
+			return
+		case <-resync:
+			return
+		case event, ok := <-w.ResultChan():
+			if !ok || event.Type == watch.Error {
+				return
+			}
+			key, err := ownerKey(event.Object)
+			if err != nil {
+				util.HandleError(err)
+				continue
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				known[key] = event.Object
+			case watch.Deleted:
+				known[key] = event.Object
+				if c.deleteInventory(inventory, event.Object) {
+					delete(known, key)
+				}
+			}
+		}
+	}
+}
+
+// deleteInventory deletes the objects of owner, a deleted owner of inventory, and returns true if they are
+// all gone.  References to objects of other kinds than inventory.Kinds, in other namespaces than that of
+// owner, or without a UID are ignored.
+func (c *Collector) deleteInventory(inventory Inventory, owner runtime.Object) bool {
+	accessor, err := meta.Accessor(owner)
+	if err != nil {
+		util.HandleError(err)
+		return true
+	}
+	refs := []kapi.ObjectReference{}
+	for _, ref := range inventory.Dependents(owner) {
+		if !inventory.Kinds.Has(ref.Kind) || ref.Namespace != accessor.Namespace() || len(ref.UID) == 0 {
+			glog.V(2).Infof("Ignoring %s %s/%s of %s %s/%s, which the %s may not own", ref.Kind, ref.Namespace, ref.Name, inventory.Kind, accessor.Namespace(), accessor.Name(), inventory.Kind)
+			continue
+		}
+		refs = append(refs, ref)
+	}
+
+	deleter := c
+	if inventory.User != nil && c.Impersonate != nil {
+		if user := inventory.User(owner); len(user) > 0 {
+			if deleter, err = c.Impersonate(user); err != nil {
+				util.HandleError(fmt.Errorf("unable to delete the objects of %s %s/%s as %s: %v", inventory.Kind, accessor.Namespace(), accessor.Name(), user, err))
+				return false
+			}
+		}
+	}
+	if err := deleter.DeleteDependents(refs); err != nil {
+		util.HandleError(fmt.Errorf("unable to delete the objects of a deleted %s: %v", inventory.Kind, err))
+		return false
+	}
+	return true
+}
+
+// ownerKey returns the namespace and name of owner.
+func ownerKey(owner runtime.Object) (string, error) {
+	accessor, err := meta.Accessor(owner)
+	if err != nil {
+		return "", err
+	}
+	return accessor.Namespace() + "/" + accessor.Name(), nil
+}
+
+// DeleteDependents deletes the objects of refs, which may belong to any API Mapper maps.  Objects that do
+// not exist, or that have another UID than a reference names, are ignored.  Every object is attempted, and the
+// first error is returned.
+func (c *Collector) DeleteDependents(refs []kapi.ObjectReference) error {
+	var firstErr error
+	for _, ref := range refs {
+		helper, err := c.helper(ref.Kind, ref.APIVersion)
+		if err == nil {
+			err = c.deleteDependent(ref, helper)
+		}
+		if err != nil && !kerrors.IsNotFound(err) && firstErr == nil {
+			firstErr = fmt.Errorf("%s %s/%s: %v", ref.Kind, ref.Namespace, ref.Name, err)
+		}
+	}
+	return firstErr
+}
+
+// deleteDependent deletes the object of ref through helper, if it has the UID ref names.
+func (c *Collector) deleteDependent(ref kapi.ObjectReference, helper *resource.Helper) error {
+	if len(ref.UID) > 0 {
+		obj, err := helper.Get(ref.Namespace, ref.Name)
+		if err != nil {
+			return err
+		}
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		if accessor.UID() != ref.UID {
+			glog.V(2).Infof("Ignoring %s %s/%s, it was replaced since its owner recorded it", ref.Kind, ref.Namespace, ref.Name)
+			return nil
+		}
+	}
+	glog.V(2).Infof("Deleting %s %s/%s, its owner no longer exists", ref.Kind, ref.Namespace, ref.Name)
+	return c.delete(ref.Kind, helper, ref.Namespace, ref.Name)
+}
+
+// delete deletes the object of kind through helper, or stops it with the reaper of kind.
+func (c *Collector) delete(kind string, helper *resource.Helper, namespace, name string) error {
+	if reaper, ok := c.Reapers[kind]; ok {
+		_, err := reaper.Stop(namespace, name)
+		return err
+	}
+	return helper.Delete(namespace, name)
+}
+
+// helper returns the helper for the resource of kind in the preferred of versions.
+func (c *Collector) helper(kind string, versions ...string) (*resource.Helper, error) {
+	mapping, err := c.Mapper.RESTMapping(kind, versions...)
+	if err != nil {
+		return nil, err
+	}
+	client, err := c.Clients.ClientForMapping(mapping)
+	if err != nil {
+		return nil, err
+	}
+	return resource.NewHelper(client, mapping), nil
+}
@@ -0,0 +1,234 @@
+package gc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/api/latest"
+	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	routeapi "github.com/openshift/origin/pkg/route/api"
+	templateapi "github.com/openshift/origin/pkg/template/api"
+)
+
+// fakeServer serves objects by path and records the deletions it receives.
+type fakeServer struct {
+	t       *testing.T
+	objects map[string]runtime.Object
+	deleted []string
+}
+
+func (s *fakeServer) Do(req *http.Request) (*http.Response, error) {
+	key := req.URL.Path + "?" + req.URL.Query().Get("namespace")
+	obj, ok := s.objects[key]
+	status := http.StatusOK
+	switch {
+	case !ok:
+		status = http.StatusNotFound
+		obj = &kapi.Status{Status: kapi.StatusFailure, Reason: kapi.StatusReasonNotFound, Code: status}
+	case req.Method == "DELETE":
+		s.deleted = append(s.deleted, key)
+		obj = &kapi.Status{Status: kapi.StatusSuccess}
+	}
+	data, err := latest.Codec.Encode(obj)
+	if err != nil {
+		s.t.Fatalf("Unexpected error: %v", err)
+	}
+	return &http.Response{StatusCode: status, Body: ioutil.NopCloser(bytes.NewReader(data))}, nil
+}
+
+func (s *fakeServer) collector() *Collector {
+	client := &kclient.FakeRESTClient{Codec: latest.Codec, Legacy: true, Client: s}
+	return &Collector{
+		Mapper: latest.RESTMapper,
+		Clients: resource.ClientMapperFunc(func(*meta.RESTMapping) (resource.RESTClient, error) {
+			return client, nil
+		}),
+	}
+}
+
+func annotatedPod(name, build string) kapi.Pod {
+	pod := kapi.Pod{ObjectMeta: kapi.ObjectMeta{Namespace: "test", Name: name}}
+	if len(build) > 0 {
+		pod.Annotations = map[string]string{buildapi.BuildAnnotation: build}
+	}
+	return pod
+}
+
+func TestCollectDeletesDependentsOfMissingOwners(t *testing.T) {
+	server := &fakeServer{t: t, objects: map[string]runtime.Object{
+		"/pods?": &kapi.PodList{Items: []kapi.Pod{
+			annotatedPod("running", "exists"),
+			annotatedPod("orphan", "missing"),
+			annotatedPod("other-orphan", "missing"),
+			annotatedPod("unowned", ""),
+		}},
+		"/builds/exists?test":     &buildapi.Build{ObjectMeta: kapi.ObjectMeta{Namespace: "test", Name: "exists"}},
+		"/pods/running?test":      &kapi.Pod{},
+		"/pods/orphan?test":       &kapi.Pod{},
+		"/pods/other-orphan?test": &kapi.Pod{},
+		"/pods/unowned?test":      &kapi.Pod{},
+	}}
+	collector := server.collector()
+	collector.Relations = []Relation{AnnotationRelation("Pod", "Build", buildapi.BuildAnnotation)}
+	collector.Collect()
+
+	sort.Strings(server.deleted)
+	expected := []string{"/pods/orphan?test", "/pods/other-orphan?test"}
+	if !reflect.DeepEqual(expected, server.deleted) {
+		t.Errorf("Expected the pods of the missing build to be deleted, got %v", server.deleted)
+	}
+}
+
+func TestDeleteDependentsAcrossAPIs(t *testing.T) {
+	server := &fakeServer{t: t, objects: map[string]runtime.Object{
+		"/routes/frontend?test":   &routeapi.Route{ObjectMeta: kapi.ObjectMeta{UID: "1"}},
+		"/services/frontend?test": &kapi.Service{ObjectMeta: kapi.ObjectMeta{UID: "2"}},
+		"/services/replaced?test": &kapi.Service{ObjectMeta: kapi.ObjectMeta{UID: "4"}},
+	}}
+	instance := &templateapi.TemplateInstance{Objects: []kapi.ObjectReference{
+		{Kind: "Route", Namespace: "test", Name: "frontend", UID: "1"},
+		{Kind: "Service", Namespace: "test", Name: "frontend", UID: "2"},
+		{Kind: "DeploymentConfig", Namespace: "test", Name: "already-deleted", UID: "3"},
+		{Kind: "Service", Namespace: "test", Name: "replaced", UID: "3"},
+	}}
+	refs := DefaultInventories()[0].Dependents(instance)
+	if err := server.collector().DeleteDependents(refs); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"/routes/frontend?test", "/services/frontend?test"}
+	if !reflect.DeepEqual(expected, server.deleted) {
+		t.Errorf("Expected the objects of the instance to be deleted, got %v", server.deleted)
+	}
+}
+
+func TestDeleteInventoryOnlyDeletesOwnedObjects(t *testing.T) {
+	server := &fakeServer{t: t, objects: map[string]runtime.Object{
+		"/routes/frontend?test":       &routeapi.Route{ObjectMeta: kapi.ObjectMeta{UID: "1"}},
+		"/routes/frontend?other":      &routeapi.Route{ObjectMeta: kapi.ObjectMeta{UID: "2"}},
+		"/policyBindings/master?test": &authorizationapi.PolicyBinding{ObjectMeta: kapi.ObjectMeta{UID: "3"}},
+		"/services/database?test":     &kapi.Service{ObjectMeta: kapi.ObjectMeta{UID: "4"}},
+	}}
+	instance := &templateapi.TemplateInstance{
+		ObjectMeta: kapi.ObjectMeta{Namespace: "test", Name: "app-1"},
+		Objects: []kapi.ObjectReference{
+			{Kind: "Route", Namespace: "test", Name: "frontend", UID: "1"},
+			{Kind: "Route", Namespace: "other", Name: "frontend", UID: "2"},
+			{Kind: "PolicyBinding", Namespace: "test", Name: "master", UID: "3"},
+			{Kind: "Service", Namespace: "test", Name: "database"},
+		},
+	}
+	if !server.collector().deleteInventory(DefaultInventories()[0], instance) {
+		t.Fatalf("Expected the objects of the instance to be deleted")
+	}
+	if !reflect.DeepEqual([]string{"/routes/frontend?test"}, server.deleted) {
+		t.Errorf("Expected only the route in the namespace of the instance to be deleted, got %v", server.deleted)
+	}
+}
+
+func TestDeleteInventoryImpersonatesUser(t *testing.T) {
+	server := &fakeServer{t: t, objects: map[string]runtime.Object{}}
+	userServer := &fakeServer{t: t, objects: map[string]runtime.Object{
+		"/routes/frontend?test": &routeapi.Route{ObjectMeta: kapi.ObjectMeta{UID: "1"}},
+	}}
+	impersonated := ""
+	collector := server.collector()
+	collector.Impersonate = func(user string) (*Collector, error) {
+		impersonated = user
+		return userServer.collector(), nil
+	}
+	instance := &templateapi.TemplateInstance{
+		ObjectMeta: kapi.ObjectMeta{
+			Namespace:   "test",
+			Name:        "app-1",
+			Annotations: map[string]string{templateapi.TemplateInstanceRequesterAnnotation: "bob"},
+		},
+		Objects: []kapi.ObjectReference{{Kind: "Route", Namespace: "test", Name: "frontend", UID: "1"}},
+	}
+	if !collector.deleteInventory(DefaultInventories()[0], instance) {
+		t.Fatalf("Expected the objects of the instance to be deleted")
+	}
+	if impersonated != "bob" {
+		t.Errorf("Expected the objects to be deleted as bob, got %q", impersonated)
+	}
+	if !reflect.DeepEqual([]string{"/routes/frontend?test"}, userServer.deleted) || len(server.deleted) != 0 {
+		t.Errorf("Expected the route to be deleted as the user, got %v and %v", userServer.deleted, server.deleted)
+	}
+}
+
+func TestDeleteDependentsOfUnknownKinds(t *testing.T) {
+	server := &fakeServer{t: t, objects: map[string]runtime.Object{}}
+	err := server.collector().DeleteDependents([]kapi.ObjectReference{{Kind: "Unknown", Namespace: "test", Name: "a"}})
+	if err == nil {
+		t.Errorf("Expected an error for an unknown kind")
+	}
+}
+
+// fakeReaper records the objects it stops.
+type fakeReaper struct {
+	stopped []string
+}
+
+func (r *fakeReaper) Stop(namespace, name string) (string, error) {
+	r.stopped = append(r.stopped, namespace+"/"+name)
+	return "", nil
+}
+
+func TestCollectStopsReapedKinds(t *testing.T) {
+	rc := kapi.ReplicationController{ObjectMeta: kapi.ObjectMeta{
+		Namespace:   "test",
+		Name:        "frontend-1",
+		Annotations: map[string]string{deployapi.DeploymentConfigAnnotation: "frontend"},
+	}}
+	server := &fakeServer{t: t, objects: map[string]runtime.Object{
+		"/replicationControllers?": &kapi.ReplicationControllerList{Items: []kapi.ReplicationController{rc}},
+	}}
+	reaper := &fakeReaper{}
+	collector := server.collector()
+	collector.Relations = []Relation{AnnotationRelation("ReplicationController", "DeploymentConfig", deployapi.DeploymentConfigAnnotation)}
+	collector.Reapers = map[string]kubectl.Reaper{"ReplicationController": reaper}
+	collector.Collect()
+
+	if !reflect.DeepEqual([]string{"test/frontend-1"}, reaper.stopped) {
+		t.Errorf("Expected the replication controller to be stopped, got %v", reaper.stopped)
+	}
+	if len(server.deleted) != 0 {
+		t.Errorf("Expected the replication controller not to be deleted directly, got %v", server.deleted)
+	}
+}
+
+func TestWatchInventoryFindsOwnersDeletedWhileNotWatched(t *testing.T) {
+	kept := templateapi.TemplateInstance{
+		ObjectMeta: kapi.ObjectMeta{Namespace: "test", Name: "kept"},
+		Objects:    []kapi.ObjectReference{{Kind: "Route", Namespace: "test", Name: "kept"}},
+	}
+	gone := &templateapi.TemplateInstance{
+		ObjectMeta: kapi.ObjectMeta{Namespace: "test", Name: "gone"},
+		Objects:    []kapi.ObjectReference{{Kind: "Route", Namespace: "test", Name: "frontend", UID: "1"}},
+	}
+	server := &fakeServer{t: t, objects: map[string]runtime.Object{
+		"/templateInstances?":   &templateapi.TemplateInstanceList{Items: []templateapi.TemplateInstance{kept}},
+		"/routes/frontend?test": &routeapi.Route{ObjectMeta: kapi.ObjectMeta{UID: "1"}},
+	}}
+	known := map[string]runtime.Object{"test/gone": gone}
+	server.collector().WatchInventory(DefaultInventories()[0], known)
+
+	if !reflect.DeepEqual([]string{"/routes/frontend?test"}, server.deleted) {
+		t.Errorf("Expected the objects of the deleted instance to be deleted, got %v", server.deleted)
+	}
+	if _, ok := known["test/kept"]; !ok || len(known) != 1 {
+		t.Errorf("Expected only the listed instance to be known, got %v", known)
+	}
+}
@@ -0,0 +1,3 @@
+// Package gc deletes the OpenShift and Kubernetes objects whose owners have been deleted, such as the pods
+// of builds and the deployments of deployment configs.
+package gc
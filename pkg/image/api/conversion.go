@@ -12,10 +12,11 @@ func init() {
 	err := kapi.Scheme.AddConversionFuncs(
 		// Convert docker client object to internal object
 		func(in *docker.Image, out *DockerImage, s conversion.Scope) error {
-			if err := s.Convert(in.Config, &out.Config, conversion.AllowDifferentFieldTypeNames); err != nil {
+			// the docker client does not read image labels yet, so they are left unset
+			if err := s.Convert(in.Config, &out.Config, conversion.AllowDifferentFieldTypeNames|conversion.IgnoreMissingFields); err != nil {
 				return err
 			}
-			if err := s.Convert(&in.ContainerConfig, &out.ContainerConfig, conversion.AllowDifferentFieldTypeNames); err != nil {
+			if err := s.Convert(&in.ContainerConfig, &out.ContainerConfig, conversion.AllowDifferentFieldTypeNames|conversion.IgnoreMissingFields); err != nil {
 				return err
 			}
 			out.ID = in.ID
@@ -49,4 +49,5 @@ type DockerConfig struct {
 	WorkingDir      string              `json:"WorkingDir,omitempty"`
 	Entrypoint      []string            `json:"Entrypoint,omitempty"`
 	NetworkDisabled bool                `json:"NetworkDisabled,omitempty"`
+	Labels          map[string]string   `json:"Labels,omitempty"`
 }
@@ -14,10 +14,10 @@ func init() {
 	err := kapi.Scheme.AddConversionFuncs(
 		// Convert docker client object to internal object, but only when this package is included
 		func(in *docker.ImagePre012, out *newer.DockerImage, s conversion.Scope) error {
-			if err := s.Convert(in.Config, &out.Config, conversion.AllowDifferentFieldTypeNames); err != nil {
+			if err := s.Convert(in.Config, &out.Config, conversion.AllowDifferentFieldTypeNames|conversion.IgnoreMissingFields); err != nil {
 				return err
 			}
-			if err := s.Convert(&in.ContainerConfig, &out.ContainerConfig, conversion.AllowDifferentFieldTypeNames); err != nil {
+			if err := s.Convert(&in.ContainerConfig, &out.ContainerConfig, conversion.AllowDifferentFieldTypeNames|conversion.IgnoreMissingFields); err != nil {
 				return err
 			}
 			out.ID = in.ID
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/api/selectable"
+)
+
+func init() {
+	selectable.Register(&Image{}, func(obj runtime.Object) labels.Set { return ImageToSelectableFields(obj.(*Image)) })
+	selectable.Register(&ImageRepository{}, func(obj runtime.Object) labels.Set { return ImageRepositoryToSelectableFields(obj.(*ImageRepository)) })
+}
+
+// ImageToSelectableFields returns the fields of an image that clients can select on.
+func ImageToSelectableFields(image *Image) labels.Set {
+	return labels.Set{
+		"name":                 image.Name,
+		"dockerImageReference": image.DockerImageReference,
+	}
+}
+
+// ImageRepositoryToSelectableFields returns the fields of an image repository that clients can select on.
+func ImageRepositoryToSelectableFields(repo *ImageRepository) labels.Set {
+	return labels.Set{
+		"name":                  repo.Name,
+		"dockerImageRepository": repo.DockerImageRepository,
+	}
+}
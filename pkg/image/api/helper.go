@@ -89,3 +89,35 @@ func parseImageName(image string) (string, string) {
 	}
 	return image, tag
 }
+
+// MaxTagEvents is the number of images recorded in the status of a repository for each tag.
+const MaxTagEvents = 10
+
+// AddTagEvent records that tag of repo was set to the image described by event, unless the tag already
+// points to that image. Only the MaxTagEvents most recent events of the tag are kept. Returns true if
+// the status of repo was changed.
+func AddTagEvent(repo *ImageRepository, tag string, event TagEvent) bool {
+	if repo.Status.Tags == nil {
+		repo.Status.Tags = make(map[string]TagEventList)
+	}
+	events := repo.Status.Tags[tag].Items
+	if len(events) > 0 && events[0].Image == event.Image && events[0].DockerImageReference == event.DockerImageReference {
+		return false
+	}
+	events = append([]TagEvent{event}, events...)
+	if len(events) > MaxTagEvents {
+		events = events[:MaxTagEvents]
+	}
+	repo.Status.Tags[tag] = TagEventList{Items: events}
+	return true
+}
+
+// LatestTaggedImage returns the most recent TagEvent of tag in the status of repo, or nil if the tag has
+// never been set.
+func LatestTaggedImage(repo *ImageRepository, tag string) *TagEvent {
+	events := repo.Status.Tags[tag].Items
+	if len(events) == 0 {
+		return nil
+	}
+	return &events[0]
+}
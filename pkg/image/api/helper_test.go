@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -64,3 +65,30 @@ func TestSplitDockerPullSpec(t *testing.T) {
 		}
 	}
 }
+
+func TestAddTagEvent(t *testing.T) {
+	repo := &ImageRepository{}
+	if !AddTagEvent(repo, "latest", TagEvent{Image: "image0"}) {
+		t.Errorf("Expected the first event to be added")
+	}
+	if AddTagEvent(repo, "latest", TagEvent{Image: "image0"}) {
+		t.Errorf("Expected an event for the current image to be ignored")
+	}
+	for i := 1; i <= MaxTagEvents; i++ {
+		AddTagEvent(repo, "latest", TagEvent{Image: fmt.Sprintf("image%d", i)})
+	}
+
+	events := repo.Status.Tags["latest"].Items
+	if len(events) != MaxTagEvents {
+		t.Fatalf("Expected %d events, got %d", MaxTagEvents, len(events))
+	}
+	if e, a := fmt.Sprintf("image%d", MaxTagEvents), LatestTaggedImage(repo, "latest").Image; e != a {
+		t.Errorf("Expected latest image %s, got %s", e, a)
+	}
+	if e, a := "image1", events[len(events)-1].Image; e != a {
+		t.Errorf("Expected oldest image %s, got %s", e, a)
+	}
+	if LatestTaggedImage(repo, "other") != nil {
+		t.Errorf("Expected no image for an unset tag")
+	}
+}
@@ -11,6 +11,7 @@ func init() {
 		&ImageRepository{},
 		&ImageRepositoryList{},
 		&ImageRepositoryMapping{},
+		&ImageRepositoryRestore{},
 		&DockerImage{},
 	)
 }
@@ -20,4 +21,5 @@ func (*ImageList) IsAnAPIObject()              {}
 func (*ImageRepository) IsAnAPIObject()        {}
 func (*ImageRepositoryList) IsAnAPIObject()    {}
 func (*ImageRepositoryMapping) IsAnAPIObject() {}
+func (*ImageRepositoryRestore) IsAnAPIObject() {}
 func (*DockerImage) IsAnAPIObject()            {}
@@ -2,12 +2,16 @@ package api
 
 import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
 // ImageList is a list of Image objects.
 type ImageList struct {
 	kapi.TypeMeta `json:",inline"`
 	kapi.ListMeta `json:"metadata,omitempty"`
+	// Continue is set when the list is a page of a larger list, and continues it from the next page when
+	// passed as the continue query parameter.
+	Continue string `json:"continue,omitempty"`
 
 	Items []Image `json:"items"`
 }
@@ -54,6 +58,24 @@ type ImageRepositoryStatus struct {
 	// Represents the effective location this repository may be accessed at. May be empty until the server
 	// determines where the repository is located
 	DockerImageRepository string `json:"dockerImageRepository,omitempty"`
+	// A historical record of images associated with each tag, most recent first. The first entry of each
+	// tag is the image the tag currently points to.
+	Tags map[string]TagEventList `json:"tags,omitempty"`
+}
+
+// TagEventList contains the recent images a tag has pointed to, most recent first.
+type TagEventList struct {
+	Items []TagEvent `json:"items"`
+}
+
+// TagEvent records when a tag was set to an image.
+type TagEvent struct {
+	// When the tag was set to the image
+	Created util.Time `json:"created"`
+	// The string that can be used to pull the image
+	DockerImageReference string `json:"dockerImageReference,omitempty"`
+	// The name of the image the tag was set to
+	Image string `json:"image,omitempty"`
 }
 
 // TODO add metadata overrides
@@ -71,3 +93,14 @@ type ImageRepositoryMapping struct {
 	// A string value this image can be located with inside the repository.
 	Tag string `json:"tag"`
 }
+
+// ImageRepositoryDeletedAnnotation is set on the copy of a deleted ImageRepository, which is kept for
+// the deletion grace period of the server, to the time the repository was deleted in RFC3339 format.
+const ImageRepositoryDeletedAnnotation = "openshift.io/deleted"
+
+// ImageRepositoryRestore requests that the ImageRepository of the same name, deleted within the
+// deletion grace period of the server, be restored.
+type ImageRepositoryRestore struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+}
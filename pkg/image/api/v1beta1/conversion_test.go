@@ -17,7 +17,8 @@ var Convert = kapi.Scheme.Convert
 func TestRoundTripVersionedObject(t *testing.T) {
 	d := &newer.DockerImage{
 		Config: newer.DockerConfig{
-			Env: []string{"A=1", "B=2"},
+			Env:    []string{"A=1", "B=2"},
+			Labels: map[string]string{"builder": "sti"},
 		},
 	}
 	i := &newer.Image{
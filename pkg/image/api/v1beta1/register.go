@@ -14,6 +14,7 @@ func init() {
 		&ImageRepository{},
 		&ImageRepositoryList{},
 		&ImageRepositoryMapping{},
+		&ImageRepositoryRestore{},
 	)
 }
 
@@ -22,3 +23,4 @@ func (*ImageList) IsAnAPIObject()              {}
 func (*ImageRepository) IsAnAPIObject()        {}
 func (*ImageRepositoryList) IsAnAPIObject()    {}
 func (*ImageRepositoryMapping) IsAnAPIObject() {}
+func (*ImageRepositoryRestore) IsAnAPIObject() {}
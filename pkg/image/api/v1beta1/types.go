@@ -3,12 +3,16 @@ package v1beta1
 import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api/v1beta3"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
 // ImageList is a list of Image objects.
 type ImageList struct {
 	kapi.TypeMeta `json:",inline"`
 	kapi.ListMeta `json:"metadata,omitempty"`
+	// Continue is set when the list is a page of a larger list, and continues it from the next page when
+	// passed as the continue query parameter.
+	Continue string `json:"continue,omitempty"`
 
 	Items []Image `json:"items"`
 }
@@ -19,11 +23,11 @@ type Image struct {
 	kapi.ObjectMeta `json:"metadata,omitempty"`
 
 	// The string that can be used to pull this image.
-	DockerImageReference string `json:"dockerImageReference,omitempty"`
+	DockerImageReference string `json:"dockerImageReference,omitempty" description:"The string that can be used to pull this image."`
 	// Metadata about this image
-	DockerImageMetadata runtime.RawExtension `json:"dockerImageMetadata,omitempty"`
+	DockerImageMetadata runtime.RawExtension `json:"dockerImageMetadata,omitempty" description:"Metadata about this image"`
 	// This attribute conveys the version of the object, which if empty defaults to "1.0"
-	DockerImageMetadataVersion string `json:"dockerImageMetadataVersion,omitempty"`
+	DockerImageMetadataVersion string `json:"dockerImageMetadataVersion,omitempty" description:"This attribute conveys the version of the object, which if empty defaults to '1.0'"`
 }
 
 // ImageRepositoryList is a list of ImageRepository objects.
@@ -42,19 +46,37 @@ type ImageRepository struct {
 	kapi.ObjectMeta `json:"metadata,omitempty"`
 
 	// Optional, if specified this repository is backed by a Docker repository on this server
-	DockerImageRepository string `json:"dockerImageRepository,omitempty"`
+	DockerImageRepository string `json:"dockerImageRepository,omitempty" description:"Optional, if specified this repository is backed by a Docker repository on this server"`
 	// Tags map arbitrary string values to specific image locators
-	Tags map[string]string `json:"tags,omitempty"`
+	Tags map[string]string `json:"tags,omitempty" description:"Tags map arbitrary string values to specific image locators"`
 
 	// Status describes the current state of this repository
-	Status ImageRepositoryStatus `json:"status,omitempty"`
+	Status ImageRepositoryStatus `json:"status,omitempty" description:"Status describes the current state of this repository"`
 }
 
 // ImageRepositoryStatus contains information about the state of this image repository.
 type ImageRepositoryStatus struct {
 	// Represents the effective location this repository may be accessed at. May be empty until the server
 	// determines where the repository is located
-	DockerImageRepository string `json:"dockerImageRepository"`
+	DockerImageRepository string `json:"dockerImageRepository" description:"Represents the effective location this repository may be accessed at. May be empty until the server determines where the repository is located"`
+	// A historical record of images associated with each tag, most recent first. The first entry of each
+	// tag is the image the tag currently points to.
+	Tags map[string]TagEventList `json:"tags,omitempty" description:"A historical record of images associated with each tag, most recent first. The first entry of each tag is the image the tag currently points to"`
+}
+
+// TagEventList contains the recent images a tag has pointed to, most recent first.
+type TagEventList struct {
+	Items []TagEvent `json:"items" description:"The recent images the tag has pointed to, most recent first"`
+}
+
+// TagEvent records when a tag was set to an image.
+type TagEvent struct {
+	// When the tag was set to the image
+	Created util.Time `json:"created" description:"When the tag was set to the image"`
+	// The string that can be used to pull the image
+	DockerImageReference string `json:"dockerImageReference,omitempty" description:"The string that can be used to pull the image"`
+	// The name of the image the tag was set to
+	Image string `json:"image,omitempty" description:"The name of the image the tag was set to"`
 }
 
 // TODO add metadata overrides
@@ -66,9 +88,16 @@ type ImageRepositoryMapping struct {
 	kapi.ObjectMeta `json:"metadata,omitempty"`
 
 	// The Docker image repository the specified image is located in
-	DockerImageRepository string `json:"dockerImageRepository"`
+	DockerImageRepository string `json:"dockerImageRepository" description:"The Docker image repository the specified image is located in"`
 	// A Docker image.
-	Image Image `json:"image"`
+	Image Image `json:"image" description:"A Docker image."`
 	// A string value this image can be located with inside the repository.
-	Tag string `json:"tag"`
+	Tag string `json:"tag" description:"A string value this image can be located with inside the repository."`
+}
+
+// ImageRepositoryRestore requests that the ImageRepository of the same name, deleted within the
+// deletion grace period of the server, be restored.
+type ImageRepositoryRestore struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
 }
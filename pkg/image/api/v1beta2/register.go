@@ -0,0 +1,26 @@
+package v1beta2
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	_ "github.com/openshift/origin/pkg/image/api/docker10"
+	_ "github.com/openshift/origin/pkg/image/api/dockerpre012"
+)
+
+func init() {
+	api.Scheme.AddKnownTypes("v1beta2",
+		&Image{},
+		&ImageList{},
+		&ImageRepository{},
+		&ImageRepositoryList{},
+		&ImageRepositoryMapping{},
+		&ImageRepositoryRestore{},
+	)
+}
+
+func (*Image) IsAnAPIObject()                  {}
+func (*ImageList) IsAnAPIObject()              {}
+func (*ImageRepository) IsAnAPIObject()        {}
+func (*ImageRepositoryList) IsAnAPIObject()    {}
+func (*ImageRepositoryMapping) IsAnAPIObject() {}
+func (*ImageRepositoryRestore) IsAnAPIObject() {}
@@ -0,0 +1,103 @@
+package v1beta2
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api/v1beta3"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// ImageList is a list of Image objects.
+type ImageList struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
+	// Continue is set when the list is a page of a larger list, and continues it from the next page when
+	// passed as the continue query parameter.
+	Continue string `json:"continue,omitempty"`
+
+	Items []Image `json:"items"`
+}
+
+// Image is an immutable representation of a Docker image and metadata at a point in time.
+type Image struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	// The string that can be used to pull this image.
+	DockerImageReference string `json:"dockerImageReference,omitempty" description:"The string that can be used to pull this image."`
+	// Metadata about this image
+	DockerImageMetadata runtime.RawExtension `json:"dockerImageMetadata,omitempty" description:"Metadata about this image"`
+	// This attribute conveys the version of the object, which if empty defaults to "1.0"
+	DockerImageMetadataVersion string `json:"dockerImageMetadataVersion,omitempty" description:"This attribute conveys the version of the object, which if empty defaults to '1.0'"`
+}
+
+// ImageRepositoryList is a list of ImageRepository objects.
+type ImageRepositoryList struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
+
+	Items []ImageRepository `json:"items"`
+}
+
+// ImageRepository stores a mapping of tags to images, metadata overrides that are applied
+// when images are tagged in a repository, and an optional reference to a Docker image
+// repository on a registry.
+type ImageRepository struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	// Optional, if specified this repository is backed by a Docker repository on this server
+	DockerImageRepository string `json:"dockerImageRepository,omitempty" description:"Optional, if specified this repository is backed by a Docker repository on this server"`
+	// Tags map arbitrary string values to specific image locators
+	Tags map[string]string `json:"tags,omitempty" description:"Tags map arbitrary string values to specific image locators"`
+
+	// Status describes the current state of this repository
+	Status ImageRepositoryStatus `json:"status,omitempty" description:"Status describes the current state of this repository"`
+}
+
+// ImageRepositoryStatus contains information about the state of this image repository.
+type ImageRepositoryStatus struct {
+	// Represents the effective location this repository may be accessed at. May be empty until the server
+	// determines where the repository is located
+	DockerImageRepository string `json:"dockerImageRepository" description:"Represents the effective location this repository may be accessed at. May be empty until the server determines where the repository is located"`
+	// A historical record of images associated with each tag, most recent first. The first entry of each
+	// tag is the image the tag currently points to.
+	Tags map[string]TagEventList `json:"tags,omitempty" description:"A historical record of images associated with each tag, most recent first. The first entry of each tag is the image the tag currently points to"`
+}
+
+// TagEventList contains the recent images a tag has pointed to, most recent first.
+type TagEventList struct {
+	Items []TagEvent `json:"items" description:"The recent images the tag has pointed to, most recent first"`
+}
+
+// TagEvent records when a tag was set to an image.
+type TagEvent struct {
+	// When the tag was set to the image
+	Created util.Time `json:"created" description:"When the tag was set to the image"`
+	// The string that can be used to pull the image
+	DockerImageReference string `json:"dockerImageReference,omitempty" description:"The string that can be used to pull the image"`
+	// The name of the image the tag was set to
+	Image string `json:"image,omitempty" description:"The name of the image the tag was set to"`
+}
+
+// TODO add metadata overrides
+
+// ImageRepositoryMapping represents a mapping from a single tag to a Docker image as
+// well as the reference to the Docker image repository the image came from.
+type ImageRepositoryMapping struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	// The Docker image repository the specified image is located in
+	DockerImageRepository string `json:"dockerImageRepository" description:"The Docker image repository the specified image is located in"`
+	// A Docker image.
+	Image Image `json:"image" description:"A Docker image."`
+	// A string value this image can be located with inside the repository.
+	Tag string `json:"tag" description:"A string value this image can be located with inside the repository."`
+}
+
+// ImageRepositoryRestore requests that the ImageRepository of the same name, deleted within the
+// deletion grace period of the server, be restored.
+type ImageRepositoryRestore struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+}
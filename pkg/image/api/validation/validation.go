@@ -2,6 +2,7 @@ package validation
 
 import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/validation"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
 	"github.com/openshift/origin/pkg/image/api"
@@ -38,17 +39,30 @@ func ValidateImageRepository(repo *api.ImageRepository) errors.ValidationErrorLi
 	}
 	if len(repo.Name) == 0 {
 		result = append(result, errors.NewFieldRequired("name", repo.Name))
+	} else if !util.IsDNSSubdomain(repo.Name) {
+		result = append(result, errors.NewFieldInvalid("name", repo.Name, "name must be a valid subdomain"))
 	}
 	if !util.IsDNSSubdomain(repo.Namespace) {
 		result = append(result, errors.NewFieldInvalid("namespace", repo.Namespace, ""))
 	}
+	result = append(result, validation.ValidateLabels(repo.Labels, "labels")...)
 	if len(repo.DockerImageRepository) != 0 {
 		_, _, _, _, err := api.SplitDockerPullSpec(repo.DockerImageRepository)
 		if err != nil {
 			result = append(result, errors.NewFieldInvalid("dockerImageRepository", repo.DockerImageRepository, err.Error()))
 		}
 	}
+	if _, ok := repo.Tags[""]; ok {
+		result = append(result, errors.NewFieldInvalid("tags", "", "tag names must not be empty"))
+	}
+
+	return result
+}
 
+// ValidateImageRepositoryUpdate tests that repo is valid and does not change the immutable fields of oldRepo.
+func ValidateImageRepositoryUpdate(repo, oldRepo *api.ImageRepository) errors.ValidationErrorList {
+	result := ValidateImageRepository(repo)
+	result = append(result, validation.ValidateObjectMetaUpdate(&oldRepo.ObjectMeta, &repo.ObjectMeta)...)
 	return result
 }
 
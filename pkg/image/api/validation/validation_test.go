@@ -167,3 +167,49 @@ func TestValidateImageRepositoryMappingNotOK(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateImageRepository(t *testing.T) {
+	errorCases := map[string]struct {
+		R api.ImageRepository
+		F string
+	}{
+		"invalid name": {
+			api.ImageRepository{ObjectMeta: kapi.ObjectMeta{Name: "-foo", Namespace: "default"}},
+			"name",
+		},
+		"invalid label": {
+			api.ImageRepository{ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: "default", Labels: map[string]string{"-bar": "baz"}}},
+			"labels",
+		},
+		"empty tag name": {
+			api.ImageRepository{ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: "default"}, Tags: map[string]string{"": "abc"}},
+			"tags",
+		},
+	}
+
+	for k, v := range errorCases {
+		errs := ValidateImageRepository(&v.R)
+		if len(errs) != 1 {
+			t.Errorf("%s: expected one error, got %v", k, errs)
+			continue
+		}
+		if field := errs[0].(*errors.ValidationError).Field; field != v.F {
+			t.Errorf("%s: expected error for field %s, got %s", k, v.F, field)
+		}
+	}
+}
+
+func TestValidateImageRepositoryUpdate(t *testing.T) {
+	oldRepo := &api.ImageRepository{ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: "default"}}
+
+	repo := &api.ImageRepository{ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: "default"}, Tags: map[string]string{"latest": "abc"}}
+	if errs := ValidateImageRepositoryUpdate(repo, oldRepo); len(errs) != 0 {
+		t.Errorf("Unexpected non-empty error list: %#v", errs)
+	}
+
+	repo = &api.ImageRepository{ObjectMeta: kapi.ObjectMeta{Name: "bar", Namespace: "default"}}
+	errs := ValidateImageRepositoryUpdate(repo, oldRepo)
+	if len(errs) != 1 || errs[0].(*errors.ValidationError).Field != "name" {
+		t.Errorf("Expected an error for the name, got %#v", errs)
+	}
+}
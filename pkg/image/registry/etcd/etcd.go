@@ -2,8 +2,8 @@ package etcd
 
 import (
 	"errors"
-	"fmt"
 	"reflect"
+	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	apierrs "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
@@ -16,7 +16,9 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 	"github.com/golang/glog"
 
+	"github.com/openshift/origin/pkg/api/selectable"
 	"github.com/openshift/origin/pkg/image/api"
+	etcdutil "github.com/openshift/origin/pkg/util/etcd"
 )
 
 const (
@@ -24,6 +26,9 @@ const (
 	ImagePath string = "/images"
 	// ImageRepositoriesPath is the path to imageRepository resources in etcd
 	ImageRepositoriesPath string = "/imageRepositories"
+	// DeletedImageRepositoriesPath is the path deleted imageRepository resources are kept at in etcd
+	// until their deletion grace period expires
+	DeletedImageRepositoriesPath string = "/deletedImageRepositories"
 )
 
 // DefaultRegistry returns the default Docker registry (host or host:port), or false if it is not available.
@@ -42,16 +47,19 @@ func (fn DefaultRegistryFunc) DefaultRegistry() (string, bool) {
 // Etcd implements ImageRegistry and ImageRepositoryRegistry backed by etcd.
 type Etcd struct {
 	tools.EtcdHelper
-	defaultRegistry DefaultRegistry
+	defaultRegistry     DefaultRegistry
+	deletionGracePeriod time.Duration
 }
 
 // New returns a new etcd registry. Default registry is the value that will be
 // applied to the Status.DockerImageRepository field if the repository does not
-// have a specified DockerImageRepository.
-func New(helper tools.EtcdHelper, defaultRegistry DefaultRegistry) *Etcd {
+// have a specified DockerImageRepository. Deleted repositories can be restored
+// for deletionGracePeriod, or not at all if it is zero.
+func New(helper tools.EtcdHelper, defaultRegistry DefaultRegistry, deletionGracePeriod time.Duration) *Etcd {
 	return &Etcd{
-		EtcdHelper:      helper,
-		defaultRegistry: defaultRegistry,
+		EtcdHelper:          helper,
+		defaultRegistry:     defaultRegistry,
+		deletionGracePeriod: deletionGracePeriod,
 	}
 }
 
@@ -72,6 +80,21 @@ func (r *Etcd) ListImages(ctx kapi.Context, selector labels.Selector) (*api.Imag
 	return &list, nil
 }
 
+// ListImagesPage retrieves a page of up to limit images that match label and field, which continues the list
+// continueToken was returned with.
+func (r *Etcd) ListImagesPage(ctx kapi.Context, label, field labels.Selector, limit int, continueToken string) (*api.ImageList, error) {
+	list := api.ImageList{}
+	matches := func(obj runtime.Object) bool {
+		return selectable.Matches(obj, label, field)
+	}
+	next, err := etcdutil.ExtractPageToList(r.EtcdHelper, makeImageListKey(ctx), &list, matches, limit, continueToken)
+	if err != nil {
+		return nil, err
+	}
+	list.Continue = next
+	return &list, nil
+}
+
 func makeImageListKey(ctx kapi.Context) string {
 	return kubeetcd.MakeEtcdListKey(ctx, ImagePath)
 }
@@ -153,17 +176,13 @@ func (r *Etcd) WatchImages(ctx kapi.Context, label, field labels.Selector, resou
 	if err != nil {
 		return nil, err
 	}
-	if !field.Empty() {
-		return nil, fmt.Errorf("field selectors are not supported on images")
-	}
-
 	return r.WatchList(makeImageListKey(ctx), version, func(obj runtime.Object) bool {
 		image, ok := obj.(*api.Image)
 		if !ok {
 			glog.Errorf("Unexpected object during image watch: %#v", obj)
 			return false
 		}
-		return label.Matches(labels.Set(image.Labels))
+		return label.Matches(labels.Set(image.Labels)) && field.Matches(api.ImageToSelectableFields(image))
 	})
 }
 
@@ -193,6 +212,10 @@ func makeImageRepositoryKey(ctx kapi.Context, id string) (string, error) {
 	return kubeetcd.MakeEtcdItemKey(ctx, ImageRepositoriesPath, id)
 }
 
+func makeDeletedImageRepositoryKey(ctx kapi.Context, id string) (string, error) {
+	return kubeetcd.MakeEtcdItemKey(ctx, DeletedImageRepositoriesPath, id)
+}
+
 // GetImageRepository retrieves an ImageRepository by id.
 func (r *Etcd) GetImageRepository(ctx kapi.Context, id string) (*api.ImageRepository, error) {
 	var repo api.ImageRepository
@@ -219,11 +242,7 @@ func (r *Etcd) WatchImageRepositories(ctx kapi.Context, label, field labels.Sele
 			glog.Errorf("Unexpected object during image repository watch: %#v", obj)
 			return false
 		}
-		fields := labels.Set{
-			"name":                  repo.Name,
-			"dockerImageRepository": repo.DockerImageRepository,
-		}
-		if !label.Matches(labels.Set(repo.Labels)) || !field.Matches(fields) {
+		if !label.Matches(labels.Set(repo.Labels)) || !field.Matches(api.ImageRepositoryToSelectableFields(repo)) {
 			return false
 		}
 		r.fillRepository(repo)
@@ -247,20 +266,115 @@ func (r *Etcd) UpdateImageRepository(ctx kapi.Context, repo *api.ImageRepository
 	if err != nil {
 		return err
 	}
-	err = r.SetObj(key, repo)
-	return etcderr.InterpretUpdateError(err, "imageRepository", repo.Name)
+	return etcdutil.UpdateObj(r.EtcdHelper, key, repo, "imageRepository", repo.Name)
 }
 
-// DeleteImageRepository deletes an ImageRepository by id.
+// DeleteImageRepository deletes an ImageRepository by id. If the registry has a deletion grace period,
+// a copy of the repository is kept until the period expires so it can be restored.
 func (r *Etcd) DeleteImageRepository(ctx kapi.Context, id string) error {
 	key, err := makeImageRepositoryKey(ctx, id)
 	if err != nil {
 		return err
 	}
+	if r.deletionGracePeriod > 0 {
+		if err := r.keepDeletedImageRepository(ctx, key, id); err != nil {
+			return err
+		}
+	}
 	err = r.Delete(key, false)
 	return etcderr.InterpretDeleteError(err, "imageRepository", id)
 }
 
+// keepDeletedImageRepository copies the repository stored at key to the deleted repositories, replacing
+// an earlier deleted repository of the same name, to expire after the deletion grace period.  The copy
+// records when the repository was deleted, so that it expires even if etcd does not remove it, such as
+// after it was migrated.
+func (r *Etcd) keepDeletedImageRepository(ctx kapi.Context, key, id string) error {
+	deletedKey, err := makeDeletedImageRepositoryKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	var repo api.ImageRepository
+	if err := r.ExtractObj(key, &repo, false); err != nil {
+		return etcderr.InterpretDeleteError(err, "imageRepository", id)
+	}
+	if err := r.Delete(deletedKey, false); err != nil && !tools.IsEtcdNotFound(err) {
+		return err
+	}
+	repo.ResourceVersion = ""
+	if repo.Annotations == nil {
+		repo.Annotations = map[string]string{}
+	}
+	repo.Annotations[api.ImageRepositoryDeletedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	ttl := uint64(r.deletionGracePeriod / time.Second)
+	if ttl == 0 {
+		ttl = 1
+	}
+	return r.CreateObj(deletedKey, &repo, ttl)
+}
+
+// RestoreImageRepository recreates the ImageRepository id if it was deleted within the deletion grace
+// period and no repository of the same name was created since.
+func (r *Etcd) RestoreImageRepository(ctx kapi.Context, id string) (*api.ImageRepository, error) {
+	deletedKey, err := makeDeletedImageRepositoryKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var repo api.ImageRepository
+	if err := r.ExtractObj(deletedKey, &repo, false); err != nil {
+		return nil, etcderr.InterpretGetError(err, "deletedImageRepository", id)
+	}
+	if r.expired(&repo) {
+		return nil, apierrs.NewNotFound("deletedImageRepository", id)
+	}
+	repo.ResourceVersion = ""
+	delete(repo.Annotations, api.ImageRepositoryDeletedAnnotation)
+	if err := r.CreateImageRepository(ctx, &repo); err != nil {
+		return nil, err
+	}
+	if err := r.Delete(deletedKey, false); err != nil && !tools.IsEtcdNotFound(err) {
+		return nil, err
+	}
+	return r.GetImageRepository(ctx, id)
+}
+
+// ListDeletedImageRepositories retrieves the deleted ImageRepositories that can still be restored.
+func (r *Etcd) ListDeletedImageRepositories(ctx kapi.Context) (*api.ImageRepositoryList, error) {
+	list := api.ImageRepositoryList{}
+	if err := r.ExtractToList(kubeetcd.MakeEtcdListKey(ctx, DeletedImageRepositoriesPath), &list); err != nil {
+		return nil, err
+	}
+	restorable := []api.ImageRepository{}
+	for _, item := range list.Items {
+		if !r.expired(&item) {
+			restorable = append(restorable, item)
+		}
+	}
+	list.Items = restorable
+	return &list, nil
+}
+
+// PurgeDeletedImageRepository removes the copy of the deleted ImageRepository id, which can then no
+// longer be restored.
+func (r *Etcd) PurgeDeletedImageRepository(ctx kapi.Context, id string) error {
+	deletedKey, err := makeDeletedImageRepositoryKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = r.Delete(deletedKey, false)
+	return etcderr.InterpretDeleteError(err, "deletedImageRepository", id)
+}
+
+// expired returns true if the deleted repository was deleted longer than the deletion grace period ago.
+// Copies that do not record when they were deleted expire with their etcd TTL only.
+func (r *Etcd) expired(repo *api.ImageRepository) bool {
+	deleted, err := time.Parse(time.RFC3339, repo.Annotations[api.ImageRepositoryDeletedAnnotation])
+	if err != nil {
+		return false
+	}
+	return !time.Now().Before(deleted.Add(r.deletionGracePeriod))
+}
+
 // fillRepository sets the status information of a repository
 func (r *Etcd) fillRepository(repo *api.ImageRepository) *api.ImageRepository {
 	var value string
@@ -63,7 +63,7 @@ var (
 )
 
 func NewTestEtcd(client tools.EtcdClient) *Etcd {
-	return New(tools.EtcdHelper{client, latest.Codec, tools.RuntimeVersionAdapter{latest.ResourceVersioner}}, noDefaultRegistry)
+	return New(tools.EtcdHelper{client, latest.Codec, tools.RuntimeVersionAdapter{latest.ResourceVersioner}}, noDefaultRegistry, 0)
 }
 
 func TestEtcdListImagesEmpty(t *testing.T) {
@@ -353,17 +353,34 @@ func TestEtcdDeleteImageOK(t *testing.T) {
 	}
 }
 
-func TestEtcdWatchImagesErrorWithFieldSet(t *testing.T) {
+func TestEtcdWatchImagesWithFieldSet(t *testing.T) {
 	fakeClient := tools.NewFakeEtcdClient(t)
 	registry := NewTestEtcd(fakeClient)
 
-	_, err := registry.WatchImages(kapi.NewDefaultContext(), labels.Everything(), labels.SelectorFromSet(labels.Set{"foo": "bar"}), "1")
-	if err == nil {
-		t.Fatal("unexpected nil error")
+	field := labels.SelectorFromSet(labels.Set{"dockerImageReference": "registry/app:latest"})
+	watching, err := registry.WatchImages(kapi.NewDefaultContext(), labels.Everything(), field, "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if err.Error() != "field selectors are not supported on images" {
-		t.Fatalf("unexpected error: %s", err.Error())
+	fakeClient.WaitForWatchCompletion()
+
+	for _, image := range []*api.Image{
+		{ObjectMeta: kapi.ObjectMeta{Name: "a"}, DockerImageReference: "registry/other:latest"},
+		{ObjectMeta: kapi.ObjectMeta{Name: "b"}, DockerImageReference: "registry/app:latest"},
+	} {
+		imageBytes, _ := latest.Codec.Encode(image)
+		fakeClient.WatchResponse <- &etcd.Response{
+			Action: "set",
+			Node:   &etcd.Node{Value: string(imageBytes)},
+		}
+	}
+
+	event := <-watching.ResultChan()
+	if image, ok := event.Object.(*api.Image); !ok || image.Name != "b" {
+		t.Errorf("expected only the matching image, got %#v", event.Object)
 	}
+	fakeClient.WatchInjectError <- nil
+	watching.Stop()
 }
 
 func TestEtcdWatchImagesOK(t *testing.T) {
@@ -730,6 +747,94 @@ func TestEtcdDeleteImageRepositoryOK(t *testing.T) {
 	}
 }
 
+func TestEtcdDeleteAndRestoreImageRepository(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	key := makeTestDefaultImageRepositoriesKey("foo")
+	deletedKey := "/deletedImageRepositories/" + kapi.NamespaceDefault + "/foo"
+	fakeClient.Set(key, runtime.EncodeOrDie(latest.Codec, &api.ImageRepository{ObjectMeta: kapi.ObjectMeta{Name: "foo"}, Tags: map[string]string{"latest": "image1"}}), 0)
+	registry := NewTestEtcd(fakeClient)
+	registry.deletionGracePeriod = time.Hour
+
+	if err := registry.DeleteImageRepository(kapi.NewDefaultContext(), "foo"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fakeClient.LastSetTTL != 3600 {
+		t.Errorf("Expected the deleted repository to expire after an hour, got a TTL of %d", fakeClient.LastSetTTL)
+	}
+	if _, err := registry.GetImageRepository(kapi.NewDefaultContext(), "foo"); !errors.IsNotFound(err) {
+		t.Errorf("Expected the repository to be deleted, got %v", err)
+	}
+
+	repo, err := registry.RestoreImageRepository(kapi.NewDefaultContext(), "foo")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if repo.Name != "foo" || repo.Tags["latest"] != "image1" || len(repo.Annotations[api.ImageRepositoryDeletedAnnotation]) != 0 {
+		t.Errorf("Unexpected restored repository: %#v", repo)
+	}
+	if e, a := deletedKey, fakeClient.DeletedKeys[len(fakeClient.DeletedKeys)-1]; e != a {
+		t.Errorf("Expected the deleted repository %s to be removed, got %s", e, a)
+	}
+
+	if _, err := registry.RestoreImageRepository(kapi.NewDefaultContext(), "foo"); !errors.IsNotFound(err) {
+		t.Errorf("Expected a not found error restoring the repository again, got %v", err)
+	}
+}
+
+func TestEtcdRestoreImageRepositoryExists(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.Set(makeTestDefaultImageRepositoriesKey("foo"), runtime.EncodeOrDie(latest.Codec, &api.ImageRepository{ObjectMeta: kapi.ObjectMeta{Name: "foo"}}), 0)
+	fakeClient.Set("/deletedImageRepositories/"+kapi.NamespaceDefault+"/foo", runtime.EncodeOrDie(latest.Codec, &api.ImageRepository{ObjectMeta: kapi.ObjectMeta{Name: "foo"}}), 0)
+	registry := NewTestEtcd(fakeClient)
+
+	if _, err := registry.RestoreImageRepository(kapi.NewDefaultContext(), "foo"); !errors.IsAlreadyExists(err) {
+		t.Errorf("Expected an already exists error, got %v", err)
+	}
+}
+
+func TestEtcdListAndPurgeDeletedImageRepositories(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	deleted := func(name string, at time.Time) *etcd.Node {
+		repo := &api.ImageRepository{ObjectMeta: kapi.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{api.ImageRepositoryDeletedAnnotation: at.UTC().Format(time.RFC3339)},
+		}}
+		return &etcd.Node{Value: runtime.EncodeOrDie(latest.Codec, repo)}
+	}
+	fakeClient.Data["/deletedImageRepositories/"+kapi.NamespaceDefault] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Nodes: []*etcd.Node{
+					deleted("foo", time.Now().Add(-time.Minute)),
+					deleted("bar", time.Now().Add(-2*time.Hour)),
+				},
+			},
+		},
+	}
+	fakeClient.Set("/deletedImageRepositories/"+kapi.NamespaceDefault+"/bar", deleted("bar", time.Now().Add(-2*time.Hour)).Value, 0)
+	registry := NewTestEtcd(fakeClient)
+	registry.deletionGracePeriod = time.Hour
+
+	repos, err := registry.ListDeletedImageRepositories(kapi.NewDefaultContext())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(repos.Items) != 1 || repos.Items[0].Name != "foo" {
+		t.Errorf("Expected only the repository deleted within the grace period to be listed, got %#v", repos.Items)
+	}
+
+	if _, err := registry.RestoreImageRepository(kapi.NewDefaultContext(), "bar"); !errors.IsNotFound(err) {
+		t.Errorf("Expected a not found error restoring an expired repository, got %v", err)
+	}
+
+	if err := registry.PurgeDeletedImageRepository(kapi.NewDefaultContext(), "bar"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if e, a := "/deletedImageRepositories/"+kapi.NamespaceDefault+"/bar", fakeClient.DeletedKeys[len(fakeClient.DeletedKeys)-1]; e != a {
+		t.Errorf("Expected the deleted repository %s to be purged, got %s", e, a)
+	}
+}
+
 func TestEtcdWatchImageRepositories(t *testing.T) {
 	fakeClient := tools.NewFakeEtcdClient(t)
 	registry := NewTestEtcd(fakeClient)
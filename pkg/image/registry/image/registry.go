@@ -11,6 +11,9 @@ import (
 type Registry interface {
 	// ListImages obtains a list of images that match a selector.
 	ListImages(ctx kapi.Context, selector labels.Selector) (*api.ImageList, error)
+	// ListImagesPage obtains a page of up to limit images that match label and field, which continues the
+	// list continueToken was returned with.
+	ListImagesPage(ctx kapi.Context, label, field labels.Selector, limit int, continueToken string) (*api.ImageList, error)
 	// GetImage retrieves a specific image.
 	GetImage(ctx kapi.Context, id string) (*api.Image, error)
 	// CreateImage creates a new image.
@@ -10,6 +10,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
+	"github.com/openshift/origin/pkg/api/selectable"
 	"github.com/openshift/origin/pkg/image/api"
 	"github.com/openshift/origin/pkg/image/api/validation"
 )
@@ -40,9 +41,21 @@ func (s *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime
 		return nil, err
 	}
 
+	if err := selectable.FilterList(images, selector, fields); err != nil {
+		return nil, err
+	}
 	return images, nil
 }
 
+// ListPage retrieves a page of up to limit Images that match selector and fields, which continues the list
+// continueToken was returned with.
+func (s *REST) ListPage(ctx kapi.Context, selector, fields labels.Selector, limit int, continueToken string) (runtime.Object, error) {
+	if err := selectable.ValidateFields(&api.Image{}, fields); err != nil {
+		return nil, err
+	}
+	return s.registry.ListImagesPage(ctx, selector, fields, limit, continueToken)
+}
+
 // Get retrieves an Image by id.
 func (s *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
 	image, err := s.registry.GetImage(ctx, id)
@@ -85,5 +98,8 @@ func (s *REST) Delete(ctx kapi.Context, id string) (<-chan apiserver.RESTResult,
 
 // Watch begins watching for new or deleted Images.
 func (s *REST) Watch(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	if err := selectable.ValidateFields(&api.Image{}, field); err != nil {
+		return nil, err
+	}
 	return s.registry.WatchImages(ctx, label, field, resourceVersion)
 }
@@ -8,8 +8,10 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
+	"github.com/openshift/origin/pkg/api/selectable"
 	"github.com/openshift/origin/pkg/image/api"
 	"github.com/openshift/origin/pkg/image/api/validation"
 )
@@ -37,7 +39,14 @@ func (*REST) NewList() runtime.Object {
 
 // List retrieves a list of ImageRepositories that match selector.
 func (s *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime.Object, error) {
-	return s.registry.ListImageRepositories(ctx, selector)
+	repos, err := s.registry.ListImageRepositories(ctx, selector)
+	if err != nil {
+		return repos, err
+	}
+	if err := selectable.FilterList(repos, selector, fields); err != nil {
+		return nil, err
+	}
+	return repos, nil
 }
 
 // Get retrieves an ImageRepository by id.
@@ -47,6 +56,9 @@ func (s *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
 
 // Watch begins watching for new, changed, or deleted ImageRepositories.
 func (s *REST) Watch(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	if err := selectable.ValidateFields(&api.ImageRepository{}, field); err != nil {
+		return nil, err
+	}
 	return s.registry.WatchImageRepositories(ctx, label, field, resourceVersion)
 }
 
@@ -63,6 +75,7 @@ func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 	}
 
 	repo.Status = api.ImageRepositoryStatus{}
+	updateTagEvents(repo, nil)
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
 		if err := s.registry.CreateImageRepository(ctx, repo); err != nil {
 			return nil, err
@@ -84,14 +97,52 @@ func (s *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 	repo.Status = api.ImageRepositoryStatus{}
 
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
-		err := s.registry.UpdateImageRepository(ctx, repo)
+		existing, err := s.registry.GetImageRepository(ctx, repo.Name)
 		if err != nil {
 			return nil, err
 		}
+		if errs := validation.ValidateImageRepositoryUpdate(repo, existing); len(errs) > 0 {
+			return nil, errors.NewInvalid("imageRepository", repo.Name, errs)
+		}
+		repo.Status.Tags = existing.Status.Tags
+		updateTagEvents(repo, existing.Tags)
+		if err := s.registry.UpdateImageRepository(ctx, repo); err != nil {
+			return nil, err
+		}
 		return s.Get(ctx, repo.Name)
 	}), nil
 }
 
+// updateTagEvents records a TagEvent in the status of repo for every tag that points to a different
+// image than in oldTags. The pull spec of the image is carried over from earlier events of the
+// repository, when there are any.
+func updateTagEvents(repo *api.ImageRepository, oldTags map[string]string) {
+	now := util.Now()
+	for tag, image := range repo.Tags {
+		if old, ok := oldTags[tag]; ok && old == image {
+			continue
+		}
+		api.AddTagEvent(repo, tag, api.TagEvent{
+			Created:              now,
+			DockerImageReference: dockerImageReferenceFor(repo, image),
+			Image:                image,
+		})
+	}
+}
+
+// dockerImageReferenceFor returns the pull spec recorded for image in the status of repo, or an empty
+// string.
+func dockerImageReferenceFor(repo *api.ImageRepository, image string) string {
+	for _, events := range repo.Status.Tags {
+		for _, event := range events.Items {
+			if event.Image == image && len(event.DockerImageReference) > 0 {
+				return event.DockerImageReference
+			}
+		}
+	}
+	return ""
+}
+
 // Delete asynchronously deletes an ImageRepository specified by its id.
 func (s *REST) Delete(ctx kapi.Context, id string) (<-chan apiserver.RESTResult, error) {
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
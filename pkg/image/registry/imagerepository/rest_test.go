@@ -208,6 +208,7 @@ func TestUpdateRegistryErrorSaving(t *testing.T) {
 
 func TestUpdateImageRepositoryOK(t *testing.T) {
 	mockRepositoryRegistry := test.NewImageRepositoryRegistry()
+	mockRepositoryRegistry.ImageRepository = &api.ImageRepository{ObjectMeta: kapi.ObjectMeta{Name: "bar", Namespace: kapi.NamespaceDefault}}
 	storage := REST{
 		registry: mockRepositoryRegistry,
 	}
@@ -228,6 +229,45 @@ func TestUpdateImageRepositoryOK(t *testing.T) {
 	}
 }
 
+func TestUpdateImageRepositoryTagEvents(t *testing.T) {
+	mockRepositoryRegistry := test.NewImageRepositoryRegistry()
+	mockRepositoryRegistry.ImageRepository = &api.ImageRepository{
+		ObjectMeta: kapi.ObjectMeta{Name: "bar", Namespace: kapi.NamespaceDefault},
+		Tags:       map[string]string{"latest": "image1", "stable": "image1"},
+		Status: api.ImageRepositoryStatus{
+			Tags: map[string]api.TagEventList{
+				"latest": {Items: []api.TagEvent{{Image: "image1", DockerImageReference: "registry/bar@image1"}}},
+				"stable": {Items: []api.TagEvent{{Image: "image1", DockerImageReference: "registry/bar@image1"}}},
+			},
+		},
+	}
+	storage := REST{
+		registry: mockRepositoryRegistry,
+	}
+
+	channel, err := storage.Update(kapi.NewDefaultContext(), &api.ImageRepository{
+		ObjectMeta: kapi.ObjectMeta{Name: "bar"},
+		Tags:       map[string]string{"latest": "image2", "stable": "image1", "old": "image1"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected non-nil error: %#v", err)
+	}
+	result := <-channel
+	repo, ok := result.Object.(*api.ImageRepository)
+	if !ok {
+		t.Fatalf("Expected image repository, got %#v", result)
+	}
+	if events := repo.Status.Tags["latest"].Items; len(events) != 2 || events[0].Image != "image2" || len(events[0].DockerImageReference) != 0 || events[1].Image != "image1" {
+		t.Errorf("Unexpected events for latest: %#v", events)
+	}
+	if events := repo.Status.Tags["stable"].Items; len(events) != 1 {
+		t.Errorf("Unexpected events for stable: %#v", events)
+	}
+	if latest := api.LatestTaggedImage(repo, "old"); latest == nil || latest.Image != "image1" || latest.DockerImageReference != "registry/bar@image1" {
+		t.Errorf("Unexpected event for old: %#v", latest)
+	}
+}
+
 func TestDeleteImageRepository(t *testing.T) {
 	mockRepositoryRegistry := test.NewImageRepositoryRegistry()
 	storage := REST{
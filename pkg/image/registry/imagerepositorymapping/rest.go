@@ -8,6 +8,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
 	"github.com/openshift/origin/pkg/image/api"
 	"github.com/openshift/origin/pkg/image/api/validation"
@@ -58,6 +59,11 @@ func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 		repo.Tags = make(map[string]string)
 	}
 	repo.Tags[mapping.Tag] = image.Name
+	api.AddTagEvent(repo, mapping.Tag, api.TagEvent{
+		Created:              util.Now(),
+		DockerImageReference: image.DockerImageReference,
+		Image:                image.Name,
+	})
 
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
 		if err := s.imageRegistry.CreateImage(ctx, &image); err != nil {
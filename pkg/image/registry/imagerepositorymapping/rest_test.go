@@ -143,6 +143,13 @@ func TestCreateImageRepositoryMapping(t *testing.T) {
 	if e, a := "imageID1", repo.Tags["latest"]; e != a {
 		t.Errorf("Expected %s, got %s", e, a)
 	}
+	latest := api.LatestTaggedImage(repo, "latest")
+	if latest == nil {
+		t.Fatalf("Expected a tag event for latest, got %#v", repo.Status)
+	}
+	if latest.Image != "imageID1" || latest.DockerImageReference != mapping.Image.DockerImageReference {
+		t.Errorf("Unexpected tag event: %#v", latest)
+	}
 }
 
 func TestCreateImageRepositoryConflictingNamespace(t *testing.T) {
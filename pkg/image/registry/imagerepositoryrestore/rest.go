@@ -0,0 +1,82 @@
+package imagerepositoryrestore
+
+import (
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/api/selectable"
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// Registry is an interface for things that can restore deleted ImageRepository objects.
+type Registry interface {
+	// RestoreImageRepository recreates a deleted image repository.
+	RestoreImageRepository(ctx kapi.Context, id string) (*api.ImageRepository, error)
+	// ListDeletedImageRepositories lists the deleted image repositories that can still be restored.
+	ListDeletedImageRepositories(ctx kapi.Context) (*api.ImageRepositoryList, error)
+	// PurgeDeletedImageRepository removes a deleted image repository so it can no longer be restored.
+	PurgeDeletedImageRepository(ctx kapi.Context, id string) error
+}
+
+// REST implements the RESTStorage interface in terms of a Registry.
+// Create restores the ImageRepository named by an ImageRepositoryRestore, List returns the deleted
+// ImageRepositories that can be restored, and Delete purges a deleted ImageRepository.
+type REST struct {
+	registry Registry
+}
+
+// NewREST returns a new REST.
+func NewREST(registry Registry) apiserver.RESTStorage {
+	return &REST{registry}
+}
+
+// New returns a new ImageRepositoryRestore for use with Create.
+func (s *REST) New() runtime.Object {
+	return &api.ImageRepositoryRestore{}
+}
+
+// NewList returns a new ImageRepositoryList, as the restorable repositories are listed.
+func (*REST) NewList() runtime.Object {
+	return &api.ImageRepositoryList{}
+}
+
+// List retrieves the deleted ImageRepositories that can still be restored and match selector.
+func (s *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime.Object, error) {
+	repos, err := s.registry.ListDeletedImageRepositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := selectable.FilterList(repos, selector, fields); err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+// Delete purges the deleted ImageRepository id, which can then no longer be restored.
+func (s *REST) Delete(ctx kapi.Context, id string) (<-chan apiserver.RESTResult, error) {
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		return &kapi.Status{Status: kapi.StatusSuccess}, s.registry.PurgeDeletedImageRepository(ctx, id)
+	}), nil
+}
+
+// Create restores the deleted ImageRepository named by the ImageRepositoryRestore and returns it.
+func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	restore := obj.(*api.ImageRepositoryRestore)
+	if !kapi.ValidNamespace(ctx, &restore.ObjectMeta) {
+		return nil, errors.NewConflict("imageRepositoryRestore", restore.Namespace, fmt.Errorf("ImageRepositoryRestore.Namespace does not match the provided context"))
+	}
+	if len(restore.Name) == 0 {
+		return nil, errors.NewInvalid("imageRepositoryRestore", restore.Name, errors.ValidationErrorList{
+			errors.NewFieldRequired("name", restore.Name),
+		})
+	}
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		return s.registry.RestoreImageRepository(ctx, restore.Name)
+	}), nil
+}
@@ -0,0 +1,106 @@
+package imagerepositoryrestore
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+type testRegistry struct {
+	restored string
+	purged   string
+}
+
+func (r *testRegistry) ListDeletedImageRepositories(ctx kapi.Context) (*api.ImageRepositoryList, error) {
+	return &api.ImageRepositoryList{Items: []api.ImageRepository{
+		{ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: kapi.NamespaceDefault}},
+		{ObjectMeta: kapi.ObjectMeta{Name: "bar", Namespace: kapi.NamespaceDefault}},
+	}}, nil
+}
+
+func (r *testRegistry) PurgeDeletedImageRepository(ctx kapi.Context, id string) error {
+	if id != "foo" {
+		return errors.NewNotFound("deletedImageRepository", id)
+	}
+	r.purged = id
+	return nil
+}
+
+func (r *testRegistry) RestoreImageRepository(ctx kapi.Context, id string) (*api.ImageRepository, error) {
+	if id != "foo" {
+		return nil, errors.NewNotFound("deletedImageRepository", id)
+	}
+	r.restored = id
+	return &api.ImageRepository{ObjectMeta: kapi.ObjectMeta{Name: id, Namespace: kapi.NamespaceDefault}}, nil
+}
+
+func TestCreateImageRepositoryRestore(t *testing.T) {
+	registry := &testRegistry{}
+	storage := REST{registry}
+
+	channel, err := storage.Create(kapi.NewDefaultContext(), &api.ImageRepositoryRestore{ObjectMeta: kapi.ObjectMeta{Name: "foo"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := <-channel
+	repo, ok := result.Object.(*api.ImageRepository)
+	if !ok || repo.Name != "foo" {
+		t.Errorf("Expected the restored repository, got %#v", result.Object)
+	}
+	if registry.restored != "foo" {
+		t.Errorf("Expected foo to be restored, got %q", registry.restored)
+	}
+
+	channel, err = storage.Create(kapi.NewDefaultContext(), &api.ImageRepositoryRestore{ObjectMeta: kapi.ObjectMeta{Name: "bar"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result = <-channel
+	if status, ok := result.Object.(*kapi.Status); !ok || status.Reason != kapi.StatusReasonNotFound {
+		t.Errorf("Expected a not found status, got %#v", result.Object)
+	}
+}
+
+func TestCreateImageRepositoryRestoreInvalid(t *testing.T) {
+	storage := REST{&testRegistry{}}
+
+	if _, err := storage.Create(kapi.NewDefaultContext(), &api.ImageRepositoryRestore{}); !errors.IsInvalid(err) {
+		t.Errorf("Expected an invalid error for a restore without a name, got %v", err)
+	}
+	if _, err := storage.Create(kapi.NewDefaultContext(), &api.ImageRepositoryRestore{ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: "other"}}); !errors.IsConflict(err) {
+		t.Errorf("Expected a conflict error for a restore in another namespace, got %v", err)
+	}
+}
+
+func TestListImageRepositoryRestores(t *testing.T) {
+	storage := REST{&testRegistry{}}
+
+	obj, err := storage.List(kapi.NewDefaultContext(), labels.Everything(), labels.SelectorFromSet(labels.Set{"name": "bar"}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	repos, ok := obj.(*api.ImageRepositoryList)
+	if !ok || len(repos.Items) != 1 || repos.Items[0].Name != "bar" {
+		t.Errorf("Expected the restorable repository bar, got %#v", obj)
+	}
+}
+
+func TestDeleteImageRepositoryRestore(t *testing.T) {
+	registry := &testRegistry{}
+	storage := REST{registry}
+
+	channel, err := storage.Delete(kapi.NewDefaultContext(), "foo")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if status, ok := (<-channel).Object.(*kapi.Status); !ok || status.Status != kapi.StatusSuccess {
+		t.Errorf("Expected a success status, got %#v", status)
+	}
+	if registry.purged != "foo" {
+		t.Errorf("Expected foo to be purged, got %q", registry.purged)
+	}
+}
@@ -0,0 +1,56 @@
+package imagesearch
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/api/selectable"
+	"github.com/openshift/origin/pkg/image/api"
+	"github.com/openshift/origin/pkg/image/registry/image"
+)
+
+// REST implements a read-only RESTStorage that finds Images by the labels set on them in their
+// Dockerfiles, rather than the labels of the Image objects.
+type REST struct {
+	registry image.Registry
+}
+
+// NewREST returns a new REST.
+func NewREST(registry image.Registry) apiserver.RESTStorage {
+	return &REST{registry}
+}
+
+// New returns a new Image.
+func (s *REST) New() runtime.Object {
+	return &api.Image{}
+}
+
+// NewList returns a new ImageList.
+func (*REST) NewList() runtime.Object {
+	return &api.ImageList{}
+}
+
+// List retrieves the Images whose Docker labels match label and whose fields match field.
+func (s *REST) List(ctx kapi.Context, label, field labels.Selector) (runtime.Object, error) {
+	images, err := s.registry.ListImages(ctx, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	if err := selectable.FilterList(images, labels.Everything(), field); err != nil {
+		return nil, err
+	}
+	if label == nil || label.Empty() {
+		return images, nil
+	}
+
+	matched := []api.Image{}
+	for _, image := range images.Items {
+		if label.Matches(labels.Set(image.DockerImageMetadata.Config.Labels)) {
+			matched = append(matched, image)
+		}
+	}
+	images.Items = matched
+	return images, nil
+}
@@ -0,0 +1,70 @@
+package imagesearch
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	"github.com/openshift/origin/pkg/image/api"
+	"github.com/openshift/origin/pkg/image/registry/test"
+)
+
+func imageWithLabels(name string, dockerLabels map[string]string) api.Image {
+	image := api.Image{
+		ObjectMeta:           kapi.ObjectMeta{Name: name, Labels: map[string]string{"kind": "image"}},
+		DockerImageReference: "registry/ns/" + name,
+	}
+	image.DockerImageMetadata.Config.Labels = dockerLabels
+	return image
+}
+
+func TestSearchImages(t *testing.T) {
+	testCases := []struct {
+		label    string
+		field    string
+		expected []string
+	}{
+		{expected: []string{"ruby", "python", "mysql"}},
+		{label: "builder=sti", expected: []string{"ruby", "python"}},
+		{label: "builder=sti,language=ruby", expected: []string{"ruby"}},
+		{label: "builder=sti", field: "name=python", expected: []string{"python"}},
+		{label: "kind=image", expected: []string{}},
+	}
+	mockRegistry := test.NewImageRegistry()
+	storage := REST{registry: mockRegistry}
+	for _, test := range testCases {
+		mockRegistry.Images = &api.ImageList{
+			Items: []api.Image{
+				imageWithLabels("ruby", map[string]string{"builder": "sti", "language": "ruby"}),
+				imageWithLabels("python", map[string]string{"builder": "sti", "language": "python"}),
+				imageWithLabels("mysql", nil),
+			},
+		}
+		label, _ := labels.ParseSelector(test.label)
+		field, _ := labels.ParseSelector(test.field)
+		obj, err := storage.List(kapi.NewDefaultContext(), label, field)
+		if err != nil {
+			t.Errorf("%s/%s: unexpected error: %v", test.label, test.field, err)
+			continue
+		}
+		names := []string{}
+		for _, image := range obj.(*api.ImageList).Items {
+			names = append(names, image.Name)
+		}
+		if len(names) != len(test.expected) {
+			t.Errorf("%s/%s: expected %v, got %v", test.label, test.field, test.expected, names)
+			continue
+		}
+		for i := range names {
+			if names[i] != test.expected[i] {
+				t.Errorf("%s/%s: expected %v, got %v", test.label, test.field, test.expected, names)
+				break
+			}
+		}
+	}
+
+	if _, err := storage.List(kapi.NewDefaultContext(), labels.Everything(), labels.SelectorFromSet(labels.Set{"unknown": "field"})); err == nil {
+		t.Errorf("Expected an error for an unknown field")
+	}
+}
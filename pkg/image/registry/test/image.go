@@ -27,6 +27,13 @@ func (r *ImageRegistry) ListImages(ctx kapi.Context, selector labels.Selector) (
 	return r.Images, r.Err
 }
 
+func (r *ImageRegistry) ListImagesPage(ctx kapi.Context, label, field labels.Selector, limit int, continueToken string) (*api.ImageList, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.Images, r.Err
+}
+
 func (r *ImageRegistry) GetImage(ctx kapi.Context, id string) (*api.Image, error) {
 	r.Lock()
 	defer r.Unlock()
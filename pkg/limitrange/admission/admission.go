@@ -0,0 +1,135 @@
+package admission
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	"github.com/openshift/origin/pkg/limitrange"
+	"github.com/openshift/origin/pkg/quota"
+)
+
+// limitRanger enforces the limits of the LimitRanges in a project when OpenShift resources are
+// created or updated.
+type limitRanger struct {
+	client   kclient.Interface
+	measures quota.MeasuresFunc
+}
+
+// NewLimitRanger returns an admission.Interface that enforces the OpenShift limits of the LimitRanges
+// in a project:
+//
+// * a build is given a completion deadline no longer than the longest duration it may run
+// * a deployment config is rejected if it requests too many or too few replicas
+// * an object is rejected if the project already holds the most objects of its kind allowed
+//
+// LimitRanges are read with client, and the number of objects and the amount the new object consumes
+// are measured with the functions measures returns for each admission.
+func NewLimitRanger(client kclient.Interface, measures quota.MeasuresFunc) admission.Interface {
+	return &limitRanger{
+		client:   client,
+		measures: measures,
+	}
+}
+
+// Admit rejects the request if the object exceeds a limit in the namespace.
+func (l *limitRanger) Admit(a admission.Attributes) error {
+	operation := a.GetOperation()
+	if operation != "CREATE" && operation != "UPDATE" {
+		return nil
+	}
+	resources := []kapi.ResourceName{}
+	if operation == "CREATE" {
+		resources = quota.ResourcesForKind(a.GetKind())
+	}
+	build, isBuild := a.GetObject().(*buildapi.Build)
+	config, isConfig := a.GetObject().(*deployapi.DeploymentConfig)
+	if len(resources) == 0 && !isBuild && !isConfig {
+		return nil
+	}
+
+	list, err := l.client.LimitRanges(a.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		return errors.NewInternalError(err)
+	}
+	if len(list.Items) == 0 {
+		return nil
+	}
+
+	usage, consume := l.measures()
+	if err := admitCount(a.GetNamespace(), list.Items, resources, a.GetObject(), usage, consume); err != nil {
+		return errors.NewForbidden(a.GetKind(), "", err)
+	}
+	switch {
+	case isBuild && operation == "CREATE":
+		limitDuration(build, list.Items)
+	case isConfig:
+		if err := admitReplicas(config, list.Items); err != nil {
+			return errors.NewForbidden(a.GetKind(), config.Name, err)
+		}
+	}
+	return nil
+}
+
+// admitCount returns an error if admitting obj, which consumes resources, would exceed the most of any
+// of resources the namespace may hold according to limitRanges, measured with usage and consume.
+func admitCount(namespace string, limitRanges []kapi.LimitRange, resources []kapi.ResourceName, obj runtime.Object, usage quota.UsageFuncs, consume quota.ConsumeFuncs) error {
+	for _, name := range resources {
+		_, _, max, hasMax := limitrange.Bounds(limitRanges, limitrange.LimitTypeProject, name)
+		if !hasMax {
+			continue
+		}
+		fn, ok := usage[name]
+		if !ok {
+			continue
+		}
+		consumed, err := consume.Consumed(name, namespace, obj)
+		if err != nil {
+			return fmt.Errorf("unable to measure consumption of %s: %v", name, err)
+		}
+		if consumed == 0 {
+			continue
+		}
+		observed, err := fn(namespace)
+		if err != nil {
+			return fmt.Errorf("unable to measure usage of %s: %v", name, err)
+		}
+		if observed+consumed > max {
+			return fmt.Errorf("limited to %d %s by a limit range", max, name)
+		}
+	}
+	return nil
+}
+
+// limitDuration sets the completion deadline of build, which the build controller enforces, to the
+// longest duration allowed by limitRanges, unless the build already has a shorter one.
+func limitDuration(build *buildapi.Build, limitRanges []kapi.LimitRange) {
+	_, _, max, hasMax := limitrange.Bounds(limitRanges, limitrange.LimitTypeBuild, limitrange.ResourceDuration)
+	if !hasMax {
+		return
+	}
+	if deadline := build.Parameters.CompletionDeadlineSeconds; deadline != nil && *deadline > 0 && *deadline <= max {
+		return
+	}
+	build.Parameters.CompletionDeadlineSeconds = &max
+}
+
+// admitReplicas returns an error if config requests more or fewer replicas than limitRanges allow.
+func admitReplicas(config *deployapi.DeploymentConfig, limitRanges []kapi.LimitRange) error {
+	min, hasMin, max, hasMax := limitrange.Bounds(limitRanges, limitrange.LimitTypeDeploymentConfig, limitrange.ResourceReplicas)
+	replicas := int64(config.Template.ControllerTemplate.Replicas)
+	if hasMax && replicas > max {
+		return fmt.Errorf("limited to at most %d replicas by a limit range", max)
+	}
+	if hasMin && replicas < min {
+		return fmt.Errorf("limited to at least %d replicas by a limit range", min)
+	}
+	return nil
+}
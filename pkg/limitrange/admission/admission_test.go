@@ -0,0 +1,143 @@
+package admission
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	"github.com/openshift/origin/pkg/limitrange"
+	"github.com/openshift/origin/pkg/quota"
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+func limitClient(items ...kapi.LimitRangeItem) *kclient.Fake {
+	return &kclient.Fake{
+		LimitRangesList: kapi.LimitRangeList{
+			Items: []kapi.LimitRange{
+				{
+					ObjectMeta: kapi.ObjectMeta{Name: "limits", Namespace: "test"},
+					Spec:       kapi.LimitRangeSpec{Limits: items},
+				},
+			},
+		},
+	}
+}
+
+// measures returns a MeasuresFunc that returns usage and consume for every admission.
+func measures(usage quota.UsageFuncs, consume quota.ConsumeFuncs) quota.MeasuresFunc {
+	return func() (quota.UsageFuncs, quota.ConsumeFuncs) {
+		return usage, consume
+	}
+}
+
+func routeUsage(count int64) quota.UsageFuncs {
+	return quota.UsageFuncs{
+		quota.ResourceRoutes: func(namespace string) (int64, error) {
+			return count, nil
+		},
+	}
+}
+
+func configWithReplicas(replicas int) *deployapi.DeploymentConfig {
+	config := &deployapi.DeploymentConfig{ObjectMeta: kapi.ObjectMeta{Name: "config"}}
+	config.Template.ControllerTemplate.Replicas = replicas
+	return config
+}
+
+func TestAdmitRouteCount(t *testing.T) {
+	client := limitClient(kapi.LimitRangeItem{
+		Type: limitrange.LimitTypeProject,
+		Max:  kapi.ResourceList{quota.ResourceRoutes: resource.MustParse("2")},
+	})
+
+	if err := NewLimitRanger(client, measures(routeUsage(1), nil)).Admit(admission.NewAttributesRecord(&routeapi.Route{}, "test", "routes", "CREATE")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := NewLimitRanger(client, measures(routeUsage(2), nil)).Admit(admission.NewAttributesRecord(&routeapi.Route{}, "test", "routes", "CREATE"))
+	if !errors.IsForbidden(err) {
+		t.Errorf("expected a forbidden error, got %v", err)
+	}
+	if err := NewLimitRanger(client, measures(routeUsage(2), nil)).Admit(admission.NewAttributesRecord(&routeapi.Route{}, "test", "routes", "UPDATE")); err != nil {
+		t.Errorf("unexpected error for update: %v", err)
+	}
+}
+
+func TestAdmitReplicas(t *testing.T) {
+	client := limitClient(kapi.LimitRangeItem{
+		Type: limitrange.LimitTypeDeploymentConfig,
+		Min:  kapi.ResourceList{limitrange.ResourceReplicas: resource.MustParse("1")},
+		Max:  kapi.ResourceList{limitrange.ResourceReplicas: resource.MustParse("3")},
+	})
+	handler := NewLimitRanger(client, measures(quota.UsageFuncs{}, nil))
+
+	testCases := map[string]struct {
+		replicas  int
+		operation string
+		forbidden bool
+	}{
+		"within":      {2, "CREATE", false},
+		"above":       {4, "CREATE", true},
+		"below":       {0, "CREATE", true},
+		"above again": {4, "UPDATE", true},
+		"deleted":     {4, "DELETE", false},
+	}
+	for name, testCase := range testCases {
+		err := handler.Admit(admission.NewAttributesRecord(configWithReplicas(testCase.replicas), "test", "deploymentConfigs", testCase.operation))
+		if testCase.forbidden != errors.IsForbidden(err) {
+			t.Errorf("%s: unexpected result: %v", name, err)
+		}
+	}
+}
+
+func TestAdmitBuildDuration(t *testing.T) {
+	client := limitClient(kapi.LimitRangeItem{
+		Type: limitrange.LimitTypeBuild,
+		Max:  kapi.ResourceList{limitrange.ResourceDuration: resource.MustParse("600")},
+	})
+	handler := NewLimitRanger(client, measures(quota.UsageFuncs{}, nil))
+
+	testCases := map[string]struct {
+		requested int64
+		expected  int64
+	}{
+		"unset":   {0, 600},
+		"shorter": {60, 60},
+		"longer":  {6000, 600},
+		"invalid": {-1, 600},
+	}
+	for name, testCase := range testCases {
+		build := &buildapi.Build{}
+		if testCase.requested != 0 {
+			requested := testCase.requested
+			build.Parameters.CompletionDeadlineSeconds = &requested
+		}
+		if err := handler.Admit(admission.NewAttributesRecord(build, "test", "builds", "CREATE")); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if actual := build.Parameters.CompletionDeadlineSeconds; actual == nil || *actual != testCase.expected {
+			t.Errorf("%s: expected deadline %d, got %v", name, testCase.expected, actual)
+		}
+	}
+}
+
+func TestAdmitWithoutLimitRanges(t *testing.T) {
+	client := &kclient.Fake{}
+	handler := NewLimitRanger(client, measures(routeUsage(100), nil))
+
+	if err := handler.Admit(admission.NewAttributesRecord(configWithReplicas(100), "test", "deploymentConfigs", "CREATE")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := handler.Admit(admission.NewAttributesRecord(&kapi.Pod{}, "test", "pods", "CREATE")); err != nil {
+		t.Errorf("unexpected error for untracked kind: %v", err)
+	}
+	if len(client.Actions) != 1 {
+		t.Errorf("unexpected client actions: %#v", client.Actions)
+	}
+}
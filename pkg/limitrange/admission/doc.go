@@ -0,0 +1,3 @@
+// Package admission contains an admission controller that enforces the limits of a project's
+// LimitRanges on builds, deployment configs and the number of objects in the project.
+package admission
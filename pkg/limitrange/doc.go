@@ -0,0 +1,3 @@
+// Package limitrange defines the limits a project's LimitRanges may place on the OpenShift
+// resources created in it.
+package limitrange
@@ -0,0 +1,43 @@
+package limitrange
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+// The following identify the kinds of OpenShift objects a LimitRangeItem may constrain
+const (
+	// LimitTypeBuild limits each build in a project
+	LimitTypeBuild kapi.LimitType = "openshift.io/Build"
+	// LimitTypeDeploymentConfig limits each deployment config in a project
+	LimitTypeDeploymentConfig kapi.LimitType = "openshift.io/DeploymentConfig"
+	// LimitTypeProject limits the number of objects in a project, by the resource names of
+	// the quota package
+	LimitTypeProject kapi.LimitType = "openshift.io/Project"
+)
+
+// The following identify the resources of a single object that a LimitRangeItem may constrain
+const (
+	// ResourceDuration is the number of seconds a build may run
+	ResourceDuration kapi.ResourceName = "openshift.io/duration"
+	// ResourceReplicas is the number of replicas a deployment config may request
+	ResourceReplicas kapi.ResourceName = "openshift.io/replicas"
+)
+
+// Bounds returns the tightest Min and Max of name across the items of limitType in limitRanges,
+// and whether each was set.
+func Bounds(limitRanges []kapi.LimitRange, limitType kapi.LimitType, name kapi.ResourceName) (min int64, hasMin bool, max int64, hasMax bool) {
+	for _, limitRange := range limitRanges {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type != limitType {
+				continue
+			}
+			if value, ok := item.Min[name]; ok && (!hasMin || value.Value() > min) {
+				min, hasMin = value.Value(), true
+			}
+			if value, ok := item.Max[name]; ok && (!hasMax || value.Value() < max) {
+				max, hasMax = value.Value(), true
+			}
+		}
+	}
+	return
+}
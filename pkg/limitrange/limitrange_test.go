@@ -0,0 +1,43 @@
+package limitrange
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+)
+
+func TestBounds(t *testing.T) {
+	limitRanges := []kapi.LimitRange{
+		{Spec: kapi.LimitRangeSpec{Limits: []kapi.LimitRangeItem{
+			{
+				Type: LimitTypeDeploymentConfig,
+				Min:  kapi.ResourceList{ResourceReplicas: resource.MustParse("1")},
+				Max:  kapi.ResourceList{ResourceReplicas: resource.MustParse("10")},
+			},
+			{
+				Type: LimitTypeBuild,
+				Max:  kapi.ResourceList{ResourceDuration: resource.MustParse("60")},
+			},
+		}}},
+		{Spec: kapi.LimitRangeSpec{Limits: []kapi.LimitRangeItem{
+			{
+				Type: LimitTypeDeploymentConfig,
+				Min:  kapi.ResourceList{ResourceReplicas: resource.MustParse("2")},
+				Max:  kapi.ResourceList{ResourceReplicas: resource.MustParse("20")},
+			},
+		}}},
+	}
+
+	min, hasMin, max, hasMax := Bounds(limitRanges, LimitTypeDeploymentConfig, ResourceReplicas)
+	if !hasMin || min != 2 || !hasMax || max != 10 {
+		t.Errorf("unexpected replica bounds: %d %t %d %t", min, hasMin, max, hasMax)
+	}
+	min, hasMin, max, hasMax = Bounds(limitRanges, LimitTypeBuild, ResourceDuration)
+	if hasMin || !hasMax || max != 60 {
+		t.Errorf("unexpected duration bounds: %d %t %d %t", min, hasMin, max, hasMax)
+	}
+	if _, hasMin, _, hasMax = Bounds(limitRanges, LimitTypeBuild, ResourceReplicas); hasMin || hasMax {
+		t.Errorf("unexpected bounds for an unconstrained resource")
+	}
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+)
+
+// MinTokenSecretLength is the shortest secret a bootstrap or router token may be created with.
+const MinTokenSecretLength = 32
+
+// TokenNameForSecret returns the name of the bootstrap or router token with secret: the hex encoded SHA-256 hash
+// of the secret, so that neither storage nor the API reveal the secrets of tokens.
+func TokenNameForSecret(secret string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(secret)))
+}
+
+// NewTokenSecret returns a random secret for a bootstrap or router token.
+func NewTokenSecret() (string, error) {
+	b := make([]byte, MinTokenSecretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", b), nil
+}
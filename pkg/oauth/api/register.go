@@ -14,6 +14,10 @@ func init() {
 		&OAuthClientList{},
 		&OAuthClientAuthorization{},
 		&OAuthClientAuthorizationList{},
+		&BootstrapToken{},
+		&BootstrapTokenList{},
+		&RouterToken{},
+		&RouterTokenList{},
 	)
 }
 
@@ -25,3 +29,7 @@ func (*OAuthAuthorizeTokenList) IsAnAPIObject()      {}
 func (*OAuthClientList) IsAnAPIObject()              {}
 func (*OAuthClientAuthorization) IsAnAPIObject()     {}
 func (*OAuthClientAuthorizationList) IsAnAPIObject() {}
+func (*BootstrapToken) IsAnAPIObject()               {}
+func (*BootstrapTokenList) IsAnAPIObject()           {}
+func (*RouterToken) IsAnAPIObject()                  {}
+func (*RouterTokenList) IsAnAPIObject()              {}
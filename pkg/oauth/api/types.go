@@ -2,6 +2,7 @@ package api
 
 import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
 type OAuthAccessToken struct {
@@ -31,6 +32,9 @@ type OAuthAccessToken struct {
 
 	// RefreshToken is the value by which this token can be renewed. Can be blank.
 	RefreshToken string `json:"refreshToken,omitempty"`
+
+	// LastUsedTimestamp is when the token was last used to authenticate a request, updated at most once a minute
+	LastUsedTimestamp util.Time `json:"lastUsedTimestamp,omitempty"`
 }
 
 type OAuthAuthorizeToken struct {
@@ -92,6 +96,44 @@ type OAuthClientAuthorization struct {
 	Scopes []string `json:"scopes,omitempty"`
 }
 
+// BootstrapToken lets a new node authenticate to the master until the token expires, with only the permissions
+// the bootstrap policy grants to bootstrap tokens.  The token is named by the hash of its secret.
+type BootstrapToken struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	// ExpiresIn is the seconds from CreationTime before this token expires.
+	ExpiresIn int64 `json:"expiresIn,omitempty"`
+
+	// Description is what the token is for, for example the nodes that join the cluster with it.
+	Description string `json:"description,omitempty"`
+
+	// Secret is what clients authenticate with.  It is generated if it is not set on create, and only
+	// returned by the create, since only its hash is stored.
+	Secret string `json:"secret,omitempty"`
+}
+
+// RouterToken lets a router authenticate to the master as a router user that may only read the routes and
+// endpoints, and record the status of the routes, of the namespaces of its shard.  The token is named by the
+// hash of its secret.
+type RouterToken struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	// RouterName is the name of the router the token authenticates, which it reports in route status.
+	RouterName string `json:"routerName"`
+
+	// Namespaces are the namespaces of the shard of the router, the only namespaces the token gives access to.
+	Namespaces []string `json:"namespaces"`
+
+	// ExpiresIn is the seconds from CreationTime before this token expires, it does not expire if zero.
+	ExpiresIn int64 `json:"expiresIn,omitempty"`
+
+	// Secret is what clients authenticate with.  It is generated if it is not set on create, and only
+	// returned by the create, since only its hash is stored.
+	Secret string `json:"secret,omitempty"`
+}
+
 type OAuthAccessTokenList struct {
 	kapi.TypeMeta `json:",inline"`
 	kapi.ListMeta `json:"metadata,omitempty"`
@@ -115,3 +157,15 @@ type OAuthClientAuthorizationList struct {
 	kapi.ListMeta `json:"metadata,omitempty"`
 	Items         []OAuthClientAuthorization `json:"items"`
 }
+
+type BootstrapTokenList struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
+	Items         []BootstrapToken `json:"items"`
+}
+
+type RouterTokenList struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
+	Items         []RouterToken `json:"items"`
+}
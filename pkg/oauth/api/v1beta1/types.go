@@ -2,6 +2,7 @@ package v1beta1
 
 import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api/v1beta3"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
 type OAuthAccessToken struct {
@@ -9,28 +10,31 @@ type OAuthAccessToken struct {
 	kapi.ObjectMeta `json:"metadata,omitempty"`
 
 	// ClientName references the client that created this token.
-	ClientName string `json:"clientName,omitempty"`
+	ClientName string `json:"clientName,omitempty" description:"ClientName references the client that created this token."`
 
 	// ExpiresIn is the seconds from CreationTime before this token expires.
-	ExpiresIn int64 `json:"expiresIn,omitempty"`
+	ExpiresIn int64 `json:"expiresIn,omitempty" description:"ExpiresIn is the seconds from CreationTime before this token expires."`
 
 	// Scopes is an array of the requested scopes.
-	Scopes []string `json:"scopes,omitempty"`
+	Scopes []string `json:"scopes,omitempty" description:"Scopes is an array of the requested scopes."`
 
 	// RedirectURI is the redirection associated with the token.
-	RedirectURI string `json:"redirectURI,omitempty"`
+	RedirectURI string `json:"redirectURI,omitempty" description:"RedirectURI is the redirection associated with the token."`
 
 	// UserName is the user name associated with this token
-	UserName string `json:"userName,omitempty"`
+	UserName string `json:"userName,omitempty" description:"UserName is the user name associated with this token"`
 
 	// UserUID is the unique UID associated with this token
-	UserUID string `json:"userUID,omitempty"`
+	UserUID string `json:"userUID,omitempty" description:"UserUID is the unique UID associated with this token"`
 
 	// AuthorizeToken contains the token that authorized this token
-	AuthorizeToken string `json:"authorizeToken,omitempty"`
+	AuthorizeToken string `json:"authorizeToken,omitempty" description:"AuthorizeToken contains the token that authorized this token"`
 
 	// RefreshToken is the value by which this token can be renewed. Can be blank.
-	RefreshToken string `json:"refreshToken,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty" description:"RefreshToken is the value by which this token can be renewed. Can be blank."`
+
+	// LastUsedTimestamp is when the token was last used to authenticate a request, updated at most once a minute
+	LastUsedTimestamp util.Time `json:"lastUsedTimestamp,omitempty" description:"LastUsedTimestamp is when the token was last used to authenticate a request, updated at most once a minute"`
 }
 
 type OAuthAuthorizeToken struct {
@@ -38,26 +42,26 @@ type OAuthAuthorizeToken struct {
 	kapi.ObjectMeta `json:"metadata,omitempty"`
 
 	// ClientName references the client that created this token.
-	ClientName string `json:"clientName,omitempty"`
+	ClientName string `json:"clientName,omitempty" description:"ClientName references the client that created this token."`
 
 	// ExpiresIn is the seconds from CreationTime before this token expires.
-	ExpiresIn int64 `json:"expiresIn,omitempty"`
+	ExpiresIn int64 `json:"expiresIn,omitempty" description:"ExpiresIn is the seconds from CreationTime before this token expires."`
 
 	// Scopes is an array of the requested scopes.
-	Scopes []string `json:"scopes,omitempty"`
+	Scopes []string `json:"scopes,omitempty" description:"Scopes is an array of the requested scopes."`
 
 	// RedirectURI is the redirection associated with the token.
-	RedirectURI string `json:"redirectURI,omitempty"`
+	RedirectURI string `json:"redirectURI,omitempty" description:"RedirectURI is the redirection associated with the token."`
 
 	// State data from request
-	State string `json:"state,omitempty"`
+	State string `json:"state,omitempty" description:"State data from request"`
 
 	// UserName is the user name associated with this token
-	UserName string `json:"userName,omitempty"`
+	UserName string `json:"userName,omitempty" description:"UserName is the user name associated with this token"`
 
 	// UserUID is the unique UID associated with this token. UserUID and UserName must both match
 	// for this token to be valid.
-	UserUID string `json:"userUID,omitempty"`
+	UserUID string `json:"userUID,omitempty" description:"UserUID is the unique UID associated with this token. UserUID and UserName must both match for this token to be valid."`
 }
 
 type OAuthClient struct {
@@ -65,13 +69,13 @@ type OAuthClient struct {
 	kapi.ObjectMeta `json:"metadata,omitempty"`
 
 	// Secret is the unique secret associated with a client
-	Secret string `json:"secret,omitempty"`
+	Secret string `json:"secret,omitempty" description:"Secret is the unique secret associated with a client"`
 
 	// RespondWithChallenges indicates whether the client wants authentication needed responses made in the form of challenges instead of redirects
-	RespondWithChallenges bool `json:"respondWithChallenges,omitempty"`
+	RespondWithChallenges bool `json:"respondWithChallenges,omitempty" description:"RespondWithChallenges indicates whether the client wants authentication needed responses made in the form of challenges instead of redirects"`
 
 	// RedirectURIs is the valid redirection URIs associated with a client
-	RedirectURIs []string `json:"redirectURIs,omitempty"`
+	RedirectURIs []string `json:"redirectURIs,omitempty" description:"RedirectURIs is the valid redirection URIs associated with a client"`
 }
 
 type OAuthClientAuthorization struct {
@@ -79,17 +83,17 @@ type OAuthClientAuthorization struct {
 	kapi.ObjectMeta `json:"metadata,omitempty"`
 
 	// ClientName references the client that created this authorization
-	ClientName string `json:"clientName,omitempty"`
+	ClientName string `json:"clientName,omitempty" description:"ClientName references the client that created this authorization"`
 
 	// UserName is the user name that authorized this client
-	UserName string `json:"userName,omitempty"`
+	UserName string `json:"userName,omitempty" description:"UserName is the user name that authorized this client"`
 
 	// UserUID is the unique UID associated with this authorization. UserUID and UserName
 	// must both match for this authorization to be valid.
-	UserUID string `json:"userUID,omitempty"`
+	UserUID string `json:"userUID,omitempty" description:"UserUID is the unique UID associated with this authorization. UserUID and UserName must both match for this authorization to be valid."`
 
 	// Scopes is an array of the granted scopes.
-	Scopes []string `json:"scopes,omitempty"`
+	Scopes []string `json:"scopes,omitempty" description:"Scopes is an array of the granted scopes."`
 }
 
 type OAuthAccessTokenList struct {
@@ -115,3 +119,53 @@ type OAuthClientAuthorizationList struct {
 	kapi.ListMeta `json:"metadata,omitempty"`
 	Items         []OAuthClientAuthorization `json:"items"`
 }
+
+// BootstrapToken lets a new node authenticate to the master until the token expires, with only the permissions
+// the bootstrap policy grants to bootstrap tokens.  The token is named by the hash of its secret.
+type BootstrapToken struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	// ExpiresIn is the seconds from CreationTime before this token expires.
+	ExpiresIn int64 `json:"expiresIn,omitempty" description:"ExpiresIn is the seconds from CreationTime before this token expires."`
+
+	// Description is what the token is for, for example the nodes that join the cluster with it.
+	Description string `json:"description,omitempty" description:"Description is what the token is for, for example the nodes that join the cluster with it."`
+
+	// Secret is what clients authenticate with.  It is generated if it is not set on create, and only
+	// returned by the create, since only its hash is stored.
+	Secret string `json:"secret,omitempty" description:"Secret is what clients authenticate with, only returned when the token is created. Generated if not set on create."`
+}
+
+// RouterToken lets a router authenticate to the master as a router user that may only read the routes and
+// endpoints, and record the status of the routes, of the namespaces of its shard.  The token is named by the
+// hash of its secret.
+type RouterToken struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	// RouterName is the name of the router the token authenticates, which it reports in route status.
+	RouterName string `json:"routerName" description:"RouterName is the name of the router the token authenticates, which it reports in route status."`
+
+	// Namespaces are the namespaces of the shard of the router, the only namespaces the token gives access to.
+	Namespaces []string `json:"namespaces" description:"Namespaces are the namespaces of the shard of the router, the only namespaces the token gives access to."`
+
+	// ExpiresIn is the seconds from CreationTime before this token expires, it does not expire if zero.
+	ExpiresIn int64 `json:"expiresIn,omitempty" description:"ExpiresIn is the seconds from CreationTime before this token expires, it does not expire if zero."`
+
+	// Secret is what clients authenticate with.  It is generated if it is not set on create, and only
+	// returned by the create, since only its hash is stored.
+	Secret string `json:"secret,omitempty" description:"Secret is what clients authenticate with, only returned when the token is created. Generated if not set on create."`
+}
+
+type BootstrapTokenList struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
+	Items         []BootstrapToken `json:"items"`
+}
+
+type RouterTokenList struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
+	Items         []RouterToken `json:"items"`
+}
@@ -0,0 +1,35 @@
+package v1beta2
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+func init() {
+	api.Scheme.AddKnownTypes("v1beta2",
+		&OAuthAccessToken{},
+		&OAuthAccessTokenList{},
+		&OAuthAuthorizeToken{},
+		&OAuthAuthorizeTokenList{},
+		&OAuthClient{},
+		&OAuthClientList{},
+		&OAuthClientAuthorization{},
+		&OAuthClientAuthorizationList{},
+		&BootstrapToken{},
+		&BootstrapTokenList{},
+		&RouterToken{},
+		&RouterTokenList{},
+	)
+}
+
+func (*OAuthAccessToken) IsAnAPIObject()             {}
+func (*OAuthAuthorizeToken) IsAnAPIObject()          {}
+func (*OAuthClient) IsAnAPIObject()                  {}
+func (*OAuthAccessTokenList) IsAnAPIObject()         {}
+func (*OAuthAuthorizeTokenList) IsAnAPIObject()      {}
+func (*OAuthClientList) IsAnAPIObject()              {}
+func (*OAuthClientAuthorization) IsAnAPIObject()     {}
+func (*OAuthClientAuthorizationList) IsAnAPIObject() {}
+func (*BootstrapToken) IsAnAPIObject()               {}
+func (*BootstrapTokenList) IsAnAPIObject()           {}
+func (*RouterToken) IsAnAPIObject()                  {}
+func (*RouterTokenList) IsAnAPIObject()              {}
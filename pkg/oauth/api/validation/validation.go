@@ -1,6 +1,8 @@
 package validation
 
 import (
+	"fmt"
+
 	errs "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/openshift/origin/pkg/oauth/api"
@@ -99,6 +101,63 @@ func ValidateClientAuthorizationUpdate(newAuth *api.OAuthClientAuthorization, ol
 	return allErrs
 }
 
+// ValidateBootstrapToken validates a bootstrap token before it is named by the hash of its secret.
+func ValidateBootstrapToken(token *api.BootstrapToken) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, validateTokenSecret(token.Name, token.Secret)...)
+	if token.ExpiresIn <= 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("expiresIn", token.ExpiresIn, "must be positive"))
+	}
+	if len(token.Namespace) != 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("namespace", token.Namespace, "namespace must be empty"))
+	}
+	allErrs = append(allErrs, validateLabels(token.Labels)...)
+	return allErrs
+}
+
+// validateTokenSecret validates the secret of a bootstrap or router token, which names the token.
+func validateTokenSecret(name, secret string) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if len(name) != 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("name", name, "the name is set from the hash of the secret"))
+	}
+	if len(secret) < api.MinTokenSecretLength {
+		allErrs = append(allErrs, errs.NewFieldInvalid("secret", "", fmt.Sprintf("must be at least %d characters", api.MinTokenSecretLength)))
+	}
+	return allErrs
+}
+
+func ValidateRouterToken(token *api.RouterToken) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, validateTokenSecret(token.Name, token.Secret)...)
+	if len(token.RouterName) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("routerName", token.RouterName))
+	} else if !util.IsDNS1123Subdomain(token.RouterName) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("routerName", token.RouterName, "must be a DNS subdomain"))
+	}
+	if len(token.Namespaces) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("namespaces", token.Namespaces))
+	}
+	seen := util.StringSet{}
+	for i, namespace := range token.Namespaces {
+		switch {
+		case !util.IsDNS1123Label(namespace):
+			allErrs = append(allErrs, errs.NewFieldInvalid(fmt.Sprintf("namespaces[%d]", i), namespace, "must be a namespace name"))
+		case seen.Has(namespace):
+			allErrs = append(allErrs, errs.NewFieldDuplicate(fmt.Sprintf("namespaces[%d]", i), namespace))
+		}
+		seen.Insert(namespace)
+	}
+	if token.ExpiresIn < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("expiresIn", token.ExpiresIn, "must not be negative"))
+	}
+	if len(token.Namespace) != 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("namespace", token.Namespace, "namespace must be empty"))
+	}
+	allErrs = append(allErrs, validateLabels(token.Labels)...)
+	return allErrs
+}
+
 func validateLabels(labels map[string]string) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
 	for k := range labels {
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -234,3 +235,130 @@ func TestValidateAuthorizeTokens(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateBootstrapTokens(t *testing.T) {
+	secret := strings.Repeat("s", oapi.MinTokenSecretLength)
+	errs := ValidateBootstrapToken(&oapi.BootstrapToken{
+		Secret:    secret,
+		ExpiresIn: 3600,
+	})
+	if len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+
+	errorCases := map[string]struct {
+		Token oapi.BootstrapToken
+		T     errors.ValidationErrorType
+		F     string
+	}{
+		"short secret": {
+			Token: oapi.BootstrapToken{Secret: "tokenName", ExpiresIn: 3600},
+			T:     errors.ValidationErrorTypeInvalid,
+			F:     "secret",
+		},
+		"chosen name": {
+			Token: oapi.BootstrapToken{ObjectMeta: api.ObjectMeta{Name: "tokenName"}, Secret: secret, ExpiresIn: 3600},
+			T:     errors.ValidationErrorTypeInvalid,
+			F:     "name",
+		},
+		"no expiry": {
+			Token: oapi.BootstrapToken{Secret: secret},
+			T:     errors.ValidationErrorTypeInvalid,
+			F:     "expiresIn",
+		},
+		"disallowed namespace": {
+			Token: oapi.BootstrapToken{ObjectMeta: api.ObjectMeta{Namespace: "foo"}, Secret: secret, ExpiresIn: 3600},
+			T:     errors.ValidationErrorTypeInvalid,
+			F:     "namespace",
+		},
+	}
+	for k, v := range errorCases {
+		errs := ValidateBootstrapToken(&v.Token)
+		if len(errs) == 0 {
+			t.Errorf("expected failure %s for %v", k, v.Token)
+			continue
+		}
+		for i := range errs {
+			if errs[i].(*errors.ValidationError).Type != v.T {
+				t.Errorf("%s: expected errors to have type %s: %v", k, v.T, errs[i])
+			}
+			if errs[i].(*errors.ValidationError).Field != v.F {
+				t.Errorf("%s: expected errors to have field %s: %v", k, v.F, errs[i])
+			}
+		}
+	}
+}
+
+func TestValidateRouterTokens(t *testing.T) {
+	secret := strings.Repeat("s", oapi.MinTokenSecretLength)
+	errs := ValidateRouterToken(&oapi.RouterToken{
+		Secret:     secret,
+		RouterName: "shard1",
+		Namespaces: []string{"foo", "bar"},
+	})
+	if len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+
+	errorCases := map[string]struct {
+		Token oapi.RouterToken
+		T     errors.ValidationErrorType
+		F     string
+	}{
+		"short secret": {
+			Token: oapi.RouterToken{Secret: "tokenName", RouterName: "shard1", Namespaces: []string{"foo"}},
+			T:     errors.ValidationErrorTypeInvalid,
+			F:     "secret",
+		},
+		"chosen name": {
+			Token: oapi.RouterToken{ObjectMeta: api.ObjectMeta{Name: "tokenName"}, Secret: secret, RouterName: "shard1", Namespaces: []string{"foo"}},
+			T:     errors.ValidationErrorTypeInvalid,
+			F:     "name",
+		},
+		"no router name": {
+			Token: oapi.RouterToken{Secret: secret, Namespaces: []string{"foo"}},
+			T:     errors.ValidationErrorTypeRequired,
+			F:     "routerName",
+		},
+		"no namespaces": {
+			Token: oapi.RouterToken{Secret: secret, RouterName: "shard1"},
+			T:     errors.ValidationErrorTypeRequired,
+			F:     "namespaces",
+		},
+		"invalid namespace": {
+			Token: oapi.RouterToken{Secret: secret, RouterName: "shard1", Namespaces: []string{"foo", "Bar"}},
+			T:     errors.ValidationErrorTypeInvalid,
+			F:     "namespaces[1]",
+		},
+		"duplicate namespace": {
+			Token: oapi.RouterToken{Secret: secret, RouterName: "shard1", Namespaces: []string{"foo", "foo"}},
+			T:     errors.ValidationErrorTypeDuplicate,
+			F:     "namespaces[1]",
+		},
+		"negative expiry": {
+			Token: oapi.RouterToken{Secret: secret, RouterName: "shard1", Namespaces: []string{"foo"}, ExpiresIn: -1},
+			T:     errors.ValidationErrorTypeInvalid,
+			F:     "expiresIn",
+		},
+		"disallowed namespace": {
+			Token: oapi.RouterToken{ObjectMeta: api.ObjectMeta{Namespace: "foo"}, Secret: secret, RouterName: "shard1", Namespaces: []string{"foo"}},
+			T:     errors.ValidationErrorTypeInvalid,
+			F:     "namespace",
+		},
+	}
+	for k, v := range errorCases {
+		errs := ValidateRouterToken(&v.Token)
+		if len(errs) == 0 {
+			t.Errorf("expected failure %s for %v", k, v.Token)
+			continue
+		}
+		for i := range errs {
+			if errs[i].(*errors.ValidationError).Type != v.T {
+				t.Errorf("%s: expected errors to have type %s: %v", k, v.T, errs[i])
+			}
+			if errs[i].(*errors.ValidationError).Field != v.F {
+				t.Errorf("%s: expected errors to have field %s: %v", k, v.F, errs[i])
+			}
+		}
+	}
+}
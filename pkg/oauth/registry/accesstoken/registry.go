@@ -2,6 +2,8 @@ package accesstoken
 
 import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	"github.com/openshift/origin/pkg/oauth/api"
 )
@@ -16,6 +18,10 @@ type Registry interface {
 	CreateAccessToken(token *api.OAuthAccessToken) error
 	// UpdateAccessToken updates an access token.
 	UpdateAccessToken(token *api.OAuthAccessToken) error
+	// RecordAccessTokenUse records that an access token was used at the given time.
+	RecordAccessTokenUse(name string, at util.Time) error
 	// DeleteAccessToken deletes an access token.
 	DeleteAccessToken(name string) error
+	// WatchAccessTokens watches for new/modified/deleted access tokens.
+	WatchAccessTokens(label, field labels.Selector, resourceVersion string) (watch.Interface, error)
 }
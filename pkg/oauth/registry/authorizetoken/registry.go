@@ -2,6 +2,7 @@ package authorizetoken
 
 import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	"github.com/openshift/origin/pkg/oauth/api"
 )
@@ -18,4 +19,6 @@ type Registry interface {
 	UpdateAuthorizeToken(token *api.OAuthAuthorizeToken) error
 	// DeleteAuthorizeToken deletes an authorize token.
 	DeleteAuthorizeToken(name string) error
+	// WatchAuthorizeTokens watches for new/modified/deleted authorize tokens.
+	WatchAuthorizeTokens(label, field labels.Selector, resourceVersion string) (watch.Interface, error)
 }
@@ -0,0 +1,22 @@
+package bootstraptoken
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	"github.com/openshift/origin/pkg/oauth/api"
+)
+
+// Registry is an interface for things that know how to store BootstrapToken objects.
+type Registry interface {
+	// ListBootstrapTokens obtains a list of bootstrap tokens that match a selector.
+	ListBootstrapTokens(selector labels.Selector) (*api.BootstrapTokenList, error)
+	// GetBootstrapToken retrieves a specific bootstrap token.
+	GetBootstrapToken(name string) (*api.BootstrapToken, error)
+	// CreateBootstrapToken creates a new bootstrap token.
+	CreateBootstrapToken(token *api.BootstrapToken) error
+	// DeleteBootstrapToken deletes a bootstrap token.
+	DeleteBootstrapToken(name string) error
+	// WatchBootstrapTokens watches for new/modified/deleted bootstrap tokens.
+	WatchBootstrapTokens(label, field labels.Selector, resourceVersion string) (watch.Interface, error)
+}
@@ -0,0 +1,109 @@
+package bootstraptoken
+
+import (
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	"github.com/openshift/origin/pkg/oauth/api"
+	"github.com/openshift/origin/pkg/oauth/api/validation"
+)
+
+// DefaultExpiresIn is how long a bootstrap token created without an expiry lasts, in seconds.
+const DefaultExpiresIn = 24 * 60 * 60
+
+// REST implements the RESTStorage interface in terms of a Registry.
+type REST struct {
+	registry Registry
+}
+
+// NewREST returns a new REST.
+func NewREST(registry Registry) apiserver.RESTStorage {
+	return &REST{registry}
+}
+
+// New returns a new BootstrapToken for use with Create.
+func (s *REST) New() runtime.Object {
+	return &api.BootstrapToken{}
+}
+
+func (*REST) NewList() runtime.Object {
+	return &api.BootstrapTokenList{}
+}
+
+// Get retrieves a BootstrapToken by id.
+func (s *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
+	token, err := s.registry.GetBootstrapToken(id)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// List retrieves a list of BootstrapTokens that match selector.
+func (s *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime.Object, error) {
+	tokens, err := s.registry.ListBootstrapTokens(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Create registers the given BootstrapToken.  A token without a secret is given a random one, and a token
+// without an expiry lasts DefaultExpiresIn seconds.  The token is named by the hash of its secret, and the
+// secret is only returned by the create.
+func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	token, ok := obj.(*api.BootstrapToken)
+	if !ok {
+		return nil, fmt.Errorf("not a bootstrap token: %#v", obj)
+	}
+
+	if len(token.Secret) == 0 {
+		secret, err := api.NewTokenSecret()
+		if err != nil {
+			return nil, err
+		}
+		token.Secret = secret
+	}
+	if token.ExpiresIn == 0 {
+		token.ExpiresIn = DefaultExpiresIn
+	}
+	kapi.FillObjectMetaSystemFields(ctx, &token.ObjectMeta)
+
+	if errs := validation.ValidateBootstrapToken(token); len(errs) > 0 {
+		return nil, kerrors.NewInvalid("bootstrapToken", token.Name, errs)
+	}
+	secret := token.Secret
+	token.Name, token.Secret = api.TokenNameForSecret(secret), ""
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		if err := s.registry.CreateBootstrapToken(token); err != nil {
+			return nil, err
+		}
+		created, err := s.registry.GetBootstrapToken(token.Name)
+		if err != nil {
+			return nil, err
+		}
+		created.Secret = secret
+		return created, nil
+	}), nil
+}
+
+// Watch returns BootstrapToken events via a watch.Interface.
+// It implements apiserver.ResourceWatcher.
+func (s *REST) Watch(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return s.registry.WatchBootstrapTokens(label, field, resourceVersion)
+}
+
+// Delete asynchronously deletes a BootstrapToken specified by its id.
+func (s *REST) Delete(ctx kapi.Context, id string) (<-chan apiserver.RESTResult, error) {
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		return &kapi.Status{Status: kapi.StatusSuccess}, s.registry.DeleteBootstrapToken(id)
+	}), nil
+}
@@ -0,0 +1,145 @@
+package bootstraptoken
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	oapi "github.com/openshift/origin/pkg/oauth/api"
+	"github.com/openshift/origin/pkg/oauth/registry/test"
+)
+
+func TestCreateValidationError(t *testing.T) {
+	registry := test.BootstrapTokenRegistry{}
+	storage := REST{
+		registry: &registry,
+	}
+	token := &oapi.BootstrapToken{
+		ExpiresIn: -1,
+	}
+
+	ctx := api.NewContext()
+	_, err := storage.Create(ctx, token)
+	if err == nil {
+		t.Errorf("Expected validation error")
+	}
+}
+
+func TestCreateDefaults(t *testing.T) {
+	registry := test.BootstrapTokenRegistry{}
+	storage := REST{
+		registry: &registry,
+	}
+
+	ctx := api.NewContext()
+	channel, err := storage.Create(ctx, &oapi.BootstrapToken{Description: "nodes"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var returned *oapi.BootstrapToken
+	select {
+	case r := <-channel:
+		ok := false
+		if returned, ok = r.Object.(*oapi.BootstrapToken); !ok {
+			t.Fatalf("Got back unexpected result: %#v", r.Object)
+		}
+	case <-time.After(time.Millisecond * 100):
+		t.Fatal("Unexpected timeout from async channel")
+	}
+	if len(returned.Secret) < oapi.MinTokenSecretLength || returned.Name != oapi.TokenNameForSecret(returned.Secret) {
+		t.Errorf("Expected a random secret that names the token, got %#v", returned)
+	}
+
+	created := registry.CreatedBootstrapToken
+	if created == nil {
+		t.Fatal("Expected the token to be stored")
+	}
+	if len(created.Secret) != 0 {
+		t.Errorf("Expected the secret not to be stored, got %q", created.Secret)
+	}
+	if created.ExpiresIn != DefaultExpiresIn {
+		t.Errorf("Expected the default expiry, got %d", created.ExpiresIn)
+	}
+
+	if channel, err = storage.Create(ctx, &oapi.BootstrapToken{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-channel
+	if registry.CreatedBootstrapToken.Name == created.Name {
+		t.Errorf("Expected every token to have a name of its own")
+	}
+}
+
+func TestCreateChosenSecret(t *testing.T) {
+	registry := test.BootstrapTokenRegistry{}
+	storage := REST{
+		registry: &registry,
+	}
+
+	ctx := api.NewContext()
+	if _, err := storage.Create(ctx, &oapi.BootstrapToken{Secret: "guessable"}); err == nil {
+		t.Errorf("Expected a short secret to be rejected")
+	}
+	if _, err := storage.Create(ctx, &oapi.BootstrapToken{ObjectMeta: api.ObjectMeta{Name: "tokenName"}}); err == nil {
+		t.Errorf("Expected a chosen name to be rejected")
+	}
+
+	secret := strings.Repeat("s", oapi.MinTokenSecretLength)
+	channel, err := storage.Create(ctx, &oapi.BootstrapToken{Secret: secret})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-channel
+	if name := registry.CreatedBootstrapToken.Name; name != oapi.TokenNameForSecret(secret) {
+		t.Errorf("Expected the token to be named by the hash of its secret, got %q", name)
+	}
+}
+
+func TestCreateStorageError(t *testing.T) {
+	registry := test.BootstrapTokenRegistry{
+		Err: errors.New("Sample Error"),
+	}
+	storage := REST{
+		registry: &registry,
+	}
+
+	ctx := api.NewContext()
+	channel, err := storage.Create(ctx, &oapi.BootstrapToken{ExpiresIn: 60})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case r := <-channel:
+		if status, ok := r.Object.(*api.Status); !ok || status.Message != registry.Err.Error() {
+			t.Errorf("Got back unexpected result: %#v", r.Object)
+		}
+	case <-time.After(time.Millisecond * 100):
+		t.Error("Unexpected timeout from async channel")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	registry := test.BootstrapTokenRegistry{}
+	storage := REST{
+		registry: &registry,
+	}
+
+	ctx := api.NewContext()
+	channel, err := storage.Delete(ctx, "tokenName")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case r := <-channel:
+		if status, ok := r.Object.(*api.Status); !ok || status.Status != api.StatusSuccess {
+			t.Errorf("Got back unexpected result: %#v", r.Object)
+		}
+	case <-time.After(time.Millisecond * 100):
+		t.Error("Unexpected timeout from async channel")
+	}
+	if registry.DeletedBootstrapTokenName != "tokenName" {
+		t.Errorf("Expected the token to be deleted, got %q", registry.DeletedBootstrapTokenName)
+	}
+}
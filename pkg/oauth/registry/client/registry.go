@@ -2,6 +2,7 @@ package client
 
 import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	"github.com/openshift/origin/pkg/oauth/api"
 )
@@ -18,4 +19,6 @@ type Registry interface {
 	UpdateClient(client *api.OAuthClient) error
 	// DeleteClient deletes an client.
 	DeleteClient(name string) error
+	// WatchClients watches for new/modified/deleted clients.
+	WatchClients(label, field labels.Selector, resourceVersion string) (watch.Interface, error)
 }
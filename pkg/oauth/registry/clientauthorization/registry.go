@@ -2,6 +2,7 @@ package clientauthorization
 
 import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	"github.com/openshift/origin/pkg/oauth/api"
 )
@@ -14,4 +15,6 @@ type Registry interface {
 	CreateClientAuthorization(token *api.OAuthClientAuthorization) error
 	UpdateClientAuthorization(token *api.OAuthClientAuthorization) error
 	DeleteClientAuthorization(name string) error
+	// WatchClientAuthorizations watches for new/modified/deleted client authorizations.
+	WatchClientAuthorizations(label, field labels.Selector, resourceVersion string) (watch.Interface, error)
 }
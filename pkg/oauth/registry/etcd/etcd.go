@@ -5,13 +5,21 @@ import (
 	"fmt"
 	"path"
 
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	etcderrs "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors/etcd"
+	kmeta "github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/golang/glog"
+
 	"github.com/openshift/origin/pkg/oauth/api"
+	etcdutil "github.com/openshift/origin/pkg/util/etcd"
 )
 
-// Etcd implements the AccessToken, AuthorizeToken, and Client registries backed by etcd.
+// Etcd implements the AccessToken, AuthorizeToken, Client, BootstrapToken, and RouterToken registries backed by etcd.
 type Etcd struct {
 	tools.EtcdHelper
 }
@@ -28,11 +36,15 @@ const (
 	OAuthAuthorizeTokenPath      = "/registry/oauth/authorizeTokens"
 	OAuthClientPath              = "/registry/oauth/clients"
 	OAuthClientAuthorizationPath = "/registry/oauth/clientAuthorizations"
+	BootstrapTokenPath           = "/registry/oauth/bootstrapTokens"
+	RouterTokenPath              = "/registry/oauth/routerTokens"
 
 	OAuthAccessTokenType         = "oauthAccessToken"
 	OAuthAuthorizeTokenType      = "oauthAuthorizeToken"
 	OAuthClientType              = "oauthClientType"
 	OAuthClientAuthorizationType = "oauthClientAuthorization"
+	BootstrapTokenType           = "bootstrapToken"
+	RouterTokenType              = "routerToken"
 )
 
 func makeAccessTokenKey(name string) string {
@@ -51,6 +63,14 @@ func makeClientAuthorizationKey(name string) string {
 	return path.Join(OAuthClientAuthorizationPath, name)
 }
 
+func makeBootstrapTokenKey(name string) string {
+	return path.Join(BootstrapTokenPath, name)
+}
+
+func makeRouterTokenKey(name string) string {
+	return path.Join(RouterTokenPath, name)
+}
+
 func (r *Etcd) GetAccessToken(name string) (token *api.OAuthAccessToken, err error) {
 	token = &api.OAuthAccessToken{}
 	err = etcderrs.InterpretGetError(r.ExtractObj(makeAccessTokenKey(name), token, false), OAuthAccessTokenType, name)
@@ -82,6 +102,31 @@ func (r *Etcd) UpdateAccessToken(*api.OAuthAccessToken) error {
 	return errors.New("not supported")
 }
 
+// errTokenUnchanged stops an atomic update of a token that needs no write.
+var errTokenUnchanged = errors.New("the token is unchanged")
+
+// RecordAccessTokenUse sets the last use of the token to at, unless a later use is already recorded.
+func (r *Etcd) RecordAccessTokenUse(name string, at util.Time) error {
+	err := r.AtomicUpdate(makeAccessTokenKey(name), &api.OAuthAccessToken{}, func(in runtime.Object) (runtime.Object, error) {
+		token := in.(*api.OAuthAccessToken)
+		if len(token.Name) == 0 {
+			return nil, kerrors.NewNotFound(OAuthAccessTokenType, name)
+		}
+		if !token.LastUsedTimestamp.Before(at.Time) {
+			return nil, errTokenUnchanged
+		}
+		token.LastUsedTimestamp = at
+		return token, nil
+	})
+	switch {
+	case err == errTokenUnchanged:
+		return nil
+	case kerrors.IsNotFound(err):
+		return err
+	}
+	return etcderrs.InterpretUpdateError(err, OAuthAccessTokenType, name)
+}
+
 func (r *Etcd) DeleteAccessToken(name string) error {
 	key := makeAccessTokenKey(name)
 	err := etcderrs.InterpretDeleteError(r.Delete(key, false), OAuthAccessTokenType, name)
@@ -180,8 +225,7 @@ func (r *Etcd) CreateClientAuthorization(client *api.OAuthClientAuthorization) e
 }
 
 func (r *Etcd) UpdateClientAuthorization(client *api.OAuthClientAuthorization) error {
-	err := etcderrs.InterpretUpdateError(r.SetObj(makeClientAuthorizationKey(client.Name), client), OAuthClientAuthorizationType, client.Name)
-	return err
+	return etcdutil.UpdateObj(r.EtcdHelper, makeClientAuthorizationKey(client.Name), client, OAuthClientAuthorizationType, client.Name)
 }
 
 func (r *Etcd) DeleteClientAuthorization(name string) error {
@@ -189,3 +233,126 @@ func (r *Etcd) DeleteClientAuthorization(name string) error {
 	err := etcderrs.InterpretDeleteError(r.Delete(key, false), OAuthClientAuthorizationType, name)
 	return err
 }
+
+func (r *Etcd) GetBootstrapToken(name string) (token *api.BootstrapToken, err error) {
+	token = &api.BootstrapToken{}
+	err = etcderrs.InterpretGetError(r.ExtractObj(makeBootstrapTokenKey(name), token, false), BootstrapTokenType, name)
+	return
+}
+
+func (r *Etcd) ListBootstrapTokens(selector labels.Selector) (*api.BootstrapTokenList, error) {
+	list := api.BootstrapTokenList{}
+	err := r.ExtractToList(BootstrapTokenPath, &list)
+	if err != nil && !tools.IsEtcdNotFound(err) {
+		return nil, err
+	}
+	filtered := []api.BootstrapToken{}
+	for _, item := range list.Items {
+		if selector.Matches(labels.Set(item.Labels)) {
+			filtered = append(filtered, item)
+		}
+	}
+	list.Items = filtered
+	return &list, nil
+}
+
+// CreateBootstrapToken stores token with a TTL of its expiry, so etcd removes it once it expires.
+func (r *Etcd) CreateBootstrapToken(token *api.BootstrapToken) error {
+	err := etcderrs.InterpretCreateError(r.CreateObj(makeBootstrapTokenKey(token.Name), token, uint64(token.ExpiresIn)), BootstrapTokenType, token.Name)
+	return err
+}
+
+func (r *Etcd) DeleteBootstrapToken(name string) error {
+	key := makeBootstrapTokenKey(name)
+	err := etcderrs.InterpretDeleteError(r.Delete(key, false), BootstrapTokenType, name)
+	return err
+}
+
+func (r *Etcd) GetRouterToken(name string) (token *api.RouterToken, err error) {
+	token = &api.RouterToken{}
+	err = etcderrs.InterpretGetError(r.ExtractObj(makeRouterTokenKey(name), token, false), RouterTokenType, name)
+	return
+}
+
+func (r *Etcd) ListRouterTokens(selector labels.Selector) (*api.RouterTokenList, error) {
+	list := api.RouterTokenList{}
+	err := r.ExtractToList(RouterTokenPath, &list)
+	if err != nil && !tools.IsEtcdNotFound(err) {
+		return nil, err
+	}
+	filtered := []api.RouterToken{}
+	for _, item := range list.Items {
+		if selector.Matches(labels.Set(item.Labels)) {
+			filtered = append(filtered, item)
+		}
+	}
+	list.Items = filtered
+	return &list, nil
+}
+
+// CreateRouterToken stores token with a TTL of its expiry, if it expires, so etcd removes it once it expires.
+func (r *Etcd) CreateRouterToken(token *api.RouterToken) error {
+	err := etcderrs.InterpretCreateError(r.CreateObj(makeRouterTokenKey(token.Name), token, uint64(token.ExpiresIn)), RouterTokenType, token.Name)
+	return err
+}
+
+func (r *Etcd) DeleteRouterToken(name string) error {
+	key := makeRouterTokenKey(name)
+	err := etcderrs.InterpretDeleteError(r.Delete(key, false), RouterTokenType, name)
+	return err
+}
+
+// WatchAccessTokens begins watching for new, changed, or deleted access tokens.
+func (r *Etcd) WatchAccessTokens(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return r.watch(OAuthAccessTokenPath, OAuthAccessTokenType, label, field, resourceVersion)
+}
+
+// WatchAuthorizeTokens begins watching for new, changed, or deleted authorize tokens.
+func (r *Etcd) WatchAuthorizeTokens(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return r.watch(OAuthAuthorizeTokenPath, OAuthAuthorizeTokenType, label, field, resourceVersion)
+}
+
+// WatchClients begins watching for new, changed, or deleted clients.
+func (r *Etcd) WatchClients(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return r.watch(OAuthClientPath, OAuthClientType, label, field, resourceVersion)
+}
+
+// WatchClientAuthorizations begins watching for new, changed, or deleted client authorizations.
+func (r *Etcd) WatchClientAuthorizations(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return r.watch(OAuthClientAuthorizationPath, OAuthClientAuthorizationType, label, field, resourceVersion)
+}
+
+// WatchBootstrapTokens begins watching for new, changed, or deleted bootstrap tokens.
+func (r *Etcd) WatchBootstrapTokens(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return r.watch(BootstrapTokenPath, BootstrapTokenType, label, field, resourceVersion)
+}
+
+// WatchRouterTokens begins watching for new, changed, or deleted router tokens.
+func (r *Etcd) WatchRouterTokens(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return r.watch(RouterTokenPath, RouterTokenType, label, field, resourceVersion)
+}
+
+// watch watches the objects under root that match label.  The only field selector supported is an exact
+// match on name, which watches a single object.
+func (r *Etcd) watch(root, kind string, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	version, err := tools.ParseWatchResourceVersion(resourceVersion, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	if name, found := field.RequiresExactMatch("name"); found {
+		return r.Watch(path.Join(root, name), version), nil
+	}
+
+	if field.Empty() {
+		return r.WatchList(root, version, func(obj runtime.Object) bool {
+			meta, err := kmeta.Accessor(obj)
+			if err != nil {
+				glog.Errorf("Unexpected object during %s watch: %#v", kind, obj)
+				return false
+			}
+			return label.Matches(labels.Set(meta.Labels()))
+		})
+	}
+	return nil, fmt.Errorf("only the 'name' and default (everything) field selectors are supported")
+}
@@ -3,6 +3,7 @@ package etcd
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
@@ -11,6 +12,8 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 	"github.com/coreos/go-etcd/etcd"
 	oapi "github.com/openshift/origin/pkg/oauth/api"
 	_ "github.com/openshift/origin/pkg/oauth/api/v1beta1"
@@ -139,6 +142,91 @@ func TestDeleteAccessToken(t *testing.T) {
 	}
 }
 
+func TestRecordAccessTokenUse(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	fakeClient.ExpectNotFoundGet(makeAccessTokenKey("missing"))
+	registry := NewTestEtcdRegistry(fakeClient)
+
+	used := util.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := registry.CreateAccessToken(&oapi.OAuthAccessToken{ObjectMeta: api.ObjectMeta{Name: "foo"}, LastUsedTimestamp: used}); err != nil {
+		t.Fatalf("unexpected error saving: %v", err)
+	}
+
+	if err := registry.RecordAccessTokenUse("foo", util.Date(2014, 1, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token, err := registry.GetAccessToken("foo"); err != nil || !token.LastUsedTimestamp.Equal(used.Time) {
+		t.Errorf("expected an earlier use not to be recorded, got %#v %v", token, err)
+	}
+
+	later := util.Date(2015, 2, 1, 0, 0, 0, 0, time.UTC)
+	if err := registry.RecordAccessTokenUse("foo", later); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token, err := registry.GetAccessToken("foo"); err != nil || !token.LastUsedTimestamp.Equal(later.Time) {
+		t.Errorf("expected the later use to be recorded, got %#v %v", token, err)
+	}
+
+	if err := registry.RecordAccessTokenUse("missing", later); !errors.IsNotFound(err) {
+		t.Errorf("expected a not found error for a missing token, got %v", err)
+	}
+}
+
+func TestCreateBootstrapToken(t *testing.T) {
+	token := &oapi.BootstrapToken{ObjectMeta: api.ObjectMeta{Name: "foo"}, ExpiresIn: 600}
+
+	fakeClient := tools.NewFakeEtcdClient(t)
+	registry := NewTestEtcdRegistry(fakeClient)
+	if err := registry.CreateBootstrapToken(token); err != nil {
+		t.Fatalf("unexpected error saving: %v", err)
+	}
+	if fakeClient.LastSetTTL != 600 {
+		t.Errorf("expected the token to be stored with a TTL of its expiry, got %d", fakeClient.LastSetTTL)
+	}
+	stored, err := registry.GetBootstrapToken(token.Name)
+	if err != nil {
+		t.Fatalf("unexpected error retrieving: %v", err)
+	}
+	if stored.Name != token.Name || stored.ExpiresIn != 600 {
+		t.Fatalf("stored token didn't match original token: %v", stored)
+	}
+
+	if err := registry.DeleteBootstrapToken("foo"); err != nil {
+		t.Fatalf("unexpected error deleting: %v", err)
+	}
+	if stored, err := registry.GetBootstrapToken("foo"); !errors.IsNotFound(err) {
+		t.Fatalf("token was retrieved after deleting: %v", stored)
+	}
+}
+
+func TestCreateRouterToken(t *testing.T) {
+	token := &oapi.RouterToken{ObjectMeta: api.ObjectMeta{Name: "foo"}, RouterName: "shard1", Namespaces: []string{"a", "b"}}
+
+	fakeClient := tools.NewFakeEtcdClient(t)
+	registry := NewTestEtcdRegistry(fakeClient)
+	if err := registry.CreateRouterToken(token); err != nil {
+		t.Fatalf("unexpected error saving: %v", err)
+	}
+	if fakeClient.LastSetTTL != 0 {
+		t.Errorf("expected a token without an expiry to be stored without a TTL, got %d", fakeClient.LastSetTTL)
+	}
+	stored, err := registry.GetRouterToken(token.Name)
+	if err != nil {
+		t.Fatalf("unexpected error retrieving: %v", err)
+	}
+	if stored.Name != token.Name || stored.RouterName != "shard1" || len(stored.Namespaces) != 2 {
+		t.Fatalf("stored token didn't match original token: %v", stored)
+	}
+
+	if err := registry.DeleteRouterToken("foo"); err != nil {
+		t.Fatalf("unexpected error deleting: %v", err)
+	}
+	if stored, err := registry.GetRouterToken("foo"); !errors.IsNotFound(err) {
+		t.Fatalf("token was retrieved after deleting: %v", stored)
+	}
+}
+
 func TestGetAuthorizeTokenNotFound(t *testing.T) {
 	key := makeAuthorizeTokenKey("foo")
 	fakeClient := tools.NewFakeEtcdClient(t)
@@ -556,3 +644,60 @@ func TestUpdateClientAuthorization(t *testing.T) {
 		t.Fatalf("client authorization was not updated: %v", updatedAuth)
 	}
 }
+
+func TestWatchClients(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	registry := NewTestEtcdRegistry(fakeClient)
+
+	selector := labels.SelectorFromSet(labels.Set{"app": "console"})
+	watching, err := registry.WatchClients(selector, labels.Everything(), "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fakeClient.WaitForWatchCompletion()
+
+	for _, client := range []*oapi.OAuthClient{
+		{ObjectMeta: api.ObjectMeta{Name: "cli", Labels: map[string]string{"app": "cli"}}},
+		{ObjectMeta: api.ObjectMeta{Name: "console", Labels: map[string]string{"app": "console"}}},
+	} {
+		fakeClient.WatchResponse <- &etcd.Response{
+			Action: "set",
+			Node:   &etcd.Node{Value: runtime.EncodeOrDie(v1beta1.Codec, client)},
+		}
+	}
+
+	event := <-watching.ResultChan()
+	if event.Type != watch.Added {
+		t.Errorf("expected add but got %s", event.Type)
+	}
+	if client, ok := event.Object.(*oapi.OAuthClient); !ok || client.Name != "console" {
+		t.Errorf("expected only the matching client, got %#v", event.Object)
+	}
+
+	fakeClient.WatchInjectError <- nil
+	if _, ok := <-watching.ResultChan(); ok {
+		t.Errorf("watching channel should be closed")
+	}
+	watching.Stop()
+}
+
+func TestWatchAccessTokensByName(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	registry := NewTestEtcdRegistry(fakeClient)
+
+	field := labels.SelectorFromSet(labels.Set{"name": "foo"})
+	watching, err := registry.WatchAccessTokens(labels.Everything(), field, "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fakeClient.WaitForWatchCompletion()
+	if fakeClient.WatchIndex != 2 {
+		t.Errorf("expected the watch to start after the resource version, got %d", fakeClient.WatchIndex)
+	}
+	watching.Stop()
+
+	field = labels.SelectorFromSet(labels.Set{"userName": "foo"})
+	if _, err := registry.WatchAccessTokens(labels.Everything(), field, "1"); err == nil {
+		t.Errorf("expected an error for an unsupported field selector")
+	}
+}
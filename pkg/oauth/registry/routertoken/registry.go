@@ -0,0 +1,22 @@
+package routertoken
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	"github.com/openshift/origin/pkg/oauth/api"
+)
+
+// Registry is an interface for things that know how to store RouterToken objects.
+type Registry interface {
+	// ListRouterTokens obtains a list of router tokens that match a selector.
+	ListRouterTokens(selector labels.Selector) (*api.RouterTokenList, error)
+	// GetRouterToken retrieves a specific router token.
+	GetRouterToken(name string) (*api.RouterToken, error)
+	// CreateRouterToken creates a new router token.
+	CreateRouterToken(token *api.RouterToken) error
+	// DeleteRouterToken deletes a router token.
+	DeleteRouterToken(name string) error
+	// WatchRouterTokens watches for new/modified/deleted router tokens.
+	WatchRouterTokens(label, field labels.Selector, resourceVersion string) (watch.Interface, error)
+}
@@ -0,0 +1,102 @@
+package routertoken
+
+import (
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	"github.com/openshift/origin/pkg/oauth/api"
+	"github.com/openshift/origin/pkg/oauth/api/validation"
+)
+
+// REST implements the RESTStorage interface in terms of a Registry.
+type REST struct {
+	registry Registry
+}
+
+// NewREST returns a new REST.
+func NewREST(registry Registry) apiserver.RESTStorage {
+	return &REST{registry}
+}
+
+// New returns a new RouterToken for use with Create.
+func (s *REST) New() runtime.Object {
+	return &api.RouterToken{}
+}
+
+func (*REST) NewList() runtime.Object {
+	return &api.RouterTokenList{}
+}
+
+// Get retrieves a RouterToken by id.
+func (s *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
+	token, err := s.registry.GetRouterToken(id)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// List retrieves a list of RouterTokens that match selector.
+func (s *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime.Object, error) {
+	tokens, err := s.registry.ListRouterTokens(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Create registers the given RouterToken under the hash of its secret, which is generated if it is not set.
+// Only the create returns the secret.
+func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	token, ok := obj.(*api.RouterToken)
+	if !ok {
+		return nil, fmt.Errorf("not a router token: %#v", obj)
+	}
+
+	if len(token.Secret) == 0 {
+		secret, err := api.NewTokenSecret()
+		if err != nil {
+			return nil, err
+		}
+		token.Secret = secret
+	}
+	kapi.FillObjectMetaSystemFields(ctx, &token.ObjectMeta)
+
+	if errs := validation.ValidateRouterToken(token); len(errs) > 0 {
+		return nil, kerrors.NewInvalid("routerToken", token.Name, errs)
+	}
+	secret := token.Secret
+	token.Name, token.Secret = api.TokenNameForSecret(secret), ""
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		if err := s.registry.CreateRouterToken(token); err != nil {
+			return nil, err
+		}
+		created, err := s.registry.GetRouterToken(token.Name)
+		if err != nil {
+			return nil, err
+		}
+		created.Secret = secret
+		return created, nil
+	}), nil
+}
+
+// Watch returns RouterToken events via a watch.Interface.
+// It implements apiserver.ResourceWatcher.
+func (s *REST) Watch(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return s.registry.WatchRouterTokens(label, field, resourceVersion)
+}
+
+// Delete asynchronously deletes a RouterToken specified by its id.
+func (s *REST) Delete(ctx kapi.Context, id string) (<-chan apiserver.RESTResult, error) {
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		return &kapi.Status{Status: kapi.StatusSuccess}, s.registry.DeleteRouterToken(id)
+	}), nil
+}
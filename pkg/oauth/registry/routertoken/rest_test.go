@@ -0,0 +1,111 @@
+package routertoken
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	oapi "github.com/openshift/origin/pkg/oauth/api"
+	"github.com/openshift/origin/pkg/oauth/registry/test"
+)
+
+func TestCreateValidationError(t *testing.T) {
+	registry := test.RouterTokenRegistry{}
+	storage := REST{
+		registry: &registry,
+	}
+	token := &oapi.RouterToken{
+		RouterName: "router",
+	}
+
+	ctx := api.NewContext()
+	_, err := storage.Create(ctx, token)
+	if err == nil {
+		t.Errorf("Expected validation error")
+	}
+}
+
+func TestCreateDefaults(t *testing.T) {
+	registry := test.RouterTokenRegistry{}
+	storage := REST{
+		registry: &registry,
+	}
+
+	ctx := api.NewContext()
+	channel, err := storage.Create(ctx, &oapi.RouterToken{RouterName: "router", Namespaces: []string{"shard1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var returned *oapi.RouterToken
+	select {
+	case r := <-channel:
+		ok := false
+		if returned, ok = r.Object.(*oapi.RouterToken); !ok {
+			t.Fatalf("Got back unexpected result: %#v", r.Object)
+		}
+	case <-time.After(time.Millisecond * 100):
+		t.Fatal("Unexpected timeout from async channel")
+	}
+	if len(returned.Secret) < oapi.MinTokenSecretLength || returned.Name != oapi.TokenNameForSecret(returned.Secret) {
+		t.Errorf("Expected a random secret that names the token, got %#v", returned)
+	}
+
+	created := registry.CreatedRouterToken
+	if created == nil {
+		t.Fatal("Expected the token to be stored")
+	}
+	if len(created.Secret) != 0 {
+		t.Errorf("Expected the secret not to be stored, got %q", created.Secret)
+	}
+	if created.ExpiresIn != 0 {
+		t.Errorf("Expected the token not to expire, got %d", created.ExpiresIn)
+	}
+}
+
+func TestCreateStorageError(t *testing.T) {
+	registry := test.RouterTokenRegistry{
+		Err: errors.New("Sample Error"),
+	}
+	storage := REST{
+		registry: &registry,
+	}
+
+	ctx := api.NewContext()
+	channel, err := storage.Create(ctx, &oapi.RouterToken{RouterName: "router", Namespaces: []string{"shard1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case r := <-channel:
+		if status, ok := r.Object.(*api.Status); !ok || status.Message != registry.Err.Error() {
+			t.Errorf("Got back unexpected result: %#v", r.Object)
+		}
+	case <-time.After(time.Millisecond * 100):
+		t.Error("Unexpected timeout from async channel")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	registry := test.RouterTokenRegistry{}
+	storage := REST{
+		registry: &registry,
+	}
+
+	ctx := api.NewContext()
+	channel, err := storage.Delete(ctx, "tokenName")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case r := <-channel:
+		if status, ok := r.Object.(*api.Status); !ok || status.Status != api.StatusSuccess {
+			t.Errorf("Got back unexpected result: %#v", r.Object)
+		}
+	case <-time.After(time.Millisecond * 100):
+		t.Error("Unexpected timeout from async channel")
+	}
+	if registry.DeletedRouterTokenName != "tokenName" {
+		t.Errorf("Expected the token to be deleted, got %q", registry.DeletedRouterTokenName)
+	}
+}
@@ -2,6 +2,8 @@ package test
 
 import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	"github.com/openshift/origin/pkg/oauth/api"
 )
@@ -11,6 +13,8 @@ type AccessTokenRegistry struct {
 	AccessTokens           *api.OAuthAccessTokenList
 	AccessToken            *api.OAuthAccessToken
 	DeletedAccessTokenName string
+	UsedAccessTokenName    string
+	UsedAccessTokenTime    util.Time
 }
 
 func (r *AccessTokenRegistry) ListAccessTokens(labels labels.Selector) (*api.OAuthAccessTokenList, error) {
@@ -29,7 +33,17 @@ func (r *AccessTokenRegistry) UpdateAccessToken(token *api.OAuthAccessToken) err
 	return r.Err
 }
 
+func (r *AccessTokenRegistry) RecordAccessTokenUse(name string, at util.Time) error {
+	r.UsedAccessTokenName = name
+	r.UsedAccessTokenTime = at
+	return r.Err
+}
+
 func (r *AccessTokenRegistry) DeleteAccessToken(name string) error {
 	r.DeletedAccessTokenName = name
 	return r.Err
 }
+
+func (r *AccessTokenRegistry) WatchAccessTokens(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return nil, r.Err
+}
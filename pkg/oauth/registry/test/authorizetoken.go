@@ -2,6 +2,7 @@ package test
 
 import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	"github.com/openshift/origin/pkg/oauth/api"
 )
@@ -33,3 +34,7 @@ func (r *AuthorizeTokenRegistry) DeleteAuthorizeToken(name string) error {
 	r.DeletedAuthorizeTokenName = name
 	return r.Err
 }
+
+func (r *AuthorizeTokenRegistry) WatchAuthorizeTokens(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return nil, r.Err
+}
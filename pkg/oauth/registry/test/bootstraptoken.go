@@ -0,0 +1,44 @@
+package test
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	"github.com/openshift/origin/pkg/oauth/api"
+)
+
+type BootstrapTokenRegistry struct {
+	Err                       error
+	BootstrapTokens           *api.BootstrapTokenList
+	BootstrapToken            *api.BootstrapToken
+	CreatedBootstrapToken     *api.BootstrapToken
+	DeletedBootstrapTokenName string
+	GetBootstrapTokenName     string
+}
+
+func (r *BootstrapTokenRegistry) ListBootstrapTokens(labels labels.Selector) (*api.BootstrapTokenList, error) {
+	return r.BootstrapTokens, r.Err
+}
+
+func (r *BootstrapTokenRegistry) GetBootstrapToken(name string) (*api.BootstrapToken, error) {
+	r.GetBootstrapTokenName = name
+	if r.BootstrapToken == nil && r.CreatedBootstrapToken != nil && r.CreatedBootstrapToken.Name == name {
+		created := *r.CreatedBootstrapToken
+		return &created, r.Err
+	}
+	return r.BootstrapToken, r.Err
+}
+
+func (r *BootstrapTokenRegistry) CreateBootstrapToken(token *api.BootstrapToken) error {
+	r.CreatedBootstrapToken = token
+	return r.Err
+}
+
+func (r *BootstrapTokenRegistry) DeleteBootstrapToken(name string) error {
+	r.DeletedBootstrapTokenName = name
+	return r.Err
+}
+
+func (r *BootstrapTokenRegistry) WatchBootstrapTokens(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return nil, r.Err
+}
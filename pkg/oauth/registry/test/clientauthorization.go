@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	"github.com/openshift/origin/pkg/oauth/api"
 )
@@ -43,3 +44,7 @@ func (r *ClientAuthorizationRegistry) DeleteClientAuthorization(name string) err
 	r.DeletedClientAuthorizationName = name
 	return r.Err
 }
+
+func (r *ClientAuthorizationRegistry) WatchClientAuthorizations(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return nil, r.Err
+}
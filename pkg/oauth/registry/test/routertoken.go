@@ -0,0 +1,44 @@
+package test
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	"github.com/openshift/origin/pkg/oauth/api"
+)
+
+type RouterTokenRegistry struct {
+	Err                    error
+	RouterTokens           *api.RouterTokenList
+	RouterToken            *api.RouterToken
+	CreatedRouterToken     *api.RouterToken
+	DeletedRouterTokenName string
+	GetRouterTokenName     string
+}
+
+func (r *RouterTokenRegistry) ListRouterTokens(labels labels.Selector) (*api.RouterTokenList, error) {
+	return r.RouterTokens, r.Err
+}
+
+func (r *RouterTokenRegistry) GetRouterToken(name string) (*api.RouterToken, error) {
+	r.GetRouterTokenName = name
+	if r.RouterToken == nil && r.CreatedRouterToken != nil && r.CreatedRouterToken.Name == name {
+		created := *r.CreatedRouterToken
+		return &created, r.Err
+	}
+	return r.RouterToken, r.Err
+}
+
+func (r *RouterTokenRegistry) CreateRouterToken(token *api.RouterToken) error {
+	r.CreatedRouterToken = token
+	return r.Err
+}
+
+func (r *RouterTokenRegistry) DeleteRouterToken(name string) error {
+	r.DeletedRouterTokenName = name
+	return r.Err
+}
+
+func (r *RouterTokenRegistry) WatchRouterTokens(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return nil, r.Err
+}
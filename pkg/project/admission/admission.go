@@ -0,0 +1,60 @@
+package admission
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+
+	osclient "github.com/openshift/origin/pkg/client"
+)
+
+// podNodeEnvironment merges the node selector and pod labels of a project into new pods.
+type podNodeEnvironment struct {
+	client osclient.ProjectsInterface
+}
+
+// NewPodNodeEnvironment returns an admission.Interface that applies the NodeSelector and PodLabels
+// of the project, read with client, to every pod created in that project. Pods in namespaces that
+// are not projects are admitted unchanged.
+func NewPodNodeEnvironment(client osclient.ProjectsInterface) admission.Interface {
+	return &podNodeEnvironment{
+		client: client,
+	}
+}
+
+// Admit updates pods on creation. The project node selector takes precedence over the pod node
+// selector, while labels already set on the pod are preserved.
+func (p *podNodeEnvironment) Admit(a admission.Attributes) error {
+	if a.GetOperation() != "CREATE" || a.GetKind() != "pods" {
+		return nil
+	}
+	pod, ok := a.GetObject().(*kapi.Pod)
+	if !ok {
+		return nil
+	}
+
+	project, err := p.client.Projects().Get(a.GetNamespace())
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return errors.NewInternalError(err)
+	}
+
+	if len(project.NodeSelector) > 0 && pod.Spec.NodeSelector == nil {
+		pod.Spec.NodeSelector = map[string]string{}
+	}
+	for k, v := range project.NodeSelector {
+		pod.Spec.NodeSelector[k] = v
+	}
+
+	if len(project.PodLabels) > 0 && pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	for k, v := range project.PodLabels {
+		if _, exists := pod.Labels[k]; !exists {
+			pod.Labels[k] = v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package admission
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	osclient "github.com/openshift/origin/pkg/client"
+	projectapi "github.com/openshift/origin/pkg/project/api"
+)
+
+// fakeProjects returns a single project, or a not found error for every other name.
+type fakeProjects struct {
+	project *projectapi.Project
+}
+
+func (c *fakeProjects) Projects() osclient.ProjectInterface {
+	return c
+}
+
+func (c *fakeProjects) Get(name string) (*projectapi.Project, error) {
+	if c.project == nil || c.project.Name != name {
+		return nil, errors.NewNotFound("project", name)
+	}
+	return c.project, nil
+}
+
+func (c *fakeProjects) List(label, field labels.Selector) (*projectapi.ProjectList, error) {
+	return &projectapi.ProjectList{}, nil
+}
+
+func (c *fakeProjects) Watch(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return watch.NewFake(), nil
+}
+
+func TestAdmitMergesProjectEnvironment(t *testing.T) {
+	client := &fakeProjects{&projectapi.Project{
+		ObjectMeta:   kapi.ObjectMeta{Name: "test"},
+		NodeSelector: map[string]string{"region": "east", "env": "prod"},
+		PodLabels:    map[string]string{"team": "a", "app": "default"},
+	}}
+	pod := &kapi.Pod{
+		ObjectMeta: kapi.ObjectMeta{Name: "pod", Labels: map[string]string{"app": "frontend"}},
+		Spec:       kapi.PodSpec{NodeSelector: map[string]string{"region": "west", "disk": "ssd"}},
+	}
+
+	handler := NewPodNodeEnvironment(client)
+	if err := handler.Admit(admission.NewAttributesRecord(pod, "test", "pods", "CREATE")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedSelector := map[string]string{"region": "east", "env": "prod", "disk": "ssd"}
+	if !kapi.Semantic.DeepEqual(pod.Spec.NodeSelector, expectedSelector) {
+		t.Errorf("Expected node selector %v, got %v", expectedSelector, pod.Spec.NodeSelector)
+	}
+	expectedLabels := map[string]string{"team": "a", "app": "frontend"}
+	if !kapi.Semantic.DeepEqual(pod.Labels, expectedLabels) {
+		t.Errorf("Expected labels %v, got %v", expectedLabels, pod.Labels)
+	}
+}
+
+func TestAdmitIgnoresNamespacesWithoutProject(t *testing.T) {
+	pod := &kapi.Pod{ObjectMeta: kapi.ObjectMeta{Name: "pod"}}
+
+	handler := NewPodNodeEnvironment(&fakeProjects{})
+	if err := handler.Admit(admission.NewAttributesRecord(pod, "test", "pods", "CREATE")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pod.Spec.NodeSelector != nil || pod.Labels != nil {
+		t.Errorf("Expected the pod to be unchanged, got %#v", pod)
+	}
+}
+
+func TestAdmitIgnoresUpdates(t *testing.T) {
+	client := &fakeProjects{&projectapi.Project{
+		ObjectMeta:   kapi.ObjectMeta{Name: "test"},
+		NodeSelector: map[string]string{"region": "east"},
+	}}
+	pod := &kapi.Pod{ObjectMeta: kapi.ObjectMeta{Name: "pod"}}
+
+	handler := NewPodNodeEnvironment(client)
+	if err := handler.Admit(admission.NewAttributesRecord(pod, "test", "pods", "UPDATE")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pod.Spec.NodeSelector != nil {
+		t.Errorf("Expected the pod to be unchanged, got %#v", pod)
+	}
+}
@@ -0,0 +1,3 @@
+// Package admission contains admission controllers that apply the node selector and pod labels
+// of a project to the pods created in it, and that reject new objects in projects being deleted.
+package admission
@@ -0,0 +1,46 @@
+package admission
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+
+	osclient "github.com/openshift/origin/pkg/client"
+	projectapi "github.com/openshift/origin/pkg/project/api"
+)
+
+// projectLifecycle rejects new content in projects that are being deleted.
+type projectLifecycle struct {
+	client osclient.ProjectsInterface
+}
+
+// NewProjectLifecycle returns an admission.Interface that rejects the creation of objects in
+// projects, read with client, whose phase is Terminating, so that nothing is created while the
+// project finalizer deletes the contents of the project. Objects in namespaces that are not
+// projects are admitted.
+func NewProjectLifecycle(client osclient.ProjectsInterface) admission.Interface {
+	return &projectLifecycle{
+		client: client,
+	}
+}
+
+// Admit rejects creations in terminating projects.
+func (p *projectLifecycle) Admit(a admission.Attributes) error {
+	if a.GetOperation() != "CREATE" || len(a.GetNamespace()) == 0 {
+		return nil
+	}
+
+	project, err := p.client.Projects().Get(a.GetNamespace())
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return errors.NewInternalError(err)
+	}
+
+	if project.Status.Phase == projectapi.ProjectTerminating {
+		return errors.NewForbidden(a.GetKind(), "", fmt.Errorf("project %s is being deleted", project.Name))
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package admission
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+
+	projectapi "github.com/openshift/origin/pkg/project/api"
+)
+
+func TestLifecycleRejectsCreatesInTerminatingProjects(t *testing.T) {
+	client := &fakeProjects{&projectapi.Project{
+		ObjectMeta: kapi.ObjectMeta{Name: "test"},
+		Status:     projectapi.ProjectStatus{Phase: projectapi.ProjectTerminating},
+	}}
+	pod := &kapi.Pod{ObjectMeta: kapi.ObjectMeta{Name: "pod"}}
+
+	handler := NewProjectLifecycle(client)
+	if err := handler.Admit(admission.NewAttributesRecord(pod, "test", "pods", "CREATE")); !errors.IsForbidden(err) {
+		t.Errorf("Expected the create to be forbidden, got %v", err)
+	}
+	if err := handler.Admit(admission.NewAttributesRecord(pod, "test", "pods", "DELETE")); err != nil {
+		t.Errorf("Expected deletes to be admitted, got %v", err)
+	}
+}
+
+func TestLifecycleAdmitsCreatesInActiveProjects(t *testing.T) {
+	client := &fakeProjects{&projectapi.Project{
+		ObjectMeta: kapi.ObjectMeta{Name: "test"},
+		Status:     projectapi.ProjectStatus{Phase: projectapi.ProjectActive},
+	}}
+	pod := &kapi.Pod{ObjectMeta: kapi.ObjectMeta{Name: "pod"}}
+
+	handler := NewProjectLifecycle(client)
+	if err := handler.Admit(admission.NewAttributesRecord(pod, "test", "pods", "CREATE")); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := handler.Admit(admission.NewAttributesRecord(pod, "other", "pods", "CREATE")); err != nil {
+		t.Errorf("Expected namespaces without a project to be admitted, got %v", err)
+	}
+}
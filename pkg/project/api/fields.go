@@ -0,0 +1,20 @@
+package api
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/api/selectable"
+)
+
+func init() {
+	selectable.Register(&Project{}, func(obj runtime.Object) labels.Set { return ProjectToSelectableFields(obj.(*Project)) })
+}
+
+// ProjectToSelectableFields returns the fields of a project that clients can select on.
+func ProjectToSelectableFields(project *Project) labels.Set {
+	return labels.Set{
+		"name":         project.Name,
+		"status.phase": string(project.Status.Phase),
+	}
+}
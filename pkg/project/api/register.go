@@ -8,8 +8,10 @@ func init() {
 	api.Scheme.AddKnownTypes("",
 		&Project{},
 		&ProjectList{},
+		&ProjectRequest{},
 	)
 }
 
-func (*Project) IsAnAPIObject()     {}
-func (*ProjectList) IsAnAPIObject() {}
+func (*Project) IsAnAPIObject()        {}
+func (*ProjectList) IsAnAPIObject()    {}
+func (*ProjectRequest) IsAnAPIObject() {}
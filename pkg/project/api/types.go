@@ -11,9 +11,44 @@ type ProjectList struct {
 	Items         []Project `json:"items"`
 }
 
+// ProjectPhase is the lifecycle phase of a Project
+type ProjectPhase string
+
+const (
+	// ProjectActive means the project is available for use
+	ProjectActive ProjectPhase = "Active"
+	// ProjectTerminating means the project is being deleted and its contents are being removed
+	ProjectTerminating ProjectPhase = "Terminating"
+)
+
+// ProjectStatus is information about the current status of a Project
+type ProjectStatus struct {
+	Phase ProjectPhase `json:"phase,omitempty"`
+}
+
 // Project is a logical top-level container for a set of origin resources
 type Project struct {
 	kapi.TypeMeta   `json:",inline"`
 	kapi.ObjectMeta `json:"metadata,omitempty"`
 	DisplayName     string `json:"displayName,omitempty"`
+	// NodeSelector is merged into the node selector of every pod created in the project, taking
+	// precedence over the selector of the pod.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// PodLabels are added to every pod created in the project that does not already have a
+	// label with the same key.
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+	Status    ProjectStatus     `json:"status,omitempty"`
+}
+
+// ProjectRequest is the set of options necessary to fully qualify a project request
+type ProjectRequest struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+	DisplayName     string `json:"displayName,omitempty"`
 }
+
+const (
+	// ProjectRequesterAnnotation is the name of the user that requested a project. It is set by the
+	// server on ProjectRequests and on the Projects created from them.
+	ProjectRequesterAnnotation = "openshift.io/requester"
+)
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	kvalidation "github.com/GoogleCloudPlatform/kubernetes/pkg/api/validation"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/openshift/origin/pkg/project/api"
 )
@@ -22,9 +23,20 @@ func ValidateProject(project *api.Project) errors.ValidationErrorList {
 	if !validateNoNewLineOrTab(project.DisplayName) {
 		result = append(result, errors.NewFieldInvalid("DisplayName", project.DisplayName, "may not contain a new line or tab"))
 	}
+	result = append(result, kvalidation.ValidateLabels(project.NodeSelector, "NodeSelector")...)
+	result = append(result, kvalidation.ValidateLabels(project.PodLabels, "PodLabels")...)
 	return result
 }
 
+// ValidateProjectRequest tests required fields for a ProjectRequest.
+func ValidateProjectRequest(request *api.ProjectRequest) errors.ValidationErrorList {
+	project := &api.Project{
+		ObjectMeta:  request.ObjectMeta,
+		DisplayName: request.DisplayName,
+	}
+	return ValidateProject(project)
+}
+
 // validateNoNewLineOrTab ensures a string has no new-line or tab
 func validateNoNewLineOrTab(s string) bool {
 	return !(strings.Contains(s, "\n") || strings.Contains(s, "\t"))
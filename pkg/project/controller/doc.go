@@ -0,0 +1,2 @@
+// Package controller contains the controllers that manage the lifecycle of projects.
+package controller
@@ -0,0 +1,242 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/golang/glog"
+
+	osclient "github.com/openshift/origin/pkg/client"
+	oscontroller "github.com/openshift/origin/pkg/controller"
+	projectapi "github.com/openshift/origin/pkg/project/api"
+)
+
+// ProjectFinalizer looks for projects that are terminating, deletes every OpenShift resource in them,
+// and removes each project once nothing remains in it.  Projects are finalized as soon as a watch
+// reports them terminating, and every Period while their contents are being deleted.
+type ProjectFinalizer struct {
+	// Client is used to list projects and to delete their contents.
+	Client osclient.Interface
+	// Remove deletes the record of a project whose contents have been deleted.
+	Remove func(name string) error
+	// Period is the interval between attempts to finalize terminating projects.
+	Period time.Duration
+	// Synced is an optional function called after each attempt to finalize every terminating project.
+	Synced func()
+	// Crashed is an optional function called with the value of a panic in the controller.
+	Crashed func(interface{})
+	// Stop is an optional channel that controls when the controller exits.
+	Stop <-chan struct{}
+}
+
+// Run begins finalizing terminating projects as they are watched, and every Period.
+func (c *ProjectFinalizer) Run() {
+	go oscontroller.Until(func() {
+		c.HandleProjects()
+		if c.Synced != nil {
+			c.Synced()
+		}
+	}, c.Period, c.Stop, c.Crashed)
+	go oscontroller.Until(c.WatchProjects, time.Second, c.Stop, c.Crashed)
+}
+
+// WatchProjects finalizes projects as a watch reports them terminating, until the watch ends or Stop is
+// closed.
+func (c *ProjectFinalizer) WatchProjects() {
+	w, err := c.Client.Projects().Watch(labels.Everything(), labels.Everything(), "")
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to watch projects: %v", err))
+		return
+	}
+	defer w.Stop()
+	for {
+		select {
+		case <-c.Stop:
+			return
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return
+			}
+			if event.Type != watch.Added && event.Type != watch.Modified {
+				continue
+			}
+			project, ok := event.Object.(*projectapi.Project)
+			if !ok || project.Status.Phase != projectapi.ProjectTerminating {
+				continue
+			}
+			if err := c.Finalize(project.Name); err != nil {
+				util.HandleError(fmt.Errorf("unable to finalize project %s: %v", project.Name, err))
+			}
+		}
+	}
+}
+
+// HandleProjects attempts to finalize every terminating project.
+func (c *ProjectFinalizer) HandleProjects() {
+	projects, err := c.Client.Projects().List(labels.Everything(), labels.Everything())
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to list projects: %v", err))
+		return
+	}
+	for _, project := range projects.Items {
+		if project.Status.Phase != projectapi.ProjectTerminating {
+			continue
+		}
+		if err := c.Finalize(project.Name); err != nil {
+			util.HandleError(fmt.Errorf("unable to finalize project %s: %v", project.Name, err))
+		}
+	}
+}
+
+// Finalize deletes the contents of the named project and removes the project once no content
+// remains. If content remains after the deletions, the project is left for a later attempt.
+func (c *ProjectFinalizer) Finalize(name string) error {
+	deleters := contentDeleters(c.Client)
+	for _, d := range deleters {
+		names, err := d.list(name)
+		if err != nil {
+			return fmt.Errorf("unable to list %s: %v", d.resource, err)
+		}
+		for _, item := range names {
+			if err := d.delete(name, item); err != nil {
+				return fmt.Errorf("unable to delete %s %s: %v", d.resource, item, err)
+			}
+		}
+	}
+
+	for _, d := range deleters {
+		names, err := d.list(name)
+		if err != nil {
+			return fmt.Errorf("unable to list %s: %v", d.resource, err)
+		}
+		if len(names) > 0 {
+			glog.V(4).Infof("Waiting for %d %s to be deleted from project %s", len(names), d.resource, name)
+			return nil
+		}
+	}
+
+	glog.V(2).Infof("Removing project %s, all of its contents have been deleted", name)
+	return c.Remove(name)
+}
+
+// contentDeleter lists and deletes one kind of resource in a namespace.
+type contentDeleter struct {
+	resource string
+	list     func(namespace string) ([]string, error)
+	delete   func(namespace, name string) error
+}
+
+// contentDeleters returns the deleters for every namespaced OpenShift resource, in the order the
+// resources should be deleted. Authorization policy is deleted last.
+func contentDeleters(client osclient.Interface) []contentDeleter {
+	everything := labels.Everything()
+	return []contentDeleter{
+		{
+			resource: "buildConfigs",
+			list: listNames(func(namespace string) (runtime.Object, error) {
+				return client.BuildConfigs(namespace).List(everything, everything)
+			}),
+			delete: func(namespace, name string) error { return client.BuildConfigs(namespace).Delete(name) },
+		},
+		{
+			resource: "builds",
+			list: listNames(func(namespace string) (runtime.Object, error) {
+				return client.Builds(namespace).List(everything, everything)
+			}),
+			delete: func(namespace, name string) error { return client.Builds(namespace).Delete(name) },
+		},
+		{
+			resource: "deploymentConfigs",
+			list: listNames(func(namespace string) (runtime.Object, error) {
+				return client.DeploymentConfigs(namespace).List(everything, everything)
+			}),
+			delete: func(namespace, name string) error { return client.DeploymentConfigs(namespace).Delete(name) },
+		},
+		{
+			resource: "deployments",
+			list: listNames(func(namespace string) (runtime.Object, error) {
+				return client.Deployments(namespace).List(everything, everything)
+			}),
+			delete: func(namespace, name string) error { return client.Deployments(namespace).Delete(name) },
+		},
+		{
+			resource: "imageRepositories",
+			list: listNames(func(namespace string) (runtime.Object, error) {
+				return client.ImageRepositories(namespace).List(everything, everything)
+			}),
+			delete: func(namespace, name string) error { return client.ImageRepositories(namespace).Delete(name) },
+		},
+		{
+			// deleted repositories are kept for a grace period and must not be restorable into a later
+			// project of the same name, so they are purged after the repositories are deleted
+			resource: "imageRepositoryRestores",
+			list: listNames(func(namespace string) (runtime.Object, error) {
+				return client.ImageRepositories(namespace).ListRestorable(everything, everything)
+			}),
+			delete: func(namespace, name string) error { return client.ImageRepositories(namespace).Purge(name) },
+		},
+		{
+			resource: "routes",
+			list: listNames(func(namespace string) (runtime.Object, error) {
+				return client.Routes(namespace).List(everything, everything)
+			}),
+			delete: func(namespace, name string) error { return client.Routes(namespace).Delete(name) },
+		},
+		{
+			resource: "templates",
+			list: listNames(func(namespace string) (runtime.Object, error) {
+				return client.Templates(namespace).List(everything, everything)
+			}),
+			delete: func(namespace, name string) error { return client.Templates(namespace).Delete(name) },
+		},
+		{
+			resource: "templateInstances",
+			list: listNames(func(namespace string) (runtime.Object, error) {
+				return client.TemplateInstances(namespace).List(everything, everything)
+			}),
+			delete: func(namespace, name string) error { return client.TemplateInstances(namespace).Delete(name) },
+		},
+		{
+			resource: "policyBindings",
+			list: listNames(func(namespace string) (runtime.Object, error) {
+				return client.PolicyBindings(namespace).List(everything, everything)
+			}),
+			delete: func(namespace, name string) error { return client.PolicyBindings(namespace).Delete(name) },
+		},
+		{
+			resource: "policies",
+			list: listNames(func(namespace string) (runtime.Object, error) {
+				return client.Policies(namespace).List(everything, everything)
+			}),
+			delete: func(namespace, name string) error { return client.Policies(namespace).Delete(name) },
+		},
+	}
+}
+
+// listNames returns a function that lists the names of the objects in the list returned by list.
+func listNames(list func(namespace string) (runtime.Object, error)) func(namespace string) ([]string, error) {
+	return func(namespace string) ([]string, error) {
+		obj, err := list(namespace)
+		if err != nil {
+			return nil, err
+		}
+		items, err := runtime.ExtractList(obj)
+		if err != nil {
+			return nil, err
+		}
+		names := []string{}
+		for _, item := range items {
+			accessor, err := meta.Accessor(item)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, accessor.Name())
+		}
+		return names, nil
+	}
+}
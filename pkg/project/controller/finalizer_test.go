@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	osclient "github.com/openshift/origin/pkg/client"
+	projectapi "github.com/openshift/origin/pkg/project/api"
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+// remainingRoutes is a client whose routes are never deleted.
+type remainingRoutes struct {
+	*osclient.Fake
+}
+
+func (c remainingRoutes) Routes(namespace string) osclient.RouteInterface {
+	return routesWithItem{&osclient.FakeRoutes{Fake: c.Fake}}
+}
+
+type routesWithItem struct {
+	*osclient.FakeRoutes
+}
+
+func (c routesWithItem) List(label, field labels.Selector) (*routeapi.RouteList, error) {
+	c.FakeRoutes.List(label, field)
+	return &routeapi.RouteList{Items: []routeapi.Route{{ObjectMeta: kapi.ObjectMeta{Name: "route"}}}}, nil
+}
+
+func TestFinalizeEmptyProject(t *testing.T) {
+	client := &osclient.Fake{}
+	removed := []string{}
+	finalizer := &ProjectFinalizer{
+		Client: client,
+		Remove: func(name string) error {
+			removed = append(removed, name)
+			return nil
+		},
+	}
+
+	if err := finalizer.Finalize("foo"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != "foo" {
+		t.Errorf("Expected project foo to be removed, got %v", removed)
+	}
+	if len(client.Actions) != 2*len(contentDeleters(client)) {
+		t.Errorf("Expected every resource to be listed twice, got %#v", client.Actions)
+	}
+}
+
+func TestFinalizeWaitsForContent(t *testing.T) {
+	fake := &osclient.Fake{}
+	removed := []string{}
+	finalizer := &ProjectFinalizer{
+		Client: remainingRoutes{fake},
+		Remove: func(name string) error {
+			removed = append(removed, name)
+			return nil
+		},
+	}
+
+	if err := finalizer.Finalize("foo"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(removed) != 0 {
+		t.Errorf("Expected the project to be kept while content remains, got %v", removed)
+	}
+	deleted := false
+	for _, action := range fake.Actions {
+		if action.Action == "delete-route" {
+			deleted = true
+		}
+	}
+	if !deleted {
+		t.Errorf("Expected the route to be deleted, got %#v", fake.Actions)
+	}
+}
+
+func TestHandleProjectsIgnoresActiveProjects(t *testing.T) {
+	projects := &projectapi.ProjectList{Items: []projectapi.Project{
+		{ObjectMeta: kapi.ObjectMeta{Name: "active"}, Status: projectapi.ProjectStatus{Phase: projectapi.ProjectActive}},
+	}}
+	removed := []string{}
+	finalizer := &ProjectFinalizer{
+		Client: listedProjects{Fake: &osclient.Fake{}, projects: projects},
+		Remove: func(name string) error {
+			removed = append(removed, name)
+			return nil
+		},
+	}
+
+	finalizer.HandleProjects()
+	if len(removed) != 0 {
+		t.Errorf("Expected active projects to be kept, got %v", removed)
+	}
+
+	projects.Items[0].Status.Phase = projectapi.ProjectTerminating
+	finalizer.HandleProjects()
+	if len(removed) != 1 || removed[0] != "active" {
+		t.Errorf("Expected terminating project to be removed, got %v", removed)
+	}
+}
+
+func TestWatchProjectsFinalizesTerminatingProjects(t *testing.T) {
+	fakeWatch := watch.NewFake()
+	removed := make(chan string, 2)
+	finalizer := &ProjectFinalizer{
+		Client: listedProjects{Fake: &osclient.Fake{}, watch: fakeWatch},
+		Remove: func(name string) error {
+			removed <- name
+			return nil
+		},
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		finalizer.WatchProjects()
+	}()
+
+	fakeWatch.Add(&projectapi.Project{ObjectMeta: kapi.ObjectMeta{Name: "active"}, Status: projectapi.ProjectStatus{Phase: projectapi.ProjectActive}})
+	fakeWatch.Modify(&projectapi.Project{ObjectMeta: kapi.ObjectMeta{Name: "terminating"}, Status: projectapi.ProjectStatus{Phase: projectapi.ProjectTerminating}})
+	fakeWatch.Stop()
+	<-done
+
+	close(removed)
+	names := []string{}
+	for name := range removed {
+		names = append(names, name)
+	}
+	if len(names) != 1 || names[0] != "terminating" {
+		t.Errorf("Expected only the terminating project to be removed, got %v", names)
+	}
+}
+
+// listedProjects is a client that returns a fixed list of projects, and a fixed watch.
+type listedProjects struct {
+	*osclient.Fake
+	projects *projectapi.ProjectList
+	watch    watch.Interface
+}
+
+func (c listedProjects) Projects() osclient.ProjectInterface {
+	return fixedProjects{&osclient.FakeProjects{Fake: c.Fake}, c.projects, c.watch}
+}
+
+type fixedProjects struct {
+	*osclient.FakeProjects
+	projects *projectapi.ProjectList
+	watch    watch.Interface
+}
+
+func (c fixedProjects) List(label, field labels.Selector) (*projectapi.ProjectList, error) {
+	return c.projects, nil
+}
+
+func (c fixedProjects) Watch(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return c.watch, nil
+}
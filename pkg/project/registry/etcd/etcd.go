@@ -6,7 +6,10 @@ import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	etcderr "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors/etcd"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/golang/glog"
 
 	"github.com/openshift/origin/pkg/project/api"
 )
@@ -75,6 +78,23 @@ func (r *Etcd) UpdateProject(ctx kapi.Context, project *api.Project) error {
 	return errors.New("not supported")
 }
 
+// WatchProjects begins watching for new, changed, or deleted projects.
+func (r *Etcd) WatchProjects(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	version, err := tools.ParseWatchResourceVersion(resourceVersion, "project")
+	if err != nil {
+		return nil, err
+	}
+
+	return r.WatchList(makeProjectListKey(ctx), version, func(obj runtime.Object) bool {
+		project, ok := obj.(*api.Project)
+		if !ok {
+			glog.Errorf("Unexpected object during project watch: %#v", obj)
+			return false
+		}
+		return label.Matches(labels.Set(project.Labels)) && field.Matches(api.ProjectToSelectableFields(project))
+	})
+}
+
 // DeleteProject deletes an existing project
 func (r *Etcd) DeleteProject(ctx kapi.Context, id string) error {
 	err := r.Delete(makeProjectKey(ctx, id), false)
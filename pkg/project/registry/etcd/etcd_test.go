@@ -9,6 +9,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 	"github.com/coreos/go-etcd/etcd"
 
 	"github.com/openshift/origin/pkg/api/latest"
@@ -277,3 +278,70 @@ func TestEtcdDeleteProjectOK(t *testing.T) {
 		t.Errorf("Unexpected key: %s, expected %s", fakeClient.DeletedKeys[0], key)
 	}
 }
+
+func TestEtcdWatchProjects(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	registry := NewTestEtcd(fakeClient)
+
+	selector := labels.SelectorFromSet(labels.Set{"env": "prod"})
+	watching, err := registry.WatchProjects(kapi.NewContext(), selector, labels.Everything(), "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fakeClient.WaitForWatchCompletion()
+
+	for _, project := range []*api.Project{
+		{ObjectMeta: kapi.ObjectMeta{Name: "dev", Labels: map[string]string{"env": "dev"}}},
+		{ObjectMeta: kapi.ObjectMeta{Name: "prod", Labels: map[string]string{"env": "prod"}}},
+	} {
+		projectBytes, _ := latest.Codec.Encode(project)
+		fakeClient.WatchResponse <- &etcd.Response{
+			Action: "set",
+			Node:   &etcd.Node{Value: string(projectBytes)},
+		}
+	}
+
+	event := <-watching.ResultChan()
+	if event.Type != watch.Added {
+		t.Errorf("expected add but got %s", event.Type)
+	}
+	if project, ok := event.Object.(*api.Project); !ok || project.Name != "prod" {
+		t.Errorf("expected only the matching project, got %#v", event.Object)
+	}
+
+	fakeClient.WatchInjectError <- nil
+	if _, ok := <-watching.ResultChan(); ok {
+		t.Errorf("watching channel should be closed")
+	}
+	watching.Stop()
+}
+
+func TestEtcdWatchProjectsWithFields(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	registry := NewTestEtcd(fakeClient)
+
+	field := labels.SelectorFromSet(labels.Set{"status.phase": string(api.ProjectTerminating)})
+	watching, err := registry.WatchProjects(kapi.NewContext(), labels.Everything(), field, "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fakeClient.WaitForWatchCompletion()
+
+	for _, project := range []*api.Project{
+		{ObjectMeta: kapi.ObjectMeta{Name: "active"}, Status: api.ProjectStatus{Phase: api.ProjectActive}},
+		{ObjectMeta: kapi.ObjectMeta{Name: "terminating"}, Status: api.ProjectStatus{Phase: api.ProjectTerminating}},
+	} {
+		projectBytes, _ := latest.Codec.Encode(project)
+		fakeClient.WatchResponse <- &etcd.Response{
+			Action: "set",
+			Node:   &etcd.Node{Value: string(projectBytes)},
+		}
+	}
+
+	event := <-watching.ResultChan()
+	if project, ok := event.Object.(*api.Project); !ok || project.Name != "terminating" {
+		t.Errorf("expected only the matching project, got %#v", event.Object)
+	}
+	fakeClient.WatchInjectError <- nil
+	watching.Stop()
+}
@@ -4,6 +4,7 @@ import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 	"github.com/openshift/origin/pkg/project/api"
 )
 
@@ -19,4 +20,6 @@ type Registry interface {
 	UpdateProject(ctx kapi.Context, Project *api.Project) error
 	// DeleteProject deletes an Project.
 	DeleteProject(ctx kapi.Context, id string) error
+	// WatchProjects watches for new/modified/deleted Projects.
+	WatchProjects(ctx kapi.Context, labels, fields labels.Selector, resourceVersion string) (watch.Interface, error)
 }
@@ -8,7 +8,9 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
+	"github.com/openshift/origin/pkg/api/selectable"
 	"github.com/openshift/origin/pkg/project/api"
 	"github.com/openshift/origin/pkg/project/api/validation"
 )
@@ -39,6 +41,9 @@ func (s *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime
 		return nil, err
 	}
 
+	if err := selectable.FilterList(projects, selector, fields); err != nil {
+		return nil, err
+	}
 	return projects, nil
 }
 
@@ -51,6 +56,15 @@ func (s *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
 	return project, nil
 }
 
+// Watch returns Project events via a watch.Interface.
+// It implements apiserver.ResourceWatcher.
+func (s *REST) Watch(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	if err := selectable.ValidateFields(&api.Project{}, field); err != nil {
+		return nil, err
+	}
+	return s.registry.WatchProjects(ctx, label, field, resourceVersion)
+}
+
 // Create registers the given Project.
 func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
 	project, ok := obj.(*api.Project)
@@ -59,6 +73,7 @@ func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 	}
 
 	kapi.FillObjectMetaSystemFields(ctx, &project.ObjectMeta)
+	project.Status.Phase = api.ProjectActive
 
 	// kubectl auto-inserts a value, we need to ignore this value until we have cluster-scoped actions in kubectl
 	project.Namespace = ""
@@ -74,9 +89,20 @@ func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 	}), nil
 }
 
-// Delete asynchronously deletes a Project specified by its id.
+// Delete asynchronously marks a Project specified by its id as terminating. The project finalizer
+// deletes the contents of the project and then removes the project itself.
 func (s *REST) Delete(ctx kapi.Context, id string) (<-chan apiserver.RESTResult, error) {
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
-		return &kapi.Status{Status: kapi.StatusSuccess}, s.registry.DeleteProject(ctx, id)
+		project, err := s.registry.GetProject(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if project.Status.Phase != api.ProjectTerminating {
+			project.Status.Phase = api.ProjectTerminating
+			if err := s.registry.UpdateProject(ctx, project); err != nil {
+				return nil, err
+			}
+		}
+		return &kapi.Status{Status: kapi.StatusSuccess}, nil
 	}), nil
 }
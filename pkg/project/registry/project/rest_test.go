@@ -200,6 +200,10 @@ func TestGetProjectOK(t *testing.T) {
 
 func TestDeleteProject(t *testing.T) {
 	mockRegistry := test.NewProjectRegistry()
+	mockRegistry.Project = &api.Project{
+		ObjectMeta: kapi.ObjectMeta{Name: "foo"},
+		Status:     api.ProjectStatus{Phase: api.ProjectActive},
+	}
 	storage := REST{registry: mockRegistry}
 	channel, err := storage.Delete(nil, "foo")
 	if channel == nil {
@@ -218,6 +222,9 @@ func TestDeleteProject(t *testing.T) {
 		if status.Status != kapi.StatusSuccess {
 			t.Errorf("Expected status=success, got: %#v", status)
 		}
+		if mockRegistry.Project.Status.Phase != api.ProjectTerminating {
+			t.Errorf("Expected the project to be terminating, got: %#v", mockRegistry.Project)
+		}
 	case <-time.After(50 * time.Millisecond):
 		t.Errorf("Timed out waiting for result")
 	}
@@ -0,0 +1,143 @@
+package projectrequest
+
+import (
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	utilerr "github.com/GoogleCloudPlatform/kubernetes/pkg/util/errors"
+	"github.com/golang/glog"
+
+	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
+	"github.com/openshift/origin/pkg/project/api"
+	"github.com/openshift/origin/pkg/project/api/validation"
+	projectregistry "github.com/openshift/origin/pkg/project/registry/project"
+	"github.com/openshift/origin/pkg/template"
+	templateapi "github.com/openshift/origin/pkg/template/api"
+	"github.com/openshift/origin/pkg/template/generator"
+)
+
+// ListCreator creates every item of a list in a namespace.
+type ListCreator interface {
+	Create(list *kapi.List, namespace string) []error
+}
+
+// REST implements the RESTStorage interface for ProjectRequests. Creating a ProjectRequest creates the
+// Project, instantiates the project template inside of it, and makes the requester the project admin.
+type REST struct {
+	registry        projectregistry.Registry
+	template        *templateapi.Template
+	creator         ListCreator
+	masterNamespace string
+}
+
+// NewREST returns a new REST. Objects from projectTemplate are created in each new project with creator.
+// The admin role is resolved from masterNamespace.
+func NewREST(registry projectregistry.Registry, projectTemplate *templateapi.Template, creator ListCreator, masterNamespace string) apiserver.RESTStorage {
+	if projectTemplate == nil {
+		projectTemplate = DefaultTemplate()
+	}
+	return &REST{
+		registry:        registry,
+		template:        projectTemplate,
+		creator:         creator,
+		masterNamespace: masterNamespace,
+	}
+}
+
+// New returns a new ProjectRequest for use with Create.
+func (r *REST) New() runtime.Object {
+	return &api.ProjectRequest{}
+}
+
+// Create creates the requested Project and its default objects.
+func (r *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	request, ok := obj.(*api.ProjectRequest)
+	if !ok {
+		return nil, fmt.Errorf("not a project request: %#v", obj)
+	}
+
+	// kubectl auto-inserts a value, we need to ignore this value until we have cluster-scoped actions in kubectl
+	request.Namespace = ""
+	if errs := validation.ValidateProjectRequest(request); len(errs) > 0 {
+		return nil, errors.NewInvalid("projectRequest", request.Name, errs)
+	}
+	requester := request.Annotations[api.ProjectRequesterAnnotation]
+	if len(requester) == 0 {
+		return nil, errors.NewForbidden("projectRequest", request.Name, fmt.Errorf("the requesting user could not be determined"))
+	}
+
+	list, err := r.processTemplate(request, requester)
+	if err != nil {
+		return nil, err
+	}
+
+	project := &api.Project{
+		ObjectMeta: kapi.ObjectMeta{
+			Name:        request.Name,
+			Annotations: map[string]string{api.ProjectRequesterAnnotation: requester},
+		},
+		DisplayName: request.DisplayName,
+		Status:      api.ProjectStatus{Phase: api.ProjectActive},
+	}
+	kapi.FillObjectMetaSystemFields(ctx, &project.ObjectMeta)
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		if err := r.registry.CreateProject(ctx, project); err != nil {
+			return nil, err
+		}
+		if errs := r.creator.Create(list, project.Name); len(errs) > 0 {
+			glog.Errorf("Unable to instantiate template %s in project %s: %v", r.template.Name, project.Name, utilerr.NewAggregate(errs))
+			return nil, errors.NewInternalError(utilerr.NewAggregate(errs))
+		}
+		return r.registry.GetProject(ctx, project.Name)
+	}), nil
+}
+
+// processTemplate instantiates the project template for request and returns the objects that must
+// be created in the new project, including the binding that makes requester the project admin.
+func (r *REST) processTemplate(request *api.ProjectRequest, requester string) (*kapi.List, error) {
+	// processing mutates the template, so work from a copy of the configured one
+	projectTemplate := &templateapi.Template{
+		ObjectMeta: r.template.ObjectMeta,
+		Parameters: append([]templateapi.Parameter{}, r.template.Parameters...),
+	}
+	for _, item := range r.template.Items {
+		copied, err := kapi.Scheme.Copy(item)
+		if err != nil {
+			return nil, errors.NewInternalError(err)
+		}
+		projectTemplate.Items = append(projectTemplate.Items, copied)
+	}
+	template.AddParameter(projectTemplate, templateapi.Parameter{Name: ProjectNameParam, Value: request.Name})
+	template.AddParameter(projectTemplate, templateapi.Parameter{Name: ProjectDisplayNameParam, Value: request.DisplayName})
+	template.AddParameter(projectTemplate, templateapi.Parameter{Name: ProjectRequesterParam, Value: requester})
+
+	generators := generator.NewDefaultGenerators(generator.NewSecureRand())
+	config, errs := template.NewProcessor(generators).Process(projectTemplate)
+	if len(errs) > 0 {
+		return nil, errors.NewInternalError(utilerr.NewAggregate(errs))
+	}
+
+	instance, err := template.NewTemplateInstance(r.template.Name, projectTemplate, config, request.Name)
+	if err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+
+	list := &kapi.List{Items: config.Items}
+	list.Items = append(list.Items, instance)
+	list.Items = append(list.Items, &authorizationapi.RoleBinding{
+		ObjectMeta: kapi.ObjectMeta{
+			Name:      "admins",
+			Namespace: request.Name,
+		},
+		RoleRef: kapi.ObjectReference{
+			Name:      "admin",
+			Namespace: r.masterNamespace,
+		},
+		UserNames: []string{requester},
+	})
+	return list, nil
+}
@@ -0,0 +1,133 @@
+package projectrequest
+
+import (
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	_ "github.com/openshift/origin/pkg/api/latest"
+	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
+	"github.com/openshift/origin/pkg/project/api"
+	"github.com/openshift/origin/pkg/project/registry/test"
+	templateapi "github.com/openshift/origin/pkg/template/api"
+)
+
+type fakeCreator struct {
+	Namespace string
+	Items     []runtime.Object
+	Errs      []error
+}
+
+func (c *fakeCreator) Create(list *kapi.List, namespace string) []error {
+	c.Namespace = namespace
+	c.Items = list.Items
+	return c.Errs
+}
+
+func projectTemplate() *templateapi.Template {
+	template := DefaultTemplate()
+	template.Items = []runtime.Object{
+		&kapi.Service{
+			ObjectMeta: kapi.ObjectMeta{Name: "frontend", Namespace: "other"},
+			Spec:       kapi.ServiceSpec{Port: 80},
+		},
+	}
+	return template
+}
+
+func waitForResult(t *testing.T, channel <-chan apiserver.RESTResult) apiserver.RESTResult {
+	select {
+	case result := <-channel:
+		return result
+	case <-time.After(50 * time.Millisecond):
+		t.Fatalf("Timed out waiting for result")
+	}
+	return apiserver.RESTResult{}
+}
+
+func TestCreateProjectRequestWithoutRequester(t *testing.T) {
+	storage := NewREST(test.NewProjectRegistry(), projectTemplate(), &fakeCreator{}, "master")
+
+	_, err := storage.(*REST).Create(kapi.NewContext(), &api.ProjectRequest{ObjectMeta: kapi.ObjectMeta{Name: "foo"}})
+	if !errors.IsForbidden(err) {
+		t.Errorf("Expected forbidden error, got %v", err)
+	}
+}
+
+func TestCreateInvalidProjectRequest(t *testing.T) {
+	storage := NewREST(test.NewProjectRegistry(), projectTemplate(), &fakeCreator{}, "master")
+
+	_, err := storage.(*REST).Create(kapi.NewContext(), &api.ProjectRequest{
+		ObjectMeta: kapi.ObjectMeta{Name: "", Annotations: map[string]string{api.ProjectRequesterAnnotation: "bob"}},
+	})
+	if !errors.IsInvalid(err) {
+		t.Errorf("Expected invalid error, got %v", err)
+	}
+}
+
+func TestCreateProjectRequest(t *testing.T) {
+	registry := test.NewProjectRegistry()
+	creator := &fakeCreator{}
+	storage := NewREST(registry, projectTemplate(), creator, "master")
+
+	channel, err := storage.(*REST).Create(kapi.NewContext(), &api.ProjectRequest{
+		ObjectMeta:  kapi.ObjectMeta{Name: "foo", Annotations: map[string]string{api.ProjectRequesterAnnotation: "bob"}},
+		DisplayName: "Foo",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := waitForResult(t, channel)
+	project, ok := result.Object.(*api.Project)
+	if !ok {
+		t.Fatalf("Expected a project, got %#v", result.Object)
+	}
+	if project.Name != "foo" || project.DisplayName != "Foo" || project.Annotations[api.ProjectRequesterAnnotation] != "bob" {
+		t.Errorf("Unexpected project: %#v", project)
+	}
+
+	if creator.Namespace != "foo" {
+		t.Errorf("Expected items to be created in foo, got %q", creator.Namespace)
+	}
+	if len(creator.Items) != 3 {
+		t.Fatalf("Expected the template item, the template instance and the admin binding, got %#v", creator.Items)
+	}
+	if service, ok := creator.Items[0].(*kapi.Service); !ok || service.Namespace != "" || service.Labels[templateapi.TemplateInstanceLabel] != DefaultTemplateName {
+		t.Errorf("Expected a service without a namespace labeled with the template instance, got %#v", creator.Items[0])
+	}
+	instance, ok := creator.Items[1].(*templateapi.TemplateInstance)
+	if !ok {
+		t.Fatalf("Expected a template instance, got %#v", creator.Items[1])
+	}
+	if instance.Name != DefaultTemplateName || instance.Namespace != "foo" || len(instance.Objects) != 0 {
+		t.Errorf("Unexpected template instance: %#v", instance)
+	}
+	binding, ok := creator.Items[2].(*authorizationapi.RoleBinding)
+	if !ok {
+		t.Fatalf("Expected a role binding, got %#v", creator.Items[2])
+	}
+	if binding.RoleRef.Name != "admin" || binding.RoleRef.Namespace != "master" || len(binding.UserNames) != 1 || binding.UserNames[0] != "bob" {
+		t.Errorf("Unexpected role binding: %#v", binding)
+	}
+}
+
+func TestCreateProjectRequestCreateError(t *testing.T) {
+	creator := &fakeCreator{Errs: []error{errors.NewAlreadyExists("service", "frontend")}}
+	storage := NewREST(test.NewProjectRegistry(), projectTemplate(), creator, "master")
+
+	channel, err := storage.(*REST).Create(kapi.NewContext(), &api.ProjectRequest{
+		ObjectMeta: kapi.ObjectMeta{Name: "foo", Annotations: map[string]string{api.ProjectRequesterAnnotation: "bob"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := waitForResult(t, channel)
+	status, ok := result.Object.(*kapi.Status)
+	if !ok || status.Status != kapi.StatusFailure {
+		t.Errorf("Expected a failure status, got %#v", result.Object)
+	}
+}
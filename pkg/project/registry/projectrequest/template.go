@@ -0,0 +1,34 @@
+package projectrequest
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	templateapi "github.com/openshift/origin/pkg/template/api"
+)
+
+// The parameters made available to a project template when it is instantiated for a request.
+const (
+	ProjectNameParam        = "PROJECT_NAME"
+	ProjectDisplayNameParam = "PROJECT_DISPLAYNAME"
+	ProjectRequesterParam   = "PROJECT_REQUESTING_USER"
+)
+
+// DefaultTemplateName is the name of the template used when no project template is configured.
+const DefaultTemplateName = "project-request"
+
+// DefaultTemplate returns the template instantiated for project requests when no other template has
+// been configured. It creates no additional objects; the requester is always made the project admin.
+func DefaultTemplate() *templateapi.Template {
+	return &templateapi.Template{
+		ObjectMeta: kapi.ObjectMeta{
+			Name: DefaultTemplateName,
+		},
+		Items: []runtime.Object{},
+		Parameters: []templateapi.Parameter{
+			{Name: ProjectNameParam, Description: "The name of the requested project"},
+			{Name: ProjectDisplayNameParam, Description: "The display name of the requested project"},
+			{Name: ProjectRequesterParam, Description: "The name of the user that requested the project"},
+		},
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 	"github.com/openshift/origin/pkg/project/api"
 )
 
@@ -55,3 +56,7 @@ func (r *ProjectRegistry) DeleteProject(ctx kapi.Context, id string) error {
 
 	return r.Err
 }
+
+func (r *ProjectRegistry) WatchProjects(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return nil, r.Err
+}
@@ -0,0 +1,118 @@
+package admission
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/golang/glog"
+
+	"github.com/openshift/origin/pkg/quota"
+)
+
+// resourceQuota enforces the hard limits of the ResourceQuotas in a project when OpenShift
+// resources are created.
+type resourceQuota struct {
+	client   kclient.Interface
+	measures quota.MeasuresFunc
+}
+
+// NewResourceQuota returns an admission.Interface that rejects the creation of builds, deployment
+// configs, image repositories, routes and image repository mappings when doing so would exceed a
+// ResourceQuota in the project. Quotas are read with client, and the current usage and the amount the
+// new object consumes are measured with the functions measures returns for each admission.
+func NewResourceQuota(client kclient.Interface, measures quota.MeasuresFunc) admission.Interface {
+	return &resourceQuota{
+		client:   client,
+		measures: measures,
+	}
+}
+
+// Admit measures the current usage of every constrained resource the new object consumes and
+// rejects the request if any quota in the namespace would be exceeded.
+func (q *resourceQuota) Admit(a admission.Attributes) error {
+	if a.GetOperation() != "CREATE" {
+		return nil
+	}
+	resources := quota.ResourcesForKind(a.GetKind())
+	if len(resources) == 0 {
+		return nil
+	}
+
+	quotas, err := q.client.ResourceQuotas(a.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		return errors.NewInternalError(err)
+	}
+
+	usage, consume := q.measures()
+	for i := range quotas.Items {
+		resourceQuota := &quotas.Items[i]
+		used, changed, err := admitQuota(resourceQuota, resources, a.GetObject(), usage, consume)
+		if err != nil {
+			return errors.NewForbidden(a.GetKind(), "", err)
+		}
+		if !changed {
+			continue
+		}
+		// Record the new usage on a best effort basis; the usage controller will correct any drift.
+		usage := &kapi.ResourceQuotaUsage{
+			ObjectMeta: kapi.ObjectMeta{
+				Name:            resourceQuota.Name,
+				Namespace:       resourceQuota.Namespace,
+				ResourceVersion: resourceQuota.ResourceVersion,
+			},
+			Status: kapi.ResourceQuotaStatus{
+				Hard: resourceQuota.Spec.Hard,
+				Used: used,
+			},
+		}
+		if err := q.client.ResourceQuotaUsages(usage.Namespace).Create(usage); err != nil {
+			glog.V(4).Infof("Unable to record usage for quota %s/%s: %v", usage.Namespace, usage.Name, err)
+		}
+	}
+	return nil
+}
+
+// admitQuota returns the usage of resourceQuota after admitting obj, which consumes resources, and
+// whether any tracked value changed, measured with usage and consume. An error is returned if a hard
+// limit would be exceeded.
+func admitQuota(resourceQuota *kapi.ResourceQuota, resources []kapi.ResourceName, obj runtime.Object, usage quota.UsageFuncs, consume quota.ConsumeFuncs) (kapi.ResourceList, bool, error) {
+	used := kapi.ResourceList{}
+	for name, value := range resourceQuota.Status.Used {
+		used[name] = value
+	}
+
+	changed := false
+	for _, name := range resources {
+		hard, ok := resourceQuota.Spec.Hard[name]
+		if !ok {
+			continue
+		}
+		fn, ok := usage[name]
+		if !ok {
+			continue
+		}
+		consumed, err := consume.Consumed(name, resourceQuota.Namespace, obj)
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to measure consumption of %s: %v", name, err)
+		}
+		if consumed == 0 {
+			continue
+		}
+		observed, err := fn(resourceQuota.Namespace)
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to measure usage of %s: %v", name, err)
+		}
+		if observed+consumed > hard.Value() {
+			return nil, false, fmt.Errorf("limited to %s %s by quota %s", hard.String(), name, resourceQuota.Name)
+		}
+		used[name] = *resource.NewQuantity(observed+consumed, resource.DecimalSI)
+		changed = true
+	}
+	return used, changed, nil
+}
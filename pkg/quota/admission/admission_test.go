@@ -0,0 +1,145 @@
+package admission
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	imageapi "github.com/openshift/origin/pkg/image/api"
+	"github.com/openshift/origin/pkg/quota"
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+func quotaClient(hard kapi.ResourceList) *kclient.Fake {
+	return &kclient.Fake{
+		ResourceQuotasList: kapi.ResourceQuotaList{
+			Items: []kapi.ResourceQuota{
+				{
+					ObjectMeta: kapi.ObjectMeta{Name: "quota", Namespace: "test", ResourceVersion: "1"},
+					Spec:       kapi.ResourceQuotaSpec{Hard: hard},
+				},
+			},
+		},
+	}
+}
+
+// measures returns a MeasuresFunc that returns usage and consume for every admission.
+func measures(usage quota.UsageFuncs, consume quota.ConsumeFuncs) quota.MeasuresFunc {
+	return func() (quota.UsageFuncs, quota.ConsumeFuncs) {
+		return usage, consume
+	}
+}
+
+func routeUsage(count int64) quota.UsageFuncs {
+	return quota.UsageFuncs{
+		quota.ResourceRoutes: func(namespace string) (int64, error) {
+			return count, nil
+		},
+	}
+}
+
+func TestAdmitUnderQuota(t *testing.T) {
+	client := quotaClient(kapi.ResourceList{quota.ResourceRoutes: resource.MustParse("2")})
+	handler := NewResourceQuota(client, measures(routeUsage(1), nil))
+
+	err := handler.Admit(admission.NewAttributesRecord(&routeapi.Route{}, "test", "routes", "CREATE"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(client.Actions) != 2 || client.Actions[1].Action != "create-resourceQuotaUsage" {
+		t.Errorf("expected usage to be recorded, got %#v", client.Actions)
+	}
+}
+
+func TestAdmitOverQuota(t *testing.T) {
+	client := quotaClient(kapi.ResourceList{quota.ResourceRoutes: resource.MustParse("2")})
+	handler := NewResourceQuota(client, measures(routeUsage(2), nil))
+
+	err := handler.Admit(admission.NewAttributesRecord(&routeapi.Route{}, "test", "routes", "CREATE"))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !errors.IsForbidden(err) {
+		t.Errorf("expected a forbidden error, got %v", err)
+	}
+}
+
+func TestAdmitIgnoresUntrackedRequests(t *testing.T) {
+	client := quotaClient(kapi.ResourceList{quota.ResourceRoutes: resource.MustParse("0")})
+	handler := NewResourceQuota(client, measures(routeUsage(5), nil))
+
+	if err := handler.Admit(admission.NewAttributesRecord(&routeapi.Route{}, "test", "routes", "UPDATE")); err != nil {
+		t.Errorf("unexpected error for update: %v", err)
+	}
+	if err := handler.Admit(admission.NewAttributesRecord(&kapi.Pod{}, "test", "pods", "CREATE")); err != nil {
+		t.Errorf("unexpected error for untracked kind: %v", err)
+	}
+	if len(client.Actions) != 0 {
+		t.Errorf("unexpected client actions: %#v", client.Actions)
+	}
+}
+
+func TestAdmitIgnoresUnconstrainedResources(t *testing.T) {
+	client := quotaClient(kapi.ResourceList{quota.ResourceBuilds: resource.MustParse("0")})
+	handler := NewResourceQuota(client, measures(routeUsage(5), nil))
+
+	if err := handler.Admit(admission.NewAttributesRecord(&routeapi.Route{}, "test", "routes", "CREATE")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAdmitImageRepositoryMapping(t *testing.T) {
+	usage := quota.UsageFuncs{
+		quota.ResourceImages:    func(namespace string) (int64, error) { return 2, nil },
+		quota.ResourceImageSize: func(namespace string) (int64, error) { return 1000, nil },
+	}
+	consume := quota.ConsumeFuncs{
+		quota.ResourceImages: func(namespace string, obj runtime.Object) (int64, error) {
+			if obj.(*imageapi.ImageRepositoryMapping).Image.Name == "tagged" {
+				return 0, nil
+			}
+			return 1, nil
+		},
+		quota.ResourceImageSize: func(namespace string, obj runtime.Object) (int64, error) {
+			if obj.(*imageapi.ImageRepositoryMapping).Image.Name == "tagged" {
+				return 0, nil
+			}
+			return obj.(*imageapi.ImageRepositoryMapping).Image.DockerImageMetadata.Size, nil
+		},
+	}
+	mapping := func(name string, size int64) *imageapi.ImageRepositoryMapping {
+		m := &imageapi.ImageRepositoryMapping{Tag: "latest"}
+		m.Image.Name = name
+		m.Image.DockerImageMetadata.Size = size
+		return m
+	}
+
+	testCases := map[string]struct {
+		hard    kapi.ResourceList
+		mapping *imageapi.ImageRepositoryMapping
+		allowed bool
+	}{
+		"under image quota":  {kapi.ResourceList{quota.ResourceImages: resource.MustParse("3")}, mapping("new", 10), true},
+		"over image quota":   {kapi.ResourceList{quota.ResourceImages: resource.MustParse("2")}, mapping("new", 10), false},
+		"under size quota":   {kapi.ResourceList{quota.ResourceImageSize: resource.MustParse("1010")}, mapping("new", 10), true},
+		"over size quota":    {kapi.ResourceList{quota.ResourceImageSize: resource.MustParse("1k")}, mapping("new", 10), false},
+		"tagged image":       {kapi.ResourceList{quota.ResourceImages: resource.MustParse("2"), quota.ResourceImageSize: resource.MustParse("1k")}, mapping("tagged", 10), true},
+		"untracked resource": {kapi.ResourceList{quota.ResourceRoutes: resource.MustParse("0")}, mapping("new", 10), true},
+	}
+	for name, test := range testCases {
+		client := quotaClient(test.hard)
+		handler := NewResourceQuota(client, measures(usage, consume))
+		err := handler.Admit(admission.NewAttributesRecord(test.mapping, "test", "imageRepositoryMappings", "CREATE"))
+		switch {
+		case test.allowed && err != nil:
+			t.Errorf("%s: unexpected error: %v", name, err)
+		case !test.allowed && !errors.IsForbidden(err):
+			t.Errorf("%s: expected a forbidden error, got %v", name, err)
+		}
+	}
+}
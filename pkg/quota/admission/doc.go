@@ -0,0 +1,3 @@
+// Package admission contains an admission controller that rejects the creation of OpenShift
+// resources once a project has reached one of the hard limits of its ResourceQuotas.
+package admission
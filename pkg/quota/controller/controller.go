@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+
+	oscontroller "github.com/openshift/origin/pkg/controller"
+	"github.com/openshift/origin/pkg/quota"
+)
+
+// UsageController periodically recalculates the usage of OpenShift resources in every project
+// that has a ResourceQuota and records the result in the quota status. Recalculation corrects
+// drift caused by deletions and by builds leaving the hourly window.
+type UsageController struct {
+	// Client is used to list ResourceQuotas and record their usage.
+	Client kclient.Interface
+	// Usage measures the usage of each OpenShift resource.
+	Usage quota.UsageFuncs
+	// Period is the interval between recalculations.
+	Period time.Duration
+	// Synced is an optional function called after each recalculation.
+	Synced func()
+	// Crashed is an optional function called with the value of a panic in the controller.
+	Crashed func(interface{})
+	// Stop is an optional channel that controls when the controller exits.
+	Stop <-chan struct{}
+}
+
+// Run begins recalculating quota usage every Period.
+func (c *UsageController) Run() {
+	go oscontroller.Until(func() {
+		c.HandleQuotas()
+		if c.Synced != nil {
+			c.Synced()
+		}
+	}, c.Period, c.Stop, c.Crashed)
+}
+
+// HandleQuotas recalculates the usage of every ResourceQuota in the cluster.
+func (c *UsageController) HandleQuotas() {
+	quotas, err := c.Client.ResourceQuotas(kapi.NamespaceAll).List(labels.Everything())
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to list resource quotas: %v", err))
+		return
+	}
+	for i := range quotas.Items {
+		if err := c.syncQuota(&quotas.Items[i]); err != nil {
+			util.HandleError(fmt.Errorf("unable to sync usage of quota %s/%s: %v", quotas.Items[i].Namespace, quotas.Items[i].Name, err))
+		}
+	}
+}
+
+// syncQuota records the observed usage of resourceQuota if it differs from its status.
+func (c *UsageController) syncQuota(resourceQuota *kapi.ResourceQuota) error {
+	observed, err := c.Usage.Usage(resourceQuota.Namespace, resourceQuota.Spec.Hard)
+	if err != nil {
+		return err
+	}
+
+	dirty := !kapi.Semantic.DeepEqual(resourceQuota.Spec.Hard, resourceQuota.Status.Hard)
+	used := kapi.ResourceList{}
+	for name, value := range resourceQuota.Status.Used {
+		used[name] = value
+	}
+	for name, value := range observed {
+		if current, ok := used[name]; !ok || current.Value() != value.Value() {
+			dirty = true
+		}
+		used[name] = value
+	}
+	if !dirty {
+		return nil
+	}
+
+	glog.V(4).Infof("Updating usage of quota %s/%s", resourceQuota.Namespace, resourceQuota.Name)
+	usage := &kapi.ResourceQuotaUsage{
+		ObjectMeta: kapi.ObjectMeta{
+			Name:            resourceQuota.Name,
+			Namespace:       resourceQuota.Namespace,
+			ResourceVersion: resourceQuota.ResourceVersion,
+		},
+		Status: kapi.ResourceQuotaStatus{
+			Hard: resourceQuota.Spec.Hard,
+			Used: used,
+		},
+	}
+	return c.Client.ResourceQuotaUsages(usage.Namespace).Create(usage)
+}
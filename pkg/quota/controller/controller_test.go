@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+
+	"github.com/openshift/origin/pkg/quota"
+)
+
+func testQuota(used int64) kapi.ResourceQuota {
+	hard := kapi.ResourceList{quota.ResourceImageRepositories: resource.MustParse("10")}
+	return kapi.ResourceQuota{
+		ObjectMeta: kapi.ObjectMeta{Name: "quota", Namespace: "test", ResourceVersion: "1"},
+		Spec:       kapi.ResourceQuotaSpec{Hard: hard},
+		Status: kapi.ResourceQuotaStatus{
+			Hard: hard,
+			Used: kapi.ResourceList{quota.ResourceImageRepositories: *resource.NewQuantity(used, resource.DecimalSI)},
+		},
+	}
+}
+
+func testUsage(count int64) quota.UsageFuncs {
+	return quota.UsageFuncs{
+		quota.ResourceImageRepositories: func(namespace string) (int64, error) {
+			return count, nil
+		},
+	}
+}
+
+func TestHandleQuotasRecordsChangedUsage(t *testing.T) {
+	client := &kclient.Fake{ResourceQuotasList: kapi.ResourceQuotaList{Items: []kapi.ResourceQuota{testQuota(1)}}}
+	controller := &UsageController{Client: client, Usage: testUsage(4)}
+
+	controller.HandleQuotas()
+
+	if len(client.Actions) != 2 || client.Actions[1].Action != "create-resourceQuotaUsage" {
+		t.Errorf("expected usage to be recorded, got %#v", client.Actions)
+	}
+}
+
+func TestHandleQuotasSkipsUnchangedUsage(t *testing.T) {
+	client := &kclient.Fake{ResourceQuotasList: kapi.ResourceQuotaList{Items: []kapi.ResourceQuota{testQuota(4)}}}
+	controller := &UsageController{Client: client, Usage: testUsage(4)}
+
+	controller.HandleQuotas()
+
+	if len(client.Actions) != 1 {
+		t.Errorf("expected only a list action, got %#v", client.Actions)
+	}
+}
@@ -0,0 +1,3 @@
+// Package controller contains the controller that keeps the observed usage of OpenShift
+// resources recorded on ResourceQuotas up to date.
+package controller
@@ -0,0 +1,3 @@
+// Package quota defines the OpenShift resources that may be constrained by a project's
+// ResourceQuota and provides the means to measure their usage.
+package quota
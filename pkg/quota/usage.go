@@ -0,0 +1,326 @@
+package quota
+
+import (
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	osclient "github.com/openshift/origin/pkg/client"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+// The following identify the OpenShift resources that may be constrained by a ResourceQuota
+const (
+	// ResourceBuilds is the total number of builds in a project
+	ResourceBuilds kapi.ResourceName = "openshift.io/builds"
+	// ResourceBuildsPerHour is the number of builds created in a project within the last hour
+	ResourceBuildsPerHour kapi.ResourceName = "openshift.io/buildsperhour"
+	// ResourceDeploymentConfigs is the total number of deployment configs in a project
+	ResourceDeploymentConfigs kapi.ResourceName = "openshift.io/deploymentconfigs"
+	// ResourceImageRepositories is the total number of image repositories in a project
+	ResourceImageRepositories kapi.ResourceName = "openshift.io/imagerepositories"
+	// ResourceImages is the number of distinct images tagged in the image repositories of a project
+	ResourceImages kapi.ResourceName = "openshift.io/images"
+	// ResourceImageSize is the total size in bytes of the layers of the images tagged in a project,
+	// as reported by the registry.  A layer shared by several images is counted once.
+	ResourceImageSize kapi.ResourceName = "openshift.io/imagesize"
+	// ResourceRoutes is the total number of routes in a project
+	ResourceRoutes kapi.ResourceName = "openshift.io/routes"
+)
+
+// resourceForKind maps the REST resource names used by the OpenShift API to the quota
+// resources whose usage grows when an object of that kind is created.
+var resourceForKind = map[string][]kapi.ResourceName{
+	"builds":            {ResourceBuilds, ResourceBuildsPerHour},
+	"deploymentConfigs": {ResourceDeploymentConfigs},
+	"imageRepositories": {ResourceImageRepositories},
+	// a mapping tags an image in a repository; it consumes nothing if the image is already tagged in the project
+	"imageRepositoryMappings": {ResourceImages, ResourceImageSize},
+	"routes":                  {ResourceRoutes},
+}
+
+// ResourcesForKind returns the quota resources that are consumed by creating an object of
+// the provided REST kind, or nil if the kind is not subject to quota.
+func ResourcesForKind(kind string) []kapi.ResourceName {
+	return resourceForKind[kind]
+}
+
+// UsageFunc returns the observed usage of a single resource in a namespace.
+type UsageFunc func(namespace string) (int64, error)
+
+// UsageFuncs maps each quota resource to the function that measures its usage.
+type UsageFuncs map[kapi.ResourceName]UsageFunc
+
+// NewUsageFuncs returns UsageFuncs that measure usage by listing objects with the provided client.
+// now is used to determine the window for rate based resources.
+func NewUsageFuncs(client osclient.Interface, now func() time.Time) UsageFuncs {
+	return newUsageFuncs(client, now, func(namespace string) (*TaggedImages, error) {
+		return ListTaggedImages(client, namespace)
+	})
+}
+
+// MeasuresFunc returns the functions that measure the usage of resources and the consumption of a new
+// object in a single admission, which share what they read.
+type MeasuresFunc func() (UsageFuncs, ConsumeFuncs)
+
+// NewMeasuresFunc returns a MeasuresFunc whose functions list objects with client, and list the images
+// tagged in a project at most once per admission.  now is used to determine the window for rate based
+// resources.
+func NewMeasuresFunc(client osclient.Interface, now func() time.Time) MeasuresFunc {
+	return func() (UsageFuncs, ConsumeFuncs) {
+		tagged := map[string]*TaggedImages{}
+		images := func(namespace string) (*TaggedImages, error) {
+			if images, ok := tagged[namespace]; ok {
+				return images, nil
+			}
+			images, err := ListTaggedImages(client, namespace)
+			if err != nil {
+				return nil, err
+			}
+			tagged[namespace] = images
+			return images, nil
+		}
+		return newUsageFuncs(client, now, images), newConsumeFuncs(images)
+	}
+}
+
+// taggedImagesFunc returns the images tagged in a namespace.
+type taggedImagesFunc func(namespace string) (*TaggedImages, error)
+
+// newUsageFuncs returns UsageFuncs that list objects with client and the tagged images with images.
+func newUsageFuncs(client osclient.Interface, now func() time.Time, images taggedImagesFunc) UsageFuncs {
+	return UsageFuncs{
+		ResourceBuilds: func(namespace string) (int64, error) {
+			list, err := client.Builds(namespace).List(labels.Everything(), labels.Everything())
+			if err != nil {
+				return 0, err
+			}
+			return int64(len(list.Items)), nil
+		},
+		ResourceBuildsPerHour: func(namespace string) (int64, error) {
+			list, err := client.Builds(namespace).List(labels.Everything(), labels.Everything())
+			if err != nil {
+				return 0, err
+			}
+			return CountBuildsSince(list, now().Add(-time.Hour)), nil
+		},
+		ResourceDeploymentConfigs: func(namespace string) (int64, error) {
+			list, err := client.DeploymentConfigs(namespace).List(labels.Everything(), labels.Everything())
+			if err != nil {
+				return 0, err
+			}
+			return int64(len(list.Items)), nil
+		},
+		ResourceImageRepositories: func(namespace string) (int64, error) {
+			list, err := client.ImageRepositories(namespace).List(labels.Everything(), labels.Everything())
+			if err != nil {
+				return 0, err
+			}
+			return int64(len(list.Items)), nil
+		},
+		ResourceImages: func(namespace string) (int64, error) {
+			tagged, err := images(namespace)
+			if err != nil {
+				return 0, err
+			}
+			return int64(len(tagged.Names)), nil
+		},
+		ResourceImageSize: func(namespace string) (int64, error) {
+			tagged, err := images(namespace)
+			if err != nil {
+				return 0, err
+			}
+			return tagged.Size(), nil
+		},
+		ResourceRoutes: func(namespace string) (int64, error) {
+			list, err := client.Routes(namespace).List(labels.Everything(), labels.Everything())
+			if err != nil {
+				return 0, err
+			}
+			return int64(len(list.Items)), nil
+		},
+	}
+}
+
+// ConsumeFunc returns how much of a resource creating obj in a namespace consumes.
+type ConsumeFunc func(namespace string, obj runtime.Object) (int64, error)
+
+// ConsumeFuncs maps the quota resources whose consumption depends on the created object to the
+// function that measures it. Creating an object consumes one of any other resource.
+type ConsumeFuncs map[kapi.ResourceName]ConsumeFunc
+
+// newConsumeFuncs returns ConsumeFuncs that look up the images already tagged in a project with images,
+// so that tagging them again, or the layers they share with them, consumes nothing.
+func newConsumeFuncs(images taggedImagesFunc) ConsumeFuncs {
+	return ConsumeFuncs{
+		ResourceImages: func(namespace string, obj runtime.Object) (int64, error) {
+			mapping, ok := obj.(*imageapi.ImageRepositoryMapping)
+			if !ok {
+				return 1, nil
+			}
+			tagged, err := images(namespace)
+			if err != nil {
+				return 0, err
+			}
+			if tagged.Names.Has(mapping.Image.Name) {
+				return 0, nil
+			}
+			return 1, nil
+		},
+		ResourceImageSize: func(namespace string, obj runtime.Object) (int64, error) {
+			mapping, ok := obj.(*imageapi.ImageRepositoryMapping)
+			if !ok {
+				return 0, nil
+			}
+			tagged, err := images(namespace)
+			if err != nil {
+				return 0, err
+			}
+			size := int64(0)
+			for id, layerSize := range tagged.layers(&mapping.Image) {
+				if _, counted := tagged.Layers[id]; !counted {
+					size += layerSize
+				}
+			}
+			return size, nil
+		},
+	}
+}
+
+// Consumed returns how much of resource creating obj in namespace consumes.
+func (c ConsumeFuncs) Consumed(resource kapi.ResourceName, namespace string, obj runtime.Object) (int64, error) {
+	fn, ok := c[resource]
+	if !ok {
+		return 1, nil
+	}
+	return fn(namespace, obj)
+}
+
+// TaggedImages are the images tagged in the image repositories of a project and the layers they are
+// built from.
+type TaggedImages struct {
+	// Names are the names of the tagged images
+	Names util.StringSet
+	// Layers are the sizes in bytes of the layers of the tagged images by layer id, so a layer shared
+	// by several images is counted once
+	Layers map[string]int64
+
+	// images are the images known to the server by layer id, through which the parent layers of an image
+	// are found
+	images map[string]*imageapi.Image
+}
+
+// ListTaggedImages returns the images tagged in the image repositories of namespace.  The layers of an
+// image are found through the images known to the server by the id of its parent layer, a layer whose
+// image is not known ends the layers of an image.  Images that are tagged but were not found have no
+// layers.
+func ListTaggedImages(client osclient.Interface, namespace string) (*TaggedImages, error) {
+	repos, err := client.ImageRepositories(namespace).List(labels.Everything(), labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	images, err := client.Images(namespace).List(labels.Everything(), labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return newTaggedImages(repos.Items, images.Items), nil
+}
+
+// newTaggedImages returns the images of images tagged in repos.
+func newTaggedImages(repos []imageapi.ImageRepository, images []imageapi.Image) *TaggedImages {
+	tagged := &TaggedImages{
+		Names:  util.NewStringSet(),
+		Layers: make(map[string]int64),
+		images: make(map[string]*imageapi.Image),
+	}
+	byName := make(map[string]*imageapi.Image)
+	for i := range images {
+		image := &images[i]
+		byName[image.Name] = image
+		tagged.images[layerID(image)] = image
+	}
+	for _, repo := range repos {
+		for _, name := range repo.Tags {
+			tagged.Names.Insert(name)
+			image, ok := byName[name]
+			if !ok {
+				continue
+			}
+			for id, size := range tagged.layers(image) {
+				tagged.Layers[id] = size
+			}
+		}
+	}
+	return tagged
+}
+
+// Size returns the total size in bytes of the layers of the tagged images.
+func (t *TaggedImages) Size() int64 {
+	size := int64(0)
+	for _, layerSize := range t.Layers {
+		size += layerSize
+	}
+	return size
+}
+
+// layers returns the sizes of the layers of image by layer id: its own layer and those of its parents
+// known to the server.
+func (t *TaggedImages) layers(image *imageapi.Image) map[string]int64 {
+	layers := map[string]int64{}
+	for image != nil {
+		id := layerID(image)
+		if _, seen := layers[id]; seen {
+			break
+		}
+		layers[id] = image.DockerImageMetadata.Size
+		parent := image.DockerImageMetadata.Parent
+		if len(parent) == 0 {
+			break
+		}
+		image = t.images[parent]
+	}
+	return layers
+}
+
+// layerID returns the id of the top layer of image, which the Docker metadata of its children name as
+// their parent.
+func layerID(image *imageapi.Image) string {
+	if len(image.DockerImageMetadata.ID) > 0 {
+		return image.DockerImageMetadata.ID
+	}
+	return image.Name
+}
+
+// Usage measures the usage of every OpenShift resource named in hard and returns the result.
+// Resources that are not tracked by these UsageFuncs are ignored.
+func (u UsageFuncs) Usage(namespace string, hard kapi.ResourceList) (kapi.ResourceList, error) {
+	used := kapi.ResourceList{}
+	for name := range hard {
+		fn, ok := u[name]
+		if !ok {
+			continue
+		}
+		value, err := fn(namespace)
+		if err != nil {
+			return nil, err
+		}
+		used[name] = *resource.NewQuantity(value, resource.DecimalSI)
+	}
+	return used, nil
+}
+
+// CountBuildsSince returns the number of builds in list created at or after since.
+func CountBuildsSince(list *buildapi.BuildList, since time.Time) int64 {
+	count := int64(0)
+	for _, build := range list.Items {
+		if !build.CreationTimestamp.Time.Before(since) {
+			count++
+		}
+	}
+	return count
+}
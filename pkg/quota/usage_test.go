@@ -0,0 +1,142 @@
+package quota
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	osclient "github.com/openshift/origin/pkg/client"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+func TestCountBuildsSince(t *testing.T) {
+	now := time.Date(2015, 1, 1, 12, 0, 0, 0, time.UTC)
+	list := &buildapi.BuildList{
+		Items: []buildapi.Build{
+			{ObjectMeta: kapi.ObjectMeta{Name: "old", CreationTimestamp: util.NewTime(now.Add(-2 * time.Hour))}},
+			{ObjectMeta: kapi.ObjectMeta{Name: "edge", CreationTimestamp: util.NewTime(now.Add(-time.Hour))}},
+			{ObjectMeta: kapi.ObjectMeta{Name: "new", CreationTimestamp: util.NewTime(now.Add(-time.Minute))}},
+		},
+	}
+	if count := CountBuildsSince(list, now.Add(-time.Hour)); count != 2 {
+		t.Errorf("expected 2 builds, got %d", count)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	usage := UsageFuncs{
+		ResourceRoutes: func(namespace string) (int64, error) {
+			if namespace != "test" {
+				t.Errorf("unexpected namespace %s", namespace)
+			}
+			return 3, nil
+		},
+	}
+	hard := kapi.ResourceList{
+		ResourceRoutes:    resource.MustParse("10"),
+		kapi.ResourcePods: resource.MustParse("5"),
+	}
+
+	used, err := usage.Usage("test", hard)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(used) != 1 {
+		t.Fatalf("expected only tracked resources to be measured, got %#v", used)
+	}
+	if value := used[ResourceRoutes]; value.Value() != 3 {
+		t.Errorf("expected 3 routes, got %s", value.String())
+	}
+}
+
+func TestUsageError(t *testing.T) {
+	usage := UsageFuncs{
+		ResourceRoutes: func(namespace string) (int64, error) {
+			return 0, errors.New("failed")
+		},
+	}
+	if _, err := usage.Usage("test", kapi.ResourceList{ResourceRoutes: resource.MustParse("1")}); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestResourcesForKind(t *testing.T) {
+	if resources := ResourcesForKind("builds"); len(resources) != 2 {
+		t.Errorf("expected builds to consume two resources, got %v", resources)
+	}
+	if resources := ResourcesForKind("pods"); resources != nil {
+		t.Errorf("expected pods to be ignored, got %v", resources)
+	}
+}
+
+func TestTaggedImagesCountSharedLayersOnce(t *testing.T) {
+	image := func(name, parent string, size int64) imageapi.Image {
+		image := imageapi.Image{ObjectMeta: kapi.ObjectMeta{Name: name}}
+		image.DockerImageMetadata.Parent = parent
+		image.DockerImageMetadata.Size = size
+		return image
+	}
+	images := []imageapi.Image{
+		image("base", "", 100),
+		image("ruby", "base", 10),
+		image("python", "base", 20),
+		image("perl", "base", 30),
+	}
+	repos := []imageapi.ImageRepository{
+		{Tags: map[string]string{"latest": "ruby"}},
+		{Tags: map[string]string{"latest": "python", "missing": "unknown"}},
+	}
+	tagged := newTaggedImages(repos, images)
+
+	if !tagged.Names.HasAll("ruby", "python", "unknown") || len(tagged.Names) != 3 {
+		t.Errorf("unexpected tagged images: %v", tagged.Names.List())
+	}
+	if size := tagged.Size(); size != 130 {
+		t.Errorf("expected the base layer to be counted once, got %d", size)
+	}
+
+	consume := newConsumeFuncs(func(namespace string) (*TaggedImages, error) { return tagged, nil })
+	perl := &imageapi.ImageRepositoryMapping{Image: image("perl", "base", 30)}
+	if size, err := consume.Consumed(ResourceImageSize, "test", perl); err != nil || size != 30 {
+		t.Errorf("expected only the new layer of the image to be consumed, got %d %v", size, err)
+	}
+	ruby := &imageapi.ImageRepositoryMapping{Image: image("ruby", "base", 10)}
+	if count, err := consume.Consumed(ResourceImages, "test", ruby); err != nil || count != 0 {
+		t.Errorf("expected a tagged image to consume nothing, got %d %v", count, err)
+	}
+}
+
+func TestMeasuresListTaggedImagesOncePerAdmission(t *testing.T) {
+	client := &osclient.Fake{}
+	measures := NewMeasuresFunc(client, time.Now)
+	usage, consume := measures()
+	if _, err := usage[ResourceImages]("test"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := usage[ResourceImageSize]("test"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := consume.Consumed(ResourceImageSize, "test", &imageapi.ImageRepositoryMapping{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	listed := 0
+	for _, action := range client.Actions {
+		if action.Action == "list-images" {
+			listed++
+		}
+	}
+	if listed != 1 {
+		t.Errorf("expected the images to be listed once, got %d", listed)
+	}
+
+	usage, _ = measures()
+	usage[ResourceImages]("test")
+	if len(client.Actions) == 0 || client.Actions[len(client.Actions)-1].Action != "list-images" {
+		t.Errorf("expected the next admission to list the images again, got %v", client.Actions)
+	}
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/api/selectable"
+)
+
+func init() {
+	selectable.Register(&Route{}, func(obj runtime.Object) labels.Set { return RouteToSelectableFields(obj.(*Route)) })
+}
+
+// RouteToSelectableFields returns the fields of a route that clients can select on.
+func RouteToSelectableFields(route *Route) labels.Set {
+	return labels.Set{
+		"name":        route.Name,
+		"host":        route.Host,
+		"serviceName": route.ServiceName,
+	}
+}
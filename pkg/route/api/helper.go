@@ -0,0 +1,71 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RouteOlderThan returns true if route a claimed its host before route b. Routes that claimed their
+// hosts at the same time are ordered by namespace and name so that every component agrees on a single
+// owner.
+func RouteOlderThan(a, b *Route) bool {
+	aClaimed, bClaimed := RouteHostClaimed(a), RouteHostClaimed(b)
+	if !aClaimed.Equal(bClaimed) {
+		return aClaimed.Before(bClaimed)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+// RouteHostClaimed returns the time route claimed its host and path, recorded in
+// RouteHostClaimedAnnotation, or the time route was created if no valid time is recorded.
+func RouteHostClaimed(route *Route) time.Time {
+	if value, ok := route.Annotations[RouteHostClaimedAnnotation]; ok {
+		if claimed, err := time.Parse(time.RFC3339, value); err == nil {
+			return claimed
+		}
+	}
+	return route.CreationTimestamp.Time
+}
+
+const (
+	// DefaultBackendWeight is the weight of a route backend that does not specify one.
+	DefaultBackendWeight = 1
+	// MaxBackendWeight is the largest weight a route backend may have.
+	MaxBackendWeight = 256
+)
+
+// RouteBackendWeights returns the weight of every service that receives traffic for route, keyed
+// by service name.
+func RouteBackendWeights(route *Route) map[string]int {
+	weights := map[string]int{route.ServiceName: backendWeight(route.ServiceWeight)}
+	for _, backend := range route.AlternateBackends {
+		weights[backend.ServiceName] = backendWeight(backend.Weight)
+	}
+	return weights
+}
+
+// backendWeight returns weight, or DefaultBackendWeight if it is not set.
+func backendWeight(weight *int) int {
+	if weight == nil {
+		return DefaultBackendWeight
+	}
+	return *weight
+}
+
+// RouteTCPPort returns the port route asks to be exposed on as a raw TCP service, or 0 if it does not
+// set one. An error is returned if the port is not a number between 1 and 65535.
+func RouteTCPPort(route *Route) (int, error) {
+	value, ok := route.Annotations[RouteTCPPortAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("must be a port number between 1 and 65535")
+	}
+	return port, nil
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+func TestRouteOlderThan(t *testing.T) {
+	now := util.Now()
+	later := util.NewTime(now.Add(time.Minute))
+
+	testCases := map[string]struct {
+		a, b     kapi.ObjectMeta
+		expected bool
+	}{
+		"older": {
+			a:        kapi.ObjectMeta{Namespace: "z", Name: "a", CreationTimestamp: now},
+			b:        kapi.ObjectMeta{Namespace: "a", Name: "a", CreationTimestamp: later},
+			expected: true,
+		},
+		"newer": {
+			a:        kapi.ObjectMeta{Namespace: "a", Name: "a", CreationTimestamp: later},
+			b:        kapi.ObjectMeta{Namespace: "z", Name: "a", CreationTimestamp: now},
+			expected: false,
+		},
+		"same time, by namespace": {
+			a:        kapi.ObjectMeta{Namespace: "a", Name: "z", CreationTimestamp: now},
+			b:        kapi.ObjectMeta{Namespace: "b", Name: "a", CreationTimestamp: now},
+			expected: true,
+		},
+		"claimed its host later": {
+			a:        kapi.ObjectMeta{Namespace: "z", Name: "a", CreationTimestamp: now, Annotations: map[string]string{RouteHostClaimedAnnotation: later.Add(time.Minute).Format(time.RFC3339)}},
+			b:        kapi.ObjectMeta{Namespace: "a", Name: "a", CreationTimestamp: later},
+			expected: false,
+		},
+		"claimed its host earlier": {
+			a:        kapi.ObjectMeta{Namespace: "z", Name: "a", CreationTimestamp: later, Annotations: map[string]string{RouteHostClaimedAnnotation: now.Format(time.RFC3339)}},
+			b:        kapi.ObjectMeta{Namespace: "a", Name: "a", CreationTimestamp: later, Annotations: map[string]string{RouteHostClaimedAnnotation: later.Format(time.RFC3339)}},
+			expected: true,
+		},
+		"invalid claim time": {
+			a:        kapi.ObjectMeta{Namespace: "z", Name: "a", CreationTimestamp: now, Annotations: map[string]string{RouteHostClaimedAnnotation: "yesterday"}},
+			b:        kapi.ObjectMeta{Namespace: "a", Name: "a", CreationTimestamp: later},
+			expected: true,
+		},
+		"same time and namespace, by name": {
+			a:        kapi.ObjectMeta{Namespace: "a", Name: "b", CreationTimestamp: now},
+			b:        kapi.ObjectMeta{Namespace: "a", Name: "a", CreationTimestamp: now},
+			expected: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		if actual := RouteOlderThan(&Route{ObjectMeta: tc.a}, &Route{ObjectMeta: tc.b}); actual != tc.expected {
+			t.Errorf("%s: expected %t, got %t", name, tc.expected, actual)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
 // Route encapsulates the inputs needed to connect an alias to endpoints.
@@ -18,9 +19,16 @@ type Route struct {
 
 	// the name of the service that this route points to
 	ServiceName string `json:"serviceName"`
+	// Optional: the share of traffic sent to ServiceName relative to AlternateBackends, defaults to 1
+	ServiceWeight *int `json:"serviceWeight,omitempty"`
+	// Optional: additional services that receive a weighted share of the route's traffic
+	AlternateBackends []RouteBackend `json:"alternateBackends,omitempty"`
 
 	//TLS provides the ability to configure certificates and termination for the route
 	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// Status is the current state of the route as reported by the routers that process it
+	Status *RouteStatus `json:"status,omitempty"`
 }
 
 // RouteList is a collection of Routes.
@@ -30,6 +38,57 @@ type RouteList struct {
 	Items         []Route `json:"items"`
 }
 
+// RouteBackend is a service that receives a weighted share of a route's traffic.
+type RouteBackend struct {
+	// ServiceName is the name of the service
+	ServiceName string `json:"serviceName"`
+	// Weight is the share of traffic sent to the service relative to the other backends, defaults to 1.
+	// A weight of 0 sends no new traffic to the service.
+	Weight *int `json:"weight,omitempty"`
+}
+
+// RouteStatus describes the routers that have processed a route.
+type RouteStatus struct {
+	// Ingress is the list of routers that have reported on this route, one entry per router
+	Ingress []RouteIngress `json:"ingress,omitempty"`
+	// CertificateExpires is when the certificate of the route expires, set by the server when the route has one
+	CertificateExpires *util.Time `json:"certificateExpires,omitempty"`
+}
+
+// RouteIngress is the state of a route as seen by a single router.
+type RouteIngress struct {
+	// RouterName identifies the router that reported this entry
+	RouterName string `json:"routerName"`
+	// Host is the host the router exposes the route under
+	Host string `json:"host,omitempty"`
+	// Phase is whether the router admitted or rejected the route
+	Phase RouteIngressPhase `json:"phase,omitempty"`
+	// Reason is a brief, machine readable explanation for a rejected route
+	Reason string `json:"reason,omitempty"`
+	// Message is a human readable description of the router's decision
+	Message string `json:"message,omitempty"`
+}
+
+// RouteIngressPhase is the outcome of a router processing a route.
+type RouteIngressPhase string
+
+const (
+	// RouteAdmitted means the router is serving traffic for the route
+	RouteAdmitted RouteIngressPhase = "Admitted"
+	// RouteRejected means the router refused to expose the route
+	RouteRejected RouteIngressPhase = "Rejected"
+)
+
+// RouteStatusUpdate is posted by a router to record its view of a route in the route's status.
+// The name of the update is the name of the route it applies to.
+type RouteStatusUpdate struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	// Ingress replaces the entry for the same router in the route's status
+	Ingress RouteIngress `json:"ingress"`
+}
+
 // TLSConfig defines config used to secure a route and provide termination
 type TLSConfig struct {
 	// Termination indicates termination type.  If termination type is not set, any termination config will be ignored
@@ -47,6 +106,11 @@ type TLSConfig struct {
 	// DestinationCACertificate provides the contents of the ca certificate of the final destination.  When using reencrypt
 	// termination this file should be provided in order to have routers use it for health checks on the secure connection
 	DestinationCACertificate string `json:"destinationCACertificate,omitempty"`
+
+	// InsecureEdgeTerminationPolicy indicates what to do with insecure connections to an edge terminated route.
+	// Allow serves the route over http as well, Redirect sends http clients to https and None refuses http
+	// connections.  Only valid with edge termination, defaults to Allow.
+	InsecureEdgeTerminationPolicy InsecureEdgeTerminationPolicyType `json:"insecureEdgeTerminationPolicy,omitempty"`
 }
 
 // TLSTerminationType dictates where the secure communication will stop
@@ -60,3 +124,64 @@ const (
 	// TLSTerminationReencrypt terminate encryption at the edge router and re-encrypt it with a new certificate supplied by the destination
 	TLSTerminationReencrypt TLSTerminationType = "reencrypt"
 )
+
+// InsecureEdgeTerminationPolicyType dictates what to do with insecure connections to a secured route
+type InsecureEdgeTerminationPolicyType string
+
+const (
+	// InsecureEdgeTerminationPolicyAllow serves the route over http as well as https
+	InsecureEdgeTerminationPolicyAllow InsecureEdgeTerminationPolicyType = "Allow"
+	// InsecureEdgeTerminationPolicyRedirect redirects http requests to https
+	InsecureEdgeTerminationPolicyRedirect InsecureEdgeTerminationPolicyType = "Redirect"
+	// InsecureEdgeTerminationPolicyNone refuses http requests for the route
+	InsecureEdgeTerminationPolicyNone InsecureEdgeTerminationPolicyType = "None"
+)
+
+const (
+	// RouteAffinityAnnotation is an annotation on a route that selects how routers keep a client on
+	// the same endpoint. Valid values are the RouteAffinityType constants.
+	RouteAffinityAnnotation = "router.openshift.io/affinity"
+	// RouteBalanceAnnotation is an annotation on a route that selects the algorithm routers use to pick
+	// an endpoint. Valid values are the RouteBalanceType constants.
+	RouteBalanceAnnotation = "router.openshift.io/balance"
+	// RouteConnectTimeoutAnnotation is an annotation on a route holding the duration, such as "5s", a
+	// router waits for a connection to an endpoint to be established.
+	RouteConnectTimeoutAnnotation = "router.openshift.io/timeout-connect"
+	// RouteServerTimeoutAnnotation is an annotation on a route holding the duration, such as "30s", a
+	// router waits for an endpoint to respond.
+	RouteServerTimeoutAnnotation = "router.openshift.io/timeout-server"
+	// RouteMaxConnectionsAnnotation is an annotation on a route holding the positive number of
+	// concurrent connections a router opens to each endpoint.
+	RouteMaxConnectionsAnnotation = "router.openshift.io/max-connections"
+	// RouteTCPPortAnnotation is an annotation on a route holding the port routers expose the route's
+	// service on as a raw TCP service. Only routes without termination or with passthrough termination
+	// may be exposed on a TCP port.
+	RouteTCPPortAnnotation = "router.openshift.io/tcp-port"
+	// RouteHostClaimedAnnotation is an annotation on a route holding the time, in RFC3339 format, the
+	// route claimed its host and path. It is set by the server when the route is created and whenever
+	// its host or path changes, and the route that claimed a host first owns it.
+	RouteHostClaimedAnnotation = "router.openshift.io/host-claimed"
+)
+
+// RouteBalanceType is the algorithm a router uses to pick an endpoint for a route
+type RouteBalanceType string
+
+const (
+	// RouteBalanceLeastConn picks the endpoint with the fewest connections
+	RouteBalanceLeastConn RouteBalanceType = "leastconn"
+	// RouteBalanceRoundRobin picks each endpoint in turn, according to its weight
+	RouteBalanceRoundRobin RouteBalanceType = "roundrobin"
+)
+
+// RouteAffinityType is the session affinity a router applies to a route
+type RouteAffinityType string
+
+const (
+	// RouteAffinityNone balances every request independently
+	RouteAffinityNone RouteAffinityType = "none"
+	// RouteAffinityCookie inserts a cookie identifying the endpoint that served the client.  Routes
+	// with passthrough termination cannot carry cookies and fall back to source affinity.
+	RouteAffinityCookie RouteAffinityType = "cookie"
+	// RouteAffinitySource sends requests from the same client address to the same endpoint
+	RouteAffinitySource RouteAffinityType = "source"
+)
@@ -0,0 +1,29 @@
+package v1beta1
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/conversion"
+
+	newer "github.com/openshift/origin/pkg/route/api"
+)
+
+// Defaults are set as objects are converted from v1beta1, so objects are fully specified when they are
+// created and when they are read back from storage.
+func init() {
+	err := api.Scheme.AddConversionFuncs(
+		// Edge terminated routes are served over http as well unless another policy is chosen
+		func(in *TLSConfig, out *newer.TLSConfig, s conversion.Scope) error {
+			if err := s.DefaultConvert(in, out, conversion.IgnoreMissingFields); err != nil {
+				return err
+			}
+			if out.Termination == newer.TLSTerminationEdge && len(out.InsecureEdgeTerminationPolicy) == 0 {
+				out.InsecureEdgeTerminationPolicy = newer.InsecureEdgeTerminationPolicyAllow
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		// If one of the conversion functions is malformed, detect it immediately.
+		panic(err)
+	}
+}
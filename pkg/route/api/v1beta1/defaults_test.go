@@ -0,0 +1,31 @@
+package v1beta1_test
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	newer "github.com/openshift/origin/pkg/route/api"
+	_ "github.com/openshift/origin/pkg/route/api/v1beta1"
+)
+
+func TestDefaultInsecureEdgeTerminationPolicy(t *testing.T) {
+	testCases := map[string]struct {
+		tls      string
+		expected newer.InsecureEdgeTerminationPolicyType
+	}{
+		"edge":          {`{"termination":"edge"}`, newer.InsecureEdgeTerminationPolicyAllow},
+		"edge redirect": {`{"termination":"edge","insecureEdgeTerminationPolicy":"Redirect"}`, newer.InsecureEdgeTerminationPolicyRedirect},
+		"passthrough":   {`{"termination":"passthrough"}`, ""},
+	}
+	for name, tc := range testCases {
+		obj, err := api.Scheme.Decode([]byte(`{"kind":"Route","apiVersion":"v1beta1","tls":` + tc.tls + `}`))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if policy := obj.(*newer.Route).TLS.InsecureEdgeTerminationPolicy; policy != tc.expected {
+			t.Errorf("%s: expected policy %q, got %q", name, tc.expected, policy)
+		}
+	}
+}
@@ -8,8 +8,10 @@ func init() {
 	api.Scheme.AddKnownTypes("v1beta1",
 		&Route{},
 		&RouteList{},
+		&RouteStatusUpdate{},
 	)
 }
 
-func (*Route) IsAnAPIObject()     {}
-func (*RouteList) IsAnAPIObject() {}
+func (*Route) IsAnAPIObject()             {}
+func (*RouteList) IsAnAPIObject()         {}
+func (*RouteStatusUpdate) IsAnAPIObject() {}
@@ -2,6 +2,7 @@ package v1beta1
 
 import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
 // Route encapsulates the inputs needed to connect an alias to endpoints.
@@ -12,15 +13,22 @@ type Route struct {
 	// Required: Alias/DNS that points to the service
 	// Can be host or host:port
 	// host and port are combined to follow the net/url URL struct
-	Host string `json:"host"`
+	Host string `json:"host" description:"Alias/DNS that points to the service Can be host or host:port host and port are combined to follow the net/url URL struct"`
 	// Optional: Path that the router watches for, to route traffic for to the service
-	Path string `json:"path,omitempty"`
+	Path string `json:"path,omitempty" description:"Path that the router watches for, to route traffic for to the service"`
 
 	// the name of the service that this route points to
-	ServiceName string `json:"serviceName"`
+	ServiceName string `json:"serviceName" description:"the name of the service that this route points to"`
+	// Optional: the share of traffic sent to ServiceName relative to AlternateBackends, defaults to 1
+	ServiceWeight *int `json:"serviceWeight,omitempty" description:"the share of traffic sent to ServiceName relative to AlternateBackends, defaults to 1"`
+	// Optional: additional services that receive a weighted share of the route's traffic
+	AlternateBackends []RouteBackend `json:"alternateBackends,omitempty" description:"additional services that receive a weighted share of the route's traffic"`
 
 	//TLS provides the ability to configure certificates and termination for the route
-	TLS *TLSConfig `json:"tls,omitempty"`
+	TLS *TLSConfig `json:"tls,omitempty" description:"TLS provides the ability to configure certificates and termination for the route"`
+
+	// Status is the current state of the route as reported by the routers that process it
+	Status *RouteStatus `json:"status,omitempty" description:"Status is the current state of the route as reported by the routers that process it"`
 }
 
 // RouteList is a collection of Routes.
@@ -30,23 +38,79 @@ type RouteList struct {
 	Items         []Route `json:"items"`
 }
 
+// RouteBackend is a service that receives a weighted share of a route's traffic.
+type RouteBackend struct {
+	// ServiceName is the name of the service
+	ServiceName string `json:"serviceName" description:"ServiceName is the name of the service"`
+	// Weight is the share of traffic sent to the service relative to the other backends, defaults to 1.
+	// A weight of 0 sends no new traffic to the service.
+	Weight *int `json:"weight,omitempty" description:"Weight is the share of traffic sent to the service relative to the other backends, defaults to 1. A weight of 0 sends no new traffic to the service."`
+}
+
+// RouteStatus describes the routers that have processed a route.
+type RouteStatus struct {
+	// Ingress is the list of routers that have reported on this route, one entry per router
+	Ingress []RouteIngress `json:"ingress,omitempty" description:"Ingress is the list of routers that have reported on this route, one entry per router"`
+	// CertificateExpires is when the certificate of the route expires, set by the server when the route has one
+	CertificateExpires *util.Time `json:"certificateExpires,omitempty" description:"CertificateExpires is when the certificate of the route expires, set by the server when the route has one"`
+}
+
+// RouteIngress is the state of a route as seen by a single router.
+type RouteIngress struct {
+	// RouterName identifies the router that reported this entry
+	RouterName string `json:"routerName" description:"RouterName identifies the router that reported this entry"`
+	// Host is the host the router exposes the route under
+	Host string `json:"host,omitempty" description:"Host is the host the router exposes the route under"`
+	// Phase is whether the router admitted or rejected the route
+	Phase RouteIngressPhase `json:"phase,omitempty" description:"Phase is whether the router admitted or rejected the route"`
+	// Reason is a brief, machine readable explanation for a rejected route
+	Reason string `json:"reason,omitempty" description:"Reason is a brief, machine readable explanation for a rejected route"`
+	// Message is a human readable description of the router's decision
+	Message string `json:"message,omitempty" description:"Message is a human readable description of the router's decision"`
+}
+
+// RouteIngressPhase is the outcome of a router processing a route.
+type RouteIngressPhase string
+
+const (
+	// RouteAdmitted means the router is serving traffic for the route
+	RouteAdmitted RouteIngressPhase = "Admitted"
+	// RouteRejected means the router refused to expose the route
+	RouteRejected RouteIngressPhase = "Rejected"
+)
+
+// RouteStatusUpdate is posted by a router to record its view of a route in the route's status.
+// The name of the update is the name of the route it applies to.
+type RouteStatusUpdate struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	// Ingress replaces the entry for the same router in the route's status
+	Ingress RouteIngress `json:"ingress" description:"Ingress replaces the entry for the same router in the route's status"`
+}
+
 // TLSConfig defines config used to secure a route and provide termination
 type TLSConfig struct {
 	// Termination indicates termination type.  If termination type is not set, any termination config will be ignored
-	Termination TLSTerminationType `json:"termination,omitempty"`
+	Termination TLSTerminationType `json:"termination,omitempty" description:"Termination indicates termination type.  If termination type is not set, any termination config will be ignored"`
 
 	// Certificate provides certificate contents
-	Certificate string `json:"certificate,omitempty"`
+	Certificate string `json:"certificate,omitempty" description:"Certificate provides certificate contents"`
 
 	// Key provides key file contents
-	Key string `json:"key,omitempty"`
+	Key string `json:"key,omitempty" description:"Key provides key file contents"`
 
 	// CACertificate provides the cert authority certificate contents
-	CACertificate string `json:"caCertificate,omitempty"`
+	CACertificate string `json:"caCertificate,omitempty" description:"CACertificate provides the cert authority certificate contents"`
 
 	// DestinationCACertificate provides the contents of the ca certificate of the final destination.  When using reencrypt
 	// termination this file should be provided in order to have routers use it for health checks on the secure connection
-	DestinationCACertificate string `json:"destinationCACertificate,omitempty"`
+	DestinationCACertificate string `json:"destinationCACertificate,omitempty" description:"DestinationCACertificate provides the contents of the ca certificate of the final destination.  When using reencrypt termination this file should be provided in order to have routers use it for health checks on the secure connection"`
+
+	// InsecureEdgeTerminationPolicy indicates what to do with insecure connections to an edge terminated route.
+	// Allow serves the route over http as well, Redirect sends http clients to https and None refuses http
+	// connections.  Only valid with edge termination, defaults to Allow.
+	InsecureEdgeTerminationPolicy InsecureEdgeTerminationPolicyType `json:"insecureEdgeTerminationPolicy,omitempty" description:"InsecureEdgeTerminationPolicy indicates what to do with insecure connections to an edge terminated route. Allow serves the route over http as well, Redirect sends http clients to https and None refuses http connections.  Only valid with edge termination, defaults to Allow."`
 }
 
 // TLSTerminationType dictates where the secure communication will stop
@@ -60,3 +124,15 @@ const (
 	// TLSTerminationReencrypt terminate encryption at the edge router and re-encrypt it with a new certificate supplied by the destination
 	TLSTerminationReencrypt TLSTerminationType = "reencrypt"
 )
+
+// InsecureEdgeTerminationPolicyType dictates what to do with insecure connections to a secured route
+type InsecureEdgeTerminationPolicyType string
+
+const (
+	// InsecureEdgeTerminationPolicyAllow serves the route over http as well as https
+	InsecureEdgeTerminationPolicyAllow InsecureEdgeTerminationPolicyType = "Allow"
+	// InsecureEdgeTerminationPolicyRedirect redirects http requests to https
+	InsecureEdgeTerminationPolicyRedirect InsecureEdgeTerminationPolicyType = "Redirect"
+	// InsecureEdgeTerminationPolicyNone refuses http requests for the route
+	InsecureEdgeTerminationPolicyNone InsecureEdgeTerminationPolicyType = "None"
+)
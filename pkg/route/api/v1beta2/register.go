@@ -0,0 +1,17 @@
+package v1beta2
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+func init() {
+	api.Scheme.AddKnownTypes("v1beta2",
+		&Route{},
+		&RouteList{},
+		&RouteStatusUpdate{},
+	)
+}
+
+func (*Route) IsAnAPIObject()             {}
+func (*RouteList) IsAnAPIObject()         {}
+func (*RouteStatusUpdate) IsAnAPIObject() {}
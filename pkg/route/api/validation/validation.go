@@ -1,7 +1,14 @@
 package validation
 
 import (
+	"fmt"
+	"strconv"
+	"time"
+
 	errs "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/validation"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
 	routeapi "github.com/openshift/origin/pkg/route/api"
 )
 
@@ -9,6 +16,12 @@ import (
 func ValidateRoute(route *routeapi.Route) errs.ValidationErrorList {
 	result := errs.ValidationErrorList{}
 
+	// the name is generated when it is empty
+	if len(route.Name) != 0 && !util.IsDNSSubdomain(route.Name) {
+		result = append(result, errs.NewFieldInvalid("name", route.Name, "name must be a valid subdomain"))
+	}
+	result = append(result, validation.ValidateLabels(route.Labels, "labels")...)
+
 	if len(route.Host) == 0 {
 		result = append(result, errs.NewFieldRequired("host", ""))
 	}
@@ -16,6 +29,36 @@ func ValidateRoute(route *routeapi.Route) errs.ValidationErrorList {
 		result = append(result, errs.NewFieldRequired("serviceName", ""))
 	}
 
+	result = append(result, validateWeight("serviceWeight", route.ServiceWeight)...)
+
+	services := map[string]bool{route.ServiceName: true}
+	for i, backend := range route.AlternateBackends {
+		field := fmt.Sprintf("alternateBackends[%d]", i)
+		if len(backend.ServiceName) == 0 {
+			result = append(result, errs.NewFieldRequired(field+".serviceName", ""))
+		} else if services[backend.ServiceName] {
+			result = append(result, errs.NewFieldDuplicate(field+".serviceName", backend.ServiceName))
+		}
+		services[backend.ServiceName] = true
+		result = append(result, validateWeight(field+".weight", backend.Weight)...)
+	}
+
+	if affinity, ok := route.Annotations[routeapi.RouteAffinityAnnotation]; ok {
+		switch routeapi.RouteAffinityType(affinity) {
+		case routeapi.RouteAffinityNone, routeapi.RouteAffinityCookie, routeapi.RouteAffinitySource:
+		default:
+			result = append(result, errs.NewFieldNotSupported("annotations."+routeapi.RouteAffinityAnnotation, affinity))
+		}
+	}
+
+	result = append(result, validateRouterAnnotations(route.Annotations)...)
+
+	if port, err := routeapi.RouteTCPPort(route); err != nil {
+		result = append(result, errs.NewFieldInvalid("annotations."+routeapi.RouteTCPPortAnnotation, route.Annotations[routeapi.RouteTCPPortAnnotation], err.Error()))
+	} else if port > 0 && route.TLS != nil && len(route.TLS.Termination) > 0 && route.TLS.Termination != routeapi.TLSTerminationPassthrough {
+		result = append(result, errs.NewFieldInvalid("annotations."+routeapi.RouteTCPPortAnnotation, route.Annotations[routeapi.RouteTCPPortAnnotation], "only routes without termination or with passthrough termination can be exposed on a TCP port"))
+	}
+
 	if errs := validateTLS(route.TLS); len(errs) != 0 {
 		result = append(result, errs.Prefix("tls")...)
 	}
@@ -23,6 +66,49 @@ func ValidateRoute(route *routeapi.Route) errs.ValidationErrorList {
 	return result
 }
 
+// ValidateRouteUpdate tests that route is valid and does not change the immutable fields of oldRoute.
+func ValidateRouteUpdate(route, oldRoute *routeapi.Route) errs.ValidationErrorList {
+	result := ValidateRoute(route)
+	result = append(result, validation.ValidateObjectMetaUpdate(&oldRoute.ObjectMeta, &route.ObjectMeta)...)
+	return result
+}
+
+// validateRouterAnnotations tests that the router tunables set as annotations on a route have valid values.
+func validateRouterAnnotations(annotations map[string]string) errs.ValidationErrorList {
+	result := errs.ValidationErrorList{}
+
+	if balance, ok := annotations[routeapi.RouteBalanceAnnotation]; ok {
+		switch routeapi.RouteBalanceType(balance) {
+		case routeapi.RouteBalanceLeastConn, routeapi.RouteBalanceRoundRobin:
+		default:
+			result = append(result, errs.NewFieldNotSupported("annotations."+routeapi.RouteBalanceAnnotation, balance))
+		}
+	}
+	for _, key := range []string{routeapi.RouteConnectTimeoutAnnotation, routeapi.RouteServerTimeoutAnnotation} {
+		if timeout, ok := annotations[key]; ok {
+			if d, err := time.ParseDuration(timeout); err != nil || d <= 0 {
+				result = append(result, errs.NewFieldInvalid("annotations."+key, timeout, "must be a positive duration such as 5s"))
+			}
+		}
+	}
+	if maxConn, ok := annotations[routeapi.RouteMaxConnectionsAnnotation]; ok {
+		if n, err := strconv.Atoi(maxConn); err != nil || n <= 0 {
+			result = append(result, errs.NewFieldInvalid("annotations."+routeapi.RouteMaxConnectionsAnnotation, maxConn, "must be a positive integer"))
+		}
+	}
+
+	return result
+}
+
+// validateWeight tests that an optional backend weight is within the range routers accept.
+func validateWeight(field string, weight *int) errs.ValidationErrorList {
+	result := errs.ValidationErrorList{}
+	if weight != nil && (*weight < 0 || *weight > routeapi.MaxBackendWeight) {
+		result = append(result, errs.NewFieldInvalid(field, *weight, fmt.Sprintf("must be between 0 and %d", routeapi.MaxBackendWeight)))
+	}
+	return result
+}
+
 // ValidateTLS tests fields for different types of TLS combinations are set.  Called
 // by ValidateRoute.
 func validateTLS(tls *routeapi.TLSConfig) errs.ValidationErrorList {
@@ -90,5 +176,34 @@ func validateTLS(tls *routeapi.TLSConfig) errs.ValidationErrorList {
 		}
 	}
 
+	switch tls.InsecureEdgeTerminationPolicy {
+	case "":
+	case routeapi.InsecureEdgeTerminationPolicyAllow, routeapi.InsecureEdgeTerminationPolicyRedirect, routeapi.InsecureEdgeTerminationPolicyNone:
+		if tls.Termination != routeapi.TLSTerminationEdge {
+			result = append(result, errs.NewFieldInvalid("insecureEdgeTerminationPolicy", tls.InsecureEdgeTerminationPolicy, "only edge termination supports an insecure edge termination policy"))
+		}
+	default:
+		result = append(result, errs.NewFieldNotSupported("insecureEdgeTerminationPolicy", tls.InsecureEdgeTerminationPolicy))
+	}
+
+	return result
+}
+
+// ValidateRouteStatusUpdate tests if required fields in the route status update are set.
+func ValidateRouteStatusUpdate(update *routeapi.RouteStatusUpdate) errs.ValidationErrorList {
+	result := errs.ValidationErrorList{}
+
+	if len(update.Name) == 0 {
+		result = append(result, errs.NewFieldRequired("name", ""))
+	}
+	if len(update.Ingress.RouterName) == 0 {
+		result = append(result, errs.NewFieldRequired("ingress.routerName", ""))
+	}
+	switch update.Ingress.Phase {
+	case routeapi.RouteAdmitted, routeapi.RouteRejected:
+	default:
+		result = append(result, errs.NewFieldNotSupported("ingress.phase", update.Ingress.Phase))
+	}
+
 	return result
 }
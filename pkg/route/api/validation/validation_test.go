@@ -52,6 +52,23 @@ func TestValidateTLSEdgeTermOKCerts(t *testing.T) {
 	}
 }
 
+// TestValidateInsecureEdgeTerminationPolicy ensures every policy is accepted on edge terminated routes
+func TestValidateInsecureEdgeTerminationPolicy(t *testing.T) {
+	for _, policy := range []api.InsecureEdgeTerminationPolicyType{"", api.InsecureEdgeTerminationPolicyAllow, api.InsecureEdgeTerminationPolicyRedirect, api.InsecureEdgeTerminationPolicyNone} {
+		errs := validateTLS(&api.TLSConfig{
+			Termination:                   api.TLSTerminationEdge,
+			Certificate:                   "abc",
+			Key:                           "abc",
+			CACertificate:                 "abc",
+			InsecureEdgeTerminationPolicy: policy,
+		})
+
+		if len(errs) > 0 {
+			t.Errorf("Unexpected non-empty error list for policy %q: %#v", policy, errs)
+		}
+	}
+}
+
 func TestValidateEdgeTermInvalid(t *testing.T) {
 	testCases := []struct {
 		name string
@@ -72,6 +89,13 @@ func TestValidateEdgeTermInvalid(t *testing.T) {
 			Certificate: "abc",
 			Key:         "abc",
 		}},
+		{"unknown insecure policy", api.TLSConfig{
+			Termination:                   api.TLSTerminationEdge,
+			Certificate:                   "abc",
+			Key:                           "abc",
+			CACertificate:                 "abc",
+			InsecureEdgeTerminationPolicy: "Sometimes",
+		}},
 	}
 
 	for _, tc := range testCases {
@@ -92,6 +116,7 @@ func TestValidatePodTermInvalid(t *testing.T) {
 		{"key", api.TLSConfig{Termination: api.TLSTerminationPassthrough, Key: "test"}},
 		{"ca cert", api.TLSConfig{Termination: api.TLSTerminationPassthrough, CACertificate: "test"}},
 		{"dest cert", api.TLSConfig{Termination: api.TLSTerminationPassthrough, DestinationCACertificate: "test"}},
+		{"insecure policy", api.TLSConfig{Termination: api.TLSTerminationPassthrough, InsecureEdgeTerminationPolicy: api.InsecureEdgeTerminationPolicyRedirect}},
 	}
 
 	for _, tc := range testCases {
@@ -113,3 +138,160 @@ func TestValidateReencryptTermInvalid(t *testing.T) {
 		t.Errorf("Unexpected error list encountered: %#v.  Expected 4 errors, got %v", errs, len(errs))
 	}
 }
+
+// TestValidateRouteBackends ensures alternate backends name distinct services with weights in range
+func TestValidateRouteBackends(t *testing.T) {
+	weight := func(w int) *int { return &w }
+
+	testCases := []struct {
+		name           string
+		serviceWeight  *int
+		backends       []api.RouteBackend
+		expectedErrors int
+	}{
+		{"no backends", nil, nil, 0},
+		{"weighted", weight(3), []api.RouteBackend{{ServiceName: "b", Weight: weight(1)}, {ServiceName: "c"}}, 0},
+		{"drained", weight(0), []api.RouteBackend{{ServiceName: "b", Weight: weight(256)}}, 0},
+		{"missing name", nil, []api.RouteBackend{{Weight: weight(1)}}, 1},
+		{"duplicate primary", nil, []api.RouteBackend{{ServiceName: "a"}}, 1},
+		{"duplicate alternate", nil, []api.RouteBackend{{ServiceName: "b"}, {ServiceName: "b"}}, 1},
+		{"negative weight", weight(-1), nil, 1},
+		{"weight too large", nil, []api.RouteBackend{{ServiceName: "b", Weight: weight(257)}}, 1},
+	}
+
+	for _, tc := range testCases {
+		errs := ValidateRoute(&api.Route{
+			Host:              "www.example.com",
+			ServiceName:       "a",
+			ServiceWeight:     tc.serviceWeight,
+			AlternateBackends: tc.backends,
+		})
+
+		if len(errs) != tc.expectedErrors {
+			t.Errorf("%s: expected %d errors, got %#v", tc.name, tc.expectedErrors, errs)
+		}
+	}
+}
+
+// TestValidateRouteAffinity ensures only supported affinity annotation values are accepted
+func TestValidateRouteAffinity(t *testing.T) {
+	for value, expectedErrors := range map[string]int{"none": 0, "cookie": 0, "source": 0, "sticky": 1, "": 1} {
+		route := &api.Route{
+			Host:        "www.example.com",
+			ServiceName: "a",
+		}
+		route.Annotations = map[string]string{api.RouteAffinityAnnotation: value}
+
+		if errs := ValidateRoute(route); len(errs) != expectedErrors {
+			t.Errorf("%q: expected %d errors, got %#v", value, expectedErrors, errs)
+		}
+	}
+}
+
+// TestValidateRouterAnnotations ensures router tunables set as annotations have valid values
+func TestValidateRouterAnnotations(t *testing.T) {
+	testCases := map[string]struct {
+		annotations    map[string]string
+		expectedErrors int
+	}{
+		"none": {nil, 0},
+		"valid": {map[string]string{
+			api.RouteBalanceAnnotation:        "roundrobin",
+			api.RouteConnectTimeoutAnnotation: "5s",
+			api.RouteServerTimeoutAnnotation:  "500ms",
+			api.RouteMaxConnectionsAnnotation: "10",
+		}, 0},
+		"invalid": {map[string]string{
+			api.RouteBalanceAnnotation:        "random",
+			api.RouteConnectTimeoutAnnotation: "5",
+			api.RouteServerTimeoutAnnotation:  "0s",
+			api.RouteMaxConnectionsAnnotation: "-1",
+		}, 4},
+	}
+
+	for name, tc := range testCases {
+		if errs := validateRouterAnnotations(tc.annotations); len(errs) != tc.expectedErrors {
+			t.Errorf("%s: expected %d errors, got %#v", name, tc.expectedErrors, errs)
+		}
+	}
+}
+
+// TestValidateRouteTCPPort ensures only unterminated or passthrough routes claim a valid TCP port
+func TestValidateRouteTCPPort(t *testing.T) {
+	testCases := map[string]struct {
+		port           string
+		termination    api.TLSTerminationType
+		expectedErrors int
+	}{
+		"plain":       {"5432", "", 0},
+		"passthrough": {"5432", api.TLSTerminationPassthrough, 0},
+		"edge":        {"5432", api.TLSTerminationEdge, 1},
+		"not number":  {"postgres", "", 1},
+		"too large":   {"70000", "", 1},
+	}
+
+	for name, tc := range testCases {
+		route := &api.Route{
+			Host:        "db.example.com",
+			ServiceName: "db",
+		}
+		route.Annotations = map[string]string{api.RouteTCPPortAnnotation: tc.port}
+		if len(tc.termination) > 0 {
+			route.TLS = &api.TLSConfig{Termination: tc.termination}
+			if tc.termination == api.TLSTerminationEdge {
+				route.TLS.Certificate, route.TLS.Key, route.TLS.CACertificate = "abc", "abc", "abc"
+			}
+		}
+
+		if errs := ValidateRoute(route); len(errs) != tc.expectedErrors {
+			t.Errorf("%s: expected %d errors, got %#v", name, tc.expectedErrors, errs)
+		}
+	}
+}
+
+// TestValidateRouteMetadata ensures the name and labels of routes are valid
+func TestValidateRouteMetadata(t *testing.T) {
+	testCases := map[string]struct {
+		name           string
+		labels         map[string]string
+		expectedErrors int
+	}{
+		"generated name": {"", nil, 0},
+		"valid name":     {"frontend.example", map[string]string{"app": "frontend"}, 0},
+		"invalid name":   {"Frontend_1", nil, 1},
+		"invalid label":  {"frontend", map[string]string{"-app": "frontend"}, 1},
+	}
+
+	for name, tc := range testCases {
+		route := &api.Route{Host: "www.example.com", ServiceName: "frontend"}
+		route.Name, route.Labels = tc.name, tc.labels
+		if errs := ValidateRoute(route); len(errs) != tc.expectedErrors {
+			t.Errorf("%s: expected %d errors, got %#v", name, tc.expectedErrors, errs)
+		}
+	}
+}
+
+// TestValidateRouteUpdate ensures the name and namespace of a route cannot change
+func TestValidateRouteUpdate(t *testing.T) {
+	oldRoute := &api.Route{Host: "www.example.com", ServiceName: "frontend"}
+	oldRoute.Name, oldRoute.Namespace = "frontend", "default"
+
+	testCases := map[string]struct {
+		name           string
+		namespace      string
+		host           string
+		expectedErrors int
+	}{
+		"host changed":      {"frontend", "default", "app.example.com", 0},
+		"name changed":      {"backend", "default", "www.example.com", 1},
+		"namespace changed": {"frontend", "other", "www.example.com", 1},
+	}
+
+	for name, tc := range testCases {
+		route := &api.Route{Host: tc.host, ServiceName: "frontend"}
+		route.Name, route.Namespace = tc.name, tc.namespace
+		if errs := ValidateRouteUpdate(route, oldRoute); len(errs) != tc.expectedErrors {
+			t.Errorf("%s: expected %d errors, got %#v", name, tc.expectedErrors, errs)
+		}
+	}
+}
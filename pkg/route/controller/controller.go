@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+
+	osclient "github.com/openshift/origin/pkg/client"
+	"github.com/openshift/origin/pkg/client/record"
+	oscontroller "github.com/openshift/origin/pkg/controller"
+)
+
+const (
+	// CertificateExpiring is the reason of the event recorded for a route whose certificate expires
+	// within the window of the controller.
+	CertificateExpiring = "certificateExpiring"
+	// CertificateExpired is the reason of the event recorded for a route whose certificate has expired.
+	CertificateExpired = "certificateExpired"
+)
+
+// CertificateExpiryController periodically checks the certificate expiry the server records in the
+// status of every route, and records an event on the routes whose certificate expires within Window
+// or has expired. An event is recorded once for each route and reason. The routes found are also
+// reported to Metrics, if set.
+type CertificateExpiryController struct {
+	// Client is used to list routes.
+	Client osclient.RoutesNamespacer
+	// Recorder records the events of the routes with expiring certificates.
+	Recorder record.Recorder
+	// Window is how long before its certificate expires a route is reported.
+	Window time.Duration
+	// Now returns the current time, defaults to time.Now.
+	Now func() time.Time
+	// Period is the interval between checks.
+	Period time.Duration
+	// Synced is an optional function called after each check.
+	Synced func()
+	// Crashed is an optional function called with the value of a panic in the controller.
+	Crashed func(interface{})
+	// Stop is an optional channel that controls when the controller exits.
+	Stop <-chan struct{}
+	// Metrics may be set to report the routes found by the last check.
+	Metrics *CertificateExpiryMetrics
+
+	// reported is the reason last recorded for each route, by namespace and name
+	reported map[string]string
+}
+
+// CertificateExpiryMetrics reports the routes a CertificateExpiryController found with expiring or expired
+// certificates as metrics.
+type CertificateExpiryMetrics struct {
+	lock sync.Mutex
+	// expiring are the routes found by the last check
+	expiring []expiringRoute
+}
+
+// expiringRoute is a route found with an expiring or expired certificate.
+type expiringRoute struct {
+	namespace string
+	name      string
+	reason    string
+	expires   time.Time
+}
+
+// Run begins checking routes every Period.
+func (c *CertificateExpiryController) Run() {
+	go oscontroller.Until(func() {
+		c.HandleRoutes()
+		if c.Synced != nil {
+			c.Synced()
+		}
+	}, c.Period, c.Stop, c.Crashed)
+}
+
+// HandleRoutes records an event on every route whose certificate expires within Window, unless the
+// same event was recorded by an earlier check.
+func (c *CertificateExpiryController) HandleRoutes() {
+	routes, err := c.Client.Routes(kapi.NamespaceAll).List(labels.Everything(), labels.Everything())
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to list routes: %v", err))
+		return
+	}
+
+	now := time.Now()
+	if c.Now != nil {
+		now = c.Now()
+	}
+
+	reported := map[string]string{}
+	expiring := []expiringRoute{}
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if route.Status == nil || route.Status.CertificateExpires == nil {
+			continue
+		}
+		expires := route.Status.CertificateExpires.Time
+		reason := ""
+		switch {
+		case !now.Before(expires):
+			reason = CertificateExpired
+		case expires.Sub(now) <= c.Window:
+			reason = CertificateExpiring
+		default:
+			continue
+		}
+		expiring = append(expiring, expiringRoute{namespace: route.Namespace, name: route.Name, reason: reason, expires: expires})
+
+		key := route.Namespace + "/" + route.Name
+		reported[key] = reason
+		if c.reported[key] == reason {
+			continue
+		}
+		glog.V(4).Infof("The certificate of route %s expires at %v", key, expires)
+		if reason == CertificateExpired {
+			c.Recorder.Eventf(route, reason, "The certificate of route %s expired at %s", route.Name, expires.UTC().Format(time.RFC3339))
+		} else {
+			c.Recorder.Eventf(route, reason, "The certificate of route %s expires at %s", route.Name, expires.UTC().Format(time.RFC3339))
+		}
+	}
+	c.reported = reported
+	if c.Metrics != nil {
+		c.Metrics.lock.Lock()
+		c.Metrics.expiring = expiring
+		c.Metrics.lock.Unlock()
+	}
+}
+
+// Write writes the routes found by the last check in the Prometheus text format.
+func (m *CertificateExpiryMetrics) Write(w io.Writer) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	counts := map[string]int{}
+	for _, route := range m.expiring {
+		counts[route.reason]++
+	}
+	fmt.Fprintf(w, "# HELP openshift_route_certificates_expiring The number of routes whose certificate expires soon or has expired.\n")
+	fmt.Fprintf(w, "# TYPE openshift_route_certificates_expiring gauge\n")
+	for _, reason := range []string{CertificateExpiring, CertificateExpired} {
+		fmt.Fprintf(w, "openshift_route_certificates_expiring{reason=%q} %d\n", reason, counts[reason])
+	}
+
+	routes := make([]expiringRoute, len(m.expiring))
+	copy(routes, m.expiring)
+	sort.Sort(byNamespaceAndName(routes))
+	fmt.Fprintf(w, "# HELP openshift_route_certificate_expiry_timestamp_seconds When the certificate of a route that expires soon or has expired expires.\n")
+	fmt.Fprintf(w, "# TYPE openshift_route_certificate_expiry_timestamp_seconds gauge\n")
+	for _, route := range routes {
+		fmt.Fprintf(w, "openshift_route_certificate_expiry_timestamp_seconds{namespace=%q,name=%q} %d\n", route.namespace, route.name, route.expires.Unix())
+	}
+}
+
+// byNamespaceAndName sorts expiring routes by namespace and then name.
+type byNamespaceAndName []expiringRoute
+
+func (r byNamespaceAndName) Len() int      { return len(r) }
+func (r byNamespaceAndName) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r byNamespaceAndName) Less(i, j int) bool {
+	if r[i].namespace != r[j].namespace {
+		return r[i].namespace < r[j].namespace
+	}
+	return r[i].name < r[j].name
+}
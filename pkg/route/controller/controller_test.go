@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	osclient "github.com/openshift/origin/pkg/client"
+	"github.com/openshift/origin/pkg/client/record"
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+type fakeRoutes struct {
+	osclient.FakeRoutes
+	routes *routeapi.RouteList
+}
+
+func (c *fakeRoutes) List(label, field labels.Selector) (*routeapi.RouteList, error) {
+	return c.routes, nil
+}
+
+type fakeClient struct {
+	routes *routeapi.RouteList
+}
+
+func (c *fakeClient) Routes(namespace string) osclient.RouteInterface {
+	return &fakeRoutes{routes: c.routes}
+}
+
+func routeExpiringAt(name string, expires *time.Time) routeapi.Route {
+	route := routeapi.Route{ObjectMeta: kapi.ObjectMeta{Name: name, Namespace: "test"}}
+	if expires != nil {
+		t := util.NewTime(*expires)
+		route.Status = &routeapi.RouteStatus{CertificateExpires: &t}
+	}
+	return route
+}
+
+func TestHandleRoutes(t *testing.T) {
+	now := time.Date(2015, time.March, 1, 0, 0, 0, 0, time.UTC)
+	soon, later, past := now.Add(time.Hour), now.Add(48*time.Hour), now.Add(-time.Hour)
+	routes := &routeapi.RouteList{
+		Items: []routeapi.Route{
+			routeExpiringAt("none", nil),
+			routeExpiringAt("later", &later),
+			routeExpiringAt("soon", &soon),
+			routeExpiringAt("past", &past),
+		},
+	}
+	recorder := &record.FakeRecorder{}
+	controller := &CertificateExpiryController{
+		Client:   &fakeClient{routes},
+		Recorder: recorder,
+		Window:   24 * time.Hour,
+		Now:      func() time.Time { return now },
+		Metrics:  &CertificateExpiryMetrics{},
+	}
+
+	controller.HandleRoutes()
+	expected := []string{
+		"certificateExpiring The certificate of route soon expires at 2015-03-01T01:00:00Z",
+		"certificateExpired The certificate of route past expired at 2015-02-28T23:00:00Z",
+	}
+	if strings.Join(recorder.Events, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected events %v, got %v", expected, recorder.Events)
+	}
+
+	buf := &bytes.Buffer{}
+	controller.Metrics.Write(buf)
+	if !strings.Contains(buf.String(), `openshift_route_certificates_expiring{reason="certificateExpiring"} 1`) ||
+		!strings.Contains(buf.String(), `openshift_route_certificates_expiring{reason="certificateExpired"} 1`) ||
+		!strings.Contains(buf.String(), `openshift_route_certificate_expiry_timestamp_seconds{namespace="test",name="past"} 1425164400`) ||
+		strings.Contains(buf.String(), `name="later"`) {
+		t.Errorf("Unexpected metrics:\n%s", buf.String())
+	}
+
+	// events are recorded once, unless the reason changes
+	now = now.Add(2 * time.Hour)
+	controller.HandleRoutes()
+	expected = append(expected, "certificateExpired The certificate of route soon expired at 2015-03-01T01:00:00Z")
+	if strings.Join(recorder.Events, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected events %v, got %v", expected, recorder.Events)
+	}
+}
@@ -0,0 +1,3 @@
+// Package controller contains the controller that reports the routes whose certificates are about
+// to expire.
+package controller
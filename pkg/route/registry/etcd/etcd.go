@@ -1,17 +1,18 @@
 package etcd
 
 import (
-	"fmt"
-
 	etcderr "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors/etcd"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/golang/glog"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kubeetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/etcd"
 	ktools "github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
 	"github.com/openshift/origin/pkg/route/api"
+	etcdutil "github.com/openshift/origin/pkg/util/etcd"
 )
 
 const (
@@ -87,8 +88,7 @@ func (registry *Etcd) UpdateRoute(ctx kapi.Context, route *api.Route) error {
 	if err != nil {
 		return err
 	}
-	err = registry.SetObj(key, route)
-	return etcderr.InterpretUpdateError(err, "route", route.Name)
+	return etcdutil.UpdateObj(registry.EtcdHelper, key, route, "route", route.Name)
 }
 
 // DeleteRoute deletes a Route specified by its ID.
@@ -103,26 +103,18 @@ func (registry *Etcd) DeleteRoute(ctx kapi.Context, routeID string) error {
 
 // WatchRoutes begins watching for new, changed, or deleted route configurations.
 func (registry *Etcd) WatchRoutes(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
-	if !label.Empty() {
-		return nil, fmt.Errorf("label selectors are not supported on routes yet")
-	}
-
 	version, err := ktools.ParseWatchResourceVersion(resourceVersion, "pod")
 	if err != nil {
 		return nil, err
 	}
 
-	if value, found := field.RequiresExactMatch("ID"); found {
-		key, err := makeRouteKey(ctx, value)
-		if err != nil {
-			return nil, err
+	key := kubeetcd.MakeEtcdListKey(ctx, RoutePath)
+	return registry.WatchList(key, version, func(obj runtime.Object) bool {
+		route, ok := obj.(*api.Route)
+		if !ok {
+			glog.Errorf("Unexpected object during route watch: %#v", obj)
+			return false
 		}
-		return registry.Watch(key, version), nil
-	}
-
-	if field.Empty() {
-		key := kubeetcd.MakeEtcdListKey(ctx, RoutePath)
-		return registry.WatchList(key, version, tools.Everything)
-	}
-	return nil, fmt.Errorf("only the 'ID' and default (everything) field selectors are supported")
+		return label.Matches(labels.Set(route.Labels)) && field.Matches(api.RouteToSelectableFields(route))
+	})
 }
@@ -3,6 +3,7 @@ package etcd
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"testing"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -245,17 +246,37 @@ func TestEtcdCreateAlreadyExistsRoutes(t *testing.T) {
 
 func TestEtcdUpdateOkRoutes(t *testing.T) {
 	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	resp, _ := fakeClient.Set(makeTestDefaultRouteKey("foo"), runtime.EncodeOrDie(latest.Codec, &api.Route{ObjectMeta: kapi.ObjectMeta{Name: "foo"}}), 0)
 	registry := NewTestEtcd(fakeClient)
 	err := registry.UpdateRoute(kapi.NewDefaultContext(), &api.Route{
 		ObjectMeta: kapi.ObjectMeta{
-			Name: "foo",
+			Name:            "foo",
+			ResourceVersion: strconv.FormatUint(resp.Node.ModifiedIndex, 10),
 		},
+		Host: "www.example.com",
 	})
 	if err != nil {
 		t.Errorf("Unexpected error %#v", err)
 	}
 }
 
+func TestEtcdUpdateMissingRoutes(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	fakeClient.ExpectNotFoundGet(makeTestDefaultRouteKey("foo"))
+	registry := NewTestEtcd(fakeClient)
+	err := registry.UpdateRoute(kapi.NewDefaultContext(), &api.Route{
+		ObjectMeta: kapi.ObjectMeta{
+			Name:            "foo",
+			ResourceVersion: "1",
+		},
+	})
+	if !errors.IsNotFound(err) {
+		t.Errorf("Expected 'not found' error, got %#v", err)
+	}
+}
+
 func TestEtcdDeleteNotFoundRoutes(t *testing.T) {
 	fakeClient := tools.NewFakeEtcdClient(t)
 	fakeClient.Err = tools.EtcdErrorNotFound
@@ -436,3 +457,39 @@ func TestEtcdWatchRoutes(t *testing.T) {
 
 	watching.Stop()
 }
+
+func TestEtcdWatchRoutesWithLabels(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	registry := NewTestEtcd(fakeClient)
+
+	selector := labels.SelectorFromSet(labels.Set{"router": "public"})
+	watching, err := registry.WatchRoutes(kapi.NewDefaultContext(), selector, labels.Everything(), "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fakeClient.WaitForWatchCompletion()
+
+	for _, route := range []*api.Route{
+		{ObjectMeta: kapi.ObjectMeta{Name: "internal", Labels: map[string]string{"router": "internal"}}},
+		{ObjectMeta: kapi.ObjectMeta{Name: "public", Labels: map[string]string{"router": "public"}}},
+	} {
+		routeBytes, _ := latest.Codec.Encode(route)
+		fakeClient.WatchResponse <- &etcd.Response{
+			Action: "set",
+			Node: &etcd.Node{
+				Value: string(routeBytes),
+			},
+		}
+	}
+
+	event := <-watching.ResultChan()
+	if event.Type != watch.Added {
+		t.Errorf("Expected add but got %s", event.Type)
+	}
+	if route, ok := event.Object.(*api.Route); !ok || route.Name != "public" {
+		t.Errorf("Expected only the public route, got %#v", event.Object)
+	}
+
+	fakeClient.WatchInjectError <- nil
+	watching.Stop()
+}
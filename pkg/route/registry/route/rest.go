@@ -1,7 +1,10 @@
 package route
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"time"
 
 	"code.google.com/p/go-uuid/uuid"
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -9,8 +12,11 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
+	"github.com/openshift/origin/pkg/api/selectable"
+	"github.com/openshift/origin/pkg/client/record"
 	"github.com/openshift/origin/pkg/route/api"
 	"github.com/openshift/origin/pkg/route/api/validation"
 	"strings"
@@ -18,12 +24,19 @@ import (
 
 // REST is an implementation of RESTStorage for the api server.
 type REST struct {
-	registry Registry
+	registry         Registry
+	allowHostSharing bool
+	recorder         record.Recorder
 }
 
-func NewREST(registry Registry) *REST {
+// NewREST returns a new REST. Unless allowHostSharing is true, a route may not claim a host and
+// path already claimed by an older route in another namespace. A route may never claim a TCP port
+// already claimed by another route.
+func NewREST(registry Registry, allowHostSharing bool) *REST {
 	return &REST{
-		registry: registry,
+		registry:         registry,
+		allowHostSharing: allowHostSharing,
+		recorder:         record.NewRecorder(),
 	}
 }
 
@@ -41,7 +54,10 @@ func (rs *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtim
 	if err != nil {
 		return nil, err
 	}
-	return list, err
+	if err := selectable.FilterList(list, selector, fields); err != nil {
+		return nil, err
+	}
+	return list, nil
 }
 
 // Get obtains the route specified by its id.
@@ -82,14 +98,26 @@ func (rs *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.R
 	}
 
 	kapi.FillObjectMetaSystemFields(ctx, &route.ObjectMeta)
+	setHostClaimed(route, route.CreationTimestamp.Format(time.RFC3339Nano))
+
+	// status is only reported by routers
+	route.Status = nil
 
 	escapeNewLines(route.TLS)
+	setCertificateExpiry(route)
 
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		if err := rs.checkHostOwnership(ctx, route); err != nil {
+			return nil, err
+		}
+		if err := rs.checkTCPPortOwnership(ctx, route); err != nil {
+			return nil, err
+		}
 		err := rs.registry.CreateRoute(ctx, route)
 		if err != nil {
 			return nil, err
 		}
+		rs.recorder.Eventf(route, "created", "Created route %s", route.Name)
 		return rs.registry.GetRoute(ctx, route.Name)
 	}), nil
 }
@@ -114,7 +142,32 @@ func (rs *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.R
 	escapeNewLines(route.TLS)
 
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
-		err := rs.registry.UpdateRoute(ctx, route)
+		// status is only reported by routers, and is reset when the exposed host, path or TCP port changes,
+		// which claims them anew: a route does not keep its age when it moves to the host or port of another
+		existing, err := rs.registry.GetRoute(ctx, route.Name)
+		if err != nil {
+			return nil, err
+		}
+		if errs := validation.ValidateRouteUpdate(route, existing); len(errs) > 0 {
+			return nil, errors.NewInvalid("route", route.Name, errs)
+		}
+		route.Status = nil
+		if existing.Host == route.Host && existing.Path == route.Path && existing.Annotations[api.RouteTCPPortAnnotation] == route.Annotations[api.RouteTCPPortAnnotation] {
+			route.Status = existing.Status
+			setHostClaimed(route, existing.Annotations[api.RouteHostClaimedAnnotation])
+		} else {
+			setHostClaimed(route, util.Now().Format(time.RFC3339Nano))
+		}
+		setCertificateExpiry(route)
+		route.CreationTimestamp = existing.CreationTimestamp
+		if err := rs.checkHostOwnership(ctx, route); err != nil {
+			return nil, err
+		}
+		if err := rs.checkTCPPortOwnership(ctx, route); err != nil {
+			return nil, err
+		}
+
+		err = rs.registry.UpdateRoute(ctx, route)
 		if err != nil {
 			return nil, err
 		}
@@ -125,9 +178,116 @@ func (rs *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.R
 // Watch returns Routes events via a watch.Interface.
 // It implements apiserver.ResourceWatcher.
 func (rs *REST) Watch(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	if err := selectable.ValidateFields(&api.Route{}, field); err != nil {
+		return nil, err
+	}
 	return rs.registry.WatchRoutes(ctx, label, field, resourceVersion)
 }
 
+// checkHostOwnership returns a conflict if a route in another namespace that is older than route
+// already claims the same host and path.
+func (rs *REST) checkHostOwnership(ctx kapi.Context, route *api.Route) error {
+	if rs.allowHostSharing {
+		return nil
+	}
+	routes, err := rs.registry.ListRoutes(kapi.WithNamespace(ctx, kapi.NamespaceAll), labels.Everything())
+	if err != nil {
+		return err
+	}
+	if routes == nil {
+		return nil
+	}
+	for i := range routes.Items {
+		other := &routes.Items[i]
+		if other.Namespace == route.Namespace || other.Host != route.Host || other.Path != route.Path {
+			continue
+		}
+		if api.RouteOlderThan(other, route) {
+			return errors.NewConflict("route", route.Name, fmt.Errorf("host %s%s is already claimed by route %s/%s", route.Host, route.Path, other.Namespace, other.Name))
+		}
+	}
+	return nil
+}
+
+// checkTCPPortOwnership returns a conflict if another route that is older than route already claims
+// the TCP port route asks to be exposed on.  Ports are claimed by a single route whatever the namespace,
+// and routes that claim a port are newer than every route already claiming it, so a port that is
+// claimed cannot be claimed again.
+func (rs *REST) checkTCPPortOwnership(ctx kapi.Context, route *api.Route) error {
+	port, err := api.RouteTCPPort(route)
+	if err != nil || port == 0 {
+		return err
+	}
+	routes, err := rs.registry.ListRoutes(kapi.WithNamespace(ctx, kapi.NamespaceAll), labels.Everything())
+	if err != nil {
+		return err
+	}
+	if routes == nil {
+		return nil
+	}
+	for i := range routes.Items {
+		other := &routes.Items[i]
+		if other.Namespace == route.Namespace && other.Name == route.Name {
+			continue
+		}
+		if otherPort, err := api.RouteTCPPort(other); err != nil || otherPort != port {
+			continue
+		}
+		if api.RouteOlderThan(other, route) {
+			return errors.NewConflict("route", route.Name, fmt.Errorf("TCP port %d is already claimed by route %s/%s", port, other.Namespace, other.Name))
+		}
+	}
+	return nil
+}
+
+// setHostClaimed records claimed as the time route claimed its host and path, or removes the record
+// if claimed is empty, so that the route is as old as it was created.
+func setHostClaimed(route *api.Route, claimed string) {
+	if len(claimed) == 0 {
+		delete(route.Annotations, api.RouteHostClaimedAnnotation)
+		return
+	}
+	if route.Annotations == nil {
+		route.Annotations = map[string]string{}
+	}
+	route.Annotations[api.RouteHostClaimedAnnotation] = claimed
+}
+
+// setCertificateExpiry records in the status of route when the first certificate of its TLS config
+// expires. Routes without a certificate, or with one that cannot be parsed, have no expiry.
+func setCertificateExpiry(route *api.Route) {
+	if route.Status != nil {
+		route.Status.CertificateExpires = nil
+		if len(route.Status.Ingress) == 0 {
+			route.Status = nil
+		}
+	}
+	if route.TLS == nil || len(route.TLS.Certificate) == 0 {
+		return
+	}
+	data := []byte(route.TLS.Certificate)
+	for len(data) > 0 {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return
+		}
+		expires := util.NewTime(cert.NotAfter)
+		if route.Status == nil {
+			route.Status = &api.RouteStatus{}
+		}
+		route.Status.CertificateExpires = &expires
+		return
+	}
+}
+
 // escapeNewLines replaces json escaped new lines with actual line breaks
 // certs in json must be single line strings, a new line in json is represented by \\n.  This utility will replace
 // a json escaped newline with a real line break which is required for the cert to function properly
@@ -1,6 +1,12 @@
 package route
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"net/http"
 	"strings"
 	"testing"
@@ -9,6 +15,9 @@ import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	"github.com/openshift/origin/pkg/client/record"
 	"github.com/openshift/origin/pkg/route/api"
 	"github.com/openshift/origin/pkg/route/registry/test"
 )
@@ -80,7 +89,8 @@ func TestCreateRouteBadObject(t *testing.T) {
 
 func TestCreateRouteOK(t *testing.T) {
 	mockRegistry := test.NewRouteRegistry()
-	storage := REST{registry: mockRegistry}
+	recorder := &record.FakeRecorder{}
+	storage := REST{registry: mockRegistry, recorder: recorder}
 
 	channel, err := storage.Create(kapi.NewDefaultContext(), &api.Route{
 		ObjectMeta:  kapi.ObjectMeta{Name: "foo"},
@@ -106,6 +116,10 @@ func TestCreateRouteOK(t *testing.T) {
 	case <-time.After(50 * time.Millisecond):
 		t.Errorf("Timed out waiting for result")
 	}
+
+	if len(recorder.Events) != 1 || recorder.Events[0] != "created Created route foo" {
+		t.Errorf("Expected a created event, got %v", recorder.Events)
+	}
 }
 
 func TestGetRouteError(t *testing.T) {
@@ -196,7 +210,7 @@ func TestUpdateRouteOK(t *testing.T) {
 	mockRepositoryRegistry.Routes = &api.RouteList{
 		Items: []api.Route{
 			{
-				ObjectMeta:  kapi.ObjectMeta{Name: "bar"},
+				ObjectMeta:  kapi.ObjectMeta{Name: "bar", Namespace: kapi.NamespaceDefault},
 				Host:        "www.frontend.com",
 				ServiceName: "rubyservice",
 			},
@@ -327,3 +341,322 @@ func checkExpectedNamespaceError(t *testing.T, err error) {
 	}
 
 }
+
+func TestUpdateRoutePreservesStatus(t *testing.T) {
+	ingress := []api.RouteIngress{{RouterName: "router", Host: "www.frontend.com", Phase: api.RouteAdmitted}}
+	mockRepositoryRegistry := test.NewRouteRegistry()
+	mockRepositoryRegistry.Routes = &api.RouteList{
+		Items: []api.Route{
+			{
+				ObjectMeta:  kapi.ObjectMeta{Name: "bar", Namespace: kapi.NamespaceDefault},
+				Host:        "www.frontend.com",
+				ServiceName: "rubyservice",
+				Status:      &api.RouteStatus{Ingress: ingress},
+			},
+		},
+	}
+	storage := REST{registry: mockRepositoryRegistry}
+
+	channel, err := storage.Update(kapi.NewDefaultContext(), &api.Route{
+		ObjectMeta:  kapi.ObjectMeta{Name: "bar"},
+		Host:        "www.frontend.com",
+		ServiceName: "newrubyservice",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected non-nil error: %#v", err)
+	}
+	route := (<-channel).Object.(*api.Route)
+	if route.Status == nil || len(route.Status.Ingress) != 1 || route.Status.Ingress[0] != ingress[0] {
+		t.Errorf("Expected status to be preserved, got %#v", route.Status)
+	}
+
+	channel, err = storage.Update(kapi.NewDefaultContext(), &api.Route{
+		ObjectMeta:  kapi.ObjectMeta{Name: "bar"},
+		Host:        "www.newfrontend.com",
+		ServiceName: "newrubyservice",
+		Status:      &api.RouteStatus{Ingress: ingress},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected non-nil error: %#v", err)
+	}
+	route = (<-channel).Object.(*api.Route)
+	if route.Status != nil {
+		t.Errorf("Expected status to be reset when the host changes, got %#v", route.Status)
+	}
+}
+
+// testCertificate returns a PEM encoded self signed certificate that expires at notAfter.
+func testCertificate(t *testing.T, notAfter time.Time) string {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "www.frontend.com"},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestCreateRouteRecordsCertificateExpiry(t *testing.T) {
+	notAfter := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	storage := REST{registry: test.NewRouteRegistry(), recorder: &record.FakeRecorder{}}
+
+	channel, err := storage.Create(kapi.NewDefaultContext(), &api.Route{
+		ObjectMeta:  kapi.ObjectMeta{Name: "foo"},
+		Host:        "www.frontend.com",
+		ServiceName: "myrubyservice",
+		TLS: &api.TLSConfig{
+			Termination:   api.TLSTerminationEdge,
+			Certificate:   testCertificate(t, notAfter),
+			Key:           "key",
+			CACertificate: "ca",
+		},
+		Status: &api.RouteStatus{CertificateExpires: &util.Time{}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	route := (<-channel).Object.(*api.Route)
+	if route.Status == nil || route.Status.CertificateExpires == nil || !route.Status.CertificateExpires.Equal(notAfter) {
+		t.Errorf("Expected the certificate to expire at %v, got %v", notAfter, route.Status.CertificateExpires)
+	}
+
+	channel, err = storage.Create(kapi.NewDefaultContext(), &api.Route{
+		ObjectMeta:  kapi.ObjectMeta{Name: "bar"},
+		Host:        "www.backend.com",
+		ServiceName: "myrubyservice",
+		TLS: &api.TLSConfig{
+			Termination:   api.TLSTerminationEdge,
+			Certificate:   "not a certificate",
+			Key:           "key",
+			CACertificate: "ca",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	route = (<-channel).Object.(*api.Route)
+	if route.Status != nil {
+		t.Errorf("Expected no expiry for an invalid certificate, got %v", route.Status.CertificateExpires)
+	}
+}
+
+func TestUpdateRouteRecordsCertificateExpiry(t *testing.T) {
+	notAfter := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	expires := util.NewTime(notAfter.Add(-time.Hour))
+	mockRegistry := test.NewRouteRegistry()
+	mockRegistry.Routes = &api.RouteList{
+		Items: []api.Route{
+			{
+				ObjectMeta:  kapi.ObjectMeta{Name: "bar", Namespace: kapi.NamespaceDefault},
+				Host:        "www.frontend.com",
+				ServiceName: "rubyservice",
+				Status:      &api.RouteStatus{CertificateExpires: &expires},
+			},
+		},
+	}
+	storage := REST{registry: mockRegistry}
+
+	channel, err := storage.Update(kapi.NewDefaultContext(), &api.Route{
+		ObjectMeta:  kapi.ObjectMeta{Name: "bar"},
+		Host:        "www.frontend.com",
+		ServiceName: "rubyservice",
+		TLS: &api.TLSConfig{
+			Termination:   api.TLSTerminationEdge,
+			Certificate:   testCertificate(t, notAfter),
+			Key:           "key",
+			CACertificate: "ca",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	route := (<-channel).Object.(*api.Route)
+	if route.Status == nil || route.Status.CertificateExpires == nil || !route.Status.CertificateExpires.Equal(notAfter) {
+		t.Errorf("Expected the certificate to expire at %v, got %v", notAfter, route.Status.CertificateExpires)
+	}
+
+	channel, err = storage.Update(kapi.NewDefaultContext(), &api.Route{
+		ObjectMeta:  kapi.ObjectMeta{Name: "bar"},
+		Host:        "www.frontend.com",
+		ServiceName: "rubyservice",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	route = (<-channel).Object.(*api.Route)
+	if route.Status != nil {
+		t.Errorf("Expected the expiry to be cleared with the certificate, got %v", route.Status.CertificateExpires)
+	}
+}
+
+func TestCreateRouteHostClaimedInOtherNamespace(t *testing.T) {
+	mockRegistry := test.NewRouteRegistry()
+	mockRegistry.Routes = &api.RouteList{
+		Items: []api.Route{
+			{
+				ObjectMeta:  kapi.ObjectMeta{Name: "foo", Namespace: "other"},
+				Host:        "www.frontend.com",
+				ServiceName: "rubyservice",
+			},
+		},
+	}
+
+	newRoute := func() *api.Route {
+		return &api.Route{
+			ObjectMeta:  kapi.ObjectMeta{Name: "bar"},
+			Host:        "www.frontend.com",
+			ServiceName: "myrubyservice",
+		}
+	}
+
+	storage := NewREST(mockRegistry, false)
+	channel, err := storage.Create(kapi.NewDefaultContext(), newRoute())
+	if err != nil {
+		t.Fatalf("Unexpected non-nil error: %#v", err)
+	}
+	status, ok := (<-channel).Object.(*kapi.Status)
+	if !ok || status.Code != http.StatusConflict {
+		t.Errorf("Expected a conflict, got %#v", status)
+	}
+
+	storage = NewREST(mockRegistry, true)
+	channel, err = storage.Create(kapi.NewDefaultContext(), newRoute())
+	if err != nil {
+		t.Fatalf("Unexpected non-nil error: %#v", err)
+	}
+	if _, ok := (<-channel).Object.(*api.Route); !ok {
+		t.Errorf("Expected the route to be created when host sharing is allowed")
+	}
+}
+
+func TestUpdateRouteHostClaimedByNewerRoute(t *testing.T) {
+	created := util.NewTime(time.Now().Add(-time.Hour))
+	mockRegistry := test.NewRouteRegistry()
+	mockRegistry.Routes = &api.RouteList{
+		Items: []api.Route{
+			{
+				ObjectMeta:  kapi.ObjectMeta{Name: "old", Namespace: kapi.NamespaceDefault, CreationTimestamp: created},
+				Host:        "www.old.com",
+				ServiceName: "rubyservice",
+			},
+			{
+				ObjectMeta:  kapi.ObjectMeta{Name: "new", Namespace: "other", CreationTimestamp: util.Now()},
+				Host:        "www.frontend.com",
+				ServiceName: "rubyservice",
+			},
+		},
+	}
+
+	storage := NewREST(mockRegistry, false)
+	channel, err := storage.Update(kapi.NewDefaultContext(), &api.Route{
+		ObjectMeta: kapi.ObjectMeta{
+			Name:        "old",
+			Namespace:   kapi.NamespaceDefault,
+			Annotations: map[string]string{api.RouteHostClaimedAnnotation: created.Format(time.RFC3339)},
+		},
+		Host:        "www.frontend.com",
+		ServiceName: "rubyservice",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected non-nil error: %#v", err)
+	}
+	status, ok := (<-channel).Object.(*kapi.Status)
+	if !ok || status.Code != http.StatusConflict {
+		t.Errorf("Expected an older route moved to a claimed host to conflict, got %#v", status)
+	}
+
+	channel, err = storage.Update(kapi.NewDefaultContext(), &api.Route{
+		ObjectMeta:  kapi.ObjectMeta{Name: "old", Namespace: kapi.NamespaceDefault},
+		Host:        "www.old.com",
+		ServiceName: "myrubyservice",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected non-nil error: %#v", err)
+	}
+	route, ok := (<-channel).Object.(*api.Route)
+	if !ok {
+		t.Fatalf("Expected the route to be updated")
+	}
+	if _, ok := route.Annotations[api.RouteHostClaimedAnnotation]; ok || !api.RouteHostClaimed(route).Equal(created.Time) {
+		t.Errorf("Expected the route to keep the age of its host, got %v", route.Annotations)
+	}
+}
+
+func TestUpdateRouteTCPPortClaimedByNewerRoute(t *testing.T) {
+	created := util.NewTime(time.Now().Add(-time.Hour))
+	mockRegistry := test.NewRouteRegistry()
+	mockRegistry.Routes = &api.RouteList{
+		Items: []api.Route{
+			{
+				ObjectMeta:  kapi.ObjectMeta{Name: "old", Namespace: kapi.NamespaceDefault, CreationTimestamp: created},
+				Host:        "db.old.com",
+				ServiceName: "database",
+			},
+			{
+				ObjectMeta: kapi.ObjectMeta{
+					Name:              "new",
+					Namespace:         kapi.NamespaceDefault,
+					CreationTimestamp: util.Now(),
+					Annotations:       map[string]string{api.RouteTCPPortAnnotation: "10000"},
+				},
+				Host:        "db.new.com",
+				ServiceName: "database",
+			},
+		},
+	}
+
+	newRoute := func(name, port string) *api.Route {
+		return &api.Route{
+			ObjectMeta: kapi.ObjectMeta{
+				Name:      name,
+				Namespace: kapi.NamespaceDefault,
+				Annotations: map[string]string{
+					api.RouteTCPPortAnnotation:     port,
+					api.RouteHostClaimedAnnotation: created.Format(time.RFC3339),
+				},
+			},
+			Host:        "db." + name + ".com",
+			ServiceName: "database",
+		}
+	}
+
+	// host sharing does not allow ports to be shared
+	storage := NewREST(mockRegistry, true)
+	channel, err := storage.Create(kapi.NewDefaultContext(), newRoute("third", "10000"))
+	if err != nil {
+		t.Fatalf("Unexpected non-nil error: %#v", err)
+	}
+	status, ok := (<-channel).Object.(*kapi.Status)
+	if !ok || status.Code != http.StatusConflict {
+		t.Errorf("Expected a route claiming a claimed port to conflict, got %#v", status)
+	}
+
+	channel, err = storage.Update(kapi.NewDefaultContext(), newRoute("old", "10000"))
+	if err != nil {
+		t.Fatalf("Unexpected non-nil error: %#v", err)
+	}
+	status, ok = (<-channel).Object.(*kapi.Status)
+	if !ok || status.Code != http.StatusConflict {
+		t.Errorf("Expected an older route moved to a claimed port to conflict, got %#v", status)
+	}
+
+	channel, err = storage.Update(kapi.NewDefaultContext(), newRoute("old", "10001"))
+	if err != nil {
+		t.Fatalf("Unexpected non-nil error: %#v", err)
+	}
+	route, ok := (<-channel).Object.(*api.Route)
+	if !ok {
+		t.Fatalf("Expected the route to be updated")
+	}
+	if !api.RouteHostClaimed(route).After(created.Time) {
+		t.Errorf("Expected the route to claim its new port anew, got %v", route.Annotations)
+	}
+}
@@ -0,0 +1,102 @@
+package routestatus
+
+import (
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/client/record"
+	"github.com/openshift/origin/pkg/route/api"
+	"github.com/openshift/origin/pkg/route/api/validation"
+	"github.com/openshift/origin/pkg/route/registry/route"
+)
+
+// maxUpdateRetries bounds how often an update that conflicts with a concurrent change to the route is retried.
+const maxUpdateRetries = 5
+
+// REST implements the RESTStorage interface for RouteStatusUpdates. Creating a RouteStatusUpdate
+// records the reporting router's view of a Route in that Route's status.
+type REST struct {
+	registry route.Registry
+	recorder record.Recorder
+}
+
+// NewREST returns a new REST.
+func NewREST(registry route.Registry) apiserver.RESTStorage {
+	return &REST{registry, record.NewRecorder()}
+}
+
+// New returns a new RouteStatusUpdate for use with Create.
+func (r *REST) New() runtime.Object {
+	return &api.RouteStatusUpdate{}
+}
+
+// Create replaces the ingress entry of the reporting router in the named Route and returns the Route.
+func (r *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	update, ok := obj.(*api.RouteStatusUpdate)
+	if !ok {
+		return nil, fmt.Errorf("not a route status update: %#v", obj)
+	}
+	if !kapi.ValidNamespace(ctx, &update.ObjectMeta) {
+		return nil, errors.NewConflict("routeStatusUpdate", update.Namespace, fmt.Errorf("RouteStatusUpdate.Namespace does not match the provided context"))
+	}
+	if errs := validation.ValidateRouteStatusUpdate(update); len(errs) > 0 {
+		return nil, errors.NewInvalid("routeStatusUpdate", update.Name, errs)
+	}
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		// routers report concurrently, the update fails if the route changed since it was read and the
+		// entry is then set on the new version
+		for i := 0; ; i++ {
+			route, err := r.registry.GetRoute(ctx, update.Name)
+			if err != nil {
+				return nil, err
+			}
+			if route.Status == nil {
+				route.Status = &api.RouteStatus{}
+			}
+			changed := SetIngress(route.Status, update.Ingress)
+			if !changed {
+				return route, nil
+			}
+			if err := r.registry.UpdateRoute(ctx, route); err != nil {
+				if errors.IsConflict(err) && i < maxUpdateRetries {
+					continue
+				}
+				return nil, err
+			}
+			recordIngress(r.recorder, route, update.Ingress)
+			return r.registry.GetRoute(ctx, route.Name)
+		}
+	}), nil
+}
+
+// recordIngress records the decision of a router about route as an event.
+func recordIngress(recorder record.Recorder, route *api.Route, ingress api.RouteIngress) {
+	switch ingress.Phase {
+	case api.RouteAdmitted:
+		recorder.Eventf(route, "admitted", "Admitted by router %s as %s", ingress.RouterName, ingress.Host)
+	case api.RouteRejected:
+		recorder.Eventf(route, "rejected", "Rejected by router %s: %s", ingress.RouterName, ingress.Message)
+	}
+}
+
+// SetIngress replaces the entry in status reported by the same router as ingress, or appends
+// ingress if that router has not reported yet. It returns false if the entry was already current.
+func SetIngress(status *api.RouteStatus, ingress api.RouteIngress) bool {
+	for i := range status.Ingress {
+		if status.Ingress[i].RouterName != ingress.RouterName {
+			continue
+		}
+		if status.Ingress[i] == ingress {
+			return false
+		}
+		status.Ingress[i] = ingress
+		return true
+	}
+	status.Ingress = append(status.Ingress, ingress)
+	return true
+}
@@ -0,0 +1,144 @@
+package routestatus
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+
+	"github.com/openshift/origin/pkg/client/record"
+	"github.com/openshift/origin/pkg/route/api"
+	"github.com/openshift/origin/pkg/route/registry/test"
+)
+
+func TestCreateRecordsIngress(t *testing.T) {
+	registry := test.NewRouteRegistry()
+	registry.Routes = &api.RouteList{
+		Items: []api.Route{
+			{
+				ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: kapi.NamespaceDefault},
+				Host:       "www.example.com",
+				Status: &api.RouteStatus{
+					Ingress: []api.RouteIngress{
+						{RouterName: "other", Host: "www.example.com", Phase: api.RouteAdmitted},
+						{RouterName: "router", Host: "www.example.com", Phase: api.RouteRejected, Reason: "RouterError"},
+					},
+				},
+			},
+		},
+	}
+	storage := NewREST(registry).(*REST)
+	recorder := &record.FakeRecorder{}
+	storage.recorder = recorder
+
+	channel, err := storage.Create(kapi.NewDefaultContext(), &api.RouteStatusUpdate{
+		ObjectMeta: kapi.ObjectMeta{Name: "foo"},
+		Ingress:    api.RouteIngress{RouterName: "router", Host: "www.example.com", Phase: api.RouteAdmitted},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := <-channel
+	route, ok := result.Object.(*api.Route)
+	if !ok {
+		t.Fatalf("expected a route, got %#v", result.Object)
+	}
+	if len(route.Status.Ingress) != 2 {
+		t.Fatalf("expected two ingress entries, got %#v", route.Status.Ingress)
+	}
+	if route.Status.Ingress[0].RouterName != "other" || route.Status.Ingress[0].Phase != api.RouteAdmitted {
+		t.Errorf("unexpected change to another router's entry: %#v", route.Status.Ingress[0])
+	}
+	if e, a := (api.RouteIngress{RouterName: "router", Host: "www.example.com", Phase: api.RouteAdmitted}), route.Status.Ingress[1]; e != a {
+		t.Errorf("expected %#v, got %#v", e, a)
+	}
+	if len(recorder.Events) != 1 || !strings.HasPrefix(recorder.Events[0], "admitted ") {
+		t.Errorf("expected an admitted event, got %v", recorder.Events)
+	}
+}
+
+// conflictingRegistry fails the first updates of routes as if they had been changed concurrently.
+type conflictingRegistry struct {
+	*test.RouteRegistry
+	conflicts int
+}
+
+func (r *conflictingRegistry) UpdateRoute(ctx kapi.Context, route *api.Route) error {
+	if r.conflicts > 0 {
+		r.conflicts--
+		return errors.NewConflict("route", route.Name, fmt.Errorf("the route was modified"))
+	}
+	return r.RouteRegistry.UpdateRoute(ctx, route)
+}
+
+func TestCreateRetriesConflicts(t *testing.T) {
+	registry := &conflictingRegistry{RouteRegistry: test.NewRouteRegistry(), conflicts: 2}
+	registry.Routes = &api.RouteList{
+		Items: []api.Route{{ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: kapi.NamespaceDefault}, Host: "www.example.com"}},
+	}
+	storage := NewREST(registry).(*REST)
+	recorder := &record.FakeRecorder{}
+	storage.recorder = recorder
+
+	channel, err := storage.Create(kapi.NewDefaultContext(), &api.RouteStatusUpdate{
+		ObjectMeta: kapi.ObjectMeta{Name: "foo"},
+		Ingress:    api.RouteIngress{RouterName: "router", Host: "www.example.com", Phase: api.RouteAdmitted},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	route, ok := (<-channel).Object.(*api.Route)
+	if !ok {
+		t.Fatalf("expected the update to be retried until it succeeds")
+	}
+	if route.Status == nil || len(route.Status.Ingress) != 1 {
+		t.Errorf("expected one ingress entry, got %#v", route.Status)
+	}
+	if registry.conflicts != 0 || len(recorder.Events) != 1 {
+		t.Errorf("expected both conflicts to be retried and one event, got %d %v", registry.conflicts, recorder.Events)
+	}
+}
+
+func TestCreateMissingRoute(t *testing.T) {
+	storage := NewREST(test.NewRouteRegistry())
+
+	channel, err := storage.(*REST).Create(kapi.NewDefaultContext(), &api.RouteStatusUpdate{
+		ObjectMeta: kapi.ObjectMeta{Name: "foo"},
+		Ingress:    api.RouteIngress{RouterName: "router", Phase: api.RouteAdmitted},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := <-channel
+	if status, ok := result.Object.(*kapi.Status); !ok || status.Status != kapi.StatusFailure {
+		t.Errorf("expected a failure status, got %#v", result.Object)
+	}
+}
+
+func TestCreateInvalid(t *testing.T) {
+	storage := NewREST(test.NewRouteRegistry())
+
+	_, err := storage.(*REST).Create(kapi.NewDefaultContext(), &api.RouteStatusUpdate{
+		ObjectMeta: kapi.ObjectMeta{Name: "foo"},
+		Ingress:    api.RouteIngress{Phase: "Unknown"},
+	})
+	if err == nil {
+		t.Errorf("expected an error for an invalid update")
+	}
+}
+
+func TestSetIngress(t *testing.T) {
+	status := &api.RouteStatus{}
+	ingress := api.RouteIngress{RouterName: "router", Phase: api.RouteAdmitted}
+	if !SetIngress(status, ingress) {
+		t.Errorf("expected a new entry to change the status")
+	}
+	if SetIngress(status, ingress) {
+		t.Errorf("expected an identical entry to leave the status unchanged")
+	}
+	if len(status.Ingress) != 1 {
+		t.Errorf("expected one ingress entry, got %#v", status.Ingress)
+	}
+}
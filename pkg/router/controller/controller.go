@@ -19,6 +19,9 @@ type RouterController struct {
 	Plugin        router.Plugin
 	NextRoute     func() (watch.EventType, *routeapi.Route, error)
 	NextEndpoints func() (watch.EventType, *kapi.Endpoints, error)
+	// Listed blocks until the routes and endpoints have been listed once and returns those lists, the
+	// plugin is not reconciled if nil
+	Listed func() (*routeapi.RouteList, *kapi.EndpointsList)
 }
 
 // Run begins watching and syncing.
@@ -26,6 +29,27 @@ func (c *RouterController) Run() {
 	glog.V(4).Info("Running router controller")
 	go util.Forever(c.HandleRoute, 0)
 	go util.Forever(c.HandleEndpoints, 0)
+	if c.Listed != nil {
+		go c.Reconcile()
+	}
+}
+
+// Reconcile waits for the first lists of routes and endpoints and passes them to the plugin, if it is a
+// router.Reconciler.
+func (c *RouterController) Reconcile() {
+	routes, endpoints := c.Listed()
+	reconciler, ok := c.Plugin.(router.Reconciler)
+	if !ok {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	glog.V(4).Infof("Reconciling the router with %d routes and %d endpoints", len(routes.Items), len(endpoints.Items))
+	if err := reconciler.Reconcile(routes.Items, endpoints.Items); err != nil {
+		glog.Errorf("Unable to reconcile the router: %v", err)
+	}
 }
 
 // HandleRoute handles a single Route event and synchronizes the router backend.
@@ -1,6 +1,8 @@
 package factory
 
 import (
+	"sync"
+
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
@@ -15,17 +17,64 @@ import (
 	"github.com/openshift/origin/pkg/router/controller"
 )
 
+// RouterControllerFactory initializes and manages the watches that drive a router controller.
 type RouterControllerFactory struct {
 	KClient  kclient.Interface
 	OSClient osclient.Interface
+
+	// Namespace limits the routes and endpoints the router handles to a single namespace, all
+	// namespaces are handled if empty
+	Namespace string
+	// Namespaces limits the routes and endpoints the router handles to several namespaces, and
+	// takes precedence over Namespace when set
+	Namespaces []string
+	// Labels limits the routes the router handles to those matching the selector, all routes are
+	// handled if nil
+	Labels labels.Selector
 }
 
+// Create creates a RouterController that dispatches the events of the routes and endpoints in the
+// router's shard to plugin.
 func (factory *RouterControllerFactory) Create(plugin router.Plugin) *controller.RouterController {
+	selector := factory.Labels
+	if selector == nil {
+		selector = labels.Everything()
+	}
+
+	var routes, endpoints cache.ListerWatcher
+	routes = &routeLW{factory.OSClient, factory.Namespace, selector}
+	endpoints = &endpointsLW{factory.KClient, factory.Namespace}
+	if len(factory.Namespaces) > 0 {
+		routes = &namespacesLW{
+			namespaces: factory.Namespaces,
+			list: func(namespace string) (runtime.Object, error) {
+				return (&routeLW{factory.OSClient, namespace, selector}).List()
+			},
+			watch: func(namespace, resourceVersion string) (watch.Interface, error) {
+				return (&routeLW{factory.OSClient, namespace, selector}).Watch(resourceVersion)
+			},
+		}
+		endpoints = &namespacesLW{
+			namespaces: factory.Namespaces,
+			list: func(namespace string) (runtime.Object, error) {
+				return (&endpointsLW{factory.KClient, namespace}).List()
+			},
+			watch: func(namespace, resourceVersion string) (watch.Interface, error) {
+				return (&endpointsLW{factory.KClient, namespace}).Watch(resourceVersion)
+			},
+		}
+	}
+
+	routesListed := make(chan runtime.Object, 1)
+	routes = &firstListLW{ListerWatcher: routes, listed: routesListed}
+	endpointsListed := make(chan runtime.Object, 1)
+	endpoints = &firstListLW{ListerWatcher: endpoints, listed: endpointsListed}
+
 	routeEventQueue := oscache.NewEventQueue(cache.MetaNamespaceKeyFunc)
-	cache.NewReflector(&routeLW{factory.OSClient}, &routeapi.Route{}, routeEventQueue).Run()
+	cache.NewReflector(routes, &routeapi.Route{}, routeEventQueue).Run()
 
 	endpointsEventQueue := oscache.NewEventQueue(cache.MetaNamespaceKeyFunc)
-	cache.NewReflector(&endpointsLW{factory.KClient}, &kapi.Endpoints{}, endpointsEventQueue).Run()
+	cache.NewReflector(endpoints, &kapi.Endpoints{}, endpointsEventQueue).Run()
 
 	return &controller.RouterController{
 		Plugin: plugin,
@@ -43,29 +92,50 @@ func (factory *RouterControllerFactory) Create(plugin router.Plugin) *controller
 			}
 			return eventType, obj.(*routeapi.Route), nil
 		},
+		Listed: func() (*routeapi.RouteList, *kapi.EndpointsList) {
+			return (<-routesListed).(*routeapi.RouteList), (<-endpointsListed).(*kapi.EndpointsList)
+		},
+	}
+}
+
+// firstListLW sends the result of the first successful list to listed.
+type firstListLW struct {
+	cache.ListerWatcher
+	once   sync.Once
+	listed chan<- runtime.Object
+}
+
+func (lw *firstListLW) List() (runtime.Object, error) {
+	obj, err := lw.ListerWatcher.List()
+	if err == nil {
+		lw.once.Do(func() { lw.listed <- obj })
 	}
+	return obj, err
 }
 
 type routeLW struct {
-	client osclient.Interface
+	client    osclient.Interface
+	namespace string
+	labels    labels.Selector
 }
 
 func (lw *routeLW) List() (runtime.Object, error) {
-	return lw.client.Routes(kapi.NamespaceAll).List(labels.Everything(), labels.Everything())
+	return lw.client.Routes(lw.namespace).List(lw.labels, labels.Everything())
 }
 
 func (lw *routeLW) Watch(resourceVersion string) (watch.Interface, error) {
-	return lw.client.Routes(kapi.NamespaceAll).Watch(labels.Everything(), labels.Everything(), resourceVersion)
+	return lw.client.Routes(lw.namespace).Watch(lw.labels, labels.Everything(), resourceVersion)
 }
 
 type endpointsLW struct {
-	client kclient.Interface
+	client    kclient.Interface
+	namespace string
 }
 
 func (lw *endpointsLW) List() (runtime.Object, error) {
-	return lw.client.Endpoints(kapi.NamespaceAll).List(labels.Everything())
+	return lw.client.Endpoints(lw.namespace).List(labels.Everything())
 }
 
 func (lw *endpointsLW) Watch(resourceVersion string) (watch.Interface, error) {
-	return lw.client.Endpoints(kapi.NamespaceAll).Watch(labels.Everything(), labels.Everything(), resourceVersion)
+	return lw.client.Endpoints(lw.namespace).Watch(labels.Everything(), labels.Everything(), resourceVersion)
 }
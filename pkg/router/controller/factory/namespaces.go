@@ -0,0 +1,167 @@
+package factory
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+// namespacesLW lists and watches a resource in several namespaces as a single resource, for routers whose
+// credentials are limited to the namespaces of their shard and cannot list or watch all namespaces.
+type namespacesLW struct {
+	namespaces []string
+	// list lists the resource in a namespace, the items of the first list are replaced with those of all
+	list func(namespace string) (runtime.Object, error)
+	// watch watches the resource in a namespace from a resource version
+	watch func(namespace, resourceVersion string) (watch.Interface, error)
+}
+
+// List returns the items of all namespaces in a single list.  Resource versions are etcd indexes shared by all
+// namespaces, the list has the lowest of them so that a watch started from it misses no change in any namespace.
+func (lw *namespacesLW) List() (runtime.Object, error) {
+	var (
+		merged          runtime.Object
+		items           []runtime.Object
+		resourceVersion uint64
+	)
+	for _, namespace := range lw.namespaces {
+		list, err := lw.list(namespace)
+		if err != nil {
+			return nil, err
+		}
+		listItems, err := runtime.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, listItems...)
+
+		accessor, err := meta.Accessor(list)
+		if err != nil {
+			return nil, err
+		}
+		version, err := strconv.ParseUint(accessor.ResourceVersion(), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if merged == nil || version < resourceVersion {
+			resourceVersion = version
+		}
+		if merged == nil {
+			merged = list
+		}
+	}
+	if merged == nil {
+		return nil, fmt.Errorf("no namespaces to list")
+	}
+
+	if err := runtime.SetList(merged, items); err != nil {
+		return nil, err
+	}
+	accessor, err := meta.Accessor(merged)
+	if err != nil {
+		return nil, err
+	}
+	accessor.SetResourceVersion(strconv.FormatUint(resourceVersion, 10))
+	return merged, nil
+}
+
+// Watch watches all namespaces from resourceVersion.  The watch ends with an error when the watch of any
+// namespace ends: the reflector would watch again from the version of the last event, which the watches of
+// the other namespaces may not have reached yet, while an error makes it list all namespaces again.
+func (lw *namespacesLW) Watch(resourceVersion string) (watch.Interface, error) {
+	watches := []watch.Interface{}
+	for _, namespace := range lw.namespaces {
+		w, err := lw.watch(namespace, resourceVersion)
+		if err != nil {
+			for _, started := range watches {
+				started.Stop()
+			}
+			return nil, err
+		}
+		watches = append(watches, w)
+	}
+	return newMergedWatch(watches), nil
+}
+
+// mergedWatch sends the events of several watches on a single channel, and an error event when one of them
+// ends before the merged watch is stopped.
+type mergedWatch struct {
+	watches  []watch.Interface
+	result   chan watch.Event
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newMergedWatch(watches []watch.Interface) *mergedWatch {
+	m := &mergedWatch{
+		watches: watches,
+		result:  make(chan watch.Event),
+		stop:    make(chan struct{}),
+	}
+	wg := sync.WaitGroup{}
+	for _, w := range watches {
+		wg.Add(1)
+		go func(w watch.Interface) {
+			defer wg.Done()
+			defer m.Stop()
+			for {
+				select {
+				case event, ok := <-w.ResultChan():
+					if !ok {
+						m.sendEnded()
+						return
+					}
+					select {
+					case m.result <- event:
+					case <-m.stop:
+						return
+					}
+				case <-m.stop:
+					return
+				}
+			}
+		}(w)
+	}
+	go func() {
+		wg.Wait()
+		close(m.result)
+	}()
+	return m
+}
+
+// sendEnded sends the error that ends the merged watch, unless it was stopped.
+func (m *mergedWatch) sendEnded() {
+	select {
+	case <-m.stop:
+		return
+	default:
+	}
+	event := watch.Event{
+		Type:   watch.Error,
+		Object: &kapi.Status{Status: kapi.StatusFailure, Message: "the watch of a namespace ended"},
+	}
+	select {
+	case m.result <- event:
+	case <-m.stop:
+	}
+}
+
+// ResultChan implements watch.Interface.
+func (m *mergedWatch) ResultChan() <-chan watch.Event {
+	return m.result
+}
+
+// Stop implements watch.Interface, it stops the watches of all namespaces.
+func (m *mergedWatch) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+		for _, w := range m.watches {
+			w.Stop()
+		}
+	})
+}
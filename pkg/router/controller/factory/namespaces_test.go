@@ -0,0 +1,155 @@
+package factory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+func TestNamespacesLWList(t *testing.T) {
+	lists := map[string]*kapi.EndpointsList{
+		"a": {
+			ListMeta: kapi.ListMeta{ResourceVersion: "12"},
+			Items:    []kapi.Endpoints{{ObjectMeta: kapi.ObjectMeta{Namespace: "a", Name: "one"}}},
+		},
+		"b": {
+			ListMeta: kapi.ListMeta{ResourceVersion: "9"},
+			Items: []kapi.Endpoints{
+				{ObjectMeta: kapi.ObjectMeta{Namespace: "b", Name: "two"}},
+				{ObjectMeta: kapi.ObjectMeta{Namespace: "b", Name: "three"}},
+			},
+		},
+	}
+	lw := &namespacesLW{
+		namespaces: []string{"a", "b"},
+		list: func(namespace string) (runtime.Object, error) {
+			return lists[namespace], nil
+		},
+	}
+
+	obj, err := lw.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	list := obj.(*kapi.EndpointsList)
+	if len(list.Items) != 3 {
+		t.Errorf("Expected the endpoints of both namespaces, got %#v", list.Items)
+	}
+	if list.ResourceVersion != "9" {
+		t.Errorf("Expected the lowest resource version, got %s", list.ResourceVersion)
+	}
+}
+
+func TestNamespacesLWListError(t *testing.T) {
+	lw := &namespacesLW{
+		namespaces: []string{"a", "b"},
+		list: func(namespace string) (runtime.Object, error) {
+			if namespace == "b" {
+				return nil, errors.New("forbidden")
+			}
+			return &kapi.EndpointsList{ListMeta: kapi.ListMeta{ResourceVersion: "1"}}, nil
+		},
+	}
+	if _, err := lw.List(); err == nil {
+		t.Errorf("Expected the error of a namespace to fail the list")
+	}
+}
+
+func TestNamespacesLWWatch(t *testing.T) {
+	watches := map[string]*watch.FakeWatcher{"a": watch.NewFake(), "b": watch.NewFake()}
+	versions := map[string]string{}
+	lw := &namespacesLW{
+		namespaces: []string{"a", "b"},
+		watch: func(namespace, resourceVersion string) (watch.Interface, error) {
+			versions[namespace] = resourceVersion
+			return watches[namespace], nil
+		},
+	}
+
+	w, err := lw.Watch("9")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if versions["a"] != "9" || versions["b"] != "9" {
+		t.Errorf("Expected both namespaces to be watched from the resource version, got %v", versions)
+	}
+
+	endpoints := &kapi.Endpoints{ObjectMeta: kapi.ObjectMeta{Namespace: "b", Name: "two"}}
+	go watches["b"].Add(endpoints)
+	select {
+	case event := <-w.ResultChan():
+		if event.Type != watch.Added || event.Object != endpoints {
+			t.Errorf("Unexpected event: %#v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the event of namespace b")
+	}
+
+	// the end of the watch of one namespace ends the merged watch with an error, so that it is listed again
+	watches["a"].Stop()
+	select {
+	case event := <-w.ResultChan():
+		if event.Type != watch.Error {
+			t.Errorf("Expected an error event, got %#v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the end of the watch of namespace a to be reported")
+	}
+	select {
+	case _, ok := <-w.ResultChan():
+		if ok {
+			t.Errorf("Expected the merged watch to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the merged watch to be closed")
+	}
+	if !watches["b"].Stopped {
+		t.Errorf("Expected the watch of namespace b to be stopped")
+	}
+}
+
+func TestNamespacesLWWatchStop(t *testing.T) {
+	lw := &namespacesLW{
+		namespaces: []string{"a", "b"},
+		watch: func(namespace, resourceVersion string) (watch.Interface, error) {
+			return watch.NewFake(), nil
+		},
+	}
+
+	w, err := lw.Watch("9")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w.Stop()
+	select {
+	case event, ok := <-w.ResultChan():
+		if ok {
+			t.Errorf("Expected a stopped watch to close without an error, got %#v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the merged watch to be closed")
+	}
+}
+
+func TestNamespacesLWWatchError(t *testing.T) {
+	started := watch.NewFake()
+	lw := &namespacesLW{
+		namespaces: []string{"a", "b"},
+		watch: func(namespace, resourceVersion string) (watch.Interface, error) {
+			if namespace == "b" {
+				return nil, errors.New("forbidden")
+			}
+			return started, nil
+		},
+	}
+	if _, err := lw.Watch("1"); err == nil {
+		t.Errorf("Expected the error of a namespace to fail the watch")
+	}
+	if !started.Stopped {
+		t.Errorf("Expected the watches already started to be stopped")
+	}
+}
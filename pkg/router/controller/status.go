@@ -0,0 +1,136 @@
+package controller
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/golang/glog"
+
+	osclient "github.com/openshift/origin/pkg/client"
+	routeapi "github.com/openshift/origin/pkg/route/api"
+	"github.com/openshift/origin/pkg/router"
+)
+
+// RouteStatusRecorder records whether a router exposed a route.
+type RouteStatusRecorder interface {
+	RecordRouteAdmission(route *routeapi.Route)
+	RecordRouteRejection(route *routeapi.Route, reason, message string)
+}
+
+// RouteRejectedError is returned by a plugin that refuses to expose a route.
+type RouteRejectedError struct {
+	// Reason is a brief, machine readable explanation
+	Reason string
+	// Message is a human readable description
+	Message string
+}
+
+// Error implements the error interface.
+func (e *RouteRejectedError) Error() string {
+	return e.Message
+}
+
+// StatusRecorder implements RouteStatusRecorder by writing route status to the master.
+// Status is only written when it differs from what the route already reports for this router.
+type StatusRecorder struct {
+	// Client is used to record status on the master
+	Client osclient.RouteStatusUpdatesNamespacer
+	// RouterName identifies this router in route status
+	RouterName string
+}
+
+// NewStatusRecorder returns a StatusRecorder reporting as routerName.
+func NewStatusRecorder(client osclient.RouteStatusUpdatesNamespacer, routerName string) *StatusRecorder {
+	return &StatusRecorder{
+		Client:     client,
+		RouterName: routerName,
+	}
+}
+
+// RecordRouteAdmission records that route is exposed by this router.
+func (r *StatusRecorder) RecordRouteAdmission(route *routeapi.Route) {
+	r.recordIngress(route, routeapi.RouteIngress{
+		RouterName: r.RouterName,
+		Host:       route.Host,
+		Phase:      routeapi.RouteAdmitted,
+	})
+}
+
+// RecordRouteRejection records that this router refused to expose route.
+func (r *StatusRecorder) RecordRouteRejection(route *routeapi.Route, reason, message string) {
+	r.recordIngress(route, routeapi.RouteIngress{
+		RouterName: r.RouterName,
+		Host:       route.Host,
+		Phase:      routeapi.RouteRejected,
+		Reason:     reason,
+		Message:    message,
+	})
+}
+
+// recordIngress posts ingress for route unless the route already reports it.
+func (r *StatusRecorder) recordIngress(route *routeapi.Route, ingress routeapi.RouteIngress) {
+	if route.Status != nil {
+		for _, existing := range route.Status.Ingress {
+			if existing == ingress {
+				return
+			}
+		}
+	}
+
+	update := &routeapi.RouteStatusUpdate{
+		ObjectMeta: kapi.ObjectMeta{Name: route.Name, Namespace: route.Namespace},
+		Ingress:    ingress,
+	}
+	if _, err := r.Client.RouteStatusUpdates(route.Namespace).Create(update); err != nil {
+		glog.Errorf("Unable to record status for route %s/%s: %v", route.Namespace, route.Name, err)
+	}
+}
+
+// StatusAdmitter wraps a router.Plugin and reports the outcome of each added or modified
+// Route back to the master, so users can tell whether and by which router it was exposed.
+type StatusAdmitter struct {
+	// Plugin is the router plugin that processes events
+	Plugin router.Plugin
+	// Recorder records the outcome of each event
+	Recorder RouteStatusRecorder
+}
+
+// NewStatusAdmitter returns a StatusAdmitter reporting to recorder.
+func NewStatusAdmitter(plugin router.Plugin, recorder RouteStatusRecorder) *StatusAdmitter {
+	return &StatusAdmitter{
+		Plugin:   plugin,
+		Recorder: recorder,
+	}
+}
+
+// HandleRoute passes the event to the wrapped plugin and records whether the route was admitted.
+func (a *StatusAdmitter) HandleRoute(eventType watch.EventType, route *routeapi.Route) error {
+	err := a.Plugin.HandleRoute(eventType, route)
+
+	switch eventType {
+	case watch.Added, watch.Modified:
+		switch e := err.(type) {
+		case nil:
+			a.Recorder.RecordRouteAdmission(route)
+		case *RouteRejectedError:
+			a.Recorder.RecordRouteRejection(route, e.Reason, e.Message)
+		default:
+			a.Recorder.RecordRouteRejection(route, "RouterError", err.Error())
+		}
+	}
+
+	return err
+}
+
+// HandleEndpoints passes the event to the wrapped plugin.
+func (a *StatusAdmitter) HandleEndpoints(eventType watch.EventType, endpoints *kapi.Endpoints) error {
+	return a.Plugin.HandleEndpoints(eventType, endpoints)
+}
+
+// Reconcile passes the first lists of routes and endpoints to the wrapped plugin, if it is a
+// router.Reconciler.
+func (a *StatusAdmitter) Reconcile(routes []routeapi.Route, endpoints []kapi.Endpoints) error {
+	if reconciler, ok := a.Plugin.(router.Reconciler); ok {
+		return reconciler.Reconcile(routes, endpoints)
+	}
+	return nil
+}
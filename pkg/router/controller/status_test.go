@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	osclient "github.com/openshift/origin/pkg/client"
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+type fakePlugin struct {
+	err error
+}
+
+func (p *fakePlugin) HandleRoute(watch.EventType, *routeapi.Route) error {
+	return p.err
+}
+
+func (p *fakePlugin) HandleEndpoints(watch.EventType, *kapi.Endpoints) error {
+	return p.err
+}
+
+func TestStatusAdmitterRecordsIngress(t *testing.T) {
+	testCases := map[string]struct {
+		eventType watch.EventType
+		pluginErr error
+		existing  []routeapi.RouteIngress
+		expected  *routeapi.RouteIngress
+	}{
+		"admitted": {
+			eventType: watch.Added,
+			expected:  &routeapi.RouteIngress{RouterName: "test", Host: "www.example.com", Phase: routeapi.RouteAdmitted},
+		},
+		"rejected": {
+			eventType: watch.Modified,
+			pluginErr: errors.New("reload failed"),
+			expected:  &routeapi.RouteIngress{RouterName: "test", Host: "www.example.com", Phase: routeapi.RouteRejected, Reason: "RouterError", Message: "reload failed"},
+		},
+		"already current": {
+			eventType: watch.Modified,
+			existing:  []routeapi.RouteIngress{{RouterName: "test", Host: "www.example.com", Phase: routeapi.RouteAdmitted}},
+		},
+		"deleted": {
+			eventType: watch.Deleted,
+		},
+	}
+
+	for name, tc := range testCases {
+		client := &osclient.Fake{}
+		admitter := NewStatusAdmitter(&fakePlugin{tc.pluginErr}, NewStatusRecorder(client, "test"))
+		route := &routeapi.Route{
+			ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: "bar"},
+			Host:       "www.example.com",
+			Status:     &routeapi.RouteStatus{Ingress: tc.existing},
+		}
+
+		if err := admitter.HandleRoute(tc.eventType, route); err != tc.pluginErr {
+			t.Errorf("%s: expected plugin error %v, got %v", name, tc.pluginErr, err)
+		}
+
+		if tc.expected == nil {
+			if len(client.Actions) != 0 {
+				t.Errorf("%s: expected no status update, got %#v", name, client.Actions)
+			}
+			continue
+		}
+		if len(client.Actions) != 1 || client.Actions[0].Action != "create-routeStatusUpdate" {
+			t.Errorf("%s: expected a status update, got %#v", name, client.Actions)
+			continue
+		}
+		update := client.Actions[0].Value.(*routeapi.RouteStatusUpdate)
+		if update.Name != "foo" || update.Namespace != "bar" {
+			t.Errorf("%s: unexpected update target: %#v", name, update.ObjectMeta)
+		}
+		if update.Ingress != *tc.expected {
+			t.Errorf("%s: expected %#v, got %#v", name, *tc.expected, update.Ingress)
+		}
+	}
+}
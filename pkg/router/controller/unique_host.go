@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/golang/glog"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+	"github.com/openshift/origin/pkg/router"
+)
+
+// HostAlreadyClaimedReason is the rejection reason for routes whose host and path belong to
+// another namespace.
+const HostAlreadyClaimedReason = "HostAlreadyClaimed"
+
+// UniqueHost wraps a router.Plugin and only passes on routes from the namespace that owns their
+// host and path. The namespace of the route that claimed a host and path first owns it, as ordered
+// by routeapi.RouteOlderThan; routes from other namespaces are rejected until every route in the
+// owning namespace is deleted.
+type UniqueHost struct {
+	// Plugin is the router plugin that exposes admitted routes
+	Plugin router.Plugin
+	// Recorder is notified when a route is admitted or rejected outside of its own event
+	Recorder RouteStatusRecorder
+
+	// claims holds every route claiming a host and path, oldest first
+	claims map[string][]*routeapi.Route
+	// claimKeys holds the host and path each known route claims, keyed by namespace and name
+	claimKeys map[string]string
+}
+
+// NewUniqueHost returns a UniqueHost that passes admitted routes to plugin.
+func NewUniqueHost(plugin router.Plugin, recorder RouteStatusRecorder) *UniqueHost {
+	return &UniqueHost{
+		Plugin:    plugin,
+		Recorder:  recorder,
+		claims:    make(map[string][]*routeapi.Route),
+		claimKeys: make(map[string]string),
+	}
+}
+
+// Reconcile passes the first lists of routes and endpoints to the wrapped plugin, if it is a
+// router.Reconciler.
+func (p *UniqueHost) Reconcile(routes []routeapi.Route, endpoints []kapi.Endpoints) error {
+	if reconciler, ok := p.Plugin.(router.Reconciler); ok {
+		return reconciler.Reconcile(routes, endpoints)
+	}
+	return nil
+}
+
+// HandleRoute tracks which namespace owns the route's host and path and passes the event to the
+// wrapped plugin only if the route belongs to that namespace. A route that is rejected returns a
+// *RouteRejectedError.
+func (p *UniqueHost) HandleRoute(eventType watch.EventType, route *routeapi.Route) error {
+	name := routeName(route)
+	key := claimKey(route)
+
+	// a route that changes its host and path gives up its previous claim
+	if oldKey, ok := p.claimKeys[name]; ok && (eventType == watch.Deleted || oldKey != key) {
+		if err := p.release(oldKey, name); err != nil {
+			return err
+		}
+	}
+
+	if eventType == watch.Deleted {
+		return nil
+	}
+
+	previousOwner, hadOwner := p.owner(key)
+	p.claim(key, route)
+	owner, _ := p.owner(key)
+
+	if owner != route.Namespace {
+		glog.V(4).Infof("Route %s rejected, %s is owned by namespace %s", name, key, owner)
+		return &RouteRejectedError{
+			Reason:  HostAlreadyClaimedReason,
+			Message: fmt.Sprintf("%s is claimed by an older route in namespace %s", key, owner),
+		}
+	}
+
+	if hadOwner && previousOwner != owner {
+		// an older route took over the claim, evict the routes of the namespace that held it
+		for _, displaced := range p.claims[key] {
+			if displaced.Namespace != previousOwner {
+				continue
+			}
+			glog.V(4).Infof("Route %s displaced by older route %s", routeName(displaced), name)
+			if err := p.Plugin.HandleRoute(watch.Deleted, displaced); err != nil {
+				glog.Errorf("Unable to remove displaced route %s: %v", routeName(displaced), err)
+			}
+			p.Recorder.RecordRouteRejection(displaced, HostAlreadyClaimedReason, fmt.Sprintf("%s is claimed by an older route in namespace %s", key, owner))
+		}
+	}
+
+	return p.Plugin.HandleRoute(eventType, route)
+}
+
+// HandleEndpoints passes the event to the wrapped plugin.
+func (p *UniqueHost) HandleEndpoints(eventType watch.EventType, endpoints *kapi.Endpoints) error {
+	return p.Plugin.HandleEndpoints(eventType, endpoints)
+}
+
+// claim records route as claiming key, replacing any earlier version of the route.
+func (p *UniqueHost) claim(key string, route *routeapi.Route) {
+	name := routeName(route)
+	routes := []*routeapi.Route{}
+	for _, existing := range p.claims[key] {
+		if routeName(existing) != name {
+			routes = append(routes, existing)
+		}
+	}
+	routes = append(routes, route)
+	sort.Sort(byAge(routes))
+
+	p.claims[key] = routes
+	p.claimKeys[name] = key
+}
+
+// release removes the claim of the named route on key. If the route was exposed it is removed from
+// the wrapped plugin, and if its namespace no longer claims key the next oldest namespace is admitted.
+func (p *UniqueHost) release(key, name string) error {
+	owner, _ := p.owner(key)
+
+	var released *routeapi.Route
+	routes := []*routeapi.Route{}
+	for _, existing := range p.claims[key] {
+		if routeName(existing) == name {
+			released = existing
+			continue
+		}
+		routes = append(routes, existing)
+	}
+	delete(p.claimKeys, name)
+	if len(routes) == 0 {
+		delete(p.claims, key)
+	} else {
+		p.claims[key] = routes
+	}
+
+	if released == nil || released.Namespace != owner {
+		return nil
+	}
+	if err := p.Plugin.HandleRoute(watch.Deleted, released); err != nil {
+		return err
+	}
+
+	newOwner, ok := p.owner(key)
+	if !ok || newOwner == owner {
+		return nil
+	}
+	for _, promoted := range routes {
+		if promoted.Namespace != newOwner {
+			continue
+		}
+		glog.V(4).Infof("Route %s now owns %s", routeName(promoted), key)
+		if err := p.Plugin.HandleRoute(watch.Added, promoted); err != nil {
+			p.Recorder.RecordRouteRejection(promoted, "RouterError", err.Error())
+			continue
+		}
+		p.Recorder.RecordRouteAdmission(promoted)
+	}
+	return nil
+}
+
+// owner returns the namespace of the oldest route claiming key.
+func (p *UniqueHost) owner(key string) (string, bool) {
+	routes := p.claims[key]
+	if len(routes) == 0 {
+		return "", false
+	}
+	return routes[0].Namespace, true
+}
+
+// routeName returns the namespace qualified name of route.
+func routeName(route *routeapi.Route) string {
+	return route.Namespace + "/" + route.Name
+}
+
+// claimKey returns the host and path claimed by route.
+func claimKey(route *routeapi.Route) string {
+	return route.Host + route.Path
+}
+
+// byAge sorts routes oldest first.
+type byAge []*routeapi.Route
+
+func (r byAge) Len() int           { return len(r) }
+func (r byAge) Less(i, j int) bool { return routeapi.RouteOlderThan(r[i], r[j]) }
+func (r byAge) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
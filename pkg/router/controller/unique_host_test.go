@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+type routeEvent struct {
+	eventType watch.EventType
+	route     string
+}
+
+// recordingPlugin records the route events it is passed.
+type recordingPlugin struct {
+	fakePlugin
+	events []routeEvent
+}
+
+func (p *recordingPlugin) HandleRoute(eventType watch.EventType, route *routeapi.Route) error {
+	p.events = append(p.events, routeEvent{eventType, routeName(route)})
+	return nil
+}
+
+type fakeRecorder struct {
+	admitted []string
+	rejected []string
+}
+
+func (r *fakeRecorder) RecordRouteAdmission(route *routeapi.Route) {
+	r.admitted = append(r.admitted, routeName(route))
+}
+
+func (r *fakeRecorder) RecordRouteRejection(route *routeapi.Route, reason, message string) {
+	r.rejected = append(r.rejected, routeName(route))
+}
+
+func newTestRoute(namespace, name, host string, age time.Duration) *routeapi.Route {
+	return &routeapi.Route{
+		ObjectMeta: kapi.ObjectMeta{
+			Namespace:         namespace,
+			Name:              name,
+			CreationTimestamp: util.NewTime(time.Unix(1000, 0).Add(-age)),
+		},
+		Host: host,
+	}
+}
+
+func TestUniqueHostRejectsNewerRouteInOtherNamespace(t *testing.T) {
+	plugin := &recordingPlugin{}
+	p := NewUniqueHost(plugin, &fakeRecorder{})
+
+	if err := p.HandleRoute(watch.Added, newTestRoute("a", "first", "www.example.com", time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.HandleRoute(watch.Added, newTestRoute("a", "second", "www.example.com", 0)); err != nil {
+		t.Fatalf("unexpected error for a route in the owning namespace: %v", err)
+	}
+	err := p.HandleRoute(watch.Added, newTestRoute("b", "hijack", "www.example.com", 0))
+	if rejected, ok := err.(*RouteRejectedError); !ok || rejected.Reason != HostAlreadyClaimedReason {
+		t.Fatalf("expected the route to be rejected, got %v", err)
+	}
+	if err := p.HandleRoute(watch.Added, newTestRoute("b", "other", "other.example.com", 0)); err != nil {
+		t.Fatalf("unexpected error for an unclaimed host: %v", err)
+	}
+
+	expected := []routeEvent{{watch.Added, "a/first"}, {watch.Added, "a/second"}, {watch.Added, "b/other"}}
+	if len(plugin.events) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, plugin.events)
+	}
+	for i := range expected {
+		if plugin.events[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, plugin.events)
+		}
+	}
+}
+
+func TestUniqueHostOlderRouteDisplacesNewer(t *testing.T) {
+	plugin := &recordingPlugin{}
+	recorder := &fakeRecorder{}
+	p := NewUniqueHost(plugin, recorder)
+
+	if err := p.HandleRoute(watch.Added, newTestRoute("b", "newer", "www.example.com", 0)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.HandleRoute(watch.Added, newTestRoute("a", "older", "www.example.com", time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []routeEvent{{watch.Added, "b/newer"}, {watch.Deleted, "b/newer"}, {watch.Added, "a/older"}}
+	if len(plugin.events) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, plugin.events)
+	}
+	for i := range expected {
+		if plugin.events[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, plugin.events)
+		}
+	}
+	if len(recorder.rejected) != 1 || recorder.rejected[0] != "b/newer" {
+		t.Errorf("expected the displaced route to be rejected, got %v", recorder.rejected)
+	}
+}
+
+func TestUniqueHostPromotesNextOwnerOnDelete(t *testing.T) {
+	plugin := &recordingPlugin{}
+	recorder := &fakeRecorder{}
+	p := NewUniqueHost(plugin, recorder)
+
+	older := newTestRoute("a", "older", "www.example.com", time.Hour)
+	p.HandleRoute(watch.Added, older)
+	p.HandleRoute(watch.Added, newTestRoute("b", "newer", "www.example.com", 0))
+	plugin.events = nil
+
+	if err := p.HandleRoute(watch.Deleted, older); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []routeEvent{{watch.Deleted, "a/older"}, {watch.Added, "b/newer"}}
+	if len(plugin.events) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, plugin.events)
+	}
+	for i := range expected {
+		if plugin.events[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, plugin.events)
+		}
+	}
+	if len(recorder.admitted) != 1 || recorder.admitted[0] != "b/newer" {
+		t.Errorf("expected the promoted route to be admitted, got %v", recorder.admitted)
+	}
+}
+
+func TestUniqueHostReleasesClaimOnHostChange(t *testing.T) {
+	plugin := &recordingPlugin{}
+	p := NewUniqueHost(plugin, &fakeRecorder{})
+
+	p.HandleRoute(watch.Added, newTestRoute("a", "route", "www.example.com", time.Hour))
+	p.HandleRoute(watch.Modified, newTestRoute("a", "route", "new.example.com", time.Hour))
+
+	if err := p.HandleRoute(watch.Added, newTestRoute("b", "route", "www.example.com", 0)); err != nil {
+		t.Errorf("expected the released host to be claimable, got %v", err)
+	}
+}
@@ -13,3 +13,10 @@ type Plugin interface {
 	HandleRoute(watch.EventType, *routeapi.Route) error
 	HandleEndpoints(watch.EventType, *kapi.Endpoints) error
 }
+
+// Reconciler is implemented by plugins that start from state saved before the router started, such as a
+// snapshot of its last configuration.  Reconcile is called once with the first lists of the routes and
+// endpoints of the router's shard, so the plugin can drop the saved state of objects that no longer exist.
+type Reconciler interface {
+	Reconcile(routes []routeapi.Route, endpoints []kapi.Endpoints) error
+}
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/api/selectable"
+)
+
+func init() {
+	selectable.Register(&Template{}, func(obj runtime.Object) labels.Set { return TemplateToSelectableFields(obj.(*Template)) })
+}
+
+// TemplateToSelectableFields returns the fields of a template that clients can select on.
+func TemplateToSelectableFields(template *Template) labels.Set {
+	return labels.Set{
+		"name": template.Name,
+	}
+}
@@ -7,7 +7,13 @@ import (
 func init() {
 	api.Scheme.AddKnownTypes("",
 		&Template{},
+		&TemplateList{},
+		&TemplateInstance{},
+		&TemplateInstanceList{},
 	)
 }
 
-func (*Template) IsAnAPIObject() {}
+func (*Template) IsAnAPIObject()             {}
+func (*TemplateList) IsAnAPIObject()         {}
+func (*TemplateInstance) IsAnAPIObject()     {}
+func (*TemplateInstanceList) IsAnAPIObject() {}
@@ -18,6 +18,58 @@ type Template struct {
 	Parameters []Parameter `json:"parameters,omitempty"`
 }
 
+// TemplateList is a list of Template objects.
+type TemplateList struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
+	Items         []Template `json:"items"`
+}
+
+// TemplateInstance records that a Template was processed and its objects created, linking the
+// created objects back to the template and the parameter values used.
+type TemplateInstance struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	// Template references the template that was processed, if it was stored.
+	Template kapi.ObjectReference `json:"template,omitempty"`
+
+	// Parameters are the parameters of the template, with the values used during processing.
+	Parameters []Parameter `json:"parameters,omitempty"`
+
+	// Objects references each object created from the template. It is set by the server from the objects
+	// labeled with the name of the instance when the instance is created.
+	Objects []kapi.ObjectReference `json:"objects,omitempty"`
+}
+
+// TemplateInstanceLabel is set on the objects processed from a template to the name of the
+// TemplateInstance that records them.
+const TemplateInstanceLabel = "templateinstance"
+
+// TemplateInstanceRequesterAnnotation is the name of the user that created a TemplateInstance. It
+// is set by the server, and the objects of the instance are deleted as that user.
+const TemplateInstanceRequesterAnnotation = "openshift.io/requester"
+
+// TemplateInstanceKinds are the kinds of the objects a TemplateInstance may record. Objects of
+// other kinds, such as policies and projects, are never recorded or deleted with an instance.
+var TemplateInstanceKinds = []string{
+	"BuildConfig",
+	"DeploymentConfig",
+	"ImageRepository",
+	"Pod",
+	"ReplicationController",
+	"Route",
+	"Service",
+	"Template",
+}
+
+// TemplateInstanceList is a list of TemplateInstance objects.
+type TemplateInstanceList struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
+	Items         []TemplateInstance `json:"items"`
+}
+
 // Parameter defines a name/value variable that is to be processed during
 // the Template to Config transformation.
 type Parameter struct {
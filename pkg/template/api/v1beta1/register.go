@@ -7,8 +7,14 @@ import (
 func init() {
 	api.Scheme.AddKnownTypes("v1beta1",
 		&Template{},
+		&TemplateList{},
+		&TemplateInstance{},
+		&TemplateInstanceList{},
 	)
 	api.Scheme.AddKnownTypeWithName("v1beta1", "TemplateConfig", &Template{})
 }
 
-func (*Template) IsAnAPIObject() {}
+func (*Template) IsAnAPIObject()             {}
+func (*TemplateList) IsAnAPIObject()         {}
+func (*TemplateInstance) IsAnAPIObject()     {}
+func (*TemplateInstanceList) IsAnAPIObject() {}
@@ -12,11 +12,42 @@ type Template struct {
 
 	// Required: Items is an array of Kubernetes resources of Service,
 	// Pod and/or ReplicationController kind.
-	Items []runtime.RawExtension `json:"items"`
+	Items []runtime.RawExtension `json:"items" description:"Items is an array of Kubernetes resources of Service, Pod and/or ReplicationController kind."`
 
 	// Optional: Parameters is an array of Parameters used during the
 	// Template to Config transformation.
-	Parameters []Parameter `json:"parameters,omitempty"`
+	Parameters []Parameter `json:"parameters,omitempty" description:"Parameters is an array of Parameters used during the Template to Config transformation."`
+}
+
+// TemplateList is a list of Template objects.
+type TemplateList struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
+	Items         []Template `json:"items"`
+}
+
+// TemplateInstance records that a Template was processed and its objects created, linking the
+// created objects back to the template and the parameter values used.
+type TemplateInstance struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	// Template references the template that was processed, if it was stored.
+	Template kapi.ObjectReference `json:"template,omitempty" description:"Template references the template that was processed, if it was stored."`
+
+	// Parameters are the parameters of the template, with the values used during processing.
+	Parameters []Parameter `json:"parameters,omitempty" description:"Parameters are the parameters of the template, with the values used during processing."`
+
+	// Objects references each object created from the template. It is set by the server from the objects
+	// labeled with the name of the instance when the instance is created.
+	Objects []kapi.ObjectReference `json:"objects,omitempty" description:"Objects references each object created from the template, set by the server."`
+}
+
+// TemplateInstanceList is a list of TemplateInstance objects.
+type TemplateInstanceList struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
+	Items         []TemplateInstance `json:"items"`
 }
 
 // Parameter defines a name/value variable that is to be processed during
@@ -24,23 +55,23 @@ type Template struct {
 type Parameter struct {
 	// Required: Parameter name must be set and it can be referenced in Template
 	// Items using ${PARAMETER_NAME}
-	Name string `json:"name"`
+	Name string `json:"name" description:"Parameter name must be set and it can be referenced in Template Items using ${PARAMETER_NAME}"`
 
 	// Optional: Parameter can have description
-	Description string `json:"description,omitempty"`
+	Description string `json:"description,omitempty" description:"Parameter can have description"`
 
 	// Optional: Generate specifies the generator to be used to generate
 	// random string from an input value specified by From field. The result
 	// string is stored into Value field. If empty, no generator is being
 	// used, leaving the result Value untouched.
-	Generate string `json:"generate,omitempty"`
+	Generate string `json:"generate,omitempty" description:"Generate specifies the generator to be used to generate random string from an input value specified by From field. The result string is stored into Value field. If empty, no generator is being used, leaving the result Value untouched."`
 
 	// Optional: From is an input value for the generator.
-	From string `json:"from,omitempty"`
+	From string `json:"from,omitempty" description:"From is an input value for the generator."`
 
 	// Optional: Value holds the Parameter data. The Value data can be
 	// overwritten by the generator. The value replaces all occurances
 	// of the Parameter ${Name} expression during the Template to Config
 	// transformation.
-	Value string `json:"value,omitempty"`
+	Value string `json:"value,omitempty" description:"Value holds the Parameter data. The Value data can be overwritten by the generator. The value replaces all occurances of the Parameter ${Name} expression during the Template to Config transformation."`
 }
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	kutil "github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
 	"github.com/openshift/origin/pkg/template/api"
 	"github.com/openshift/origin/pkg/util"
 )
@@ -39,6 +41,35 @@ func ValidateTemplate(template *api.Template) (errs errors.ValidationErrorList)
 	return
 }
 
+// ValidateTemplateInstance tests if required fields in the TemplateInstance are set.
+func ValidateTemplateInstance(instance *api.TemplateInstance) (errs errors.ValidationErrorList) {
+	if len(instance.Name) == 0 {
+		errs = append(errs, errors.NewFieldRequired("name", instance.Name))
+	}
+	if len(instance.Template.Name) == 0 {
+		errs = append(errs, errors.NewFieldRequired("template.name", instance.Template.Name))
+	}
+	for i := range instance.Parameters {
+		paramErr := ValidateParameter(&instance.Parameters[i])
+		errs = append(errs, paramErr.PrefixIndex(i).Prefix("parameters")...)
+	}
+	for i, ref := range instance.Objects {
+		if len(ref.Kind) == 0 {
+			errs = append(errs, errors.NewFieldRequired(fmt.Sprintf("objects[%d].kind", i), ref.Kind))
+		}
+		if len(ref.Name) == 0 {
+			errs = append(errs, errors.NewFieldRequired(fmt.Sprintf("objects[%d].name", i), ref.Name))
+		}
+		if ref.Namespace != instance.Namespace {
+			errs = append(errs, errors.NewFieldInvalid(fmt.Sprintf("objects[%d].namespace", i), ref.Namespace, "must be the namespace of the template instance"))
+		}
+		if len(ref.Kind) > 0 && !kutil.NewStringSet(api.TemplateInstanceKinds...).Has(ref.Kind) {
+			errs = append(errs, errors.NewFieldNotSupported(fmt.Sprintf("objects[%d].kind", i), ref.Kind))
+		}
+	}
+	return
+}
+
 func filter(errs errors.ValidationErrorList, prefix string) errors.ValidationErrorList {
 	if errs == nil {
 		return errs
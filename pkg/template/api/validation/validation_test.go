@@ -98,3 +98,30 @@ func TestValidateTemplate(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateTemplateInstanceObjects(t *testing.T) {
+	tests := map[string]struct {
+		ref             kapi.ObjectReference
+		isValidExpected bool
+	}{
+		"in the namespace":     {kapi.ObjectReference{Kind: "Service", Namespace: "test", Name: "frontend"}, true},
+		"in another namespace": {kapi.ObjectReference{Kind: "Service", Namespace: "other", Name: "frontend"}, false},
+		"without a namespace":  {kapi.ObjectReference{Kind: "Service", Name: "frontend"}, false},
+		"of another kind":      {kapi.ObjectReference{Kind: "PolicyBinding", Namespace: "test", Name: "master"}, false},
+		"without a name":       {kapi.ObjectReference{Kind: "Service", Namespace: "test"}, false},
+	}
+	for name, test := range tests {
+		instance := &api.TemplateInstance{
+			ObjectMeta: kapi.ObjectMeta{Name: "app-1", Namespace: "test"},
+			Template:   kapi.ObjectReference{Name: "app"},
+			Objects:    []kapi.ObjectReference{test.ref},
+		}
+		errs := ValidateTemplateInstance(instance)
+		if test.isValidExpected && len(errs) != 0 {
+			t.Errorf("%s: unexpected errors: %v", name, errs)
+		}
+		if !test.isValidExpected && len(errs) == 0 {
+			t.Errorf("%s: expected validation errors", name)
+		}
+	}
+}
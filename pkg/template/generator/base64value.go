@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+)
+
+// Base64ValueGenerator implements Generator interface. It generates a
+// random string from the input expression the same way as the
+// ExpressionValueGenerator does, and returns the result encoded in
+// base64. It is meant for minting secrets that must be base64 encoded.
+//
+// Examples:
+//
+// from             | value
+// -----------------------------
+// "[a-zA-Z0-9]{8}" | "aFc0eVFVNWk="
+// "[\\w]{4}"       | "RFZnSw=="
+type Base64ValueGenerator struct {
+	expression ExpressionValueGenerator
+}
+
+// NewBase64ValueGenerator creates new Base64ValueGenerator.
+func NewBase64ValueGenerator(seed *rand.Rand) Base64ValueGenerator {
+	return Base64ValueGenerator{expression: NewExpressionValueGenerator(seed)}
+}
+
+// GenerateValue generates a random string from the expression and
+// returns it encoded in base64.
+func (g Base64ValueGenerator) GenerateValue(expression string) (interface{}, error) {
+	value, err := g.expression.GenerateValue(expression)
+	if err != nil {
+		return "", err
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("Unable to encode the generated value '%#v'", value)
+	}
+	return base64.StdEncoding.EncodeToString([]byte(s)), nil
+}
@@ -1,7 +1,24 @@
 package generator
 
+import (
+	"math/rand"
+)
+
 // Generator is an interface for generating random values
 // from an input expression
 type Generator interface {
 	GenerateValue(expression string) (interface{}, error)
 }
+
+// NewDefaultGenerators returns the generators available to the Parameters
+// of every processed Template, keyed by the name used in the Generate
+// field. The random generators use the provided seed, which should be
+// NewSecureRand unless the values may be predictable, since generated
+// values are commonly passwords and other secrets.
+func NewDefaultGenerators(seed *rand.Rand) map[string]Generator {
+	return map[string]Generator{
+		"expression": NewExpressionValueGenerator(seed),
+		"base64":     NewBase64ValueGenerator(seed),
+		"uuid":       NewUUIDGenerator(),
+	}
+}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"encoding/base64"
+	"math/rand"
+	"regexp"
+	"testing"
+)
+
+func TestBase64ValueGenerator(t *testing.T) {
+	generator := NewBase64ValueGenerator(rand.New(rand.NewSource(1337)))
+
+	value, err := generator.GenerateValue("test[A-Z0-9]{4}template")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(value.(string))
+	if err != nil {
+		t.Fatalf("Expected a base64 encoded value, got %v: %v", value, err)
+	}
+	if string(decoded) != "testQ3HVtemplate" {
+		t.Errorf("Expected testQ3HVtemplate to be encoded, got %s", decoded)
+	}
+
+	if v, err := generator.GenerateValue("[ABC]{3}"); err == nil {
+		t.Errorf("Expected [ABC]{3} to produce malformed syntax error (returned: %s)", v)
+	}
+}
+
+func TestUUIDGenerator(t *testing.T) {
+	uuidExp := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	generator := NewUUIDGenerator()
+
+	first, err := generator.GenerateValue("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := generator.GenerateValue("")
+	if !uuidExp.MatchString(first.(string)) {
+		t.Errorf("Expected a random UUID, got %s", first)
+	}
+	if first == second {
+		t.Errorf("Expected unique values, got %s twice", first)
+	}
+}
+
+func TestNewDefaultGenerators(t *testing.T) {
+	generators := NewDefaultGenerators(rand.New(rand.NewSource(1337)))
+	for _, name := range []string{"expression", "base64", "uuid"} {
+		if generators[name] == nil {
+			t.Errorf("Expected the %s generator to be available", name)
+		}
+	}
+}
+
+func TestNewSecureRand(t *testing.T) {
+	seed := NewSecureRand()
+	seed.Seed(1337)
+	first, second := seed.Int63(), seed.Int63()
+	if first < 0 || second < 0 {
+		t.Errorf("Expected non-negative values, got %d and %d", first, second)
+	}
+	if first == second {
+		t.Errorf("Expected unpredictable values, got %d twice", first)
+	}
+
+	generator := NewBase64ValueGenerator(NewSecureRand())
+	value, err := generator.GenerateValue("[a-zA-Z0-9]{16}")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(value.(string)); err != nil || len(decoded) != 16 {
+		t.Errorf("Expected 16 base64 encoded characters, got %v (%v)", value, err)
+	}
+}
@@ -0,0 +1,31 @@
+package generator
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+)
+
+// cryptoSource is a rand.Source that reads from crypto/rand, so that the values generated from it,
+// such as passwords and other secrets, cannot be predicted from earlier values or the time they were
+// generated at.
+type cryptoSource struct{}
+
+// Int63 returns a non-negative random int64 read from crypto/rand.
+func (cryptoSource) Int63() int64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("unable to read random bytes: %v", err))
+	}
+	return int64(binary.BigEndian.Uint64(b[:]) &^ (1 << 63))
+}
+
+// Seed does nothing, a cryptoSource cannot be seeded.
+func (cryptoSource) Seed(int64) {}
+
+// NewSecureRand returns a rand.Rand backed by crypto/rand, for the generators of values that must
+// not be predictable.
+func NewSecureRand() *rand.Rand {
+	return rand.New(cryptoSource{})
+}
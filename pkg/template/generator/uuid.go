@@ -0,0 +1,25 @@
+package generator
+
+import (
+	"code.google.com/p/go-uuid/uuid"
+)
+
+// UUIDGenerator implements Generator interface. It generates a random
+// (version 4) UUID, ignoring the input expression.
+//
+// Example:
+//
+// from | value
+// -----------------------------------------------
+// ""   | "1b4e28ba-2fa1-41d2-883f-0016d3cca427"
+type UUIDGenerator struct{}
+
+// NewUUIDGenerator creates new UUIDGenerator.
+func NewUUIDGenerator() UUIDGenerator {
+	return UUIDGenerator{}
+}
+
+// GenerateValue returns a new random UUID.
+func (g UUIDGenerator) GenerateValue(expression string) (interface{}, error) {
+	return uuid.NewRandom().String(), nil
+}
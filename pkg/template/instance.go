@@ -0,0 +1,44 @@
+package template
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	utilerr "github.com/GoogleCloudPlatform/kubernetes/pkg/util/errors"
+
+	configapi "github.com/openshift/origin/pkg/config/api"
+	"github.com/openshift/origin/pkg/template/api"
+)
+
+// NewTemplateInstance returns a TemplateInstance with the given name in namespace that records
+// that config was processed from the Template t, and labels the objects of config with the name
+// so the server records the objects created from them when the instance is created. The values
+// of generated parameters are not recorded.
+func NewTemplateInstance(name string, t *api.Template, config *configapi.Config, namespace string) (*api.TemplateInstance, error) {
+	instance := &api.TemplateInstance{
+		ObjectMeta: kapi.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Template: kapi.ObjectReference{
+			Kind:            "Template",
+			Namespace:       t.Namespace,
+			Name:            t.Name,
+			UID:             t.UID,
+			ResourceVersion: t.ResourceVersion,
+		},
+		Parameters: []api.Parameter{},
+	}
+
+	for _, param := range t.Parameters {
+		// generated values are usually secrets, which must not be readable from the instance
+		if len(param.Generate) > 0 {
+			param.Value = ""
+		}
+		instance.Parameters = append(instance.Parameters, param)
+	}
+
+	if errs := AddConfigLabels(config, labels.Set{api.TemplateInstanceLabel: name}); len(errs) > 0 {
+		return nil, utilerr.NewAggregate(errs)
+	}
+	return instance, nil
+}
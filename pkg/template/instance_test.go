@@ -0,0 +1,58 @@
+package template
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	configapi "github.com/openshift/origin/pkg/config/api"
+	"github.com/openshift/origin/pkg/template/api"
+)
+
+func TestNewTemplateInstance(t *testing.T) {
+	template := &api.Template{
+		ObjectMeta: kapi.ObjectMeta{Name: "app", Namespace: "shared", UID: "1234"},
+		Parameters: []api.Parameter{
+			{Name: "USER", Value: "admin"},
+			{Name: "PASSWORD", Generate: "expression", From: "[a-z]{8}", Value: "secret"},
+		},
+	}
+	config := &configapi.Config{Items: []runtime.Object{
+		&kapi.Service{ObjectMeta: kapi.ObjectMeta{Name: "frontend"}},
+		&kapi.Pod{ObjectMeta: kapi.ObjectMeta{Name: "database"}},
+	}}
+
+	instance, err := NewTemplateInstance("app-1", template, config, "test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if instance.Name != "app-1" || instance.Namespace != "test" {
+		t.Errorf("Unexpected instance metadata: %#v", instance.ObjectMeta)
+	}
+	if instance.Template.Name != "app" || instance.Template.Namespace != "shared" || instance.Template.UID != "1234" {
+		t.Errorf("Unexpected template reference: %#v", instance.Template)
+	}
+	if len(instance.Parameters) != 2 || instance.Parameters[0].Value != "admin" {
+		t.Errorf("Unexpected parameters: %#v", instance.Parameters)
+	}
+	if p := instance.Parameters[1]; p.Name != "PASSWORD" || p.From != "[a-z]{8}" || len(p.Value) != 0 {
+		t.Errorf("Expected the generated value to be redacted, got %#v", p)
+	}
+	if template.Parameters[1].Value != "secret" {
+		t.Errorf("Expected the template parameters to be unchanged, got %#v", template.Parameters)
+	}
+	if len(instance.Objects) != 0 {
+		t.Errorf("Expected the objects to be left to the server, got %#v", instance.Objects)
+	}
+	for _, item := range config.Items {
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if accessor.Labels()[api.TemplateInstanceLabel] != "app-1" {
+			t.Errorf("Expected %s to be labeled with the instance, got %v", accessor.Name(), accessor.Labels())
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package etcd
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	etcderr "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors/etcd"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	kubeetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/etcd"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	"github.com/openshift/origin/pkg/template/api"
+	etcdutil "github.com/openshift/origin/pkg/util/etcd"
+)
+
+const (
+	// TemplatePath is the path to template resources in etcd
+	TemplatePath string = "/templates"
+	// TemplateInstancePath is the path to template instance resources in etcd
+	TemplateInstancePath string = "/templateinstances"
+)
+
+// Etcd implements template.Registry backed by etcd.
+type Etcd struct {
+	tools.EtcdHelper
+}
+
+// New creates an etcd registry.
+func New(helper tools.EtcdHelper) *Etcd {
+	return &Etcd{
+		EtcdHelper: helper,
+	}
+}
+
+func makeTemplateListKey(ctx kapi.Context) string {
+	return kubeetcd.MakeEtcdListKey(ctx, TemplatePath)
+}
+
+func makeTemplateKey(ctx kapi.Context, id string) (string, error) {
+	return kubeetcd.MakeEtcdItemKey(ctx, TemplatePath, id)
+}
+
+// ListTemplates obtains a list of Templates.
+func (r *Etcd) ListTemplates(ctx kapi.Context, selector labels.Selector) (*api.TemplateList, error) {
+	allTemplates := api.TemplateList{}
+	err := r.ExtractToList(makeTemplateListKey(ctx), &allTemplates)
+	if err != nil {
+		return nil, err
+	}
+	filtered := []api.Template{}
+	for _, template := range allTemplates.Items {
+		if selector.Matches(labels.Set(template.Labels)) {
+			filtered = append(filtered, template)
+		}
+	}
+	allTemplates.Items = filtered
+	return &allTemplates, nil
+}
+
+// GetTemplate gets a specific Template specified by its ID.
+func (r *Etcd) GetTemplate(ctx kapi.Context, id string) (*api.Template, error) {
+	template := api.Template{}
+	key, err := makeTemplateKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	err = r.ExtractObj(key, &template, false)
+	if err != nil {
+		return nil, etcderr.InterpretGetError(err, "template", id)
+	}
+	return &template, nil
+}
+
+// CreateTemplate creates a new Template.
+func (r *Etcd) CreateTemplate(ctx kapi.Context, template *api.Template) error {
+	key, err := makeTemplateKey(ctx, template.Name)
+	if err != nil {
+		return err
+	}
+	err = r.CreateObj(key, template, 0)
+	return etcderr.InterpretCreateError(err, "template", template.Name)
+}
+
+// UpdateTemplate replaces an existing Template.
+func (r *Etcd) UpdateTemplate(ctx kapi.Context, template *api.Template) error {
+	key, err := makeTemplateKey(ctx, template.Name)
+	if err != nil {
+		return err
+	}
+	return etcdutil.UpdateObj(r.EtcdHelper, key, template, "template", template.Name)
+}
+
+// DeleteTemplate deletes a Template specified by its ID.
+func (r *Etcd) DeleteTemplate(ctx kapi.Context, id string) error {
+	key, err := makeTemplateKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = r.Delete(key, false)
+	return etcderr.InterpretDeleteError(err, "template", id)
+}
+
+// WatchTemplates begins watching for new, changed, or deleted Templates.
+func (r *Etcd) WatchTemplates(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	version, err := tools.ParseWatchResourceVersion(resourceVersion, "template")
+	if err != nil {
+		return nil, err
+	}
+	return r.WatchList(makeTemplateListKey(ctx), version, func(obj runtime.Object) bool {
+		template, ok := obj.(*api.Template)
+		if !ok {
+			return false
+		}
+		return label.Matches(labels.Set(template.Labels)) && field.Matches(api.TemplateToSelectableFields(template))
+	})
+}
+
+func makeTemplateInstanceListKey(ctx kapi.Context) string {
+	return kubeetcd.MakeEtcdListKey(ctx, TemplateInstancePath)
+}
+
+func makeTemplateInstanceKey(ctx kapi.Context, id string) (string, error) {
+	return kubeetcd.MakeEtcdItemKey(ctx, TemplateInstancePath, id)
+}
+
+// ListTemplateInstances obtains a list of TemplateInstances.
+func (r *Etcd) ListTemplateInstances(ctx kapi.Context, selector labels.Selector) (*api.TemplateInstanceList, error) {
+	allInstances := api.TemplateInstanceList{}
+	err := r.ExtractToList(makeTemplateInstanceListKey(ctx), &allInstances)
+	if err != nil {
+		return nil, err
+	}
+	filtered := []api.TemplateInstance{}
+	for _, instance := range allInstances.Items {
+		if selector.Matches(labels.Set(instance.Labels)) {
+			filtered = append(filtered, instance)
+		}
+	}
+	allInstances.Items = filtered
+	return &allInstances, nil
+}
+
+// GetTemplateInstance gets a specific TemplateInstance specified by its ID.
+func (r *Etcd) GetTemplateInstance(ctx kapi.Context, id string) (*api.TemplateInstance, error) {
+	instance := api.TemplateInstance{}
+	key, err := makeTemplateInstanceKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	err = r.ExtractObj(key, &instance, false)
+	if err != nil {
+		return nil, etcderr.InterpretGetError(err, "templateInstance", id)
+	}
+	return &instance, nil
+}
+
+// CreateTemplateInstance creates a new TemplateInstance.
+func (r *Etcd) CreateTemplateInstance(ctx kapi.Context, instance *api.TemplateInstance) error {
+	key, err := makeTemplateInstanceKey(ctx, instance.Name)
+	if err != nil {
+		return err
+	}
+	err = r.CreateObj(key, instance, 0)
+	return etcderr.InterpretCreateError(err, "templateInstance", instance.Name)
+}
+
+// UpdateTemplateInstance replaces an existing TemplateInstance.
+func (r *Etcd) UpdateTemplateInstance(ctx kapi.Context, instance *api.TemplateInstance) error {
+	key, err := makeTemplateInstanceKey(ctx, instance.Name)
+	if err != nil {
+		return err
+	}
+	return etcdutil.UpdateObj(r.EtcdHelper, key, instance, "templateInstance", instance.Name)
+}
+
+// DeleteTemplateInstance deletes a TemplateInstance specified by its ID.
+func (r *Etcd) DeleteTemplateInstance(ctx kapi.Context, id string) error {
+	key, err := makeTemplateInstanceKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = r.Delete(key, false)
+	return etcderr.InterpretDeleteError(err, "templateInstance", id)
+}
@@ -1,9 +1,6 @@
 package template
 
 import (
-	"math/rand"
-	"time"
-
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	apierr "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
@@ -38,9 +35,7 @@ func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 		return nil, apierr.NewInvalid("template", tpl.Name, errs)
 	}
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
-		generators := map[string]generator.Generator{
-			"expression": generator.NewExpressionValueGenerator(rand.New(rand.NewSource(time.Now().UnixNano()))),
-		}
+		generators := generator.NewDefaultGenerators(generator.NewSecureRand())
 		processor := template.NewProcessor(generators)
 		cfg, err := processor.Process(tpl)
 		if len(err) > 0 {
@@ -54,6 +49,16 @@ func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RE
 			// good way how to do it for just some items.
 			glog.V(1).Infof(utilerr.NewAggregate(err).Error())
 		}
+
+		// the instance is created after the processed objects, recording every instantiation under a
+		// name generated from the template, so a template can be processed many times. The objects are
+		// labeled with the name, which the server finds them by when the instance is created.
+		name := kapi.SimpleNameGenerator.GenerateName(tpl.Name + "-")
+		instance, instanceErr := template.NewTemplateInstance(name, tpl, cfg, kapi.Namespace(ctx))
+		if instanceErr != nil {
+			return nil, apierr.NewInternalError(instanceErr)
+		}
+		cfg.Items = append(cfg.Items, instance)
 		return cfg, nil
 	}), nil
 }
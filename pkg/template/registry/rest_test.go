@@ -1,12 +1,19 @@
 package template
 
 import (
+	"strings"
 	"testing"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
 
+	"github.com/openshift/origin/pkg/api/latest"
 	config "github.com/openshift/origin/pkg/config/api"
 	template "github.com/openshift/origin/pkg/template/api"
+	"github.com/openshift/origin/pkg/template/registry/etcd"
+	"github.com/openshift/origin/pkg/template/registry/templateinstance"
 )
 
 func TestNewRESTInvalidType(t *testing.T) {
@@ -19,7 +26,7 @@ func TestNewRESTInvalidType(t *testing.T) {
 
 func TestNewRESTDefaultsName(t *testing.T) {
 	storage := NewREST()
-	ch, err := storage.Create(nil, &template.Template{
+	ch, err := storage.Create(kapi.NewDefaultContext(), &template.Template{
 		ObjectMeta: kapi.ObjectMeta{
 			Name: "test",
 		},
@@ -28,8 +35,63 @@ func TestNewRESTDefaultsName(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	obj := <-ch
-	_, ok := obj.Object.(*config.Config)
+	cfg, ok := obj.Object.(*config.Config)
 	if !ok {
 		t.Fatalf("unexpected return object: %#v", obj)
 	}
+	if len(cfg.Items) != 1 {
+		t.Fatalf("expected only the template instance, got %#v", cfg.Items)
+	}
+	instance, ok := cfg.Items[0].(*template.TemplateInstance)
+	if !ok {
+		t.Fatalf("expected a template instance, got %#v", cfg.Items[0])
+	}
+	if !strings.HasPrefix(instance.Name, "test-") || len(instance.Name) == len("test-") || instance.Namespace != kapi.NamespaceDefault || instance.Template.Name != "test" {
+		t.Errorf("unexpected template instance: %#v", instance)
+	}
+}
+
+// noObjects finds no objects of template instances.
+type noObjects struct{}
+
+func (noObjects) ListObjects(kind, namespace string, selector labels.Selector) ([]kapi.ObjectReference, error) {
+	return nil, nil
+}
+
+func TestNewRESTRecordsEveryInstantiation(t *testing.T) {
+	ctx := kapi.NewDefaultContext()
+	fakeClient := tools.NewFakeEtcdClient(t)
+	registry := etcd.New(tools.EtcdHelper{Client: fakeClient, Codec: latest.Codec, ResourceVersioner: tools.RuntimeVersionAdapter{latest.ResourceVersioner}})
+	instances := templateinstance.NewREST(registry, noObjects{}).(apiserver.RESTCreater)
+
+	storage := NewREST()
+	names := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		ch, err := storage.Create(ctx, &template.Template{ObjectMeta: kapi.ObjectMeta{Name: "test"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cfg := (<-ch).Object.(*config.Config)
+		instance := cfg.Items[len(cfg.Items)-1]
+
+		ch, err = instances.Create(ctx, instance)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result := <-ch
+		created, ok := result.Object.(*template.TemplateInstance)
+		if !ok {
+			t.Fatalf("expected the template instance to be created, got %#v", result.Object)
+		}
+		names[created.Name] = true
+	}
+
+	if len(names) != 2 {
+		t.Errorf("expected two template instances with different names, got %v", names)
+	}
+	for name := range names {
+		if _, err := registry.GetTemplateInstance(ctx, name); err != nil {
+			t.Errorf("expected the template instance %s to be recorded, got %v", name, err)
+		}
+	}
 }
@@ -0,0 +1,25 @@
+package template
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	"github.com/openshift/origin/pkg/template/api"
+)
+
+// Registry is an interface for things that know how to store Templates.
+type Registry interface {
+	// ListTemplates obtains a list of templates that match a selector.
+	ListTemplates(ctx kapi.Context, selector labels.Selector) (*api.TemplateList, error)
+	// GetTemplate retrieves a specific template.
+	GetTemplate(ctx kapi.Context, id string) (*api.Template, error)
+	// CreateTemplate creates a new template.
+	CreateTemplate(ctx kapi.Context, template *api.Template) error
+	// UpdateTemplate updates a template.
+	UpdateTemplate(ctx kapi.Context, template *api.Template) error
+	// DeleteTemplate deletes a template.
+	DeleteTemplate(ctx kapi.Context, id string) error
+	// WatchTemplates watches for new/modified/deleted templates.
+	WatchTemplates(ctx kapi.Context, labels, fields labels.Selector, resourceVersion string) (watch.Interface, error)
+}
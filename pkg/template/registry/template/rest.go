@@ -0,0 +1,119 @@
+package template
+
+import (
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	"github.com/openshift/origin/pkg/api/selectable"
+	"github.com/openshift/origin/pkg/template/api"
+	"github.com/openshift/origin/pkg/template/api/validation"
+)
+
+// REST is an implementation of RESTStorage for stored Templates.
+type REST struct {
+	registry Registry
+}
+
+// NewREST creates a new REST backed by the given registry.
+func NewREST(registry Registry) apiserver.RESTStorage {
+	return &REST{
+		registry: registry,
+	}
+}
+
+// New returns a new Template for use with Create and Update.
+func (s *REST) New() runtime.Object {
+	return &api.Template{}
+}
+
+func (*REST) NewList() runtime.Object {
+	return &api.TemplateList{}
+}
+
+// List obtains a list of Templates that match selector.
+func (s *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime.Object, error) {
+	templates, err := s.registry.ListTemplates(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	if err := selectable.FilterList(templates, selector, fields); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// Get obtains the Template specified by its id.
+func (s *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
+	return s.registry.GetTemplate(ctx, id)
+}
+
+// Create stores a new Template.
+func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	template, ok := obj.(*api.Template)
+	if !ok {
+		return nil, fmt.Errorf("not a template: %#v", obj)
+	}
+	if !kapi.ValidNamespace(ctx, &template.ObjectMeta) {
+		return nil, errors.NewConflict("template", template.Namespace, fmt.Errorf("Template.Namespace does not match the provided context"))
+	}
+
+	kapi.FillObjectMetaSystemFields(ctx, &template.ObjectMeta)
+
+	if errs := validation.ValidateTemplate(template); len(errs) > 0 {
+		return nil, errors.NewInvalid("template", template.Name, errs)
+	}
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		if err := s.registry.CreateTemplate(ctx, template); err != nil {
+			return nil, err
+		}
+		return s.registry.GetTemplate(ctx, template.Name)
+	}), nil
+}
+
+// Update replaces an existing Template.
+func (s *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	template, ok := obj.(*api.Template)
+	if !ok {
+		return nil, fmt.Errorf("not a template: %#v", obj)
+	}
+	if len(template.Name) == 0 {
+		return nil, fmt.Errorf("name is unspecified: %#v", template)
+	}
+	if !kapi.ValidNamespace(ctx, &template.ObjectMeta) {
+		return nil, errors.NewConflict("template", template.Namespace, fmt.Errorf("Template.Namespace does not match the provided context"))
+	}
+
+	if errs := validation.ValidateTemplate(template); len(errs) > 0 {
+		return nil, errors.NewInvalid("template", template.Name, errs)
+	}
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		if err := s.registry.UpdateTemplate(ctx, template); err != nil {
+			return nil, err
+		}
+		return s.registry.GetTemplate(ctx, template.Name)
+	}), nil
+}
+
+// Delete asynchronously deletes the Template specified by its id.
+func (s *REST) Delete(ctx kapi.Context, id string) (<-chan apiserver.RESTResult, error) {
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		return &kapi.Status{Status: kapi.StatusSuccess}, s.registry.DeleteTemplate(ctx, id)
+	}), nil
+}
+
+// Watch returns Template events via a watch.Interface.
+// It implements apiserver.ResourceWatcher.
+func (s *REST) Watch(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	if err := selectable.ValidateFields(&api.Template{}, field); err != nil {
+		return nil, err
+	}
+	return s.registry.WatchTemplates(ctx, label, field, resourceVersion)
+}
@@ -0,0 +1,92 @@
+package template
+
+import (
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/template/api"
+	"github.com/openshift/origin/pkg/template/registry/test"
+)
+
+func TestCreateInvalidTemplate(t *testing.T) {
+	storage := NewREST(test.NewTemplateRegistry()).(*REST)
+
+	_, err := storage.Create(kapi.NewDefaultContext(), &api.Template{})
+	if !errors.IsInvalid(err) {
+		t.Errorf("Expected invalid error, got %v", err)
+	}
+}
+
+func TestCreateTemplateNamespaceMismatch(t *testing.T) {
+	storage := NewREST(test.NewTemplateRegistry()).(*REST)
+
+	_, err := storage.Create(kapi.NewDefaultContext(), &api.Template{
+		ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: "other"},
+	})
+	if !errors.IsConflict(err) {
+		t.Errorf("Expected conflict error, got %v", err)
+	}
+}
+
+func TestCreateTemplate(t *testing.T) {
+	registry := test.NewTemplateRegistry()
+	storage := NewREST(registry).(*REST)
+
+	channel, err := storage.Create(kapi.NewDefaultContext(), &api.Template{
+		ObjectMeta: kapi.ObjectMeta{Name: "foo"},
+		Items:      []runtime.Object{},
+		Parameters: []api.Parameter{{Name: "PASSWORD", Generate: "expression", From: "[a-z]{8}"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	select {
+	case result := <-channel:
+		template, ok := result.Object.(*api.Template)
+		if !ok {
+			t.Fatalf("Expected a template, got %#v", result.Object)
+		}
+		if template.Name != "foo" || template.Namespace != kapi.NamespaceDefault {
+			t.Errorf("Unexpected template: %#v", template)
+		}
+		if len(template.Parameters) != 1 || len(template.Parameters[0].Value) != 0 {
+			t.Errorf("Expected parameters to be stored unprocessed, got %#v", template.Parameters)
+		}
+	case <-time.After(50 * time.Millisecond):
+		t.Fatalf("Timed out waiting for result")
+	}
+}
+
+func TestUpdateTemplateWithoutName(t *testing.T) {
+	storage := NewREST(test.NewTemplateRegistry()).(*REST)
+
+	if _, err := storage.Update(kapi.NewDefaultContext(), &api.Template{}); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestDeleteTemplate(t *testing.T) {
+	registry := test.NewTemplateRegistry()
+	storage := NewREST(registry).(*REST)
+
+	channel, err := storage.Delete(kapi.NewDefaultContext(), "foo")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	select {
+	case result := <-channel:
+		status, ok := result.Object.(*kapi.Status)
+		if !ok || status.Status != kapi.StatusSuccess {
+			t.Errorf("Expected success status, got %#v", result.Object)
+		}
+		if registry.DeletedID != "foo" {
+			t.Errorf("Expected foo to be deleted, got %q", registry.DeletedID)
+		}
+	case <-time.After(50 * time.Millisecond):
+		t.Fatalf("Timed out waiting for result")
+	}
+}
@@ -0,0 +1,58 @@
+package templateinstance
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+)
+
+// ObjectLister finds the objects a template instance records.
+type ObjectLister interface {
+	// ListObjects returns references to the objects of kind in namespace that match selector.
+	ListObjects(kind, namespace string, selector labels.Selector) ([]kapi.ObjectReference, error)
+}
+
+// ClientObjectLister lists objects through the API. Kinds are mapped to resources with Mapper,
+// which may map the kinds of several APIs, and each resource is reached through the client
+// Clients returns for it.
+type ClientObjectLister struct {
+	Mapper  meta.RESTMapper
+	Clients resource.ClientMapper
+}
+
+// ListObjects returns references to the objects of kind in namespace that match selector.
+func (l *ClientObjectLister) ListObjects(kind, namespace string, selector labels.Selector) ([]kapi.ObjectReference, error) {
+	mapping, err := l.Mapper.RESTMapping(kind)
+	if err != nil {
+		return nil, err
+	}
+	client, err := l.Clients.ClientForMapping(mapping)
+	if err != nil {
+		return nil, err
+	}
+	list, err := resource.NewHelper(client, mapping).List(namespace, selector)
+	if err != nil {
+		return nil, err
+	}
+	items, err := runtime.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	refs := []kapi.ObjectReference{}
+	for _, item := range items {
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, kapi.ObjectReference{
+			Kind:       kind,
+			APIVersion: mapping.APIVersion,
+			Namespace:  namespace,
+			Name:       accessor.Name(),
+			UID:        accessor.UID(),
+		})
+	}
+	return refs, nil
+}
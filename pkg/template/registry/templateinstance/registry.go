@@ -0,0 +1,22 @@
+package templateinstance
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	"github.com/openshift/origin/pkg/template/api"
+)
+
+// Registry is an interface for things that know how to store TemplateInstances.
+type Registry interface {
+	// ListTemplateInstances obtains a list of template instances that match a selector.
+	ListTemplateInstances(ctx kapi.Context, selector labels.Selector) (*api.TemplateInstanceList, error)
+	// GetTemplateInstance retrieves a specific template instance.
+	GetTemplateInstance(ctx kapi.Context, id string) (*api.TemplateInstance, error)
+	// CreateTemplateInstance creates a new template instance.
+	CreateTemplateInstance(ctx kapi.Context, instance *api.TemplateInstance) error
+	// UpdateTemplateInstance updates a template instance.
+	UpdateTemplateInstance(ctx kapi.Context, instance *api.TemplateInstance) error
+	// DeleteTemplateInstance deletes a template instance.
+	DeleteTemplateInstance(ctx kapi.Context, id string) error
+}
@@ -0,0 +1,153 @@
+package templateinstance
+
+import (
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+	"github.com/openshift/origin/pkg/template/api"
+	"github.com/openshift/origin/pkg/template/api/validation"
+)
+
+// REST is an implementation of RESTStorage for TemplateInstances. The objects an instance records
+// are found by the server when it is created, among the objects of its namespace labeled with its
+// name, and cannot be set by clients. The user that creates an instance is recorded with it too, as
+// the user its objects are deleted as.
+type REST struct {
+	registry Registry
+	objects  ObjectLister
+}
+
+// NewREST creates a new REST backed by the given registry, which finds the objects of instances
+// with objects.
+func NewREST(registry Registry, objects ObjectLister) apiserver.RESTStorage {
+	return &REST{
+		registry: registry,
+		objects:  objects,
+	}
+}
+
+// New returns a new TemplateInstance for use with Create and Update.
+func (s *REST) New() runtime.Object {
+	return &api.TemplateInstance{}
+}
+
+func (*REST) NewList() runtime.Object {
+	return &api.TemplateInstanceList{}
+}
+
+// List obtains a list of TemplateInstances that match selector.
+func (s *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime.Object, error) {
+	return s.registry.ListTemplateInstances(ctx, selector)
+}
+
+// Get obtains the TemplateInstance specified by its id.
+func (s *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
+	return s.registry.GetTemplateInstance(ctx, id)
+}
+
+// Create stores a new TemplateInstance.
+func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	instance, ok := obj.(*api.TemplateInstance)
+	if !ok {
+		return nil, fmt.Errorf("not a template instance: %#v", obj)
+	}
+	if !kapi.ValidNamespace(ctx, &instance.ObjectMeta) {
+		return nil, errors.NewConflict("templateInstance", instance.Namespace, fmt.Errorf("TemplateInstance.Namespace does not match the provided context"))
+	}
+
+	kapi.FillObjectMetaSystemFields(ctx, &instance.ObjectMeta)
+	kapi.GenerateName(kapi.SimpleNameGenerator, &instance.ObjectMeta)
+	instance.Objects = nil
+	setRequester(instance, "")
+	if user, ok := authcontext.UserFrom(ctx); ok {
+		setRequester(instance, user.GetName())
+	}
+
+	if errs := validation.ValidateTemplateInstance(instance); len(errs) > 0 {
+		return nil, errors.NewInvalid("templateInstance", instance.Name, errs)
+	}
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		objects, err := s.findObjects(instance)
+		if err != nil {
+			return nil, err
+		}
+		instance.Objects = objects
+		if err := s.registry.CreateTemplateInstance(ctx, instance); err != nil {
+			return nil, err
+		}
+		return s.registry.GetTemplateInstance(ctx, instance.Name)
+	}), nil
+}
+
+// Update replaces an existing TemplateInstance.
+func (s *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	instance, ok := obj.(*api.TemplateInstance)
+	if !ok {
+		return nil, fmt.Errorf("not a template instance: %#v", obj)
+	}
+	if !kapi.ValidNamespace(ctx, &instance.ObjectMeta) {
+		return nil, errors.NewConflict("templateInstance", instance.Namespace, fmt.Errorf("TemplateInstance.Namespace does not match the provided context"))
+	}
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		// the objects are recorded when the instance is created and are kept as they are
+		existing, err := s.registry.GetTemplateInstance(ctx, instance.Name)
+		if err != nil {
+			return nil, err
+		}
+		instance.Objects = existing.Objects
+		setRequester(instance, existing.Annotations[api.TemplateInstanceRequesterAnnotation])
+
+		if errs := validation.ValidateTemplateInstance(instance); len(errs) > 0 {
+			return nil, errors.NewInvalid("templateInstance", instance.Name, errs)
+		}
+		if err := s.registry.UpdateTemplateInstance(ctx, instance); err != nil {
+			return nil, err
+		}
+		return s.registry.GetTemplateInstance(ctx, instance.Name)
+	}), nil
+}
+
+// Delete asynchronously deletes the TemplateInstance specified by its id. The objects
+// it records are deleted by the garbage collector once it observes the deletion.
+func (s *REST) Delete(ctx kapi.Context, id string) (<-chan apiserver.RESTResult, error) {
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		return &kapi.Status{Status: kapi.StatusSuccess}, s.registry.DeleteTemplateInstance(ctx, id)
+	}), nil
+}
+
+// findObjects returns references to the objects of the kinds an instance may record in the
+// namespace of instance that are labeled with its name. Objects that existed before the template
+// was processed are not labeled with the new name of the instance and are not recorded.
+func (s *REST) findObjects(instance *api.TemplateInstance) ([]kapi.ObjectReference, error) {
+	selector := labels.Set{api.TemplateInstanceLabel: instance.Name}.AsSelector()
+	objects := []kapi.ObjectReference{}
+	for _, kind := range api.TemplateInstanceKinds {
+		refs, err := s.objects.ListObjects(kind, instance.Namespace, selector)
+		if err != nil {
+			return nil, errors.NewInternalError(fmt.Errorf("unable to find the %s objects of the template instance: %v", kind, err))
+		}
+		objects = append(objects, refs...)
+	}
+	return objects, nil
+}
+
+// setRequester records user as the user that created instance, whose objects are deleted as that
+// user, or removes the record if user is empty.
+func setRequester(instance *api.TemplateInstance, user string) {
+	if len(user) == 0 {
+		delete(instance.Annotations, api.TemplateInstanceRequesterAnnotation)
+		return
+	}
+	if instance.Annotations == nil {
+		instance.Annotations = map[string]string{}
+	}
+	instance.Annotations[api.TemplateInstanceRequesterAnnotation] = user
+}
@@ -0,0 +1,103 @@
+package templateinstance
+
+import (
+	"reflect"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+
+	"github.com/openshift/origin/pkg/api/latest"
+	authapi "github.com/openshift/origin/pkg/auth/api"
+	authcontext "github.com/openshift/origin/pkg/auth/context"
+	"github.com/openshift/origin/pkg/template/api"
+	"github.com/openshift/origin/pkg/template/registry/etcd"
+)
+
+// fakeObjectLister finds the services and routes named after the namespace and selector it is asked for.
+type fakeObjectLister struct {
+	kinds []string
+}
+
+func (l *fakeObjectLister) ListObjects(kind, namespace string, selector labels.Selector) ([]kapi.ObjectReference, error) {
+	l.kinds = append(l.kinds, kind)
+	if kind != "Service" && kind != "Route" {
+		return nil, nil
+	}
+	return []kapi.ObjectReference{{Kind: kind, Namespace: namespace, Name: selector.String(), UID: "1"}}, nil
+}
+
+func newStorage(t *testing.T) (*REST, *fakeObjectLister) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	registry := etcd.New(tools.EtcdHelper{Client: fakeClient, Codec: latest.Codec, ResourceVersioner: tools.RuntimeVersionAdapter{Versioner: latest.ResourceVersioner}})
+	objects := &fakeObjectLister{}
+	return NewREST(registry, objects).(*REST), objects
+}
+
+func TestCreateFindsObjects(t *testing.T) {
+	storage, objects := newStorage(t)
+	ctx := authcontext.WithUser(kapi.WithNamespace(kapi.NewContext(), "test"), &authapi.DefaultUserInfo{Name: "bob"})
+	ch, err := storage.Create(ctx, &api.TemplateInstance{
+		ObjectMeta: kapi.ObjectMeta{
+			Name:        "app-1",
+			Namespace:   "test",
+			Annotations: map[string]string{api.TemplateInstanceRequesterAnnotation: "mallory"},
+		},
+		Template: kapi.ObjectReference{Name: "app"},
+		Objects:  []kapi.ObjectReference{{Kind: "PolicyBinding", Namespace: "master", Name: "master"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := <-ch
+	instance, ok := result.Object.(*api.TemplateInstance)
+	if !ok {
+		t.Fatalf("expected a template instance, got %#v", result.Object)
+	}
+	expected := []kapi.ObjectReference{
+		{Kind: "Route", Namespace: "test", Name: "templateinstance=app-1", UID: "1"},
+		{Kind: "Service", Namespace: "test", Name: "templateinstance=app-1", UID: "1"},
+	}
+	if !reflect.DeepEqual(expected, instance.Objects) {
+		t.Errorf("expected the objects labeled with the instance to be recorded, got %#v", instance.Objects)
+	}
+	if requester := instance.Annotations[api.TemplateInstanceRequesterAnnotation]; requester != "bob" {
+		t.Errorf("expected the instance to be recorded as created by bob, got %q", requester)
+	}
+	if !reflect.DeepEqual(api.TemplateInstanceKinds, objects.kinds) {
+		t.Errorf("expected every kind an instance may record to be searched, got %v", objects.kinds)
+	}
+}
+
+func TestUpdateKeepsObjects(t *testing.T) {
+	storage, _ := newStorage(t)
+	ctx := authcontext.WithUser(kapi.WithNamespace(kapi.NewContext(), "test"), &authapi.DefaultUserInfo{Name: "bob"})
+	ch, err := storage.Create(ctx, &api.TemplateInstance{
+		ObjectMeta: kapi.ObjectMeta{Name: "app-1", Namespace: "test"},
+		Template:   kapi.ObjectReference{Name: "app"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	created := (<-ch).Object.(*api.TemplateInstance)
+
+	created.Objects = []kapi.ObjectReference{{Kind: "Service", Namespace: "test", Name: "database"}}
+	created.Annotations[api.TemplateInstanceRequesterAnnotation] = "mallory"
+	ch, err = storage.Update(ctx, created)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := <-ch
+	updated, ok := result.Object.(*api.TemplateInstance)
+	if !ok {
+		t.Fatalf("expected a template instance, got %#v", result.Object)
+	}
+	if len(updated.Objects) != 2 || updated.Objects[1].Name != "templateinstance=app-1" {
+		t.Errorf("expected the recorded objects to be kept, got %#v", updated.Objects)
+	}
+	if requester := updated.Annotations[api.TemplateInstanceRequesterAnnotation]; requester != "bob" {
+		t.Errorf("expected the recorded requester to be kept, got %q", requester)
+	}
+}
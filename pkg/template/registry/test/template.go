@@ -0,0 +1,66 @@
+package test
+
+import (
+	"sync"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	"github.com/openshift/origin/pkg/template/api"
+)
+
+// TemplateRegistry is a fake implementation of the template.Registry interface.
+type TemplateRegistry struct {
+	Err       error
+	Template  *api.Template
+	Templates *api.TemplateList
+	DeletedID string
+	sync.Mutex
+}
+
+func NewTemplateRegistry() *TemplateRegistry {
+	return &TemplateRegistry{}
+}
+
+func (r *TemplateRegistry) ListTemplates(ctx kapi.Context, selector labels.Selector) (*api.TemplateList, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.Templates, r.Err
+}
+
+func (r *TemplateRegistry) GetTemplate(ctx kapi.Context, id string) (*api.Template, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.Template, r.Err
+}
+
+func (r *TemplateRegistry) CreateTemplate(ctx kapi.Context, template *api.Template) error {
+	r.Lock()
+	defer r.Unlock()
+
+	r.Template = template
+	return r.Err
+}
+
+func (r *TemplateRegistry) UpdateTemplate(ctx kapi.Context, template *api.Template) error {
+	r.Lock()
+	defer r.Unlock()
+
+	r.Template = template
+	return r.Err
+}
+
+func (r *TemplateRegistry) DeleteTemplate(ctx kapi.Context, id string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	r.DeletedID = id
+	return r.Err
+}
+
+func (r *TemplateRegistry) WatchTemplates(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return nil, r.Err
+}
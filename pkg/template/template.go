@@ -2,6 +2,7 @@ package template
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
 	"strings"
 
@@ -10,7 +11,6 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	configapi "github.com/openshift/origin/pkg/config/api"
-	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	"github.com/openshift/origin/pkg/template/api"
 	. "github.com/openshift/origin/pkg/template/generator"
 )
@@ -37,7 +37,7 @@ func NewProcessor(generators map[string]Generator) *Processor {
 // Process transforms Template object into List object. It generates
 // Parameter values using the defined set of generators first, and then it
 // substitutes all Parameter expression occurances with their corresponding
-// values (currently in the containers' Environment variables only).
+// values in every string field of every item.
 func (p *Processor) Process(template *api.Template) (*configapi.Config, errs.ValidationErrorList) {
 	templateErrors := errs.ValidationErrorList{}
 
@@ -83,58 +83,86 @@ func GetParameterByName(t *api.Template, name string) *api.Parameter {
 	return nil
 }
 
-// SubstituteParameters loops over all Environment variables defined for
-// all ReplicationController and Pod containers and substitutes all
-// Parameter expression occurances with their corresponding values.
+// SubstituteParameters substitutes all Parameter expression occurances in
+// every string field of the item with their corresponding values. The item
+// can be of any kind known to the Scheme, including nested maps, slices and
+// pointers. Expressions that do not reference a known Parameter are left
+// untouched.
 //
 // Example of Parameter expression:
 //   - ${PARAMETER_NAME}
-//
-// TODO: Implement substitution for more types and fields.
 func (p *Processor) SubstituteParameters(params []api.Parameter, item runtime.Object) (runtime.Object, error) {
+	if _, _, err := kapi.Scheme.ObjectVersionAndKind(item); err != nil {
+		return item, err
+	}
+
 	// Make searching for given parameter name/value more effective
 	paramMap := make(map[string]string, len(params))
 	for _, param := range params {
 		paramMap[param.Name] = param.Value
 	}
 
-	switch obj := item.(type) {
-	case *kapi.ReplicationController:
-		p.substituteParametersInManifest(obj.Spec.Template.Spec.Containers, paramMap)
-		return obj, nil
-	case *kapi.Pod:
-		p.substituteParametersInManifest(obj.Spec.Containers, paramMap)
-		return obj, nil
-	case *deployapi.Deployment:
-		p.substituteParametersInManifest(obj.ControllerTemplate.Template.Spec.Containers, paramMap)
-		return obj, nil
-	case *deployapi.DeploymentConfig:
-		p.substituteParametersInManifest(obj.Template.ControllerTemplate.Template.Spec.Containers, paramMap)
-		return obj, nil
-	default:
-		return obj, nil
-	}
+	substituteParametersInValue(reflect.ValueOf(item), paramMap)
+	return item, nil
+}
 
+// substituteParametersInValue is a helper function that walks the given
+// value and substitutes all Parameter expression occurances found in
+// settable strings with their corresponding values.
+func substituteParametersInValue(v reflect.Value, paramMap map[string]string) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			substituteParametersInValue(v.Elem(), paramMap)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			// unexported fields can not be changed
+			if len(v.Type().Field(i).PkgPath) > 0 {
+				continue
+			}
+			substituteParametersInValue(v.Field(i), paramMap)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			substituteParametersInValue(v.Index(i), paramMap)
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			for _, key := range v.MapKeys() {
+				// map values are not addressable, so substitute in a copy and store it back
+				value := reflect.New(v.Type().Elem()).Elem()
+				value.Set(v.MapIndex(key))
+				substituteParametersInValue(value, paramMap)
+				v.SetMapIndex(key, value)
+			}
+			return
+		}
+		for _, key := range v.MapKeys() {
+			value := v.MapIndex(key).String()
+			if substituted := substituteParametersInString(value, paramMap); substituted != value {
+				v.SetMapIndex(key, reflect.ValueOf(substituted).Convert(v.Type().Elem()))
+			}
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(substituteParametersInString(v.String(), paramMap))
+		}
+	}
 }
 
-// substituteParametersInManifest is a helper function that iterates
-// over the given manifest and substitutes all Parameter expression
-// occurances with their corresponding values.
-func (p *Processor) substituteParametersInManifest(containers []kapi.Container, paramMap map[string]string) {
-	for i := range containers {
-		for e := range containers[i].Env {
-			envValue := &containers[i].Env[e].Value
-			// Match all parameter expressions found in the given env var
-			for _, match := range parameterExp.FindAllStringSubmatch(*envValue, -1) {
-				// Substitute expression with its value, if corresponding parameter found
-				if len(match) > 1 {
-					if paramValue, found := paramMap[match[1]]; found {
-						*envValue = strings.Replace(*envValue, match[0], paramValue, 1)
-					}
-				}
+// substituteParametersInString substitutes all Parameter expression
+// occurances in s with their corresponding values.
+func substituteParametersInString(s string, paramMap map[string]string) string {
+	for _, match := range parameterExp.FindAllStringSubmatch(s, -1) {
+		// Substitute expression with its value, if corresponding parameter found
+		if len(match) > 1 {
+			if paramValue, found := paramMap[match[1]]; found {
+				s = strings.Replace(s, match[0], paramValue, 1)
 			}
 		}
 	}
+	return s
 }
 
 // GenerateParameterValues generates Value for each Parameter of the given
@@ -7,9 +7,12 @@ import (
 	"math/rand"
 	"testing"
 
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	_ "github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/errors"
 	"github.com/openshift/origin/pkg/api/latest"
+	routeapi "github.com/openshift/origin/pkg/route/api"
 	"github.com/openshift/origin/pkg/template/api"
 	"github.com/openshift/origin/pkg/template/generator"
 )
@@ -131,3 +134,59 @@ func ExampleProcessTemplateParameters() {
 	//<nil>
 	//{"kind":"Config","apiVersion":"v1beta1","metadata":{"creationTimestamp":null},"items":[{"kind":"Route","apiVersion":"v1beta1","metadata":{"creationTimestamp":null},"host":"guestbook.example.com","serviceName":"frontend-service"},{"kind":"Service","id":"frontend-service","creationTimestamp":null,"apiVersion":"v1beta1","port":5432,"selector":{"name":"frontend-service"},"containerPort":0},{"kind":"Service","id":"redis-master","creationTimestamp":null,"apiVersion":"v1beta1","port":10000,"selector":{"name":"redis-master"},"containerPort":0},{"kind":"Service","id":"redis-slave","creationTimestamp":null,"apiVersion":"v1beta1","port":10001,"selector":{"name":"redis-slave"},"containerPort":0},{"kind":"Pod","id":"redis-master","creationTimestamp":null,"apiVersion":"v1beta1","labels":{"name":"redis-master"},"desiredState":{"manifest":{"version":"v1beta2","id":"","volumes":null,"containers":[{"name":"master","image":"dockerfile/redis","ports":[{"containerPort":6379}],"env":[{"name":"REDIS_PASSWORD","key":"REDIS_PASSWORD","value":"P8vxbV4C"}],"resources":{},"imagePullPolicy":"","capabilities":{}}],"restartPolicy":{}}},"currentState":{"manifest":{"version":"","id":"","volumes":null,"containers":null,"restartPolicy":{}}}},{"kind":"ReplicationController","id":"guestbook","creationTimestamp":null,"apiVersion":"v1beta1","desiredState":{"replicas":3,"replicaSelector":{"name":"frontend-service"},"podTemplate":{"desiredState":{"manifest":{"version":"v1beta2","id":"","volumes":null,"containers":[{"name":"php-redis","image":"brendanburns/php-redis","ports":[{"hostPort":8000,"containerPort":80}],"env":[{"name":"ADMIN_USERNAME","key":"ADMIN_USERNAME","value":"adminQ3H"},{"name":"ADMIN_PASSWORD","key":"ADMIN_PASSWORD","value":"dwNJiJwW"},{"name":"REDIS_PASSWORD","key":"REDIS_PASSWORD","value":"P8vxbV4C"}],"resources":{},"imagePullPolicy":"","capabilities":{}}],"restartPolicy":{}}},"labels":{"name":"frontend-service"}}},"currentState":{"replicas":0,"podTemplate":{"desiredState":{"manifest":{"version":"","id":"","volumes":null,"containers":null,"restartPolicy":{}}}}}},{"kind":"ReplicationController","id":"redis-slave","creationTimestamp":null,"apiVersion":"v1beta1","desiredState":{"replicas":2,"replicaSelector":{"name":"redis-slave"},"podTemplate":{"desiredState":{"manifest":{"version":"v1beta2","id":"","volumes":null,"containers":[{"name":"slave","image":"brendanburns/redis-slave","ports":[{"hostPort":6380,"containerPort":6379}],"env":[{"name":"REDIS_PASSWORD","key":"REDIS_PASSWORD","value":"P8vxbV4C"}],"resources":{},"imagePullPolicy":"","capabilities":{}}],"restartPolicy":{}}},"labels":{"name":"redis-slave"}}},"currentState":{"replicas":0,"podTemplate":{"desiredState":{"manifest":{"version":"","id":"","volumes":null,"containers":null,"restartPolicy":{}}}}}}]}
 }
+
+func TestSubstituteParametersInAnyField(t *testing.T) {
+	processor := NewProcessor(map[string]generator.Generator{})
+	params := []api.Parameter{
+		makeParameter("NAME", "frontend", ""),
+		makeParameter("IMAGE", "openshift/ruby", ""),
+		makeParameter("HOST", "www.example.com", ""),
+	}
+
+	tests := []struct {
+		item     runtime.Object
+		expected runtime.Object
+	}{
+		{
+			item: &kapi.Service{
+				ObjectMeta: kapi.ObjectMeta{Name: "${NAME}", Labels: map[string]string{"name": "${NAME}"}},
+				Spec:       kapi.ServiceSpec{Port: 80, Selector: map[string]string{"name": "${NAME}-pod"}},
+			},
+			expected: &kapi.Service{
+				ObjectMeta: kapi.ObjectMeta{Name: "frontend", Labels: map[string]string{"name": "frontend"}},
+				Spec:       kapi.ServiceSpec{Port: 80, Selector: map[string]string{"name": "frontend-pod"}},
+			},
+		},
+		{
+			item: &kapi.ReplicationController{
+				Spec: kapi.ReplicationControllerSpec{
+					Template: &kapi.PodTemplateSpec{
+						Spec: kapi.PodSpec{Containers: []kapi.Container{{Name: "${NAME}", Image: "${IMAGE}:latest"}}},
+					},
+				},
+			},
+			expected: &kapi.ReplicationController{
+				Spec: kapi.ReplicationControllerSpec{
+					Template: &kapi.PodTemplateSpec{
+						Spec: kapi.PodSpec{Containers: []kapi.Container{{Name: "frontend", Image: "openshift/ruby:latest"}}},
+					},
+				},
+			},
+		},
+		{
+			item:     &routeapi.Route{Host: "${HOST}", ServiceName: "${NAME}", Path: "${UNKNOWN}"},
+			expected: &routeapi.Route{Host: "www.example.com", ServiceName: "frontend", Path: "${UNKNOWN}"},
+		},
+	}
+
+	for i, test := range tests {
+		actual, err := processor.SubstituteParameters(params, test.item)
+		if err != nil {
+			t.Errorf("test[%v]: Unexpected error: %v", i, err)
+			continue
+		}
+		if !kapi.Semantic.DeepEqual(actual, test.expected) {
+			t.Errorf("test[%v]: Expected %#v, got %#v", i, test.expected, actual)
+		}
+	}
+}
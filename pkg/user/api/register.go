@@ -8,6 +8,7 @@ func init() {
 	api.Scheme.AddKnownTypes("",
 		&User{},
 		&Identity{},
+		&IdentityList{},
 		&UserIdentityMapping{},
 	)
 }
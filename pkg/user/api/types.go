@@ -2,6 +2,7 @@ package api
 
 import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
 // Auth system gets identity name and provider
@@ -12,6 +13,16 @@ type User struct {
 	kapi.ObjectMeta `json:"metadata,omitempty"`
 
 	FullName string `json:"fullName,omitempty"`
+
+	// Identities are the names of the identities mapped to this user
+	Identities []string `json:"identities,omitempty"`
+
+	// Disabled users cannot authenticate, but keep their identities and role bindings
+	Disabled bool `json:"disabled,omitempty"`
+
+	// LastLoginTimestamp is when the user last logged in with one of its identities, updated at most once a
+	// minute
+	LastLoginTimestamp util.Time `json:"lastLoginTimestamp,omitempty"`
 }
 
 type UserList struct {
@@ -32,6 +43,15 @@ type Identity struct {
 	UserName string `json:"userName"`
 
 	Extra map[string]string `json:"extra,omitempty"`
+
+	// User is the user this identity is mapped to
+	User kapi.ObjectReference `json:"user,omitempty"`
+}
+
+type IdentityList struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
+	Items         []Identity `json:"items"`
 }
 
 type UserIdentityMapping struct {
@@ -45,4 +65,5 @@ type UserIdentityMapping struct {
 func (*User) IsAnAPIObject()                {}
 func (*UserList) IsAnAPIObject()            {}
 func (*Identity) IsAnAPIObject()            {}
+func (*IdentityList) IsAnAPIObject()        {}
 func (*UserIdentityMapping) IsAnAPIObject() {}
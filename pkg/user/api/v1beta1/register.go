@@ -8,6 +8,7 @@ func init() {
 	api.Scheme.AddKnownTypes("v1beta1",
 		&User{},
 		&Identity{},
+		&IdentityList{},
 		&UserIdentityMapping{},
 	)
 }
@@ -0,0 +1,14 @@
+package v1beta2
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+func init() {
+	api.Scheme.AddKnownTypes("v1beta2",
+		&User{},
+		&Identity{},
+		&IdentityList{},
+		&UserIdentityMapping{},
+	)
+}
@@ -0,0 +1,69 @@
+package v1beta2
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api/v1beta3"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// Auth system gets identity name and provider
+// POST to UserIdentityMapping, get back error or a filled out UserIdentityMapping object
+
+type User struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	FullName string `json:"fullName,omitempty"`
+
+	// Identities are the names of the identities mapped to this user
+	Identities []string `json:"identities,omitempty"`
+
+	// Disabled users cannot authenticate, but keep their identities and role bindings
+	Disabled bool `json:"disabled,omitempty"`
+
+	// LastLoginTimestamp is when the user last logged in with one of its identities, updated at most once a
+	// minute
+	LastLoginTimestamp util.Time `json:"lastLoginTimestamp,omitempty"`
+}
+
+type UserList struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+	Items           []User `json:"items"`
+}
+
+type Identity struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	// Provider is the source of identity information - if empty, the default provider
+	// is assumed.
+	Provider string `json:"provider" description:"Provider is the source of identity information - if empty, the default provider is assumed."`
+
+	// UserName uniquely represents this identity in the scope of the identity provider
+	UserName string `json:"userName" description:"UserName uniquely represents this identity in the scope of the identity provider"`
+
+	Extra map[string]string `json:"extra,omitempty"`
+
+	// User is the user this identity is mapped to
+	User kapi.ObjectReference `json:"user,omitempty" description:"User is the user this identity is mapped to"`
+}
+
+type IdentityList struct {
+	kapi.TypeMeta `json:",inline"`
+	kapi.ListMeta `json:"metadata,omitempty"`
+	Items         []Identity `json:"items"`
+}
+
+type UserIdentityMapping struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	Identity Identity `json:"identity,omitempty"`
+	User     User     `json:"user,omitempty"`
+}
+
+func (*User) IsAnAPIObject()                {}
+func (*UserList) IsAnAPIObject()            {}
+func (*Identity) IsAnAPIObject()            {}
+func (*IdentityList) IsAnAPIObject()        {}
+func (*UserIdentityMapping) IsAnAPIObject() {}
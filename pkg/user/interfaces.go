@@ -1,9 +1,42 @@
 package user
 
 import (
+	"strings"
+
 	"github.com/openshift/origin/pkg/user/api"
 )
 
 type Initializer interface {
 	InitializeUser(identity *api.Identity, user *api.User) error
 }
+
+// MappingMethod determines how an identity that has not been seen before is mapped to a user.  The
+// identity provider's user name is the preferred user name in every method.
+type MappingMethod string
+
+const (
+	// MappingMethodClaim maps the identity to a new user with the preferred user name, and fails if a user
+	// with that name already exists.
+	MappingMethodClaim MappingMethod = "claim"
+	// MappingMethodLookup only maps identities that are already mapped to users, so that an administrator
+	// must create the mapping before the identity can log in.
+	MappingMethodLookup MappingMethod = "lookup"
+	// MappingMethodAdd maps the identity to the user with the preferred user name, adding it to the
+	// identities of that user if one already exists.
+	MappingMethodAdd MappingMethod = "add"
+	// MappingMethodGenerate maps the identity to a new user, appending a number to the preferred user name
+	// until it is unique.
+	MappingMethodGenerate MappingMethod = "generate"
+)
+
+// reservedUserNames are the users of the infrastructure components the bootstrap policy grants access to.
+var reservedUserNames = map[string]bool{
+	"openshift-client": true,
+	"kube-client":      true,
+}
+
+// IsReservedUserName returns true if name is reserved for the system or for infrastructure components, so that
+// no identity from an identity provider may be mapped to a user of that name.
+func IsReservedUserName(name string) bool {
+	return strings.HasPrefix(name, "system:") || reservedUserNames[name]
+}
@@ -3,42 +3,197 @@ package etcd
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	etcderrs "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors/etcd"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
 
 	"github.com/openshift/origin/pkg/user"
 	"github.com/openshift/origin/pkg/user/api"
 )
 
+const (
+	// UserPath is the path users are stored at in etcd
+	UserPath = "/users"
+	// UserIdentityMappingPath is the path the mappings of identities to users are stored at in etcd
+	UserIdentityMappingPath = "/userIdentityMappings"
+)
+
 // Etcd implements UserIdentityMapping backed by etcd.
 type Etcd struct {
 	tools.EtcdHelper
 	initializer user.Initializer
+	method      user.MappingMethod
 }
 
-// New returns a new Etcd.
-func New(helper tools.EtcdHelper, initializer user.Initializer) *Etcd {
+// New returns a new Etcd that maps new identities to users with method.
+func New(helper tools.EtcdHelper, initializer user.Initializer, method user.MappingMethod) *Etcd {
 	return &Etcd{
 		EtcdHelper:  helper,
 		initializer: initializer,
+		method:      method,
 	}
 }
 
-var errExists = errors.New("the mapping already exists")
+// maxGeneratedUserNames bounds the user names tried for an identity by MappingMethodGenerate.
+const maxGeneratedUserNames = 100
+
+var errUserNameTaken = errors.New("the user name is taken")
+
+var errUserNotStored = errors.New("the user is only stored in the mapping of its identity")
+
+var errUnchanged = errors.New("the user is unchanged")
 
 func makeUserKey(id string) string {
-	return "/userIdentityMappings/" + id
+	return UserIdentityMappingPath + "/" + id
+}
+
+func makeUserNameKey(name string) string {
+	return UserPath + "/" + name
 }
 
-func (r *Etcd) GetUser(name string) (user *api.User, err error) {
+func (r *Etcd) GetUser(name string) (*api.User, error) {
+	found := &api.User{}
+	if err := r.ExtractObj(makeUserNameKey(name), found, true); err != nil {
+		return nil, etcderrs.InterpretGetError(err, "User", name)
+	}
+	if len(found.Name) > 0 {
+		return found, nil
+	}
+
+	// users created before identities were mapped by method are only stored in the mapping of their identity
 	mapping := &api.UserIdentityMapping{}
-	err = r.ExtractObj(makeUserKey(name), mapping, false)
+	err := r.ExtractObj(makeUserKey(name), mapping, false)
 	err = etcderrs.InterpretGetError(err, "User", name)
-	user = &mapping.User
-	return
+	return &mapping.User, err
+}
+
+// UpdateUser sets the full name of the user and whether it is disabled.  The identities of a user only change
+// when they are mapped to it.
+func (r *Etcd) UpdateUser(user *api.User) (*api.User, error) {
+	return r.updateUser(user.Name, func(existing *api.User) bool {
+		existing.FullName = user.FullName
+		existing.Disabled = user.Disabled
+		return true
+	})
+}
+
+// RecordLogin sets the last login time of the user to at, unless a later login is already recorded.
+func (r *Etcd) RecordLogin(name string, at util.Time) error {
+	_, err := r.updateUser(name, func(existing *api.User) bool {
+		if !existing.LastLoginTimestamp.Before(at.Time) {
+			return false
+		}
+		existing.LastLoginTimestamp = at
+		return true
+	})
+	return err
+}
+
+// updateUser applies update to the stored user, and writes the user if update returns true.
+func (r *Etcd) updateUser(name string, update func(*api.User) bool) (*api.User, error) {
+	current, err := r.GetUser(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated *api.User
+	err = r.AtomicUpdate(makeUserNameKey(name), &api.User{}, func(in runtime.Object) (runtime.Object, error) {
+		existing := *in.(*api.User)
+		if len(existing.Name) == 0 {
+			// store users that were only stored in the mapping of their identity on their own
+			existing = *current
+			existing.ResourceVersion = ""
+			existing.Identities = []string{current.Name}
+		}
+		updated = &existing
+		if !update(&existing) {
+			return in, errUnchanged
+		}
+		return &existing, nil
+	})
+	if err != nil && err != errUnchanged {
+		return nil, etcderrs.InterpretUpdateError(err, "User", name)
+	}
+	return updated, nil
+}
+
+// DeleteUser deletes the user and the mappings of its identities.
+func (r *Etcd) DeleteUser(name string) error {
+	user, err := r.GetUser(name)
+	if err != nil {
+		return err
+	}
+	identities := user.Identities
+	if len(identities) == 0 {
+		identities = []string{name}
+	}
+	for _, identity := range identities {
+		if err := r.Delete(makeUserKey(identity), false); err != nil && !tools.IsEtcdNotFound(err) {
+			return etcderrs.InterpretDeleteError(err, "UserIdentityMapping", identity)
+		}
+	}
+	if err := r.Delete(makeUserNameKey(name), false); err != nil && !tools.IsEtcdNotFound(err) {
+		return etcderrs.InterpretDeleteError(err, "User", name)
+	}
+	return nil
+}
+
+// identityOf returns the identity of mapping with a reference to its user.
+func identityOf(mapping *api.UserIdentityMapping) *api.Identity {
+	identity := mapping.Identity
+	identity.User = kapi.ObjectReference{Kind: "User", Name: mapping.User.Name, UID: mapping.User.UID}
+	return &identity
+}
+
+// ListIdentities returns the identities that match selector, with references to the users they are mapped to.
+func (r *Etcd) ListIdentities(selector labels.Selector) (*api.IdentityList, error) {
+	mappings := []api.UserIdentityMapping{}
+	var resourceVersion uint64
+	if err := r.ExtractList(UserIdentityMappingPath, &mappings, &resourceVersion); err != nil {
+		return nil, err
+	}
+	list := &api.IdentityList{}
+	list.ResourceVersion = strconv.FormatUint(resourceVersion, 10)
+	for i := range mappings {
+		identity := identityOf(&mappings[i])
+		if selector.Matches(labels.Set(identity.Labels)) {
+			list.Items = append(list.Items, *identity)
+		}
+	}
+	return list, nil
+}
+
+// GetIdentity returns the identity with a reference to the user it is mapped to.
+func (r *Etcd) GetIdentity(name string) (*api.Identity, error) {
+	mapping := &api.UserIdentityMapping{}
+	if err := r.ExtractObj(makeUserKey(name), mapping, false); err != nil {
+		return nil, etcderrs.InterpretGetError(err, "Identity", name)
+	}
+	return identityOf(mapping), nil
+}
+
+// DeleteIdentity deletes the mapping of the identity and removes it from the identities of its user.  The
+// user is kept, so the identity is mapped anew the next time it logs in.
+func (r *Etcd) DeleteIdentity(name string) error {
+	mapping := &api.UserIdentityMapping{}
+	if err := r.ExtractObj(makeUserKey(name), mapping, false); err != nil {
+		return etcderrs.InterpretDeleteError(err, "Identity", name)
+	}
+
+	err := r.removeIdentity(mapping.User.Name, name)
+	if err != nil && err != errUserNotStored {
+		return etcderrs.InterpretDeleteError(err, "Identity", name)
+	}
+
+	return etcderrs.InterpretDeleteError(r.Delete(makeUserKey(name), false), "Identity", name)
 }
 
 func (r *Etcd) GetUserIdentityMapping(name string) (mapping *api.UserIdentityMapping, err error) {
@@ -48,68 +203,178 @@ func (r *Etcd) GetUserIdentityMapping(name string) (mapping *api.UserIdentityMap
 	return
 }
 
-// CreateOrUpdateUserIdentityMapping implements useridentitymapping.Registry
+// CreateOrUpdateUserIdentityMapping implements useridentitymapping.Registry.  Identities that are already
+// mapped keep their user, which is returned as it is currently stored.  A new identity is mapped to the user named in the mapping, if there is one, and
+// otherwise to a user chosen by the mapping method of the registry.
 func (r *Etcd) CreateOrUpdateUserIdentityMapping(mapping *api.UserIdentityMapping) (*api.UserIdentityMapping, bool, error) {
 	// Create Identity.Name by combining Provider and UserName
 	name := fmt.Sprintf("%s:%s", mapping.Identity.Provider, mapping.Identity.UserName)
 	key := makeUserKey(name)
 
-	// track the object we set into etcd to return
-	var found *api.UserIdentityMapping
-	var created bool
+	existing := &api.UserIdentityMapping{}
+	if err := r.ExtractObj(key, existing, true); err != nil {
+		return nil, false, etcderrs.InterpretGetError(err, "UserIdentityMapping", name)
+	}
+	if len(existing.Name) > 0 {
+		if existing.Identity.Name != name {
+			return nil, false, fmt.Errorf("the provided identity does not match the existing mapping %s", existing.Identity.Name)
+		}
+		// the user may have changed since the identity was mapped to it
+		user, err := r.GetUser(existing.User.Name)
+		if err != nil {
+			return nil, false, err
+		}
+		existing.User = *user
 
-	err := r.AtomicUpdate(key, &api.UserIdentityMapping{}, func(in runtime.Object) (runtime.Object, error) {
-		existing := *in.(*api.UserIdentityMapping)
+		// TODO: should update identity based on new info as well.
+		return existing, false, nil
+	}
+	method, preferred := r.method, mapping.Identity.UserName
+	if len(mapping.User.Name) > 0 {
+		method, preferred = user.MappingMethodAdd, mapping.User.Name
+	}
+	if method == user.MappingMethodLookup {
+		return nil, false, kerrors.NewNotFound("UserIdentityMapping", name)
+	}
+	if len(preferred) == 0 {
+		preferred = name
+	}
+	if len(mapping.User.Name) == 0 && user.IsReservedUserName(preferred) {
+		return nil, false, kerrors.NewForbidden("UserIdentityMapping", name, fmt.Errorf("the user name %s is reserved", preferred))
+	}
 
-		// did not previously exist
-		if existing.Name == "" {
-			now := util.Now()
+	// TODO: move these initializations the rest layer once we stop using the registry directly
+	now := util.Now()
+	created := &api.UserIdentityMapping{}
+	created.Name = name
+	created.UID = util.NewUUID()
+	created.CreationTimestamp = now
+	created.Identity = mapping.Identity
+	created.Identity.Name = name
+	created.Identity.UID = util.NewUUID()
+	created.Identity.CreationTimestamp = now
 
-			// TODO: move these initializations the rest layer once we stop using the registry directly
-			existing.Name = name
-			existing.UID = util.NewUUID()
-			existing.CreationTimestamp = now
-			existing.Identity = mapping.Identity
+	mapped, newUser, err := r.mapUser(&created.Identity, preferred, method)
+	if err != nil {
+		return nil, false, err
+	}
+	created.User = *mapped
 
-			identityuid := util.NewUUID()
-			existing.Identity.Name = name
-			existing.Identity.UID = identityuid
-			existing.Identity.CreationTimestamp = now
+	// the mapping guards the identity, if it was mapped concurrently the user changes made for it are undone
+	if err := r.CreateObj(key, created, 0); err != nil {
+		err = etcderrs.InterpretCreateError(err, "UserIdentityMapping", name)
+		exists := kerrors.IsAlreadyExists(err)
+		if winner, getErr := r.GetUserIdentityMapping(name); !exists || getErr != nil || winner.User.Name != mapped.Name {
+			if err := r.unmapUser(&created.Identity, mapped.Name, newUser); err != nil {
+				glog.Errorf("Unable to remove the identity %s from the user %s: %v", name, mapped.Name, err)
+			}
+		}
+		if exists {
+			return r.CreateOrUpdateUserIdentityMapping(mapping)
+		}
+		return nil, false, err
+	}
+	return created, true, nil
+}
 
-			useruid := util.NewUUID()
-			existing.User.Name = name
-			existing.User.UID = useruid
-			existing.User.CreationTimestamp = now
+// unmapUser reverts the changes mapUser made to the user named name for the identity.  A user created for the
+// identity is deleted.
+func (r *Etcd) unmapUser(identity *api.Identity, name string, created bool) error {
+	if created {
+		return r.Delete(makeUserNameKey(name), false)
+	}
+	err := r.removeIdentity(name, identity.Name)
+	if err == errUserNotStored {
+		return nil
+	}
+	return err
+}
 
-			if err := r.initializer.InitializeUser(&existing.Identity, &existing.User); err != nil {
-				return in, err
+// removeIdentity removes the identity from the identities of the user named name.  It returns
+// errUserNotStored if the user is only stored in the mapping of its identity.
+func (r *Etcd) removeIdentity(name, identity string) error {
+	return r.AtomicUpdate(makeUserNameKey(name), &api.User{}, func(in runtime.Object) (runtime.Object, error) {
+		existing := *in.(*api.User)
+		if len(existing.Name) == 0 {
+			// users that are only stored in the mapping of their identity are deleted with it
+			return in, errUserNotStored
+		}
+		identities := []string{}
+		for _, mapped := range existing.Identities {
+			if mapped != identity {
+				identities = append(identities, mapped)
 			}
-
-			// set these again to prevent bad initialization from messing up data
-			existing.Identity.Name = name
-			existing.Identity.UID = identityuid
-			existing.Identity.CreationTimestamp = now
-			existing.User.Name = name
-			existing.User.UID = useruid
-			existing.User.CreationTimestamp = now
-
-			found = &existing
-			created = true
-			return &existing, nil
 		}
+		existing.Identities = identities
+		return &existing, nil
+	})
+}
 
-		if existing.User.Name != name {
-			return in, fmt.Errorf("the provided user name does not match the existing mapping %s", existing.User.Name)
+// mapUser returns the user the new identity is mapped to by method, creating the user or adding the identity
+// to it, and whether the user was created.
+func (r *Etcd) mapUser(identity *api.Identity, preferred string, method user.MappingMethod) (*api.User, bool, error) {
+	for i := 1; i <= maxGeneratedUserNames; i++ {
+		name := preferred
+		if i > 1 {
+			name = fmt.Sprintf("%s%d", preferred, i)
 		}
-		found = &existing
 
-		// TODO: should update identity based on new info as well.
-		return in, errExists
-	})
+		var found *api.User
+		created := false
+		err := r.AtomicUpdate(makeUserNameKey(name), &api.User{}, func(in runtime.Object) (runtime.Object, error) {
+			existing := *in.(*api.User)
 
-	if err != nil && err != errExists {
-		err = etcderrs.InterpretCreateError(err, "UserIdentityMapping", name)
-		return nil, false, err
+			// the identity was mapped to the user concurrently
+			for _, mapped := range existing.Identities {
+				if mapped == identity.Name {
+					found = &existing
+					return in, errUnchanged
+				}
+			}
+
+			// did not previously exist
+			if len(existing.Name) == 0 {
+				now := util.Now()
+				uid := util.NewUUID()
+				existing.Name = name
+				existing.UID = uid
+				existing.CreationTimestamp = now
+
+				if err := r.initializer.InitializeUser(identity, &existing); err != nil {
+					return in, err
+				}
+
+				// set these again to prevent bad initialization from messing up data
+				existing.Name = name
+				existing.UID = uid
+				existing.CreationTimestamp = now
+				existing.Identities = []string{identity.Name}
+
+				found, created = &existing, true
+				return &existing, nil
+			}
+
+			switch method {
+			case user.MappingMethodAdd:
+				existing.Identities = append(existing.Identities, identity.Name)
+				found = &existing
+				return &existing, nil
+			case user.MappingMethodGenerate:
+				return in, errUserNameTaken
+			default:
+				return in, kerrors.NewConflict("User", name, fmt.Errorf("the user is already mapped to the identity %s", strings.Join(existing.Identities, ", ")))
+			}
+		})
+		if err == errUserNameTaken {
+			continue
+		}
+		if err == errUnchanged {
+			return found, false, nil
+		}
+		if err != nil {
+			return nil, false, etcderrs.InterpretCreateError(err, "User", name)
+		}
+		return found, created, nil
 	}
-	return found, created, nil
+	return nil, false, fmt.Errorf("unable to generate a unique user name for the identity %s", identity.Name)
 }
@@ -2,12 +2,16 @@ package etcd
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
 	"github.com/openshift/origin/pkg/api/latest"
 
@@ -17,7 +21,11 @@ import (
 )
 
 func NewTestEtcd(client tools.EtcdClient) *Etcd {
-	return New(tools.EtcdHelper{client, latest.Codec, tools.RuntimeVersionAdapter{latest.ResourceVersioner}}, user.NewDefaultUserInitStrategy())
+	return NewTestEtcdWithMethod(client, user.MappingMethodClaim)
+}
+
+func NewTestEtcdWithMethod(client tools.EtcdClient, method user.MappingMethod) *Etcd {
+	return New(tools.EtcdHelper{client, latest.Codec, tools.RuntimeVersionAdapter{latest.ResourceVersioner}}, user.NewDefaultUserInitStrategy(), method)
 }
 
 // This copy and paste is not pure ignorance.  This is that we can be sure that the key is getting made as we
@@ -42,6 +50,7 @@ func TestEtcdGetUser(t *testing.T) {
 	}
 	key := makeTestUserIdentityMapping(expectedResultingMapping.Identity.Provider, expectedResultingMapping.Identity.UserName)
 	fakeClient.Set(key, runtime.EncodeOrDie(latest.Codec, expectedResultingMapping), 0)
+	fakeClient.Data["/users/victor:tango"] = tools.EtcdResponseWithError{R: &etcd.Response{}, E: tools.EtcdErrorNotFound}
 	registry := NewTestEtcd(fakeClient)
 
 	user, err := registry.GetUser("victor:tango")
@@ -82,6 +91,7 @@ func TestEtcdCreateUserIdentityMapping(t *testing.T) {
 		},
 		E: tools.EtcdErrorNotFound,
 	}
+	fakeClient.Data["/users/sierra:"] = tools.EtcdResponseWithError{R: &etcd.Response{}, E: tools.EtcdErrorNotFound}
 	registry := NewTestEtcd(fakeClient)
 	persistedUserIdentityMapping, created, err := registry.CreateOrUpdateUserIdentityMapping(testMapping)
 	if err != nil {
@@ -98,7 +108,7 @@ func TestEtcdCreateUserIdentityMapping(t *testing.T) {
 	}
 }
 
-func TestEtcdUpdateUserIdentityMappingWithConflictingUser(t *testing.T) {
+func TestEtcdUpdateUserIdentityMappingWithConflictingIdentity(t *testing.T) {
 	fakeClient := tools.NewFakeEtcdClient(t)
 	startingMapping := &userapi.UserIdentityMapping{
 		ObjectMeta: kapi.ObjectMeta{Name: "whiskey"},
@@ -130,7 +140,7 @@ func TestEtcdUpdateUserIdentityMappingWithConflictingUser(t *testing.T) {
 	if err == nil {
 		t.Errorf("Expected an error, but we didn't get one")
 	} else {
-		const expectedError = "the provided user name does not match the existing mapping"
+		const expectedError = "the provided identity does not match the existing mapping"
 		if !strings.Contains(err.Error(), expectedError) {
 			t.Errorf("Expected error %v, but we got %v", expectedError, expectedError)
 		}
@@ -143,6 +153,273 @@ func TestEtcdUpdateUserIdentityMappingWithConflictingUser(t *testing.T) {
 	}
 }
 
+func TestEtcdCreateUserIdentityMappingMethods(t *testing.T) {
+	testCases := map[string]struct {
+		method       user.MappingMethod
+		identityName string
+		userName     string
+		expectedUser string
+		expectedErr  bool
+	}{
+		"claim new user":            {user.MappingMethodClaim, "charlie", "", "charlie", false},
+		"claim existing user":       {user.MappingMethodClaim, "alice", "", "", true},
+		"lookup unmapped identity":  {user.MappingMethodLookup, "charlie", "", "", true},
+		"add to existing user":      {user.MappingMethodAdd, "alice", "", "alice", false},
+		"generate unique user name": {user.MappingMethodGenerate, "alice", "", "alice3", false},
+		"explicit user":             {user.MappingMethodLookup, "charlie", "alice", "alice", false},
+		"reserved system user":      {user.MappingMethodClaim, "system:admin", "", "", true},
+		"reserved component user":   {user.MappingMethodGenerate, "kube-client", "", "", true},
+	}
+
+	for name, tc := range testCases {
+		fakeClient := tools.NewFakeEtcdClient(t)
+		fakeClient.TestIndex = true
+		for _, existing := range []string{"alice", "alice2"} {
+			fakeClient.Set(makeUserNameKey(existing), runtime.EncodeOrDie(latest.Codec, &userapi.User{
+				ObjectMeta: kapi.ObjectMeta{Name: existing},
+				Identities: []string{"delta:" + existing},
+			}), 0)
+		}
+		for _, missing := range []string{makeUserKey("echo:alice"), makeUserKey("echo:charlie"), makeUserKey("echo:system:admin"), makeUserKey("echo:kube-client"), makeUserNameKey("alice3"), makeUserNameKey("charlie")} {
+			fakeClient.Data[missing] = tools.EtcdResponseWithError{R: &etcd.Response{}, E: tools.EtcdErrorNotFound}
+		}
+		registry := NewTestEtcdWithMethod(fakeClient, tc.method)
+
+		mapping, created, err := registry.CreateOrUpdateUserIdentityMapping(&userapi.UserIdentityMapping{
+			Identity: userapi.Identity{Provider: "echo", UserName: tc.identityName},
+			User:     userapi.User{ObjectMeta: kapi.ObjectMeta{Name: tc.userName}},
+		})
+		if tc.expectedErr {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %#v", name, mapping)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if !created || mapping.Name != "echo:"+tc.identityName || mapping.User.Name != tc.expectedUser {
+			t.Errorf("%s: unexpected mapping: %#v", name, mapping)
+		}
+
+		persisted, err := registry.GetUser(tc.expectedUser)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if identities := strings.Join(persisted.Identities, ","); !strings.HasSuffix(identities, "echo:"+tc.identityName) {
+			t.Errorf("%s: expected the identity to be added to the user, got %s", name, identities)
+		}
+
+		if again, created, err := registry.CreateOrUpdateUserIdentityMapping(&userapi.UserIdentityMapping{
+			Identity: userapi.Identity{Provider: "echo", UserName: tc.identityName},
+		}); err != nil || created || again.User.Name != tc.expectedUser {
+			t.Errorf("%s: expected the existing mapping to be returned, got %#v %t %v", name, again, created, err)
+		}
+	}
+}
+
+// racingClient maps an identity to another user just before the mapping of the identity is created.
+type racingClient struct {
+	*tools.FakeEtcdClient
+	key    string
+	winner string
+}
+
+func (c *racingClient) Create(key, value string, ttl uint64) (*etcd.Response, error) {
+	if key == c.key {
+		c.FakeEtcdClient.Set(key, c.winner, 0)
+	}
+	return c.FakeEtcdClient.Create(key, value, ttl)
+}
+
+func TestEtcdCreateUserIdentityMappingConcurrently(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	fakeClient.Set(makeUserNameKey("alice"), runtime.EncodeOrDie(latest.Codec, &userapi.User{
+		ObjectMeta: kapi.ObjectMeta{Name: "alice"},
+		Identities: []string{"echo:alice"},
+	}), 0)
+	for _, missing := range []string{makeUserKey("echo:alice"), makeUserNameKey("alice2"), makeUserKey("alice2")} {
+		fakeClient.Data[missing] = tools.EtcdResponseWithError{R: &etcd.Response{}, E: tools.EtcdErrorNotFound}
+	}
+	client := &racingClient{
+		FakeEtcdClient: fakeClient,
+		key:            makeUserKey("echo:alice"),
+		winner: runtime.EncodeOrDie(latest.Codec, &userapi.UserIdentityMapping{
+			ObjectMeta: kapi.ObjectMeta{Name: "echo:alice"},
+			Identity:   userapi.Identity{ObjectMeta: kapi.ObjectMeta{Name: "echo:alice"}, Provider: "echo", UserName: "alice"},
+			User:       userapi.User{ObjectMeta: kapi.ObjectMeta{Name: "alice"}},
+		}),
+	}
+	// the identity is not yet mapped, but its user was created by a concurrent login
+	registry := NewTestEtcdWithMethod(client, user.MappingMethodGenerate)
+
+	mapping, created, err := registry.CreateOrUpdateUserIdentityMapping(&userapi.UserIdentityMapping{
+		Identity: userapi.Identity{Provider: "echo", UserName: "alice"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created || mapping.User.Name != "alice" {
+		t.Errorf("expected the concurrently created mapping, got %t %#v", created, mapping)
+	}
+	if _, err := registry.GetUser("alice2"); err == nil {
+		t.Errorf("expected no user to be generated for the identity")
+	}
+}
+
+func TestEtcdCreateUserIdentityMappingConcurrentlyRollsBack(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	for _, missing := range []string{makeUserKey("echo:alice"), makeUserNameKey("alice"), makeUserKey("alice")} {
+		fakeClient.Data[missing] = tools.EtcdResponseWithError{R: &etcd.Response{}, E: tools.EtcdErrorNotFound}
+	}
+	fakeClient.Set(makeUserNameKey("alice2"), runtime.EncodeOrDie(latest.Codec, &userapi.User{
+		ObjectMeta: kapi.ObjectMeta{Name: "alice2"},
+		Identities: []string{"echo:alice"},
+	}), 0)
+	client := &racingClient{
+		FakeEtcdClient: fakeClient,
+		key:            makeUserKey("echo:alice"),
+		winner: runtime.EncodeOrDie(latest.Codec, &userapi.UserIdentityMapping{
+			ObjectMeta: kapi.ObjectMeta{Name: "echo:alice"},
+			Identity:   userapi.Identity{ObjectMeta: kapi.ObjectMeta{Name: "echo:alice"}, Provider: "echo", UserName: "alice"},
+			User:       userapi.User{ObjectMeta: kapi.ObjectMeta{Name: "alice2"}},
+		}),
+	}
+	// a concurrent login mapped the identity to alice2 while this one created alice
+	registry := NewTestEtcdWithMethod(client, user.MappingMethodGenerate)
+
+	mapping, created, err := registry.CreateOrUpdateUserIdentityMapping(&userapi.UserIdentityMapping{
+		Identity: userapi.Identity{Provider: "echo", UserName: "alice"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created || mapping.User.Name != "alice2" {
+		t.Errorf("expected the concurrently created mapping, got %t %#v", created, mapping)
+	}
+	if _, err := registry.GetUser("alice"); err == nil {
+		t.Errorf("expected the user created for the identity to be deleted")
+	}
+}
+
+func TestEtcdUpdateAndDeleteUser(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	// a user that is only stored in the mapping of its identity
+	fakeClient.Set(makeUserKey("foxtrot:golf"), runtime.EncodeOrDie(latest.Codec, &userapi.UserIdentityMapping{
+		ObjectMeta: kapi.ObjectMeta{Name: "foxtrot:golf"},
+		Identity:   userapi.Identity{ObjectMeta: kapi.ObjectMeta{Name: "foxtrot:golf"}, Provider: "foxtrot", UserName: "golf"},
+		User:       userapi.User{ObjectMeta: kapi.ObjectMeta{Name: "foxtrot:golf"}, FullName: "Golf"},
+	}), 0)
+	fakeClient.Data[makeUserNameKey("foxtrot:golf")] = tools.EtcdResponseWithError{R: &etcd.Response{}, E: tools.EtcdErrorNotFound}
+	registry := NewTestEtcd(fakeClient)
+
+	updated, err := registry.UpdateUser(&userapi.User{ObjectMeta: kapi.ObjectMeta{Name: "foxtrot:golf"}, FullName: "Golf", Disabled: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !updated.Disabled || !reflect.DeepEqual(updated.Identities, []string{"foxtrot:golf"}) {
+		t.Errorf("Expected the user to be disabled and keep its identity, got %#v", updated)
+	}
+	if user, err := registry.GetUser("foxtrot:golf"); err != nil || !user.Disabled || user.FullName != "Golf" {
+		t.Errorf("Expected the disabled user to be stored, got %#v %v", user, err)
+	}
+
+	if err := registry.DeleteUser("foxtrot:golf"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, key := range []string{makeUserKey("foxtrot:golf"), makeUserNameKey("foxtrot:golf")} {
+		if _, err := fakeClient.Get(key, false, false); !tools.IsEtcdNotFound(err) {
+			t.Errorf("Expected %s to be deleted, got %v", key, err)
+		}
+	}
+}
+
+func TestEtcdRecordLogin(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	login := util.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	fakeClient.Set(makeUserNameKey("hotel"), runtime.EncodeOrDie(latest.Codec, &userapi.User{
+		ObjectMeta:         kapi.ObjectMeta{Name: "hotel"},
+		LastLoginTimestamp: login,
+	}), 0)
+	registry := NewTestEtcd(fakeClient)
+
+	if err := registry.RecordLogin("hotel", util.Date(2014, 1, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user, err := registry.GetUser("hotel"); err != nil || !user.LastLoginTimestamp.Equal(login.Time) {
+		t.Errorf("Expected an earlier login not to be recorded, got %#v %v", user, err)
+	}
+
+	later := util.Date(2015, 2, 1, 0, 0, 0, 0, time.UTC)
+	if err := registry.RecordLogin("hotel", later); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user, err := registry.GetUser("hotel"); err != nil || !user.LastLoginTimestamp.Equal(later.Time) {
+		t.Errorf("Expected the later login to be recorded, got %#v %v", user, err)
+	}
+}
+
+func TestEtcdListGetAndDeleteIdentities(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	mapping := &userapi.UserIdentityMapping{
+		ObjectMeta: kapi.ObjectMeta{Name: "hotel:india"},
+		Identity:   userapi.Identity{ObjectMeta: kapi.ObjectMeta{Name: "hotel:india"}, Provider: "hotel", UserName: "india"},
+		User:       userapi.User{ObjectMeta: kapi.ObjectMeta{Name: "india", UID: "1"}},
+	}
+	fakeClient.Data["/userIdentityMappings"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Nodes: []*etcd.Node{
+					{Value: runtime.EncodeOrDie(latest.Codec, mapping), ModifiedIndex: 1},
+				},
+			},
+		},
+	}
+	fakeClient.Set(makeUserKey("hotel:india"), runtime.EncodeOrDie(latest.Codec, mapping), 0)
+	fakeClient.Set(makeUserNameKey("india"), runtime.EncodeOrDie(latest.Codec, &userapi.User{
+		ObjectMeta: kapi.ObjectMeta{Name: "india", UID: "1"},
+		Identities: []string{"juliet:india", "hotel:india"},
+	}), 0)
+	registry := NewTestEtcd(fakeClient)
+
+	identities, err := registry.ListIdentities(labels.Everything())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(identities.Items) != 1 || identities.Items[0].Name != "hotel:india" || identities.Items[0].User.Name != "india" {
+		t.Errorf("Expected the identity with a reference to its user, got %#v", identities.Items)
+	}
+
+	identity, err := registry.GetIdentity("hotel:india")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if identity.User.Name != "india" || identity.User.UID != "1" {
+		t.Errorf("Expected the identity to reference its user, got %#v", identity.User)
+	}
+
+	if err := registry.DeleteIdentity("hotel:india"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := fakeClient.Get(makeUserKey("hotel:india"), false, false); !tools.IsEtcdNotFound(err) {
+		t.Errorf("Expected the mapping to be deleted, got %v", err)
+	}
+	user, err := registry.GetUser("india")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(user.Identities, []string{"juliet:india"}) {
+		t.Errorf("Expected the identity to be removed from its user, got %v", user.Identities)
+	}
+}
+
 func compareUserIdentityMappingFieldsThatAreFixed(expected, actual *userapi.UserIdentityMapping) bool {
 	if ((actual == nil) && (expected != nil)) || ((actual != nil) && (expected == nil)) {
 		return false
@@ -1,5 +1,17 @@
 package identity
 
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	"github.com/openshift/origin/pkg/user/api"
+)
+
 // Registry is an interface for things that know how to store Identity objects.
 type Registry interface {
+	// ListIdentities obtains a list of identities that match a selector.
+	ListIdentities(selector labels.Selector) (*api.IdentityList, error)
+	// GetIdentity retrieves a specific identity.
+	GetIdentity(name string) (*api.Identity, error)
+	// DeleteIdentity deletes an identity and removes it from the identities of its user.
+	DeleteIdentity(name string) error
 }
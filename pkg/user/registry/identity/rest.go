@@ -0,0 +1,48 @@
+package identity
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/user/api"
+)
+
+// REST implements the RESTStorage interface in terms of an Registry.  Identities are created by mapping them
+// to users, so they can only be listed, retrieved, and deleted.
+type REST struct {
+	registry Registry
+}
+
+// NewREST returns a new REST.
+func NewREST(registry Registry) apiserver.RESTStorage {
+	return &REST{registry}
+}
+
+// New returns a new Identity.
+func (s *REST) New() runtime.Object {
+	return &api.Identity{}
+}
+
+// NewList returns a new IdentityList.
+func (*REST) NewList() runtime.Object {
+	return &api.IdentityList{}
+}
+
+// Get retrieves an Identity by id.
+func (s *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
+	return s.registry.GetIdentity(id)
+}
+
+// List retrieves a list of Identities that match selector.
+func (s *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime.Object, error) {
+	return s.registry.ListIdentities(selector)
+}
+
+// Delete asynchronously deletes an Identity specified by its id.
+func (s *REST) Delete(ctx kapi.Context, id string) (<-chan apiserver.RESTResult, error) {
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		return &kapi.Status{Status: kapi.StatusSuccess}, s.registry.DeleteIdentity(id)
+	}), nil
+}
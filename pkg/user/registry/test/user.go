@@ -1,6 +1,8 @@
 package test
 
 import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
 	"github.com/openshift/origin/pkg/user/api"
 )
 
@@ -8,8 +10,25 @@ type UserRegistry struct {
 	Err           error
 	User          *api.User
 	DeletedUserID string
+	LoginUserID   string
+	LoginTime     util.Time
 }
 
 func (r *UserRegistry) GetUser(id string) (*api.User, error) {
 	return r.User, r.Err
 }
+
+func (r *UserRegistry) UpdateUser(user *api.User) (*api.User, error) {
+	r.User = user
+	return r.User, r.Err
+}
+
+func (r *UserRegistry) RecordLogin(id string, at util.Time) error {
+	r.LoginUserID, r.LoginTime = id, at
+	return r.Err
+}
+
+func (r *UserRegistry) DeleteUser(id string) error {
+	r.DeletedUserID = id
+	return r.Err
+}
@@ -17,5 +17,8 @@ func (r *UserIdentityMappingRegistry) GetUserIdentityMapping(name string) (*api.
 
 func (r *UserIdentityMappingRegistry) CreateOrUpdateUserIdentityMapping(mapping *api.UserIdentityMapping) (*api.UserIdentityMapping, bool, error) {
 	r.CreatedUserIdentityMapping = mapping
+	if r.UserIdentityMapping != nil {
+		return r.UserIdentityMapping, r.Created, r.Err
+	}
 	return r.CreatedUserIdentityMapping, r.Created, r.Err
 }
@@ -0,0 +1,96 @@
+package user
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	"github.com/openshift/origin/pkg/authorization/registry/policybinding"
+	"github.com/openshift/origin/pkg/oauth/registry/accesstoken"
+	"github.com/openshift/origin/pkg/oauth/registry/authorizetoken"
+	"github.com/openshift/origin/pkg/oauth/registry/clientauthorization"
+)
+
+// Cleanup removes the references other registries hold to a user that is being deleted.
+type Cleanup struct {
+	AccessTokens         accesstoken.Registry
+	AuthorizeTokens      authorizetoken.Registry
+	ClientAuthorizations clientauthorization.Registry
+	PolicyBindings       policybinding.Registry
+	// Namespaces lists the namespaces whose role bindings may name the user
+	Namespaces func() ([]string, error)
+}
+
+// RemoveUser deletes the OAuth tokens and client authorizations of the user, and removes the user from the
+// role bindings in every namespace.
+func (c *Cleanup) RemoveUser(name string) error {
+	accessTokens, err := c.AccessTokens.ListAccessTokens(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, token := range accessTokens.Items {
+		if token.UserName == name {
+			if err := c.AccessTokens.DeleteAccessToken(token.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	authorizeTokens, err := c.AuthorizeTokens.ListAuthorizeTokens(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, token := range authorizeTokens.Items {
+		if token.UserName == name {
+			if err := c.AuthorizeTokens.DeleteAuthorizeToken(token.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	authorizations, err := c.ClientAuthorizations.ListClientAuthorizations(labels.Everything(), labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, authorization := range authorizations.Items {
+		if authorization.UserName == name {
+			if err := c.ClientAuthorizations.DeleteClientAuthorization(authorization.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	namespaces, err := c.Namespaces()
+	if err != nil {
+		return err
+	}
+	for _, namespace := range namespaces {
+		ctx := kapi.WithNamespace(kapi.NewContext(), namespace)
+		bindings, err := c.PolicyBindings.ListPolicyBindings(ctx, labels.Everything(), labels.Everything())
+		if err != nil {
+			return err
+		}
+		for i := range bindings.Items {
+			binding := &bindings.Items[i]
+			changed := false
+			for roleBindingName, roleBinding := range binding.RoleBindings {
+				userNames := []string{}
+				for _, userName := range roleBinding.UserNames {
+					if userName != name {
+						userNames = append(userNames, userName)
+					}
+				}
+				if len(userNames) != len(roleBinding.UserNames) {
+					roleBinding.UserNames = userNames
+					binding.RoleBindings[roleBindingName] = roleBinding
+					changed = true
+				}
+			}
+			if changed {
+				if err := c.PolicyBindings.UpdatePolicyBinding(ctx, binding); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
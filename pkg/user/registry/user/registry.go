@@ -1,10 +1,18 @@
 package user
 
 import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
 	"github.com/openshift/origin/pkg/user/api"
 )
 
 // Registry is an interface for things that know how to store User objects.
 type Registry interface {
 	GetUser(name string) (*api.User, error)
+	// UpdateUser sets the full name of an existing user and whether it is disabled.
+	UpdateUser(user *api.User) (*api.User, error)
+	// DeleteUser deletes a user and the mappings of its identities.
+	DeleteUser(name string) error
+	// RecordLogin sets the last login time of a user.
+	RecordLogin(name string, at util.Time) error
 }
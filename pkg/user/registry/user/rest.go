@@ -1,6 +1,8 @@
 package user
 
 import (
+	"fmt"
+
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
@@ -11,11 +13,12 @@ import (
 // REST implements the RESTStorage interface in terms of an Registry.
 type REST struct {
 	registry Registry
+	cleanup  *Cleanup
 }
 
-// NewREST returns a new REST.
-func NewREST(registry Registry) apiserver.RESTStorage {
-	return &REST{registry}
+// NewREST returns a new REST that removes the references of cleanup to the users it deletes.
+func NewREST(registry Registry, cleanup *Cleanup) apiserver.RESTStorage {
+	return &REST{registry, cleanup}
 }
 
 // New returns a new UserIdentityMapping for use with Create and Update.
@@ -27,3 +30,34 @@ func (s *REST) New() runtime.Object {
 func (s *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
 	return s.registry.GetUser(id)
 }
+
+// Update sets the full name of a user and whether it is disabled.  Disabled users are refused
+// authentication.
+func (s *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	user, ok := obj.(*api.User)
+	if !ok {
+		return nil, fmt.Errorf("not a user: %#v", obj)
+	}
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		return s.registry.UpdateUser(user)
+	}), nil
+}
+
+// Delete disables a user so that it cannot authenticate while its OAuth tokens, client authorizations, and
+// role bindings are removed, and then deletes the user and the mappings of its identities.
+func (s *REST) Delete(ctx kapi.Context, id string) (<-chan apiserver.RESTResult, error) {
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		user, err := s.registry.GetUser(id)
+		if err != nil {
+			return nil, err
+		}
+		user.Disabled = true
+		if _, err := s.registry.UpdateUser(user); err != nil {
+			return nil, err
+		}
+		if err := s.cleanup.RemoveUser(id); err != nil {
+			return nil, err
+		}
+		return &kapi.Status{Status: kapi.StatusSuccess}, s.registry.DeleteUser(id)
+	}), nil
+}
@@ -0,0 +1,82 @@
+package user
+
+import (
+	"reflect"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
+	authorizationtest "github.com/openshift/origin/pkg/authorization/registry/test"
+	oauthapi "github.com/openshift/origin/pkg/oauth/api"
+	oauthtest "github.com/openshift/origin/pkg/oauth/registry/test"
+	"github.com/openshift/origin/pkg/user/api"
+	"github.com/openshift/origin/pkg/user/registry/test"
+)
+
+func TestUpdateUser(t *testing.T) {
+	registry := &test.UserRegistry{}
+	storage := NewREST(registry, &Cleanup{}).(*REST)
+
+	ch, err := storage.Update(kapi.NewContext(), &api.User{ObjectMeta: kapi.ObjectMeta{Name: "alice"}, Disabled: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := <-ch; result.Object.(*api.User).Name != "alice" || !registry.User.Disabled {
+		t.Errorf("Expected the user to be disabled, got %#v", result.Object)
+	}
+}
+
+func TestDeleteUserCascades(t *testing.T) {
+	registry := &test.UserRegistry{User: &api.User{ObjectMeta: kapi.ObjectMeta{Name: "alice"}}}
+	accessTokens := &oauthtest.AccessTokenRegistry{AccessTokens: &oauthapi.OAuthAccessTokenList{Items: []oauthapi.OAuthAccessToken{
+		{ObjectMeta: kapi.ObjectMeta{Name: "bobs-token"}, UserName: "bob"},
+		{ObjectMeta: kapi.ObjectMeta{Name: "alices-token"}, UserName: "alice"},
+	}}}
+	authorizeTokens := &oauthtest.AuthorizeTokenRegistry{AuthorizeTokens: &oauthapi.OAuthAuthorizeTokenList{Items: []oauthapi.OAuthAuthorizeToken{
+		{ObjectMeta: kapi.ObjectMeta{Name: "alices-code"}, UserName: "alice"},
+	}}}
+	authorizations := &oauthtest.ClientAuthorizationRegistry{ClientAuthorizations: &oauthapi.OAuthClientAuthorizationList{Items: []oauthapi.OAuthClientAuthorization{
+		{ObjectMeta: kapi.ObjectMeta{Name: "alice:console"}, UserName: "alice"},
+	}}}
+	bindings := &authorizationtest.PolicyBindingRegistry{PolicyBindings: []authorizationapi.PolicyBinding{
+		{
+			ObjectMeta: kapi.ObjectMeta{Name: "master", Namespace: "project"},
+			RoleBindings: map[string]authorizationapi.RoleBinding{
+				"admins":  {UserNames: []string{"bob", "alice"}},
+				"viewers": {UserNames: []string{"carol"}},
+			},
+		},
+	}}
+	storage := NewREST(registry, &Cleanup{
+		AccessTokens:         accessTokens,
+		AuthorizeTokens:      authorizeTokens,
+		ClientAuthorizations: authorizations,
+		PolicyBindings:       bindings,
+		Namespaces:           func() ([]string, error) { return []string{"master", "project"}, nil },
+	}).(*REST)
+
+	ch, err := storage.Delete(kapi.NewContext(), "alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status, ok := (<-ch).Object.(*kapi.Status); !ok || status.Status != kapi.StatusSuccess {
+		t.Errorf("Expected the user to be deleted, got %#v", status)
+	}
+	if !registry.User.Disabled || registry.DeletedUserID != "alice" {
+		t.Errorf("Expected the user to be disabled and deleted: %#v %q", registry.User, registry.DeletedUserID)
+	}
+	if accessTokens.DeletedAccessTokenName != "alices-token" {
+		t.Errorf("Expected the access token of the user to be deleted, got %q", accessTokens.DeletedAccessTokenName)
+	}
+	if authorizeTokens.DeletedAuthorizeTokenName != "alices-code" {
+		t.Errorf("Expected the authorize token of the user to be deleted, got %q", authorizeTokens.DeletedAuthorizeTokenName)
+	}
+	if authorizations.DeletedClientAuthorizationName != "alice:console" {
+		t.Errorf("Expected the client authorization of the user to be deleted, got %q", authorizations.DeletedClientAuthorizationName)
+	}
+	roleBindings := bindings.PolicyBindings[0].RoleBindings
+	if !reflect.DeepEqual(roleBindings["admins"].UserNames, []string{"bob"}) || !reflect.DeepEqual(roleBindings["viewers"].UserNames, []string{"carol"}) {
+		t.Errorf("Expected the user to be removed from the role bindings, got %#v", roleBindings)
+	}
+}
@@ -0,0 +1,46 @@
+// Package etcd holds helpers shared by the etcd registries of OpenShift resources.
+package etcd
+
+import (
+	"errors"
+
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+)
+
+// ErrResourceVersionRequired is the cause of the conflict returned when an update does not name the version
+// of the object it replaces.
+var ErrResourceVersionRequired = errors.New("resourceVersion must be set to update an object, read the latest version and apply your changes to it")
+
+// ErrObjectModified is the cause of the conflict returned when the object was changed after the version an
+// update was made from.
+var ErrObjectModified = errors.New("the object has been modified, read the latest version and apply your changes to it")
+
+// UpdateObj replaces the object of kind stored at key with obj, if the stored object is still at the
+// resourceVersion of obj.  An update without a resourceVersion, or of an object that has changed since, is
+// a Conflict error, and an update of an object that does not exist is a NotFound error.
+func UpdateObj(helper tools.EtcdHelper, key string, obj runtime.Object, kind, name string) error {
+	version, err := helper.ResourceVersioner.ResourceVersion(obj)
+	if err != nil {
+		return err
+	}
+	if version == 0 {
+		return kerrors.NewConflict(kind, name, ErrResourceVersionRequired)
+	}
+	data, err := helper.Codec.Encode(obj)
+	if err != nil {
+		return err
+	}
+	_, err = helper.Client.CompareAndSwap(key, string(data), 0, "", version)
+	switch {
+	case err == nil:
+		return nil
+	case tools.IsEtcdNotFound(err):
+		return kerrors.NewNotFound(kind, name)
+	case tools.IsEtcdTestFailed(err):
+		return kerrors.NewConflict(kind, name, ErrObjectModified)
+	default:
+		return err
+	}
+}
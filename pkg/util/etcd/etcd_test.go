@@ -0,0 +1,43 @@
+package etcd
+
+import (
+	"strconv"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+)
+
+func TestUpdateObj(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	helper := tools.EtcdHelper{Client: fakeClient, Codec: latest.Codec, ResourceVersioner: tools.RuntimeVersionAdapter{Versioner: latest.ResourceVersioner}}
+	resp, _ := fakeClient.Set("/pods/foo", runtime.EncodeOrDie(latest.Codec, &kapi.Pod{ObjectMeta: kapi.ObjectMeta{Name: "foo"}}), 0)
+	current := strconv.FormatUint(resp.Node.ModifiedIndex, 10)
+	stale := strconv.FormatUint(resp.Node.ModifiedIndex-1, 10)
+
+	fakeClient.ExpectNotFoundGet("/pods/bar")
+
+	// the updates are applied in order, the version of the object changes with the successful one
+	testCases := []struct {
+		name    string
+		key     string
+		version string
+		check   func(error) bool
+	}{
+		{"no version", "/pods/foo", "", kerrors.IsConflict},
+		{"stale version", "/pods/foo", stale, kerrors.IsConflict},
+		{"missing object", "/pods/bar", current, kerrors.IsNotFound},
+		{"current version", "/pods/foo", current, func(err error) bool { return err == nil }},
+		{"version before the update", "/pods/foo", current, kerrors.IsConflict},
+	}
+	for _, tc := range testCases {
+		pod := &kapi.Pod{ObjectMeta: kapi.ObjectMeta{Name: "foo", ResourceVersion: tc.version}}
+		if err := UpdateObj(helper, tc.key, pod, "pod", "foo"); !tc.check(err) {
+			t.Errorf("%s: unexpected result %#v", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package etcd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/conversion"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/coreos/go-etcd/etcd"
+)
+
+// ExtractPageToList decodes into the items of listObj up to limit of the objects stored below key that
+// matches returns true for, in the order etcd lists them, and returns the continue token of the next page,
+// which is empty if no objects remain.  A page continues the list after the last key of the page
+// continueToken was returned with, or starts it if continueToken is empty.
+//
+// etcd v2 cannot read part of a directory, so every page reads the whole collection below key from etcd.
+// Paging bounds the objects decoded and returned for each page, not the etcd read.
+//
+// The etcd index of the first page is the resourceVersion of every page of the list.  etcd v2 cannot read at
+// an earlier index either, so later pages read the collection as it is now and leave out the objects created
+// after the first page was read.  Keys are listed in order, so no object is listed twice, but an object that
+// is deleted and recreated under the same key before its page is read is left out of the list altogether,
+// and objects changed since are listed as they are now.
+func ExtractPageToList(helper tools.EtcdHelper, key string, listObj runtime.Object, matches func(runtime.Object) bool, limit int, continueToken string) (string, error) {
+	if limit <= 0 {
+		return "", kerrors.NewBadRequest(fmt.Sprintf("limit must be a positive integer, got %d", limit))
+	}
+	index, after, err := parseContinueToken(key, continueToken)
+	if err != nil {
+		return "", err
+	}
+	listPtr, err := runtime.GetItemsPtr(listObj)
+	if err != nil {
+		return "", err
+	}
+	items, err := conversion.EnforcePtr(listPtr)
+	if err != nil || items.Kind() != reflect.Slice {
+		return "", fmt.Errorf("the items of %T are not a slice", listObj)
+	}
+
+	response, err := helper.Client.Get(key, true, true)
+	var nodes []*etcd.Node
+	switch {
+	case err == nil:
+		if response.Node != nil {
+			nodes = leafNodes(response.Node.Nodes, nil)
+		}
+		if index == 0 {
+			index = response.EtcdIndex
+		}
+	case tools.IsEtcdNotFound(err):
+		if etcdErr, ok := err.(*etcd.EtcdError); ok && index == 0 {
+			index = etcdErr.Index
+		}
+	default:
+		return "", err
+	}
+
+	next := ""
+	for _, node := range nodes {
+		if node.CreatedIndex > index || (len(after) > 0 && !keyAfter(node.Key, after)) {
+			continue
+		}
+		if items.Len() == limit {
+			next = formatContinueToken(index, after)
+			break
+		}
+		obj := reflect.New(items.Type().Elem())
+		if err := helper.Codec.DecodeInto([]byte(node.Value), obj.Interface().(runtime.Object)); err != nil {
+			return "", err
+		}
+		if helper.ResourceVersioner != nil {
+			// being unable to set the version does not prevent the object from being listed
+			_ = helper.ResourceVersioner.SetResourceVersion(obj.Interface().(runtime.Object), node.ModifiedIndex)
+		}
+		after = node.Key
+		if matches(obj.Interface().(runtime.Object)) {
+			items.Set(reflect.Append(items, obj.Elem()))
+		}
+	}
+	if helper.ResourceVersioner != nil {
+		if err := helper.ResourceVersioner.SetResourceVersion(listObj, index); err != nil {
+			return "", err
+		}
+	}
+	return next, nil
+}
+
+// leafNodes appends the nodes holding objects in the tree of nodes to leaves, in the order etcd lists them.
+func leafNodes(nodes []*etcd.Node, leaves []*etcd.Node) []*etcd.Node {
+	for _, node := range nodes {
+		if node.Dir {
+			leaves = leafNodes(node.Nodes, leaves)
+			continue
+		}
+		leaves = append(leaves, node)
+	}
+	return leaves
+}
+
+// keyAfter returns true if key is listed after the key after.  etcd sorts the keys of each directory
+// separately, so keys are compared a path segment at a time.
+func keyAfter(key, after string) bool {
+	keyParts, afterParts := strings.Split(key, "/"), strings.Split(after, "/")
+	for i := 0; i < len(keyParts) && i < len(afterParts); i++ {
+		if keyParts[i] != afterParts[i] {
+			return keyParts[i] > afterParts[i]
+		}
+	}
+	return len(keyParts) > len(afterParts)
+}
+
+// formatContinueToken returns the continue token of the page that follows the key after in a list read at
+// the etcd index.
+func formatContinueToken(index uint64, after string) string {
+	return base64.URLEncoding.EncodeToString([]byte(strconv.FormatUint(index, 10) + after))
+}
+
+// parseContinueToken returns the etcd index and last key of the list below key that token continues.  An
+// empty token starts a list.
+func parseContinueToken(key, token string) (uint64, string, error) {
+	if len(token) == 0 {
+		return 0, "", nil
+	}
+	data, err := base64.URLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, "", kerrors.NewBadRequest("the continue token is invalid, list again without it")
+	}
+	token = string(data)
+	i := strings.Index(token, "/")
+	if i <= 0 || !strings.HasPrefix(token[i:], strings.TrimSuffix(key, "/")+"/") {
+		return 0, "", kerrors.NewBadRequest("the continue token is invalid, list again without it")
+	}
+	index, err := strconv.ParseUint(token[:i], 10, 64)
+	if err != nil || index == 0 {
+		return 0, "", kerrors.NewBadRequest("the continue token is invalid, list again without it")
+	}
+	return index, token[i:], nil
+}
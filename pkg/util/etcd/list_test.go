@@ -0,0 +1,120 @@
+package etcd
+
+import (
+	"reflect"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/coreos/go-etcd/etcd"
+)
+
+func podNode(namespace, name string, createdIndex uint64) *etcd.Node {
+	pod := &kapi.Pod{ObjectMeta: kapi.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"name": name}}}
+	return &etcd.Node{
+		Key:           "/pods/" + namespace + "/" + name,
+		Value:         runtime.EncodeOrDie(latest.Codec, pod),
+		CreatedIndex:  createdIndex,
+		ModifiedIndex: createdIndex,
+	}
+}
+
+func podNames(list *kapi.PodList) []string {
+	names := []string{}
+	for _, pod := range list.Items {
+		names = append(names, pod.Namespace+"/"+pod.Name)
+	}
+	return names
+}
+
+func TestExtractPageToList(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	helper := tools.EtcdHelper{Client: fakeClient, Codec: latest.Codec, ResourceVersioner: tools.RuntimeVersionAdapter{Versioner: latest.ResourceVersioner}}
+	// "a-b" sorts before "a/" as a key, but etcd lists the directory "a" before "a-b"
+	fakeClient.Data["/pods"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			EtcdIndex: 10,
+			Node: &etcd.Node{
+				Key: "/pods",
+				Dir: true,
+				Nodes: []*etcd.Node{
+					{Key: "/pods/a-b", Dir: true, Nodes: []*etcd.Node{podNode("a-b", "one", 4)}},
+					{Key: "/pods/a", Dir: true, Nodes: []*etcd.Node{podNode("a", "two", 3), podNode("a", "one", 2)}},
+					{Key: "/pods/b", Dir: true, Nodes: []*etcd.Node{podNode("b", "one", 5), podNode("b", "skip", 6), podNode("b", "two", 7)}},
+				},
+			},
+		},
+	}
+	notSkipped := func(obj runtime.Object) bool {
+		return obj.(*kapi.Pod).Name != "skip"
+	}
+
+	pages := [][]string{}
+	token := ""
+	for {
+		list := &kapi.PodList{}
+		next, err := ExtractPageToList(helper, "/pods", list, notSkipped, 2, token)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if list.ResourceVersion != "10" {
+			t.Errorf("expected every page to be read at index 10, got %q", list.ResourceVersion)
+		}
+		pages = append(pages, podNames(list))
+		if len(next) == 0 {
+			break
+		}
+		token = next
+
+		if len(pages) == 1 {
+			// deleting a listed pod does not move the next page, and a pod created after the list started is
+			// left out of its later pages, even if it was deleted and recreated under the same key
+			dir := fakeClient.Data["/pods"].R.Node.Nodes
+			dir[0].Nodes = append(dir[0].Nodes[1:], podNode("a", "x", 11))
+			dir[2].Nodes[0] = podNode("b", "one", 12)
+		}
+	}
+
+	expected := [][]string{{"a/one", "a/two"}, {"a-b/one", "b/two"}}
+	if !reflect.DeepEqual(expected, pages) {
+		t.Errorf("expected pages %v, got %v", expected, pages)
+	}
+}
+
+func TestExtractPageToListNotFound(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	helper := tools.EtcdHelper{Client: fakeClient, Codec: latest.Codec, ResourceVersioner: tools.RuntimeVersionAdapter{Versioner: latest.ResourceVersioner}}
+	fakeClient.ExpectNotFoundGet("/pods")
+
+	list := &kapi.PodList{}
+	next, err := ExtractPageToList(helper, "/pods", list, func(runtime.Object) bool { return true }, 2, "")
+	if err != nil || len(next) != 0 || len(list.Items) != 0 {
+		t.Errorf("expected an empty list, got %#v %q %v", list, next, err)
+	}
+}
+
+func TestExtractPageToListInvalid(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	helper := tools.EtcdHelper{Client: fakeClient, Codec: latest.Codec, ResourceVersioner: tools.RuntimeVersionAdapter{Versioner: latest.ResourceVersioner}}
+
+	testCases := map[string]struct {
+		limit int
+		token string
+	}{
+		"no limit":                        {0, ""},
+		"negative limit":                  {-1, ""},
+		"not base64":                      {2, "?"},
+		"no index":                        {2, formatContinueToken(0, "/pods/a/one")},
+		"another collection":              {2, formatContinueToken(10, "/services/a/one")},
+		"a collection with a longer name": {2, formatContinueToken(10, "/podsx/a/one")},
+	}
+	for name, tc := range testCases {
+		_, err := ExtractPageToList(helper, "/pods", &kapi.PodList{}, func(runtime.Object) bool { return true }, tc.limit, tc.token)
+		if !kerrors.IsBadRequest(err) {
+			t.Errorf("%s: expected a bad request, got %v", name, err)
+		}
+	}
+}
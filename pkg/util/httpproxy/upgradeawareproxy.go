@@ -9,6 +9,7 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"strings"
+	"time"
 
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/golang/glog"
@@ -22,6 +23,8 @@ type UpgradeAwareSingleHostReverseProxy struct {
 	backendAddr  *url.URL
 	transport    http.RoundTripper
 	reverseProxy *httputil.ReverseProxy
+	// streamingProxy proxies the requests whose responses are streamed, flushing what it has read periodically
+	streamingProxy *httputil.ReverseProxy
 }
 
 // NewUpgradeAwareSingleHostReverseProxy creates a new UpgradeAwareSingleHostReverseProxy.
@@ -31,12 +34,16 @@ func NewUpgradeAwareSingleHostReverseProxy(clientConfig *kclient.Config, backend
 		return nil, err
 	}
 	p := &UpgradeAwareSingleHostReverseProxy{
-		clientConfig: clientConfig,
-		backendAddr:  backendAddr,
-		transport:    transport,
-		reverseProxy: httputil.NewSingleHostReverseProxy(backendAddr),
+		clientConfig:   clientConfig,
+		backendAddr:    backendAddr,
+		transport:      transport,
+		reverseProxy:   httputil.NewSingleHostReverseProxy(backendAddr),
+		streamingProxy: httputil.NewSingleHostReverseProxy(backendAddr),
 	}
 	p.reverseProxy.Transport = p
+	p.streamingProxy.Transport = p
+	// flush periodically so streamed responses like watches and logs reach the client as they are written
+	p.streamingProxy.FlushInterval = 200 * time.Millisecond
 	return p, nil
 }
 
@@ -92,7 +99,11 @@ func (p *UpgradeAwareSingleHostReverseProxy) newProxyRequest(req *http.Request)
 	return newReq, nil
 }
 
+// isUpgradeRequest returns true if req asks to switch protocols, for example to web sockets.
 func (p *UpgradeAwareSingleHostReverseProxy) isUpgradeRequest(req *http.Request) bool {
+	if len(req.Header.Get("Upgrade")) == 0 {
+		return false
+	}
 	for _, h := range req.Header[http.CanonicalHeaderKey("Connection")] {
 		if strings.Contains(strings.ToLower(h), "upgrade") {
 			return true
@@ -101,6 +112,23 @@ func (p *UpgradeAwareSingleHostReverseProxy) isUpgradeRequest(req *http.Request)
 	return false
 }
 
+// isStreamingRequest returns true if the response to req is streamed as the backend writes it, like the
+// responses to watches and to requests that follow logs.
+func (p *UpgradeAwareSingleHostReverseProxy) isStreamingRequest(req *http.Request) bool {
+	for _, segment := range strings.Split(req.URL.Path, "/") {
+		if segment == "watch" {
+			return true
+		}
+	}
+	query := req.URL.Query()
+	for _, param := range []string{"watch", "follow"} {
+		if value := query.Get(param); value == "true" || value == "1" {
+			return true
+		}
+	}
+	return false
+}
+
 // ServeHTTP inspects the request and either proxies an upgraded connection directly,
 // or uses httputil.ReverseProxy to proxy the normal request.
 func (p *UpgradeAwareSingleHostReverseProxy) ServeHTTP(w http.ResponseWriter, req *http.Request) {
@@ -113,10 +141,20 @@ func (p *UpgradeAwareSingleHostReverseProxy) ServeHTTP(w http.ResponseWriter, re
 	}
 
 	if !p.isUpgradeRequest(req) {
+		if p.isStreamingRequest(req) {
+			p.streamingProxy.ServeHTTP(w, newReq)
+			return
+		}
 		p.reverseProxy.ServeHTTP(w, newReq)
 		return
 	}
 
+	// only HTTP/1.x connections can be taken over, HTTP/2 has no connection upgrades.  The backend request
+	// is always HTTP/1.1, so the version the client connected with is checked.
+	if req.ProtoMajor != 1 {
+		http.Error(w, "Connection upgrades require HTTP/1.1", http.StatusHTTPVersionNotSupported)
+		return
+	}
 	p.serveUpgrade(w, newReq)
 }
 
@@ -145,6 +183,12 @@ func (p *UpgradeAwareSingleHostReverseProxy) dialBackend(req *http.Request) (net
 }
 
 func (p *UpgradeAwareSingleHostReverseProxy) serveUpgrade(w http.ResponseWriter, req *http.Request) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "Connection upgrades require HTTP/1.1", http.StatusHTTPVersionNotSupported)
+		return
+	}
+
 	backendConn, err := p.dialBackend(req)
 	if err != nil {
 		glog.Errorf("Error connecting to backend: %s", err)
@@ -154,7 +198,7 @@ func (p *UpgradeAwareSingleHostReverseProxy) serveUpgrade(w http.ResponseWriter,
 	}
 	defer backendConn.Close()
 
-	requestHijackedConn, _, err := w.(http.Hijacker).Hijack()
+	requestHijackedConn, requestBuffer, err := hijacker.Hijack()
 	if err != nil {
 		glog.Errorf("Error hijacking request connection: %s", err)
 		return
@@ -172,7 +216,8 @@ func (p *UpgradeAwareSingleHostReverseProxy) serveUpgrade(w http.ResponseWriter,
 	done := make(chan struct{}, 2)
 
 	go func() {
-		_, err := io.Copy(backendConn, requestHijackedConn)
+		// the client may have sent data after the request that was already read into the buffer
+		_, err := io.Copy(backendConn, io.MultiReader(io.LimitReader(requestBuffer, int64(requestBuffer.Reader.Buffered())), requestHijackedConn))
 		if err != nil {
 			// TODO I see this printed at least whenever the client goes away from the page.
 			// Should we check for different types of errors and only log certain ones?
@@ -0,0 +1,158 @@
+package httpproxy
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+)
+
+func TestIsUpgradeRequest(t *testing.T) {
+	testCases := map[string]struct {
+		headers  map[string]string
+		expected bool
+	}{
+		"no headers":        {expected: false},
+		"connection only":   {headers: map[string]string{"Connection": "Upgrade"}, expected: false},
+		"upgrade only":      {headers: map[string]string{"Upgrade": "websocket"}, expected: false},
+		"upgrade":           {headers: map[string]string{"Connection": "Upgrade", "Upgrade": "websocket"}, expected: true},
+		"multiple options":  {headers: map[string]string{"Connection": "keep-alive, upgrade", "Upgrade": "websocket"}, expected: true},
+		"keep alive":        {headers: map[string]string{"Connection": "keep-alive", "Upgrade": "websocket"}, expected: false},
+		"lowercase headers": {headers: map[string]string{"connection": "upgrade", "upgrade": "SPDY/3.1"}, expected: true},
+	}
+	p := &UpgradeAwareSingleHostReverseProxy{}
+	for name, testCase := range testCases {
+		req, _ := http.NewRequest("GET", "http://localhost/api", nil)
+		for k, v := range testCase.headers {
+			req.Header.Set(k, v)
+		}
+		if actual := p.isUpgradeRequest(req); actual != testCase.expected {
+			t.Errorf("%s: expected %v, got %v", name, testCase.expected, actual)
+		}
+	}
+}
+
+func TestServeUpgradeWithoutHijacker(t *testing.T) {
+	backendURL, _ := url.Parse("http://127.0.0.1:1")
+	p, err := NewUpgradeAwareSingleHostReverseProxy(&kclient.Config{}, backendURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req, _ := http.NewRequest("GET", "http://localhost/exec", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, req)
+	if w.Code != http.StatusHTTPVersionNotSupported {
+		t.Errorf("expected %d, got %d", http.StatusHTTPVersionNotSupported, w.Code)
+	}
+}
+
+// hijackRecorder is a ResponseWriter that can be hijacked, and fails the test if it is.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	t *testing.T
+}
+
+func (r hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.t.Errorf("unexpected hijack of the connection")
+	return nil, nil, io.EOF
+}
+
+func TestServeUpgradeHTTP2(t *testing.T) {
+	backendURL, _ := url.Parse("http://127.0.0.1:1")
+	p, err := NewUpgradeAwareSingleHostReverseProxy(&kclient.Config{}, backendURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req, _ := http.NewRequest("GET", "http://localhost/exec", nil)
+	req.Proto, req.ProtoMajor, req.ProtoMinor = "HTTP/2.0", 2, 0
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+
+	w := hijackRecorder{httptest.NewRecorder(), t}
+	p.ServeHTTP(w, req)
+	if w.Code != http.StatusHTTPVersionNotSupported {
+		t.Errorf("expected %d, got %d", http.StatusHTTPVersionNotSupported, w.Code)
+	}
+}
+
+func TestIsStreamingRequest(t *testing.T) {
+	testCases := map[string]bool{
+		"http://localhost/api/v1beta1/pods":                                    false,
+		"http://localhost/api/v1beta1/watch/pods":                              true,
+		"http://localhost/api/v1beta3/namespaces/ns/pods?watch=true":           true,
+		"http://localhost/osapi/v1beta1/buildLogs/build?namespace=ns&follow=1": true,
+		"http://localhost/osapi/v1beta1/buildLogs/build?namespace=ns":          false,
+		"http://localhost/osapi/v1beta1/builds/watcher":                        false,
+	}
+	p := &UpgradeAwareSingleHostReverseProxy{}
+	for path, expected := range testCases {
+		req, _ := http.NewRequest("GET", path, nil)
+		if actual := p.isStreamingRequest(req); actual != expected {
+			t.Errorf("%s: expected %v, got %v", path, expected, actual)
+		}
+	}
+}
+
+func TestServeUpgrade(t *testing.T) {
+	// the backend switches protocols and then echoes everything it reads
+	backend, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer backend.Close()
+	go func() {
+		conn, err := backend.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		if _, err := http.ReadRequest(reader); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		io.WriteString(conn, "HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")
+		io.Copy(conn, reader)
+	}()
+
+	backendURL, _ := url.Parse("http://" + backend.Addr().String())
+	p, err := NewUpgradeAwareSingleHostReverseProxy(&kclient.Config{}, backendURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer conn.Close()
+	// data sent along with the request must reach the backend
+	io.WriteString(conn, "GET /echo HTTP/1.1\r\nHost: localhost\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\nhello")
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected %d, got %d", http.StatusSwitchingProtocols, resp.StatusCode)
+	}
+	io.WriteString(conn, " world")
+	data := make([]byte, len("hello world"))
+	if _, err := io.ReadFull(reader, data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != "hello world" {
+		t.Errorf("expected %q, got %q", "hello world", string(data))
+	}
+}
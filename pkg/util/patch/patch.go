@@ -0,0 +1,407 @@
+// Package patch applies JSON merge patches (RFC 7386), strategic merge patches and JSON patches (RFC 6902)
+// to JSON documents.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrTestFailed is returned when a test operation of a JSON patch does not match the document.
+var ErrTestFailed = errors.New("test operation failed")
+
+// directive is the key of strategic merge patch items that control how the item is merged.
+const directive = "$patch"
+
+// mergeKey is the key that identifies the items of a list merged by a strategic merge patch.
+const mergeKey = "name"
+
+// MergePatch applies the JSON merge patch in patch to the JSON document original.  Fields of patch replace
+// the fields of original, objects are merged recursively, and null fields are removed.
+func MergePatch(original, patch []byte) ([]byte, error) {
+	return applyMerge(original, patch, false)
+}
+
+// StrategicMergePatch applies the strategic merge patch in patch to the JSON document original.  It is a
+// merge patch that also merges lists of objects with a name item by item, so a single container or port
+// can be changed without sending the whole list.  An item or object with "$patch": "delete" is removed,
+// and an object with "$patch": "replace" replaces the original instead of being merged into it.
+func StrategicMergePatch(original, patch []byte) ([]byte, error) {
+	return applyMerge(original, patch, true)
+}
+
+func applyMerge(original, patch []byte, strategic bool) ([]byte, error) {
+	doc, err := decode(original)
+	if err != nil {
+		return nil, err
+	}
+	p, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("the patch is not valid JSON: %v", err)
+	}
+	if _, ok := p.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("the patch must be a JSON object")
+	}
+	merged, _ := merge(doc, p, strategic)
+	return json.Marshal(merged)
+}
+
+// merge returns patch merged into doc, and false if the value should be removed.
+func merge(doc, patch interface{}, strategic bool) (interface{}, bool) {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch, true
+	}
+	if strategic {
+		switch p[directive] {
+		case "delete":
+			return nil, false
+		case "replace":
+			replaced := map[string]interface{}{}
+			for k, v := range p {
+				if k != directive {
+					replaced[k] = v
+				}
+			}
+			return replaced, true
+		}
+	}
+	d, ok := doc.(map[string]interface{})
+	if !ok {
+		d = map[string]interface{}{}
+	}
+	for k, v := range p {
+		if strategic && k == directive {
+			continue
+		}
+		if v == nil {
+			delete(d, k)
+			continue
+		}
+		if strategic {
+			if items, ok := mergeList(d[k], v); ok {
+				d[k] = items
+				continue
+			}
+		}
+		if merged, keep := merge(d[k], v, strategic); keep {
+			d[k] = merged
+		} else {
+			delete(d, k)
+		}
+	}
+	return d, true
+}
+
+// mergeList merges the items of the patch list into the doc list by name.  It returns false if either is
+// not a list of objects with names.
+func mergeList(doc, patch interface{}) ([]interface{}, bool) {
+	patchItems, ok := patch.([]interface{})
+	if !ok || len(patchItems) == 0 || !named(patchItems) {
+		return nil, false
+	}
+	docItems, ok := doc.([]interface{})
+	if !ok || !named(docItems) {
+		return nil, false
+	}
+	items := append([]interface{}{}, docItems...)
+	for _, patchItem := range patchItems {
+		name := patchItem.(map[string]interface{})[mergeKey]
+		found := false
+		for i := range items {
+			if items[i].(map[string]interface{})[mergeKey] != name {
+				continue
+			}
+			found = true
+			if merged, keep := merge(items[i], patchItem, true); keep {
+				items[i] = merged
+			} else {
+				items = append(items[:i], items[i+1:]...)
+			}
+			break
+		}
+		if !found {
+			if merged, keep := merge(nil, patchItem, true); keep {
+				items = append(items, merged)
+			}
+		}
+	}
+	return items, true
+}
+
+// named returns true if every item is an object with a name.
+func named(items []interface{}) bool {
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := obj[mergeKey].(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// operation is an operation of a JSON patch.
+type operation struct {
+	Op    string           `json:"op"`
+	Path  string           `json:"path"`
+	From  string           `json:"from"`
+	Value *json.RawMessage `json:"value"`
+}
+
+// JSONPatch applies the operations of the JSON patch in patch to the JSON document original.  The patch
+// is applied as a whole: if any operation fails the document is not changed.
+func JSONPatch(original, patch []byte) ([]byte, error) {
+	doc, err := decode(original)
+	if err != nil {
+		return nil, err
+	}
+	ops := []operation{}
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("the patch is not a list of JSON patch operations: %v", err)
+	}
+	for i, op := range ops {
+		if doc, err = apply(doc, op); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %v", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(doc)
+}
+
+func apply(doc interface{}, op operation) (interface{}, error) {
+	var value interface{}
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("value is required")
+		}
+		v, err := decode(*op.Value)
+		if err != nil {
+			return nil, err
+		}
+		value = v
+	case "move", "copy":
+		v, err := get(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		// a copy must not share objects and lists with the original
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = decode(data); err != nil {
+			return nil, err
+		}
+	}
+
+	switch op.Op {
+	case "add":
+		return add(doc, op.Path, value)
+	case "remove":
+		doc, _, err := remove(doc, op.Path)
+		return doc, err
+	case "replace":
+		doc, _, err := remove(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.Path, value)
+	case "move":
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("a value cannot be moved into itself")
+		}
+		doc, _, err := remove(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.Path, value)
+	case "copy":
+		return add(doc, op.Path, value)
+	case "test":
+		actual, err := get(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(normalize(actual), normalize(value)) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown operation")
+	}
+}
+
+// parsePointer returns the reference tokens of the JSON pointer path.
+func parsePointer(path string) ([]string, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("the path %q must start with /", path)
+	}
+	tokens := strings.Split(path[1:], "/")
+	for i := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(tokens[i], "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// get returns the value at path in doc.
+func get(doc interface{}, path string) (interface{}, error) {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		switch t := doc.(type) {
+		case map[string]interface{}:
+			v, ok := t[token]
+			if !ok {
+				return nil, fmt.Errorf("%s does not exist", path)
+			}
+			doc = v
+		case []interface{}:
+			i, err := index(token, len(t)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = t[i]
+		default:
+			return nil, fmt.Errorf("%s does not exist", path)
+		}
+	}
+	return doc, nil
+}
+
+// add returns doc with value added at path.
+func add(doc interface{}, path string, value interface{}) (interface{}, error) {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	parent, err := get(doc, path[:strings.LastIndex(path, "/")])
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch t := parent.(type) {
+	case map[string]interface{}:
+		t[last] = value
+		return doc, nil
+	case []interface{}:
+		i := len(t)
+		if last != "-" {
+			if i, err = index(last, len(t)); err != nil {
+				return nil, err
+			}
+		}
+		items := append(append(append([]interface{}{}, t[:i]...), value), t[i:]...)
+		return set(doc, tokens[:len(tokens)-1], items), nil
+	default:
+		return nil, fmt.Errorf("the parent of %s is not an object or a list", path)
+	}
+}
+
+// remove returns doc with the value at path removed, and the removed value.
+func remove(doc interface{}, path string) (interface{}, interface{}, error) {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, doc, nil
+	}
+	parent, err := get(doc, path[:strings.LastIndex(path, "/")])
+	if err != nil {
+		return nil, nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch t := parent.(type) {
+	case map[string]interface{}:
+		value, ok := t[last]
+		if !ok {
+			return nil, nil, fmt.Errorf("%s does not exist", path)
+		}
+		delete(t, last)
+		return doc, value, nil
+	case []interface{}:
+		i, err := index(last, len(t)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		value := t[i]
+		items := append(append([]interface{}{}, t[:i]...), t[i+1:]...)
+		return set(doc, tokens[:len(tokens)-1], items), value, nil
+	default:
+		return nil, nil, fmt.Errorf("%s does not exist", path)
+	}
+}
+
+// set returns doc with the value at the location named by tokens, which must exist, replaced by value.
+func set(doc interface{}, tokens []string, value interface{}) interface{} {
+	if len(tokens) == 0 {
+		return value
+	}
+	switch t := doc.(type) {
+	case map[string]interface{}:
+		t[tokens[0]] = set(t[tokens[0]], tokens[1:], value)
+	case []interface{}:
+		i, _ := strconv.Atoi(tokens[0])
+		t[i] = set(t[i], tokens[1:], value)
+	}
+	return doc
+}
+
+// index returns the list index in token, which must be at most max.
+func index(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%q is not a valid index", token)
+	}
+	return i, nil
+}
+
+// decode decodes the JSON in data, keeping numbers as they were written.
+func decode(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// normalize returns v with numbers as float64, so values that are written differently compare equal.
+func normalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		f, _ := t.Float64()
+		return f
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for k, item := range t {
+			m[k] = normalize(item)
+		}
+		return m
+	case []interface{}:
+		items := make([]interface{}, len(t))
+		for i := range t {
+			items[i] = normalize(t[i])
+		}
+		return items
+	default:
+		return v
+	}
+}
@@ -0,0 +1,147 @@
+package patch
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func jsonEqual(t *testing.T, name string, expected string, actual []byte) {
+	var e, a interface{}
+	if err := json.Unmarshal([]byte(expected), &e); err != nil {
+		t.Fatalf("%s: invalid expected JSON: %v", name, err)
+	}
+	if err := json.Unmarshal(actual, &a); err != nil {
+		t.Errorf("%s: invalid JSON %q: %v", name, actual, err)
+		return
+	}
+	if !reflect.DeepEqual(e, a) {
+		t.Errorf("%s: expected %s, got %s", name, expected, actual)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	original := `{"host":"www.example.com","labels":{"app":"ruby","tier":"web"},"ports":[{"name":"http","port":80}],"resourceVersion":12345678901234567}`
+	testCases := map[string]struct {
+		patch    string
+		expected string
+	}{
+		"field": {
+			patch:    `{"host":"ruby.example.com"}`,
+			expected: `{"host":"ruby.example.com","labels":{"app":"ruby","tier":"web"},"ports":[{"name":"http","port":80}],"resourceVersion":12345678901234567}`,
+		},
+		"nested field and removal": {
+			patch:    `{"labels":{"tier":null,"env":"prod"}}`,
+			expected: `{"host":"www.example.com","labels":{"app":"ruby","env":"prod"},"ports":[{"name":"http","port":80}],"resourceVersion":12345678901234567}`,
+		},
+		"lists are replaced": {
+			patch:    `{"ports":[{"name":"https","port":443}]}`,
+			expected: `{"host":"www.example.com","labels":{"app":"ruby","tier":"web"},"ports":[{"name":"https","port":443}],"resourceVersion":12345678901234567}`,
+		},
+	}
+	for name, tc := range testCases {
+		patched, err := MergePatch([]byte(original), []byte(tc.patch))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		jsonEqual(t, name, tc.expected, patched)
+	}
+
+	if _, err := MergePatch([]byte(original), []byte(`["host"]`)); err == nil {
+		t.Errorf("expected an error for a patch that is not an object")
+	}
+}
+
+func TestStrategicMergePatch(t *testing.T) {
+	original := `{"containers":[{"name":"ruby","image":"ruby:2.0","ports":[80]},{"name":"proxy","image":"nginx"}],"triggers":[{"type":"generic"}]}`
+	testCases := map[string]struct {
+		patch    string
+		expected string
+	}{
+		"merge item": {
+			patch:    `{"containers":[{"name":"ruby","image":"ruby:2.2"}]}`,
+			expected: `{"containers":[{"name":"ruby","image":"ruby:2.2","ports":[80]},{"name":"proxy","image":"nginx"}],"triggers":[{"type":"generic"}]}`,
+		},
+		"add item": {
+			patch:    `{"containers":[{"name":"db","image":"mysql"}]}`,
+			expected: `{"containers":[{"name":"ruby","image":"ruby:2.0","ports":[80]},{"name":"proxy","image":"nginx"},{"name":"db","image":"mysql"}],"triggers":[{"type":"generic"}]}`,
+		},
+		"delete item": {
+			patch:    `{"containers":[{"name":"proxy","$patch":"delete"}]}`,
+			expected: `{"containers":[{"name":"ruby","image":"ruby:2.0","ports":[80]}],"triggers":[{"type":"generic"}]}`,
+		},
+		"replace object": {
+			patch:    `{"containers":[{"name":"ruby","image":"ruby:2.2","$patch":"replace"}]}`,
+			expected: `{"containers":[{"name":"ruby","image":"ruby:2.2"},{"name":"proxy","image":"nginx"}],"triggers":[{"type":"generic"}]}`,
+		},
+		"unnamed lists are replaced": {
+			patch:    `{"triggers":[{"type":"imageChange"}]}`,
+			expected: `{"containers":[{"name":"ruby","image":"ruby:2.0","ports":[80]},{"name":"proxy","image":"nginx"}],"triggers":[{"type":"imageChange"}]}`,
+		},
+	}
+	for name, tc := range testCases {
+		patched, err := StrategicMergePatch([]byte(original), []byte(tc.patch))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		jsonEqual(t, name, tc.expected, patched)
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	original := `{"host":"www.example.com","labels":{"a/b":"c"},"triggers":[{"type":"generic"},{"type":"github"}]}`
+	testCases := map[string]struct {
+		patch    string
+		expected string
+		err      bool
+	}{
+		"replace": {
+			patch:    `[{"op":"test","path":"/host","value":"www.example.com"},{"op":"replace","path":"/host","value":"ruby.example.com"}]`,
+			expected: `{"host":"ruby.example.com","labels":{"a/b":"c"},"triggers":[{"type":"generic"},{"type":"github"}]}`,
+		},
+		"add to list": {
+			patch:    `[{"op":"add","path":"/triggers/1","value":{"type":"imageChange"}},{"op":"add","path":"/triggers/-","value":{"type":"configChange"}}]`,
+			expected: `{"host":"www.example.com","labels":{"a/b":"c"},"triggers":[{"type":"generic"},{"type":"imageChange"},{"type":"github"},{"type":"configChange"}]}`,
+		},
+		"remove escaped": {
+			patch:    `[{"op":"remove","path":"/labels/a~1b"},{"op":"remove","path":"/triggers/0"}]`,
+			expected: `{"host":"www.example.com","labels":{},"triggers":[{"type":"github"}]}`,
+		},
+		"move and copy": {
+			patch:    `[{"op":"copy","from":"/triggers/0","path":"/trigger"},{"op":"move","from":"/host","path":"/labels/host"},{"op":"replace","path":"/trigger/type","value":"copied"}]`,
+			expected: `{"labels":{"a/b":"c","host":"www.example.com"},"trigger":{"type":"copied"},"triggers":[{"type":"generic"},{"type":"github"}]}`,
+		},
+		"failed test": {
+			patch: `[{"op":"replace","path":"/host","value":"ruby.example.com"},{"op":"test","path":"/host","value":"www.example.com"}]`,
+			err:   true,
+		},
+		"missing path": {
+			patch: `[{"op":"replace","path":"/missing","value":1}]`,
+			err:   true,
+		},
+		"invalid index": {
+			patch: `[{"op":"remove","path":"/triggers/01"}]`,
+			err:   true,
+		},
+		"unknown operation": {
+			patch: `[{"op":"merge","path":"/host","value":"x"}]`,
+			err:   true,
+		},
+	}
+	for name, tc := range testCases {
+		patched, err := JSONPatch([]byte(original), []byte(tc.patch))
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %s", name, patched)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		jsonEqual(t, name, tc.expected, patched)
+	}
+}
@@ -0,0 +1,69 @@
+package templaterouter
+
+import (
+	"fmt"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+const (
+	// BackendHAProxy is the default router backend, configured by the templates of the haproxy router image
+	BackendHAProxy = "haproxy"
+	// BackendNginx is configured by the templates of the nginx router image
+	BackendNginx = "nginx"
+)
+
+// backend describes what a router backend expects of the files the template router writes besides the
+// rendered templates, and which routes it is able to serve.
+type backend struct {
+	// name selects the backend at router start
+	name string
+	// defaultCertPath is where the certificate served for hosts without a certificate of their own is written
+	defaultCertPath string
+	// separateKeys writes the private key of a route to <host>.key and its certificate chain to <host>.pem,
+	// instead of a single <host>.pem holding both
+	separateKeys bool
+	// statsSocket is true if the backend serves per route statistics on an haproxy admin socket
+	statsSocket bool
+	// tcpPorts is true if the backend can expose routes as raw TCP services on ports of their own
+	tcpPorts bool
+	// unsupportedTerminations are the TLS terminations the backend cannot serve, routes using them are rejected
+	unsupportedTerminations []routeapi.TLSTerminationType
+}
+
+// backends are the router backends known to the template router, by name.
+var backends = map[string]backend{
+	BackendHAProxy: {
+		name:            BackendHAProxy,
+		defaultCertPath: "/var/lib/haproxy/conf/default_pub_keys.pem",
+		statsSocket:     true,
+		tcpPorts:        true,
+	},
+	// nginx selects a certificate per server block, so it reads separate key files, and has no TCP proxying
+	// to pass TLS connections through to the endpoints by SNI host or expose routes on TCP ports
+	BackendNginx: {
+		name:                    BackendNginx,
+		defaultCertPath:         "/var/lib/nginx/conf/default_pub_keys.pem",
+		separateKeys:            true,
+		unsupportedTerminations: []routeapi.TLSTerminationType{routeapi.TLSTerminationPassthrough},
+	},
+}
+
+// lookupBackend returns the backend called name.
+func lookupBackend(name string) (backend, error) {
+	b, ok := backends[name]
+	if !ok {
+		return backend{}, fmt.Errorf("unknown router backend %q", name)
+	}
+	return b, nil
+}
+
+// supportsTermination returns true if the backend can serve routes with the given TLS termination.
+func (b backend) supportsTermination(termination routeapi.TLSTerminationType) bool {
+	for _, unsupported := range b.unsupportedTerminations {
+		if termination == unsupported {
+			return false
+		}
+	}
+	return true
+}
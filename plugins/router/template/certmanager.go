@@ -2,38 +2,63 @@ package templaterouter
 
 import (
 	"bytes"
-	"github.com/golang/glog"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/golang/glog"
 
 	routeapi "github.com/openshift/origin/pkg/route/api"
 )
 
-// certManager is responsible for writing out certificates to the disk for the template router plugin.
-type certManager struct{}
+// certManager is responsible for writing out certificates to the disk for the template router plugin, in the
+// layout its backend expects.
+type certManager struct {
+	backend backend
+}
 
 // writeCertificatesForConfig write certificates for edge and reencrypt termination by appending the key, cert, and ca cert
-// into a single <host>.pem file.  Also write <host>_pod.pem file if it is reencrypt termination
+// into a single <host>.pem file, or the cert and ca cert into <host>.pem and the key into <host>.key for backends with
+// separate keys.  Also write <host>_pod.pem file if it is reencrypt termination.  Certificates that are expired or do
+// not match their key are not written, so the router serves its default certificate instead.
 func (cm *certManager) writeCertificatesForConfig(config *ServiceAliasConfig) error {
 	if len(config.Certificates) > 0 {
 		if config.TLSTermination == routeapi.TLSTerminationEdge || config.TLSTermination == routeapi.TLSTerminationReencrypt {
 			certObj, ok := config.Certificates[config.Host]
 
 			if ok {
-				newLine := []byte("\n")
+				// an invalid certificate is not served, clients are given the default certificate instead
+				if err := validateCertificate(certObj.Contents, certObj.PrivateKey, time.Now()); err != nil {
+					glog.Errorf("Not serving the certificate for %s: %v", config.Host, err)
+					cm.deleteCertificate(certDir, config.Host)
+					if cm.backend.separateKeys {
+						// the server of the host names its certificate files, fill them with the default certificate
+						cm.writeDefaultCertificateFor(config.Host)
+					}
+				} else {
+					newLine := []byte("\n")
+
+					//initialize with key and append the newline and cert
+					buffer := bytes.NewBuffer([]byte(certObj.PrivateKey))
+					buffer.Write(newLine)
+					if cm.backend.separateKeys {
+						cm.writeKey(certDir, config.Host, buffer.Bytes())
+						buffer.Reset()
+					}
+					buffer.Write([]byte(certObj.Contents))
 
-				//initialize with key and append the newline and cert
-				buffer := bytes.NewBuffer([]byte(certObj.PrivateKey))
-				buffer.Write(newLine)
-				buffer.Write([]byte(certObj.Contents))
+					caCertObj, caOk := config.Certificates[config.Host+caCertPostfix]
 
-				caCertObj, caOk := config.Certificates[config.Host+caCertPostfix]
+					if caOk {
+						buffer.Write(newLine)
+						buffer.Write([]byte(caCertObj.Contents))
+					}
 
-				if caOk {
-					buffer.Write(newLine)
-					buffer.Write([]byte(caCertObj.Contents))
+					cm.writeCertificate(certDir, config.Host, buffer.Bytes())
 				}
-
-				cm.writeCertificate(certDir, config.Host, buffer.Bytes())
 			}
 		}
 
@@ -64,6 +89,80 @@ func (cm *certManager) writeCertificate(directory string, id string, cert []byte
 	return nil
 }
 
+// writeKey creates and writes the private key file identified by <id> in <directory>.  The file extension .key
+// will be added to id.
+func (cm *certManager) writeKey(directory string, id string, key []byte) error {
+	fileName := directory + id + ".key"
+	if err := ioutil.WriteFile(fileName, key, 0600); err != nil {
+		glog.Errorf("Error writing key file %v: %v", fileName, err)
+		return err
+	}
+	return nil
+}
+
+// writeDefaultCertificateFor writes the default certificate and its key as the certificate and key files of the
+// host identified by <id>.
+func (cm *certManager) writeDefaultCertificateFor(id string) error {
+	contents, err := ioutil.ReadFile(cm.backend.defaultCertPath)
+	if err != nil {
+		glog.Errorf("Error reading default certificate file %v: %v", cm.backend.defaultCertPath, err)
+		return err
+	}
+	if err := cm.writeCertificate(certDir, id, contents); err != nil {
+		return err
+	}
+	return cm.writeKey(certDir, id, contents)
+}
+
+// writeDefaultCertificate validates the PEM encoded certificate and key in contents and writes them as the
+// certificate served for hosts without a certificate of their own.
+func (cm *certManager) writeDefaultCertificate(contents string) error {
+	if err := validateCertificate(contents, contents, time.Now()); err != nil {
+		return fmt.Errorf("invalid default certificate: %v", err)
+	}
+	if err := ioutil.WriteFile(cm.backend.defaultCertPath, []byte(contents), 0600); err != nil {
+		glog.Errorf("Error writing default certificate file %v: %v", cm.backend.defaultCertPath, err)
+		return err
+	}
+	return nil
+}
+
+// deleteCertificate removes the file identified by <id> in <directory>, and its key file for backends with
+// separate keys, if they exist.
+func (cm *certManager) deleteCertificate(directory string, id string) error {
+	fileNames := []string{directory + id + ".pem"}
+	if cm.backend.separateKeys {
+		fileNames = append(fileNames, directory+id+".key")
+	}
+	for _, fileName := range fileNames {
+		if err := os.Remove(fileName); err != nil && !os.IsNotExist(err) {
+			glog.Errorf("Error deleting certificate file %v: %v", fileName, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCertificate returns an error if the PEM encoded certificate and key do not form a pair, or if the
+// certificate is not valid at now.
+func validateCertificate(certificate, key string, now time.Time) error {
+	pair, err := tls.X509KeyPair([]byte(certificate), []byte(key))
+	if err != nil {
+		return fmt.Errorf("certificate and key do not match: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("unable to parse certificate: %v", err)
+	}
+	if now.Before(leaf.NotBefore) {
+		return fmt.Errorf("certificate is not valid before %s", leaf.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if now.After(leaf.NotAfter) {
+		return fmt.Errorf("certificate expired at %s", leaf.NotAfter.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
 // deleteCertificatesForConfig will delete all certificates for the ServiceAliasConfig
 func (cm *certManager) deleteCertificatesForConfig(config *ServiceAliasConfig) error {
 	//TODO
@@ -0,0 +1,64 @@
+package templaterouter
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// newTestCertificate returns a PEM encoded self signed certificate for host valid between notBefore and
+// notAfter, and its PEM encoded key.
+func newTestCertificate(t *testing.T, host string, notBefore, notAfter time.Time) (string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Unable to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: host},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		DNSNames:     []string{host},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Unable to create certificate: %v", err)
+	}
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return string(cert), string(keyPEM)
+}
+
+func TestValidateCertificate(t *testing.T) {
+	now := time.Now()
+	validCert, validKey := newTestCertificate(t, "www.example.com", now.Add(-time.Hour), now.Add(time.Hour))
+	expiredCert, expiredKey := newTestCertificate(t, "www.example.com", now.Add(-2*time.Hour), now.Add(-time.Hour))
+	futureCert, futureKey := newTestCertificate(t, "www.example.com", now.Add(time.Hour), now.Add(2*time.Hour))
+
+	testCases := map[string]struct {
+		cert, key   string
+		expectError bool
+	}{
+		"valid":          {validCert, validKey, false},
+		"combined":       {validCert + validKey, validCert + validKey, false},
+		"expired":        {expiredCert, expiredKey, true},
+		"not yet valid":  {futureCert, futureKey, true},
+		"mismatched key": {validCert, expiredKey, true},
+		"garbage":        {"abc", "def", true},
+	}
+
+	for name, tc := range testCases {
+		err := validateCertificate(tc.cert, tc.key, now)
+		if tc.expectError && err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		if !tc.expectError && err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package templaterouter
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+// routerStatus tracks the outcome of the configuration changes committed by a templateRouter.
+type routerStatus struct {
+	lock sync.Mutex
+
+	// generation is the number of successful commits
+	generation int
+	// lastCommit is when the last commit finished, successful or not
+	lastCommit time.Time
+	// lastCommitDuration is how long writing the configuration and reloading took
+	lastCommitDuration time.Duration
+	// lastCommitError is the error of the last commit, nil if it succeeded
+	lastCommitError error
+
+	// reloads, reloadFailures, and reloadDuration count every commit that was attempted
+	reloads        int
+	reloadFailures int
+	reloadDuration time.Duration
+	// endpointUpdates counts the endpoint changes applied to the running backend without a reload
+	endpointUpdates int
+
+	serviceUnits int
+	routes       int
+	endpoints    int
+	// backends are the backends of every route in the last successfully loaded configuration
+	backends []routeBackend
+}
+
+// routeBackend identifies the backend generated for a ServiceAliasConfig.
+type routeBackend struct {
+	Host    string
+	Path    string
+	Service string
+	// Name is the name of the backend in the generated configuration
+	Name string
+}
+
+// record stores the result of a commit of state that took duration.
+func (s *routerStatus) record(state map[string]ServiceUnit, duration time.Duration, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.lastCommit = time.Now()
+	s.lastCommitDuration = duration
+	s.lastCommitError = err
+	s.reloads++
+	s.reloadDuration += duration
+	if err != nil {
+		s.reloadFailures++
+		return
+	}
+
+	s.generation++
+	s.serviceUnits = len(state)
+	s.routes, s.endpoints = 0, 0
+	s.backends = []routeBackend{}
+	for id, serviceUnit := range state {
+		s.routes += len(serviceUnit.ServiceAliasConfigs)
+		s.endpoints += len(serviceUnit.EndpointTable)
+		for _, cfg := range serviceUnit.ServiceAliasConfigs {
+			s.backends = append(s.backends, routeBackend{
+				Host:    cfg.Host,
+				Path:    cfg.Path,
+				Service: id,
+				Name:    backendName(id, cfg),
+			})
+		}
+	}
+}
+
+// recordEndpointUpdate stores that the endpoints of state were applied without a reload.
+func (s *routerStatus) recordEndpointUpdate(state map[string]ServiceUnit) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.endpointUpdates++
+	s.endpoints = 0
+	for _, serviceUnit := range state {
+		s.endpoints += len(serviceUnit.EndpointTable)
+	}
+}
+
+// backendName returns the name of the backend the haproxy template generates for cfg in the service unit id.
+func backendName(id string, cfg ServiceAliasConfig) string {
+	if cfg.TCPPort > 0 {
+		return "be_tcp_" + id
+	}
+	switch cfg.TLSTermination {
+	case routeapi.TLSTerminationPassthrough:
+		return "be_tcp_" + id
+	case routeapi.TLSTerminationReencrypt:
+		return "be_secure_" + id
+	}
+	return "be_http_" + id
+}
+
+// healthReport is the body served by the health endpoints.
+type healthReport struct {
+	// Healthy is false if the last reload failed
+	Healthy bool `json:"healthy"`
+	// Ready is true once a configuration has been loaded and the router is healthy
+	Ready bool `json:"ready"`
+	// Generation is the number of configurations successfully loaded
+	Generation int `json:"generation"`
+	// ServiceUnits, Routes, and Endpoints count the contents of the loaded configuration
+	ServiceUnits int `json:"serviceUnits"`
+	Routes       int `json:"routes"`
+	Endpoints    int `json:"endpoints"`
+	// LastReload is when the last reload finished, in RFC 3339 format
+	LastReload string `json:"lastReload,omitempty"`
+	// LastReloadError is the error of the last reload, if it failed
+	LastReloadError string `json:"lastReloadError,omitempty"`
+}
+
+// report returns the current health of the router.
+func (s *routerStatus) report() healthReport {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	report := healthReport{
+		Healthy:      s.lastCommitError == nil,
+		Generation:   s.generation,
+		ServiceUnits: s.serviceUnits,
+		Routes:       s.routes,
+		Endpoints:    s.endpoints,
+	}
+	report.Ready = report.Healthy && s.generation > 0
+	if !s.lastCommit.IsZero() {
+		report.LastReload = s.lastCommit.UTC().Format(time.RFC3339)
+	}
+	if s.lastCommitError != nil {
+		report.LastReloadError = s.lastCommitError.Error()
+	}
+	return report
+}
+
+// healthHandler serves the router health at /healthz, which fails if the last reload failed, and its
+// readiness at /healthz/ready, which fails until a configuration has been loaded.
+func healthHandler(status *routerStatus) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, req *http.Request) {
+		report := status.report()
+		writeHealthReport(w, report, report.Healthy)
+	})
+	mux.HandleFunc("/healthz/ready", func(w http.ResponseWriter, req *http.Request) {
+		report := status.report()
+		writeHealthReport(w, report, report.Ready)
+	})
+	return mux
+}
+
+// writeHealthReport writes report as JSON, with a service unavailable status unless ok.
+func writeHealthReport(w http.ResponseWriter, report healthReport, ok bool) {
+	w.Header().Set("Content-Type", "application/json")
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,55 @@
+package templaterouter
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealthHandler(t *testing.T) {
+	status := &routerStatus{}
+	server := httptest.NewServer(healthHandler(status))
+	defer server.Close()
+
+	check := func(path string, expectedCode int) healthReport {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != expectedCode {
+			t.Errorf("%s: expected status %d, got %d", path, expectedCode, resp.StatusCode)
+		}
+		report := healthReport{}
+		if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+			t.Fatalf("%s: unable to decode report: %v", path, err)
+		}
+		return report
+	}
+
+	// nothing loaded yet
+	check("/healthz", http.StatusOK)
+	check("/healthz/ready", http.StatusServiceUnavailable)
+
+	state := map[string]ServiceUnit{
+		"app": {
+			EndpointTable:       map[string]Endpoint{"1.1.1.1:80": {}, "1.1.1.2:80": {}},
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{"www.example.com-": {}},
+		},
+	}
+	status.record(state, time.Second, nil)
+	report := check("/healthz/ready", http.StatusOK)
+	if report.Generation != 1 || report.ServiceUnits != 1 || report.Routes != 1 || report.Endpoints != 2 || len(report.LastReload) == 0 {
+		t.Errorf("Unexpected report: %#v", report)
+	}
+
+	status.record(state, time.Second, errors.New("reload failed"))
+	report = check("/healthz", http.StatusServiceUnavailable)
+	check("/healthz/ready", http.StatusServiceUnavailable)
+	if report.Generation != 1 || report.LastReloadError != "reload failed" {
+		t.Errorf("Unexpected report: %#v", report)
+	}
+}
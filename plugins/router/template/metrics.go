@@ -0,0 +1,202 @@
+package templaterouter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// serverStats is the state of a single proxy or server as reported by the backend.
+type serverStats struct {
+	// Backend is the name of the backend
+	Backend string
+	// Server is the name of the server, or "BACKEND" for the backend as a whole
+	Server string
+	// Sessions is the total number of sessions handled
+	Sessions int64
+	// Up is true if the backend reports the server as available
+	Up bool
+	// Maintenance is true if the server is disabled, such as the server of a removed endpoint or an empty
+	// server slot
+	Maintenance bool
+}
+
+// statsReader returns the current stats of every backend and server.
+type statsReader func() ([]serverStats, error)
+
+// metricsHandler serves router metrics in the Prometheus text format: reload counts and durations from
+// status and, if readStats is not nil, the session counts and availability of every route backend.
+func metricsHandler(status *routerStatus, readStats statsReader) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+		status.lock.Lock()
+		reloads, failures, duration := status.reloads, status.reloadFailures, status.reloadDuration
+		lastDuration, generation := status.lastCommitDuration, status.generation
+		endpointUpdates := status.endpointUpdates
+		// the backends are shared with other requests, so they are sorted in a copy
+		backends := make([]routeBackend, len(status.backends))
+		copy(backends, status.backends)
+		status.lock.Unlock()
+
+		fmt.Fprintf(w, "# HELP router_reloads_total The number of times the router configuration was reloaded.\n")
+		fmt.Fprintf(w, "# TYPE router_reloads_total counter\n")
+		fmt.Fprintf(w, "router_reloads_total %d\n", reloads)
+		fmt.Fprintf(w, "# HELP router_reload_failures_total The number of router reloads that failed.\n")
+		fmt.Fprintf(w, "# TYPE router_reload_failures_total counter\n")
+		fmt.Fprintf(w, "router_reload_failures_total %d\n", failures)
+		fmt.Fprintf(w, "# HELP router_reload_duration_seconds_total The time spent reloading the router.\n")
+		fmt.Fprintf(w, "# TYPE router_reload_duration_seconds_total counter\n")
+		fmt.Fprintf(w, "router_reload_duration_seconds_total %g\n", duration.Seconds())
+		fmt.Fprintf(w, "# HELP router_last_reload_duration_seconds The time the last reload took.\n")
+		fmt.Fprintf(w, "# TYPE router_last_reload_duration_seconds gauge\n")
+		fmt.Fprintf(w, "router_last_reload_duration_seconds %g\n", lastDuration.Seconds())
+		fmt.Fprintf(w, "# HELP router_config_generation The number of configurations successfully loaded.\n")
+		fmt.Fprintf(w, "# TYPE router_config_generation gauge\n")
+		fmt.Fprintf(w, "router_config_generation %d\n", generation)
+		fmt.Fprintf(w, "# HELP router_endpoint_updates_total The number of endpoint changes applied without a reload.\n")
+		fmt.Fprintf(w, "# TYPE router_endpoint_updates_total counter\n")
+		fmt.Fprintf(w, "router_endpoint_updates_total %d\n", endpointUpdates)
+
+		if readStats == nil {
+			return
+		}
+		stats, err := readStats()
+		if err != nil {
+			glog.Errorf("Unable to read router stats: %v", err)
+			fmt.Fprintf(w, "router_stats_up 0\n")
+			return
+		}
+		fmt.Fprintf(w, "router_stats_up 1\n")
+
+		byBackend := map[string][]serverStats{}
+		for _, s := range stats {
+			byBackend[s.Backend] = append(byBackend[s.Backend], s)
+		}
+
+		sort.Sort(byHostPath(backends))
+		fmt.Fprintf(w, "# HELP router_route_sessions_total The number of sessions handled for a route.\n")
+		fmt.Fprintf(w, "# TYPE router_route_sessions_total counter\n")
+		for _, b := range backends {
+			for _, s := range byBackend[b.Name] {
+				if s.Server == "BACKEND" {
+					fmt.Fprintf(w, "router_route_sessions_total{%s} %d\n", b.labels(), s.Sessions)
+				}
+			}
+		}
+		fmt.Fprintf(w, "# HELP router_route_up Whether the backend of a route is available.\n")
+		fmt.Fprintf(w, "# TYPE router_route_up gauge\n")
+		for _, b := range backends {
+			for _, s := range byBackend[b.Name] {
+				if s.Server == "BACKEND" {
+					fmt.Fprintf(w, "router_route_up{%s} %d\n", b.labels(), boolToInt(s.Up))
+				}
+			}
+		}
+		fmt.Fprintf(w, "# HELP router_route_server_up Whether an endpoint serving a route is available.\n")
+		fmt.Fprintf(w, "# TYPE router_route_server_up gauge\n")
+		for _, b := range backends {
+			for _, s := range byBackend[b.Name] {
+				if s.Server != "BACKEND" && s.Server != "FRONTEND" && !s.Maintenance {
+					fmt.Fprintf(w, "router_route_server_up{%s,server=%q} %d\n", b.labels(), s.Server, boolToInt(s.Up))
+				}
+			}
+		}
+	})
+}
+
+// labels returns the metric labels identifying the route of b.
+func (b routeBackend) labels() string {
+	return fmt.Sprintf("host=%q,path=%q,service=%q", b.Host, b.Path, b.Service)
+}
+
+// byHostPath sorts route backends by host and path so metrics are written in a stable order.
+type byHostPath []routeBackend
+
+func (b byHostPath) Len() int      { return len(b) }
+func (b byHostPath) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byHostPath) Less(i, j int) bool {
+	if b[i].Host != b[j].Host {
+		return b[i].Host < b[j].Host
+	}
+	return b[i].Path < b[j].Path
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// haproxyStatsReader returns a statsReader that reads the "show stat" output of the haproxy admin socket at path.
+func haproxyStatsReader(path string) statsReader {
+	return func() ([]serverStats, error) {
+		conn, err := net.DialTimeout("unix", path, 5*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		if _, err := io.WriteString(conn, "show stat\n"); err != nil {
+			return nil, err
+		}
+		return parseHAProxyStats(conn)
+	}
+}
+
+// parseHAProxyStats parses the CSV output of the haproxy "show stat" command.
+func parseHAProxyStats(r io.Reader) ([]serverStats, error) {
+	reader := bufio.NewReader(r)
+	header, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("unable to read stats header: %v", err)
+	}
+	if !strings.HasPrefix(header, "# ") {
+		return nil, fmt.Errorf("unexpected stats header %q", header)
+	}
+	columns := map[string]int{}
+	for i, name := range strings.Split(strings.TrimSpace(strings.TrimPrefix(header, "# ")), ",") {
+		columns[name] = i
+	}
+	for _, name := range []string{"pxname", "svname", "stot", "status"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("stats are missing the %s column", name)
+		}
+	}
+
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	stats := []serverStats{}
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) <= columns["status"] || len(record) <= columns["stot"] {
+			continue
+		}
+		sessions, _ := strconv.ParseInt(record[columns["stot"]], 10, 64)
+		stats = append(stats, serverStats{
+			Backend:     record[columns["pxname"]],
+			Server:      record[columns["svname"]],
+			Sessions:    sessions,
+			Up:          strings.HasPrefix(record[columns["status"]], "UP") || record[columns["status"]] == "OPEN",
+			Maintenance: strings.HasPrefix(record[columns["status"]], "MAINT"),
+		})
+	}
+	return stats, nil
+}
@@ -0,0 +1,92 @@
+package templaterouter
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testStats = `# pxname,svname,qcur,qmax,scur,smax,slim,stot,bin,bout,dreq,dresp,ereq,econ,eresp,wretr,wredis,status,
+public,FRONTEND,,,0,1,2000,10,0,0,0,0,0,,,,,OPEN,
+be_http_app,1.1.1.1:8080,0,0,0,1,,7,0,0,,0,,0,0,0,0,UP,
+be_http_app,1.1.1.2:8080,0,0,0,1,,3,0,0,,0,,0,0,0,0,DOWN,
+be_http_app,_slot_app_1,0,0,0,0,,0,0,0,,0,,0,0,0,0,MAINT,
+be_http_app,BACKEND,0,0,0,1,200,10,0,0,0,0,,0,0,0,0,UP,
+`
+
+func TestParseHAProxyStats(t *testing.T) {
+	stats, err := parseHAProxyStats(strings.NewReader(testStats))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []serverStats{
+		{Backend: "public", Server: "FRONTEND", Sessions: 10, Up: true},
+		{Backend: "be_http_app", Server: "1.1.1.1:8080", Sessions: 7, Up: true},
+		{Backend: "be_http_app", Server: "1.1.1.2:8080", Sessions: 3, Up: false},
+		{Backend: "be_http_app", Server: "_slot_app_1", Sessions: 0, Up: false, Maintenance: true},
+		{Backend: "be_http_app", Server: "BACKEND", Sessions: 10, Up: true},
+	}
+	if len(stats) != len(expected) {
+		t.Fatalf("Expected %#v, got %#v", expected, stats)
+	}
+	for i := range expected {
+		if stats[i] != expected[i] {
+			t.Errorf("Expected %#v, got %#v", expected[i], stats[i])
+		}
+	}
+
+	if _, err := parseHAProxyStats(strings.NewReader("pxname,svname\n")); err == nil {
+		t.Errorf("Expected an error for output without a header")
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	status := &routerStatus{}
+	state := map[string]ServiceUnit{
+		"app": {
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{
+				"www.example.com-": {Host: "www.example.com"},
+			},
+		},
+	}
+	status.record(state, 2*time.Second, nil)
+
+	readStats := func() ([]serverStats, error) {
+		return parseHAProxyStats(strings.NewReader(testStats))
+	}
+	server := httptest.NewServer(metricsHandler(status, readStats))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{
+		"router_reloads_total 1\n",
+		"router_reload_failures_total 0\n",
+		"router_last_reload_duration_seconds 2\n",
+		"router_config_generation 1\n",
+		"router_stats_up 1\n",
+		`router_route_sessions_total{host="www.example.com",path="",service="app"} 10` + "\n",
+		`router_route_up{host="www.example.com",path="",service="app"} 1` + "\n",
+		`router_route_server_up{host="www.example.com",path="",service="app",server="1.1.1.1:8080"} 1` + "\n",
+		`router_route_server_up{host="www.example.com",path="",service="app",server="1.1.1.2:8080"} 0` + "\n",
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("Expected metrics to contain %q:\n%s", expected, body)
+		}
+	}
+	if strings.Contains(string(body), "_slot_app_1") {
+		t.Errorf("Expected disabled servers to be left out of the metrics:\n%s", body)
+	}
+}
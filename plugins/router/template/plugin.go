@@ -2,20 +2,49 @@ package templaterouter
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 	"github.com/golang/glog"
 
 	routeapi "github.com/openshift/origin/pkg/route/api"
+	"github.com/openshift/origin/pkg/router/controller"
 )
 
 // TemplatePlugin implements the router.Plugin interface to provide
 // a template based, backend-agnostic router.
 type TemplatePlugin struct {
 	Router router
+	// Recorder is notified when a route is exposed or removed because another route released or took over
+	// its TCP port, outside of the route's own event.  It may be nil.
+	Recorder controller.RouteStatusRecorder
+	// ReloadInterval is the minimum time between commits of the router.  Changes made in between
+	// are batched into a single commit.  Every change is committed immediately if zero.
+	ReloadInterval time.Duration
+
+	// status is the state of the router reported by HealthHandler
+	status *routerStatus
+	// backend is the router backend configured by the templates
+	backend backend
+	// ports holds the ports claimed by routes exposed as raw TCP services, nil if the router exposes none
+	ports *portPool
+	// templates is where the templates of the router are loaded from
+	templates templateSource
+	// templatesVersion identifies the template files the router renders with
+	templatesVersion string
+
+	// lock serializes changes to the router with delayed commits
+	lock sync.Mutex
+	// commitPending is true if a delayed commit is scheduled
+	commitPending bool
+	// lastCommit is when the router was last committed
+	lastCommit time.Time
 }
 
 // router controls the interaction of the plugin with the underlying router implementation
@@ -42,27 +71,125 @@ type router interface {
 
 	// Commit refreshes the backend and persists the router state.
 	Commit() error
+
+	// SetTemplates replaces the templates the configuration is rendered with.  The next commit
+	// reloads the backend even if the state is unchanged.
+	SetTemplates(templates map[string]*template.Template)
+	// Reconcile drops the state restored from a snapshot that is missing from the first lists of
+	// routes and endpoints and has not changed since.
+	Reconcile(routes []routeapi.Route, endpoints []kapi.Endpoints)
 }
 
-// NewTemplatePlugin creates a new TemplatePlugin.
-func NewTemplatePlugin(templatePath, reloadScriptPath string) (*TemplatePlugin, error) {
-	masterTemplate := template.Must(template.New("config").ParseFiles(templatePath))
-	templates := map[string]*template.Template{}
+// NewTemplatePlugin creates a new TemplatePlugin that reloads the router at most once every reloadInterval.
+// The templates are parsed from templatePath and then from the files of templateDir, either of which may be
+// empty; templates defined in templateDir replace those of the same name.  If defaultCertificate is not
+// empty it holds the PEM encoded certificate and key served for hosts that have no certificate of their own.
+// backendName selects the router backend the templates configure, one of BackendHAProxy and BackendNginx.
+// If runtimeSocket is not empty it is the haproxy admin socket changes to endpoints alone are applied through,
+// without a reload.  New endpoints are set in the server slots the templates reserve with serverSlots, and the
+// backend is reloaded once a service unit has more new endpoints than slots.
+func NewTemplatePlugin(templatePath, templateDir, reloadScriptPath string, reloadInterval time.Duration, defaultCertificate, backendName, runtimeSocket string) (*TemplatePlugin, error) {
+	backend, err := lookupBackend(backendName)
+	if err != nil {
+		return nil, err
+	}
+	if len(runtimeSocket) > 0 && !backend.statsSocket {
+		return nil, fmt.Errorf("the %s router backend has no admin socket to update endpoints through", backend.name)
+	}
+	source := templateSource{file: templatePath, dir: templateDir}
+	version, err := source.version()
+	if err != nil {
+		return nil, err
+	}
+	templates, err := source.load()
+	if err != nil {
+		return nil, err
+	}
+
+	router, err := newTemplateRouter(templates, reloadScriptPath, defaultCertificate, backend, runtimeSocket)
+	plugin := &TemplatePlugin{
+		Router:           router,
+		ReloadInterval:   reloadInterval,
+		status:           &router.status,
+		backend:          backend,
+		templates:        source,
+		templatesVersion: version,
+	}
+	return plugin, err
+}
 
-	for _, template := range masterTemplate.Templates() {
-		if template == masterTemplate {
-			continue
+// WatchTemplates checks the template files of the router for changes every interval until stop is
+// closed, and commits the router with the new templates when they change.  The router keeps its
+// current templates if the new ones cannot be parsed.
+func (p *TemplatePlugin) WatchTemplates(interval time.Duration, stop <-chan struct{}) {
+	go util.Until(func() {
+		if err := p.checkTemplates(); err != nil {
+			glog.Errorf("Unable to reload the router templates: %v", err)
 		}
+	}, interval, stop)
+}
 
-		templates[template.Name()] = template
+// checkTemplates reloads the templates of the router if their files changed since they were loaded.
+func (p *TemplatePlugin) checkTemplates() error {
+	version, err := p.templates.version()
+	if err != nil {
+		return err
 	}
 
-	router, err := newTemplateRouter(templates, reloadScriptPath)
-	return &TemplatePlugin{router}, err
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if version == p.templatesVersion {
+		return nil
+	}
+	// the files are only read again once they change, even if they cannot be parsed
+	p.templatesVersion = version
+	templates, err := p.templates.load()
+	if err != nil {
+		return err
+	}
+
+	glog.Infof("Router templates changed, reloading the router")
+	p.Router.SetTemplates(templates)
+	return p.commit()
+}
+
+// ExposeTCPPorts allows routes to claim the ports in portRange, such as "10000-10999", to be exposed as raw
+// TCP services.
+func (p *TemplatePlugin) ExposeTCPPorts(portRange string) error {
+	if !p.backend.tcpPorts {
+		return fmt.Errorf("the %s router cannot expose TCP ports", p.backend.name)
+	}
+	min, max, err := parsePortRange(portRange)
+	if err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.ports = newPortPool(min, max)
+	return nil
+}
+
+// HealthHandler returns a handler serving the health and readiness of the router.
+func (p *TemplatePlugin) HealthHandler() http.Handler {
+	return healthHandler(p.status)
+}
+
+// MetricsHandler returns a handler serving router metrics.  Per route metrics are read from the haproxy
+// admin socket at statsSocket, and are omitted if it is empty or the backend has no such socket.
+func (p *TemplatePlugin) MetricsHandler(statsSocket string) http.Handler {
+	var readStats statsReader
+	if len(statsSocket) > 0 && p.backend.statsSocket {
+		readStats = haproxyStatsReader(statsSocket)
+	}
+	return metricsHandler(p.status, readStats)
 }
 
 // HandleEndpoints processes watch events on the Endpoints resource.
 func (p *TemplatePlugin) HandleEndpoints(eventType watch.EventType, endpoints *kapi.Endpoints) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
 	key := endpointsKey(*endpoints)
 
 	glog.V(4).Infof("Processing %d Endpoints for Name: %v (%v)", len(endpoints.Endpoints), endpoints.Name, eventType)
@@ -86,11 +213,24 @@ func (p *TemplatePlugin) HandleEndpoints(eventType watch.EventType, endpoints *k
 		p.Router.AddEndpoints(key, routerEndpoints)
 	}
 
-	return p.Router.Commit()
+	return p.commit()
+}
+
+// Reconcile drops the routes and endpoints the router restored from its last known configuration that no
+// longer exist, now that the routes and endpoints of its shard have been listed.
+func (p *TemplatePlugin) Reconcile(routes []routeapi.Route, endpoints []kapi.Endpoints) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.Router.Reconcile(routes, endpoints)
+	return p.commit()
 }
 
 // HandleRoute processes watch events on the Route resource.
 func (p *TemplatePlugin) HandleRoute(eventType watch.EventType, route *routeapi.Route) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
 	key := routeKey(*route)
 	if _, ok := p.Router.FindServiceUnit(key); !ok {
 		glog.V(4).Infof("Creating new frontend for key: %v", key)
@@ -99,14 +239,162 @@ func (p *TemplatePlugin) HandleRoute(eventType watch.EventType, route *routeapi.
 
 	switch eventType {
 	case watch.Added, watch.Modified:
+		if route.TLS != nil && !p.backend.supportsTermination(route.TLS.Termination) {
+			return p.rejectRoute(key, route, "UnsupportedTermination", fmt.Errorf("%s termination is not supported by the %s router", route.TLS.Termination, p.backend.name))
+		}
+		if err := validateRouteCertificate(route); err != nil {
+			return p.rejectRoute(key, route, "InvalidCertificate", err)
+		}
+		if err := p.allocateTCPPort(route); err != nil {
+			// a route waiting for a port claimed by an older route is exposed once the port is released
+			return p.removeRoute(key, route, "TCPPortUnavailable", err)
+		}
+
 		glog.V(4).Infof("Modifying routes for %s", key)
-		p.Router.AddRoute(key, route)
+		p.addRoute(key, route)
 	case watch.Deleted:
 		glog.V(4).Infof("Deleting routes for %s", key)
+		p.releaseTCPPort(routeName(route))
 		p.Router.RemoveRoute(key, route)
 	}
 
-	return p.Router.Commit()
+	return p.commit()
+}
+
+// addRoute adds route to the router under key.  Must be called with p.lock held.
+func (p *TemplatePlugin) addRoute(key string, route *routeapi.Route) {
+	// alternate backends receive traffic from the endpoints of their own service units
+	for _, backend := range route.AlternateBackends {
+		if _, ok := p.Router.FindServiceUnit(backend.ServiceName); !ok {
+			glog.V(4).Infof("Creating new frontend for alternate backend: %v", backend.ServiceName)
+			p.Router.CreateServiceUnit(backend.ServiceName)
+		}
+	}
+	p.Router.AddRoute(key, route)
+}
+
+// rejectRoute releases the TCP port claimed by route, removes route from the router and returns a
+// *controller.RouteRejectedError for err.  Must be called with p.lock held.
+func (p *TemplatePlugin) rejectRoute(key string, route *routeapi.Route, reason string, err error) error {
+	p.releaseTCPPort(routeName(route))
+	return p.removeRoute(key, route, reason, err)
+}
+
+// removeRoute removes route from the router and returns a *controller.RouteRejectedError for err.  Must
+// be called with p.lock held.
+func (p *TemplatePlugin) removeRoute(key string, route *routeapi.Route, reason string, err error) error {
+	glog.V(4).Infof("Rejecting route %s: %v", routeName(route), err)
+	p.Router.RemoveRoute(key, route)
+	if commitErr := p.commit(); commitErr != nil {
+		return commitErr
+	}
+	return &controller.RouteRejectedError{Reason: reason, Message: err.Error()}
+}
+
+// allocateTCPPort claims the TCP port route asks to be exposed on, releasing any other port it claimed.
+// An older route claiming the port takes it over from the route that owned it.  Must be called with
+// p.lock held.
+func (p *TemplatePlugin) allocateTCPPort(route *routeapi.Route) error {
+	name := routeName(route)
+	port, err := routeapi.RouteTCPPort(route)
+	if err != nil {
+		p.releaseTCPPort(name)
+		return err
+	}
+	if current, ok := p.ports.port(name); ok && current != port {
+		p.releaseTCPPort(name)
+	}
+	if port == 0 {
+		return nil
+	}
+	previous := p.ports.owner(port)
+	err = p.ports.claim(route, port)
+	p.handOverTCPPort(port, previous, name)
+	return err
+}
+
+// releaseTCPPort gives up the TCP port claimed by the named route, if any, exposing the route that owns
+// the port next.  Must be called with p.lock held.
+func (p *TemplatePlugin) releaseTCPPort(name string) {
+	port, ok := p.ports.port(name)
+	if !ok {
+		return
+	}
+	previous := p.ports.owner(port)
+	p.ports.release(name)
+	p.handOverTCPPort(port, previous, name)
+}
+
+// handOverTCPPort removes the route that owned port before, previous, from the router and exposes the
+// route that owns the port now, if the owner changed.  The named route, whose claim changed, is left to
+// its own event.  Must be called with p.lock held.
+func (p *TemplatePlugin) handOverTCPPort(port int, previous *routeapi.Route, name string) {
+	owner := p.ports.owner(port)
+	if owner == nil || (previous != nil && routeName(previous) == routeName(owner)) {
+		return
+	}
+	message := fmt.Sprintf("port %d is already claimed by route %s", port, routeName(owner))
+	if previous != nil && routeName(previous) != name {
+		glog.V(4).Infof("Route %s displaced from port %d by older route %s", routeName(previous), port, routeName(owner))
+		p.Router.RemoveRoute(routeKey(*previous), previous)
+		if p.Recorder != nil {
+			p.Recorder.RecordRouteRejection(previous, "TCPPortUnavailable", message)
+		}
+	}
+	if routeName(owner) != name {
+		glog.V(4).Infof("Route %s now owns port %d", routeName(owner), port)
+		p.addRoute(routeKey(*owner), owner)
+		if p.Recorder != nil {
+			p.Recorder.RecordRouteAdmission(owner)
+		}
+	}
+}
+
+// commit commits the router unless it was committed within ReloadInterval, in which case a single
+// commit is scheduled for when the interval has passed.  Must be called with p.lock held.
+func (p *TemplatePlugin) commit() error {
+	if p.ReloadInterval == 0 {
+		return p.Router.Commit()
+	}
+	if p.commitPending {
+		return nil
+	}
+
+	wait := p.ReloadInterval - time.Since(p.lastCommit)
+	if wait <= 0 {
+		p.lastCommit = time.Now()
+		return p.Router.Commit()
+	}
+
+	glog.V(4).Infof("Delaying router commit for %v", wait)
+	p.commitPending = true
+	time.AfterFunc(wait, p.flush)
+	return nil
+}
+
+// flush performs a delayed commit.
+func (p *TemplatePlugin) flush() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.commitPending = false
+	p.lastCommit = time.Now()
+	if err := p.Router.Commit(); err != nil {
+		glog.Errorf("Unable to commit router changes: %v", err)
+	}
+}
+
+// validateRouteCertificate returns an error if the route terminates TLS in the router with a certificate
+// that is expired or does not match its key.  Routes without a certificate are served the default certificate.
+func validateRouteCertificate(route *routeapi.Route) error {
+	if route.TLS == nil || len(route.TLS.Certificate) == 0 {
+		return nil
+	}
+	switch route.TLS.Termination {
+	case routeapi.TLSTerminationEdge, routeapi.TLSTerminationReencrypt:
+		return validateCertificate(route.TLS.Certificate, route.TLS.Key, time.Now())
+	}
+	return nil
 }
 
 // TODO: the internal keys for routes and endpoints should be namespaced.  Currently
@@ -118,6 +406,11 @@ func routeKey(route routeapi.Route) string {
 	return route.ServiceName
 }
 
+// routeName returns the namespace qualified name of route.
+func routeName(route *routeapi.Route) string {
+	return route.Namespace + "/" + route.Name
+}
+
 // endpointsKey returns the internal router key to use for the given Endpoints.
 func endpointsKey(endpoints kapi.Endpoints) string {
 	return endpoints.Name
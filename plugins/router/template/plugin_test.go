@@ -3,18 +3,26 @@ package templaterouter
 import (
 	"reflect"
 	"testing"
+	"text/template"
+	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	routeapi "github.com/openshift/origin/pkg/route/api"
+	"github.com/openshift/origin/pkg/router/controller"
 )
 
 // TestRouter provides an implementation of the plugin's router interface suitable for unit testing.
 type TestRouter struct {
 	State         map[string]ServiceUnit
 	Committed     bool
+	Commits       int
 	ErrorOnCommit error
+	Templates     map[string]*template.Template
+	// Reconciled holds the number of routes and endpoints the router was reconciled with
+	Reconciled []int
 }
 
 // NewTestRouter creates a new TestRouter and registers the initial state.
@@ -100,9 +108,22 @@ func (r *TestRouter) routeKey(route *routeapi.Route) string {
 // Commit saves router state
 func (r *TestRouter) Commit() error {
 	r.Committed = true
+	r.Commits++
 	return r.ErrorOnCommit
 }
 
+// SetTemplates records the templates the router renders with
+func (r *TestRouter) SetTemplates(templates map[string]*template.Template) {
+	r.Committed = false //expect any call to this method to subsequently call commit
+	r.Templates = templates
+}
+
+// Reconcile records the number of routes and endpoints the router was reconciled with
+func (r *TestRouter) Reconcile(routes []routeapi.Route, endpoints []kapi.Endpoints) {
+	r.Committed = false //expect any call to this method to subsequently call commit
+	r.Reconciled = []int{len(routes), len(endpoints)}
+}
+
 // TestHandleEndpoints test endpoint watch events
 func TestHandleEndpoints(t *testing.T) {
 	testCases := []struct {
@@ -275,6 +296,116 @@ func TestHandleRoute(t *testing.T) {
 
 }
 
+// TestHandleRouteBatchesCommits tests that changes within the reload interval are committed together
+func TestHandleRouteBatchesCommits(t *testing.T) {
+	router := newTestRouter(make(map[string]ServiceUnit))
+	plugin := &TemplatePlugin{Router: router, ReloadInterval: 50 * time.Millisecond}
+	commits := func() int {
+		plugin.lock.Lock()
+		defer plugin.lock.Unlock()
+		return router.Commits
+	}
+
+	for _, host := range []string{"a.example.com", "b.example.com", "c.example.com"} {
+		route := &routeapi.Route{Host: host, ServiceName: "TestService"}
+		if err := plugin.HandleRoute(watch.Added, route); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if commits() != 1 {
+		t.Errorf("Expected only the first change to be committed immediately, got %d commits", commits())
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if commits() != 2 {
+		t.Errorf("Expected the remaining changes to be committed together, got %d commits", commits())
+	}
+	su, _ := router.FindServiceUnit("TestService")
+	if len(su.ServiceAliasConfigs) != 3 {
+		t.Errorf("Expected all routes to be added, got %#v", su.ServiceAliasConfigs)
+	}
+}
+
+// TestHandleRouteInvalidCertificate tests that routes with an expired certificate are rejected
+func TestHandleRouteInvalidCertificate(t *testing.T) {
+	router := newTestRouter(make(map[string]ServiceUnit))
+	plugin := TemplatePlugin{Router: router}
+
+	now := time.Now()
+	cert, key := newTestCertificate(t, "www.example.com", now.Add(-2*time.Hour), now.Add(-time.Hour))
+	route := &routeapi.Route{
+		Host:        "www.example.com",
+		ServiceName: "TestService",
+		TLS: &routeapi.TLSConfig{
+			Termination: routeapi.TLSTerminationEdge,
+			Certificate: cert,
+			Key:         key,
+		},
+	}
+
+	err := plugin.HandleRoute(watch.Added, route)
+	if rejected, ok := err.(*controller.RouteRejectedError); !ok || rejected.Reason != "InvalidCertificate" {
+		t.Fatalf("Expected the route to be rejected, got %v", err)
+	}
+	su, _ := router.FindServiceUnit("TestService")
+	if len(su.ServiceAliasConfigs) != 0 {
+		t.Errorf("Expected the rejected route not to be added, got %#v", su.ServiceAliasConfigs)
+	}
+}
+
+// TestHandleRouteUnsupportedTermination tests that routes with a termination the backend cannot serve are rejected
+func TestHandleRouteUnsupportedTermination(t *testing.T) {
+	router := newTestRouter(make(map[string]ServiceUnit))
+	plugin := TemplatePlugin{Router: router, backend: backends[BackendNginx]}
+
+	route := &routeapi.Route{
+		Host:        "www.example.com",
+		ServiceName: "TestService",
+		TLS:         &routeapi.TLSConfig{Termination: routeapi.TLSTerminationPassthrough},
+	}
+
+	err := plugin.HandleRoute(watch.Added, route)
+	if rejected, ok := err.(*controller.RouteRejectedError); !ok || rejected.Reason != "UnsupportedTermination" {
+		t.Fatalf("Expected the route to be rejected, got %v", err)
+	}
+	su, _ := router.FindServiceUnit("TestService")
+	if len(su.ServiceAliasConfigs) != 0 {
+		t.Errorf("Expected the rejected route not to be added, got %#v", su.ServiceAliasConfigs)
+	}
+
+	route.TLS.Termination = routeapi.TLSTerminationEdge
+	if err := plugin.HandleRoute(watch.Added, route); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	su, _ = router.FindServiceUnit("TestService")
+	if len(su.ServiceAliasConfigs) != 1 {
+		t.Errorf("Expected the edge route to be added, got %#v", su.ServiceAliasConfigs)
+	}
+}
+
+// TestExposeTCPPortsUnsupported tests that backends without raw TCP frontends cannot expose TCP ports
+func TestExposeTCPPortsUnsupported(t *testing.T) {
+	plugin := TemplatePlugin{Router: newTestRouter(make(map[string]ServiceUnit)), backend: backends[BackendNginx]}
+	if err := plugin.ExposeTCPPorts("10000-10010"); err == nil {
+		t.Errorf("Expected the nginx backend to refuse TCP ports")
+	}
+}
+
+// TestNewTemplatePluginUnknownBackend tests that only known backends can be selected
+func TestNewTemplatePluginUnknownBackend(t *testing.T) {
+	if _, err := NewTemplatePlugin("template", "", "reload", 0, "", "apache", ""); err == nil {
+		t.Errorf("Expected an unknown backend to be rejected")
+	}
+}
+
+// TestNewTemplatePluginRuntimeSocketUnsupported tests that endpoints are only updated through the admin
+// socket of backends that have one
+func TestNewTemplatePluginRuntimeSocketUnsupported(t *testing.T) {
+	if _, err := NewTemplatePlugin("template", "", "reload", 0, "", BackendNginx, "/var/lib/nginx/run/admin.sock"); err == nil {
+		t.Errorf("Expected the nginx backend to refuse dynamic endpoints")
+	}
+}
+
 // TestEndpointFromString test creation of endpoint from a string
 func TestEndpointFromString(t *testing.T) {
 	endpointFromStringTestCases := map[string]struct {
@@ -319,3 +450,120 @@ func TestEndpointFromString(t *testing.T) {
 		}
 	}
 }
+
+// TestHandleRouteTCPPort tests that routes claim the TCP ports they ask for and that conflicting or
+// out of range claims are rejected
+func TestHandleRouteTCPPort(t *testing.T) {
+	router := newTestRouter(make(map[string]ServiceUnit))
+	plugin := TemplatePlugin{Router: router, backend: backends[BackendHAProxy]}
+
+	newRoute := func(namespace, port string) *routeapi.Route {
+		route := &routeapi.Route{
+			Host:        "db.example.com",
+			ServiceName: "db",
+		}
+		route.Namespace = namespace
+		route.Name = "db"
+		route.Annotations = map[string]string{routeapi.RouteTCPPortAnnotation: port}
+		return route
+	}
+
+	err := plugin.HandleRoute(watch.Added, newRoute("first", "10000"))
+	if rejected, ok := err.(*controller.RouteRejectedError); !ok || rejected.Reason != "TCPPortUnavailable" {
+		t.Fatalf("Expected the route to be rejected by a router without TCP ports, got %v", err)
+	}
+
+	if err := plugin.ExposeTCPPorts("10000-10010"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := plugin.HandleRoute(watch.Added, newRoute("first", "10000")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	su, _ := router.FindServiceUnit("db")
+	if len(su.ServiceAliasConfigs) != 1 {
+		t.Fatalf("Expected the route to be added, got %#v", su.ServiceAliasConfigs)
+	}
+
+	for _, port := range []string{"10000", "20000"} {
+		err := plugin.HandleRoute(watch.Added, newRoute("second", port))
+		if rejected, ok := err.(*controller.RouteRejectedError); !ok || rejected.Reason != "TCPPortUnavailable" {
+			t.Errorf("Expected port %s to be rejected, got %v", port, err)
+		}
+	}
+
+	if err := plugin.HandleRoute(watch.Deleted, newRoute("first", "10000")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := plugin.HandleRoute(watch.Added, newRoute("second", "10000")); err != nil {
+		t.Errorf("Expected the released port to be claimed, got %v", err)
+	}
+}
+
+// fakeRecorder records the routes admitted and rejected outside of their own events.
+type fakeRecorder struct {
+	admitted, rejected []string
+}
+
+func (r *fakeRecorder) RecordRouteAdmission(route *routeapi.Route) {
+	r.admitted = append(r.admitted, route.Namespace)
+}
+
+func (r *fakeRecorder) RecordRouteRejection(route *routeapi.Route, reason, message string) {
+	r.rejected = append(r.rejected, route.Namespace)
+}
+
+// TestHandleRouteTCPPortOlderRoute tests that an older route takes over the TCP port of a newer route,
+// and that the newer route is exposed again once the older route releases the port
+func TestHandleRouteTCPPortOlderRoute(t *testing.T) {
+	router := newTestRouter(make(map[string]ServiceUnit))
+	recorder := &fakeRecorder{}
+	plugin := TemplatePlugin{Router: router, Recorder: recorder, backend: backends[BackendHAProxy]}
+	if err := plugin.ExposeTCPPorts("10000-10010"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	newRoute := func(namespace string, age int) *routeapi.Route {
+		route := &routeapi.Route{
+			Host:        namespace + ".example.com",
+			ServiceName: namespace,
+		}
+		route.Namespace = namespace
+		route.Name = "db"
+		route.CreationTimestamp = util.Date(2015, 1, 1, 0, age, 0, 0, time.UTC)
+		route.Annotations = map[string]string{routeapi.RouteTCPPortAnnotation: "10000"}
+		return route
+	}
+	exposed := func(route *routeapi.Route) bool {
+		su, _ := router.FindServiceUnit(route.ServiceName)
+		return len(su.ServiceAliasConfigs) == 1
+	}
+	older, newer := newRoute("older", 0), newRoute("newer", 1)
+
+	if err := plugin.HandleRoute(watch.Added, newer); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := plugin.HandleRoute(watch.Added, older); err != nil {
+		t.Fatalf("Expected the older route to take the port over, got %v", err)
+	}
+	if !exposed(older) || exposed(newer) {
+		t.Errorf("Expected only the older route to be exposed, got %#v", router.State)
+	}
+	if !reflect.DeepEqual(recorder.rejected, []string{"newer"}) {
+		t.Errorf("Expected the newer route to be rejected, got %v", recorder.rejected)
+	}
+
+	err := plugin.HandleRoute(watch.Modified, newer)
+	if rejected, ok := err.(*controller.RouteRejectedError); !ok || rejected.Reason != "TCPPortUnavailable" {
+		t.Errorf("Expected the newer route to wait for the port, got %v", err)
+	}
+
+	if err := plugin.HandleRoute(watch.Deleted, older); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if exposed(older) || !exposed(newer) {
+		t.Errorf("Expected the newer route to be exposed once the port was released, got %#v", router.State)
+	}
+	if !reflect.DeepEqual(recorder.admitted, []string{"newer"}) {
+		t.Errorf("Expected the newer route to be admitted, got %v", recorder.admitted)
+	}
+}
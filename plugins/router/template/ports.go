@@ -0,0 +1,127 @@
+package templaterouter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+// portPool tracks the ports of a range that routes have claimed for their raw TCP frontends.  A port
+// belongs to the oldest route claiming it, as ordered by routeapi.RouteOlderThan, and newer routes wait
+// for the port until every older claim is released.  A nil portPool holds no ports.
+type portPool struct {
+	// min and max bound the ports routes may claim
+	min, max int
+	// claims holds every route claiming each port, oldest first
+	claims map[int][]*routeapi.Route
+	// ports holds the port claimed by each route, keyed by namespace and name
+	ports map[string]int
+}
+
+// newPortPool returns a portPool for the ports between min and max inclusive.
+func newPortPool(min, max int) *portPool {
+	return &portPool{
+		min:    min,
+		max:    max,
+		claims: make(map[int][]*routeapi.Route),
+		ports:  make(map[string]int),
+	}
+}
+
+// parsePortRange parses a port range such as "10000-10999" or a single port such as "5432".
+func parsePortRange(value string) (int, int, error) {
+	parts := strings.SplitN(value, "-", 2)
+	min, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port range %q: %v", value, err)
+	}
+	max := min
+	if len(parts) == 2 {
+		if max, err = strconv.Atoi(strings.TrimSpace(parts[1])); err != nil {
+			return 0, 0, fmt.Errorf("invalid port range %q: %v", value, err)
+		}
+	}
+	if min < 1 || max > 65535 || min > max {
+		return 0, 0, fmt.Errorf("invalid port range %q: ports must be between 1 and 65535 and the range must not be empty", value)
+	}
+	return min, max, nil
+}
+
+// claim records route as claiming port, replacing any earlier version of the route.  An error is
+// returned if the port is outside the pool, in which case the claim is not recorded, or if the port is
+// owned by an older route, in which case route waits for the port.  A route must release the port it
+// claims before it claims another.
+func (p *portPool) claim(route *routeapi.Route, port int) error {
+	if p == nil {
+		return fmt.Errorf("the router does not expose TCP ports")
+	}
+	if port < p.min || port > p.max {
+		return fmt.Errorf("port %d is outside the router's TCP port range %d-%d", port, p.min, p.max)
+	}
+
+	name := routeName(route)
+	routes := []*routeapi.Route{route}
+	for _, existing := range p.claims[port] {
+		if routeName(existing) != name {
+			routes = append(routes, existing)
+		}
+	}
+	sort.Sort(byAge(routes))
+	p.claims[port] = routes
+	p.ports[name] = port
+
+	if owner := routes[0]; routeName(owner) != name {
+		return fmt.Errorf("port %d is already claimed by route %s", port, routeName(owner))
+	}
+	return nil
+}
+
+// release gives up the claim of the named route, if any.
+func (p *portPool) release(name string) {
+	if p == nil {
+		return
+	}
+	port, ok := p.ports[name]
+	if !ok {
+		return
+	}
+	delete(p.ports, name)
+	routes := []*routeapi.Route{}
+	for _, existing := range p.claims[port] {
+		if routeName(existing) != name {
+			routes = append(routes, existing)
+		}
+	}
+	if len(routes) == 0 {
+		delete(p.claims, port)
+		return
+	}
+	p.claims[port] = routes
+}
+
+// port returns the port claimed by the named route, if any.
+func (p *portPool) port(name string) (int, bool) {
+	if p == nil {
+		return 0, false
+	}
+	port, ok := p.ports[name]
+	return port, ok
+}
+
+// owner returns the oldest route claiming port, or nil if no route claims it.
+func (p *portPool) owner(port int) *routeapi.Route {
+	if p == nil || len(p.claims[port]) == 0 {
+		return nil
+	}
+	return p.claims[port][0]
+}
+
+// byAge sorts routes oldest first.
+type byAge []*routeapi.Route
+
+func (r byAge) Len() int           { return len(r) }
+func (r byAge) Less(i, j int) bool { return routeapi.RouteOlderThan(r[i], r[j]) }
+func (r byAge) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
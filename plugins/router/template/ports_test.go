@@ -0,0 +1,98 @@
+package templaterouter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+func TestParsePortRange(t *testing.T) {
+	testCases := map[string]struct {
+		min, max int
+		valid    bool
+	}{
+		"10000-10999": {10000, 10999, true},
+		"5432":        {5432, 5432, true},
+		"10999-10000": {valid: false},
+		"0-10":        {valid: false},
+		"1-70000":     {valid: false},
+		"a-b":         {valid: false},
+	}
+
+	for value, tc := range testCases {
+		min, max, err := parsePortRange(value)
+		if !tc.valid {
+			if err == nil {
+				t.Errorf("%s: expected an error", value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", value, err)
+			continue
+		}
+		if min != tc.min || max != tc.max {
+			t.Errorf("%s: expected %d-%d, got %d-%d", value, tc.min, tc.max, min, max)
+		}
+	}
+}
+
+func TestPortPoolClaim(t *testing.T) {
+	pool := newPortPool(10000, 10010)
+	newRoute := func(namespace string, age int) *routeapi.Route {
+		route := &routeapi.Route{}
+		route.Namespace, route.Name = namespace, "db"
+		route.CreationTimestamp = util.Date(2015, 1, 1, 0, age, 0, 0, time.UTC)
+		return route
+	}
+	older, newer := newRoute("older", 0), newRoute("newer", 1)
+
+	if err := pool.claim(newer, 10000); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := pool.claim(newer, 10000); err != nil {
+		t.Errorf("Expected claiming the same port again to succeed: %v", err)
+	}
+	if err := pool.claim(older, 20000); err == nil {
+		t.Errorf("Expected an error for a port outside the range")
+	}
+	if _, ok := pool.port("older/db"); ok {
+		t.Errorf("Expected a port outside the range not to be claimed")
+	}
+
+	// an older route takes the port over, and the newer route waits for it
+	if err := pool.claim(older, 10000); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if owner := pool.owner(10000); owner != older {
+		t.Errorf("Expected the older route to own the port, got %#v", owner)
+	}
+	if err := pool.claim(newer, 10000); err == nil {
+		t.Errorf("Expected a conflict for a port owned by an older route")
+	}
+
+	pool.release("older/db")
+	if owner := pool.owner(10000); owner != newer {
+		t.Errorf("Expected the waiting route to own the released port, got %#v", owner)
+	}
+	pool.release("newer/db")
+	if owner := pool.owner(10000); owner != nil {
+		t.Errorf("Expected the port to be free, got %#v", owner)
+	}
+}
+
+func TestPortPoolDisabled(t *testing.T) {
+	var pool *portPool
+	route := &routeapi.Route{}
+	route.Namespace, route.Name = "ns", "db"
+	if err := pool.claim(route, 5432); err == nil {
+		t.Errorf("Expected an error when the router does not expose TCP ports")
+	}
+	pool.release("ns/db")
+	if owner := pool.owner(5432); owner != nil {
+		t.Errorf("Expected no owner, got %#v", owner)
+	}
+}
@@ -1,12 +1,19 @@
 package templaterouter
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"text/template"
+	"time"
 
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/golang/glog"
 
 	routeapi "github.com/openshift/origin/pkg/route/api"
@@ -19,6 +26,7 @@ const (
 )
 
 const (
+	// routeFile holds the snapshot of the router state written on every commit
 	routeFile = "/var/lib/containers/router/routes.json"
 	certDir   = "/var/lib/containers/router/certs/"
 	caCertDir = "/var/lib/containers/router/cacerts/"
@@ -33,60 +41,259 @@ const (
 type templateRouter struct {
 	templates        map[string]*template.Template
 	reloadScriptPath string
-	state            map[string]ServiceUnit
-	certManager      certManager
+	// statePath is where the state snapshot is written and read back on restart
+	statePath   string
+	state       map[string]ServiceUnit
+	certManager certManager
+	status      routerStatus
+	// committedState is the serialized state last applied to the backend, by a reload or an endpoint update
+	committedState []byte
+	// loadedState is the serialized state the configuration of the running backend was rendered from
+	loadedState []byte
+	// updateServers changes the servers of the running backend, endpoint changes always reload the
+	// backend if nil
+	updateServers serverUpdater
+	// slots are the endpoints set in the server slots of the running backend, by slot name for each
+	// service unit id.  The slots are empty after a reload.
+	slots map[string]map[string]Endpoint
+	// restored identifies the state read from the snapshot that has not changed since, until the router is
+	// reconciled with the first lists of routes and endpoints
+	restored *restoredState
 }
 
-func newTemplateRouter(templates map[string]*template.Template, reloadScriptPath string) (*templateRouter, error) {
-	router := &templateRouter{templates, reloadScriptPath, map[string]ServiceUnit{}, certManager{}}
-	err := router.readState()
-	return router, err
+// restoredState identifies the service units, endpoints, and routes a router read from its state snapshot.
+// Endpoints and routes are forgotten once they change, since the router then has their current state.
+type restoredState struct {
+	// units are the ids of the restored service units
+	units util.StringSet
+	// endpoints are the ids of the service units whose endpoints are restored
+	endpoints util.StringSet
+	// aliases are the keys of the restored service alias configs, by service unit id
+	aliases map[string]util.StringSet
 }
 
-func (r *templateRouter) readState() error {
-	dat, err := ioutil.ReadFile(routeFile)
-	// XXX: rework
+// newRestoredState returns the restoredState of the service units in state.
+func newRestoredState(state map[string]ServiceUnit) *restoredState {
+	s := &restoredState{units: util.StringSet{}, endpoints: util.StringSet{}, aliases: map[string]util.StringSet{}}
+	for id, unit := range state {
+		s.units.Insert(id)
+		s.endpoints.Insert(id)
+		s.aliases[id] = util.StringSet{}
+		for key := range unit.ServiceAliasConfigs {
+			s.aliases[id].Insert(key)
+		}
+	}
+	return s
+}
+
+// endpointsChanged forgets the restored endpoints of the service unit id.
+func (s *restoredState) endpointsChanged(id string) {
+	if s != nil {
+		s.endpoints.Delete(id)
+	}
+}
+
+// aliasChanged forgets the restored service alias config key of the service unit id.
+func (s *restoredState) aliasChanged(id, key string) {
+	if s != nil {
+		s.aliases[id].Delete(key)
+	}
+}
+
+func newTemplateRouter(templates map[string]*template.Template, reloadScriptPath, defaultCertificate string, backend backend, runtimeSocket string) (*templateRouter, error) {
+	router := &templateRouter{
+		templates:        templates,
+		reloadScriptPath: reloadScriptPath,
+		statePath:        routeFile,
+		state:            map[string]ServiceUnit{},
+		certManager:      certManager{backend: backend},
+	}
+	if len(runtimeSocket) > 0 {
+		router.updateServers = haproxyServerUpdater(runtimeSocket)
+	}
+	if len(defaultCertificate) > 0 {
+		if err := router.certManager.writeDefaultCertificate(defaultCertificate); err != nil {
+			return router, err
+		}
+	}
+
+	// serve the configuration the router had before it restarted until the routes and endpoints are resynced
+	router.readState()
+	if len(router.state) > 0 {
+		glog.Infof("Serving the last known configuration of %d service units while resyncing", len(router.state))
+		if err := router.Commit(); err != nil {
+			glog.Errorf("Unable to serve the last known configuration: %v", err)
+		}
+	}
+	return router, nil
+}
+
+// readState loads the state snapshot written by the last commit.  The router starts with an empty state if
+// there is no snapshot or it cannot be read.
+func (r *templateRouter) readState() {
+	r.state = make(map[string]ServiceUnit)
+	r.restored = nil
+
+	dat, err := ioutil.ReadFile(r.statePath)
 	if err != nil {
-		r.state = make(map[string]ServiceUnit)
-		return nil
+		if !os.IsNotExist(err) {
+			glog.Warningf("Unable to read the router state snapshot %s: %v", r.statePath, err)
+		}
+		return
+	}
+
+	state := make(map[string]ServiceUnit)
+	if err := json.Unmarshal(dat, &state); err != nil {
+		glog.Warningf("Discarding the unreadable router state snapshot %s: %v", r.statePath, err)
+		return
 	}
+	r.state = state
+	r.restored = newRestoredState(state)
+}
+
+// Reconcile drops the restored endpoints and routes that are missing from endpoints and routes, the first
+// lists of the router's shard, unless they changed since they were restored.  Restored service units left
+// without routes or endpoints are deleted.  The router state is not reconciled again.
+func (r *templateRouter) Reconcile(routes []routeapi.Route, endpoints []kapi.Endpoints) {
+	restored := r.restored
+	if restored == nil {
+		return
+	}
+	r.restored = nil
 
-	return json.Unmarshal(dat, &r.state)
+	listedUnits := util.StringSet{}
+	listedEndpoints := util.StringSet{}
+	for i := range endpoints {
+		listedEndpoints.Insert(endpointsKey(endpoints[i]))
+		listedUnits.Insert(endpointsKey(endpoints[i]))
+	}
+	listedAliases := map[string]util.StringSet{}
+	for i := range routes {
+		id := routeKey(routes[i])
+		if _, ok := listedAliases[id]; !ok {
+			listedAliases[id] = util.StringSet{}
+		}
+		listedAliases[id].Insert(r.routeKey(&routes[i]))
+		listedUnits.Insert(id)
+	}
+
+	for id := range restored.endpoints {
+		if !listedEndpoints.Has(id) {
+			glog.V(4).Infof("Dropping the restored endpoints of %s, they no longer exist", id)
+			r.DeleteEndpoints(id)
+		}
+	}
+	for id, keys := range restored.aliases {
+		unit, ok := r.state[id]
+		if !ok {
+			continue
+		}
+		for key := range keys {
+			if !listedAliases[id].Has(key) {
+				glog.V(4).Infof("Dropping the restored route %s of %s, it no longer exists", key, id)
+				delete(unit.ServiceAliasConfigs, key)
+			}
+		}
+	}
+	for id := range restored.units {
+		if unit, ok := r.state[id]; ok && !listedUnits.Has(id) && len(unit.ServiceAliasConfigs) == 0 && len(unit.EndpointTable) == 0 {
+			r.DeleteServiceUnit(id)
+		}
+	}
 }
 
 // Commit refreshes the backend and persists the router state.
 func (r *templateRouter) Commit() error {
 	glog.V(4).Info("Commiting router changes")
 
-	if err := r.writeState(); err != nil {
-		return err
+	start := time.Now()
+	reloaded, err := r.commit()
+	if reloaded || err != nil {
+		r.status.record(r.state, time.Since(start), err)
+	}
+	return err
+}
+
+// commit writes the router state and configuration and reloads the backend.  The backend is not
+// reloaded if the state is unchanged since the last successful reload, since the configuration
+// rendered from it would be identical, or if only its endpoints changed and they could be updated
+// in the running backend.
+func (r *templateRouter) commit() (bool, error) {
+	dat, err := json.MarshalIndent(r.state, "", "  ")
+	if err != nil {
+		glog.Errorf("Failed to marshal route table: %v", err)
+		return false, err
+	}
+	if r.committedState != nil && bytes.Equal(dat, r.committedState) {
+		glog.V(4).Info("Router state is unchanged, skipping reload")
+		return false, nil
+	}
+
+	if err := r.writeState(dat); err != nil {
+		return false, err
+	}
+
+	if r.updateEndpoints() {
+		r.committedState = dat
+		return false, nil
 	}
 
 	if err := r.writeConfig(); err != nil {
-		return err
+		return false, err
 	}
 
 	if err := r.reloadRouter(); err != nil {
-		return err
+		return false, err
 	}
 
-	return nil
+	r.committedState = dat
+	r.loadedState = dat
+	r.slots = nil
+	return true, nil
 }
 
-// writeState writes the state of this router to disk.
-func (r *templateRouter) writeState() error {
-	dat, err := json.MarshalIndent(r.state, "", "  ")
+// writeState writes the serialized state of this router to disk.  The snapshot is replaced atomically so a
+// router that restarts mid-write reads either the previous or the new state.
+func (r *templateRouter) writeState(dat []byte) error {
+	err := writeFileAtomic(r.statePath, dat, 0644)
 	if err != nil {
-		glog.Errorf("Failed to marshal route table: %v", err)
+		glog.Errorf("Failed to write route table: %v", err)
 		return err
 	}
-	err = ioutil.WriteFile(routeFile, dat, 0644)
+
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file in the directory of path and renames it over path, so
+// that readers never see a partially written file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir, name := filepath.Split(path)
+	if len(dir) == 0 {
+		dir = "."
+	}
+	file, err := ioutil.TempFile(dir, "."+name)
 	if err != nil {
-		glog.Errorf("Failed to write route table: %v", err)
 		return err
 	}
+	tmp := file.Name()
 
-	return nil
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, perm)
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
 }
 
 // write the config to disk
@@ -118,6 +325,14 @@ func (r *templateRouter) writeConfig() error {
 	return nil
 }
 
+// SetTemplates replaces the templates the configuration is rendered with, and makes the next commit
+// reload the backend even if the state is unchanged.
+func (r *templateRouter) SetTemplates(templates map[string]*template.Template) {
+	r.templates = templates
+	r.committedState = nil
+	r.loadedState = nil
+}
+
 // reloadRouter executes the router's reload script.
 func (r *templateRouter) reloadRouter() error {
 	cmd := exec.Command(r.reloadScriptPath)
@@ -159,9 +374,10 @@ func (r *templateRouter) DeleteEndpoints(id string) {
 	frontend.EndpointTable = make(map[string]Endpoint)
 
 	r.state[id] = frontend
+	r.restored.endpointsChanged(id)
 }
 
-//generate route key in form of Host-Path
+// generate route key in form of Host-Path
 func (r *templateRouter) routeKey(route *routeapi.Route) string {
 	return route.Host + "-" + route.Path
 }
@@ -173,13 +389,29 @@ func (r *templateRouter) AddRoute(id string, route *routeapi.Route) {
 	backendKey := r.routeKey(route)
 
 	config := ServiceAliasConfig{
-		Host: route.Host,
-		Path: route.Path,
+		Host:             route.Host,
+		Path:             route.Path,
+		ServiceUnitNames: routeapi.RouteBackendWeights(route),
+		Affinity:         routeAffinity(route),
+	}
+	applyRouteTunables(&config, route)
+
+	if port, err := routeapi.RouteTCPPort(route); err == nil {
+		config.TCPPort = port
+	} else {
+		glog.Warningf("Ignoring invalid TCP port for route %s/%s: %v", route.Namespace, route.Name, err)
 	}
 
 	if route.TLS != nil && len(route.TLS.Termination) > 0 {
 		config.TLSTermination = route.TLS.Termination
 
+		if route.TLS.Termination == routeapi.TLSTerminationEdge {
+			config.InsecureEdgeTerminationPolicy = route.TLS.InsecureEdgeTerminationPolicy
+			if len(config.InsecureEdgeTerminationPolicy) == 0 {
+				config.InsecureEdgeTerminationPolicy = routeapi.InsecureEdgeTerminationPolicyAllow
+			}
+		}
+
 		if route.TLS.Termination != routeapi.TLSTerminationPassthrough {
 			if config.Certificates == nil {
 				config.Certificates = make(map[string]Certificate)
@@ -216,6 +448,66 @@ func (r *templateRouter) AddRoute(id string, route *routeapi.Route) {
 	//create or replace
 	frontend.ServiceAliasConfigs[backendKey] = config
 	r.state[id] = frontend
+	r.restored.aliasChanged(id, backendKey)
+}
+
+// routeAffinity returns the session affinity requested by the route's annotations.  Cookies cannot be
+// inserted into passthrough traffic, so those routes fall back to source affinity.
+func routeAffinity(route *routeapi.Route) routeapi.RouteAffinityType {
+	affinity := routeapi.RouteAffinityType(route.Annotations[routeapi.RouteAffinityAnnotation])
+	switch affinity {
+	case routeapi.RouteAffinityCookie:
+		if route.TLS != nil && route.TLS.Termination == routeapi.TLSTerminationPassthrough {
+			return routeapi.RouteAffinitySource
+		}
+		return affinity
+	case routeapi.RouteAffinitySource:
+		return affinity
+	}
+	return routeapi.RouteAffinityNone
+}
+
+// applyRouteTunables sets the balancing algorithm, timeouts, and connection limit requested by the route's
+// annotations on config.  Invalid values are ignored so that the template defaults apply.
+func applyRouteTunables(config *ServiceAliasConfig, route *routeapi.Route) {
+	if balance := routeapi.RouteBalanceType(route.Annotations[routeapi.RouteBalanceAnnotation]); len(balance) > 0 {
+		switch balance {
+		case routeapi.RouteBalanceLeastConn, routeapi.RouteBalanceRoundRobin:
+			config.Balance = string(balance)
+		default:
+			glog.Warningf("Ignoring unknown balance algorithm %q for route %s/%s", balance, route.Namespace, route.Name)
+		}
+	}
+	// source affinity is implemented by balancing on the client address
+	if config.Affinity == routeapi.RouteAffinitySource {
+		config.Balance = "source"
+	}
+
+	config.ConnectTimeout = routeTimeout(route, routeapi.RouteConnectTimeoutAnnotation)
+	config.ServerTimeout = routeTimeout(route, routeapi.RouteServerTimeoutAnnotation)
+
+	if value, ok := route.Annotations[routeapi.RouteMaxConnectionsAnnotation]; ok {
+		if maxConn, err := strconv.Atoi(value); err == nil && maxConn > 0 {
+			config.MaxConnections = maxConn
+		} else {
+			glog.Warningf("Ignoring invalid max connections %q for route %s/%s", value, route.Namespace, route.Name)
+		}
+	}
+}
+
+// routeTimeout returns the duration in the route annotation key as haproxy milliseconds, or an empty
+// string if it is not set or invalid.
+func routeTimeout(route *routeapi.Route, key string) string {
+	value, ok := route.Annotations[key]
+	if !ok {
+		return ""
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		glog.Warningf("Ignoring invalid timeout %s=%q for route %s/%s", key, value, route.Namespace, route.Name)
+		return ""
+	}
+	return fmt.Sprintf("%dms", timeout/time.Millisecond)
 }
 
 // RemoveAlias removes the given alias for the given id.
@@ -227,6 +519,7 @@ func (r *templateRouter) RemoveRoute(id string, route *routeapi.Route) {
 	}
 
 	delete(r.state[id].ServiceAliasConfigs, r.routeKey(route))
+	r.restored.aliasChanged(id, r.routeKey(route))
 }
 
 // AddRoute adds new Endpoints for the given id.
@@ -242,6 +535,7 @@ func (r *templateRouter) AddEndpoints(id string, endpoints []Endpoint) {
 	}
 
 	r.state[id] = frontend
+	r.restored.endpointsChanged(id)
 }
 
 func cmpStrSlices(first []string, second []string) bool {
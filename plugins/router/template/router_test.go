@@ -1,8 +1,16 @@
 package templaterouter
 
 import (
-	routeapi "github.com/openshift/origin/pkg/route/api"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
 )
 
 // emptyRouter creates a new, empty template router
@@ -173,10 +181,38 @@ func TestAddRoute(t *testing.T) {
 			if saCfg.Host != route.Host || saCfg.Path != route.Path || !compareTLS(route, saCfg, t) {
 				t.Errorf("Route %v did not match serivce alias config %v", route, saCfg)
 			}
+			if saCfg.InsecureEdgeTerminationPolicy != routeapi.InsecureEdgeTerminationPolicyAllow {
+				t.Errorf("Expected edge route to default to the %s insecure policy, got %q", routeapi.InsecureEdgeTerminationPolicyAllow, saCfg.InsecureEdgeTerminationPolicy)
+			}
 		}
 	}
 }
 
+// TestAddRouteWeightedBackends tests that a route's weighted services are recorded on its service alias config
+func TestAddRouteWeightedBackends(t *testing.T) {
+	router := emptyRouter()
+	serviceWeight, alternateWeight := 3, 0
+	route := &routeapi.Route{
+		Host:          "host",
+		ServiceName:   "blue",
+		ServiceWeight: &serviceWeight,
+		AlternateBackends: []routeapi.RouteBackend{
+			{ServiceName: "green", Weight: &alternateWeight},
+			{ServiceName: "canary"},
+		},
+	}
+	router.CreateServiceUnit("blue")
+
+	router.AddRoute("blue", route)
+
+	su, _ := router.FindServiceUnit("blue")
+	saCfg := su.ServiceAliasConfigs[router.routeKey(route)]
+	expected := map[string]int{"blue": 3, "green": 0, "canary": 1}
+	if !reflect.DeepEqual(saCfg.ServiceUnitNames, expected) {
+		t.Errorf("Expected service unit weights %v, got %v", expected, saCfg.ServiceUnitNames)
+	}
+}
+
 // compareTLS is a utility to help compare cert contents between an route and a config
 func compareTLS(route *routeapi.Route, saCfg ServiceAliasConfig, t *testing.T) bool {
 	return findCert(route.TLS.DestinationCACertificate, saCfg.Certificates, false, t) &&
@@ -247,3 +283,207 @@ func TestRemoveRoute(t *testing.T) {
 		}
 	}
 }
+
+// TestRouteAffinity tests that the affinity annotation is read from routes
+func TestRouteAffinity(t *testing.T) {
+	testCases := map[string]struct {
+		annotation  string
+		termination routeapi.TLSTerminationType
+		expected    routeapi.RouteAffinityType
+	}{
+		"unset":                 {"", "", routeapi.RouteAffinityNone},
+		"unknown":               {"sticky", "", routeapi.RouteAffinityNone},
+		"cookie":                {"cookie", routeapi.TLSTerminationEdge, routeapi.RouteAffinityCookie},
+		"source":                {"source", "", routeapi.RouteAffinitySource},
+		"cookie on passthrough": {"cookie", routeapi.TLSTerminationPassthrough, routeapi.RouteAffinitySource},
+	}
+
+	for name, tc := range testCases {
+		route := &routeapi.Route{}
+		if len(tc.annotation) > 0 {
+			route.Annotations = map[string]string{routeapi.RouteAffinityAnnotation: tc.annotation}
+		}
+		if len(tc.termination) > 0 {
+			route.TLS = &routeapi.TLSConfig{Termination: tc.termination}
+		}
+		if actual := routeAffinity(route); actual != tc.expected {
+			t.Errorf("%s: expected %s, got %s", name, tc.expected, actual)
+		}
+	}
+}
+
+// TestApplyRouteTunables tests that router tunables are read from route annotations
+func TestApplyRouteTunables(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		affinity    routeapi.RouteAffinityType
+		expected    ServiceAliasConfig
+	}{
+		"defaults": {
+			expected: ServiceAliasConfig{},
+		},
+		"all set": {
+			annotations: map[string]string{
+				routeapi.RouteBalanceAnnotation:        "roundrobin",
+				routeapi.RouteConnectTimeoutAnnotation: "2s",
+				routeapi.RouteServerTimeoutAnnotation:  "1m",
+				routeapi.RouteMaxConnectionsAnnotation: "100",
+			},
+			expected: ServiceAliasConfig{Balance: "roundrobin", ConnectTimeout: "2000ms", ServerTimeout: "60000ms", MaxConnections: 100},
+		},
+		"invalid values ignored": {
+			annotations: map[string]string{
+				routeapi.RouteBalanceAnnotation:        "random",
+				routeapi.RouteConnectTimeoutAnnotation: "soon",
+				routeapi.RouteServerTimeoutAnnotation:  "-1s",
+				routeapi.RouteMaxConnectionsAnnotation: "0",
+			},
+			expected: ServiceAliasConfig{},
+		},
+		"source affinity overrides balance": {
+			annotations: map[string]string{routeapi.RouteBalanceAnnotation: "roundrobin"},
+			affinity:    routeapi.RouteAffinitySource,
+			expected:    ServiceAliasConfig{Affinity: routeapi.RouteAffinitySource, Balance: "source"},
+		},
+	}
+
+	for name, tc := range testCases {
+		route := &routeapi.Route{}
+		route.Annotations = tc.annotations
+		config := ServiceAliasConfig{Affinity: tc.affinity}
+
+		applyRouteTunables(&config, route)
+
+		if !reflect.DeepEqual(config, tc.expected) {
+			t.Errorf("%s: expected %#v, got %#v", name, tc.expected, config)
+		}
+	}
+}
+
+// TestCommitSkipsUnchangedState tests that the router is not reloaded when its state has not changed
+func TestCommitSkipsUnchangedState(t *testing.T) {
+	router := emptyRouter()
+	router.CreateServiceUnit("test")
+	dat, err := json.MarshalIndent(router.state, "", "  ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	router.committedState = dat
+
+	reloaded, err := router.commit()
+	if err != nil || reloaded {
+		t.Errorf("Expected unchanged state to skip the reload, got reloaded=%t err=%v", reloaded, err)
+	}
+	if err := router.Commit(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !router.status.lastCommit.IsZero() {
+		t.Errorf("Expected a skipped reload not to be recorded")
+	}
+}
+
+// TestAddRouteTCPPort tests that the TCP port a route asks for is recorded on its service alias config
+func TestAddRouteTCPPort(t *testing.T) {
+	router := emptyRouter()
+	route := &routeapi.Route{
+		Host:        "db.example.com",
+		ServiceName: "db",
+	}
+	route.Annotations = map[string]string{routeapi.RouteTCPPortAnnotation: "10000"}
+	router.CreateServiceUnit("db")
+
+	router.AddRoute("db", route)
+
+	su, _ := router.FindServiceUnit("db")
+	if cfg := su.ServiceAliasConfigs[router.routeKey(route)]; cfg.TCPPort != 10000 {
+		t.Errorf("Expected the route to be exposed on port 10000, got %#v", cfg)
+	}
+}
+
+// TestStateSnapshot tests that the written state snapshot is read back by a restarted router and that an
+// unreadable snapshot is discarded
+func TestStateSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "router-state")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	router := emptyRouter()
+	router.statePath = filepath.Join(dir, "routes.json")
+	router.CreateServiceUnit("test")
+	router.AddRoute("test", &routeapi.Route{Host: "www.example.com", ServiceName: "test"})
+	router.AddEndpoints("test", []Endpoint{{ID: "1.1.1.1:8080", IP: "1.1.1.1", Port: "8080"}})
+
+	dat, err := json.MarshalIndent(router.state, "", "  ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := router.writeState(dat); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 1 {
+		t.Errorf("Expected only the snapshot to remain in %s, got %d files", dir, len(files))
+	}
+
+	restarted := templateRouter{statePath: router.statePath}
+	restarted.readState()
+	if !reflect.DeepEqual(router.state, restarted.state) {
+		t.Errorf("Expected the restarted router to load %#v, got %#v", router.state, restarted.state)
+	}
+
+	if err := ioutil.WriteFile(router.statePath, []byte("{not json"), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	restarted.readState()
+	if restarted.state == nil || len(restarted.state) != 0 {
+		t.Errorf("Expected an unreadable snapshot to be discarded, got %#v", restarted.state)
+	}
+}
+
+// TestReconcileRestoredState tests that the routes and endpoints restored from the snapshot are dropped
+// once the first lists show they no longer exist, unless they changed since they were restored
+func TestReconcileRestoredState(t *testing.T) {
+	router := emptyRouter()
+	for _, id := range []string{"kept", "deleted", "changed"} {
+		router.CreateServiceUnit(id)
+		router.AddRoute(id, &routeapi.Route{Host: id + ".example.com", ServiceName: id})
+		router.AddEndpoints(id, []Endpoint{{ID: "1.1.1.1:8080", IP: "1.1.1.1", Port: "8080"}})
+	}
+	router.restored = newRestoredState(router.state)
+
+	// the route of the changed service moved to another host and its endpoints were updated
+	router.RemoveRoute("changed", &routeapi.Route{Host: "changed.example.com", ServiceName: "changed"})
+	router.AddRoute("changed", &routeapi.Route{Host: "moved.example.com", ServiceName: "changed"})
+	router.DeleteEndpoints("changed")
+	router.AddEndpoints("changed", []Endpoint{{ID: "2.2.2.2:8080", IP: "2.2.2.2", Port: "8080"}})
+	// a route created after the list is handled before the router is reconciled
+	router.CreateServiceUnit("created")
+	router.AddRoute("created", &routeapi.Route{Host: "created.example.com", ServiceName: "created"})
+
+	router.Reconcile(
+		[]routeapi.Route{{Host: "kept.example.com", ServiceName: "kept"}},
+		[]kapi.Endpoints{{ObjectMeta: kapi.ObjectMeta{Name: "kept"}}},
+	)
+
+	if _, ok := router.state["deleted"]; ok {
+		t.Errorf("Expected the service unit that no longer exists to be deleted, got %#v", router.state["deleted"])
+	}
+	kept := router.state["kept"]
+	if _, ok := kept.ServiceAliasConfigs["kept.example.com-"]; !ok || len(kept.EndpointTable) != 1 {
+		t.Errorf("Expected the listed route and endpoints to be kept, got %#v", kept)
+	}
+	changed := router.state["changed"]
+	if _, ok := changed.ServiceAliasConfigs["moved.example.com-"]; !ok || len(changed.ServiceAliasConfigs) != 1 {
+		t.Errorf("Expected only the changed route to be kept, got %#v", changed.ServiceAliasConfigs)
+	}
+	if _, ok := changed.EndpointTable["2.2.2.2:8080"]; !ok {
+		t.Errorf("Expected the changed endpoints to be kept, got %#v", changed.EndpointTable)
+	}
+	if _, ok := router.state["created"].ServiceAliasConfigs["created.example.com-"]; !ok {
+		t.Errorf("Expected the created route to be kept, got %#v", router.state["created"])
+	}
+	if router.restored != nil {
+		t.Errorf("Expected the router to be reconciled once")
+	}
+}
@@ -0,0 +1,279 @@
+package templaterouter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// maxCommandsPerRequest limits the commands sent to the haproxy admin socket in a single request.
+const maxCommandsPerRequest = 100
+
+// serverSlotsPerServiceUnit is the number of disabled servers the haproxy template adds to a backend for each
+// service unit it sends traffic to.  New endpoints of the service unit are set in these slots at runtime, so
+// the backend is only reloaded to add servers once they are all in use.
+const serverSlotsPerServiceUnit = 4
+
+// serverSlots returns the names of the server slots of the service unit id, in the order they are filled.
+// Service unit ids are namespace/name, neither of which may hold "_", so the names are unique.
+func serverSlots(id string) []string {
+	names := make([]string, serverSlotsPerServiceUnit)
+	for i := range names {
+		names[i] = fmt.Sprintf("_slot_%s_%d", strings.Replace(id, "/", "_", -1), i+1)
+	}
+	return names
+}
+
+// serverUpdater runs commands that change the servers of the running backend, such as
+// "disable server be_http_test/1.1.1.1:8080".
+type serverUpdater func(commands []string) error
+
+// haproxyServerUpdater returns a serverUpdater that runs commands through the haproxy admin socket at path.
+// The socket must be opened with the admin level.
+func haproxyServerUpdater(path string) serverUpdater {
+	return func(commands []string) error {
+		for len(commands) > 0 {
+			n := len(commands)
+			if n > maxCommandsPerRequest {
+				n = maxCommandsPerRequest
+			}
+			if err := runHAProxyCommands(path, commands[:n]); err != nil {
+				return err
+			}
+			commands = commands[n:]
+		}
+		return nil
+	}
+}
+
+// runHAProxyCommands runs commands in a single request to the haproxy admin socket at path.  Commands that
+// succeed produce no output, except for the report of a changed server address, so any other output is
+// returned as an error.
+func runHAProxyCommands(path string, commands []string) error {
+	conn, err := net.DialTimeout("unix", path, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if _, err := io.WriteString(conn, strings.Join(commands, ";")+"\n"); err != nil {
+		return err
+	}
+	out, err := ioutil.ReadAll(conn)
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "IP changed from") || strings.HasPrefix(line, "no need to change the addr") {
+			continue
+		}
+		return fmt.Errorf("haproxy rejected the server changes: %s", line)
+	}
+	return nil
+}
+
+// updateEndpoints applies the endpoints of the router state to the running backend without reloading it.
+// This is only possible if the state differs from the state the running configuration was rendered from in
+// endpoints alone.  The servers of endpoints in that configuration are enabled or disabled, and new endpoints
+// are set in the free server slots of their service unit.  Returns false if the backend must be reloaded
+// instead, such as when a service unit has more new endpoints than server slots.
+func (r *templateRouter) updateEndpoints() bool {
+	if r.updateServers == nil || r.loadedState == nil || r.committedState == nil {
+		return false
+	}
+	loaded, applied := map[string]ServiceUnit{}, map[string]ServiceUnit{}
+	if err := json.Unmarshal(r.loadedState, &loaded); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(r.committedState, &applied); err != nil {
+		return false
+	}
+
+	commands, slots, ok := serverCommands(loaded, applied, r.state, r.slots)
+	if !ok {
+		return false
+	}
+	if len(commands) > 0 {
+		if err := r.updateServers(commands); err != nil {
+			glog.Warningf("Unable to change the endpoints of the router without a reload, reloading: %v", err)
+			return false
+		}
+	}
+	glog.V(4).Infof("Updated the endpoints of the router without a reload with %d commands", len(commands))
+	r.slots = slots
+	r.status.recordEndpointUpdate(r.state)
+	return true
+}
+
+// serverCommands returns the commands that change the servers of a backend running the configuration
+// rendered from loaded, whose servers were last changed to the endpoints of applied with the endpoints in
+// slots set in the server slots of each service unit, to the endpoints of current.  The endpoints set in the
+// server slots once the commands have run are returned.  Returns false if current differs from loaded in
+// anything but endpoints, or a service unit has more new endpoints than server slots.
+func serverCommands(loaded, applied, current map[string]ServiceUnit, slots map[string]map[string]Endpoint) ([]string, map[string]map[string]Endpoint, bool) {
+	if !sameRoutes(loaded, current) {
+		return nil, nil, false
+	}
+
+	ids := []string{}
+	for id := range current {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	commands := []string{}
+	updatedSlots := map[string]map[string]Endpoint{}
+	for _, id := range ids {
+		unit, loadedUnit := current[id], loaded[id]
+		unitSlots := map[string]Endpoint{}
+		for slot, endpoint := range slots[id] {
+			unitSlots[slot] = endpoint
+		}
+		updatedSlots[id] = unitSlots
+		if sameEndpoints(applied[id].EndpointTable, unit.EndpointTable) {
+			continue
+		}
+
+		// the servers of the loaded endpoints are enabled or disabled by name, the server slots are emptied of
+		// removed endpoints and filled with new endpoints in order
+		changes := []serverChange{}
+		servers := []string{}
+		for endpointID := range loadedUnit.EndpointTable {
+			servers = append(servers, endpointID)
+		}
+		sort.Strings(servers)
+		for _, server := range servers {
+			if _, ok := unit.EndpointTable[server]; ok {
+				changes = append(changes, serverChange{"enable server", server, ""})
+			} else {
+				changes = append(changes, serverChange{"disable server", server, ""})
+			}
+		}
+
+		filled := map[string]string{}
+		for _, slot := range serverSlots(id) {
+			endpoint, ok := unitSlots[slot]
+			if !ok {
+				continue
+			}
+			if listed, ok := unit.EndpointTable[endpoint.ID]; !ok || listed != endpoint {
+				delete(unitSlots, slot)
+				changes = append(changes, serverChange{"disable server", slot, ""})
+				continue
+			}
+			filled[endpoint.ID] = slot
+		}
+
+		endpointIDs := []string{}
+		for endpointID := range unit.EndpointTable {
+			endpointIDs = append(endpointIDs, endpointID)
+		}
+		sort.Strings(endpointIDs)
+		free := serverSlots(id)
+		for _, endpointID := range endpointIDs {
+			endpoint := unit.EndpointTable[endpointID]
+			if loadedEndpoint, ok := loadedUnit.EndpointTable[endpointID]; ok {
+				if loadedEndpoint != endpoint {
+					return nil, nil, false
+				}
+				continue
+			}
+			if _, ok := filled[endpointID]; ok {
+				continue
+			}
+			for len(free) > 0 && len(unitSlots[free[0]].ID) > 0 {
+				free = free[1:]
+			}
+			if len(free) == 0 {
+				return nil, nil, false
+			}
+			slot := free[0]
+			unitSlots[slot] = endpoint
+			changes = append(changes,
+				serverChange{"set server", slot, fmt.Sprintf(" addr %s port %s", endpoint.IP, endpoint.Port)},
+				serverChange{"enable server", slot, ""})
+		}
+
+		for _, backend := range serviceUnitBackends(current, id) {
+			for _, change := range changes {
+				commands = append(commands, change.command(backend))
+			}
+		}
+	}
+	return commands, updatedSlots, true
+}
+
+// serverChange is a command that changes a server of every backend that sends traffic to a service unit.
+type serverChange struct {
+	// verb is the command, such as "enable server"
+	verb string
+	// server is the name of the server in the backend
+	server string
+	// args follow the server in the command
+	args string
+}
+
+// command returns the command that makes the change to the server of backend.
+func (c serverChange) command(backend string) string {
+	return fmt.Sprintf("%s %s/%s%s", c.verb, backend, c.server, c.args)
+}
+
+// sameRoutes returns true if the service units and routes of a and b are the same, ignoring endpoints.
+func sameRoutes(a, b map[string]ServiceUnit) bool {
+	withoutEndpoints := func(state map[string]ServiceUnit) []byte {
+		units := map[string]ServiceUnit{}
+		for id, unit := range state {
+			unit.EndpointTable = nil
+			units[id] = unit
+		}
+		dat, err := json.Marshal(units)
+		if err != nil {
+			return nil
+		}
+		return dat
+	}
+	dat := withoutEndpoints(a)
+	return dat != nil && bytes.Equal(dat, withoutEndpoints(b))
+}
+
+// sameEndpoints returns true if a and b hold the same endpoints.
+func sameEndpoints(a, b map[string]Endpoint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, endpoint := range a {
+		if other, ok := b[id]; !ok || other != endpoint {
+			return false
+		}
+	}
+	return true
+}
+
+// serviceUnitBackends returns the names of the backends that send traffic to the endpoints of the
+// service unit id.
+func serviceUnitBackends(state map[string]ServiceUnit, id string) []string {
+	names := map[string]bool{}
+	for unitID, unit := range state {
+		for _, cfg := range unit.ServiceAliasConfigs {
+			if _, ok := cfg.ServiceUnitNames[id]; ok {
+				names[backendName(unitID, cfg)] = true
+			}
+		}
+	}
+	backends := []string{}
+	for name := range names {
+		backends = append(backends, name)
+	}
+	sort.Strings(backends)
+	return backends
+}
@@ -0,0 +1,245 @@
+package templaterouter
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// endpointsState returns a state with an edge route and a reencrypt route sending traffic to the
+// service unit "web", which has endpoints.
+func endpointsState(endpoints ...Endpoint) map[string]ServiceUnit {
+	table := map[string]Endpoint{}
+	for _, endpoint := range endpoints {
+		table[endpoint.ID] = endpoint
+	}
+	return map[string]ServiceUnit{
+		"web": {
+			Name:          "web",
+			EndpointTable: table,
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{
+				"www.example.com-": {Host: "www.example.com", ServiceUnitNames: map[string]int{"web": 1}},
+			},
+		},
+		"secure": {
+			Name:          "secure",
+			EndpointTable: map[string]Endpoint{},
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{
+				"secure.example.com-": {Host: "secure.example.com", TLSTermination: "reencrypt", ServiceUnitNames: map[string]int{"secure": 1, "web": 1}},
+			},
+		},
+	}
+}
+
+func TestServerCommands(t *testing.T) {
+	ep1 := Endpoint{ID: "1.1.1.1:8080", IP: "1.1.1.1", Port: "8080"}
+	ep2 := Endpoint{ID: "1.1.1.2:8080", IP: "1.1.1.2", Port: "8080"}
+	ep3 := Endpoint{ID: "1.1.1.3:8080", IP: "1.1.1.3", Port: "8080"}
+	ep4 := Endpoint{ID: "1.1.1.4:8080", IP: "1.1.1.4", Port: "8080"}
+	changedRoute := endpointsState(ep1)
+	changedRoute["web"].ServiceAliasConfigs["www.example.com-"] = ServiceAliasConfig{Host: "www.example.com", Balance: "roundrobin", ServiceUnitNames: map[string]int{"web": 1}}
+	scaledUp := []Endpoint{ep1, ep2}
+	for i := 1; i <= serverSlotsPerServiceUnit+1; i++ {
+		scaledUp = append(scaledUp, Endpoint{ID: fmt.Sprintf("1.1.2.%d:8080", i), IP: fmt.Sprintf("1.1.2.%d", i), Port: "8080"})
+	}
+
+	testCases := map[string]struct {
+		applied  map[string]ServiceUnit
+		slots    map[string]map[string]Endpoint
+		current  map[string]ServiceUnit
+		commands []string
+		filled   map[string]Endpoint
+		ok       bool
+	}{
+		"unchanged": {
+			applied:  endpointsState(ep1, ep2),
+			current:  endpointsState(ep1, ep2),
+			commands: []string{},
+			filled:   map[string]Endpoint{},
+			ok:       true,
+		},
+		"removed endpoint": {
+			applied: endpointsState(ep1, ep2),
+			current: endpointsState(ep1),
+			commands: []string{
+				"enable server be_http_web/1.1.1.1:8080",
+				"disable server be_http_web/1.1.1.2:8080",
+				"enable server be_secure_secure/1.1.1.1:8080",
+				"disable server be_secure_secure/1.1.1.2:8080",
+			},
+			filled: map[string]Endpoint{},
+			ok:     true,
+		},
+		"restored endpoint": {
+			applied: endpointsState(ep1),
+			current: endpointsState(ep1, ep2),
+			commands: []string{
+				"enable server be_http_web/1.1.1.1:8080",
+				"enable server be_http_web/1.1.1.2:8080",
+				"enable server be_secure_secure/1.1.1.1:8080",
+				"enable server be_secure_secure/1.1.1.2:8080",
+			},
+			filled: map[string]Endpoint{},
+			ok:     true,
+		},
+		"new endpoint": {
+			applied: endpointsState(ep1, ep2),
+			current: endpointsState(ep1, ep3),
+			commands: []string{
+				"enable server be_http_web/1.1.1.1:8080",
+				"disable server be_http_web/1.1.1.2:8080",
+				"set server be_http_web/_slot_web_1 addr 1.1.1.3 port 8080",
+				"enable server be_http_web/_slot_web_1",
+				"enable server be_secure_secure/1.1.1.1:8080",
+				"disable server be_secure_secure/1.1.1.2:8080",
+				"set server be_secure_secure/_slot_web_1 addr 1.1.1.3 port 8080",
+				"enable server be_secure_secure/_slot_web_1",
+			},
+			filled: map[string]Endpoint{"_slot_web_1": ep3},
+			ok:     true,
+		},
+		"new endpoint with a slot in use": {
+			applied: endpointsState(ep1, ep2, ep3),
+			slots:   map[string]map[string]Endpoint{"web": {"_slot_web_1": ep3}},
+			current: endpointsState(ep1, ep2, ep3, ep4),
+			commands: []string{
+				"enable server be_http_web/1.1.1.1:8080",
+				"enable server be_http_web/1.1.1.2:8080",
+				"set server be_http_web/_slot_web_2 addr 1.1.1.4 port 8080",
+				"enable server be_http_web/_slot_web_2",
+				"enable server be_secure_secure/1.1.1.1:8080",
+				"enable server be_secure_secure/1.1.1.2:8080",
+				"set server be_secure_secure/_slot_web_2 addr 1.1.1.4 port 8080",
+				"enable server be_secure_secure/_slot_web_2",
+			},
+			filled: map[string]Endpoint{"_slot_web_1": ep3, "_slot_web_2": ep4},
+			ok:     true,
+		},
+		"endpoint removed from a slot": {
+			applied: endpointsState(ep1, ep2, ep3),
+			slots:   map[string]map[string]Endpoint{"web": {"_slot_web_1": ep3}},
+			current: endpointsState(ep1, ep2),
+			commands: []string{
+				"enable server be_http_web/1.1.1.1:8080",
+				"enable server be_http_web/1.1.1.2:8080",
+				"disable server be_http_web/_slot_web_1",
+				"enable server be_secure_secure/1.1.1.1:8080",
+				"enable server be_secure_secure/1.1.1.2:8080",
+				"disable server be_secure_secure/_slot_web_1",
+			},
+			filled: map[string]Endpoint{},
+			ok:     true,
+		},
+		"more new endpoints than slots": {
+			applied: endpointsState(ep1, ep2),
+			current: endpointsState(scaledUp...),
+		},
+		"changed route": {
+			applied: endpointsState(ep1, ep2),
+			current: changedRoute,
+		},
+	}
+	for name, tc := range testCases {
+		commands, slots, ok := serverCommands(endpointsState(ep1, ep2), tc.applied, tc.current, tc.slots)
+		if ok != tc.ok || !reflect.DeepEqual(commands, tc.commands) {
+			t.Errorf("%s: expected %v %t, got %v %t", name, tc.commands, tc.ok, commands, ok)
+		}
+		if ok && !reflect.DeepEqual(slots["web"], tc.filled) {
+			t.Errorf("%s: expected the slots of web to hold %v, got %v", name, tc.filled, slots["web"])
+		}
+	}
+}
+
+func TestUpdateEndpoints(t *testing.T) {
+	ep1 := Endpoint{ID: "1.1.1.1:8080", IP: "1.1.1.1", Port: "8080"}
+	ep2 := Endpoint{ID: "1.1.1.2:8080", IP: "1.1.1.2", Port: "8080"}
+	dat, err := json.Marshal(endpointsState(ep1, ep2))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var commands []string
+	var updateErr error
+	router := &templateRouter{
+		state:          endpointsState(ep1),
+		loadedState:    dat,
+		committedState: dat,
+		updateServers: func(c []string) error {
+			commands = append(commands, c...)
+			return updateErr
+		},
+	}
+	if !router.updateEndpoints() || len(commands) != 4 {
+		t.Errorf("Expected the endpoints to be updated without a reload, got %v", commands)
+	}
+	if router.status.endpointUpdates != 1 || router.status.endpoints != 1 {
+		t.Errorf("Expected the endpoint update to be recorded, got %d updates of %d endpoints", router.status.endpointUpdates, router.status.endpoints)
+	}
+
+	// a new endpoint fills a server slot, which is emptied by a reload
+	commands = nil
+	router.state = endpointsState(ep1, Endpoint{ID: "1.1.1.3:8080", IP: "1.1.1.3", Port: "8080"})
+	if !router.updateEndpoints() || len(commands) != 8 || len(router.slots["web"]) != 1 {
+		t.Errorf("Expected the new endpoint to be set in a server slot, got %v %v", commands, router.slots)
+	}
+
+	updateErr = errors.New("no such server")
+	if router.updateEndpoints() {
+		t.Errorf("Expected a failed update to reload the router")
+	}
+
+	router.updateServers = nil
+	if router.updateEndpoints() {
+		t.Errorf("Expected the router to be reloaded without a runtime API")
+	}
+}
+
+func TestRunHAProxyCommands(t *testing.T) {
+	dir, err := ioutil.TempDir("", "haproxy")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "haproxy.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer listener.Close()
+
+	requests := make(chan string, 3)
+	go func() {
+		for _, response := range []string{"\n\n", "IP changed from '127.0.0.1' to '1.1.1.3', port changed from '1' to '8080' by 'stats socket command'\n\n", "No such server.\n\n"} {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			requests <- line
+			conn.Write([]byte(response))
+			conn.Close()
+		}
+	}()
+
+	commands := []string{"disable server be_http_web/1.1.1.2:8080", "enable server be_http_web/1.1.1.1:8080"}
+	if err := runHAProxyCommands(path, commands); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if request := <-requests; request != "disable server be_http_web/1.1.1.2:8080;enable server be_http_web/1.1.1.1:8080\n" {
+		t.Errorf("Unexpected request %q", request)
+	}
+	if err := runHAProxyCommands(path, []string{"set server be_http_web/_slot_web_1 addr 1.1.1.3 port 8080"}); err != nil {
+		t.Errorf("Expected a changed server address to succeed, got %v", err)
+	}
+	<-requests
+	if err := runHAProxyCommands(path, commands[:1]); err == nil {
+		t.Errorf("Expected an error for a rejected command")
+	}
+}
@@ -0,0 +1,474 @@
+package templaterouter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/template"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+const haproxyTemplate = "../../../images/router/haproxy/conf/haproxy-config.template"
+
+// TestHAProxyTemplateWeightedBackends ensures the haproxy backend for a route splits traffic across
+// the endpoints of every service the route sends traffic to.
+func TestHAProxyTemplateWeightedBackends(t *testing.T) {
+	templates := template.Must(template.New("config").Funcs(templateFuncs).ParseFiles(haproxyTemplate))
+	state := map[string]ServiceUnit{
+		"blue": {
+			Name: "blue",
+			EndpointTable: map[string]Endpoint{
+				"1.1.1.1:8080": {ID: "1.1.1.1:8080", IP: "1.1.1.1", Port: "8080"},
+			},
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{
+				"www.example.com-": {
+					Host:             "www.example.com",
+					ServiceUnitNames: map[string]int{"blue": 3, "green": 1},
+				},
+			},
+		},
+		"green": {
+			Name: "green",
+			EndpointTable: map[string]Endpoint{
+				"2.2.2.2:8080": {ID: "2.2.2.2:8080", IP: "2.2.2.2", Port: "8080"},
+			},
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	if err := templates.ExecuteTemplate(out, "/var/lib/haproxy/conf/haproxy.config", state); err != nil {
+		t.Fatalf("Unexpected error executing template: %v", err)
+	}
+	config := out.String()
+
+	for _, expected := range []string{
+		"backend be_http_blue",
+		"server 1.1.1.1:8080 1.1.1.1:8080 check inter 5000ms weight 3",
+		"server 2.2.2.2:8080 2.2.2.2:8080 check inter 5000ms weight 1",
+		// every service unit reserves disabled servers for new endpoints
+		"server _slot_blue_1 127.0.0.1:1 disabled check inter 5000ms weight 3",
+		"server _slot_green_4 127.0.0.1:1 disabled check inter 5000ms weight 1",
+	} {
+		if !strings.Contains(config, expected) {
+			t.Errorf("Expected config to contain %q:\n%s", expected, config)
+		}
+	}
+	if strings.Contains(config, "backend be_http_green") {
+		t.Errorf("Expected no backend for a service unit without routes:\n%s", config)
+	}
+}
+
+// TestHAProxyTemplateReencrypt ensures routes with reencrypt termination get an http mode backend that
+// re-establishes TLS to the endpoints using the route's destination CA certificate.
+func TestHAProxyTemplateReencrypt(t *testing.T) {
+	templates := template.Must(template.New("config").Funcs(templateFuncs).ParseFiles(haproxyTemplate))
+	state := map[string]ServiceUnit{
+		"secure": {
+			Name: "secure",
+			EndpointTable: map[string]Endpoint{
+				"1.1.1.1:8443": {ID: "1.1.1.1:8443", IP: "1.1.1.1", Port: "8443"},
+			},
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{
+				"www.example.com-": {
+					Host:             "www.example.com",
+					TLSTermination:   "reencrypt",
+					ServiceUnitNames: map[string]int{"secure": 1},
+				},
+			},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	if err := templates.ExecuteTemplate(out, "/var/lib/haproxy/conf/haproxy.config", state); err != nil {
+		t.Fatalf("Unexpected error executing template: %v", err)
+	}
+	config := out.String()
+
+	i := strings.Index(config, "backend be_secure_secure")
+	if i < 0 {
+		t.Fatalf("Expected a reencrypt backend:\n%s", config)
+	}
+	backend := config[i:]
+	for _, expected := range []string{
+		"mode http",
+		"server 1.1.1.1:8443 1.1.1.1:8443 ssl check inter 5000ms verify required ca-file /var/lib/containers/router/cacerts/www.example.com_pod.pem weight 1",
+	} {
+		if !strings.Contains(backend, expected) {
+			t.Errorf("Expected the reencrypt backend to contain %q:\n%s", expected, backend)
+		}
+	}
+	if strings.Contains(config, "backend be_http_secure") {
+		t.Errorf("Expected no http backend for a reencrypt route:\n%s", config)
+	}
+
+	out.Reset()
+	if err := templates.ExecuteTemplate(out, "/var/lib/haproxy/conf/os_reencrypt.map", state); err != nil {
+		t.Fatalf("Unexpected error executing template: %v", err)
+	}
+	if !strings.Contains(out.String(), "www.example.com 1") {
+		t.Errorf("Expected the reencrypt map to contain the route host:\n%s", out.String())
+	}
+}
+
+// TestHAProxyTemplateAffinity ensures route affinity is rendered as cookie insertion or source balancing.
+func TestHAProxyTemplateAffinity(t *testing.T) {
+	templates := template.Must(template.New("config").Funcs(templateFuncs).ParseFiles(haproxyTemplate))
+
+	testCases := map[routeapi.RouteAffinityType][]string{
+		routeapi.RouteAffinityNone: {
+			"balance leastconn",
+			"server 1.1.1.1:8080 1.1.1.1:8080 check inter 5000ms weight 1\n",
+		},
+		routeapi.RouteAffinityCookie: {
+			"balance leastconn",
+			"cookie OPENSHIFT_app insert indirect nocache",
+			"server 1.1.1.1:8080 1.1.1.1:8080 check inter 5000ms weight 1 cookie 1.1.1.1:8080",
+		},
+	}
+
+	for affinity, expected := range testCases {
+		state := map[string]ServiceUnit{
+			"app": {
+				Name: "app",
+				EndpointTable: map[string]Endpoint{
+					"1.1.1.1:8080": {ID: "1.1.1.1:8080", IP: "1.1.1.1", Port: "8080"},
+				},
+				ServiceAliasConfigs: map[string]ServiceAliasConfig{
+					"www.example.com-": {
+						Host:             "www.example.com",
+						Affinity:         affinity,
+						ServiceUnitNames: map[string]int{"app": 1},
+					},
+				},
+			},
+		}
+
+		out := &bytes.Buffer{}
+		if err := templates.ExecuteTemplate(out, "/var/lib/haproxy/conf/haproxy.config", state); err != nil {
+			t.Fatalf("%s: unexpected error executing template: %v", affinity, err)
+		}
+		config := out.String()
+		for _, e := range expected {
+			if !strings.Contains(config, e) {
+				t.Errorf("%s: expected config to contain %q:\n%s", affinity, e, config)
+			}
+		}
+		if affinity != routeapi.RouteAffinityCookie && strings.Contains(config, "cookie") {
+			t.Errorf("%s: expected no cookie configuration:\n%s", affinity, config)
+		}
+	}
+}
+
+// TestHAProxyTemplateTunables ensures per route balancing, timeouts, and connection limits are rendered.
+func TestHAProxyTemplateTunables(t *testing.T) {
+	templates := template.Must(template.New("config").Funcs(templateFuncs).ParseFiles(haproxyTemplate))
+	state := map[string]ServiceUnit{
+		"app": {
+			Name: "app",
+			EndpointTable: map[string]Endpoint{
+				"1.1.1.1:8080": {ID: "1.1.1.1:8080", IP: "1.1.1.1", Port: "8080"},
+			},
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{
+				"www.example.com-": {
+					Host:             "www.example.com",
+					ServiceUnitNames: map[string]int{"app": 1},
+					Balance:          "roundrobin",
+					ConnectTimeout:   "2000ms",
+					ServerTimeout:    "60000ms",
+					MaxConnections:   100,
+				},
+			},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	if err := templates.ExecuteTemplate(out, "/var/lib/haproxy/conf/haproxy.config", state); err != nil {
+		t.Fatalf("Unexpected error executing template: %v", err)
+	}
+	config := out.String()
+
+	for _, expected := range []string{
+		"balance roundrobin",
+		"timeout connect 2000ms",
+		"timeout server 60000ms",
+		"server 1.1.1.1:8080 1.1.1.1:8080 check inter 5000ms weight 1 maxconn 100",
+	} {
+		if !strings.Contains(config, expected) {
+			t.Errorf("Expected config to contain %q:\n%s", expected, config)
+		}
+	}
+}
+
+// TestHAProxyTemplateInsecureEdgeTerminationPolicy ensures edge terminated routes are only mapped for http
+// traffic when they allow it, and that http requests are redirected when the route asks for it.
+func TestHAProxyTemplateInsecureEdgeTerminationPolicy(t *testing.T) {
+	templates := template.Must(template.New("config").Funcs(templateFuncs).ParseFiles(haproxyTemplate))
+
+	testCases := map[routeapi.InsecureEdgeTerminationPolicyType]struct {
+		mapped     bool
+		redirected bool
+	}{
+		routeapi.InsecureEdgeTerminationPolicyAllow:    {mapped: true},
+		routeapi.InsecureEdgeTerminationPolicyRedirect: {redirected: true},
+		routeapi.InsecureEdgeTerminationPolicyNone:     {},
+	}
+
+	for policy, tc := range testCases {
+		state := map[string]ServiceUnit{
+			"app": {
+				Name: "app",
+				ServiceAliasConfigs: map[string]ServiceAliasConfig{
+					"www.example.com-": {
+						Host:                          "www.example.com",
+						TLSTermination:                routeapi.TLSTerminationEdge,
+						InsecureEdgeTerminationPolicy: policy,
+						ServiceUnitNames:              map[string]int{"app": 1},
+					},
+				},
+			},
+		}
+
+		httpMap := &bytes.Buffer{}
+		if err := templates.ExecuteTemplate(httpMap, "/var/lib/haproxy/conf/os_http_be.map", state); err != nil {
+			t.Fatalf("%s: unexpected error executing template: %v", policy, err)
+		}
+		if mapped := strings.Contains(httpMap.String(), "www.example.com app"); mapped != tc.mapped {
+			t.Errorf("%s: expected http mapping %t, got:\n%s", policy, tc.mapped, httpMap.String())
+		}
+
+		redirectMap := &bytes.Buffer{}
+		if err := templates.ExecuteTemplate(redirectMap, "/var/lib/haproxy/conf/os_edge_http_redirect.map", state); err != nil {
+			t.Fatalf("%s: unexpected error executing template: %v", policy, err)
+		}
+		if redirected := strings.Contains(redirectMap.String(), "www.example.com 1"); redirected != tc.redirected {
+			t.Errorf("%s: expected redirect %t, got:\n%s", policy, tc.redirected, redirectMap.String())
+		}
+	}
+}
+
+// TestHAProxyTemplateTCPPort ensures routes exposed on a TCP port get a dedicated frontend and a tcp
+// backend, and are not served by the shared http frontend.
+func TestHAProxyTemplateTCPPort(t *testing.T) {
+	templates := template.Must(template.New("config").Funcs(templateFuncs).ParseFiles(haproxyTemplate))
+	state := map[string]ServiceUnit{
+		"db": {
+			Name: "db",
+			EndpointTable: map[string]Endpoint{
+				"1.1.1.1:5432": {ID: "1.1.1.1:5432", IP: "1.1.1.1", Port: "5432"},
+			},
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{
+				"db.example.com-": {
+					Host:             "db.example.com",
+					TCPPort:          10000,
+					ServiceUnitNames: map[string]int{"db": 1},
+				},
+			},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	if err := templates.ExecuteTemplate(out, "/var/lib/haproxy/conf/haproxy.config", state); err != nil {
+		t.Fatalf("Unexpected error executing template: %v", err)
+	}
+	config := out.String()
+	for _, expected := range []string{
+		"frontend tcp_10000\n  bind :10000\n  mode tcp\n  default_backend be_tcp_db",
+		"backend be_tcp_db",
+		"server 1.1.1.1:5432 1.1.1.1:5432 check inter 5000ms weight 1",
+	} {
+		if !strings.Contains(config, expected) {
+			t.Errorf("Expected config to contain %q:\n%s", expected, config)
+		}
+	}
+	if strings.Contains(config, "backend be_http_db") {
+		t.Errorf("Expected no http backend for a TCP route:\n%s", config)
+	}
+
+	httpMap := &bytes.Buffer{}
+	if err := templates.ExecuteTemplate(httpMap, "/var/lib/haproxy/conf/os_http_be.map", state); err != nil {
+		t.Fatalf("Unexpected error executing template: %v", err)
+	}
+	if strings.Contains(httpMap.String(), "db.example.com") {
+		t.Errorf("Expected no http mapping for a TCP route:\n%s", httpMap.String())
+	}
+}
+
+const nginxTemplate = "../../../images/router/nginx/conf/nginx-config.template"
+
+// TestNginxTemplateWeightedBackends ensures the nginx upstream for a route splits traffic across the endpoints
+// of every service the route sends traffic to, and that the route host is served on both ports.
+func TestNginxTemplateWeightedBackends(t *testing.T) {
+	templates := template.Must(template.New("config").Funcs(templateFuncs).ParseFiles(nginxTemplate))
+	state := map[string]ServiceUnit{
+		"blue": {
+			Name: "blue",
+			EndpointTable: map[string]Endpoint{
+				"1.1.1.1:8080": {ID: "1.1.1.1:8080", IP: "1.1.1.1", Port: "8080"},
+			},
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{
+				"www.example.com-": {
+					Host:             "www.example.com",
+					ServiceUnitNames: map[string]int{"blue": 3, "green": 1, "red": 0},
+				},
+			},
+		},
+		"green": {
+			Name: "green",
+			EndpointTable: map[string]Endpoint{
+				"2.2.2.2:8080": {ID: "2.2.2.2:8080", IP: "2.2.2.2", Port: "8080"},
+			},
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{},
+		},
+		"red": {
+			Name: "red",
+			EndpointTable: map[string]Endpoint{
+				"3.3.3.3:8080": {ID: "3.3.3.3:8080", IP: "3.3.3.3", Port: "8080"},
+			},
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	if err := templates.ExecuteTemplate(out, "/var/lib/nginx/conf/nginx.conf", state); err != nil {
+		t.Fatalf("Unexpected error executing template: %v", err)
+	}
+	config := out.String()
+
+	for _, expected := range []string{
+		"upstream be_blue {",
+		"least_conn;",
+		"server 1.1.1.1:8080 weight=3;",
+		"server 2.2.2.2:8080 weight=1;",
+		"server 3.3.3.3:8080 down;",
+		"listen 80;",
+		"server_name www.example.com;",
+		"ssl_certificate /var/lib/nginx/conf/default_pub_keys.pem;",
+		"proxy_pass http://be_blue;",
+	} {
+		if !strings.Contains(config, expected) {
+			t.Errorf("Expected config to contain %q:\n%s", expected, config)
+		}
+	}
+	if strings.Contains(config, "upstream be_green") {
+		t.Errorf("Expected no upstream for a service unit without routes:\n%s", config)
+	}
+}
+
+// TestNginxTemplateTermination ensures edge routes are served with their own certificate files, reencrypt routes
+// verify the endpoints with the destination CA certificate, and passthrough routes are left out.
+func TestNginxTemplateTermination(t *testing.T) {
+	templates := template.Must(template.New("config").Funcs(templateFuncs).ParseFiles(nginxTemplate))
+	state := map[string]ServiceUnit{
+		"edge": {
+			Name:          "edge",
+			EndpointTable: map[string]Endpoint{},
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{
+				"edge.example.com-": {
+					Host:             "edge.example.com",
+					TLSTermination:   "edge",
+					ServiceUnitNames: map[string]int{"edge": 1},
+					Certificates: map[string]Certificate{
+						"edge.example.com": {ID: "edge.example.com", Contents: "cert", PrivateKey: "key"},
+					},
+				},
+			},
+		},
+		"secure": {
+			Name:          "secure",
+			EndpointTable: map[string]Endpoint{},
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{
+				"secure.example.com-": {
+					Host:             "secure.example.com",
+					TLSTermination:   "reencrypt",
+					ServiceUnitNames: map[string]int{"secure": 1},
+				},
+			},
+		},
+		"passthrough": {
+			Name:          "passthrough",
+			EndpointTable: map[string]Endpoint{},
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{
+				"passthrough.example.com-": {
+					Host:             "passthrough.example.com",
+					TLSTermination:   "passthrough",
+					ServiceUnitNames: map[string]int{"passthrough": 1},
+				},
+			},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	if err := templates.ExecuteTemplate(out, "/var/lib/nginx/conf/nginx.conf", state); err != nil {
+		t.Fatalf("Unexpected error executing template: %v", err)
+	}
+	config := out.String()
+
+	for _, expected := range []string{
+		"ssl_certificate /var/lib/containers/router/certs/edge.example.com.pem;",
+		"ssl_certificate_key /var/lib/containers/router/certs/edge.example.com.key;",
+		"proxy_pass https://be_secure;",
+		"proxy_ssl_trusted_certificate /var/lib/containers/router/cacerts/secure.example.com_pod.pem;",
+	} {
+		if !strings.Contains(config, expected) {
+			t.Errorf("Expected config to contain %q:\n%s", expected, config)
+		}
+	}
+	i := strings.Index(config, "server_name secure.example.com;")
+	if i < 0 || strings.Contains(config[strings.LastIndex(config[:i], "server {"):i], "listen 80;") {
+		t.Errorf("Expected the reencrypt route to be served on the TLS port only:\n%s", config)
+	}
+	if strings.Contains(config, "passthrough.example.com") {
+		t.Errorf("Expected no server for a passthrough route:\n%s", config)
+	}
+}
+
+// TestNginxTemplateInsecureEdgeTerminationPolicy ensures the server of an edge terminated route only listens for
+// http traffic when the route allows it, and that http requests are redirected when the route asks for it.
+func TestNginxTemplateInsecureEdgeTerminationPolicy(t *testing.T) {
+	templates := template.Must(template.New("config").Funcs(templateFuncs).ParseFiles(nginxTemplate))
+
+	testCases := map[routeapi.InsecureEdgeTerminationPolicyType]struct {
+		served     bool
+		redirected bool
+	}{
+		routeapi.InsecureEdgeTerminationPolicyAllow:    {served: true},
+		routeapi.InsecureEdgeTerminationPolicyRedirect: {redirected: true},
+		routeapi.InsecureEdgeTerminationPolicyNone:     {},
+	}
+
+	for policy, tc := range testCases {
+		state := map[string]ServiceUnit{
+			"app": {
+				Name: "app",
+				ServiceAliasConfigs: map[string]ServiceAliasConfig{
+					"www.example.com-": {
+						Host:                          "www.example.com",
+						TLSTermination:                routeapi.TLSTerminationEdge,
+						InsecureEdgeTerminationPolicy: policy,
+						ServiceUnitNames:              map[string]int{"app": 1},
+					},
+				},
+			},
+		}
+
+		out := &bytes.Buffer{}
+		if err := templates.ExecuteTemplate(out, "/var/lib/nginx/conf/nginx.conf", state); err != nil {
+			t.Fatalf("%s: unexpected error executing template: %v", policy, err)
+		}
+		config := out.String()
+
+		i := strings.Index(config, "upstream be_app")
+		if i < 0 {
+			t.Fatalf("%s: expected an upstream for the route:\n%s", policy, config)
+		}
+		routeConfig := config[i:]
+		if redirected := strings.Contains(routeConfig, "return 301 https://$host$request_uri;"); redirected != tc.redirected {
+			t.Errorf("%s: expected redirect %t, got:\n%s", policy, tc.redirected, routeConfig)
+		}
+		if served := strings.Contains(routeConfig, "listen 80;\n") && !tc.redirected; served != tc.served {
+			t.Errorf("%s: expected http traffic to be served %t, got:\n%s", policy, tc.served, routeConfig)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package templaterouter
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// templateFuncs are the functions the templates may call.  serverSlots names the disabled servers a backend
+// reserves for new endpoints of a service unit, which the router fills without a reload.
+var templateFuncs = template.FuncMap{
+	"serverSlots": serverSlots,
+}
+
+// templateSource is the set of files the templates of the router are parsed from: a template file and
+// a directory of files whose templates are parsed after it.  A template defined in the directory
+// replaces the template of the same name, so a single configuration file can be customized by
+// mounting a directory into the router without rebuilding its image.  Either may be empty.
+type templateSource struct {
+	file string
+	dir  string
+}
+
+// paths returns the files of the source in the order they are parsed.  The files of the directory
+// are parsed in lexical order; hidden files and subdirectories are ignored.
+func (s templateSource) paths() ([]string, error) {
+	paths := []string{}
+	if len(s.file) > 0 {
+		paths = append(paths, s.file)
+	}
+	if len(s.dir) == 0 {
+		return paths, nil
+	}
+
+	infos, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, info := range infos {
+		if strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+		// stat the path so symlinks, as used by mounted volumes, are resolved
+		path := filepath.Join(s.dir, info.Name())
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+		names = append(names, path)
+	}
+	sort.Strings(names)
+	return append(paths, names...), nil
+}
+
+// load parses the files of the source and returns the templates it defines by the path of the
+// configuration file they render.
+func (s templateSource) load() (map[string]*template.Template, error) {
+	paths, err := s.paths()
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no templates found")
+	}
+
+	// each file is parsed on its own, since a set cannot redefine a template before Go 1.6, and the later
+	// definition of a name replaces the earlier ones
+	trees := map[string]*parse.Tree{}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.Base(path)
+		file, err := template.New(name).Funcs(templateFuncs).Parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse template %s: %v", path, err)
+		}
+		// only the templates defined by the files are rendered, not the files themselves
+		for _, defined := range file.Templates() {
+			if defined == file || defined.Tree == nil {
+				continue
+			}
+			trees[defined.Name()] = defined.Tree
+		}
+	}
+
+	masterTemplate := template.New("config").Funcs(templateFuncs)
+	templates := map[string]*template.Template{}
+	for name, tree := range trees {
+		defined, err := masterTemplate.AddParseTree(name, tree)
+		if err != nil {
+			return nil, fmt.Errorf("unable to add template %s: %v", name, err)
+		}
+		templates[name] = defined
+	}
+	return templates, nil
+}
+
+// version returns a string that changes whenever a file of the source is added, removed or modified.
+func (s templateSource) version() (string, error) {
+	paths, err := s.paths()
+	if err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(buf, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return buf.String(), nil
+}
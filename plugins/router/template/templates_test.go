@@ -0,0 +1,114 @@
+package templaterouter
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTemplate(t *testing.T, path, data string) {
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func renderTemplates(t *testing.T, source templateSource) map[string]string {
+	templates, err := source.load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rendered := map[string]string{}
+	for name, template := range templates {
+		buf := &bytes.Buffer{}
+		if err := template.Execute(buf, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		rendered[name] = buf.String()
+	}
+	return rendered
+}
+
+func TestTemplateSourceLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "templates")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "config.template")
+	writeTemplate(t, file, `{{define "/conf/a"}}a{{template "settings"}}{{end}}{{define "settings"}} default{{end}}{{define "/conf/b"}}b{{end}}`)
+	customDir := filepath.Join(dir, "custom")
+	if err := os.Mkdir(customDir, 0755); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rendered := renderTemplates(t, templateSource{file: file})
+	if len(rendered) != 3 || rendered["/conf/a"] != "a default" || rendered["/conf/b"] != "b" {
+		t.Errorf("Unexpected templates: %#v", rendered)
+	}
+
+	writeTemplate(t, filepath.Join(customDir, "settings.template"), `{{define "settings"}} custom{{end}}`)
+	writeTemplate(t, filepath.Join(customDir, "extra.template"), `{{define "/conf/c"}}c{{end}}`)
+	writeTemplate(t, filepath.Join(customDir, ".hidden"), `{{define "/conf/d"}}d{{end}}`)
+	rendered = renderTemplates(t, templateSource{file: file, dir: customDir})
+	if len(rendered) != 4 || rendered["/conf/a"] != "a custom" || rendered["/conf/c"] != "c" {
+		t.Errorf("Unexpected templates: %#v", rendered)
+	}
+
+	rendered = renderTemplates(t, templateSource{dir: customDir})
+	if len(rendered) != 2 || rendered["/conf/c"] != "c" {
+		t.Errorf("Unexpected templates: %#v", rendered)
+	}
+
+	writeTemplate(t, filepath.Join(customDir, "broken.template"), `{{define "/conf/e"}}`)
+	if _, err := (templateSource{file: file, dir: customDir}).load(); err == nil {
+		t.Errorf("Expected an error for an invalid template")
+	}
+}
+
+func TestCheckTemplates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "templates")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	writeTemplate(t, filepath.Join(dir, "config.template"), `{{define "/conf/a"}}a{{end}}`)
+
+	router := newTestRouter(make(map[string]ServiceUnit))
+	plugin := &TemplatePlugin{Router: router, templates: templateSource{dir: dir}}
+	version, err := plugin.templates.version()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	plugin.templatesVersion = version
+
+	if err := plugin.checkTemplates(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if router.Commits != 0 || router.Templates != nil {
+		t.Errorf("Expected unchanged templates not to be reloaded")
+	}
+
+	writeTemplate(t, filepath.Join(dir, "extra.template"), `{{define "/conf/b"}}b{{end}}`)
+	if err := plugin.checkTemplates(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if router.Commits != 1 || len(router.Templates) != 2 {
+		t.Errorf("Expected the router to be committed with the new templates, got %d commits and %#v", router.Commits, router.Templates)
+	}
+
+	// invalid templates are reported once and the router keeps its templates
+	writeTemplate(t, filepath.Join(dir, "extra.template"), `{{define "/conf/b"}}`)
+	os.Chtimes(filepath.Join(dir, "extra.template"), time.Now(), time.Now().Add(time.Minute))
+	if err := plugin.checkTemplates(); err == nil {
+		t.Errorf("Expected an error for an invalid template")
+	}
+	if err := plugin.checkTemplates(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if router.Commits != 1 || len(router.Templates) != 2 {
+		t.Errorf("Expected the router to keep its templates, got %d commits and %#v", router.Commits, router.Templates)
+	}
+}
@@ -22,8 +22,29 @@ type ServiceAliasConfig struct {
 	Host string
 	// An optional path.  Ie. www.example.com/myservice where "myservice" is the path
 	Path string
+	// ServiceUnitNames are the service units whose endpoints receive traffic for this route, keyed by
+	// service unit name with the relative weight of each.  Drives the weights of the backend servers.
+	ServiceUnitNames map[string]int
 	// Termination policy for this backend, drives the mapping files and router configuration
 	TLSTermination routeapi.TLSTerminationType
+	// InsecureEdgeTerminationPolicy decides whether an edge terminated route is also served over http, redirected
+	// to https or refused over http.  Drives the http mapping files
+	InsecureEdgeTerminationPolicy routeapi.InsecureEdgeTerminationPolicyType
+	// Affinity keeps a client on the same endpoint, drives the balancing and cookie configuration of the backend
+	Affinity routeapi.RouteAffinityType
+	// Balance is the algorithm used to pick an endpoint, the template default is used if empty
+	Balance string
+	// ConnectTimeout is how long to wait for a connection to an endpoint, in haproxy time format.  The global default
+	// is used if empty
+	ConnectTimeout string
+	// ServerTimeout is how long to wait for an endpoint to respond, in haproxy time format.  The global default is
+	// used if empty
+	ServerTimeout string
+	// MaxConnections limits the concurrent connections to each endpoint, unlimited if 0
+	MaxConnections int
+	// TCPPort is the port the route is exposed on as a raw TCP service, the route is only served through the
+	// shared http and https frontends if 0
+	TCPPort int
 	// Certificates used for securing this backend.  Keyed by the cert id
 	Certificates map[string]Certificate
 }
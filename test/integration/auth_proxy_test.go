@@ -47,8 +47,8 @@ func TestFrontProxyOnAuthorize(t *testing.T) {
 	etcdClient := newEtcdClient()
 	etcdHelper, _ := master.NewEtcdHelper(etcdClient, klatest.Version)
 	oauthEtcd := oauthetcd.New(etcdHelper)
-	userRegistry := useretcd.New(etcdHelper, user.NewDefaultUserInitStrategy())
-	identityMapper := identitymapper.NewAlwaysCreateUserIdentityToUserMapper("front-proxy-test" /*for now*/, userRegistry)
+	userRegistry := useretcd.New(etcdHelper, user.NewDefaultUserInitStrategy(), user.MappingMethodClaim)
+	identityMapper := identitymapper.NewAlwaysCreateUserIdentityToUserMapper("front-proxy-test" /*for now*/, userRegistry, userRegistry)
 
 	// this auth request handler is the one that is supposed to recognize information from a front proxy
 	authRequestHandler := headerrequest.NewAuthenticator(headerrequest.NewDefaultConfig(), identityMapper)
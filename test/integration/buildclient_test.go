@@ -220,6 +220,7 @@ func NewTestBuildOpenshift(t *testing.T) *testBuildOpenshift {
 	}
 
 	factory.Create().Run()
+	factory.CreatePodController().Run()
 
 	return openshift
 }
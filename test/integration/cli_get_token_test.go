@@ -43,8 +43,8 @@ func TestGetToken(t *testing.T) {
 	etcdClient := newEtcdClient()
 	etcdHelper, _ := master.NewEtcdHelper(etcdClient, klatest.Version)
 	oauthEtcd := oauthetcd.New(etcdHelper)
-	userRegistry := useretcd.New(etcdHelper, user.NewDefaultUserInitStrategy())
-	identityMapper := identitymapper.NewAlwaysCreateUserIdentityToUserMapper("front-proxy-test" /*for now*/, userRegistry)
+	userRegistry := useretcd.New(etcdHelper, user.NewDefaultUserInitStrategy(), user.MappingMethodClaim)
+	identityMapper := identitymapper.NewAlwaysCreateUserIdentityToUserMapper("front-proxy-test" /*for now*/, userRegistry, userRegistry)
 
 	authRequestHandler := basicauthrequest.NewBasicAuthAuthentication(allowanypassword.New(identityMapper))
 	authHandler := oauthhandlers.NewUnionAuthenticationHandler(
@@ -106,7 +106,7 @@ func TestGetToken(t *testing.T) {
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
 	}
-	if token.UserName != "front-proxy-test:user" {
+	if token.UserName != "user" {
 		t.Errorf("Expected token for \"user\", but got: %#v", token)
 	}
 }
@@ -237,7 +237,7 @@ func NewTestImageOpenShift(t *testing.T) *testImageOpenshift {
 
 	interfaces, _ := latest.InterfacesFor(latest.Version)
 
-	imageEtcd := imageetcd.New(etcdHelper, imageetcd.DefaultRegistryFunc(func() (string, bool) { return openshift.dockerServer.URL, true }))
+	imageEtcd := imageetcd.New(etcdHelper, imageetcd.DefaultRegistryFunc(func() (string, bool) { return openshift.dockerServer.URL, true }), 0)
 
 	storage := map[string]apiserver.RESTStorage{
 		"images":                  image.NewREST(imageEtcd),
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
@@ -38,10 +39,10 @@ func TestUserInitialization(t *testing.T) {
 	deleteAllEtcdKeys()
 	etcdClient := newEtcdClient()
 	interfaces, _ := latest.InterfacesFor(latest.Version)
-	userRegistry := etcd.New(tools.EtcdHelper{etcdClient, interfaces.Codec, tools.RuntimeVersionAdapter{interfaces.MetadataAccessor}}, user.NewDefaultUserInitStrategy())
+	userRegistry := etcd.New(tools.EtcdHelper{etcdClient, interfaces.Codec, tools.RuntimeVersionAdapter{interfaces.MetadataAccessor}}, user.NewDefaultUserInitStrategy(), user.MappingMethodClaim)
 	storage := map[string]apiserver.RESTStorage{
 		"userIdentityMappings": useridentitymapping.NewREST(userRegistry),
-		"users":                userregistry.NewREST(userRegistry),
+		"users":                userregistry.NewREST(userRegistry, &userregistry.Cleanup{}),
 	}
 
 	server := httptest.NewServer(apiserver.Handle(storage, v1beta1.Codec, "/osapi", "v1beta1", interfaces.MetadataAccessor, admit.NewAlwaysAdmit(), latest.RESTMapper))
@@ -73,12 +74,13 @@ func TestUserInitialization(t *testing.T) {
 
 	expectedUser := api.User{
 		ObjectMeta: kapi.ObjectMeta{
-			Name: ":test",
+			Name: "test",
 			// Copy the UID and timestamp from the actual one
 			UID:               actual.User.UID,
 			CreationTimestamp: actual.User.CreationTimestamp,
 		},
-		FullName: "Mr. Test",
+		FullName:   "Mr. Test",
+		Identities: []string{":test"},
 	}
 	// Copy the UID and timestamp from the actual one
 	mapping.Identity.UID = actual.Identity.UID
@@ -150,30 +152,32 @@ func TestUserLookup(t *testing.T) {
 	deleteAllEtcdKeys()
 	etcdClient := newEtcdClient()
 	interfaces, _ := latest.InterfacesFor(latest.Version)
-	userRegistry := etcd.New(tools.EtcdHelper{etcdClient, interfaces.Codec, tools.RuntimeVersionAdapter{interfaces.MetadataAccessor}}, user.NewDefaultUserInitStrategy())
+	userRegistry := etcd.New(tools.EtcdHelper{etcdClient, interfaces.Codec, tools.RuntimeVersionAdapter{interfaces.MetadataAccessor}}, user.NewDefaultUserInitStrategy(), user.MappingMethodClaim)
 	userInfo := &authapi.DefaultUserInfo{
-		Name: ":test",
+		Name: "test",
 	}
-	userContext := context.NewRequestContextMap()
+	userContext := context.NewRequestContextMapper()
 	userContextFunc := userregistry.ContextFunc(func(req *http.Request) (userregistry.Info, bool) {
-		obj, found := userContext.Get(req)
-		if user, ok := obj.(authapi.UserInfo); found && ok {
-			return user, true
+		if ctx, found := userContext.Get(req); found {
+			if user, ok := context.UserFrom(ctx); ok {
+				return user, true
+			}
 		}
 		return nil, false
 	})
 
 	storage := map[string]apiserver.RESTStorage{
 		"userIdentityMappings": useridentitymapping.NewREST(userRegistry),
-		"users":                userregistry.NewREST(userRegistry),
+		"users":                userregistry.NewREST(userRegistry, &userregistry.Cleanup{}),
 	}
 
 	apihandler := apiserver.Handle(storage, interfaces.Codec, "/osapi", "v1beta1", interfaces.MetadataAccessor, admit.NewAlwaysAdmit(), latest.RESTMapper)
 	apihandler = userregistry.NewCurrentContextFilter("/osapi/v1beta1/users/~", userContextFunc, apihandler)
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		userContext.Set(req, userInfo)
+	server := httptest.NewServer(context.NewRequestContextFilter(userContext, time.Minute, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, _ := userContext.Get(req)
+		userContext.Update(req, context.WithUser(ctx, userInfo))
 		apihandler.ServeHTTP(w, req)
-	}))
+	})))
 
 	mapping := api.UserIdentityMapping{
 		Identity: api.Identity{
@@ -200,12 +204,13 @@ func TestUserLookup(t *testing.T) {
 	}
 	expectedUser := api.User{
 		ObjectMeta: kapi.ObjectMeta{
-			Name: ":test",
+			Name: "test",
 			// Copy the UID and timestamp from the actual one
 			UID:               actual.User.UID,
 			CreationTimestamp: actual.User.CreationTimestamp,
 		},
-		FullName: "Mr. Test",
+		FullName:   "Mr. Test",
+		Identities: []string{":test"},
 	}
 	// Copy the UID and timestamp from the actual one
 	mapping.Identity.UID = actual.Identity.UID